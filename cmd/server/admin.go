@@ -0,0 +1,240 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rom8726/floxy-manager/internal"
+	"github.com/rom8726/floxy-manager/internal/config"
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/services/migrator"
+)
+
+// cliUsername attributes the audit log entries of the admin subcommands.
+const cliUsername = "cli"
+
+var (
+	superuserUsername string
+	superuserEmail    string
+	superuserPassword string
+	resetPassword     string
+)
+
+var createSuperuserCmd = &cobra.Command{
+	Use:   "create-superuser",
+	Short: "Create a superuser, a generated password is temporary",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return withAdmin(cmd, func(ctx context.Context, admin *internal.Admin) error {
+			user, password, err := admin.CreateSuperuser(ctx, superuserUsername, superuserEmail, superuserPassword)
+			if err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Created superuser %s (id %d)\n", user.Username, user.ID)
+			if superuserPassword == "" {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Temporary password: %s\n", password)
+			}
+
+			return nil
+		})
+	},
+}
+
+var resetPasswordCmd = &cobra.Command{
+	Use:   "reset-password <username|email>",
+	Short: "Set a temporary password of a user and revoke the user sessions",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withAdmin(cmd, func(ctx context.Context, admin *internal.Admin) error {
+			user, password, err := admin.ResetPassword(ctx, args[0], resetPassword)
+			if err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Reset password of %s (id %d)\n", user.Username, user.ID)
+			if resetPassword == "" {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Temporary password: %s\n", password)
+			}
+
+			return nil
+		})
+	},
+}
+
+var listUsersCmd = &cobra.Command{
+	Use:   "list-users",
+	Short: "List users",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return withAdmin(cmd, func(ctx context.Context, admin *internal.Admin) error {
+			users, err := admin.ListUsers(ctx)
+			if err != nil {
+				return fmt.Errorf("list users: %w", err)
+			}
+
+			out := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(out, "ID\tUSERNAME\tEMAIL\tSUPERUSER\tACTIVE\tLAST LOGIN")
+			for _, user := range users {
+				lastLogin := "-"
+				if user.LastLogin != nil {
+					lastLogin = user.LastLogin.Format("2006-01-02 15:04:05")
+				}
+
+				_, _ = fmt.Fprintf(out, "%d\t%s\t%s\t%t\t%t\t%s\n",
+					user.ID, user.Username, user.Email, user.IsSuperuser, user.IsActive, lastLogin)
+			}
+
+			return out.Flush()
+		})
+	},
+}
+
+var applyMigrationsCmd = &cobra.Command{
+	Use:   "apply-migrations",
+	Short: "Apply pending database migrations",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		cfg, err := loadConfig(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		return upMigrations(cmd.Context(), cfg.Postgres.ConnString(), cfg.MigrationsDir)
+	},
+}
+
+var seedDemoDataCmd = &cobra.Command{
+	Use:   "seed-demo-data",
+	Short: "Create a demo tenant with a project and a workflow definition",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return withAdmin(cmd, func(ctx context.Context, admin *internal.Admin) error {
+			data, err := admin.SeedDemoData(ctx)
+			if err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Created tenant %q (id %d), project id %d, workflow %s\n",
+				data.Tenant.Name, data.Tenant.ID, data.ProjectID, data.WorkflowID)
+
+			return nil
+		})
+	},
+}
+
+var checkConfigCmd = &cobra.Command{
+	Use:   "check-config",
+	Short: "Validate the configuration, the database connection and the migrations",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return checkConfig(cmd.Context(), cmd.OutOrStdout())
+	},
+}
+
+func init() { //nolint:gochecknoinits // cobra command initialization
+	createSuperuserCmd.Flags().StringVar(&superuserUsername, "username", "", "username (default: the part of the email before @)")
+	createSuperuserCmd.Flags().StringVar(&superuserEmail, "email", "", "email")
+	createSuperuserCmd.Flags().StringVar(&superuserPassword, "password", "", "password (default: generated)")
+	_ = createSuperuserCmd.MarkFlagRequired("email")
+
+	resetPasswordCmd.Flags().StringVar(&resetPassword, "password", "", "temporary password (default: generated)")
+
+	ServerCmd.AddCommand(
+		createSuperuserCmd,
+		resetPasswordCmd,
+		listUsersCmd,
+		applyMigrationsCmd,
+		seedDemoDataCmd,
+		checkConfigCmd,
+	)
+}
+
+func withAdmin(cmd *cobra.Command, fn func(ctx context.Context, admin *internal.Admin) error) error {
+	cfg, err := loadConfig(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	ctx := appcontext.WithUsername(cmd.Context(), cliUsername)
+
+	admin, err := internal.NewAdmin(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer admin.Close()
+
+	return fn(ctx, admin)
+}
+
+// loadConfig loads the config and resolves its secret references.
+func loadConfig(ctx context.Context) (*config.Config, error) {
+	cfg, err := config.New(envFile)
+	if err != nil {
+		return nil, fmt.Errorf("load config: %w", err)
+	}
+
+	if err := cfg.ResolveSecrets(ctx); err != nil {
+		return nil, fmt.Errorf("resolve secrets: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// checkConfig reports each check and fails when any of them failed.
+func checkConfig(ctx context.Context, out io.Writer) error {
+	cfg, err := config.New(envFile)
+	if err != nil {
+		_, _ = fmt.Fprintf(out, "config: FAIL: %v\n", err)
+
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	_, _ = fmt.Fprintln(out, "config: ok")
+
+	if err := cfg.ResolveSecrets(ctx); err != nil {
+		_, _ = fmt.Fprintf(out, "secrets: FAIL: %v\n", err)
+
+		return fmt.Errorf("resolve secrets: %w", err)
+	}
+
+	_, _ = fmt.Fprintf(out, "secrets: ok (%d referenced)\n", cfg.SecretsResolver().Len())
+
+	var errs []error
+
+	admin, err := internal.NewAdmin(ctx, cfg)
+	if err == nil {
+		err = admin.Ping(ctx)
+		admin.Close()
+	}
+
+	if err != nil {
+		_, _ = fmt.Fprintf(out, "database: FAIL: %v\n", err)
+		errs = append(errs, fmt.Errorf("database: %w", err))
+	} else {
+		_, _ = fmt.Fprintln(out, "database: ok")
+	}
+
+	status, err := migrator.New(nil, nil, &migrator.Config{
+		ConnString:    cfg.Postgres.ConnString(),
+		MigrationsDir: cfg.MigrationsDir,
+	}).Status(ctx)
+
+	switch {
+	case err != nil:
+		_, _ = fmt.Fprintf(out, "migrations: FAIL: %v\n", err)
+		errs = append(errs, fmt.Errorf("migrations: %w", err))
+	case status.Dirty:
+		_, _ = fmt.Fprintf(out, "migrations: FAIL: version %d is dirty\n", status.Version)
+		errs = append(errs, migrator.ErrDirty)
+	default:
+		_, _ = fmt.Fprintf(out, "migrations: ok (version %d, %d pending)\n", status.Version, len(status.Pending))
+	}
+
+	return errors.Join(errs...)
+}
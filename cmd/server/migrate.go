@@ -1,67 +1,24 @@
 package server
 
 import (
-	"database/sql"
-	"errors"
-	"fmt"
+	"context"
 	"log/slog"
-	"net/url"
 
-	"github.com/golang-migrate/migrate/v4"
-	"github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
-	_ "github.com/lib/pq"
+	"github.com/rom8726/floxy-manager/internal/services/migrator"
 )
 
-const (
-	dbSchema = "workflows_manager"
-)
-
-func upMigrations(connStr, migrationsDir string) error {
+func upMigrations(ctx context.Context, connStr, migrationsDir string) error {
 	slog.Info("up migrations...")
 
-	connStringURL, err := url.Parse(connStr)
-	if err != nil {
-		return fmt.Errorf("parsing connection string: %w", err)
-	}
-
-	values := connStringURL.Query()
-	values.Set("search_path", dbSchema) // set db schema
-	connStringURL.RawQuery = values.Encode()
-	connStr = connStringURL.String()
-
-	db, err := sql.Open("postgres", connStr)
-	if err != nil {
-		return fmt.Errorf("open postgres connection: %w", err)
-	}
-
-	defer func() { _ = db.Close() }()
-
-	_, err = db.Exec("CREATE SCHEMA IF NOT EXISTS " + dbSchema)
+	status, err := migrator.New(nil, nil, &migrator.Config{
+		ConnString:    connStr,
+		MigrationsDir: migrationsDir,
+	}).Up(ctx)
 	if err != nil {
 		return err
 	}
 
-	driver, err := postgres.WithInstance(db, &postgres.Config{})
-	if err != nil {
-		return fmt.Errorf("create postgres driver: %w", err)
-	}
-
-	pgMigrate, err := migrate.NewWithDatabaseInstance("file://"+migrationsDir, "postgres", driver)
-	if err != nil {
-		return fmt.Errorf("create migrations: %w", err)
-	}
-	if err := pgMigrate.Up(); err != nil {
-		if errors.Is(err, migrate.ErrNoChange) {
-			slog.Info("up migrations: no changes")
-
-			return nil
-		}
-
-		return fmt.Errorf("up: %w", err)
-	}
-
-	slog.Info("up migrations: done")
+	slog.Info("up migrations: done", "version", status.Version)
 
 	return nil
 }
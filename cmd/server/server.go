@@ -10,6 +10,8 @@ import (
 
 	"github.com/rom8726/floxy-manager/internal"
 	"github.com/rom8726/floxy-manager/internal/config"
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/services/logging"
 )
 
 var ServerCmd = &cobra.Command{
@@ -20,7 +22,10 @@ var ServerCmd = &cobra.Command{
 	},
 }
 
-var envFile string
+var (
+	envFile   string
+	checkOnly bool
+)
 
 func init() { //nolint:gochecknoinits // cobra command initialization
 	ServerCmd.PersistentFlags().StringVarP(
@@ -30,6 +35,12 @@ func init() { //nolint:gochecknoinits // cobra command initialization
 		"",
 		"path to env file",
 	)
+	ServerCmd.Flags().BoolVar(
+		&checkOnly,
+		"check",
+		false,
+		"validate the configuration and exit",
+	)
 }
 
 func runServerCommand(ctx context.Context, _ []string) error {
@@ -38,17 +49,31 @@ func runServerCommand(ctx context.Context, _ []string) error {
 		return fmt.Errorf("load config: %w", err)
 	}
 
-	loggerHandler := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
-		Level: &cfg.Logger,
-	})
-	logger := slog.New(loggerHandler)
+	if checkOnly {
+		_, _ = fmt.Fprintln(os.Stdout, "config is valid")
+
+		return nil
+	}
+
+	if err := cfg.ResolveSecrets(ctx); err != nil {
+		return fmt.Errorf("resolve secrets: %w", err)
+	}
+
+	logControl, err := logging.New(os.Stdout, cfg.Logger.Lvl, cfg.Logger.Format)
+	if err != nil {
+		return fmt.Errorf("create logger: %w", err)
+	}
+
+	logger := slog.New(appcontext.NewLogHandler(logControl.Handler()))
 	slog.SetDefault(logger)
 
-	if err := upMigrations(cfg.Postgres.ConnString(), cfg.MigrationsDir); err != nil {
-		return fmt.Errorf("up migrations: %w", err)
+	if cfg.MigrateOnStart {
+		if err := upMigrations(ctx, cfg.Postgres.ConnString(), cfg.MigrationsDir); err != nil {
+			return fmt.Errorf("up migrations: %w", err)
+		}
 	}
 
-	app, err := internal.NewApp(ctx, cfg, logger)
+	app, err := internal.NewApp(ctx, cfg, logger, logControl)
 	if err != nil {
 		return fmt.Errorf("create app: %w", err)
 	}
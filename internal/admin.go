@@ -0,0 +1,266 @@
+package internal
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/rom8726/floxy-manager/internal/config"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/repository/projects"
+	"github.com/rom8726/floxy-manager/internal/repository/sessions"
+	"github.com/rom8726/floxy-manager/internal/repository/tenants"
+	"github.com/rom8726/floxy-manager/internal/repository/tenantschemas"
+	"github.com/rom8726/floxy-manager/internal/repository/users"
+	"github.com/rom8726/floxy-manager/internal/repository/workflows"
+	"github.com/rom8726/floxy-manager/pkg/db"
+	"github.com/rom8726/floxy-manager/pkg/passworder"
+)
+
+const (
+	minPasswordLength = 6
+
+	demoTenantName   = "Demo"
+	demoProjectName  = "Demo project"
+	demoWorkflowName = "demo-order"
+)
+
+var (
+	ErrPasswordTooShort  = fmt.Errorf("password must be at least %d characters", minPasswordLength)
+	ErrDemoDataExists    = errors.New("demo data already exists")
+	errUserLoginRequired = errors.New("username or email is required")
+)
+
+// demoDefinition is a small order workflow with a compensation step.
+const demoDefinition = `{
+	"start": "validate",
+	"steps": {
+		"validate": {"name": "validate", "type": "task", "handler": "validate", "next": ["charge"]},
+		"charge": {"name": "charge", "type": "task", "handler": "charge", "max_retries": 3,
+			"next": ["ship"], "on_failure": "refund"},
+		"ship": {"name": "ship", "type": "task", "handler": "ship"},
+		"refund": {"name": "refund", "type": "task", "handler": "refund"}
+	}
+}`
+
+// Admin runs the bootstrap and break-glass operations of the CLI right against the database,
+// without the servers and background runners of the App.
+type Admin struct {
+	pool *pgxpool.Pool
+	tx   db.TxManager
+
+	usersRepo     *users.Repository
+	sessionsRepo  *sessions.Repository
+	tenantsRepo   *tenants.Repository
+	projectsRepo  *projects.Repository
+	workflowsRepo *workflows.Repository
+}
+
+// DemoData is what SeedDemoData created.
+type DemoData struct {
+	Tenant     domain.Tenant
+	ProjectID  domain.ProjectID
+	WorkflowID string
+}
+
+func NewAdmin(ctx context.Context, cfg *config.Config) (*Admin, error) {
+	ctx, cancel := context.WithTimeout(ctx, ctxTimeout)
+	defer cancel()
+
+	pool, err := newPostgresConnPool(ctx, &cfg.Postgres, cfg.SecretValue("POSTGRES_PASSWORD"))
+	if err != nil {
+		return nil, fmt.Errorf("create postgres pool: %w", err)
+	}
+
+	schemas := tenantschemas.New(pool, &tenantschemas.Config{
+		Enabled: cfg.TenantIsolation == config.TenantIsolationSchema,
+	})
+
+	return &Admin{
+		pool:          pool,
+		tx:            db.NewTxManager(pool),
+		usersRepo:     users.New(pool),
+		sessionsRepo:  sessions.New(pool),
+		tenantsRepo:   tenants.New(pool, schemas),
+		projectsRepo:  projects.New(pool),
+		workflowsRepo: workflows.New(pool, schemas),
+	}, nil
+}
+
+func (a *Admin) Close() {
+	a.pool.Close()
+}
+
+// CreateSuperuser creates a superuser with a verified email. An empty password is generated
+// and marked temporary, so it must be changed on the first login; the password is returned.
+func (a *Admin) CreateSuperuser(ctx context.Context, username, email, password string) (domain.User, string, error) {
+	if email == "" {
+		return domain.User{}, "", errors.New("email is required")
+	}
+
+	if username == "" {
+		username = usernameFromEmail(email)
+	}
+
+	password, tmpPassword, err := passwordOrGenerated(password)
+	if err != nil {
+		return domain.User{}, "", err
+	}
+
+	passwordHash, err := passworder.PasswordHash(password)
+	if err != nil {
+		return domain.User{}, "", fmt.Errorf("hash password: %w", err)
+	}
+
+	user, err := a.usersRepo.Create(ctx, domain.UserDTO{
+		Username:      username,
+		Email:         email,
+		PasswordHash:  passwordHash,
+		IsSuperuser:   true,
+		IsTmpPassword: tmpPassword,
+		EmailVerified: true,
+	})
+	if err != nil {
+		return domain.User{}, "", fmt.Errorf("create superuser: %w", err)
+	}
+
+	return user, password, nil
+}
+
+// ResetPassword sets a temporary password of the user found by username or email and revokes
+// the sessions of the user. An empty password is generated; the password is returned.
+func (a *Admin) ResetPassword(ctx context.Context, login, password string) (domain.User, string, error) {
+	password, _, err := passwordOrGenerated(password)
+	if err != nil {
+		return domain.User{}, "", err
+	}
+
+	passwordHash, err := passworder.PasswordHash(password)
+	if err != nil {
+		return domain.User{}, "", fmt.Errorf("hash password: %w", err)
+	}
+
+	var user domain.User
+	err = a.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		user, err = a.findUser(ctx, login)
+		if err != nil {
+			return err
+		}
+
+		user.PasswordHash = passwordHash
+		user.IsTmpPassword = true
+		if err := a.usersRepo.Update(ctx, &user); err != nil {
+			return fmt.Errorf("update user: %w", err)
+		}
+
+		if err := a.sessionsRepo.RevokeAll(ctx, user.ID, time.Now()); err != nil {
+			return fmt.Errorf("revoke sessions: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return domain.User{}, "", err
+	}
+
+	return user, password, nil
+}
+
+func (a *Admin) ListUsers(ctx context.Context) ([]domain.User, error) {
+	return a.usersRepo.List(ctx)
+}
+
+// SeedDemoData creates a demo tenant with a project and an order workflow definition.
+// It fails with ErrDemoDataExists when the demo tenant is already there.
+func (a *Admin) SeedDemoData(ctx context.Context) (DemoData, error) {
+	var data DemoData
+
+	err := a.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		existing, err := a.tenantsRepo.List(ctx)
+		if err != nil {
+			return fmt.Errorf("list tenants: %w", err)
+		}
+
+		for _, tenant := range existing {
+			if tenant.Name == demoTenantName {
+				return ErrDemoDataExists
+			}
+		}
+
+		data.Tenant, err = a.tenantsRepo.Create(ctx, demoTenantName)
+		if err != nil {
+			return fmt.Errorf("create tenant: %w", err)
+		}
+
+		data.ProjectID, err = a.projectsRepo.Create(ctx, &domain.ProjectDTO{
+			Name:        demoProjectName,
+			Description: "Sample project created by seed-demo-data",
+		}, data.Tenant.ID)
+		if err != nil {
+			return fmt.Errorf("create project: %w", err)
+		}
+
+		data.WorkflowID, err = a.workflowsRepo.CreateWorkflowDefinition(ctx, data.ProjectID, demoWorkflowName, 1,
+			json.RawMessage(demoDefinition))
+		if err != nil {
+			return fmt.Errorf("create workflow definition: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return DemoData{}, err
+	}
+
+	return data, nil
+}
+
+// Ping checks that the database is reachable.
+func (a *Admin) Ping(ctx context.Context) error {
+	return a.pool.Ping(ctx)
+}
+
+func (a *Admin) findUser(ctx context.Context, login string) (domain.User, error) {
+	if login == "" {
+		return domain.User{}, errUserLoginRequired
+	}
+
+	user, err := a.usersRepo.GetByUsername(ctx, login)
+	if err == nil || !errors.Is(err, domain.ErrEntityNotFound) {
+		return user, err
+	}
+
+	return a.usersRepo.GetByEmail(ctx, login)
+}
+
+// passwordOrGenerated returns the password, or a generated one marked temporary when it is empty.
+func passwordOrGenerated(password string) (string, bool, error) {
+	if password != "" {
+		if len(password) < minPasswordLength {
+			return "", false, ErrPasswordTooShort
+		}
+
+		return password, false, nil
+	}
+
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", false, fmt.Errorf("generate password: %w", err)
+	}
+
+	return hex.EncodeToString(buf), true, nil
+}
+
+// usernameFromEmail takes the part of the email before @ as the username.
+func usernameFromEmail(email string) string {
+	username, _, _ := strings.Cut(email, "@")
+
+	return username
+}
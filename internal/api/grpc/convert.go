@@ -0,0 +1,141 @@
+package grpc
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/rom8726/floxy-manager/internal/api/grpc/workflowspb"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+const (
+	defaultPage     = 1
+	defaultPageSize = 20
+)
+
+// pageOf returns the requested page and page size, the defaults for the unset ones.
+func pageOf(page, pageSize int32) (int, int) {
+	if page <= 0 {
+		page = defaultPage
+	}
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+
+	return int(page), int(pageSize)
+}
+
+func jsonString(v json.RawMessage) string {
+	if len(v) == 0 || string(v) == "null" {
+		return ""
+	}
+
+	return string(v)
+}
+
+func timestamp(v time.Time) *timestamppb.Timestamp {
+	if v.IsZero() {
+		return nil
+	}
+
+	return timestamppb.New(v)
+}
+
+func nullTimestamp(v sql.NullTime) *timestamppb.Timestamp {
+	if !v.Valid {
+		return nil
+	}
+
+	return timestamp(v.Time)
+}
+
+func toWorkflowDefinition(v *domain.WorkflowDefinition) *workflowspb.WorkflowDefinition {
+	return &workflowspb.WorkflowDefinition{
+		TenantId:   int64(v.TenantID),
+		ProjectId:  int64(v.ProjectID),
+		Id:         v.ID,
+		Name:       v.Name,
+		Version:    int32(v.Version),
+		Definition: jsonString(v.Definition),
+		CreatedAt:  timestamp(v.CreatedAt),
+	}
+}
+
+func toWorkflowInstance(v *domain.WorkflowInstance) *workflowspb.WorkflowInstance {
+	return &workflowspb.WorkflowInstance{
+		TenantId:    int64(v.TenantID),
+		ProjectId:   int64(v.ProjectID),
+		Id:          int64(v.ID),
+		WorkflowId:  v.WorkflowID,
+		Status:      v.Status,
+		Input:       jsonString(v.Input),
+		Output:      jsonString(v.Output),
+		Error:       v.Error.String,
+		StartedAt:   nullTimestamp(v.StartedAt),
+		CompletedAt: nullTimestamp(v.CompletedAt),
+		CreatedAt:   timestamp(v.CreatedAt),
+		UpdatedAt:   timestamp(v.UpdatedAt),
+	}
+}
+
+func toWorkflowStep(v *domain.WorkflowStep) *workflowspb.WorkflowStep {
+	return &workflowspb.WorkflowStep{
+		TenantId:               int64(v.TenantID),
+		ProjectId:              int64(v.ProjectID),
+		Id:                     int64(v.ID),
+		InstanceId:             int64(v.InstanceID),
+		StepName:               v.StepName,
+		StepType:               v.StepType,
+		Status:                 v.Status,
+		Input:                  jsonString(v.Input),
+		Output:                 jsonString(v.Output),
+		Error:                  v.Error.String,
+		RetryCount:             int32(v.RetryCount),
+		MaxRetries:             int32(v.MaxRetries),
+		CompensationRetryCount: int32(v.CompensationRetryCount),
+		IdempotencyKey:         v.IdempotencyKey,
+		StartedAt:              nullTimestamp(v.StartedAt),
+		CompletedAt:            nullTimestamp(v.CompletedAt),
+		CreatedAt:              timestamp(v.CreatedAt),
+	}
+}
+
+func toWorkflowEvent(v *domain.WorkflowEvent) *workflowspb.WorkflowEvent {
+	return &workflowspb.WorkflowEvent{
+		TenantId:   int64(v.TenantID),
+		ProjectId:  int64(v.ProjectID),
+		Id:         int64(v.ID),
+		InstanceId: int64(v.InstanceID),
+		StepId:     v.StepID.Int64,
+		EventType:  v.EventType,
+		Payload:    jsonString(v.Payload),
+		CreatedAt:  timestamp(v.CreatedAt),
+	}
+}
+
+func toWorkflowStat(v *domain.WorkflowStat) *workflowspb.WorkflowStat {
+	return &workflowspb.WorkflowStat{
+		TenantId:           int64(v.TenantID),
+		ProjectId:          int64(v.ProjectID),
+		Name:               v.Name,
+		Version:            int32(v.Version),
+		TotalInstances:     int64(v.TotalInstances),
+		CompletedInstances: int64(v.CompletedInstances),
+		FailedInstances:    int64(v.FailedInstances),
+		RunningInstances:   int64(v.RunningInstances),
+		AverageDurationNs:  v.AverageDuration,
+	}
+}
+
+// toList converts the items of a list response.
+func toList[T, M any](items []T, convert func(*T) *M) []*M {
+	out := make([]*M, 0, len(items))
+	for i := range items {
+		out = append(out, convert(&items[i]))
+	}
+
+	return out
+}
@@ -0,0 +1,93 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/rom8726/floxy-manager/internal/api/rest/middlewares"
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+// scopedRequest is implemented by all request messages of WorkflowReadService.
+type scopedRequest interface {
+	GetTenantId() int64
+	GetProjectId() int64
+}
+
+// StatusInterceptor logs the calls failed with errors carrying no gRPC status and sends them
+// to the client as an Internal status without details.
+func StatusInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+
+		slog.ErrorContext(ctx, "gRPC call failed", "method", info.FullMethod, "error", err)
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+}
+
+// AuthInterceptor authenticates calls by the "authorization: Bearer <token>" metadata carrying
+// an access token or an API token. All calls are reads, so read-only API tokens are accepted.
+func AuthInterceptor(
+	tokenizer contract.Tokenizer,
+	usersSrv contract.UsersUseCase,
+	apiTokens contract.APITokensUseCase,
+) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		var token string
+		if values := metadata.ValueFromIncomingContext(ctx, "authorization"); len(values) > 0 {
+			token, _ = strings.CutPrefix(values[0], "Bearer ")
+		}
+
+		if token == "" {
+			return nil, status.Error(codes.Unauthenticated, "bearer token is required")
+		}
+
+		ctx, err := middlewares.Authenticate(ctx, http.MethodGet, token, tokenizer, usersSrv, apiTokens)
+		if err != nil {
+			if errors.Is(err, middlewares.ErrAPITokenScope) {
+				return nil, status.Error(codes.PermissionDenied, err.Error())
+			}
+
+			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// ScopeInterceptor requires the tenant and project of the request and checks the caller can view the project.
+func ScopeInterceptor(permissionsSrv contract.PermissionsService) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		scoped, ok := req.(scopedRequest)
+		if !ok || scoped.GetTenantId() <= 0 || scoped.GetProjectId() <= 0 {
+			return nil, status.Error(codes.InvalidArgument, "tenant_id and project_id are required")
+		}
+
+		if err := permissionsSrv.CanViewProject(ctx, domain.ProjectID(scoped.GetProjectId())); err != nil {
+			if errors.Is(err, domain.ErrPermissionDenied) {
+				return nil, status.Error(codes.PermissionDenied, "access denied to this project")
+			}
+
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
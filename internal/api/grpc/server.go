@@ -0,0 +1,143 @@
+// Package grpc serves workflow read models over gRPC for internal services.
+//
+// The service is described in workflows.proto, the stubs in workflowspb are generated
+// from it with `make proto`.
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
+
+	"github.com/rom8726/floxy-manager/internal/api/grpc/workflowspb"
+	"github.com/rom8726/floxy-manager/internal/contract"
+)
+
+const (
+	// maxRequestSize bounds request messages, which only carry scalar filters.
+	maxRequestSize = 64 << 10
+
+	// keepaliveMinTime is the shortest ping interval allowed to clients.
+	keepaliveMinTime = 10 * time.Second
+
+	shutdownTimeout = 5 * time.Second
+)
+
+type Config struct {
+	Addr string
+	// ReadHeaderTimeout bounds the TLS and HTTP/2 handshake of new connections.
+	ReadHeaderTimeout time.Duration
+	// IdleTimeout closes connections without calls for this long.
+	IdleTimeout time.Duration
+	// KeepaliveTime and KeepaliveTimeout ping idle connections and close the ones not answering.
+	KeepaliveTime    time.Duration
+	KeepaliveTimeout time.Duration
+	CertFile         string
+	KeyFile          string
+	UseTLS           bool
+}
+
+type Server struct {
+	addr   string
+	server *grpc.Server
+}
+
+func NewServer(
+	cfg *Config,
+	workflowsRepo contract.WorkflowsRepository,
+	permissionsSrv contract.PermissionsService,
+	tokenizer contract.Tokenizer,
+	usersSrv contract.UsersUseCase,
+	apiTokens contract.APITokensUseCase,
+	redactor contract.PayloadRedactor,
+) (*Server, error) {
+	opts := []grpc.ServerOption{
+		grpc.ConnectionTimeout(cfg.ReadHeaderTimeout),
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			MaxConnectionIdle: cfg.IdleTimeout,
+			Time:              cfg.KeepaliveTime,
+			Timeout:           cfg.KeepaliveTimeout,
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             keepaliveMinTime,
+			PermitWithoutStream: true,
+		}),
+		grpc.MaxRecvMsgSize(maxRequestSize),
+		grpc.ChainUnaryInterceptor(
+			StatusInterceptor(),
+			AuthInterceptor(tokenizer, usersSrv, apiTokens),
+			ScopeInterceptor(permissionsSrv),
+		),
+	}
+
+	if cfg.UseTLS {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load TLS key pair: %w", err)
+		}
+
+		opts = append(opts, grpc.Creds(credentials.NewTLS(&tls.Config{
+			MinVersion:   tls.VersionTLS12,
+			Certificates: []tls.Certificate{cert},
+		})))
+	}
+
+	service := &workflowsService{workflowsRepo: workflowsRepo, permissionsSrv: permissionsSrv, redactor: redactor}
+
+	return newServer(cfg.Addr, service, opts...), nil
+}
+
+func newServer(addr string, service workflowspb.WorkflowReadServiceServer, opts ...grpc.ServerOption) *Server {
+	server := grpc.NewServer(opts...)
+	workflowspb.RegisterWorkflowReadServiceServer(server, service)
+
+	return &Server{
+		addr:   addr,
+		server: server,
+	}
+}
+
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	lis, err := net.Listen("tcp", s.addr) //nolint:noctx // need to refactor
+	if err != nil {
+		return fmt.Errorf("listen %q: %w", s.addr, err)
+	}
+
+	return s.Serve(ctx, lis)
+}
+
+// Serve serves the calls until ctx is done, then waits for the running calls to finish.
+func (s *Server) Serve(ctx context.Context, lis net.Listener) error {
+	errc := make(chan error, 1)
+
+	go func() {
+		errc <- s.server.Serve(lis)
+		close(errc)
+	}()
+
+	select {
+	case <-ctx.Done():
+	case err := <-errc:
+		return err
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(shutdownTimeout):
+		s.server.Stop()
+	}
+
+	return <-errc
+}
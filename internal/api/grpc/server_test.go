@@ -0,0 +1,183 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/rom8726/floxy-manager/internal/api/grpc/workflowspb"
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type fakeWorkflowsRepo struct {
+	contract.WorkflowsRepository
+	definition domain.WorkflowDefinition
+}
+
+func (r *fakeWorkflowsRepo) GetWorkflowDefinition(
+	_ context.Context,
+	tenantID domain.TenantID,
+	projectID domain.ProjectID,
+	id string,
+) (domain.WorkflowDefinition, error) {
+	if tenantID != r.definition.TenantID || projectID != r.definition.ProjectID || id != r.definition.ID {
+		return domain.WorkflowDefinition{}, domain.ErrEntityNotFound
+	}
+
+	return r.definition, nil
+}
+
+// dial serves the service with the interceptors on an in-memory listener and returns a client.
+func dial(
+	t *testing.T,
+	service workflowspb.WorkflowReadServiceServer,
+	interceptors ...grpc.UnaryServerInterceptor,
+) workflowspb.WorkflowReadServiceClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	srv := newServer("", service, grpc.ChainUnaryInterceptor(interceptors...))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- srv.Serve(ctx, lis) }()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+		cancel()
+		require.NoError(t, <-done)
+	})
+
+	return workflowspb.NewWorkflowReadServiceClient(conn)
+}
+
+func TestServer_GetWorkflowDefinition(t *testing.T) {
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	service := &workflowsService{workflowsRepo: &fakeWorkflowsRepo{definition: domain.WorkflowDefinition{
+		TenantID:   1,
+		ProjectID:  2,
+		ID:         "wf-1",
+		Name:       "orders",
+		Version:    3,
+		Definition: []byte(`{"steps":[]}`),
+		CreatedAt:  createdAt,
+	}}}
+	client := dial(t, service, StatusInterceptor())
+
+	resp, err := client.GetWorkflowDefinition(context.Background(),
+		&workflowspb.GetWorkflowDefinitionRequest{TenantId: 1, ProjectId: 2, Id: "wf-1"})
+	require.NoError(t, err)
+
+	assert.True(t, proto.Equal(&workflowspb.WorkflowDefinition{
+		TenantId:   1,
+		ProjectId:  2,
+		Id:         "wf-1",
+		Name:       "orders",
+		Version:    3,
+		Definition: `{"steps":[]}`,
+		CreatedAt:  timestamppb.New(createdAt),
+	}, resp), resp)
+
+	_, err = client.GetWorkflowDefinition(context.Background(),
+		&workflowspb.GetWorkflowDefinitionRequest{TenantId: 1, ProjectId: 2, Id: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+type failingStats struct {
+	workflowspb.UnimplementedWorkflowReadServiceServer
+}
+
+func (failingStats) ListWorkflowStats(
+	context.Context,
+	*workflowspb.ListWorkflowStatsRequest,
+) (*workflowspb.ListWorkflowStatsResponse, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestServer_Interceptors(t *testing.T) {
+	requireToken := func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if len(metadata.ValueFromIncomingContext(ctx, "authorization")) == 0 {
+			return nil, status.Error(codes.Unauthenticated, "bearer token is required")
+		}
+
+		return handler(ctx, req)
+	}
+	client := dial(t, failingStats{}, StatusInterceptor(), requireToken, ScopeInterceptor(&fakePermissions{}))
+
+	_, err := client.ListWorkflowStats(context.Background(), &workflowspb.ListWorkflowStatsRequest{})
+	st := status.Convert(err)
+	assert.Equal(t, codes.Unauthenticated, st.Code())
+	assert.Equal(t, "bearer token is required", st.Message())
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer token")
+
+	_, err = client.ListWorkflowStats(ctx, &workflowspb.ListWorkflowStatsRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// Internal errors are not exposed to clients
+	_, err = client.ListWorkflowStats(ctx, &workflowspb.ListWorkflowStatsRequest{TenantId: 1, ProjectId: 2})
+	st = status.Convert(err)
+	assert.Equal(t, codes.Internal, st.Code())
+	assert.Equal(t, "internal error", st.Message())
+
+	_, err = client.ListWorkflowSteps(ctx, &workflowspb.ListWorkflowStepsRequest{TenantId: 1, ProjectId: 2})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
+
+func TestPageOf_Defaults(t *testing.T) {
+	page, pageSize := pageOf(0, -1)
+	assert.Equal(t, defaultPage, page)
+	assert.Equal(t, defaultPageSize, pageSize)
+
+	page, pageSize = pageOf(3, 50)
+	assert.Equal(t, 3, page)
+	assert.Equal(t, 50, pageSize)
+}
+
+type fakePermissions struct {
+	contract.PermissionsService
+	dataView error
+}
+
+func (p *fakePermissions) CanViewProject(context.Context, domain.ProjectID) error {
+	return nil
+}
+
+func (p *fakePermissions) CanViewWorkflowData(context.Context, domain.ProjectID) error {
+	return p.dataView
+}
+
+func TestOmitWorkflowData(t *testing.T) {
+	items := []domain.WorkflowInstance{{ID: 1, Status: "completed", Input: []byte(`{"a":1}`), Output: []byte(`{}`)}}
+
+	require.NoError(t, omitWorkflowData(context.Background(), &fakePermissions{}, 1, items))
+	assert.JSONEq(t, `{"a":1}`, string(items[0].Input))
+
+	require.NoError(t, omitWorkflowData(context.Background(),
+		&fakePermissions{dataView: domain.ErrPermissionDenied}, 1, items))
+	assert.Nil(t, items[0].Input)
+	assert.Nil(t, items[0].Output)
+	assert.Equal(t, "completed", items[0].Status)
+}
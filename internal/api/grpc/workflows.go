@@ -0,0 +1,237 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/rom8726/floxy-manager/internal/api/grpc/workflowspb"
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+// workflowsService implements WorkflowReadService on top of WorkflowsRepository.
+// ScopeInterceptor has checked the tenant and project of the requests.
+type workflowsService struct {
+	workflowspb.UnimplementedWorkflowReadServiceServer
+
+	workflowsRepo  contract.WorkflowsRepository
+	permissionsSrv contract.PermissionsService
+	redactor       contract.PayloadRedactor
+}
+
+func (s *workflowsService) ListWorkflowDefinitions(
+	ctx context.Context,
+	req *workflowspb.ListWorkflowDefinitionsRequest,
+) (*workflowspb.ListWorkflowDefinitionsResponse, error) {
+	page, pageSize := pageOf(req.GetPage(), req.GetPageSize())
+
+	items, total, err := s.workflowsRepo.ListWorkflowDefinitions(ctx,
+		domain.TenantID(req.GetTenantId()), domain.ProjectID(req.GetProjectId()), page, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("list workflow definitions: %w", err)
+	}
+
+	return &workflowspb.ListWorkflowDefinitionsResponse{
+		Items:    toList(items, toWorkflowDefinition),
+		Total:    int64(total),
+		Page:     int32(page),
+		PageSize: int32(pageSize),
+	}, nil
+}
+
+func (s *workflowsService) GetWorkflowDefinition(
+	ctx context.Context,
+	req *workflowspb.GetWorkflowDefinitionRequest,
+) (*workflowspb.WorkflowDefinition, error) {
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	item, err := s.workflowsRepo.GetWorkflowDefinition(ctx,
+		domain.TenantID(req.GetTenantId()), domain.ProjectID(req.GetProjectId()), req.GetId())
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			return nil, status.Error(codes.NotFound, "workflow not found")
+		}
+
+		return nil, fmt.Errorf("get workflow definition: %w", err)
+	}
+
+	return toWorkflowDefinition(&item), nil
+}
+
+func (s *workflowsService) ListWorkflowInstances(
+	ctx context.Context,
+	req *workflowspb.ListWorkflowInstancesRequest,
+) (*workflowspb.ListWorkflowInstancesResponse, error) {
+	projectID := domain.ProjectID(req.GetProjectId())
+	page, pageSize := pageOf(req.GetPage(), req.GetPageSize())
+
+	items, total, err := s.workflowsRepo.ListWorkflowInstances(ctx, domain.TenantID(req.GetTenantId()), projectID,
+		req.GetWorkflowId(), domain.FieldSet{}, page, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("list workflow instances: %w", err)
+	}
+
+	if err := s.redactor.RedactInstances(ctx, items); err != nil {
+		return nil, fmt.Errorf("redact workflow instances: %w", err)
+	}
+
+	if err := omitWorkflowData(ctx, s.permissionsSrv, projectID, items); err != nil {
+		return nil, err
+	}
+
+	return &workflowspb.ListWorkflowInstancesResponse{
+		Items:    toList(items, toWorkflowInstance),
+		Total:    int64(total),
+		Page:     int32(page),
+		PageSize: int32(pageSize),
+	}, nil
+}
+
+func (s *workflowsService) GetWorkflowInstance(
+	ctx context.Context,
+	req *workflowspb.GetWorkflowInstanceRequest,
+) (*workflowspb.WorkflowInstance, error) {
+	if req.GetInstanceId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "instance_id is required")
+	}
+
+	projectID := domain.ProjectID(req.GetProjectId())
+
+	item, err := s.workflowsRepo.GetWorkflowInstance(ctx, domain.TenantID(req.GetTenantId()), projectID,
+		int(req.GetInstanceId()))
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			return nil, status.Error(codes.NotFound, "workflow instance not found")
+		}
+
+		return nil, fmt.Errorf("get workflow instance: %w", err)
+	}
+
+	items := []domain.WorkflowInstance{item}
+	if err := s.redactor.RedactInstances(ctx, items); err != nil {
+		return nil, fmt.Errorf("redact workflow instance: %w", err)
+	}
+
+	if err := omitWorkflowData(ctx, s.permissionsSrv, projectID, items); err != nil {
+		return nil, err
+	}
+
+	return toWorkflowInstance(&items[0]), nil
+}
+
+func (s *workflowsService) ListWorkflowSteps(
+	ctx context.Context,
+	req *workflowspb.ListWorkflowStepsRequest,
+) (*workflowspb.ListWorkflowStepsResponse, error) {
+	if req.GetInstanceId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "instance_id is required")
+	}
+
+	projectID := domain.ProjectID(req.GetProjectId())
+	page, pageSize := pageOf(req.GetPage(), req.GetPageSize())
+
+	items, total, err := s.workflowsRepo.ListWorkflowSteps(ctx, domain.TenantID(req.GetTenantId()), projectID,
+		int(req.GetInstanceId()), domain.FieldSet{}, page, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("list workflow steps: %w", err)
+	}
+
+	if err := s.redactor.RedactSteps(ctx, items); err != nil {
+		return nil, fmt.Errorf("redact workflow steps: %w", err)
+	}
+
+	if err := omitWorkflowData(ctx, s.permissionsSrv, projectID, items); err != nil {
+		return nil, err
+	}
+
+	return &workflowspb.ListWorkflowStepsResponse{
+		Items:    toList(items, toWorkflowStep),
+		Total:    int64(total),
+		Page:     int32(page),
+		PageSize: int32(pageSize),
+	}, nil
+}
+
+func (s *workflowsService) ListWorkflowEvents(
+	ctx context.Context,
+	req *workflowspb.ListWorkflowEventsRequest,
+) (*workflowspb.ListWorkflowEventsResponse, error) {
+	if req.GetInstanceId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "instance_id is required")
+	}
+
+	projectID := domain.ProjectID(req.GetProjectId())
+	page, pageSize := pageOf(req.GetPage(), req.GetPageSize())
+
+	items, total, err := s.workflowsRepo.ListWorkflowEvents(ctx, domain.TenantID(req.GetTenantId()), projectID,
+		int(req.GetInstanceId()), page, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("list workflow events: %w", err)
+	}
+
+	if err := s.redactor.RedactEvents(ctx, items); err != nil {
+		return nil, fmt.Errorf("redact workflow events: %w", err)
+	}
+
+	if err := omitWorkflowData(ctx, s.permissionsSrv, projectID, items); err != nil {
+		return nil, err
+	}
+
+	return &workflowspb.ListWorkflowEventsResponse{
+		Items:    toList(items, toWorkflowEvent),
+		Total:    int64(total),
+		Page:     int32(page),
+		PageSize: int32(pageSize),
+	}, nil
+}
+
+func (s *workflowsService) ListWorkflowStats(
+	ctx context.Context,
+	req *workflowspb.ListWorkflowStatsRequest,
+) (*workflowspb.ListWorkflowStatsResponse, error) {
+	page, pageSize := pageOf(req.GetPage(), req.GetPageSize())
+
+	items, total, err := s.workflowsRepo.ListWorkflowStats(ctx,
+		domain.TenantID(req.GetTenantId()), domain.ProjectID(req.GetProjectId()), page, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("list workflow stats: %w", err)
+	}
+
+	return &workflowspb.ListWorkflowStatsResponse{
+		Items:    toList(items, toWorkflowStat),
+		Total:    int64(total),
+		Page:     int32(page),
+		PageSize: int32(pageSize),
+	}, nil
+}
+
+// omitWorkflowData clears the payloads of the items when the caller may not see workflow data of the project.
+func omitWorkflowData[T any, PT interface {
+	*T
+	OmitPayloads()
+}](
+	ctx context.Context,
+	permissionsSrv contract.PermissionsService,
+	projectID domain.ProjectID,
+	items []T,
+) error {
+	err := permissionsSrv.CanViewWorkflowData(ctx, projectID)
+	switch {
+	case err == nil:
+		return nil
+	case !errors.Is(err, domain.ErrPermissionDenied):
+		return fmt.Errorf("check workflow data permission: %w", err)
+	}
+
+	for i := range items {
+		PT(&items[i]).OmitPayloads()
+	}
+
+	return nil
+}
@@ -0,0 +1,355 @@
+// Read models of floxy workflows exposed by floxy-manager over gRPC.
+//
+// Every call requires the "authorization: Bearer <token>" metadata with an access token
+// or an API token (flx_...) and is scoped to a tenant and project the caller can view.
+// All request messages keep tenant_id = 1 and project_id = 2.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: workflows.proto
+
+package workflowspb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	WorkflowReadService_ListWorkflowDefinitions_FullMethodName = "/floxy.manager.v1.WorkflowReadService/ListWorkflowDefinitions"
+	WorkflowReadService_GetWorkflowDefinition_FullMethodName   = "/floxy.manager.v1.WorkflowReadService/GetWorkflowDefinition"
+	WorkflowReadService_ListWorkflowInstances_FullMethodName   = "/floxy.manager.v1.WorkflowReadService/ListWorkflowInstances"
+	WorkflowReadService_GetWorkflowInstance_FullMethodName     = "/floxy.manager.v1.WorkflowReadService/GetWorkflowInstance"
+	WorkflowReadService_ListWorkflowSteps_FullMethodName       = "/floxy.manager.v1.WorkflowReadService/ListWorkflowSteps"
+	WorkflowReadService_ListWorkflowEvents_FullMethodName      = "/floxy.manager.v1.WorkflowReadService/ListWorkflowEvents"
+	WorkflowReadService_ListWorkflowStats_FullMethodName       = "/floxy.manager.v1.WorkflowReadService/ListWorkflowStats"
+)
+
+// WorkflowReadServiceClient is the client API for WorkflowReadService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type WorkflowReadServiceClient interface {
+	ListWorkflowDefinitions(ctx context.Context, in *ListWorkflowDefinitionsRequest, opts ...grpc.CallOption) (*ListWorkflowDefinitionsResponse, error)
+	GetWorkflowDefinition(ctx context.Context, in *GetWorkflowDefinitionRequest, opts ...grpc.CallOption) (*WorkflowDefinition, error)
+	ListWorkflowInstances(ctx context.Context, in *ListWorkflowInstancesRequest, opts ...grpc.CallOption) (*ListWorkflowInstancesResponse, error)
+	GetWorkflowInstance(ctx context.Context, in *GetWorkflowInstanceRequest, opts ...grpc.CallOption) (*WorkflowInstance, error)
+	ListWorkflowSteps(ctx context.Context, in *ListWorkflowStepsRequest, opts ...grpc.CallOption) (*ListWorkflowStepsResponse, error)
+	ListWorkflowEvents(ctx context.Context, in *ListWorkflowEventsRequest, opts ...grpc.CallOption) (*ListWorkflowEventsResponse, error)
+	ListWorkflowStats(ctx context.Context, in *ListWorkflowStatsRequest, opts ...grpc.CallOption) (*ListWorkflowStatsResponse, error)
+}
+
+type workflowReadServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWorkflowReadServiceClient(cc grpc.ClientConnInterface) WorkflowReadServiceClient {
+	return &workflowReadServiceClient{cc}
+}
+
+func (c *workflowReadServiceClient) ListWorkflowDefinitions(ctx context.Context, in *ListWorkflowDefinitionsRequest, opts ...grpc.CallOption) (*ListWorkflowDefinitionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListWorkflowDefinitionsResponse)
+	err := c.cc.Invoke(ctx, WorkflowReadService_ListWorkflowDefinitions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowReadServiceClient) GetWorkflowDefinition(ctx context.Context, in *GetWorkflowDefinitionRequest, opts ...grpc.CallOption) (*WorkflowDefinition, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WorkflowDefinition)
+	err := c.cc.Invoke(ctx, WorkflowReadService_GetWorkflowDefinition_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowReadServiceClient) ListWorkflowInstances(ctx context.Context, in *ListWorkflowInstancesRequest, opts ...grpc.CallOption) (*ListWorkflowInstancesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListWorkflowInstancesResponse)
+	err := c.cc.Invoke(ctx, WorkflowReadService_ListWorkflowInstances_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowReadServiceClient) GetWorkflowInstance(ctx context.Context, in *GetWorkflowInstanceRequest, opts ...grpc.CallOption) (*WorkflowInstance, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WorkflowInstance)
+	err := c.cc.Invoke(ctx, WorkflowReadService_GetWorkflowInstance_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowReadServiceClient) ListWorkflowSteps(ctx context.Context, in *ListWorkflowStepsRequest, opts ...grpc.CallOption) (*ListWorkflowStepsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListWorkflowStepsResponse)
+	err := c.cc.Invoke(ctx, WorkflowReadService_ListWorkflowSteps_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowReadServiceClient) ListWorkflowEvents(ctx context.Context, in *ListWorkflowEventsRequest, opts ...grpc.CallOption) (*ListWorkflowEventsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListWorkflowEventsResponse)
+	err := c.cc.Invoke(ctx, WorkflowReadService_ListWorkflowEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowReadServiceClient) ListWorkflowStats(ctx context.Context, in *ListWorkflowStatsRequest, opts ...grpc.CallOption) (*ListWorkflowStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListWorkflowStatsResponse)
+	err := c.cc.Invoke(ctx, WorkflowReadService_ListWorkflowStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// WorkflowReadServiceServer is the server API for WorkflowReadService service.
+// All implementations must embed UnimplementedWorkflowReadServiceServer
+// for forward compatibility.
+type WorkflowReadServiceServer interface {
+	ListWorkflowDefinitions(context.Context, *ListWorkflowDefinitionsRequest) (*ListWorkflowDefinitionsResponse, error)
+	GetWorkflowDefinition(context.Context, *GetWorkflowDefinitionRequest) (*WorkflowDefinition, error)
+	ListWorkflowInstances(context.Context, *ListWorkflowInstancesRequest) (*ListWorkflowInstancesResponse, error)
+	GetWorkflowInstance(context.Context, *GetWorkflowInstanceRequest) (*WorkflowInstance, error)
+	ListWorkflowSteps(context.Context, *ListWorkflowStepsRequest) (*ListWorkflowStepsResponse, error)
+	ListWorkflowEvents(context.Context, *ListWorkflowEventsRequest) (*ListWorkflowEventsResponse, error)
+	ListWorkflowStats(context.Context, *ListWorkflowStatsRequest) (*ListWorkflowStatsResponse, error)
+	mustEmbedUnimplementedWorkflowReadServiceServer()
+}
+
+// UnimplementedWorkflowReadServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedWorkflowReadServiceServer struct{}
+
+func (UnimplementedWorkflowReadServiceServer) ListWorkflowDefinitions(context.Context, *ListWorkflowDefinitionsRequest) (*ListWorkflowDefinitionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWorkflowDefinitions not implemented")
+}
+func (UnimplementedWorkflowReadServiceServer) GetWorkflowDefinition(context.Context, *GetWorkflowDefinitionRequest) (*WorkflowDefinition, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWorkflowDefinition not implemented")
+}
+func (UnimplementedWorkflowReadServiceServer) ListWorkflowInstances(context.Context, *ListWorkflowInstancesRequest) (*ListWorkflowInstancesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWorkflowInstances not implemented")
+}
+func (UnimplementedWorkflowReadServiceServer) GetWorkflowInstance(context.Context, *GetWorkflowInstanceRequest) (*WorkflowInstance, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWorkflowInstance not implemented")
+}
+func (UnimplementedWorkflowReadServiceServer) ListWorkflowSteps(context.Context, *ListWorkflowStepsRequest) (*ListWorkflowStepsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWorkflowSteps not implemented")
+}
+func (UnimplementedWorkflowReadServiceServer) ListWorkflowEvents(context.Context, *ListWorkflowEventsRequest) (*ListWorkflowEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWorkflowEvents not implemented")
+}
+func (UnimplementedWorkflowReadServiceServer) ListWorkflowStats(context.Context, *ListWorkflowStatsRequest) (*ListWorkflowStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWorkflowStats not implemented")
+}
+func (UnimplementedWorkflowReadServiceServer) mustEmbedUnimplementedWorkflowReadServiceServer() {}
+func (UnimplementedWorkflowReadServiceServer) testEmbeddedByValue()                             {}
+
+// UnsafeWorkflowReadServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to WorkflowReadServiceServer will
+// result in compilation errors.
+type UnsafeWorkflowReadServiceServer interface {
+	mustEmbedUnimplementedWorkflowReadServiceServer()
+}
+
+func RegisterWorkflowReadServiceServer(s grpc.ServiceRegistrar, srv WorkflowReadServiceServer) {
+	// If the following call pancis, it indicates UnimplementedWorkflowReadServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&WorkflowReadService_ServiceDesc, srv)
+}
+
+func _WorkflowReadService_ListWorkflowDefinitions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWorkflowDefinitionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowReadServiceServer).ListWorkflowDefinitions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowReadService_ListWorkflowDefinitions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowReadServiceServer).ListWorkflowDefinitions(ctx, req.(*ListWorkflowDefinitionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowReadService_GetWorkflowDefinition_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWorkflowDefinitionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowReadServiceServer).GetWorkflowDefinition(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowReadService_GetWorkflowDefinition_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowReadServiceServer).GetWorkflowDefinition(ctx, req.(*GetWorkflowDefinitionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowReadService_ListWorkflowInstances_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWorkflowInstancesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowReadServiceServer).ListWorkflowInstances(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowReadService_ListWorkflowInstances_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowReadServiceServer).ListWorkflowInstances(ctx, req.(*ListWorkflowInstancesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowReadService_GetWorkflowInstance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWorkflowInstanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowReadServiceServer).GetWorkflowInstance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowReadService_GetWorkflowInstance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowReadServiceServer).GetWorkflowInstance(ctx, req.(*GetWorkflowInstanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowReadService_ListWorkflowSteps_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWorkflowStepsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowReadServiceServer).ListWorkflowSteps(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowReadService_ListWorkflowSteps_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowReadServiceServer).ListWorkflowSteps(ctx, req.(*ListWorkflowStepsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowReadService_ListWorkflowEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWorkflowEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowReadServiceServer).ListWorkflowEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowReadService_ListWorkflowEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowReadServiceServer).ListWorkflowEvents(ctx, req.(*ListWorkflowEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowReadService_ListWorkflowStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWorkflowStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowReadServiceServer).ListWorkflowStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkflowReadService_ListWorkflowStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowReadServiceServer).ListWorkflowStats(ctx, req.(*ListWorkflowStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// WorkflowReadService_ServiceDesc is the grpc.ServiceDesc for WorkflowReadService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var WorkflowReadService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "floxy.manager.v1.WorkflowReadService",
+	HandlerType: (*WorkflowReadServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListWorkflowDefinitions",
+			Handler:    _WorkflowReadService_ListWorkflowDefinitions_Handler,
+		},
+		{
+			MethodName: "GetWorkflowDefinition",
+			Handler:    _WorkflowReadService_GetWorkflowDefinition_Handler,
+		},
+		{
+			MethodName: "ListWorkflowInstances",
+			Handler:    _WorkflowReadService_ListWorkflowInstances_Handler,
+		},
+		{
+			MethodName: "GetWorkflowInstance",
+			Handler:    _WorkflowReadService_GetWorkflowInstance_Handler,
+		},
+		{
+			MethodName: "ListWorkflowSteps",
+			Handler:    _WorkflowReadService_ListWorkflowSteps_Handler,
+		},
+		{
+			MethodName: "ListWorkflowEvents",
+			Handler:    _WorkflowReadService_ListWorkflowEvents_Handler,
+		},
+		{
+			MethodName: "ListWorkflowStats",
+			Handler:    _WorkflowReadService_ListWorkflowStats_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "workflows.proto",
+}
@@ -0,0 +1,103 @@
+// Package apierror writes the error responses of the REST API, all of them share one envelope
+// with a machine-readable code and the ID of the request.
+package apierror
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Codes of the error responses, clients should match them instead of the messages.
+const (
+	CodeInvalidRequest     = "invalid_request"
+	CodeValidationFailed   = "validation_failed"
+	CodeUnauthorized       = "unauthorized"
+	CodeForbidden          = "forbidden"
+	CodeLicenseNotAccepted = "license_not_accepted"
+	CodeFeatureNotLicensed = "feature_not_licensed"
+	CodeNotFound           = "not_found"
+	CodeMethodNotAllowed   = "method_not_allowed"
+	CodeConflict           = "conflict"
+	CodeAlreadyExists      = "already_exists"
+	CodeInUse              = "in_use"
+	CodePreconditionFailed = "precondition_failed"
+	CodeTooLarge           = "payload_too_large"
+	CodeUnprocessable      = "unprocessable"
+	CodeRateLimited        = "rate_limited"
+	CodeInternal           = "internal_error"
+	CodeNotImplemented     = "not_implemented"
+	CodeUnavailable        = "unavailable"
+	CodeTimeout            = "timeout"
+)
+
+// requestIDHeader is the response header set by middlewares.RequestIDMdw.
+const requestIDHeader = "X-Request-Id"
+
+// Response is the body of every error response. Error repeats Message for clients
+// reading the former {"error": "..."} body.
+type Response struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Details   any    `json:"details,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+	Error     string `json:"error"`
+}
+
+// Respond writes the error response with the code of the status.
+func Respond(w http.ResponseWriter, status int, message string) {
+	RespondDetails(w, status, CodeForStatus(status), message, nil)
+}
+
+// RespondDetails writes the error response with an explicit code and details,
+// e.g. the invalid fields of the request.
+func RespondDetails(w http.ResponseWriter, status int, code, message string, details any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(Response{
+		Code:      code,
+		Message:   message,
+		Details:   details,
+		RequestID: w.Header().Get(requestIDHeader),
+		Error:     message,
+	})
+}
+
+// CodeForStatus returns the default code of the status.
+func CodeForStatus(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeInvalidRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusMethodNotAllowed:
+		return CodeMethodNotAllowed
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusPreconditionFailed:
+		return CodePreconditionFailed
+	case http.StatusRequestEntityTooLarge:
+		return CodeTooLarge
+	case http.StatusUnprocessableEntity:
+		return CodeUnprocessable
+	case http.StatusTooManyRequests:
+		return CodeRateLimited
+	case http.StatusNotImplemented:
+		return CodeNotImplemented
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return CodeUnavailable
+	case http.StatusGatewayTimeout:
+		return CodeTimeout
+	}
+
+	if status >= http.StatusInternalServerError {
+		return CodeInternal
+	}
+
+	return CodeInvalidRequest
+}
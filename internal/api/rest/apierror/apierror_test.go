@@ -0,0 +1,38 @@
+package apierror
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRespond(t *testing.T) {
+	rec := httptest.NewRecorder()
+	rec.Header().Set(requestIDHeader, "req-1")
+
+	Respond(rec, http.StatusNotFound, "Project not found")
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var resp Response
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, Response{
+		Code:      CodeNotFound,
+		Message:   "Project not found",
+		RequestID: "req-1",
+		Error:     "Project not found",
+	}, resp)
+}
+
+func TestCodeForStatus(t *testing.T) {
+	assert.Equal(t, CodeInvalidRequest, CodeForStatus(http.StatusBadRequest))
+	assert.Equal(t, CodeConflict, CodeForStatus(http.StatusConflict))
+	assert.Equal(t, CodeTimeout, CodeForStatus(http.StatusGatewayTimeout))
+	assert.Equal(t, CodeInternal, CodeForStatus(http.StatusInternalServerError))
+	assert.Equal(t, CodeInternal, CodeForStatus(http.StatusHTTPVersionNotSupported))
+}
@@ -1,11 +1,12 @@
 package handlers
 
 import (
-	"encoding/json"
+	"errors"
 	"net/http"
 
 	appcontext "github.com/rom8726/floxy-manager/internal/context"
 	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
 )
 
 type TwoFAHandler struct {
@@ -20,27 +21,26 @@ func NewTwoFAHandler(usersService contract.UsersUseCase) *TwoFAHandler {
 
 func (h *TwoFAHandler) Verify2FA(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	var req struct {
-		Code      string `json:"code"`
-		SessionID string `json:"session_id"`
+		Code      string `json:"code" validate:"required"`
+		SessionID string `json:"session_id" validate:"required"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request", http.StatusBadRequest)
-		return
-	}
-
-	if req.Code == "" || req.SessionID == "" {
-		respondError(w, http.StatusBadRequest, "code and session_id are required")
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
 	accessToken, refreshToken, expiresIn, err := h.usersService.Verify2FA(r.Context(), req.Code, req.SessionID)
 	if err != nil {
+		if errors.Is(err, domain.ErrTooMany2FAAttempts) {
+			respondError(w, http.StatusTooManyRequests, err.Error())
+			return
+		}
+
 		respondError(w, http.StatusUnauthorized, "Invalid 2FA code")
 		return
 	}
@@ -54,7 +54,7 @@ func (h *TwoFAHandler) Verify2FA(w http.ResponseWriter, r *http.Request) {
 
 func (h *TwoFAHandler) Setup2FA(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -66,6 +66,11 @@ func (h *TwoFAHandler) Setup2FA(w http.ResponseWriter, r *http.Request) {
 
 	secret, qrURL, qrImage, err := h.usersService.Setup2FA(r.Context(), userID)
 	if err != nil {
+		if errors.Is(err, domain.ErrEmailNotVerified) {
+			respondError(w, http.StatusForbidden, "Verify your email address before setting up 2FA")
+			return
+		}
+
 		respondError(w, http.StatusInternalServerError, "Failed to setup 2FA")
 		return
 	}
@@ -79,7 +84,7 @@ func (h *TwoFAHandler) Setup2FA(w http.ResponseWriter, r *http.Request) {
 
 func (h *TwoFAHandler) Confirm2FA(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -90,33 +95,85 @@ func (h *TwoFAHandler) Confirm2FA(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		Code string `json:"code"`
+		Code string `json:"code" validate:"required"`
+	}
+
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	recoveryCodes, err := h.usersService.Confirm2FA(r.Context(), userID, req.Code)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid code")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message":        "2FA enabled successfully",
+		"recovery_codes": recoveryCodes,
+	})
+}
+
+// GetRecoveryCodes handles GET /api/v1/auth/2fa/recovery-codes and returns the number of unused codes.
+func (h *TwoFAHandler) GetRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userID := appcontext.UserID(r.Context())
+	if userID == 0 {
+		respondError(w, http.StatusUnauthorized, "Authentication required")
+		return
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request", http.StatusBadRequest)
+	remaining, err := h.usersService.CountRecoveryCodes(r.Context(), userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get recovery codes")
 		return
 	}
 
-	if req.Code == "" {
-		respondError(w, http.StatusBadRequest, "code is required")
+	respondJSON(w, http.StatusOK, map[string]int{
+		"remaining": remaining,
+	})
+}
+
+// RegenerateRecoveryCodes handles POST /api/v1/auth/2fa/recovery-codes.
+// Previous codes stop working; a current TOTP code is required.
+func (h *TwoFAHandler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
-	err := h.usersService.Confirm2FA(r.Context(), userID, req.Code)
+	userID := appcontext.UserID(r.Context())
+	if userID == 0 {
+		respondError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req struct {
+		Code string `json:"code" validate:"required"`
+	}
+
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	recoveryCodes, err := h.usersService.RegenerateRecoveryCodes(r.Context(), userID, req.Code)
 	if err != nil {
 		respondError(w, http.StatusBadRequest, "Invalid code")
 		return
 	}
 
-	respondJSON(w, http.StatusOK, map[string]string{
-		"message": "2FA enabled successfully",
+	respondJSON(w, http.StatusOK, map[string][]string{
+		"recovery_codes": recoveryCodes,
 	})
 }
 
 func (h *TwoFAHandler) Send2FACode(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -130,8 +187,7 @@ func (h *TwoFAHandler) Send2FACode(w http.ResponseWriter, r *http.Request) {
 		Action string `json:"action"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request", http.StatusBadRequest)
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
@@ -148,7 +204,7 @@ func (h *TwoFAHandler) Send2FACode(w http.ResponseWriter, r *http.Request) {
 
 func (h *TwoFAHandler) Disable2FA(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -159,16 +215,10 @@ func (h *TwoFAHandler) Disable2FA(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		EmailCode string `json:"email_code"`
+		EmailCode string `json:"email_code" validate:"required"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request", http.StatusBadRequest)
-		return
-	}
-
-	if req.EmailCode == "" {
-		respondError(w, http.StatusBadRequest, "email_code is required")
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
@@ -185,7 +235,7 @@ func (h *TwoFAHandler) Disable2FA(w http.ResponseWriter, r *http.Request) {
 
 func (h *TwoFAHandler) Reset2FA(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -196,16 +246,10 @@ func (h *TwoFAHandler) Reset2FA(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		EmailCode string `json:"email_code"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request", http.StatusBadRequest)
-		return
+		EmailCode string `json:"email_code" validate:"required"`
 	}
 
-	if req.EmailCode == "" {
-		respondError(w, http.StatusBadRequest, "email_code is required")
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	alertsusecase "github.com/rom8726/floxy-manager/internal/usecases/alerts"
+)
+
+type AlertsHandler struct {
+	alertsUseCase  contract.AlertsUseCase
+	permissionsSrv contract.PermissionsService
+}
+
+func NewAlertsHandler(
+	alertsUseCase contract.AlertsUseCase,
+	permissionsSrv contract.PermissionsService,
+) *AlertsHandler {
+	return &AlertsHandler{
+		alertsUseCase:  alertsUseCase,
+		permissionsSrv: permissionsSrv,
+	}
+}
+
+type alertSettingsRequest struct {
+	Enabled                  bool   `json:"enabled"`
+	RecipientRoleKey         string `json:"recipient_role_key"`
+	NotifyOnFailure          bool   `json:"notify_on_failure"`
+	DurationThresholdSeconds *int   `json:"duration_threshold_seconds"`
+	DigestEnabled            bool   `json:"digest_enabled"`
+}
+
+// GetSettings handles GET /api/v1/projects/:id/alerts
+func (h *AlertsHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	projectID, ok := authorizeProjectParam(w, r, h.permissionsSrv, false)
+	if !ok {
+		return
+	}
+
+	settings, err := h.alertsUseCase.GetSettings(r.Context(), projectID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to get alert settings",
+			"error", err,
+			"project_id", projectID,
+		)
+		respondError(w, http.StatusInternalServerError, "Failed to get alert settings")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, settings)
+}
+
+// UpdateSettings handles PUT /api/v1/projects/:id/alerts
+func (h *AlertsHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	projectID, ok := authorizeProjectParam(w, r, h.permissionsSrv, true)
+	if !ok {
+		return
+	}
+
+	var req alertSettingsRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	settings, err := h.alertsUseCase.UpdateSettings(r.Context(), domain.AlertSettings{
+		ProjectID:                projectID,
+		Enabled:                  req.Enabled,
+		RecipientRoleKey:         req.RecipientRoleKey,
+		NotifyOnFailure:          req.NotifyOnFailure,
+		DurationThresholdSeconds: req.DurationThresholdSeconds,
+		DigestEnabled:            req.DigestEnabled,
+	})
+	if err != nil {
+		if errors.Is(err, alertsusecase.ErrInvalidAlertSettings) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		slog.ErrorContext(r.Context(), "Failed to update alert settings",
+			"error", err,
+			"project_id", projectID,
+		)
+		respondError(w, http.StatusInternalServerError, "Failed to update alert settings")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, settings)
+}
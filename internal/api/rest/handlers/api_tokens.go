@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	apitokensusecase "github.com/rom8726/floxy-manager/internal/usecases/apitokens"
+)
+
+type APITokensHandler struct {
+	apiTokensUseCase contract.APITokensUseCase
+}
+
+func NewAPITokensHandler(apiTokensUseCase contract.APITokensUseCase) *APITokensHandler {
+	return &APITokensHandler{
+		apiTokensUseCase: apiTokensUseCase,
+	}
+}
+
+type apiTokenRequest struct {
+	Name      string                 `json:"name"`
+	Scopes    []domain.APITokenScope `json:"scopes"`
+	ExpiresAt *time.Time             `json:"expires_at"`
+}
+
+type apiTokenCreatedResponse struct {
+	domain.APIToken
+	Token string `json:"token"`
+}
+
+// List handles GET /api/v1/users/me/tokens
+func (h *APITokensHandler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	userID := appcontext.UserID(r.Context())
+
+	tokens, err := h.apiTokensUseCase.List(r.Context(), userID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to list api tokens", "error", err)
+		respondError(w, http.StatusInternalServerError, "Failed to list api tokens")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, tokens)
+}
+
+// Create handles POST /api/v1/users/me/tokens. The token value is only returned in this response.
+func (h *APITokensHandler) Create(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	if appcontext.APITokenID(r.Context()) != 0 {
+		respondError(w, http.StatusForbidden, "API tokens cannot be created with an API token")
+		return
+	}
+
+	// An impersonation must not outlive its token
+	if appcontext.Impersonator(r.Context()) != "" {
+		respondError(w, http.StatusForbidden, "API tokens cannot be created while impersonating a user")
+		return
+	}
+
+	var req apiTokenRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	token, plain, err := h.apiTokensUseCase.Create(r.Context(), appcontext.UserID(r.Context()), domain.APITokenDTO{
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, apitokensusecase.ErrInvalidAPIToken):
+			respondError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, domain.ErrEntityAlreadyExists):
+			respondError(w, http.StatusConflict, "API token with this name already exists")
+		case errors.Is(err, domain.ErrEmailNotVerified):
+			respondError(w, http.StatusForbidden, "Verify your email address before creating API tokens")
+		default:
+			slog.ErrorContext(r.Context(), "Failed to create api token", "error", err)
+			respondError(w, http.StatusInternalServerError, "Failed to create api token")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, apiTokenCreatedResponse{APIToken: token, Token: plain})
+}
+
+// Delete handles DELETE /api/v1/users/:id/tokens/:tid, where :id is "me" or a user ID (superusers only).
+func (h *APITokensHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	userID, ok := targetUser(w, r, appcontext.Param(r.Context(), "id"))
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(appcontext.Param(r.Context(), "tid"))
+	if err != nil || id <= 0 {
+		respondError(w, http.StatusBadRequest, "invalid token id")
+		return
+	}
+
+	if err := h.apiTokensUseCase.Delete(r.Context(), userID, domain.APITokenID(id)); err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			respondError(w, http.StatusNotFound, "api token not found")
+			return
+		}
+
+		slog.ErrorContext(r.Context(), "Failed to delete api token",
+			"error", err,
+			"token_id", id,
+		)
+		respondError(w, http.StatusInternalServerError, "Failed to delete api token")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "api token deleted successfully"})
+}
@@ -1,34 +1,105 @@
 package handlers
 
 import (
+	"encoding/csv"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"strconv"
+	"time"
 
 	appcontext "github.com/rom8726/floxy-manager/internal/context"
 	"github.com/rom8726/floxy-manager/internal/contract"
 	"github.com/rom8726/floxy-manager/internal/domain"
+	auditsinksusecase "github.com/rom8726/floxy-manager/internal/usecases/auditsinks"
 )
 
+const (
+	auditExportFormatCSV    = "csv"
+	auditExportFormatNDJSON = "ndjson"
+
+	// auditExportFlushEvery is the number of rows written between flushes of the chunked response.
+	auditExportFlushEvery = 500
+	// auditExportTruncatedTrailer tells after the body whether the export was cut by the row limit.
+	auditExportTruncatedTrailer = "X-Export-Truncated"
+)
+
+var errAuditExportLimitReached = errors.New("audit export row limit reached")
+
 type AuditLogHandler struct {
 	auditLogRepo   contract.AuditLogRepository
 	permissionsSrv contract.PermissionsService
+	settingsSrv    contract.SettingsUseCase
+	auditSinksSrv  contract.AuditSinksUseCase
+	features       contract.LicenseFeatures
 }
 
 func NewAuditLogHandler(
 	auditLogRepo contract.AuditLogRepository,
 	permissionsSrv contract.PermissionsService,
+	settingsSrv contract.SettingsUseCase,
+	auditSinksSrv contract.AuditSinksUseCase,
+	features contract.LicenseFeatures,
 ) *AuditLogHandler {
 	return &AuditLogHandler{
 		auditLogRepo:   auditLogRepo,
 		permissionsSrv: permissionsSrv,
+		settingsSrv:    settingsSrv,
+		auditSinksSrv:  auditSinksSrv,
+		features:       features,
 	}
 }
 
 func (h *AuditLogHandler) List(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	filter, _, ok := h.auditScope(w, r)
+	if !ok {
+		return
+	}
+
+	if !parseAuditFilter(w, r, &filter) {
+		return
+	}
+
+	page, pageSize := parsePagination(r)
+
+	entries, total, err := h.auditLogRepo.List(r.Context(), filter, page, pageSize)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to list audit log",
+			"error", err,
+			"project_id", filter.ProjectID,
+			"tenant_id", filter.TenantID,
+			"page", page,
+			"page_size", pageSize,
+		)
+		respondErr(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"items":     entries,
+		"page":      page,
+		"page_size": pageSize,
+		"total":     total,
+	})
+}
+
+// Export handles GET /api/v1/audit-log/export
+// It streams the filtered audit log of a project, a tenant or the whole system as CSV or NDJSON,
+// newest first, up to the row limit configured by superusers.
+func (h *AuditLogHandler) Export(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -36,57 +107,451 @@ func (h *AuditLogHandler) List(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	projectIDStr := r.URL.Query().Get("project_id")
-	if projectIDStr == "" {
-		respondError(w, http.StatusBadRequest, "project_id is required")
+	if !h.features.IsFeatureAvailable(domain.FeatureAuditExport) {
+		respondErr(w, domain.ErrFeatureNotLicensed)
+		return
+	}
+
+	filter, scopeName, ok := h.auditScope(w, r)
+	if !ok {
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = auditExportFormatCSV
+	}
+	if format != auditExportFormatCSV && format != auditExportFormatNDJSON {
+		respondError(w, http.StatusBadRequest, "format must be csv or ndjson")
+		return
+	}
+
+	if !parseAuditFilter(w, r, &filter) {
 		return
 	}
 
-	projectIDInt, err := strconv.Atoi(projectIDStr)
+	limit, err := h.settingsSrv.GetAuditExportRowLimit(r.Context())
 	if err != nil {
-		respondError(w, http.StatusBadRequest, "invalid project_id")
+		slog.ErrorContext(r.Context(), "Failed to get audit export row limit", "error", err)
+		respondError(w, http.StatusInternalServerError, "Failed to export audit log")
 		return
 	}
-	projectID := domain.ProjectID(projectIDInt)
 
+	rc := http.NewResponseController(w)
+	// Large exports may take longer than the server write timeout
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	csvWriter := csv.NewWriter(w)
+	encoder := json.NewEncoder(w)
+
+	var (
+		started   bool
+		rows      int
+		truncated bool
+	)
+
+	start := func() {
+		started = true
+
+		contentType := "text/csv; charset=utf-8"
+		if format == auditExportFormatNDJSON {
+			contentType = "application/x-ndjson"
+		}
+
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition",
+			fmt.Sprintf(`attachment; filename="audit-log-%s.%s"`, scopeName, format))
+		w.Header().Set("Trailer", auditExportTruncatedTrailer)
+		w.WriteHeader(http.StatusOK)
+
+		if format == auditExportFormatCSV {
+			_ = csvWriter.Write([]string{"id", "created_at", "username", "entity", "entity_id", "action", "status_code", "changes", "impersonator"})
+		}
+	}
+
+	// One row over the limit is requested to tell whether the export is complete
+	err = h.auditLogRepo.Export(r.Context(), filter, limit+1, func(entry contract.AuditLogEntry) error {
+		if rows == limit {
+			truncated = true
+			return errAuditExportLimitReached
+		}
+
+		if !started {
+			start()
+		}
+
+		var err error
+		if format == auditExportFormatCSV {
+			err = csvWriter.Write([]string{
+				strconv.FormatInt(entry.ID, 10),
+				entry.CreatedAt.UTC().Format(time.RFC3339),
+				entry.Username,
+				entry.Entity,
+				entry.EntityID,
+				entry.Action,
+				formatStatusCode(entry.StatusCode),
+				string(entry.Changes),
+				stringOrEmpty(entry.Impersonator),
+			})
+		} else {
+			err = encoder.Encode(entry)
+		}
+		if err != nil {
+			return err
+		}
+
+		rows++
+		if rows%auditExportFlushEvery == 0 {
+			csvWriter.Flush()
+			_ = rc.Flush()
+		}
+
+		return nil
+	})
+	if err != nil && !errors.Is(err, errAuditExportLimitReached) {
+		slog.ErrorContext(r.Context(), "Failed to export audit log",
+			"error", err,
+			"scope", scopeName,
+			"rows", rows,
+		)
+
+		if !started {
+			respondError(w, http.StatusInternalServerError, "Failed to export audit log")
+		}
+
+		return
+	}
+
+	if !started {
+		start()
+	}
+
+	csvWriter.Flush()
+	w.Header().Set(auditExportTruncatedTrailer, strconv.FormatBool(truncated))
+}
+
+// GetExportSettings handles GET /api/v1/audit-log/export-settings
+func (h *AuditLogHandler) GetExportSettings(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	if !appcontext.IsSuper(r.Context()) {
+		respondError(w, http.StatusForbidden, "Only superusers can manage audit export settings")
+		return
+	}
+
+	limit, err := h.settingsSrv.GetAuditExportRowLimit(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to get audit export row limit", "error", err)
+		respondError(w, http.StatusInternalServerError, "Failed to get audit export settings")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]int{
+		"row_limit":     limit,
+		"max_row_limit": domain.MaxAuditExportRowLimit,
+	})
+}
+
+// UpdateExportSettings handles PUT /api/v1/audit-log/export-settings
+func (h *AuditLogHandler) UpdateExportSettings(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	if !appcontext.IsSuper(r.Context()) {
+		respondError(w, http.StatusForbidden, "Only superusers can manage audit export settings")
+		return
+	}
+
+	var req struct {
+		RowLimit int `json:"row_limit"`
+	}
+
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	if req.RowLimit <= 0 || req.RowLimit > domain.MaxAuditExportRowLimit {
+		respondError(w, http.StatusBadRequest,
+			fmt.Sprintf("row_limit must be between 1 and %d", domain.MaxAuditExportRowLimit))
+		return
+	}
+
+	if err := h.settingsSrv.SetAuditExportRowLimit(r.Context(), req.RowLimit); err != nil {
+		slog.ErrorContext(r.Context(), "Failed to update audit export row limit", "error", err)
+		respondError(w, http.StatusInternalServerError, "Failed to update audit export settings")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]int{
+		"row_limit":     req.RowLimit,
+		"max_row_limit": domain.MaxAuditExportRowLimit,
+	})
+}
+
+// GetReadAccess handles GET /api/v1/audit-log/read-access
+func (h *AuditLogHandler) GetReadAccess(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	if !appcontext.IsSuper(r.Context()) {
+		respondError(w, http.StatusForbidden, "Only superusers can manage read access auditing")
+		return
+	}
+
+	enabled, err := h.settingsSrv.GetAuditReadAccess(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to get read access audit setting", "error", err)
+		respondError(w, http.StatusInternalServerError, "Failed to get read access auditing")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]bool{"enabled": enabled})
+}
+
+// UpdateReadAccess handles PUT /api/v1/audit-log/read-access
+func (h *AuditLogHandler) UpdateReadAccess(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	if !appcontext.IsSuper(r.Context()) {
+		respondError(w, http.StatusForbidden, "Only superusers can manage read access auditing")
+		return
+	}
+
+	var req struct {
+		Enabled bool `json:"enabled"`
+	}
+
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	if err := h.settingsSrv.SetAuditReadAccess(r.Context(), req.Enabled); err != nil {
+		slog.ErrorContext(r.Context(), "Failed to update read access audit setting", "error", err)
+		respondError(w, http.StatusInternalServerError, "Failed to update read access auditing")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]bool{"enabled": req.Enabled})
+}
+
+// GetSinks handles GET /api/v1/audit-log/sinks
+func (h *AuditLogHandler) GetSinks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	if !appcontext.IsSuper(r.Context()) {
+		respondError(w, http.StatusForbidden, "Only superusers can manage audit sinks")
+		return
+	}
+
+	cfg, err := h.auditSinksSrv.GetConfig(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to get audit sinks config", "error", err)
+		respondError(w, http.StatusInternalServerError, "Failed to get audit sinks")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, cfg)
+}
+
+// UpdateSinks handles PUT /api/v1/audit-log/sinks
+func (h *AuditLogHandler) UpdateSinks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	if !appcontext.IsSuper(r.Context()) {
+		respondError(w, http.StatusForbidden, "Only superusers can manage audit sinks")
+		return
+	}
+
+	var cfg domain.AuditSinksConfig
+	if !decodeRequest(w, r, &cfg) {
+		return
+	}
+
+	if cfg.Sinks == nil {
+		cfg.Sinks = []domain.AuditSinkConfig{}
+	}
+
+	if err := h.auditSinksSrv.UpdateConfig(r.Context(), cfg); err != nil {
+		if errors.Is(err, auditsinksusecase.ErrInvalidAuditSink) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		slog.ErrorContext(r.Context(), "Failed to update audit sinks config", "error", err)
+		respondError(w, http.StatusInternalServerError, "Failed to update audit sinks")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, cfg)
+}
+
+func stringOrEmpty(value *string) string {
+	if value == nil {
+		return ""
+	}
+
+	return *value
+}
+
+func formatStatusCode(statusCode *int) string {
+	if statusCode == nil {
+		return ""
+	}
+
+	return strconv.Itoa(*statusCode)
+}
+
+// auditScope resolves the audit view requested by the scope query parameter and checks access to it:
+//   - project (default): entries of project_id, requires audit.view in the project;
+//   - tenant: entries of all projects of tenant_id and of the tenant itself, requires audit.view
+//     globally or through a tenant membership;
+//   - global: the whole audit log including entries with no project, requires global audit.view.
+//
+// It returns the filter with the scope set and the scope name used for export file names.
+func (h *AuditLogHandler) auditScope(w http.ResponseWriter, r *http.Request) (contract.AuditLogFilter, string, bool) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	switch query.Get("scope") {
+	case "", "project":
+		projectID, err := strconv.Atoi(query.Get("project_id"))
+		if err != nil || projectID <= 0 {
+			respondError(w, http.StatusBadRequest, "project_id is required")
+			return contract.AuditLogFilter{}, "", false
+		}
+
+		if !h.authorizeProjectAudit(w, r, domain.ProjectID(projectID)) {
+			return contract.AuditLogFilter{}, "", false
+		}
+
+		return contract.AuditLogFilter{ProjectID: domain.ProjectID(projectID)}, "project-" + strconv.Itoa(projectID), true
+	case "tenant":
+		tenantID, err := strconv.Atoi(query.Get("tenant_id"))
+		if err != nil || tenantID <= 0 {
+			respondError(w, http.StatusBadRequest, "tenant_id is required")
+			return contract.AuditLogFilter{}, "", false
+		}
+
+		if err := h.permissionsSrv.CanViewTenantAudit(ctx, domain.TenantID(tenantID)); err != nil {
+			if errors.Is(err, domain.ErrPermissionDenied) {
+				respondError(w, http.StatusForbidden, "Access denied to tenant audit log")
+				return contract.AuditLogFilter{}, "", false
+			}
+			respondError(w, http.StatusInternalServerError, "Failed to verify permissions")
+			return contract.AuditLogFilter{}, "", false
+		}
+
+		return contract.AuditLogFilter{TenantID: domain.TenantID(tenantID)}, "tenant-" + strconv.Itoa(tenantID), true
+	case "global":
+		ok, err := h.permissionsSrv.HasGlobalPermission(ctx, domain.PermAuditView)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to verify permissions")
+			return contract.AuditLogFilter{}, "", false
+		}
+
+		if !ok {
+			respondError(w, http.StatusForbidden, "Access denied to global audit log")
+			return contract.AuditLogFilter{}, "", false
+		}
+
+		return contract.AuditLogFilter{}, "global", true
+	default:
+		respondError(w, http.StatusBadRequest, "scope must be project, tenant or global")
+		return contract.AuditLogFilter{}, "", false
+	}
+}
+
+func (h *AuditLogHandler) authorizeProjectAudit(w http.ResponseWriter, r *http.Request, projectID domain.ProjectID) bool {
 	if err := h.permissionsSrv.CanViewProject(r.Context(), projectID); err != nil {
 		if errors.Is(err, domain.ErrPermissionDenied) {
 			respondError(w, http.StatusForbidden, "Access denied to this project")
-			return
+			return false
 		}
 		respondError(w, http.StatusInternalServerError, "Failed to verify permissions")
-		return
+		return false
 	}
 
 	if !appcontext.IsSuper(r.Context()) {
 		if err := h.permissionsSrv.CanViewAudit(r.Context(), projectID); err != nil {
 			if errors.Is(err, domain.ErrPermissionDenied) {
 				respondError(w, http.StatusForbidden, "Access denied to audit log")
-				return
+				return false
 			}
 			respondError(w, http.StatusInternalServerError, "Failed to verify permissions")
-			return
+			return false
 		}
 	}
 
-	page, pageSize := parsePagination(r)
+	return true
+}
 
-	entries, total, err := h.auditLogRepo.List(r.Context(), projectID, page, pageSize)
-	if err != nil {
-		slog.Error("Failed to list audit log",
-			"error", err,
-			"project_id", projectID,
-			"page", page,
-			"page_size", pageSize,
-		)
-		respondError(w, http.StatusInternalServerError, err.Error())
-		return
+// parseAuditFilter reads the optional from/to (RFC3339), entity, action and username filters.
+func parseAuditFilter(w http.ResponseWriter, r *http.Request, filter *contract.AuditLogFilter) bool {
+	query := r.URL.Query()
+
+	filter.Entity = query.Get("entity")
+	filter.Action = query.Get("action")
+	filter.Username = query.Get("username")
+
+	for _, param := range []struct {
+		name   string
+		target **time.Time
+	}{
+		{"from", &filter.From},
+		{"to", &filter.To},
+	} {
+		value := query.Get(param.name)
+		if value == "" {
+			continue
+		}
+
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "invalid "+param.name+", expected RFC3339")
+			return false
+		}
+		*param.target = &parsed
 	}
 
-	respondJSON(w, http.StatusOK, map[string]interface{}{
-		"items":     entries,
-		"page":      page,
-		"page_size": pageSize,
-		"total":     total,
-	})
+	return true
 }
@@ -2,10 +2,17 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
 	"net/http"
 
+	"github.com/rom8726/floxy-manager/internal/api/rest/apierror"
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
 	"github.com/rom8726/floxy-manager/internal/contract"
 	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/safejson"
+	"github.com/rom8726/floxy-manager/pkg/validate"
 )
 
 type AuthHandler struct {
@@ -20,31 +27,32 @@ func NewAuthHandler(usersService contract.UsersUseCase) *AuthHandler {
 
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	var req struct {
-		UsernameOrEmail string `json:"username_or_email"`
-		Password        string `json:"password"`
+		UsernameOrEmail string `json:"username_or_email" validate:"required"`
+		Password        string `json:"password" validate:"required"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request", http.StatusBadRequest)
-		return
-	}
-
-	if req.UsernameOrEmail == "" || req.Password == "" {
-		respondError(w, http.StatusBadRequest, "username_or_email and password are required")
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
 	accessToken, refreshToken, sessionID, isTmpPassword, err := h.usersService.Login(r.Context(), req.UsernameOrEmail, req.Password)
 	if err != nil {
 		if err == domain.ErrTwoFARequired {
+			methods, err := h.usersService.Get2FAMethods(r.Context(), sessionID)
+			if err != nil {
+				respondError(w, http.StatusUnauthorized, "Invalid credentials")
+				return
+			}
+
 			respondJSON(w, http.StatusOK, map[string]interface{}{
 				"session_id":   sessionID,
 				"requires_2fa": true,
+				"methods":      methods,
 			})
 			return
 		}
@@ -61,21 +69,15 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 
 func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	var req struct {
-		RefreshToken string `json:"refresh_token"`
+		RefreshToken string `json:"refresh_token" validate:"required"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request", http.StatusBadRequest)
-		return
-	}
-
-	if req.RefreshToken == "" {
-		respondError(w, http.StatusBadRequest, "refresh_token is required")
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
@@ -91,12 +93,142 @@ func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// Logout handles POST /api/v1/auth/logout. It revokes the session of the presented refresh token,
+// or of the access token if no refresh token is given. With "all" set every session of the user is revoked.
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req struct {
+		RefreshToken string `json:"refresh_token"`
+		All          bool   `json:"all"`
+	}
+
+	if err := decodeJSON(r, &req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	var err error
+	switch {
+	case req.RefreshToken != "":
+		err = h.usersService.Logout(r.Context(), req.RefreshToken, req.All)
+	case requireAuth(r):
+		err = h.usersService.LogoutSession(r.Context(),
+			appcontext.UserID(r.Context()), appcontext.SessionID(r.Context()), req.All)
+	default:
+		respondError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidToken) {
+			respondError(w, http.StatusUnauthorized, "Invalid token")
+			return
+		}
+
+		slog.ErrorContext(r.Context(), "Failed to logout", "error", err)
+		respondError(w, http.StatusInternalServerError, "Failed to logout")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "logged out successfully"})
+}
+
+// VerifyEmail handles POST /api/v1/auth/verify-email with the token from the verification email.
+func (h *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req struct {
+		Token string `json:"token" validate:"required"`
+	}
+
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	if err := h.usersService.VerifyEmail(r.Context(), req.Token); err != nil {
+		if errors.Is(err, domain.ErrInvalidToken) || errors.Is(err, domain.ErrEntityNotFound) {
+			respondError(w, http.StatusBadRequest, "Invalid or expired token")
+			return
+		}
+
+		slog.ErrorContext(r.Context(), "Failed to verify email", "error", err)
+		respondError(w, http.StatusInternalServerError, "Failed to verify email")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "email verified successfully"})
+}
+
+// ResendVerificationEmail handles POST /api/v1/auth/verify-email/resend for the current user.
+func (h *AuthHandler) ResendVerificationEmail(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	err := h.usersService.ResendVerificationEmail(r.Context(), appcontext.UserID(r.Context()))
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrEmailAlreadyVerified):
+			respondError(w, http.StatusConflict, err.Error())
+		case errors.Is(err, domain.ErrTooManyVerificationEmails):
+			respondError(w, http.StatusTooManyRequests, err.Error())
+		default:
+			slog.ErrorContext(r.Context(), "Failed to send verification email", "error", err)
+			respondError(w, http.StatusInternalServerError, "Failed to send verification email")
+		}
+
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "verification email sent"})
+}
+
 func respondJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(data)
 }
 
-func respondError(w http.ResponseWriter, status int, message string) {
-	respondJSON(w, status, map[string]string{"error": message})
+// decodeJSON decodes the request body into v with the depth limit and the unknown fields
+// policy set by middlewares.BodyLimitMdw.
+func decodeJSON(r *http.Request, v any) error {
+	return safejson.Decode(r.Body, v, safejson.FromContext(r.Context()))
+}
+
+// decodeRequest decodes the request body into v and checks it against the rules of its validate tags.
+// It responds with 400 and returns false when the body is malformed or fails the rules.
+func decodeRequest(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := decodeJSON(r, v); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+
+		return false
+	}
+
+	return validRequest(w, v)
+}
+
+// validRequest checks the decoded request against the rules of its validate tags.
+// It responds with 400 listing the failed fields and returns false when they fail.
+func validRequest(w http.ResponseWriter, v any) bool {
+	if err := validate.Struct(v); err != nil {
+		var fieldErrs validate.Errors
+		errors.As(err, &fieldErrs)
+		apierror.RespondDetails(w, http.StatusBadRequest, apierror.CodeValidationFailed, err.Error(), fieldErrs)
+
+		return false
+	}
+
+	return true
 }
@@ -1,9 +1,14 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
+	"github.com/rom8726/floxy-manager/internal/api/rest/apierror"
 	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
 )
 
 // requireAuth checks if the user is authenticated in the context.
@@ -22,3 +27,149 @@ func checkAuthAndRespond(w http.ResponseWriter, r *http.Request) bool {
 	}
 	return true
 }
+
+// authorizeProjectParam reads the project ID from the ":id" URL param and checks view
+// (or manage) permission on it. Responds with an error and returns false on failure.
+func authorizeProjectParam(
+	w http.ResponseWriter,
+	r *http.Request,
+	permissionsSrv contract.PermissionsService,
+	manage bool,
+) (domain.ProjectID, bool) {
+	id, err := strconv.Atoi(appcontext.Param(r.Context(), "id"))
+	if err != nil || id <= 0 {
+		respondError(w, http.StatusBadRequest, "invalid project_id")
+		return 0, false
+	}
+
+	projectID := domain.ProjectID(id)
+
+	if !authorizeProject(w, r, permissionsSrv, projectID, manage) {
+		return 0, false
+	}
+
+	return projectID, true
+}
+
+// parseForce reads the ?force= flag of cascading deletions.
+func parseForce(r *http.Request) (bool, error) {
+	value := r.URL.Query().Get("force")
+	if value == "" {
+		return false, nil
+	}
+
+	return strconv.ParseBool(value)
+}
+
+// respondDeletionError responds to a failed project or tenant deletion. Deletions blocked by dependents
+// get 409 with the impact, so the caller can review it before forcing the deletion.
+func respondDeletionError(w http.ResponseWriter, err error, entity string, impact domain.DeletionImpact) {
+	switch {
+	case errors.Is(err, domain.ErrEntityNotFound):
+		respondError(w, http.StatusNotFound, entity+" not found")
+	case errors.Is(err, domain.ErrEntityInUse):
+		apierror.RespondDetails(w, http.StatusConflict, apierror.CodeInUse,
+			entity+" is in use, delete its dependents first or force the deletion as a superuser", impact)
+	default:
+		respondError(w, http.StatusInternalServerError, "Failed to delete "+entity)
+	}
+}
+
+// canViewWorkflowData reports whether the user may see the input and output payloads of workflow
+// instances in the project. Responds with an error and returns ok=false when the check fails.
+func canViewWorkflowData(
+	w http.ResponseWriter,
+	r *http.Request,
+	permissionsSrv contract.PermissionsService,
+	projectID domain.ProjectID,
+) (visible, ok bool) {
+	err := permissionsSrv.CanViewWorkflowData(r.Context(), projectID)
+	switch {
+	case err == nil:
+		return true, true
+	case errors.Is(err, domain.ErrPermissionDenied):
+		return false, true
+	default:
+		respondError(w, http.StatusInternalServerError, "Failed to verify permissions")
+		return false, false
+	}
+}
+
+// omitWorkflowData clears the payloads of the items when the user may not see workflow data of the project.
+// Responds with an error and returns false when the check fails.
+func omitWorkflowData[T any, PT interface {
+	*T
+	OmitPayloads()
+}](
+	w http.ResponseWriter,
+	r *http.Request,
+	permissionsSrv contract.PermissionsService,
+	projectID domain.ProjectID,
+	items []T,
+) bool {
+	visible, ok := canViewWorkflowData(w, r, permissionsSrv, projectID)
+	if !ok {
+		return false
+	}
+
+	if !visible {
+		for i := range items {
+			PT(&items[i]).OmitPayloads()
+		}
+	}
+
+	return true
+}
+
+// authorizeInstanceParam reads the instance ID from the ":id" URL param and the tenant and project
+// from the query, and checks view (or manage) permission on the project.
+// Responds with an error and returns false on failure.
+func authorizeInstanceParam(
+	w http.ResponseWriter,
+	r *http.Request,
+	permissionsSrv contract.PermissionsService,
+	manage bool,
+) (int, domain.TenantID, domain.ProjectID, bool) {
+	id, err := strconv.Atoi(appcontext.Param(r.Context(), "id"))
+	if err != nil || id <= 0 {
+		respondError(w, http.StatusBadRequest, "Invalid instance ID")
+		return 0, 0, 0, false
+	}
+
+	tenantID, projectID, err := parseTenantAndProject(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return 0, 0, 0, false
+	}
+
+	if !authorizeProject(w, r, permissionsSrv, projectID, manage) {
+		return 0, 0, 0, false
+	}
+
+	return id, tenantID, projectID, true
+}
+
+func authorizeProject(
+	w http.ResponseWriter,
+	r *http.Request,
+	permissionsSrv contract.PermissionsService,
+	projectID domain.ProjectID,
+	manage bool,
+) bool {
+	var err error
+	if manage {
+		err = permissionsSrv.CanManageProject(r.Context(), projectID)
+	} else {
+		err = permissionsSrv.CanViewProject(r.Context(), projectID)
+	}
+	if err != nil {
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			respondError(w, http.StatusForbidden, "Access denied to this project")
+			return false
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to verify permissions")
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/rom8726/floxy-manager/internal/api/rest/apierror"
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/contract"
+)
+
+// ConfigReloadHandler lets superusers apply configuration changes without a restart, like SIGHUP.
+type ConfigReloadHandler struct {
+	reloader contract.ConfigReloader
+}
+
+func NewConfigReloadHandler(reloader contract.ConfigReloader) *ConfigReloadHandler {
+	return &ConfigReloadHandler{
+		reloader: reloader,
+	}
+}
+
+// Reload handles POST /api/v1/admin/config/reload
+// It reports the changed settings, the ones with reloaded false need a restart.
+func (h *ConfigReloadHandler) Reload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	if !appcontext.IsSuper(r.Context()) {
+		respondError(w, http.StatusForbidden, "Only superusers can reload the configuration")
+		return
+	}
+
+	reload, err := h.reloader.Reload(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to reload config", "error", err)
+		apierror.RespondDetails(w, http.StatusInternalServerError, apierror.CodeInternal, "Failed to reload config", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, reload)
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	emailoutboxusecase "github.com/rom8726/floxy-manager/internal/usecases/emailoutbox"
+)
+
+// EmailOutboxHandler lets superusers inspect the queued emails and resend the failed ones.
+type EmailOutboxHandler struct {
+	emailOutboxUseCase contract.EmailOutboxUseCase
+}
+
+func NewEmailOutboxHandler(emailOutboxUseCase contract.EmailOutboxUseCase) *EmailOutboxHandler {
+	return &EmailOutboxHandler{
+		emailOutboxUseCase: emailOutboxUseCase,
+	}
+}
+
+// List handles GET /api/v1/email-outbox
+// The status query parameter selects pending, sent or failed emails, failed by default.
+func (h *EmailOutboxHandler) List(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r, http.MethodGet) {
+		return
+	}
+
+	status := domain.EmailStatus(r.URL.Query().Get("status"))
+	switch status {
+	case "":
+		status = domain.EmailFailed
+	case domain.EmailPending, domain.EmailSent, domain.EmailFailed:
+	default:
+		respondError(w, http.StatusBadRequest, "Invalid status")
+		return
+	}
+
+	page, pageSize := parsePagination(r)
+
+	items, total, err := h.emailOutboxUseCase.List(r.Context(), status, page, pageSize)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to list emails", "error", err)
+		respondError(w, http.StatusInternalServerError, "Failed to list emails")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"items":     items,
+		"page":      page,
+		"page_size": pageSize,
+		"total":     total,
+	})
+}
+
+// Resend handles POST /api/v1/email-outbox/:id/resend
+func (h *EmailOutboxHandler) Resend(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r, http.MethodPost) {
+		return
+	}
+
+	id, err := strconv.ParseInt(appcontext.Param(r.Context(), "id"), 10, 64)
+	if err != nil || id <= 0 {
+		respondError(w, http.StatusBadRequest, "Invalid email ID")
+		return
+	}
+
+	email, err := h.emailOutboxUseCase.Resend(r.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrEntityNotFound):
+			respondError(w, http.StatusNotFound, "Email not found")
+		case errors.Is(err, emailoutboxusecase.ErrNotFailed):
+			respondError(w, http.StatusConflict, emailoutboxusecase.ErrNotFailed.Error())
+		default:
+			slog.ErrorContext(r.Context(), "Failed to resend email", "error", err, "email_id", id)
+			respondError(w, http.StatusInternalServerError, "Failed to resend email")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, email)
+}
+
+func (h *EmailOutboxHandler) authorize(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method != method {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return false
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return false
+	}
+
+	if !appcontext.IsSuper(r.Context()) {
+		respondError(w, http.StatusForbidden, "Only superusers can manage the email outbox")
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/rom8726/floxy-manager/internal/api/rest/apierror"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+// internalErrorMessage replaces the messages of unexpected errors, their text may hold SQL
+// or other internal details.
+const internalErrorMessage = "Internal server error"
+
+// domainErrors maps the domain errors to the responses, their messages are safe to return.
+var domainErrors = []struct {
+	err    error
+	status int
+	code   string
+}{
+	{domain.ErrEntityNotFound, http.StatusNotFound, apierror.CodeNotFound},
+	{domain.ErrUserNotFound, http.StatusNotFound, apierror.CodeNotFound},
+	{domain.ErrEntityAlreadyExists, http.StatusConflict, apierror.CodeAlreadyExists},
+	{domain.ErrUsernameAlreadyInUse, http.StatusConflict, apierror.CodeAlreadyExists},
+	{domain.ErrEmailAlreadyInUse, http.StatusConflict, apierror.CodeAlreadyExists},
+	{domain.ErrEmailAlreadyVerified, http.StatusConflict, apierror.CodeConflict},
+	{domain.ErrEntityInUse, http.StatusConflict, apierror.CodeInUse},
+	{domain.ErrPermissionDenied, http.StatusForbidden, apierror.CodeForbidden},
+	{domain.ErrInactiveUser, http.StatusForbidden, apierror.CodeForbidden},
+	{domain.ErrEmailNotVerified, http.StatusForbidden, apierror.CodeForbidden},
+	{domain.ErrServiceAccountLogin, http.StatusForbidden, apierror.CodeForbidden},
+	{domain.ErrFeatureNotLicensed, http.StatusForbidden, apierror.CodeFeatureNotLicensed},
+	{domain.ErrInvalidToken, http.StatusUnauthorized, apierror.CodeUnauthorized},
+	{domain.ErrInvalidCredentials, http.StatusUnauthorized, apierror.CodeUnauthorized},
+	{domain.ErrInvalidPassword, http.StatusBadRequest, apierror.CodeInvalidRequest},
+	{domain.ErrInvalid2FACode, http.StatusBadRequest, apierror.CodeInvalidRequest},
+	{domain.ErrInvalidEmailCode, http.StatusBadRequest, apierror.CodeInvalidRequest},
+	{domain.ErrInvalidWebAuthnResponse, http.StatusBadRequest, apierror.CodeInvalidRequest},
+	{domain.ErrNoWebAuthnCredentials, http.StatusBadRequest, apierror.CodeInvalidRequest},
+	{domain.ErrInvalidCursor, http.StatusBadRequest, apierror.CodeInvalidRequest},
+	{domain.ErrRoleScopeMismatch, http.StatusBadRequest, apierror.CodeInvalidRequest},
+	{domain.ErrInvalidUserMerge, http.StatusBadRequest, apierror.CodeInvalidRequest},
+	{domain.ErrInvalidLicense, http.StatusBadRequest, apierror.CodeInvalidRequest},
+	{domain.ErrStepLogTooLarge, http.StatusRequestEntityTooLarge, apierror.CodeTooLarge},
+	{domain.ErrTooMany2FAAttempts, http.StatusTooManyRequests, apierror.CodeRateLimited},
+	{domain.ErrTooManyVerificationEmails, http.StatusTooManyRequests, apierror.CodeRateLimited},
+}
+
+func respondError(w http.ResponseWriter, status int, message string) {
+	apierror.Respond(w, status, message)
+}
+
+// respondErr maps err to the error response. Domain errors keep their messages, a query
+// timeout answers 504 and any other error a generic 500, so the text of unexpected errors
+// is never returned; the caller logs them.
+func respondErr(w http.ResponseWriter, err error) {
+	for _, known := range domainErrors {
+		if errors.Is(err, known.err) {
+			apierror.RespondDetails(w, known.status, known.code, known.err.Error(), nil)
+
+			return
+		}
+	}
+
+	if db.IsQueryTimeout(err) {
+		respondError(w, http.StatusGatewayTimeout, "Query timed out")
+
+		return
+	}
+
+	respondError(w, http.StatusInternalServerError, internalErrorMessage)
+}
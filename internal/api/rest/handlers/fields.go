@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+var (
+	workflowInstanceFields = jsonFieldNames(reflect.TypeFor[domain.WorkflowInstance]())
+	workflowStepFields     = jsonFieldNames(reflect.TypeFor[domain.WorkflowStep]())
+)
+
+// parseFieldSet reads ?fields= and ?exclude= with comma-separated JSON field names of list items.
+func parseFieldSet(r *http.Request, known []string) (domain.FieldSet, error) {
+	var fields domain.FieldSet
+
+	for param, dst := range map[string]*[]string{"fields": &fields.Include, "exclude": &fields.Exclude} {
+		value := r.URL.Query().Get(param)
+		if value == "" {
+			continue
+		}
+
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+
+			if !slices.Contains(known, name) {
+				return domain.FieldSet{}, fmt.Errorf("unknown field %q in %s", name, param)
+			}
+
+			*dst = append(*dst, name)
+		}
+	}
+
+	return fields, nil
+}
+
+// sparseItems returns items with only the selected fields in their JSON.
+func sparseItems[T any](items []T, fields domain.FieldSet) (any, error) {
+	if fields.All() {
+		return items, nil
+	}
+
+	result := make([]map[string]json.RawMessage, 0, len(items))
+	for i := range items {
+		data, err := json.Marshal(items[i])
+		if err != nil {
+			return nil, err
+		}
+
+		var item map[string]json.RawMessage
+		if err := json.Unmarshal(data, &item); err != nil {
+			return nil, err
+		}
+
+		for name := range item {
+			if !fields.Has(name) {
+				delete(item, name)
+			}
+		}
+
+		result = append(result, item)
+	}
+
+	return result, nil
+}
+
+func jsonFieldNames(typ reflect.Type) []string {
+	names := make([]string, 0, typ.NumField())
+	for i := range typ.NumField() {
+		name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+// GlobalRolesHandler manages global role assignments. Only superusers can assign global roles.
+type GlobalRolesHandler struct {
+	globalRolesRepo contract.GlobalRolesRepository
+	rolesRepo       contract.RolesRepository
+	usersService    contract.UsersUseCase
+	permissions     contract.PermissionsService
+}
+
+func NewGlobalRolesHandler(
+	globalRolesRepo contract.GlobalRolesRepository,
+	rolesRepo contract.RolesRepository,
+	usersService contract.UsersUseCase,
+	permissions contract.PermissionsService,
+) *GlobalRolesHandler {
+	return &GlobalRolesHandler{
+		globalRolesRepo: globalRolesRepo,
+		rolesRepo:       rolesRepo,
+		usersService:    usersService,
+		permissions:     permissions,
+	}
+}
+
+type globalRoleAssignmentResponse struct {
+	ID        domain.GlobalRoleAssignmentID `json:"id"`
+	UserID    domain.UserID                 `json:"user_id"`
+	RoleID    domain.RoleID                 `json:"role_id"`
+	RoleKey   string                        `json:"role_key"`
+	RoleName  string                        `json:"role_name"`
+	CreatedAt string                        `json:"created_at"`
+}
+
+func toGlobalRoleAssignmentResponse(a domain.GlobalRoleAssignment) globalRoleAssignmentResponse {
+	return globalRoleAssignmentResponse{
+		ID:        a.ID,
+		UserID:    a.UserID,
+		RoleID:    a.RoleID,
+		RoleKey:   a.RoleKey,
+		RoleName:  a.RoleName,
+		CreatedAt: a.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// List handles GET /api/v1/global-role-assignments
+func (h *GlobalRolesHandler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	if !appcontext.IsSuper(r.Context()) {
+		respondError(w, http.StatusForbidden, "Only superusers can manage global roles")
+		return
+	}
+
+	assignments, err := h.globalRolesRepo.List(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to list global role assignments", "error", err)
+		respondError(w, http.StatusInternalServerError, "Failed to list global role assignments")
+		return
+	}
+
+	result := make([]globalRoleAssignmentResponse, 0, len(assignments))
+	for _, a := range assignments {
+		result = append(result, toGlobalRoleAssignmentResponse(a))
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// Create handles POST /api/v1/global-role-assignments
+func (h *GlobalRolesHandler) Create(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	if !appcontext.IsSuper(r.Context()) {
+		respondError(w, http.StatusForbidden, "Only superusers can manage global roles")
+		return
+	}
+
+	var req struct {
+		UserID int    `json:"user_id" validate:"required,min=1"`
+		RoleID string `json:"role_id" validate:"required"`
+	}
+
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	role, err := h.rolesRepo.GetByID(r.Context(), domain.RoleID(req.RoleID))
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			respondError(w, http.StatusBadRequest, "Role not found")
+			return
+		}
+
+		respondError(w, http.StatusInternalServerError, "Failed to verify role")
+		return
+	}
+
+	if !role.IsGlobalRole() {
+		respondError(w, http.StatusBadRequest, "Only global roles can be assigned globally")
+		return
+	}
+
+	user, err := h.usersService.GetByID(r.Context(), domain.UserID(req.UserID))
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			respondError(w, http.StatusNotFound, "User not found")
+			return
+		}
+
+		respondError(w, http.StatusInternalServerError, "Failed to verify user")
+		return
+	}
+
+	if user.IsServiceAccount {
+		respondError(w, http.StatusBadRequest, "Service accounts cannot have global roles")
+		return
+	}
+
+	assignment, err := h.globalRolesRepo.Create(r.Context(), user.ID, role.ID)
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityAlreadyExists) {
+			respondError(w, http.StatusConflict, "User already has this role")
+			return
+		}
+
+		slog.ErrorContext(r.Context(), "Failed to assign global role",
+			"error", err,
+			"user_id", req.UserID,
+			"role_id", req.RoleID,
+		)
+		respondError(w, http.StatusInternalServerError, "Failed to assign global role")
+		return
+	}
+
+	h.permissions.InvalidateCache()
+
+	respondJSON(w, http.StatusCreated, toGlobalRoleAssignmentResponse(assignment))
+}
+
+// Delete handles DELETE /api/v1/global-role-assignments/:id
+func (h *GlobalRolesHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	if !appcontext.IsSuper(r.Context()) {
+		respondError(w, http.StatusForbidden, "Only superusers can manage global roles")
+		return
+	}
+
+	id, err := strconv.Atoi(appcontext.Param(r.Context(), "id"))
+	if err != nil || id <= 0 {
+		respondError(w, http.StatusBadRequest, "invalid assignment id")
+		return
+	}
+
+	err = h.globalRolesRepo.Delete(r.Context(), domain.GlobalRoleAssignmentID(id))
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			respondError(w, http.StatusNotFound, "global role assignment not found")
+			return
+		}
+
+		slog.ErrorContext(r.Context(), "Failed to delete global role assignment", "error", err, "id", id)
+		respondError(w, http.StatusInternalServerError, "Failed to delete global role assignment")
+		return
+	}
+
+	h.permissions.InvalidateCache()
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "global role assignment deleted successfully"})
+}
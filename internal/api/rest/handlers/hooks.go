@@ -0,0 +1,354 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	hooksusecase "github.com/rom8726/floxy-manager/internal/usecases/hooks"
+)
+
+const (
+	hookSignatureHeader = "X-Floxy-Signature"
+	maxHookPayloadSize  = 1 << 20
+)
+
+type HooksHandler struct {
+	hooksUseCase   contract.HooksUseCase
+	permissionsSrv contract.PermissionsService
+}
+
+func NewHooksHandler(
+	hooksUseCase contract.HooksUseCase,
+	permissionsSrv contract.PermissionsService,
+) *HooksHandler {
+	return &HooksHandler{
+		hooksUseCase:   hooksUseCase,
+		permissionsSrv: permissionsSrv,
+	}
+}
+
+type hookRequest struct {
+	WorkflowID         string `json:"workflow_id"`
+	Name               string `json:"name"`
+	RequireSignature   *bool  `json:"require_signature"`
+	RateLimitPerMinute int    `json:"rate_limit_per_minute"`
+	Enabled            *bool  `json:"enabled"`
+}
+
+func (req *hookRequest) toDTO() domain.HookDTO {
+	requireSignature := true
+	if req.RequireSignature != nil {
+		requireSignature = *req.RequireSignature
+	}
+
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	return domain.HookDTO{
+		WorkflowID:         req.WorkflowID,
+		Name:               req.Name,
+		RequireSignature:   requireSignature,
+		RateLimitPerMinute: req.RateLimitPerMinute,
+		Enabled:            enabled,
+	}
+}
+
+// Trigger handles POST /api/v1/hooks/:token
+// The endpoint is public: the hook is authenticated by its token and,
+// when required, by the X-Floxy-Signature header (sha256=<hex HMAC of the body>).
+func (h *HooksHandler) Trigger(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	token := appcontext.Param(r.Context(), "token")
+	if token == "" {
+		respondError(w, http.StatusNotFound, "Hook not found")
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxHookPayloadSize+1))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Failed to read request body")
+		return
+	}
+	if len(body) > maxHookPayloadSize {
+		respondError(w, http.StatusRequestEntityTooLarge, "Payload too large")
+		return
+	}
+
+	instanceID, err := h.hooksUseCase.Trigger(r.Context(), token, body, r.Header.Get(hookSignatureHeader))
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrEntityNotFound), errors.Is(err, hooksusecase.ErrHookDisabled):
+			respondError(w, http.StatusNotFound, "Hook not found")
+		case errors.Is(err, hooksusecase.ErrInvalidSignature):
+			respondError(w, http.StatusUnauthorized, "Invalid signature")
+		case errors.Is(err, hooksusecase.ErrRateLimited):
+			respondError(w, http.StatusTooManyRequests, "Rate limit exceeded")
+		case errors.Is(err, hooksusecase.ErrInvalidPayload):
+			respondError(w, http.StatusBadRequest, err.Error())
+		default:
+			slog.ErrorContext(r.Context(), "Failed to trigger hook", "error", err)
+			respondError(w, http.StatusInternalServerError, "Failed to trigger hook")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusAccepted, map[string]int64{"instance_id": instanceID})
+}
+
+// List handles GET /api/v1/hook-triggers
+func (h *HooksHandler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	_, projectID, ok := h.authorize(w, r, false)
+	if !ok {
+		return
+	}
+
+	page, pageSize := parsePagination(r)
+
+	items, total, err := h.hooksUseCase.List(r.Context(), projectID, page, pageSize)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to list hooks",
+			"error", err,
+			"project_id", projectID,
+		)
+		respondError(w, http.StatusInternalServerError, "Failed to list hooks")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"items":     items,
+		"page":      page,
+		"page_size": pageSize,
+		"total":     total,
+	})
+}
+
+// Get handles GET /api/v1/hook-triggers/:id
+func (h *HooksHandler) Get(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	id, ok := parseHookID(w, r)
+	if !ok {
+		return
+	}
+
+	_, projectID, ok := h.authorize(w, r, false)
+	if !ok {
+		return
+	}
+
+	hook, err := h.hooksUseCase.Get(r.Context(), projectID, id)
+	if err != nil {
+		respondHookError(w, r, err, "Failed to get hook", id)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, hook)
+}
+
+// Create handles POST /api/v1/hook-triggers
+func (h *HooksHandler) Create(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	tenantID, projectID, ok := h.authorize(w, r, true)
+	if !ok {
+		return
+	}
+
+	var req hookRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	hook, err := h.hooksUseCase.Create(r.Context(), tenantID, projectID, req.toDTO())
+	if err != nil {
+		respondHookError(w, r, err, "Failed to create hook", 0)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, hook)
+}
+
+// Update handles PUT /api/v1/hook-triggers/:id
+func (h *HooksHandler) Update(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	id, ok := parseHookID(w, r)
+	if !ok {
+		return
+	}
+
+	tenantID, projectID, ok := h.authorize(w, r, true)
+	if !ok {
+		return
+	}
+
+	var req hookRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	hook, err := h.hooksUseCase.Update(r.Context(), tenantID, projectID, id, req.toDTO())
+	if err != nil {
+		respondHookError(w, r, err, "Failed to update hook", id)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, hook)
+}
+
+// RotateSecret handles POST /api/v1/hook-triggers/:id/rotate-secret
+func (h *HooksHandler) RotateSecret(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	id, ok := parseHookID(w, r)
+	if !ok {
+		return
+	}
+
+	_, projectID, ok := h.authorize(w, r, true)
+	if !ok {
+		return
+	}
+
+	hook, err := h.hooksUseCase.RotateSecret(r.Context(), projectID, id)
+	if err != nil {
+		respondHookError(w, r, err, "Failed to rotate hook secret", id)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, hook)
+}
+
+// Delete handles DELETE /api/v1/hook-triggers/:id
+func (h *HooksHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	id, ok := parseHookID(w, r)
+	if !ok {
+		return
+	}
+
+	_, projectID, ok := h.authorize(w, r, true)
+	if !ok {
+		return
+	}
+
+	if err := h.hooksUseCase.Delete(r.Context(), projectID, id); err != nil {
+		respondHookError(w, r, err, "Failed to delete hook", id)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "hook deleted successfully"})
+}
+
+// authorize parses tenant and project and checks view (or manage) permission on the project.
+func (h *HooksHandler) authorize(
+	w http.ResponseWriter,
+	r *http.Request,
+	manage bool,
+) (domain.TenantID, domain.ProjectID, bool) {
+	tenantID, projectID, err := parseTenantAndProject(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return 0, 0, false
+	}
+
+	if manage {
+		err = h.permissionsSrv.CanManageProject(r.Context(), projectID)
+	} else {
+		err = h.permissionsSrv.CanViewProject(r.Context(), projectID)
+	}
+	if err != nil {
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			respondError(w, http.StatusForbidden, "Access denied to this project")
+			return 0, 0, false
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to verify permissions")
+		return 0, 0, false
+	}
+
+	return tenantID, projectID, true
+}
+
+func parseHookID(w http.ResponseWriter, r *http.Request) (domain.HookID, bool) {
+	id, err := strconv.Atoi(appcontext.Param(r.Context(), "id"))
+	if err != nil || id <= 0 {
+		respondError(w, http.StatusBadRequest, "Invalid hook ID")
+		return 0, false
+	}
+
+	return domain.HookID(id), true
+}
+
+func respondHookError(w http.ResponseWriter, r *http.Request, err error, msg string, id domain.HookID) {
+	switch {
+	case errors.Is(err, hooksusecase.ErrInvalidHook):
+		respondError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrEntityNotFound):
+		respondError(w, http.StatusNotFound, "Hook or workflow not found")
+	case errors.Is(err, domain.ErrEntityAlreadyExists):
+		respondError(w, http.StatusConflict, "Hook with this name already exists")
+	default:
+		slog.ErrorContext(r.Context(), msg,
+			"error", err,
+			"hook_id", id,
+		)
+		respondError(w, http.StatusInternalServerError, msg)
+	}
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	instanceholdsusecase "github.com/rom8726/floxy-manager/internal/usecases/instanceholds"
+)
+
+type InstanceHoldsHandler struct {
+	holdsUseCase   contract.InstanceHoldsUseCase
+	permissionsSrv contract.PermissionsService
+}
+
+func NewInstanceHoldsHandler(
+	holdsUseCase contract.InstanceHoldsUseCase,
+	permissionsSrv contract.PermissionsService,
+) *InstanceHoldsHandler {
+	return &InstanceHoldsHandler{
+		holdsUseCase:   holdsUseCase,
+		permissionsSrv: permissionsSrv,
+	}
+}
+
+type pauseInstanceRequest struct {
+	Reason string `json:"reason" validate:"max=1000"`
+}
+
+// Pause handles POST /api/v1/instances/:id/pause?tenant_id=&project_id=
+// Queued steps of the instance are kept aside until it is resumed, steps already running finish.
+func (h *InstanceHoldsHandler) Pause(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !requireAuthForWorkflows(w, r) {
+		return
+	}
+
+	id, tenantID, projectID, ok := authorizeInstanceParam(w, r, h.permissionsSrv, true)
+	if !ok {
+		return
+	}
+
+	var req pauseInstanceRequest
+	if r.ContentLength != 0 {
+		if !decodeRequest(w, r, &req) {
+			return
+		}
+	}
+
+	hold, err := h.holdsUseCase.Pause(r.Context(), tenantID, projectID, id, req.Reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrEntityNotFound):
+			respondError(w, http.StatusNotFound, "Instance not found")
+		case errors.Is(err, domain.ErrEntityAlreadyExists):
+			respondError(w, http.StatusConflict, "Instance is already paused")
+		case errors.Is(err, instanceholdsusecase.ErrInstanceNotActive):
+			respondError(w, http.StatusConflict, err.Error())
+		default:
+			slog.ErrorContext(r.Context(), "Failed to pause workflow instance",
+				"error", err,
+				"instance_id", id,
+				"project_id", projectID,
+			)
+			respondError(w, http.StatusInternalServerError, "Failed to pause instance")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, hold)
+}
+
+// Resume handles POST /api/v1/instances/:id/resume?tenant_id=&project_id=
+func (h *InstanceHoldsHandler) Resume(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !requireAuthForWorkflows(w, r) {
+		return
+	}
+
+	id, tenantID, projectID, ok := authorizeInstanceParam(w, r, h.permissionsSrv, true)
+	if !ok {
+		return
+	}
+
+	released, err := h.holdsUseCase.Resume(r.Context(), tenantID, projectID, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			respondError(w, http.StatusNotFound, "Instance is not paused")
+			return
+		}
+		slog.ErrorContext(r.Context(), "Failed to resume workflow instance",
+			"error", err,
+			"instance_id", id,
+			"project_id", projectID,
+		)
+		respondError(w, http.StatusInternalServerError, "Failed to resume instance")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"instance_id":    id,
+		"released_steps": released,
+	})
+}
+
+// List handles GET /api/v1/instance-holds?tenant_id=&project_id=
+func (h *InstanceHoldsHandler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !requireAuthForWorkflows(w, r) {
+		return
+	}
+
+	_, projectID, err := parseTenantAndProject(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !authorizeProject(w, r, h.permissionsSrv, projectID, false) {
+		return
+	}
+
+	holds, err := h.holdsUseCase.List(r.Context(), projectID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to list instance holds", "error", err, "project_id", projectID)
+		respondError(w, http.StatusInternalServerError, "Failed to list paused instances")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"items": holds,
+	})
+}
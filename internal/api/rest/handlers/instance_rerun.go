@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/jsonpatch"
+)
+
+const maxRerunRequestSize = 1 << 20
+
+type rerunInstanceRequest struct {
+	// Patch is a JSON Patch (RFC 6902) applied to the input of the original instance
+	Patch json.RawMessage `json:"patch"`
+}
+
+// RerunInstance handles POST /api/v1/instances/:id/rerun
+// A new instance of the same definition is started with the input of the original instance,
+// edited by the optional patch of the body. The new instance has parent_instance_id set to the original.
+func (h *WorkflowsHandler) RerunInstance(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !requireAuthForWorkflows(w, r) {
+		return
+	}
+
+	id, err := strconv.Atoi(appcontext.Param(r.Context(), "id"))
+	if err != nil || id <= 0 {
+		respondError(w, http.StatusBadRequest, "Invalid instance ID")
+		return
+	}
+
+	tenantID, projectID, err := parseTenantAndProject(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.permissionsSrv.CanManageProject(r.Context(), projectID); err != nil {
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			respondError(w, http.StatusForbidden, "Access denied to this project")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to verify permissions")
+		return
+	}
+
+	var req rerunInstanceRequest
+	if r.ContentLength != 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, maxRerunRequestSize)
+		if !decodeRequest(w, r, &req) {
+			return
+		}
+	}
+
+	if string(req.Patch) == "null" {
+		req.Patch = nil
+	}
+
+	instance, err := h.workflowsUseCase.RerunInstance(r.Context(), tenantID, projectID, id, req.Patch)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrEntityNotFound):
+			respondError(w, http.StatusNotFound, "Instance not found")
+		case errors.Is(err, jsonpatch.ErrInvalidPatch):
+			respondError(w, http.StatusUnprocessableEntity, err.Error())
+		default:
+			slog.ErrorContext(r.Context(), "Failed to rerun workflow instance",
+				"error", err,
+				"instance_id", id,
+				"tenant_id", tenantID,
+				"project_id", projectID,
+			)
+			respondError(w, http.StatusInternalServerError, "Failed to rerun instance")
+		}
+		return
+	}
+
+	instances := []domain.WorkflowInstance{instance}
+	if err := h.redactor.RedactInstances(r.Context(), instances); err != nil {
+		respondRedactionError(w, r, err)
+		return
+	}
+
+	if !omitWorkflowData(w, r, h.permissionsSrv, projectID, instances) {
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, instances[0])
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	instancesignalsusecase "github.com/rom8726/floxy-manager/internal/usecases/instancesignals"
+)
+
+const (
+	maxSignalRequestSize = 1 << 20
+	idempotencyKeyHeader = "Idempotency-Key"
+)
+
+type InstanceSignalsHandler struct {
+	signalsUseCase contract.InstanceSignalsUseCase
+	permissionsSrv contract.PermissionsService
+}
+
+func NewInstanceSignalsHandler(
+	signalsUseCase contract.InstanceSignalsUseCase,
+	permissionsSrv contract.PermissionsService,
+) *InstanceSignalsHandler {
+	return &InstanceSignalsHandler{
+		signalsUseCase: signalsUseCase,
+		permissionsSrv: permissionsSrv,
+	}
+}
+
+type signalInstanceRequest struct {
+	Name     string          `json:"name"`
+	Payload  json.RawMessage `json:"payload"`
+	Decision string          `json:"decision"`
+}
+
+// Signal handles POST /api/v1/instances/:id/signal?tenant_id=&project_id=
+// The named event completes the step of the same name waiting for a decision, "confirmed" by default.
+// A repeated request with the same Idempotency-Key header returns the recorded signal with 200
+// instead of delivering it again.
+func (h *InstanceSignalsHandler) Signal(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !requireAuthForWorkflows(w, r) {
+		return
+	}
+
+	id, tenantID, projectID, ok := authorizeInstanceParam(w, r, h.permissionsSrv, true)
+	if !ok {
+		return
+	}
+
+	var req signalInstanceRequest
+	r.Body = http.MaxBytesReader(w, r.Body, maxSignalRequestSize)
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	if string(req.Payload) == "null" {
+		req.Payload = nil
+	}
+
+	dto := domain.InstanceSignalDTO{
+		Name:     req.Name,
+		Payload:  req.Payload,
+		Decision: req.Decision,
+	}
+	if key := r.Header.Get(idempotencyKeyHeader); key != "" {
+		dto.IdempotencyKey = &key
+	}
+
+	signal, replayed, err := h.signalsUseCase.Send(r.Context(), tenantID, projectID, id, dto)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrEntityNotFound):
+			respondError(w, http.StatusNotFound, "Instance not found")
+		case errors.Is(err, instancesignalsusecase.ErrInvalidSignal):
+			respondError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, instancesignalsusecase.ErrNoWaitingStep):
+			respondError(w, http.StatusConflict, err.Error())
+		default:
+			slog.ErrorContext(r.Context(), "Failed to signal workflow instance",
+				"error", err,
+				"instance_id", id,
+				"project_id", projectID,
+				"signal", req.Name,
+			)
+			respondError(w, http.StatusInternalServerError, "Failed to deliver signal")
+		}
+		return
+	}
+
+	status := http.StatusAccepted
+	if replayed {
+		status = http.StatusOK
+	}
+
+	respondJSON(w, status, signal)
+}
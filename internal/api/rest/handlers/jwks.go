@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+)
+
+type JWKSHandler struct {
+	tokenizer contract.Tokenizer
+}
+
+func NewJWKSHandler(tokenizer contract.Tokenizer) *JWKSHandler {
+	return &JWKSHandler{tokenizer: tokenizer}
+}
+
+// GetJWKS handles GET /api/v1/auth/jwks
+// Responds with the public keys validating access tokens, the set is empty with HS256 signing.
+func (h *JWKSHandler) GetJWKS(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	respondJSON(w, http.StatusOK, h.tokenizer.JWKS())
+}
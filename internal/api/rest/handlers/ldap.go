@@ -1,7 +1,7 @@
 package handlers
 
 import (
-	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -9,6 +9,7 @@ import (
 	appcontext "github.com/rom8726/floxy-manager/internal/context"
 	"github.com/rom8726/floxy-manager/internal/contract"
 	"github.com/rom8726/floxy-manager/internal/domain"
+	ldapusecase "github.com/rom8726/floxy-manager/internal/usecases/ldap"
 )
 
 type LDAPHandler struct {
@@ -26,7 +27,7 @@ func NewLDAPHandler(ldapUseCase contract.LDAPSyncUseCase, settingsUseCase contra
 // GetLDAPConfig returns the current LDAP configuration
 func (h *LDAPHandler) GetLDAPConfig(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -53,7 +54,7 @@ func (h *LDAPHandler) GetLDAPConfig(w http.ResponseWriter, r *http.Request) {
 // UpdateLDAPConfig updates the LDAP configuration
 func (h *LDAPHandler) UpdateLDAPConfig(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -68,13 +69,16 @@ func (h *LDAPHandler) UpdateLDAPConfig(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var config domain.LDAPConfig
-	if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+	if !decodeRequest(w, r, &config) {
 		return
 	}
 
 	ctx := r.Context()
 	if err := h.ldapUseCase.UpdateConfig(ctx, &config); err != nil {
+		if errors.Is(err, ldapusecase.ErrInvalidGroupMapping) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		respondError(w, http.StatusInternalServerError, "Failed to update LDAP configuration")
 		return
 	}
@@ -88,7 +92,7 @@ func (h *LDAPHandler) UpdateLDAPConfig(w http.ResponseWriter, r *http.Request) {
 // DeleteLDAPConfig deletes the LDAP configuration (disables LDAP)
 func (h *LDAPHandler) DeleteLDAPConfig(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodDelete {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -116,7 +120,7 @@ func (h *LDAPHandler) DeleteLDAPConfig(w http.ResponseWriter, r *http.Request) {
 // TestLDAPConnection tests the connection to the LDAP server
 func (h *LDAPHandler) TestLDAPConnection(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -131,8 +135,7 @@ func (h *LDAPHandler) TestLDAPConnection(w http.ResponseWriter, r *http.Request)
 	}
 
 	var testConfig domain.LDAPConfig
-	if err := json.NewDecoder(r.Body).Decode(&testConfig); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+	if !decodeRequest(w, r, &testConfig) {
 		return
 	}
 
@@ -189,7 +192,7 @@ func (h *LDAPHandler) TestLDAPConnection(w http.ResponseWriter, r *http.Request)
 // SyncLDAPUsers starts a manual LDAP user synchronization
 func (h *LDAPHandler) SyncLDAPUsers(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -223,7 +226,7 @@ func (h *LDAPHandler) SyncLDAPUsers(w http.ResponseWriter, r *http.Request) {
 // CancelLDAPSync cancels an ongoing LDAP synchronization
 func (h *LDAPHandler) CancelLDAPSync(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodDelete {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -255,7 +258,7 @@ func (h *LDAPHandler) CancelLDAPSync(w http.ResponseWriter, r *http.Request) {
 // GetLDAPSyncStatus returns the current LDAP synchronization status
 func (h *LDAPHandler) GetLDAPSyncStatus(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -297,7 +300,7 @@ func (h *LDAPHandler) GetLDAPSyncStatus(w http.ResponseWriter, r *http.Request)
 // GetLDAPSyncProgress returns the progress of an ongoing LDAP synchronization
 func (h *LDAPHandler) GetLDAPSyncProgress(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -342,7 +345,7 @@ func (h *LDAPHandler) GetLDAPSyncProgress(w http.ResponseWriter, r *http.Request
 // GetLDAPSyncLogs returns LDAP synchronization logs with filtering
 func (h *LDAPHandler) GetLDAPSyncLogs(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -402,7 +405,7 @@ func (h *LDAPHandler) GetLDAPSyncLogs(w http.ResponseWriter, r *http.Request) {
 // GetLDAPSyncLogDetails returns details of a specific sync log entry
 func (h *LDAPHandler) GetLDAPSyncLogDetails(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -443,7 +446,7 @@ func (h *LDAPHandler) GetLDAPSyncLogDetails(w http.ResponseWriter, r *http.Reque
 // GetLDAPStatistics returns comprehensive LDAP statistics
 func (h *LDAPHandler) GetLDAPStatistics(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+// LicenseHandler serves the license agreement and its acceptance by the users,
+// and the signed license enabling the enterprise features.
+type LicenseHandler struct {
+	settingsUseCase contract.SettingsUseCase
+	usersService    contract.UsersUseCase
+	licenseService  contract.LicenseService
+}
+
+func NewLicenseHandler(
+	settingsUseCase contract.SettingsUseCase,
+	usersService contract.UsersUseCase,
+	licenseService contract.LicenseService,
+) *LicenseHandler {
+	return &LicenseHandler{
+		settingsUseCase: settingsUseCase,
+		usersService:    usersService,
+		licenseService:  licenseService,
+	}
+}
+
+type licenseResponse struct {
+	domain.LicenseAgreement
+	Accepted   bool       `json:"accepted"`
+	AcceptedAt *time.Time `json:"accepted_at"`
+}
+
+// GetLicense handles GET /api/v1/license
+// It returns the current license agreement and whether the current user accepted its version.
+func (h *LicenseHandler) GetLicense(w http.ResponseWriter, r *http.Request) {
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	agreement, err := h.settingsUseCase.GetLicenseAgreement(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to get license agreement", "error", err)
+		respondError(w, http.StatusInternalServerError, "Failed to get license agreement")
+		return
+	}
+
+	user, err := h.usersService.GetByID(r.Context(), appcontext.UserID(r.Context()))
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to get user", "error", err)
+		respondError(w, http.StatusInternalServerError, "Failed to get user information")
+		return
+	}
+
+	response := licenseResponse{LicenseAgreement: agreement}
+	if agreement.Version != "" && user.LicenseVersion == agreement.Version {
+		response.Accepted = true
+		response.AcceptedAt = user.LicenseAcceptedAt
+	}
+
+	respondJSON(w, http.StatusOK, response)
+}
+
+// UpdateLicense handles PUT /api/v1/license
+// Publishing a new version asks every user to accept it again.
+func (h *LicenseHandler) UpdateLicense(w http.ResponseWriter, r *http.Request) {
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	if !appcontext.IsSuper(r.Context()) {
+		respondError(w, http.StatusForbidden, "Only superusers can manage the license agreement")
+		return
+	}
+
+	var req struct {
+		Version  string `json:"version" validate:"required,max=64"`
+		Text     string `json:"text" validate:"required"`
+		Required bool   `json:"required"`
+	}
+
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	agreement := domain.LicenseAgreement{Version: req.Version, Text: req.Text, Required: req.Required}
+	if err := h.settingsUseCase.UpdateLicenseAgreement(r.Context(), agreement); err != nil {
+		respondSettingsError(w, r, err, "Failed to update license agreement")
+		return
+	}
+
+	saved, err := h.settingsUseCase.GetLicenseAgreement(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to get license agreement", "error", err)
+		respondError(w, http.StatusInternalServerError, "Failed to get license agreement")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, saved)
+}
+
+// AcceptLicense handles POST /api/v1/users/me/accept-license
+// The version must be the current one, so users cannot accept a text they were not shown.
+func (h *LicenseHandler) AcceptLicense(w http.ResponseWriter, r *http.Request) {
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	var req struct {
+		Version string `json:"version" validate:"required"`
+	}
+
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	agreement, err := h.settingsUseCase.GetLicenseAgreement(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to get license agreement", "error", err)
+		respondError(w, http.StatusInternalServerError, "Failed to get license agreement")
+		return
+	}
+
+	if agreement.Version == "" {
+		respondError(w, http.StatusNotFound, "No license agreement is published")
+		return
+	}
+
+	if req.Version != agreement.Version {
+		respondError(w, http.StatusConflict, "License agreement version is not the current one")
+		return
+	}
+
+	if err := h.usersService.AcceptLicense(r.Context(), appcontext.UserID(r.Context()), req.Version); err != nil {
+		slog.ErrorContext(r.Context(), "Failed to accept license agreement", "error", err)
+		respondErr(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"message": "License agreement accepted",
+		"version": req.Version,
+	})
+}
+
+// GetStatus handles GET /api/v1/license/status
+// It returns the installed license and the enterprise features available now, the signed
+// license text is returned to superusers only.
+func (h *LicenseHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	status, err := h.licenseService.Status(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to get license status", "error", err)
+		respondErr(w, err)
+		return
+	}
+
+	if !appcontext.IsSuper(r.Context()) {
+		status.LicenseText = ""
+	}
+
+	respondJSON(w, http.StatusOK, status)
+}
+
+// Install handles POST /api/v1/license/install
+// The signed license replaces the installed one, the features it enables are available at once.
+func (h *LicenseHandler) Install(w http.ResponseWriter, r *http.Request) {
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	if !appcontext.IsSuper(r.Context()) {
+		respondError(w, http.StatusForbidden, "Only superusers can install licenses")
+		return
+	}
+
+	var req struct {
+		License string `json:"license" validate:"required"`
+	}
+
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	status, err := h.licenseService.Install(r.Context(), req.License)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidLicense) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		slog.ErrorContext(r.Context(), "Failed to install license", "error", err)
+		respondErr(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, status)
+}
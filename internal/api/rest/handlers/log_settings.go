@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/services/logging"
+)
+
+// LogSettingsHandler lets superusers switch the log level and format without a restart.
+type LogSettingsHandler struct {
+	control contract.LogControl
+}
+
+func NewLogSettingsHandler(control contract.LogControl) *LogSettingsHandler {
+	return &LogSettingsHandler{
+		control: control,
+	}
+}
+
+// Get handles GET /api/v1/admin/loglevel
+func (h *LogSettingsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r, http.MethodGet) {
+		return
+	}
+
+	respondJSON(w, http.StatusOK, h.control.Settings())
+}
+
+// Update handles PUT /api/v1/admin/loglevel
+// It takes {"level": "debug", "format": "json"}, an omitted field is left as is. The settings
+// last until the restart or a config reload changing them.
+func (h *LogSettingsHandler) Update(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r, http.MethodPut) {
+		return
+	}
+
+	var settings domain.LogSettings
+	if !decodeRequest(w, r, &settings) {
+		return
+	}
+
+	if err := h.control.Update(settings); err != nil {
+		if errors.Is(err, logging.ErrInvalidSettings) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		respondError(w, http.StatusInternalServerError, "Failed to update log settings")
+		return
+	}
+
+	current := h.control.Settings()
+	slog.InfoContext(r.Context(), "Log settings changed",
+		"level", current.Level, "format", current.Format, "username", appcontext.Username(r.Context()))
+
+	respondJSON(w, http.StatusOK, current)
+}
+
+func (h *LogSettingsHandler) authorize(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method != method {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return false
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return false
+	}
+
+	if !appcontext.IsSuper(r.Context()) {
+		respondError(w, http.StatusForbidden, "Only superusers can change the log settings")
+		return false
+	}
+
+	return true
+}
@@ -1,11 +1,13 @@
 package handlers
 
 import (
-	"encoding/json"
 	"errors"
 	"log/slog"
 	"net/http"
 	"strconv"
+	"time"
+
+	"github.com/google/uuid"
 
 	appcontext "github.com/rom8726/floxy-manager/internal/context"
 	"github.com/rom8726/floxy-manager/internal/contract"
@@ -33,7 +35,7 @@ func NewMembershipsHandler(
 // ListProjectMemberships returns all memberships for a project
 func (h *MembershipsHandler) ListProjectMemberships(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -72,7 +74,7 @@ func (h *MembershipsHandler) ListProjectMemberships(w http.ResponseWriter, r *ht
 
 	memberships, err := h.membershipsSrv.ListProjectMemberships(r.Context(), projID)
 	if err != nil {
-		slog.Error("Failed to list project memberships",
+		slog.ErrorContext(r.Context(), "Failed to list project memberships",
 			"error", err,
 			"project_id", projectID,
 		)
@@ -82,22 +84,24 @@ func (h *MembershipsHandler) ListProjectMemberships(w http.ResponseWriter, r *ht
 
 	// Enrich with user information
 	type MembershipResponse struct {
-		ID        string `json:"id"`
-		ProjectID int    `json:"project_id"`
-		UserID    int    `json:"user_id"`
-		Username  string `json:"username"`
-		Email     string `json:"email"`
-		RoleID    string `json:"role_id"`
-		RoleKey   string `json:"role_key"`
-		RoleName  string `json:"role_name"`
-		CreatedAt string `json:"created_at"`
+		ID         string  `json:"id"`
+		ProjectID  int     `json:"project_id"`
+		UserID     int     `json:"user_id"`
+		Username   string  `json:"username"`
+		Email      string  `json:"email"`
+		RoleID     string  `json:"role_id"`
+		RoleKey    string  `json:"role_key"`
+		RoleName   string  `json:"role_name"`
+		Source     string  `json:"source"`
+		ValidUntil *string `json:"valid_until,omitempty"`
+		CreatedAt  string  `json:"created_at"`
 	}
 
 	result := make([]MembershipResponse, 0, len(memberships))
 	for _, membership := range memberships {
 		user, err := h.usersSrv.GetByID(r.Context(), membership.UserID)
 		if err != nil {
-			slog.Warn("Failed to get user for membership",
+			slog.WarnContext(r.Context(), "Failed to get user for membership",
 				"error", err,
 				"user_id", membership.UserID,
 				"membership_id", membership.ID,
@@ -106,15 +110,17 @@ func (h *MembershipsHandler) ListProjectMemberships(w http.ResponseWriter, r *ht
 		}
 
 		result = append(result, MembershipResponse{
-			ID:        string(membership.ID),
-			ProjectID: membership.ProjectID.Int(),
-			UserID:    membership.UserID.Int(),
-			Username:  user.Username,
-			Email:     user.Email,
-			RoleID:    string(membership.RoleID),
-			RoleKey:   membership.RoleKey,
-			RoleName:  membership.RoleName,
-			CreatedAt: membership.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			ID:         strconv.Itoa(int(membership.ID)),
+			ProjectID:  membership.ProjectID.Int(),
+			UserID:     membership.UserID.Int(),
+			Username:   user.Username,
+			Email:      user.Email,
+			RoleID:     string(membership.RoleID),
+			RoleKey:    membership.RoleKey,
+			RoleName:   membership.RoleName,
+			Source:     string(membership.Source),
+			ValidUntil: formatValidUntil(membership.ValidUntil),
+			CreatedAt:  membership.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		})
 	}
 
@@ -124,7 +130,7 @@ func (h *MembershipsHandler) ListProjectMemberships(w http.ResponseWriter, r *ht
 // CreateProjectMembership adds a user to a project with a specific role
 func (h *MembershipsHandler) CreateProjectMembership(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -146,22 +152,17 @@ func (h *MembershipsHandler) CreateProjectMembership(w http.ResponseWriter, r *h
 	}
 
 	var req struct {
-		UserID int    `json:"user_id"`
-		RoleID string `json:"role_id"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
-		return
+		UserID     int        `json:"user_id" validate:"required"`
+		RoleID     string     `json:"role_id" validate:"required"`
+		ValidUntil *time.Time `json:"valid_until"`
 	}
 
-	if req.UserID == 0 {
-		respondError(w, http.StatusBadRequest, "user_id is required")
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
-	if req.RoleID == "" {
-		respondError(w, http.StatusBadRequest, "role_id is required")
+	if req.ValidUntil != nil && !req.ValidUntil.After(time.Now()) {
+		respondError(w, http.StatusBadRequest, "valid_until must be in the future")
 		return
 	}
 
@@ -193,6 +194,7 @@ func (h *MembershipsHandler) CreateProjectMembership(w http.ResponseWriter, r *h
 		projID,
 		domain.UserID(req.UserID),
 		domain.RoleID(req.RoleID),
+		req.ValidUntil,
 	)
 	if err != nil {
 		// Check for duplicate membership
@@ -200,7 +202,19 @@ func (h *MembershipsHandler) CreateProjectMembership(w http.ResponseWriter, r *h
 			respondError(w, http.StatusConflict, "User is already a member of this project")
 			return
 		}
-		slog.Error("Failed to create project membership",
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			respondError(w, http.StatusForbidden, "Service accounts can only be members of their own project")
+			return
+		}
+		if errors.Is(err, domain.ErrRoleScopeMismatch) {
+			respondError(w, http.StatusBadRequest, "Tenant and global roles cannot be granted by project memberships")
+			return
+		}
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			respondError(w, http.StatusBadRequest, "Role not found")
+			return
+		}
+		slog.ErrorContext(r.Context(), "Failed to create project membership",
 			"error", err,
 			"project_id", projectID,
 			"user_id", req.UserID,
@@ -212,7 +226,7 @@ func (h *MembershipsHandler) CreateProjectMembership(w http.ResponseWriter, r *h
 
 	user, err := h.usersSrv.GetByID(r.Context(), membership.UserID)
 	if err != nil {
-		slog.Error("Failed to get user for membership response",
+		slog.ErrorContext(r.Context(), "Failed to get user for membership response",
 			"error", err,
 			"user_id", membership.UserID,
 		)
@@ -221,34 +235,46 @@ func (h *MembershipsHandler) CreateProjectMembership(w http.ResponseWriter, r *h
 	}
 
 	type MembershipResponse struct {
-		ID        string `json:"id"`
-		ProjectID int    `json:"project_id"`
-		UserID    int    `json:"user_id"`
-		Username  string `json:"username"`
-		Email     string `json:"email"`
-		RoleID    string `json:"role_id"`
-		RoleKey   string `json:"role_key"`
-		RoleName  string `json:"role_name"`
-		CreatedAt string `json:"created_at"`
+		ID         string  `json:"id"`
+		ProjectID  int     `json:"project_id"`
+		UserID     int     `json:"user_id"`
+		Username   string  `json:"username"`
+		Email      string  `json:"email"`
+		RoleID     string  `json:"role_id"`
+		RoleKey    string  `json:"role_key"`
+		RoleName   string  `json:"role_name"`
+		ValidUntil *string `json:"valid_until,omitempty"`
+		CreatedAt  string  `json:"created_at"`
 	}
 
 	respondJSON(w, http.StatusCreated, MembershipResponse{
-		ID:        string(membership.ID),
-		ProjectID: membership.ProjectID.Int(),
-		UserID:    membership.UserID.Int(),
-		Username:  user.Username,
-		Email:     user.Email,
-		RoleID:    string(membership.RoleID),
-		RoleKey:   membership.RoleKey,
-		RoleName:  membership.RoleName,
-		CreatedAt: membership.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		ID:         strconv.Itoa(int(membership.ID)),
+		ProjectID:  membership.ProjectID.Int(),
+		UserID:     membership.UserID.Int(),
+		Username:   user.Username,
+		Email:      user.Email,
+		RoleID:     string(membership.RoleID),
+		RoleKey:    membership.RoleKey,
+		RoleName:   membership.RoleName,
+		ValidUntil: formatValidUntil(membership.ValidUntil),
+		CreatedAt:  membership.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	})
 }
 
+func formatValidUntil(validUntil *time.Time) *string {
+	if validUntil == nil {
+		return nil
+	}
+
+	formatted := validUntil.Format(time.RFC3339)
+
+	return &formatted
+}
+
 // DeleteProjectMembership removes a user from a project
 func (h *MembershipsHandler) DeleteProjectMembership(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodDelete {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -301,7 +327,7 @@ func (h *MembershipsHandler) DeleteProjectMembership(w http.ResponseWriter, r *h
 			respondError(w, http.StatusNotFound, "Membership not found")
 			return
 		}
-		slog.Error("Failed to delete project membership",
+		slog.ErrorContext(r.Context(), "Failed to delete project membership",
 			"error", err,
 			"project_id", projectID,
 			"membership_id", membershipIDStr,
@@ -316,7 +342,7 @@ func (h *MembershipsHandler) DeleteProjectMembership(w http.ResponseWriter, r *h
 // ListRoles returns all available roles
 func (h *MembershipsHandler) ListRoles(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -326,7 +352,7 @@ func (h *MembershipsHandler) ListRoles(w http.ResponseWriter, r *http.Request) {
 
 	roles, err := h.membershipsSrv.ListRoles(r.Context())
 	if err != nil {
-		slog.Error("Failed to list roles", "error", err)
+		slog.ErrorContext(r.Context(), "Failed to list roles", "error", err)
 		respondError(w, http.StatusInternalServerError, "Failed to list roles")
 		return
 	}
@@ -350,3 +376,174 @@ func (h *MembershipsHandler) ListRoles(w http.ResponseWriter, r *http.Request) {
 
 	respondJSON(w, http.StatusOK, result)
 }
+
+type permissionResponse struct {
+	Key         domain.PermKey `json:"key"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+}
+
+func toPermissionsResponse(perms []domain.Permission) []permissionResponse {
+	result := make([]permissionResponse, 0, len(perms))
+	for _, perm := range perms {
+		result = append(result, permissionResponse{
+			Key:         perm.Key,
+			Name:        perm.Name,
+			Description: perm.Description,
+		})
+	}
+
+	return result
+}
+
+// ListPermissions returns the catalog of all permissions
+func (h *MembershipsHandler) ListPermissions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	perms, err := h.membershipsSrv.ListPermissions(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to list permissions", "error", err)
+		respondError(w, http.StatusInternalServerError, "Failed to list permissions")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, toPermissionsResponse(perms))
+}
+
+// GetRolePermissions returns the permissions granted by a role
+func (h *MembershipsHandler) GetRolePermissions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	roleID := appcontext.Param(r.Context(), "id")
+	if err := uuid.Validate(roleID); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid role id")
+		return
+	}
+
+	perms, err := h.membershipsSrv.GetRolePermissions(r.Context(), domain.RoleID(roleID))
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			respondError(w, http.StatusNotFound, "Role not found")
+			return
+		}
+
+		slog.ErrorContext(r.Context(), "Failed to get role permissions", "error", err, "role_id", roleID)
+		respondError(w, http.StatusInternalServerError, "Failed to get role permissions")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, toPermissionsResponse(perms))
+}
+
+type permissionGrantResponse struct {
+	Permission domain.PermKey `json:"permission"`
+	Source     string         `json:"source"`
+	RoleID     string         `json:"role_id,omitempty"`
+	RoleKey    string         `json:"role_key,omitempty"`
+	RoleName   string         `json:"role_name,omitempty"`
+	GrantID    int            `json:"grant_id,omitempty"`
+}
+
+type effectivePermissionsResponse struct {
+	UserID      int                       `json:"user_id"`
+	ProjectID   int                       `json:"project_id"`
+	IsSuperuser bool                      `json:"is_superuser"`
+	Permissions []domain.PermKey          `json:"permissions"`
+	Grants      []permissionGrantResponse `json:"grants"`
+}
+
+// GetEffectivePermissions returns the permissions a user has in a project and what grants each of them.
+// Without user_id it describes the current user. Describing other users requires membership.manage.
+func (h *MembershipsHandler) GetEffectivePermissions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	projectID, err := strconv.Atoi(appcontext.Param(r.Context(), "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid project_id")
+		return
+	}
+
+	projID := domain.ProjectID(projectID)
+
+	userID := appcontext.UserID(r.Context())
+	if userIDStr := r.URL.Query().Get("user_id"); userIDStr != "" {
+		id, err := strconv.Atoi(userIDStr)
+		if err != nil || id <= 0 {
+			respondError(w, http.StatusBadRequest, "invalid user_id")
+			return
+		}
+
+		userID = domain.UserID(id)
+	}
+
+	if userID != appcontext.UserID(r.Context()) && !appcontext.IsSuper(r.Context()) {
+		hasManage, err := h.permissionsSrv.HasProjectPermission(r.Context(), projID, domain.PermMembershipManage)
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			respondError(w, http.StatusNotFound, "Project not found")
+			return
+		}
+
+		if err != nil || !hasManage {
+			respondError(w, http.StatusForbidden,
+				"Only users with membership.manage permission can view permissions of other users")
+			return
+		}
+	}
+
+	effective, err := h.membershipsSrv.GetEffectivePermissions(r.Context(), projID, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			respondError(w, http.StatusNotFound, "Project or user not found")
+			return
+		}
+
+		slog.ErrorContext(r.Context(), "Failed to get effective permissions",
+			"error", err,
+			"project_id", projectID,
+			"user_id", userID,
+		)
+		respondError(w, http.StatusInternalServerError, "Failed to get effective permissions")
+		return
+	}
+
+	grants := make([]permissionGrantResponse, 0, len(effective.Grants))
+	for _, grant := range effective.Grants {
+		grants = append(grants, permissionGrantResponse{
+			Permission: grant.Permission,
+			Source:     string(grant.Source),
+			RoleID:     string(grant.RoleID),
+			RoleKey:    grant.RoleKey,
+			RoleName:   grant.RoleName,
+			GrantID:    grant.GrantID,
+		})
+	}
+
+	respondJSON(w, http.StatusOK, effectivePermissionsResponse{
+		UserID:      effective.UserID.Int(),
+		ProjectID:   effective.ProjectID.Int(),
+		IsSuperuser: effective.IsSuperuser,
+		Permissions: effective.Permissions,
+		Grants:      grants,
+	})
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/rom8726/floxy-manager/internal/api/rest/apierror"
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/services/migrator"
+)
+
+// MigrationsHandler lets superusers diagnose the database schema without a database shell.
+type MigrationsHandler struct {
+	migrator contract.Migrator
+}
+
+func NewMigrationsHandler(migrator contract.Migrator) *MigrationsHandler {
+	return &MigrationsHandler{
+		migrator: migrator,
+	}
+}
+
+// Status handles GET /api/v1/admin/migrations
+// It reports the current schema version, the dirty flag and the pending migrations.
+func (h *MigrationsHandler) Status(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r, http.MethodGet) {
+		return
+	}
+
+	status, err := h.migrator.Status(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to get migrations status", "error", err)
+		apierror.RespondDetails(w, http.StatusInternalServerError, apierror.CodeInternal, "Failed to get migrations status", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, status)
+}
+
+// Apply handles POST /api/v1/admin/migrations/apply
+// A dirty database is left alone (409), the failed migration must be fixed by hand first.
+func (h *MigrationsHandler) Apply(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r, http.MethodPost) {
+		return
+	}
+
+	status, err := h.migrator.Up(r.Context())
+	if err != nil {
+		if errors.Is(err, migrator.ErrDirty) {
+			respondError(w, http.StatusConflict, migrator.ErrDirty.Error())
+			return
+		}
+
+		slog.ErrorContext(r.Context(), "Failed to apply migrations", "error", err)
+		apierror.RespondDetails(w, http.StatusInternalServerError, apierror.CodeInternal, "Failed to apply migrations", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, status)
+}
+
+func (h *MigrationsHandler) authorize(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method != method {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return false
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return false
+	}
+
+	if !appcontext.IsSuper(r.Context()) {
+		respondError(w, http.StatusForbidden, "Only superusers can manage database migrations")
+		return false
+	}
+
+	return true
+}
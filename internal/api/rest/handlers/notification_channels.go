@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	notificationchannelsusecase "github.com/rom8726/floxy-manager/internal/usecases/notificationchannels"
+)
+
+type NotificationChannelsHandler struct {
+	channelsUseCase contract.NotificationChannelsUseCase
+	permissionsSrv  contract.PermissionsService
+}
+
+func NewNotificationChannelsHandler(
+	channelsUseCase contract.NotificationChannelsUseCase,
+	permissionsSrv contract.PermissionsService,
+) *NotificationChannelsHandler {
+	return &NotificationChannelsHandler{
+		channelsUseCase: channelsUseCase,
+		permissionsSrv:  permissionsSrv,
+	}
+}
+
+type notificationChannelRequest struct {
+	Name                        string                      `json:"name"`
+	Kind                        string                      `json:"kind"`
+	WebhookURL                  string                      `json:"webhook_url"`
+	Channel                     string                      `json:"channel"`
+	Events                      []domain.LifecycleEventType `json:"events"`
+	LongRunningThresholdSeconds int                         `json:"long_running_threshold_seconds"`
+	Enabled                     *bool                       `json:"enabled"`
+}
+
+func (req *notificationChannelRequest) toDTO() domain.NotificationChannelDTO {
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	return domain.NotificationChannelDTO{
+		Name:                        req.Name,
+		Kind:                        domain.NotificationChannelKind(req.Kind),
+		WebhookURL:                  req.WebhookURL,
+		Channel:                     req.Channel,
+		Events:                      req.Events,
+		LongRunningThresholdSeconds: req.LongRunningThresholdSeconds,
+		Enabled:                     enabled,
+	}
+}
+
+// List handles GET /api/v1/projects/:id/notifications
+func (h *NotificationChannelsHandler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	projectID, ok := authorizeProjectParam(w, r, h.permissionsSrv, false)
+	if !ok {
+		return
+	}
+
+	channels, err := h.channelsUseCase.List(r.Context(), projectID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to list notification channels",
+			"error", err,
+			"project_id", projectID,
+		)
+		respondError(w, http.StatusInternalServerError, "Failed to list notification channels")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, channels)
+}
+
+// Create handles POST /api/v1/projects/:id/notifications
+func (h *NotificationChannelsHandler) Create(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	projectID, ok := authorizeProjectParam(w, r, h.permissionsSrv, true)
+	if !ok {
+		return
+	}
+
+	var req notificationChannelRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	channel, err := h.channelsUseCase.Create(r.Context(), projectID, req.toDTO())
+	if err != nil {
+		respondNotificationChannelError(w, r, err, "Failed to create notification channel", 0)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, channel)
+}
+
+// Get handles GET /api/v1/projects/:id/notifications/:nid
+func (h *NotificationChannelsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	projectID, ok := authorizeProjectParam(w, r, h.permissionsSrv, false)
+	if !ok {
+		return
+	}
+
+	id, ok := parseNotificationChannelID(w, r)
+	if !ok {
+		return
+	}
+
+	channel, err := h.channelsUseCase.Get(r.Context(), projectID, id)
+	if err != nil {
+		respondNotificationChannelError(w, r, err, "Failed to get notification channel", id)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, channel)
+}
+
+// Update handles PUT /api/v1/projects/:id/notifications/:nid
+// An empty webhook_url keeps the current URL.
+func (h *NotificationChannelsHandler) Update(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	projectID, ok := authorizeProjectParam(w, r, h.permissionsSrv, true)
+	if !ok {
+		return
+	}
+
+	id, ok := parseNotificationChannelID(w, r)
+	if !ok {
+		return
+	}
+
+	var req notificationChannelRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	channel, err := h.channelsUseCase.Update(r.Context(), projectID, id, req.toDTO())
+	if err != nil {
+		respondNotificationChannelError(w, r, err, "Failed to update notification channel", id)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, channel)
+}
+
+// Delete handles DELETE /api/v1/projects/:id/notifications/:nid
+func (h *NotificationChannelsHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	projectID, ok := authorizeProjectParam(w, r, h.permissionsSrv, true)
+	if !ok {
+		return
+	}
+
+	id, ok := parseNotificationChannelID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.channelsUseCase.Delete(r.Context(), projectID, id); err != nil {
+		respondNotificationChannelError(w, r, err, "Failed to delete notification channel", id)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "notification channel deleted successfully"})
+}
+
+// ListMessages handles GET /api/v1/projects/:id/notifications/:nid/messages
+func (h *NotificationChannelsHandler) ListMessages(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	projectID, ok := authorizeProjectParam(w, r, h.permissionsSrv, false)
+	if !ok {
+		return
+	}
+
+	id, ok := parseNotificationChannelID(w, r)
+	if !ok {
+		return
+	}
+
+	page, pageSize := parsePagination(r)
+
+	items, total, err := h.channelsUseCase.ListMessages(r.Context(), projectID, id, page, pageSize)
+	if err != nil {
+		respondNotificationChannelError(w, r, err, "Failed to list notification messages", id)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"items":     items,
+		"page":      page,
+		"page_size": pageSize,
+		"total":     total,
+	})
+}
+
+func parseNotificationChannelID(w http.ResponseWriter, r *http.Request) (domain.NotificationChannelID, bool) {
+	id, err := strconv.Atoi(appcontext.Param(r.Context(), "nid"))
+	if err != nil || id <= 0 {
+		respondError(w, http.StatusBadRequest, "Invalid notification channel ID")
+		return 0, false
+	}
+
+	return domain.NotificationChannelID(id), true
+}
+
+func respondNotificationChannelError(
+	w http.ResponseWriter,
+	r *http.Request,
+	err error,
+	msg string,
+	id domain.NotificationChannelID,
+) {
+	switch {
+	case errors.Is(err, notificationchannelsusecase.ErrInvalidChannel):
+		respondError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrEntityNotFound):
+		respondError(w, http.StatusNotFound, "Notification channel not found")
+	case errors.Is(err, domain.ErrEntityAlreadyExists):
+		respondError(w, http.StatusConflict, "Notification channel with this name already exists")
+	default:
+		slog.ErrorContext(r.Context(), msg,
+			"error", err,
+			"channel_id", id,
+		)
+		respondError(w, http.StatusInternalServerError, msg)
+	}
+}
@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"encoding/json"
 	"errors"
 	"net/http"
 
@@ -22,21 +21,15 @@ func NewPasswordHandler(usersService contract.UsersUseCase) *PasswordHandler {
 
 func (h *PasswordHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	var req struct {
-		Email string `json:"email"`
+		Email string `json:"email" validate:"required"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request", http.StatusBadRequest)
-		return
-	}
-
-	if req.Email == "" {
-		respondError(w, http.StatusBadRequest, "email is required")
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
@@ -53,22 +46,16 @@ func (h *PasswordHandler) ForgotPassword(w http.ResponseWriter, r *http.Request)
 
 func (h *PasswordHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	var req struct {
-		Token       string `json:"token"`
-		NewPassword string `json:"new_password"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request", http.StatusBadRequest)
-		return
+		Token       string `json:"token" validate:"required"`
+		NewPassword string `json:"new_password" validate:"required"`
 	}
 
-	if req.Token == "" || req.NewPassword == "" {
-		respondError(w, http.StatusBadRequest, "token and new_password are required")
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
@@ -85,7 +72,7 @@ func (h *PasswordHandler) ResetPassword(w http.ResponseWriter, r *http.Request)
 
 func (h *PasswordHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -100,21 +87,10 @@ func (h *PasswordHandler) ChangePassword(w http.ResponseWriter, r *http.Request)
 	}
 
 	var req struct {
-		NewPassword string `json:"new_password"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request", http.StatusBadRequest)
-		return
-	}
-
-	if req.NewPassword == "" {
-		respondError(w, http.StatusBadRequest, "new_password is required")
-		return
+		NewPassword string `json:"new_password" validate:"required,min=6"`
 	}
 
-	if len(req.NewPassword) < 6 {
-		respondError(w, http.StatusBadRequest, "password must be at least 6 characters")
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
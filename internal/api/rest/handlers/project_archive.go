@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/rom8726/floxy-manager/internal/api/rest/apierror"
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/services/projectarchive"
+	projectarchiveusecase "github.com/rom8726/floxy-manager/internal/usecases/projectarchive"
+)
+
+const maxProjectArchiveSize = 64 << 20
+
+type ProjectArchiveHandler struct {
+	projectArchiveUseCase contract.ProjectArchiveUseCase
+	tenantsRepo           contract.TenantsRepository
+	permissionsSrv        contract.PermissionsService
+}
+
+func NewProjectArchiveHandler(
+	projectArchiveUseCase contract.ProjectArchiveUseCase,
+	tenantsRepo contract.TenantsRepository,
+	permissionsSrv contract.PermissionsService,
+) *ProjectArchiveHandler {
+	return &ProjectArchiveHandler{
+		projectArchiveUseCase: projectArchiveUseCase,
+		tenantsRepo:           tenantsRepo,
+		permissionsSrv:        permissionsSrv,
+	}
+}
+
+// ExportProject handles GET /api/v1/project-export?tenant_id=&project_id=
+// The project state is returned as a tar.gz archive (see projectarchive.Write).
+// Notification channel webhook URLs are included with include_secrets=true only.
+func (h *ProjectArchiveHandler) ExportProject(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !requireAuthForWorkflows(w, r) {
+		return
+	}
+
+	tenantID, projectID, err := parseTenantAndProject(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.permissionsSrv.CanManageProject(r.Context(), projectID); err != nil {
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			respondError(w, http.StatusForbidden, "Access denied to this project")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to verify permissions")
+		return
+	}
+
+	includeSecrets := r.URL.Query().Get("include_secrets") == "true"
+
+	archive, err := h.projectArchiveUseCase.Export(r.Context(), tenantID, projectID, includeSecrets)
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			respondError(w, http.StatusNotFound, "Project not found")
+			return
+		}
+
+		slog.ErrorContext(r.Context(), "Failed to export project",
+			"error", err,
+			"tenant_id", tenantID,
+			"project_id", projectID,
+		)
+		respondError(w, http.StatusInternalServerError, "Failed to export project")
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := projectarchive.Write(&buf, archive); err != nil {
+		slog.ErrorContext(r.Context(), "Failed to write project archive", "error", err, "project_id", projectID)
+		respondError(w, http.StatusInternalServerError, "Failed to export project")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition",
+		fmt.Sprintf(`attachment; filename="project-%d.tar.gz"`, projectID))
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(buf.Bytes())
+}
+
+// ImportProject handles POST /api/v1/project-import?tenant_id=&name=
+// The body is an archive written by ExportProject. A new project is created in the tenant, named after
+// the archived project unless name is given. Superusers only, as memberships are recreated.
+// When an entity is rejected nothing is created and 422 is returned.
+func (h *ProjectArchiveHandler) ImportProject(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	if !appcontext.IsSuper(r.Context()) {
+		respondError(w, http.StatusForbidden, "Only superusers can import projects")
+		return
+	}
+
+	tenantIDInt, err := strconv.Atoi(r.URL.Query().Get("tenant_id"))
+	if err != nil || tenantIDInt <= 0 {
+		respondError(w, http.StatusBadRequest, "tenant_id is required")
+		return
+	}
+
+	tenantID := domain.TenantID(tenantIDInt)
+
+	if _, err := h.tenantsRepo.GetByID(r.Context(), tenantID); err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			respondError(w, http.StatusNotFound, "Tenant not found")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to get tenant")
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxProjectArchiveSize+1))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Failed to read request body")
+		return
+	}
+	if len(data) > maxProjectArchiveSize {
+		respondError(w, http.StatusRequestEntityTooLarge, "Payload too large")
+		return
+	}
+
+	archive, err := projectarchive.Read(data)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := h.projectArchiveUseCase.Import(r.Context(), tenantID, r.URL.Query().Get("name"), archive)
+	if err != nil {
+		if errors.Is(err, projectarchiveusecase.ErrImportRejected) {
+			apierror.RespondDetails(w, http.StatusUnprocessableEntity, apierror.CodeUnprocessable, err.Error(),
+				map[string]interface{}{"workflows": result.Workflows})
+			return
+		}
+
+		slog.ErrorContext(r.Context(), "Failed to import project",
+			"error", err,
+			"tenant_id", tenantID,
+		)
+		respondError(w, http.StatusInternalServerError, "Failed to import project")
+		return
+	}
+
+	h.permissionsSrv.InvalidateCache()
+
+	respondJSON(w, http.StatusCreated, result)
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	projectsettingsusecase "github.com/rom8726/floxy-manager/internal/usecases/projectsettings"
+)
+
+type ProjectSettingsHandler struct {
+	projectSettingsUseCase contract.ProjectSettingsUseCase
+	permissionsSrv         contract.PermissionsService
+}
+
+func NewProjectSettingsHandler(
+	projectSettingsUseCase contract.ProjectSettingsUseCase,
+	permissionsSrv contract.PermissionsService,
+) *ProjectSettingsHandler {
+	return &ProjectSettingsHandler{
+		projectSettingsUseCase: projectSettingsUseCase,
+		permissionsSrv:         permissionsSrv,
+	}
+}
+
+// GetSettings handles GET /api/v1/projects/:id/settings
+func (h *ProjectSettingsHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	projectID, ok := authorizeProjectParam(w, r, h.permissionsSrv, false)
+	if !ok {
+		return
+	}
+
+	settings, err := h.projectSettingsUseCase.GetSettings(r.Context(), projectID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to get project settings",
+			"error", err,
+			"project_id", projectID,
+		)
+		respondError(w, http.StatusInternalServerError, "Failed to get project settings")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, settings)
+}
+
+// UpdateSettings handles PUT /api/v1/projects/:id/settings
+// The body replaces all the settings, the ones left empty get their defaults.
+func (h *ProjectSettingsHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	projectID, ok := authorizeProjectParam(w, r, h.permissionsSrv, true)
+	if !ok {
+		return
+	}
+
+	var req domain.ProjectSettingsValues
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	settings, err := h.projectSettingsUseCase.UpdateSettings(r.Context(), domain.ProjectSettings{
+		ProjectID:             projectID,
+		ProjectSettingsValues: req,
+	})
+	if err != nil {
+		if errors.Is(err, projectsettingsusecase.ErrInvalidSettings) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		slog.ErrorContext(r.Context(), "Failed to update project settings",
+			"error", err,
+			"project_id", projectID,
+		)
+		respondError(w, http.StatusInternalServerError, "Failed to update project settings")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, settings)
+}
@@ -1,7 +1,7 @@
 package handlers
 
 import (
-	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 	"strconv"
@@ -16,6 +16,7 @@ type ProjectsHandler struct {
 	permissionsSrv  contract.PermissionsService
 	rolesRepo       contract.RolesRepository
 	membershipsRepo contract.MembershipsRepository
+	deletionUseCase contract.DeletionUseCase
 }
 
 func NewProjectsHandler(
@@ -23,18 +24,20 @@ func NewProjectsHandler(
 	permissionsSrv contract.PermissionsService,
 	rolesRepo contract.RolesRepository,
 	membershipsRepo contract.MembershipsRepository,
+	deletionUseCase contract.DeletionUseCase,
 ) *ProjectsHandler {
 	return &ProjectsHandler{
 		projectsRepo:    projectsRepo,
 		permissionsSrv:  permissionsSrv,
 		rolesRepo:       rolesRepo,
 		membershipsRepo: membershipsRepo,
+		deletionUseCase: deletionUseCase,
 	}
 }
 
 func (h *ProjectsHandler) List(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -45,7 +48,7 @@ func (h *ProjectsHandler) List(w http.ResponseWriter, r *http.Request) {
 	// Get tenant_id from query parameter
 	tenantIDStr := r.URL.Query().Get("tenant_id")
 	if tenantIDStr == "" {
-		slog.Warn("Missing tenant_id in request",
+		slog.WarnContext(r.Context(), "Missing tenant_id in request",
 			"path", r.URL.Path,
 		)
 		respondError(w, http.StatusBadRequest, "tenant_id is required")
@@ -54,7 +57,7 @@ func (h *ProjectsHandler) List(w http.ResponseWriter, r *http.Request) {
 
 	tenantID, err := strconv.Atoi(tenantIDStr)
 	if err != nil {
-		slog.Warn("Invalid tenant_id in request",
+		slog.WarnContext(r.Context(), "Invalid tenant_id in request",
 			"tenant_id", tenantIDStr,
 			"error", err,
 		)
@@ -64,11 +67,11 @@ func (h *ProjectsHandler) List(w http.ResponseWriter, r *http.Request) {
 
 	allProjects, err := h.projectsRepo.ListByTenant(r.Context(), domain.TenantID(tenantID))
 	if err != nil {
-		slog.Error("Failed to list projects by tenant",
+		slog.ErrorContext(r.Context(), "Failed to list projects by tenant",
 			"error", err,
 			"tenant_id", tenantID,
 		)
-		respondError(w, http.StatusInternalServerError, err.Error())
+		respondErr(w, err)
 		return
 	}
 
@@ -82,7 +85,7 @@ func (h *ProjectsHandler) List(w http.ResponseWriter, r *http.Request) {
 		// Filter projects by permissions
 		accessibleProjects, err := h.permissionsSrv.GetAccessibleProjects(r.Context(), allProjects)
 		if err != nil {
-			slog.Error("Failed to filter projects by permissions",
+			slog.ErrorContext(r.Context(), "Failed to filter projects by permissions",
 				"error", err,
 				"tenant_id", tenantID,
 			)
@@ -97,7 +100,7 @@ func (h *ProjectsHandler) List(w http.ResponseWriter, r *http.Request) {
 
 func (h *ProjectsHandler) Create(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -117,7 +120,7 @@ func (h *ProjectsHandler) Create(w http.ResponseWriter, r *http.Request) {
 		// Check if user is project_owner in any project
 		allProjects, err := h.projectsRepo.List(r.Context())
 		if err != nil {
-			slog.Error("Failed to list projects for permission check", "error", err)
+			slog.ErrorContext(r.Context(), "Failed to list projects for permission check", "error", err)
 			respondError(w, http.StatusInternalServerError, "Failed to check permissions")
 			return
 		}
@@ -147,23 +150,12 @@ func (h *ProjectsHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		Name        string `json:"name"`
+		Name        string `json:"name" validate:"required"`
 		Description string `json:"description"`
-		TenantID    int    `json:"tenant_id"`
+		TenantID    int    `json:"tenant_id" validate:"required"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
-		return
-	}
-
-	if req.Name == "" {
-		respondError(w, http.StatusBadRequest, "name is required")
-		return
-	}
-
-	if req.TenantID == 0 {
-		respondError(w, http.StatusBadRequest, "tenant_id is required")
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
@@ -174,7 +166,7 @@ func (h *ProjectsHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 	projectID, err := h.projectsRepo.Create(r.Context(), projectDTO, domain.TenantID(req.TenantID))
 	if err != nil {
-		slog.Error("Failed to create project",
+		slog.ErrorContext(r.Context(), "Failed to create project",
 			"error", err,
 			"name", req.Name,
 			"tenant_id", req.TenantID,
@@ -185,7 +177,7 @@ func (h *ProjectsHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 	project, err := h.projectsRepo.GetByID(r.Context(), projectID)
 	if err != nil {
-		slog.Error("Failed to get created project", "error", err, "project_id", projectID)
+		slog.ErrorContext(r.Context(), "Failed to get created project", "error", err, "project_id", projectID)
 		respondError(w, http.StatusInternalServerError, "Failed to retrieve created project")
 		return
 	}
@@ -195,7 +187,7 @@ func (h *ProjectsHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 func (h *ProjectsHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodDelete {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -225,7 +217,7 @@ func (h *ProjectsHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		// Check if user has project.manage permission for this project
 		hasManage, err := h.permissionsSrv.HasProjectPermission(r.Context(), projectID, domain.PermProjectManage)
 		if err != nil {
-			slog.Error("Failed to check project.manage permission for delete",
+			slog.ErrorContext(r.Context(), "Failed to check project.manage permission for delete",
 				"error", err,
 				"user_id", userID,
 				"project_id", projectID,
@@ -234,39 +226,52 @@ func (h *ProjectsHandler) Delete(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		if !hasManage {
-			slog.Warn("User denied delete project - no project.manage permission",
+			slog.WarnContext(r.Context(), "User denied delete project - no project.manage permission",
 				"user_id", userID,
 				"project_id", projectID,
 			)
 			respondError(w, http.StatusForbidden, "Only superusers or users with project.manage permission can delete projects")
 			return
 		}
-		slog.Debug("User has project.manage permission for delete",
+		slog.DebugContext(r.Context(), "User has project.manage permission for delete",
 			"user_id", userID,
 			"project_id", projectID,
 		)
 	}
 
-	err = h.projectsRepo.Delete(r.Context(), projectID)
+	force, err := parseForce(r)
 	if err != nil {
-		if err == domain.ErrEntityNotFound {
-			respondError(w, http.StatusNotFound, "project not found")
-			return
+		respondError(w, http.StatusBadRequest, "invalid force flag")
+		return
+	}
+
+	if force && !isSuper {
+		respondError(w, http.StatusForbidden, "Only superusers can force the deletion of a project")
+		return
+	}
+
+	impact, err := h.deletionUseCase.DeleteProject(r.Context(), projectID, force)
+	if err != nil {
+		if !errors.Is(err, domain.ErrEntityNotFound) && !errors.Is(err, domain.ErrEntityInUse) {
+			slog.ErrorContext(r.Context(), "Failed to delete project",
+				"error", err,
+				"project_id", id,
+				"force", force,
+			)
 		}
-		slog.Error("Failed to delete project",
-			"error", err,
-			"project_id", id,
-		)
-		respondError(w, http.StatusInternalServerError, "Failed to delete project")
+		respondDeletionError(w, err, "project", impact)
 		return
 	}
 
-	respondJSON(w, http.StatusOK, map[string]string{"message": "project deleted successfully"})
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "project deleted successfully",
+		"impact":  impact,
+	})
 }
 
 func (h *ProjectsHandler) Update(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPut && r.Method != http.MethodPatch {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -296,7 +301,7 @@ func (h *ProjectsHandler) Update(w http.ResponseWriter, r *http.Request) {
 		// Check if user has project.manage permission for this project
 		hasManage, err := h.permissionsSrv.HasProjectPermission(r.Context(), projectID, domain.PermProjectManage)
 		if err != nil {
-			slog.Error("Failed to check project.manage permission for update",
+			slog.ErrorContext(r.Context(), "Failed to check project.manage permission for update",
 				"error", err,
 				"user_id", userID,
 				"project_id", projectID,
@@ -305,31 +310,25 @@ func (h *ProjectsHandler) Update(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		if !hasManage {
-			slog.Warn("User denied update project - no project.manage permission",
+			slog.WarnContext(r.Context(), "User denied update project - no project.manage permission",
 				"user_id", userID,
 				"project_id", projectID,
 			)
 			respondError(w, http.StatusForbidden, "Only superusers or users with project.manage permission can update projects")
 			return
 		}
-		slog.Debug("User has project.manage permission for update",
+		slog.DebugContext(r.Context(), "User has project.manage permission for update",
 			"user_id", userID,
 			"project_id", projectID,
 		)
 	}
 
 	var req struct {
-		Name        string `json:"name"`
+		Name        string `json:"name" validate:"required"`
 		Description string `json:"description"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
-		return
-	}
-
-	if req.Name == "" {
-		respondError(w, http.StatusBadRequest, "name is required")
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
@@ -339,7 +338,7 @@ func (h *ProjectsHandler) Update(w http.ResponseWriter, r *http.Request) {
 			respondError(w, http.StatusNotFound, "project not found")
 			return
 		}
-		slog.Error("Failed to update project",
+		slog.ErrorContext(r.Context(), "Failed to update project",
 			"error", err,
 			"project_id", id,
 			"name", req.Name,
@@ -351,7 +350,7 @@ func (h *ProjectsHandler) Update(w http.ResponseWriter, r *http.Request) {
 	// Get updated project to return
 	project, err := h.projectsRepo.GetByID(r.Context(), projectID)
 	if err != nil {
-		slog.Error("Failed to get updated project", "error", err, "project_id", projectID)
+		slog.ErrorContext(r.Context(), "Failed to get updated project", "error", err, "project_id", projectID)
 		respondError(w, http.StatusInternalServerError, "Failed to retrieve updated project")
 		return
 	}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	redactionusecase "github.com/rom8726/floxy-manager/internal/usecases/redaction"
+)
+
+type RedactionHandler struct {
+	redactionUseCase contract.RedactionUseCase
+	permissionsSrv   contract.PermissionsService
+}
+
+func NewRedactionHandler(
+	redactionUseCase contract.RedactionUseCase,
+	permissionsSrv contract.PermissionsService,
+) *RedactionHandler {
+	return &RedactionHandler{
+		redactionUseCase: redactionUseCase,
+		permissionsSrv:   permissionsSrv,
+	}
+}
+
+type redactionRulesRequest struct {
+	Paths []string `json:"paths"`
+}
+
+// GetRules handles GET /api/v1/projects/:id/redaction
+func (h *RedactionHandler) GetRules(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	projectID, ok := authorizeProjectParam(w, r, h.permissionsSrv, false)
+	if !ok {
+		return
+	}
+
+	rules, err := h.redactionUseCase.GetRules(r.Context(), projectID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to get redaction rules", "error", err, "project_id", projectID)
+		respondError(w, http.StatusInternalServerError, "Failed to get redaction rules")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, rules)
+}
+
+// UpdateRules handles PUT /api/v1/projects/:id/redaction
+// The paths are JSONPath patterns, e.g. "$.card.number" or "$..token", matched against the input
+// and output of instances and steps, the payload of events and the input of DLQ items.
+func (h *RedactionHandler) UpdateRules(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	projectID, ok := authorizeProjectParam(w, r, h.permissionsSrv, true)
+	if !ok {
+		return
+	}
+
+	var req redactionRulesRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	rules, err := h.redactionUseCase.UpdateRules(r.Context(), projectID, req.Paths)
+	if err != nil {
+		if errors.Is(err, redactionusecase.ErrInvalidRules) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		slog.ErrorContext(r.Context(), "Failed to update redaction rules", "error", err, "project_id", projectID)
+		respondError(w, http.StatusInternalServerError, "Failed to update redaction rules")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, rules)
+}
+
+// respondRedactionError responds to a failed redaction: payloads are never returned unredacted.
+func respondRedactionError(w http.ResponseWriter, r *http.Request, err error) {
+	slog.ErrorContext(r.Context(), "Failed to redact payloads", "error", err)
+	respondError(w, http.StatusInternalServerError, "Failed to redact payloads")
+}
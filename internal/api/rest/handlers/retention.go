@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	retentionusecase "github.com/rom8726/floxy-manager/internal/usecases/retention"
+)
+
+type RetentionHandler struct {
+	retentionUseCase contract.RetentionUseCase
+	permissionsSrv   contract.PermissionsService
+}
+
+func NewRetentionHandler(
+	retentionUseCase contract.RetentionUseCase,
+	permissionsSrv contract.PermissionsService,
+) *RetentionHandler {
+	return &RetentionHandler{
+		retentionUseCase: retentionUseCase,
+		permissionsSrv:   permissionsSrv,
+	}
+}
+
+type retentionSettingsRequest struct {
+	CompletedDays *int `json:"completed_days"`
+	FailedDays    *int `json:"failed_days"`
+	EventsDays    *int `json:"events_days"`
+}
+
+// GetSettings handles GET /api/v1/projects/:id/retention
+func (h *RetentionHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	projectID, ok := authorizeProjectParam(w, r, h.permissionsSrv, false)
+	if !ok {
+		return
+	}
+
+	settings, err := h.retentionUseCase.GetSettings(r.Context(), projectID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to get retention settings",
+			"error", err,
+			"project_id", projectID,
+		)
+		respondError(w, http.StatusInternalServerError, "Failed to get retention settings")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, settings)
+}
+
+// UpdateSettings handles PUT /api/v1/projects/:id/retention
+// A period left null keeps the corresponding history forever.
+func (h *RetentionHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	projectID, ok := authorizeProjectParam(w, r, h.permissionsSrv, true)
+	if !ok {
+		return
+	}
+
+	var req retentionSettingsRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	settings, err := h.retentionUseCase.UpdateSettings(r.Context(), domain.RetentionSettings{
+		ProjectID:     projectID,
+		CompletedDays: req.CompletedDays,
+		FailedDays:    req.FailedDays,
+		EventsDays:    req.EventsDays,
+	})
+	if err != nil {
+		if errors.Is(err, retentionusecase.ErrInvalidRetentionSettings) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		slog.ErrorContext(r.Context(), "Failed to update retention settings",
+			"error", err,
+			"project_id", projectID,
+		)
+		respondError(w, http.StatusInternalServerError, "Failed to update retention settings")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, settings)
+}
+
+// RunRetention handles POST /api/v1/projects/:id/retention/run
+// The retention settings of the project are applied now instead of on the next run of the background job.
+func (h *RetentionHandler) RunRetention(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	projectID, ok := authorizeProjectParam(w, r, h.permissionsSrv, true)
+	if !ok {
+		return
+	}
+
+	run, err := h.retentionUseCase.RunProject(r.Context(), projectID, time.Now())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to apply retention settings",
+			"error", err,
+			"project_id", projectID,
+		)
+		respondError(w, http.StatusInternalServerError, "Failed to apply retention settings")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, run)
+}
@@ -0,0 +1,346 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	schedulesusecase "github.com/rom8726/floxy-manager/internal/usecases/schedules"
+)
+
+const defaultPreviewRuns = 5
+
+type SchedulesHandler struct {
+	schedulesUseCase contract.SchedulesUseCase
+	permissionsSrv   contract.PermissionsService
+}
+
+func NewSchedulesHandler(
+	schedulesUseCase contract.SchedulesUseCase,
+	permissionsSrv contract.PermissionsService,
+) *SchedulesHandler {
+	return &SchedulesHandler{
+		schedulesUseCase: schedulesUseCase,
+		permissionsSrv:   permissionsSrv,
+	}
+}
+
+type scheduleRequest struct {
+	WorkflowID string          `json:"workflow_id"`
+	Name       string          `json:"name"`
+	CronExpr   string          `json:"cron_expr"`
+	Timezone   string          `json:"timezone"`
+	Input      json.RawMessage `json:"input"`
+	Enabled    *bool           `json:"enabled"`
+}
+
+func (req *scheduleRequest) toDTO() domain.ScheduleDTO {
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	return domain.ScheduleDTO{
+		WorkflowID: req.WorkflowID,
+		Name:       req.Name,
+		CronExpr:   req.CronExpr,
+		Timezone:   req.Timezone,
+		Input:      req.Input,
+		Enabled:    enabled,
+	}
+}
+
+// List handles GET /api/v1/schedules
+func (h *SchedulesHandler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	_, projectID, ok := h.authorize(w, r, false)
+	if !ok {
+		return
+	}
+
+	page, pageSize := parsePagination(r)
+
+	items, total, err := h.schedulesUseCase.List(r.Context(), projectID, page, pageSize)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to list schedules",
+			"error", err,
+			"project_id", projectID,
+		)
+		respondError(w, http.StatusInternalServerError, "Failed to list schedules")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"items":     items,
+		"page":      page,
+		"page_size": pageSize,
+		"total":     total,
+	})
+}
+
+// Get handles GET /api/v1/schedules/:id
+func (h *SchedulesHandler) Get(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	id, ok := parseScheduleID(w, r)
+	if !ok {
+		return
+	}
+
+	_, projectID, ok := h.authorize(w, r, false)
+	if !ok {
+		return
+	}
+
+	schedule, err := h.schedulesUseCase.Get(r.Context(), projectID, id)
+	if err != nil {
+		respondScheduleError(w, r, err, "Failed to get schedule", id)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, schedule)
+}
+
+// Create handles POST /api/v1/schedules
+func (h *SchedulesHandler) Create(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	tenantID, projectID, ok := h.authorize(w, r, true)
+	if !ok {
+		return
+	}
+
+	var req scheduleRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	schedule, err := h.schedulesUseCase.Create(r.Context(), tenantID, projectID, req.toDTO())
+	if err != nil {
+		respondScheduleError(w, r, err, "Failed to create schedule", 0)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, schedule)
+}
+
+// Update handles PUT /api/v1/schedules/:id
+func (h *SchedulesHandler) Update(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	id, ok := parseScheduleID(w, r)
+	if !ok {
+		return
+	}
+
+	tenantID, projectID, ok := h.authorize(w, r, true)
+	if !ok {
+		return
+	}
+
+	var req scheduleRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	schedule, err := h.schedulesUseCase.Update(r.Context(), tenantID, projectID, id, req.toDTO())
+	if err != nil {
+		respondScheduleError(w, r, err, "Failed to update schedule", id)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, schedule)
+}
+
+// Delete handles DELETE /api/v1/schedules/:id
+func (h *SchedulesHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	id, ok := parseScheduleID(w, r)
+	if !ok {
+		return
+	}
+
+	_, projectID, ok := h.authorize(w, r, true)
+	if !ok {
+		return
+	}
+
+	if err := h.schedulesUseCase.Delete(r.Context(), projectID, id); err != nil {
+		respondScheduleError(w, r, err, "Failed to delete schedule", id)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "schedule deleted successfully"})
+}
+
+// Enable handles POST /api/v1/schedules/:id/enable
+func (h *SchedulesHandler) Enable(w http.ResponseWriter, r *http.Request) {
+	h.setEnabled(w, r, true)
+}
+
+// Disable handles POST /api/v1/schedules/:id/disable
+func (h *SchedulesHandler) Disable(w http.ResponseWriter, r *http.Request) {
+	h.setEnabled(w, r, false)
+}
+
+// Preview handles GET /api/v1/schedule-preview?cron_expr=...&timezone=...&count=5
+func (h *SchedulesHandler) Preview(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	count := defaultPreviewRuns
+	if countStr := r.URL.Query().Get("count"); countStr != "" {
+		var err error
+		count, err = strconv.Atoi(countStr)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "invalid count")
+			return
+		}
+	}
+
+	runs, err := h.schedulesUseCase.PreviewNextRuns(
+		r.URL.Query().Get("cron_expr"),
+		r.URL.Query().Get("timezone"),
+		count,
+	)
+	if err != nil {
+		respondScheduleError(w, r, err, "Failed to preview schedule", 0)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string][]time.Time{"next_runs": runs})
+}
+
+func (h *SchedulesHandler) setEnabled(w http.ResponseWriter, r *http.Request, enabled bool) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	id, ok := parseScheduleID(w, r)
+	if !ok {
+		return
+	}
+
+	_, projectID, ok := h.authorize(w, r, true)
+	if !ok {
+		return
+	}
+
+	schedule, err := h.schedulesUseCase.SetEnabled(r.Context(), projectID, id, enabled)
+	if err != nil {
+		respondScheduleError(w, r, err, "Failed to change schedule state", id)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, schedule)
+}
+
+// authorize parses tenant and project and checks view (or manage) permission on the project.
+func (h *SchedulesHandler) authorize(
+	w http.ResponseWriter,
+	r *http.Request,
+	manage bool,
+) (domain.TenantID, domain.ProjectID, bool) {
+	tenantID, projectID, err := parseTenantAndProject(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return 0, 0, false
+	}
+
+	if manage {
+		err = h.permissionsSrv.CanManageProject(r.Context(), projectID)
+	} else {
+		err = h.permissionsSrv.CanViewProject(r.Context(), projectID)
+	}
+	if err != nil {
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			respondError(w, http.StatusForbidden, "Access denied to this project")
+			return 0, 0, false
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to verify permissions")
+		return 0, 0, false
+	}
+
+	return tenantID, projectID, true
+}
+
+func parseScheduleID(w http.ResponseWriter, r *http.Request) (domain.ScheduleID, bool) {
+	id, err := strconv.Atoi(appcontext.Param(r.Context(), "id"))
+	if err != nil || id <= 0 {
+		respondError(w, http.StatusBadRequest, "Invalid schedule ID")
+		return 0, false
+	}
+
+	return domain.ScheduleID(id), true
+}
+
+func respondScheduleError(w http.ResponseWriter, r *http.Request, err error, msg string, id domain.ScheduleID) {
+	switch {
+	case errors.Is(err, schedulesusecase.ErrInvalidSchedule):
+		respondError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrEntityNotFound):
+		respondError(w, http.StatusNotFound, "Schedule or workflow not found")
+	case errors.Is(err, domain.ErrEntityAlreadyExists):
+		respondError(w, http.StatusConflict, "Schedule with this name already exists")
+	default:
+		slog.ErrorContext(r.Context(), msg,
+			"error", err,
+			"schedule_id", id,
+		)
+		respondError(w, http.StatusInternalServerError, msg)
+	}
+}
@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	apitokensusecase "github.com/rom8726/floxy-manager/internal/usecases/apitokens"
+	serviceaccountsusecase "github.com/rom8726/floxy-manager/internal/usecases/serviceaccounts"
+)
+
+type ServiceAccountsHandler struct {
+	serviceAccountsUseCase contract.ServiceAccountsUseCase
+	permissionsSrv         contract.PermissionsService
+}
+
+func NewServiceAccountsHandler(
+	serviceAccountsUseCase contract.ServiceAccountsUseCase,
+	permissionsSrv contract.PermissionsService,
+) *ServiceAccountsHandler {
+	return &ServiceAccountsHandler{
+		serviceAccountsUseCase: serviceAccountsUseCase,
+		permissionsSrv:         permissionsSrv,
+	}
+}
+
+type serviceAccountRequest struct {
+	Name    string `json:"name"`
+	RoleKey string `json:"role_key"`
+}
+
+// List handles GET /api/v1/projects/:id/service-accounts
+func (h *ServiceAccountsHandler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	projectID, ok := authorizeProjectParam(w, r, h.permissionsSrv, false)
+	if !ok {
+		return
+	}
+
+	accounts, err := h.serviceAccountsUseCase.List(r.Context(), projectID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to list service accounts",
+			"error", err,
+			"project_id", projectID,
+		)
+		respondError(w, http.StatusInternalServerError, "Failed to list service accounts")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, accounts)
+}
+
+// Create handles POST /api/v1/projects/:id/service-accounts
+func (h *ServiceAccountsHandler) Create(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	projectID, ok := authorizeProjectParam(w, r, h.permissionsSrv, true)
+	if !ok {
+		return
+	}
+
+	var req serviceAccountRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	account, err := h.serviceAccountsUseCase.Create(r.Context(), projectID, domain.ServiceAccountDTO{
+		Name:    req.Name,
+		RoleKey: req.RoleKey,
+	})
+	if err != nil {
+		respondServiceAccountError(w, r, err, "Failed to create service account", 0)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, account)
+}
+
+// Delete handles DELETE /api/v1/projects/:id/service-accounts/:said
+func (h *ServiceAccountsHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	projectID, ok := authorizeProjectParam(w, r, h.permissionsSrv, true)
+	if !ok {
+		return
+	}
+
+	id, ok := parseServiceAccountID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.serviceAccountsUseCase.Delete(r.Context(), projectID, id); err != nil {
+		respondServiceAccountError(w, r, err, "Failed to delete service account", id)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "service account deleted successfully"})
+}
+
+// ListTokens handles GET /api/v1/projects/:id/service-accounts/:said/tokens
+func (h *ServiceAccountsHandler) ListTokens(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	projectID, ok := authorizeProjectParam(w, r, h.permissionsSrv, true)
+	if !ok {
+		return
+	}
+
+	id, ok := parseServiceAccountID(w, r)
+	if !ok {
+		return
+	}
+
+	tokens, err := h.serviceAccountsUseCase.ListTokens(r.Context(), projectID, id)
+	if err != nil {
+		respondServiceAccountError(w, r, err, "Failed to list service account tokens", id)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, tokens)
+}
+
+// CreateToken handles POST /api/v1/projects/:id/service-accounts/:said/tokens.
+// The token value is only returned in this response.
+func (h *ServiceAccountsHandler) CreateToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	if appcontext.APITokenID(r.Context()) != 0 {
+		respondError(w, http.StatusForbidden, "API tokens cannot be created with an API token")
+		return
+	}
+
+	projectID, ok := authorizeProjectParam(w, r, h.permissionsSrv, true)
+	if !ok {
+		return
+	}
+
+	id, ok := parseServiceAccountID(w, r)
+	if !ok {
+		return
+	}
+
+	var req apiTokenRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	token, plain, err := h.serviceAccountsUseCase.CreateToken(r.Context(), projectID, id, domain.APITokenDTO{
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+	})
+	if err != nil {
+		respondServiceAccountError(w, r, err, "Failed to create service account token", id)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, apiTokenCreatedResponse{APIToken: token, Token: plain})
+}
+
+// DeleteToken handles DELETE /api/v1/projects/:id/service-accounts/:said/tokens/:tid
+func (h *ServiceAccountsHandler) DeleteToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	projectID, ok := authorizeProjectParam(w, r, h.permissionsSrv, true)
+	if !ok {
+		return
+	}
+
+	id, ok := parseServiceAccountID(w, r)
+	if !ok {
+		return
+	}
+
+	tokenID, err := strconv.Atoi(appcontext.Param(r.Context(), "tid"))
+	if err != nil || tokenID <= 0 {
+		respondError(w, http.StatusBadRequest, "invalid token id")
+		return
+	}
+
+	if err := h.serviceAccountsUseCase.DeleteToken(r.Context(), projectID, id, domain.APITokenID(tokenID)); err != nil {
+		respondServiceAccountError(w, r, err, "Failed to delete service account token", id)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "api token deleted successfully"})
+}
+
+func parseServiceAccountID(w http.ResponseWriter, r *http.Request) (domain.UserID, bool) {
+	id, err := strconv.Atoi(appcontext.Param(r.Context(), "said"))
+	if err != nil || id <= 0 {
+		respondError(w, http.StatusBadRequest, "invalid service account id")
+		return 0, false
+	}
+
+	return domain.UserID(id), true
+}
+
+func respondServiceAccountError(w http.ResponseWriter, r *http.Request, err error, msg string, id domain.UserID) {
+	switch {
+	case errors.Is(err, serviceaccountsusecase.ErrInvalidServiceAccount),
+		errors.Is(err, apitokensusecase.ErrInvalidAPIToken):
+		respondError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrEntityNotFound):
+		respondError(w, http.StatusNotFound, "Service account not found")
+	case errors.Is(err, domain.ErrEntityAlreadyExists):
+		respondError(w, http.StatusConflict, "Service account or token with this name already exists")
+	default:
+		slog.ErrorContext(r.Context(), msg,
+			"error", err,
+			"service_account_id", id,
+		)
+		respondError(w, http.StatusInternalServerError, msg)
+	}
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+// ListSessions handles GET /api/v1/users/me/sessions.
+// Superusers may pass ?user_id= to list sessions of another user.
+func (h *UsersHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	userID, ok := targetUser(w, r, r.URL.Query().Get("user_id"))
+	if !ok {
+		return
+	}
+
+	sessions, err := h.usersService.ListSessions(r.Context(), userID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to list sessions",
+			"error", err,
+			"user_id", userID,
+		)
+		respondError(w, http.StatusInternalServerError, "Failed to list sessions")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, sessions)
+}
+
+// RevokeSession handles DELETE /api/v1/users/:id/sessions/:sid, where :id is "me" or a user ID (superusers only).
+func (h *UsersHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	userID, ok := targetUser(w, r, appcontext.Param(r.Context(), "id"))
+	if !ok {
+		return
+	}
+
+	sessionID := domain.SessionID(appcontext.Param(r.Context(), "sid"))
+	if sessionID == "" {
+		respondError(w, http.StatusBadRequest, "session id is required")
+		return
+	}
+
+	if err := h.usersService.RevokeSession(r.Context(), userID, sessionID); err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			respondError(w, http.StatusNotFound, "session not found")
+			return
+		}
+
+		slog.ErrorContext(r.Context(), "Failed to revoke session",
+			"error", err,
+			"user_id", userID,
+			"session_id", sessionID,
+		)
+		respondError(w, http.StatusInternalServerError, "Failed to revoke session")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "session revoked successfully"})
+}
+
+// RevokeAllSessions handles DELETE /api/v1/users/:id/sessions, where :id is "me" or a user ID (superusers only).
+func (h *UsersHandler) RevokeAllSessions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	userID, ok := targetUser(w, r, appcontext.Param(r.Context(), "id"))
+	if !ok {
+		return
+	}
+
+	if err := h.usersService.RevokeAllSessions(r.Context(), userID); err != nil {
+		slog.ErrorContext(r.Context(), "Failed to revoke sessions",
+			"error", err,
+			"user_id", userID,
+		)
+		respondError(w, http.StatusInternalServerError, "Failed to revoke sessions")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "sessions revoked successfully"})
+}
+
+// targetUser resolves whose sessions or tokens are managed: the current user for "" or "me",
+// any other user for superusers only.
+func targetUser(w http.ResponseWriter, r *http.Request, value string) (domain.UserID, bool) {
+	userID, ok := userIDParam(w, r, value)
+	if !ok {
+		return 0, false
+	}
+
+	if userID != appcontext.UserID(r.Context()) && !appcontext.IsSuper(r.Context()) {
+		respondError(w, http.StatusForbidden, "Only superusers can manage other users")
+		return 0, false
+	}
+
+	return userID, true
+}
+
+// userIDParam parses a user path param; "me" (or an empty value) is the current user.
+func userIDParam(w http.ResponseWriter, r *http.Request, value string) (domain.UserID, bool) {
+	if value == "" || value == "me" {
+		return appcontext.UserID(r.Context()), true
+	}
+
+	id, err := strconv.Atoi(value)
+	if err != nil || id <= 0 {
+		respondError(w, http.StatusBadRequest, "invalid user id")
+		return 0, false
+	}
+
+	return domain.UserID(id), true
+}
@@ -0,0 +1,269 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	settingsusecase "github.com/rom8726/floxy-manager/internal/usecases/settings"
+)
+
+// SettingsHandler serves the runtime-tunable configuration of the installation.
+// Saved settings take precedence over the environment and apply without a restart.
+type SettingsHandler struct {
+	settingsUseCase  contract.SettingsUseCase
+	samlReconfigurer contract.SAMLReconfigurer
+	usersService     contract.UsersUseCase
+	emailer          contract.Emailer
+}
+
+func NewSettingsHandler(
+	settingsUseCase contract.SettingsUseCase,
+	samlReconfigurer contract.SAMLReconfigurer,
+	usersService contract.UsersUseCase,
+	emailer contract.Emailer,
+) *SettingsHandler {
+	return &SettingsHandler{
+		settingsUseCase:  settingsUseCase,
+		samlReconfigurer: samlReconfigurer,
+		usersService:     usersService,
+		emailer:          emailer,
+	}
+}
+
+// smtpConfigResponse never contains the secrets, only whether they are set.
+type smtpConfigResponse struct {
+	domain.SMTPConfig
+	PasswordSet           bool `json:"password_set"`
+	OAuth2ClientSecretSet bool `json:"oauth2_client_secret_set"`
+	OAuth2RefreshTokenSet bool `json:"oauth2_refresh_token_set"`
+}
+
+// GetSMTPConfig handles GET /api/v1/settings/smtp
+func (h *SettingsHandler) GetSMTPConfig(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r, http.MethodGet) {
+		return
+	}
+
+	config, err := h.settingsUseCase.GetSMTPConfig(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to get SMTP settings", "error", err)
+		respondError(w, http.StatusInternalServerError, "Failed to get SMTP settings")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, newSMTPConfigResponse(config))
+}
+
+// UpdateSMTPConfig handles PUT /api/v1/settings/smtp
+// Empty secrets (password, OAuth2 client secret and refresh token) keep the current ones.
+func (h *SettingsHandler) UpdateSMTPConfig(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r, http.MethodPut) {
+		return
+	}
+
+	var config domain.SMTPConfig
+	if !decodeRequest(w, r, &config) {
+		return
+	}
+
+	if err := h.settingsUseCase.UpdateSMTPConfig(r.Context(), config); err != nil {
+		respondSettingsError(w, r, err, "Failed to update SMTP settings")
+		return
+	}
+
+	saved, err := h.settingsUseCase.GetSMTPConfig(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to get SMTP settings", "error", err)
+		respondError(w, http.StatusInternalServerError, "Failed to get SMTP settings")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, newSMTPConfigResponse(saved))
+}
+
+// TestSMTPConfig handles POST /api/v1/settings/smtp/test
+// A test email is sent to the caller with the saved mail server configuration.
+func (h *SettingsHandler) TestSMTPConfig(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r, http.MethodPost) {
+		return
+	}
+
+	user, err := h.usersService.GetByID(r.Context(), appcontext.UserID(r.Context()))
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to get current user", "error", err)
+		respondError(w, http.StatusInternalServerError, "Failed to get current user")
+		return
+	}
+
+	if user.Email == "" {
+		respondError(w, http.StatusBadRequest, "Current user has no email address")
+		return
+	}
+
+	if err := h.emailer.SendTestEmail(r.Context(), user.Email); err != nil {
+		respondJSON(w, http.StatusOK, map[string]interface{}{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"message": "Test email sent to " + user.Email,
+	})
+}
+
+// GetSAMLSettings handles GET /api/v1/settings/saml
+func (h *SettingsHandler) GetSAMLSettings(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r, http.MethodGet) {
+		return
+	}
+
+	settings, err := h.settingsUseCase.GetSAMLSettings(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to get SAML settings", "error", err)
+		respondError(w, http.StatusInternalServerError, "Failed to get SAML settings")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, settings)
+}
+
+// UpdateSAMLSettings handles PUT /api/v1/settings/saml
+// The default SAML provider is rebuilt with the new settings.
+func (h *SettingsHandler) UpdateSAMLSettings(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r, http.MethodPut) {
+		return
+	}
+
+	var settings domain.SAMLSettings
+	if !decodeRequest(w, r, &settings) {
+		return
+	}
+
+	if err := h.settingsUseCase.UpdateSAMLSettings(r.Context(), settings); err != nil {
+		respondSettingsError(w, r, err, "Failed to update SAML settings")
+		return
+	}
+
+	h.respondSAMLReloaded(w, r, settings)
+}
+
+// GetGeneralSettings handles GET /api/v1/settings/general
+func (h *SettingsHandler) GetGeneralSettings(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r, http.MethodGet) {
+		return
+	}
+
+	settings, err := h.settingsUseCase.GetGeneralSettings(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to get general settings", "error", err)
+		respondError(w, http.StatusInternalServerError, "Failed to get general settings")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, settings)
+}
+
+// UpdateGeneralSettings handles PUT /api/v1/settings/general
+// The default SAML provider is rebuilt as its URLs depend on the frontend URL.
+func (h *SettingsHandler) UpdateGeneralSettings(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r, http.MethodPut) {
+		return
+	}
+
+	var settings domain.GeneralSettings
+	if !decodeRequest(w, r, &settings) {
+		return
+	}
+
+	if err := h.settingsUseCase.UpdateGeneralSettings(r.Context(), settings); err != nil {
+		respondSettingsError(w, r, err, "Failed to update general settings")
+		return
+	}
+
+	saved, err := h.settingsUseCase.GetGeneralSettings(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to get general settings", "error", err)
+		respondError(w, http.StatusInternalServerError, "Failed to get general settings")
+		return
+	}
+
+	h.respondSAMLReloaded(w, r, saved)
+}
+
+func (h *SettingsHandler) authorize(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method != method {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return false
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return false
+	}
+
+	if !appcontext.IsSuper(r.Context()) {
+		respondError(w, http.StatusForbidden, "Only superusers can manage settings")
+		return false
+	}
+
+	return true
+}
+
+// respondSAMLReloaded rebuilds the default SAML provider and responds with the saved settings.
+// The settings stay saved when the provider cannot be rebuilt, the response carries a warning then.
+func (h *SettingsHandler) respondSAMLReloaded(w http.ResponseWriter, r *http.Request, settings any) {
+	response := map[string]any{"settings": settings}
+
+	if err := h.reloadSAML(r.Context()); err != nil {
+		slog.WarnContext(r.Context(), "Failed to reload SAML provider", "error", err)
+		response["warning"] = "SAML provider was not reloaded: " + err.Error()
+	}
+
+	respondJSON(w, http.StatusOK, response)
+}
+
+func (h *SettingsHandler) reloadSAML(ctx context.Context) error {
+	samlSettings, err := h.settingsUseCase.GetSAMLSettings(ctx)
+	if err != nil {
+		return err
+	}
+
+	general, err := h.settingsUseCase.GetGeneralSettings(ctx)
+	if err != nil {
+		return err
+	}
+
+	return h.samlReconfigurer.Reconfigure(samlSettings, general.FrontendURL)
+}
+
+func newSMTPConfigResponse(config domain.SMTPConfig) smtpConfigResponse {
+	response := smtpConfigResponse{
+		PasswordSet:           config.Password != "",
+		OAuth2ClientSecretSet: config.OAuth2ClientSecret != "",
+		OAuth2RefreshTokenSet: config.OAuth2RefreshToken != "",
+	}
+
+	config.Password = ""
+	config.OAuth2ClientSecret = ""
+	config.OAuth2RefreshToken = ""
+	response.SMTPConfig = config
+
+	return response
+}
+
+func respondSettingsError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	if errors.Is(err, settingsusecase.ErrInvalidSettings) {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	slog.ErrorContext(r.Context(), message, "error", err)
+	respondError(w, http.StatusInternalServerError, message)
+}
@@ -1,7 +1,7 @@
 package handlers
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -15,19 +15,25 @@ import (
 
 type SSOHandler struct {
 	usersService contract.UsersUseCase
+	settings     contract.RuntimeSettingsReader
 	frontendURL  string
 }
 
-func NewSSOHandler(usersService contract.UsersUseCase, frontendURL string) *SSOHandler {
+func NewSSOHandler(
+	usersService contract.UsersUseCase,
+	settings contract.RuntimeSettingsReader,
+	frontendURL string,
+) *SSOHandler {
 	return &SSOHandler{
 		usersService: usersService,
+		settings:     settings,
 		frontendURL:  frontendURL,
 	}
 }
 
 func (h *SSOHandler) GetProviders(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -54,21 +60,15 @@ func (h *SSOHandler) GetProviders(w http.ResponseWriter, r *http.Request) {
 
 func (h *SSOHandler) Initiate(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	var req struct {
-		ProviderName string `json:"provider_name"`
+		ProviderName string `json:"provider_name" validate:"required"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request", http.StatusBadRequest)
-		return
-	}
-
-	if req.ProviderName == "" {
-		respondError(w, http.StatusBadRequest, "provider_name is required")
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
@@ -85,7 +85,7 @@ func (h *SSOHandler) Initiate(w http.ResponseWriter, r *http.Request) {
 
 func (h *SSOHandler) Callback(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -127,14 +127,16 @@ func (h *SSOHandler) Callback(w http.ResponseWriter, r *http.Request) {
 
 func (h *SSOHandler) GetMetadata(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
-	providerName := r.URL.Query().Get("provider")
+	providerName := appcontext.Param(r.Context(), "provider")
 	if providerName == "" {
-		respondError(w, http.StatusBadRequest, "provider parameter is required")
-		return
+		providerName = r.URL.Query().Get("provider")
+	}
+	if providerName == "" {
+		providerName = domain.SSOProviderNameADSaml
 	}
 
 	metadata, err := h.usersService.GetSSOMetadata(r.Context(), providerName)
@@ -150,9 +152,10 @@ func (h *SSOHandler) GetMetadata(w http.ResponseWriter, r *http.Request) {
 
 // ACS handles SAML Assertion Consumer Service (ACS) endpoint.
 // This endpoint receives POST requests from SAML Identity Providers with SAMLResponse and RelayState.
+// The provider is taken from the ":provider" URL param; the legacy route serves the AD SAML provider.
 func (h *SSOHandler) ACS(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -162,10 +165,16 @@ func (h *SSOHandler) ACS(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	providerName := appcontext.Param(r.Context(), "provider")
+	if providerName == "" {
+		providerName = domain.SSOProviderNameADSaml
+	}
+
 	samlResponse := r.FormValue("SAMLResponse")
 	relayState := r.FormValue("RelayState")
 
-	slog.Debug("SAML ACS endpoint called",
+	slog.DebugContext(r.Context(), "SAML ACS endpoint called",
+		"provider", providerName,
 		"saml_response_length", len(samlResponse),
 		"relay_state", relayState,
 		"method", r.Method,
@@ -201,12 +210,11 @@ func (h *SSOHandler) ACS(w http.ResponseWriter, r *http.Request) {
 	// Put the request in context for SAML processing
 	ctx = appcontext.WithRawRequest(ctx, rawReq)
 
-	// Call SSOCallback with AD SAML provider
 	accessToken, refreshToken, _, err := h.usersService.SSOCallback(
-		ctx, domain.SSOProviderNameADSaml, appcontext.RawRequest(ctx), samlResponse, relayState,
+		ctx, providerName, appcontext.RawRequest(ctx), samlResponse, relayState,
 	)
 	if err != nil {
-		slog.Error("SSO assert failed", "error", err)
+		slog.ErrorContext(r.Context(), "SSO assert failed", "provider", providerName, "error", err)
 
 		// Redirect to the frontend error page instead of returning JSON error
 		errorMsg := "SSO authentication failed"
@@ -222,31 +230,41 @@ func (h *SSOHandler) ACS(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 
-		errorURL := h.buildFrontErrorLocation(errorMsg, errorDetails)
+		errorURL := h.buildFrontErrorLocation(ctx, errorMsg, errorDetails)
 		http.Redirect(w, r, errorURL, http.StatusFound)
 
 		return
 	}
 
 	// Build redirect URL to the frontend
-	redirectURL := h.buildFrontLoginSuccessLocation(accessToken, refreshToken)
+	redirectURL := h.buildFrontLoginSuccessLocation(ctx, accessToken, refreshToken)
 	http.Redirect(w, r, redirectURL, http.StatusFound)
 }
 
 // buildFrontLoginSuccessLocation builds the frontend success URL with tokens.
-func (h *SSOHandler) buildFrontLoginSuccessLocation(accessToken, refreshToken string) string {
+func (h *SSOHandler) buildFrontLoginSuccessLocation(ctx context.Context, accessToken, refreshToken string) string {
 	values := url.Values{}
 	values.Set("access_token", accessToken)
 	values.Set("refresh_token", refreshToken)
-	return h.frontendURL + "/auth/saml/success?" + values.Encode()
+	return h.currentFrontendURL(ctx) + "/auth/saml/success?" + values.Encode()
 }
 
 // buildFrontErrorLocation builds the frontend error URL with error information.
-func (h *SSOHandler) buildFrontErrorLocation(errorMsg, errorDetails string) string {
+func (h *SSOHandler) buildFrontErrorLocation(ctx context.Context, errorMsg, errorDetails string) string {
 	values := url.Values{}
 	values.Set("error", errorMsg)
 	if errorDetails != "" {
 		values.Set("details", errorDetails)
 	}
-	return h.frontendURL + "/auth/saml/error?" + values.Encode()
+	return h.currentFrontendURL(ctx) + "/auth/saml/error?" + values.Encode()
+}
+
+// currentFrontendURL returns the frontend URL of the general settings, the configured one if they cannot be read.
+func (h *SSOHandler) currentFrontendURL(ctx context.Context) string {
+	settings, err := h.settings.GetGeneralSettings(ctx)
+	if err != nil || settings.FrontendURL == "" {
+		return h.frontendURL
+	}
+
+	return settings.FrontendURL
 }
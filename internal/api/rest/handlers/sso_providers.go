@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	ssoprovidersusecase "github.com/rom8726/floxy-manager/internal/usecases/ssoproviders"
+)
+
+// SSOProvidersHandler manages the SAML providers configured at runtime.
+// Changes are applied to the SSO provider manager without a restart.
+type SSOProvidersHandler struct {
+	ssoProvidersUseCase contract.SSOProvidersUseCase
+}
+
+func NewSSOProvidersHandler(ssoProvidersUseCase contract.SSOProvidersUseCase) *SSOProvidersHandler {
+	return &SSOProvidersHandler{
+		ssoProvidersUseCase: ssoProvidersUseCase,
+	}
+}
+
+// ListProviders handles GET /api/v1/settings/sso
+func (h *SSOProvidersHandler) ListProviders(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r, http.MethodGet) {
+		return
+	}
+
+	providers, err := h.ssoProvidersUseCase.List(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to list SSO providers", "error", err)
+		respondError(w, http.StatusInternalServerError, "Failed to list SSO providers")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, providers)
+}
+
+// GetProvider handles GET /api/v1/settings/sso/:name
+func (h *SSOProvidersHandler) GetProvider(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r, http.MethodGet) {
+		return
+	}
+
+	provider, err := h.ssoProvidersUseCase.Get(r.Context(), appcontext.Param(r.Context(), "name"))
+	if err != nil {
+		respondSSOProviderError(w, r, err, "Failed to get SSO provider")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, provider)
+}
+
+// CreateProvider handles POST /api/v1/settings/sso
+// An enabled provider is only created when its Identity Provider metadata can be loaded.
+func (h *SSOProvidersHandler) CreateProvider(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r, http.MethodPost) {
+		return
+	}
+
+	var provider domain.SSOProviderSettings
+	if !decodeRequest(w, r, &provider) {
+		return
+	}
+
+	if err := h.ssoProvidersUseCase.Create(r.Context(), provider); err != nil {
+		respondSSOProviderError(w, r, err, "Failed to create SSO provider")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, provider)
+}
+
+// UpdateProvider handles PUT /api/v1/settings/sso/:name
+func (h *SSOProvidersHandler) UpdateProvider(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r, http.MethodPut) {
+		return
+	}
+
+	var provider domain.SSOProviderSettings
+	if !decodeRequest(w, r, &provider) {
+		return
+	}
+
+	provider.Name = appcontext.Param(r.Context(), "name")
+
+	if err := h.ssoProvidersUseCase.Update(r.Context(), provider); err != nil {
+		respondSSOProviderError(w, r, err, "Failed to update SSO provider")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, provider)
+}
+
+// EnableProvider handles POST /api/v1/settings/sso/:name/enable
+func (h *SSOProvidersHandler) EnableProvider(w http.ResponseWriter, r *http.Request) {
+	h.setEnabled(w, r, true)
+}
+
+// DisableProvider handles POST /api/v1/settings/sso/:name/disable
+func (h *SSOProvidersHandler) DisableProvider(w http.ResponseWriter, r *http.Request) {
+	h.setEnabled(w, r, false)
+}
+
+// DeleteProvider handles DELETE /api/v1/settings/sso/:name
+func (h *SSOProvidersHandler) DeleteProvider(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r, http.MethodDelete) {
+		return
+	}
+
+	if err := h.ssoProvidersUseCase.Delete(r.Context(), appcontext.Param(r.Context(), "name")); err != nil {
+		respondSSOProviderError(w, r, err, "Failed to delete SSO provider")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// TestMetadata handles POST /api/v1/settings/sso-test
+// The Identity Provider metadata of the settings is fetched, nothing is saved.
+func (h *SSOProvidersHandler) TestMetadata(w http.ResponseWriter, r *http.Request) {
+	if !h.authorize(w, r, http.MethodPost) {
+		return
+	}
+
+	var settings domain.SAMLSettings
+	if !decodeRequest(w, r, &settings) {
+		return
+	}
+
+	entityID, err := h.ssoProvidersUseCase.TestMetadata(r.Context(), settings)
+	if err != nil {
+		respondJSON(w, http.StatusOK, map[string]interface{}{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"success":       true,
+		"message":       "Metadata fetched successfully",
+		"idp_entity_id": entityID,
+	})
+}
+
+func (h *SSOProvidersHandler) setEnabled(w http.ResponseWriter, r *http.Request, enabled bool) {
+	if !h.authorize(w, r, http.MethodPost) {
+		return
+	}
+
+	provider, err := h.ssoProvidersUseCase.SetEnabled(r.Context(), appcontext.Param(r.Context(), "name"), enabled)
+	if err != nil {
+		respondSSOProviderError(w, r, err, "Failed to update SSO provider")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, provider)
+}
+
+func (h *SSOProvidersHandler) authorize(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method != method {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return false
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return false
+	}
+
+	if !appcontext.IsSuper(r.Context()) {
+		respondError(w, http.StatusForbidden, "Only superusers can manage SSO providers")
+		return false
+	}
+
+	return true
+}
+
+func respondSSOProviderError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrEntityNotFound):
+		respondError(w, http.StatusNotFound, "SSO provider not found")
+	case errors.Is(err, ssoprovidersusecase.ErrProviderExists):
+		respondError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, ssoprovidersusecase.ErrInvalidProvider),
+		errors.Is(err, ssoprovidersusecase.ErrProviderNotLoaded):
+		respondError(w, http.StatusBadRequest, err.Error())
+	default:
+		slog.ErrorContext(r.Context(), message, "error", err)
+		respondError(w, http.StatusInternalServerError, message)
+	}
+}
@@ -0,0 +1,303 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+const (
+	defaultStepLogReadSize = 1 << 20
+	maxStepLogReadSize     = 16 << 20
+	maxStepLogAppendSize   = 1 << 20
+
+	// stepLogPollInterval and stepLogFollowTimeout bound the follow mode of log reads.
+	stepLogPollInterval  = 2 * time.Second
+	stepLogFollowTimeout = 5 * time.Minute
+)
+
+// activeStepStatuses are the statuses of steps whose log may still grow.
+var activeStepStatuses = map[string]bool{
+	"pending":          true,
+	"running":          true,
+	"compensation":     true,
+	"waiting_decision": true,
+}
+
+type StepLogsHandler struct {
+	stepLogsUseCase contract.StepLogsUseCase
+	permissionsSrv  contract.PermissionsService
+}
+
+func NewStepLogsHandler(
+	stepLogsUseCase contract.StepLogsUseCase,
+	permissionsSrv contract.PermissionsService,
+) *StepLogsHandler {
+	return &StepLogsHandler{
+		stepLogsUseCase: stepLogsUseCase,
+		permissionsSrv:  permissionsSrv,
+	}
+}
+
+// Get handles GET /api/v1/instances/:id/steps/:sid/logs
+// The log is returned as text/plain starting at the offset query parameter, at most max_bytes bytes.
+// With follow=true the response is streamed while the step is active.
+func (h *StepLogsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !requireAuthForWorkflows(w, r) {
+		return
+	}
+
+	instanceID, stepID, ok := parseStepLogIDs(w, r)
+	if !ok {
+		return
+	}
+
+	tenantID, projectID, ok := h.authorize(w, r, false)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+
+	offset, err := parseInt64Param(query.Get("offset"), 0)
+	if err != nil || offset < 0 {
+		respondError(w, http.StatusBadRequest, "Invalid offset")
+		return
+	}
+
+	limit, err := parseInt64Param(query.Get("max_bytes"), defaultStepLogReadSize)
+	if err != nil || limit <= 0 {
+		respondError(w, http.StatusBadRequest, "Invalid max_bytes")
+		return
+	}
+	limit = min(limit, maxStepLogReadSize)
+
+	follow := query.Get("follow") == "true"
+
+	// The first read is buffered so that errors are still reported with a proper status
+	var buf []byte
+	info, err := h.stepLogsUseCase.Read(r.Context(), tenantID, projectID, instanceID, stepID, offset, limit,
+		func(chunk []byte) error {
+			buf = append(buf, chunk...)
+			return nil
+		})
+	if err != nil {
+		respondStepLogError(w, r, err, "Failed to read step log", stepID)
+		return
+	}
+
+	offset += int64(len(buf))
+	limit -= int64(len(buf))
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Log-Size", strconv.FormatInt(info.Size, 10))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	if !follow {
+		w.Header().Set("X-Log-Truncated", strconv.FormatBool(offset < info.Size))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(buf)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(buf)
+
+	h.follow(w, r, tenantID, projectID, instanceID, stepID, info, offset, limit)
+}
+
+// follow polls the log and writes new content until the step becomes inactive,
+// the read limit or the follow timeout is reached or the client goes away.
+func (h *StepLogsHandler) follow(
+	w http.ResponseWriter,
+	r *http.Request,
+	tenantID domain.TenantID,
+	projectID domain.ProjectID,
+	instanceID, stepID int,
+	info domain.StepLogInfo,
+	offset, limit int64,
+) {
+	ctrl := http.NewResponseController(w)
+	deadline := time.Now().Add(stepLogFollowTimeout)
+	// The server write timeout is shorter than the follow timeout
+	_ = ctrl.SetWriteDeadline(deadline.Add(stepLogPollInterval))
+
+	ticker := time.NewTicker(stepLogPollInterval)
+	defer ticker.Stop()
+
+	for activeStepStatuses[info.StepStatus] && limit > 0 && time.Now().Before(deadline) {
+		if err := ctrl.Flush(); err != nil {
+			return
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+		}
+
+		var (
+			written int64
+			err     error
+		)
+		info, err = h.stepLogsUseCase.Read(r.Context(), tenantID, projectID, instanceID, stepID, offset, limit,
+			func(chunk []byte) error {
+				n, err := w.Write(chunk)
+				written += int64(n)
+
+				return err
+			})
+		if err != nil {
+			if r.Context().Err() == nil {
+				slog.ErrorContext(r.Context(), "Failed to follow step log",
+					"error", err,
+					"step_id", stepID,
+				)
+			}
+
+			return
+		}
+
+		offset += written
+		limit -= written
+	}
+
+	_ = ctrl.Flush()
+}
+
+// Append handles POST /api/v1/instances/:id/steps/:sid/logs
+// Workers attach output to a step by posting it as the request body, which is appended to the step log.
+func (h *StepLogsHandler) Append(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !requireAuthForWorkflows(w, r) {
+		return
+	}
+
+	instanceID, stepID, ok := parseStepLogIDs(w, r)
+	if !ok {
+		return
+	}
+
+	tenantID, projectID, ok := h.authorize(w, r, true)
+	if !ok {
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxStepLogAppendSize+1))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Failed to read request body")
+		return
+	}
+	if len(body) > maxStepLogAppendSize {
+		respondError(w, http.StatusRequestEntityTooLarge, "Payload too large")
+		return
+	}
+
+	size, err := h.stepLogsUseCase.Append(r.Context(), tenantID, projectID, instanceID, stepID, body)
+	if err != nil {
+		respondStepLogError(w, r, err, "Failed to append step log", stepID)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"step_id": stepID,
+		"size":    size,
+	})
+}
+
+// authorize parses tenant and project and checks manage permission on the project, or for reads
+// view permission on the project and its workflow data since step logs carry payloads.
+func (h *StepLogsHandler) authorize(
+	w http.ResponseWriter,
+	r *http.Request,
+	manage bool,
+) (domain.TenantID, domain.ProjectID, bool) {
+	tenantID, projectID, err := parseTenantAndProject(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return 0, 0, false
+	}
+
+	if manage {
+		err = h.permissionsSrv.CanManageProject(r.Context(), projectID)
+	} else {
+		err = h.permissionsSrv.CanViewProject(r.Context(), projectID)
+	}
+	if err != nil {
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			respondError(w, http.StatusForbidden, "Access denied to this project")
+			return 0, 0, false
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to verify permissions")
+		return 0, 0, false
+	}
+
+	if !manage {
+		visible, ok := canViewWorkflowData(w, r, h.permissionsSrv, projectID)
+		if !ok {
+			return 0, 0, false
+		}
+
+		if !visible {
+			respondError(w, http.StatusForbidden, "Access denied to workflow data of this project")
+			return 0, 0, false
+		}
+	}
+
+	return tenantID, projectID, true
+}
+
+func parseStepLogIDs(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	instanceID, err := strconv.Atoi(appcontext.Param(r.Context(), "id"))
+	if err != nil || instanceID <= 0 {
+		respondError(w, http.StatusBadRequest, "Invalid instance ID")
+		return 0, 0, false
+	}
+
+	stepID, err := strconv.Atoi(appcontext.Param(r.Context(), "sid"))
+	if err != nil || stepID <= 0 {
+		respondError(w, http.StatusBadRequest, "Invalid step ID")
+		return 0, 0, false
+	}
+
+	return instanceID, stepID, true
+}
+
+func parseInt64Param(value string, def int64) (int64, error) {
+	if value == "" {
+		return def, nil
+	}
+
+	return strconv.ParseInt(value, 10, 64)
+}
+
+func respondStepLogError(w http.ResponseWriter, r *http.Request, err error, msg string, stepID int) {
+	switch {
+	case errors.Is(err, domain.ErrEntityNotFound):
+		respondError(w, http.StatusNotFound, "Step not found")
+	case errors.Is(err, domain.ErrStepLogTooLarge):
+		respondError(w, http.StatusRequestEntityTooLarge, "Step log size limit exceeded")
+	default:
+		slog.ErrorContext(r.Context(), msg,
+			"error", err,
+			"step_id", stepID,
+		)
+		respondError(w, http.StatusInternalServerError, msg)
+	}
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type fakeStepLogs struct {
+	contract.StepLogsUseCase
+	reads int
+}
+
+func (f *fakeStepLogs) Read(
+	_ context.Context,
+	_ domain.TenantID,
+	_ domain.ProjectID,
+	_, _ int,
+	_, _ int64,
+	fn func([]byte) error,
+) (domain.StepLogInfo, error) {
+	f.reads++
+
+	return domain.StepLogInfo{StepStatus: "completed", Size: 5}, fn([]byte("hello"))
+}
+
+func TestStepLogsHandler_Get_RequiresDataView(t *testing.T) {
+	params := httprouter.Params{{Key: "id", Value: "3"}, {Key: "sid", Value: "4"}}
+
+	for _, target := range []string{
+		"/api/v1/instances/3/steps/4/logs?tenant_id=1&project_id=2",
+		"/api/v1/instances/3/steps/4/logs?tenant_id=1&project_id=2&follow=true",
+	} {
+		t.Run(target, func(t *testing.T) {
+			logs := &fakeStepLogs{}
+			rec := httptest.NewRecorder()
+			NewStepLogsHandler(logs, projectViewerPermissions{}).Get(rec, viewerRequest(target, params))
+			assert.Equal(t, http.StatusForbidden, rec.Code)
+			assert.Zero(t, logs.reads)
+
+			rec = httptest.NewRecorder()
+			NewStepLogsHandler(logs, viewerPermissions{}).Get(rec, viewerRequest(target, params))
+			require.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, "hello", rec.Body.String())
+		})
+	}
+}
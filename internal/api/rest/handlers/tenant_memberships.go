@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type tenantMembershipResponse struct {
+	ID        domain.TenantMembershipID `json:"id"`
+	TenantID  domain.TenantID           `json:"tenant_id"`
+	UserID    domain.UserID             `json:"user_id"`
+	RoleID    domain.RoleID             `json:"role_id"`
+	RoleKey   string                    `json:"role_key"`
+	RoleName  string                    `json:"role_name"`
+	CreatedAt string                    `json:"created_at"`
+}
+
+func toTenantMembershipResponse(m domain.TenantMembership) tenantMembershipResponse {
+	return tenantMembershipResponse{
+		ID:        m.ID,
+		TenantID:  m.TenantID,
+		UserID:    m.UserID,
+		RoleID:    m.RoleID,
+		RoleKey:   m.RoleKey,
+		RoleName:  m.RoleName,
+		CreatedAt: m.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+// tenantFromPath returns the tenant of the :id path param and responds with an error if it does not exist.
+func (h *TenantsHandler) tenantFromPath(w http.ResponseWriter, r *http.Request) (domain.Tenant, bool) {
+	id, err := strconv.Atoi(appcontext.Param(r.Context(), "id"))
+	if err != nil || id <= 0 {
+		respondError(w, http.StatusBadRequest, "invalid tenant id")
+		return domain.Tenant{}, false
+	}
+
+	tenant, err := h.tenantsRepo.GetByID(r.Context(), domain.TenantID(id))
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			respondError(w, http.StatusNotFound, "tenant not found")
+			return domain.Tenant{}, false
+		}
+
+		slog.ErrorContext(r.Context(), "Failed to get tenant", "error", err, "tenant_id", id)
+		respondError(w, http.StatusInternalServerError, "Failed to get tenant")
+		return domain.Tenant{}, false
+	}
+
+	return tenant, true
+}
+
+// ListMemberships handles GET /api/v1/tenants/:id/memberships
+func (h *TenantsHandler) ListMemberships(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	if !appcontext.IsSuper(r.Context()) {
+		respondError(w, http.StatusForbidden, "Only superusers can manage tenant memberships")
+		return
+	}
+
+	tenant, ok := h.tenantFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	memberships, err := h.tenantMembershipsRepo.ListForTenant(r.Context(), tenant.ID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to list tenant memberships", "error", err, "tenant_id", tenant.ID)
+		respondError(w, http.StatusInternalServerError, "Failed to list tenant memberships")
+		return
+	}
+
+	result := make([]tenantMembershipResponse, 0, len(memberships))
+	for _, m := range memberships {
+		result = append(result, toTenantMembershipResponse(m))
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// CreateMembership handles POST /api/v1/tenants/:id/memberships.
+// The user gets the tenant role in every project of the tenant.
+func (h *TenantsHandler) CreateMembership(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	if !appcontext.IsSuper(r.Context()) {
+		respondError(w, http.StatusForbidden, "Only superusers can manage tenant memberships")
+		return
+	}
+
+	tenant, ok := h.tenantFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		UserID int    `json:"user_id" validate:"required,min=1"`
+		RoleID string `json:"role_id" validate:"required"`
+	}
+
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	role, err := h.rolesRepo.GetByID(r.Context(), domain.RoleID(req.RoleID))
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			respondError(w, http.StatusBadRequest, "Role not found")
+			return
+		}
+
+		respondError(w, http.StatusInternalServerError, "Failed to verify role")
+		return
+	}
+
+	if !role.IsTenantRole() {
+		respondError(w, http.StatusBadRequest, "Only tenant roles can be granted by tenant memberships")
+		return
+	}
+
+	user, err := h.usersService.GetByID(r.Context(), domain.UserID(req.UserID))
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			respondError(w, http.StatusNotFound, "User not found")
+			return
+		}
+
+		respondError(w, http.StatusInternalServerError, "Failed to verify user")
+		return
+	}
+
+	if user.IsServiceAccount {
+		respondError(w, http.StatusBadRequest, "Service accounts can only be members of their own project")
+		return
+	}
+
+	membership, err := h.tenantMembershipsRepo.Create(r.Context(), tenant.ID, user.ID, role.ID)
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityAlreadyExists) {
+			respondError(w, http.StatusConflict, "User is already a member of this tenant")
+			return
+		}
+
+		slog.ErrorContext(r.Context(), "Failed to create tenant membership",
+			"error", err,
+			"tenant_id", tenant.ID,
+			"user_id", req.UserID,
+			"role_id", req.RoleID,
+		)
+		respondError(w, http.StatusInternalServerError, "Failed to create tenant membership")
+		return
+	}
+
+	h.permissionsService.InvalidateCache()
+
+	respondJSON(w, http.StatusCreated, toTenantMembershipResponse(membership))
+}
+
+// DeleteMembership handles DELETE /api/v1/tenants/:id/memberships/:mid
+func (h *TenantsHandler) DeleteMembership(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	if !appcontext.IsSuper(r.Context()) {
+		respondError(w, http.StatusForbidden, "Only superusers can manage tenant memberships")
+		return
+	}
+
+	tenant, ok := h.tenantFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	membershipID, err := strconv.Atoi(appcontext.Param(r.Context(), "mid"))
+	if err != nil || membershipID <= 0 {
+		respondError(w, http.StatusBadRequest, "invalid membership id")
+		return
+	}
+
+	err = h.tenantMembershipsRepo.Delete(r.Context(), tenant.ID, domain.TenantMembershipID(membershipID))
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			respondError(w, http.StatusNotFound, "tenant membership not found")
+			return
+		}
+
+		slog.ErrorContext(r.Context(), "Failed to delete tenant membership",
+			"error", err,
+			"tenant_id", tenant.ID,
+			"membership_id", membershipID,
+		)
+		respondError(w, http.StatusInternalServerError, "Failed to delete tenant membership")
+		return
+	}
+
+	h.permissionsService.InvalidateCache()
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "tenant membership deleted successfully"})
+}
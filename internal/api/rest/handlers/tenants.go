@@ -1,7 +1,7 @@
 package handlers
 
 import (
-	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 	"strconv"
@@ -12,18 +12,35 @@ import (
 )
 
 type TenantsHandler struct {
-	tenantsRepo contract.TenantsRepository
+	tenantsRepo           contract.TenantsRepository
+	tenantMembershipsRepo contract.TenantMembershipsRepository
+	rolesRepo             contract.RolesRepository
+	usersService          contract.UsersUseCase
+	permissionsService    contract.PermissionsService
+	deletionUseCase       contract.DeletionUseCase
 }
 
-func NewTenantsHandler(tenantsRepo contract.TenantsRepository) *TenantsHandler {
+func NewTenantsHandler(
+	tenantsRepo contract.TenantsRepository,
+	tenantMembershipsRepo contract.TenantMembershipsRepository,
+	rolesRepo contract.RolesRepository,
+	usersService contract.UsersUseCase,
+	permissionsService contract.PermissionsService,
+	deletionUseCase contract.DeletionUseCase,
+) *TenantsHandler {
 	return &TenantsHandler{
-		tenantsRepo: tenantsRepo,
+		tenantsRepo:           tenantsRepo,
+		tenantMembershipsRepo: tenantMembershipsRepo,
+		rolesRepo:             rolesRepo,
+		usersService:          usersService,
+		permissionsService:    permissionsService,
+		deletionUseCase:       deletionUseCase,
 	}
 }
 
 func (h *TenantsHandler) List(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -33,10 +50,10 @@ func (h *TenantsHandler) List(w http.ResponseWriter, r *http.Request) {
 
 	tenants, err := h.tenantsRepo.List(r.Context())
 	if err != nil {
-		slog.Error("Failed to list tenants",
+		slog.ErrorContext(r.Context(), "Failed to list tenants",
 			"error", err,
 		)
-		respondError(w, http.StatusInternalServerError, err.Error())
+		respondErr(w, err)
 		return
 	}
 
@@ -45,7 +62,7 @@ func (h *TenantsHandler) List(w http.ResponseWriter, r *http.Request) {
 
 func (h *TenantsHandler) Create(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -60,22 +77,16 @@ func (h *TenantsHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		Name string `json:"name"`
+		Name string `json:"name" validate:"required"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
-		return
-	}
-
-	if req.Name == "" {
-		respondError(w, http.StatusBadRequest, "name is required")
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
 	tenant, err := h.tenantsRepo.Create(r.Context(), req.Name)
 	if err != nil {
-		slog.Error("Failed to create tenant",
+		slog.ErrorContext(r.Context(), "Failed to create tenant",
 			"error", err,
 			"name", req.Name,
 		)
@@ -88,7 +99,7 @@ func (h *TenantsHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 func (h *TenantsHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodDelete {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -115,26 +126,34 @@ func (h *TenantsHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.tenantsRepo.Delete(r.Context(), domain.TenantID(id))
+	force, err := parseForce(r)
 	if err != nil {
-		if err == domain.ErrEntityNotFound {
-			respondError(w, http.StatusNotFound, "tenant not found")
-			return
+		respondError(w, http.StatusBadRequest, "invalid force flag")
+		return
+	}
+
+	impact, err := h.deletionUseCase.DeleteTenant(r.Context(), domain.TenantID(id), force)
+	if err != nil {
+		if !errors.Is(err, domain.ErrEntityNotFound) && !errors.Is(err, domain.ErrEntityInUse) {
+			slog.ErrorContext(r.Context(), "Failed to delete tenant",
+				"error", err,
+				"tenant_id", id,
+				"force", force,
+			)
 		}
-		slog.Error("Failed to delete tenant",
-			"error", err,
-			"tenant_id", id,
-		)
-		respondError(w, http.StatusInternalServerError, "Failed to delete tenant")
+		respondDeletionError(w, err, "tenant", impact)
 		return
 	}
 
-	respondJSON(w, http.StatusOK, map[string]string{"message": "tenant deleted successfully"})
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "tenant deleted successfully",
+		"impact":  impact,
+	})
 }
 
 func (h *TenantsHandler) Update(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPut && r.Method != http.MethodPatch {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -162,16 +181,10 @@ func (h *TenantsHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		Name string `json:"name"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
-		return
+		Name string `json:"name" validate:"required"`
 	}
 
-	if req.Name == "" {
-		respondError(w, http.StatusBadRequest, "name is required")
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
@@ -181,7 +194,7 @@ func (h *TenantsHandler) Update(w http.ResponseWriter, r *http.Request) {
 			respondError(w, http.StatusNotFound, "tenant not found")
 			return
 		}
-		slog.Error("Failed to update tenant",
+		slog.ErrorContext(r.Context(), "Failed to update tenant",
 			"error", err,
 			"tenant_id", id,
 			"name", req.Name,
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	userpreferencesusecase "github.com/rom8726/floxy-manager/internal/usecases/userpreferences"
+)
+
+type UserPreferencesHandler struct {
+	userPreferencesUseCase contract.UserPreferencesUseCase
+}
+
+func NewUserPreferencesHandler(userPreferencesUseCase contract.UserPreferencesUseCase) *UserPreferencesHandler {
+	return &UserPreferencesHandler{
+		userPreferencesUseCase: userPreferencesUseCase,
+	}
+}
+
+type userPreferencesRequest struct {
+	DefaultProjectID    *domain.ProjectID           `json:"default_project_id"`
+	Locale              string                      `json:"locale"`
+	ItemsPerPage        int                         `json:"items_per_page"`
+	NotificationOptOuts []domain.LifecycleEventType `json:"notification_opt_outs"`
+}
+
+// GetPreferences handles GET /api/v1/users/me/preferences
+func (h *UserPreferencesHandler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	userID := appcontext.UserID(r.Context())
+
+	preferences, err := h.userPreferencesUseCase.Get(r.Context(), userID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to get user preferences",
+			"error", err,
+			"user_id", userID,
+		)
+		respondError(w, http.StatusInternalServerError, "Failed to get user preferences")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, preferences)
+}
+
+// UpdatePreferences handles PUT /api/v1/users/me/preferences
+// The body replaces all the preferences, the ones left empty get their defaults.
+func (h *UserPreferencesHandler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	// The route is registered with :id, preferences are only managed by their own user
+	if appcontext.Param(r.Context(), "id") != "me" {
+		respondError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	var req userPreferencesRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	userID := appcontext.UserID(r.Context())
+
+	preferences, err := h.userPreferencesUseCase.Update(r.Context(), domain.UserPreferences{
+		UserID:              userID,
+		DefaultProjectID:    req.DefaultProjectID,
+		Locale:              req.Locale,
+		ItemsPerPage:        req.ItemsPerPage,
+		NotificationOptOuts: req.NotificationOptOuts,
+	})
+	if err != nil {
+		if errors.Is(err, userpreferencesusecase.ErrInvalidPreferences) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		slog.ErrorContext(r.Context(), "Failed to update user preferences",
+			"error", err,
+			"user_id", userID,
+		)
+		respondError(w, http.StatusInternalServerError, "Failed to update user preferences")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, preferences)
+}
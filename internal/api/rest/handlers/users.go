@@ -1,11 +1,11 @@
 package handlers
 
 import (
-	"encoding/json"
 	"errors"
 	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	appcontext "github.com/rom8726/floxy-manager/internal/context"
@@ -31,10 +31,25 @@ func NewUsersHandler(
 	}
 }
 
+// canManageUsers reports whether the current user is a superuser or a user manager.
+func (h *UsersHandler) canManageUsers(r *http.Request) bool {
+	if appcontext.IsSuper(r.Context()) {
+		return true
+	}
+
+	ok, err := h.permissionsService.HasGlobalPermission(r.Context(), domain.PermUserManage)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to check user.manage permission", "error", err)
+		return false
+	}
+
+	return ok
+}
+
 // GetCurrentUser returns information about the current authenticated user
 func (h *UsersHandler) GetCurrentUser(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -57,23 +72,28 @@ func (h *UsersHandler) GetCurrentUser(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, map[string]interface{}{
 		"id":                  user.ID,
 		"username":            user.Username,
+		"display_name":        user.DisplayName,
 		"email":               user.Email,
 		"is_superuser":        user.IsSuperuser,
 		"is_active":           user.IsActive,
 		"is_external":         user.IsExternal,
 		"two_fa_enabled":      user.TwoFAEnabled,
 		"two_fa_confirmed_at": user.TwoFAConfirmedAt,
+		"email_verified":      user.EmailVerified(),
 		"created_at":          user.CreatedAt,
 		"updated_at":          user.UpdatedAt,
 		"last_login":          user.LastLogin,
 		"license_accepted":    user.LicenseAccepted,
+		"license_version":     user.LicenseVersion,
+		"license_accepted_at": user.LicenseAcceptedAt,
+		"impersonator":        appcontext.Impersonator(r.Context()),
 	})
 }
 
 // GetMyProjects returns projects and permissions for the current user
 func (h *UsersHandler) GetMyProjects(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -114,6 +134,7 @@ func (h *UsersHandler) GetMyProjects(w http.ResponseWriter, r *http.Request) {
 			domain.PermProjectManage,
 			domain.PermProjectCreate,
 			domain.PermWorkflowCreate,
+			domain.PermWorkflowDataView,
 			domain.PermAuditView,
 			domain.PermMembershipManage,
 		}
@@ -232,7 +253,7 @@ func (h *UsersHandler) GetMyProjects(w http.ResponseWriter, r *http.Request) {
 // UpdatePassword allows authenticated user to change their password
 func (h *UsersHandler) UpdatePassword(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -247,22 +268,11 @@ func (h *UsersHandler) UpdatePassword(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		OldPassword string `json:"old_password"`
-		NewPassword string `json:"new_password"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request", http.StatusBadRequest)
-		return
+		OldPassword string `json:"old_password" validate:"required"`
+		NewPassword string `json:"new_password" validate:"required,min=6"`
 	}
 
-	if req.OldPassword == "" || req.NewPassword == "" {
-		respondError(w, http.StatusBadRequest, "old_password and new_password are required")
-		return
-	}
-
-	if len(req.NewPassword) < 6 {
-		respondError(w, http.StatusBadRequest, "password must be at least 6 characters")
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
@@ -285,10 +295,102 @@ func (h *UsersHandler) UpdatePassword(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// UpdateUser handles PUT /api/v1/users/:id ("me" or, for superusers and user managers, a user ID).
+// Users can change their own email and display name; superusers and user managers can also change usernames.
+// Changing the email sends a verification email to the new address.
+func (h *UsersHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	// User managers can update any user, other users only themselves
+	canManage := h.canManageUsers(r)
+
+	var userID domain.UserID
+	var ok bool
+
+	if canManage {
+		userID, ok = userIDParam(w, r, appcontext.Param(r.Context(), "id"))
+	} else {
+		userID, ok = targetUser(w, r, appcontext.Param(r.Context(), "id"))
+	}
+
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Username    *string `json:"username"`
+		Email       *string `json:"email" validate:"email"`
+		DisplayName *string `json:"display_name" validate:"max=255"`
+	}
+
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	if req.Username == nil && req.Email == nil && req.DisplayName == nil {
+		respondError(w, http.StatusBadRequest, "username, email or display_name is required")
+		return
+	}
+
+	if req.Username != nil && strings.TrimSpace(*req.Username) == "" {
+		respondError(w, http.StatusBadRequest, "username cannot be empty")
+		return
+	}
+
+	update := domain.UserUpdate{
+		Username:    req.Username,
+		Email:       req.Email,
+		DisplayName: req.DisplayName,
+	}
+
+	var user domain.User
+	var err error
+
+	if canManage {
+		user, err = h.usersService.UpdateUser(r.Context(), userID, update)
+	} else {
+		user, err = h.usersService.UpdateProfile(r.Context(), userID, update)
+	}
+
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrEntityNotFound):
+			respondError(w, http.StatusNotFound, "User not found")
+		case errors.Is(err, domain.ErrUsernameAlreadyInUse):
+			respondError(w, http.StatusConflict, "Username already in use")
+		case errors.Is(err, domain.ErrEmailAlreadyInUse), errors.Is(err, domain.ErrEntityAlreadyExists):
+			respondError(w, http.StatusConflict, "Email already in use")
+		case errors.Is(err, domain.ErrPermissionDenied):
+			respondError(w, http.StatusForbidden, "You are not allowed to change these fields")
+		default:
+			slog.ErrorContext(r.Context(), "Failed to update user", "error", err, "user_id", userID)
+			respondError(w, http.StatusInternalServerError, "Failed to update user")
+		}
+
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"id":             user.ID,
+		"username":       user.Username,
+		"display_name":   user.DisplayName,
+		"email":          user.Email,
+		"email_verified": user.EmailVerified(),
+		"updated_at":     user.UpdatedAt,
+	})
+}
+
 // CreateUser creates a new internal user. Only superusers can create users.
 func (h *UsersHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -296,9 +398,8 @@ func (h *UsersHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check if user is superuser
-	if !appcontext.IsSuper(r.Context()) {
-		respondError(w, http.StatusForbidden, "Only superusers can create users")
+	if !h.canManageUsers(r) {
+		respondError(w, http.StatusForbidden, "Only superusers and user managers can create users")
 		return
 	}
 
@@ -315,41 +416,49 @@ func (h *UsersHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		Username    string `json:"username"`
-		Email       string `json:"email"`
-		Password    string `json:"password"`
+		Username    string `json:"username" validate:"required"`
+		Email       string `json:"email" validate:"required,email"`
+		Password    string `json:"password" validate:"required,min=6"`
 		IsSuperuser bool   `json:"is_superuser"`
+		// Memberships are granted together with the user creation
+		Memberships []struct {
+			ProjectID  int        `json:"project_id" validate:"required,min=1"`
+			RoleID     string     `json:"role_id" validate:"required"`
+			ValidUntil *time.Time `json:"valid_until"`
+		} `json:"memberships"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
-		return
-	}
-
-	if req.Username == "" {
-		respondError(w, http.StatusBadRequest, "username is required")
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
-	if req.Email == "" {
-		respondError(w, http.StatusBadRequest, "email is required")
-		return
-	}
-
-	if req.Password == "" {
-		respondError(w, http.StatusBadRequest, "password is required")
-		return
-	}
+	grants := make([]domain.MembershipGrant, 0, len(req.Memberships))
+	for _, membership := range req.Memberships {
+		if membership.ValidUntil != nil && !membership.ValidUntil.After(time.Now()) {
+			respondError(w, http.StatusBadRequest, "valid_until must be in the future")
+			return
+		}
 
-	if len(req.Password) < 6 {
-		respondError(w, http.StatusBadRequest, "password must be at least 6 characters")
-		return
+		grants = append(grants, domain.MembershipGrant{
+			ProjectID:  domain.ProjectID(membership.ProjectID),
+			RoleID:     domain.RoleID(membership.RoleID),
+			ValidUntil: membership.ValidUntil,
+		})
 	}
 
-	user, err := h.usersService.Create(r.Context(), currentUser, req.Username, req.Email, req.Password, req.IsSuperuser)
+	user, err := h.usersService.CreateWithMemberships(r.Context(), currentUser,
+		req.Username, req.Email, req.Password, req.IsSuperuser, grants)
 	if err != nil {
 		if errors.Is(err, domain.ErrPermissionDenied) {
-			respondError(w, http.StatusForbidden, "Only superusers can create users")
+			respondError(w, http.StatusForbidden, "Not allowed to create the user or grant the memberships")
+			return
+		}
+		if errors.Is(err, domain.ErrRoleScopeMismatch) {
+			respondError(w, http.StatusBadRequest, "Tenant and global roles cannot be granted by project memberships")
+			return
+		}
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			respondError(w, http.StatusBadRequest, "Role not found")
 			return
 		}
 		if errors.Is(err, domain.ErrUsernameAlreadyInUse) {
@@ -376,10 +485,10 @@ func (h *UsersHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// ListUsers returns all users. Superusers and users with membership.manage permission can list users.
+// ListUsers returns all users. Superusers, user managers and users with membership.manage permission can list users.
 func (h *UsersHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -387,9 +496,8 @@ func (h *UsersHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check if user is superuser
-	isSuper := appcontext.IsSuper(r.Context())
-	if !isSuper {
+	// Superusers and user managers can always list users
+	if !h.canManageUsers(r) {
 		userID := appcontext.UserID(r.Context())
 		if userID == 0 {
 			respondError(w, http.StatusUnauthorized, "Unauthorized")
@@ -399,26 +507,26 @@ func (h *UsersHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
 		// Check if user has membership.manage permission in any of their projects
 		projectPermissions, err := h.permissionsService.GetMyProjectPermissions(r.Context())
 		if err != nil {
-			slog.Error("Failed to get project permissions", "error", err, "user_id", userID)
+			slog.ErrorContext(r.Context(), "Failed to get project permissions", "error", err, "user_id", userID)
 			respondError(w, http.StatusInternalServerError, "Failed to check permissions")
 			return
 		}
 
-		slog.Debug("Checking user permissions for list users",
+		slog.DebugContext(r.Context(), "Checking user permissions for list users",
 			"user_id", userID,
 			"projects_count", len(projectPermissions),
 		)
 
 		hasMembershipManage := false
 		for projectID, perms := range projectPermissions {
-			slog.Debug("Checking project permissions",
+			slog.DebugContext(r.Context(), "Checking project permissions",
 				"user_id", userID,
 				"project_id", projectID,
 				"permissions", perms,
 			)
 			for _, perm := range perms {
 				if perm == domain.PermMembershipManage {
-					slog.Debug("User has membership.manage permission",
+					slog.DebugContext(r.Context(), "User has membership.manage permission",
 						"user_id", userID, "project_id", projectID, "permission", perm)
 					hasMembershipManage = true
 					break
@@ -430,7 +538,7 @@ func (h *UsersHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
 		}
 
 		if !hasMembershipManage {
-			slog.Warn("User denied access to list users",
+			slog.WarnContext(r.Context(), "User denied access to list users",
 				"user_id", userID,
 				"project_permissions_count", len(projectPermissions),
 				"project_permissions", projectPermissions,
@@ -472,6 +580,7 @@ func (h *UsersHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
 			"is_external":         user.IsExternal,
 			"two_fa_enabled":      user.TwoFAEnabled,
 			"two_fa_confirmed_at": twoFAConfirmedAt,
+			"email_verified":      user.EmailVerified(),
 			"created_at":          user.CreatedAt.Format(time.RFC3339),
 			"updated_at":          user.UpdatedAt.Format(time.RFC3339),
 			"last_login":          lastLogin,
@@ -486,7 +595,7 @@ func (h *UsersHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
 // UpdateUserStatus updates user active status. Only superusers can update user status.
 func (h *UsersHandler) UpdateUserStatus(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPut && r.Method != http.MethodPatch {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -494,9 +603,8 @@ func (h *UsersHandler) UpdateUserStatus(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Check if user is superuser
-	if !appcontext.IsSuper(r.Context()) {
-		respondError(w, http.StatusForbidden, "Only superusers can update user status")
+	if !h.canManageUsers(r) {
+		respondError(w, http.StatusForbidden, "Only superusers and user managers can update user status")
 		return
 	}
 
@@ -518,8 +626,7 @@ func (h *UsersHandler) UpdateUserStatus(w http.ResponseWriter, r *http.Request)
 		IsSuperuser *bool `json:"is_superuser,omitempty"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
@@ -589,6 +696,7 @@ func (h *UsersHandler) UpdateUserStatus(w http.ResponseWriter, r *http.Request)
 		"is_external":         updatedUser.IsExternal,
 		"two_fa_enabled":      updatedUser.TwoFAEnabled,
 		"two_fa_confirmed_at": twoFAConfirmedAt,
+		"email_verified":      updatedUser.EmailVerified(),
 		"created_at":          updatedUser.CreatedAt.Format(time.RFC3339),
 		"updated_at":          updatedUser.UpdatedAt.Format(time.RFC3339),
 		"last_login":          lastLogin,
@@ -600,7 +708,7 @@ func (h *UsersHandler) UpdateUserStatus(w http.ResponseWriter, r *http.Request)
 // DeleteUser deletes a user. Only superusers can delete users.
 func (h *UsersHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodDelete {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -608,9 +716,8 @@ func (h *UsersHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check if user is superuser
-	if !appcontext.IsSuper(r.Context()) {
-		respondError(w, http.StatusForbidden, "Only superusers can delete users")
+	if !h.canManageUsers(r) {
+		respondError(w, http.StatusForbidden, "Only superusers and user managers can delete users")
 		return
 	}
 
@@ -643,3 +750,180 @@ func (h *UsersHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 
 	respondJSON(w, http.StatusOK, map[string]string{"message": "user deleted successfully"})
 }
+
+// BulkUsers handles POST /api/v1/users/bulk: activates, deactivates or deletes a list of users
+// in a single transaction and reports the result for each user. Only superusers can use it.
+func (h *UsersHandler) BulkUsers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	if !h.canManageUsers(r) {
+		respondError(w, http.StatusForbidden, "Only superusers and user managers can manage users")
+		return
+	}
+
+	var req struct {
+		Action  domain.BulkUserAction `json:"action"`
+		UserIDs []domain.UserID       `json:"user_ids" validate:"required,max=1000"`
+	}
+
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	if !req.Action.Valid() {
+		respondError(w, http.StatusBadRequest, "action must be one of activate, deactivate, delete")
+		return
+	}
+
+	results, err := h.usersService.BulkUpdate(r.Context(), req.Action, req.UserIDs)
+	if err != nil {
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			respondError(w, http.StatusForbidden, "Only superusers can manage users")
+			return
+		}
+
+		slog.ErrorContext(r.Context(), "Failed to apply bulk user action", "error", err, "action", req.Action)
+		respondError(w, http.StatusInternalServerError, "Failed to apply bulk user action")
+		return
+	}
+
+	items := make([]map[string]interface{}, 0, len(results))
+	succeeded := 0
+
+	for _, result := range results {
+		item := map[string]interface{}{
+			"id":      result.UserID,
+			"success": result.Err == nil,
+		}
+
+		switch {
+		case result.Err == nil:
+			succeeded++
+		case errors.Is(result.Err, domain.ErrEntityNotFound):
+			item["error"] = "user not found"
+		case errors.Is(result.Err, domain.ErrPermissionDenied):
+			item["error"] = "not allowed for this user"
+		default:
+			item["error"] = result.Err.Error()
+		}
+
+		items = append(items, item)
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"action":    req.Action,
+		"succeeded": succeeded,
+		"failed":    len(results) - succeeded,
+		"results":   items,
+	})
+}
+
+// MergeUser handles POST /api/v1/users/merge: folds source_user_id into target_user_id, e.g. a local
+// account duplicated by SSO or LDAP. Memberships, global roles, preferences and audit log attribution
+// are moved to the target and the user is deactivated. Only superusers and user managers can merge users.
+func (h *UsersHandler) MergeUser(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	if !h.canManageUsers(r) {
+		respondError(w, http.StatusForbidden, "Only superusers and user managers can merge users")
+		return
+	}
+
+	var req struct {
+		SourceUserID domain.UserID `json:"source_user_id" validate:"required,min=1"`
+		TargetUserID domain.UserID `json:"target_user_id" validate:"required,min=1"`
+	}
+
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	result, err := h.usersService.Merge(r.Context(), req.SourceUserID, req.TargetUserID)
+	if err != nil {
+		if !errors.Is(err, domain.ErrEntityNotFound) && !errors.Is(err, domain.ErrPermissionDenied) &&
+			!errors.Is(err, domain.ErrInvalidUserMerge) {
+			slog.ErrorContext(r.Context(), "Failed to merge users",
+				"error", err,
+				"source_id", req.SourceUserID,
+				"target_id", req.TargetUserID,
+			)
+		}
+
+		respondErr(w, err)
+		return
+	}
+
+	h.permissionsService.InvalidateCache()
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"source_id":          result.SourceID,
+		"target_id":          result.TargetID,
+		"memberships":        result.Memberships,
+		"tenant_memberships": result.TenantMemberships,
+		"global_roles":       result.GlobalRoles,
+		"preferences":        result.Preferences,
+	})
+}
+
+// Impersonate handles POST /api/v1/users/impersonate: issues a superuser a short-lived access token
+// acting as user_id, to debug permission issues. There is no refresh token; the actions taken with the
+// token are audited with the superuser as the impersonator.
+func (h *UsersHandler) Impersonate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	if !appcontext.IsSuper(r.Context()) {
+		respondError(w, http.StatusForbidden, "Only superusers can impersonate users")
+		return
+	}
+
+	var req struct {
+		UserID domain.UserID `json:"user_id" validate:"required,min=1"`
+	}
+
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	accessToken, expiresIn, err := h.usersService.Impersonate(r.Context(), req.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			respondError(w, http.StatusForbidden,
+				"Only superusers logged in with a session can impersonate active users that are not superusers")
+			return
+		}
+
+		if !errors.Is(err, domain.ErrEntityNotFound) {
+			slog.ErrorContext(r.Context(), "Failed to impersonate user", "error", err, "user_id", req.UserID)
+		}
+
+		respondErr(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token":  accessToken,
+		"expires_in":    expiresIn,
+		"impersonating": req.UserID,
+	})
+}
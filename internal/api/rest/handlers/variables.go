@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	variablesusecase "github.com/rom8726/floxy-manager/internal/usecases/variables"
+)
+
+const maxVariableRequestSize = 128 << 10
+
+type VariablesHandler struct {
+	variablesUseCase contract.VariablesUseCase
+	permissionsSrv   contract.PermissionsService
+}
+
+func NewVariablesHandler(
+	variablesUseCase contract.VariablesUseCase,
+	permissionsSrv contract.PermissionsService,
+) *VariablesHandler {
+	return &VariablesHandler{
+		variablesUseCase: variablesUseCase,
+		permissionsSrv:   permissionsSrv,
+	}
+}
+
+type setVariableRequest struct {
+	Value *string `json:"value" validate:"required"`
+	// Secret defaults to true: secret values are never returned by the API
+	Secret *bool `json:"secret"`
+}
+
+// List handles GET /api/v1/projects/:id/variables
+func (h *VariablesHandler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	projectID, ok := authorizeProjectParam(w, r, h.permissionsSrv, false)
+	if !ok {
+		return
+	}
+
+	variables, err := h.variablesUseCase.List(r.Context(), projectID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to list project variables", "error", err, "project_id", projectID)
+		respondError(w, http.StatusInternalServerError, "Failed to list variables")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"items": variables,
+	})
+}
+
+// Get handles GET /api/v1/projects/:id/variables/:name
+func (h *VariablesHandler) Get(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	projectID, ok := authorizeProjectParam(w, r, h.permissionsSrv, false)
+	if !ok {
+		return
+	}
+
+	name := appcontext.Param(r.Context(), "name")
+
+	variable, err := h.variablesUseCase.Get(r.Context(), projectID, name)
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			respondError(w, http.StatusNotFound, "Variable not found")
+			return
+		}
+		slog.ErrorContext(r.Context(), "Failed to get project variable",
+			"error", err,
+			"project_id", projectID,
+			"name", name,
+		)
+		respondError(w, http.StatusInternalServerError, "Failed to get variable")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, variable)
+}
+
+// Set handles PUT /api/v1/projects/:id/variables/:name
+// The variable is created or replaced, the response is 201 when it was created.
+func (h *VariablesHandler) Set(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	projectID, ok := authorizeProjectParam(w, r, h.permissionsSrv, true)
+	if !ok {
+		return
+	}
+
+	var req setVariableRequest
+	r.Body = http.MaxBytesReader(w, r.Body, maxVariableRequestSize)
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	dto := domain.ProjectVariableDTO{
+		Name:   appcontext.Param(r.Context(), "name"),
+		Value:  *req.Value,
+		Secret: req.Secret == nil || *req.Secret,
+	}
+
+	variable, created, err := h.variablesUseCase.Set(r.Context(), projectID, dto)
+	if err != nil {
+		if errors.Is(err, variablesusecase.ErrInvalidVariable) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		slog.ErrorContext(r.Context(), "Failed to set project variable",
+			"error", err,
+			"project_id", projectID,
+			"name", dto.Name,
+		)
+		respondError(w, http.StatusInternalServerError, "Failed to set variable")
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+
+	respondJSON(w, status, variable)
+}
+
+// Delete handles DELETE /api/v1/projects/:id/variables/:name
+func (h *VariablesHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	projectID, ok := authorizeProjectParam(w, r, h.permissionsSrv, true)
+	if !ok {
+		return
+	}
+
+	name := appcontext.Param(r.Context(), "name")
+
+	if err := h.variablesUseCase.Delete(r.Context(), projectID, name); err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			respondError(w, http.StatusNotFound, "Variable not found")
+			return
+		}
+		slog.ErrorContext(r.Context(), "Failed to delete project variable",
+			"error", err,
+			"project_id", projectID,
+			"name", name,
+		)
+		respondError(w, http.StatusInternalServerError, "Failed to delete variable")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+// WebAuthnRegisterBegin handles POST /api/v1/auth/2fa/webauthn/register/begin
+func (h *TwoFAHandler) WebAuthnRegisterBegin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userID := appcontext.UserID(r.Context())
+	if userID == 0 {
+		respondError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	creation, err := h.usersService.BeginWebAuthnRegistration(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrEmailNotVerified) {
+			respondError(w, http.StatusForbidden, "Verify your email address before registering a security key")
+			return
+		}
+
+		slog.ErrorContext(r.Context(), "Failed to begin security key registration", "error", err)
+		respondError(w, http.StatusInternalServerError, "Failed to begin security key registration")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, creation)
+}
+
+// WebAuthnRegisterFinish handles POST /api/v1/auth/2fa/webauthn/register/finish?name=...
+// The body is the PublicKeyCredential returned by navigator.credentials.create().
+func (h *TwoFAHandler) WebAuthnRegisterFinish(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userID := appcontext.UserID(r.Context())
+	if userID == 0 {
+		respondError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	cred, err := h.usersService.FinishWebAuthnRegistration(r.Context(), userID, r.URL.Query().Get("name"), r)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidToken):
+			respondError(w, http.StatusBadRequest, "Registration session expired, start again")
+		case errors.Is(err, domain.ErrInvalidWebAuthnResponse):
+			respondError(w, http.StatusBadRequest, "Invalid security key response")
+		case errors.Is(err, domain.ErrEntityAlreadyExists):
+			respondError(w, http.StatusConflict, "Security key is already registered")
+		default:
+			slog.ErrorContext(r.Context(), "Failed to register security key", "error", err)
+			respondError(w, http.StatusInternalServerError, "Failed to register security key")
+		}
+
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, cred)
+}
+
+// WebAuthnLoginBegin handles POST /api/v1/auth/2fa/webauthn/login/begin
+func (h *TwoFAHandler) WebAuthnLoginBegin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req struct {
+		SessionID string `json:"session_id" validate:"required"`
+	}
+
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	assertion, err := h.usersService.BeginWebAuthnLogin(r.Context(), req.SessionID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrTooMany2FAAttempts):
+			respondError(w, http.StatusTooManyRequests, err.Error())
+		case errors.Is(err, domain.ErrNoWebAuthnCredentials):
+			respondError(w, http.StatusBadRequest, err.Error())
+		default:
+			respondError(w, http.StatusUnauthorized, "Invalid 2FA session")
+		}
+
+		return
+	}
+
+	respondJSON(w, http.StatusOK, assertion)
+}
+
+// WebAuthnLoginFinish handles POST /api/v1/auth/2fa/webauthn/login/finish?session_id=...
+// The body is the PublicKeyCredential returned by navigator.credentials.get().
+func (h *TwoFAHandler) WebAuthnLoginFinish(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	sessionID := r.URL.Query().Get("session_id")
+	if sessionID == "" {
+		respondError(w, http.StatusBadRequest, "session_id is required")
+		return
+	}
+
+	accessToken, refreshToken, expiresIn, err := h.usersService.FinishWebAuthnLogin(r.Context(), sessionID, r)
+	if err != nil {
+		if errors.Is(err, domain.ErrTooMany2FAAttempts) {
+			respondError(w, http.StatusTooManyRequests, err.Error())
+			return
+		}
+
+		respondError(w, http.StatusUnauthorized, "Invalid security key")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token":  accessToken,
+		"refresh_token": refreshToken,
+		"expires_in":    expiresIn,
+	})
+}
+
+// ListWebAuthnCredentials handles GET /api/v1/users/me/webauthn-credentials
+func (h *TwoFAHandler) ListWebAuthnCredentials(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userID := appcontext.UserID(r.Context())
+	if userID == 0 {
+		respondError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	creds, err := h.usersService.ListWebAuthnCredentials(r.Context(), userID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to list security keys", "error", err)
+		respondError(w, http.StatusInternalServerError, "Failed to list security keys")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, creds)
+}
+
+// DeleteWebAuthnCredential handles DELETE /api/v1/users/:id/webauthn-credentials/:cid
+func (h *TwoFAHandler) DeleteWebAuthnCredential(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	userID, ok := targetUser(w, r, appcontext.Param(r.Context(), "id"))
+	if !ok {
+		return
+	}
+
+	id, err := strconv.Atoi(appcontext.Param(r.Context(), "cid"))
+	if err != nil || id <= 0 {
+		respondError(w, http.StatusBadRequest, "invalid security key id")
+		return
+	}
+
+	err = h.usersService.DeleteWebAuthnCredential(r.Context(), userID, domain.WebAuthnCredentialID(id))
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			respondError(w, http.StatusNotFound, "security key not found")
+			return
+		}
+
+		slog.ErrorContext(r.Context(), "Failed to delete security key",
+			"error", err,
+			"credential_id", id,
+		)
+		respondError(w, http.StatusInternalServerError, "Failed to delete security key")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "security key deleted successfully"})
+}
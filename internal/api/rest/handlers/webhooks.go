@@ -0,0 +1,266 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	webhooksusecase "github.com/rom8726/floxy-manager/internal/usecases/webhooks"
+)
+
+type WebhooksHandler struct {
+	webhooksUseCase contract.WebhooksUseCase
+	permissionsSrv  contract.PermissionsService
+}
+
+func NewWebhooksHandler(
+	webhooksUseCase contract.WebhooksUseCase,
+	permissionsSrv contract.PermissionsService,
+) *WebhooksHandler {
+	return &WebhooksHandler{
+		webhooksUseCase: webhooksUseCase,
+		permissionsSrv:  permissionsSrv,
+	}
+}
+
+type webhookRequest struct {
+	Name    string                      `json:"name"`
+	URL     string                      `json:"url"`
+	Events  []domain.LifecycleEventType `json:"events"`
+	Enabled *bool                       `json:"enabled"`
+}
+
+func (req *webhookRequest) toDTO() domain.WebhookDTO {
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	return domain.WebhookDTO{
+		Name:    req.Name,
+		URL:     req.URL,
+		Events:  req.Events,
+		Enabled: enabled,
+	}
+}
+
+// List handles GET /api/v1/projects/:id/webhooks
+func (h *WebhooksHandler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	projectID, ok := authorizeProjectParam(w, r, h.permissionsSrv, false)
+	if !ok {
+		return
+	}
+
+	webhooks, err := h.webhooksUseCase.List(r.Context(), projectID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to list webhooks",
+			"error", err,
+			"project_id", projectID,
+		)
+		respondError(w, http.StatusInternalServerError, "Failed to list webhooks")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, webhooks)
+}
+
+// Create handles POST /api/v1/projects/:id/webhooks
+func (h *WebhooksHandler) Create(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	projectID, ok := authorizeProjectParam(w, r, h.permissionsSrv, true)
+	if !ok {
+		return
+	}
+
+	var req webhookRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	webhook, err := h.webhooksUseCase.Create(r.Context(), projectID, req.toDTO())
+	if err != nil {
+		respondWebhookError(w, r, err, "Failed to create webhook", 0)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, webhook)
+}
+
+// Get handles GET /api/v1/projects/:id/webhooks/:wid
+func (h *WebhooksHandler) Get(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	projectID, ok := authorizeProjectParam(w, r, h.permissionsSrv, false)
+	if !ok {
+		return
+	}
+
+	id, ok := parseWebhookID(w, r)
+	if !ok {
+		return
+	}
+
+	webhook, err := h.webhooksUseCase.Get(r.Context(), projectID, id)
+	if err != nil {
+		respondWebhookError(w, r, err, "Failed to get webhook", id)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, webhook)
+}
+
+// Update handles PUT /api/v1/projects/:id/webhooks/:wid
+func (h *WebhooksHandler) Update(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	projectID, ok := authorizeProjectParam(w, r, h.permissionsSrv, true)
+	if !ok {
+		return
+	}
+
+	id, ok := parseWebhookID(w, r)
+	if !ok {
+		return
+	}
+
+	var req webhookRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	webhook, err := h.webhooksUseCase.Update(r.Context(), projectID, id, req.toDTO())
+	if err != nil {
+		respondWebhookError(w, r, err, "Failed to update webhook", id)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, webhook)
+}
+
+// Delete handles DELETE /api/v1/projects/:id/webhooks/:wid
+func (h *WebhooksHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	projectID, ok := authorizeProjectParam(w, r, h.permissionsSrv, true)
+	if !ok {
+		return
+	}
+
+	id, ok := parseWebhookID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.webhooksUseCase.Delete(r.Context(), projectID, id); err != nil {
+		respondWebhookError(w, r, err, "Failed to delete webhook", id)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "webhook deleted successfully"})
+}
+
+// ListDeliveries handles GET /api/v1/projects/:id/webhooks/:wid/deliveries
+func (h *WebhooksHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !checkAuthAndRespond(w, r) {
+		return
+	}
+
+	projectID, ok := authorizeProjectParam(w, r, h.permissionsSrv, false)
+	if !ok {
+		return
+	}
+
+	id, ok := parseWebhookID(w, r)
+	if !ok {
+		return
+	}
+
+	page, pageSize := parsePagination(r)
+
+	items, total, err := h.webhooksUseCase.ListDeliveries(r.Context(), projectID, id, page, pageSize)
+	if err != nil {
+		respondWebhookError(w, r, err, "Failed to list webhook deliveries", id)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"items":     items,
+		"page":      page,
+		"page_size": pageSize,
+		"total":     total,
+	})
+}
+
+func parseWebhookID(w http.ResponseWriter, r *http.Request) (domain.WebhookID, bool) {
+	id, err := strconv.Atoi(appcontext.Param(r.Context(), "wid"))
+	if err != nil || id <= 0 {
+		respondError(w, http.StatusBadRequest, "Invalid webhook ID")
+		return 0, false
+	}
+
+	return domain.WebhookID(id), true
+}
+
+func respondWebhookError(w http.ResponseWriter, r *http.Request, err error, msg string, id domain.WebhookID) {
+	switch {
+	case errors.Is(err, webhooksusecase.ErrInvalidWebhook):
+		respondError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrEntityNotFound):
+		respondError(w, http.StatusNotFound, "Webhook not found")
+	case errors.Is(err, domain.ErrEntityAlreadyExists):
+		respondError(w, http.StatusConflict, "Webhook with this name already exists")
+	default:
+		slog.ErrorContext(r.Context(), msg,
+			"error", err,
+			"webhook_id", id,
+		)
+		respondError(w, http.StatusInternalServerError, msg)
+	}
+}
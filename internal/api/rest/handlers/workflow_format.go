@@ -0,0 +1,273 @@
+package handlers
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/services/workflowformat"
+	workflowsusecase "github.com/rom8726/floxy-manager/internal/usecases/workflows"
+)
+
+const (
+	// maxWorkflowYAMLSize bounds YAML request bodies, which are read whole before conversion.
+	maxWorkflowYAMLSize = 10 << 20
+
+	// maxWorkflowImportSize bounds bulk import payloads, archives included.
+	maxWorkflowImportSize = 32 << 20
+
+	exportPageSize = 100
+)
+
+var unsafeFileNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// decodeWorkflowRequest decodes a JSON or, with a YAML Content-Type, a YAML request body into v.
+func decodeWorkflowRequest(r *http.Request, v any) error {
+	if !workflowformat.IsYAML(r.Header.Get("Content-Type")) {
+		return decodeJSON(r, v)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxWorkflowYAMLSize+1))
+	if err != nil {
+		return err
+	}
+	if len(data) > maxWorkflowYAMLSize {
+		return errors.New("request body is too large")
+	}
+
+	data, err = workflowformat.YAMLToJSON(data)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// respondWorkflow responds with JSON or, when the client prefers it, with YAML.
+func respondWorkflow(w http.ResponseWriter, r *http.Request, status int, data any) {
+	w.Header().Add("Vary", "Accept")
+
+	if !workflowformat.AcceptsYAML(r.Header.Get("Accept")) {
+		respondJSON(w, status, data)
+		return
+	}
+
+	out, err := workflowformat.MarshalYAML(data)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to encode YAML response", "error", err)
+		respondError(w, http.StatusInternalServerError, "Failed to encode response")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/yaml")
+	w.WriteHeader(status)
+	_, _ = w.Write(out)
+}
+
+// ExportWorkflows handles GET /api/v1/workflow-export
+// Responds with a gzipped tarball holding a <name>/v<version>.<format> file per workflow definition
+// of the project; format is yaml (default) or json.
+func (h *WorkflowsHandler) ExportWorkflows(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !requireAuthForWorkflows(w, r) {
+		return
+	}
+
+	tenantID, projectID, err := parseTenantAndProject(r)
+	if err != nil {
+		slog.WarnContext(r.Context(), "Invalid tenant_id or project_id in request",
+			"error", err,
+			"path", r.URL.Path,
+		)
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Check if user has permission to view this project
+	if err := h.permissionsSrv.CanViewProject(r.Context(), projectID); err != nil {
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			respondError(w, http.StatusForbidden, "Access denied to this project")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to verify permissions")
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	switch format {
+	case "":
+		format = "yaml"
+	case "yaml", "json":
+	default:
+		respondError(w, http.StatusBadRequest, "format must be yaml or json")
+		return
+	}
+
+	// Definitions are collected first: errors can't be reported once the archive is being written
+	var workflows []domain.WorkflowDefinition
+	for page := 1; ; page++ {
+		items, total, err := h.workflowsRepo.ListWorkflowDefinitions(r.Context(), tenantID, projectID,
+			page, exportPageSize)
+		if err != nil {
+			slog.ErrorContext(r.Context(), "Failed to list workflow definitions",
+				"error", err,
+				"project_id", projectID,
+			)
+			respondErr(w, err)
+			return
+		}
+
+		workflows = append(workflows, items...)
+		if len(items) == 0 || page*exportPageSize >= total {
+			break
+		}
+	}
+
+	fileName := fmt.Sprintf("workflows-project-%d-%s.tar.gz", projectID, time.Now().UTC().Format("20060102T150405Z"))
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+fileName+`"`)
+	w.WriteHeader(http.StatusOK)
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	if err := writeWorkflowsArchive(tw, workflows, format); err != nil {
+		// The status is sent already, the truncated archive fails to unpack on the client side
+		slog.ErrorContext(r.Context(), "Failed to write workflows archive",
+			"error", err,
+			"project_id", projectID,
+		)
+		return
+	}
+
+	if err := tw.Close(); err != nil {
+		slog.ErrorContext(r.Context(), "Failed to write workflows archive", "error", err)
+		return
+	}
+
+	_ = gz.Close()
+}
+
+func writeWorkflowsArchive(tw *tar.Writer, workflows []domain.WorkflowDefinition, format string) error {
+	for i := range workflows {
+		workflow := &workflows[i]
+
+		data := []byte(workflow.Definition)
+		if format == "yaml" {
+			var err error
+			data, err = workflowformat.JSONToYAML(workflow.Definition)
+			if err != nil {
+				return fmt.Errorf("convert workflow %s: %w", workflow.ID, err)
+			}
+		}
+
+		dir := unsafeFileNameChars.ReplaceAllString(workflow.Name, "_")
+		if strings.Trim(dir, ".") == "" {
+			dir = "_"
+		}
+
+		header := &tar.Header{
+			Name:    fmt.Sprintf("%s/v%d.%s", dir, workflow.Version, format),
+			Mode:    0o644,
+			Size:    int64(len(data)),
+			ModTime: workflow.CreatedAt,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ImportWorkflows handles POST /api/v1/projects/:id/workflows/import
+// The payload holds several definitions (see workflowformat.ParseImport). They are validated and
+// created in one transaction: when any of them is invalid or conflicts, nothing is created and
+// 422 is returned. The response lists the result of each definition; with dry_run=true nothing
+// is created either.
+func (h *WorkflowsHandler) ImportWorkflows(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !requireAuthForWorkflows(w, r) {
+		return
+	}
+
+	projectIDInt, err := strconv.Atoi(appcontext.Param(r.Context(), "id"))
+	if err != nil || projectIDInt <= 0 {
+		respondError(w, http.StatusBadRequest, "invalid project id")
+		return
+	}
+
+	projectID := domain.ProjectID(projectIDInt)
+
+	// Check if user has permission to create workflows in this project
+	if err := h.permissionsSrv.CanCreateWorkflow(r.Context(), projectID); err != nil {
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			respondError(w, http.StatusForbidden, "Access denied to create workflows in this project")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to verify permissions")
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxWorkflowImportSize+1))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Failed to read request body")
+		return
+	}
+	if len(data) > maxWorkflowImportSize {
+		respondError(w, http.StatusRequestEntityTooLarge, "Payload too large")
+		return
+	}
+
+	items, err := workflowformat.ParseImport(r.Header.Get("Content-Type"), data, workflowsusecase.MaxImportItems)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+
+	results, err := h.workflowsUseCase.ImportDefinitions(r.Context(), projectID, items, dryRun)
+	if err != nil && !errors.Is(err, workflowsusecase.ErrImportRejected) {
+		slog.ErrorContext(r.Context(), "Failed to import workflow definitions",
+			"error", err,
+			"project_id", projectID,
+			"count", len(items),
+		)
+		respondError(w, http.StatusInternalServerError, "Failed to import workflow definitions")
+		return
+	}
+
+	status := http.StatusOK
+	if err != nil {
+		status = http.StatusUnprocessableEntity
+	}
+
+	respondJSON(w, status, map[string]interface{}{
+		"dry_run": dryRun,
+		"items":   results,
+	})
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/services/workflowsim"
+)
+
+const maxSimulationRequestSize = 1 << 20
+
+// SimulateWorkflow handles POST /api/v1/workflows/:id/simulate
+// The definition is walked from its start step with the input of the request, following the routing
+// rules of the engine; nothing is started or stored. The body may set step_outputs to mock task outputs
+// and decisions ("confirmed" or "rejected") for human steps, which are assumed confirmed otherwise.
+func (h *WorkflowsHandler) SimulateWorkflow(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !requireAuthForWorkflows(w, r) {
+		return
+	}
+
+	id := appcontext.Param(r.Context(), "id")
+	if id == "" {
+		respondError(w, http.StatusBadRequest, "Invalid workflow ID")
+		return
+	}
+
+	tenantID, projectID, err := parseTenantAndProject(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Simulations have no side effects, viewers may run them
+	if err := h.permissionsSrv.CanViewProject(r.Context(), projectID); err != nil {
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			respondError(w, http.StatusForbidden, "Access denied to this project")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to verify permissions")
+		return
+	}
+
+	var req domain.WorkflowSimulationInput
+	r.Body = http.MaxBytesReader(w, r.Body, maxSimulationRequestSize)
+	if err := decodeWorkflowRequest(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	workflow, err := h.workflowsRepo.GetWorkflowDefinition(r.Context(), tenantID, projectID, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			respondError(w, http.StatusNotFound, "Workflow not found")
+			return
+		}
+		slog.ErrorContext(r.Context(), "Failed to get workflow definition",
+			"error", err,
+			"workflow_id", id,
+			"tenant_id", tenantID,
+			"project_id", projectID,
+		)
+		respondErr(w, err)
+		return
+	}
+
+	simulation, err := workflowsim.Simulate(workflow.Definition, req)
+	if err != nil {
+		if errors.Is(err, workflowsim.ErrInvalidSimulation) {
+			respondError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to simulate workflow")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, simulation)
+}
@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	workflowsusecase "github.com/rom8726/floxy-manager/internal/usecases/workflows"
+)
+
+const (
+	defaultRolloutsLimit = 50
+	maxRolloutsLimit     = 500
+)
+
+type workflowVersionRequest struct {
+	Version int `json:"version" validate:"min=0"`
+}
+
+// ListActiveWorkflowVersions handles GET /api/v1/projects/:id/workflow-versions
+func (h *WorkflowsHandler) ListActiveWorkflowVersions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !requireAuthForWorkflows(w, r) {
+		return
+	}
+
+	projectID, ok := authorizeProjectParam(w, r, h.permissionsSrv, false)
+	if !ok {
+		return
+	}
+
+	versions, err := h.workflowsRepo.ListActiveVersions(r.Context(), projectID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to list active workflow versions", "error", err, "project_id", projectID)
+		respondError(w, http.StatusInternalServerError, "Failed to list active versions")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"items": versions,
+	})
+}
+
+// PromoteWorkflowVersion handles POST /api/v1/projects/:id/workflow-versions/:name/promote
+// The version of the body becomes the active version of the workflow: scheduled and hook-triggered
+// instances start on it from now on. Running instances are not affected.
+func (h *WorkflowsHandler) PromoteWorkflowVersion(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !requireAuthForWorkflows(w, r) {
+		return
+	}
+
+	projectID, ok := authorizeProjectParam(w, r, h.permissionsSrv, true)
+	if !ok {
+		return
+	}
+
+	var req workflowVersionRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	// Rollbacks may omit the version, promotions may not
+	if req.Version == 0 {
+		respondError(w, http.StatusBadRequest, "version is required")
+		return
+	}
+
+	name := appcontext.Param(r.Context(), "name")
+
+	active, err := h.workflowsUseCase.PromoteVersion(r.Context(), projectID, name, req.Version)
+	if err != nil {
+		h.respondVersionError(w, r, err, projectID, name)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, active)
+}
+
+// RollbackWorkflowVersion handles POST /api/v1/projects/:id/workflow-versions/:name/rollback
+// Without a version in the body the workflow goes back to the version that was active before
+// the current one was promoted.
+func (h *WorkflowsHandler) RollbackWorkflowVersion(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !requireAuthForWorkflows(w, r) {
+		return
+	}
+
+	projectID, ok := authorizeProjectParam(w, r, h.permissionsSrv, true)
+	if !ok {
+		return
+	}
+
+	var req workflowVersionRequest
+	if r.ContentLength != 0 {
+		if !decodeRequest(w, r, &req) {
+			return
+		}
+	}
+
+	name := appcontext.Param(r.Context(), "name")
+
+	active, err := h.workflowsUseCase.RollbackVersion(r.Context(), projectID, name, req.Version)
+	if err != nil {
+		h.respondVersionError(w, r, err, projectID, name)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, active)
+}
+
+// UnpinWorkflowVersion handles DELETE /api/v1/projects/:id/workflow-versions/:name
+// New instances start on the version referenced by their schedule or hook again.
+func (h *WorkflowsHandler) UnpinWorkflowVersion(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !requireAuthForWorkflows(w, r) {
+		return
+	}
+
+	projectID, ok := authorizeProjectParam(w, r, h.permissionsSrv, true)
+	if !ok {
+		return
+	}
+
+	name := appcontext.Param(r.Context(), "name")
+
+	if err := h.workflowsRepo.DeleteActiveVersion(r.Context(), projectID, name); err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			respondError(w, http.StatusNotFound, "Workflow is not pinned")
+			return
+		}
+		slog.ErrorContext(r.Context(), "Failed to unpin workflow version",
+			"error", err,
+			"project_id", projectID,
+			"workflow_name", name,
+		)
+		respondError(w, http.StatusInternalServerError, "Failed to unpin workflow version")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListWorkflowVersionRollouts handles GET /api/v1/projects/:id/workflow-versions/:name/history?limit=
+func (h *WorkflowsHandler) ListWorkflowVersionRollouts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !requireAuthForWorkflows(w, r) {
+		return
+	}
+
+	projectID, ok := authorizeProjectParam(w, r, h.permissionsSrv, false)
+	if !ok {
+		return
+	}
+
+	limit := defaultRolloutsLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		if err != nil || l <= 0 {
+			respondError(w, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+		limit = min(l, maxRolloutsLimit)
+	}
+
+	name := appcontext.Param(r.Context(), "name")
+
+	rollouts, err := h.workflowsRepo.ListVersionRollouts(r.Context(), projectID, name, limit)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to list workflow version rollouts",
+			"error", err,
+			"project_id", projectID,
+			"workflow_name", name,
+		)
+		respondError(w, http.StatusInternalServerError, "Failed to list rollouts")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"items": rollouts,
+	})
+}
+
+func (h *WorkflowsHandler) respondVersionError(
+	w http.ResponseWriter,
+	r *http.Request,
+	err error,
+	projectID domain.ProjectID,
+	name string,
+) {
+	switch {
+	case errors.Is(err, domain.ErrEntityNotFound):
+		respondError(w, http.StatusNotFound, "Workflow version not found in this project")
+	case errors.Is(err, workflowsusecase.ErrVersionAlreadyActive),
+		errors.Is(err, workflowsusecase.ErrNoRollbackTarget):
+		respondError(w, http.StatusConflict, err.Error())
+	default:
+		slog.ErrorContext(r.Context(), "Failed to change active workflow version",
+			"error", err,
+			"project_id", projectID,
+			"workflow_name", name,
+		)
+		respondError(w, http.StatusInternalServerError, "Failed to change active version")
+	}
+}
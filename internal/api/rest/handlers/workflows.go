@@ -7,31 +7,55 @@ import (
 	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	appcontext "github.com/rom8726/floxy-manager/internal/context"
 	"github.com/rom8726/floxy-manager/internal/contract"
 	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/services/definitionsearch"
+	"github.com/rom8726/floxy-manager/internal/services/instancegraph"
+	"github.com/rom8726/floxy-manager/internal/services/instancesearch"
+	"github.com/rom8726/floxy-manager/internal/services/workflowdiff"
+	workflowsusecase "github.com/rom8726/floxy-manager/internal/usecases/workflows"
+)
+
+const (
+	defaultFailuresWindow = 30 * 24 * time.Hour
+	defaultFailuresLimit  = 50
+	maxFailuresLimit      = 500
+
+	graphStepsPageSize = 500
 )
 
 type WorkflowsHandler struct {
-	workflowsRepo  contract.WorkflowsRepository
-	permissionsSrv contract.PermissionsService
+	workflowsRepo    contract.WorkflowsRepository
+	workflowsUseCase contract.WorkflowsUseCase
+	workflowStats    contract.WorkflowStatsUseCase
+	permissionsSrv   contract.PermissionsService
+	redactor         contract.PayloadRedactor
 }
 
 func NewWorkflowsHandler(
 	workflowsRepo contract.WorkflowsRepository,
+	workflowsUseCase contract.WorkflowsUseCase,
+	workflowStats contract.WorkflowStatsUseCase,
 	permissionsSrv contract.PermissionsService,
+	redactor contract.PayloadRedactor,
 ) *WorkflowsHandler {
 	return &WorkflowsHandler{
-		workflowsRepo:  workflowsRepo,
-		permissionsSrv: permissionsSrv,
+		workflowsRepo:    workflowsRepo,
+		workflowsUseCase: workflowsUseCase,
+		workflowStats:    workflowStats,
+		permissionsSrv:   permissionsSrv,
+		redactor:         redactor,
 	}
 }
 
 // ListWorkflows handles GET /api/v1/workflows
 func (h *WorkflowsHandler) ListWorkflows(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -41,7 +65,7 @@ func (h *WorkflowsHandler) ListWorkflows(w http.ResponseWriter, r *http.Request)
 
 	tenantID, projectID, err := parseTenantAndProject(r)
 	if err != nil {
-		slog.Warn("Invalid tenant_id or project_id in request",
+		slog.WarnContext(r.Context(), "Invalid tenant_id or project_id in request",
 			"error", err,
 			"path", r.URL.Path,
 		)
@@ -63,14 +87,14 @@ func (h *WorkflowsHandler) ListWorkflows(w http.ResponseWriter, r *http.Request)
 
 	workflowDefs, total, err := h.workflowsRepo.ListWorkflowDefinitions(r.Context(), tenantID, projectID, page, pageSize)
 	if err != nil {
-		slog.Error("Failed to list workflow definitions",
+		slog.ErrorContext(r.Context(), "Failed to list workflow definitions",
 			"error", err,
 			"tenant_id", tenantID,
 			"project_id", projectID,
 			"page", page,
 			"page_size", pageSize,
 		)
-		respondError(w, http.StatusInternalServerError, err.Error())
+		respondErr(w, err)
 		return
 	}
 
@@ -85,7 +109,7 @@ func (h *WorkflowsHandler) ListWorkflows(w http.ResponseWriter, r *http.Request)
 // GetWorkflow handles GET /api/v1/workflows/:id
 func (h *WorkflowsHandler) GetWorkflow(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -101,7 +125,7 @@ func (h *WorkflowsHandler) GetWorkflow(w http.ResponseWriter, r *http.Request) {
 
 	tenantID, projectID, err := parseTenantAndProject(r)
 	if err != nil {
-		slog.Warn("Invalid tenant_id or project_id in request",
+		slog.WarnContext(r.Context(), "Invalid tenant_id or project_id in request",
 			"error", err,
 			"workflow_id", id,
 			"path", r.URL.Path,
@@ -123,7 +147,7 @@ func (h *WorkflowsHandler) GetWorkflow(w http.ResponseWriter, r *http.Request) {
 	workflow, err := h.workflowsRepo.GetWorkflowDefinition(r.Context(), tenantID, projectID, id)
 	if err != nil {
 		if errors.Is(err, domain.ErrEntityNotFound) {
-			slog.Warn("Workflow not found",
+			slog.WarnContext(r.Context(), "Workflow not found",
 				"workflow_id", id,
 				"tenant_id", tenantID,
 				"project_id", projectID,
@@ -131,23 +155,23 @@ func (h *WorkflowsHandler) GetWorkflow(w http.ResponseWriter, r *http.Request) {
 			respondError(w, http.StatusNotFound, "Workflow not found")
 			return
 		}
-		slog.Error("Failed to get workflow definition",
+		slog.ErrorContext(r.Context(), "Failed to get workflow definition",
 			"error", err,
 			"workflow_id", id,
 			"tenant_id", tenantID,
 			"project_id", projectID,
 		)
-		respondError(w, http.StatusInternalServerError, err.Error())
+		respondErr(w, err)
 		return
 	}
 
-	respondJSON(w, http.StatusOK, workflow)
+	respondWorkflow(w, r, http.StatusOK, workflow)
 }
 
 // ListWorkflowInstances handles GET /api/v1/workflows/:id/instances
 func (h *WorkflowsHandler) ListWorkflowInstances(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -163,7 +187,7 @@ func (h *WorkflowsHandler) ListWorkflowInstances(w http.ResponseWriter, r *http.
 
 	tenantID, projectID, err := parseTenantAndProject(r)
 	if err != nil {
-		slog.Warn("Invalid tenant_id or project_id in request",
+		slog.WarnContext(r.Context(), "Invalid tenant_id or project_id in request",
 			"error", err,
 			"workflow_id", workflowID,
 			"path", r.URL.Path,
@@ -184,16 +208,75 @@ func (h *WorkflowsHandler) ListWorkflowInstances(w http.ResponseWriter, r *http.
 
 	page, pageSize := parsePagination(r)
 
+	fields, err := parseFieldSet(r, workflowInstanceFields)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	visible, ok := canViewWorkflowData(w, r, h.permissionsSrv, projectID)
+	if !ok {
+		return
+	}
+
+	if !visible {
+		fields.Exclude = append(fields.Exclude, "input", "output")
+	}
+
+	cursor, keyset, err := parseCursor(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if keyset {
+		instances, next, err := h.workflowsRepo.ListWorkflowInstancesAfter(
+			r.Context(),
+			tenantID,
+			projectID,
+			workflowID,
+			fields,
+			cursor,
+			pageSize,
+		)
+		if err != nil {
+			slog.ErrorContext(r.Context(), "Failed to list workflow instances for workflow",
+				"error", err,
+				"workflow_id", workflowID,
+				"tenant_id", tenantID,
+				"project_id", projectID,
+				"page_size", pageSize,
+			)
+			respondErr(w, err)
+			return
+		}
+
+		if err := h.redactor.RedactInstances(r.Context(), instances); err != nil {
+			respondRedactionError(w, r, err)
+			return
+		}
+
+		items, err := sparseItems(instances, fields)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to encode workflow instances")
+			return
+		}
+
+		respondJSON(w, http.StatusOK, cursorPage(items, pageSize, next))
+		return
+	}
+
 	instances, total, err := h.workflowsRepo.ListWorkflowInstances(
 		r.Context(),
 		tenantID,
 		projectID,
 		workflowID,
+		fields,
 		page,
 		pageSize,
 	)
 	if err != nil {
-		slog.Error("Failed to list workflow instances for workflow",
+		slog.ErrorContext(r.Context(), "Failed to list workflow instances for workflow",
 			"error", err,
 			"workflow_id", workflowID,
 			"tenant_id", tenantID,
@@ -201,22 +284,114 @@ func (h *WorkflowsHandler) ListWorkflowInstances(w http.ResponseWriter, r *http.
 			"page", page,
 			"page_size", pageSize,
 		)
-		respondError(w, http.StatusInternalServerError, err.Error())
+		respondErr(w, err)
+		return
+	}
+
+	if err := h.redactor.RedactInstances(r.Context(), instances); err != nil {
+		respondRedactionError(w, r, err)
+		return
+	}
+
+	items, err := sparseItems(instances, fields)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to encode workflow instances")
 		return
 	}
 
 	respondJSON(w, http.StatusOK, map[string]interface{}{
-		"items":     instances,
+		"items":     items,
 		"page":      page,
 		"page_size": pageSize,
 		"total":     total,
 	})
 }
 
+// ListWorkflowFailures handles GET /api/v1/workflows/:id/failures?since=<RFC3339>&limit=N.
+// Failed steps are grouped by step name and error signature; the window defaults to the last 30 days.
+func (h *WorkflowsHandler) ListWorkflowFailures(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !requireAuthForWorkflows(w, r) {
+		return
+	}
+
+	workflowID := appcontext.Param(r.Context(), "id")
+	if workflowID == "" {
+		respondError(w, http.StatusBadRequest, "Invalid workflow ID")
+		return
+	}
+
+	tenantID, projectID, err := parseTenantAndProject(r)
+	if err != nil {
+		slog.WarnContext(r.Context(), "Invalid tenant_id or project_id in request",
+			"error", err,
+			"workflow_id", workflowID,
+			"path", r.URL.Path,
+		)
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Check if user has permission to view this project
+	if err := h.permissionsSrv.CanViewProject(r.Context(), projectID); err != nil {
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			respondError(w, http.StatusForbidden, "Access denied to this project")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to verify permissions")
+		return
+	}
+
+	since := time.Now().Add(-defaultFailuresWindow)
+	if v := r.URL.Query().Get("since"); v != "" {
+		since, err = time.Parse(time.RFC3339, v)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "since must be an RFC3339 timestamp")
+			return
+		}
+	}
+
+	limit := defaultFailuresLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit <= 0 || limit > maxFailuresLimit {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", maxFailuresLimit))
+			return
+		}
+	}
+
+	groups, err := h.workflowsRepo.ListWorkflowFailures(r.Context(), tenantID, projectID, workflowID, since, limit)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to list workflow failures",
+			"error", err,
+			"workflow_id", workflowID,
+			"tenant_id", tenantID,
+			"project_id", projectID,
+		)
+		respondErr(w, err)
+		return
+	}
+
+	totalFailures := 0
+	for _, group := range groups {
+		totalFailures += group.Failures
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"items":          groups,
+		"since":          since,
+		"total_failures": totalFailures,
+	})
+}
+
 // ListInstances handles GET /api/v1/instances
 func (h *WorkflowsHandler) ListInstances(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -226,7 +401,7 @@ func (h *WorkflowsHandler) ListInstances(w http.ResponseWriter, r *http.Request)
 
 	tenantID, projectID, err := parseTenantAndProject(r)
 	if err != nil {
-		slog.Warn("Invalid tenant_id or project_id in request",
+		slog.WarnContext(r.Context(), "Invalid tenant_id or project_id in request",
 			"error", err,
 			"path", r.URL.Path,
 		)
@@ -246,38 +421,109 @@ func (h *WorkflowsHandler) ListInstances(w http.ResponseWriter, r *http.Request)
 
 	page, pageSize := parsePagination(r)
 
+	fields, err := parseFieldSet(r, workflowInstanceFields)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	visible, ok := canViewWorkflowData(w, r, h.permissionsSrv, projectID)
+	if !ok {
+		return
+	}
+
+	if !visible {
+		fields.Exclude = append(fields.Exclude, "input", "output")
+	}
+
+	cursor, keyset, err := parseCursor(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if keyset {
+		instances, next, err := h.workflowsRepo.ListWorkflowInstancesAfter(
+			r.Context(),
+			tenantID,
+			projectID,
+			"",
+			fields,
+			cursor,
+			pageSize,
+		)
+		if err != nil {
+			slog.ErrorContext(r.Context(), "Failed to list workflow instances",
+				"error", err,
+				"tenant_id", tenantID,
+				"project_id", projectID,
+				"page_size", pageSize,
+			)
+			respondErr(w, err)
+			return
+		}
+
+		if err := h.redactor.RedactInstances(r.Context(), instances); err != nil {
+			respondRedactionError(w, r, err)
+			return
+		}
+
+		items, err := sparseItems(instances, fields)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to encode workflow instances")
+			return
+		}
+
+		respondJSON(w, http.StatusOK, cursorPage(items, pageSize, next))
+		return
+	}
+
 	instances, total, err := h.workflowsRepo.ListWorkflowInstances(
 		r.Context(),
 		tenantID,
 		projectID,
 		"",
+		fields,
 		page,
 		pageSize,
 	)
 	if err != nil {
-		slog.Error("Failed to list workflow instances",
+		slog.ErrorContext(r.Context(), "Failed to list workflow instances",
 			"error", err,
 			"tenant_id", tenantID,
 			"project_id", projectID,
 			"page", page,
 			"page_size", pageSize,
 		)
-		respondError(w, http.StatusInternalServerError, err.Error())
+		respondErr(w, err)
+		return
+	}
+
+	if err := h.redactor.RedactInstances(r.Context(), instances); err != nil {
+		respondRedactionError(w, r, err)
+		return
+	}
+
+	items, err := sparseItems(instances, fields)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to encode workflow instances")
 		return
 	}
 
 	respondJSON(w, http.StatusOK, map[string]interface{}{
-		"items":     instances,
+		"items":     items,
 		"page":      page,
 		"page_size": pageSize,
 		"total":     total,
 	})
 }
 
-// ListActiveWorkflows handles GET /api/v1/active-workflows
-func (h *WorkflowsHandler) ListActiveWorkflows(w http.ResponseWriter, r *http.Request) {
+// SearchWorkflows handles GET /api/v1/workflow-search
+// Finds workflow definitions having a step with the given step_type and handler whose name,
+// keys or values contain the q text. Results list the matching steps.
+func (h *WorkflowsHandler) SearchWorkflows(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -287,7 +533,7 @@ func (h *WorkflowsHandler) ListActiveWorkflows(w http.ResponseWriter, r *http.Re
 
 	tenantID, projectID, err := parseTenantAndProject(r)
 	if err != nil {
-		slog.Warn("Invalid tenant_id or project_id in request",
+		slog.WarnContext(r.Context(), "Invalid tenant_id or project_id in request",
 			"error", err,
 			"path", r.URL.Path,
 		)
@@ -305,39 +551,66 @@ func (h *WorkflowsHandler) ListActiveWorkflows(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	query := domain.WorkflowDefinitionSearchQuery{
+		StepType: r.URL.Query().Get("step_type"),
+		Handler:  r.URL.Query().Get("handler"),
+		Text:     r.URL.Query().Get("q"),
+	}
+	if err := definitionsearch.ValidateQuery(&query); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	page, pageSize := parsePagination(r)
 
-	activeWorkflows, total, err := h.workflowsRepo.ListActiveWorkflows(
+	workflows, total, err := h.workflowsRepo.SearchWorkflowDefinitions(
 		r.Context(),
 		tenantID,
 		projectID,
+		query,
 		page,
 		pageSize,
 	)
 	if err != nil {
-		slog.Error("Failed to list active workflows",
+		slog.ErrorContext(r.Context(), "Failed to search workflow definitions",
 			"error", err,
 			"tenant_id", tenantID,
 			"project_id", projectID,
-			"page", page,
-			"page_size", pageSize,
 		)
-		respondError(w, http.StatusInternalServerError, err.Error())
+		respondErr(w, err)
 		return
 	}
 
+	items := make([]domain.WorkflowDefinitionSearchResult, 0, len(workflows))
+	for i := range workflows {
+		steps, err := definitionsearch.MatchSteps(workflows[i].Definition, query)
+		if err != nil {
+			slog.WarnContext(r.Context(), "Failed to match workflow definition steps",
+				"error", err,
+				"workflow_id", workflows[i].ID,
+			)
+		}
+
+		items = append(items, domain.WorkflowDefinitionSearchResult{
+			Workflow: workflows[i],
+			Steps:    steps,
+		})
+	}
+
 	respondJSON(w, http.StatusOK, map[string]interface{}{
-		"items":     activeWorkflows,
+		"items":     items,
 		"page":      page,
 		"page_size": pageSize,
 		"total":     total,
 	})
 }
 
-// GetInstance handles GET /api/v1/instances/:id
-func (h *WorkflowsHandler) GetInstance(w http.ResponseWriter, r *http.Request) {
+// SearchInstances handles GET /api/v1/instance-search
+// The q parameter is either a JSON document the instance input or output must contain
+// or a text searched in the input, output and error. Results carry the matching paths.
+func (h *WorkflowsHandler) SearchInstances(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -345,26 +618,10 @@ func (h *WorkflowsHandler) GetInstance(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	idStr := appcontext.Param(r.Context(), "id")
-	if idStr == "" {
-		respondError(w, http.StatusBadRequest, "Invalid instance ID")
-		return
-	}
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
-		slog.Warn("Invalid instance ID",
-			"instance_id", idStr,
-			"error", err,
-		)
-		respondError(w, http.StatusBadRequest, "Invalid instance ID")
-		return
-	}
-
 	tenantID, projectID, err := parseTenantAndProject(r)
 	if err != nil {
-		slog.Warn("Invalid tenant_id or project_id in request",
+		slog.WarnContext(r.Context(), "Invalid tenant_id or project_id in request",
 			"error", err,
-			"instance_id", id,
 			"path", r.URL.Path,
 		)
 		respondError(w, http.StatusBadRequest, err.Error())
@@ -381,61 +638,90 @@ func (h *WorkflowsHandler) GetInstance(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	instance, err := h.workflowsRepo.GetWorkflowInstance(r.Context(), tenantID, projectID, id)
+	// The matches quote the payloads, searching them requires seeing them
+	visible, ok := canViewWorkflowData(w, r, h.permissionsSrv, projectID)
+	if !ok {
+		return
+	}
+
+	if !visible {
+		respondError(w, http.StatusForbidden, "Access denied to workflow data of this project")
+		return
+	}
+
+	query, err := instancesearch.ParseQuery(r.URL.Query().Get("q"))
 	if err != nil {
-		if errors.Is(err, domain.ErrEntityNotFound) {
-			slog.Warn("Instance not found",
-				"instance_id", id,
-				"tenant_id", tenantID,
-				"project_id", projectID,
-			)
-			respondError(w, http.StatusNotFound, "Instance not found")
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Matching the raw payloads would tell which redacted values a query hits
+	query.Excluded, err = h.redactor.SearchExclusions(r.Context(), projectID)
+	if err != nil {
+		if errors.Is(err, domain.ErrPayloadNotSearchable) {
+			respondError(w, http.StatusForbidden, err.Error())
 			return
 		}
-		slog.Error("Failed to get workflow instance",
+		respondRedactionError(w, r, err)
+		return
+	}
+
+	page, pageSize := parsePagination(r)
+
+	instances, total, err := h.workflowsRepo.SearchWorkflowInstances(
+		r.Context(),
+		tenantID,
+		projectID,
+		query,
+		page,
+		pageSize,
+	)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to search workflow instances",
 			"error", err,
-			"instance_id", id,
 			"tenant_id", tenantID,
 			"project_id", projectID,
 		)
-		respondError(w, http.StatusInternalServerError, err.Error())
+		respondErr(w, err)
 		return
 	}
 
-	respondJSON(w, http.StatusOK, instance)
-}
-
-// ListInstanceSteps handles GET /api/v1/instances/:id/steps
-func (h *WorkflowsHandler) ListInstanceSteps(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	if err := h.redactor.RedactInstances(r.Context(), instances); err != nil {
+		respondRedactionError(w, r, err)
 		return
 	}
 
-	if !requireAuthForWorkflows(w, r) {
-		return
+	items := make([]domain.InstanceSearchResult, 0, len(instances))
+	for i := range instances {
+		items = append(items, domain.InstanceSearchResult{
+			Instance: instances[i],
+			Matches:  instancesearch.Highlight(&instances[i], query),
+		})
 	}
 
-	idStr := appcontext.Param(r.Context(), "id")
-	if idStr == "" {
-		respondError(w, http.StatusBadRequest, "Invalid instance ID")
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"items":     items,
+		"page":      page,
+		"page_size": pageSize,
+		"total":     total,
+	})
+}
+
+// ListActiveWorkflows handles GET /api/v1/active-workflows
+func (h *WorkflowsHandler) ListActiveWorkflows(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
-		slog.Warn("Invalid instance ID",
-			"instance_id", idStr,
-			"error", err,
-		)
-		respondError(w, http.StatusBadRequest, "Invalid instance ID")
+
+	if !requireAuthForWorkflows(w, r) {
 		return
 	}
 
 	tenantID, projectID, err := parseTenantAndProject(r)
 	if err != nil {
-		slog.Warn("Invalid tenant_id or project_id in request",
+		slog.WarnContext(r.Context(), "Invalid tenant_id or project_id in request",
 			"error", err,
-			"instance_id", id,
 			"path", r.URL.Path,
 		)
 		respondError(w, http.StatusBadRequest, err.Error())
@@ -454,32 +740,37 @@ func (h *WorkflowsHandler) ListInstanceSteps(w http.ResponseWriter, r *http.Requ
 
 	page, pageSize := parsePagination(r)
 
-	steps, total, err := h.workflowsRepo.ListWorkflowSteps(r.Context(), tenantID, projectID, id, page, pageSize)
+	activeWorkflows, total, err := h.workflowsRepo.ListActiveWorkflows(
+		r.Context(),
+		tenantID,
+		projectID,
+		page,
+		pageSize,
+	)
 	if err != nil {
-		slog.Error("Failed to list workflow steps",
+		slog.ErrorContext(r.Context(), "Failed to list active workflows",
 			"error", err,
-			"instance_id", id,
 			"tenant_id", tenantID,
 			"project_id", projectID,
 			"page", page,
 			"page_size", pageSize,
 		)
-		respondError(w, http.StatusInternalServerError, err.Error())
+		respondErr(w, err)
 		return
 	}
 
 	respondJSON(w, http.StatusOK, map[string]interface{}{
-		"items":     steps,
+		"items":     activeWorkflows,
 		"page":      page,
 		"page_size": pageSize,
 		"total":     total,
 	})
 }
 
-// ListInstanceEvents handles GET /api/v1/instances/:id/events
-func (h *WorkflowsHandler) ListInstanceEvents(w http.ResponseWriter, r *http.Request) {
+// GetInstance handles GET /api/v1/instances/:id
+func (h *WorkflowsHandler) GetInstance(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -494,7 +785,7 @@ func (h *WorkflowsHandler) ListInstanceEvents(w http.ResponseWriter, r *http.Req
 	}
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		slog.Warn("Invalid instance ID",
+		slog.WarnContext(r.Context(), "Invalid instance ID",
 			"instance_id", idStr,
 			"error", err,
 		)
@@ -504,7 +795,7 @@ func (h *WorkflowsHandler) ListInstanceEvents(w http.ResponseWriter, r *http.Req
 
 	tenantID, projectID, err := parseTenantAndProject(r)
 	if err != nil {
-		slog.Warn("Invalid tenant_id or project_id in request",
+		slog.WarnContext(r.Context(), "Invalid tenant_id or project_id in request",
 			"error", err,
 			"instance_id", id,
 			"path", r.URL.Path,
@@ -523,34 +814,45 @@ func (h *WorkflowsHandler) ListInstanceEvents(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	page, pageSize := parsePagination(r)
-
-	events, total, err := h.workflowsRepo.ListWorkflowEvents(r.Context(), tenantID, projectID, id, page, pageSize)
+	instance, err := h.workflowsRepo.GetWorkflowInstance(r.Context(), tenantID, projectID, id)
 	if err != nil {
-		slog.Error("Failed to list workflow events",
-			"error", err,
-			"instance_id", id,
-			"tenant_id", tenantID,
-			"project_id", projectID,
-			"page", page,
-			"page_size", pageSize,
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			slog.WarnContext(r.Context(), "Instance not found",
+				"instance_id", id,
+				"tenant_id", tenantID,
+				"project_id", projectID,
+			)
+			respondError(w, http.StatusNotFound, "Instance not found")
+			return
+		}
+		slog.ErrorContext(r.Context(), "Failed to get workflow instance",
+			"error", err,
+			"instance_id", id,
+			"tenant_id", tenantID,
+			"project_id", projectID,
 		)
-		respondError(w, http.StatusInternalServerError, err.Error())
+		respondErr(w, err)
 		return
 	}
 
-	respondJSON(w, http.StatusOK, map[string]interface{}{
-		"items":     events,
-		"page":      page,
-		"page_size": pageSize,
-		"total":     total,
-	})
+	instances := []domain.WorkflowInstance{instance}
+	if err := h.redactor.RedactInstances(r.Context(), instances); err != nil {
+		respondRedactionError(w, r, err)
+		return
+	}
+
+	if !omitWorkflowData(w, r, h.permissionsSrv, projectID, instances) {
+		return
+	}
+
+	respondJSON(w, http.StatusOK, instances[0])
 }
 
-// ListStats handles GET /api/v1/stats
-func (h *WorkflowsHandler) ListStats(w http.ResponseWriter, r *http.Request) {
+// GetInstanceGraph handles GET /api/v1/instances/:id/graph. It returns the definition DAG
+// annotated with status, timings, retries and compensation state of each step.
+func (h *WorkflowsHandler) GetInstanceGraph(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -558,10 +860,17 @@ func (h *WorkflowsHandler) ListStats(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	id, err := strconv.Atoi(appcontext.Param(r.Context(), "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid instance ID")
+		return
+	}
+
 	tenantID, projectID, err := parseTenantAndProject(r)
 	if err != nil {
-		slog.Warn("Invalid tenant_id or project_id in request",
+		slog.WarnContext(r.Context(), "Invalid tenant_id or project_id in request",
 			"error", err,
+			"instance_id", id,
 			"path", r.URL.Path,
 		)
 		respondError(w, http.StatusBadRequest, err.Error())
@@ -578,33 +887,91 @@ func (h *WorkflowsHandler) ListStats(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	page, pageSize := parsePagination(r)
+	instance, err := h.workflowsRepo.GetWorkflowInstance(r.Context(), tenantID, projectID, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			respondError(w, http.StatusNotFound, "Instance not found")
+			return
+		}
+		slog.ErrorContext(r.Context(), "Failed to get workflow instance",
+			"error", err,
+			"instance_id", id,
+		)
+		respondErr(w, err)
+		return
+	}
 
-	stats, total, err := h.workflowsRepo.ListWorkflowStats(r.Context(), tenantID, projectID, page, pageSize)
+	// The graph of an instance whose definition was deleted has the executed steps only
+	var definition json.RawMessage
+	workflow, err := h.workflowsRepo.GetWorkflowDefinition(r.Context(), tenantID, projectID, instance.WorkflowID)
+	switch {
+	case err == nil:
+		definition = workflow.Definition
+	case !errors.Is(err, domain.ErrEntityNotFound):
+		slog.ErrorContext(r.Context(), "Failed to get workflow definition",
+			"error", err,
+			"workflow_id", instance.WorkflowID,
+		)
+		respondErr(w, err)
+		return
+	}
+
+	steps, err := h.listAllInstanceSteps(r, tenantID, projectID, id)
 	if err != nil {
-		slog.Error("Failed to list workflow stats",
+		slog.ErrorContext(r.Context(), "Failed to list workflow steps",
 			"error", err,
-			"tenant_id", tenantID,
-			"project_id", projectID,
-			"page", page,
-			"page_size", pageSize,
+			"instance_id", id,
 		)
-		respondError(w, http.StatusInternalServerError, err.Error())
+		respondErr(w, err)
 		return
 	}
 
-	respondJSON(w, http.StatusOK, map[string]interface{}{
-		"items":     stats,
-		"page":      page,
-		"page_size": pageSize,
-		"total":     total,
-	})
+	if err := h.redactor.RedactSteps(r.Context(), steps); err != nil {
+		respondRedactionError(w, r, err)
+		return
+	}
+
+	graph, err := instancegraph.Build(definition, instance, steps)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to build instance graph",
+			"error", err,
+			"instance_id", id,
+		)
+		respondError(w, http.StatusInternalServerError, "Failed to build instance graph")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, graph)
 }
 
-// ListDLQ handles GET /api/v1/dlq
-func (h *WorkflowsHandler) ListDLQ(w http.ResponseWriter, r *http.Request) {
+// listAllInstanceSteps loads all steps of the instance without their payloads.
+func (h *WorkflowsHandler) listAllInstanceSteps(
+	r *http.Request,
+	tenantID domain.TenantID,
+	projectID domain.ProjectID,
+	instanceID int,
+) ([]domain.WorkflowStep, error) {
+	fields := domain.FieldSet{Exclude: []string{"input", "output"}}
+
+	var steps []domain.WorkflowStep
+	for page := 1; ; page++ {
+		items, total, err := h.workflowsRepo.ListWorkflowSteps(r.Context(), tenantID, projectID, instanceID,
+			fields, page, graphStepsPageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		steps = append(steps, items...)
+		if len(items) < graphStepsPageSize || len(steps) >= total {
+			return steps, nil
+		}
+	}
+}
+
+// ListInstanceSteps handles GET /api/v1/instances/:id/steps
+func (h *WorkflowsHandler) ListInstanceSteps(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -612,10 +979,26 @@ func (h *WorkflowsHandler) ListDLQ(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	idStr := appcontext.Param(r.Context(), "id")
+	if idStr == "" {
+		respondError(w, http.StatusBadRequest, "Invalid instance ID")
+		return
+	}
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		slog.WarnContext(r.Context(), "Invalid instance ID",
+			"instance_id", idStr,
+			"error", err,
+		)
+		respondError(w, http.StatusBadRequest, "Invalid instance ID")
+		return
+	}
+
 	tenantID, projectID, err := parseTenantAndProject(r)
 	if err != nil {
-		slog.Warn("Invalid tenant_id or project_id in request",
+		slog.WarnContext(r.Context(), "Invalid tenant_id or project_id in request",
 			"error", err,
+			"instance_id", id,
 			"path", r.URL.Path,
 		)
 		respondError(w, http.StatusBadRequest, err.Error())
@@ -634,16 +1017,43 @@ func (h *WorkflowsHandler) ListDLQ(w http.ResponseWriter, r *http.Request) {
 
 	page, pageSize := parsePagination(r)
 
-	items, total, err := h.workflowsRepo.ListDLQItems(r.Context(), tenantID, projectID, page, pageSize)
+	fields, err := parseFieldSet(r, workflowStepFields)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	visible, ok := canViewWorkflowData(w, r, h.permissionsSrv, projectID)
+	if !ok {
+		return
+	}
+
+	if !visible {
+		fields.Exclude = append(fields.Exclude, "input", "output")
+	}
+
+	steps, total, err := h.workflowsRepo.ListWorkflowSteps(r.Context(), tenantID, projectID, id, fields, page, pageSize)
 	if err != nil {
-		slog.Error("Failed to list DLQ items",
+		slog.ErrorContext(r.Context(), "Failed to list workflow steps",
 			"error", err,
+			"instance_id", id,
 			"tenant_id", tenantID,
 			"project_id", projectID,
 			"page", page,
 			"page_size", pageSize,
 		)
-		respondError(w, http.StatusInternalServerError, err.Error())
+		respondErr(w, err)
+		return
+	}
+
+	if err := h.redactor.RedactSteps(r.Context(), steps); err != nil {
+		respondRedactionError(w, r, err)
+		return
+	}
+
+	items, err := sparseItems(steps, fields)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to encode workflow steps")
 		return
 	}
 
@@ -655,10 +1065,10 @@ func (h *WorkflowsHandler) ListDLQ(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// GetDLQItem handles GET /api/v1/dlq/:id
-func (h *WorkflowsHandler) GetDLQItem(w http.ResponseWriter, r *http.Request) {
+// ListInstanceEvents handles GET /api/v1/instances/:id/events
+func (h *WorkflowsHandler) ListInstanceEvents(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -668,24 +1078,24 @@ func (h *WorkflowsHandler) GetDLQItem(w http.ResponseWriter, r *http.Request) {
 
 	idStr := appcontext.Param(r.Context(), "id")
 	if idStr == "" {
-		respondError(w, http.StatusBadRequest, "Invalid DLQ item ID")
+		respondError(w, http.StatusBadRequest, "Invalid instance ID")
 		return
 	}
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		slog.Warn("Invalid DLQ item ID",
-			"dlq_item_id", idStr,
+		slog.WarnContext(r.Context(), "Invalid instance ID",
+			"instance_id", idStr,
 			"error", err,
 		)
-		respondError(w, http.StatusBadRequest, "Invalid DLQ item ID")
+		respondError(w, http.StatusBadRequest, "Invalid instance ID")
 		return
 	}
 
 	tenantID, projectID, err := parseTenantAndProject(r)
 	if err != nil {
-		slog.Warn("Invalid tenant_id or project_id in request",
+		slog.WarnContext(r.Context(), "Invalid tenant_id or project_id in request",
 			"error", err,
-			"dlq_item_id", id,
+			"instance_id", id,
 			"path", r.URL.Path,
 		)
 		respondError(w, http.StatusBadRequest, err.Error())
@@ -702,76 +1112,83 @@ func (h *WorkflowsHandler) GetDLQItem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	item, err := h.workflowsRepo.GetDLQItem(r.Context(), tenantID, projectID, id)
+	page, pageSize := parsePagination(r)
+
+	cursor, keyset, err := parseCursor(r)
 	if err != nil {
-		if errors.Is(err, domain.ErrEntityNotFound) {
-			slog.Warn("DLQ item not found",
-				"dlq_item_id", id,
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if keyset {
+		events, next, err := h.workflowsRepo.ListWorkflowEventsAfter(
+			r.Context(),
+			tenantID,
+			projectID,
+			id,
+			cursor,
+			pageSize,
+		)
+		if err != nil {
+			slog.ErrorContext(r.Context(), "Failed to list workflow events",
+				"error", err,
+				"instance_id", id,
 				"tenant_id", tenantID,
 				"project_id", projectID,
+				"page_size", pageSize,
 			)
-			respondError(w, http.StatusNotFound, "DLQ item not found")
+			respondErr(w, err)
 			return
 		}
-		slog.Error("Failed to get DLQ item",
-			"error", err,
-			"dlq_item_id", id,
-			"tenant_id", tenantID,
-			"project_id", projectID,
-		)
-		respondError(w, http.StatusInternalServerError, err.Error())
-		return
-	}
 
-	respondJSON(w, http.StatusOK, item)
-}
+		if err := h.redactor.RedactEvents(r.Context(), events); err != nil {
+			respondRedactionError(w, r, err)
+			return
+		}
 
-// ListUnassignedWorkflows handles GET /api/v1/workflows/unassigned
-func (h *WorkflowsHandler) ListUnassignedWorkflows(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
+		if !omitWorkflowData(w, r, h.permissionsSrv, projectID, events) {
+			return
+		}
 
-	if !requireAuthForWorkflows(w, r) {
+		respondJSON(w, http.StatusOK, cursorPage(events, pageSize, next))
 		return
 	}
 
-	// Check if user is superuser or has project management permission
-	// Superusers can view all unassigned workflows
-	// Project managers can also view unassigned workflows to assign them to their projects
-	isSuper := appcontext.IsSuper(r.Context())
-	if !isSuper {
-		// For non-superusers, we still allow viewing unassigned workflows
-		// as they may need to assign them to their projects
-		// The actual assignment will be checked by CanManageProject in AssignWorkflowsToProject
-	}
-
-	page, pageSize := parsePagination(r)
-
-	workflowDefs, total, err := h.workflowsRepo.ListUnassignedWorkflowDefinitions(r.Context(), page, pageSize)
+	events, total, err := h.workflowsRepo.ListWorkflowEvents(r.Context(), tenantID, projectID, id, page, pageSize)
 	if err != nil {
-		slog.Error("Failed to list unassigned workflow definitions",
+		slog.ErrorContext(r.Context(), "Failed to list workflow events",
 			"error", err,
+			"instance_id", id,
+			"tenant_id", tenantID,
+			"project_id", projectID,
 			"page", page,
 			"page_size", pageSize,
 		)
-		respondError(w, http.StatusInternalServerError, err.Error())
+		respondErr(w, err)
+		return
+	}
+
+	if err := h.redactor.RedactEvents(r.Context(), events); err != nil {
+		respondRedactionError(w, r, err)
+		return
+	}
+
+	if !omitWorkflowData(w, r, h.permissionsSrv, projectID, events) {
 		return
 	}
 
 	respondJSON(w, http.StatusOK, map[string]interface{}{
-		"items":     workflowDefs,
+		"items":     events,
 		"page":      page,
 		"page_size": pageSize,
 		"total":     total,
 	})
 }
 
-// AssignWorkflowsToProject handles POST /api/v1/projects/:id/workflows/assign
-func (h *WorkflowsHandler) AssignWorkflowsToProject(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+// ListStats handles GET /api/v1/stats
+func (h *WorkflowsHandler) ListStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -779,66 +1196,84 @@ func (h *WorkflowsHandler) AssignWorkflowsToProject(w http.ResponseWriter, r *ht
 		return
 	}
 
-	// Get project ID from URL params
-	projectIDStr := appcontext.Param(r.Context(), "id")
-	if projectIDStr == "" {
-		respondError(w, http.StatusBadRequest, "project id is required")
-		return
-	}
-
-	projectIDInt, err := strconv.Atoi(projectIDStr)
+	tenantID, projectID, err := parseTenantAndProject(r)
 	if err != nil {
-		respondError(w, http.StatusBadRequest, "invalid project id")
+		slog.WarnContext(r.Context(), "Invalid tenant_id or project_id in request",
+			"error", err,
+			"path", r.URL.Path,
+		)
+		respondError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	projectID := domain.ProjectID(projectIDInt)
-
-	// Check if user has permission to manage this project
-	if err := h.permissionsSrv.CanManageProject(r.Context(), projectID); err != nil {
+	// Check if user has permission to view this project
+	if err := h.permissionsSrv.CanViewProject(r.Context(), projectID); err != nil {
 		if errors.Is(err, domain.ErrPermissionDenied) {
-			respondError(w, http.StatusForbidden, "Access denied to manage this project")
+			respondError(w, http.StatusForbidden, "Access denied to this project")
 			return
 		}
 		respondError(w, http.StatusInternalServerError, "Failed to verify permissions")
 		return
 	}
 
-	var req struct {
-		WorkflowIDs []string `json:"workflow_ids"`
-		// If workflow_ids is empty, assign all unassigned workflows
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
-		return
-	}
+	page, pageSize := parsePagination(r)
 
-	assignedCount, err := h.workflowsRepo.AssignWorkflowDefinitionsToProject(
-		r.Context(),
-		projectID,
-		req.WorkflowIDs,
-	)
+	stats, total, err := h.workflowsRepo.ListWorkflowStats(r.Context(), tenantID, projectID, page, pageSize)
 	if err != nil {
-		slog.Error("Failed to assign workflow definitions to project",
+		slog.ErrorContext(r.Context(), "Failed to list workflow stats",
 			"error", err,
+			"tenant_id", tenantID,
 			"project_id", projectID,
-			"workflow_ids", req.WorkflowIDs,
+			"page", page,
+			"page_size", pageSize,
 		)
-		respondError(w, http.StatusInternalServerError, err.Error())
+		respondErr(w, err)
+		return
+	}
+
+	refreshedAt, err := h.workflowStats.RefreshedAt(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to get workflow stats refresh time", "error", err)
+		respondError(w, http.StatusInternalServerError, "Failed to get workflow stats refresh time")
 		return
 	}
 
 	respondJSON(w, http.StatusOK, map[string]interface{}{
-		"message":        "Workflow definitions assigned successfully",
-		"assigned_count": assignedCount,
+		"items":        stats,
+		"page":         page,
+		"page_size":    pageSize,
+		"total":        total,
+		"refreshed_at": refreshedAt,
 	})
 }
 
-// CreateWorkflow handles POST /api/v1/workflows
-func (h *WorkflowsHandler) CreateWorkflow(w http.ResponseWriter, r *http.Request) {
+// RefreshStats handles POST /api/v1/stats/refresh
+func (h *WorkflowsHandler) RefreshStats(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !requireAuthForWorkflows(w, r) {
+		return
+	}
+
+	refreshedAt, err := h.workflowStats.Refresh(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to refresh workflow stats", "error", err)
+		respondError(w, http.StatusInternalServerError, "Failed to refresh workflow stats")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"refreshed_at": refreshedAt,
+	})
+}
+
+// ListDLQ handles GET /api/v1/dlq
+func (h *WorkflowsHandler) ListDLQ(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -848,7 +1283,7 @@ func (h *WorkflowsHandler) CreateWorkflow(w http.ResponseWriter, r *http.Request
 
 	tenantID, projectID, err := parseTenantAndProject(r)
 	if err != nil {
-		slog.Warn("Invalid tenant_id or project_id in request",
+		slog.WarnContext(r.Context(), "Invalid tenant_id or project_id in request",
 			"error", err,
 			"path", r.URL.Path,
 		)
@@ -856,39 +1291,306 @@ func (h *WorkflowsHandler) CreateWorkflow(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	// Check if user has permission to create workflows in this project
-	if err := h.permissionsSrv.CanCreateWorkflow(r.Context(), projectID); err != nil {
+	// Check if user has permission to view this project
+	if err := h.permissionsSrv.CanViewProject(r.Context(), projectID); err != nil {
 		if errors.Is(err, domain.ErrPermissionDenied) {
-			respondError(w, http.StatusForbidden, "Access denied to create workflows in this project")
+			respondError(w, http.StatusForbidden, "Access denied to this project")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to verify permissions")
+		return
+	}
+
+	page, pageSize := parsePagination(r)
+
+	cursor, keyset, err := parseCursor(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if keyset {
+		items, next, err := h.workflowsRepo.ListDLQItemsAfter(r.Context(), tenantID, projectID, cursor, pageSize)
+		if err != nil {
+			slog.ErrorContext(r.Context(), "Failed to list DLQ items",
+				"error", err,
+				"tenant_id", tenantID,
+				"project_id", projectID,
+				"page_size", pageSize,
+			)
+			respondErr(w, err)
+			return
+		}
+
+		if err := h.redactor.RedactDLQItems(r.Context(), items); err != nil {
+			respondRedactionError(w, r, err)
+			return
+		}
+
+		if !omitWorkflowData(w, r, h.permissionsSrv, projectID, items) {
+			return
+		}
+
+		respondJSON(w, http.StatusOK, cursorPage(items, pageSize, next))
+		return
+	}
+
+	items, total, err := h.workflowsRepo.ListDLQItems(r.Context(), tenantID, projectID, page, pageSize)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to list DLQ items",
+			"error", err,
+			"tenant_id", tenantID,
+			"project_id", projectID,
+			"page", page,
+			"page_size", pageSize,
+		)
+		respondErr(w, err)
+		return
+	}
+
+	if err := h.redactor.RedactDLQItems(r.Context(), items); err != nil {
+		respondRedactionError(w, r, err)
+		return
+	}
+
+	if !omitWorkflowData(w, r, h.permissionsSrv, projectID, items) {
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"items":     items,
+		"page":      page,
+		"page_size": pageSize,
+		"total":     total,
+	})
+}
+
+// GetDLQItem handles GET /api/v1/dlq/:id
+func (h *WorkflowsHandler) GetDLQItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !requireAuthForWorkflows(w, r) {
+		return
+	}
+
+	idStr := appcontext.Param(r.Context(), "id")
+	if idStr == "" {
+		respondError(w, http.StatusBadRequest, "Invalid DLQ item ID")
+		return
+	}
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		slog.WarnContext(r.Context(), "Invalid DLQ item ID",
+			"dlq_item_id", idStr,
+			"error", err,
+		)
+		respondError(w, http.StatusBadRequest, "Invalid DLQ item ID")
+		return
+	}
+
+	tenantID, projectID, err := parseTenantAndProject(r)
+	if err != nil {
+		slog.WarnContext(r.Context(), "Invalid tenant_id or project_id in request",
+			"error", err,
+			"dlq_item_id", id,
+			"path", r.URL.Path,
+		)
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Check if user has permission to view this project
+	if err := h.permissionsSrv.CanViewProject(r.Context(), projectID); err != nil {
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			respondError(w, http.StatusForbidden, "Access denied to this project")
 			return
 		}
 		respondError(w, http.StatusInternalServerError, "Failed to verify permissions")
 		return
 	}
 
+	item, err := h.workflowsRepo.GetDLQItem(r.Context(), tenantID, projectID, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			slog.WarnContext(r.Context(), "DLQ item not found",
+				"dlq_item_id", id,
+				"tenant_id", tenantID,
+				"project_id", projectID,
+			)
+			respondError(w, http.StatusNotFound, "DLQ item not found")
+			return
+		}
+		slog.ErrorContext(r.Context(), "Failed to get DLQ item",
+			"error", err,
+			"dlq_item_id", id,
+			"tenant_id", tenantID,
+			"project_id", projectID,
+		)
+		respondErr(w, err)
+		return
+	}
+
+	items := []domain.DLQItem{item}
+	if err := h.redactor.RedactDLQItems(r.Context(), items); err != nil {
+		respondRedactionError(w, r, err)
+		return
+	}
+
+	if !omitWorkflowData(w, r, h.permissionsSrv, projectID, items) {
+		return
+	}
+
+	respondJSON(w, http.StatusOK, items[0])
+}
+
+// ListUnassignedWorkflows handles GET /api/v1/workflows/unassigned
+func (h *WorkflowsHandler) ListUnassignedWorkflows(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !requireAuthForWorkflows(w, r) {
+		return
+	}
+
+	// Check if user is superuser or has project management permission
+	// Superusers can view all unassigned workflows
+	// Project managers can also view unassigned workflows to assign them to their projects
+	isSuper := appcontext.IsSuper(r.Context())
+	if !isSuper {
+		// For non-superusers, we still allow viewing unassigned workflows
+		// as they may need to assign them to their projects
+		// The actual assignment will be checked by CanManageProject in AssignWorkflowsToProject
+	}
+
+	page, pageSize := parsePagination(r)
+
+	workflowDefs, total, err := h.workflowsRepo.ListUnassignedWorkflowDefinitions(r.Context(), page, pageSize)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to list unassigned workflow definitions",
+			"error", err,
+			"page", page,
+			"page_size", pageSize,
+		)
+		respondErr(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"items":     workflowDefs,
+		"page":      page,
+		"page_size": pageSize,
+		"total":     total,
+	})
+}
+
+// AssignWorkflowsToProject handles POST /api/v1/projects/:id/workflows/assign
+func (h *WorkflowsHandler) AssignWorkflowsToProject(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !requireAuthForWorkflows(w, r) {
+		return
+	}
+
+	// Get project ID from URL params
+	projectIDStr := appcontext.Param(r.Context(), "id")
+	if projectIDStr == "" {
+		respondError(w, http.StatusBadRequest, "project id is required")
+		return
+	}
+
+	projectIDInt, err := strconv.Atoi(projectIDStr)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid project id")
+		return
+	}
+
+	projectID := domain.ProjectID(projectIDInt)
+
 	var req struct {
-		Name       string          `json:"name"`
-		Version    int             `json:"version"`
-		Definition json.RawMessage `json:"definition"`
+		WorkflowIDs []string `json:"workflow_ids"`
+		// If workflow_ids is empty, assign all unassigned workflows
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+	if !decodeRequest(w, r, &req) {
 		return
 	}
 
-	if req.Name == "" {
-		respondError(w, http.StatusBadRequest, "name is required")
+	assignedCount, err := h.workflowsUseCase.AssignDefinitionsToProject(
+		r.Context(),
+		projectID,
+		req.WorkflowIDs,
+	)
+	if err != nil {
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			respondError(w, http.StatusForbidden, "Access denied to manage this project")
+			return
+		}
+		slog.ErrorContext(r.Context(), "Failed to assign workflow definitions to project",
+			"error", err,
+			"project_id", projectID,
+			"workflow_ids", req.WorkflowIDs,
+		)
+		respondErr(w, err)
 		return
 	}
 
-	if req.Version <= 0 {
-		respondError(w, http.StatusBadRequest, "version must be greater than 0")
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message":        "Workflow definitions assigned successfully",
+		"assigned_count": assignedCount,
+	})
+}
+
+// CreateWorkflow handles POST /api/v1/workflows
+func (h *WorkflowsHandler) CreateWorkflow(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
-	if len(req.Definition) == 0 {
-		respondError(w, http.StatusBadRequest, "definition is required")
+	if !requireAuthForWorkflows(w, r) {
+		return
+	}
+
+	tenantID, projectID, err := parseTenantAndProject(r)
+	if err != nil {
+		slog.WarnContext(r.Context(), "Invalid tenant_id or project_id in request",
+			"error", err,
+			"path", r.URL.Path,
+		)
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Check if user has permission to create workflows in this project
+	if err := h.permissionsSrv.CanCreateWorkflow(r.Context(), projectID); err != nil {
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			respondError(w, http.StatusForbidden, "Access denied to create workflows in this project")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to verify permissions")
+		return
+	}
+
+	var req struct {
+		Name       string          `json:"name" validate:"required"`
+		Version    int             `json:"version" validate:"required,min=1"`
+		Definition json.RawMessage `json:"definition" validate:"required"`
+	}
+
+	if err := decodeWorkflowRequest(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if !validRequest(w, &req) {
 		return
 	}
 
@@ -901,20 +1603,20 @@ func (h *WorkflowsHandler) CreateWorkflow(w http.ResponseWriter, r *http.Request
 		req.Definition,
 	)
 	if err != nil {
-		slog.Error("Failed to create workflow definition",
+		slog.ErrorContext(r.Context(), "Failed to create workflow definition",
 			"error", err,
 			"name", req.Name,
 			"version", req.Version,
 			"project_id", projectID,
 		)
-		respondError(w, http.StatusInternalServerError, err.Error())
+		respondErr(w, err)
 		return
 	}
 
 	// Get the created workflow to return
 	workflow, err := h.workflowsRepo.GetWorkflowDefinition(r.Context(), tenantID, projectID, workflowID)
 	if err != nil {
-		slog.Error("Failed to get created workflow definition",
+		slog.ErrorContext(r.Context(), "Failed to get created workflow definition",
 			"error", err,
 			"workflow_id", workflowID,
 		)
@@ -928,7 +1630,337 @@ func (h *WorkflowsHandler) CreateWorkflow(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	respondJSON(w, http.StatusCreated, workflow)
+	respondWorkflow(w, r, http.StatusCreated, workflow)
+}
+
+// UpdateWorkflow handles PUT /api/v1/workflows/:id
+// The definition is stored as a new version; the response contains the created version.
+func (h *WorkflowsHandler) UpdateWorkflow(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !requireAuthForWorkflows(w, r) {
+		return
+	}
+
+	id := appcontext.Param(r.Context(), "id")
+	if id == "" {
+		respondError(w, http.StatusBadRequest, "Invalid workflow ID")
+		return
+	}
+
+	tenantID, projectID, err := parseTenantAndProject(r)
+	if err != nil {
+		slog.WarnContext(r.Context(), "Invalid tenant_id or project_id in request",
+			"error", err,
+			"workflow_id", id,
+			"path", r.URL.Path,
+		)
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Check if user has permission to create workflows in this project
+	if err := h.permissionsSrv.CanCreateWorkflow(r.Context(), projectID); err != nil {
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			respondError(w, http.StatusForbidden, "Access denied to update workflows in this project")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to verify permissions")
+		return
+	}
+
+	var req struct {
+		Definition json.RawMessage `json:"definition" validate:"required"`
+	}
+
+	if err := decodeWorkflowRequest(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if !validRequest(w, &req) {
+		return
+	}
+
+	workflowID, err := h.workflowsUseCase.UpdateDefinition(r.Context(), tenantID, projectID, id, req.Definition)
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			respondError(w, http.StatusNotFound, "Workflow not found")
+			return
+		}
+		slog.ErrorContext(r.Context(), "Failed to update workflow definition",
+			"error", err,
+			"workflow_id", id,
+			"project_id", projectID,
+		)
+		respondErr(w, err)
+		return
+	}
+
+	workflow, err := h.workflowsRepo.GetWorkflowDefinition(r.Context(), tenantID, projectID, workflowID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to get updated workflow definition",
+			"error", err,
+			"workflow_id", workflowID,
+		)
+		respondJSON(w, http.StatusOK, map[string]interface{}{
+			"id":      workflowID,
+			"message": "Workflow definition updated successfully",
+		})
+		return
+	}
+
+	respondWorkflow(w, r, http.StatusOK, workflow)
+}
+
+// DeleteWorkflow handles DELETE /api/v1/workflows/:id
+func (h *WorkflowsHandler) DeleteWorkflow(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !requireAuthForWorkflows(w, r) {
+		return
+	}
+
+	id := appcontext.Param(r.Context(), "id")
+	if id == "" {
+		respondError(w, http.StatusBadRequest, "Invalid workflow ID")
+		return
+	}
+
+	tenantID, projectID, err := parseTenantAndProject(r)
+	if err != nil {
+		slog.WarnContext(r.Context(), "Invalid tenant_id or project_id in request",
+			"error", err,
+			"workflow_id", id,
+			"path", r.URL.Path,
+		)
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Check if user has permission to manage this project
+	if err := h.permissionsSrv.CanManageProject(r.Context(), projectID); err != nil {
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			respondError(w, http.StatusForbidden, "Access denied to delete workflows in this project")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to verify permissions")
+		return
+	}
+
+	if err := h.workflowsUseCase.DeleteDefinition(r.Context(), tenantID, projectID, id); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrEntityNotFound):
+			respondError(w, http.StatusNotFound, "Workflow not found")
+		case errors.Is(err, domain.ErrEntityInUse):
+			respondError(w, http.StatusConflict, "Workflow definition has instances and cannot be deleted")
+		default:
+			slog.ErrorContext(r.Context(), "Failed to delete workflow definition",
+				"error", err,
+				"workflow_id", id,
+				"project_id", projectID,
+			)
+			respondErr(w, err)
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "workflow definition deleted successfully"})
+}
+
+// TransferWorkflow handles POST /api/v1/workflows/:id/transfer
+func (h *WorkflowsHandler) TransferWorkflow(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !requireAuthForWorkflows(w, r) {
+		return
+	}
+
+	id := appcontext.Param(r.Context(), "id")
+	if id == "" {
+		respondError(w, http.StatusBadRequest, "Invalid workflow ID")
+		return
+	}
+
+	tenantID, projectID, err := parseTenantAndProject(r)
+	if err != nil {
+		slog.WarnContext(r.Context(), "Invalid tenant_id or project_id in request",
+			"error", err,
+			"workflow_id", id,
+			"path", r.URL.Path,
+		)
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var req struct {
+		TargetProjectID  int  `json:"target_project_id" validate:"required,min=1"`
+		IncludeInstances bool `json:"include_instances"`
+	}
+
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	targetProjectID := domain.ProjectID(req.TargetProjectID)
+
+	err = h.workflowsUseCase.TransferDefinition(
+		r.Context(),
+		tenantID,
+		projectID,
+		targetProjectID,
+		id,
+		req.IncludeInstances,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrPermissionDenied):
+			respondError(w, http.StatusForbidden, "Access denied to manage source or target project")
+		case errors.Is(err, domain.ErrEntityNotFound):
+			respondError(w, http.StatusNotFound, "Workflow or target project not found")
+		case errors.Is(err, workflowsusecase.ErrSameProject), errors.Is(err, workflowsusecase.ErrOtherTenant):
+			respondError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, workflowsusecase.ErrInstancesNotIncluded):
+			respondError(w, http.StatusConflict, err.Error())
+		default:
+			slog.ErrorContext(r.Context(), "Failed to transfer workflow definition",
+				"error", err,
+				"workflow_id", id,
+				"project_id", projectID,
+				"target_project_id", targetProjectID,
+			)
+			respondErr(w, err)
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"id":                id,
+		"project_id":        projectID,
+		"target_project_id": targetProjectID,
+		"message":           "Workflow definition transferred successfully",
+	})
+}
+
+// DiffWorkflow handles GET /api/v1/workflows/:id/diff?from=v1&to=v2
+// The :id path segment holds the workflow name.
+func (h *WorkflowsHandler) DiffWorkflow(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if !requireAuthForWorkflows(w, r) {
+		return
+	}
+
+	name := appcontext.Param(r.Context(), "id")
+	if name == "" {
+		respondError(w, http.StatusBadRequest, "Invalid workflow name")
+		return
+	}
+
+	fromVersion, err := parseWorkflowVersion(r.URL.Query().Get("from"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid from version")
+		return
+	}
+
+	toVersion, err := parseWorkflowVersion(r.URL.Query().Get("to"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid to version")
+		return
+	}
+
+	tenantID, projectID, err := parseTenantAndProject(r)
+	if err != nil {
+		slog.WarnContext(r.Context(), "Invalid tenant_id or project_id in request",
+			"error", err,
+			"workflow_name", name,
+			"path", r.URL.Path,
+		)
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Check if user has permission to view this project
+	if err := h.permissionsSrv.CanViewProject(r.Context(), projectID); err != nil {
+		if errors.Is(err, domain.ErrPermissionDenied) {
+			respondError(w, http.StatusForbidden, "Access denied to this project")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to verify permissions")
+		return
+	}
+
+	from, err := h.workflowsRepo.GetWorkflowDefinitionByVersion(r.Context(), tenantID, projectID, name, fromVersion)
+	if err != nil {
+		h.respondDefinitionLookupError(w, r, err, name, fromVersion)
+		return
+	}
+
+	to, err := h.workflowsRepo.GetWorkflowDefinitionByVersion(r.Context(), tenantID, projectID, name, toVersion)
+	if err != nil {
+		h.respondDefinitionLookupError(w, r, err, name, toVersion)
+		return
+	}
+
+	diff, err := workflowdiff.Diff(from, to)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to diff workflow definitions",
+			"error", err,
+			"workflow_name", name,
+			"from", fromVersion,
+			"to", toVersion,
+		)
+		respondError(w, http.StatusUnprocessableEntity, "Failed to parse workflow definition")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, diff)
+}
+
+func (h *WorkflowsHandler) respondDefinitionLookupError(
+	w http.ResponseWriter,
+	r *http.Request,
+	err error,
+	name string,
+	version int,
+) {
+	if errors.Is(err, domain.ErrEntityNotFound) {
+		respondError(w, http.StatusNotFound, fmt.Sprintf("Workflow %s version %d not found", name, version))
+		return
+	}
+
+	slog.ErrorContext(r.Context(), "Failed to get workflow definition",
+		"error", err,
+		"workflow_name", name,
+		"version", version,
+	)
+	respondErr(w, err)
+}
+
+// parseWorkflowVersion accepts versions in "v2" or "2" form.
+func parseWorkflowVersion(value string) (int, error) {
+	version, err := strconv.Atoi(strings.TrimPrefix(value, "v"))
+	if err != nil {
+		return 0, err
+	}
+
+	if version <= 0 {
+		return 0, fmt.Errorf("version must be greater than 0")
+	}
+
+	return version, nil
 }
 
 func requireAuthForWorkflows(w http.ResponseWriter, r *http.Request) bool {
@@ -956,6 +1988,42 @@ func parseTenantAndProject(r *http.Request) (domain.TenantID, domain.ProjectID,
 	return domain.TenantID(tenantID), domain.ProjectID(projectID), nil
 }
 
+// parseCursor reports whether the request asks for keyset pagination (?cursor=, empty for the first page)
+// and returns the decoded cursor.
+func parseCursor(r *http.Request) (*domain.Cursor, bool, error) {
+	query := r.URL.Query()
+	if !query.Has("cursor") {
+		return nil, false, nil
+	}
+
+	token := query.Get("cursor")
+	if token == "" {
+		return nil, true, nil
+	}
+
+	cursor, err := domain.ParseCursor(token)
+	if err != nil {
+		return nil, true, err
+	}
+
+	return &cursor, true, nil
+}
+
+// cursorPage is the response of a list in keyset pagination mode. next_cursor is null on the last page.
+func cursorPage(items any, pageSize int, next *domain.Cursor) map[string]interface{} {
+	var nextCursor *string
+	if next != nil {
+		token := next.String()
+		nextCursor = &token
+	}
+
+	return map[string]interface{}{
+		"items":       items,
+		"page_size":   pageSize,
+		"next_cursor": nextCursor,
+	}
+}
+
 func parsePagination(r *http.Request) (page, pageSize int) {
 	page = 1
 	pageSize = 20
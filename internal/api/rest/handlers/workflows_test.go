@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/usecases/redaction"
+)
+
+const secretInput = `{"order":7,"variables":{"API_TOKEN":"s3cr3t-token","REGION":"eu"}}`
+
+type fakeInstancesRepo struct {
+	contract.WorkflowsRepository
+	instance domain.WorkflowInstance
+	searched []domain.InstanceSearchQuery
+}
+
+func (f *fakeInstancesRepo) GetWorkflowInstance(
+	context.Context,
+	domain.TenantID,
+	domain.ProjectID,
+	int,
+) (domain.WorkflowInstance, error) {
+	return f.instance, nil
+}
+
+func (f *fakeInstancesRepo) SearchWorkflowInstances(
+	_ context.Context,
+	_ domain.TenantID,
+	_ domain.ProjectID,
+	query domain.InstanceSearchQuery,
+	_, _ int,
+) ([]domain.WorkflowInstance, int, error) {
+	f.searched = append(f.searched, query)
+
+	return []domain.WorkflowInstance{f.instance}, 1, nil
+}
+
+// viewerPermissions lets the caller view the projects and their workflow data.
+type viewerPermissions struct {
+	contract.PermissionsService
+}
+
+func (viewerPermissions) CanViewProject(context.Context, domain.ProjectID) error {
+	return nil
+}
+
+func (viewerPermissions) CanViewWorkflowData(context.Context, domain.ProjectID) error {
+	return nil
+}
+
+// projectViewerPermissions lets the caller view the projects but not their workflow data.
+type projectViewerPermissions struct {
+	viewerPermissions
+}
+
+func (projectViewerPermissions) CanViewWorkflowData(context.Context, domain.ProjectID) error {
+	return domain.ErrPermissionDenied
+}
+
+type wildcardRedactionRules struct {
+	contract.RedactionRepository
+}
+
+func (wildcardRedactionRules) ListRules(_ context.Context, projectIDs []domain.ProjectID) ([]domain.RedactionRules, error) {
+	rules := make([]domain.RedactionRules, 0, len(projectIDs))
+	for _, id := range projectIDs {
+		rules = append(rules, domain.RedactionRules{ProjectID: id, Paths: []string{"$..card"}})
+	}
+
+	return rules, nil
+}
+
+type noRedactionRules struct {
+	contract.RedactionRepository
+}
+
+func (noRedactionRules) ListRules(context.Context, []domain.ProjectID) ([]domain.RedactionRules, error) {
+	return nil, nil
+}
+
+type secretVariables struct {
+	contract.VariablesRepository
+}
+
+func (secretVariables) ListSecretNames(
+	_ context.Context,
+	projectIDs []domain.ProjectID,
+) (map[domain.ProjectID][]string, error) {
+	names := map[domain.ProjectID][]string{}
+	for _, id := range projectIDs {
+		names[id] = []string{"API_TOKEN"}
+	}
+
+	return names, nil
+}
+
+func newSecretsHandler() *WorkflowsHandler {
+	handler, _ := newSearchHandler(viewerPermissions{}, noRedactionRules{})
+
+	return handler
+}
+
+func newSearchHandler(
+	permissions contract.PermissionsService,
+	rules contract.RedactionRepository,
+) (*WorkflowsHandler, *fakeInstancesRepo) {
+	repo := &fakeInstancesRepo{instance: domain.WorkflowInstance{
+		TenantID:  1,
+		ProjectID: 2,
+		ID:        3,
+		Input:     json.RawMessage(secretInput),
+	}}
+	redactor := redaction.New(nil, rules, secretVariables{})
+
+	return NewWorkflowsHandler(repo, nil, nil, permissions, redactor), repo
+}
+
+func viewerRequest(target string, params httprouter.Params) *http.Request {
+	ctx := appcontext.WithUserID(context.Background(), 5)
+	ctx = appcontext.WithParams(ctx, params)
+
+	return httptest.NewRequest(http.MethodGet, target, nil).WithContext(ctx)
+}
+
+func TestWorkflowsHandler_SecretVariablesRedacted(t *testing.T) {
+	handler := newSecretsHandler()
+
+	rec := httptest.NewRecorder()
+	handler.GetInstance(rec, viewerRequest("/api/v1/instances/3?tenant_id=1&project_id=2",
+		httprouter.Params{{Key: "id", Value: "3"}}))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "s3cr3t-token")
+
+	var instance struct {
+		Input json.RawMessage `json:"input"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &instance))
+	assert.JSONEq(t, `{"order":7,"variables":{"API_TOKEN":"[REDACTED]","REGION":"eu"}}`, string(instance.Input))
+
+	rec = httptest.NewRecorder()
+	handler.SearchInstances(rec, viewerRequest("/api/v1/instance-search?tenant_id=1&project_id=2&q=order", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "s3cr3t-token")
+}
+
+func TestWorkflowsHandler_SearchInstances_RedactedValues(t *testing.T) {
+	const target = "/api/v1/instance-search?tenant_id=1&project_id=2&q=s3cr3t"
+
+	t.Run("without data view", func(t *testing.T) {
+		handler, repo := newSearchHandler(projectViewerPermissions{}, noRedactionRules{})
+
+		rec := httptest.NewRecorder()
+		handler.SearchInstances(rec, viewerRequest(target, nil))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.Empty(t, repo.searched)
+	})
+
+	t.Run("redacted locations are excluded", func(t *testing.T) {
+		handler, repo := newSearchHandler(viewerPermissions{}, noRedactionRules{})
+
+		rec := httptest.NewRecorder()
+		handler.SearchInstances(rec, viewerRequest(target, nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Len(t, repo.searched, 1)
+		assert.Equal(t, [][]string{{"variables", "API_TOKEN"}}, repo.searched[0].Excluded)
+	})
+
+	t.Run("rules selecting several locations", func(t *testing.T) {
+		handler, repo := newSearchHandler(viewerPermissions{}, wildcardRedactionRules{})
+
+		rec := httptest.NewRecorder()
+		handler.SearchInstances(rec, viewerRequest(target, nil))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.Empty(t, repo.searched)
+	})
+}
@@ -1,6 +1,8 @@
 package middlewares
 
 import (
+	"context"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
@@ -35,7 +37,7 @@ func AuditMiddleware(executor db.Tx) func(http.Handler) http.Handler {
 			action := mapMethodToAction(r.Method)
 
 			// Try to get project_id from context or extract from path
-			projectID := appcontext.ProjectID(ctx)
+			projectID := appcontext.LookupProjectID(ctx)
 			if projectID == 0 {
 				// Try to extract from URL path (e.g., /api/v1/projects/{id}/...)
 				if pid, ok := extractProjectIDFromPath(r.URL.Path); ok {
@@ -43,9 +45,20 @@ func AuditMiddleware(executor db.Tx) func(http.Handler) http.Handler {
 				}
 			}
 
-			_ = auditlog.WriteLog(ctx, executor, entity, entityID, action, projectID)
+			recorder := &statusRecorder{ResponseWriter: w}
 
-			next.ServeHTTP(w, r)
+			next.ServeHTTP(recorder, r)
+
+			status := recorder.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			// The client may have gone away, the entry is written anyway
+			err := auditlog.WriteRequestLog(context.WithoutCancel(ctx), executor, entity, entityID, action, projectID, status)
+			if err != nil {
+				slog.WarnContext(ctx, "Failed to write audit log", "error", err, "entity", entity, "entity_id", entityID)
+			}
 		})
 	}
 }
@@ -1,16 +1,26 @@
 package middlewares
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strings"
 
+	"github.com/rom8726/floxy-manager/internal/api/rest/apierror"
 	appcontext "github.com/rom8726/floxy-manager/internal/context"
 	"github.com/rom8726/floxy-manager/internal/contract"
 	"github.com/rom8726/floxy-manager/internal/domain"
 )
 
+// ErrAPITokenScope is returned by Authenticate when the API token scope does not allow the method.
+var ErrAPITokenScope = errors.New("api token scope does not allow this request")
+
 // AuthMiddleware extracts the user ID from the request and sets it in the context.
-func AuthMiddleware(tokenizer contract.Tokenizer, usersSrv contract.UsersUseCase) func(http.Handler) http.Handler {
+func AuthMiddleware(
+	tokenizer contract.Tokenizer,
+	usersSrv contract.UsersUseCase,
+	apiTokens contract.APITokensUseCase,
+) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
 			// Extract the Authorization header
@@ -25,29 +35,20 @@ func AuthMiddleware(tokenizer contract.Tokenizer, usersSrv contract.UsersUseCase
 			// Extract the token
 			token := strings.TrimPrefix(authHeader, "Bearer ")
 
-			// Verify the token and get the user ID
-			claims, err := tokenizer.VerifyToken(token, domain.TokenTypeAccess)
+			ctx, err := Authenticate(request.Context(), request.Method, token, tokenizer, usersSrv, apiTokens)
 			if err != nil {
-				// Invalid token, pass through
-				next.ServeHTTP(writer, request)
+				if errors.Is(err, ErrAPITokenScope) {
+					apierror.Respond(writer, http.StatusForbidden, err.Error())
 
-				return
-			}
+					return
+				}
 
-			// Get the user
-			user, err := usersSrv.GetByID(request.Context(), domain.UserID(claims.UserID))
-			if err != nil {
-				// User isn't found, pass through
+				// Invalid token, unknown user or revoked session, pass through
 				next.ServeHTTP(writer, request)
 
 				return
 			}
 
-			// Set the user ID and superuser flag in the context
-			ctx := appcontext.WithUserID(request.Context(), user.ID)
-			ctx = appcontext.WithUsername(ctx, user.Username)
-			ctx = appcontext.WithIsSuper(ctx, user.IsSuperuser)
-
 			// Continue with the modified context
 			next.ServeHTTP(writer, request.WithContext(ctx))
 		})
@@ -55,40 +56,130 @@ func AuthMiddleware(tokenizer contract.Tokenizer, usersSrv contract.UsersUseCase
 }
 
 // RequireAuthMiddleware requires authentication and returns 401 if not authenticated.
-func RequireAuthMiddleware(tokenizer contract.Tokenizer, usersSrv contract.UsersUseCase) func(http.Handler) http.Handler {
+func RequireAuthMiddleware(
+	tokenizer contract.Tokenizer,
+	usersSrv contract.UsersUseCase,
+	apiTokens contract.APITokensUseCase,
+) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
 			// Extract the Authorization header
 			authHeader := request.Header.Get("Authorization")
 			if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
-				http.Error(writer, "Unauthorized", http.StatusUnauthorized)
+				apierror.Respond(writer, http.StatusUnauthorized, "Unauthorized")
 				return
 			}
 
 			// Extract the token
 			token := strings.TrimPrefix(authHeader, "Bearer ")
 
-			// Verify the token and get the user ID
-			claims, err := tokenizer.VerifyToken(token, domain.TokenTypeAccess)
+			ctx, err := Authenticate(request.Context(), request.Method, token, tokenizer, usersSrv, apiTokens)
 			if err != nil {
-				http.Error(writer, "Unauthorized", http.StatusUnauthorized)
-				return
-			}
+				if errors.Is(err, ErrAPITokenScope) {
+					apierror.Respond(writer, http.StatusForbidden, err.Error())
+					return
+				}
 
-			// Get the user
-			user, err := usersSrv.GetByID(request.Context(), domain.UserID(claims.UserID))
-			if err != nil {
-				http.Error(writer, "Unauthorized", http.StatusUnauthorized)
+				apierror.Respond(writer, http.StatusUnauthorized, "Unauthorized")
 				return
 			}
 
-			// Set the user ID and superuser flag in the context
-			ctx := appcontext.WithUserID(request.Context(), user.ID)
-			ctx = appcontext.WithUsername(ctx, user.Username)
-			ctx = appcontext.WithIsSuper(ctx, user.IsSuperuser)
-
 			// Continue with the modified context
 			next.ServeHTTP(writer, request.WithContext(ctx))
 		})
 	}
 }
+
+// Authenticate verifies a bearer token (JWT access token or API token) for a request with
+// the given method and returns ctx with the user set.
+func Authenticate(
+	ctx context.Context,
+	method string,
+	token string,
+	tokenizer contract.Tokenizer,
+	usersSrv contract.UsersUseCase,
+	apiTokens contract.APITokensUseCase,
+) (context.Context, error) {
+	if strings.HasPrefix(token, domain.APITokenPrefix) {
+		user, apiToken, err := apiTokens.Authenticate(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+
+		if !apiToken.Allows(method) {
+			return nil, ErrAPITokenScope
+		}
+
+		ctx = withUser(ctx, &user)
+		ctx = appcontext.WithAPITokenID(ctx, apiToken.ID)
+
+		return ctx, nil
+	}
+
+	// Verify the token and get the user ID
+	claims, err := tokenizer.VerifyToken(token, domain.TokenTypeAccess)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the user
+	user, err := usersSrv.GetByID(ctx, domain.UserID(claims.UserID))
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Actor != nil {
+		return impersonate(ctx, claims, &user, usersSrv)
+	}
+
+	// Tokens issued before sessions were introduced have no session ID
+	if claims.SessionID != "" {
+		if err := usersSrv.CheckSession(ctx, user.ID, claims.SessionID); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx = withUser(ctx, &user)
+	ctx = appcontext.WithSessionID(ctx, claims.SessionID)
+
+	return ctx, nil
+}
+
+// impersonate returns ctx with the user of an impersonation token and its actor set. The token is valid
+// while the user is active, the actor is an active superuser and the session it was issued in is not revoked.
+func impersonate(
+	ctx context.Context,
+	claims *domain.TokenClaims,
+	user *domain.User,
+	usersSrv contract.UsersUseCase,
+) (context.Context, error) {
+	if !user.IsActive {
+		return nil, domain.ErrPermissionDenied
+	}
+
+	actor, err := usersSrv.GetByID(ctx, domain.UserID(claims.Actor.UserID))
+	if err != nil {
+		return nil, err
+	}
+
+	if !actor.IsActive || !actor.IsSuperuser {
+		return nil, domain.ErrPermissionDenied
+	}
+
+	if err := usersSrv.CheckSession(ctx, actor.ID, claims.SessionID); err != nil {
+		return nil, err
+	}
+
+	ctx = withUser(ctx, user)
+	ctx = appcontext.WithImpersonator(ctx, actor.Username)
+
+	return ctx, nil
+}
+
+// withUser sets the user ID, username and superuser flag in the context.
+func withUser(ctx context.Context, user *domain.User) context.Context {
+	ctx = appcontext.WithUserID(ctx, user.ID)
+	ctx = appcontext.WithUsername(ctx, user.Username)
+
+	return appcontext.WithIsSuper(ctx, user.IsSuperuser)
+}
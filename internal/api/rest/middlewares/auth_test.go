@@ -0,0 +1,47 @@
+package middlewares
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type fakeImpersonationUsers struct {
+	contract.UsersUseCase
+
+	users map[domain.UserID]domain.User
+}
+
+func (f *fakeImpersonationUsers) GetByID(_ context.Context, id domain.UserID) (domain.User, error) {
+	user, ok := f.users[id]
+	if !ok {
+		return domain.User{}, domain.ErrEntityNotFound
+	}
+
+	return user, nil
+}
+
+func (f *fakeImpersonationUsers) CheckSession(context.Context, domain.UserID, domain.SessionID) error {
+	return nil
+}
+
+func TestImpersonate(t *testing.T) {
+	usersSrv := &fakeImpersonationUsers{users: map[domain.UserID]domain.User{
+		1: {ID: 1, Username: "admin", IsActive: true, IsSuperuser: true},
+	}}
+	claims := &domain.TokenClaims{UserID: 2, SessionID: "s1", Actor: &domain.TokenActor{UserID: 1, Username: "admin"}}
+
+	ctx, err := impersonate(context.Background(), claims, &domain.User{ID: 2, Username: "jdoe", IsActive: true}, usersSrv)
+	require.NoError(t, err)
+	assert.Equal(t, domain.UserID(2), appcontext.UserID(ctx))
+	assert.Equal(t, "admin", appcontext.Impersonator(ctx))
+
+	_, err = impersonate(context.Background(), claims, &domain.User{ID: 2, Username: "jdoe"}, usersSrv)
+	require.ErrorIs(t, err, domain.ErrPermissionDenied)
+}
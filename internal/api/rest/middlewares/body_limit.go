@@ -0,0 +1,86 @@
+package middlewares
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/rom8726/floxy-manager/internal/api/rest/apierror"
+	"github.com/rom8726/floxy-manager/pkg/safejson"
+)
+
+// BodyLimits holds the maximum request body sizes in bytes of the route groups.
+type BodyLimits struct {
+	Default int64
+	Auth    int64
+	Upload  int64
+}
+
+// limitFor returns the body limit of the route group the path belongs to.
+func (l BodyLimits) limitFor(path string) int64 {
+	switch {
+	case strings.HasPrefix(path, "/api/v1/auth/"):
+		return l.Auth
+	case path == "/api/v1/workflows",
+		strings.HasPrefix(path, "/api/v1/workflows/"),
+		path == "/api/v1/project-import",
+		strings.HasPrefix(path, "/api/v1/projects/") && strings.HasSuffix(path, "/workflows/import"):
+		return l.Upload
+	default:
+		return l.Default
+	}
+}
+
+// BodyLimitMdw caps request bodies by route group and passes the JSON decoding options
+// to handlers. Requests declaring a larger Content-Length are rejected right away,
+// other oversized bodies fail while being read.
+func BodyLimitMdw(limits BodyLimits, jsonOpts safejson.Options) func(http.Handler) http.Handler {
+	return NewRequestLimits(limits, jsonOpts).Middleware()
+}
+
+// RequestLimits holds the body limits and the JSON decoding options, they may be replaced
+// while serving, e.g. on config reload.
+type RequestLimits struct {
+	current atomic.Pointer[requestLimits]
+}
+
+type requestLimits struct {
+	body     BodyLimits
+	jsonOpts safejson.Options
+}
+
+func NewRequestLimits(limits BodyLimits, jsonOpts safejson.Options) *RequestLimits {
+	l := &RequestLimits{}
+	l.Set(limits, jsonOpts)
+
+	return l
+}
+
+// Set replaces the limits of the next requests.
+func (l *RequestLimits) Set(limits BodyLimits, jsonOpts safejson.Options) {
+	l.current.Store(&requestLimits{body: limits, jsonOpts: jsonOpts})
+}
+
+// Middleware works like BodyLimitMdw with the current limits.
+func (l *RequestLimits) Middleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			current := l.current.Load()
+
+			limit := current.body.limitFor(request.URL.Path)
+			if limit > 0 {
+				if request.ContentLength > limit {
+					apierror.Respond(writer, http.StatusRequestEntityTooLarge, "Request body is too large")
+
+					return
+				}
+
+				request.Body = http.MaxBytesReader(writer, request.Body, limit)
+			}
+
+			ctx := safejson.NewContext(request.Context(), current.jsonOpts)
+
+			next.ServeHTTP(writer, request.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package middlewares
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rom8726/floxy-manager/pkg/safejson"
+)
+
+func TestBodyLimitMdw(t *testing.T) {
+	limits := BodyLimits{Default: 16, Auth: 4, Upload: 64}
+	assert.Equal(t, int64(4), limits.limitFor("/api/v1/auth/login"))
+	assert.Equal(t, int64(64), limits.limitFor("/api/v1/workflows/12"))
+	assert.Equal(t, int64(64), limits.limitFor("/api/v1/projects/3/workflows/import"))
+	assert.Equal(t, int64(16), limits.limitFor("/api/v1/projects/3/workflows/assign"))
+
+	var readErr error
+	handler := BodyLimitMdw(limits, safejson.Options{MaxDepth: 5})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, 5, safejson.FromContext(r.Context()).MaxDepth)
+			_, readErr = io.ReadAll(r.Body)
+		}),
+	)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", strings.NewReader("too long")))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+	// Without a Content-Length the body fails while being read
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", strings.NewReader("too long"))
+	req.ContentLength = -1
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Error(t, readErr)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/v1/users", strings.NewReader("fits")))
+	assert.NoError(t, readErr)
+}
@@ -0,0 +1,67 @@
+package middlewares
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+)
+
+// ClientIPMdw resolves the client IP address of the request. X-Forwarded-For is only read when the
+// request comes from a trusted proxy, the client is then its rightmost hop that is not a trusted proxy.
+// Other requests are attributed to their remote address.
+func ClientIPMdw(trustedProxies []netip.Prefix) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := appcontext.WithClientIP(r.Context(), clientIP(r, trustedProxies))
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func clientIP(r *http.Request, trustedProxies []netip.Prefix) string {
+	remote := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		remote = host
+	}
+
+	addr, err := netip.ParseAddr(remote)
+	if err != nil || !isTrustedProxy(addr, trustedProxies) {
+		return remote
+	}
+
+	var hops []string
+	for _, value := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(value, ",")...)
+	}
+
+	client := addr
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			// A malformed hop cannot be attributed, the last proxy is the closest known address
+			break
+		}
+
+		client = hop
+		if !isTrustedProxy(hop, trustedProxies) {
+			break
+		}
+	}
+
+	return client.Unmap().String()
+}
+
+func isTrustedProxy(addr netip.Addr, trustedProxies []netip.Prefix) bool {
+	addr = addr.Unmap()
+	for _, prefix := range trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,41 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientIP(t *testing.T) {
+	trusted := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+
+	tests := []struct {
+		name      string
+		remote    string
+		forwarded []string
+		want      string
+	}{
+		{"direct client", "203.0.113.7:5000", nil, "203.0.113.7"},
+		{"forwarded by untrusted peer", "203.0.113.7:5000", []string{"198.51.100.1"}, "203.0.113.7"},
+		{"forwarded by trusted proxy", "10.0.0.1:5000", []string{"198.51.100.1"}, "198.51.100.1"},
+		{"spoofed leftmost hop", "10.0.0.1:5000", []string{"1.2.3.4, 198.51.100.1"}, "198.51.100.1"},
+		{"chain of trusted proxies", "10.0.0.1:5000", []string{"198.51.100.1, 10.0.0.2", "10.0.0.3"}, "198.51.100.1"},
+		{"malformed hop", "10.0.0.1:5000", []string{"198.51.100.1, bogus, 10.0.0.2"}, "10.0.0.2"},
+		{"trusted proxy without header", "10.0.0.1:5000", nil, "10.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remote
+			for _, value := range tt.forwarded {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+
+			assert.Equal(t, tt.want, clientIP(req, trusted))
+		})
+	}
+}
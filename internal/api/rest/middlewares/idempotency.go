@@ -0,0 +1,220 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/api/rest/apierror"
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+const (
+	idempotencyKeyHeader      = "Idempotency-Key"
+	idempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	maxIdempotentResponseSize = 1 << 20
+)
+
+type IdempotencyConfig struct {
+	TTL time.Duration
+}
+
+// Idempotency replays the stored response of a request retried with the same Idempotency-Key header,
+// so retries of flaky clients do not create workflows, memberships or instances twice.
+// Requests without the header are passed as is.
+type Idempotency struct {
+	repo contract.IdempotencyKeysRepository
+	ttl  time.Duration
+}
+
+func NewIdempotency(repo contract.IdempotencyKeysRepository, cfg *IdempotencyConfig) *Idempotency {
+	return &Idempotency{
+		repo: repo,
+		ttl:  cfg.TTL,
+	}
+}
+
+// Middleware runs the first request with a key and stores its response. A retry gets the stored
+// response, 409 while the first request is in progress and 422 when its body differs.
+// Server errors are not stored, so the request can be retried with the same key.
+func (i *Idempotency) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(idempotencyKeyHeader)
+		if key == "" {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			apierror.Respond(w, http.StatusBadRequest, "Idempotency-Key is too long")
+
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			if maxBytesErr := new(http.MaxBytesError); errors.As(err, &maxBytesErr) {
+				apierror.Respond(w, http.StatusRequestEntityTooLarge, "Request body is too large")
+
+				return
+			}
+
+			apierror.Respond(w, http.StatusBadRequest, "Failed to read request body")
+
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := r.Context()
+		req := &domain.IdempotentRequest{
+			UserID:      appcontext.UserID(ctx),
+			Method:      r.Method,
+			Path:        r.URL.Path,
+			Key:         key,
+			RequestHash: requestHash(r.URL.RawQuery, body),
+			ExpiresAt:   time.Now().Add(i.ttl),
+		}
+
+		reserved, err := i.repo.Reserve(ctx, req)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to reserve idempotency key", "error", err)
+			apierror.Respond(w, http.StatusInternalServerError, "Internal Server Error")
+
+			return
+		}
+
+		if !reserved {
+			i.replay(w, r, req)
+
+			return
+		}
+
+		i.serve(w, r, next, req)
+	})
+}
+
+func (i *Idempotency) replay(w http.ResponseWriter, r *http.Request, req *domain.IdempotentRequest) {
+	stored, err := i.repo.Get(r.Context(), req)
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			// The first request failed and released the key meanwhile
+			apierror.Respond(w, http.StatusConflict, "Request with this Idempotency-Key failed, retry it")
+
+			return
+		}
+
+		slog.ErrorContext(r.Context(), "Failed to get idempotency key", "error", err)
+		apierror.Respond(w, http.StatusInternalServerError, "Internal Server Error")
+
+		return
+	}
+
+	switch {
+	case stored.RequestHash != req.RequestHash:
+		apierror.Respond(w, http.StatusUnprocessableEntity, "Idempotency-Key is already used with another request")
+	case !stored.Completed():
+		apierror.Respond(w, http.StatusConflict, "Request with this Idempotency-Key is in progress")
+	default:
+		if stored.ContentType != "" {
+			w.Header().Set("Content-Type", stored.ContentType)
+		}
+		w.Header().Set(idempotentReplayedHeader, "true")
+		w.WriteHeader(stored.StatusCode)
+		_, _ = w.Write(stored.Body)
+	}
+}
+
+func (i *Idempotency) serve(w http.ResponseWriter, r *http.Request, next http.Handler, req *domain.IdempotentRequest) {
+	recorder := &idempotencyWriter{ResponseWriter: w}
+	completed := false
+
+	// The response is stored even when the client is gone, that is when a retry comes
+	ctx := context.WithoutCancel(r.Context())
+
+	defer func() {
+		if completed {
+			return
+		}
+
+		if err := i.repo.Release(ctx, req); err != nil {
+			slog.ErrorContext(ctx, "Failed to release idempotency key", "error", err)
+		}
+	}()
+
+	next.ServeHTTP(recorder, r)
+
+	if recorder.status == 0 {
+		recorder.status = http.StatusOK
+	}
+
+	if recorder.status >= http.StatusInternalServerError || recorder.overflow {
+		return
+	}
+
+	req.StatusCode = recorder.status
+	req.ContentType = recorder.Header().Get("Content-Type")
+	req.Body = recorder.body.Bytes()
+
+	if err := i.repo.Complete(ctx, req); err != nil {
+		slog.ErrorContext(ctx, "Failed to store idempotent response", "error", err)
+
+		return
+	}
+
+	completed = true
+}
+
+func requestHash(query string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(query))
+	hash.Write([]byte{0})
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// idempotencyWriter copies the response to be stored, responses larger than
+// maxIdempotentResponseSize are not stored.
+type idempotencyWriter struct {
+	http.ResponseWriter
+	status   int
+	body     bytes.Buffer
+	overflow bool
+}
+
+func (w *idempotencyWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *idempotencyWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	if w.body.Len()+len(p) > maxIdempotentResponseSize {
+		w.overflow = true
+	} else if !w.overflow {
+		w.body.Write(p)
+	}
+
+	return w.ResponseWriter.Write(p)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *idempotencyWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
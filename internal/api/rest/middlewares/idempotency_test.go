@@ -0,0 +1,114 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type fakeIdempotencyKeys struct {
+	mu       sync.Mutex
+	requests map[string]domain.IdempotentRequest
+}
+
+func (f *fakeIdempotencyKeys) id(req *domain.IdempotentRequest) string {
+	return req.Method + " " + req.Path + " " + req.Key
+}
+
+func (f *fakeIdempotencyKeys) Reserve(_ context.Context, req *domain.IdempotentRequest) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.requests[f.id(req)]; ok {
+		return false, nil
+	}
+
+	f.requests[f.id(req)] = *req
+
+	return true, nil
+}
+
+func (f *fakeIdempotencyKeys) Get(_ context.Context, req *domain.IdempotentRequest) (domain.IdempotentRequest, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	stored, ok := f.requests[f.id(req)]
+	if !ok {
+		return domain.IdempotentRequest{}, domain.ErrEntityNotFound
+	}
+
+	return stored, nil
+}
+
+func (f *fakeIdempotencyKeys) Complete(_ context.Context, req *domain.IdempotentRequest) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests[f.id(req)] = *req
+
+	return nil
+}
+
+func (f *fakeIdempotencyKeys) Release(_ context.Context, req *domain.IdempotentRequest) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.requests, f.id(req))
+
+	return nil
+}
+
+func TestIdempotency_Middleware(t *testing.T) {
+	calls := 0
+	status := http.StatusCreated
+	idempotency := NewIdempotency(&fakeIdempotencyKeys{requests: map[string]domain.IdempotentRequest{}},
+		&IdempotencyConfig{TTL: time.Hour})
+	handler := idempotency.Middleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"id":1}`))
+	}))
+
+	send := func(key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/workflows", strings.NewReader(body))
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	rec := send("a", `{"name":"x"}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = send("a", `{"name":"x"}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, `{"id":1}`, rec.Body.String())
+	assert.Equal(t, "true", rec.Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.Equal(t, 1, calls)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, send("a", `{"name":"y"}`).Code)
+
+	send("", `{"name":"x"}`)
+	assert.Equal(t, 2, calls)
+
+	// Server errors are not stored
+	status = http.StatusInternalServerError
+	send("b", `{}`)
+	status = http.StatusCreated
+	assert.Equal(t, http.StatusCreated, send("b", `{}`).Code)
+	assert.Equal(t, 4, calls)
+}
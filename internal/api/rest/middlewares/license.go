@@ -0,0 +1,83 @@
+package middlewares
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/rom8726/floxy-manager/internal/api/rest/apierror"
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/contract"
+)
+
+// licenseExemptPaths stay available to users who have not accepted the license agreement,
+// so the UI can show the agreement and record its acceptance.
+var licenseExemptPaths = map[string]struct{}{
+	"/api/v1/license":                 {},
+	"/api/v1/license/status":          {},
+	"/api/v1/users/me":                {},
+	"/api/v1/users/me/accept-license": {},
+}
+
+// LicenseMdw answers 403 with the license_not_accepted code to users who have not accepted the current
+// version of the license agreement when its acceptance is required. Anonymous requests, /api/v1/auth
+// endpoints, API tokens and impersonation tokens are not checked.
+func LicenseMdw(
+	settings contract.RuntimeSettingsReader,
+	usersSrv contract.UsersUseCase,
+) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			if !licenseChecked(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			agreement, err := settings.GetLicenseAgreement(ctx)
+			if err != nil {
+				slog.WarnContext(ctx, "Failed to get license agreement", "error", err)
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			if !agreement.Required || agreement.Version == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			user, err := usersSrv.GetByID(ctx, appcontext.UserID(ctx))
+			if err != nil {
+				slog.WarnContext(ctx, "Failed to get user", "error", err)
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			if user.IsServiceAccount || user.LicenseVersion == agreement.Version {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			apierror.RespondDetails(w, http.StatusForbidden, apierror.CodeLicenseNotAccepted,
+				"License agreement must be accepted", map[string]string{"version": agreement.Version})
+		})
+	}
+}
+
+func licenseChecked(r *http.Request) bool {
+	ctx := r.Context()
+	if appcontext.UserID(ctx) == 0 || appcontext.APITokenID(ctx) != 0 || appcontext.Impersonator(ctx) != "" {
+		return false
+	}
+
+	path := r.URL.Path
+	if !strings.HasPrefix(path, "/api/") || strings.HasPrefix(path, "/api/v1/auth/") {
+		return false
+	}
+
+	_, exempt := licenseExemptPaths[path]
+
+	return !exempt
+}
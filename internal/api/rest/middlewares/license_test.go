@@ -0,0 +1,61 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type fakeLicenseSettings struct {
+	contract.RuntimeSettingsReader
+	agreement domain.LicenseAgreement
+}
+
+func (s *fakeLicenseSettings) GetLicenseAgreement(context.Context) (domain.LicenseAgreement, error) {
+	return s.agreement, nil
+}
+
+type fakeLicenseUsers struct {
+	contract.UsersUseCase
+	user domain.User
+}
+
+func (u *fakeLicenseUsers) GetByID(context.Context, domain.UserID) (domain.User, error) {
+	return u.user, nil
+}
+
+func TestLicenseMdw(t *testing.T) {
+	settings := &fakeLicenseSettings{agreement: domain.LicenseAgreement{Version: "2", Text: "terms", Required: true}}
+	users := &fakeLicenseUsers{user: domain.User{ID: 1, LicenseVersion: "1"}}
+	handler := LicenseMdw(settings, users)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	serve := func(ctx context.Context, path string) int {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequestWithContext(ctx, http.MethodGet, path, nil))
+
+		return recorder.Code
+	}
+
+	userCtx := appcontext.WithUserID(context.Background(), 1)
+
+	assert.Equal(t, http.StatusForbidden, serve(userCtx, "/api/v1/projects"))
+	assert.Equal(t, http.StatusNoContent, serve(userCtx, "/api/v1/license"))
+	assert.Equal(t, http.StatusNoContent, serve(context.Background(), "/api/v1/projects"))
+	assert.Equal(t, http.StatusNoContent, serve(appcontext.WithAPITokenID(userCtx, 1), "/api/v1/projects"))
+
+	users.user.LicenseVersion = "2"
+	assert.Equal(t, http.StatusNoContent, serve(userCtx, "/api/v1/projects"))
+
+	users.user.LicenseVersion = ""
+	settings.agreement.Required = false
+	assert.Equal(t, http.StatusNoContent, serve(userCtx, "/api/v1/projects"))
+}
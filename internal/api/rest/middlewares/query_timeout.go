@@ -0,0 +1,41 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+// QueryTimeoutMdw answers 504 instead of 500 when a database query of the request timed out.
+func QueryTimeoutMdw(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		ctx := db.WithQueryTimeoutMarker(request.Context())
+
+		next.ServeHTTP(&queryTimeoutWriter{ResponseWriter: writer, ctx: ctx}, request.WithContext(ctx))
+	})
+}
+
+type queryTimeoutWriter struct {
+	http.ResponseWriter
+	ctx context.Context
+}
+
+func (w *queryTimeoutWriter) WriteHeader(status int) {
+	if status == http.StatusInternalServerError && db.QueryTimedOut(w.ctx) {
+		status = http.StatusGatewayTimeout
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *queryTimeoutWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *queryTimeoutWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
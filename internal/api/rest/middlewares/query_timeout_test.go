@@ -0,0 +1,32 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+func TestQueryTimeoutMdw(t *testing.T) {
+	tracer := db.NewQueryTimeoutTracer(0)
+	handler := QueryTimeoutMdw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			tracer.TraceQueryEnd(r.Context(), nil, pgx.TraceQueryEndData{Err: &pgconn.PgError{Code: "57014"}})
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/slow", nil))
+	assert.Equal(t, http.StatusGatewayTimeout, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/broken", nil))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
@@ -0,0 +1,101 @@
+package middlewares
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/repository/auditlog"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+// ReadAuditMiddleware records GET requests for sensitive resources in the audit log together with
+// the response status when read access auditing is enabled in settings.
+// sensitive returns the audited entity of a request, or false if the request is not audited.
+func ReadAuditMiddleware(
+	executor db.Tx,
+	settingsUseCase contract.SettingsUseCase,
+	sensitive func(r *http.Request) (string, bool),
+) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet || appcontext.Username(r.Context()) == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			entity, ok := sensitive(r)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			recorder := &statusRecorder{ResponseWriter: w}
+
+			next.ServeHTTP(recorder, r)
+
+			ctx := context.WithoutCancel(r.Context())
+
+			enabled, err := settingsUseCase.GetAuditReadAccess(ctx)
+			if err != nil {
+				slog.WarnContext(ctx, "Failed to get read access audit setting", "error", err)
+				return
+			}
+
+			if !enabled {
+				return
+			}
+
+			status := recorder.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			entityID, projectID := readTarget(r)
+
+			err = auditlog.WriteRequestLog(ctx, executor, entity, entityID, domain.ActionRead, projectID, status)
+			if err != nil {
+				slog.WarnContext(ctx, "Failed to write read access audit log", "error", err, "entity", entity)
+			}
+		})
+	}
+}
+
+// AuditedEntity marks every request as a read of entity.
+func AuditedEntity(entity string) func(r *http.Request) (string, bool) {
+	return func(*http.Request) (string, bool) {
+		return entity, true
+	}
+}
+
+// IsDLQRead reports reads of dead letter queue items (and their payloads) served by the floxy API.
+func IsDLQRead(r *http.Request) (string, bool) {
+	return domain.EntityDLQItem, r.URL.Path == "/api/dlq" || strings.HasPrefix(r.URL.Path, "/api/dlq/")
+}
+
+// readTarget resolves the entity ID and project of a read from route params, the path and the query.
+func readTarget(r *http.Request) (string, domain.ProjectID) {
+	query := r.URL.Query()
+
+	var projectID domain.ProjectID
+	if pid, ok := extractProjectIDFromPath(r.URL.Path); ok {
+		projectID = domain.ProjectID(pid)
+	} else if pid, err := strconv.Atoi(query.Get("project_id")); err == nil && pid > 0 {
+		projectID = domain.ProjectID(pid)
+	}
+
+	entityID := appcontext.Param(r.Context(), "id")
+	if entityID == "" {
+		entityID = extractIDFromPath(r.URL.Path)
+	}
+	if entityID == "" {
+		entityID = query.Get("project_id")
+	}
+
+	return entityID, projectID
+}
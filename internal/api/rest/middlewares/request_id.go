@@ -10,17 +10,36 @@ import (
 
 const (
 	RequestIDHeader = "X-Request-Id"
+
+	maxRequestIDLength = 128
 )
 
 func RequestIDMdw(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
 		reqID := request.Header.Get(RequestIDHeader)
-		if reqID == "" {
+		if !validRequestID(reqID) {
 			reqID = uuid.NewString()
 		}
 
+		writer.Header().Set(RequestIDHeader, reqID)
+
 		ctx := appcontext.WithRequestID(request.Context(), reqID)
 
 		next.ServeHTTP(writer, request.WithContext(ctx))
 	})
 }
+
+// validRequestID accepts short printable ASCII IDs so that client supplied values are safe to log.
+func validRequestID(reqID string) bool {
+	if reqID == "" || len(reqID) > maxRequestIDLength {
+		return false
+	}
+
+	for i := 0; i < len(reqID); i++ {
+		if reqID[i] <= ' ' || reqID[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,64 @@
+package middlewares
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/rom8726/floxy-manager/internal/api/rest/apierror"
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+// TenantMdw sets the tenant the request addresses in the context, taken from the tenant_id query param,
+// the X-Tenant-ID header or the /api/v1/tenants/{id} path. It is the tenant of the database session
+// variables, the permissions are still checked by the handlers. The tenant is set only for superusers
+// and members of the tenant, other users get 403 and anonymous requests are served without a tenant.
+func TenantMdw(memberships contract.TenantMembershipsRepository) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			tenantID := requestTenantID(r)
+			userID := appcontext.UserID(ctx)
+			if tenantID == 0 || userID == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !appcontext.IsSuper(ctx) {
+				roleID, err := memberships.GetForUserTenant(ctx, userID, tenantID)
+				if err != nil {
+					slog.ErrorContext(ctx, "Failed to get tenant membership", "error", err)
+					apierror.Respond(w, http.StatusInternalServerError, "Internal Server Error")
+
+					return
+				}
+
+				if roleID == "" {
+					apierror.Respond(w, http.StatusForbidden, "Access denied to this tenant")
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r.WithContext(appcontext.WithTenantID(ctx, tenantID)))
+		})
+	}
+}
+
+func requestTenantID(r *http.Request) domain.TenantID {
+	candidates := []string{r.URL.Query().Get("tenant_id"), r.Header.Get("X-Tenant-ID")}
+	if rest, ok := strings.CutPrefix(r.URL.Path, "/api/v1/tenants/"); ok {
+		id, _, _ := strings.Cut(rest, "/")
+		candidates = append(candidates, id)
+	}
+
+	for _, candidate := range candidates {
+		if id, err := strconv.Atoi(candidate); err == nil && id > 0 {
+			return domain.TenantID(id)
+		}
+	}
+
+	return 0
+}
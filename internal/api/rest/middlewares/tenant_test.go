@@ -0,0 +1,104 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type fakeTenantMemberships struct {
+	contract.TenantMembershipsRepository
+	roles map[domain.TenantID]string
+	err   error
+}
+
+func (f *fakeTenantMemberships) GetForUserTenant(
+	_ context.Context,
+	_ domain.UserID,
+	tenantID domain.TenantID,
+) (string, error) {
+	return f.roles[tenantID], f.err
+}
+
+func asUser(r *http.Request, userID domain.UserID, isSuper bool) *http.Request {
+	ctx := appcontext.WithIsSuper(appcontext.WithUserID(r.Context(), userID), isSuper)
+
+	return r.WithContext(ctx)
+}
+
+func TestTenantMdw(t *testing.T) {
+	var tenantID domain.TenantID
+	memberships := &fakeTenantMemberships{roles: map[domain.TenantID]string{3: "viewer", 4: "admin", 5: "viewer"}}
+	handler := TenantMdw(memberships)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		tenantID = appcontext.TenantID(r.Context())
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), asUser(httptest.NewRequest(http.MethodGet, "/api/v1/projects?tenant_id=3", nil), 1, false))
+	assert.Equal(t, domain.TenantID(3), tenantID)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/workflows", nil)
+	req.Header.Set("X-Tenant-ID", "4")
+	handler.ServeHTTP(httptest.NewRecorder(), asUser(req, 1, false))
+	assert.Equal(t, domain.TenantID(4), tenantID)
+
+	handler.ServeHTTP(httptest.NewRecorder(), asUser(httptest.NewRequest(http.MethodGet, "/api/v1/tenants/5/memberships", nil), 1, false))
+	assert.Equal(t, domain.TenantID(5), tenantID)
+
+	handler.ServeHTTP(httptest.NewRecorder(), asUser(httptest.NewRequest(http.MethodGet, "/api/v1/tenants?tenant_id=x", nil), 1, false))
+	assert.Equal(t, domain.TenantID(0), tenantID)
+}
+
+func TestTenantMdw_Membership(t *testing.T) {
+	memberships := &fakeTenantMemberships{roles: map[domain.TenantID]string{3: "viewer"}}
+
+	var (
+		called   bool
+		tenantID domain.TenantID
+	)
+	handler := TenantMdw(memberships)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		called = true
+		tenantID = appcontext.TenantID(r.Context())
+	}))
+
+	serve := func(r *http.Request) int {
+		called, tenantID = false, 0
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, r)
+
+		return rec.Code
+	}
+
+	t.Run("not a member", func(t *testing.T) {
+		code := serve(asUser(httptest.NewRequest(http.MethodGet, "/api/v1/projects?tenant_id=7", nil), 1, false))
+		assert.Equal(t, http.StatusForbidden, code)
+		assert.False(t, called)
+	})
+
+	t.Run("superuser", func(t *testing.T) {
+		serve(asUser(httptest.NewRequest(http.MethodGet, "/api/v1/projects?tenant_id=7", nil), 1, true))
+		assert.True(t, called)
+		assert.Equal(t, domain.TenantID(7), tenantID)
+	})
+
+	t.Run("anonymous", func(t *testing.T) {
+		serve(httptest.NewRequest(http.MethodGet, "/api/v1/projects?tenant_id=3", nil))
+		assert.True(t, called)
+		assert.Equal(t, domain.TenantID(0), tenantID)
+	})
+
+	t.Run("membership error", func(t *testing.T) {
+		failing := &fakeTenantMemberships{err: errors.New("db down")}
+		rec := httptest.NewRecorder()
+		TenantMdw(failing)(http.NotFoundHandler()).ServeHTTP(rec,
+			asUser(httptest.NewRequest(http.MethodGet, "/api/v1/projects?tenant_id=3", nil), 1, false))
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+}
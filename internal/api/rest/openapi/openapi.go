@@ -0,0 +1,319 @@
+// Package openapi builds the OpenAPI 3.0 document of the REST API from the registered routes
+// and serves it together with a Swagger UI page.
+//
+// Operations are derived from code: the operation ID and summary come from the handler method name,
+// the tag from the resource in the path and path parameters from the httprouter pattern.
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const version = "3.0.3"
+
+//go:embed swagger-ui.html
+var swaggerUIPage string
+
+type Document struct {
+	OpenAPI    string                `json:"openapi"`
+	Info       Info                  `json:"info"`
+	Tags       []Tag                 `json:"tags"`
+	Paths      map[string]PathItem   `json:"paths"`
+	Components Components            `json:"components"`
+	Security   []map[string][]string `json:"security"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type Tag struct {
+	Name string `json:"name"`
+}
+
+// PathItem maps lower-case HTTP methods to operations.
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string                 `json:"operationId"`
+	Summary     string                 `json:"summary"`
+	Tags        []string               `json:"tags"`
+	Parameters  []Parameter            `json:"parameters,omitempty"`
+	RequestBody *RequestBody           `json:"requestBody,omitempty"`
+	Responses   map[string]Response    `json:"responses"`
+	Security    *[]map[string][]string `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name     string `json:"name"`
+	In       string `json:"in"`
+	Required bool   `json:"required"`
+	Schema   Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema Schema `json:"schema"`
+}
+
+type Schema struct {
+	Ref                  string            `json:"$ref,omitempty"`
+	Type                 string            `json:"type,omitempty"`
+	Properties           map[string]Schema `json:"properties,omitempty"`
+	AdditionalProperties *bool             `json:"additionalProperties,omitempty"`
+}
+
+type Components struct {
+	Schemas         map[string]Schema         `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+// Builder collects the operations of the registered routes.
+type Builder struct {
+	doc          Document
+	operationIDs map[string]int
+}
+
+func NewBuilder(title, description, apiVersion string) *Builder {
+	return &Builder{
+		doc: Document{
+			OpenAPI: version,
+			Info: Info{
+				Title:       title,
+				Description: description,
+				Version:     apiVersion,
+			},
+			Paths: make(map[string]PathItem),
+			Components: Components{
+				Schemas: map[string]Schema{
+					"Error": {
+						Type: "object",
+						Properties: map[string]Schema{
+							"code":       {Type: "string"},
+							"message":    {Type: "string"},
+							"details":    {},
+							"request_id": {Type: "string"},
+							"error":      {Type: "string"},
+						},
+					},
+				},
+				SecuritySchemes: map[string]SecurityScheme{
+					"bearerAuth": {
+						Type:         "http",
+						Scheme:       "bearer",
+						BearerFormat: "JWT",
+						Description:  "Access token from /api/v1/auth/login or a personal API token (flx_...)",
+					},
+				},
+			},
+			Security: []map[string][]string{{"bearerAuth": {}}},
+		},
+		operationIDs: make(map[string]int),
+	}
+}
+
+// Add documents the route. path is an httprouter pattern, handlerName the name of the handler method.
+// Public operations do not require authentication.
+func (b *Builder) Add(method, path, handlerName string, public bool) {
+	specPath, params := convertPath(path)
+
+	op := &Operation{
+		OperationID: b.operationID(handlerName),
+		Summary:     summary(handlerName),
+		Tags:        []string{tag(path)},
+		Parameters:  params,
+		Responses: map[string]Response{
+			"200": {
+				Description: "Successful response",
+				Content:     jsonContent(Schema{Type: "object"}),
+			},
+			"default": {
+				Description: "Error",
+				Content:     jsonContent(Schema{Ref: "#/components/schemas/Error"}),
+			},
+		},
+	}
+
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+		op.RequestBody = &RequestBody{Content: jsonContent(Schema{Type: "object"})}
+	}
+
+	if public {
+		op.Security = &[]map[string][]string{}
+	}
+
+	item, ok := b.doc.Paths[specPath]
+	if !ok {
+		item = make(PathItem)
+		b.doc.Paths[specPath] = item
+	}
+	item[strings.ToLower(method)] = op
+}
+
+// Document returns the document with tags of all operations.
+func (b *Builder) Document() *Document {
+	tags := make(map[string]struct{})
+	for _, item := range b.doc.Paths {
+		for _, op := range item {
+			for _, name := range op.Tags {
+				tags[name] = struct{}{}
+			}
+		}
+	}
+
+	b.doc.Tags = make([]Tag, 0, len(tags))
+	for name := range tags {
+		b.doc.Tags = append(b.doc.Tags, Tag{Name: name})
+	}
+	sort.Slice(b.doc.Tags, func(i, j int) bool { return b.doc.Tags[i].Name < b.doc.Tags[j].Name })
+
+	return &b.doc
+}
+
+// SpecHandler serves the document as JSON.
+func SpecHandler(doc *Document) (http.HandlerFunc, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("marshal openapi document: %w", err)
+	}
+
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(data)
+	}, nil
+}
+
+// UIHandler serves the Swagger UI page for the document at specURL.
+func UIHandler(specURL string) http.HandlerFunc {
+	page := strings.ReplaceAll(swaggerUIPage, "{{SPEC_URL}}", specURL)
+
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(page))
+	}
+}
+
+func (b *Builder) operationID(handlerName string) string {
+	id := lowerFirst(handlerName)
+
+	b.operationIDs[id]++
+	if n := b.operationIDs[id]; n > 1 {
+		// The same handler may serve several routes (e.g. per-provider SAML endpoints)
+		id = fmt.Sprintf("%s%d", id, n)
+	}
+
+	return id
+}
+
+// convertPath turns /projects/:id into /projects/{id} and returns the path parameters.
+func convertPath(path string) (string, []Parameter) {
+	segments := strings.Split(path, "/")
+	var params []Parameter
+
+	for i, segment := range segments {
+		if name, ok := strings.CutPrefix(segment, ":"); ok {
+			segments[i] = "{" + name + "}"
+			params = append(params, Parameter{
+				Name:     name,
+				In:       "path",
+				Required: true,
+				Schema:   Schema{Type: "string"},
+			})
+		}
+	}
+
+	return strings.Join(segments, "/"), params
+}
+
+// tag returns the resource of the path: the first segment after /api/v1, or the nested resource
+// of a project or tenant (/api/v1/projects/:id/webhooks -> webhooks).
+func tag(path string) string {
+	segments := strings.Split(strings.TrimPrefix(path, "/api/v1/"), "/")
+
+	if len(segments) >= 3 && (segments[0] == "projects" || segments[0] == "tenants") &&
+		strings.HasPrefix(segments[1], ":") && !strings.HasPrefix(segments[2], ":") {
+		return segments[2]
+	}
+
+	return segments[0]
+}
+
+// summary splits a handler name into words: GetLDAPConfig -> "Get LDAP config".
+func summary(handlerName string) string {
+	runes := []rune(handlerName)
+	var words []string
+	start := 0
+
+	for i := 1; i < len(runes); i++ {
+		prev, cur := runes[i-1], runes[i]
+		nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+
+		upperStart := unicode.IsUpper(cur) && (unicode.IsLower(prev) || unicode.IsUpper(prev) && nextLower)
+		digitStart := unicode.IsDigit(cur) && unicode.IsLetter(prev)
+
+		if upperStart || digitStart {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	words = append(words, string(runes[start:]))
+
+	for i := 1; i < len(words); i++ {
+		if !isAcronym(words[i]) {
+			words[i] = strings.ToLower(words[i])
+		}
+	}
+
+	return strings.Join(words, " ")
+}
+
+func isAcronym(word string) bool {
+	upper := 0
+	for _, r := range word {
+		if unicode.IsUpper(r) {
+			upper++
+		}
+	}
+
+	return upper > 1
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+
+	runes := []rune(s)
+	runes[0] = unicode.ToLower(runes[0])
+
+	return string(runes)
+}
+
+func jsonContent(schema Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: schema}}
+}
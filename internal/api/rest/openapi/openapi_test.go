@@ -0,0 +1,61 @@
+package openapi
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummary(t *testing.T) {
+	tests := map[string]string{
+		"ListUsers":               "List users",
+		"GetLDAPConfig":           "Get LDAP config",
+		"Setup2FA":                "Setup 2FA",
+		"Send2FACode":             "Send 2FA code",
+		"ACS":                     "ACS",
+		"GetMyProjectPermissions": "Get my project permissions",
+	}
+
+	for name, want := range tests {
+		assert.Equal(t, want, summary(name), name)
+	}
+}
+
+func TestConvertPath(t *testing.T) {
+	path, params := convertPath("/api/v1/projects/:id/service-accounts/:said")
+
+	assert.Equal(t, "/api/v1/projects/{id}/service-accounts/{said}", path)
+	require.Len(t, params, 2)
+	assert.Equal(t, "id", params[0].Name)
+	assert.Equal(t, "said", params[1].Name)
+	assert.True(t, params[1].Required)
+}
+
+func TestTag(t *testing.T) {
+	assert.Equal(t, "users", tag("/api/v1/users/:id"))
+	assert.Equal(t, "projects", tag("/api/v1/projects/:id"))
+	assert.Equal(t, "webhooks", tag("/api/v1/projects/:id/webhooks"))
+	assert.Equal(t, "audit-log", tag("/api/v1/audit-log/export"))
+}
+
+func TestBuilder_Add(t *testing.T) {
+	b := NewBuilder("API", "", "v1")
+	b.Add(http.MethodGet, "/api/v1/auth/saml/metadata", "GetMetadata", true)
+	b.Add(http.MethodGet, "/api/v1/auth/saml/providers/:provider/metadata", "GetMetadata", true)
+	b.Add(http.MethodPut, "/api/v1/users/:id", "UpdateUser", false)
+
+	doc := b.Document()
+
+	assert.Equal(t, "getMetadata", doc.Paths["/api/v1/auth/saml/metadata"]["get"].OperationID)
+	assert.Equal(t, "getMetadata2", doc.Paths["/api/v1/auth/saml/providers/{provider}/metadata"]["get"].OperationID)
+
+	update := doc.Paths["/api/v1/users/{id}"]["put"]
+	assert.NotNil(t, update.RequestBody)
+	assert.Nil(t, update.Security)
+
+	require.Len(t, doc.Tags, 2)
+	assert.Equal(t, "auth", doc.Tags[0].Name)
+	assert.Equal(t, "users", doc.Tags[1].Name)
+}
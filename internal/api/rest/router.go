@@ -2,6 +2,7 @@ package rest
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
@@ -16,11 +17,14 @@ import (
 	"github.com/rom8726/floxy-pro/plugins/api/dlq"
 	human_decision "github.com/rom8726/floxy-pro/plugins/api/human-decision"
 
+	"github.com/rom8726/floxy-manager/internal/api/rest/apierror"
 	"github.com/rom8726/floxy-manager/internal/api/rest/handlers"
 	"github.com/rom8726/floxy-manager/internal/api/rest/middlewares"
+	"github.com/rom8726/floxy-manager/internal/api/rest/openapi"
 	appcontext "github.com/rom8726/floxy-manager/internal/context"
 	"github.com/rom8726/floxy-manager/internal/contract"
 	"github.com/rom8726/floxy-manager/internal/domain"
+	pkgmiddlewares "github.com/rom8726/floxy-manager/pkg/httpserver/middlewares"
 )
 
 type Router struct {
@@ -47,40 +51,53 @@ func NewRouter(
 	frontendURL string,
 	usersService contract.UsersUseCase,
 	tenantsRepo contract.TenantsRepository,
+	tenantMembershipsRepo contract.TenantMembershipsRepository,
+	globalRolesRepo contract.GlobalRolesRepository,
 	projectsRepo contract.ProjectsRepository,
 	workflowsRepo contract.WorkflowsRepository,
+	workflowsUseCase contract.WorkflowsUseCase,
+	workflowStatsUseCase contract.WorkflowStatsUseCase,
 	permissionsService contract.PermissionsService,
 	rolesRepo contract.RolesRepository,
 	membershipsRepo contract.MembershipsRepository,
 	membershipsSrv contract.MembershipsUseCase,
 	ldapUseCase contract.LDAPSyncUseCase,
 	settingsUseCase contract.SettingsUseCase,
+	licenseService contract.LicenseService,
 	auditLogRepo contract.AuditLogRepository,
+	auditSinksUseCase contract.AuditSinksUseCase,
+	schedulesUseCase contract.SchedulesUseCase,
+	stepLogsUseCase contract.StepLogsUseCase,
+	hooksUseCase contract.HooksUseCase,
+	webhooksUseCase contract.WebhooksUseCase,
+	notificationChannelsUseCase contract.NotificationChannelsUseCase,
+	alertsUseCase contract.AlertsUseCase,
+	apiTokensUseCase contract.APITokensUseCase,
+	serviceAccountsUseCase contract.ServiceAccountsUseCase,
+	projectArchiveUseCase contract.ProjectArchiveUseCase,
+	retentionUseCase contract.RetentionUseCase,
+	instanceHoldsUseCase contract.InstanceHoldsUseCase,
+	instanceSignalsUseCase contract.InstanceSignalsUseCase,
+	variablesUseCase contract.VariablesUseCase,
+	redactionUseCase contract.RedactionUseCase,
+	deletionUseCase contract.DeletionUseCase,
+	projectSettingsUseCase contract.ProjectSettingsUseCase,
+	userPreferencesUseCase contract.UserPreferencesUseCase,
+	samlReconfigurer contract.SAMLReconfigurer,
+	ssoProvidersUseCase contract.SSOProvidersUseCase,
+	emailer contract.Emailer,
+	emailOutboxUseCase contract.EmailOutboxUseCase,
+	migrator contract.Migrator,
+	configReloader contract.ConfigReloader,
+	logControl contract.LogControl,
+	idempotency *middlewares.Idempotency,
 ) (*Router, error) {
 	store := floxy.NewStore(pool)
 	engine := floxy.NewEngine(pool)
 
-	humanDecisionPlugin := human_decision.New(engine, store, func(req *http.Request) (string, error) {
-		username := appcontext.Username(req.Context())
-		if username == "" {
-			return "", errors.New("username not found in context")
-		}
-		return username, nil
-	})
-	cancelPlugin := cancel.New(engine, func(req *http.Request) (string, error) {
-		username := appcontext.Username(req.Context())
-		if username == "" {
-			return "", errors.New("username not found in context")
-		}
-		return username, nil
-	})
-	abortPlugin := abort.New(engine, func(req *http.Request) (string, error) {
-		username := appcontext.Username(req.Context())
-		if username == "" {
-			return "", errors.New("username not found in context")
-		}
-		return username, nil
-	})
+	humanDecisionPlugin := human_decision.New(engine, store, actorFromRequest)
+	cancelPlugin := cancel.New(engine, actorFromRequest)
+	abortPlugin := abort.New(engine, actorFromRequest)
 	dlqPlugin := dlq.New(engine, store)
 	cleanupPlugin := cleanup.New(store)
 
@@ -94,97 +111,331 @@ func NewRouter(
 
 	router := httprouter.New()
 
+	api := &apiRoutes{
+		router: router,
+		spec: openapi.NewBuilder("Floxy Manager API",
+			"REST API of Floxy Manager. The workflow engine API under /api/ is served by floxy plugins and not described here.",
+			"v1"),
+	}
+
+	// readAudit records reads of sensitive resources when enabled in settings
+	readAudit := func(entity string) func(http.Handler) http.Handler {
+		return middlewares.ReadAuditMiddleware(pool, settingsUseCase, middlewares.AuditedEntity(entity))
+	}
+
 	authHandler := handlers.NewAuthHandler(usersService)
 	passwordHandler := handlers.NewPasswordHandler(usersService)
 	twoFAHandler := handlers.NewTwoFAHandler(usersService)
-	ssoHandler := handlers.NewSSOHandler(usersService, frontendURL)
-	tenantsHandler := handlers.NewTenantsHandler(tenantsRepo)
-	projectsHandler := handlers.NewProjectsHandler(projectsRepo, permissionsService, rolesRepo, membershipsRepo)
-	workflowsHandler := handlers.NewWorkflowsHandler(workflowsRepo, permissionsService)
+	ssoHandler := handlers.NewSSOHandler(usersService, settingsUseCase, frontendURL)
+	jwksHandler := handlers.NewJWKSHandler(tokenizer)
+	tenantsHandler := handlers.NewTenantsHandler(
+		tenantsRepo, tenantMembershipsRepo, rolesRepo, usersService, permissionsService, deletionUseCase,
+	)
+	globalRolesHandler := handlers.NewGlobalRolesHandler(globalRolesRepo, rolesRepo, usersService, permissionsService)
+	projectsHandler := handlers.NewProjectsHandler(
+		projectsRepo, permissionsService, rolesRepo, membershipsRepo, deletionUseCase,
+	)
+	workflowsHandler := handlers.NewWorkflowsHandler(
+		workflowsRepo, workflowsUseCase, workflowStatsUseCase, permissionsService, redactionUseCase,
+	)
 	usersHandler := handlers.NewUsersHandler(usersService, projectsRepo, permissionsService)
 	membershipsHandler := handlers.NewMembershipsHandler(membershipsSrv, usersService, permissionsService)
 	ldapHandler := handlers.NewLDAPHandler(ldapUseCase, settingsUseCase)
-	auditLogHandler := handlers.NewAuditLogHandler(auditLogRepo, permissionsService)
-
-	router.POST("/api/v1/auth/login", wrapHandler(authHandler.Login))
-	router.POST("/api/v1/auth/refresh", wrapHandler(authHandler.Refresh))
-	router.POST("/api/v1/auth/forgot-password", wrapHandler(passwordHandler.ForgotPassword))
-	router.POST("/api/v1/auth/reset-password", wrapHandler(passwordHandler.ResetPassword))
-	router.POST("/api/v1/auth/change-password", wrapHandler(passwordHandler.ChangePassword))
-
-	router.GET("/api/v1/auth/sso/providers", wrapHandler(ssoHandler.GetProviders))
-	router.POST("/api/v1/auth/sso/initiate", wrapHandler(ssoHandler.Initiate))
-	router.GET("/api/v1/auth/sso/callback", wrapHandler(ssoHandler.Callback))
-	router.GET("/api/v1/auth/saml/metadata", wrapHandler(ssoHandler.GetMetadata))
-	router.POST("/api/v1/auth/saml/acs", wrapHandler(ssoHandler.ACS))
-
-	router.POST("/api/v1/auth/2fa/verify", wrapHandler(twoFAHandler.Verify2FA))
-	router.POST("/api/v1/auth/2fa/setup", wrapHandler(twoFAHandler.Setup2FA))
-	router.POST("/api/v1/auth/2fa/confirm", wrapHandler(twoFAHandler.Confirm2FA))
-	router.POST("/api/v1/auth/2fa/send-code", wrapHandler(twoFAHandler.Send2FACode))
-	router.POST("/api/v1/auth/2fa/disable", wrapHandler(twoFAHandler.Disable2FA))
-	router.POST("/api/v1/auth/2fa/reset", wrapHandler(twoFAHandler.Reset2FA))
-
-	router.GET("/api/v1/tenants", wrapHandler(tenantsHandler.List))
-	router.POST("/api/v1/tenants", wrapHandler(tenantsHandler.Create))
-	router.PUT("/api/v1/tenants/:id", wrapHandler(tenantsHandler.Update))
-	router.DELETE("/api/v1/tenants/:id", wrapHandler(tenantsHandler.Delete))
-	router.GET("/api/v1/projects", wrapHandler(projectsHandler.List))
-	router.POST("/api/v1/projects", wrapHandler(projectsHandler.Create))
-	router.PUT("/api/v1/projects/:id", wrapHandler(projectsHandler.Update))
-	router.DELETE("/api/v1/projects/:id", wrapHandler(projectsHandler.Delete))
+	settingsHandler := handlers.NewSettingsHandler(settingsUseCase, samlReconfigurer, usersService, emailer)
+	ssoProvidersHandler := handlers.NewSSOProvidersHandler(ssoProvidersUseCase)
+	emailOutboxHandler := handlers.NewEmailOutboxHandler(emailOutboxUseCase)
+	migrationsHandler := handlers.NewMigrationsHandler(migrator)
+	configReloadHandler := handlers.NewConfigReloadHandler(configReloader)
+	logSettingsHandler := handlers.NewLogSettingsHandler(logControl)
+	auditLogHandler := handlers.NewAuditLogHandler(
+		auditLogRepo, permissionsService, settingsUseCase, auditSinksUseCase, licenseService,
+	)
+	schedulesHandler := handlers.NewSchedulesHandler(schedulesUseCase, permissionsService)
+	stepLogsHandler := handlers.NewStepLogsHandler(stepLogsUseCase, permissionsService)
+	hooksHandler := handlers.NewHooksHandler(hooksUseCase, permissionsService)
+	webhooksHandler := handlers.NewWebhooksHandler(webhooksUseCase, permissionsService)
+	notificationsHandler := handlers.NewNotificationChannelsHandler(notificationChannelsUseCase, permissionsService)
+	alertsHandler := handlers.NewAlertsHandler(alertsUseCase, permissionsService)
+	retentionHandler := handlers.NewRetentionHandler(retentionUseCase, permissionsService)
+	projectSettingsHandler := handlers.NewProjectSettingsHandler(projectSettingsUseCase, permissionsService)
+	instanceHoldsHandler := handlers.NewInstanceHoldsHandler(instanceHoldsUseCase, permissionsService)
+	instanceSignalsHandler := handlers.NewInstanceSignalsHandler(instanceSignalsUseCase, permissionsService)
+	variablesHandler := handlers.NewVariablesHandler(variablesUseCase, permissionsService)
+	redactionHandler := handlers.NewRedactionHandler(redactionUseCase, permissionsService)
+	apiTokensHandler := handlers.NewAPITokensHandler(apiTokensUseCase)
+	userPreferencesHandler := handlers.NewUserPreferencesHandler(userPreferencesUseCase)
+	serviceAccountsHandler := handlers.NewServiceAccountsHandler(serviceAccountsUseCase, permissionsService)
+	projectArchiveHandler := handlers.NewProjectArchiveHandler(projectArchiveUseCase, tenantsRepo, permissionsService)
+	licenseHandler := handlers.NewLicenseHandler(settingsUseCase, usersService, licenseService)
+
+	api.POST("/api/v1/auth/login", authHandler.Login)
+	api.POST("/api/v1/auth/refresh", authHandler.Refresh)
+	api.POST("/api/v1/auth/logout", authHandler.Logout)
+	api.POST("/api/v1/auth/forgot-password", passwordHandler.ForgotPassword)
+	api.POST("/api/v1/auth/reset-password", passwordHandler.ResetPassword)
+	api.POST("/api/v1/auth/change-password", passwordHandler.ChangePassword)
+	api.POST("/api/v1/auth/verify-email", authHandler.VerifyEmail)
+	api.POST("/api/v1/auth/verify-email/resend", authHandler.ResendVerificationEmail)
+	api.GET("/api/v1/auth/jwks", jwksHandler.GetJWKS)
+
+	api.GET("/api/v1/auth/sso/providers", ssoHandler.GetProviders)
+	api.POST("/api/v1/auth/sso/initiate", ssoHandler.Initiate)
+	api.GET("/api/v1/auth/sso/callback", ssoHandler.Callback)
+	api.GET("/api/v1/auth/saml/metadata", ssoHandler.GetMetadata)
+	api.POST("/api/v1/auth/saml/acs", ssoHandler.ACS)
+	api.GET("/api/v1/auth/saml/providers/:provider/metadata", ssoHandler.GetMetadata)
+	api.POST("/api/v1/auth/saml/providers/:provider/acs", ssoHandler.ACS)
+
+	api.POST("/api/v1/auth/2fa/verify", twoFAHandler.Verify2FA)
+	api.POST("/api/v1/auth/2fa/setup", twoFAHandler.Setup2FA)
+	api.POST("/api/v1/auth/2fa/confirm", twoFAHandler.Confirm2FA)
+	api.POST("/api/v1/auth/2fa/send-code", twoFAHandler.Send2FACode)
+	api.POST("/api/v1/auth/2fa/disable", twoFAHandler.Disable2FA)
+	api.POST("/api/v1/auth/2fa/reset", twoFAHandler.Reset2FA)
+	api.GET("/api/v1/auth/2fa/recovery-codes", twoFAHandler.GetRecoveryCodes)
+	api.POST("/api/v1/auth/2fa/recovery-codes", twoFAHandler.RegenerateRecoveryCodes)
+	api.POST("/api/v1/auth/2fa/webauthn/register/begin", twoFAHandler.WebAuthnRegisterBegin)
+	api.POST("/api/v1/auth/2fa/webauthn/register/finish", twoFAHandler.WebAuthnRegisterFinish)
+	api.POST("/api/v1/auth/2fa/webauthn/login/begin", twoFAHandler.WebAuthnLoginBegin)
+	api.POST("/api/v1/auth/2fa/webauthn/login/finish", twoFAHandler.WebAuthnLoginFinish)
+
+	api.GET("/api/v1/tenants", tenantsHandler.List)
+	api.POST("/api/v1/tenants", tenantsHandler.Create)
+	api.PUT("/api/v1/tenants/:id", tenantsHandler.Update)
+	api.DELETE("/api/v1/tenants/:id", tenantsHandler.Delete)
+	api.GET("/api/v1/tenants/:id/memberships", tenantsHandler.ListMemberships)
+	api.POST("/api/v1/tenants/:id/memberships", tenantsHandler.CreateMembership, idempotency.Middleware)
+	api.DELETE("/api/v1/tenants/:id/memberships/:mid", tenantsHandler.DeleteMembership)
+
+	api.GET("/api/v1/global-role-assignments", globalRolesHandler.List)
+	api.POST("/api/v1/global-role-assignments", globalRolesHandler.Create)
+	api.DELETE("/api/v1/global-role-assignments/:id", globalRolesHandler.Delete)
+	api.GET("/api/v1/projects", projectsHandler.List)
+	api.POST("/api/v1/projects", projectsHandler.Create)
+	api.PUT("/api/v1/projects/:id", projectsHandler.Update)
+	api.DELETE("/api/v1/projects/:id", projectsHandler.Delete)
+	api.GET("/api/v1/project-export", projectArchiveHandler.ExportProject)
+	api.POST("/api/v1/project-import", projectArchiveHandler.ImportProject)
 
 	// User account endpoints
-	router.GET("/api/v1/users/me", wrapHandler(usersHandler.GetCurrentUser))
-	router.GET("/api/v1/users/me/projects", wrapHandler(usersHandler.GetMyProjects))
-	router.POST("/api/v1/users/me/password", wrapHandler(usersHandler.UpdatePassword))
-	router.GET("/api/v1/users", wrapHandler(usersHandler.ListUsers))
-	router.POST("/api/v1/users", wrapHandler(usersHandler.CreateUser))
-	router.PUT("/api/v1/users/:id/status", wrapHandler(usersHandler.UpdateUserStatus))
-	router.DELETE("/api/v1/users/:id", wrapHandler(usersHandler.DeleteUser))
-
-	// Workflows endpoints
-	router.GET("/api/v1/workflows", wrapHandler(workflowsHandler.ListWorkflows))
-	router.POST("/api/v1/workflows", wrapHandler(workflowsHandler.CreateWorkflow))
-	router.GET("/api/v1/active-workflows", wrapHandler(workflowsHandler.ListActiveWorkflows))
-	router.GET("/api/v1/unassigned-workflows", wrapHandler(workflowsHandler.ListUnassignedWorkflows))
-	router.GET("/api/v1/workflows/:id", wrapHandler(workflowsHandler.GetWorkflow))
-	router.GET("/api/v1/workflows/:id/instances", wrapHandler(workflowsHandler.ListWorkflowInstances))
-	router.GET("/api/v1/instances", wrapHandler(workflowsHandler.ListInstances))
-	router.GET("/api/v1/instances/:id", wrapHandler(workflowsHandler.GetInstance))
-	router.GET("/api/v1/instances/:id/steps", wrapHandler(workflowsHandler.ListInstanceSteps))
-	router.GET("/api/v1/instances/:id/events", wrapHandler(workflowsHandler.ListInstanceEvents))
-	router.GET("/api/v1/stats", wrapHandler(workflowsHandler.ListStats))
-	router.GET("/api/v1/dlq", wrapHandler(workflowsHandler.ListDLQ))
-	router.GET("/api/v1/dlq/:id", wrapHandler(workflowsHandler.GetDLQItem))
+	api.GET("/api/v1/users/me", usersHandler.GetCurrentUser)
+	api.GET("/api/v1/users/me/projects", usersHandler.GetMyProjects)
+	api.POST("/api/v1/users/me/password", usersHandler.UpdatePassword)
+	api.POST("/api/v1/users/me/accept-license", licenseHandler.AcceptLicense)
+	// DELETE routes use :id ("me" or a user ID) because /api/v1/users/:id is already registered for DELETE.
+	api.GET("/api/v1/users/me/sessions", usersHandler.ListSessions)
+	api.DELETE("/api/v1/users/:id/sessions", usersHandler.RevokeAllSessions)
+	api.DELETE("/api/v1/users/:id/sessions/:sid", usersHandler.RevokeSession)
+	api.GET("/api/v1/users/me/tokens", apiTokensHandler.List)
+	api.POST("/api/v1/users/me/tokens", apiTokensHandler.Create)
+	api.DELETE("/api/v1/users/:id/tokens/:tid", apiTokensHandler.Delete)
+	api.GET("/api/v1/users/me/webauthn-credentials", twoFAHandler.ListWebAuthnCredentials)
+	api.GET("/api/v1/users/me/preferences", userPreferencesHandler.GetPreferences)
+	api.DELETE("/api/v1/users/:id/webauthn-credentials/:cid", twoFAHandler.DeleteWebAuthnCredential)
+	api.GET("/api/v1/users", usersHandler.ListUsers, readAudit(domain.EntityUser))
+	api.POST("/api/v1/users", usersHandler.CreateUser)
+	api.POST("/api/v1/users/bulk", usersHandler.BulkUsers)
+	// POST /api/v1/users/:id/... would conflict with /api/v1/users/bulk, the users are in the bodies
+	api.POST("/api/v1/users/merge", usersHandler.MergeUser)
+	api.POST("/api/v1/users/impersonate", usersHandler.Impersonate)
+	// PUT /api/v1/users/me updates the own profile and PUT /api/v1/users/me/preferences the own preferences;
+	// :id is used since /api/v1/users/:id/status is registered for PUT.
+	api.PUT("/api/v1/users/:id", usersHandler.UpdateUser)
+	api.PUT("/api/v1/users/:id/status", usersHandler.UpdateUserStatus)
+	api.PUT("/api/v1/users/:id/preferences", userPreferencesHandler.UpdatePreferences)
+	api.DELETE("/api/v1/users/:id", usersHandler.DeleteUser)
+
+	// Workflows endpoints. Definitions and stats are read often by the UI and rarely change,
+	// so they are compressed and revalidated with ETags.
+	cacheable := []func(http.Handler) http.Handler{pkgmiddlewares.CompressMdw, pkgmiddlewares.ETagMdw}
+	api.GET("/api/v1/workflows", workflowsHandler.ListWorkflows, cacheable...)
+	api.POST("/api/v1/workflows", workflowsHandler.CreateWorkflow, idempotency.Middleware)
+	api.GET("/api/v1/active-workflows", workflowsHandler.ListActiveWorkflows)
+	api.GET("/api/v1/unassigned-workflows", workflowsHandler.ListUnassignedWorkflows)
+	api.GET("/api/v1/workflows/:id", workflowsHandler.GetWorkflow, cacheable...)
+	api.GET("/api/v1/workflow-search", workflowsHandler.SearchWorkflows)
+	api.GET("/api/v1/workflow-export", workflowsHandler.ExportWorkflows)
+	api.PUT("/api/v1/workflows/:id", workflowsHandler.UpdateWorkflow)
+	api.DELETE("/api/v1/workflows/:id", workflowsHandler.DeleteWorkflow)
+	api.POST("/api/v1/workflows/:id/transfer", workflowsHandler.TransferWorkflow)
+	api.GET("/api/v1/workflows/:id/diff", workflowsHandler.DiffWorkflow)
+	api.POST("/api/v1/workflows/:id/simulate", workflowsHandler.SimulateWorkflow)
+	api.GET("/api/v1/workflows/:id/instances", workflowsHandler.ListWorkflowInstances)
+	api.GET("/api/v1/workflows/:id/failures", workflowsHandler.ListWorkflowFailures)
+	api.GET("/api/v1/instances", workflowsHandler.ListInstances)
+	api.GET("/api/v1/instances/:id", workflowsHandler.GetInstance)
+	api.GET("/api/v1/instance-search", workflowsHandler.SearchInstances)
+	api.GET("/api/v1/instances/:id/steps", workflowsHandler.ListInstanceSteps)
+	api.GET("/api/v1/instances/:id/graph", workflowsHandler.GetInstanceGraph)
+	api.GET("/api/v1/instances/:id/events", workflowsHandler.ListInstanceEvents)
+	api.POST("/api/v1/instances/:id/rerun", workflowsHandler.RerunInstance, idempotency.Middleware)
+	api.POST("/api/v1/instances/:id/pause", instanceHoldsHandler.Pause)
+	api.POST("/api/v1/instances/:id/resume", instanceHoldsHandler.Resume)
+	api.GET("/api/v1/instance-holds", instanceHoldsHandler.List)
+	api.POST("/api/v1/instances/:id/signal", instanceSignalsHandler.Signal)
+	api.GET("/api/v1/instances/:id/steps/:sid/logs", stepLogsHandler.Get)
+	api.POST("/api/v1/instances/:id/steps/:sid/logs", stepLogsHandler.Append)
+	api.GET("/api/v1/stats", workflowsHandler.ListStats, cacheable...)
+	api.POST("/api/v1/stats/refresh", workflowsHandler.RefreshStats)
+	api.GET("/api/v1/dlq", workflowsHandler.ListDLQ)
+	api.GET("/api/v1/dlq/:id", workflowsHandler.GetDLQItem)
+
+	// Workflow schedules endpoints
+	api.GET("/api/v1/schedules", schedulesHandler.List)
+	api.POST("/api/v1/schedules", schedulesHandler.Create)
+	api.GET("/api/v1/schedules/:id", schedulesHandler.Get)
+	api.PUT("/api/v1/schedules/:id", schedulesHandler.Update)
+	api.DELETE("/api/v1/schedules/:id", schedulesHandler.Delete)
+	api.POST("/api/v1/schedules/:id/enable", schedulesHandler.Enable)
+	api.POST("/api/v1/schedules/:id/disable", schedulesHandler.Disable)
+	api.GET("/api/v1/schedule-preview", schedulesHandler.Preview)
+
+	// Workflow webhook triggers: management API and public inbound endpoint
+	api.GET("/api/v1/hook-triggers", hooksHandler.List)
+	api.POST("/api/v1/hook-triggers", hooksHandler.Create)
+	api.GET("/api/v1/hook-triggers/:id", hooksHandler.Get)
+	api.PUT("/api/v1/hook-triggers/:id", hooksHandler.Update)
+	api.DELETE("/api/v1/hook-triggers/:id", hooksHandler.Delete)
+	api.POST("/api/v1/hook-triggers/:id/rotate-secret", hooksHandler.RotateSecret)
+	api.POST("/api/v1/hooks/:token", hooksHandler.Trigger, idempotency.Middleware)
 
 	// Project workflows assignment endpoints
-	router.POST("/api/v1/projects/:id/workflows/assign", wrapHandler(workflowsHandler.AssignWorkflowsToProject))
+	api.POST("/api/v1/projects/:id/workflows/assign", workflowsHandler.AssignWorkflowsToProject)
+	api.POST("/api/v1/projects/:id/workflows/import", workflowsHandler.ImportWorkflows)
+
+	// Workflow version pinning: active versions, promotions and rollbacks
+	api.GET("/api/v1/projects/:id/workflow-versions", workflowsHandler.ListActiveWorkflowVersions)
+	api.DELETE("/api/v1/projects/:id/workflow-versions/:name", workflowsHandler.UnpinWorkflowVersion)
+	api.POST("/api/v1/projects/:id/workflow-versions/:name/promote", workflowsHandler.PromoteWorkflowVersion)
+	api.POST("/api/v1/projects/:id/workflow-versions/:name/rollback", workflowsHandler.RollbackWorkflowVersion)
+	api.GET("/api/v1/projects/:id/workflow-versions/:name/history", workflowsHandler.ListWorkflowVersionRollouts)
 
 	// Memberships endpoints
-	router.GET("/api/v1/projects/:id/memberships", wrapHandler(membershipsHandler.ListProjectMemberships))
-	router.POST("/api/v1/projects/:id/memberships", wrapHandler(membershipsHandler.CreateProjectMembership))
-	router.DELETE("/api/v1/projects/:id/memberships/:mid", wrapHandler(membershipsHandler.DeleteProjectMembership))
-	router.GET("/api/v1/roles", wrapHandler(membershipsHandler.ListRoles))
+	api.GET("/api/v1/projects/:id/memberships", membershipsHandler.ListProjectMemberships)
+	api.POST("/api/v1/projects/:id/memberships", membershipsHandler.CreateProjectMembership, idempotency.Middleware)
+	api.DELETE("/api/v1/projects/:id/memberships/:mid", membershipsHandler.DeleteProjectMembership)
+	api.GET("/api/v1/projects/:id/effective-permissions", membershipsHandler.GetEffectivePermissions)
+	api.GET("/api/v1/roles", membershipsHandler.ListRoles)
+	api.GET("/api/v1/roles/:id/permissions", membershipsHandler.GetRolePermissions)
+	api.GET("/api/v1/permissions", membershipsHandler.ListPermissions)
+
+	// Outbound notification webhooks endpoints
+	api.GET("/api/v1/projects/:id/webhooks", webhooksHandler.List)
+	api.POST("/api/v1/projects/:id/webhooks", webhooksHandler.Create)
+	api.GET("/api/v1/projects/:id/webhooks/:wid", webhooksHandler.Get)
+	api.PUT("/api/v1/projects/:id/webhooks/:wid", webhooksHandler.Update)
+	api.DELETE("/api/v1/projects/:id/webhooks/:wid", webhooksHandler.Delete)
+	api.GET("/api/v1/projects/:id/webhooks/:wid/deliveries", webhooksHandler.ListDeliveries)
+
+	// Slack / Teams notification channels endpoints
+	api.GET("/api/v1/projects/:id/notifications", notificationsHandler.List)
+	api.POST("/api/v1/projects/:id/notifications", notificationsHandler.Create)
+	api.GET("/api/v1/projects/:id/notifications/:nid", notificationsHandler.Get)
+	api.PUT("/api/v1/projects/:id/notifications/:nid", notificationsHandler.Update)
+	api.DELETE("/api/v1/projects/:id/notifications/:nid", notificationsHandler.Delete)
+	api.GET("/api/v1/projects/:id/notifications/:nid/messages", notificationsHandler.ListMessages)
+
+	// Service accounts endpoints
+	api.GET("/api/v1/projects/:id/service-accounts", serviceAccountsHandler.List)
+	api.POST("/api/v1/projects/:id/service-accounts", serviceAccountsHandler.Create)
+	api.DELETE("/api/v1/projects/:id/service-accounts/:said", serviceAccountsHandler.Delete)
+	api.GET("/api/v1/projects/:id/service-accounts/:said/tokens", serviceAccountsHandler.ListTokens)
+	api.POST("/api/v1/projects/:id/service-accounts/:said/tokens", serviceAccountsHandler.CreateToken)
+	api.DELETE("/api/v1/projects/:id/service-accounts/:said/tokens/:tid", serviceAccountsHandler.DeleteToken)
+
+	// Email alerts endpoints
+	api.GET("/api/v1/projects/:id/alerts", alertsHandler.GetSettings)
+	api.PUT("/api/v1/projects/:id/alerts", alertsHandler.UpdateSettings)
+
+	// Project settings
+	api.GET("/api/v1/projects/:id/settings", projectSettingsHandler.GetSettings)
+	api.PUT("/api/v1/projects/:id/settings", projectSettingsHandler.UpdateSettings)
+
+	// Project retention settings
+	api.GET("/api/v1/projects/:id/retention", retentionHandler.GetSettings)
+	api.PUT("/api/v1/projects/:id/retention", retentionHandler.UpdateSettings)
+	api.POST("/api/v1/projects/:id/retention/run", retentionHandler.RunRetention)
+
+	// Project variables endpoints
+	api.GET("/api/v1/projects/:id/variables", variablesHandler.List)
+	api.GET("/api/v1/projects/:id/variables/:name", variablesHandler.Get)
+	api.PUT("/api/v1/projects/:id/variables/:name", variablesHandler.Set)
+	api.DELETE("/api/v1/projects/:id/variables/:name", variablesHandler.Delete)
+
+	// Payload redaction rules
+	api.GET("/api/v1/projects/:id/redaction", redactionHandler.GetRules)
+	api.PUT("/api/v1/projects/:id/redaction", redactionHandler.UpdateRules)
+
+	// Runtime settings endpoints
+	api.GET("/api/v1/settings/smtp", settingsHandler.GetSMTPConfig)
+	api.PUT("/api/v1/settings/smtp", settingsHandler.UpdateSMTPConfig)
+	api.POST("/api/v1/settings/smtp/test", settingsHandler.TestSMTPConfig)
+	api.GET("/api/v1/settings/saml", settingsHandler.GetSAMLSettings)
+	api.PUT("/api/v1/settings/saml", settingsHandler.UpdateSAMLSettings)
+	api.GET("/api/v1/settings/general", settingsHandler.GetGeneralSettings)
+	api.PUT("/api/v1/settings/general", settingsHandler.UpdateGeneralSettings)
+
+	// License agreement endpoints
+	api.GET("/api/v1/license", licenseHandler.GetLicense)
+	api.PUT("/api/v1/license", licenseHandler.UpdateLicense)
+	api.GET("/api/v1/license/status", licenseHandler.GetStatus)
+	api.POST("/api/v1/license/install", licenseHandler.Install)
+
+	// SSO provider endpoints
+	api.GET("/api/v1/settings/sso", ssoProvidersHandler.ListProviders)
+	api.POST("/api/v1/settings/sso", ssoProvidersHandler.CreateProvider)
+	api.GET("/api/v1/settings/sso/:name", ssoProvidersHandler.GetProvider)
+	api.PUT("/api/v1/settings/sso/:name", ssoProvidersHandler.UpdateProvider)
+	api.DELETE("/api/v1/settings/sso/:name", ssoProvidersHandler.DeleteProvider)
+	api.POST("/api/v1/settings/sso/:name/enable", ssoProvidersHandler.EnableProvider)
+	api.POST("/api/v1/settings/sso/:name/disable", ssoProvidersHandler.DisableProvider)
+	api.POST("/api/v1/settings/sso-test", ssoProvidersHandler.TestMetadata)
+
+	// Email outbox endpoints
+	api.GET("/api/v1/email-outbox", emailOutboxHandler.List)
+	api.POST("/api/v1/email-outbox/:id/resend", emailOutboxHandler.Resend)
+
+	// Database migrations endpoints
+	api.GET("/api/v1/admin/migrations", migrationsHandler.Status)
+	api.POST("/api/v1/admin/migrations/apply", migrationsHandler.Apply)
+	api.POST("/api/v1/admin/config/reload", configReloadHandler.Reload)
+	api.GET("/api/v1/admin/loglevel", logSettingsHandler.Get)
+	api.PUT("/api/v1/admin/loglevel", logSettingsHandler.Update)
 
 	// LDAP endpoints
-	router.GET("/api/v1/ldap/config", wrapHandler(ldapHandler.GetLDAPConfig))
-	router.POST("/api/v1/ldap/config", wrapHandler(ldapHandler.UpdateLDAPConfig))
-	router.DELETE("/api/v1/ldap/config", wrapHandler(ldapHandler.DeleteLDAPConfig))
-	router.POST("/api/v1/ldap/test-connection", wrapHandler(ldapHandler.TestLDAPConnection))
-	router.POST("/api/v1/ldap/sync/users", wrapHandler(ldapHandler.SyncLDAPUsers))
-	router.DELETE("/api/v1/ldap/sync/cancel", wrapHandler(ldapHandler.CancelLDAPSync))
-	router.GET("/api/v1/ldap/sync/status", wrapHandler(ldapHandler.GetLDAPSyncStatus))
-	router.GET("/api/v1/ldap/sync/progress", wrapHandler(ldapHandler.GetLDAPSyncProgress))
-	router.GET("/api/v1/ldap/sync/logs", wrapHandler(ldapHandler.GetLDAPSyncLogs))
-	router.GET("/api/v1/ldap/sync/logs/:id", wrapHandler(ldapHandler.GetLDAPSyncLogDetails))
-	router.GET("/api/v1/ldap/statistics", wrapHandler(ldapHandler.GetLDAPStatistics))
-
-	router.GET("/api/v1/audit-log", wrapHandler(auditLogHandler.List))
+	api.GET("/api/v1/ldap/config", ldapHandler.GetLDAPConfig, readAudit(domain.EntityLDAPConfig))
+	api.POST("/api/v1/ldap/config", ldapHandler.UpdateLDAPConfig)
+	api.DELETE("/api/v1/ldap/config", ldapHandler.DeleteLDAPConfig)
+	api.POST("/api/v1/ldap/test-connection", ldapHandler.TestLDAPConnection)
+	api.POST("/api/v1/ldap/sync/users", ldapHandler.SyncLDAPUsers)
+	api.DELETE("/api/v1/ldap/sync/cancel", ldapHandler.CancelLDAPSync)
+	api.GET("/api/v1/ldap/sync/status", ldapHandler.GetLDAPSyncStatus)
+	api.GET("/api/v1/ldap/sync/progress", ldapHandler.GetLDAPSyncProgress)
+	api.GET("/api/v1/ldap/sync/logs", ldapHandler.GetLDAPSyncLogs)
+	api.GET("/api/v1/ldap/sync/logs/:id", ldapHandler.GetLDAPSyncLogDetails)
+	api.GET("/api/v1/ldap/statistics", ldapHandler.GetLDAPStatistics)
+
+	api.GET("/api/v1/audit-log", auditLogHandler.List, readAudit(domain.EntityAuditLog))
+	api.GET("/api/v1/audit-log/export", auditLogHandler.Export, readAudit(domain.EntityAuditLog))
+	api.GET("/api/v1/audit-log/export-settings", auditLogHandler.GetExportSettings)
+	api.PUT("/api/v1/audit-log/export-settings", auditLogHandler.UpdateExportSettings)
+	api.GET("/api/v1/audit-log/sinks", auditLogHandler.GetSinks)
+	api.PUT("/api/v1/audit-log/sinks", auditLogHandler.UpdateSinks)
+	api.GET("/api/v1/audit-log/read-access", auditLogHandler.GetReadAccess)
+	api.PUT("/api/v1/audit-log/read-access", auditLogHandler.UpdateReadAccess)
+
+	// The spec is built from the routes registered above, so these two go last.
+	specHandler, err := openapi.SpecHandler(api.spec.Document())
+	if err != nil {
+		return nil, fmt.Errorf("build openapi spec: %w", err)
+	}
+	router.GET("/api/v1/openapi.json", wrapHandler(specHandler))
+	router.GET("/api/v1/docs", wrapHandler(openapi.UIHandler("/api/v1/openapi.json")))
 
 	floxyMux := floxyServer.Mux()
 	auditFloxyMux := middlewares.AuditMiddleware(pool)(floxyMux)
-	protectedFloxyMux := middlewares.RequireAuthMiddleware(tokenizer, usersService)(auditFloxyMux)
+	readAuditFloxyMux := middlewares.ReadAuditMiddleware(pool, settingsUseCase, middlewares.IsDLQRead)(auditFloxyMux)
+	protectedFloxyMux := middlewares.RequireAuthMiddleware(tokenizer, usersService, apiTokensUseCase)(readAuditFloxyMux)
 
 	staticMux := http.NewServeMux()
 	staticFS := http.FileServer(http.Dir("./web/dist/"))
@@ -234,26 +485,26 @@ func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		if isMutatingMethod(req.Method) {
 			// Require auth: user must be set in context by outer middleware
 			if appcontext.UserID(req.Context()) == 0 {
-				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				apierror.Respond(w, http.StatusUnauthorized, "Unauthorized")
 				return
 			}
 
 			projID, ok := extractProjectID(req)
 			if !ok {
-				http.Error(w, "project_id is required (query ?project_id=... or header X-Project-ID or Referer path)", http.StatusBadRequest)
+				apierror.Respond(w, http.StatusBadRequest, "project_id is required (query ?project_id=... or header X-Project-ID or Referer path)")
 				return
 			}
 
 			if err := r.permissionsService.CanManageProject(req.Context(), domain.ProjectID(projID)); err != nil {
 				if errors.Is(err, domain.ErrPermissionDenied) {
-					http.Error(w, "Forbidden", http.StatusForbidden)
+					apierror.Respond(w, http.StatusForbidden, "Forbidden")
 					return
 				}
 				if errors.Is(err, domain.ErrUserNotFound) {
-					http.Error(w, "Unauthorized", http.StatusUnauthorized)
+					apierror.Respond(w, http.StatusUnauthorized, "Unauthorized")
 					return
 				}
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				apierror.Respond(w, http.StatusInternalServerError, "Internal Server Error")
 				return
 			}
 		}
@@ -265,6 +516,19 @@ func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	http.ServeFile(w, req, "./web/dist/index.html")
 }
 
+// actorFromRequest returns the floxy plugin actor resolver. The plugin mux is served behind
+// RequireAuthMiddleware, so the actor is the user it authenticated.
+func actorFromRequest(req *http.Request) (string, error) {
+	ctx := req.Context()
+
+	username := appcontext.Username(ctx)
+	if appcontext.UserID(ctx) == 0 || username == "" {
+		return "", errors.New("username not found in context")
+	}
+
+	return username, nil
+}
+
 func isMutatingMethod(m string) bool {
 	switch m {
 	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
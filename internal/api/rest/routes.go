@@ -0,0 +1,85 @@
+package rest
+
+import (
+	"net/http"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/rom8726/floxy-manager/internal/api/rest/openapi"
+)
+
+// authenticatedAuthRoutes are /api/v1/auth endpoints that require a signed-in user;
+// the rest of them are public.
+var authenticatedAuthRoutes = map[string]struct{}{
+	"/api/v1/auth/change-password":              {},
+	"/api/v1/auth/verify-email/resend":          {},
+	"/api/v1/auth/2fa/setup":                    {},
+	"/api/v1/auth/2fa/confirm":                  {},
+	"/api/v1/auth/2fa/disable":                  {},
+	"/api/v1/auth/2fa/reset":                    {},
+	"/api/v1/auth/2fa/recovery-codes":           {},
+	"/api/v1/auth/2fa/webauthn/register/begin":  {},
+	"/api/v1/auth/2fa/webauthn/register/finish": {},
+}
+
+// apiRoutes registers REST handlers in the router and documents them in the OpenAPI spec.
+type apiRoutes struct {
+	router *httprouter.Router
+	spec   *openapi.Builder
+}
+
+func (a *apiRoutes) GET(path string, fn http.HandlerFunc, mws ...func(http.Handler) http.Handler) {
+	a.handle(http.MethodGet, path, fn, mws)
+}
+
+func (a *apiRoutes) POST(path string, fn http.HandlerFunc, mws ...func(http.Handler) http.Handler) {
+	a.handle(http.MethodPost, path, fn, mws)
+}
+
+func (a *apiRoutes) PUT(path string, fn http.HandlerFunc, mws ...func(http.Handler) http.Handler) {
+	a.handle(http.MethodPut, path, fn, mws)
+}
+
+func (a *apiRoutes) PATCH(path string, fn http.HandlerFunc, mws ...func(http.Handler) http.Handler) {
+	a.handle(http.MethodPatch, path, fn, mws)
+}
+
+func (a *apiRoutes) DELETE(path string, fn http.HandlerFunc, mws ...func(http.Handler) http.Handler) {
+	a.handle(http.MethodDelete, path, fn, mws)
+}
+
+// handle registers fn wrapped into mws, the first middleware being the outermost one.
+func (a *apiRoutes) handle(method, path string, fn http.HandlerFunc, mws []func(http.Handler) http.Handler) {
+	var handler http.Handler = fn
+	for i := len(mws) - 1; i >= 0; i-- {
+		handler = mws[i](handler)
+	}
+
+	a.router.Handle(method, path, wrapHandler(handler.ServeHTTP))
+	a.spec.Add(method, path, handlerName(fn), isPublicRoute(path))
+}
+
+func isPublicRoute(path string) bool {
+	if !strings.HasPrefix(path, "/api/v1/auth/") {
+		return false
+	}
+
+	_, authenticated := authenticatedAuthRoutes[path]
+
+	return !authenticated
+}
+
+// handlerName returns the method name of a handler method value, e.g. ListUsers.
+func handlerName(fn http.HandlerFunc) string {
+	name := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()).Name()
+	name = strings.TrimSuffix(name, "-fm")
+
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+
+	return name
+}
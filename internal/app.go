@@ -2,51 +2,126 @@ package internal
 
 import (
 	"context"
+	"crypto"
+	"crypto/tls"
+	"expvar"
 	"fmt"
 	"log/slog"
 	"net"
 	"net/http"
 	"net/http/pprof"
+	"net/url"
+	"os"
 	"path"
+	"strconv"
+	"strings"
 	"time"
 
+	grpcapi "github.com/rom8726/floxy-manager/internal/api/grpc"
 	"github.com/rom8726/floxy-manager/internal/api/rest"
 	"github.com/rom8726/floxy-manager/internal/api/rest/middlewares"
 	"github.com/rom8726/floxy-manager/internal/config"
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
 	"github.com/rom8726/floxy-manager/internal/contract"
 	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/repository/alerts"
+	"github.com/rom8726/floxy-manager/internal/repository/apitokens"
 	"github.com/rom8726/floxy-manager/internal/repository/auditlog"
+	"github.com/rom8726/floxy-manager/internal/repository/deletion"
+	"github.com/rom8726/floxy-manager/internal/repository/emailoutbox"
+	"github.com/rom8726/floxy-manager/internal/repository/hooks"
+	"github.com/rom8726/floxy-manager/internal/repository/idempotencykeys"
+	"github.com/rom8726/floxy-manager/internal/repository/instanceholds"
+	"github.com/rom8726/floxy-manager/internal/repository/instancesignals"
 	"github.com/rom8726/floxy-manager/internal/repository/ldapsynclogs"
 	"github.com/rom8726/floxy-manager/internal/repository/ldapsyncstats"
 	"github.com/rom8726/floxy-manager/internal/repository/licenses"
+	"github.com/rom8726/floxy-manager/internal/repository/lifecycleevents"
+	"github.com/rom8726/floxy-manager/internal/repository/notificationchannels"
 	"github.com/rom8726/floxy-manager/internal/repository/productinfo"
 	"github.com/rom8726/floxy-manager/internal/repository/projects"
+	"github.com/rom8726/floxy-manager/internal/repository/projectsettings"
 	"github.com/rom8726/floxy-manager/internal/repository/rbac"
+	"github.com/rom8726/floxy-manager/internal/repository/recoverycodes"
+	"github.com/rom8726/floxy-manager/internal/repository/redaction"
+	"github.com/rom8726/floxy-manager/internal/repository/retention"
+	"github.com/rom8726/floxy-manager/internal/repository/samlrequests"
+	"github.com/rom8726/floxy-manager/internal/repository/schedules"
+	"github.com/rom8726/floxy-manager/internal/repository/sessions"
 	"github.com/rom8726/floxy-manager/internal/repository/settings"
+	"github.com/rom8726/floxy-manager/internal/repository/steplogs"
 	"github.com/rom8726/floxy-manager/internal/repository/tenants"
+	"github.com/rom8726/floxy-manager/internal/repository/tenantschemas"
+	"github.com/rom8726/floxy-manager/internal/repository/userpreferences"
 	"github.com/rom8726/floxy-manager/internal/repository/users"
+	"github.com/rom8726/floxy-manager/internal/repository/variables"
+	"github.com/rom8726/floxy-manager/internal/repository/webauthncredentials"
+	"github.com/rom8726/floxy-manager/internal/repository/webhooks"
 	"github.com/rom8726/floxy-manager/internal/repository/workflows"
 	ratelimiter2fa "github.com/rom8726/floxy-manager/internal/services/2fa/ratelimiter"
+	"github.com/rom8726/floxy-manager/internal/services/auditforwarder"
+	"github.com/rom8726/floxy-manager/internal/services/cleaner"
+	"github.com/rom8726/floxy-manager/internal/services/configreloader"
 	"github.com/rom8726/floxy-manager/internal/services/email"
+	"github.com/rom8726/floxy-manager/internal/services/emailsender"
 	"github.com/rom8726/floxy-manager/internal/services/ldap"
+	"github.com/rom8726/floxy-manager/internal/services/license"
+	"github.com/rom8726/floxy-manager/internal/services/logging"
+	"github.com/rom8726/floxy-manager/internal/services/membershipexpirer"
+	"github.com/rom8726/floxy-manager/internal/services/migrator"
+	"github.com/rom8726/floxy-manager/internal/services/notifier"
+	"github.com/rom8726/floxy-manager/internal/services/notifiers"
 	"github.com/rom8726/floxy-manager/internal/services/permissions"
+	"github.com/rom8726/floxy-manager/internal/services/scheduler"
+	"github.com/rom8726/floxy-manager/internal/services/secretsrefresher"
 	ssoprovidermanager "github.com/rom8726/floxy-manager/internal/services/sso/provider-manager"
 	samlprovider "github.com/rom8726/floxy-manager/internal/services/sso/saml"
+	"github.com/rom8726/floxy-manager/internal/services/statsrefresher"
 	"github.com/rom8726/floxy-manager/internal/services/tokenizer"
+	alertsusecase "github.com/rom8726/floxy-manager/internal/usecases/alerts"
+	apitokensusecase "github.com/rom8726/floxy-manager/internal/usecases/apitokens"
+	auditsinksusecase "github.com/rom8726/floxy-manager/internal/usecases/auditsinks"
+	deletionusecase "github.com/rom8726/floxy-manager/internal/usecases/deletion"
+	emailoutboxusecase "github.com/rom8726/floxy-manager/internal/usecases/emailoutbox"
+	hooksusecase "github.com/rom8726/floxy-manager/internal/usecases/hooks"
+	instanceholdsusecase "github.com/rom8726/floxy-manager/internal/usecases/instanceholds"
+	instancesignalsusecase "github.com/rom8726/floxy-manager/internal/usecases/instancesignals"
 	ldapusecase "github.com/rom8726/floxy-manager/internal/usecases/ldap"
+	lifecycleeventsusecase "github.com/rom8726/floxy-manager/internal/usecases/lifecycleevents"
+	notificationchannelsusecase "github.com/rom8726/floxy-manager/internal/usecases/notificationchannels"
+	projectarchiveusecase "github.com/rom8726/floxy-manager/internal/usecases/projectarchive"
 	projectsusecase "github.com/rom8726/floxy-manager/internal/usecases/projects"
+	projectsettingsusecase "github.com/rom8726/floxy-manager/internal/usecases/projectsettings"
 	rbacusecase "github.com/rom8726/floxy-manager/internal/usecases/rbac"
+	redactionusecase "github.com/rom8726/floxy-manager/internal/usecases/redaction"
+	retentionusecase "github.com/rom8726/floxy-manager/internal/usecases/retention"
+	schedulesusecase "github.com/rom8726/floxy-manager/internal/usecases/schedules"
+	serviceaccountsusecase "github.com/rom8726/floxy-manager/internal/usecases/serviceaccounts"
 	settingsusecase "github.com/rom8726/floxy-manager/internal/usecases/settings"
+	ssoprovidersusecase "github.com/rom8726/floxy-manager/internal/usecases/ssoproviders"
+	steplogsusecase "github.com/rom8726/floxy-manager/internal/usecases/steplogs"
+	userpreferencesusecase "github.com/rom8726/floxy-manager/internal/usecases/userpreferences"
 	usersusecase "github.com/rom8726/floxy-manager/internal/usecases/users"
+	variablesusecase "github.com/rom8726/floxy-manager/internal/usecases/variables"
+	webhooksusecase "github.com/rom8726/floxy-manager/internal/usecases/webhooks"
+	workflowsusecase "github.com/rom8726/floxy-manager/internal/usecases/workflows"
+	workflowstatsusecase "github.com/rom8726/floxy-manager/internal/usecases/workflowstats"
 	"github.com/rom8726/floxy-manager/pkg/db"
 	"github.com/rom8726/floxy-manager/pkg/httpserver"
 	pkgmiddlewares "github.com/rom8726/floxy-manager/pkg/httpserver/middlewares"
 	"github.com/rom8726/floxy-manager/pkg/passworder"
+	"github.com/rom8726/floxy-manager/pkg/secrets"
 
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/julienschmidt/httprouter"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rom8726/di"
+	floxy "github.com/rom8726/floxy-pro"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -65,16 +140,27 @@ type App struct {
 	PostgresPool *pgxpool.Pool
 
 	APIServer Serverer
+	// RedirectServer redirects plain HTTP to the API server, nil when not configured.
+	RedirectServer Serverer
+	// GRPCServer is nil when the gRPC API is disabled.
+	GRPCServer Serverer
 
 	container *di.Container
 	diApp     *di.App
+
+	// logControl, requestLimits and reloadedConfig are updated on config reload, see reloadConfig.
+	logControl     *logging.Control
+	requestLimits  *middlewares.RequestLimits
+	reloadedConfig *config.Config
 }
 
-func NewApp(ctx context.Context, cfg *config.Config, logger *slog.Logger) (*App, error) {
+// NewApp creates the app. The logger must use the handler of logControl, which is switched on
+// config reload and by the admin API.
+func NewApp(ctx context.Context, cfg *config.Config, logger *slog.Logger, logControl *logging.Control) (*App, error) {
 	ctx, cancel := context.WithTimeout(ctx, ctxTimeout)
 	defer cancel()
 
-	pgPool, err := newPostgresConnPool(ctx, &cfg.Postgres)
+	pgPool, err := newPostgresConnPool(ctx, &cfg.Postgres, cfg.SecretValue("POSTGRES_PASSWORD"))
 	if err != nil {
 		return nil, fmt.Errorf("create postgres pool: %w", err)
 	}
@@ -83,20 +169,38 @@ func NewApp(ctx context.Context, cfg *config.Config, logger *slog.Logger) (*App,
 	diApp := di.NewApp(container)
 
 	app := &App{
-		Config:       cfg,
-		Logger:       logger,
-		container:    container,
-		diApp:        diApp,
-		PostgresPool: pgPool,
+		Config:         cfg,
+		Logger:         logger,
+		container:      container,
+		diApp:          diApp,
+		PostgresPool:   pgPool,
+		logControl:     logControl,
+		reloadedConfig: cfg,
 	}
 
 	app.registerComponents()
 
-	app.APIServer, err = app.newAPIServer()
+	acmeManager := app.newACMEManager()
+
+	app.APIServer, err = app.newAPIServer(acmeManager)
 	if err != nil {
 		return nil, fmt.Errorf("create API server: %w", err)
 	}
 
+	if cfg.APIServer.HTTPRedirectAddr != "" {
+		app.RedirectServer, err = app.newRedirectServer(acmeManager)
+		if err != nil {
+			return nil, fmt.Errorf("create HTTP redirect server: %w", err)
+		}
+	}
+
+	if cfg.GRPCServer.Addr != "" {
+		app.GRPCServer, err = app.newGRPCServer()
+		if err != nil {
+			return nil, fmt.Errorf("create gRPC server: %w", err)
+		}
+	}
+
 	return app, nil
 }
 
@@ -130,6 +234,14 @@ func (app *App) Run(ctx context.Context) error {
 	group, groupCtx := errgroup.WithContext(ctx)
 	group.Go(func() error { return app.APIServer.ListenAndServe(groupCtx) })
 	group.Go(func() error { return techServer.ListenAndServe(groupCtx) })
+	if app.RedirectServer != nil {
+		app.Logger.Info("Start HTTP redirect server", "addr", app.Config.APIServer.HTTPRedirectAddr)
+		group.Go(func() error { return app.RedirectServer.ListenAndServe(groupCtx) })
+	}
+	if app.GRPCServer != nil {
+		app.Logger.Info("Start gRPC server", "addr", app.Config.GRPCServer.Addr)
+		group.Go(func() error { return app.GRPCServer.ListenAndServe(groupCtx) })
+	}
 	group.Go(func() error { return app.diApp.Run(groupCtx) })
 
 	return group.Wait()
@@ -149,8 +261,17 @@ func (app *App) registerComponents() {
 	app.registerComponent(db.NewTxManager).Arg(app.PostgresPool)
 
 	// Register repositories
+	app.registerComponent(tenantschemas.New).Arg(app.PostgresPool).Arg(&tenantschemas.Config{
+		Enabled: app.Config.TenantIsolation == config.TenantIsolationSchema,
+	})
 	app.registerComponent(projects.New).Arg(app.PostgresPool)
 	app.registerComponent(users.New).Arg(app.PostgresPool)
+	app.registerComponent(sessions.New).Arg(app.PostgresPool)
+	app.registerComponent(apitokens.New).Arg(app.PostgresPool)
+	app.registerComponent(samlrequests.New).Arg(app.PostgresPool)
+	app.registerComponent(idempotencykeys.New).Arg(app.PostgresPool)
+	app.registerComponent(webauthncredentials.New).Arg(app.PostgresPool)
+	app.registerComponent(recoverycodes.New).Arg(app.PostgresPool)
 	app.registerComponent(tenants.New).Arg(app.PostgresPool)
 	app.registerComponent(auditlog.New).Arg(app.PostgresPool)
 	app.registerComponent(ldapsyncstats.New).Arg(app.PostgresPool)
@@ -159,10 +280,28 @@ func (app *App) registerComponents() {
 	app.registerComponent(productinfo.New).Arg(app.PostgresPool)
 	app.registerComponent(settings.New).Arg(app.PostgresPool)
 	app.registerComponent(workflows.New).Arg(app.PostgresPool)
+	app.registerComponent(schedules.New).Arg(app.PostgresPool)
+	app.registerComponent(steplogs.New).Arg(app.PostgresPool)
+	app.registerComponent(hooks.New).Arg(app.PostgresPool)
+	app.registerComponent(webhooks.New).Arg(app.PostgresPool)
+	app.registerComponent(lifecycleevents.New).Arg(app.PostgresPool)
+	app.registerComponent(notificationchannels.New).Arg(app.PostgresPool)
+	app.registerComponent(alerts.New).Arg(app.PostgresPool)
+	app.registerComponent(retention.New).Arg(app.PostgresPool)
+	app.registerComponent(instanceholds.New).Arg(app.PostgresPool)
+	app.registerComponent(instancesignals.New).Arg(app.PostgresPool)
+	app.registerComponent(variables.New).Arg(app.PostgresPool)
+	app.registerComponent(redaction.New).Arg(app.PostgresPool)
+	app.registerComponent(deletion.New).Arg(app.PostgresPool)
+	app.registerComponent(projectsettings.New).Arg(app.PostgresPool)
+	app.registerComponent(userpreferences.New).Arg(app.PostgresPool)
+	app.registerComponent(emailoutbox.New).Arg(app.PostgresPool)
 	// Register RBAC repositories
 	app.registerComponent(rbac.NewRoles).Arg(app.PostgresPool)
 	app.registerComponent(rbac.NewPermissions).Arg(app.PostgresPool)
 	app.registerComponent(rbac.NewMemberships).Arg(app.PostgresPool)
+	app.registerComponent(rbac.NewTenantMemberships).Arg(app.PostgresPool)
+	app.registerComponent(rbac.NewGlobalRoles).Arg(app.PostgresPool)
 
 	// Register permissions service
 	app.registerComponent(permissions.New)
@@ -175,15 +314,156 @@ func (app *App) registerComponents() {
 		KeyFile:       app.Config.Mailer.KeyFile,
 		AllowInsecure: app.Config.Mailer.AllowInsecure,
 		UseTLS:        app.Config.Mailer.UseTLS,
+		StartTLS:      app.Config.Mailer.StartTLS,
 		BaseURL:       app.Config.FrontendURL,
 		From:          app.Config.Mailer.From,
+		AuthMethod:    app.Config.Mailer.AuthMethod,
+		OAuth2: email.OAuth2Config{
+			TokenURL:     app.Config.Mailer.OAuth2.TokenURL,
+			ClientID:     app.Config.Mailer.OAuth2.ClientID,
+			ClientSecret: app.Config.Mailer.OAuth2.ClientSecret,
+			RefreshToken: app.Config.Mailer.OAuth2.RefreshToken,
+			Scopes:       app.Config.Mailer.OAuth2.Scopes,
+		},
 	})
+	app.registerComponent(notifiers.New)
 
 	// Register use cases
 	app.registerComponent(projectsusecase.New)
 	app.registerComponent(ldapusecase.New)
 	app.registerComponent(rbacusecase.New)
-	app.registerComponent(settingsusecase.New).Arg(app.Config.SecretKey)
+	app.registerComponent(settingsusecase.New).Arg(app.Config.SecretKey).Arg(app.settingsDefaults())
+	app.registerComponent(workflowsusecase.New)
+	app.registerComponent(schedulesusecase.New)
+	app.registerComponent(steplogsusecase.New)
+	app.registerComponent(hooksusecase.New).Arg(app.Config.SecretKey)
+	app.registerComponent(webhooksusecase.New)
+	app.registerComponent(auditsinksusecase.New).Arg(app.Config.SecretKey)
+	app.registerComponent(lifecycleeventsusecase.New)
+	app.registerComponent(notificationchannelsusecase.New).Arg(app.Config.SecretKey)
+	app.registerComponent(alertsusecase.New)
+	app.registerComponent(apitokensusecase.New)
+	app.registerComponent(serviceaccountsusecase.New)
+	app.registerComponent(projectarchiveusecase.New)
+	app.registerComponent(instanceholdsusecase.New)
+	app.registerComponent(instancesignalsusecase.New)
+	app.registerComponent(variablesusecase.New).Arg(app.Config.SecretKey)
+	app.registerComponent(redactionusecase.New)
+	app.registerComponent(deletionusecase.New)
+	app.registerComponent(projectsettingsusecase.New)
+	app.registerComponent(userpreferencesusecase.New)
+	app.registerComponent(emailoutboxusecase.New)
+	app.registerComponent(migrator.New).Arg(app.PostgresPool).Arg(&migrator.Config{
+		ConnString:    app.Config.Postgres.ConnString(),
+		MigrationsDir: app.Config.MigrationsDir,
+	})
+	app.registerComponent(func() *logging.Control { return app.logControl })
+	app.registerComponent(configreloader.New).Arg(app.PostgresPool).Arg(configreloader.Apply(app.reloadConfig))
+
+	var configReloader *configreloader.Reloader
+	if err := app.container.Resolve(&configReloader); err != nil {
+		panic(err)
+	}
+
+	// Register workflow engine and scheduler
+	app.registerComponent(newFloxyEngine).Arg(app.PostgresPool)
+	app.registerComponent(scheduler.New).Arg(app.PostgresPool).Arg(&scheduler.Config{
+		Enabled:  app.Config.Scheduler.Enabled,
+		Interval: app.Config.Scheduler.Interval,
+	})
+
+	var schedulerRunner *scheduler.Runner
+	if err := app.container.Resolve(&schedulerRunner); err != nil {
+		panic(err)
+	}
+
+	// Register lifecycle notifications runner
+	app.registerComponent(notifier.New).Arg(app.PostgresPool).Arg(&notifier.Config{
+		Enabled:  app.Config.Notifier.Enabled,
+		Interval: app.Config.Notifier.Interval,
+	})
+
+	var notifierRunner *notifier.Runner
+	if err := app.container.Resolve(&notifierRunner); err != nil {
+		panic(err)
+	}
+
+	// Register queued emails runner, password resets and verifications do not wait for notifications
+	app.registerComponent(emailsender.New).Arg(app.PostgresPool).Arg(&emailsender.Config{
+		Enabled:  app.Config.EmailOutbox.Enabled,
+		Interval: app.Config.EmailOutbox.Interval,
+	})
+
+	var emailSender *emailsender.Runner
+	if err := app.container.Resolve(&emailSender); err != nil {
+		panic(err)
+	}
+
+	// Register time-bound memberships expiry runner
+	app.registerComponent(membershipexpirer.New).Arg(app.PostgresPool).Arg(&membershipexpirer.Config{
+		Enabled:  app.Config.MembershipExpiry.Enabled,
+		Interval: app.Config.MembershipExpiry.Interval,
+	})
+
+	var membershipExpirer *membershipexpirer.Runner
+	if err := app.container.Resolve(&membershipExpirer); err != nil {
+		panic(err)
+	}
+
+	// Register audit log forwarding runner
+	app.registerComponent(auditforwarder.New).Arg(app.PostgresPool).Arg(&auditforwarder.Config{
+		Enabled:  app.Config.AuditForward.Enabled,
+		Interval: app.Config.AuditForward.Interval,
+	})
+
+	var auditForwarder *auditforwarder.Runner
+	if err := app.container.Resolve(&auditForwarder); err != nil {
+		panic(err)
+	}
+
+	// Register retention runner, it also runs the engine store cleanup
+	app.registerComponent(newFloxyStore).Arg(app.PostgresPool)
+	app.registerComponent(retentionusecase.New)
+	app.registerComponent(cleaner.New).Arg(app.PostgresPool).Arg(&cleaner.Config{
+		Enabled:  app.Config.Retention.Enabled,
+		Interval: app.Config.Retention.Interval,
+	})
+
+	var cleanerRunner *cleaner.Runner
+	if err := app.container.Resolve(&cleanerRunner); err != nil {
+		panic(err)
+	}
+
+	// Register workflow stats refresh runner
+	app.registerComponent(workflowstatsusecase.New)
+	app.registerComponent(statsrefresher.New).Arg(app.PostgresPool).Arg(&statsrefresher.Config{
+		Enabled:  app.Config.StatsRefresh.Enabled,
+		Interval: app.Config.StatsRefresh.Interval,
+	})
+
+	var statsRefresher *statsrefresher.Runner
+	if err := app.container.Resolve(&statsRefresher); err != nil {
+		panic(err)
+	}
+
+	// Register secrets refresh runner
+	app.registerComponent(secretsrefresher.New).Arg(app.Config.SecretsResolver()).Arg(&secretsrefresher.Config{
+		Interval: app.Config.Secrets.RefreshInterval,
+	})
+
+	var secretsRefresher *secretsrefresher.Runner
+	if err := app.container.Resolve(&secretsRefresher); err != nil {
+		panic(err)
+	}
+
+	// Register license service, it gates the enterprise features
+	app.registerComponent(license.New).Arg(&license.Params{PublicKey: app.Config.License.Key()})
+
+	if app.Config.License.File != "" {
+		if err := app.installLicenseFile(); err != nil {
+			slog.Error("License file is not installed, licensed features stay disabled", "error", err)
+		}
+	}
 
 	// Register LDAP service
 	app.registerComponent(ldap.New)
@@ -196,46 +476,325 @@ func (app *App) registerComponents() {
 	// Initialize SSO provider manager
 	app.registerComponent(ssoprovidermanager.New)
 
-	// Initialize SAML provider
-	app.registerComponent(samlprovider.New).Arg(&samlprovider.SAMLParams{
-		Name:        domain.SSOProviderNameADSaml,
-		DisplayName: "Sign in with Active Directory",
-		IconURL:     "",
-		Config: &domain.SAMLConfig{
-			Enabled:          app.Config.SAML.Enabled,
-			CreateCerts:      app.Config.SAML.CreateCerts,
-			EntityID:         app.Config.SAML.EntityID,
-			CertificatePath:  app.Config.SAML.CertificatePath,
-			PrivateKeyPath:   app.Config.SAML.PrivateKeyPath,
-			IDPMetadataURL:   app.Config.SAML.IDPMetadataURL,
-			SSOURL:           app.Config.SAML.SSOURL,
-			AttributeMapping: app.Config.SAML.AttributeMapping,
-			CallbackURL:      path.Join(app.Config.FrontendURL, "/api/v1/auth/sso/callback"),
-			PublicRootURL:    app.Config.FrontendURL,
-			SkipTLSVerify:    app.Config.SAML.SkipTLSVerify,
-		},
-	})
+	// Initialize SAML provider, saved settings take precedence over the environment
+	app.registerComponent(samlprovider.New).Arg(app.defaultSAMLParams())
 
 	var samlProvider *samlprovider.SAMLProvider
 	if err := app.container.Resolve(&samlProvider); err != nil {
 		panic(err)
 	}
 
+	// Initialize additional named SAML providers
+	if len(app.Config.SAMLProviders) > 0 {
+		var ssoManager contract.SSOProviderManager
+		if err := app.container.Resolve(&ssoManager); err != nil {
+			panic(err)
+		}
+
+		var usersRepo contract.UsersRepository
+		if err := app.container.Resolve(&usersRepo); err != nil {
+			panic(err)
+		}
+
+		var txManager db.TxManager
+		if err := app.container.Resolve(&txManager); err != nil {
+			panic(err)
+		}
+
+		var membershipsRepo contract.MembershipsRepository
+		if err := app.container.Resolve(&membershipsRepo); err != nil {
+			panic(err)
+		}
+
+		var rolesRepo contract.RolesRepository
+		if err := app.container.Resolve(&rolesRepo); err != nil {
+			panic(err)
+		}
+
+		var samlRequestsRepo contract.SAMLRequestsRepository
+		if err := app.container.Resolve(&samlRequestsRepo); err != nil {
+			panic(err)
+		}
+
+		var permissionsService contract.PermissionsService
+		if err := app.container.Resolve(&permissionsService); err != nil {
+			panic(err)
+		}
+
+		var licenseFeatures contract.LicenseFeatures
+		if err := app.container.Resolve(&licenseFeatures); err != nil {
+			panic(err)
+		}
+
+		for _, name := range app.Config.SAMLProviders {
+			providerCfg := app.Config.SAMLProviderConfigs[name]
+
+			displayName := providerCfg.DisplayName
+			if displayName == "" {
+				displayName = "Sign in with " + name
+			}
+
+			params := app.samlParams(name, displayName, providerCfg.IconURL, &providerCfg.SAMLConfig)
+			_, err := samlprovider.New(
+				params, ssoManager, usersRepo, txManager, membershipsRepo, rolesRepo, samlRequestsRepo,
+				permissionsService, licenseFeatures,
+			)
+			if err != nil {
+				panic(fmt.Errorf("init SAML provider %q: %w", name, err))
+			}
+		}
+	}
+
+	// Load the SAML providers managed at runtime, the ones of the environment cannot be overridden
+	app.registerComponent(samlprovider.NewLoader)
+	app.registerComponent(ssoprovidersusecase.New).Arg(&ssoprovidersusecase.Config{
+		ReservedNames: append([]string{domain.SSOProviderNameADSaml}, app.Config.SAMLProviders...),
+	})
+
+	var ssoProvidersUseCase contract.SSOProvidersUseCase
+	if err := app.container.Resolve(&ssoProvidersUseCase); err != nil {
+		panic(err)
+	}
+
+	if err := ssoProvidersUseCase.LoadAll(context.Background()); err != nil {
+		panic(fmt.Errorf("load SSO providers: %w", err))
+	}
+
 	app.registerComponent(usersusecase.New).Arg([]usersusecase.AuthProvider{
 		ldap.NewAuthService(ldapService.(*ldap.Service)), //nolint:forcetypeassert // ldapService guaranteed
 	})
 
 	// Register services
+	signing := app.Config.JWTSigning
+
+	var signer crypto.Signer
+	if signing.Algorithm != tokenizer.AlgorithmHS256 {
+		var err error
+		signer, err = tokenizer.LoadSigner(context.Background(), signing.PrivateKey)
+		if err != nil {
+			panic(fmt.Errorf("load JWT signing key: %w", err))
+		}
+	}
+
 	app.registerComponent(tokenizer.New).Arg(&tokenizer.ServiceParams{
 		SecretKey:        []byte(app.Config.JWTSecretKey),
+		Algorithm:        signing.Algorithm,
+		Signer:           signer,
+		KeyID:            signing.KeyID,
 		AccessTTL:        app.Config.AccessTokenTTL,
 		RefreshTTL:       app.Config.RefreshTokenTTL,
 		ResetPasswordTTL: app.Config.ResetPasswordTTL,
+		VerifyEmailTTL:   app.Config.VerifyEmailTTL,
+		ImpersonationTTL: app.Config.ImpersonationTTL,
 	})
 	app.registerComponent(ratelimiter2fa.New)
+	app.registerComponent(webauthn.New).Arg(app.webAuthnConfig())
+}
+
+// settingsDefaults returns the runtime-tunable settings of the environment, they apply until saved.
+func (app *App) settingsDefaults() *settingsusecase.Defaults {
+	saml := app.samlParams(domain.SSOProviderNameADSaml, "", "", &app.Config.SAML).Config
+
+	return &settingsusecase.Defaults{
+		SMTP: smtpDefaults(&app.Config.Mailer),
+		SAML: domain.SAMLSettings{
+			Enabled:          saml.Enabled,
+			EntityID:         saml.EntityID,
+			IDPMetadataURL:   saml.IDPMetadataURL,
+			SSOURL:           saml.SSOURL,
+			AttributeMapping: saml.AttributeMapping,
+			SkipTLSVerify:    saml.SkipTLSVerify,
+			GroupAttribute:   saml.GroupAttribute,
+			GroupMappings:    saml.GroupMappings,
+		},
+		General: domain.GeneralSettings{
+			FrontendURL: app.Config.FrontendURL,
+		},
+		RotatedSMTPCredentials: app.rotatedMailerCredentials,
+	}
+}
+
+// installLicenseFile installs the license of LICENSE_FILE when it is newer than the installed one,
+// so a license renewed through the API is not replaced by an older file on restart.
+func (app *App) installLicenseFile() error {
+	var licenseService contract.LicenseService
+	if err := app.container.Resolve(&licenseService); err != nil {
+		return fmt.Errorf("resolve license service component: %w", err)
+	}
+
+	licenseText, err := os.ReadFile(app.Config.License.File)
+	if err != nil {
+		return fmt.Errorf("read license file: %w", err)
+	}
+
+	status, installed, err := licenseService.InstallIfNewer(context.Background(), string(licenseText))
+	if err != nil {
+		return fmt.Errorf("install license file: %w", err)
+	}
+
+	if !installed {
+		slog.Info("License file is not newer than the installed license", "id", status.ID,
+			"expires_at", status.ExpiresAt)
+
+		return nil
+	}
+
+	slog.Info("License installed", "id", status.ID, "type", status.Type, "expires_at", status.ExpiresAt)
+
+	return nil
+}
+
+// defaultSAMLParams builds the parameters of the default SAML provider with the saved SAML settings.
+func (app *App) defaultSAMLParams() *samlprovider.SAMLParams {
+	params := app.samlParams(
+		domain.SSOProviderNameADSaml, "Sign in with Active Directory", "", &app.Config.SAML,
+	)
+
+	var settingsUseCase contract.SettingsUseCase
+	if err := app.container.Resolve(&settingsUseCase); err != nil {
+		panic(err)
+	}
+
+	ctx := context.Background()
+
+	samlSettings, err := settingsUseCase.GetSAMLSettings(ctx)
+	if err != nil {
+		panic(fmt.Errorf("get SAML settings: %w", err))
+	}
+
+	general, err := settingsUseCase.GetGeneralSettings(ctx)
+	if err != nil {
+		panic(fmt.Errorf("get general settings: %w", err))
+	}
+
+	config := samlSettings.Apply(*params.Config)
+	config.PublicRootURL = general.FrontendURL
+	config.CallbackURL = strings.TrimRight(general.FrontendURL, "/") + "/api/v1/auth/sso/callback"
+	params.Config = &config
+
+	return params
+}
+
+// webAuthnConfig builds the relying party settings for security keys.
+// The RP ID and origin default to the frontend URL.
+func (app *App) webAuthnConfig() *webauthn.Config {
+	cfg := app.Config.WebAuthn
+
+	rpID := cfg.RPID
+	if rpID == "" {
+		if frontendURL, err := url.Parse(app.Config.FrontendURL); err == nil {
+			rpID = frontendURL.Hostname()
+		}
+	}
+
+	origins := cfg.RPOrigins
+	if len(origins) == 0 {
+		origins = []string{strings.TrimRight(app.Config.FrontendURL, "/")}
+	}
+
+	return &webauthn.Config{
+		RPID:          rpID,
+		RPDisplayName: cfg.RPDisplayName,
+		RPOrigins:     origins,
+	}
 }
 
-func (app *App) newAPIServer() (Serverer, error) {
+func (app *App) samlParams(
+	name, displayName, iconURL string,
+	cfg *config.SAMLConfig,
+) *samlprovider.SAMLParams {
+	groupMappings := make([]domain.SAMLGroupMapping, 0, len(cfg.GroupMapping))
+	for _, item := range cfg.GroupMapping {
+		groupMappings = append(groupMappings, domain.SAMLGroupMapping{
+			Group:     item.Group,
+			ProjectID: domain.ProjectID(item.ProjectID),
+			RoleKey:   item.Role,
+		})
+	}
+
+	return &samlprovider.SAMLParams{
+		Name:        name,
+		DisplayName: displayName,
+		IconURL:     iconURL,
+		Config: &domain.SAMLConfig{
+			Enabled:          cfg.Enabled,
+			CreateCerts:      cfg.CreateCerts,
+			EntityID:         cfg.EntityID,
+			CertificatePath:  cfg.CertificatePath,
+			PrivateKeyPath:   cfg.PrivateKeyPath,
+			IDPMetadataURL:   cfg.IDPMetadataURL,
+			SSOURL:           cfg.SSOURL,
+			AttributeMapping: cfg.AttributeMapping,
+			CallbackURL:      path.Join(app.Config.FrontendURL, "/api/v1/auth/sso/callback"),
+			PublicRootURL:    app.Config.FrontendURL,
+			SkipTLSVerify:    cfg.SkipTLSVerify,
+			GroupAttribute:   cfg.GroupAttribute,
+			GroupMappings:    groupMappings,
+		},
+	}
+}
+
+func newFloxyEngine(pool *pgxpool.Pool) *floxy.Engine {
+	return floxy.NewEngine(pool)
+}
+
+func newFloxyStore(pool *pgxpool.Pool) *floxy.StoreImpl {
+	return floxy.NewStore(pool)
+}
+
+// newACMEManager returns the certificate manager of the API server, nil without ACME domains.
+func (app *App) newACMEManager() *autocert.Manager {
+	cfg := app.Config.APIServer.ACME
+	if len(cfg.Domains) == 0 {
+		return nil
+	}
+
+	manager := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(cfg.Domains...),
+		Cache:      autocert.DirCache(cfg.CacheDir),
+		Email:      cfg.Email,
+	}
+
+	if cfg.DirectoryURL != "" {
+		manager.Client = &acme.Client{DirectoryURL: cfg.DirectoryURL}
+	}
+
+	return manager
+}
+
+// newRedirectServer serves plain HTTP redirecting to the API server, it also answers
+// the HTTP-01 challenges of ACME.
+func (app *App) newRedirectServer(acmeManager *autocert.Manager) (Serverer, error) {
+	cfg := app.Config.APIServer
+
+	_, httpsPort, err := net.SplitHostPort(cfg.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("parse API server address %q: %w", cfg.Addr, err)
+	}
+
+	handler := httpserver.RedirectHTTPS(httpsPort)
+	if acmeManager != nil {
+		handler = acmeManager.HTTPHandler(handler)
+	}
+
+	lis, err := net.Listen("tcp", cfg.HTTPRedirectAddr) //nolint:noctx // need to refactor
+	if err != nil {
+		return nil, fmt.Errorf("listen %q: %w", cfg.HTTPRedirectAddr, err)
+	}
+
+	return &httpserver.Server{
+		Listener:          lis,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+		Handler:           handler,
+	}, nil
+}
+
+func (app *App) newAPIServer(acmeManager *autocert.Manager) (Serverer, error) {
 	cfg := app.Config.APIServer
 
 	var tokenizerSrv contract.Tokenizer
@@ -248,19 +807,43 @@ func (app *App) newAPIServer() (Serverer, error) {
 		return nil, fmt.Errorf("resolve users service component: %w", err)
 	}
 
+	var apiTokensSrv contract.APITokensUseCase
+	if err := app.container.Resolve(&apiTokensSrv); err != nil {
+		return nil, fmt.Errorf("resolve api tokens service component: %w", err)
+	}
+
+	var settingsUseCase contract.SettingsUseCase
+	if err := app.container.Resolve(&settingsUseCase); err != nil {
+		return nil, fmt.Errorf("resolve settings use case component: %w", err)
+	}
+
+	var tenantMembershipsRepo contract.TenantMembershipsRepository
+	if err := app.container.Resolve(&tenantMembershipsRepo); err != nil {
+		return nil, fmt.Errorf("resolve tenant memberships repository component: %w", err)
+	}
+
+	app.registerComponent(middlewares.NewIdempotency).Arg(&middlewares.IdempotencyConfig{TTL: app.Config.IdempotencyTTL})
 	app.registerComponent(rest.NewRouter).Arg(app.PostgresPool).Arg(app.Config.FrontendURL)
 	var apiRouter *rest.Router
 	if err := app.container.Resolve(&apiRouter); err != nil {
 		return nil, fmt.Errorf("resolve api router component: %w", err)
 	}
 
+	app.requestLimits = middlewares.NewRequestLimits(requestLimits(&app.Config.RequestLimits))
+
 	handler := pkgmiddlewares.CORSMdw(
 		middlewares.WithRawRequest(
-			middlewares.RequestIDMdw(
-				middlewares.AuthMiddleware(tokenizerSrv, usersSrv)(
-					apiRouter,
+			middlewares.ClientIPMdw(cfg.TrustedProxyPrefixes())(middlewares.RequestIDMdw(
+				middlewares.AuthMiddleware(tokenizerSrv, usersSrv, apiTokensSrv)(
+					middlewares.TenantMdw(tenantMembershipsRepo)(middlewares.AccessLogMdw(
+						middlewares.LicenseMdw(settingsUseCase, usersSrv)(
+							middlewares.QueryTimeoutMdw(
+								app.requestLimits.Middleware()(apiRouter),
+							),
+						),
+					)),
 				),
-			),
+			)),
 		),
 	)
 
@@ -269,27 +852,68 @@ func (app *App) newAPIServer() (Serverer, error) {
 		return nil, fmt.Errorf("listen %q: %w", cfg.Addr, err)
 	}
 
+	// HTTP/2 is negotiated over TLS by the standard server
+	if acmeManager != nil {
+		return &httpserver.ServerTLS{
+			Listener:          lis,
+			ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+			ReadTimeout:       cfg.ReadTimeout,
+			WriteTimeout:      cfg.WriteTimeout,
+			IdleTimeout:       cfg.IdleTimeout,
+			MaxHeaderBytes:    cfg.MaxHeaderBytes,
+			Handler:           handler,
+			TLSConfig:         acmeManager.TLSConfig(),
+		}, nil
+	}
+
 	if cfg.UseTLS {
 		return &httpserver.ServerTLS{
-			Listener:     lis,
-			ReadTimeout:  cfg.ReadTimeout,
-			WriteTimeout: cfg.WriteTimeout,
-			IdleTimeout:  cfg.IdleTimeout,
-			Handler:      handler,
-			CertFile:     cfg.CertFile,
-			KeyFile:      cfg.KeyFile,
+			Listener:          lis,
+			ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+			ReadTimeout:       cfg.ReadTimeout,
+			WriteTimeout:      cfg.WriteTimeout,
+			IdleTimeout:       cfg.IdleTimeout,
+			MaxHeaderBytes:    cfg.MaxHeaderBytes,
+			Handler:           handler,
+			CertFile:          cfg.CertFile,
+			KeyFile:           cfg.KeyFile,
+			TLSConfig:         &tls.Config{MinVersion: tls.VersionTLS12},
 		}, nil
 	}
 
 	return &httpserver.Server{
-		Listener:     lis,
-		ReadTimeout:  cfg.ReadTimeout,
-		WriteTimeout: cfg.WriteTimeout,
-		IdleTimeout:  cfg.IdleTimeout,
-		Handler:      handler,
+		Listener:          lis,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+		Handler:           handler,
 	}, nil
 }
 
+func (app *App) newGRPCServer() (Serverer, error) {
+	cfg := app.Config.GRPCServer
+
+	app.registerComponent(grpcapi.NewServer).Arg(&grpcapi.Config{
+		Addr:              cfg.Addr,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		KeepaliveTime:     cfg.KeepaliveTime,
+		KeepaliveTimeout:  cfg.KeepaliveTimeout,
+		CertFile:          cfg.CertFile,
+		KeyFile:           cfg.KeyFile,
+		UseTLS:            cfg.UseTLS,
+	})
+
+	var grpcServer *grpcapi.Server
+	if err := app.container.Resolve(&grpcServer); err != nil {
+		return nil, fmt.Errorf("resolve grpc server component: %w", err)
+	}
+
+	return grpcServer, nil
+}
+
 func (app *App) newTechServer() (*httpserver.Server, error) {
 	cfg := app.Config.TechServer
 	lis, err := net.Listen("tcp", cfg.Addr) //nolint:noctx // need to refactor
@@ -308,24 +932,30 @@ func (app *App) newTechServer() (*httpserver.Server, error) {
 
 	router.Handler(http.MethodGet, "/metrics", promhttp.Handler())
 
-	router.HandlerFunc(http.MethodGet, "/debug/pprof", pprof.Index)
-	router.HandlerFunc(http.MethodGet, "/debug/pprof/cmdline", pprof.Cmdline)
-	router.HandlerFunc(http.MethodGet, "/debug/pprof/profile", pprof.Profile)
-	router.HandlerFunc(http.MethodGet, "/debug/pprof/symbol", pprof.Symbol)
-	router.HandlerFunc(http.MethodGet, "/debug/pprof/trace", pprof.Trace)
-	router.Handler(http.MethodGet, "/debug/pprof/allocs", pprof.Handler("allocs"))
-	router.Handler(http.MethodGet, "/debug/pprof/block", pprof.Handler("block"))
-	router.Handler(http.MethodGet, "/debug/pprof/goroutine", pprof.Handler("goroutine"))
-	router.Handler(http.MethodGet, "/debug/pprof/heap", pprof.Handler("heap"))
-	router.Handler(http.MethodGet, "/debug/pprof/mutex", pprof.Handler("mutex"))
-	router.Handler(http.MethodGet, "/debug/pprof/threadcreate", pprof.Handler("threadcreate"))
+	if cfg.DebugEndpoints {
+		router.HandlerFunc(http.MethodGet, "/debug/pprof", pprof.Index)
+		router.HandlerFunc(http.MethodGet, "/debug/pprof/cmdline", pprof.Cmdline)
+		router.HandlerFunc(http.MethodGet, "/debug/pprof/profile", pprof.Profile)
+		router.HandlerFunc(http.MethodGet, "/debug/pprof/symbol", pprof.Symbol)
+		router.HandlerFunc(http.MethodGet, "/debug/pprof/trace", pprof.Trace)
+		router.Handler(http.MethodGet, "/debug/pprof/allocs", pprof.Handler("allocs"))
+		router.Handler(http.MethodGet, "/debug/pprof/block", pprof.Handler("block"))
+		router.Handler(http.MethodGet, "/debug/pprof/goroutine", pprof.Handler("goroutine"))
+		router.Handler(http.MethodGet, "/debug/pprof/heap", pprof.Handler("heap"))
+		router.Handler(http.MethodGet, "/debug/pprof/mutex", pprof.Handler("mutex"))
+		router.Handler(http.MethodGet, "/debug/pprof/threadcreate", pprof.Handler("threadcreate"))
+
+		router.Handler(http.MethodGet, "/debug/vars", expvar.Handler())
+	}
 
 	return &httpserver.Server{
-		Listener:     lis,
-		ReadTimeout:  cfg.ReadTimeout,
-		WriteTimeout: cfg.WriteTimeout,
-		IdleTimeout:  cfg.IdleTimeout,
-		Handler:      router,
+		Listener:          lis,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+		Handler:           router,
 	}, nil
 }
 
@@ -347,15 +977,7 @@ func (app *App) ensureSuperuser(ctx context.Context) error {
 		return nil
 	}
 
-	// Extract username from email (part before @)
-	username := app.Config.AdminEmail
-	for i, c := range username {
-		if c == '@' {
-			username = username[:i]
-
-			break
-		}
-	}
+	username := usernameFromEmail(app.Config.AdminEmail)
 
 	// Hash the temporary password
 	passwordHash, err := passworder.PasswordHash(app.Config.AdminTmpPassword)
@@ -371,6 +993,7 @@ func (app *App) ensureSuperuser(ctx context.Context) error {
 		IsSuperuser:   true,
 		IsTmpPassword: true,
 		IsExternal:    false,
+		EmailVerified: true,
 	}
 
 	user, err := usersRepo.Create(ctx, userDTO)
@@ -383,16 +1006,73 @@ func (app *App) ensureSuperuser(ctx context.Context) error {
 	return nil
 }
 
-func newPostgresConnPool(ctx context.Context, cfg *config.Postgres) (*pgxpool.Pool, error) {
+// rotatedMailerCredentials sets the current values of the mail server secrets that are references.
+func (app *App) rotatedMailerCredentials(config *domain.SMTPConfig) {
+	if value := app.Config.SecretValue("MAILER_PASSWORD"); value != nil {
+		config.Password = value.Get()
+	}
+
+	if value := app.Config.SecretValue("MAILER_OAUTH2_CLIENT_SECRET"); value != nil {
+		config.OAuth2ClientSecret = value.Get()
+	}
+
+	if value := app.Config.SecretValue("MAILER_OAUTH2_REFRESH_TOKEN"); value != nil {
+		config.OAuth2RefreshToken = value.Get()
+	}
+}
+
+// sessionVarNames are the session variables row-level security policies can use, see sessionVarValues.
+var sessionVarNames = []string{"app.user_id", "app.tenant_id", "app.is_superuser"}
+
+// sessionVarValues are the user, the tenant of the request and the superuser flag, empty when unknown.
+func sessionVarValues(ctx context.Context) []string {
+	values := make([]string, len(sessionVarNames))
+	if userID := appcontext.UserID(ctx); userID != 0 {
+		values[0] = strconv.FormatUint(uint64(userID), 10)
+	}
+
+	if tenantID := appcontext.TenantID(ctx); tenantID != 0 {
+		values[1] = strconv.Itoa(int(tenantID))
+	}
+
+	values[2] = strconv.FormatBool(appcontext.IsSuper(ctx))
+
+	return values
+}
+
+// newPostgresConnPool creates the pool, new connections use the current password when it is a rotated secret.
+func newPostgresConnPool(ctx context.Context, cfg *config.Postgres, password *secrets.Value) (*pgxpool.Pool, error) {
 	pgCfg, err := pgxpool.ParseConfig(cfg.ConnStringWithPoolSize())
 	if err != nil {
 		return nil, fmt.Errorf("parse config: %w", err)
 	}
 
 	pgCfg.MaxConnLifetime = cfg.ConnMaxLifetime
-	pgCfg.MaxConnLifetimeJitter = time.Second * 5
+	pgCfg.MaxConnLifetimeJitter = cfg.ConnMaxLifetimeJitter
 	pgCfg.MaxConnIdleTime = cfg.MaxIdleConnTime
-	pgCfg.HealthCheckPeriod = time.Second * 5
+	pgCfg.HealthCheckPeriod = cfg.HealthCheckPeriod
+
+	if password != nil {
+		pgCfg.BeforeConnect = func(_ context.Context, connCfg *pgx.ConnConfig) error {
+			connCfg.Password = password.Get()
+
+			return nil
+		}
+	}
+
+	if cfg.SessionContext {
+		sessionVars := db.NewSessionVars(sessionVarNames, sessionVarValues)
+		pgCfg.PrepareConn = sessionVars.PrepareConn
+		pgCfg.BeforeClose = sessionVars.BeforeClose
+	}
+
+	statsTracer, err := db.NewQueryStatsTracer(cfg.SlowQueryThreshold, prometheus.DefaultRegisterer)
+	if err != nil {
+		return nil, err
+	}
+
+	// The stats tracer wraps the timeout one, so the durations cover the timed out queries too
+	pgCfg.ConnConfig.Tracer = db.QueryTracers{statsTracer, db.NewQueryTimeoutTracer(cfg.QueryTimeout)}
 
 	pool, err := pgxpool.NewWithConfig(ctx, pgCfg)
 	if err != nil {
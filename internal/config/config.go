@@ -1,33 +1,67 @@
 package config
 
 import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"log/slog"
 	"net"
+	"net/netip"
 	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/kelseyhightower/envconfig"
+
+	"github.com/rom8726/floxy-manager/pkg/secrets"
 )
 
 const (
 	prefix = ""
 )
 
+// Tenant isolation modes. In the schema mode the workflow views of every tenant are kept
+// in a dedicated schema.
+const (
+	TenantIsolationNone   = "none"
+	TenantIsolationSchema = "schema"
+)
+
 type Config struct {
-	Logger           Logger        `envconfig:"LOGGER"`
-	APIServer        Server        `envconfig:"API_SERVER"`
-	TechServer       Server        `envconfig:"TECH_SERVER"`
-	Postgres         Postgres      `envconfig:"POSTGRES"`
-	Mailer           Mailer        `envconfig:"MAILER"`
-	MigrationsDir    string        `default:"./migrations"     envconfig:"MIGRATIONS_DIR"`
-	FrontendURL      string        `envconfig:"FRONTEND_URL"   required:"true"`
-	SecretKey        string        `envconfig:"SECRET_KEY"     required:"true"`
-	JWTSecretKey     string        `envconfig:"JWT_SECRET_KEY" required:"true"`
-	AccessTokenTTL   time.Duration `default:"3h"               envconfig:"ACCESS_TOKEN_TTL"`
-	RefreshTokenTTL  time.Duration `default:"168h"             envconfig:"REFRESH_TOKEN_TTL"`
-	ResetPasswordTTL time.Duration `default:"8h"               envconfig:"RESET_PASSWORD_TTL"`
+	Logger           Logger           `envconfig:"LOGGER"`
+	APIServer        Server           `envconfig:"API_SERVER"`
+	TechServer       Server           `envconfig:"TECH_SERVER"`
+	GRPCServer       GRPCServer       `envconfig:"GRPC_SERVER"`
+	RequestLimits    RequestLimits    `envconfig:"REQUEST_LIMITS"`
+	Postgres         Postgres         `envconfig:"POSTGRES"`
+	Mailer           Mailer           `envconfig:"MAILER"`
+	Scheduler        Scheduler        `envconfig:"SCHEDULER"`
+	Notifier         Notifier         `envconfig:"NOTIFIER"`
+	EmailOutbox      EmailOutbox      `envconfig:"EMAIL_OUTBOX"`
+	MembershipExpiry MembershipExpiry `envconfig:"MEMBERSHIP_EXPIRY"`
+	AuditForward     AuditForward     `envconfig:"AUDIT_FORWARD"`
+	Retention        Retention        `envconfig:"RETENTION"`
+	StatsRefresh     StatsRefresh     `envconfig:"STATS_REFRESH"`
+	Secrets          Secrets          `envconfig:"SECRETS"`
+	WebAuthn         WebAuthn         `envconfig:"WEBAUTHN"`
+	License          License          `envconfig:"LICENSE"`
+	MigrationsDir    string           `default:"./migrations"     envconfig:"MIGRATIONS_DIR"`
+	MigrateOnStart   bool             `default:"true"             envconfig:"MIGRATE_ON_START"`
+	TenantIsolation  string           `default:"none"             envconfig:"TENANT_ISOLATION"`
+	FrontendURL      string           `envconfig:"FRONTEND_URL"   required:"true"`
+	SecretKey        string           `envconfig:"SECRET_KEY"     required:"true"`
+	JWTSecretKey     string           `envconfig:"JWT_SECRET_KEY" required:"true"`
+	JWTSigning       JWTSigning       `envconfig:"JWT_SIGNING"`
+	AccessTokenTTL   time.Duration    `default:"3h"               envconfig:"ACCESS_TOKEN_TTL"`
+	RefreshTokenTTL  time.Duration    `default:"168h"             envconfig:"REFRESH_TOKEN_TTL"`
+	ResetPasswordTTL time.Duration    `default:"8h"               envconfig:"RESET_PASSWORD_TTL"`
+	VerifyEmailTTL   time.Duration    `default:"72h"              envconfig:"VERIFY_EMAIL_TTL"`
+	IdempotencyTTL   time.Duration    `default:"24h"              envconfig:"IDEMPOTENCY_TTL"`
+	ImpersonationTTL time.Duration    `default:"30m"              envconfig:"IMPERSONATION_TTL"`
 
 	AdminEmail       string `envconfig:"ADMIN_EMAIL"`
 	AdminTmpPassword string `envconfig:"ADMIN_TMP_PASSWORD"`
@@ -35,35 +69,143 @@ type Config struct {
 	// SSO Configuration
 	// Keycloak KeycloakConfig `envconfig:"KEYCLOAK"`
 	SAML SAMLConfig `envconfig:"SAML"`
+	// SAMLProviders lists names of additional SAML providers. Each one is configured
+	// with SAML_<NAME>_* variables (e.g. SAML_OKTA_IDP_METADATA_URL).
+	SAMLProviders       []string                      `envconfig:"SAML_PROVIDERS"`
+	SAMLProviderConfigs map[string]SAMLProviderConfig `ignored:"true"`
+
+	envFile         string
+	secretsResolver *secrets.Resolver
+	secretValues    map[string]*secrets.Value
+	secretRefs      map[string]string
 }
 
-type Logger struct {
-	Lvl string `default:"info" envconfig:"LEVEL"`
+// JWTSigning selects an asymmetric token signing algorithm instead of HS256 with JWT_SECRET_KEY.
+// PrivateKey is a PEM file path or a key URI of a registered KMS loader.
+type JWTSigning struct {
+	Algorithm  string `default:"HS256" envconfig:"ALGORITHM"`
+	PrivateKey string `envconfig:"PRIVATE_KEY"`
+	KeyID      string `envconfig:"KEY_ID"`
+}
+
+// License verifies the signed license enabling the enterprise features (SSO, LDAP, audit export).
+// Licensing is not enforced without PublicKey, the base64 encoded Ed25519 public key.
+// File is a license installed on start.
+type License struct {
+	PublicKey string `envconfig:"PUBLIC_KEY"`
+	File      string `envconfig:"FILE"`
 }
 
-func (l *Logger) Level() slog.Level {
-	switch l.Lvl {
-	case "debug":
-		return slog.LevelDebug
-	case "info":
-		return slog.LevelInfo
-	case "warn":
-		return slog.LevelWarn
-	case "error":
-		return slog.LevelError
-	default:
-		panic("invalid logger level " + l.Lvl)
+// Key returns the decoded public key, nil when it is not set or invalid.
+func (l License) Key() ed25519.PublicKey {
+	key, err := base64.StdEncoding.DecodeString(l.PublicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil
 	}
+
+	return key
+}
+
+type Logger struct {
+	Lvl    string `default:"info" envconfig:"LEVEL"`
+	Format string `default:"text" envconfig:"FORMAT"`
 }
 
 type Server struct {
-	Addr         string        `envconfig:"ADDR" required:"true"`
-	ReadTimeout  time.Duration `default:"15s"    envconfig:"READ_TIMEOUT"`
-	WriteTimeout time.Duration `default:"30s"    envconfig:"WRITE_TIMEOUT"`
-	IdleTimeout  time.Duration `default:"60s"    envconfig:"IDLE_TIMEOUT"`
-	CertFile     string        `default:""       envconfig:"CERT_FILE"`
-	KeyFile      string        `default:""       envconfig:"KEY_FILE"`
-	UseTLS       bool          `default:"false"  envconfig:"USE_TLS"`
+	Addr              string        `envconfig:"ADDR" required:"true"`
+	ReadHeaderTimeout time.Duration `default:"5s"      envconfig:"READ_HEADER_TIMEOUT"`
+	ReadTimeout       time.Duration `default:"15s"     envconfig:"READ_TIMEOUT"`
+	WriteTimeout      time.Duration `default:"30s"     envconfig:"WRITE_TIMEOUT"`
+	IdleTimeout       time.Duration `default:"60s"     envconfig:"IDLE_TIMEOUT"`
+	MaxHeaderBytes    int           `default:"1048576" envconfig:"MAX_HEADER_BYTES"`
+	CertFile          string        `default:""        envconfig:"CERT_FILE"`
+	KeyFile           string        `default:""        envconfig:"KEY_FILE"`
+	UseTLS            bool          `default:"false"   envconfig:"USE_TLS"`
+
+	// DebugEndpoints is used by the technical server only, it exposes /debug/pprof and /debug/vars.
+	DebugEndpoints bool `default:"true" envconfig:"DEBUG_ENDPOINTS"`
+
+	// HTTPRedirectAddr and ACME are used by the API server only.
+	// HTTPRedirectAddr serves plain HTTP redirecting to HTTPS, e.g. ":80".
+	HTTPRedirectAddr string `envconfig:"HTTP_REDIRECT_ADDR"`
+	ACME             ACME   `envconfig:"ACME"`
+
+	// TrustedProxies lists the addresses and CIDRs of the reverse proxies in front of the API server.
+	// X-Forwarded-For is only read from requests they send.
+	TrustedProxies []string `envconfig:"TRUSTED_PROXIES"`
+}
+
+// TrustedProxyPrefixes returns the parsed TrustedProxies, invalid entries are skipped.
+func (s Server) TrustedProxyPrefixes() []netip.Prefix {
+	prefixes := make([]netip.Prefix, 0, len(s.TrustedProxies))
+	for _, proxy := range s.TrustedProxies {
+		if prefix, err := parseProxy(proxy); err == nil {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+
+	return prefixes
+}
+
+func parseProxy(proxy string) (netip.Prefix, error) {
+	proxy = strings.TrimSpace(proxy)
+	if strings.Contains(proxy, "/") {
+		prefix, err := netip.ParsePrefix(proxy)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+
+		return prefix.Masked(), nil
+	}
+
+	addr, err := netip.ParseAddr(proxy)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	addr = addr.Unmap()
+
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// ACME obtains the TLS certificate of the listed domains from an ACME CA (Let's Encrypt by default)
+// instead of CertFile and KeyFile. The domains must resolve to the server.
+type ACME struct {
+	Domains      []string `envconfig:"DOMAINS"`
+	Email        string   `envconfig:"EMAIL"`
+	CacheDir     string   `default:"./acme-cache" envconfig:"CACHE_DIR"`
+	DirectoryURL string   `envconfig:"DIRECTORY_URL"`
+}
+
+// RequestLimits bounds API request bodies. Auth applies to /api/v1/auth/, Upload to workflow
+// definitions and imports, Default to the other endpoints.
+type RequestLimits struct {
+	Default               int64 `default:"2097152"  envconfig:"DEFAULT"`
+	Auth                  int64 `default:"65536"    envconfig:"AUTH"`
+	Upload                int64 `default:"67108864" envconfig:"UPLOAD"`
+	JSONMaxDepth          int   `default:"64"       envconfig:"JSON_MAX_DEPTH"`
+	DisallowUnknownFields bool  `default:"false"    envconfig:"DISALLOW_UNKNOWN_FIELDS"`
+}
+
+// GRPCServer configures the gRPC API for workflow read models. It is disabled when Addr is empty.
+// Without TLS the server speaks HTTP/2 over cleartext (h2c). Connections are long-lived, so instead of
+// read and write timeouts they are bounded by the handshake timeout, the idle timeout and HTTP/2 pings.
+type GRPCServer struct {
+	Addr              string        `default:""      envconfig:"ADDR"`
+	ReadHeaderTimeout time.Duration `default:"5s"    envconfig:"READ_HEADER_TIMEOUT"`
+	IdleTimeout       time.Duration `default:"5m"    envconfig:"IDLE_TIMEOUT"`
+	KeepaliveTime     time.Duration `default:"1m"    envconfig:"KEEPALIVE_TIME"`
+	KeepaliveTimeout  time.Duration `default:"20s"   envconfig:"KEEPALIVE_TIMEOUT"`
+	CertFile          string        `default:""      envconfig:"CERT_FILE"`
+	KeyFile           string        `default:""      envconfig:"KEY_FILE"`
+	UseTLS            bool          `default:"false" envconfig:"USE_TLS"`
+}
+
+// WebAuthn configures security keys used as a second factor.
+// RPID and RPOrigins default to the host and origin of FrontendURL.
+type WebAuthn struct {
+	RPID          string   `default:""              envconfig:"RP_ID"`
+	RPDisplayName string   `default:"Floxy Manager" envconfig:"RP_DISPLAY_NAME"`
+	RPOrigins     []string `default:""              envconfig:"RP_ORIGINS"`
 }
 
 // SAMLConfig holds SAML configuration.
@@ -77,17 +219,113 @@ type SAMLConfig struct {
 	SSOURL           string            `default:""      envconfig:"SSO_URL"` // Optional: override SSO URL from metadata
 	AttributeMapping map[string]string `default:""      envconfig:"ATTRIBUTE_MAPPING"`
 	SkipTLSVerify    bool              `default:"false" envconfig:"SKIP_TLS_VERIFY"`
+	GroupAttribute   string            `default:""      envconfig:"GROUP_ATTRIBUTE"`
+	GroupMapping     SAMLGroupMapping  `default:""      envconfig:"GROUP_MAPPING"`
 }
 
+// SAMLGroupRole grants a project role to members of an IdP group.
+type SAMLGroupRole struct {
+	Group     string `json:"group"`
+	ProjectID int    `json:"project_id"`
+	Role      string `json:"role"`
+}
+
+// SAMLGroupMapping is a JSON list of group to project role mappings,
+// e.g. [{"group":"wf-admins","project_id":1,"role":"project_owner"}].
+type SAMLGroupMapping []SAMLGroupRole
+
+// Decode implements envconfig.Decoder.
+func (m *SAMLGroupMapping) Decode(value string) error {
+	if value == "" {
+		*m = nil
+
+		return nil
+	}
+
+	var mapping []SAMLGroupRole
+	if err := json.Unmarshal([]byte(value), &mapping); err != nil {
+		return fmt.Errorf("parse SAML group mapping: %w", err)
+	}
+
+	for _, item := range mapping {
+		if item.Group == "" || item.ProjectID <= 0 || item.Role == "" {
+			return fmt.Errorf("invalid SAML group mapping entry %+v", item)
+		}
+	}
+
+	*m = mapping
+
+	return nil
+}
+
+// SAMLProviderConfig holds configuration of an additional named SAML provider.
+type SAMLProviderConfig struct {
+	SAMLConfig
+	DisplayName string `default:"" envconfig:"DISPLAY_NAME"`
+	IconURL     string `default:"" envconfig:"ICON_URL"`
+}
+
+var samlProviderNameRe = regexp.MustCompile(`^[a-z][a-z0-9_]{0,31}$`)
+
+// reservedSAMLProviderName is the name of the provider configured with plain SAML_* variables.
+const reservedSAMLProviderName = "ad_saml"
+
 type Mailer struct {
-	Addr          string `envconfig:"ADDR"     required:"true"`
-	User          string `envconfig:"USER"     required:"true"`
-	Password      string `envconfig:"PASSWORD" required:"true"`
-	From          string `envconfig:"FROM"     required:"true"`
-	AllowInsecure bool   `default:"false"      envconfig:"ALLOW_INSECURE"`
-	CertFile      string `default:""           envconfig:"CERT_FILE"`
-	KeyFile       string `default:""           envconfig:"KEY_FILE"`
-	UseTLS        bool   `default:"false"      envconfig:"USE_TLS"`
+	Addr          string       `envconfig:"ADDR"     required:"true"`
+	User          string       `envconfig:"USER"     required:"true"`
+	Password      string       `envconfig:"PASSWORD" required:"true"`
+	From          string       `envconfig:"FROM"     required:"true"`
+	AllowInsecure bool         `default:"false"      envconfig:"ALLOW_INSECURE"`
+	CertFile      string       `default:""           envconfig:"CERT_FILE"`
+	KeyFile       string       `default:""           envconfig:"KEY_FILE"`
+	UseTLS        bool         `default:"false"      envconfig:"USE_TLS"`
+	StartTLS      string       `default:""           envconfig:"STARTTLS"`
+	AuthMethod    string       `default:"plain"      envconfig:"AUTH_METHOD"`
+	OAuth2        MailerOAuth2 `envconfig:"OAUTH2"`
+}
+
+// MailerOAuth2 configures the XOAUTH2 authentication of the mail server.
+type MailerOAuth2 struct {
+	TokenURL     string   `envconfig:"TOKEN_URL"`
+	ClientID     string   `envconfig:"CLIENT_ID"`
+	ClientSecret string   `envconfig:"CLIENT_SECRET"`
+	RefreshToken string   `envconfig:"REFRESH_TOKEN"`
+	Scopes       []string `envconfig:"SCOPES"`
+}
+
+type Scheduler struct {
+	Enabled  bool          `default:"true" envconfig:"ENABLED"`
+	Interval time.Duration `default:"15s"  envconfig:"INTERVAL"`
+}
+
+type Notifier struct {
+	Enabled  bool          `default:"true" envconfig:"ENABLED"`
+	Interval time.Duration `default:"10s"  envconfig:"INTERVAL"`
+}
+
+type EmailOutbox struct {
+	Enabled  bool          `default:"true" envconfig:"ENABLED"`
+	Interval time.Duration `default:"5s"   envconfig:"INTERVAL"`
+}
+
+type MembershipExpiry struct {
+	Enabled  bool          `default:"true" envconfig:"ENABLED"`
+	Interval time.Duration `default:"1m"   envconfig:"INTERVAL"`
+}
+
+type AuditForward struct {
+	Enabled  bool          `default:"true" envconfig:"ENABLED"`
+	Interval time.Duration `default:"10s"  envconfig:"INTERVAL"`
+}
+
+type Retention struct {
+	Enabled  bool          `default:"true" envconfig:"ENABLED"`
+	Interval time.Duration `default:"1h"   envconfig:"INTERVAL"`
+}
+
+type StatsRefresh struct {
+	Enabled  bool          `default:"true" envconfig:"ENABLED"`
+	Interval time.Duration `default:"1m"   envconfig:"INTERVAL"`
 }
 
 type Postgres struct {
@@ -98,7 +336,22 @@ type Postgres struct {
 	Database        string        `envconfig:"DATABASE" required:"true"`
 	MaxIdleConnTime time.Duration `default:"5m"         envconfig:"MAX_IDLE_CONN_TIME"`
 	MaxConns        int           `default:"20"         envconfig:"MAX_CONNS"`
+	MinConns        int           `default:"0"          envconfig:"MIN_CONNS"`
 	ConnMaxLifetime time.Duration `default:"10m"        envconfig:"CONN_MAX_LIFETIME"`
+	// ConnMaxLifetimeJitter spreads connection closing so the pool is not recycled at once.
+	ConnMaxLifetimeJitter time.Duration `default:"5s" envconfig:"CONN_MAX_LIFETIME_JITTER"`
+	HealthCheckPeriod     time.Duration `default:"5s" envconfig:"HEALTH_CHECK_PERIOD"`
+	// QueryTimeout bounds every query including reading its rows, zero disables it.
+	QueryTimeout time.Duration `default:"30s" envconfig:"QUERY_TIMEOUT"`
+	// SlowQueryThreshold logs the queries running longer, zero disables the log.
+	SlowQueryThreshold time.Duration `default:"500ms" envconfig:"SLOW_QUERY_THRESHOLD"`
+	// StatementCacheMode is the pgx default query exec mode: cache_statement, cache_describe,
+	// describe_exec, exec or simple_protocol. Use exec or simple_protocol behind PgBouncer
+	// in transaction pooling mode.
+	StatementCacheMode string `default:"cache_statement" envconfig:"STATEMENT_CACHE_MODE"`
+	// SessionContext sets the app.user_id, app.tenant_id and app.is_superuser session variables
+	// of the connections for row-level security policies. It needs session pooling.
+	SessionContext bool `default:"false" envconfig:"SESSION_CONTEXT"`
 }
 
 func (db *Postgres) ConnString() string {
@@ -132,25 +385,86 @@ func (db *Postgres) ConnString() string {
 func (db *Postgres) ConnStringWithPoolSize() string {
 	connString := db.ConnString()
 
-	return connString + fmt.Sprintf("&pool_max_conns=%d", db.MaxConns)
+	connString += fmt.Sprintf("&pool_max_conns=%d&pool_min_conns=%d", db.MaxConns, db.MinConns)
+	if db.StatementCacheMode != "" {
+		connString += "&default_query_exec_mode=" + url.QueryEscape(db.StatementCacheMode)
+	}
+
+	return connString
 }
 
 func New(filePath string) (*Config, error) {
-	cfg := &Config{}
-
 	if filePath != "" {
 		if err := godotenv.Load(filePath); err != nil {
 			return nil, fmt.Errorf("error loading env file: %w", err)
 		}
 	}
 
+	cfg, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.envFile = filePath
+
+	return cfg, nil
+}
+
+// load processes and validates the config of the environment.
+func load() (*Config, error) {
+	cfg := &Config{}
+
+	if missing := missingRequired(prefix, reflect.TypeFor[Config]()); len(missing) > 0 {
+		errs := make([]error, 0, len(missing))
+		for _, key := range missing {
+			errs = append(errs, fmt.Errorf("%s: is required", key))
+		}
+
+		return nil, fmt.Errorf("invalid config:\n%w", errors.Join(errs...))
+	}
+
 	if err := envconfig.Process(prefix, cfg); err != nil {
 		return nil, err
 	}
 
+	if err := cfg.loadSAMLProviders(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config:\n%w", err)
+	}
+
 	return cfg, nil
 }
 
+func (cfg *Config) loadSAMLProviders() error {
+	cfg.SAMLProviderConfigs = make(map[string]SAMLProviderConfig, len(cfg.SAMLProviders))
+
+	for _, name := range cfg.SAMLProviders {
+		if !samlProviderNameRe.MatchString(name) {
+			return fmt.Errorf("invalid SAML provider name %q", name)
+		}
+
+		if name == reservedSAMLProviderName {
+			return errors.New("SAML provider name " + reservedSAMLProviderName + " is reserved")
+		}
+
+		if _, ok := cfg.SAMLProviderConfigs[name]; ok {
+			return fmt.Errorf("duplicate SAML provider %q", name)
+		}
+
+		var providerCfg SAMLProviderConfig
+		if err := envconfig.Process("SAML_"+strings.ToUpper(name), &providerCfg); err != nil {
+			return fmt.Errorf("SAML provider %q: %w", name, err)
+		}
+
+		cfg.SAMLProviderConfigs[name] = providerCfg
+	}
+
+	return nil
+}
+
 func MustNew(filePath string) *Config {
 	cfg, err := New(filePath)
 	if err != nil {
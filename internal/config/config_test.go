@@ -0,0 +1,166 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_loadSAMLProviders(t *testing.T) {
+	t.Setenv("SAML_OKTA_ENABLED", "true")
+	t.Setenv("SAML_OKTA_IDP_METADATA_URL", "https://okta.example.com/metadata")
+	t.Setenv("SAML_OKTA_DISPLAY_NAME", "Sign in with Okta")
+
+	cfg := &Config{SAMLProviders: []string{"okta", "adfs"}}
+	require.NoError(t, cfg.loadSAMLProviders())
+
+	require.Len(t, cfg.SAMLProviderConfigs, 2)
+	okta := cfg.SAMLProviderConfigs["okta"]
+	assert.True(t, okta.Enabled)
+	assert.Equal(t, "https://okta.example.com/metadata", okta.IDPMetadataURL)
+	assert.Equal(t, "Sign in with Okta", okta.DisplayName)
+	assert.False(t, cfg.SAMLProviderConfigs["adfs"].Enabled)
+
+	for _, names := range [][]string{{"ad_saml"}, {"Okta"}, {"okta-1"}, {"okta", "okta"}} {
+		cfg := &Config{SAMLProviders: names}
+		assert.Error(t, cfg.loadSAMLProviders(), names)
+	}
+}
+
+func TestSAMLGroupMapping_Decode(t *testing.T) {
+	var mapping SAMLGroupMapping
+	require.NoError(t, mapping.Decode(`[{"group":"wf-admins","project_id":1,"role":"project_owner"}]`))
+	assert.Equal(t, SAMLGroupMapping{{Group: "wf-admins", ProjectID: 1, Role: "project_owner"}}, mapping)
+
+	require.NoError(t, mapping.Decode(""))
+	assert.Nil(t, mapping)
+
+	assert.Error(t, mapping.Decode(`{"group":"wf-admins"}`))
+	assert.Error(t, mapping.Decode(`[{"group":"wf-admins","project_id":0,"role":"project_owner"}]`))
+}
+
+func TestPostgres_ConnStringWithPoolSize(t *testing.T) {
+	db := &Postgres{
+		User: "floxy", Password: "secret", Host: "localhost", Port: "5432", Database: "floxy",
+		MaxConns: 30, MinConns: 2, StatementCacheMode: "exec",
+	}
+
+	poolCfg, err := pgxpool.ParseConfig(db.ConnStringWithPoolSize())
+	require.NoError(t, err)
+	assert.EqualValues(t, 30, poolCfg.MaxConns)
+	assert.EqualValues(t, 2, poolCfg.MinConns)
+	assert.Equal(t, pgx.QueryExecModeExec, poolCfg.ConnConfig.DefaultQueryExecMode)
+
+	db.StatementCacheMode = "unknown"
+	_, err = pgxpool.ParseConfig(db.ConnStringWithPoolSize())
+	assert.Error(t, err)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	valid := func() *Config {
+		return &Config{
+			Logger:           Logger{Lvl: "info", Format: "text"},
+			APIServer:        Server{Addr: ":8080", MaxHeaderBytes: 1 << 20},
+			TechServer:       Server{Addr: ":8081", MaxHeaderBytes: 1 << 20},
+			Postgres:         Postgres{MaxConns: 20, StatementCacheMode: "cache_statement"},
+			Mailer:           Mailer{AuthMethod: "plain"},
+			JWTSigning:       JWTSigning{Algorithm: "HS256"},
+			Scheduler:        Scheduler{Enabled: true, Interval: time.Second},
+			Secrets:          Secrets{RefreshInterval: time.Minute},
+			TenantIsolation:  TenantIsolationNone,
+			IdempotencyTTL:   time.Hour,
+			ImpersonationTTL: time.Minute,
+			FrontendURL:      "https://floxy.example.com",
+		}
+	}
+
+	require.NoError(t, valid().Validate())
+
+	cfg := valid()
+	cfg.FrontendURL = "floxy.example.com"
+	cfg.APIServer.UseTLS = true
+	cfg.APIServer.ACME.Domains = []string{"floxy.example.com"}
+	cfg.Mailer.UseTLS = true
+	cfg.Mailer.StartTLS = "require"
+	cfg.JWTSigning.Algorithm = "RS256"
+	cfg.Scheduler.Interval = 0
+	cfg.License.PublicKey = "not-a-key"
+	cfg.APIServer.TrustedProxies = []string{"10.0.0.0/8", "proxy.local"}
+
+	err := cfg.Validate()
+	require.Error(t, err)
+
+	for _, key := range []string{
+		"FRONTEND_URL", "API_SERVER_USE_TLS", "API_SERVER_ACME_DOMAINS", "MAILER_STARTTLS",
+		"JWT_SIGNING_PRIVATE_KEY", "SCHEDULER_INTERVAL", "LICENSE_PUBLIC_KEY", "API_SERVER_TRUSTED_PROXIES",
+	} {
+		assert.Contains(t, err.Error(), key+": ")
+	}
+}
+
+func TestMissingRequired(t *testing.T) {
+	t.Setenv("API_SERVER_ADDR", ":8080")
+	t.Setenv("POSTGRES_USER", "floxy")
+
+	missing := missingRequired("", reflect.TypeFor[Config]())
+
+	assert.Contains(t, missing, "TECH_SERVER_ADDR")
+	assert.Contains(t, missing, "POSTGRES_HOST")
+	assert.NotContains(t, missing, "API_SERVER_ADDR")
+	assert.NotContains(t, missing, "POSTGRES_USER")
+	assert.NotContains(t, missing, "POSTGRES_PORT")
+}
+
+func TestConfig_Reload(t *testing.T) {
+	for key, value := range map[string]string{
+		"API_SERVER_ADDR":   ":8080",
+		"TECH_SERVER_ADDR":  ":8081",
+		"POSTGRES_USER":     "floxy",
+		"POSTGRES_PASSWORD": "secret",
+		"POSTGRES_HOST":     "localhost",
+		"POSTGRES_DATABASE": "floxy",
+		"MAILER_ADDR":       "smtp.example.com:587",
+		"MAILER_USER":       "floxy",
+		"MAILER_PASSWORD":   "secret",
+		"MAILER_FROM":       "floxy@example.com",
+		"FRONTEND_URL":      "https://floxy.example.com",
+		"SECRET_KEY":        "secret-key",
+		"JWT_SECRET_KEY":    "jwt-secret-key",
+	} {
+		t.Setenv(key, value)
+	}
+
+	cfg, err := New("")
+	require.NoError(t, err)
+
+	t.Setenv("LOGGER_LEVEL", "debug")
+	t.Setenv("API_SERVER_ADDR", ":9090")
+	t.Setenv("MAILER_PASSWORD", "rotated")
+
+	next, changes, err := cfg.Reload()
+	require.NoError(t, err)
+
+	assert.Equal(t, []Change{
+		{Key: "API_SERVER_ADDR", Old: ":8080", New: ":9090"},
+		{Key: "LOGGER_LEVEL", Old: "info", New: "debug", Reloadable: true},
+		{Key: "MAILER_PASSWORD", Secret: true},
+	}, changes)
+
+	assert.Equal(t, "debug", next.Logger.Lvl)
+	assert.Equal(t, ":8080", next.APIServer.Addr)
+	assert.Equal(t, "secret", next.Mailer.Password)
+
+	// Settings that need a restart are reported again
+	_, changes, err = next.Reload()
+	require.NoError(t, err)
+	assert.Len(t, changes, 2)
+
+	t.Setenv("LOGGER_LEVEL", "loud")
+	_, _, err = next.Reload()
+	assert.Error(t, err)
+}
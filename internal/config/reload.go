@@ -0,0 +1,135 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// reloadableKeys are the settings Reload applies while running, the others need a restart.
+// The mailer secrets are rotated with secret references instead, see ResolveSecrets.
+var reloadableKeys = map[string]bool{
+	"LOGGER_LEVEL":                           true,
+	"LOGGER_FORMAT":                          true,
+	"MAILER_ADDR":                            true,
+	"MAILER_USER":                            true,
+	"MAILER_FROM":                            true,
+	"MAILER_ALLOW_INSECURE":                  true,
+	"MAILER_USE_TLS":                         true,
+	"MAILER_STARTTLS":                        true,
+	"MAILER_AUTH_METHOD":                     true,
+	"MAILER_OAUTH2_TOKEN_URL":                true,
+	"MAILER_OAUTH2_CLIENT_ID":                true,
+	"MAILER_OAUTH2_SCOPES":                   true,
+	"REQUEST_LIMITS_DEFAULT":                 true,
+	"REQUEST_LIMITS_AUTH":                    true,
+	"REQUEST_LIMITS_UPLOAD":                  true,
+	"REQUEST_LIMITS_JSON_MAX_DEPTH":          true,
+	"REQUEST_LIMITS_DISALLOW_UNKNOWN_FIELDS": true,
+	"NOTIFIER_INTERVAL":                      true,
+}
+
+// Change is a setting changed on reload. The values of secrets are left empty.
+type Change struct {
+	Key        string
+	Old        string
+	New        string
+	Secret     bool
+	Reloadable bool
+}
+
+// Reload reads the env file again, its values now override the environment, and returns the config
+// with the reloadable settings updated and the list of the changed settings. The other settings
+// keep their values, so they are reported on every reload until the restart.
+func (cfg *Config) Reload() (*Config, []Change, error) {
+	if cfg.envFile != "" {
+		if err := godotenv.Overload(cfg.envFile); err != nil {
+			return nil, nil, fmt.Errorf("error loading env file: %w", err)
+		}
+	}
+
+	next, err := load()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	changes := cfg.changes(next)
+
+	applied := *cfg
+	applied.Logger = next.Logger
+	applied.RequestLimits = next.RequestLimits
+	applied.Notifier.Interval = next.Notifier.Interval
+	applied.Mailer = next.Mailer
+	applied.Mailer.Password = cfg.Mailer.Password
+	applied.Mailer.OAuth2.ClientSecret = cfg.Mailer.OAuth2.ClientSecret
+	applied.Mailer.OAuth2.RefreshToken = cfg.Mailer.OAuth2.RefreshToken
+	applied.Mailer.CertFile = cfg.Mailer.CertFile
+	applied.Mailer.KeyFile = cfg.Mailer.KeyFile
+
+	return &applied, changes, nil
+}
+
+// changes compares the settings with the ones of next, which has no secrets resolved yet.
+func (cfg *Config) changes(next *Config) []Change {
+	oldValues := make(map[string]string)
+	flatten(prefix, reflect.ValueOf(cfg).Elem(), oldValues)
+
+	for key, ref := range cfg.secretRefs {
+		oldValues[key] = ref
+	}
+
+	newValues := make(map[string]string)
+	flatten(prefix, reflect.ValueOf(next).Elem(), newValues)
+
+	secretKeys := cfg.secretFields()
+
+	var changes []Change
+	for key, newValue := range newValues {
+		oldValue := oldValues[key]
+		if oldValue == newValue {
+			continue
+		}
+
+		change := Change{Key: key, Old: oldValue, New: newValue, Reloadable: reloadableKeys[key]}
+		if _, ok := secretKeys[key]; ok {
+			change = Change{Key: key, Secret: true}
+		}
+
+		changes = append(changes, change)
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+
+	return changes
+}
+
+// flatten collects the values of the settings of spec keyed by their env var.
+func flatten(prefix string, spec reflect.Value, values map[string]string) {
+	for i := range spec.NumField() {
+		field := spec.Type().Field(i)
+		if !field.IsExported() || field.Tag.Get("ignored") == "true" {
+			continue
+		}
+
+		key := field.Tag.Get("envconfig")
+		if key == "" {
+			key = strings.ToUpper(field.Name)
+		}
+
+		if prefix != "" {
+			key = prefix + "_" + key
+		}
+
+		if field.Type.Kind() == reflect.Struct && field.Type != reflect.TypeFor[time.Time]() {
+			flatten(key, spec.Field(i), values)
+
+			continue
+		}
+
+		values[key] = fmt.Sprint(spec.Field(i).Interface())
+	}
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rom8726/floxy-manager/pkg/secrets"
+)
+
+// Secrets configures the refresh of secret references, e.g. POSTGRES_PASSWORD=vault://secret/data/floxy#db_password.
+type Secrets struct {
+	RefreshInterval time.Duration `default:"5m" envconfig:"REFRESH_INTERVAL"`
+}
+
+// secretFields returns the fields that may hold secret references, keyed by their env var.
+func (cfg *Config) secretFields() map[string]*string {
+	return map[string]*string{
+		"POSTGRES_PASSWORD":           &cfg.Postgres.Password,
+		"MAILER_PASSWORD":             &cfg.Mailer.Password,
+		"MAILER_OAUTH2_CLIENT_SECRET": &cfg.Mailer.OAuth2.ClientSecret,
+		"MAILER_OAUTH2_REFRESH_TOKEN": &cfg.Mailer.OAuth2.RefreshToken,
+		"SECRET_KEY":                  &cfg.SecretKey,
+		"JWT_SECRET_KEY":              &cfg.JWTSecretKey,
+		"ADMIN_TMP_PASSWORD":          &cfg.AdminTmpPassword,
+	}
+}
+
+// ResolveSecrets replaces the secret references of the config with their values. The referenced
+// values are kept fresh by the resolver, see SecretValue; the others are read once.
+func (cfg *Config) ResolveSecrets(ctx context.Context) error {
+	resolver := secrets.NewResolver()
+	values := make(map[string]*secrets.Value)
+	refs := make(map[string]string)
+
+	var errs []error
+	for key, field := range cfg.secretFields() {
+		value, err := resolver.Resolve(ctx, *field)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+
+			continue
+		}
+
+		if value.IsReference() {
+			values[key] = value
+			refs[key] = *field
+		}
+
+		*field = value.Get()
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	cfg.secretsResolver = resolver
+	cfg.secretValues = values
+	cfg.secretRefs = refs
+
+	return nil
+}
+
+// SecretsResolver returns the resolver of the secret references, nil before ResolveSecrets.
+func (cfg *Config) SecretsResolver() *secrets.Resolver {
+	return cfg.secretsResolver
+}
+
+// SecretValue returns the current value of a secret referenced by the env var, nil when
+// the variable holds the value itself.
+func (cfg *Config) SecretValue(key string) *secrets.Value {
+	return cfg.secretValues[key]
+}
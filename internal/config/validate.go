@@ -0,0 +1,300 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+var statementCacheModes = []string{"cache_statement", "cache_describe", "describe_exec", "exec", "simple_protocol"}
+
+// validator collects the problems of a config, each one reported with the env var it comes from.
+type validator struct {
+	errs []error
+}
+
+func (v *validator) addf(key, format string, args ...any) {
+	v.errs = append(v.errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+}
+
+func (v *validator) err() error {
+	return errors.Join(v.errs...)
+}
+
+// Validate checks the values, the formats and the combinations of options that the env parsing
+// does not, and reports all problems at once.
+func (cfg *Config) Validate() error {
+	v := &validator{}
+
+	switch cfg.Logger.Lvl {
+	case "debug", "info", "warn", "error":
+	default:
+		v.addf("LOGGER_LEVEL", "must be debug, info, warn or error, got %q", cfg.Logger.Lvl)
+	}
+
+	switch cfg.Logger.Format {
+	case "text", "json":
+	default:
+		v.addf("LOGGER_FORMAT", "must be text or json, got %q", cfg.Logger.Format)
+	}
+
+	switch cfg.TenantIsolation {
+	case TenantIsolationNone, TenantIsolationSchema:
+	default:
+		v.addf("TENANT_ISOLATION", "must be none or schema, got %q", cfg.TenantIsolation)
+	}
+
+	if !isAbsoluteURL(cfg.FrontendURL) {
+		v.addf("FRONTEND_URL", "must be an absolute URL, got %q", cfg.FrontendURL)
+	}
+
+	cfg.APIServer.validate(v, "API_SERVER")
+	cfg.TechServer.validate(v, "TECH_SERVER")
+	cfg.GRPCServer.validate(v, "GRPC_SERVER")
+	cfg.Postgres.validate(v, "POSTGRES")
+	cfg.Mailer.validate(v, "MAILER")
+	cfg.JWTSigning.validate(v, "JWT_SIGNING")
+
+	validateInterval(v, "SCHEDULER", cfg.Scheduler.Enabled, cfg.Scheduler.Interval)
+	validateInterval(v, "NOTIFIER", cfg.Notifier.Enabled, cfg.Notifier.Interval)
+	validateInterval(v, "EMAIL_OUTBOX", cfg.EmailOutbox.Enabled, cfg.EmailOutbox.Interval)
+	validateInterval(v, "MEMBERSHIP_EXPIRY", cfg.MembershipExpiry.Enabled, cfg.MembershipExpiry.Interval)
+	validateInterval(v, "AUDIT_FORWARD", cfg.AuditForward.Enabled, cfg.AuditForward.Interval)
+	validateInterval(v, "RETENTION", cfg.Retention.Enabled, cfg.Retention.Interval)
+	validateInterval(v, "STATS_REFRESH", cfg.StatsRefresh.Enabled, cfg.StatsRefresh.Interval)
+	validateInterval(v, "SECRETS_REFRESH", true, cfg.Secrets.RefreshInterval)
+
+	if cfg.IdempotencyTTL <= 0 {
+		v.addf("IDEMPOTENCY_TTL", "must be positive, got %s", cfg.IdempotencyTTL)
+	}
+
+	if cfg.ImpersonationTTL <= 0 {
+		v.addf("IMPERSONATION_TTL", "must be positive, got %s", cfg.ImpersonationTTL)
+	}
+
+	if cfg.License.PublicKey != "" && cfg.License.Key() == nil {
+		v.addf("LICENSE_PUBLIC_KEY", "must be a base64 encoded Ed25519 public key")
+	}
+
+	if cfg.License.File != "" && cfg.License.PublicKey == "" {
+		v.addf("LICENSE_FILE", "requires LICENSE_PUBLIC_KEY")
+	}
+
+	for _, origin := range cfg.WebAuthn.RPOrigins {
+		if origin != "" && !isAbsoluteURL(origin) {
+			v.addf("WEBAUTHN_RP_ORIGINS", "must be absolute URLs, got %q", origin)
+		}
+	}
+
+	if cfg.AdminEmail != "" && cfg.AdminTmpPassword == "" {
+		v.addf("ADMIN_TMP_PASSWORD", "is required with ADMIN_EMAIL")
+	}
+
+	cfg.SAML.validate(v, "SAML")
+	for name, providerCfg := range cfg.SAMLProviderConfigs {
+		providerCfg.validate(v, "SAML_"+strings.ToUpper(name))
+	}
+
+	return v.err()
+}
+
+func (s *Server) validate(v *validator, prefix string) {
+	if _, _, err := net.SplitHostPort(s.Addr); err != nil {
+		v.addf(prefix+"_ADDR", "must be host:port, got %q", s.Addr)
+	}
+
+	if s.UseTLS && (s.CertFile == "" || s.KeyFile == "") {
+		v.addf(prefix+"_USE_TLS", "requires %s_CERT_FILE and %s_KEY_FILE", prefix, prefix)
+	}
+
+	if len(s.ACME.Domains) > 0 && (s.UseTLS || s.CertFile != "") {
+		v.addf(prefix+"_ACME_DOMAINS", "cannot be set with %s_USE_TLS or %s_CERT_FILE", prefix, prefix)
+	}
+
+	if s.ACME.DirectoryURL != "" && !isAbsoluteURL(s.ACME.DirectoryURL) {
+		v.addf(prefix+"_ACME_DIRECTORY_URL", "must be an absolute URL, got %q", s.ACME.DirectoryURL)
+	}
+
+	if s.HTTPRedirectAddr != "" {
+		if _, _, err := net.SplitHostPort(s.HTTPRedirectAddr); err != nil {
+			v.addf(prefix+"_HTTP_REDIRECT_ADDR", "must be host:port, got %q", s.HTTPRedirectAddr)
+		}
+
+		if !s.UseTLS && len(s.ACME.Domains) == 0 {
+			v.addf(prefix+"_HTTP_REDIRECT_ADDR", "requires %s_USE_TLS or %s_ACME_DOMAINS", prefix, prefix)
+		}
+	}
+
+	if s.MaxHeaderBytes <= 0 {
+		v.addf(prefix+"_MAX_HEADER_BYTES", "must be positive, got %d", s.MaxHeaderBytes)
+	}
+
+	for _, proxy := range s.TrustedProxies {
+		if _, err := parseProxy(proxy); err != nil {
+			v.addf(prefix+"_TRUSTED_PROXIES", "must be IP addresses or CIDRs, got %q", proxy)
+		}
+	}
+}
+
+func (s *GRPCServer) validate(v *validator, prefix string) {
+	if s.Addr == "" {
+		return
+	}
+
+	if _, _, err := net.SplitHostPort(s.Addr); err != nil {
+		v.addf(prefix+"_ADDR", "must be host:port, got %q", s.Addr)
+	}
+
+	if s.UseTLS && (s.CertFile == "" || s.KeyFile == "") {
+		v.addf(prefix+"_USE_TLS", "requires %s_CERT_FILE and %s_KEY_FILE", prefix, prefix)
+	}
+
+	validatePositive(v, prefix+"_READ_HEADER_TIMEOUT", s.ReadHeaderTimeout)
+	validatePositive(v, prefix+"_IDLE_TIMEOUT", s.IdleTimeout)
+	validatePositive(v, prefix+"_KEEPALIVE_TIME", s.KeepaliveTime)
+	validatePositive(v, prefix+"_KEEPALIVE_TIMEOUT", s.KeepaliveTimeout)
+}
+
+func validatePositive(v *validator, key string, d time.Duration) {
+	if d <= 0 {
+		v.addf(key, "must be positive, got %s", d)
+	}
+}
+
+func (db *Postgres) validate(v *validator, prefix string) {
+	if db.MaxConns <= 0 {
+		v.addf(prefix+"_MAX_CONNS", "must be positive, got %d", db.MaxConns)
+	}
+
+	if db.MinConns < 0 || db.MinConns > db.MaxConns {
+		v.addf(prefix+"_MIN_CONNS", "must be between 0 and %s_MAX_CONNS, got %d", prefix, db.MinConns)
+	}
+
+	if db.StatementCacheMode != "" && !slices.Contains(statementCacheModes, db.StatementCacheMode) {
+		v.addf(prefix+"_STATEMENT_CACHE_MODE", "must be one of %s, got %q",
+			strings.Join(statementCacheModes, ", "), db.StatementCacheMode)
+	}
+
+	if db.QueryTimeout < 0 {
+		v.addf(prefix+"_QUERY_TIMEOUT", "must not be negative, got %s", db.QueryTimeout)
+	}
+
+	if db.SlowQueryThreshold < 0 {
+		v.addf(prefix+"_SLOW_QUERY_THRESHOLD", "must not be negative, got %s", db.SlowQueryThreshold)
+	}
+}
+
+func (m *Mailer) validate(v *validator, prefix string) {
+	switch m.StartTLS {
+	case "", "require", "opportunistic", "disabled":
+	default:
+		v.addf(prefix+"_STARTTLS", "must be empty, require, opportunistic or disabled, got %q", m.StartTLS)
+	}
+
+	if m.UseTLS && m.StartTLS != "" {
+		v.addf(prefix+"_STARTTLS", "cannot be set with %s_USE_TLS", prefix)
+	}
+
+	switch m.AuthMethod {
+	case "plain":
+	case "xoauth2":
+		if !isAbsoluteURL(m.OAuth2.TokenURL) {
+			v.addf(prefix+"_OAUTH2_TOKEN_URL", "must be an absolute URL for xoauth2, got %q", m.OAuth2.TokenURL)
+		}
+
+		if m.OAuth2.ClientID == "" {
+			v.addf(prefix+"_OAUTH2_CLIENT_ID", "is required for xoauth2")
+		}
+
+		if m.OAuth2.ClientSecret == "" && m.OAuth2.RefreshToken == "" {
+			v.addf(prefix+"_OAUTH2_CLIENT_SECRET", "or %s_OAUTH2_REFRESH_TOKEN is required for xoauth2", prefix)
+		}
+	default:
+		v.addf(prefix+"_AUTH_METHOD", "must be plain or xoauth2, got %q", m.AuthMethod)
+	}
+}
+
+func (s *JWTSigning) validate(v *validator, prefix string) {
+	switch s.Algorithm {
+	case "HS256":
+		if s.PrivateKey != "" {
+			v.addf(prefix+"_PRIVATE_KEY", "cannot be set with HS256, it signs with JWT_SECRET_KEY")
+		}
+	case "RS256", "EdDSA":
+		if s.PrivateKey == "" {
+			v.addf(prefix+"_PRIVATE_KEY", "is required for %s", s.Algorithm)
+		}
+	default:
+		v.addf(prefix+"_ALGORITHM", "must be HS256, RS256 or EdDSA, got %q", s.Algorithm)
+	}
+}
+
+func (s *SAMLConfig) validate(v *validator, prefix string) {
+	if !s.Enabled {
+		return
+	}
+
+	if !isAbsoluteURL(s.IDPMetadataURL) {
+		v.addf(prefix+"_IDP_METADATA_URL", "must be an absolute URL when SAML is enabled, got %q", s.IDPMetadataURL)
+	}
+
+	if s.SSOURL != "" && !isAbsoluteURL(s.SSOURL) {
+		v.addf(prefix+"_SSO_URL", "must be an absolute URL, got %q", s.SSOURL)
+	}
+}
+
+func validateInterval(v *validator, prefix string, enabled bool, interval time.Duration) {
+	if enabled && interval <= 0 {
+		v.addf(prefix+"_INTERVAL", "must be positive, got %s", interval)
+	}
+}
+
+func isAbsoluteURL(value string) bool {
+	u, err := url.Parse(value)
+
+	return err == nil && u.Scheme != "" && u.Host != ""
+}
+
+// missingRequired lists the env vars of the required fields of spec that are not set,
+// so they are reported together rather than one per start.
+func missingRequired(prefix string, spec reflect.Type) []string {
+	var missing []string
+
+	for i := range spec.NumField() {
+		field := spec.Field(i)
+		if !field.IsExported() || field.Tag.Get("ignored") == "true" {
+			continue
+		}
+
+		key := field.Tag.Get("envconfig")
+		if key == "" {
+			key = strings.ToUpper(field.Name)
+		}
+
+		if prefix != "" {
+			key = prefix + "_" + key
+		}
+
+		if field.Type.Kind() == reflect.Struct && field.Type != reflect.TypeFor[time.Time]() {
+			missing = append(missing, missingRequired(key, field.Type)...)
+
+			continue
+		}
+
+		if field.Tag.Get("required") != "true" || field.Tag.Get("default") != "" {
+			continue
+		}
+
+		if _, ok := os.LookupEnv(key); !ok {
+			missing = append(missing, key)
+		}
+	}
+
+	return missing
+}
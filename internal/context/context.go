@@ -3,6 +3,7 @@ package context
 
 import (
 	"context"
+	"net"
 	"net/http"
 
 	"github.com/julienschmidt/httprouter"
@@ -13,12 +14,18 @@ type contextKey string
 
 const (
 	ctxKeyProjectID  contextKey = "project_id"
+	ctxKeyTenantID   contextKey = "tenant_id"
 	ctxKeyUserID     contextKey = "user_id"
 	ctxKeyIsSuper    contextKey = "is_superuser"
 	ctxKeyRawRequest contextKey = "raw_request"
 	ctxKeyRequestID  contextKey = "request_id"
 	ctxKeyUsername   contextKey = "username"
 	ctxKeyParams     contextKey = "httprouter_params"
+	ctxKeySessionID  contextKey = "session_id"
+	ctxKeyAPITokenID contextKey = "api_token_id"
+	ctxKeyClientIP   contextKey = "client_ip"
+
+	ctxKeyImpersonator contextKey = "impersonator"
 )
 
 func WithProjectID(ctx context.Context, id domain.ProjectID) context.Context {
@@ -29,6 +36,24 @@ func ProjectID(ctx context.Context) domain.ProjectID {
 	return ctx.Value(ctxKeyProjectID).(domain.ProjectID) //nolint:forcetypeassert // ProjectID guaranteed
 }
 
+// LookupProjectID returns the project ID from the context or 0 if it is not set.
+func LookupProjectID(ctx context.Context) domain.ProjectID {
+	id, _ := ctx.Value(ctxKeyProjectID).(domain.ProjectID)
+
+	return id
+}
+
+func WithTenantID(ctx context.Context, id domain.TenantID) context.Context {
+	return context.WithValue(ctx, ctxKeyTenantID, id)
+}
+
+// TenantID returns the tenant of the request or 0 if it is not set.
+func TenantID(ctx context.Context) domain.TenantID {
+	id, _ := ctx.Value(ctxKeyTenantID).(domain.TenantID)
+
+	return id
+}
+
 func WithUserID(ctx context.Context, userID domain.UserID) context.Context {
 	return context.WithValue(ctx, ctxKeyUserID, userID)
 }
@@ -60,6 +85,31 @@ func RawRequest(ctx context.Context) *http.Request {
 	return ctx.Value(ctxKeyRawRequest).(*http.Request) //nolint:forcetypeassert // RawRequest guaranteed
 }
 
+// WithClientIP stores the client IP address resolved by the API server.
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, ctxKeyClientIP, ip)
+}
+
+// ClientInfo returns the client IP address and user agent of the raw request stored in the context.
+// The address is the one stored by WithClientIP, the remote address of the request without it.
+func ClientInfo(ctx context.Context) (ip, userAgent string) {
+	req, ok := ctx.Value(ctxKeyRawRequest).(*http.Request)
+	if !ok || req == nil {
+		return "", ""
+	}
+
+	if clientIP, ok := ctx.Value(ctxKeyClientIP).(string); ok && clientIP != "" {
+		return clientIP, req.UserAgent()
+	}
+
+	ip = req.RemoteAddr
+	if host, _, err := net.SplitHostPort(req.RemoteAddr); err == nil {
+		ip = host
+	}
+
+	return ip, req.UserAgent()
+}
+
 func WithRequestID(ctx context.Context, reqID string) context.Context {
 	return context.WithValue(ctx, ctxKeyRequestID, reqID)
 }
@@ -86,6 +136,48 @@ func Username(ctx context.Context) string {
 	return ""
 }
 
+func WithSessionID(ctx context.Context, id domain.SessionID) context.Context {
+	return context.WithValue(ctx, ctxKeySessionID, id)
+}
+
+func SessionID(ctx context.Context) domain.SessionID {
+	v, ok := ctx.Value(ctxKeySessionID).(domain.SessionID)
+	if ok {
+		return v
+	}
+
+	return ""
+}
+
+func WithImpersonator(ctx context.Context, username string) context.Context {
+	return context.WithValue(ctx, ctxKeyImpersonator, username)
+}
+
+// Impersonator returns the username of the superuser acting as the user of the request,
+// or an empty string when the user acts by themselves.
+func Impersonator(ctx context.Context) string {
+	v, ok := ctx.Value(ctxKeyImpersonator).(string)
+	if ok {
+		return v
+	}
+
+	return ""
+}
+
+func WithAPITokenID(ctx context.Context, id domain.APITokenID) context.Context {
+	return context.WithValue(ctx, ctxKeyAPITokenID, id)
+}
+
+// APITokenID returns the ID of the API token the request was authenticated with, or 0 for JWT auth.
+func APITokenID(ctx context.Context) domain.APITokenID {
+	v, ok := ctx.Value(ctxKeyAPITokenID).(domain.APITokenID)
+	if ok {
+		return v
+	}
+
+	return 0
+}
+
 func WithParams(ctx context.Context, params httprouter.Params) context.Context {
 	return context.WithValue(ctx, ctxKeyParams, params)
 }
@@ -2,6 +2,8 @@ package context
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -79,6 +81,13 @@ func TestProjectID(t *testing.T) {
 	}
 }
 
+func TestTenantID(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, domain.TenantID(7), TenantID(WithTenantID(context.Background(), domain.TenantID(7))))
+	require.Equal(t, domain.TenantID(0), TenantID(context.Background()))
+}
+
 func TestWithUserID(t *testing.T) {
 	t.Parallel()
 
@@ -211,3 +220,28 @@ func TestIsSuper(t *testing.T) {
 		})
 	}
 }
+
+func TestClientInfo(t *testing.T) {
+	t.Parallel()
+
+	ip, userAgent := ClientInfo(context.Background())
+	require.Empty(t, ip)
+	require.Empty(t, userAgent)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", nil)
+	req.RemoteAddr = "10.0.0.1:52341"
+	req.Header.Set("User-Agent", "test-agent")
+
+	ip, userAgent = ClientInfo(WithRawRequest(context.Background(), req))
+	require.Equal(t, "10.0.0.1", ip)
+	require.Equal(t, "test-agent", userAgent)
+
+	// The forwarding headers are only honored through WithClientIP
+	req.Header.Set("X-Forwarded-For", "203.0.113.7, 10.0.0.1")
+
+	ip, _ = ClientInfo(WithRawRequest(context.Background(), req))
+	require.Equal(t, "10.0.0.1", ip)
+
+	ip, _ = ClientInfo(WithClientIP(WithRawRequest(context.Background(), req), "203.0.113.7"))
+	require.Equal(t, "203.0.113.7", ip)
+}
@@ -0,0 +1,43 @@
+package context
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LogHandler adds request scoped attributes (request ID, user ID) to records
+// logged with a context, so that logs of one request can be correlated.
+type LogHandler struct {
+	next slog.Handler
+}
+
+func NewLogHandler(next slog.Handler) *LogHandler {
+	return &LogHandler{next: next}
+}
+
+func (h *LogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+//nolint:gocritic // slog.Handler signature
+func (h *LogHandler) Handle(ctx context.Context, record slog.Record) error {
+	if ctx != nil {
+		if reqID := RequestID(ctx); reqID != "" {
+			record.AddAttrs(slog.String("request_id", reqID))
+		}
+
+		if userID := UserID(ctx); userID != 0 {
+			record.AddAttrs(slog.Uint64("user_id", uint64(userID)))
+		}
+	}
+
+	return h.next.Handle(ctx, record)
+}
+
+func (h *LogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &LogHandler{next: h.next.WithAttrs(attrs)}
+}
+
+func (h *LogHandler) WithGroup(name string) slog.Handler {
+	return &LogHandler{next: h.next.WithGroup(name)}
+}
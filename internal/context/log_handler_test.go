@@ -0,0 +1,31 @@
+package context
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+func TestLogHandler(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	logger := slog.New(NewLogHandler(slog.NewTextHandler(&buf, nil)))
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	ctx = WithUserID(ctx, domain.UserID(42))
+
+	logger.InfoContext(ctx, "with context")
+	require.Contains(t, buf.String(), "request_id=req-1")
+	require.Contains(t, buf.String(), "user_id=42")
+
+	buf.Reset()
+	logger.Info("without context")
+	require.NotContains(t, buf.String(), "request_id")
+	require.NotContains(t, buf.String(), "user_id")
+}
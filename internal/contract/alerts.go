@@ -0,0 +1,33 @@
+package contract
+
+import (
+	"context"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type AlertsRepository interface {
+	// GetSettings returns the project alert settings or domain.ErrEntityNotFound if they were never saved.
+	GetSettings(ctx context.Context, projectID domain.ProjectID) (domain.AlertSettings, error)
+	SaveSettings(ctx context.Context, settings domain.AlertSettings) error
+	ListEnabledSettings(ctx context.Context) ([]domain.AlertSettings, error)
+	SaveDigestTime(ctx context.Context, projectID domain.ProjectID, at time.Time) error
+
+	// EnqueueAlert queues an alert. It reports false if the event was already queued.
+	EnqueueAlert(ctx context.Context, projectID domain.ProjectID, event domain.LifecycleEvent) (bool, error)
+	ListPendingAlerts(ctx context.Context, projectID domain.ProjectID, limit int) ([]domain.Alert, error)
+	MarkAlertsSent(ctx context.Context, ids []int64, at time.Time) error
+}
+
+type AlertsUseCase interface {
+	LifecycleEventHandler
+
+	GetSettings(ctx context.Context, projectID domain.ProjectID) (domain.AlertSettings, error)
+	UpdateSettings(ctx context.Context, settings domain.AlertSettings) (domain.AlertSettings, error)
+
+	// CheckLongRunning queues alerts for instances running longer than the project thresholds.
+	CheckLongRunning(ctx context.Context, now time.Time) error
+	// SendPending emails queued alerts, respecting the digest interval of each project.
+	SendPending(ctx context.Context, now time.Time) (int, error)
+}
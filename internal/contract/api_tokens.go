@@ -0,0 +1,32 @@
+package contract
+
+import (
+	"context"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type APITokensRepository interface {
+	Create(
+		ctx context.Context,
+		userID domain.UserID,
+		dto domain.APITokenDTO,
+		tokenHash, tokenPrefix string,
+	) (domain.APIToken, error)
+	List(ctx context.Context, userID domain.UserID) ([]domain.APIToken, error)
+	GetByHash(ctx context.Context, tokenHash string) (domain.APIToken, error)
+	Delete(ctx context.Context, userID domain.UserID, id domain.APITokenID) error
+	// TouchLastUsed updates last_used_at at most once a minute to keep writes cheap.
+	TouchLastUsed(ctx context.Context, id domain.APITokenID, at time.Time) error
+}
+
+type APITokensUseCase interface {
+	// Create issues a new token and returns it together with the plain token value, which is shown only once.
+	Create(ctx context.Context, userID domain.UserID, dto domain.APITokenDTO) (domain.APIToken, string, error)
+	List(ctx context.Context, userID domain.UserID) ([]domain.APIToken, error)
+	Delete(ctx context.Context, userID domain.UserID, id domain.APITokenID) error
+	// Authenticate resolves the owner of a plain token. It returns domain.ErrInvalidToken
+	// for unknown or expired tokens and domain.ErrInactiveUser for disabled owners.
+	Authenticate(ctx context.Context, token string) (domain.User, domain.APIToken, error)
+}
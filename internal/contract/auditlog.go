@@ -2,20 +2,47 @@ package contract
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/rom8726/floxy-manager/internal/domain"
 )
 
 type AuditLogEntry struct {
-	ID        int64     `json:"id"`
-	Entity    string    `json:"entity"`
-	EntityID  string    `json:"entity_id"`
-	Username  string    `json:"username"`
-	Action    string    `json:"action"`
-	CreatedAt time.Time `json:"created_at"`
+	ID        int64            `json:"id"`
+	ProjectID domain.ProjectID `json:"project_id,omitempty"`
+	Entity    string           `json:"entity"`
+	EntityID  string           `json:"entity_id"`
+	Username  string           `json:"username"`
+	Action    string           `json:"action"`
+	// Changes holds old and new values of the fields changed by an update, if recorded.
+	Changes json.RawMessage `json:"changes,omitempty"`
+	// StatusCode is the HTTP status of the request that performed the action, if recorded.
+	StatusCode *int `json:"status_code,omitempty"`
+	// Impersonator is the superuser who performed the action acting as the user, if any.
+	Impersonator *string   `json:"impersonator,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// AuditLogFilter selects audit log entries. Empty fields do not filter.
+// At most one of ProjectID and TenantID is set; without both the whole audit log is selected,
+// including entries with no project (users, tenants, LDAP config).
+type AuditLogFilter struct {
+	ProjectID domain.ProjectID
+	// TenantID selects entries of all projects of the tenant and of the tenant itself.
+	TenantID domain.TenantID
+	From     *time.Time
+	To       *time.Time
+	Entity   string
+	Action   string
+	Username string
 }
 
 type AuditLogRepository interface {
-	List(ctx context.Context, projectID domain.ProjectID, page, pageSize int) ([]AuditLogEntry, int, error)
+	List(ctx context.Context, filter AuditLogFilter, page, pageSize int) ([]AuditLogEntry, int, error)
+	// Export passes entries matching the filter to fn, newest first, and stops after limit entries.
+	Export(ctx context.Context, filter AuditLogFilter, limit int, fn func(AuditLogEntry) error) error
+	// ListUnforwarded returns the oldest entries not yet passed to external audit sinks.
+	ListUnforwarded(ctx context.Context, limit int) ([]AuditLogEntry, error)
+	MarkForwarded(ctx context.Context, ids []int64) error
 }
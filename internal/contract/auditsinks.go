@@ -0,0 +1,14 @@
+package contract
+
+import (
+	"context"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type AuditSinksUseCase interface {
+	GetConfig(ctx context.Context) (domain.AuditSinksConfig, error)
+	UpdateConfig(ctx context.Context, cfg domain.AuditSinksConfig) error
+	// Forward sends audit log entries not forwarded yet to the enabled sinks and returns their number.
+	Forward(ctx context.Context) (int, error)
+}
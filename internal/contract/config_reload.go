@@ -0,0 +1,12 @@
+package contract
+
+import (
+	"context"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+// ConfigReloader reloads the settings that can change without a restart.
+type ConfigReloader interface {
+	Reload(ctx context.Context) (domain.ConfigReload, error)
+}
@@ -0,0 +1,30 @@
+package contract
+
+import (
+	"context"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+// DeletionRepository finds and deletes what depends on projects and tenants.
+type DeletionRepository interface {
+	ProjectImpact(ctx context.Context, projectID domain.ProjectID) (domain.DeletionImpact, error)
+	TenantImpact(ctx context.Context, tenantID domain.TenantID) (domain.DeletionImpact, error)
+	// ListTenantProjects returns the ids of all projects of the tenant, archived ones included.
+	ListTenantProjects(ctx context.Context, tenantID domain.TenantID) ([]domain.ProjectID, error)
+	// DeleteProjectInstances deletes all instances of the project workflows, finished or not.
+	DeleteProjectInstances(ctx context.Context, projectID domain.ProjectID) (int64, error)
+	// DeleteProjectWorkflows unassigns the workflow definitions of the project and deletes
+	// the ones not assigned to another project.
+	DeleteProjectWorkflows(ctx context.Context, projectID domain.ProjectID) (int64, error)
+	DeleteProjectMemberships(ctx context.Context, projectID domain.ProjectID) (int64, error)
+	DeleteTenantMemberships(ctx context.Context, tenantID domain.TenantID) (int64, error)
+}
+
+type DeletionUseCase interface {
+	// DeleteProject deletes the project when nothing depends on it. Otherwise it returns domain.ErrEntityInUse
+	// together with the impact, unless force is set: then the dependents are deleted first, in one transaction.
+	DeleteProject(ctx context.Context, projectID domain.ProjectID, force bool) (domain.DeletionImpact, error)
+	// DeleteTenant works like DeleteProject, force deletes the projects of the tenant too.
+	DeleteTenant(ctx context.Context, tenantID domain.TenantID, force bool) (domain.DeletionImpact, error)
+}
@@ -1,11 +1,50 @@
 package contract
 
-import "context"
+import (
+	"context"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
 
 // Emailer defines the interface for sending emails.
 type Emailer interface {
 	// SendResetPasswordEmail sends a password reset email with a token.
 	SendResetPasswordEmail(ctx context.Context, email, token string) error
+	// SendVerifyEmail sends a link confirming the email address of a new user.
+	SendVerifyEmail(ctx context.Context, email, token string, ttl time.Duration) error
 	// Send2FACodeEmail sends a 2FA code email for the specified action (disable/reset).
 	Send2FACodeEmail(ctx context.Context, email, code, action string) error
+	// SendNotificationEmail sends a notification whose subject and body are rendered by the caller.
+	SendNotificationEmail(ctx context.Context, email, subject, body string) error
+	// SendMembershipExpiredEmail tells a user that their time-bound access to a project has expired.
+	SendMembershipExpiredEmail(ctx context.Context, email string, project domain.Project) error
+	// SendMemberAccessExpiredEmail tells a project manager that the access of a member has expired.
+	SendMemberAccessExpiredEmail(ctx context.Context, email string, project domain.Project, member domain.User) error
+	// SendTestEmail sends an email checking the mail server configuration, it fails when none is configured.
+	SendTestEmail(ctx context.Context, email string) error
+}
+
+// EmailDeliverer sends an email right away through the configured mail server.
+type EmailDeliverer interface {
+	Deliver(ctx context.Context, email, subject, body string) error
+}
+
+type EmailOutboxRepository interface {
+	Enqueue(ctx context.Context, email domain.OutboxEmail) (int64, error)
+	// ListDue locks pending emails whose next attempt is due.
+	ListDue(ctx context.Context, now time.Time, limit int) ([]domain.OutboxEmail, error)
+	// SaveAttempt stores the result of an attempt, the body of a sent email is cleared.
+	SaveAttempt(ctx context.Context, email domain.OutboxEmail) error
+	GetByID(ctx context.Context, id int64) (domain.OutboxEmail, error)
+	List(ctx context.Context, status domain.EmailStatus, page, pageSize int) ([]domain.OutboxEmail, int, error)
+	// Requeue makes a failed email pending again with a fresh set of attempts.
+	Requeue(ctx context.Context, id int64, now time.Time) error
+}
+
+type EmailOutboxUseCase interface {
+	// SendPending sends due emails and schedules retries for failed ones.
+	SendPending(ctx context.Context, now time.Time) (int, error)
+	List(ctx context.Context, status domain.EmailStatus, page, pageSize int) ([]domain.OutboxEmail, int, error)
+	Resend(ctx context.Context, id int64) (domain.OutboxEmail, error)
 }
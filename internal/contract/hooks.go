@@ -0,0 +1,45 @@
+package contract
+
+import (
+	"context"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type HooksRepository interface {
+	List(ctx context.Context, projectID domain.ProjectID, page, pageSize int) ([]domain.Hook, int, error)
+	GetByID(ctx context.Context, projectID domain.ProjectID, id domain.HookID) (domain.Hook, error)
+	GetByToken(ctx context.Context, token string) (domain.Hook, error)
+	Create(
+		ctx context.Context,
+		projectID domain.ProjectID,
+		dto domain.HookDTO,
+		token, secretEncrypted string,
+	) (domain.HookID, error)
+	Update(ctx context.Context, projectID domain.ProjectID, id domain.HookID, dto domain.HookDTO) error
+	UpdateSecret(ctx context.Context, projectID domain.ProjectID, id domain.HookID, secretEncrypted string) error
+	Delete(ctx context.Context, projectID domain.ProjectID, id domain.HookID) error
+	MarkTriggered(ctx context.Context, id domain.HookID, at time.Time) error
+}
+
+type HooksUseCase interface {
+	List(ctx context.Context, projectID domain.ProjectID, page, pageSize int) ([]domain.Hook, int, error)
+	Get(ctx context.Context, projectID domain.ProjectID, id domain.HookID) (domain.Hook, error)
+	Create(
+		ctx context.Context,
+		tenantID domain.TenantID,
+		projectID domain.ProjectID,
+		dto domain.HookDTO,
+	) (domain.Hook, error)
+	Update(
+		ctx context.Context,
+		tenantID domain.TenantID,
+		projectID domain.ProjectID,
+		id domain.HookID,
+		dto domain.HookDTO,
+	) (domain.Hook, error)
+	RotateSecret(ctx context.Context, projectID domain.ProjectID, id domain.HookID) (domain.Hook, error)
+	Delete(ctx context.Context, projectID domain.ProjectID, id domain.HookID) error
+	Trigger(ctx context.Context, token string, body []byte, signature string) (int64, error)
+}
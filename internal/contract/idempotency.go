@@ -0,0 +1,21 @@
+package contract
+
+import (
+	"context"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+// IdempotencyKeysRepository stores the responses of requests sent with an Idempotency-Key header.
+type IdempotencyKeysRepository interface {
+	// Reserve stores the request in progress unless a not expired one with the key exists,
+	// it reports whether the request was stored. Expired requests are removed.
+	Reserve(ctx context.Context, req *domain.IdempotentRequest) (bool, error)
+	// Get returns the request with the key of req.
+	// Returns domain.ErrEntityNotFound if there is no such request.
+	Get(ctx context.Context, req *domain.IdempotentRequest) (domain.IdempotentRequest, error)
+	// Complete stores the response of the reserved request.
+	Complete(ctx context.Context, req *domain.IdempotentRequest) error
+	// Release removes the reserved request, so a retry runs it again.
+	Release(ctx context.Context, req *domain.IdempotentRequest) error
+}
@@ -0,0 +1,36 @@
+package contract
+
+import (
+	"context"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type InstanceHoldsRepository interface {
+	// Create holds the instance and moves its queued steps aside.
+	// Returns domain.ErrEntityAlreadyExists if the instance is already held.
+	Create(
+		ctx context.Context,
+		projectID domain.ProjectID,
+		instanceID int,
+		reason string,
+	) (domain.InstanceHold, error)
+	// Delete releases the instance and queues its held steps again.
+	// Returns domain.ErrEntityNotFound if the instance is not held.
+	Delete(ctx context.Context, projectID domain.ProjectID, instanceID int) (int, error)
+	Get(ctx context.Context, projectID domain.ProjectID, instanceID int) (domain.InstanceHold, error)
+	List(ctx context.Context, projectID domain.ProjectID) ([]domain.InstanceHold, error)
+}
+
+type InstanceHoldsUseCase interface {
+	Pause(
+		ctx context.Context,
+		tenantID domain.TenantID,
+		projectID domain.ProjectID,
+		instanceID int,
+		reason string,
+	) (domain.InstanceHold, error)
+	List(ctx context.Context, projectID domain.ProjectID) ([]domain.InstanceHold, error)
+	// Resume returns the number of steps queued again.
+	Resume(ctx context.Context, tenantID domain.TenantID, projectID domain.ProjectID, instanceID int) (int, error)
+}
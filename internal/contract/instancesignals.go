@@ -0,0 +1,40 @@
+package contract
+
+import (
+	"context"
+
+	floxy "github.com/rom8726/floxy-pro"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+// HumanDecisionMaker completes steps waiting for a decision, the engine implements it.
+type HumanDecisionMaker interface {
+	MakeHumanDecision(
+		ctx context.Context,
+		stepID int64,
+		decidedBy string,
+		decision floxy.HumanDecision,
+		comment *string,
+	) error
+}
+
+type InstanceSignalsRepository interface {
+	// FindWaitingStep returns the step of the instance with the name that waits for a decision.
+	FindWaitingStep(ctx context.Context, instanceID int, stepName string) (domain.WorkflowStep, error)
+	// Create returns domain.ErrEntityAlreadyExists if the idempotency key was used for the instance.
+	Create(ctx context.Context, signal domain.InstanceSignal) (domain.InstanceSignal, error)
+	GetByIdempotencyKey(ctx context.Context, instanceID int, key string) (domain.InstanceSignal, error)
+}
+
+type InstanceSignalsUseCase interface {
+	// Send delivers the signal to the waiting step. With an idempotency key already used for the instance
+	// the recorded signal is returned and replayed is true.
+	Send(
+		ctx context.Context,
+		tenantID domain.TenantID,
+		projectID domain.ProjectID,
+		instanceID int,
+		dto domain.InstanceSignalDTO,
+	) (signal domain.InstanceSignal, replayed bool, err error)
+}
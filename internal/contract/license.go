@@ -0,0 +1,29 @@
+package contract
+
+import (
+	"context"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+// LicenseFeatures tells whether the enterprise features are available in the installed license.
+type LicenseFeatures interface {
+	IsFeatureAvailable(feature domain.LicenseFeature) bool
+}
+
+type LicenseService interface {
+	LicenseFeatures
+	Status(ctx context.Context) (domain.LicenseStatus, error)
+	Install(ctx context.Context, licenseText string) (domain.LicenseStatus, error)
+	// InstallIfNewer installs the license unless the current one is newer, it reports whether it did.
+	InstallIfNewer(ctx context.Context, licenseText string) (domain.LicenseStatus, bool, error)
+}
+
+type LicensesRepository interface {
+	GetLastByExpiresAt(ctx context.Context) (domain.License, error)
+	UpdateLicense(ctx context.Context, license domain.License) (domain.License, error)
+}
+
+type ProductInfoRepository interface {
+	GetClientID(ctx context.Context) (string, error)
+}
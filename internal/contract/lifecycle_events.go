@@ -0,0 +1,33 @@
+package contract
+
+import (
+	"context"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type LifecycleEventsRepository interface {
+	// GetCursor returns the stored position of the named reader or domain.ErrEntityNotFound.
+	GetCursor(ctx context.Context, name string) (time.Time, error)
+	SaveCursor(ctx context.Context, name string, position time.Time) error
+	// List returns lifecycle events that occurred in (from, to], ordered by time.
+	List(ctx context.Context, from, to time.Time) ([]domain.LifecycleEvent, error)
+	// ListLongRunning returns long_running_instance events for project instances running since before startedBefore.
+	ListLongRunning(
+		ctx context.Context,
+		projectID domain.ProjectID,
+		startedBefore time.Time,
+	) ([]domain.LifecycleEvent, error)
+}
+
+// LifecycleEventHandler consumes workflow lifecycle events.
+// Events may be passed more than once and must be deduplicated by their Key.
+type LifecycleEventHandler interface {
+	HandleEvents(ctx context.Context, events []domain.LifecycleEvent) error
+}
+
+type LifecycleEventsUseCase interface {
+	// Dispatch reads events that occurred since the last call and passes them to the handlers.
+	Dispatch(ctx context.Context, now time.Time, handlers ...LifecycleEventHandler) (int, error)
+}
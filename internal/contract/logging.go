@@ -0,0 +1,12 @@
+package contract
+
+import (
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+// LogControl switches the level and the format of the logs while running.
+type LogControl interface {
+	Settings() domain.LogSettings
+	// Update sets the level and the format, an empty one is left as is.
+	Update(settings domain.LogSettings) error
+}
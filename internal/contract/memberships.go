@@ -2,6 +2,7 @@ package contract
 
 import (
 	"context"
+	"time"
 
 	"github.com/rom8726/floxy-manager/internal/domain"
 )
@@ -22,6 +23,7 @@ type MembershipsUseCase interface {
 		projectID domain.ProjectID,
 		userID domain.UserID,
 		roleID domain.RoleID,
+		validUntil *time.Time,
 	) (domain.ProjectMembership, error)
 	GetProjectMembership(
 		ctx context.Context,
@@ -35,4 +37,13 @@ type MembershipsUseCase interface {
 		roleID domain.RoleID,
 	) (domain.ProjectMembership, error)
 	DeleteProjectMembership(ctx context.Context, projectID domain.ProjectID, membershipID domain.MembershipID) error
+	// GetEffectivePermissions computes the permissions of the user in the project and what grants each of them.
+	GetEffectivePermissions(
+		ctx context.Context,
+		projectID domain.ProjectID,
+		userID domain.UserID,
+	) (domain.EffectivePermissions, error)
+	// ExpireProjectMemberships removes memberships whose valid_until has passed and notifies
+	// the former members and the project managers. It returns the number of removed memberships.
+	ExpireProjectMemberships(ctx context.Context, now time.Time) (int, error)
 }
@@ -0,0 +1,14 @@
+package contract
+
+import (
+	"context"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+// Migrator reports and applies the database schema migrations.
+type Migrator interface {
+	Status(ctx context.Context) (domain.MigrationStatus, error)
+	// Up applies the pending migrations and returns the resulting status.
+	Up(ctx context.Context) (domain.MigrationStatus, error)
+}
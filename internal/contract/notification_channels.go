@@ -0,0 +1,86 @@
+package contract
+
+import (
+	"context"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type NotificationChannelsRepository interface {
+	List(ctx context.Context, projectID domain.ProjectID) ([]domain.NotificationChannel, error)
+	GetByID(
+		ctx context.Context,
+		projectID domain.ProjectID,
+		id domain.NotificationChannelID,
+	) (domain.NotificationChannel, error)
+	Create(
+		ctx context.Context,
+		projectID domain.ProjectID,
+		dto domain.NotificationChannelDTO,
+		webhookURLEncrypted string,
+	) (domain.NotificationChannelID, error)
+	// Update changes the channel settings. An empty webhookURLEncrypted keeps the current URL.
+	Update(
+		ctx context.Context,
+		projectID domain.ProjectID,
+		id domain.NotificationChannelID,
+		dto domain.NotificationChannelDTO,
+		webhookURLEncrypted string,
+	) error
+	Delete(ctx context.Context, projectID domain.ProjectID, id domain.NotificationChannelID) error
+	// ListSubscribed returns enabled channels subscribed to the event type.
+	// A zero projectID means channels of all projects.
+	ListSubscribed(
+		ctx context.Context,
+		projectID domain.ProjectID,
+		eventType domain.LifecycleEventType,
+	) ([]domain.NotificationChannel, error)
+
+	// EnqueueMessage creates a pending message. It reports false if the event was already enqueued.
+	EnqueueMessage(ctx context.Context, message domain.NotificationMessage) (bool, error)
+	// ListDueMessages locks pending messages whose next attempt is due, with their channels.
+	ListDueMessages(ctx context.Context, now time.Time, limit int) ([]domain.NotificationMessage, error)
+	SaveMessageAttempt(ctx context.Context, message domain.NotificationMessage) error
+	ListMessages(
+		ctx context.Context,
+		channelID domain.NotificationChannelID,
+		page, pageSize int,
+	) ([]domain.NotificationMessage, int, error)
+}
+
+type NotificationChannelsUseCase interface {
+	LifecycleEventHandler
+
+	List(ctx context.Context, projectID domain.ProjectID) ([]domain.NotificationChannel, error)
+	// ListWithWebhookURLs returns the channels with their decrypted webhook URLs, for project exports.
+	ListWithWebhookURLs(ctx context.Context, projectID domain.ProjectID) ([]domain.NotificationChannel, error)
+	Get(
+		ctx context.Context,
+		projectID domain.ProjectID,
+		id domain.NotificationChannelID,
+	) (domain.NotificationChannel, error)
+	Create(
+		ctx context.Context,
+		projectID domain.ProjectID,
+		dto domain.NotificationChannelDTO,
+	) (domain.NotificationChannel, error)
+	Update(
+		ctx context.Context,
+		projectID domain.ProjectID,
+		id domain.NotificationChannelID,
+		dto domain.NotificationChannelDTO,
+	) (domain.NotificationChannel, error)
+	Delete(ctx context.Context, projectID domain.ProjectID, id domain.NotificationChannelID) error
+	ListMessages(
+		ctx context.Context,
+		projectID domain.ProjectID,
+		id domain.NotificationChannelID,
+		page, pageSize int,
+	) ([]domain.NotificationMessage, int, error)
+
+	// CheckLongRunning enqueues long_running_instance messages for instances over the channel thresholds.
+	CheckLongRunning(ctx context.Context, now time.Time) error
+	// SendPending posts due messages and schedules retries for failed ones.
+	SendPending(ctx context.Context, now time.Time) (int, error)
+}
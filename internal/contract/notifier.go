@@ -0,0 +1,18 @@
+package contract
+
+import (
+	"context"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+// Notifier delivers a notification to a target of the kinds it handles.
+type Notifier interface {
+	Notify(ctx context.Context, target domain.NotificationTarget, notification domain.Notification) error
+}
+
+// NotificationRouter dispatches notifications to the notifier registered for the target kind.
+type NotificationRouter interface {
+	Notifier
+	Supports(kind domain.NotificationTargetKind) bool
+}
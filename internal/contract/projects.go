@@ -23,3 +23,23 @@ type ProjectsRepository interface {
 	Archive(ctx context.Context, id domain.ProjectID) error
 	Delete(ctx context.Context, id domain.ProjectID) error
 }
+
+// ProjectArchiveUseCase moves the full state of a project between environments.
+type ProjectArchiveUseCase interface {
+	// Export collects the project state. Notification channel webhook URLs are secrets
+	// and are included when includeSecrets is set only.
+	Export(
+		ctx context.Context,
+		tenantID domain.TenantID,
+		projectID domain.ProjectID,
+		includeSecrets bool,
+	) (*domain.ProjectArchive, error)
+	// Import creates a project named name (the archived name when empty) in the tenant and recreates
+	// the archived state in it. Nothing is created when any entity is rejected.
+	Import(
+		ctx context.Context,
+		tenantID domain.TenantID,
+		name string,
+		archive *domain.ProjectArchive,
+	) (domain.ProjectImportResult, error)
+}
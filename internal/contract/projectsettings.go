@@ -0,0 +1,26 @@
+package contract
+
+import (
+	"context"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type ProjectSettingsRepository interface {
+	Get(ctx context.Context, projectID domain.ProjectID) (domain.ProjectSettings, error)
+	// List returns the saved settings of all projects, projects with default settings are not listed.
+	List(ctx context.Context) ([]domain.ProjectSettings, error)
+	Save(ctx context.Context, projectID domain.ProjectID, values domain.ProjectSettingsValues) error
+}
+
+// ProjectSettingsReader gives the settings of projects to other subsystems, unset values have their defaults.
+type ProjectSettingsReader interface {
+	GetSettings(ctx context.Context, projectID domain.ProjectID) (domain.ProjectSettings, error)
+	// ListSettings returns the settings of the projects that saved any.
+	ListSettings(ctx context.Context) ([]domain.ProjectSettings, error)
+}
+
+type ProjectSettingsUseCase interface {
+	ProjectSettingsReader
+	UpdateSettings(ctx context.Context, settings domain.ProjectSettings) (domain.ProjectSettings, error)
+}
@@ -11,8 +11,10 @@ type PermissionsService interface {
 	CanViewProject(ctx context.Context, projectID domain.ProjectID) error
 	CanManageProject(ctx context.Context, projectID domain.ProjectID) error
 	CanViewAudit(ctx context.Context, projectID domain.ProjectID) error
+	CanViewTenantAudit(ctx context.Context, tenantID domain.TenantID) error
 	CanManageMembership(ctx context.Context, projectID domain.ProjectID) error
 	CanCreateWorkflow(ctx context.Context, projectID domain.ProjectID) error
+	CanViewWorkflowData(ctx context.Context, projectID domain.ProjectID) error
 	GetAccessibleProjects(
 		ctx context.Context,
 		projects []domain.Project,
@@ -21,4 +23,6 @@ type PermissionsService interface {
 	HasGlobalPermission(ctx context.Context, permKey domain.PermKey) (bool, error)
 	GetMyProjectPermissions(ctx context.Context) (map[domain.ProjectID][]domain.PermKey, error)
 	GetMyProjectRoles(ctx context.Context) (map[domain.ProjectID]domain.Role, error)
+	// InvalidateCache drops cached permission decisions. It is called after roles or memberships change.
+	InvalidateCache()
 }
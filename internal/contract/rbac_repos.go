@@ -2,6 +2,7 @@ package contract
 
 import (
 	"context"
+	"time"
 
 	"github.com/rom8726/floxy-manager/internal/domain"
 )
@@ -14,6 +15,8 @@ type RolesRepository interface {
 
 type PermissionsRepository interface {
 	RoleHasPermission(ctx context.Context, roleID string, key domain.PermKey) (bool, error)
+	// RoleHasPermissions checks several permissions of a role with one query.
+	RoleHasPermissions(ctx context.Context, roleID string, keys ...domain.PermKey) (map[domain.PermKey]bool, error)
 	List(ctx context.Context) ([]domain.Permission, error)
 	ListForRole(ctx context.Context, roleID domain.RoleID) ([]domain.Permission, error)
 	ListForAllRoles(ctx context.Context) (map[domain.Role][]domain.Permission, error)
@@ -22,12 +25,20 @@ type PermissionsRepository interface {
 type MembershipsRepository interface {
 	GetForUserProject(ctx context.Context, userID domain.UserID, projectID domain.ProjectID) (roleID string, err error)
 	ListForProject(ctx context.Context, projectID domain.ProjectID) ([]domain.ProjectMembership, error)
+	ListForUser(ctx context.Context, userID domain.UserID) ([]domain.ProjectMembership, error)
 	Create(
 		ctx context.Context,
 		projectID domain.ProjectID,
 		userID domain.UserID,
 		roleID domain.RoleID,
 	) (domain.ProjectMembership, error)
+	CreateWithSource(
+		ctx context.Context,
+		projectID domain.ProjectID,
+		userID domain.UserID,
+		roleID domain.RoleID,
+		source domain.MembershipSource,
+	) (domain.ProjectMembership, error)
 	Get(
 		ctx context.Context,
 		projectID domain.ProjectID,
@@ -40,4 +51,43 @@ type MembershipsRepository interface {
 		roleID domain.RoleID,
 	) (domain.ProjectMembership, error)
 	Delete(ctx context.Context, projectID domain.ProjectID, membershipID domain.MembershipID) error
+	SetValidUntil(
+		ctx context.Context,
+		projectID domain.ProjectID,
+		membershipID domain.MembershipID,
+		validUntil *time.Time,
+	) error
+	// ListExpired returns time-bound memberships whose valid_until is not after now.
+	ListExpired(ctx context.Context, now time.Time) ([]domain.ProjectMembership, error)
+}
+
+type TenantMembershipsRepository interface {
+	// GetForUserProject returns the role the user has in the tenant of the project, or "" without a membership.
+	GetForUserProject(ctx context.Context, userID domain.UserID, projectID domain.ProjectID) (roleID string, err error)
+	// GetForUserTenant returns the role the user has in the tenant, or "" without a membership.
+	GetForUserTenant(ctx context.Context, userID domain.UserID, tenantID domain.TenantID) (roleID string, err error)
+	// GetMembershipForUserProject returns the tenant membership of the user in the tenant of the project.
+	GetMembershipForUserProject(
+		ctx context.Context,
+		userID domain.UserID,
+		projectID domain.ProjectID,
+	) (domain.TenantMembership, error)
+	// ListProjectRolesForUser returns the role the user has through tenant memberships in every project of their tenants.
+	ListProjectRolesForUser(ctx context.Context, userID domain.UserID) (map[domain.ProjectID]string, error)
+	ListForTenant(ctx context.Context, tenantID domain.TenantID) ([]domain.TenantMembership, error)
+	Create(
+		ctx context.Context,
+		tenantID domain.TenantID,
+		userID domain.UserID,
+		roleID domain.RoleID,
+	) (domain.TenantMembership, error)
+	Delete(ctx context.Context, tenantID domain.TenantID, id domain.TenantMembershipID) error
+}
+
+type GlobalRolesRepository interface {
+	UserHasPermission(ctx context.Context, userID domain.UserID, key domain.PermKey) (bool, error)
+	List(ctx context.Context) ([]domain.GlobalRoleAssignment, error)
+	ListForUser(ctx context.Context, userID domain.UserID) ([]domain.GlobalRoleAssignment, error)
+	Create(ctx context.Context, userID domain.UserID, roleID domain.RoleID) (domain.GlobalRoleAssignment, error)
+	Delete(ctx context.Context, id domain.GlobalRoleAssignmentID) error
 }
@@ -0,0 +1,16 @@
+package contract
+
+import (
+	"context"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type RecoveryCodesRepository interface {
+	// Replace removes all recovery codes of the user and stores the new code hashes.
+	Replace(ctx context.Context, userID domain.UserID, codeHashes []string) error
+	// Use marks an unused code as used; it returns domain.ErrEntityNotFound when there is no such code.
+	Use(ctx context.Context, userID domain.UserID, codeHash string) error
+	CountUnused(ctx context.Context, userID domain.UserID) (int, error)
+	DeleteAll(ctx context.Context, userID domain.UserID) error
+}
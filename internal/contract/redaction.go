@@ -0,0 +1,33 @@
+package contract
+
+import (
+	"context"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type RedactionRepository interface {
+	GetRules(ctx context.Context, projectID domain.ProjectID) (domain.RedactionRules, error)
+	// ListRules returns the rules of the projects that have any.
+	ListRules(ctx context.Context, projectIDs []domain.ProjectID) ([]domain.RedactionRules, error)
+	SaveRules(ctx context.Context, projectID domain.ProjectID, paths []string) error
+}
+
+// PayloadRedactor masks the payload values matched by the redaction rules of the project of each item.
+// Values masked in the input or output are masked in the error text of the same item as well.
+type PayloadRedactor interface {
+	RedactInstances(ctx context.Context, instances []domain.WorkflowInstance) error
+	RedactSteps(ctx context.Context, steps []domain.WorkflowStep) error
+	RedactEvents(ctx context.Context, events []domain.WorkflowEvent) error
+	RedactDLQItems(ctx context.Context, items []domain.DLQItem) error
+	// SearchExclusions returns the locations of the project payloads a search must leave out,
+	// domain.ErrPayloadNotSearchable when a redacted path does not select a single location.
+	SearchExclusions(ctx context.Context, projectID domain.ProjectID) ([][]string, error)
+}
+
+type RedactionUseCase interface {
+	PayloadRedactor
+
+	GetRules(ctx context.Context, projectID domain.ProjectID) (domain.RedactionRules, error)
+	UpdateRules(ctx context.Context, projectID domain.ProjectID, paths []string) (domain.RedactionRules, error)
+}
@@ -0,0 +1,44 @@
+package contract
+
+import (
+	"context"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type RetentionRepository interface {
+	// GetSettings returns the project retention settings or domain.ErrEntityNotFound if they were never saved.
+	GetSettings(ctx context.Context, projectID domain.ProjectID) (domain.RetentionSettings, error)
+	SaveSettings(ctx context.Context, settings domain.RetentionSettings) error
+	// ListConfiguredSettings returns the settings having at least one retention period.
+	ListConfiguredSettings(ctx context.Context) ([]domain.RetentionSettings, error)
+	SaveRun(ctx context.Context, projectID domain.ProjectID, run domain.RetentionRun) error
+
+	// DeleteInstances deletes up to limit finished instances of the project with one of the statuses,
+	// finished before the time, with their step logs. Returns the number of deleted instances.
+	DeleteInstances(
+		ctx context.Context,
+		projectID domain.ProjectID,
+		statuses []string,
+		before time.Time,
+		limit int,
+	) (int64, error)
+	// DeleteEvents deletes up to limit events of the project instances created before the time.
+	DeleteEvents(ctx context.Context, projectID domain.ProjectID, before time.Time, limit int) (int64, error)
+}
+
+// WorkflowStoreCleaner runs the cleanup of the engine store, as the floxy cleanup plugin does.
+type WorkflowStoreCleaner interface {
+	CleanupOldWorkflows(ctx context.Context) error
+}
+
+type RetentionUseCase interface {
+	GetSettings(ctx context.Context, projectID domain.ProjectID) (domain.RetentionSettings, error)
+	UpdateSettings(ctx context.Context, settings domain.RetentionSettings) (domain.RetentionSettings, error)
+
+	// RunProject applies the retention settings of the project now.
+	RunProject(ctx context.Context, projectID domain.ProjectID, now time.Time) (domain.RetentionRun, error)
+	// RunAll applies the retention settings of all projects, then runs the engine store cleanup.
+	RunAll(ctx context.Context, now time.Time) error
+}
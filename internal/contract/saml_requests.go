@@ -0,0 +1,15 @@
+package contract
+
+import (
+	"context"
+	"time"
+)
+
+// SAMLRequestsRepository stores IDs of pending SAML AuthnRequests by RelayState.
+type SAMLRequestsRepository interface {
+	// Save stores the request ID and removes expired requests.
+	Save(ctx context.Context, provider, state, requestID string, expiresAt time.Time) error
+	// Take returns and deletes the request ID of a not expired request.
+	// Returns domain.ErrEntityNotFound if there is no such request.
+	Take(ctx context.Context, provider, state string, now time.Time) (string, error)
+}
@@ -0,0 +1,67 @@
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type SchedulesRepository interface {
+	List(ctx context.Context, projectID domain.ProjectID, page, pageSize int) ([]domain.Schedule, int, error)
+	GetByID(ctx context.Context, projectID domain.ProjectID, id domain.ScheduleID) (domain.Schedule, error)
+	Create(
+		ctx context.Context,
+		projectID domain.ProjectID,
+		dto domain.ScheduleDTO,
+		nextRunAt *time.Time,
+	) (domain.ScheduleID, error)
+	Update(
+		ctx context.Context,
+		projectID domain.ProjectID,
+		id domain.ScheduleID,
+		dto domain.ScheduleDTO,
+		nextRunAt *time.Time,
+	) error
+	SetEnabled(
+		ctx context.Context,
+		projectID domain.ProjectID,
+		id domain.ScheduleID,
+		enabled bool,
+		nextRunAt *time.Time,
+	) error
+	Delete(ctx context.Context, projectID domain.ProjectID, id domain.ScheduleID) error
+	ListDue(ctx context.Context, now time.Time, limit int) ([]domain.Schedule, error)
+	// ClaimRun records the activation identified by runKey and moves next_run_at forward,
+	// it returns false when the activation was claimed already.
+	ClaimRun(ctx context.Context, id domain.ScheduleID, runKey string, run domain.ScheduleRun) (bool, error)
+	SaveRun(ctx context.Context, id domain.ScheduleID, run domain.ScheduleRun) error
+}
+
+type SchedulesUseCase interface {
+	List(ctx context.Context, projectID domain.ProjectID, page, pageSize int) ([]domain.Schedule, int, error)
+	Get(ctx context.Context, projectID domain.ProjectID, id domain.ScheduleID) (domain.Schedule, error)
+	Create(
+		ctx context.Context,
+		tenantID domain.TenantID,
+		projectID domain.ProjectID,
+		dto domain.ScheduleDTO,
+	) (domain.Schedule, error)
+	Update(
+		ctx context.Context,
+		tenantID domain.TenantID,
+		projectID domain.ProjectID,
+		id domain.ScheduleID,
+		dto domain.ScheduleDTO,
+	) (domain.Schedule, error)
+	SetEnabled(ctx context.Context, projectID domain.ProjectID, id domain.ScheduleID, enabled bool) (domain.Schedule, error)
+	Delete(ctx context.Context, projectID domain.ProjectID, id domain.ScheduleID) error
+	PreviewNextRuns(cronExpr, timezone string, count int) ([]time.Time, error)
+	RunDue(ctx context.Context, now time.Time) (int, error)
+}
+
+// WorkflowEngine starts workflow instances in the floxy engine.
+type WorkflowEngine interface {
+	Start(ctx context.Context, workflowID string, input json.RawMessage) (int64, error)
+}
@@ -0,0 +1,22 @@
+package contract
+
+import (
+	"context"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type ServiceAccountsUseCase interface {
+	List(ctx context.Context, projectID domain.ProjectID) ([]domain.ServiceAccount, error)
+	Create(ctx context.Context, projectID domain.ProjectID, dto domain.ServiceAccountDTO) (domain.ServiceAccount, error)
+	Delete(ctx context.Context, projectID domain.ProjectID, id domain.UserID) error
+
+	ListTokens(ctx context.Context, projectID domain.ProjectID, id domain.UserID) ([]domain.APIToken, error)
+	CreateToken(
+		ctx context.Context,
+		projectID domain.ProjectID,
+		id domain.UserID,
+		dto domain.APITokenDTO,
+	) (domain.APIToken, string, error)
+	DeleteToken(ctx context.Context, projectID domain.ProjectID, id domain.UserID, tokenID domain.APITokenID) error
+}
@@ -0,0 +1,23 @@
+package contract
+
+import (
+	"context"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type SessionsRepository interface {
+	Create(ctx context.Context, dto domain.SessionDTO) (domain.SessionID, error)
+	GetByID(ctx context.Context, id domain.SessionID) (domain.Session, error)
+	// ListActive returns not revoked and not expired sessions of the user, newest first.
+	ListActive(ctx context.Context, userID domain.UserID, now time.Time) ([]domain.Session, error)
+	// Rotate replaces the current refresh token of an active session and extends it.
+	// It returns domain.ErrEntityNotFound if currentTokenID is no longer the current one.
+	// An empty currentTokenID matches sessions started before rotation.
+	Rotate(ctx context.Context, id domain.SessionID, currentTokenID, newTokenID string, lastUsedAt, expiresAt time.Time) error
+	Revoke(ctx context.Context, userID domain.UserID, id domain.SessionID, at time.Time) error
+	RevokeAll(ctx context.Context, userID domain.UserID, at time.Time) error
+	// RevokeReused revokes a session whose rotated refresh token was reused and records it in the audit log.
+	RevokeReused(ctx context.Context, userID domain.UserID, id domain.SessionID, at time.Time) error
+}
@@ -13,6 +13,24 @@ type SettingsUseCase interface {
 	SetSetting(ctx context.Context, name string, value any, description string) error
 	DeleteSetting(ctx context.Context, name string) error
 	ListSettings(ctx context.Context) ([]*domain.Setting, error)
+	GetAuditExportRowLimit(ctx context.Context) (int, error)
+	SetAuditExportRowLimit(ctx context.Context, limit int) error
+	GetAuditReadAccess(ctx context.Context) (bool, error)
+	SetAuditReadAccess(ctx context.Context, enabled bool) error
+	RuntimeSettingsReader
+	UpdateSMTPConfig(ctx context.Context, config domain.SMTPConfig) error
+	GetSAMLSettings(ctx context.Context) (domain.SAMLSettings, error)
+	UpdateSAMLSettings(ctx context.Context, settings domain.SAMLSettings) error
+	UpdateGeneralSettings(ctx context.Context, settings domain.GeneralSettings) error
+	UpdateLicenseAgreement(ctx context.Context, agreement domain.LicenseAgreement) error
+}
+
+// RuntimeSettingsReader gives the runtime-tunable configuration, saved settings take precedence
+// over the environment.
+type RuntimeSettingsReader interface {
+	GetSMTPConfig(ctx context.Context) (domain.SMTPConfig, error)
+	GetGeneralSettings(ctx context.Context) (domain.GeneralSettings, error)
+	GetLicenseAgreement(ctx context.Context) (domain.LicenseAgreement, error)
 }
 
 // SettingRepository defines the interface for settings operations.
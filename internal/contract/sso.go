@@ -14,13 +14,14 @@ type SSOProvider interface {
 	GetDisplayName() string
 	GetIconURL() string
 	IsEnabled() bool
-	GenerateAuthURL(state string) (string, error)
+	GenerateAuthURL(ctx context.Context, state string) (string, error)
 	GenerateSPMetadata() ([]byte, error)
 	Authenticate(ctx context.Context, req *http.Request, response, state string) (*domain.User, error)
 }
 
 type SSOProviderManager interface {
 	AddProvider(name string, provider SSOProvider, config domain.SSOProviderConfig)
+	RemoveProvider(name string)
 	GetProvider(name string) (SSOProvider, bool)
 	GetEnabledProviders() []SSOProvider
 	GetProviderConfig(name string) (domain.SSOProviderConfig, bool)
@@ -33,3 +34,29 @@ type SSOProviderManager interface {
 		state string,
 	) (*domain.User, error)
 }
+
+// SAMLReconfigurer applies changed settings to the running default SAML provider.
+type SAMLReconfigurer interface {
+	Reconfigure(settings domain.SAMLSettings, publicRootURL string) error
+}
+
+// SAMLProviderLoader registers SAML providers built from settings in the SSO provider manager.
+type SAMLProviderLoader interface {
+	// Load replaces the registered provider, a disabled provider is unregistered.
+	Load(provider domain.SSOProviderSettings, publicRootURL string) error
+	Unload(name string)
+	// FetchMetadata loads the Identity Provider metadata and returns its entity ID.
+	FetchMetadata(ctx context.Context, settings domain.SAMLSettings) (string, error)
+}
+
+type SSOProvidersUseCase interface {
+	List(ctx context.Context) ([]domain.SSOProviderSettings, error)
+	Get(ctx context.Context, name string) (domain.SSOProviderSettings, error)
+	Create(ctx context.Context, provider domain.SSOProviderSettings) error
+	Update(ctx context.Context, provider domain.SSOProviderSettings) error
+	SetEnabled(ctx context.Context, name string, enabled bool) (domain.SSOProviderSettings, error)
+	Delete(ctx context.Context, name string) error
+	TestMetadata(ctx context.Context, settings domain.SAMLSettings) (string, error)
+	// LoadAll registers the saved providers, it is called on startup.
+	LoadAll(ctx context.Context) error
+}
@@ -0,0 +1,36 @@
+package contract
+
+import (
+	"context"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type StepLogsRepository interface {
+	// Lock serializes appends to the log of the step until the end of the transaction.
+	Lock(ctx context.Context, stepID int) error
+	Size(ctx context.Context, stepID int) (int64, error)
+	Insert(ctx context.Context, stepID int, offset int64, content []byte) error
+	// Read passes the log bytes in [offset, offset+limit) to fn chunk by chunk.
+	Read(ctx context.Context, stepID int, offset, limit int64, fn func([]byte) error) error
+}
+
+type StepLogsUseCase interface {
+	// Append adds content to the log of a step of the instance and returns the new log size.
+	Append(
+		ctx context.Context,
+		tenantID domain.TenantID,
+		projectID domain.ProjectID,
+		instanceID, stepID int,
+		content []byte,
+	) (int64, error)
+	// Read passes up to limit bytes of the step log starting at offset to fn.
+	Read(
+		ctx context.Context,
+		tenantID domain.TenantID,
+		projectID domain.ProjectID,
+		instanceID, stepID int,
+		offset, limit int64,
+		fn func([]byte) error,
+	) (domain.StepLogInfo, error)
+}
@@ -7,10 +7,19 @@ import (
 )
 
 type Tokenizer interface {
-	AccessToken(user *domain.User) (string, error)
-	RefreshToken(user *domain.User) (string, error)
+	AccessToken(user *domain.User, sessionID domain.SessionID) (string, error)
+	// RefreshToken creates a refresh token with the given ID (jti).
+	RefreshToken(user *domain.User, sessionID domain.SessionID, tokenID string) (string, error)
+	// ImpersonationToken creates an access token acting as the user on behalf of the actor,
+	// bound to the session of the actor.
+	ImpersonationToken(user, actor *domain.User, actorSessionID domain.SessionID) (string, error)
+	ImpersonationTokenTTL() time.Duration
 	VerifyToken(token string, tokenType domain.TokenType) (*domain.TokenClaims, error)
 	ResetPasswordToken(user *domain.User) (string, time.Duration, error)
+	VerifyEmailToken(user *domain.User) (string, time.Duration, error)
 	AccessTokenTTL() time.Duration
+	RefreshTokenTTL() time.Duration
 	SecretKey() string
+	// JWKS returns the public keys downstream services validate tokens with.
+	JWKS() domain.JSONWebKeySet
 }
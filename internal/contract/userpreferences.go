@@ -0,0 +1,19 @@
+package contract
+
+import (
+	"context"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type UserPreferencesRepository interface {
+	Get(ctx context.Context, userID domain.UserID) (domain.UserPreferences, error)
+	// ListByUserIDs returns the saved preferences of the users, users with default preferences are not listed.
+	ListByUserIDs(ctx context.Context, userIDs []domain.UserID) ([]domain.UserPreferences, error)
+	Save(ctx context.Context, preferences domain.UserPreferences) error
+}
+
+type UserPreferencesUseCase interface {
+	Get(ctx context.Context, userID domain.UserID) (domain.UserPreferences, error)
+	Update(ctx context.Context, preferences domain.UserPreferences) (domain.UserPreferences, error)
+}
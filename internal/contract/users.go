@@ -5,6 +5,8 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/go-webauthn/webauthn/protocol"
+
 	"github.com/rom8726/floxy-manager/internal/domain"
 )
 
@@ -32,23 +34,62 @@ type UsersUseCase interface {
 		username, email, password string,
 		isSuperuser bool,
 	) (domain.User, error)
+	CreateWithMemberships(
+		ctx context.Context,
+		currentUser domain.User,
+		username, email, password string,
+		isSuperuser bool,
+		grants []domain.MembershipGrant,
+	) (domain.User, error)
 	SetSuperuserStatus(ctx context.Context, id domain.UserID, isSuperuser bool) (domain.User, error)
 	SetActiveStatus(ctx context.Context, id domain.UserID, isActive bool) (domain.User, error)
 	Delete(ctx context.Context, id domain.UserID) error
+	BulkUpdate(ctx context.Context, action domain.BulkUserAction, ids []domain.UserID) ([]domain.BulkUserResult, error)
+	Merge(ctx context.Context, sourceID, targetID domain.UserID) (domain.UserMergeResult, error)
+	Impersonate(ctx context.Context, userID domain.UserID) (accessToken string, expiresIn int, err error)
 	UpdatePassword(ctx context.Context, id domain.UserID, oldPassword, newPassword string) error
+	UpdateProfile(ctx context.Context, id domain.UserID, update domain.UserUpdate) (domain.User, error)
+	UpdateUser(ctx context.Context, id domain.UserID, update domain.UserUpdate) (domain.User, error)
 	ChangeTemporaryPassword(ctx context.Context, id domain.UserID, newPassword string) error
 	ForgotPassword(ctx context.Context, email string) error
 	ResetPassword(ctx context.Context, token, newPassword string) error
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerificationEmail(ctx context.Context, userID domain.UserID) error
 	Setup2FA(ctx context.Context, userID domain.UserID) (secret, qrURL, qrImage string, err error)
-	Confirm2FA(ctx context.Context, userID domain.UserID, code string) error
+	Confirm2FA(ctx context.Context, userID domain.UserID, code string) (recoveryCodes []string, err error)
 	Send2FACode(ctx context.Context, userID domain.UserID, action string) error
 	Disable2FA(ctx context.Context, userID domain.UserID, emailCode string) error
 	Reset2FA(ctx context.Context, userID domain.UserID, emailCode string) (secret, qrURL, qrImage string, err error)
 	Verify2FA(ctx context.Context, code, sessionID string) (accessToken, refreshToken string, expiresIn int, err error)
+	RegenerateRecoveryCodes(ctx context.Context, userID domain.UserID, code string) ([]string, error)
+	CountRecoveryCodes(ctx context.Context, userID domain.UserID) (int, error)
+	Get2FAMethods(ctx context.Context, sessionID string) ([]domain.TwoFAMethod, error)
+	BeginWebAuthnRegistration(ctx context.Context, userID domain.UserID) (*protocol.CredentialCreation, error)
+	FinishWebAuthnRegistration(
+		ctx context.Context,
+		userID domain.UserID,
+		name string,
+		req *http.Request,
+	) (domain.WebAuthnCredential, error)
+	ListWebAuthnCredentials(ctx context.Context, userID domain.UserID) ([]domain.WebAuthnCredential, error)
+	DeleteWebAuthnCredential(ctx context.Context, userID domain.UserID, id domain.WebAuthnCredentialID) error
+	BeginWebAuthnLogin(ctx context.Context, sessionID string) (*protocol.CredentialAssertion, error)
+	FinishWebAuthnLogin(
+		ctx context.Context,
+		sessionID string,
+		req *http.Request,
+	) (accessToken, refreshToken string, expiresIn int, err error)
 	VerifyTOTP(ctx context.Context, userID domain.UserID, code string) error
 	InitiateTOTPApproval(ctx context.Context, userID domain.UserID) (sessionID string, err error)
 	UpdateLicenseAcceptance(ctx context.Context, userID domain.UserID, accepted bool) error
+	AcceptLicense(ctx context.Context, userID domain.UserID, version string) error
 	VerifyPassword(ctx context.Context, userID domain.UserID, password string) error
+	ListSessions(ctx context.Context, userID domain.UserID) ([]domain.Session, error)
+	RevokeSession(ctx context.Context, userID domain.UserID, id domain.SessionID) error
+	RevokeAllSessions(ctx context.Context, userID domain.UserID) error
+	CheckSession(ctx context.Context, userID domain.UserID, id domain.SessionID) error
+	Logout(ctx context.Context, refreshToken string, all bool) error
+	LogoutSession(ctx context.Context, userID domain.UserID, id domain.SessionID, all bool) error
 }
 
 type UsersRepository interface {
@@ -61,7 +102,12 @@ type UsersRepository interface {
 	Update(ctx context.Context, user *domain.User) error
 	Delete(ctx context.Context, id domain.UserID) error
 	List(ctx context.Context) ([]domain.User, error)
+	ListServiceAccounts(ctx context.Context, projectID domain.ProjectID) ([]domain.User, error)
 	UpdateLastLogin(ctx context.Context, id domain.UserID) error
 	UpdatePassword(ctx context.Context, id domain.UserID, passwordHash string) error
+	AcceptLicense(ctx context.Context, id domain.UserID, version string, acceptedAt time.Time) error
 	Update2FA(ctx context.Context, id domain.UserID, enabled bool, secret string, confirmedAt *time.Time) error
+	MarkEmailVerified(ctx context.Context, id domain.UserID) error
+	UpdateProfile(ctx context.Context, id domain.UserID, username, email, displayName string) error
+	Merge(ctx context.Context, sourceID, targetID domain.UserID) (domain.UserMergeResult, error)
 }
@@ -0,0 +1,40 @@
+package contract
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type VariablesRepository interface {
+	List(ctx context.Context, projectID domain.ProjectID) ([]domain.ProjectVariable, error)
+	Get(ctx context.Context, projectID domain.ProjectID, name string) (domain.ProjectVariable, error)
+	// ListSecretNames returns the names of the secret variables of the projects, by project.
+	ListSecretNames(ctx context.Context, projectIDs []domain.ProjectID) (map[domain.ProjectID][]string, error)
+	// Upsert stores the encrypted value and returns true when the variable was created.
+	Upsert(
+		ctx context.Context,
+		projectID domain.ProjectID,
+		name string,
+		valueEncrypted string,
+		secret bool,
+	) (bool, error)
+	Delete(ctx context.Context, projectID domain.ProjectID, name string) error
+}
+
+// VariablesInjector adds the project variables to the input of a workflow instance about to start.
+type VariablesInjector interface {
+	InjectVariables(ctx context.Context, projectID domain.ProjectID, input json.RawMessage) (json.RawMessage, error)
+}
+
+type VariablesUseCase interface {
+	VariablesInjector
+
+	// List returns the variables with the values of those that are not secret.
+	List(ctx context.Context, projectID domain.ProjectID) ([]domain.ProjectVariable, error)
+	Get(ctx context.Context, projectID domain.ProjectID, name string) (domain.ProjectVariable, error)
+	// Set creates or replaces the variable, returns true when it was created.
+	Set(ctx context.Context, projectID domain.ProjectID, dto domain.ProjectVariableDTO) (domain.ProjectVariable, bool, error)
+	Delete(ctx context.Context, projectID domain.ProjectID, name string) error
+}
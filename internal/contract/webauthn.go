@@ -0,0 +1,16 @@
+package contract
+
+import (
+	"context"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type WebAuthnCredentialsRepository interface {
+	Create(ctx context.Context, cred domain.WebAuthnCredential) (domain.WebAuthnCredential, error)
+	List(ctx context.Context, userID domain.UserID) ([]domain.WebAuthnCredential, error)
+	// UpdateCredential stores the authenticator data after a successful assertion.
+	UpdateCredential(ctx context.Context, id domain.WebAuthnCredentialID, credential []byte, usedAt time.Time) error
+	Delete(ctx context.Context, userID domain.UserID, id domain.WebAuthnCredentialID) error
+}
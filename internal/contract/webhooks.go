@@ -0,0 +1,57 @@
+package contract
+
+import (
+	"context"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type WebhooksRepository interface {
+	List(ctx context.Context, projectID domain.ProjectID) ([]domain.Webhook, error)
+	GetByID(ctx context.Context, projectID domain.ProjectID, id domain.WebhookID) (domain.Webhook, error)
+	Create(ctx context.Context, projectID domain.ProjectID, dto domain.WebhookDTO) (domain.WebhookID, error)
+	Update(ctx context.Context, projectID domain.ProjectID, id domain.WebhookID, dto domain.WebhookDTO) error
+	Delete(ctx context.Context, projectID domain.ProjectID, id domain.WebhookID) error
+	// ListSubscribed returns enabled webhooks of the project subscribed to the event type.
+	ListSubscribed(
+		ctx context.Context,
+		projectID domain.ProjectID,
+		eventType domain.LifecycleEventType,
+	) ([]domain.Webhook, error)
+
+	// EnqueueDelivery creates a pending delivery. It reports false if the event was already enqueued.
+	EnqueueDelivery(ctx context.Context, delivery domain.WebhookDelivery) (bool, error)
+	// ListDueDeliveries locks pending deliveries whose next attempt is due.
+	ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]domain.WebhookDelivery, error)
+	SaveDeliveryAttempt(ctx context.Context, delivery domain.WebhookDelivery) error
+	ListDeliveries(
+		ctx context.Context,
+		webhookID domain.WebhookID,
+		page, pageSize int,
+	) ([]domain.WebhookDelivery, int, error)
+}
+
+type WebhooksUseCase interface {
+	LifecycleEventHandler
+
+	List(ctx context.Context, projectID domain.ProjectID) ([]domain.Webhook, error)
+	Get(ctx context.Context, projectID domain.ProjectID, id domain.WebhookID) (domain.Webhook, error)
+	Create(ctx context.Context, projectID domain.ProjectID, dto domain.WebhookDTO) (domain.Webhook, error)
+	Update(
+		ctx context.Context,
+		projectID domain.ProjectID,
+		id domain.WebhookID,
+		dto domain.WebhookDTO,
+	) (domain.Webhook, error)
+	Delete(ctx context.Context, projectID domain.ProjectID, id domain.WebhookID) error
+	ListDeliveries(
+		ctx context.Context,
+		projectID domain.ProjectID,
+		id domain.WebhookID,
+		page, pageSize int,
+	) ([]domain.WebhookDelivery, int, error)
+
+	// DeliverPending sends due deliveries and schedules retries for failed ones.
+	DeliverPending(ctx context.Context, now time.Time) (int, error)
+}
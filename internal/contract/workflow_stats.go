@@ -0,0 +1,13 @@
+package contract
+
+import (
+	"context"
+	"time"
+)
+
+// WorkflowStatsUseCase keeps the materialized workflow statistics fresh.
+type WorkflowStatsUseCase interface {
+	// Refresh recomputes the statistics unless they were refreshed moments ago, returns the refresh time.
+	Refresh(ctx context.Context) (time.Time, error)
+	RefreshedAt(ctx context.Context) (time.Time, error)
+}
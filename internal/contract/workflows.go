@@ -3,6 +3,7 @@ package contract
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/rom8726/floxy-manager/internal/domain"
 )
@@ -20,26 +21,68 @@ type WorkflowsRepository interface {
 		projectID domain.ProjectID,
 		id string,
 	) (domain.WorkflowDefinition, error)
+	GetWorkflowDefinitionByVersion(
+		ctx context.Context,
+		tenantID domain.TenantID,
+		projectID domain.ProjectID,
+		name string,
+		version int,
+	) (domain.WorkflowDefinition, error)
 	ListWorkflowInstances(
 		ctx context.Context,
 		tenantID domain.TenantID,
 		projectID domain.ProjectID,
 		workflowID string,
+		fields domain.FieldSet,
 		page, pageSize int,
 	) ([]domain.WorkflowInstance, int, error)
+	// ListWorkflowInstancesAfter returns up to limit instances after the cursor (from the newest when nil)
+	// and the cursor of the next page, nil on the last page.
+	ListWorkflowInstancesAfter(
+		ctx context.Context,
+		tenantID domain.TenantID,
+		projectID domain.ProjectID,
+		workflowID string,
+		fields domain.FieldSet,
+		cursor *domain.Cursor,
+		limit int,
+	) ([]domain.WorkflowInstance, *domain.Cursor, error)
 	GetWorkflowInstance(
 		ctx context.Context,
 		tenantID domain.TenantID,
 		projectID domain.ProjectID,
 		id int,
 	) (domain.WorkflowInstance, error)
+	CreateInstanceLineage(ctx context.Context, instanceID, parentInstanceID int) error
 	ListWorkflowSteps(
 		ctx context.Context,
 		tenantID domain.TenantID,
 		projectID domain.ProjectID,
 		instanceID int,
+		fields domain.FieldSet,
 		page, pageSize int,
 	) ([]domain.WorkflowStep, int, error)
+	FindWorkflowDefinition(ctx context.Context, name string, version int) (domain.WorkflowDefinition, error)
+	SearchWorkflowDefinitions(
+		ctx context.Context,
+		tenantID domain.TenantID,
+		projectID domain.ProjectID,
+		query domain.WorkflowDefinitionSearchQuery,
+		page, pageSize int,
+	) ([]domain.WorkflowDefinition, int, error)
+	SearchWorkflowInstances(
+		ctx context.Context,
+		tenantID domain.TenantID,
+		projectID domain.ProjectID,
+		query domain.InstanceSearchQuery,
+		page, pageSize int,
+	) ([]domain.WorkflowInstance, int, error)
+	GetWorkflowStep(
+		ctx context.Context,
+		tenantID domain.TenantID,
+		projectID domain.ProjectID,
+		instanceID, stepID int,
+	) (domain.WorkflowStep, error)
 	ListWorkflowEvents(
 		ctx context.Context,
 		tenantID domain.TenantID,
@@ -47,6 +90,14 @@ type WorkflowsRepository interface {
 		instanceID int,
 		page, pageSize int,
 	) ([]domain.WorkflowEvent, int, error)
+	ListWorkflowEventsAfter(
+		ctx context.Context,
+		tenantID domain.TenantID,
+		projectID domain.ProjectID,
+		instanceID int,
+		cursor *domain.Cursor,
+		limit int,
+	) ([]domain.WorkflowEvent, *domain.Cursor, error)
 	ListActiveWorkflows(
 		ctx context.Context,
 		tenantID domain.TenantID,
@@ -59,12 +110,32 @@ type WorkflowsRepository interface {
 		projectID domain.ProjectID,
 		page, pageSize int,
 	) ([]domain.WorkflowStat, int, error)
+	// RefreshWorkflowStats recomputes the materialized workflow statistics and returns the refresh time.
+	RefreshWorkflowStats(ctx context.Context) (time.Time, error)
+	// WorkflowStatsRefreshedAt returns the time of the last workflow statistics refresh.
+	WorkflowStatsRefreshedAt(ctx context.Context) (time.Time, error)
+	// ListWorkflowFailures groups failed steps of the workflow since the given time, most frequent first.
+	ListWorkflowFailures(
+		ctx context.Context,
+		tenantID domain.TenantID,
+		projectID domain.ProjectID,
+		workflowID string,
+		since time.Time,
+		limit int,
+	) ([]domain.WorkflowFailureGroup, error)
 	ListDLQItems(
 		ctx context.Context,
 		tenantID domain.TenantID,
 		projectID domain.ProjectID,
 		page, pageSize int,
 	) ([]domain.DLQItem, int, error)
+	ListDLQItemsAfter(
+		ctx context.Context,
+		tenantID domain.TenantID,
+		projectID domain.ProjectID,
+		cursor *domain.Cursor,
+		limit int,
+	) ([]domain.DLQItem, *domain.Cursor, error)
 	GetDLQItem(
 		ctx context.Context,
 		tenantID domain.TenantID,
@@ -87,4 +158,100 @@ type WorkflowsRepository interface {
 		version int,
 		definition json.RawMessage,
 	) (string, error)
+	UpdateWorkflowDefinition(
+		ctx context.Context,
+		tenantID domain.TenantID,
+		projectID domain.ProjectID,
+		id string,
+		definition json.RawMessage,
+	) (string, error)
+	DeleteWorkflowDefinition(
+		ctx context.Context,
+		tenantID domain.TenantID,
+		projectID domain.ProjectID,
+		id string,
+	) error
+	IsWorkflowDefinitionInUse(ctx context.Context, id string) (bool, error)
+	TransferWorkflowDefinition(
+		ctx context.Context,
+		fromProjectID domain.ProjectID,
+		toProjectID domain.ProjectID,
+		id string,
+	) error
+
+	ListActiveVersions(ctx context.Context, projectID domain.ProjectID) ([]domain.WorkflowActiveVersion, error)
+	GetActiveVersion(ctx context.Context, projectID domain.ProjectID, name string) (domain.WorkflowActiveVersion, error)
+	SetActiveVersion(
+		ctx context.Context,
+		projectID domain.ProjectID,
+		name string,
+		version int,
+		action string,
+	) (domain.WorkflowActiveVersion, error)
+	DeleteActiveVersion(ctx context.Context, projectID domain.ProjectID, name string) error
+	ListVersionRollouts(
+		ctx context.Context,
+		projectID domain.ProjectID,
+		name string,
+		limit int,
+	) ([]domain.WorkflowRollout, error)
+	GetPromotedFromVersion(ctx context.Context, projectID domain.ProjectID, name string, version int) (int, error)
+	// ResolveActiveWorkflowID returns the definition new instances of workflowID start on in the project.
+	ResolveActiveWorkflowID(ctx context.Context, projectID domain.ProjectID, workflowID string) (string, error)
+}
+
+type WorkflowsUseCase interface {
+	// UpdateDefinition stores the definition as the next version of the workflow and returns its ID.
+	UpdateDefinition(
+		ctx context.Context,
+		tenantID domain.TenantID,
+		projectID domain.ProjectID,
+		id string,
+		definition json.RawMessage,
+	) (string, error)
+	// DeleteDefinition unassigns the definition from the project and deletes it when no other
+	// project references it.
+	DeleteDefinition(ctx context.Context, tenantID domain.TenantID, projectID domain.ProjectID, id string) error
+	TransferDefinition(
+		ctx context.Context,
+		tenantID domain.TenantID,
+		fromProjectID domain.ProjectID,
+		toProjectID domain.ProjectID,
+		id string,
+		includeInstances bool,
+	) error
+	// ImportDefinitions creates the definitions in the project in one transaction.
+	// Nothing is created when an item is invalid or conflicts with an existing definition.
+	ImportDefinitions(
+		ctx context.Context,
+		projectID domain.ProjectID,
+		items []domain.WorkflowImportItem,
+		dryRun bool,
+	) ([]domain.WorkflowImportResult, error)
+	// AssignDefinitionsToProject assigns the definitions, or all unassigned ones when ids is empty,
+	// to the project and audits them in one transaction.
+	AssignDefinitionsToProject(ctx context.Context, projectID domain.ProjectID, ids []string) (int, error)
+	PromoteVersion(
+		ctx context.Context,
+		projectID domain.ProjectID,
+		name string,
+		version int,
+	) (domain.WorkflowActiveVersion, error)
+	// RollbackVersion pins the workflow back to version, or to the version active before
+	// the current one was promoted when version is 0.
+	RollbackVersion(
+		ctx context.Context,
+		projectID domain.ProjectID,
+		name string,
+		version int,
+	) (domain.WorkflowActiveVersion, error)
+	// RerunInstance starts a new instance of the definition of the instance with its input,
+	// edited by the JSON Patch (RFC 6902) when given, and links it to the original instance.
+	RerunInstance(
+		ctx context.Context,
+		tenantID domain.TenantID,
+		projectID domain.ProjectID,
+		instanceID int,
+		patch json.RawMessage,
+	) (domain.WorkflowInstance, error)
 }
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"time"
+)
+
+const DefaultAlertRecipientRole = "project_owner"
+
+// AlertSettings configures email alerts of a project.
+type AlertSettings struct {
+	ProjectID ProjectID `json:"project_id"`
+	Enabled   bool      `json:"enabled"`
+	// RecipientRoleKey selects the project members that receive alerts.
+	RecipientRoleKey string `json:"recipient_role_key"`
+	NotifyOnFailure  bool   `json:"notify_on_failure"`
+	// DurationThresholdSeconds enables alerts for instances running longer than the threshold.
+	DurationThresholdSeconds *int `json:"duration_threshold_seconds"`
+	// DigestEnabled batches alerts into at most one email per hour.
+	DigestEnabled bool       `json:"digest_enabled"`
+	LastDigestAt  *time.Time `json:"last_digest_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// Alert is a queued email alert about a lifecycle event.
+type Alert struct {
+	ID        int64
+	ProjectID ProjectID
+	Event     LifecycleEvent
+	CreatedAt time.Time
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// APITokenPrefix marks bearer tokens that are API tokens rather than JWTs.
+const APITokenPrefix = "flx_"
+
+type APITokenID int
+
+func (id APITokenID) Int() int {
+	return int(id)
+}
+
+func (id APITokenID) String() string {
+	return strconv.Itoa(int(id))
+}
+
+type APITokenScope string
+
+const (
+	// APITokenScopeRead allows safe (GET, HEAD) requests only.
+	APITokenScopeRead APITokenScope = "read"
+	// APITokenScopeWrite allows any request.
+	APITokenScopeWrite APITokenScope = "write"
+)
+
+func (s APITokenScope) Valid() bool {
+	return s == APITokenScopeRead || s == APITokenScopeWrite
+}
+
+// APIToken is a personal access token. Only its hash is stored; Prefix helps users recognize it.
+type APIToken struct {
+	ID         APITokenID      `json:"id"`
+	UserID     UserID          `json:"user_id"`
+	Name       string          `json:"name"`
+	Prefix     string          `json:"prefix"`
+	Scopes     []APITokenScope `json:"scopes"`
+	ExpiresAt  *time.Time      `json:"expires_at"`
+	LastUsedAt *time.Time      `json:"last_used_at"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+func (t *APIToken) Expired(now time.Time) bool {
+	return t.ExpiresAt != nil && !now.Before(*t.ExpiresAt)
+}
+
+// Allows reports whether the token scopes permit a request with the given HTTP method.
+func (t *APIToken) Allows(method string) bool {
+	for _, scope := range t.Scopes {
+		switch scope {
+		case APITokenScopeWrite:
+			return true
+		case APITokenScopeRead:
+			if method == http.MethodGet || method == http.MethodHead {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+type APITokenDTO struct {
+	Name      string
+	Scopes    []APITokenScope
+	ExpiresAt *time.Time
+}
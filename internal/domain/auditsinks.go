@@ -0,0 +1,33 @@
+package domain
+
+// AuditSinkType is the kind of external system audit log entries are forwarded to.
+type AuditSinkType string
+
+const (
+	AuditSinkSyslog  AuditSinkType = "syslog"
+	AuditSinkKafka   AuditSinkType = "kafka"
+	AuditSinkWebhook AuditSinkType = "webhook"
+)
+
+// AuditSinkConfig describes an external system (e.g. a SIEM such as Splunk or Elastic)
+// that receives audit log entries in addition to Postgres.
+type AuditSinkConfig struct {
+	Name    string        `json:"name"`
+	Type    AuditSinkType `json:"type"`
+	Enabled bool          `json:"enabled"`
+	// Address is host:port of a syslog server.
+	Address string `json:"address,omitempty"`
+	// Network is the syslog transport: udp (default) or tcp.
+	Network string `json:"network,omitempty"`
+	// URL is the webhook endpoint or the base URL of a Kafka REST Proxy.
+	URL string `json:"url,omitempty"`
+	// Topic is the Kafka topic entries are produced to.
+	Topic string `json:"topic,omitempty"`
+	// Token is sent as a bearer token to webhooks and Kafka REST Proxy. Stored encrypted.
+	Token string `json:"token,omitempty"`
+}
+
+// AuditSinksConfig is the list of audit sinks stored in settings.
+type AuditSinksConfig struct {
+	Sinks []AuditSinkConfig `json:"sinks"`
+}
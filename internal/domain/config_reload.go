@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// ConfigChange is a setting changed on a configuration reload. Reloaded is false for the settings
+// that need a restart. The values of secrets are redacted.
+type ConfigChange struct {
+	Key      string `json:"key"`
+	Old      string `json:"old"`
+	New      string `json:"new"`
+	Reloaded bool   `json:"reloaded"`
+}
+
+// ConfigReload is the result of a configuration reload.
+type ConfigReload struct {
+	Changes    []ConfigChange `json:"changes"`
+	ReloadedAt time.Time      `json:"reloaded_at"`
+}
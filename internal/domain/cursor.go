@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cursor is a keyset pagination position: created_at and id of the last item of a page.
+// Lists ordered by created_at DESC, id DESC continue with items strictly before it.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        int
+}
+
+// String encodes the cursor as an opaque URL-safe token.
+func (c Cursor) String() string {
+	raw := strconv.FormatInt(c.CreatedAt.UnixNano(), 10) + "," + strconv.Itoa(c.ID)
+
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParseCursor decodes a token produced by Cursor.String.
+func ParseCursor(token string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	createdAtStr, idStr, ok := strings.Cut(string(raw), ",")
+	if !ok {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	createdAt, err := strconv.ParseInt(createdAtStr, 10, 64)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	return Cursor{CreatedAt: time.Unix(0, createdAt).UTC(), ID: id}, nil
+}
@@ -0,0 +1,18 @@
+package domain
+
+// DeletionImpact counts what still depends on a project or a tenant that is about to be deleted.
+type DeletionImpact struct {
+	// Projects and TenantMemberships are counted for tenants only.
+	Projects          int `json:"projects,omitempty"`
+	TenantMemberships int `json:"tenant_memberships,omitempty"`
+	// LiveInstances are the instances that have not finished yet.
+	LiveInstances int `json:"live_instances"`
+	Memberships   int `json:"memberships"`
+	// Workflows are the assigned workflow definitions.
+	Workflows int `json:"workflows"`
+}
+
+// Empty reports whether nothing depends on the project or the tenant.
+func (i DeletionImpact) Empty() bool {
+	return i == DeletionImpact{}
+}
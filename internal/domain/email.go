@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"time"
+)
+
+type EmailStatus string
+
+const (
+	EmailPending EmailStatus = "pending"
+	EmailSent    EmailStatus = "sent"
+	// EmailFailed is the dead-letter status of emails that were not sent after the last attempt.
+	EmailFailed EmailStatus = "failed"
+)
+
+// OutboxEmail is an email queued for the background sender.
+type OutboxEmail struct {
+	ID            int64       `json:"id"`
+	Recipient     string      `json:"recipient"`
+	Subject       string      `json:"subject"`
+	Status        EmailStatus `json:"status"`
+	Attempts      int         `json:"attempts"`
+	NextAttemptAt *time.Time  `json:"next_attempt_at"`
+	LastError     *string     `json:"last_error"`
+	CreatedAt     time.Time   `json:"created_at"`
+	SentAt        *time.Time  `json:"sent_at"`
+
+	// Body may contain tokens and codes, it is never returned and is cleared once the email is sent.
+	Body string `json:"-"`
+}
@@ -1,17 +1,60 @@
 package domain
 
 const (
-	EntityUser       = "user"
-	EntityProject    = "project"
-	EntityTenant     = "tenant"
-	EntityWorkflow   = "workflow"
-	EntityPlugin     = "plugin"
-	EntityMembership = "membership"
+	EntityUser                = "user"
+	EntityProject             = "project"
+	EntityTenant              = "tenant"
+	EntityWorkflow            = "workflow"
+	EntityPlugin              = "plugin"
+	EntityMembership          = "membership"
+	EntitySchedule            = "schedule"
+	EntityHook                = "hook"
+	EntityWebhook             = "webhook"
+	EntityNotificationChannel = "notification_channel"
+	EntityAlertSettings       = "alert_settings"
+	EntityAPIToken            = "api_token"
+	EntityWebAuthnCredential  = "webauthn_credential"
+	EntityLDAPConfig          = "ldap_config"
+	EntityAuditLog            = "audit_log"
+	EntityDLQItem             = "dlq_item"
+	EntityRetentionSettings   = "retention_settings"
+	EntityInstance            = "instance"
+	EntityProjectVariable     = "project_variable"
+	EntityRedactionRules      = "redaction_rules"
+	EntityTenantMembership    = "tenant_membership"
+	EntityProjectSettings     = "project_settings"
+	EntitySetting             = "setting"
+	EntitySSOProvider         = "sso_provider"
+	EntityEmail               = "email"
+	EntitySession             = "session"
+	EntityMigration           = "migration"
+	EntityConfig              = "config"
+	EntityLicense             = "license"
 )
 
 const (
-	ActionCreate  = "create"
-	ActionUpdate  = "update"
-	ActionDelete  = "delete"
-	ActionArchive = "archive"
+	ActionCreate   = "create"
+	ActionUpdate   = "update"
+	ActionDelete   = "delete"
+	ActionArchive  = "archive"
+	ActionTransfer = "transfer"
+	ActionEnable   = "enable"
+	ActionDisable  = "disable"
+	ActionRotate   = "rotate"
+	ActionRead     = "read"
+	ActionPromote  = "promote"
+	ActionRollback = "rollback"
+	ActionPause    = "pause"
+	ActionResume   = "resume"
+	ActionSignal   = "signal"
+	ActionPurge    = "purge"
+	ActionResend   = "resend"
+	ActionApply    = "apply"
+	ActionReload   = "reload"
+	ActionAssign   = "assign"
+	ActionMerge    = "merge"
+	// ActionImpersonate marks an impersonation token issued to a superuser.
+	ActionImpersonate = "impersonate"
+	// ActionTokenReuse marks a session revoked because a rotated refresh token was presented again.
+	ActionTokenReuse = "token_reuse"
 )
@@ -7,6 +7,7 @@ import (
 var (
 	ErrEntityNotFound       = errors.New("entity not found")
 	ErrEntityAlreadyExists  = errors.New("entity already exists")
+	ErrEntityInUse          = errors.New("entity in use")
 	ErrInvalidToken         = errors.New("invalid token")
 	ErrUsernameAlreadyInUse = errors.New("username already in use")
 	ErrEmailAlreadyInUse    = errors.New("email already in use")
@@ -19,6 +20,20 @@ var (
 	ErrInvalidEmailCode     = errors.New("invalid email code")
 	ErrTwoFARequired        = errors.New("2FA required")
 	ErrTooMany2FAAttempts   = errors.New("too many 2FA attempts, try later")
+	ErrServiceAccountLogin  = errors.New("service accounts can authenticate with API tokens only")
+	ErrRoleScopeMismatch    = errors.New("role cannot be assigned in this scope")
+
+	ErrEmailNotVerified          = errors.New("email address is not verified")
+	ErrEmailAlreadyVerified      = errors.New("email address is already verified")
+	ErrTooManyVerificationEmails = errors.New("verification email was sent recently, try later")
+	ErrNoWebAuthnCredentials     = errors.New("no security keys registered")
+	ErrInvalidWebAuthnResponse   = errors.New("invalid security key response")
+	ErrInvalidCursor             = errors.New("invalid pagination cursor")
+	ErrStepLogTooLarge           = errors.New("step log size limit exceeded")
+	ErrInvalidUserMerge          = errors.New("only two different active non-service accounts can be merged")
+	ErrInvalidLicense            = errors.New("invalid license")
+	ErrFeatureNotLicensed        = errors.New("feature is not available in the current license")
+	ErrPayloadNotSearchable      = errors.New("payloads cannot be searched without matching redacted values")
 )
 
 type SkippableError struct {
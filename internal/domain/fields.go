@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"slices"
+)
+
+// FieldSet selects fields of list items by their JSON names. The zero value selects all fields.
+type FieldSet struct {
+	// Include lists the only fields to return, all fields when empty.
+	Include []string
+	// Exclude lists fields not to return.
+	Exclude []string
+}
+
+// All reports whether the set selects every field.
+func (s FieldSet) All() bool {
+	return len(s.Include) == 0 && len(s.Exclude) == 0
+}
+
+// Has reports whether the field is selected.
+func (s FieldSet) Has(field string) bool {
+	if len(s.Include) > 0 && !slices.Contains(s.Include, field) {
+		return false
+	}
+
+	return !slices.Contains(s.Exclude, field)
+}
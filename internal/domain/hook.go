@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"strconv"
+	"time"
+)
+
+type HookID int
+
+func (id HookID) String() string {
+	return strconv.Itoa(int(id))
+}
+
+func (id HookID) Int() int {
+	return int(id)
+}
+
+// Hook is an inbound webhook that starts a workflow with the request body as input.
+type Hook struct {
+	ID                 HookID     `json:"id"`
+	ProjectID          ProjectID  `json:"project_id"`
+	WorkflowID         string     `json:"workflow_id"`
+	Name               string     `json:"name"`
+	Token              string     `json:"token"`
+	RequireSignature   bool       `json:"require_signature"`
+	RateLimitPerMinute int        `json:"rate_limit_per_minute"`
+	Enabled            bool       `json:"enabled"`
+	LastTriggeredAt    *time.Time `json:"last_triggered_at"`
+	CreatedBy          string     `json:"created_by"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+
+	// Secret is the plain HMAC secret. It is only filled right after creation or rotation.
+	Secret string `json:"secret,omitempty"`
+	// SecretEncrypted is the stored (encrypted) form of the secret.
+	SecretEncrypted string `json:"-"`
+}
+
+type HookDTO struct {
+	WorkflowID         string
+	Name               string
+	RequireSignature   bool
+	RateLimitPerMinute int
+	Enabled            bool
+}
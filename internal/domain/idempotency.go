@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"time"
+)
+
+// IdempotentRequest is a mutating request sent with an Idempotency-Key header and its response.
+// The key is scoped by the user, the method and the path.
+type IdempotentRequest struct {
+	UserID      UserID
+	Method      string
+	Path        string
+	Key         string
+	RequestHash string
+	// StatusCode is zero while the request is in progress.
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	ExpiresAt   time.Time
+}
+
+// Completed reports whether the response of the request is stored.
+func (r *IdempotentRequest) Completed() bool {
+	return r.StatusCode != 0
+}
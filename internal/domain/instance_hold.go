@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"time"
+)
+
+// InstanceHold pauses a workflow instance: its queued steps are kept aside until it is resumed.
+type InstanceHold struct {
+	InstanceID int       `json:"instance_id"`
+	ProjectID  ProjectID `json:"project_id"`
+	Reason     string    `json:"reason"`
+	// HeldSteps is the number of queued steps kept aside.
+	HeldSteps int       `json:"held_steps"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// InstanceSignal is an external event delivered to a waiting step of a workflow instance.
+type InstanceSignal struct {
+	ID             int64           `json:"id"`
+	InstanceID     int             `json:"instance_id"`
+	ProjectID      ProjectID       `json:"project_id"`
+	StepID         int             `json:"step_id"`
+	Name           string          `json:"name"`
+	Payload        json.RawMessage `json:"payload,omitempty"`
+	Decision       string          `json:"decision"`
+	IdempotencyKey *string         `json:"idempotency_key,omitempty"`
+	CreatedBy      string          `json:"created_by"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+type InstanceSignalDTO struct {
+	// Name addresses the waiting step of the same name
+	Name           string
+	Payload        json.RawMessage
+	Decision       string
+	IdempotencyKey *string
+}
@@ -10,6 +10,7 @@ const (
 	TokenTypeAccess        TokenType = "accessToken"
 	TokenTypeRefresh       TokenType = "refreshToken"
 	TokenTypeResetPassword TokenType = "resetPassword"
+	TokenTypeVerifyEmail   TokenType = "verifyEmail"
 )
 
 type TokenClaims struct {
@@ -18,4 +19,35 @@ type TokenClaims struct {
 	UserID      uint      `json:"userId"`
 	Username    string    `json:"username"`
 	IsSuperuser bool      `json:"isSuperuser"`
+	SessionID   SessionID `json:"sid,omitempty"`
+	// Email binds an email verification token to the address it was sent to.
+	Email string `json:"email,omitempty"`
+	// Actor is the superuser impersonating the user, the act claim of RFC 8693.
+	// The session of an impersonation token is the one of the actor.
+	Actor *TokenActor `json:"act,omitempty"`
+}
+
+// TokenActor identifies the superuser acting with an impersonation token.
+type TokenActor struct {
+	UserID   uint   `json:"userId"`
+	Username string `json:"username"`
+}
+
+// JSONWebKey is a public token verification key in the RFC 7517 format.
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	// N and E are the RSA modulus and exponent.
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Crv and X are the curve and the public key of OKP keys (Ed25519).
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JSONWebKeySet lists the keys downstream services use to validate access tokens.
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
 }
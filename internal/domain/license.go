@@ -42,4 +42,7 @@ type LicenseStatus struct {
 	IsExpired       bool        `json:"is_expired"`
 	DaysUntilExpiry int         `json:"days_until_expiry"`
 	LicenseText     string      `json:"license_text"`
+	// Enforced is false when no license public key is configured, all features are available then.
+	Enforced bool             `json:"enforced"`
+	Features []LicenseFeature `json:"features"`
 }
@@ -12,6 +12,9 @@ const (
 
 	// (Slack, Pachca, Mattermost, Webhooks).
 	FeatureCorpNotifChannels LicenseFeature = "corp_notif_channels"
+
+	// FeatureAuditExport represents the export of the audit log.
+	FeatureAuditExport LicenseFeature = "audit_export"
 )
 
 // GetAvailableFeatures returns the list of features available for the given license type.
@@ -23,6 +26,7 @@ func GetAvailableFeatures(licenseType LicenseType) []LicenseFeature {
 			FeatureSSO,
 			FeatureLDAP,
 			FeatureCorpNotifChannels,
+			FeatureAuditExport,
 		}
 	default:
 		// Default to no features for unknown license types
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+type LifecycleEventType string
+
+const (
+	EventInstanceCompleted LifecycleEventType = "instance_completed"
+	EventInstanceFailed    LifecycleEventType = "instance_failed"
+	EventDLQItemCreated    LifecycleEventType = "dlq_item_created"
+
+	// EventLongRunningInstance is derived from the instance state (running longer than
+	// a threshold) rather than read from the event stream.
+	EventLongRunningInstance LifecycleEventType = "long_running_instance"
+)
+
+// Valid reports whether t is an event type read from the lifecycle event stream.
+func (t LifecycleEventType) Valid() bool {
+	switch t {
+	case EventInstanceCompleted, EventInstanceFailed, EventDLQItemCreated:
+		return true
+	default:
+		return false
+	}
+}
+
+// LifecycleEvent is a workflow lifecycle event detected in the floxy tables.
+type LifecycleEvent struct {
+	Type       LifecycleEventType `json:"type"`
+	ProjectID  ProjectID          `json:"project_id"`
+	WorkflowID string             `json:"workflow_id"`
+	InstanceID int64              `json:"instance_id"`
+	DLQItemID  *int64             `json:"dlq_item_id,omitempty"`
+	StepName   *string            `json:"step_name,omitempty"`
+	Status     string             `json:"status"`
+	Error      *string            `json:"error,omitempty"`
+	OccurredAt time.Time          `json:"occurred_at"`
+}
+
+// Key identifies the event so that it is delivered at most once per subscriber.
+func (e LifecycleEvent) Key() string {
+	if e.DLQItemID != nil {
+		return fmt.Sprintf("%s:%d", e.Type, *e.DLQItemID)
+	}
+
+	return fmt.Sprintf("%s:%d", e.Type, e.InstanceID)
+}
@@ -0,0 +1,7 @@
+package domain
+
+// LogSettings are the level (debug, info, warn or error) and the format (text or json) of the logs.
+type LogSettings struct {
+	Level  string `json:"level"`
+	Format string `json:"format"`
+}
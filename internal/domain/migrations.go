@@ -0,0 +1,15 @@
+package domain
+
+// Migration is a schema migration found in the migrations directory.
+type Migration struct {
+	Version uint   `json:"version"`
+	Name    string `json:"name"`
+}
+
+// MigrationStatus is the state of the database schema. Version is 0 before the first migration,
+// Dirty is set when a migration failed halfway and the schema needs a manual fix.
+type MigrationStatus struct {
+	Version uint        `json:"version"`
+	Dirty   bool        `json:"dirty"`
+	Pending []Migration `json:"pending"`
+}
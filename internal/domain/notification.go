@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"fmt"
+)
+
+// NotificationTargetKind selects the notifier delivering a notification.
+type NotificationTargetKind string
+
+const (
+	NotificationTargetEmail   NotificationTargetKind = "email"
+	NotificationTargetWebhook NotificationTargetKind = "webhook"
+	NotificationTargetSlack   NotificationTargetKind = "slack"
+	NotificationTargetTeams   NotificationTargetKind = "teams"
+)
+
+// NotificationTarget is where a notification is delivered.
+type NotificationTarget struct {
+	Kind NotificationTargetKind
+	// Address is the email address or the endpoint URL.
+	Address string
+	// Channel overrides the default channel of a Slack incoming webhook.
+	Channel string
+	// Headers are extra request headers of a webhook target.
+	Headers map[string]string
+}
+
+type NotificationFact struct {
+	Name  string
+	Value string
+}
+
+// Notification is a channel-independent message, every notifier renders the parts it supports.
+type Notification struct {
+	Subject string
+	// Text is the plain text body used by email.
+	Text string
+	// Color is the accent color of chat messages as a hex RGB value without '#'.
+	Color string
+	Facts []NotificationFact
+	// Payload is the JSON body posted to webhook targets.
+	Payload []byte
+}
+
+// NotificationEndpointError is returned when an HTTP endpoint responds with a non-2xx status.
+type NotificationEndpointError struct {
+	StatusCode int
+}
+
+func (e *NotificationEndpointError) Error() string {
+	return fmt.Sprintf("unexpected status code %d", e.StatusCode)
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"strconv"
+	"time"
+)
+
+type NotificationChannelID int
+
+func (id NotificationChannelID) String() string {
+	return strconv.Itoa(int(id))
+}
+
+func (id NotificationChannelID) Int() int {
+	return int(id)
+}
+
+type NotificationChannelKind string
+
+const (
+	NotificationChannelSlack NotificationChannelKind = "slack"
+	NotificationChannelTeams NotificationChannelKind = "teams"
+)
+
+// NotificationChannel posts workflow events to a Slack or Microsoft Teams incoming webhook.
+type NotificationChannel struct {
+	ID                          NotificationChannelID   `json:"id"`
+	ProjectID                   ProjectID               `json:"project_id"`
+	Name                        string                  `json:"name"`
+	Kind                        NotificationChannelKind `json:"kind"`
+	Channel                     string                  `json:"channel"`
+	Events                      []LifecycleEventType    `json:"events"`
+	LongRunningThresholdSeconds int                     `json:"long_running_threshold_seconds"`
+	Enabled                     bool                    `json:"enabled"`
+	CreatedBy                   string                  `json:"created_by"`
+	CreatedAt                   time.Time               `json:"created_at"`
+	UpdatedAt                   time.Time               `json:"updated_at"`
+
+	// WebhookURL is the plain incoming webhook URL. It is never returned by the API.
+	WebhookURL string `json:"-"`
+	// WebhookURLEncrypted is the stored (encrypted) form of the URL.
+	WebhookURLEncrypted string `json:"-"`
+}
+
+// NotificationChannelDTO holds channel settings. An empty WebhookURL on update keeps the current URL.
+type NotificationChannelDTO struct {
+	Name                        string
+	Kind                        NotificationChannelKind
+	WebhookURL                  string
+	Channel                     string
+	Events                      []LifecycleEventType
+	LongRunningThresholdSeconds int
+	Enabled                     bool
+}
+
+type NotificationMessageStatus string
+
+const (
+	NotificationMessagePending NotificationMessageStatus = "pending"
+	NotificationMessageSent    NotificationMessageStatus = "sent"
+	NotificationMessageFailed  NotificationMessageStatus = "failed"
+)
+
+// NotificationMessage is an event posted (or to be posted) to a notification channel.
+type NotificationMessage struct {
+	ID            int64                     `json:"id"`
+	ChannelID     NotificationChannelID     `json:"channel_id"`
+	EventType     LifecycleEventType        `json:"event_type"`
+	EventKey      string                    `json:"event_key"`
+	Event         LifecycleEvent            `json:"event"`
+	Status        NotificationMessageStatus `json:"status"`
+	Attempts      int                       `json:"attempts"`
+	NextAttemptAt *time.Time                `json:"next_attempt_at"`
+	LastError     *string                   `json:"last_error"`
+	CreatedAt     time.Time                 `json:"created_at"`
+	SentAt        *time.Time                `json:"sent_at"`
+
+	// Channel is the target channel; filled for messages picked up for sending.
+	Channel NotificationChannel `json:"-"`
+}
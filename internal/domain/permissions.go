@@ -10,9 +10,44 @@ const (
 	PermProjectCreate PermKey = "project.create"
 
 	// Workflow-level.
-	PermWorkflowCreate PermKey = "workflow.create"
+	PermWorkflowCreate   PermKey = "workflow.create"
+	PermWorkflowDataView PermKey = "workflow.data.view"
 
 	// Audit & Membership.
 	PermAuditView        PermKey = "audit.view"
 	PermMembershipManage PermKey = "membership.manage"
+
+	// Global.
+	PermUserManage PermKey = "user.manage"
 )
+
+// PermissionGrantSource tells what grants a user a permission in a project.
+type PermissionGrantSource string
+
+const (
+	PermissionGrantSuperuser         PermissionGrantSource = "superuser"
+	PermissionGrantProjectMembership PermissionGrantSource = "project_membership"
+	PermissionGrantTenantMembership  PermissionGrantSource = "tenant_membership"
+	PermissionGrantGlobalRole        PermissionGrantSource = "global_role"
+)
+
+// PermissionGrant is a permission together with the role and the membership or assignment granting it.
+// GrantID is the id of the project membership, tenant membership or global role assignment,
+// it is 0 for superusers.
+type PermissionGrant struct {
+	Permission PermKey
+	Source     PermissionGrantSource
+	RoleID     RoleID
+	RoleKey    string
+	RoleName   string
+	GrantID    int
+}
+
+// EffectivePermissions is the computed permission set of a user in a project.
+type EffectivePermissions struct {
+	UserID      UserID
+	ProjectID   ProjectID
+	IsSuperuser bool
+	Permissions []PermKey
+	Grants      []PermissionGrant
+}
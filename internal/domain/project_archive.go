@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ProjectArchiveFormatVersion is the version of the project archive layout written by exports.
+const ProjectArchiveFormatVersion = 1
+
+// ProjectArchive is the portable state of a project. Entities are referenced by natural keys
+// (workflow name and version, user name, role key) so that the archive can be imported in another
+// environment.
+type ProjectArchive struct {
+	FormatVersion        int                          `json:"format_version"`
+	ExportedAt           time.Time                    `json:"exported_at"`
+	Project              ProjectArchiveProject        `json:"project"`
+	Workflows            []ProjectArchiveWorkflow     `json:"-"`
+	Schedules            []ProjectArchiveSchedule     `json:"schedules"`
+	NotificationChannels []ProjectArchiveChannel      `json:"notification_channels"`
+	Webhooks             []ProjectArchiveWebhook      `json:"webhooks"`
+	AlertSettings        *ProjectArchiveAlertSettings `json:"alert_settings,omitempty"`
+	Memberships          []ProjectArchiveMembership   `json:"memberships"`
+}
+
+type ProjectArchiveProject struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type ProjectArchiveWorkflow struct {
+	Name       string          `json:"name"`
+	Version    int             `json:"version"`
+	Definition json.RawMessage `json:"definition"`
+}
+
+type ProjectArchiveSchedule struct {
+	WorkflowID string          `json:"workflow_id"`
+	Name       string          `json:"name"`
+	CronExpr   string          `json:"cron_expr"`
+	Timezone   string          `json:"timezone"`
+	Input      json.RawMessage `json:"input"`
+	Enabled    bool            `json:"enabled"`
+}
+
+type ProjectArchiveChannel struct {
+	Name string                  `json:"name"`
+	Kind NotificationChannelKind `json:"kind"`
+	// WebhookURL is exported on request only, a channel without it is not imported.
+	WebhookURL                  string               `json:"webhook_url,omitempty"`
+	Channel                     string               `json:"channel"`
+	Events                      []LifecycleEventType `json:"events"`
+	LongRunningThresholdSeconds int                  `json:"long_running_threshold_seconds"`
+	Enabled                     bool                 `json:"enabled"`
+}
+
+type ProjectArchiveWebhook struct {
+	Name    string               `json:"name"`
+	URL     string               `json:"url"`
+	Events  []LifecycleEventType `json:"events"`
+	Enabled bool                 `json:"enabled"`
+}
+
+type ProjectArchiveAlertSettings struct {
+	Enabled                  bool   `json:"enabled"`
+	RecipientRoleKey         string `json:"recipient_role_key"`
+	NotifyOnFailure          bool   `json:"notify_on_failure"`
+	DurationThresholdSeconds *int   `json:"duration_threshold_seconds"`
+	DigestEnabled            bool   `json:"digest_enabled"`
+}
+
+// ProjectArchiveMembership is matched to a user of the target environment by username, then by email.
+type ProjectArchiveMembership struct {
+	Username   string     `json:"username"`
+	Email      string     `json:"email"`
+	RoleKey    string     `json:"role_key"`
+	ValidUntil *time.Time `json:"valid_until,omitempty"`
+}
+
+// ProjectImportResult describes a project created from an archive
+type ProjectImportResult struct {
+	ProjectID            ProjectID              `json:"project_id"`
+	Workflows            []WorkflowImportResult `json:"workflows"`
+	Schedules            int                    `json:"schedules"`
+	NotificationChannels int                    `json:"notification_channels"`
+	Webhooks             int                    `json:"webhooks"`
+	Memberships          int                    `json:"memberships"`
+	// Warnings list the archive entries that were skipped
+	Warnings []string `json:"warnings"`
+}
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"time"
+)
+
+const (
+	DefaultProjectTimezone = "UTC"
+	DefaultProjectPageSize = 20
+	MaxProjectPageSize     = 500
+)
+
+// ProjectSettings are the settings of a project. The values are stored as one JSON document,
+// so a new setting needs no migration; a value that was never set has its default.
+type ProjectSettings struct {
+	ProjectID ProjectID `json:"project_id"`
+	ProjectSettingsValues
+	UpdatedBy string     `json:"updated_by"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+// ProjectSettingsValues is the stored document of the project settings.
+type ProjectSettingsValues struct {
+	// Timezone is the IANA name of the time zone dates of the project are shown in.
+	Timezone string `json:"timezone"`
+	// DefaultPageSize is the page size of lists when the client does not ask for one.
+	DefaultPageSize int                  `json:"default_page_size"`
+	Notifications   NotificationDefaults `json:"notifications"`
+	Retention       RetentionDefaults    `json:"retention"`
+}
+
+// NotificationDefaults fill in the notification channels created without events or a long-running threshold.
+type NotificationDefaults struct {
+	Events                      []LifecycleEventType `json:"events"`
+	LongRunningThresholdSeconds int                  `json:"long_running_threshold_seconds"`
+}
+
+// RetentionDefaults apply to the history of the project until its retention settings are saved.
+// A nil period keeps the history forever.
+type RetentionDefaults struct {
+	CompletedDays *int `json:"completed_days"`
+	FailedDays    *int `json:"failed_days"`
+	EventsDays    *int `json:"events_days"`
+}
+
+// WithDefaults returns the values with the unset ones replaced by their defaults.
+func (v ProjectSettingsValues) WithDefaults() ProjectSettingsValues {
+	if v.Timezone == "" {
+		v.Timezone = DefaultProjectTimezone
+	}
+
+	if v.DefaultPageSize == 0 {
+		v.DefaultPageSize = DefaultProjectPageSize
+	}
+
+	if v.Notifications.Events == nil {
+		v.Notifications.Events = []LifecycleEventType{}
+	}
+
+	return v
+}
+
+// Location returns the time zone of the project, UTC when the stored name is not known.
+func (v ProjectSettingsValues) Location() *time.Location {
+	if v.Timezone == "" {
+		return time.UTC
+	}
+
+	loc, err := time.LoadLocation(v.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+
+	return loc
+}
+
+// Settings returns the retention settings of the project the defaults stand for.
+func (d RetentionDefaults) Settings(projectID ProjectID) RetentionSettings {
+	return RetentionSettings{
+		ProjectID:     projectID,
+		CompletedDays: d.CompletedDays,
+		FailedDays:    d.FailedDays,
+		EventsDays:    d.EventsDays,
+	}
+}
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"time"
+)
+
+// ProjectVariable is injected into the input of the workflow instances started by the manager.
+type ProjectVariable struct {
+	ProjectID ProjectID `json:"project_id"`
+	Name      string    `json:"name"`
+	// Value is returned by the API only for variables that are not secret.
+	Value     string    `json:"value,omitempty"`
+	Secret    bool      `json:"secret"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedBy string    `json:"updated_by"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// ValueEncrypted is the stored (encrypted) form of the value.
+	ValueEncrypted string `json:"-"`
+}
+
+type ProjectVariableDTO struct {
+	Name   string
+	Value  string
+	Secret bool
+}
@@ -5,13 +5,23 @@ import "time"
 type PermissionID string
 
 type Permission struct {
-	ID   PermissionID
-	Key  PermKey
-	Name string
+	ID          PermissionID
+	Key         PermKey
+	Name        string
+	Description string
 }
 
 type MembershipID int
 
+// MembershipSource tells how a project membership was created.
+type MembershipSource string
+
+const (
+	MembershipSourceManual MembershipSource = "manual"
+	// MembershipSourceLDAP memberships are added, updated and removed by LDAP group mappings.
+	MembershipSourceLDAP MembershipSource = "ldap"
+)
+
 type ProjectMembership struct {
 	ID        MembershipID
 	UserID    UserID
@@ -19,5 +29,20 @@ type ProjectMembership struct {
 	RoleID    RoleID
 	RoleKey   string
 	RoleName  string
-	CreatedAt time.Time
+	Source    MembershipSource
+	// ValidUntil limits the membership in time. Nil means the membership does not expire.
+	ValidUntil *time.Time
+	CreatedAt  time.Time
+}
+
+// MembershipGrant is a project membership granted to a user on creation.
+type MembershipGrant struct {
+	ProjectID  ProjectID
+	RoleID     RoleID
+	ValidUntil *time.Time
+}
+
+// Expired reports whether a time-bound membership no longer grants access at now.
+func (m *ProjectMembership) Expired(now time.Time) bool {
+	return m.ValidUntil != nil && !m.ValidUntil.After(now)
 }
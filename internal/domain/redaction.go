@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"time"
+)
+
+// RedactedValue replaces the payload values matched by redaction rules.
+const RedactedValue = "[REDACTED]"
+
+// RedactionRules lists the JSONPath patterns of the payload values of a project that are masked
+// when instances, steps, events and DLQ items are returned.
+type RedactionRules struct {
+	ProjectID ProjectID  `json:"project_id"`
+	Paths     []string   `json:"paths"`
+	UpdatedBy string     `json:"updated_by,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"time"
+)
+
+// MaxRetentionDays is the longest retention period, about ten years.
+const MaxRetentionDays = 3650
+
+// RetentionSettings configures how long the workflow history of a project is kept.
+// A nil period keeps the history forever.
+type RetentionSettings struct {
+	ProjectID ProjectID `json:"project_id"`
+	// CompletedDays applies to completed instances.
+	CompletedDays *int `json:"completed_days"`
+	// FailedDays applies to failed, cancelled and aborted instances.
+	FailedDays *int `json:"failed_days"`
+	// EventsDays applies to the events of the instances that are kept.
+	EventsDays *int `json:"events_days"`
+
+	LastRunAt            *time.Time `json:"last_run_at"`
+	LastDeletedInstances int64      `json:"last_deleted_instances"`
+	LastDeletedEvents    int64      `json:"last_deleted_events"`
+	LastError            *string    `json:"last_error"`
+	UpdatedAt            time.Time  `json:"updated_at"`
+}
+
+// Configured reports whether any retention period is set.
+func (s RetentionSettings) Configured() bool {
+	return s.CompletedDays != nil || s.FailedDays != nil || s.EventsDays != nil
+}
+
+// RetentionRun is the result of applying the retention settings of a project.
+type RetentionRun struct {
+	RunAt            time.Time `json:"run_at"`
+	DeletedInstances int64     `json:"deleted_instances"`
+	DeletedEvents    int64     `json:"deleted_events"`
+	Error            *string   `json:"error,omitempty"`
+}
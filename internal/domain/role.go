@@ -1,11 +1,19 @@
 package domain
 
 import (
+	"strings"
 	"time"
 )
 
 type RoleID string
 
+const (
+	// tenantRoleKeyPrefix marks roles granted by tenant memberships, e.g. tenant_admin and tenant_viewer.
+	tenantRoleKeyPrefix = "tenant_"
+	// globalRoleKeyPrefix marks roles assigned globally, e.g. global_auditor and global_user_manager.
+	globalRoleKeyPrefix = "global_"
+)
+
 type Role struct {
 	ID          RoleID
 	Key         string
@@ -13,3 +21,25 @@ type Role struct {
 	Description string
 	CreatedAt   time.Time
 }
+
+// IsTenantRole reports whether the role is assigned by tenant memberships rather than project memberships.
+func (r Role) IsTenantRole() bool {
+	return strings.HasPrefix(r.Key, tenantRoleKeyPrefix)
+}
+
+// IsGlobalRole reports whether the role is assigned globally rather than by a membership.
+func (r Role) IsGlobalRole() bool {
+	return strings.HasPrefix(r.Key, globalRoleKeyPrefix)
+}
+
+type GlobalRoleAssignmentID int
+
+// GlobalRoleAssignment grants the permissions of a global role everywhere, including every project.
+type GlobalRoleAssignment struct {
+	ID        GlobalRoleAssignmentID
+	UserID    UserID
+	RoleID    RoleID
+	RoleKey   string
+	RoleName  string
+	CreatedAt time.Time
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+type ScheduleID int
+
+func (id ScheduleID) String() string {
+	return strconv.Itoa(int(id))
+}
+
+func (id ScheduleID) Int() int {
+	return int(id)
+}
+
+// Schedule is a cron trigger that starts a workflow with a fixed input payload.
+type Schedule struct {
+	ID             ScheduleID      `json:"id"`
+	ProjectID      ProjectID       `json:"project_id"`
+	WorkflowID     string          `json:"workflow_id"`
+	Name           string          `json:"name"`
+	CronExpr       string          `json:"cron_expr"`
+	Timezone       string          `json:"timezone"`
+	Input          json.RawMessage `json:"input"`
+	Enabled        bool            `json:"enabled"`
+	NextRunAt      *time.Time      `json:"next_run_at"`
+	LastRunAt      *time.Time      `json:"last_run_at"`
+	LastInstanceID *int64          `json:"last_instance_id"`
+	LastError      *string         `json:"last_error"`
+	CreatedBy      string          `json:"created_by"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+type ScheduleDTO struct {
+	WorkflowID string
+	Name       string
+	CronExpr   string
+	Timezone   string
+	Input      json.RawMessage
+	Enabled    bool
+}
+
+// ScheduleRun is the result of a single schedule activation.
+type ScheduleRun struct {
+	RunAt      time.Time
+	NextRunAt  *time.Time
+	InstanceID *int64
+	Error      *string
+}
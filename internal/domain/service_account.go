@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"time"
+)
+
+// ServiceAccount is a project view of a service account user.
+type ServiceAccount struct {
+	ID        UserID    `json:"id"`
+	ProjectID ProjectID `json:"project_id"`
+	Name      string    `json:"name"`
+	Username  string    `json:"username"`
+	RoleKey   string    `json:"role_key"`
+	RoleName  string    `json:"role_name"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ServiceAccountDTO struct {
+	Name    string
+	RoleKey string
+}
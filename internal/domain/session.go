@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"time"
+)
+
+type SessionID string
+
+func (id SessionID) String() string {
+	return string(id)
+}
+
+// Session is a login session. Refresh tokens carry the session ID and stop working
+// once the session is revoked or expired. Every refresh rotates the token, only the
+// last issued one (RefreshTokenID) is accepted.
+type Session struct {
+	ID         SessionID  `json:"id"`
+	UserID     UserID     `json:"user_id"`
+	UserAgent  string     `json:"user_agent"`
+	IPAddress  string     `json:"ip_address"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt time.Time  `json:"last_used_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+
+	// RefreshTokenID is the ID of the current refresh token, empty for sessions started before rotation.
+	RefreshTokenID string `json:"-"`
+}
+
+// Active reports whether the session can still be used to refresh tokens.
+func (s *Session) Active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+type SessionDTO struct {
+	UserID         UserID
+	UserAgent      string
+	IPAddress      string
+	ExpiresAt      time.Time
+	RefreshTokenID string
+}
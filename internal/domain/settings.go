@@ -15,6 +15,13 @@ type Setting struct {
 	UpdatedAt   time.Time       `db:"updated_at"  json:"updated_at"`
 }
 
+const (
+	// DefaultAuditExportRowLimit caps audit log exports until a superuser configures another limit.
+	DefaultAuditExportRowLimit = 100_000
+	// MaxAuditExportRowLimit is the highest configurable audit log export limit.
+	MaxAuditExportRowLimit = 1_000_000
+)
+
 // LDAPConfig represents LDAP configuration stored in settings.
 type LDAPConfig struct {
 	Enabled       bool   `json:"enabled"`
@@ -29,4 +36,115 @@ type LDAPConfig struct {
 	InsecureTLS   bool   `json:"insecure_tls"`
 	Timeout       string `json:"timeout"`
 	SyncInterval  uint   `json:"sync_interval"`
+	// GroupAttr is the user attribute that lists group DNs (memberOf by default).
+	GroupAttr     string             `json:"group_attr"`
+	GroupMappings []LDAPGroupMapping `json:"group_mappings"`
+	// GroupMappingsDryRun only logs membership changes planned by group mappings.
+	GroupMappingsDryRun bool `json:"group_mappings_dry_run"`
+	// IncrementalSync processes only users changed since the last successful sync.
+	IncrementalSync bool `json:"incremental_sync"`
+	// ChangeAttr is the attribute used to detect changes (modifyTimestamp by default, uSNChanged for AD).
+	ChangeAttr string `json:"change_attr"`
+	// FullSyncInterval in seconds forces a full sync when the last one is older (24h by default).
+	FullSyncInterval uint `json:"full_sync_interval"`
+	// LocalPasswordFallback caches a password hash on successful LDAP login and accepts it
+	// while the directory server is unavailable.
+	LocalPasswordFallback bool `json:"local_password_fallback"`
+}
+
+// LDAPSyncCursor is the position of incremental LDAP sync.
+type LDAPSyncCursor struct {
+	ChangeAttr     string    `json:"change_attr"`
+	Value          string    `json:"value"`
+	LastFullSyncAt time.Time `json:"last_full_sync_at"`
+}
+
+// LDAPGroupMapping grants a project role to members of an LDAP group.
+type LDAPGroupMapping struct {
+	Group     string    `json:"group"`
+	ProjectID ProjectID `json:"project_id"`
+	RoleKey   string    `json:"role_key"`
+}
+
+const (
+	SMTPAuthPlain   = "plain"
+	SMTPAuthXOAuth2 = "xoauth2"
+)
+
+const (
+	// SMTPStartTLSRequire fails the send when the mail server does not offer STARTTLS.
+	SMTPStartTLSRequire = "require"
+	// SMTPStartTLSOpportunistic upgrades the connection when the mail server offers STARTTLS.
+	SMTPStartTLSOpportunistic = "opportunistic"
+	SMTPStartTLSDisabled      = "disabled"
+)
+
+// SMTPConfig is the mail server configuration, it replaces the MAILER_* environment variables once saved.
+type SMTPConfig struct {
+	Addr          string `json:"addr"`
+	User          string `json:"user"`
+	Password      string `json:"password"`
+	From          string `json:"from"`
+	AllowInsecure bool   `json:"allow_insecure"`
+	UseTLS        bool   `json:"use_tls"`
+	// StartTLS is require, opportunistic or disabled. Empty uses the default of the port:
+	// implicit TLS on 465, required STARTTLS on 587 and opportunistic STARTTLS elsewhere.
+	StartTLS string `json:"starttls"`
+	// AuthMethod is plain (the default) or xoauth2. With xoauth2 the password is not used,
+	// an access token for User is obtained from the OAuth2 token endpoint instead.
+	AuthMethod         string `json:"auth_method"`
+	OAuth2TokenURL     string `json:"oauth2_token_url"`
+	OAuth2ClientID     string `json:"oauth2_client_id"`
+	OAuth2ClientSecret string `json:"oauth2_client_secret"`
+	// OAuth2RefreshToken selects the refresh token grant (e.g. Gmail),
+	// the client credentials grant is used without it (e.g. Microsoft 365).
+	OAuth2RefreshToken string   `json:"oauth2_refresh_token"`
+	OAuth2Scopes       []string `json:"oauth2_scopes"`
+}
+
+// XOAuth2 reports whether the mail server is authenticated with OAuth2 access tokens.
+func (c SMTPConfig) XOAuth2() bool {
+	return c.AuthMethod == SMTPAuthXOAuth2
+}
+
+// SAMLSettings are the runtime-tunable settings of the default SAML provider, they replace
+// the corresponding SAML_* environment variables once saved. Certificates stay in the environment.
+type SAMLSettings struct {
+	Enabled          bool               `json:"enabled"`
+	EntityID         string             `json:"entity_id"`
+	IDPMetadataURL   string             `json:"idp_metadata_url"`
+	SSOURL           string             `json:"sso_url"`
+	AttributeMapping map[string]string  `json:"attribute_mapping"`
+	SkipTLSVerify    bool               `json:"skip_tls_verify"`
+	GroupAttribute   string             `json:"group_attribute"`
+	GroupMappings    []SAMLGroupMapping `json:"group_mappings"`
+}
+
+// Apply returns the SAML provider configuration with the settings in place of its tunable fields.
+func (s SAMLSettings) Apply(config SAMLConfig) SAMLConfig {
+	config.Enabled = s.Enabled
+	config.EntityID = s.EntityID
+	config.IDPMetadataURL = s.IDPMetadataURL
+	config.SSOURL = s.SSOURL
+	config.AttributeMapping = s.AttributeMapping
+	config.SkipTLSVerify = s.SkipTLSVerify
+	config.GroupAttribute = s.GroupAttribute
+	config.GroupMappings = s.GroupMappings
+
+	return config
+}
+
+// GeneralSettings are the runtime-tunable settings of the whole installation.
+type GeneralSettings struct {
+	// FrontendURL is the public URL of the UI used in emails and SSO redirects.
+	FrontendURL string `json:"frontend_url"`
+}
+
+// LicenseAgreement is the license users accept before using the installation. Acceptance is
+// recorded per version, publishing a new version asks every user to accept it again.
+type LicenseAgreement struct {
+	Version string `json:"version"`
+	Text    string `json:"text"`
+	// Required blocks the API for users who have not accepted the current version.
+	Required bool `json:"required"`
 }
@@ -1,5 +1,9 @@
 package domain
 
+import (
+	"strings"
+)
+
 // SSOProviderType represents the type of SSO provider.
 type SSOProviderType string
 
@@ -29,8 +33,43 @@ type SAMLConfig struct {
 	CallbackURL      string            `json:"callback_url"`
 	PublicRootURL    string            `json:"public_root_url"`
 	SkipTLSVerify    bool              `yaml:"skip_tls_verify"`
+	// GroupAttribute is the assertion attribute that lists the groups of the user.
+	GroupAttribute string             `json:"group_attribute"`
+	GroupMappings  []SAMLGroupMapping `json:"group_mappings"`
+}
+
+// SAMLGroupMapping grants a project role to users that are members of an IdP group.
+type SAMLGroupMapping struct {
+	Group     string    `json:"group"`
+	ProjectID ProjectID `json:"project_id"`
+	RoleKey   string    `json:"role"`
 }
 
 const (
 	SSOProviderNameADSaml = "ad_saml"
 )
+
+// SSOProviderSettings is a named SAML provider managed at runtime, next to the ones of the environment.
+type SSOProviderSettings struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	IconURL     string `json:"icon_url"`
+	SAMLSettings
+	// CertificatePath and PrivateKeyPath locate the key pair of the service provider,
+	// a self-signed pair is generated there when the files are missing.
+	CertificatePath string `json:"certificate_path"`
+	PrivateKeyPath  string `json:"private_key_path"`
+}
+
+// SAMLConfig returns the configuration of the provider served at publicRootURL.
+func (p SSOProviderSettings) SAMLConfig(publicRootURL string) SAMLConfig {
+	config := p.SAMLSettings.Apply(SAMLConfig{
+		CreateCerts:     true,
+		CertificatePath: p.CertificatePath,
+		PrivateKeyPath:  p.PrivateKeyPath,
+	})
+	config.PublicRootURL = publicRootURL
+	config.CallbackURL = strings.TrimRight(publicRootURL, "/") + "/api/v1/auth/sso/callback"
+
+	return config
+}
@@ -15,3 +15,16 @@ type Tenant struct {
 func (id TenantID) Int() int {
 	return int(id)
 }
+
+type TenantMembershipID int
+
+// TenantMembership grants the role in every project of the tenant.
+type TenantMembership struct {
+	ID        TenantMembershipID
+	TenantID  TenantID
+	UserID    UserID
+	RoleID    RoleID
+	RoleKey   string
+	RoleName  string
+	CreatedAt time.Time
+}
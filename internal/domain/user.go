@@ -10,6 +10,7 @@ type UserID uint
 type User struct {
 	ID               UserID
 	Username         string
+	DisplayName      string
 	Email            string
 	PasswordHash     string
 	IsSuperuser      bool
@@ -23,6 +24,13 @@ type User struct {
 	UpdatedAt        time.Time
 	LastLogin        *time.Time
 	LicenseAccepted  bool
+	// LicenseVersion is the version of the license agreement accepted at LicenseAcceptedAt.
+	LicenseVersion    string
+	LicenseAcceptedAt *time.Time
+	EmailVerifiedAt   *time.Time
+	// IsServiceAccount marks machine users bound to ServiceProjectID that authenticate with API tokens only.
+	IsServiceAccount bool
+	ServiceProjectID *ProjectID
 }
 
 type UserDTO struct {
@@ -32,8 +40,69 @@ type UserDTO struct {
 	IsSuperuser   bool
 	IsTmpPassword bool
 	IsExternal    bool
+	// EmailVerified is set for users whose email comes from a trusted source (directory, operator).
+	EmailVerified bool
+
+	IsServiceAccount bool
+	ServiceProjectID *ProjectID
+}
+
+// UserUpdate holds the user fields to change; nil fields are left as they are.
+type UserUpdate struct {
+	Username    *string
+	Email       *string
+	DisplayName *string
+}
+
+// BulkUserAction is an operation applied to a batch of users.
+type BulkUserAction string
+
+const (
+	BulkUserActionActivate   BulkUserAction = "activate"
+	BulkUserActionDeactivate BulkUserAction = "deactivate"
+	BulkUserActionDelete     BulkUserAction = "delete"
+)
+
+func (a BulkUserAction) Valid() bool {
+	switch a {
+	case BulkUserActionActivate, BulkUserActionDeactivate, BulkUserActionDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// BulkUserResult is the outcome of a bulk operation for a single user; Err is nil on success.
+type BulkUserResult struct {
+	UserID UserID
+	Err    error
+}
+
+// UserMergeResult counts what was moved from the source account of a merge to the target one.
+// Memberships the target already has in the same project or tenant are kept as they are.
+type UserMergeResult struct {
+	SourceID          UserID
+	TargetID          UserID
+	Memberships       int
+	TenantMemberships int
+	GlobalRoles       int
+	Preferences       bool
 }
 
 func (id UserID) Int() int {
 	return int(id)
 }
+
+// Name returns the display name of the user, or the username when it is not set.
+func (u *User) Name() string {
+	if u.DisplayName != "" {
+		return u.DisplayName
+	}
+
+	return u.Username
+}
+
+// EmailVerified reports whether the user confirmed the email address.
+func (u *User) EmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
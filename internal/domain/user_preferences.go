@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"time"
+)
+
+const (
+	DefaultUserLocale       = "en"
+	DefaultUserItemsPerPage = 20
+	MaxUserItemsPerPage     = 500
+)
+
+// UserPreferences are the UI preferences of a user, kept by the server so they follow the user between browsers.
+type UserPreferences struct {
+	UserID UserID `json:"user_id"`
+	// DefaultProjectID is the project the UI opens after login, nil to let the user pick one.
+	DefaultProjectID *ProjectID `json:"default_project_id"`
+	Locale           string     `json:"locale"`
+	ItemsPerPage     int        `json:"items_per_page"`
+	// NotificationOptOuts are the events the user receives no email alerts about.
+	NotificationOptOuts []LifecycleEventType `json:"notification_opt_outs"`
+	UpdatedAt           *time.Time           `json:"updated_at"`
+}
+
+// DefaultUserPreferences returns the preferences of a user that never saved any.
+func DefaultUserPreferences(userID UserID) UserPreferences {
+	return UserPreferences{
+		UserID:              userID,
+		Locale:              DefaultUserLocale,
+		ItemsPerPage:        DefaultUserItemsPerPage,
+		NotificationOptOuts: []LifecycleEventType{},
+	}
+}
+
+// OptedOut reports whether the user receives no alerts about the event.
+func (p UserPreferences) OptedOut(eventType LifecycleEventType) bool {
+	for _, optOut := range p.NotificationOptOuts {
+		if optOut == eventType {
+			return true
+		}
+	}
+
+	return false
+}
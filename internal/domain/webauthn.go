@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"strconv"
+	"time"
+)
+
+// TwoFAMethod is a second factor a user can pass the 2FA step of the login with.
+type TwoFAMethod string
+
+const (
+	TwoFAMethodTOTP     TwoFAMethod = "totp"
+	TwoFAMethodWebAuthn TwoFAMethod = "webauthn"
+)
+
+type WebAuthnCredentialID int
+
+func (id WebAuthnCredentialID) Int() int {
+	return int(id)
+}
+
+func (id WebAuthnCredentialID) String() string {
+	return strconv.Itoa(int(id))
+}
+
+// WebAuthnCredential is a security key registered as a second factor. Credential holds the
+// serialized authenticator data (public key, sign counter, flags) used to verify assertions.
+type WebAuthnCredential struct {
+	ID           WebAuthnCredentialID `json:"id"`
+	UserID       UserID               `json:"user_id"`
+	Name         string               `json:"name"`
+	CredentialID []byte               `json:"-"`
+	Credential   []byte               `json:"-"`
+	LastUsedAt   *time.Time           `json:"last_used_at,omitempty"`
+	CreatedAt    time.Time            `json:"created_at"`
+}
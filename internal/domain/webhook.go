@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+type WebhookID int
+
+func (id WebhookID) String() string {
+	return strconv.Itoa(int(id))
+}
+
+func (id WebhookID) Int() int {
+	return int(id)
+}
+
+// Webhook is an outbound notification endpoint that receives workflow events as JSON.
+type Webhook struct {
+	ID        WebhookID            `json:"id"`
+	ProjectID ProjectID            `json:"project_id"`
+	Name      string               `json:"name"`
+	URL       string               `json:"url"`
+	Events    []LifecycleEventType `json:"events"`
+	Enabled   bool                 `json:"enabled"`
+	CreatedBy string               `json:"created_by"`
+	CreatedAt time.Time            `json:"created_at"`
+	UpdatedAt time.Time            `json:"updated_at"`
+}
+
+type WebhookDTO struct {
+	Name    string
+	URL     string
+	Events  []LifecycleEventType
+	Enabled bool
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery is a single event sent (or to be sent) to a webhook.
+type WebhookDelivery struct {
+	ID             int64                 `json:"id"`
+	WebhookID      WebhookID             `json:"webhook_id"`
+	EventType      LifecycleEventType    `json:"event_type"`
+	EventKey       string                `json:"event_key"`
+	Payload        json.RawMessage       `json:"payload"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       int                   `json:"attempts"`
+	NextAttemptAt  *time.Time            `json:"next_attempt_at"`
+	LastStatusCode *int                  `json:"last_status_code"`
+	LastError      *string               `json:"last_error"`
+	CreatedAt      time.Time             `json:"created_at"`
+	DeliveredAt    *time.Time            `json:"delivered_at"`
+
+	// URL is the target of the webhook; filled for deliveries picked up for sending.
+	URL string `json:"-"`
+}
@@ -85,6 +85,14 @@ type WorkflowInstance struct {
 	CompletedAt sql.NullTime    `json:"completed_at,omitempty"`
 	CreatedAt   time.Time       `json:"created_at"`
 	UpdatedAt   time.Time       `json:"updated_at"`
+	// ParentInstanceID is the instance this one re-runs, nil for instances that are not re-runs.
+	ParentInstanceID *int `json:"parent_instance_id"`
+}
+
+// OmitPayloads clears the input and output, for users not allowed to see workflow data.
+func (i *WorkflowInstance) OmitPayloads() {
+	i.Input = nil
+	i.Output = nil
 }
 
 // WorkflowStep represents a workflow step
@@ -108,6 +116,12 @@ type WorkflowStep struct {
 	CreatedAt              time.Time       `json:"created_at"`
 }
 
+// OmitPayloads clears the input and output, for users not allowed to see workflow data.
+func (s *WorkflowStep) OmitPayloads() {
+	s.Input = nil
+	s.Output = nil
+}
+
 // WorkflowEvent represents a workflow event
 type WorkflowEvent struct {
 	TenantID   TenantID        `json:"tenant_id"`
@@ -120,6 +134,11 @@ type WorkflowEvent struct {
 	CreatedAt  time.Time       `json:"created_at"`
 }
 
+// OmitPayloads clears the payload, for users not allowed to see workflow data.
+func (e *WorkflowEvent) OmitPayloads() {
+	e.Payload = nil
+}
+
 // ActiveWorkflow represents an active workflow
 type ActiveWorkflow struct {
 	TenantID        TenantID  `json:"tenant_id"`
@@ -149,6 +168,17 @@ type WorkflowStat struct {
 	AverageDuration    int64     `json:"average_duration"` // nanoseconds
 }
 
+// WorkflowFailureGroup aggregates failed steps of a workflow with the same step name and
+// error signature (the error message with numbers and UUIDs masked).
+type WorkflowFailureGroup struct {
+	StepName       string    `json:"step_name"`
+	ErrorSignature string    `json:"error_signature"`
+	SampleError    string    `json:"sample_error"`
+	Failures       int       `json:"failures"`
+	Instances      int       `json:"instances"`
+	LastOccurredAt time.Time `json:"last_occurred_at"`
+}
+
 // DLQItem represents a dead letter queue item
 type DLQItem struct {
 	TenantID   TenantID        `json:"tenant_id"`
@@ -164,3 +194,131 @@ type DLQItem struct {
 	Reason     string          `json:"reason"`
 	CreatedAt  time.Time       `json:"created_at"`
 }
+
+// OmitPayloads clears the input, for users not allowed to see workflow data.
+func (d *DLQItem) OmitPayloads() {
+	d.Input = nil
+}
+
+// WorkflowDiff represents the structural difference between two versions of a workflow definition
+type WorkflowDiff struct {
+	Name         string                `json:"name"`
+	FromVersion  int                   `json:"from_version"`
+	ToVersion    int                   `json:"to_version"`
+	Changes      []WorkflowFieldChange `json:"changes"`
+	StepsAdded   []string              `json:"steps_added"`
+	StepsRemoved []string              `json:"steps_removed"`
+	StepsChanged []WorkflowStepDiff    `json:"steps_changed"`
+}
+
+// WorkflowStepDiff represents changes of a single step present in both versions
+type WorkflowStepDiff struct {
+	Step    string                `json:"step"`
+	Changes []WorkflowFieldChange `json:"changes"`
+}
+
+// WorkflowFieldChange represents a changed field value
+type WorkflowFieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+// InstanceGraph is the definition DAG of a workflow instance annotated with the execution state of its steps
+type InstanceGraph struct {
+	InstanceID  int                 `json:"instance_id"`
+	WorkflowID  string              `json:"workflow_id"`
+	Status      string              `json:"status"`
+	Start       string              `json:"start"`
+	StartedAt   *time.Time          `json:"started_at"`
+	CompletedAt *time.Time          `json:"completed_at"`
+	Nodes       []InstanceGraphNode `json:"nodes"`
+	Edges       []InstanceGraphEdge `json:"edges"`
+}
+
+// Node states of an instance graph
+const (
+	CompensationNone        = "none"
+	CompensationRunning     = "compensating"
+	CompensationRolledBack  = "rolled_back"
+	InstanceGraphNotStarted = "not_started"
+)
+
+// InstanceGraphNode is a step of the definition with its latest execution.
+// Status is InstanceGraphNotStarted for steps that have not run.
+type InstanceGraphNode struct {
+	Name                   string     `json:"name"`
+	Type                   string     `json:"type"`
+	Handler                string     `json:"handler,omitempty"`
+	Status                 string     `json:"status"`
+	Executions             int        `json:"executions"`
+	RetryCount             int        `json:"retry_count"`
+	MaxRetries             int        `json:"max_retries"`
+	CompensationRetryCount int        `json:"compensation_retry_count"`
+	Compensation           string     `json:"compensation"`
+	Error                  string     `json:"error,omitempty"`
+	StartedAt              *time.Time `json:"started_at"`
+	CompletedAt            *time.Time `json:"completed_at"`
+	DurationMs             *int64     `json:"duration_ms"`
+	// InDefinition is false for executed steps missing from the definition
+	InDefinition bool `json:"in_definition"`
+}
+
+// InstanceGraphEdge is a transition of the definition. Kind is next, else, parallel or on_failure.
+type InstanceGraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Kind string `json:"kind"`
+}
+
+// StepLogInfo describes the log attached to a workflow step by workers
+type StepLogInfo struct {
+	StepID     int
+	StepStatus string
+	// Size is the size of the whole log in bytes
+	Size int64
+}
+
+// InstanceSearchQuery selects workflow instances by their payload
+type InstanceSearchQuery struct {
+	// Text is matched as a case-insensitive substring of the input, output and error
+	Text string
+	// Contains is a JSON document the input or output must contain, it takes precedence over Text
+	Contains json.RawMessage
+	// Excluded are the redacted locations of the input and output, as keys and array indexes, left out
+	// of the match. The error is not searched when any is set, it may quote the redacted values.
+	Excluded [][]string
+}
+
+// InstanceSearchMatch locates a match of a search query in an instance
+type InstanceSearchMatch struct {
+	// Field is "input", "output" or "error"
+	Field string `json:"field"`
+	// Path is the JSONPath of the matching value within the field, "$" for the error
+	Path    string `json:"path"`
+	Snippet string `json:"snippet"`
+	// MatchStart and MatchEnd delimit the matched text in the snippet, in characters
+	MatchStart int `json:"match_start"`
+	MatchEnd   int `json:"match_end"`
+}
+
+// InstanceSearchResult is a workflow instance found by a search query
+type InstanceSearchResult struct {
+	Instance WorkflowInstance      `json:"instance"`
+	Matches  []InstanceSearchMatch `json:"matches"`
+}
+
+// WorkflowDefinitionSearchQuery selects workflow definitions having a step matching all the set criteria
+type WorkflowDefinitionSearchQuery struct {
+	StepType string
+	Handler  string
+	// Text is matched as a case-insensitive substring of the step name, keys and values
+	Text string
+}
+
+// WorkflowDefinitionSearchResult is a workflow definition found by a search query
+type WorkflowDefinitionSearchResult struct {
+	Workflow WorkflowDefinition `json:"workflow"`
+	// Steps are the names of the matching steps
+	Steps []string `json:"steps"`
+}
@@ -0,0 +1,33 @@
+package domain
+
+import "encoding/json"
+
+type WorkflowImportStatus string
+
+const (
+	WorkflowImportCreated WorkflowImportStatus = "created"
+	// WorkflowImportUnchanged means the project already has the same definition under the same version.
+	WorkflowImportUnchanged WorkflowImportStatus = "unchanged"
+	// WorkflowImportConflict means the version exists with another definition or in another project.
+	WorkflowImportConflict WorkflowImportStatus = "conflict"
+	WorkflowImportInvalid  WorkflowImportStatus = "invalid"
+)
+
+// WorkflowImportItem is a workflow definition of an import payload
+type WorkflowImportItem struct {
+	// Source locates the item in the payload: a document index or an archive file name
+	Source     string
+	Name       string
+	Version    int
+	Definition json.RawMessage
+}
+
+// WorkflowImportResult is the outcome of importing an item
+type WorkflowImportResult struct {
+	Source  string               `json:"source"`
+	Name    string               `json:"name"`
+	Version int                  `json:"version"`
+	ID      string               `json:"id,omitempty"`
+	Status  WorkflowImportStatus `json:"status"`
+	Error   string               `json:"error,omitempty"`
+}
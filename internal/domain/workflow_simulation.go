@@ -0,0 +1,54 @@
+package domain
+
+import "encoding/json"
+
+// Outcomes of a workflow simulation
+const (
+	SimulationCompleted = "completed"
+	// SimulationAborted means a human decision rejected the instance.
+	SimulationAborted = "aborted"
+	// SimulationFailed means a step would fail, e.g. a condition cannot be evaluated.
+	SimulationFailed = "failed"
+	// SimulationStepLimit means the walk was stopped, the definition most likely loops.
+	SimulationStepLimit = "step_limit"
+)
+
+// WorkflowSimulationInput drives a simulation. Task handlers are not run: a task passes its input
+// through unless StepOutputs holds its output. Human steps are confirmed unless Decisions says otherwise.
+type WorkflowSimulationInput struct {
+	Input       json.RawMessage            `json:"input"`
+	StepOutputs map[string]json.RawMessage `json:"step_outputs"`
+	Decisions   map[string]string          `json:"decisions"`
+}
+
+// WorkflowSimulation is the predicted execution of a workflow definition
+type WorkflowSimulation struct {
+	Outcome        string                       `json:"outcome"`
+	Path           []WorkflowSimulationStep     `json:"path"`
+	DecisionPoints []WorkflowSimulationDecision `json:"decision_points"`
+	Warnings       []string                     `json:"warnings"`
+}
+
+// WorkflowSimulationStep is a step on the predicted path, in execution order
+type WorkflowSimulationStep struct {
+	Name    string          `json:"name"`
+	Type    string          `json:"type"`
+	Handler string          `json:"handler,omitempty"`
+	Input   json.RawMessage `json:"input"`
+	Output  json.RawMessage `json:"output,omitempty"`
+	// OutputMocked tells the task output was provided rather than assumed to be its input.
+	OutputMocked bool `json:"output_mocked,omitempty"`
+	// ConditionResult is set for condition steps.
+	ConditionResult *bool `json:"condition_result,omitempty"`
+	// Next lists the steps started after this one.
+	Next  []string `json:"next"`
+	Error string   `json:"error,omitempty"`
+}
+
+// WorkflowSimulationDecision is a human step reached by the simulation
+type WorkflowSimulationDecision struct {
+	Step     string `json:"step"`
+	Decision string `json:"decision"`
+	// Assumed is true when the decision was not provided and the step is assumed confirmed.
+	Assumed bool `json:"assumed"`
+}
@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// WorkflowActiveVersion pins a workflow of a project to a version: scheduled and hook-triggered
+// instances of any version of the workflow start on the active version instead.
+type WorkflowActiveVersion struct {
+	ProjectID    ProjectID `json:"project_id"`
+	WorkflowName string    `json:"workflow_name"`
+	Version      int       `json:"version"`
+	// WorkflowID is the ID of the active definition.
+	WorkflowID string    `json:"workflow_id"`
+	UpdatedBy  string    `json:"updated_by"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// WorkflowRollout is a change of the active version of a workflow
+type WorkflowRollout struct {
+	ID           int       `json:"id"`
+	ProjectID    ProjectID `json:"project_id"`
+	WorkflowName string    `json:"workflow_name"`
+	// FromVersion is nil when the workflow was not pinned.
+	FromVersion *int `json:"from_version"`
+	ToVersion   int  `json:"to_version"`
+	// Action is ActionPromote or ActionRollback.
+	Action    string    `json:"action"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
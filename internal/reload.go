@@ -0,0 +1,98 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rom8726/floxy-manager/internal/api/rest/middlewares"
+	"github.com/rom8726/floxy-manager/internal/config"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/repository/auditlog"
+	"github.com/rom8726/floxy-manager/internal/services/notifier"
+	settingsusecase "github.com/rom8726/floxy-manager/internal/usecases/settings"
+	"github.com/rom8726/floxy-manager/pkg/safejson"
+)
+
+// reloadConfig loads the config again and applies the log settings, the mail server, the request
+// limits and the notifier interval. The reloads are serialized by the config reloader.
+func (app *App) reloadConfig(context.Context) ([]domain.ConfigChange, error) {
+	next, changes, err := app.reloadedConfig.Reload()
+	if err != nil {
+		return nil, fmt.Errorf("reload config: %w", err)
+	}
+
+	var settings *settingsusecase.Service
+	if err := app.container.Resolve(&settings); err != nil {
+		return nil, fmt.Errorf("resolve settings use case: %w", err)
+	}
+
+	var notifierRunner *notifier.Runner
+	if err := app.container.Resolve(&notifierRunner); err != nil {
+		return nil, fmt.Errorf("resolve notifier runner: %w", err)
+	}
+
+	// The log settings switched by the admin API are kept unless the config changes them
+	if next.Logger != app.reloadedConfig.Logger {
+		err := app.logControl.Update(domain.LogSettings{Level: next.Logger.Lvl, Format: next.Logger.Format})
+		if err != nil {
+			return nil, fmt.Errorf("update log settings: %w", err)
+		}
+	}
+
+	app.requestLimits.Set(requestLimits(&next.RequestLimits))
+	settings.SetSMTPDefaults(smtpDefaults(&next.Mailer))
+	notifierRunner.SetInterval(next.Notifier.Interval)
+
+	app.reloadedConfig = next
+
+	result := make([]domain.ConfigChange, 0, len(changes))
+	for _, change := range changes {
+		configChange := domain.ConfigChange{
+			Key:      change.Key,
+			Old:      change.Old,
+			New:      change.New,
+			Reloaded: change.Reloadable,
+		}
+		if change.Secret {
+			configChange.Old = auditlog.RedactedValue
+			configChange.New = auditlog.RedactedValue
+		}
+
+		result = append(result, configChange)
+	}
+
+	return result, nil
+}
+
+// smtpDefaults is the mail server configuration of the environment, used until one is saved.
+func smtpDefaults(mailer *config.Mailer) domain.SMTPConfig {
+	return domain.SMTPConfig{
+		Addr:               mailer.Addr,
+		User:               mailer.User,
+		Password:           mailer.Password,
+		From:               mailer.From,
+		AllowInsecure:      mailer.AllowInsecure,
+		UseTLS:             mailer.UseTLS,
+		StartTLS:           mailer.StartTLS,
+		AuthMethod:         mailer.AuthMethod,
+		OAuth2TokenURL:     mailer.OAuth2.TokenURL,
+		OAuth2ClientID:     mailer.OAuth2.ClientID,
+		OAuth2ClientSecret: mailer.OAuth2.ClientSecret,
+		OAuth2RefreshToken: mailer.OAuth2.RefreshToken,
+		OAuth2Scopes:       mailer.OAuth2.Scopes,
+	}
+}
+
+func requestLimits(limits *config.RequestLimits) (middlewares.BodyLimits, safejson.Options) {
+	bodyLimits := middlewares.BodyLimits{
+		Default: limits.Default,
+		Auth:    limits.Auth,
+		Upload:  limits.Upload,
+	}
+	jsonOpts := safejson.Options{
+		MaxDepth:              limits.JSONMaxDepth,
+		DisallowUnknownFields: limits.DisallowUnknownFields,
+	}
+
+	return bodyLimits, jsonOpts
+}
@@ -0,0 +1,38 @@
+package alerts
+
+import (
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type settingsModel struct {
+	ProjectID                int        `db:"project_id"`
+	Enabled                  bool       `db:"enabled"`
+	RecipientRoleKey         string     `db:"recipient_role_key"`
+	NotifyOnFailure          bool       `db:"notify_on_failure"`
+	DurationThresholdSeconds *int       `db:"duration_threshold_seconds"`
+	DigestEnabled            bool       `db:"digest_enabled"`
+	LastDigestAt             *time.Time `db:"last_digest_at"`
+	UpdatedAt                time.Time  `db:"updated_at"`
+}
+
+func (m *settingsModel) toDomain() domain.AlertSettings {
+	return domain.AlertSettings{
+		ProjectID:                domain.ProjectID(m.ProjectID),
+		Enabled:                  m.Enabled,
+		RecipientRoleKey:         m.RecipientRoleKey,
+		NotifyOnFailure:          m.NotifyOnFailure,
+		DurationThresholdSeconds: m.DurationThresholdSeconds,
+		DigestEnabled:            m.DigestEnabled,
+		LastDigestAt:             m.LastDigestAt,
+		UpdatedAt:                m.UpdatedAt,
+	}
+}
+
+type alertModel struct {
+	ID        int64     `db:"id"`
+	ProjectID int       `db:"project_id"`
+	Event     []byte    `db:"event"`
+	CreatedAt time.Time `db:"created_at"`
+}
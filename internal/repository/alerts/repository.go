@@ -0,0 +1,229 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/repository/auditlog"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.AlertsRepository = (*Repository)(nil)
+
+const settingsColumns = `project_id, enabled, recipient_role_key, notify_on_failure, duration_threshold_seconds,
+digest_enabled, last_digest_at, updated_at`
+
+type Repository struct {
+	db db.Tx
+}
+
+func New(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		db: pool,
+	}
+}
+
+func (r *Repository) GetSettings(ctx context.Context, projectID domain.ProjectID) (domain.AlertSettings, error) {
+	executor := r.getExecutor(ctx)
+
+	query := `
+SELECT ` + settingsColumns + `
+FROM workflows_manager.project_alert_settings
+WHERE project_id = $1`
+
+	rows, err := executor.Query(ctx, query, projectID.Int())
+	if err != nil {
+		return domain.AlertSettings{}, fmt.Errorf("query alert settings: %w", err)
+	}
+	defer rows.Close()
+
+	model, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[settingsModel])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.AlertSettings{}, domain.ErrEntityNotFound
+		}
+
+		return domain.AlertSettings{}, fmt.Errorf("collect alert settings: %w", err)
+	}
+
+	return model.toDomain(), nil
+}
+
+func (r *Repository) SaveSettings(ctx context.Context, settings domain.AlertSettings) error {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+INSERT INTO workflows_manager.project_alert_settings
+    (project_id, enabled, recipient_role_key, notify_on_failure, duration_threshold_seconds, digest_enabled)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (project_id) DO UPDATE
+SET enabled = EXCLUDED.enabled,
+    recipient_role_key = EXCLUDED.recipient_role_key,
+    notify_on_failure = EXCLUDED.notify_on_failure,
+    duration_threshold_seconds = EXCLUDED.duration_threshold_seconds,
+    digest_enabled = EXCLUDED.digest_enabled,
+    updated_at = NOW()`
+
+	_, err := executor.Exec(ctx, query,
+		settings.ProjectID.Int(),
+		settings.Enabled,
+		settings.RecipientRoleKey,
+		settings.NotifyOnFailure,
+		settings.DurationThresholdSeconds,
+		settings.DigestEnabled,
+	)
+	if err != nil {
+		return fmt.Errorf("save alert settings: %w", err)
+	}
+
+	err = auditlog.WriteLog(ctx, executor, domain.EntityAlertSettings, settings.ProjectID.String(),
+		domain.ActionUpdate, settings.ProjectID)
+	if err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) ListEnabledSettings(ctx context.Context) ([]domain.AlertSettings, error) {
+	executor := r.getExecutor(ctx)
+
+	query := `
+SELECT ` + settingsColumns + `
+FROM workflows_manager.project_alert_settings
+WHERE enabled
+ORDER BY project_id`
+
+	rows, err := executor.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("query alert settings: %w", err)
+	}
+	defer rows.Close()
+
+	listModels, err := pgx.CollectRows(rows, pgx.RowToStructByName[settingsModel])
+	if err != nil {
+		return nil, fmt.Errorf("collect alert settings: %w", err)
+	}
+
+	settings := make([]domain.AlertSettings, 0, len(listModels))
+	for i := range listModels {
+		settings = append(settings, listModels[i].toDomain())
+	}
+
+	return settings, nil
+}
+
+func (r *Repository) SaveDigestTime(ctx context.Context, projectID domain.ProjectID, at time.Time) error {
+	executor := r.getExecutor(ctx)
+
+	_, err := executor.Exec(ctx,
+		`UPDATE workflows_manager.project_alert_settings SET last_digest_at = $2 WHERE project_id = $1`,
+		projectID.Int(), at,
+	)
+	if err != nil {
+		return fmt.Errorf("save alert digest time: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) EnqueueAlert(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	event domain.LifecycleEvent,
+) (bool, error) {
+	executor := r.getExecutor(ctx)
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return false, fmt.Errorf("marshal event: %w", err)
+	}
+
+	const query = `
+INSERT INTO workflows_manager.project_alerts (project_id, event_key, event)
+VALUES ($1, $2, $3)
+ON CONFLICT (project_id, event_key) DO NOTHING`
+
+	result, err := executor.Exec(ctx, query, projectID.Int(), event.Key(), payload)
+	if err != nil {
+		return false, fmt.Errorf("insert alert: %w", err)
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+func (r *Repository) ListPendingAlerts(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	limit int,
+) ([]domain.Alert, error) {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+SELECT id, project_id, event, created_at
+FROM workflows_manager.project_alerts
+WHERE project_id = $1 AND sent_at IS NULL
+ORDER BY created_at, id
+LIMIT $2`
+
+	rows, err := executor.Query(ctx, query, projectID.Int(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("query pending alerts: %w", err)
+	}
+	defer rows.Close()
+
+	listModels, err := pgx.CollectRows(rows, pgx.RowToStructByName[alertModel])
+	if err != nil {
+		return nil, fmt.Errorf("collect pending alerts: %w", err)
+	}
+
+	alerts := make([]domain.Alert, 0, len(listModels))
+	for i := range listModels {
+		model := listModels[i]
+
+		var event domain.LifecycleEvent
+		if err := json.Unmarshal(model.Event, &event); err != nil {
+			return nil, fmt.Errorf("unmarshal alert %d event: %w", model.ID, err)
+		}
+
+		alerts = append(alerts, domain.Alert{
+			ID:        model.ID,
+			ProjectID: domain.ProjectID(model.ProjectID),
+			Event:     event,
+			CreatedAt: model.CreatedAt,
+		})
+	}
+
+	return alerts, nil
+}
+
+func (r *Repository) MarkAlertsSent(ctx context.Context, ids []int64, at time.Time) error {
+	executor := r.getExecutor(ctx)
+
+	_, err := executor.Exec(ctx,
+		`UPDATE workflows_manager.project_alerts SET sent_at = $2 WHERE id = ANY($1)`,
+		ids, at,
+	)
+	if err != nil {
+		return fmt.Errorf("mark alerts sent: %w", err)
+	}
+
+	return nil
+}
+
+//nolint:ireturn // it's ok here
+func (r *Repository) getExecutor(ctx context.Context) db.Tx {
+	if tx := db.TxFromContext(ctx); tx != nil {
+		return tx
+	}
+
+	return r.db
+}
@@ -0,0 +1,36 @@
+package apitokens
+
+import (
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type tokenModel struct {
+	ID          int        `db:"id"`
+	UserID      int        `db:"user_id"`
+	Name        string     `db:"name"`
+	TokenPrefix string     `db:"token_prefix"`
+	Scopes      []string   `db:"scopes"`
+	ExpiresAt   *time.Time `db:"expires_at"`
+	LastUsedAt  *time.Time `db:"last_used_at"`
+	CreatedAt   time.Time  `db:"created_at"`
+}
+
+func (m *tokenModel) toDomain() domain.APIToken {
+	scopes := make([]domain.APITokenScope, 0, len(m.Scopes))
+	for _, scope := range m.Scopes {
+		scopes = append(scopes, domain.APITokenScope(scope))
+	}
+
+	return domain.APIToken{
+		ID:         domain.APITokenID(m.ID),
+		UserID:     domain.UserID(m.UserID),
+		Name:       m.Name,
+		Prefix:     m.TokenPrefix,
+		Scopes:     scopes,
+		ExpiresAt:  m.ExpiresAt,
+		LastUsedAt: m.LastUsedAt,
+		CreatedAt:  m.CreatedAt,
+	}
+}
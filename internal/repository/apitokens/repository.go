@@ -0,0 +1,171 @@
+package apitokens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/repository/auditlog"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.APITokensRepository = (*Repository)(nil)
+
+const tokenColumns = `id, user_id, name, token_prefix, scopes, expires_at, last_used_at, created_at`
+
+type Repository struct {
+	db db.Tx
+}
+
+func New(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		db: pool,
+	}
+}
+
+func (r *Repository) Create(
+	ctx context.Context,
+	userID domain.UserID,
+	dto domain.APITokenDTO,
+	tokenHash, tokenPrefix string,
+) (domain.APIToken, error) {
+	executor := r.getExecutor(ctx)
+
+	scopes := make([]string, 0, len(dto.Scopes))
+	for _, scope := range dto.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+
+	query := `
+INSERT INTO workflows_manager.api_tokens (user_id, name, token_hash, token_prefix, scopes, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING ` + tokenColumns
+
+	rows, err := executor.Query(ctx, query, int(userID), dto.Name, tokenHash, tokenPrefix, scopes, dto.ExpiresAt)
+	if err != nil {
+		return domain.APIToken{}, fmt.Errorf("insert api token: %w", err)
+	}
+	defer rows.Close()
+
+	model, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[tokenModel])
+	if err != nil {
+		if db.IsUniqueViolation(err) {
+			return domain.APIToken{}, domain.ErrEntityAlreadyExists
+		}
+
+		return domain.APIToken{}, fmt.Errorf("collect api token: %w", err)
+	}
+
+	token := model.toDomain()
+	if err := auditlog.WriteLog(ctx, executor, domain.EntityAPIToken, token.ID.String(), domain.ActionCreate, 0); err != nil {
+		return domain.APIToken{}, fmt.Errorf("write audit log: %w", err)
+	}
+
+	return token, nil
+}
+
+func (r *Repository) List(ctx context.Context, userID domain.UserID) ([]domain.APIToken, error) {
+	executor := r.getExecutor(ctx)
+
+	query := `
+SELECT ` + tokenColumns + `
+FROM workflows_manager.api_tokens
+WHERE user_id = $1
+ORDER BY id`
+
+	rows, err := executor.Query(ctx, query, int(userID))
+	if err != nil {
+		return nil, fmt.Errorf("query api tokens: %w", err)
+	}
+	defer rows.Close()
+
+	listModels, err := pgx.CollectRows(rows, pgx.RowToStructByName[tokenModel])
+	if err != nil {
+		return nil, fmt.Errorf("collect api tokens: %w", err)
+	}
+
+	tokens := make([]domain.APIToken, 0, len(listModels))
+	for i := range listModels {
+		tokens = append(tokens, listModels[i].toDomain())
+	}
+
+	return tokens, nil
+}
+
+func (r *Repository) GetByHash(ctx context.Context, tokenHash string) (domain.APIToken, error) {
+	executor := r.getExecutor(ctx)
+
+	query := `
+SELECT ` + tokenColumns + `
+FROM workflows_manager.api_tokens
+WHERE token_hash = $1`
+
+	rows, err := executor.Query(ctx, query, tokenHash)
+	if err != nil {
+		return domain.APIToken{}, fmt.Errorf("query api token: %w", err)
+	}
+	defer rows.Close()
+
+	model, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[tokenModel])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.APIToken{}, domain.ErrEntityNotFound
+		}
+
+		return domain.APIToken{}, fmt.Errorf("collect api token: %w", err)
+	}
+
+	return model.toDomain(), nil
+}
+
+func (r *Repository) Delete(ctx context.Context, userID domain.UserID, id domain.APITokenID) error {
+	executor := r.getExecutor(ctx)
+
+	result, err := executor.Exec(ctx,
+		`DELETE FROM workflows_manager.api_tokens WHERE user_id = $1 AND id = $2`,
+		int(userID), id.Int(),
+	)
+	if err != nil {
+		return fmt.Errorf("delete api token: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrEntityNotFound
+	}
+
+	if err := auditlog.WriteLog(ctx, executor, domain.EntityAPIToken, id.String(), domain.ActionDelete, 0); err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) TouchLastUsed(ctx context.Context, id domain.APITokenID, at time.Time) error {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+UPDATE workflows_manager.api_tokens
+SET last_used_at = $2
+WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < $2 - INTERVAL '1 minute')`
+
+	if _, err := executor.Exec(ctx, query, id.Int(), at); err != nil {
+		return fmt.Errorf("touch api token: %w", err)
+	}
+
+	return nil
+}
+
+//nolint:ireturn // it's ok here
+func (r *Repository) getExecutor(ctx context.Context) db.Tx {
+	if tx := db.TxFromContext(ctx); tx != nil {
+		return tx
+	}
+
+	return r.db
+}
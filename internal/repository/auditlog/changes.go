@@ -0,0 +1,97 @@
+package auditlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// RedactedValue replaces values of secret fields in audit log changes.
+const RedactedValue = "[REDACTED]"
+
+// secretFieldMarkers are substrings of field names whose values are never written to the audit log.
+var secretFieldMarkers = []string{"password", "secret", "token", "private_key"}
+
+// Change is the old and the new value of a field.
+type Change struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
+
+// Diff compares JSON representations of oldVal and newVal and returns the changed fields
+// keyed by JSON field name. Either value may be nil, e.g. when a setting is created.
+// Values of secret fields are redacted, so the entry only tells that a secret was set, changed or cleared.
+func Diff(oldVal, newVal any) (map[string]Change, error) {
+	oldFields, err := toFields(oldVal)
+	if err != nil {
+		return nil, fmt.Errorf("old value: %w", err)
+	}
+
+	newFields, err := toFields(newVal)
+	if err != nil {
+		return nil, fmt.Errorf("new value: %w", err)
+	}
+
+	changes := make(map[string]Change)
+
+	for key, oldField := range oldFields {
+		newField := newFields[key]
+		if !reflect.DeepEqual(oldField, newField) {
+			changes[key] = makeChange(key, oldField, newField)
+		}
+	}
+
+	for key, newField := range newFields {
+		if _, ok := oldFields[key]; !ok && newField != nil {
+			changes[key] = makeChange(key, nil, newField)
+		}
+	}
+
+	return changes, nil
+}
+
+func toFields(value any) (map[string]any, error) {
+	fields := make(map[string]any)
+	if value == nil {
+		return fields, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("marshal: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("unmarshal: %w", err)
+	}
+
+	return fields, nil
+}
+
+func makeChange(key string, oldField, newField any) Change {
+	if isSecretField(key) {
+		return Change{Old: redact(oldField), New: redact(newField)}
+	}
+
+	return Change{Old: oldField, New: newField}
+}
+
+func isSecretField(key string) bool {
+	key = strings.ToLower(key)
+	for _, marker := range secretFieldMarkers {
+		if strings.Contains(key, marker) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func redact(value any) any {
+	if value == nil || value == "" {
+		return value
+	}
+
+	return RedactedValue
+}
@@ -0,0 +1,43 @@
+package auditlog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	type config struct {
+		URL          string `json:"url"`
+		BindPassword string `json:"bind_password"`
+		Enabled      bool   `json:"enabled"`
+	}
+
+	changes, err := Diff(
+		config{URL: "ldap://old", BindPassword: "old-secret", Enabled: true},
+		config{URL: "ldap://new", BindPassword: "new-secret", Enabled: true},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]Change{
+		"url":           {Old: "ldap://old", New: "ldap://new"},
+		"bind_password": {Old: RedactedValue, New: RedactedValue},
+	}, changes)
+}
+
+func TestDiff_NilOld(t *testing.T) {
+	changes, err := Diff(nil, map[string]any{"name": "prod", "token": "", "description": nil})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]Change{
+		"name":  {Old: nil, New: "prod"},
+		"token": {Old: nil, New: ""},
+	}, changes)
+}
+
+func TestDiff_NoChanges(t *testing.T) {
+	changes, err := Diff(map[string]any{"name": "prod"}, map[string]any{"name": "prod"})
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
@@ -2,9 +2,12 @@ package auditlog
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	appcontext "github.com/rom8726/floxy-manager/internal/context"
@@ -14,6 +17,41 @@ import (
 )
 
 func WriteLog(ctx context.Context, executor db.Tx, entity, entityID, action string, projectID domain.ProjectID) error {
+	return WriteChangeLog(ctx, executor, entity, entityID, action, projectID, nil)
+}
+
+// WriteChangeLog writes an audit log entry with the changed fields of the entity (see Diff).
+// Empty changes are stored as NULL.
+func WriteChangeLog(
+	ctx context.Context,
+	executor db.Tx,
+	entity, entityID, action string,
+	projectID domain.ProjectID,
+	changes map[string]Change,
+) error {
+	return write(ctx, executor, entity, entityID, action, projectID, changes, nil)
+}
+
+// WriteRequestLog writes an audit log entry for an HTTP request together with its response status,
+// so that rejected and failed requests are not mistaken for performed actions.
+func WriteRequestLog(
+	ctx context.Context,
+	executor db.Tx,
+	entity, entityID, action string,
+	projectID domain.ProjectID,
+	statusCode int,
+) error {
+	return write(ctx, executor, entity, entityID, action, projectID, nil, &statusCode)
+}
+
+func write(
+	ctx context.Context,
+	executor db.Tx,
+	entity, entityID, action string,
+	projectID domain.ProjectID,
+	changes map[string]Change,
+	statusCode *int,
+) error {
 	tx := db.TxFromContext(ctx)
 	if tx == nil {
 		tx = executor
@@ -26,7 +64,7 @@ func WriteLog(ctx context.Context, executor db.Tx, entity, entityID, action stri
 
 	// Try to get project_id from context if not provided
 	if projectID == 0 {
-		projectID = appcontext.ProjectID(ctx)
+		projectID = appcontext.LookupProjectID(ctx)
 	}
 
 	// For project entity, project_id is the entity_id
@@ -57,8 +95,9 @@ func WriteLog(ctx context.Context, executor db.Tx, entity, entityID, action stri
 	}
 
 	const query = `
-INSERT INTO workflows_manager.audit_log (entity, entity_id, username, action, project_id, created_at)
-VALUES ($1, $2, $3, $4, $5, NOW())`
+INSERT INTO workflows_manager.audit_log
+    (entity, entity_id, username, action, project_id, changes, status_code, impersonator, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())`
 
 	var projectIDVal *int
 	if projectID > 0 {
@@ -66,7 +105,21 @@ VALUES ($1, $2, $3, $4, $5, NOW())`
 		projectIDVal = &val
 	}
 
-	_, err := tx.Exec(ctx, query, entity, entityID, username, action, projectIDVal)
+	var changesJSON []byte
+	if len(changes) > 0 {
+		var err error
+		changesJSON, err = json.Marshal(changes)
+		if err != nil {
+			return fmt.Errorf("marshal audit log changes: %w", err)
+		}
+	}
+
+	var impersonator *string
+	if name := appcontext.Impersonator(ctx); name != "" {
+		impersonator = &name
+	}
+
+	_, err := tx.Exec(ctx, query, entity, entityID, username, action, projectIDVal, changesJSON, statusCode, impersonator)
 	if err != nil {
 		return fmt.Errorf("write audit log: %w", err)
 	}
@@ -84,53 +137,183 @@ func New(pool *pgxpool.Pool) *Repository {
 	}
 }
 
-func (r *Repository) List(ctx context.Context, projectID domain.ProjectID, page, pageSize int) ([]contract.AuditLogEntry, int, error) {
+func (r *Repository) List(
+	ctx context.Context,
+	filter contract.AuditLogFilter,
+	page, pageSize int,
+) ([]contract.AuditLogEntry, int, error) {
 	executor := r.getExecutor(ctx)
 
 	offset := (page - 1) * pageSize
+	where, args := filterConditions(filter)
+
+	countQuery := `SELECT COUNT(*) FROM workflows_manager.audit_log ` + where
+	countArgs := args
 
-	countQuery := `SELECT COUNT(*) FROM workflows_manager.audit_log WHERE project_id = $1`
-	var total int
-	err := executor.QueryRow(ctx, countQuery, projectID.Int()).Scan(&total)
+	args = append(args, pageSize, offset)
+	query := fmt.Sprintf(`
+SELECT id, COALESCE(project_id, 0), entity, entity_id, username, action, changes, status_code, impersonator, created_at
+FROM workflows_manager.audit_log
+%s
+ORDER BY created_at DESC, id DESC
+LIMIT $%d OFFSET $%d`, where, len(args)-1, len(args))
+
+	entries, total, err := db.QueryPage(ctx, executor, db.PageQuery{
+		CountSQL:  countQuery,
+		CountArgs: countArgs,
+		SQL:       query,
+		Args:      args,
+	}, scanEntry)
 	if err != nil {
-		return nil, 0, fmt.Errorf("count audit log: %w", err)
+		return nil, 0, fmt.Errorf("list audit log: %w", err)
 	}
 
-	query := `
-SELECT id, entity, entity_id, username, action, created_at
+	return entries, total, nil
+}
+
+func (r *Repository) Export(
+	ctx context.Context,
+	filter contract.AuditLogFilter,
+	limit int,
+	fn func(contract.AuditLogEntry) error,
+) error {
+	where, args := filterConditions(filter)
+
+	args = append(args, limit)
+	query := fmt.Sprintf(`
+SELECT id, COALESCE(project_id, 0), entity, entity_id, username, action, changes, status_code, impersonator, created_at
 FROM workflows_manager.audit_log
-WHERE project_id = $1
-ORDER BY created_at DESC
-LIMIT $2 OFFSET $3`
+%s
+ORDER BY created_at DESC, id DESC
+LIMIT $%d`, where, len(args))
+
+	return r.query(ctx, query, args, fn)
+}
+
+// filterConditions builds the WHERE clause of the filter and its arguments.
+func filterConditions(filter contract.AuditLogFilter) (string, []any) {
+	var (
+		conditions []string
+		args       []any
+	)
+
+	addCondition := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, strings.ReplaceAll(condition, "$?", fmt.Sprintf("$%d", len(args))))
+	}
+
+	switch {
+	case filter.ProjectID != 0:
+		addCondition("project_id = $?", filter.ProjectID.Int())
+	case filter.TenantID != 0:
+		// Entries of the tenant projects and of the tenant itself
+		addCondition(`(project_id IN (SELECT id FROM workflows_manager.projects WHERE tenant_id = $?)
+	OR (entity = 'tenant' AND entity_id = $?::text))`, filter.TenantID.Int())
+	}
+
+	if filter.From != nil {
+		addCondition("created_at >= $?", *filter.From)
+	}
+	if filter.To != nil {
+		addCondition("created_at < $?", *filter.To)
+	}
+	if filter.Entity != "" {
+		addCondition("entity = $?", filter.Entity)
+	}
+	if filter.Action != "" {
+		addCondition("action = $?", filter.Action)
+	}
+	if filter.Username != "" {
+		addCondition("username = $?", filter.Username)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
 
-	rows, err := executor.Query(ctx, query, projectID.Int(), pageSize, offset)
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// query runs an audit log query and passes the scanned entries to fn until it returns an error.
+func (r *Repository) query(ctx context.Context, query string, args []any, fn func(contract.AuditLogEntry) error) error {
+	executor := r.getExecutor(ctx)
+
+	rows, err := executor.Query(ctx, query, args...)
 	if err != nil {
-		return nil, 0, fmt.Errorf("query audit log: %w", err)
+		return fmt.Errorf("query audit log: %w", err)
 	}
 	defer rows.Close()
 
-	entries := make([]contract.AuditLogEntry, 0)
 	for rows.Next() {
-		var entry contract.AuditLogEntry
-		err := rows.Scan(
-			&entry.ID,
-			&entry.Entity,
-			&entry.EntityID,
-			&entry.Username,
-			&entry.Action,
-			&entry.CreatedAt,
-		)
+		entry, err := scanEntry(rows)
 		if err != nil {
-			return nil, 0, fmt.Errorf("scan audit log entry: %w", err)
+			return err
+		}
+
+		if err := fn(entry); err != nil {
+			return err
 		}
-		entries = append(entries, entry)
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, 0, fmt.Errorf("rows error: %w", err)
+		return fmt.Errorf("rows error: %w", err)
 	}
 
-	return entries, total, nil
+	return nil
+}
+
+func scanEntry(row pgx.CollectableRow) (contract.AuditLogEntry, error) {
+	var entry contract.AuditLogEntry
+	err := row.Scan(
+		&entry.ID,
+		&entry.ProjectID,
+		&entry.Entity,
+		&entry.EntityID,
+		&entry.Username,
+		&entry.Action,
+		&entry.Changes,
+		&entry.StatusCode,
+		&entry.Impersonator,
+		&entry.CreatedAt,
+	)
+	if err != nil {
+		return contract.AuditLogEntry{}, fmt.Errorf("scan audit log entry: %w", err)
+	}
+
+	return entry, nil
+}
+
+func (r *Repository) ListUnforwarded(ctx context.Context, limit int) ([]contract.AuditLogEntry, error) {
+	const query = `
+SELECT id, COALESCE(project_id, 0), entity, entity_id, username, action, changes, status_code, impersonator, created_at
+FROM workflows_manager.audit_log
+WHERE forwarded_at IS NULL
+ORDER BY id
+LIMIT $1`
+
+	entries := make([]contract.AuditLogEntry, 0)
+	err := r.query(ctx, query, []any{limit}, func(entry contract.AuditLogEntry) error {
+		entries = append(entries, entry)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (r *Repository) MarkForwarded(ctx context.Context, ids []int64) error {
+	executor := r.getExecutor(ctx)
+
+	const query = `UPDATE workflows_manager.audit_log SET forwarded_at = NOW() WHERE id = ANY($1)`
+
+	if _, err := executor.Exec(ctx, query, ids); err != nil {
+		return fmt.Errorf("mark audit log forwarded: %w", err)
+	}
+
+	return nil
 }
 
 func (r *Repository) getExecutor(ctx context.Context) db.Tx {
@@ -0,0 +1,27 @@
+package auditlog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+)
+
+func TestFilterConditions(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	where, args := filterConditions(contract.AuditLogFilter{TenantID: 3, From: &from, Action: "update"})
+	assert.Equal(t, `WHERE (project_id IN (SELECT id FROM workflows_manager.projects WHERE tenant_id = $1)
+	OR (entity = 'tenant' AND entity_id = $1::text)) AND created_at >= $2 AND action = $3`, where)
+	assert.Equal(t, []any{3, from, "update"}, args)
+
+	where, args = filterConditions(contract.AuditLogFilter{ProjectID: 7})
+	assert.Equal(t, "WHERE project_id = $1", where)
+	assert.Equal(t, []any{7}, args)
+
+	where, args = filterConditions(contract.AuditLogFilter{})
+	assert.Empty(t, where)
+	assert.Empty(t, args)
+}
@@ -0,0 +1,284 @@
+package deletion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/repository/auditlog"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.DeletionRepository = (*Repository)(nil)
+
+// liveInstanceCondition selects the instances that have not finished yet.
+const liveInstanceCondition = `wi.status NOT IN ('completed', 'failed', 'cancelled', 'aborted')`
+
+type Repository struct {
+	db db.Tx
+}
+
+func New(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		db: pool,
+	}
+}
+
+func (r *Repository) ProjectImpact(ctx context.Context, projectID domain.ProjectID) (domain.DeletionImpact, error) {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+SELECT (SELECT COUNT(*)
+        FROM workflows.workflow_instances wi
+                 JOIN workflows_manager.project_workflows pw ON pw.workflow_definition_id = wi.workflow_id
+        WHERE pw.project_id = p.id
+          AND ` + liveInstanceCondition + `)                                                   AS live_instances,
+       (SELECT COUNT(*) FROM workflows_manager.memberships m WHERE m.project_id = p.id)          AS memberships,
+       (SELECT COUNT(*) FROM workflows_manager.project_workflows pw WHERE pw.project_id = p.id) AS workflows
+FROM workflows_manager.projects p
+WHERE p.id = $1`
+
+	var impact domain.DeletionImpact
+	err := executor.QueryRow(ctx, query, projectID.Int()).
+		Scan(&impact.LiveInstances, &impact.Memberships, &impact.Workflows)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.DeletionImpact{}, domain.ErrEntityNotFound
+		}
+
+		return domain.DeletionImpact{}, fmt.Errorf("count project dependents: %w", err)
+	}
+
+	return impact, nil
+}
+
+func (r *Repository) TenantImpact(ctx context.Context, tenantID domain.TenantID) (domain.DeletionImpact, error) {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+SELECT (SELECT COUNT(*) FROM workflows_manager.projects p WHERE p.tenant_id = t.id) AS projects,
+       (SELECT COUNT(*)
+        FROM workflows_manager.tenant_memberships tm
+        WHERE tm.tenant_id = t.id)                                                  AS tenant_memberships,
+       (SELECT COUNT(*)
+        FROM workflows.workflow_instances wi
+                 JOIN workflows_manager.project_workflows pw ON pw.workflow_definition_id = wi.workflow_id
+                 JOIN workflows_manager.projects p ON p.id = pw.project_id
+        WHERE p.tenant_id = t.id
+          AND ` + liveInstanceCondition + `)                                       AS live_instances,
+       (SELECT COUNT(*)
+        FROM workflows_manager.memberships m
+                 JOIN workflows_manager.projects p ON p.id = m.project_id
+        WHERE p.tenant_id = t.id)                                                   AS memberships,
+       (SELECT COUNT(*)
+        FROM workflows_manager.project_workflows pw
+                 JOIN workflows_manager.projects p ON p.id = pw.project_id
+        WHERE p.tenant_id = t.id)                                                   AS workflows
+FROM workflows_manager.tenants t
+WHERE t.id = $1`
+
+	var impact domain.DeletionImpact
+	err := executor.QueryRow(ctx, query, tenantID.Int()).Scan(
+		&impact.Projects,
+		&impact.TenantMemberships,
+		&impact.LiveInstances,
+		&impact.Memberships,
+		&impact.Workflows,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.DeletionImpact{}, domain.ErrEntityNotFound
+		}
+
+		return domain.DeletionImpact{}, fmt.Errorf("count tenant dependents: %w", err)
+	}
+
+	return impact, nil
+}
+
+func (r *Repository) ListTenantProjects(ctx context.Context, tenantID domain.TenantID) ([]domain.ProjectID, error) {
+	executor := r.getExecutor(ctx)
+
+	const query = `SELECT id FROM workflows_manager.projects WHERE tenant_id = $1 ORDER BY id`
+
+	rows, err := executor.Query(ctx, query, tenantID.Int())
+	if err != nil {
+		return nil, fmt.Errorf("query tenant projects: %w", err)
+	}
+	defer rows.Close()
+
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, fmt.Errorf("collect tenant projects: %w", err)
+	}
+
+	projectIDs := make([]domain.ProjectID, 0, len(ids))
+	for _, id := range ids {
+		projectIDs = append(projectIDs, domain.ProjectID(id))
+	}
+
+	return projectIDs, nil
+}
+
+func (r *Repository) DeleteProjectInstances(ctx context.Context, projectID domain.ProjectID) (int64, error) {
+	executor := r.getExecutor(ctx)
+
+	// The manager data of the instances is deleted the same way retention does it.
+	const query = `
+WITH doomed AS (
+    SELECT wi.id
+    FROM workflows.workflow_instances wi
+             JOIN workflows_manager.project_workflows pw ON pw.workflow_definition_id = wi.workflow_id
+    WHERE pw.project_id = $1
+),
+logs AS (
+    DELETE FROM workflows_manager.step_logs sl
+    USING workflows.workflow_steps ws
+    WHERE ws.id = sl.step_id AND ws.instance_id IN (SELECT id FROM doomed)
+),
+lineage AS (
+    DELETE FROM workflows_manager.instance_lineage
+    WHERE instance_id IN (SELECT id FROM doomed)
+),
+holds AS (
+    DELETE FROM workflows_manager.instance_holds
+    WHERE instance_id IN (SELECT id FROM doomed)
+),
+held_items AS (
+    DELETE FROM workflows_manager.held_queue_items
+    WHERE instance_id IN (SELECT id FROM doomed)
+),
+signals AS (
+    DELETE FROM workflows_manager.instance_signals
+    WHERE instance_id IN (SELECT id FROM doomed)
+)
+DELETE FROM workflows.workflow_instances
+WHERE id IN (SELECT id FROM doomed)`
+
+	tag, err := executor.Exec(ctx, query, projectID.Int())
+	if err != nil {
+		return 0, fmt.Errorf("delete project instances: %w", err)
+	}
+
+	deleted := tag.RowsAffected()
+	if deleted == 0 {
+		return 0, nil
+	}
+
+	changes := map[string]auditlog.Change{"instances": {Old: deleted, New: 0}}
+	err = auditlog.WriteChangeLog(ctx, executor, domain.EntityProject, projectID.String(),
+		domain.ActionPurge, projectID, changes)
+	if err != nil {
+		return 0, fmt.Errorf("write audit log: %w", err)
+	}
+
+	return deleted, nil
+}
+
+func (r *Repository) DeleteProjectWorkflows(ctx context.Context, projectID domain.ProjectID) (int64, error) {
+	executor := r.getExecutor(ctx)
+
+	const unassignQuery = `
+DELETE FROM workflows_manager.project_workflows
+WHERE project_id = $1
+RETURNING workflow_definition_id`
+
+	rows, err := executor.Query(ctx, unassignQuery, projectID.Int())
+	if err != nil {
+		return 0, fmt.Errorf("unassign project workflows: %w", err)
+	}
+
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return 0, fmt.Errorf("collect unassigned workflows: %w", err)
+	}
+
+	const deleteQuery = `
+DELETE FROM workflows.workflow_definitions wd
+WHERE wd.id = ANY ($1)
+  AND NOT EXISTS (SELECT 1 FROM workflows_manager.project_workflows pw WHERE pw.workflow_definition_id = wd.id)
+RETURNING wd.id`
+
+	rows, err = executor.Query(ctx, deleteQuery, ids)
+	if err != nil {
+		return 0, fmt.Errorf("delete project workflows: %w", err)
+	}
+
+	deleted, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return 0, fmt.Errorf("collect deleted workflows: %w", err)
+	}
+
+	for _, id := range deleted {
+		if err := auditlog.WriteLog(ctx, executor, domain.EntityWorkflow, id, domain.ActionDelete, projectID); err != nil {
+			return 0, fmt.Errorf("write audit log: %w", err)
+		}
+	}
+
+	return int64(len(deleted)), nil
+}
+
+func (r *Repository) DeleteProjectMemberships(ctx context.Context, projectID domain.ProjectID) (int64, error) {
+	executor := r.getExecutor(ctx)
+
+	const query = `DELETE FROM workflows_manager.memberships WHERE project_id = $1 RETURNING id`
+
+	rows, err := executor.Query(ctx, query, projectID.Int())
+	if err != nil {
+		return 0, fmt.Errorf("delete project memberships: %w", err)
+	}
+
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return 0, fmt.Errorf("collect deleted memberships: %w", err)
+	}
+
+	for _, id := range ids {
+		err := auditlog.WriteLog(ctx, executor, domain.EntityMembership, strconv.Itoa(id), domain.ActionDelete, projectID)
+		if err != nil {
+			return 0, fmt.Errorf("write audit log: %w", err)
+		}
+	}
+
+	return int64(len(ids)), nil
+}
+
+func (r *Repository) DeleteTenantMemberships(ctx context.Context, tenantID domain.TenantID) (int64, error) {
+	executor := r.getExecutor(ctx)
+
+	const query = `DELETE FROM workflows_manager.tenant_memberships WHERE tenant_id = $1 RETURNING id`
+
+	rows, err := executor.Query(ctx, query, tenantID.Int())
+	if err != nil {
+		return 0, fmt.Errorf("delete tenant memberships: %w", err)
+	}
+
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return 0, fmt.Errorf("collect deleted tenant memberships: %w", err)
+	}
+
+	for _, id := range ids {
+		err := auditlog.WriteLog(ctx, executor, domain.EntityTenantMembership, strconv.Itoa(id), domain.ActionDelete, 0)
+		if err != nil {
+			return 0, fmt.Errorf("write audit log: %w", err)
+		}
+	}
+
+	return int64(len(ids)), nil
+}
+
+//nolint:ireturn // it's ok here
+func (r *Repository) getExecutor(ctx context.Context) db.Tx {
+	if tx := db.TxFromContext(ctx); tx != nil {
+		return tx
+	}
+
+	return r.db
+}
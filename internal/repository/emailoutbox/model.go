@@ -0,0 +1,41 @@
+package emailoutbox
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type emailModel struct {
+	ID            int64          `db:"id"`
+	Recipient     string         `db:"recipient"`
+	Subject       string         `db:"subject"`
+	Body          string         `db:"body"`
+	Status        string         `db:"status"`
+	Attempts      int            `db:"attempts"`
+	NextAttemptAt *time.Time     `db:"next_attempt_at"`
+	LastError     sql.NullString `db:"last_error"`
+	CreatedAt     time.Time      `db:"created_at"`
+	SentAt        *time.Time     `db:"sent_at"`
+}
+
+func (m *emailModel) toDomain() domain.OutboxEmail {
+	var lastError *string
+	if m.LastError.Valid {
+		lastError = &m.LastError.String
+	}
+
+	return domain.OutboxEmail{
+		ID:            m.ID,
+		Recipient:     m.Recipient,
+		Subject:       m.Subject,
+		Body:          m.Body,
+		Status:        domain.EmailStatus(m.Status),
+		Attempts:      m.Attempts,
+		NextAttemptAt: m.NextAttemptAt,
+		LastError:     lastError,
+		CreatedAt:     m.CreatedAt,
+		SentAt:        m.SentAt,
+	}
+}
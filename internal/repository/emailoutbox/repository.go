@@ -0,0 +1,200 @@
+package emailoutbox
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/repository/auditlog"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.EmailOutboxRepository = (*Repository)(nil)
+
+const emailColumns = `id, recipient, subject, body, status, attempts, next_attempt_at, last_error, created_at, sent_at`
+
+type Repository struct {
+	db db.Tx
+}
+
+func New(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		db: pool,
+	}
+}
+
+func (r *Repository) Enqueue(ctx context.Context, email domain.OutboxEmail) (int64, error) {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+INSERT INTO workflows_manager.email_outbox (recipient, subject, body, status, next_attempt_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id`
+
+	var id int64
+	err := executor.QueryRow(ctx, query,
+		email.Recipient,
+		email.Subject,
+		email.Body,
+		string(domain.EmailPending),
+		email.NextAttemptAt,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("insert outbox email: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *Repository) ListDue(ctx context.Context, now time.Time, limit int) ([]domain.OutboxEmail, error) {
+	query := `
+SELECT ` + emailColumns + `
+FROM workflows_manager.email_outbox
+WHERE status = $1 AND next_attempt_at <= $2
+ORDER BY next_attempt_at
+LIMIT $3
+FOR UPDATE SKIP LOCKED`
+
+	return r.getMany(ctx, query, string(domain.EmailPending), now, limit)
+}
+
+func (r *Repository) SaveAttempt(ctx context.Context, email domain.OutboxEmail) error {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+UPDATE workflows_manager.email_outbox
+SET status = $2,
+    attempts = $3,
+    next_attempt_at = $4,
+    last_error = $5,
+    sent_at = $6,
+    body = CASE WHEN $2 = 'sent' THEN '' ELSE body END
+WHERE id = $1`
+
+	_, err := executor.Exec(ctx, query,
+		email.ID,
+		string(email.Status),
+		email.Attempts,
+		email.NextAttemptAt,
+		email.LastError,
+		email.SentAt,
+	)
+	if err != nil {
+		return fmt.Errorf("update outbox email: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) GetByID(ctx context.Context, id int64) (domain.OutboxEmail, error) {
+	query := `SELECT ` + emailColumns + ` FROM workflows_manager.email_outbox WHERE id = $1`
+
+	emails, err := r.getMany(ctx, query, id)
+	if err != nil {
+		return domain.OutboxEmail{}, err
+	}
+
+	if len(emails) == 0 {
+		return domain.OutboxEmail{}, domain.ErrEntityNotFound
+	}
+
+	return emails[0], nil
+}
+
+func (r *Repository) List(
+	ctx context.Context,
+	status domain.EmailStatus,
+	page, pageSize int,
+) ([]domain.OutboxEmail, int, error) {
+	executor := r.getExecutor(ctx)
+
+	offset := (page - 1) * pageSize
+
+	query := `
+SELECT ` + emailColumns + `
+FROM workflows_manager.email_outbox
+WHERE status = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2 OFFSET $3`
+
+	listModels, total, err := db.QueryPage(ctx, executor, db.PageQuery{
+		CountSQL:  `SELECT COUNT(*) FROM workflows_manager.email_outbox WHERE status = $1`,
+		CountArgs: []any{string(status)},
+		SQL:       query,
+		Args:      []any{string(status), pageSize, offset},
+	}, pgx.RowToStructByName[emailModel])
+	if err != nil {
+		return nil, 0, fmt.Errorf("list outbox emails: %w", err)
+	}
+
+	emails := make([]domain.OutboxEmail, 0, len(listModels))
+	for i := range listModels {
+		emails = append(emails, listModels[i].toDomain())
+	}
+
+	return emails, total, nil
+}
+
+func (r *Repository) Requeue(ctx context.Context, id int64, now time.Time) error {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+UPDATE workflows_manager.email_outbox
+SET status = $2,
+    attempts = 0,
+    next_attempt_at = $3
+WHERE id = $1 AND status = $4`
+
+	tag, err := executor.Exec(ctx, query, id, string(domain.EmailPending), now, string(domain.EmailFailed))
+	if err != nil {
+		return fmt.Errorf("requeue outbox email: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrEntityNotFound
+	}
+
+	err = auditlog.WriteLog(ctx, executor, domain.EntityEmail, strconv.FormatInt(id, 10), domain.ActionResend, 0)
+	if err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) getMany(ctx context.Context, query string, args ...any) ([]domain.OutboxEmail, error) {
+	executor := r.getExecutor(ctx)
+
+	rows, err := executor.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query outbox emails: %w", err)
+	}
+	defer rows.Close()
+
+	listModels, err := pgx.CollectRows(rows, pgx.RowToStructByName[emailModel])
+	if err != nil {
+		return nil, fmt.Errorf("collect outbox emails: %w", err)
+	}
+
+	emails := make([]domain.OutboxEmail, 0, len(listModels))
+	for i := range listModels {
+		emails = append(emails, listModels[i].toDomain())
+	}
+
+	return emails, nil
+}
+
+//nolint:ireturn // it's ok here
+func (r *Repository) getExecutor(ctx context.Context) db.Tx {
+	if tx := db.TxFromContext(ctx); tx != nil {
+		return tx
+	}
+
+	return r.db
+}
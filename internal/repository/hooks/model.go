@@ -0,0 +1,41 @@
+package hooks
+
+import (
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type hookModel struct {
+	ID                   int        `db:"id"`
+	ProjectID            int        `db:"project_id"`
+	WorkflowDefinitionID string     `db:"workflow_definition_id"`
+	Name                 string     `db:"name"`
+	Token                string     `db:"token"`
+	SecretEncrypted      string     `db:"secret_encrypted"`
+	RequireSignature     bool       `db:"require_signature"`
+	RateLimitPerMinute   int        `db:"rate_limit_per_minute"`
+	Enabled              bool       `db:"enabled"`
+	LastTriggeredAt      *time.Time `db:"last_triggered_at"`
+	CreatedBy            string     `db:"created_by"`
+	CreatedAt            time.Time  `db:"created_at"`
+	UpdatedAt            time.Time  `db:"updated_at"`
+}
+
+func (m *hookModel) toDomain() domain.Hook {
+	return domain.Hook{
+		ID:                 domain.HookID(m.ID),
+		ProjectID:          domain.ProjectID(m.ProjectID),
+		WorkflowID:         m.WorkflowDefinitionID,
+		Name:               m.Name,
+		Token:              m.Token,
+		SecretEncrypted:    m.SecretEncrypted,
+		RequireSignature:   m.RequireSignature,
+		RateLimitPerMinute: m.RateLimitPerMinute,
+		Enabled:            m.Enabled,
+		LastTriggeredAt:    m.LastTriggeredAt,
+		CreatedBy:          m.CreatedBy,
+		CreatedAt:          m.CreatedAt,
+		UpdatedAt:          m.UpdatedAt,
+	}
+}
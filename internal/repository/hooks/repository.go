@@ -0,0 +1,270 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/repository/auditlog"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.HooksRepository = (*Repository)(nil)
+
+const hookColumns = `id, project_id, workflow_definition_id, name, token, secret_encrypted, require_signature,
+rate_limit_per_minute, enabled, last_triggered_at, created_by, created_at, updated_at`
+
+type Repository struct {
+	db db.Tx
+}
+
+func New(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		db: pool,
+	}
+}
+
+func (r *Repository) List(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	page, pageSize int,
+) ([]domain.Hook, int, error) {
+	executor := r.getExecutor(ctx)
+
+	offset := (page - 1) * pageSize
+
+	query := `
+SELECT ` + hookColumns + `
+FROM workflows_manager.workflow_hooks
+WHERE project_id = $1
+ORDER BY id
+LIMIT $2 OFFSET $3`
+
+	listModels, total, err := db.QueryPage(ctx, executor, db.PageQuery{
+		CountSQL:  `SELECT COUNT(*) FROM workflows_manager.workflow_hooks WHERE project_id = $1`,
+		CountArgs: []any{projectID.Int()},
+		SQL:       query,
+		Args:      []any{projectID.Int(), pageSize, offset},
+	}, pgx.RowToStructByName[hookModel])
+	if err != nil {
+		return nil, 0, fmt.Errorf("list hooks: %w", err)
+	}
+
+	hooks := make([]domain.Hook, 0, len(listModels))
+	for i := range listModels {
+		hooks = append(hooks, listModels[i].toDomain())
+	}
+
+	return hooks, total, nil
+}
+
+func (r *Repository) GetByID(ctx context.Context, projectID domain.ProjectID, id domain.HookID) (domain.Hook, error) {
+	query := `
+SELECT ` + hookColumns + `
+FROM workflows_manager.workflow_hooks
+WHERE project_id = $1 AND id = $2
+LIMIT 1`
+
+	return r.getOne(ctx, query, projectID.Int(), id.Int())
+}
+
+func (r *Repository) GetByToken(ctx context.Context, token string) (domain.Hook, error) {
+	query := `
+SELECT ` + hookColumns + `
+FROM workflows_manager.workflow_hooks
+WHERE token = $1
+LIMIT 1`
+
+	return r.getOne(ctx, query, token)
+}
+
+func (r *Repository) Create(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	dto domain.HookDTO,
+	token, secretEncrypted string,
+) (domain.HookID, error) {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+INSERT INTO workflows_manager.workflow_hooks
+    (project_id, workflow_definition_id, name, token, secret_encrypted, require_signature,
+     rate_limit_per_minute, enabled, created_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING id`
+
+	var id int
+	err := executor.QueryRow(ctx, query,
+		projectID.Int(),
+		dto.WorkflowID,
+		dto.Name,
+		token,
+		secretEncrypted,
+		dto.RequireSignature,
+		dto.RateLimitPerMinute,
+		dto.Enabled,
+		appcontext.Username(ctx),
+	).Scan(&id)
+	if err != nil {
+		if db.IsUniqueViolation(err) {
+			return 0, domain.ErrEntityAlreadyExists
+		}
+
+		return 0, fmt.Errorf("insert hook: %w", err)
+	}
+
+	hookID := domain.HookID(id)
+	if err := auditlog.WriteLog(ctx, executor, domain.EntityHook, hookID.String(), domain.ActionCreate, projectID); err != nil {
+		return 0, fmt.Errorf("write audit log: %w", err)
+	}
+
+	return hookID, nil
+}
+
+func (r *Repository) Update(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	id domain.HookID,
+	dto domain.HookDTO,
+) error {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+UPDATE workflows_manager.workflow_hooks
+SET workflow_definition_id = $3,
+    name = $4,
+    require_signature = $5,
+    rate_limit_per_minute = $6,
+    enabled = $7,
+    updated_at = NOW()
+WHERE project_id = $1 AND id = $2`
+
+	result, err := executor.Exec(ctx, query,
+		projectID.Int(),
+		id.Int(),
+		dto.WorkflowID,
+		dto.Name,
+		dto.RequireSignature,
+		dto.RateLimitPerMinute,
+		dto.Enabled,
+	)
+	if err != nil {
+		if db.IsUniqueViolation(err) {
+			return domain.ErrEntityAlreadyExists
+		}
+
+		return fmt.Errorf("update hook: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrEntityNotFound
+	}
+
+	if err := auditlog.WriteLog(ctx, executor, domain.EntityHook, id.String(), domain.ActionUpdate, projectID); err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) UpdateSecret(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	id domain.HookID,
+	secretEncrypted string,
+) error {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+UPDATE workflows_manager.workflow_hooks
+SET secret_encrypted = $3, updated_at = NOW()
+WHERE project_id = $1 AND id = $2`
+
+	result, err := executor.Exec(ctx, query, projectID.Int(), id.Int(), secretEncrypted)
+	if err != nil {
+		return fmt.Errorf("update hook secret: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrEntityNotFound
+	}
+
+	if err := auditlog.WriteLog(ctx, executor, domain.EntityHook, id.String(), domain.ActionRotate, projectID); err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) Delete(ctx context.Context, projectID domain.ProjectID, id domain.HookID) error {
+	executor := r.getExecutor(ctx)
+
+	result, err := executor.Exec(ctx,
+		`DELETE FROM workflows_manager.workflow_hooks WHERE project_id = $1 AND id = $2`,
+		projectID.Int(), id.Int(),
+	)
+	if err != nil {
+		return fmt.Errorf("delete hook: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrEntityNotFound
+	}
+
+	if err := auditlog.WriteLog(ctx, executor, domain.EntityHook, id.String(), domain.ActionDelete, projectID); err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) MarkTriggered(ctx context.Context, id domain.HookID, at time.Time) error {
+	executor := r.getExecutor(ctx)
+
+	_, err := executor.Exec(ctx,
+		`UPDATE workflows_manager.workflow_hooks SET last_triggered_at = $2 WHERE id = $1`,
+		id.Int(), at,
+	)
+	if err != nil {
+		return fmt.Errorf("mark hook triggered: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) getOne(ctx context.Context, query string, args ...any) (domain.Hook, error) {
+	executor := r.getExecutor(ctx)
+
+	rows, err := executor.Query(ctx, query, args...)
+	if err != nil {
+		return domain.Hook{}, fmt.Errorf("query hook: %w", err)
+	}
+	defer rows.Close()
+
+	model, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[hookModel])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Hook{}, domain.ErrEntityNotFound
+		}
+
+		return domain.Hook{}, fmt.Errorf("collect hook: %w", err)
+	}
+
+	return model.toDomain(), nil
+}
+
+//nolint:ireturn // it's ok here
+func (r *Repository) getExecutor(ctx context.Context) db.Tx {
+	if tx := db.TxFromContext(ctx); tx != nil {
+		return tx
+	}
+
+	return r.db
+}
@@ -0,0 +1,112 @@
+package idempotencykeys
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.IdempotencyKeysRepository = (*Repository)(nil)
+
+type Repository struct {
+	db db.Tx
+}
+
+func New(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		db: pool,
+	}
+}
+
+func (r *Repository) Reserve(ctx context.Context, req *domain.IdempotentRequest) (bool, error) {
+	executor := r.getExecutor(ctx)
+
+	const cleanupQuery = `DELETE FROM workflows_manager.idempotency_keys WHERE expires_at <= now()`
+
+	if _, err := executor.Exec(ctx, cleanupQuery); err != nil {
+		return false, fmt.Errorf("delete expired idempotency keys: %w", err)
+	}
+
+	const query = `
+INSERT INTO workflows_manager.idempotency_keys (user_id, method, path, idempotency_key, request_hash, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (user_id, method, path, idempotency_key) DO NOTHING`
+
+	result, err := executor.Exec(ctx, query,
+		int64(req.UserID), req.Method, req.Path, req.Key, req.RequestHash, req.ExpiresAt)
+	if err != nil {
+		return false, fmt.Errorf("insert idempotency key: %w", err)
+	}
+
+	return result.RowsAffected() == 1, nil
+}
+
+func (r *Repository) Get(ctx context.Context, req *domain.IdempotentRequest) (domain.IdempotentRequest, error) {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+SELECT request_hash, COALESCE(status_code, 0), content_type, COALESCE(response_body, ''::bytea), expires_at
+FROM workflows_manager.idempotency_keys
+WHERE user_id = $1 AND method = $2 AND path = $3 AND idempotency_key = $4`
+
+	stored := domain.IdempotentRequest{UserID: req.UserID, Method: req.Method, Path: req.Path, Key: req.Key}
+
+	err := executor.QueryRow(ctx, query, int64(req.UserID), req.Method, req.Path, req.Key).Scan(
+		&stored.RequestHash, &stored.StatusCode, &stored.ContentType, &stored.Body, &stored.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.IdempotentRequest{}, domain.ErrEntityNotFound
+		}
+
+		return domain.IdempotentRequest{}, fmt.Errorf("get idempotency key: %w", err)
+	}
+
+	return stored, nil
+}
+
+func (r *Repository) Complete(ctx context.Context, req *domain.IdempotentRequest) error {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+UPDATE workflows_manager.idempotency_keys
+SET status_code = $5, content_type = $6, response_body = $7
+WHERE user_id = $1 AND method = $2 AND path = $3 AND idempotency_key = $4`
+
+	_, err := executor.Exec(ctx, query, int64(req.UserID), req.Method, req.Path, req.Key,
+		req.StatusCode, req.ContentType, req.Body)
+	if err != nil {
+		return fmt.Errorf("update idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) Release(ctx context.Context, req *domain.IdempotentRequest) error {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+DELETE FROM workflows_manager.idempotency_keys
+WHERE user_id = $1 AND method = $2 AND path = $3 AND idempotency_key = $4 AND status_code IS NULL`
+
+	if _, err := executor.Exec(ctx, query, int64(req.UserID), req.Method, req.Path, req.Key); err != nil {
+		return fmt.Errorf("delete idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+//nolint:ireturn // it's ok here
+func (r *Repository) getExecutor(ctx context.Context) db.Tx {
+	if tx := db.TxFromContext(ctx); tx != nil {
+		return tx
+	}
+
+	return r.db
+}
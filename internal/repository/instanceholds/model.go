@@ -0,0 +1,32 @@
+package instanceholds
+
+import (
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+const holdsQuery = `
+SELECT h.instance_id, h.project_id, h.reason, h.created_by, h.created_at,
+       (SELECT count(*) FROM workflows_manager.held_queue_items q WHERE q.instance_id = h.instance_id) AS held_steps
+FROM workflows_manager.instance_holds h`
+
+type holdModel struct {
+	InstanceID int64     `db:"instance_id"`
+	ProjectID  int       `db:"project_id"`
+	Reason     string    `db:"reason"`
+	CreatedBy  string    `db:"created_by"`
+	CreatedAt  time.Time `db:"created_at"`
+	HeldSteps  int64     `db:"held_steps"`
+}
+
+func (m *holdModel) toDomain() domain.InstanceHold {
+	return domain.InstanceHold{
+		InstanceID: int(m.InstanceID),
+		ProjectID:  domain.ProjectID(m.ProjectID),
+		Reason:     m.Reason,
+		HeldSteps:  int(m.HeldSteps),
+		CreatedBy:  m.CreatedBy,
+		CreatedAt:  m.CreatedAt,
+	}
+}
@@ -0,0 +1,188 @@
+package instanceholds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/repository/auditlog"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.InstanceHoldsRepository = (*Repository)(nil)
+
+type Repository struct {
+	db db.Tx
+}
+
+func New(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		db: pool,
+	}
+}
+
+func (r *Repository) Create(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	instanceID int,
+	reason string,
+) (domain.InstanceHold, error) {
+	executor := r.getExecutor(ctx)
+
+	const insertQuery = `
+INSERT INTO workflows_manager.instance_holds (instance_id, project_id, reason, created_by)
+VALUES ($1, $2, $3, $4)
+RETURNING created_at`
+
+	hold := domain.InstanceHold{
+		InstanceID: instanceID,
+		ProjectID:  projectID,
+		Reason:     reason,
+		CreatedBy:  appcontext.Username(ctx),
+	}
+
+	err := executor.QueryRow(ctx, insertQuery, instanceID, projectID.Int(), reason, hold.CreatedBy).
+		Scan(&hold.CreatedAt)
+	if err != nil {
+		if db.IsUniqueViolation(err) {
+			return domain.InstanceHold{}, domain.ErrEntityAlreadyExists
+		}
+
+		return domain.InstanceHold{}, fmt.Errorf("insert instance hold: %w", err)
+	}
+
+	// Steps a worker has already picked up are left to finish
+	const holdQuery = `
+WITH moved AS (
+    DELETE FROM workflows.workflow_queue
+    WHERE instance_id = $1 AND attempted_at IS NULL
+    RETURNING id, instance_id, step_id, scheduled_at, priority
+)
+INSERT INTO workflows_manager.held_queue_items (id, instance_id, step_id, scheduled_at, priority)
+SELECT id, instance_id, step_id, scheduled_at, priority
+FROM moved`
+
+	tag, err := executor.Exec(ctx, holdQuery, instanceID)
+	if err != nil {
+		return domain.InstanceHold{}, fmt.Errorf("hold queued steps: %w", err)
+	}
+	hold.HeldSteps = int(tag.RowsAffected())
+
+	err = auditlog.WriteLog(ctx, executor, domain.EntityInstance, strconv.Itoa(instanceID),
+		domain.ActionPause, projectID)
+	if err != nil {
+		return domain.InstanceHold{}, fmt.Errorf("write audit log: %w", err)
+	}
+
+	return hold, nil
+}
+
+func (r *Repository) Delete(ctx context.Context, projectID domain.ProjectID, instanceID int) (int, error) {
+	executor := r.getExecutor(ctx)
+
+	const deleteQuery = `
+DELETE FROM workflows_manager.instance_holds
+WHERE instance_id = $1 AND project_id = $2`
+
+	tag, err := executor.Exec(ctx, deleteQuery, instanceID, projectID.Int())
+	if err != nil {
+		return 0, fmt.Errorf("delete instance hold: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return 0, domain.ErrEntityNotFound
+	}
+
+	// The hold is gone, so the trigger lets the steps into the queue again
+	const releaseQuery = `
+WITH released AS (
+    DELETE FROM workflows_manager.held_queue_items
+    WHERE instance_id = $1
+    RETURNING instance_id, step_id, scheduled_at, priority
+)
+INSERT INTO workflows.workflow_queue (instance_id, step_id, scheduled_at, priority)
+SELECT instance_id, step_id, scheduled_at, priority
+FROM released`
+
+	tag, err = executor.Exec(ctx, releaseQuery, instanceID)
+	if err != nil {
+		return 0, fmt.Errorf("release held steps: %w", err)
+	}
+
+	err = auditlog.WriteLog(ctx, executor, domain.EntityInstance, strconv.Itoa(instanceID),
+		domain.ActionResume, projectID)
+	if err != nil {
+		return 0, fmt.Errorf("write audit log: %w", err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+func (r *Repository) Get(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	instanceID int,
+) (domain.InstanceHold, error) {
+	executor := r.getExecutor(ctx)
+
+	const query = holdsQuery + `
+WHERE h.project_id = $1 AND h.instance_id = $2`
+
+	rows, err := executor.Query(ctx, query, projectID.Int(), instanceID)
+	if err != nil {
+		return domain.InstanceHold{}, fmt.Errorf("query instance hold: %w", err)
+	}
+	defer rows.Close()
+
+	model, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[holdModel])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.InstanceHold{}, domain.ErrEntityNotFound
+		}
+
+		return domain.InstanceHold{}, fmt.Errorf("collect instance hold: %w", err)
+	}
+
+	return model.toDomain(), nil
+}
+
+func (r *Repository) List(ctx context.Context, projectID domain.ProjectID) ([]domain.InstanceHold, error) {
+	executor := r.getExecutor(ctx)
+
+	const query = holdsQuery + `
+WHERE h.project_id = $1
+ORDER BY h.created_at DESC`
+
+	rows, err := executor.Query(ctx, query, projectID.Int())
+	if err != nil {
+		return nil, fmt.Errorf("query instance holds: %w", err)
+	}
+	defer rows.Close()
+
+	listModels, err := pgx.CollectRows(rows, pgx.RowToStructByName[holdModel])
+	if err != nil {
+		return nil, fmt.Errorf("collect instance holds: %w", err)
+	}
+
+	holds := make([]domain.InstanceHold, 0, len(listModels))
+	for i := range listModels {
+		holds = append(holds, listModels[i].toDomain())
+	}
+
+	return holds, nil
+}
+
+//nolint:ireturn // it's ok here
+func (r *Repository) getExecutor(ctx context.Context) db.Tx {
+	if tx := db.TxFromContext(ctx); tx != nil {
+		return tx
+	}
+
+	return r.db
+}
@@ -0,0 +1,36 @@
+package instancesignals
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type signalModel struct {
+	ID             int64     `db:"id"`
+	InstanceID     int64     `db:"instance_id"`
+	ProjectID      int       `db:"project_id"`
+	StepID         int64     `db:"step_id"`
+	Name           string    `db:"name"`
+	Payload        []byte    `db:"payload"`
+	Decision       string    `db:"decision"`
+	IdempotencyKey *string   `db:"idempotency_key"`
+	CreatedBy      string    `db:"created_by"`
+	CreatedAt      time.Time `db:"created_at"`
+}
+
+func (m *signalModel) toDomain() domain.InstanceSignal {
+	return domain.InstanceSignal{
+		ID:             m.ID,
+		InstanceID:     int(m.InstanceID),
+		ProjectID:      domain.ProjectID(m.ProjectID),
+		StepID:         int(m.StepID),
+		Name:           m.Name,
+		Payload:        json.RawMessage(m.Payload),
+		Decision:       m.Decision,
+		IdempotencyKey: m.IdempotencyKey,
+		CreatedBy:      m.CreatedBy,
+		CreatedAt:      m.CreatedAt,
+	}
+}
@@ -0,0 +1,140 @@
+package instancesignals
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/repository/auditlog"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.InstanceSignalsRepository = (*Repository)(nil)
+
+type Repository struct {
+	db db.Tx
+}
+
+func New(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		db: pool,
+	}
+}
+
+func (r *Repository) FindWaitingStep(
+	ctx context.Context,
+	instanceID int,
+	stepName string,
+) (domain.WorkflowStep, error) {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+SELECT id, instance_id, step_name, step_type, status
+FROM workflows.workflow_steps
+WHERE instance_id = $1 AND step_name = $2 AND status = 'waiting_decision'
+ORDER BY id DESC
+LIMIT 1`
+
+	var step domain.WorkflowStep
+	err := executor.QueryRow(ctx, query, instanceID, stepName).
+		Scan(&step.ID, &step.InstanceID, &step.StepName, &step.StepType, &step.Status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.WorkflowStep{}, domain.ErrEntityNotFound
+		}
+
+		return domain.WorkflowStep{}, fmt.Errorf("query waiting step: %w", err)
+	}
+
+	return step, nil
+}
+
+func (r *Repository) Create(ctx context.Context, signal domain.InstanceSignal) (domain.InstanceSignal, error) {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+INSERT INTO workflows_manager.instance_signals
+    (instance_id, project_id, step_id, name, payload, decision, idempotency_key, created_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, created_at`
+
+	signal.CreatedBy = appcontext.Username(ctx)
+
+	var payload *string
+	if len(signal.Payload) > 0 {
+		s := string(signal.Payload)
+		payload = &s
+	}
+
+	err := executor.QueryRow(ctx, query,
+		signal.InstanceID,
+		signal.ProjectID.Int(),
+		signal.StepID,
+		signal.Name,
+		payload,
+		signal.Decision,
+		signal.IdempotencyKey,
+		signal.CreatedBy,
+	).Scan(&signal.ID, &signal.CreatedAt)
+	if err != nil {
+		if db.IsUniqueViolation(err) {
+			return domain.InstanceSignal{}, domain.ErrEntityAlreadyExists
+		}
+
+		return domain.InstanceSignal{}, fmt.Errorf("insert instance signal: %w", err)
+	}
+
+	err = auditlog.WriteLog(ctx, executor, domain.EntityInstance, strconv.Itoa(signal.InstanceID),
+		domain.ActionSignal, signal.ProjectID)
+	if err != nil {
+		return domain.InstanceSignal{}, fmt.Errorf("write audit log: %w", err)
+	}
+
+	return signal, nil
+}
+
+func (r *Repository) GetByIdempotencyKey(
+	ctx context.Context,
+	instanceID int,
+	key string,
+) (domain.InstanceSignal, error) {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+SELECT id, instance_id, project_id, step_id, name, payload, decision, idempotency_key, created_by, created_at
+FROM workflows_manager.instance_signals
+WHERE instance_id = $1 AND idempotency_key = $2`
+
+	rows, err := executor.Query(ctx, query, instanceID, key)
+	if err != nil {
+		return domain.InstanceSignal{}, fmt.Errorf("query instance signal: %w", err)
+	}
+	defer rows.Close()
+
+	model, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[signalModel])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.InstanceSignal{}, domain.ErrEntityNotFound
+		}
+
+		return domain.InstanceSignal{}, fmt.Errorf("collect instance signal: %w", err)
+	}
+
+	return model.toDomain(), nil
+}
+
+//nolint:ireturn // it's ok here
+func (r *Repository) getExecutor(ctx context.Context) db.Tx {
+	if tx := db.TxFromContext(ctx); tx != nil {
+		return tx
+	}
+
+	return r.db
+}
@@ -144,15 +144,19 @@ func (r *Repository) List(ctx context.Context, filter domain.LDAPSyncLogFilter)
 		return domain.LDAPSyncLogsResult{}, fmt.Errorf("build select query: %w", err)
 	}
 
-	rows, err := executor.Query(ctx, sqlStr, args...)
+	countSQL, countArgs, err := countBuilder.ToSql()
 	if err != nil {
-		return domain.LDAPSyncLogsResult{}, fmt.Errorf("execute select query: %w", err)
+		return domain.LDAPSyncLogsResult{}, fmt.Errorf("build count query: %w", err)
 	}
-	defer rows.Close()
 
-	models, err := pgx.CollectRows(rows, pgx.RowToStructByName[ldapSyncLogModel])
+	models, total, err := db.QueryPage(ctx, executor, db.PageQuery{
+		CountSQL:  countSQL,
+		CountArgs: countArgs,
+		SQL:       sqlStr,
+		Args:      args,
+	}, pgx.RowToStructByName[ldapSyncLogModel])
 	if err != nil {
-		return domain.LDAPSyncLogsResult{}, fmt.Errorf("collect rows: %w", err)
+		return domain.LDAPSyncLogsResult{}, fmt.Errorf("list ldap sync logs: %w", err)
 	}
 
 	logs := make([]domain.LDAPSyncLog, 0, len(models))
@@ -160,16 +164,6 @@ func (r *Repository) List(ctx context.Context, filter domain.LDAPSyncLogFilter)
 		logs = append(logs, m.toDomain())
 	}
 
-	countSQL, countArgs, err := countBuilder.ToSql()
-	if err != nil {
-		return domain.LDAPSyncLogsResult{}, fmt.Errorf("build count query: %w", err)
-	}
-
-	var total int
-	if err := executor.QueryRow(ctx, countSQL, countArgs...).Scan(&total); err != nil {
-		return domain.LDAPSyncLogsResult{}, fmt.Errorf("execute count query: %w", err)
-	}
-
 	return domain.LDAPSyncLogsResult{
 		Logs:  logs,
 		Total: total,
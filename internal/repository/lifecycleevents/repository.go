@@ -0,0 +1,204 @@
+package lifecycleevents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.LifecycleEventsRepository = (*Repository)(nil)
+
+// longRunningLimit caps the number of long running instances reported per project and check.
+const longRunningLimit = 100
+
+type Repository struct {
+	db db.Tx
+}
+
+func New(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		db: pool,
+	}
+}
+
+type eventModel struct {
+	Type       string    `db:"type"`
+	ProjectID  int       `db:"project_id"`
+	WorkflowID string    `db:"workflow_id"`
+	InstanceID int64     `db:"instance_id"`
+	DLQItemID  *int64    `db:"dlq_item_id"`
+	StepName   *string   `db:"step_name"`
+	Status     string    `db:"status"`
+	Error      *string   `db:"error"`
+	OccurredAt time.Time `db:"occurred_at"`
+}
+
+func (r *Repository) GetCursor(ctx context.Context, name string) (time.Time, error) {
+	executor := r.getExecutor(ctx)
+
+	var position time.Time
+	err := executor.QueryRow(ctx,
+		`SELECT position FROM workflows_manager.event_cursors WHERE name = $1`,
+		name,
+	).Scan(&position)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return time.Time{}, domain.ErrEntityNotFound
+		}
+
+		return time.Time{}, fmt.Errorf("get event cursor: %w", err)
+	}
+
+	return position, nil
+}
+
+func (r *Repository) SaveCursor(ctx context.Context, name string, position time.Time) error {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+INSERT INTO workflows_manager.event_cursors (name, position)
+VALUES ($1, $2)
+ON CONFLICT (name) DO UPDATE SET position = EXCLUDED.position, updated_at = NOW()`
+
+	if _, err := executor.Exec(ctx, query, name, position); err != nil {
+		return fmt.Errorf("save event cursor: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) List(ctx context.Context, from, to time.Time) ([]domain.LifecycleEvent, error) {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+SELECT type, project_id, workflow_id, instance_id, dlq_item_id, step_name, status, error, occurred_at
+FROM (
+    SELECT CASE wi.status WHEN 'completed' THEN $3::text ELSE $4::text END AS type,
+           pw.project_id,
+           wi.workflow_id,
+           wi.id          AS instance_id,
+           NULL::bigint   AS dlq_item_id,
+           NULL::text     AS step_name,
+           wi.status,
+           wi.error,
+           wi.updated_at  AS occurred_at
+    FROM workflows.workflow_instances wi
+    JOIN workflows_manager.project_workflows pw ON pw.workflow_definition_id = wi.workflow_id
+    WHERE wi.status IN ('completed', 'failed')
+      AND wi.updated_at > $1 AND wi.updated_at <= $2
+    UNION ALL
+    SELECT $5::text,
+           pw.project_id,
+           d.workflow_id,
+           d.instance_id,
+           d.id,
+           d.step_name,
+           'dlq',
+           d.error,
+           d.created_at
+    FROM workflows.workflow_dlq d
+    JOIN workflows_manager.project_workflows pw ON pw.workflow_definition_id = d.workflow_id
+    WHERE d.created_at > $1 AND d.created_at <= $2
+) events
+ORDER BY occurred_at`
+
+	rows, err := executor.Query(ctx, query,
+		from,
+		to,
+		string(domain.EventInstanceCompleted),
+		string(domain.EventInstanceFailed),
+		string(domain.EventDLQItemCreated),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query lifecycle events: %w", err)
+	}
+	defer rows.Close()
+
+	listModels, err := pgx.CollectRows(rows, pgx.RowToStructByName[eventModel])
+	if err != nil {
+		return nil, fmt.Errorf("collect lifecycle events: %w", err)
+	}
+
+	return toDomainEvents(listModels), nil
+}
+
+func (r *Repository) ListLongRunning(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	startedBefore time.Time,
+) ([]domain.LifecycleEvent, error) {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+SELECT $3::text                              AS type,
+       pw.project_id,
+       wi.workflow_id,
+       wi.id                                 AS instance_id,
+       NULL::bigint                          AS dlq_item_id,
+       NULL::text                            AS step_name,
+       wi.status,
+       wi.error,
+       COALESCE(wi.started_at, wi.created_at) AS occurred_at
+FROM workflows.workflow_instances wi
+JOIN workflows_manager.project_workflows pw ON pw.workflow_definition_id = wi.workflow_id
+WHERE pw.project_id = $1
+  AND wi.status = 'running'
+  AND COALESCE(wi.started_at, wi.created_at) <= $2
+ORDER BY occurred_at
+LIMIT $4`
+
+	rows, err := executor.Query(ctx, query,
+		projectID.Int(),
+		startedBefore,
+		string(domain.EventLongRunningInstance),
+		longRunningLimit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query long running instances: %w", err)
+	}
+	defer rows.Close()
+
+	listModels, err := pgx.CollectRows(rows, pgx.RowToStructByName[eventModel])
+	if err != nil {
+		return nil, fmt.Errorf("collect long running instances: %w", err)
+	}
+
+	return toDomainEvents(listModels), nil
+}
+
+//nolint:ireturn // it's ok here
+func (r *Repository) getExecutor(ctx context.Context) db.Tx {
+	if tx := db.TxFromContext(ctx); tx != nil {
+		return tx
+	}
+
+	return r.db
+}
+
+func toDomainEvents(listModels []eventModel) []domain.LifecycleEvent {
+	events := make([]domain.LifecycleEvent, 0, len(listModels))
+	for i := range listModels {
+		model := listModels[i]
+		events = append(events, domain.LifecycleEvent{
+			Type:       domain.LifecycleEventType(model.Type),
+			ProjectID:  domain.ProjectID(model.ProjectID),
+			WorkflowID: model.WorkflowID,
+			InstanceID: model.InstanceID,
+			DLQItemID:  model.DLQItemID,
+			StepName:   model.StepName,
+			Status:     model.Status,
+			Error:      model.Error,
+			OccurredAt: model.OccurredAt,
+		})
+	}
+
+	return events
+}
@@ -0,0 +1,95 @@
+package notificationchannels
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type channelModel struct {
+	ID                          int            `db:"id"`
+	ProjectID                   int            `db:"project_id"`
+	Name                        string         `db:"name"`
+	Kind                        string         `db:"kind"`
+	WebhookURLEncrypted         string         `db:"webhook_url_encrypted"`
+	Channel                     sql.NullString `db:"channel"`
+	Events                      []string       `db:"events"`
+	LongRunningThresholdSeconds int            `db:"long_running_threshold_seconds"`
+	Enabled                     bool           `db:"enabled"`
+	CreatedBy                   string         `db:"created_by"`
+	CreatedAt                   time.Time      `db:"created_at"`
+	UpdatedAt                   time.Time      `db:"updated_at"`
+}
+
+func (m *channelModel) toDomain() domain.NotificationChannel {
+	events := make([]domain.LifecycleEventType, 0, len(m.Events))
+	for _, event := range m.Events {
+		events = append(events, domain.LifecycleEventType(event))
+	}
+
+	return domain.NotificationChannel{
+		ID:                          domain.NotificationChannelID(m.ID),
+		ProjectID:                   domain.ProjectID(m.ProjectID),
+		Name:                        m.Name,
+		Kind:                        domain.NotificationChannelKind(m.Kind),
+		Channel:                     m.Channel.String,
+		Events:                      events,
+		LongRunningThresholdSeconds: m.LongRunningThresholdSeconds,
+		Enabled:                     m.Enabled,
+		CreatedBy:                   m.CreatedBy,
+		CreatedAt:                   m.CreatedAt,
+		UpdatedAt:                   m.UpdatedAt,
+		WebhookURLEncrypted:         m.WebhookURLEncrypted,
+	}
+}
+
+type messageModel struct {
+	ID            int64          `db:"id"`
+	ChannelID     int            `db:"channel_id"`
+	EventType     string         `db:"event_type"`
+	EventKey      string         `db:"event_key"`
+	Event         []byte         `db:"event"`
+	Status        string         `db:"status"`
+	Attempts      int            `db:"attempts"`
+	NextAttemptAt *time.Time     `db:"next_attempt_at"`
+	LastError     sql.NullString `db:"last_error"`
+	CreatedAt     time.Time      `db:"created_at"`
+	SentAt        *time.Time     `db:"sent_at"`
+}
+
+func (m *messageModel) toDomain() (domain.NotificationMessage, error) {
+	var event domain.LifecycleEvent
+	if err := json.Unmarshal(m.Event, &event); err != nil {
+		return domain.NotificationMessage{}, fmt.Errorf("unmarshal message %d event: %w", m.ID, err)
+	}
+
+	var lastError *string
+	if m.LastError.Valid {
+		lastError = &m.LastError.String
+	}
+
+	return domain.NotificationMessage{
+		ID:            m.ID,
+		ChannelID:     domain.NotificationChannelID(m.ChannelID),
+		EventType:     domain.LifecycleEventType(m.EventType),
+		EventKey:      m.EventKey,
+		Event:         event,
+		Status:        domain.NotificationMessageStatus(m.Status),
+		Attempts:      m.Attempts,
+		NextAttemptAt: m.NextAttemptAt,
+		LastError:     lastError,
+		CreatedAt:     m.CreatedAt,
+		SentAt:        m.SentAt,
+	}, nil
+}
+
+type dueMessageModel struct {
+	messageModel
+	ProjectID           int            `db:"project_id"`
+	Kind                string         `db:"kind"`
+	WebhookURLEncrypted string         `db:"webhook_url_encrypted"`
+	Channel             sql.NullString `db:"channel"`
+}
@@ -0,0 +1,395 @@
+package notificationchannels
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/repository/auditlog"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.NotificationChannelsRepository = (*Repository)(nil)
+
+const (
+	channelColumns = `id, project_id, name, kind, webhook_url_encrypted, channel, events,
+long_running_threshold_seconds, enabled, created_by, created_at, updated_at`
+	messageColumns = `m.id, m.channel_id, m.event_type, m.event_key, m.event, m.status, m.attempts,
+m.next_attempt_at, m.last_error, m.created_at, m.sent_at`
+)
+
+type Repository struct {
+	db db.Tx
+}
+
+func New(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		db: pool,
+	}
+}
+
+func (r *Repository) List(ctx context.Context, projectID domain.ProjectID) ([]domain.NotificationChannel, error) {
+	query := `
+SELECT ` + channelColumns + `
+FROM workflows_manager.notification_channels
+WHERE project_id = $1
+ORDER BY id`
+
+	return r.getMany(ctx, query, projectID.Int())
+}
+
+func (r *Repository) GetByID(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	id domain.NotificationChannelID,
+) (domain.NotificationChannel, error) {
+	executor := r.getExecutor(ctx)
+
+	query := `
+SELECT ` + channelColumns + `
+FROM workflows_manager.notification_channels
+WHERE project_id = $1 AND id = $2
+LIMIT 1`
+
+	rows, err := executor.Query(ctx, query, projectID.Int(), id.Int())
+	if err != nil {
+		return domain.NotificationChannel{}, fmt.Errorf("query notification channel: %w", err)
+	}
+	defer rows.Close()
+
+	model, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[channelModel])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.NotificationChannel{}, domain.ErrEntityNotFound
+		}
+
+		return domain.NotificationChannel{}, fmt.Errorf("collect notification channel: %w", err)
+	}
+
+	return model.toDomain(), nil
+}
+
+func (r *Repository) Create(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	dto domain.NotificationChannelDTO,
+	webhookURLEncrypted string,
+) (domain.NotificationChannelID, error) {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+INSERT INTO workflows_manager.notification_channels
+    (project_id, name, kind, webhook_url_encrypted, channel, events, long_running_threshold_seconds,
+     enabled, created_by)
+VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8, $9)
+RETURNING id`
+
+	var id int
+	err := executor.QueryRow(ctx, query,
+		projectID.Int(),
+		dto.Name,
+		string(dto.Kind),
+		webhookURLEncrypted,
+		dto.Channel,
+		eventsToStrings(dto.Events),
+		dto.LongRunningThresholdSeconds,
+		dto.Enabled,
+		appcontext.Username(ctx),
+	).Scan(&id)
+	if err != nil {
+		if db.IsUniqueViolation(err) {
+			return 0, domain.ErrEntityAlreadyExists
+		}
+
+		return 0, fmt.Errorf("insert notification channel: %w", err)
+	}
+
+	channelID := domain.NotificationChannelID(id)
+	err = auditlog.WriteLog(ctx, executor, domain.EntityNotificationChannel, channelID.String(), domain.ActionCreate, projectID)
+	if err != nil {
+		return 0, fmt.Errorf("write audit log: %w", err)
+	}
+
+	return channelID, nil
+}
+
+func (r *Repository) Update(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	id domain.NotificationChannelID,
+	dto domain.NotificationChannelDTO,
+	webhookURLEncrypted string,
+) error {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+UPDATE workflows_manager.notification_channels
+SET name = $3,
+    kind = $4,
+    webhook_url_encrypted = COALESCE(NULLIF($5, ''), webhook_url_encrypted),
+    channel = NULLIF($6, ''),
+    events = $7,
+    long_running_threshold_seconds = $8,
+    enabled = $9,
+    updated_at = NOW()
+WHERE project_id = $1 AND id = $2`
+
+	result, err := executor.Exec(ctx, query,
+		projectID.Int(),
+		id.Int(),
+		dto.Name,
+		string(dto.Kind),
+		webhookURLEncrypted,
+		dto.Channel,
+		eventsToStrings(dto.Events),
+		dto.LongRunningThresholdSeconds,
+		dto.Enabled,
+	)
+	if err != nil {
+		if db.IsUniqueViolation(err) {
+			return domain.ErrEntityAlreadyExists
+		}
+
+		return fmt.Errorf("update notification channel: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrEntityNotFound
+	}
+
+	err = auditlog.WriteLog(ctx, executor, domain.EntityNotificationChannel, id.String(), domain.ActionUpdate, projectID)
+	if err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) Delete(ctx context.Context, projectID domain.ProjectID, id domain.NotificationChannelID) error {
+	executor := r.getExecutor(ctx)
+
+	result, err := executor.Exec(ctx,
+		`DELETE FROM workflows_manager.notification_channels WHERE project_id = $1 AND id = $2`,
+		projectID.Int(), id.Int(),
+	)
+	if err != nil {
+		return fmt.Errorf("delete notification channel: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrEntityNotFound
+	}
+
+	err = auditlog.WriteLog(ctx, executor, domain.EntityNotificationChannel, id.String(), domain.ActionDelete, projectID)
+	if err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) ListSubscribed(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	eventType domain.LifecycleEventType,
+) ([]domain.NotificationChannel, error) {
+	query := `
+SELECT ` + channelColumns + `
+FROM workflows_manager.notification_channels
+WHERE ($1 = 0 OR project_id = $1) AND enabled AND $2 = ANY (events)
+ORDER BY id`
+
+	return r.getMany(ctx, query, projectID.Int(), string(eventType))
+}
+
+func (r *Repository) EnqueueMessage(ctx context.Context, message domain.NotificationMessage) (bool, error) {
+	executor := r.getExecutor(ctx)
+
+	event, err := json.Marshal(message.Event)
+	if err != nil {
+		return false, fmt.Errorf("marshal event: %w", err)
+	}
+
+	const query = `
+INSERT INTO workflows_manager.notification_channel_messages
+    (channel_id, event_type, event_key, event, status, next_attempt_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (channel_id, event_key) DO NOTHING`
+
+	result, err := executor.Exec(ctx, query,
+		message.ChannelID.Int(),
+		string(message.EventType),
+		message.EventKey,
+		event,
+		string(domain.NotificationMessagePending),
+		message.NextAttemptAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("insert notification message: %w", err)
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+func (r *Repository) ListDueMessages(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+) ([]domain.NotificationMessage, error) {
+	executor := r.getExecutor(ctx)
+
+	query := `
+SELECT ` + messageColumns + `, c.project_id, c.kind, c.webhook_url_encrypted, c.channel
+FROM workflows_manager.notification_channel_messages m
+JOIN workflows_manager.notification_channels c ON c.id = m.channel_id
+WHERE m.status = $1 AND m.next_attempt_at <= $2
+ORDER BY m.next_attempt_at
+LIMIT $3
+FOR UPDATE OF m SKIP LOCKED`
+
+	rows, err := executor.Query(ctx, query, string(domain.NotificationMessagePending), now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query due notification messages: %w", err)
+	}
+	defer rows.Close()
+
+	listModels, err := pgx.CollectRows(rows, pgx.RowToStructByName[dueMessageModel])
+	if err != nil {
+		return nil, fmt.Errorf("collect due notification messages: %w", err)
+	}
+
+	messages := make([]domain.NotificationMessage, 0, len(listModels))
+	for i := range listModels {
+		model := listModels[i]
+
+		message, err := model.toDomain()
+		if err != nil {
+			return nil, err
+		}
+
+		message.Channel = domain.NotificationChannel{
+			ID:                  domain.NotificationChannelID(model.ChannelID),
+			ProjectID:           domain.ProjectID(model.ProjectID),
+			Kind:                domain.NotificationChannelKind(model.Kind),
+			Channel:             model.Channel.String,
+			WebhookURLEncrypted: model.WebhookURLEncrypted,
+		}
+		messages = append(messages, message)
+	}
+
+	return messages, nil
+}
+
+func (r *Repository) SaveMessageAttempt(ctx context.Context, message domain.NotificationMessage) error {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+UPDATE workflows_manager.notification_channel_messages
+SET status = $2,
+    attempts = $3,
+    next_attempt_at = $4,
+    last_error = $5,
+    sent_at = $6
+WHERE id = $1`
+
+	_, err := executor.Exec(ctx, query,
+		message.ID,
+		string(message.Status),
+		message.Attempts,
+		message.NextAttemptAt,
+		message.LastError,
+		message.SentAt,
+	)
+	if err != nil {
+		return fmt.Errorf("update notification message: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) ListMessages(
+	ctx context.Context,
+	channelID domain.NotificationChannelID,
+	page, pageSize int,
+) ([]domain.NotificationMessage, int, error) {
+	executor := r.getExecutor(ctx)
+
+	offset := (page - 1) * pageSize
+
+	query := `
+SELECT ` + messageColumns + `
+FROM workflows_manager.notification_channel_messages m
+WHERE m.channel_id = $1
+ORDER BY m.created_at DESC, m.id DESC
+LIMIT $2 OFFSET $3`
+
+	listModels, total, err := db.QueryPage(ctx, executor, db.PageQuery{
+		CountSQL:  `SELECT COUNT(*) FROM workflows_manager.notification_channel_messages WHERE channel_id = $1`,
+		CountArgs: []any{channelID.Int()},
+		SQL:       query,
+		Args:      []any{channelID.Int(), pageSize, offset},
+	}, pgx.RowToStructByName[messageModel])
+	if err != nil {
+		return nil, 0, fmt.Errorf("list notification messages: %w", err)
+	}
+
+	messages := make([]domain.NotificationMessage, 0, len(listModels))
+	for i := range listModels {
+		message, err := listModels[i].toDomain()
+		if err != nil {
+			return nil, 0, err
+		}
+
+		messages = append(messages, message)
+	}
+
+	return messages, total, nil
+}
+
+func (r *Repository) getMany(ctx context.Context, query string, args ...any) ([]domain.NotificationChannel, error) {
+	executor := r.getExecutor(ctx)
+
+	rows, err := executor.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query notification channels: %w", err)
+	}
+	defer rows.Close()
+
+	listModels, err := pgx.CollectRows(rows, pgx.RowToStructByName[channelModel])
+	if err != nil {
+		return nil, fmt.Errorf("collect notification channels: %w", err)
+	}
+
+	channels := make([]domain.NotificationChannel, 0, len(listModels))
+	for i := range listModels {
+		channels = append(channels, listModels[i].toDomain())
+	}
+
+	return channels, nil
+}
+
+//nolint:ireturn // it's ok here
+func (r *Repository) getExecutor(ctx context.Context) db.Tx {
+	if tx := db.TxFromContext(ctx); tx != nil {
+		return tx
+	}
+
+	return r.db
+}
+
+func eventsToStrings(events []domain.LifecycleEventType) []string {
+	result := make([]string, 0, len(events))
+	for _, event := range events {
+		result = append(result, string(event))
+	}
+
+	return result
+}
@@ -2,9 +2,13 @@ package productinfo
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"github.com/rom8726/floxy-manager/internal/domain"
 	"github.com/rom8726/floxy-manager/pkg/db"
 )
 
@@ -29,7 +33,11 @@ func (r *Repository) GetClientID(ctx context.Context) (string, error) {
 
 	err := row.Scan(&clientID)
 	if err != nil {
-		return "", err
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", domain.ErrEntityNotFound
+		}
+
+		return "", fmt.Errorf("query client ID: %w", err)
 	}
 
 	return clientID, nil
@@ -141,17 +141,31 @@ ORDER BY p.id
 func (r *Repository) Update(ctx context.Context, id domain.ProjectID, name, description string) error {
 	executor := r.getExecutor(ctx)
 
+	current, err := r.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("get project: %w", err)
+	}
+
 	const query = `
 UPDATE  workflows_manager.projects
 	SET name = $1, description = $2, updated_at = NOW()
 WHERE id = $3`
 
-	_, err := executor.Exec(ctx, query, name, description, id.Int())
+	_, err = executor.Exec(ctx, query, name, description, id.Int())
 	if err != nil {
 		return fmt.Errorf("failed to update project: %w", err)
 	}
 
-	if err := auditlog.WriteLog(ctx, executor, domain.EntityProject, strconv.Itoa(id.Int()), domain.ActionUpdate, id); err != nil {
+	changes, err := auditlog.Diff(
+		map[string]string{"name": current.Name, "description": current.Description},
+		map[string]string{"name": name, "description": description},
+	)
+	if err != nil {
+		return fmt.Errorf("diff project: %w", err)
+	}
+
+	err = auditlog.WriteChangeLog(ctx, executor, domain.EntityProject, strconv.Itoa(id.Int()), domain.ActionUpdate, id, changes)
+	if err != nil {
 		return fmt.Errorf("write audit log: %w", err)
 	}
 
@@ -252,6 +266,11 @@ func (r *Repository) Count(ctx context.Context) (uint, error) {
 func (r *Repository) Delete(ctx context.Context, id domain.ProjectID) error {
 	executor := r.getExecutor(ctx)
 
+	// The entry is written first, the audit log keeps it with the project reference cleared
+	if err := auditlog.WriteLog(ctx, executor, domain.EntityProject, strconv.Itoa(id.Int()), domain.ActionDelete, id); err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+
 	const query = `DELETE FROM workflows_manager.projects WHERE id = $1`
 
 	result, err := executor.Exec(ctx, query, id.Int())
@@ -0,0 +1,30 @@
+package projectsettings
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type settingsModel struct {
+	ProjectID int       `db:"project_id"`
+	Settings  []byte    `db:"settings"`
+	UpdatedBy string    `db:"updated_by"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func (m *settingsModel) toDomain() (domain.ProjectSettings, error) {
+	settings := domain.ProjectSettings{
+		ProjectID: domain.ProjectID(m.ProjectID),
+		UpdatedBy: m.UpdatedBy,
+		UpdatedAt: &m.UpdatedAt,
+	}
+
+	if err := json.Unmarshal(m.Settings, &settings.ProjectSettingsValues); err != nil {
+		return domain.ProjectSettings{}, fmt.Errorf("unmarshal project %d settings: %w", m.ProjectID, err)
+	}
+
+	return settings, nil
+}
@@ -0,0 +1,129 @@
+package projectsettings
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/repository/auditlog"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.ProjectSettingsRepository = (*Repository)(nil)
+
+type Repository struct {
+	db db.Tx
+}
+
+func New(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		db: pool,
+	}
+}
+
+func (r *Repository) Get(ctx context.Context, projectID domain.ProjectID) (domain.ProjectSettings, error) {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+SELECT project_id, settings, updated_by, updated_at
+FROM workflows_manager.project_settings
+WHERE project_id = $1`
+
+	rows, err := executor.Query(ctx, query, projectID.Int())
+	if err != nil {
+		return domain.ProjectSettings{}, fmt.Errorf("query project settings: %w", err)
+	}
+	defer rows.Close()
+
+	model, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[settingsModel])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ProjectSettings{}, domain.ErrEntityNotFound
+		}
+
+		return domain.ProjectSettings{}, fmt.Errorf("collect project settings: %w", err)
+	}
+
+	return model.toDomain()
+}
+
+func (r *Repository) List(ctx context.Context) ([]domain.ProjectSettings, error) {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+SELECT project_id, settings, updated_by, updated_at
+FROM workflows_manager.project_settings
+ORDER BY project_id`
+
+	rows, err := executor.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("query project settings: %w", err)
+	}
+	defer rows.Close()
+
+	listModels, err := pgx.CollectRows(rows, pgx.RowToStructByName[settingsModel])
+	if err != nil {
+		return nil, fmt.Errorf("collect project settings: %w", err)
+	}
+
+	settings := make([]domain.ProjectSettings, 0, len(listModels))
+	for i := range listModels {
+		item, err := listModels[i].toDomain()
+		if err != nil {
+			return nil, err
+		}
+		settings = append(settings, item)
+	}
+
+	return settings, nil
+}
+
+func (r *Repository) Save(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	values domain.ProjectSettingsValues,
+) error {
+	executor := r.getExecutor(ctx)
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		return fmt.Errorf("marshal project settings: %w", err)
+	}
+
+	const query = `
+INSERT INTO workflows_manager.project_settings (project_id, settings, updated_by)
+VALUES ($1, $2, $3)
+ON CONFLICT (project_id) DO UPDATE
+SET settings = EXCLUDED.settings,
+    updated_by = EXCLUDED.updated_by,
+    updated_at = NOW()`
+
+	_, err = executor.Exec(ctx, query, projectID.Int(), data, appcontext.Username(ctx))
+	if err != nil {
+		return fmt.Errorf("save project settings: %w", err)
+	}
+
+	err = auditlog.WriteLog(ctx, executor, domain.EntityProjectSettings, projectID.String(),
+		domain.ActionUpdate, projectID)
+	if err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+
+	return nil
+}
+
+//nolint:ireturn // it's ok here
+func (r *Repository) getExecutor(ctx context.Context) db.Tx {
+	if tx := db.TxFromContext(ctx); tx != nil {
+		return tx
+	}
+
+	return r.db
+}
@@ -0,0 +1,154 @@
+package rbac
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+// GlobalRoles repository implementation.
+// It implements contract.GlobalRolesRepository.
+type GlobalRoles struct {
+	db db.Tx
+}
+
+func NewGlobalRoles(pool *pgxpool.Pool) *GlobalRoles {
+	return &GlobalRoles{db: pool}
+}
+
+func (r *GlobalRoles) UserHasPermission(
+	ctx context.Context,
+	userID domain.UserID,
+	key domain.PermKey,
+) (bool, error) {
+	exec := getExecutor(ctx, r.db)
+
+	const query = `select exists(
+select 1 from  workflows_manager.global_role_assignments gra
+join  workflows_manager.role_permissions rp on rp.role_id = gra.role_id
+join  workflows_manager.permissions p on p.id = rp.permission_id
+where gra.user_id = $1 and p.key = $2
+)`
+
+	var has bool
+	if err := exec.QueryRow(ctx, query, userID, string(key)).Scan(&has); err != nil {
+		return false, fmt.Errorf("global role has permission: %w", err)
+	}
+
+	return has, nil
+}
+
+func (r *GlobalRoles) List(ctx context.Context) ([]domain.GlobalRoleAssignment, error) {
+	exec := getExecutor(ctx, r.db)
+
+	const query = `
+select gra.id, gra.user_id, gra.role_id, r.key as role_key, r.name as role_name, gra.created_at
+from  workflows_manager.global_role_assignments gra
+join  workflows_manager.roles r on r.id = gra.role_id
+order by gra.user_id, r.key`
+
+	rows, err := exec.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("list global role assignments: %w", err)
+	}
+	defer rows.Close()
+
+	models, err := pgx.CollectRows(rows, pgx.RowToStructByName[globalRoleAssignmentModel])
+	if err != nil {
+		return nil, fmt.Errorf("collect global role assignments: %w", err)
+	}
+
+	res := make([]domain.GlobalRoleAssignment, 0, len(models))
+	for _, m := range models {
+		res = append(res, m.toDomain())
+	}
+
+	return res, nil
+}
+
+func (r *GlobalRoles) ListForUser(ctx context.Context, userID domain.UserID) ([]domain.GlobalRoleAssignment, error) {
+	exec := getExecutor(ctx, r.db)
+
+	const query = `
+select gra.id, gra.user_id, gra.role_id, r.key as role_key, r.name as role_name, gra.created_at
+from  workflows_manager.global_role_assignments gra
+join  workflows_manager.roles r on r.id = gra.role_id
+where gra.user_id = $1
+order by r.key`
+
+	rows, err := exec.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list user global role assignments: %w", err)
+	}
+	defer rows.Close()
+
+	models, err := pgx.CollectRows(rows, pgx.RowToStructByName[globalRoleAssignmentModel])
+	if err != nil {
+		return nil, fmt.Errorf("collect global role assignments: %w", err)
+	}
+
+	res := make([]domain.GlobalRoleAssignment, 0, len(models))
+	for _, m := range models {
+		res = append(res, m.toDomain())
+	}
+
+	return res, nil
+}
+
+func (r *GlobalRoles) Create(
+	ctx context.Context,
+	userID domain.UserID,
+	roleID domain.RoleID,
+) (domain.GlobalRoleAssignment, error) {
+	exec := getExecutor(ctx, r.db)
+
+	const query = `
+with ins as (
+	insert into  workflows_manager.global_role_assignments (user_id, role_id)
+	values ($1, $2)
+	returning id, user_id, role_id, created_at
+)
+select ins.id, ins.user_id, ins.role_id, r.key as role_key, r.name as role_name, ins.created_at
+from ins join  workflows_manager.roles r on r.id = ins.role_id`
+
+	rows, err := exec.Query(ctx, query, userID, roleID)
+	if err != nil {
+		return domain.GlobalRoleAssignment{}, fmt.Errorf("insert global role assignment: %w", err)
+	}
+	defer rows.Close()
+
+	model, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[globalRoleAssignmentModel])
+	if err != nil {
+		if db.IsUniqueViolation(err) {
+			return domain.GlobalRoleAssignment{}, domain.ErrEntityAlreadyExists
+		}
+
+		return domain.GlobalRoleAssignment{}, fmt.Errorf("insert global role assignment: %w", err)
+	}
+
+	return model.toDomain(), nil
+}
+
+func (r *GlobalRoles) Delete(ctx context.Context, id domain.GlobalRoleAssignmentID) error {
+	exec := getExecutor(ctx, r.db)
+
+	const query = `delete from  workflows_manager.global_role_assignments where id = $1`
+
+	ct, err := exec.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("delete global role assignment: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return domain.ErrEntityNotFound
+	}
+
+	return nil
+}
+
+var _ contract.GlobalRolesRepository = (*GlobalRoles)(nil)
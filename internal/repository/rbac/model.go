@@ -25,18 +25,50 @@ func (role *roleModel) toDomain() domain.Role {
 }
 
 type permissionModel struct {
-	ID   string `db:"id"`
-	Key  string `db:"key"`
-	Name string `db:"name"`
+	ID          string `db:"id"`
+	Key         string `db:"key"`
+	Name        string `db:"name"`
+	Description string `db:"description"`
 }
 
 func (p *permissionModel) toDomain() domain.Permission {
-	return domain.Permission{ID: domain.PermissionID(p.ID), Key: domain.PermKey(p.Key), Name: p.Name}
+	return domain.Permission{
+		ID:          domain.PermissionID(p.ID),
+		Key:         domain.PermKey(p.Key),
+		Name:        p.Name,
+		Description: p.Description,
+	}
 }
 
 type membershipModel struct {
+	ID         int        `db:"id"`
+	ProjectID  int        `db:"project_id"`
+	UserID     int        `db:"user_id"`
+	RoleID     string     `db:"role_id"`
+	RoleKey    string     `db:"role_key"`
+	RoleName   string     `db:"role_name"`
+	Source     string     `db:"source"`
+	ValidUntil *time.Time `db:"valid_until"`
+	CreatedAt  time.Time  `db:"created_at"`
+}
+
+func (m *membershipModel) toDomain() domain.ProjectMembership {
+	return domain.ProjectMembership{
+		ID:         domain.MembershipID(m.ID),
+		UserID:     domain.UserID(m.UserID),
+		ProjectID:  domain.ProjectID(m.ProjectID),
+		RoleID:     domain.RoleID(m.RoleID),
+		RoleKey:    m.RoleKey,
+		RoleName:   m.RoleName,
+		Source:     domain.MembershipSource(m.Source),
+		ValidUntil: m.ValidUntil,
+		CreatedAt:  m.CreatedAt,
+	}
+}
+
+type tenantMembershipModel struct {
 	ID        int       `db:"id"`
-	ProjectID int       `db:"project_id"`
+	TenantID  int       `db:"tenant_id"`
 	UserID    int       `db:"user_id"`
 	RoleID    string    `db:"role_id"`
 	RoleKey   string    `db:"role_key"`
@@ -44,11 +76,31 @@ type membershipModel struct {
 	CreatedAt time.Time `db:"created_at"`
 }
 
-func (m *membershipModel) toDomain() domain.ProjectMembership {
-	return domain.ProjectMembership{
-		ID:        domain.MembershipID(m.ID),
+func (m *tenantMembershipModel) toDomain() domain.TenantMembership {
+	return domain.TenantMembership{
+		ID:        domain.TenantMembershipID(m.ID),
+		TenantID:  domain.TenantID(m.TenantID),
+		UserID:    domain.UserID(m.UserID),
+		RoleID:    domain.RoleID(m.RoleID),
+		RoleKey:   m.RoleKey,
+		RoleName:  m.RoleName,
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+type globalRoleAssignmentModel struct {
+	ID        int       `db:"id"`
+	UserID    int       `db:"user_id"`
+	RoleID    string    `db:"role_id"`
+	RoleKey   string    `db:"role_key"`
+	RoleName  string    `db:"role_name"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func (m *globalRoleAssignmentModel) toDomain() domain.GlobalRoleAssignment {
+	return domain.GlobalRoleAssignment{
+		ID:        domain.GlobalRoleAssignmentID(m.ID),
 		UserID:    domain.UserID(m.UserID),
-		ProjectID: domain.ProjectID(m.ProjectID),
 		RoleID:    domain.RoleID(m.RoleID),
 		RoleKey:   m.RoleKey,
 		RoleName:  m.RoleName,
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -164,7 +165,7 @@ func (r *Permissions) ListForAllRoles(ctx context.Context) (map[domain.Role][]do
 
 	const query = `
 		select r.id as id, r.key as key, r.name as name, r.description as description, r.created_at as created_at,
-		       p.id as p_id, p.key as p_key, p.name as p_name
+		       p.id as p_id, p.key as p_key, p.name as p_name, p.description as p_description
 		from  workflows_manager.roles r
 		left join  workflows_manager.role_permissions rp on rp.role_id = r.id
 		left join  workflows_manager.permissions p on p.id = rp.permission_id
@@ -179,9 +180,10 @@ func (r *Permissions) ListForAllRoles(ctx context.Context) (map[domain.Role][]do
 
 	type row struct {
 		roleModel
-		P_ID   *string `db:"p_id"`
-		P_Key  *string `db:"p_key"`
-		P_Name *string `db:"p_name"`
+		P_ID          *string `db:"p_id"`
+		P_Key         *string `db:"p_key"`
+		P_Name        *string `db:"p_name"`
+		P_Description *string `db:"p_description"`
 	}
 
 	items, err := pgx.CollectRows(rows, pgx.RowToStructByName[row])
@@ -196,7 +198,12 @@ func (r *Permissions) ListForAllRoles(ctx context.Context) (map[domain.Role][]do
 			result[role] = []domain.Permission{}
 		}
 		if it.P_ID != nil {
-			perm := domain.Permission{ID: domain.PermissionID(*it.P_ID), Key: domain.PermKey(*it.P_Key), Name: *it.P_Name}
+			perm := domain.Permission{
+				ID:          domain.PermissionID(*it.P_ID),
+				Key:         domain.PermKey(*it.P_Key),
+				Name:        *it.P_Name,
+				Description: *it.P_Description,
+			}
 			result[role] = append(result[role], perm)
 		}
 	}
@@ -236,6 +243,50 @@ where rp.role_id = $1 and p.key = $2
 	return has, nil
 }
 
+// RoleHasPermissions checks several permissions of a role with one query.
+// The result has an entry for every requested key.
+func (r *Permissions) RoleHasPermissions(
+	ctx context.Context,
+	roleID string,
+	keys ...domain.PermKey,
+) (map[domain.PermKey]bool, error) {
+	result := make(map[domain.PermKey]bool, len(keys))
+	if len(keys) == 0 {
+		return result, nil
+	}
+
+	exec := getExecutor(ctx, r.db)
+
+	keyStrings := make([]string, 0, len(keys))
+	for _, key := range keys {
+		result[key] = false
+		keyStrings = append(keyStrings, string(key))
+	}
+
+	const query = `
+select p.key
+from  workflows_manager.role_permissions rp
+join  workflows_manager.permissions p on p.id = rp.permission_id
+where rp.role_id = $1 and p.key = any($2)`
+
+	rows, err := exec.Query(ctx, query, roleID, keyStrings)
+	if err != nil {
+		return nil, fmt.Errorf("role has permissions: %w", err)
+	}
+	defer rows.Close()
+
+	granted, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("collect role permissions: %w", err)
+	}
+
+	for _, key := range granted {
+		result[domain.PermKey(key)] = true
+	}
+
+	return result, nil
+}
+
 var _ contract.PermissionsRepository = (*Permissions)(nil)
 
 // Memberships repository implementation.
@@ -255,7 +306,10 @@ func (r *Memberships) GetForUserProject(
 ) (string, error) { // roleID
 	exec := getExecutor(ctx, r.db)
 
-	const query = `select role_id from  workflows_manager.memberships where project_id = $1 and user_id = $2 limit 1`
+	const query = `
+select role_id from  workflows_manager.memberships
+where project_id = $1 and user_id = $2 and (valid_until is null or valid_until > now())
+limit 1`
 
 	var roleID string
 	if err := exec.QueryRow(ctx, query, projectID, userID).Scan(&roleID); err != nil {
@@ -276,7 +330,8 @@ func (r *Memberships) ListForProject(
 	exec := getExecutor(ctx, r.db)
 
 	const query = `
-select m.id, m.project_id, m.user_id, m.role_id, r.key as role_key, r.name as role_name, m.created_at
+select m.id, m.project_id, m.user_id, m.role_id, r.key as role_key, r.name as role_name, m.source,
+	m.valid_until, m.created_at
 from  workflows_manager.memberships m
 join  workflows_manager.roles r on r.id = m.role_id
 where m.project_id = $1
@@ -301,24 +356,65 @@ order by m.created_at desc`
 	return res, nil
 }
 
+func (r *Memberships) ListForUser(ctx context.Context, userID domain.UserID) ([]domain.ProjectMembership, error) {
+	exec := getExecutor(ctx, r.db)
+
+	const query = `
+select m.id, m.project_id, m.user_id, m.role_id, r.key as role_key, r.name as role_name, m.source,
+	m.valid_until, m.created_at
+from  workflows_manager.memberships m
+join  workflows_manager.roles r on r.id = m.role_id
+where m.user_id = $1
+order by m.project_id`
+
+	rows, err := exec.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list user memberships: %w", err)
+	}
+	defer rows.Close()
+
+	models, err := pgx.CollectRows(rows, pgx.RowToStructByName[membershipModel])
+	if err != nil {
+		return nil, fmt.Errorf("collect memberships: %w", err)
+	}
+
+	res := make([]domain.ProjectMembership, 0, len(models))
+	for _, m := range models {
+		res = append(res, m.toDomain())
+	}
+
+	return res, nil
+}
+
 func (r *Memberships) Create(
 	ctx context.Context,
 	projectID domain.ProjectID,
 	userID domain.UserID,
 	roleID domain.RoleID,
+) (domain.ProjectMembership, error) {
+	return r.CreateWithSource(ctx, projectID, userID, roleID, domain.MembershipSourceManual)
+}
+
+func (r *Memberships) CreateWithSource(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	userID domain.UserID,
+	roleID domain.RoleID,
+	source domain.MembershipSource,
 ) (domain.ProjectMembership, error) {
 	exec := getExecutor(ctx, r.db)
 
 	const query = `
 with ins as (
-	insert into  workflows_manager.memberships (project_id, user_id, role_id)
-	values ($1, $2, $3)
-	returning id, project_id, user_id, role_id, created_at
+	insert into  workflows_manager.memberships (project_id, user_id, role_id, source)
+	values ($1, $2, $3, $4)
+	returning id, project_id, user_id, role_id, source, valid_until, created_at
 )
-select ins.id, ins.project_id, ins.user_id, ins.role_id, r.key as role_key, r.name as role_name, ins.created_at
+select ins.id, ins.project_id, ins.user_id, ins.role_id, r.key as role_key, r.name as role_name,
+	ins.source, ins.valid_until, ins.created_at
 from ins join  workflows_manager.roles r on r.id = ins.role_id`
 
-	row := exec.QueryRow(ctx, query, projectID, userID, roleID)
+	row := exec.QueryRow(ctx, query, projectID, userID, roleID, source)
 	var model membershipModel
 	if err := row.Scan(
 		&model.ID,
@@ -327,6 +423,8 @@ from ins join  workflows_manager.roles r on r.id = ins.role_id`
 		&model.RoleID,
 		&model.RoleKey,
 		&model.RoleName,
+		&model.Source,
+		&model.ValidUntil,
 		&model.CreatedAt,
 	); err != nil {
 		return domain.ProjectMembership{}, fmt.Errorf("insert membership: %w", err)
@@ -343,7 +441,8 @@ func (r *Memberships) Get(
 	exec := getExecutor(ctx, r.db)
 
 	const query = `
-select m.id, m.project_id, m.user_id, m.role_id, r.key as role_key, r.name as role_name, m.created_at
+select m.id, m.project_id, m.user_id, m.role_id, r.key as role_key, r.name as role_name, m.source,
+	m.valid_until, m.created_at
 from  workflows_manager.memberships m
 join  workflows_manager.roles r on r.id = m.role_id
 where m.project_id = $1 and m.id = $2
@@ -358,6 +457,8 @@ limit 1`
 		&model.RoleID,
 		&model.RoleKey,
 		&model.RoleName,
+		&model.Source,
+		&model.ValidUntil,
 		&model.CreatedAt,
 	); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -382,9 +483,10 @@ func (r *Memberships) Update(
 with upd as (
 	update  workflows_manager.memberships set role_id = $3, updated_at = now()
 	where project_id = $1 and id = $2
-	returning id, project_id, user_id, role_id, created_at
+	returning id, project_id, user_id, role_id, source, valid_until, created_at
 )
-select upd.id, upd.project_id, upd.user_id, upd.role_id, r.key as role_key, r.name as role_name, upd.created_at
+select upd.id, upd.project_id, upd.user_id, upd.role_id, r.key as role_key, r.name as role_name,
+	upd.source, upd.valid_until, upd.created_at
 from upd join  workflows_manager.roles r on r.id = upd.role_id`
 
 	row := exec.QueryRow(ctx, query, projectID, membershipID, roleID)
@@ -396,6 +498,8 @@ from upd join  workflows_manager.roles r on r.id = upd.role_id`
 		&model.RoleID,
 		&model.RoleKey,
 		&model.RoleName,
+		&model.Source,
+		&model.ValidUntil,
 		&model.CreatedAt,
 	); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -424,6 +528,60 @@ func (r *Memberships) Delete(ctx context.Context, projectID domain.ProjectID, me
 	return nil
 }
 
+func (r *Memberships) SetValidUntil(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	membershipID domain.MembershipID,
+	validUntil *time.Time,
+) error {
+	exec := getExecutor(ctx, r.db)
+
+	const query = `
+update  workflows_manager.memberships set valid_until = $3, updated_at = now()
+where project_id = $1 and id = $2`
+
+	ct, err := exec.Exec(ctx, query, projectID, membershipID, validUntil)
+	if err != nil {
+		return fmt.Errorf("set membership valid until: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return domain.ErrEntityNotFound
+	}
+
+	return nil
+}
+
+// ListExpired returns time-bound memberships whose valid_until is not after now.
+func (r *Memberships) ListExpired(ctx context.Context, now time.Time) ([]domain.ProjectMembership, error) {
+	exec := getExecutor(ctx, r.db)
+
+	const query = `
+select m.id, m.project_id, m.user_id, m.role_id, r.key as role_key, r.name as role_name, m.source,
+	m.valid_until, m.created_at
+from  workflows_manager.memberships m
+join  workflows_manager.roles r on r.id = m.role_id
+where m.valid_until <= $1
+order by m.valid_until`
+
+	rows, err := exec.Query(ctx, query, now)
+	if err != nil {
+		return nil, fmt.Errorf("list expired memberships: %w", err)
+	}
+	defer rows.Close()
+
+	models, err := pgx.CollectRows(rows, pgx.RowToStructByName[membershipModel])
+	if err != nil {
+		return nil, fmt.Errorf("collect memberships: %w", err)
+	}
+
+	res := make([]domain.ProjectMembership, 0, len(models))
+	for _, m := range models {
+		res = append(res, m.toDomain())
+	}
+
+	return res, nil
+}
+
 var _ contract.MembershipsRepository = (*Memberships)(nil)
 
 // helper to get tx from context
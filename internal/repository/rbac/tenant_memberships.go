@@ -0,0 +1,237 @@
+package rbac
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+// TenantMemberships repository implementation.
+// It implements contract.TenantMembershipsRepository.
+type TenantMemberships struct {
+	db db.Tx
+}
+
+func NewTenantMemberships(pool *pgxpool.Pool) *TenantMemberships {
+	return &TenantMemberships{db: pool}
+}
+
+func (r *TenantMemberships) GetForUserProject(
+	ctx context.Context,
+	userID domain.UserID,
+	projectID domain.ProjectID,
+) (string, error) { // roleID
+	exec := getExecutor(ctx, r.db)
+
+	const query = `
+select tm.role_id
+from  workflows_manager.tenant_memberships tm
+join  workflows_manager.projects p on p.tenant_id = tm.tenant_id
+where p.id = $1 and tm.user_id = $2
+limit 1`
+
+	var roleID string
+	if err := exec.QueryRow(ctx, query, projectID, userID).Scan(&roleID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("get tenant membership for user/project: %w", err)
+	}
+
+	return roleID, nil
+}
+
+func (r *TenantMemberships) GetForUserTenant(
+	ctx context.Context,
+	userID domain.UserID,
+	tenantID domain.TenantID,
+) (string, error) { // roleID
+	exec := getExecutor(ctx, r.db)
+
+	const query = `
+select role_id
+from  workflows_manager.tenant_memberships
+where tenant_id = $1 and user_id = $2`
+
+	var roleID string
+	if err := exec.QueryRow(ctx, query, tenantID, userID).Scan(&roleID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("get tenant membership for user/tenant: %w", err)
+	}
+
+	return roleID, nil
+}
+
+// GetMembershipForUserProject returns the tenant membership of the user in the tenant of the project.
+func (r *TenantMemberships) GetMembershipForUserProject(
+	ctx context.Context,
+	userID domain.UserID,
+	projectID domain.ProjectID,
+) (domain.TenantMembership, error) {
+	exec := getExecutor(ctx, r.db)
+
+	const query = `
+select tm.id, tm.tenant_id, tm.user_id, tm.role_id, r.key as role_key, r.name as role_name, tm.created_at
+from  workflows_manager.tenant_memberships tm
+join  workflows_manager.projects p on p.tenant_id = tm.tenant_id
+join  workflows_manager.roles r on r.id = tm.role_id
+where p.id = $1 and tm.user_id = $2
+limit 1`
+
+	rows, err := exec.Query(ctx, query, projectID, userID)
+	if err != nil {
+		return domain.TenantMembership{}, fmt.Errorf("get tenant membership for user/project: %w", err)
+	}
+	defer rows.Close()
+
+	model, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[tenantMembershipModel])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.TenantMembership{}, domain.ErrEntityNotFound
+		}
+
+		return domain.TenantMembership{}, fmt.Errorf("get tenant membership for user/project: %w", err)
+	}
+
+	return model.toDomain(), nil
+}
+
+// ListProjectRolesForUser returns the role the user has through tenant memberships in every project
+// of their tenants.
+func (r *TenantMemberships) ListProjectRolesForUser(
+	ctx context.Context,
+	userID domain.UserID,
+) (map[domain.ProjectID]string, error) {
+	exec := getExecutor(ctx, r.db)
+
+	const query = `
+select p.id, tm.role_id
+from  workflows_manager.tenant_memberships tm
+join  workflows_manager.projects p on p.tenant_id = tm.tenant_id
+where tm.user_id = $1`
+
+	rows, err := exec.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list tenant project roles for user: %w", err)
+	}
+	defer rows.Close()
+
+	res := make(map[domain.ProjectID]string)
+	for rows.Next() {
+		var (
+			projectID int
+			roleID    string
+		)
+		if err := rows.Scan(&projectID, &roleID); err != nil {
+			return nil, fmt.Errorf("scan tenant project role: %w", err)
+		}
+
+		res[domain.ProjectID(projectID)] = roleID
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate tenant project roles: %w", err)
+	}
+
+	return res, nil
+}
+
+func (r *TenantMemberships) ListForTenant(
+	ctx context.Context,
+	tenantID domain.TenantID,
+) ([]domain.TenantMembership, error) {
+	exec := getExecutor(ctx, r.db)
+
+	const query = `
+select tm.id, tm.tenant_id, tm.user_id, tm.role_id, r.key as role_key, r.name as role_name, tm.created_at
+from  workflows_manager.tenant_memberships tm
+join  workflows_manager.roles r on r.id = tm.role_id
+where tm.tenant_id = $1
+order by tm.created_at desc`
+
+	rows, err := exec.Query(ctx, query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("list tenant memberships: %w", err)
+	}
+	defer rows.Close()
+
+	models, err := pgx.CollectRows(rows, pgx.RowToStructByName[tenantMembershipModel])
+	if err != nil {
+		return nil, fmt.Errorf("collect tenant memberships: %w", err)
+	}
+
+	res := make([]domain.TenantMembership, 0, len(models))
+	for _, m := range models {
+		res = append(res, m.toDomain())
+	}
+
+	return res, nil
+}
+
+func (r *TenantMemberships) Create(
+	ctx context.Context,
+	tenantID domain.TenantID,
+	userID domain.UserID,
+	roleID domain.RoleID,
+) (domain.TenantMembership, error) {
+	exec := getExecutor(ctx, r.db)
+
+	const query = `
+with ins as (
+	insert into  workflows_manager.tenant_memberships (tenant_id, user_id, role_id)
+	values ($1, $2, $3)
+	returning id, tenant_id, user_id, role_id, created_at
+)
+select ins.id, ins.tenant_id, ins.user_id, ins.role_id, r.key as role_key, r.name as role_name, ins.created_at
+from ins join  workflows_manager.roles r on r.id = ins.role_id`
+
+	rows, err := exec.Query(ctx, query, tenantID, userID, roleID)
+	if err != nil {
+		return domain.TenantMembership{}, fmt.Errorf("insert tenant membership: %w", err)
+	}
+	defer rows.Close()
+
+	model, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[tenantMembershipModel])
+	if err != nil {
+		if db.IsUniqueViolation(err) {
+			return domain.TenantMembership{}, domain.ErrEntityAlreadyExists
+		}
+
+		return domain.TenantMembership{}, fmt.Errorf("insert tenant membership: %w", err)
+	}
+
+	return model.toDomain(), nil
+}
+
+func (r *TenantMemberships) Delete(
+	ctx context.Context,
+	tenantID domain.TenantID,
+	id domain.TenantMembershipID,
+) error {
+	exec := getExecutor(ctx, r.db)
+
+	const query = `delete from  workflows_manager.tenant_memberships where tenant_id = $1 and id = $2`
+
+	ct, err := exec.Exec(ctx, query, tenantID, id)
+	if err != nil {
+		return fmt.Errorf("delete tenant membership: %w", err)
+	}
+	if ct.RowsAffected() == 0 {
+		return domain.ErrEntityNotFound
+	}
+
+	return nil
+}
+
+var _ contract.TenantMembershipsRepository = (*TenantMemberships)(nil)
@@ -0,0 +1,98 @@
+package recoverycodes
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.RecoveryCodesRepository = (*Repository)(nil)
+
+type Repository struct {
+	db db.Tx
+}
+
+func New(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		db: pool,
+	}
+}
+
+func (r *Repository) Replace(ctx context.Context, userID domain.UserID, codeHashes []string) error {
+	executor := r.getExecutor(ctx)
+
+	// a single statement keeps the old codes valid if the insert fails
+	const query = `
+WITH deleted AS (
+    DELETE FROM workflows_manager.two_fa_recovery_codes WHERE user_id = $1
+)
+INSERT INTO workflows_manager.two_fa_recovery_codes (user_id, code_hash)
+SELECT $1, unnest($2::text[])`
+
+	if _, err := executor.Exec(ctx, query, int(userID), codeHashes); err != nil {
+		return fmt.Errorf("replace recovery codes: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) Use(ctx context.Context, userID domain.UserID, codeHash string) error {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+UPDATE workflows_manager.two_fa_recovery_codes
+SET used_at = NOW()
+WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL`
+
+	result, err := executor.Exec(ctx, query, int(userID), codeHash)
+	if err != nil {
+		return fmt.Errorf("use recovery code: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrEntityNotFound
+	}
+
+	return nil
+}
+
+func (r *Repository) CountUnused(ctx context.Context, userID domain.UserID) (int, error) {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+SELECT COUNT(*)
+FROM workflows_manager.two_fa_recovery_codes
+WHERE user_id = $1 AND used_at IS NULL`
+
+	var count int
+	if err := executor.QueryRow(ctx, query, int(userID)).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count recovery codes: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *Repository) DeleteAll(ctx context.Context, userID domain.UserID) error {
+	executor := r.getExecutor(ctx)
+
+	_, err := executor.Exec(ctx, `DELETE FROM workflows_manager.two_fa_recovery_codes WHERE user_id = $1`, int(userID))
+	if err != nil {
+		return fmt.Errorf("delete recovery codes: %w", err)
+	}
+
+	return nil
+}
+
+//nolint:ireturn // it's ok here
+func (r *Repository) getExecutor(ctx context.Context) db.Tx {
+	if tx := db.TxFromContext(ctx); tx != nil {
+		return tx
+	}
+
+	return r.db
+}
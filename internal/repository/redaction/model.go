@@ -0,0 +1,23 @@
+package redaction
+
+import (
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type rulesModel struct {
+	ProjectID int       `db:"project_id"`
+	Paths     []string  `db:"paths"`
+	UpdatedBy string    `db:"updated_by"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func (m *rulesModel) toDomain() domain.RedactionRules {
+	return domain.RedactionRules{
+		ProjectID: domain.ProjectID(m.ProjectID),
+		Paths:     m.Paths,
+		UpdatedBy: m.UpdatedBy,
+		UpdatedAt: &m.UpdatedAt,
+	}
+}
@@ -0,0 +1,120 @@
+package redaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/repository/auditlog"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.RedactionRepository = (*Repository)(nil)
+
+type Repository struct {
+	db db.Tx
+}
+
+func New(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		db: pool,
+	}
+}
+
+func (r *Repository) GetRules(ctx context.Context, projectID domain.ProjectID) (domain.RedactionRules, error) {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+SELECT project_id, paths, updated_by, updated_at
+FROM workflows_manager.project_redaction_rules
+WHERE project_id = $1`
+
+	rows, err := executor.Query(ctx, query, projectID.Int())
+	if err != nil {
+		return domain.RedactionRules{}, fmt.Errorf("query redaction rules: %w", err)
+	}
+	defer rows.Close()
+
+	model, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[rulesModel])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.RedactionRules{}, domain.ErrEntityNotFound
+		}
+
+		return domain.RedactionRules{}, fmt.Errorf("collect redaction rules: %w", err)
+	}
+
+	return model.toDomain(), nil
+}
+
+func (r *Repository) ListRules(ctx context.Context, projectIDs []domain.ProjectID) ([]domain.RedactionRules, error) {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+SELECT project_id, paths, updated_by, updated_at
+FROM workflows_manager.project_redaction_rules
+WHERE project_id = ANY ($1) AND cardinality(paths) > 0`
+
+	ids := make([]int, 0, len(projectIDs))
+	for _, id := range projectIDs {
+		ids = append(ids, id.Int())
+	}
+
+	rows, err := executor.Query(ctx, query, ids)
+	if err != nil {
+		return nil, fmt.Errorf("query redaction rules: %w", err)
+	}
+	defer rows.Close()
+
+	listModels, err := pgx.CollectRows(rows, pgx.RowToStructByName[rulesModel])
+	if err != nil {
+		return nil, fmt.Errorf("collect redaction rules: %w", err)
+	}
+
+	rules := make([]domain.RedactionRules, 0, len(listModels))
+	for i := range listModels {
+		rules = append(rules, listModels[i].toDomain())
+	}
+
+	return rules, nil
+}
+
+func (r *Repository) SaveRules(ctx context.Context, projectID domain.ProjectID, paths []string) error {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+INSERT INTO workflows_manager.project_redaction_rules (project_id, paths, updated_by)
+VALUES ($1, $2, $3)
+ON CONFLICT (project_id) DO UPDATE
+SET paths = EXCLUDED.paths,
+    updated_by = EXCLUDED.updated_by,
+    updated_at = NOW()`
+
+	_, err := executor.Exec(ctx, query, projectID.Int(), paths, appcontext.Username(ctx))
+	if err != nil {
+		return fmt.Errorf("save redaction rules: %w", err)
+	}
+
+	err = auditlog.WriteLog(ctx, executor, domain.EntityRedactionRules, projectID.String(),
+		domain.ActionUpdate, projectID)
+	if err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+
+	return nil
+}
+
+//nolint:ireturn // it's ok here
+func (r *Repository) getExecutor(ctx context.Context) db.Tx {
+	if tx := db.TxFromContext(ctx); tx != nil {
+		return tx
+	}
+
+	return r.db
+}
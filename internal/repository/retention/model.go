@@ -0,0 +1,33 @@
+package retention
+
+import (
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type settingsModel struct {
+	ProjectID            int        `db:"project_id"`
+	CompletedDays        *int       `db:"completed_days"`
+	FailedDays           *int       `db:"failed_days"`
+	EventsDays           *int       `db:"events_days"`
+	LastRunAt            *time.Time `db:"last_run_at"`
+	LastDeletedInstances int64      `db:"last_deleted_instances"`
+	LastDeletedEvents    int64      `db:"last_deleted_events"`
+	LastError            *string    `db:"last_error"`
+	UpdatedAt            time.Time  `db:"updated_at"`
+}
+
+func (m *settingsModel) toDomain() domain.RetentionSettings {
+	return domain.RetentionSettings{
+		ProjectID:            domain.ProjectID(m.ProjectID),
+		CompletedDays:        m.CompletedDays,
+		FailedDays:           m.FailedDays,
+		EventsDays:           m.EventsDays,
+		LastRunAt:            m.LastRunAt,
+		LastDeletedInstances: m.LastDeletedInstances,
+		LastDeletedEvents:    m.LastDeletedEvents,
+		LastError:            m.LastError,
+		UpdatedAt:            m.UpdatedAt,
+	}
+}
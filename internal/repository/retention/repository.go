@@ -0,0 +1,222 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/repository/auditlog"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.RetentionRepository = (*Repository)(nil)
+
+const settingsColumns = `project_id, completed_days, failed_days, events_days,
+last_run_at, last_deleted_instances, last_deleted_events, last_error, updated_at`
+
+type Repository struct {
+	db db.Tx
+}
+
+func New(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		db: pool,
+	}
+}
+
+func (r *Repository) GetSettings(ctx context.Context, projectID domain.ProjectID) (domain.RetentionSettings, error) {
+	executor := r.getExecutor(ctx)
+
+	query := `
+SELECT ` + settingsColumns + `
+FROM workflows_manager.project_retention_settings
+WHERE project_id = $1`
+
+	rows, err := executor.Query(ctx, query, projectID.Int())
+	if err != nil {
+		return domain.RetentionSettings{}, fmt.Errorf("query retention settings: %w", err)
+	}
+	defer rows.Close()
+
+	model, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[settingsModel])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.RetentionSettings{}, domain.ErrEntityNotFound
+		}
+
+		return domain.RetentionSettings{}, fmt.Errorf("collect retention settings: %w", err)
+	}
+
+	return model.toDomain(), nil
+}
+
+func (r *Repository) SaveSettings(ctx context.Context, settings domain.RetentionSettings) error {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+INSERT INTO workflows_manager.project_retention_settings (project_id, completed_days, failed_days, events_days)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (project_id) DO UPDATE
+SET completed_days = EXCLUDED.completed_days,
+    failed_days = EXCLUDED.failed_days,
+    events_days = EXCLUDED.events_days,
+    updated_at = NOW()`
+
+	_, err := executor.Exec(ctx, query,
+		settings.ProjectID.Int(),
+		settings.CompletedDays,
+		settings.FailedDays,
+		settings.EventsDays,
+	)
+	if err != nil {
+		return fmt.Errorf("save retention settings: %w", err)
+	}
+
+	err = auditlog.WriteLog(ctx, executor, domain.EntityRetentionSettings, settings.ProjectID.String(),
+		domain.ActionUpdate, settings.ProjectID)
+	if err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) ListConfiguredSettings(ctx context.Context) ([]domain.RetentionSettings, error) {
+	executor := r.getExecutor(ctx)
+
+	query := `
+SELECT ` + settingsColumns + `
+FROM workflows_manager.project_retention_settings
+WHERE completed_days IS NOT NULL OR failed_days IS NOT NULL OR events_days IS NOT NULL
+ORDER BY project_id`
+
+	rows, err := executor.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("query retention settings: %w", err)
+	}
+	defer rows.Close()
+
+	listModels, err := pgx.CollectRows(rows, pgx.RowToStructByName[settingsModel])
+	if err != nil {
+		return nil, fmt.Errorf("collect retention settings: %w", err)
+	}
+
+	settings := make([]domain.RetentionSettings, 0, len(listModels))
+	for i := range listModels {
+		settings = append(settings, listModels[i].toDomain())
+	}
+
+	return settings, nil
+}
+
+func (r *Repository) SaveRun(ctx context.Context, projectID domain.ProjectID, run domain.RetentionRun) error {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+UPDATE workflows_manager.project_retention_settings
+SET last_run_at = $2, last_deleted_instances = $3, last_deleted_events = $4, last_error = $5
+WHERE project_id = $1`
+
+	_, err := executor.Exec(ctx, query, projectID.Int(), run.RunAt, run.DeletedInstances, run.DeletedEvents, run.Error)
+	if err != nil {
+		return fmt.Errorf("save retention run: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) DeleteInstances(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	statuses []string,
+	before time.Time,
+	limit int,
+) (int64, error) {
+	executor := r.getExecutor(ctx)
+
+	// Step logs, lineage, holds and signals have no foreign keys to the engine tables, they are deleted with their instances.
+	// Data-modifying CTEs share a snapshot, so the steps are still visible to the logs deletion.
+	const query = `
+WITH expired AS (
+    SELECT wi.id
+    FROM workflows.workflow_instances wi
+             JOIN workflows_manager.project_workflows pw ON pw.workflow_definition_id = wi.workflow_id
+    WHERE pw.project_id = $1
+      AND wi.status = ANY ($2)
+      AND wi.completed_at < $3
+    LIMIT $4
+),
+logs AS (
+    DELETE FROM workflows_manager.step_logs sl
+    USING workflows.workflow_steps ws
+    WHERE ws.id = sl.step_id AND ws.instance_id IN (SELECT id FROM expired)
+),
+lineage AS (
+    DELETE FROM workflows_manager.instance_lineage
+    WHERE instance_id IN (SELECT id FROM expired)
+),
+holds AS (
+    DELETE FROM workflows_manager.instance_holds
+    WHERE instance_id IN (SELECT id FROM expired)
+),
+held_items AS (
+    DELETE FROM workflows_manager.held_queue_items
+    WHERE instance_id IN (SELECT id FROM expired)
+),
+signals AS (
+    DELETE FROM workflows_manager.instance_signals
+    WHERE instance_id IN (SELECT id FROM expired)
+)
+DELETE FROM workflows.workflow_instances
+WHERE id IN (SELECT id FROM expired)`
+
+	tag, err := executor.Exec(ctx, query, projectID.Int(), statuses, before, limit)
+	if err != nil {
+		return 0, fmt.Errorf("delete instances: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func (r *Repository) DeleteEvents(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	before time.Time,
+	limit int,
+) (int64, error) {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+DELETE FROM workflows.workflow_events
+WHERE id IN (
+    SELECT e.id
+    FROM workflows.workflow_events e
+             JOIN workflows.workflow_instances wi ON wi.id = e.instance_id
+             JOIN workflows_manager.project_workflows pw ON pw.workflow_definition_id = wi.workflow_id
+    WHERE pw.project_id = $1
+      AND e.created_at < $2
+    LIMIT $3
+)`
+
+	tag, err := executor.Exec(ctx, query, projectID.Int(), before, limit)
+	if err != nil {
+		return 0, fmt.Errorf("delete events: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+//nolint:ireturn // it's ok here
+func (r *Repository) getExecutor(ctx context.Context) db.Tx {
+	if tx := db.TxFromContext(ctx); tx != nil {
+		return tx
+	}
+
+	return r.db
+}
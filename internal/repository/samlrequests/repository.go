@@ -0,0 +1,89 @@
+package samlrequests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.SAMLRequestsRepository = (*Repository)(nil)
+
+type Repository struct {
+	db db.Tx
+}
+
+func New(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		db: pool,
+	}
+}
+
+func (r *Repository) Save(ctx context.Context, provider, state, requestID string, expiresAt time.Time) error {
+	executor := r.getExecutor(ctx)
+
+	const cleanupQuery = `DELETE FROM workflows_manager.saml_requests WHERE expires_at <= now()`
+
+	if _, err := executor.Exec(ctx, cleanupQuery); err != nil {
+		return fmt.Errorf("delete expired saml requests: %w", err)
+	}
+
+	const query = `
+INSERT INTO workflows_manager.saml_requests (state, provider, request_id, expires_at)
+VALUES ($1, $2, $3, $4)`
+
+	if _, err := executor.Exec(ctx, query, state, provider, requestID, expiresAt); err != nil {
+		if db.IsUniqueViolation(err) {
+			return domain.ErrEntityAlreadyExists
+		}
+
+		return fmt.Errorf("insert saml request: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) Take(ctx context.Context, provider, state string, now time.Time) (string, error) {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+DELETE FROM workflows_manager.saml_requests
+WHERE state = $1 AND provider = $2
+RETURNING request_id, expires_at`
+
+	var (
+		requestID string
+		expiresAt time.Time
+	)
+
+	err := executor.QueryRow(ctx, query, state, provider).Scan(&requestID, &expiresAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", domain.ErrEntityNotFound
+		}
+
+		return "", fmt.Errorf("delete saml request: %w", err)
+	}
+
+	if !expiresAt.After(now) {
+		return "", domain.ErrEntityNotFound
+	}
+
+	return requestID, nil
+}
+
+//nolint:ireturn // it's ok here
+func (r *Repository) getExecutor(ctx context.Context) db.Tx {
+	if tx := db.TxFromContext(ctx); tx != nil {
+		return tx
+	}
+
+	return r.db
+}
@@ -0,0 +1,52 @@
+package schedules
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type scheduleModel struct {
+	ID                   int            `db:"id"`
+	ProjectID            int            `db:"project_id"`
+	WorkflowDefinitionID string         `db:"workflow_definition_id"`
+	Name                 string         `db:"name"`
+	CronExpr             string         `db:"cron_expr"`
+	Timezone             string         `db:"timezone"`
+	Input                []byte         `db:"input"`
+	Enabled              bool           `db:"enabled"`
+	NextRunAt            *time.Time     `db:"next_run_at"`
+	LastRunAt            *time.Time     `db:"last_run_at"`
+	LastInstanceID       *int64         `db:"last_instance_id"`
+	LastError            sql.NullString `db:"last_error"`
+	CreatedBy            string         `db:"created_by"`
+	CreatedAt            time.Time      `db:"created_at"`
+	UpdatedAt            time.Time      `db:"updated_at"`
+}
+
+func (m *scheduleModel) toDomain() domain.Schedule {
+	var lastError *string
+	if m.LastError.Valid {
+		lastError = &m.LastError.String
+	}
+
+	return domain.Schedule{
+		ID:             domain.ScheduleID(m.ID),
+		ProjectID:      domain.ProjectID(m.ProjectID),
+		WorkflowID:     m.WorkflowDefinitionID,
+		Name:           m.Name,
+		CronExpr:       m.CronExpr,
+		Timezone:       m.Timezone,
+		Input:          json.RawMessage(m.Input),
+		Enabled:        m.Enabled,
+		NextRunAt:      m.NextRunAt,
+		LastRunAt:      m.LastRunAt,
+		LastInstanceID: m.LastInstanceID,
+		LastError:      lastError,
+		CreatedBy:      m.CreatedBy,
+		CreatedAt:      m.CreatedAt,
+		UpdatedAt:      m.UpdatedAt,
+	}
+}
@@ -0,0 +1,335 @@
+package schedules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/repository/auditlog"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.SchedulesRepository = (*Repository)(nil)
+
+const scheduleColumns = `id, project_id, workflow_definition_id, name, cron_expr, timezone, input, enabled,
+next_run_at, last_run_at, last_instance_id, last_error, created_by, created_at, updated_at`
+
+type Repository struct {
+	db db.Tx
+}
+
+func New(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		db: pool,
+	}
+}
+
+func (r *Repository) List(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	page, pageSize int,
+) ([]domain.Schedule, int, error) {
+	executor := r.getExecutor(ctx)
+
+	offset := (page - 1) * pageSize
+
+	query := `
+SELECT ` + scheduleColumns + `
+FROM workflows_manager.workflow_schedules
+WHERE project_id = $1
+ORDER BY id
+LIMIT $2 OFFSET $3`
+
+	listModels, total, err := db.QueryPage(ctx, executor, db.PageQuery{
+		CountSQL:  `SELECT COUNT(*) FROM workflows_manager.workflow_schedules WHERE project_id = $1`,
+		CountArgs: []any{projectID.Int()},
+		SQL:       query,
+		Args:      []any{projectID.Int(), pageSize, offset},
+	}, pgx.RowToStructByName[scheduleModel])
+	if err != nil {
+		return nil, 0, fmt.Errorf("list schedules: %w", err)
+	}
+
+	schedules := make([]domain.Schedule, 0, len(listModels))
+	for i := range listModels {
+		schedules = append(schedules, listModels[i].toDomain())
+	}
+
+	return schedules, total, nil
+}
+
+func (r *Repository) GetByID(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	id domain.ScheduleID,
+) (domain.Schedule, error) {
+	executor := r.getExecutor(ctx)
+
+	query := `
+SELECT ` + scheduleColumns + `
+FROM workflows_manager.workflow_schedules
+WHERE project_id = $1 AND id = $2
+LIMIT 1`
+
+	rows, err := executor.Query(ctx, query, projectID.Int(), id.Int())
+	if err != nil {
+		return domain.Schedule{}, fmt.Errorf("query schedule: %w", err)
+	}
+	defer rows.Close()
+
+	model, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[scheduleModel])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Schedule{}, domain.ErrEntityNotFound
+		}
+
+		return domain.Schedule{}, fmt.Errorf("collect schedule: %w", err)
+	}
+
+	return model.toDomain(), nil
+}
+
+func (r *Repository) Create(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	dto domain.ScheduleDTO,
+	nextRunAt *time.Time,
+) (domain.ScheduleID, error) {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+INSERT INTO workflows_manager.workflow_schedules
+    (project_id, workflow_definition_id, name, cron_expr, timezone, input, enabled, next_run_at, created_by)
+VALUES ($1, $2, $3, $4, $5, $6::jsonb, $7, $8, $9)
+RETURNING id`
+
+	var id int
+	err := executor.QueryRow(ctx, query,
+		projectID.Int(),
+		dto.WorkflowID,
+		dto.Name,
+		dto.CronExpr,
+		dto.Timezone,
+		dto.Input,
+		dto.Enabled,
+		nextRunAt,
+		appcontext.Username(ctx),
+	).Scan(&id)
+	if err != nil {
+		if db.IsUniqueViolation(err) {
+			return 0, domain.ErrEntityAlreadyExists
+		}
+
+		return 0, fmt.Errorf("insert schedule: %w", err)
+	}
+
+	scheduleID := domain.ScheduleID(id)
+	err = auditlog.WriteLog(ctx, executor, domain.EntitySchedule, scheduleID.String(), domain.ActionCreate, projectID)
+	if err != nil {
+		return 0, fmt.Errorf("write audit log: %w", err)
+	}
+
+	return scheduleID, nil
+}
+
+func (r *Repository) Update(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	id domain.ScheduleID,
+	dto domain.ScheduleDTO,
+	nextRunAt *time.Time,
+) error {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+UPDATE workflows_manager.workflow_schedules
+SET workflow_definition_id = $3,
+    name = $4,
+    cron_expr = $5,
+    timezone = $6,
+    input = $7::jsonb,
+    enabled = $8,
+    next_run_at = $9,
+    updated_at = NOW()
+WHERE project_id = $1 AND id = $2`
+
+	result, err := executor.Exec(ctx, query,
+		projectID.Int(),
+		id.Int(),
+		dto.WorkflowID,
+		dto.Name,
+		dto.CronExpr,
+		dto.Timezone,
+		dto.Input,
+		dto.Enabled,
+		nextRunAt,
+	)
+	if err != nil {
+		if db.IsUniqueViolation(err) {
+			return domain.ErrEntityAlreadyExists
+		}
+
+		return fmt.Errorf("update schedule: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrEntityNotFound
+	}
+
+	if err := auditlog.WriteLog(ctx, executor, domain.EntitySchedule, id.String(), domain.ActionUpdate, projectID); err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) SetEnabled(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	id domain.ScheduleID,
+	enabled bool,
+	nextRunAt *time.Time,
+) error {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+UPDATE workflows_manager.workflow_schedules
+SET enabled = $3, next_run_at = $4, updated_at = NOW()
+WHERE project_id = $1 AND id = $2`
+
+	result, err := executor.Exec(ctx, query, projectID.Int(), id.Int(), enabled, nextRunAt)
+	if err != nil {
+		return fmt.Errorf("set schedule enabled: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrEntityNotFound
+	}
+
+	action := domain.ActionDisable
+	if enabled {
+		action = domain.ActionEnable
+	}
+
+	if err := auditlog.WriteLog(ctx, executor, domain.EntitySchedule, id.String(), action, projectID); err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) Delete(ctx context.Context, projectID domain.ProjectID, id domain.ScheduleID) error {
+	executor := r.getExecutor(ctx)
+
+	result, err := executor.Exec(ctx,
+		`DELETE FROM workflows_manager.workflow_schedules WHERE project_id = $1 AND id = $2`,
+		projectID.Int(), id.Int(),
+	)
+	if err != nil {
+		return fmt.Errorf("delete schedule: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrEntityNotFound
+	}
+
+	if err := auditlog.WriteLog(ctx, executor, domain.EntitySchedule, id.String(), domain.ActionDelete, projectID); err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+
+	return nil
+}
+
+// ListDue returns enabled schedules whose next run time has come.
+// Rows are locked when called inside a transaction so concurrent runners skip them.
+func (r *Repository) ListDue(ctx context.Context, now time.Time, limit int) ([]domain.Schedule, error) {
+	executor := r.getExecutor(ctx)
+
+	query := `
+SELECT ` + scheduleColumns + `
+FROM workflows_manager.workflow_schedules
+WHERE enabled AND next_run_at IS NOT NULL AND next_run_at <= $1
+ORDER BY next_run_at
+LIMIT $2
+FOR UPDATE SKIP LOCKED`
+
+	rows, err := executor.Query(ctx, query, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query due schedules: %w", err)
+	}
+	defer rows.Close()
+
+	listModels, err := pgx.CollectRows(rows, pgx.RowToStructByName[scheduleModel])
+	if err != nil {
+		return nil, fmt.Errorf("collect due schedules: %w", err)
+	}
+
+	schedules := make([]domain.Schedule, 0, len(listModels))
+	for i := range listModels {
+		schedules = append(schedules, listModels[i].toDomain())
+	}
+
+	return schedules, nil
+}
+
+// ClaimRun records the activation of the schedule identified by runKey and moves next_run_at forward.
+// It returns false when the activation was claimed already, its instance must not be started again.
+func (r *Repository) ClaimRun(
+	ctx context.Context,
+	id domain.ScheduleID,
+	runKey string,
+	run domain.ScheduleRun,
+) (bool, error) {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+UPDATE workflows_manager.workflow_schedules
+SET last_run_at = $3,
+    next_run_at = $4,
+    last_run_key = $2,
+    last_error = $5,
+    updated_at = NOW()
+WHERE id = $1 AND last_run_key IS DISTINCT FROM $2`
+
+	result, err := executor.Exec(ctx, query, id.Int(), runKey, run.RunAt, run.NextRunAt, run.Error)
+	if err != nil {
+		return false, fmt.Errorf("claim schedule run: %w", err)
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+// SaveRun stores the outcome of a claimed schedule activation.
+func (r *Repository) SaveRun(ctx context.Context, id domain.ScheduleID, run domain.ScheduleRun) error {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+UPDATE workflows_manager.workflow_schedules
+SET last_instance_id = COALESCE($2, last_instance_id),
+    last_error = $3,
+    updated_at = NOW()
+WHERE id = $1`
+
+	_, err := executor.Exec(ctx, query, id.Int(), run.InstanceID, run.Error)
+	if err != nil {
+		return fmt.Errorf("save schedule run: %w", err)
+	}
+
+	return nil
+}
+
+//nolint:ireturn // it's ok here
+func (r *Repository) getExecutor(ctx context.Context) db.Tx {
+	if tx := db.TxFromContext(ctx); tx != nil {
+		return tx
+	}
+
+	return r.db
+}
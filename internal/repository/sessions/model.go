@@ -0,0 +1,40 @@
+package sessions
+
+import (
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type sessionModel struct {
+	ID         string     `db:"id"`
+	UserID     int        `db:"user_id"`
+	UserAgent  string     `db:"user_agent"`
+	IPAddress  string     `db:"ip_address"`
+	CreatedAt  time.Time  `db:"created_at"`
+	LastUsedAt time.Time  `db:"last_used_at"`
+	ExpiresAt  time.Time  `db:"expires_at"`
+	RevokedAt  *time.Time `db:"revoked_at"`
+
+	RefreshTokenID *string `db:"refresh_token_id"`
+}
+
+func (m *sessionModel) toDomain() domain.Session {
+	var refreshTokenID string
+	if m.RefreshTokenID != nil {
+		refreshTokenID = *m.RefreshTokenID
+	}
+
+	return domain.Session{
+		ID:         domain.SessionID(m.ID),
+		UserID:     domain.UserID(m.UserID),
+		UserAgent:  m.UserAgent,
+		IPAddress:  m.IPAddress,
+		CreatedAt:  m.CreatedAt,
+		LastUsedAt: m.LastUsedAt,
+		ExpiresAt:  m.ExpiresAt,
+		RevokedAt:  m.RevokedAt,
+
+		RefreshTokenID: refreshTokenID,
+	}
+}
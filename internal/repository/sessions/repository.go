@@ -0,0 +1,205 @@
+package sessions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/repository/auditlog"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.SessionsRepository = (*Repository)(nil)
+
+const (
+	sessionColumns = `id::text, user_id, user_agent, ip_address, created_at, last_used_at, expires_at, revoked_at,
+refresh_token_id`
+
+	maxUserAgentLength = 512
+	maxIPAddressLength = 64
+)
+
+type Repository struct {
+	db db.Tx
+}
+
+func New(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		db: pool,
+	}
+}
+
+func (r *Repository) Create(ctx context.Context, dto domain.SessionDTO) (domain.SessionID, error) {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+INSERT INTO workflows_manager.user_sessions (user_id, user_agent, ip_address, expires_at, refresh_token_id)
+VALUES ($1, $2, $3, $4, NULLIF($5, ''))
+RETURNING id::text`
+
+	var id string
+	err := executor.QueryRow(ctx, query,
+		int(dto.UserID),
+		truncate(dto.UserAgent, maxUserAgentLength),
+		truncate(dto.IPAddress, maxIPAddressLength),
+		dto.ExpiresAt,
+		dto.RefreshTokenID,
+	).Scan(&id)
+	if err != nil {
+		return "", fmt.Errorf("insert session: %w", err)
+	}
+
+	return domain.SessionID(id), nil
+}
+
+func (r *Repository) GetByID(ctx context.Context, id domain.SessionID) (domain.Session, error) {
+	executor := r.getExecutor(ctx)
+
+	query := `
+SELECT ` + sessionColumns + `
+FROM workflows_manager.user_sessions
+WHERE id::text = $1`
+
+	rows, err := executor.Query(ctx, query, id.String())
+	if err != nil {
+		return domain.Session{}, fmt.Errorf("query session: %w", err)
+	}
+	defer rows.Close()
+
+	model, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[sessionModel])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Session{}, domain.ErrEntityNotFound
+		}
+
+		return domain.Session{}, fmt.Errorf("collect session: %w", err)
+	}
+
+	return model.toDomain(), nil
+}
+
+func (r *Repository) ListActive(ctx context.Context, userID domain.UserID, now time.Time) ([]domain.Session, error) {
+	executor := r.getExecutor(ctx)
+
+	query := `
+SELECT ` + sessionColumns + `
+FROM workflows_manager.user_sessions
+WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > $2
+ORDER BY created_at DESC`
+
+	rows, err := executor.Query(ctx, query, int(userID), now)
+	if err != nil {
+		return nil, fmt.Errorf("query sessions: %w", err)
+	}
+	defer rows.Close()
+
+	listModels, err := pgx.CollectRows(rows, pgx.RowToStructByName[sessionModel])
+	if err != nil {
+		return nil, fmt.Errorf("collect sessions: %w", err)
+	}
+
+	sessions := make([]domain.Session, 0, len(listModels))
+	for i := range listModels {
+		sessions = append(sessions, listModels[i].toDomain())
+	}
+
+	return sessions, nil
+}
+
+func (r *Repository) Rotate(
+	ctx context.Context,
+	id domain.SessionID,
+	currentTokenID, newTokenID string,
+	lastUsedAt, expiresAt time.Time,
+) error {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+UPDATE workflows_manager.user_sessions
+SET refresh_token_id = $3, last_used_at = $4, expires_at = $5
+WHERE id::text = $1 AND revoked_at IS NULL AND refresh_token_id IS NOT DISTINCT FROM NULLIF($2, '')`
+
+	result, err := executor.Exec(ctx, query, id.String(), currentTokenID, newTokenID, lastUsedAt, expiresAt)
+	if err != nil {
+		return fmt.Errorf("rotate session token: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrEntityNotFound
+	}
+
+	return nil
+}
+
+func (r *Repository) Revoke(ctx context.Context, userID domain.UserID, id domain.SessionID, at time.Time) error {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+UPDATE workflows_manager.user_sessions
+SET revoked_at = $3
+WHERE user_id = $1 AND id::text = $2 AND revoked_at IS NULL`
+
+	result, err := executor.Exec(ctx, query, int(userID), id.String(), at)
+	if err != nil {
+		return fmt.Errorf("revoke session: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrEntityNotFound
+	}
+
+	return nil
+}
+
+func (r *Repository) RevokeAll(ctx context.Context, userID domain.UserID, at time.Time) error {
+	executor := r.getExecutor(ctx)
+
+	_, err := executor.Exec(ctx,
+		`UPDATE workflows_manager.user_sessions SET revoked_at = $2 WHERE user_id = $1 AND revoked_at IS NULL`,
+		int(userID), at,
+	)
+	if err != nil {
+		return fmt.Errorf("revoke sessions: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) RevokeReused(ctx context.Context, userID domain.UserID, id domain.SessionID, at time.Time) error {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+UPDATE workflows_manager.user_sessions
+SET revoked_at = $3
+WHERE user_id = $1 AND id::text = $2 AND revoked_at IS NULL`
+
+	if _, err := executor.Exec(ctx, query, int(userID), id.String(), at); err != nil {
+		return fmt.Errorf("revoke session: %w", err)
+	}
+
+	return auditlog.WriteLog(ctx, executor, domain.EntitySession, id.String(), domain.ActionTokenReuse, 0)
+}
+
+//nolint:ireturn // it's ok here
+func (r *Repository) getExecutor(ctx context.Context) db.Tx {
+	if tx := db.TxFromContext(ctx); tx != nil {
+		return tx
+	}
+
+	return r.db
+}
+
+func truncate(value string, maxLen int) string {
+	if len(value) <= maxLen {
+		return value
+	}
+
+	return strings.ToValidUTF8(value[:maxLen], "")
+}
@@ -0,0 +1,122 @@
+package steplogs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.StepLogsRepository = (*Repository)(nil)
+
+// lockNamespace is the first key of step log advisory locks ("flxl"), the step ID being the second one.
+const lockNamespace int32 = 0x666c786c
+
+type Repository struct {
+	db db.Tx
+}
+
+func New(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		db: pool,
+	}
+}
+
+func (r *Repository) Lock(ctx context.Context, stepID int) error {
+	executor := r.getExecutor(ctx)
+
+	const query = `SELECT pg_advisory_xact_lock($1, $2)`
+
+	if _, err := executor.Exec(ctx, query, lockNamespace, int32(stepID)); err != nil {
+		return fmt.Errorf("lock step log: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) Size(ctx context.Context, stepID int) (int64, error) {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+SELECT coalesce(max(start_offset + length(content)), 0)
+FROM workflows_manager.step_logs
+WHERE step_id = $1`
+
+	var size int64
+	if err := executor.QueryRow(ctx, query, stepID).Scan(&size); err != nil {
+		return 0, fmt.Errorf("get step log size: %w", err)
+	}
+
+	return size, nil
+}
+
+func (r *Repository) Insert(ctx context.Context, stepID int, offset int64, content []byte) error {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+INSERT INTO workflows_manager.step_logs (step_id, start_offset, content)
+VALUES ($1, $2, $3)`
+
+	if _, err := executor.Exec(ctx, query, stepID, offset, content); err != nil {
+		return fmt.Errorf("insert step log chunk: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) Read(
+	ctx context.Context,
+	stepID int,
+	offset, limit int64,
+	fn func([]byte) error,
+) error {
+	executor := r.getExecutor(ctx)
+
+	// Chunks are cut to the requested range on the database side; substring positions are 1-based.
+	const query = `
+SELECT substring(
+	content
+	FROM greatest($2 - start_offset, 0)::int + 1
+	FOR (least(start_offset + length(content), $2 + $3) - greatest(start_offset, $2))::int
+)
+FROM workflows_manager.step_logs
+WHERE step_id = $1
+  AND start_offset + length(content) > $2
+  AND start_offset < $2 + $3
+ORDER BY start_offset`
+
+	rows, err := executor.Query(ctx, query, stepID, offset, limit)
+	if err != nil {
+		return fmt.Errorf("query step log: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var chunk []byte
+		if err := rows.Scan(&chunk); err != nil {
+			return fmt.Errorf("scan step log chunk: %w", err)
+		}
+
+		if err := fn(chunk); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("read step log: %w", err)
+	}
+
+	return nil
+}
+
+//nolint:ireturn // it's ok here
+func (r *Repository) getExecutor(ctx context.Context) db.Tx {
+	if tx := db.TxFromContext(ctx); tx != nil {
+		return tx
+	}
+
+	return r.db
+}
@@ -4,21 +4,26 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/repository/auditlog"
+	"github.com/rom8726/floxy-manager/internal/repository/tenantschemas"
 	"github.com/rom8726/floxy-manager/pkg/db"
 )
 
 type Repository struct {
-	db db.Tx
+	db      db.Tx
+	schemas *tenantschemas.Schemas
 }
 
-func New(pool *pgxpool.Pool) *Repository {
+func New(pool *pgxpool.Pool, schemas *tenantschemas.Schemas) *Repository {
 	return &Repository{
-		db: pool,
+		db:      pool,
+		schemas: schemas,
 	}
 }
 
@@ -87,6 +92,10 @@ func (r *Repository) Create(ctx context.Context, name string) (domain.Tenant, er
 		return domain.Tenant{}, fmt.Errorf("collect tenant: %w", err)
 	}
 
+	if err := r.schemas.Provision(ctx, domain.TenantID(model.ID)); err != nil {
+		return domain.Tenant{}, err
+	}
+
 	//if err := auditlog.WriteLog(ctx, executor, domain.EntityTenant, strconv.Itoa(model.ID), domain.ActionCreate); err != nil {
 	//	return domain.Tenant{}, fmt.Errorf("write audit log: %w", err)
 	//}
@@ -134,9 +143,13 @@ func (r *Repository) Delete(ctx context.Context, id domain.TenantID) error {
 		return domain.ErrEntityNotFound
 	}
 
-	//if err := auditlog.WriteLog(ctx, executor, domain.EntityTenant, strconv.Itoa(id.Int()), domain.ActionDelete); err != nil {
-	//	return fmt.Errorf("write audit log: %w", err)
-	//}
+	if err := r.schemas.Drop(ctx, id); err != nil {
+		return err
+	}
+
+	if err := auditlog.WriteLog(ctx, executor, domain.EntityTenant, strconv.Itoa(id.Int()), domain.ActionDelete, 0); err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
 
 	return nil
 }
@@ -0,0 +1,213 @@
+// Package tenantschemas implements the schema isolation mode of tenants. Every tenant gets a
+// dedicated schema with the workflow views filtered by the tenant, and the queries of a tenant
+// are routed to it, so a query missing a tenant condition cannot read the rows of other tenants.
+// The workflow engine tables stay in the shared workflows schema, the engine does not support others.
+package tenantschemas
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rom8726/di"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ di.Servicer = (*Schemas)(nil)
+
+const (
+	sharedSchema = "workflows_manager"
+	viewPrefix   = sharedSchema + ".v_"
+)
+
+// views are the workflow views of the shared schema copied to the tenant schemas,
+// each of them has a tenant_id column.
+var views = []string{
+	"v_workflow_definitions",
+	"v_workflow_instances",
+	"v_workflow_steps",
+	"v_workflow_events",
+	"v_workflow_dlq",
+	"v_workflow_queue",
+	"v_workflow_join_state",
+	"v_workflow_cancel_requests",
+	"v_workflow_human_decisions",
+	"v_workflow_stats",
+	"v_active_workflows",
+}
+
+type Config struct {
+	Enabled bool
+}
+
+type Schemas struct {
+	db  db.Tx
+	tx  db.TxManager
+	cfg Config
+}
+
+func New(pool *pgxpool.Pool, cfg *Config) *Schemas {
+	return &Schemas{
+		db:  pool,
+		tx:  db.NewTxManager(pool),
+		cfg: *cfg,
+	}
+}
+
+// Name returns the schema of the tenant.
+func Name(tenantID domain.TenantID) string {
+	return fmt.Sprintf("workflows_tenant_%d", tenantID)
+}
+
+// Start provisions the schemas of the existing tenants, so the views follow the migrations
+// and the tenants created before the isolation mode was enabled get theirs.
+func (s *Schemas) Start(ctx context.Context) error {
+	return s.ProvisionAll(ctx)
+}
+
+func (s *Schemas) Stop(context.Context) error {
+	return nil
+}
+
+// ProvisionAll provisions the schemas of all tenants. The views select the columns the shared views
+// had when they were created, it must run after the migrations changing them.
+func (s *Schemas) ProvisionAll(ctx context.Context) error {
+	if !s.cfg.Enabled {
+		return nil
+	}
+
+	rows, err := s.db.Query(ctx, `SELECT id FROM workflows_manager.tenants ORDER BY id`)
+	if err != nil {
+		return fmt.Errorf("query tenants: %w", err)
+	}
+
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return fmt.Errorf("collect tenants: %w", err)
+	}
+
+	for _, id := range ids {
+		if err := s.Provision(ctx, domain.TenantID(id)); err != nil {
+			return err
+		}
+	}
+
+	slog.InfoContext(ctx, "Provisioned tenant schemas", "tenants", len(ids))
+
+	return nil
+}
+
+// Provision creates the schema of the tenant or recreates its views in a transaction, so the queries
+// of the tenant never miss a view. It does nothing when the isolation mode is disabled.
+func (s *Schemas) Provision(ctx context.Context, tenantID domain.TenantID) error {
+	if !s.cfg.Enabled {
+		return nil
+	}
+
+	schema := pgx.Identifier{Name(tenantID)}.Sanitize()
+
+	statements := []string{`CREATE SCHEMA IF NOT EXISTS ` + schema}
+	for _, view := range views {
+		name := pgx.Identifier{Name(tenantID), view}.Sanitize()
+		statements = append(statements,
+			`DROP VIEW IF EXISTS `+name,
+			fmt.Sprintf(`CREATE VIEW %s WITH (security_barrier) AS SELECT * FROM %s.%s WHERE tenant_id = %d`,
+				name, sharedSchema, view, tenantID),
+		)
+	}
+
+	err := s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		executor := s.getExecutor(ctx)
+		for _, statement := range statements {
+			if _, err := executor.Exec(ctx, statement); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("provision schema of tenant %d: %w", tenantID, err)
+	}
+
+	return nil
+}
+
+// Drop drops the schema of the tenant, it does nothing when the isolation mode is disabled.
+func (s *Schemas) Drop(ctx context.Context, tenantID domain.TenantID) error {
+	if !s.cfg.Enabled {
+		return nil
+	}
+
+	_, err := s.getExecutor(ctx).Exec(ctx, `DROP SCHEMA IF EXISTS `+pgx.Identifier{Name(tenantID)}.Sanitize()+` CASCADE`)
+	if err != nil {
+		return fmt.Errorf("drop schema of tenant %d: %w", tenantID, err)
+	}
+
+	return nil
+}
+
+// Executor returns the executor routing the queries of the workflow views to the schema
+// of the tenant. Without the isolation mode or a tenant the executor is returned as is.
+//
+//nolint:ireturn // it's ok here
+func (s *Schemas) Executor(executor db.Tx, tenantID domain.TenantID) db.Tx {
+	if !s.cfg.Enabled || tenantID == 0 {
+		return executor
+	}
+
+	return &routedTx{Tx: executor, schema: Name(tenantID)}
+}
+
+//nolint:ireturn // it's ok here
+func (s *Schemas) getExecutor(ctx context.Context) db.Tx {
+	if tx := db.TxFromContext(ctx); tx != nil {
+		return tx
+	}
+
+	return s.db
+}
+
+// routedTx rewrites the workflow views of the shared schema to the ones of the tenant schema
+// in queries and in the queued queries of batches.
+type routedTx struct {
+	db.Tx
+	schema string
+}
+
+func (t *routedTx) route(sql string) string {
+	return strings.ReplaceAll(sql, viewPrefix, t.schema+".v_")
+}
+
+func (t *routedTx) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	return t.Tx.Query(ctx, t.route(sql), args...)
+}
+
+//nolint:ireturn // pgx.Row is an interface
+func (t *routedTx) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return t.Tx.QueryRow(ctx, t.route(sql), args...)
+}
+
+func (t *routedTx) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	return t.Tx.Exec(ctx, t.route(sql), args...)
+}
+
+// SendBatch sends a copy of the batch with the queries routed, the batch itself is left as is.
+//
+//nolint:ireturn // pgx.BatchResults is an interface
+func (t *routedTx) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	routed := &pgx.Batch{QueuedQueries: make([]*pgx.QueuedQuery, 0, len(b.QueuedQueries))}
+	for _, queued := range b.QueuedQueries {
+		query := *queued
+		query.SQL = t.route(queued.SQL)
+		routed.QueuedQueries = append(routed.QueuedQueries, &query)
+	}
+
+	return t.Tx.SendBatch(ctx, routed)
+}
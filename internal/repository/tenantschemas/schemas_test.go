@@ -0,0 +1,172 @@
+package tenantschemas
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+func TestName(t *testing.T) {
+	assert.Equal(t, "workflows_tenant_42", Name(domain.TenantID(42)))
+}
+
+func TestRoutedTx_Route(t *testing.T) {
+	tx := &routedTx{schema: Name(domain.TenantID(7))}
+
+	assert.Equal(t,
+		"SELECT i.id FROM workflows_tenant_7.v_workflow_instances i "+
+			"JOIN workflows_tenant_7.v_workflow_steps s ON s.instance_id = i.id "+
+			"JOIN workflows_manager.projects p ON p.id = i.project_id",
+		tx.route("SELECT i.id FROM workflows_manager.v_workflow_instances i "+
+			"JOIN workflows_manager.v_workflow_steps s ON s.instance_id = i.id "+
+			"JOIN workflows_manager.projects p ON p.id = i.project_id"),
+	)
+}
+
+func TestSchemas_Executor(t *testing.T) {
+	disabled := &Schemas{}
+	assert.Nil(t, disabled.Executor(nil, domain.TenantID(1)))
+
+	enabled := &Schemas{cfg: Config{Enabled: true}}
+	assert.Nil(t, enabled.Executor(nil, 0))
+	assert.IsType(t, &routedTx{}, enabled.Executor(nil, domain.TenantID(1)))
+}
+
+// fakeTx records the queries of the batches it is sent and answers each of them with a single row.
+type fakeTx struct {
+	db.Tx
+	sent []string
+}
+
+func (tx *fakeTx) SendBatch(_ context.Context, b *pgx.Batch) pgx.BatchResults {
+	for _, queued := range b.QueuedQueries {
+		tx.sent = append(tx.sent, queued.SQL)
+	}
+
+	return &fakeBatchResults{}
+}
+
+type fakeBatchResults struct {
+	pgx.BatchResults
+}
+
+//nolint:ireturn // pgx.Row is an interface
+func (*fakeBatchResults) QueryRow() pgx.Row {
+	return &fakeRows{value: 1}
+}
+
+//nolint:ireturn // pgx.Rows is an interface
+func (*fakeBatchResults) Query() (pgx.Rows, error) {
+	return &fakeRows{value: "orders-v1"}, nil
+}
+
+func (*fakeBatchResults) Close() error {
+	return nil
+}
+
+type fakeRows struct {
+	pgx.Rows
+	value any
+	read  bool
+}
+
+func (r *fakeRows) Next() bool {
+	if r.read {
+		return false
+	}
+	r.read = true
+
+	return true
+}
+
+func (r *fakeRows) Scan(dest ...any) error {
+	reflect.ValueOf(dest[0]).Elem().Set(reflect.ValueOf(r.value))
+
+	return nil
+}
+
+func (*fakeRows) Err() error {
+	return nil
+}
+
+func (*fakeRows) Close() {}
+
+func TestRoutedTx_QueryPage(t *testing.T) {
+	tx := &fakeTx{}
+	schemas := &Schemas{cfg: Config{Enabled: true}}
+
+	query := db.PageQuery{
+		CountSQL: `SELECT COUNT(*) FROM workflows_manager.v_workflow_definitions WHERE tenant_id = $1`,
+		SQL:      `SELECT id FROM workflows_manager.v_workflow_definitions WHERE tenant_id = $1 LIMIT $2`,
+	}
+
+	items, total, err := db.QueryPage(context.Background(), schemas.Executor(tx, domain.TenantID(3)), query,
+		pgx.RowTo[string])
+	require.NoError(t, err)
+	assert.Equal(t, []string{"orders-v1"}, items)
+	assert.Equal(t, 1, total)
+
+	assert.Equal(t, []string{
+		`SELECT COUNT(*) FROM workflows_tenant_3.v_workflow_definitions WHERE tenant_id = $1`,
+		`SELECT id FROM workflows_tenant_3.v_workflow_definitions WHERE tenant_id = $1 LIMIT $2`,
+	}, tx.sent)
+}
+
+// fakeTxManager runs the functions as is, recording whether one is running.
+type fakeTxManager struct {
+	db.TxManager
+	running bool
+	calls   int
+}
+
+func (m *fakeTxManager) ReadCommitted(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.calls++
+	m.running = true
+	defer func() { m.running = false }()
+
+	return fn(ctx)
+}
+
+// execTx records the statements it executes and whether they ran in a transaction.
+type execTx struct {
+	db.Tx
+	txManager  *fakeTxManager
+	statements []string
+	outside    int
+}
+
+func (tx *execTx) Exec(_ context.Context, sql string, _ ...any) (pgconn.CommandTag, error) {
+	tx.statements = append(tx.statements, sql)
+	if !tx.txManager.running {
+		tx.outside++
+	}
+
+	return pgconn.CommandTag{}, nil
+}
+
+func TestSchemas_Provision(t *testing.T) {
+	txManager := &fakeTxManager{}
+	tx := &execTx{txManager: txManager}
+	schemas := &Schemas{db: tx, tx: txManager, cfg: Config{Enabled: true}}
+
+	require.NoError(t, schemas.Provision(context.Background(), domain.TenantID(5)))
+
+	assert.Equal(t, 1, txManager.calls)
+	assert.Zero(t, tx.outside)
+	require.Len(t, tx.statements, 1+2*len(views))
+	assert.Equal(t, `DROP VIEW IF EXISTS "workflows_tenant_5"."v_workflow_definitions"`, tx.statements[1])
+	assert.Equal(t, `CREATE VIEW "workflows_tenant_5"."v_workflow_definitions" WITH (security_barrier) AS `+
+		`SELECT * FROM workflows_manager.v_workflow_definitions WHERE tenant_id = 5`, tx.statements[2])
+
+	disabled := &Schemas{db: tx, tx: txManager}
+	require.NoError(t, disabled.Provision(context.Background(), domain.TenantID(5)))
+	assert.Equal(t, 1, txManager.calls)
+}
@@ -0,0 +1,38 @@
+package userpreferences
+
+import (
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type preferencesModel struct {
+	UserID              int        `db:"user_id"`
+	DefaultProjectID    *int       `db:"default_project_id"`
+	Locale              string     `db:"locale"`
+	ItemsPerPage        int        `db:"items_per_page"`
+	NotificationOptOuts []string   `db:"notification_opt_outs"`
+	UpdatedAt           *time.Time `db:"updated_at"`
+}
+
+func (m *preferencesModel) toDomain() domain.UserPreferences {
+	var defaultProjectID *domain.ProjectID
+	if m.DefaultProjectID != nil {
+		id := domain.ProjectID(*m.DefaultProjectID)
+		defaultProjectID = &id
+	}
+
+	optOuts := make([]domain.LifecycleEventType, 0, len(m.NotificationOptOuts))
+	for _, optOut := range m.NotificationOptOuts {
+		optOuts = append(optOuts, domain.LifecycleEventType(optOut))
+	}
+
+	return domain.UserPreferences{
+		UserID:              domain.UserID(m.UserID),
+		DefaultProjectID:    defaultProjectID,
+		Locale:              m.Locale,
+		ItemsPerPage:        m.ItemsPerPage,
+		NotificationOptOuts: optOuts,
+		UpdatedAt:           m.UpdatedAt,
+	}
+}
@@ -0,0 +1,129 @@
+package userpreferences
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.UserPreferencesRepository = (*Repository)(nil)
+
+const preferencesColumns = `user_id, default_project_id, locale, items_per_page, notification_opt_outs, updated_at`
+
+type Repository struct {
+	db db.Tx
+}
+
+func New(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		db: pool,
+	}
+}
+
+func (r *Repository) Get(ctx context.Context, userID domain.UserID) (domain.UserPreferences, error) {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+SELECT ` + preferencesColumns + `
+FROM workflows_manager.user_preferences
+WHERE user_id = $1`
+
+	rows, err := executor.Query(ctx, query, userID.Int())
+	if err != nil {
+		return domain.UserPreferences{}, fmt.Errorf("query user preferences: %w", err)
+	}
+	defer rows.Close()
+
+	model, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[preferencesModel])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.UserPreferences{}, domain.ErrEntityNotFound
+		}
+
+		return domain.UserPreferences{}, fmt.Errorf("collect user preferences: %w", err)
+	}
+
+	return model.toDomain(), nil
+}
+
+func (r *Repository) ListByUserIDs(ctx context.Context, userIDs []domain.UserID) ([]domain.UserPreferences, error) {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+SELECT ` + preferencesColumns + `
+FROM workflows_manager.user_preferences
+WHERE user_id = ANY ($1)`
+
+	rows, err := executor.Query(ctx, query, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("query user preferences: %w", err)
+	}
+	defer rows.Close()
+
+	listModels, err := pgx.CollectRows(rows, pgx.RowToStructByName[preferencesModel])
+	if err != nil {
+		return nil, fmt.Errorf("collect user preferences: %w", err)
+	}
+
+	preferences := make([]domain.UserPreferences, 0, len(listModels))
+	for i := range listModels {
+		preferences = append(preferences, listModels[i].toDomain())
+	}
+
+	return preferences, nil
+}
+
+func (r *Repository) Save(ctx context.Context, preferences domain.UserPreferences) error {
+	executor := r.getExecutor(ctx)
+
+	var defaultProjectID *int
+	if preferences.DefaultProjectID != nil {
+		id := preferences.DefaultProjectID.Int()
+		defaultProjectID = &id
+	}
+
+	optOuts := make([]string, 0, len(preferences.NotificationOptOuts))
+	for _, optOut := range preferences.NotificationOptOuts {
+		optOuts = append(optOuts, string(optOut))
+	}
+
+	const query = `
+INSERT INTO workflows_manager.user_preferences
+    (user_id, default_project_id, locale, items_per_page, notification_opt_outs)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (user_id) DO UPDATE
+SET default_project_id = EXCLUDED.default_project_id,
+    locale = EXCLUDED.locale,
+    items_per_page = EXCLUDED.items_per_page,
+    notification_opt_outs = EXCLUDED.notification_opt_outs,
+    updated_at = NOW()`
+
+	_, err := executor.Exec(ctx, query,
+		preferences.UserID.Int(),
+		defaultProjectID,
+		preferences.Locale,
+		preferences.ItemsPerPage,
+		optOuts,
+	)
+	if err != nil {
+		return fmt.Errorf("save user preferences: %w", err)
+	}
+
+	return nil
+}
+
+//nolint:ireturn // it's ok here
+func (r *Repository) getExecutor(ctx context.Context) db.Tx {
+	if tx := db.TxFromContext(ctx); tx != nil {
+		return tx
+	}
+
+	return r.db
+}
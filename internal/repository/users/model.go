@@ -8,39 +8,57 @@ import (
 )
 
 type userModel struct {
-	ID               uint           `db:"id"`
-	Username         string         `db:"username"`
-	Email            string         `db:"email"`
-	PasswordHash     string         `db:"password_hash"`
-	IsSuperuser      bool           `db:"is_superuser"`
-	IsActive         bool           `db:"is_active"`
-	IsTmpPassword    bool           `db:"is_tmp_password"`
-	IsExternal       bool           `db:"is_external"`
-	TwoFAEnabled     bool           `db:"two_fa_enabled"`
-	TwoFASecret      sql.NullString `db:"two_fa_secret"`
-	TwoFAConfirmedAt *time.Time     `db:"two_fa_confirmed_at"`
-	CreatedAt        time.Time      `db:"created_at"`
-	UpdatedAt        time.Time      `db:"updated_at"`
-	LastLogin        *time.Time     `db:"last_login"`
-	LicenseAccepted  bool           `db:"license_accepted"`
+	ID                uint           `db:"id"`
+	Username          string         `db:"username"`
+	DisplayName       string         `db:"display_name"`
+	Email             string         `db:"email"`
+	PasswordHash      string         `db:"password_hash"`
+	IsSuperuser       bool           `db:"is_superuser"`
+	IsActive          bool           `db:"is_active"`
+	IsTmpPassword     bool           `db:"is_tmp_password"`
+	IsExternal        bool           `db:"is_external"`
+	TwoFAEnabled      bool           `db:"two_fa_enabled"`
+	TwoFASecret       sql.NullString `db:"two_fa_secret"`
+	TwoFAConfirmedAt  *time.Time     `db:"two_fa_confirmed_at"`
+	CreatedAt         time.Time      `db:"created_at"`
+	UpdatedAt         time.Time      `db:"updated_at"`
+	LastLogin         *time.Time     `db:"last_login"`
+	LicenseAccepted   bool           `db:"license_accepted"`
+	LicenseVersion    string         `db:"license_version"`
+	LicenseAcceptedAt *time.Time     `db:"license_accepted_at"`
+	EmailVerifiedAt   *time.Time     `db:"email_verified_at"`
+	IsServiceAccount  bool           `db:"is_service_account"`
+	ServiceProjectID  *int           `db:"service_project_id"`
 }
 
 func (m *userModel) toDomain() domain.User {
+	var serviceProjectID *domain.ProjectID
+	if m.ServiceProjectID != nil {
+		id := domain.ProjectID(*m.ServiceProjectID)
+		serviceProjectID = &id
+	}
+
 	return domain.User{
-		ID:               domain.UserID(m.ID),
-		Username:         m.Username,
-		Email:            m.Email,
-		PasswordHash:     m.PasswordHash,
-		IsSuperuser:      m.IsSuperuser,
-		IsActive:         m.IsActive,
-		IsTmpPassword:    m.IsTmpPassword,
-		IsExternal:       m.IsExternal,
-		TwoFAEnabled:     m.TwoFAEnabled,
-		TwoFASecret:      m.TwoFASecret.String,
-		TwoFAConfirmedAt: m.TwoFAConfirmedAt,
-		CreatedAt:        m.CreatedAt,
-		UpdatedAt:        m.UpdatedAt,
-		LastLogin:        m.LastLogin,
-		LicenseAccepted:  m.LicenseAccepted,
+		ID:                domain.UserID(m.ID),
+		Username:          m.Username,
+		DisplayName:       m.DisplayName,
+		Email:             m.Email,
+		PasswordHash:      m.PasswordHash,
+		IsSuperuser:       m.IsSuperuser,
+		IsActive:          m.IsActive,
+		IsTmpPassword:     m.IsTmpPassword,
+		IsExternal:        m.IsExternal,
+		TwoFAEnabled:      m.TwoFAEnabled,
+		TwoFASecret:       m.TwoFASecret.String,
+		TwoFAConfirmedAt:  m.TwoFAConfirmedAt,
+		CreatedAt:         m.CreatedAt,
+		UpdatedAt:         m.UpdatedAt,
+		LastLogin:         m.LastLogin,
+		LicenseAccepted:   m.LicenseAccepted,
+		LicenseVersion:    m.LicenseVersion,
+		LicenseAcceptedAt: m.LicenseAcceptedAt,
+		EmailVerifiedAt:   m.EmailVerifiedAt,
+		IsServiceAccount:  m.IsServiceAccount,
+		ServiceProjectID:  serviceProjectID,
 	}
 }
@@ -4,12 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
 	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/repository/auditlog"
 	"github.com/rom8726/floxy-manager/pkg/db"
 )
 
@@ -27,10 +30,18 @@ func (r *Repository) Create(ctx context.Context, userDTO domain.UserDTO) (domain
 	executor := r.getExecutor(ctx)
 
 	const query = `
-INSERT INTO  workflows_manager.users (username, email, password_hash, is_superuser, is_active, created_at, is_tmp_password, is_external)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+INSERT INTO  workflows_manager.users (username, email, password_hash, is_superuser, is_active, created_at, is_tmp_password, is_external,
+    is_service_account, service_project_id, email_verified_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, CASE WHEN $11::boolean THEN $6 END)
 RETURNING id, username, email, password_hash, is_superuser,
-    is_active, created_at, last_login, is_tmp_password, is_external`
+    is_active, created_at, last_login, is_tmp_password, is_external, is_service_account, service_project_id,
+    email_verified_at`
+
+	var serviceProjectID *int
+	if userDTO.ServiceProjectID != nil {
+		id := userDTO.ServiceProjectID.Int()
+		serviceProjectID = &id
+	}
 
 	var user userModel
 
@@ -43,6 +54,9 @@ RETURNING id, username, email, password_hash, is_superuser,
 		time.Now(),
 		userDTO.IsTmpPassword,
 		userDTO.IsExternal,
+		userDTO.IsServiceAccount,
+		serviceProjectID,
+		userDTO.EmailVerified,
 	).Scan(
 		&user.ID,
 		&user.Username,
@@ -54,6 +68,9 @@ RETURNING id, username, email, password_hash, is_superuser,
 		&user.LastLogin,
 		&user.IsTmpPassword,
 		&user.IsExternal,
+		&user.IsServiceAccount,
+		&user.ServiceProjectID,
+		&user.EmailVerifiedAt,
 	)
 	if err != nil {
 		return domain.User{}, fmt.Errorf("insert user: %w", err)
@@ -178,11 +195,23 @@ func (r *Repository) FetchByIDs(ctx context.Context, ids []domain.UserID) ([]dom
 func (r *Repository) Update(ctx context.Context, user *domain.User) error {
 	executor := r.getExecutor(ctx)
 
+	// Updates without an acting user (SSO provisioning, logins) are not audited
+	audited := appcontext.Username(ctx) != ""
+
+	var current domain.User
+	if audited {
+		var err error
+		current, err = r.GetByID(ctx, user.ID)
+		if err != nil {
+			return fmt.Errorf("get user: %w", err)
+		}
+	}
+
 	const query = `
 UPDATE  workflows_manager.users
 SET username = $1, email = $2, password_hash = $3, is_superuser = $4, is_active = $5, last_login = $6,
-    is_tmp_password = $7, is_external = $8, license_accepted = $9, updated_at = NOW()
-WHERE id = $10`
+    is_tmp_password = $7, is_external = $8, license_accepted = $9, display_name = $10, updated_at = NOW()
+WHERE id = $11`
 
 	tag, err := executor.Exec(ctx, query,
 		user.Username,
@@ -194,6 +223,7 @@ WHERE id = $10`
 		user.IsTmpPassword,
 		user.IsExternal,
 		user.LicenseAccepted,
+		user.DisplayName,
 		user.ID,
 	)
 	if err != nil {
@@ -204,9 +234,23 @@ WHERE id = $10`
 		return domain.ErrEntityNotFound
 	}
 
-	//if err := auditlog.WriteLog(ctx, executor, domain.EntityUser, strconv.Itoa(int(user.ID)), domain.ActionUpdate); err != nil {
-	//	return fmt.Errorf("write audit log: %w", err)
-	//}
+	if !audited {
+		return nil
+	}
+
+	changes, err := auditlog.Diff(auditFields(&current), auditFields(user))
+	if err != nil {
+		return fmt.Errorf("diff user: %w", err)
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	err = auditlog.WriteChangeLog(ctx, executor, domain.EntityUser, strconv.Itoa(user.ID.Int()), domain.ActionUpdate, 0, changes)
+	if err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
 
 	return nil
 }
@@ -233,7 +277,8 @@ WHERE id = $1`
 func (r *Repository) List(ctx context.Context) ([]domain.User, error) {
 	executor := r.getExecutor(ctx)
 
-	const query = `SELECT * FROM  workflows_manager.users ORDER BY id`
+	// Service accounts are managed per project, see ListServiceAccounts.
+	const query = `SELECT * FROM  workflows_manager.users WHERE NOT is_service_account ORDER BY id`
 
 	rows, err := executor.Query(ctx, query)
 	if err != nil {
@@ -256,6 +301,34 @@ func (r *Repository) List(ctx context.Context) ([]domain.User, error) {
 	return users, nil
 }
 
+// ListServiceAccounts returns service accounts bound to the project.
+func (r *Repository) ListServiceAccounts(ctx context.Context, projectID domain.ProjectID) ([]domain.User, error) {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+SELECT * FROM  workflows_manager.users
+WHERE is_service_account AND service_project_id = $1
+ORDER BY id`
+
+	rows, err := executor.Query(ctx, query, projectID.Int())
+	if err != nil {
+		return nil, fmt.Errorf("query service accounts: %w", err)
+	}
+	defer rows.Close()
+
+	listModels, err := pgx.CollectRows(rows, pgx.RowToStructByName[userModel])
+	if err != nil {
+		return nil, fmt.Errorf("collect service accounts: %w", err)
+	}
+
+	users := make([]domain.User, 0, len(listModels))
+	for i := range listModels {
+		users = append(users, listModels[i].toDomain())
+	}
+
+	return users, nil
+}
+
 func (r *Repository) UpdateLastLogin(ctx context.Context, id domain.UserID) error {
 	executor := r.getExecutor(ctx)
 
@@ -276,6 +349,104 @@ func (r *Repository) UpdatePassword(ctx context.Context, id domain.UserID, passw
 	return err
 }
 
+// MarkEmailVerified records that the user confirmed the email address; already verified users are not changed.
+func (r *Repository) MarkEmailVerified(ctx context.Context, id domain.UserID) error {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+UPDATE  workflows_manager.users
+SET email_verified_at = COALESCE(email_verified_at, NOW()), updated_at = NOW()
+WHERE id = $1`
+
+	tag, err := executor.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("mark email verified: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrEntityNotFound
+	}
+
+	return nil
+}
+
+// AcceptLicense records that the user accepted the version of the license agreement.
+func (r *Repository) AcceptLicense(ctx context.Context, id domain.UserID, version string, acceptedAt time.Time) error {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+UPDATE  workflows_manager.users
+SET license_accepted = true, license_version = $1, license_accepted_at = $2, updated_at = NOW()
+WHERE id = $3`
+
+	tag, err := executor.Exec(ctx, query, version, acceptedAt, id)
+	if err != nil {
+		return fmt.Errorf("accept license: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrEntityNotFound
+	}
+
+	err = auditlog.WriteChangeLog(ctx, executor, domain.EntityUser, strconv.Itoa(id.Int()), domain.ActionUpdate, 0,
+		map[string]auditlog.Change{"license_version": {New: version}})
+	if err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateProfile updates the username, email and display name of a user.
+// Changing the email clears its verification so the new address has to be verified again.
+func (r *Repository) UpdateProfile(
+	ctx context.Context,
+	id domain.UserID,
+	username, email, displayName string,
+) error {
+	executor := r.getExecutor(ctx)
+
+	current, err := r.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("get user: %w", err)
+	}
+
+	const query = `
+UPDATE  workflows_manager.users
+SET username = $1, email = $2, display_name = $3,
+    email_verified_at = CASE WHEN email = $2 THEN email_verified_at END,
+    updated_at = NOW()
+WHERE id = $4`
+
+	tag, err := executor.Exec(ctx, query, username, email, displayName, id)
+	if err != nil {
+		if db.IsUniqueViolation(err) {
+			return domain.ErrEntityAlreadyExists
+		}
+
+		return fmt.Errorf("update profile: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrEntityNotFound
+	}
+
+	changes, err := auditlog.Diff(
+		map[string]string{"username": current.Username, "email": current.Email, "display_name": current.DisplayName},
+		map[string]string{"username": username, "email": email, "display_name": displayName},
+	)
+	if err != nil {
+		return fmt.Errorf("diff user: %w", err)
+	}
+
+	err = auditlog.WriteChangeLog(ctx, executor, domain.EntityUser, strconv.Itoa(id.Int()), domain.ActionUpdate, 0, changes)
+	if err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+
+	return nil
+}
+
 // Update2FA updates only 2FA-related fields for a user.
 func (r *Repository) Update2FA(
 	ctx context.Context,
@@ -308,6 +479,98 @@ WHERE id = $4`
 	return nil
 }
 
+// Merge moves the project and tenant memberships, the global roles and the preferences of the source user
+// to the target one. Memberships and preferences the target already has win, the ones of the source left
+// behind are deleted. The audit log is left as written, the merge is recorded on both users.
+// The caller runs it in a transaction.
+func (r *Repository) Merge(ctx context.Context, sourceID, targetID domain.UserID) (domain.UserMergeResult, error) {
+	executor := r.getExecutor(ctx)
+	result := domain.UserMergeResult{SourceID: sourceID, TargetID: targetID}
+
+	moves := []struct {
+		name  string
+		query string
+		count *int
+	}{
+		{
+			name: "memberships",
+			query: `
+UPDATE workflows_manager.memberships
+SET user_id = $2, updated_at = NOW()
+WHERE user_id = $1
+  AND project_id NOT IN (SELECT project_id FROM workflows_manager.memberships WHERE user_id = $2)`,
+			count: &result.Memberships,
+		},
+		{
+			name: "tenant memberships",
+			query: `
+UPDATE workflows_manager.tenant_memberships
+SET user_id = $2
+WHERE user_id = $1
+  AND tenant_id NOT IN (SELECT tenant_id FROM workflows_manager.tenant_memberships WHERE user_id = $2)`,
+			count: &result.TenantMemberships,
+		},
+		{
+			name: "global roles",
+			query: `
+UPDATE workflows_manager.global_role_assignments
+SET user_id = $2
+WHERE user_id = $1
+  AND role_id NOT IN (SELECT role_id FROM workflows_manager.global_role_assignments WHERE user_id = $2)`,
+			count: &result.GlobalRoles,
+		},
+	}
+
+	for _, move := range moves {
+		tag, err := executor.Exec(ctx, move.query, sourceID, targetID)
+		if err != nil {
+			return domain.UserMergeResult{}, fmt.Errorf("move %s: %w", move.name, err)
+		}
+
+		*move.count = int(tag.RowsAffected())
+	}
+
+	const movePreferences = `
+INSERT INTO workflows_manager.user_preferences
+    (user_id, default_project_id, locale, items_per_page, notification_opt_outs, updated_at)
+SELECT $2, default_project_id, locale, items_per_page, notification_opt_outs, NOW()
+FROM workflows_manager.user_preferences
+WHERE user_id = $1
+ON CONFLICT (user_id) DO NOTHING`
+
+	tag, err := executor.Exec(ctx, movePreferences, sourceID, targetID)
+	if err != nil {
+		return domain.UserMergeResult{}, fmt.Errorf("move preferences: %w", err)
+	}
+
+	result.Preferences = tag.RowsAffected() > 0
+
+	for _, query := range []string{
+		`DELETE FROM workflows_manager.memberships WHERE user_id = $1`,
+		`DELETE FROM workflows_manager.tenant_memberships WHERE user_id = $1`,
+		`DELETE FROM workflows_manager.global_role_assignments WHERE user_id = $1`,
+		`DELETE FROM workflows_manager.user_preferences WHERE user_id = $1`,
+	} {
+		if _, err := executor.Exec(ctx, query, sourceID); err != nil {
+			return domain.UserMergeResult{}, fmt.Errorf("delete leftovers of the source user: %w", err)
+		}
+	}
+
+	err = auditlog.WriteChangeLog(ctx, executor, domain.EntityUser, strconv.Itoa(sourceID.Int()), domain.ActionMerge, 0,
+		map[string]auditlog.Change{"merged_into": {New: targetID}})
+	if err != nil {
+		return domain.UserMergeResult{}, fmt.Errorf("write audit log: %w", err)
+	}
+
+	err = auditlog.WriteChangeLog(ctx, executor, domain.EntityUser, strconv.Itoa(targetID.Int()), domain.ActionMerge, 0,
+		map[string]auditlog.Change{"merged_from": {New: sourceID}})
+	if err != nil {
+		return domain.UserMergeResult{}, fmt.Errorf("write audit log: %w", err)
+	}
+
+	return result, nil
+}
+
 //nolint:ireturn // it's ok here
 func (r *Repository) getExecutor(ctx context.Context) db.Tx {
 	if tx := db.TxFromContext(ctx); tx != nil {
@@ -316,3 +579,18 @@ func (r *Repository) getExecutor(ctx context.Context) db.Tx {
 
 	return r.db
 }
+
+// auditFields lists the user attributes recorded in audit log changes.
+func auditFields(user *domain.User) map[string]any {
+	return map[string]any{
+		"username":         user.Username,
+		"display_name":     user.DisplayName,
+		"email":            user.Email,
+		"password_hash":    user.PasswordHash,
+		"is_superuser":     user.IsSuperuser,
+		"is_active":        user.IsActive,
+		"is_tmp_password":  user.IsTmpPassword,
+		"is_external":      user.IsExternal,
+		"license_accepted": user.LicenseAccepted,
+	}
+}
@@ -0,0 +1,31 @@
+package variables
+
+import (
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type variableModel struct {
+	ProjectID      int       `db:"project_id"`
+	Name           string    `db:"name"`
+	ValueEncrypted string    `db:"value_encrypted"`
+	Secret         bool      `db:"secret"`
+	CreatedBy      string    `db:"created_by"`
+	CreatedAt      time.Time `db:"created_at"`
+	UpdatedBy      string    `db:"updated_by"`
+	UpdatedAt      time.Time `db:"updated_at"`
+}
+
+func (m *variableModel) toDomain() domain.ProjectVariable {
+	return domain.ProjectVariable{
+		ProjectID:      domain.ProjectID(m.ProjectID),
+		Name:           m.Name,
+		Secret:         m.Secret,
+		CreatedBy:      m.CreatedBy,
+		CreatedAt:      m.CreatedAt,
+		UpdatedBy:      m.UpdatedBy,
+		UpdatedAt:      m.UpdatedAt,
+		ValueEncrypted: m.ValueEncrypted,
+	}
+}
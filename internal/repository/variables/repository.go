@@ -0,0 +1,200 @@
+package variables
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/repository/auditlog"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.VariablesRepository = (*Repository)(nil)
+
+const variableColumns = `project_id, name, value_encrypted, secret, created_by, created_at, updated_by, updated_at`
+
+type Repository struct {
+	db db.Tx
+}
+
+func New(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		db: pool,
+	}
+}
+
+func (r *Repository) List(ctx context.Context, projectID domain.ProjectID) ([]domain.ProjectVariable, error) {
+	executor := r.getExecutor(ctx)
+
+	query := `
+SELECT ` + variableColumns + `
+FROM workflows_manager.project_variables
+WHERE project_id = $1
+ORDER BY name`
+
+	rows, err := executor.Query(ctx, query, projectID.Int())
+	if err != nil {
+		return nil, fmt.Errorf("query project variables: %w", err)
+	}
+	defer rows.Close()
+
+	listModels, err := pgx.CollectRows(rows, pgx.RowToStructByName[variableModel])
+	if err != nil {
+		return nil, fmt.Errorf("collect project variables: %w", err)
+	}
+
+	variables := make([]domain.ProjectVariable, 0, len(listModels))
+	for i := range listModels {
+		variables = append(variables, listModels[i].toDomain())
+	}
+
+	return variables, nil
+}
+
+func (r *Repository) Get(ctx context.Context, projectID domain.ProjectID, name string) (domain.ProjectVariable, error) {
+	executor := r.getExecutor(ctx)
+
+	query := `
+SELECT ` + variableColumns + `
+FROM workflows_manager.project_variables
+WHERE project_id = $1 AND name = $2`
+
+	rows, err := executor.Query(ctx, query, projectID.Int(), name)
+	if err != nil {
+		return domain.ProjectVariable{}, fmt.Errorf("query project variable: %w", err)
+	}
+	defer rows.Close()
+
+	model, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[variableModel])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ProjectVariable{}, domain.ErrEntityNotFound
+		}
+
+		return domain.ProjectVariable{}, fmt.Errorf("collect project variable: %w", err)
+	}
+
+	return model.toDomain(), nil
+}
+
+// ListSecretNames returns the names of the secret variables of the projects, by project.
+func (r *Repository) ListSecretNames(
+	ctx context.Context,
+	projectIDs []domain.ProjectID,
+) (map[domain.ProjectID][]string, error) {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+SELECT project_id, name
+FROM workflows_manager.project_variables
+WHERE project_id = ANY ($1) AND secret
+ORDER BY project_id, name`
+
+	ids := make([]int, 0, len(projectIDs))
+	for _, id := range projectIDs {
+		ids = append(ids, id.Int())
+	}
+
+	rows, err := executor.Query(ctx, query, ids)
+	if err != nil {
+		return nil, fmt.Errorf("query secret variable names: %w", err)
+	}
+	defer rows.Close()
+
+	names := make(map[domain.ProjectID][]string)
+	for rows.Next() {
+		var (
+			projectID int
+			name      string
+		)
+		if err := rows.Scan(&projectID, &name); err != nil {
+			return nil, fmt.Errorf("scan secret variable name: %w", err)
+		}
+
+		names[domain.ProjectID(projectID)] = append(names[domain.ProjectID(projectID)], name)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate secret variable names: %w", err)
+	}
+
+	return names, nil
+}
+
+func (r *Repository) Upsert(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	name string,
+	valueEncrypted string,
+	secret bool,
+) (bool, error) {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+INSERT INTO workflows_manager.project_variables
+    (project_id, name, value_encrypted, secret, created_by, updated_by)
+VALUES ($1, $2, $3, $4, $5, $5)
+ON CONFLICT (project_id, name) DO UPDATE
+SET value_encrypted = EXCLUDED.value_encrypted,
+    secret = EXCLUDED.secret,
+    updated_by = EXCLUDED.updated_by,
+    updated_at = NOW()
+RETURNING (xmax = 0) AS created`
+
+	var created bool
+	err := executor.QueryRow(ctx, query, projectID.Int(), name, valueEncrypted, secret, appcontext.Username(ctx)).
+		Scan(&created)
+	if err != nil {
+		return false, fmt.Errorf("upsert project variable: %w", err)
+	}
+
+	action := domain.ActionUpdate
+	if created {
+		action = domain.ActionCreate
+	}
+
+	err = auditlog.WriteLog(ctx, executor, domain.EntityProjectVariable, name, action, projectID)
+	if err != nil {
+		return false, fmt.Errorf("write audit log: %w", err)
+	}
+
+	return created, nil
+}
+
+func (r *Repository) Delete(ctx context.Context, projectID domain.ProjectID, name string) error {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+DELETE FROM workflows_manager.project_variables
+WHERE project_id = $1 AND name = $2`
+
+	tag, err := executor.Exec(ctx, query, projectID.Int(), name)
+	if err != nil {
+		return fmt.Errorf("delete project variable: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrEntityNotFound
+	}
+
+	err = auditlog.WriteLog(ctx, executor, domain.EntityProjectVariable, name, domain.ActionDelete, projectID)
+	if err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+
+	return nil
+}
+
+//nolint:ireturn // it's ok here
+func (r *Repository) getExecutor(ctx context.Context) db.Tx {
+	if tx := db.TxFromContext(ctx); tx != nil {
+		return tx
+	}
+
+	return r.db
+}
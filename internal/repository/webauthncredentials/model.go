@@ -0,0 +1,29 @@
+package webauthncredentials
+
+import (
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type credentialModel struct {
+	ID           int        `db:"id"`
+	UserID       int        `db:"user_id"`
+	Name         string     `db:"name"`
+	CredentialID []byte     `db:"credential_id"`
+	Credential   []byte     `db:"credential"`
+	LastUsedAt   *time.Time `db:"last_used_at"`
+	CreatedAt    time.Time  `db:"created_at"`
+}
+
+func (m *credentialModel) toDomain() domain.WebAuthnCredential {
+	return domain.WebAuthnCredential{
+		ID:           domain.WebAuthnCredentialID(m.ID),
+		UserID:       domain.UserID(m.UserID),
+		Name:         m.Name,
+		CredentialID: m.CredentialID,
+		Credential:   m.Credential,
+		LastUsedAt:   m.LastUsedAt,
+		CreatedAt:    m.CreatedAt,
+	}
+}
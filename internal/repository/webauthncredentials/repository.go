@@ -0,0 +1,150 @@
+package webauthncredentials
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/repository/auditlog"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.WebAuthnCredentialsRepository = (*Repository)(nil)
+
+const credentialColumns = `id, user_id, name, credential_id, credential, last_used_at, created_at`
+
+type Repository struct {
+	db db.Tx
+}
+
+func New(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		db: pool,
+	}
+}
+
+func (r *Repository) Create(
+	ctx context.Context,
+	cred domain.WebAuthnCredential,
+) (domain.WebAuthnCredential, error) {
+	executor := r.getExecutor(ctx)
+
+	query := `
+INSERT INTO workflows_manager.webauthn_credentials (user_id, name, credential_id, credential)
+VALUES ($1, $2, $3, $4)
+RETURNING ` + credentialColumns
+
+	rows, err := executor.Query(ctx, query, int(cred.UserID), cred.Name, cred.CredentialID, cred.Credential)
+	if err != nil {
+		return domain.WebAuthnCredential{}, fmt.Errorf("insert webauthn credential: %w", err)
+	}
+	defer rows.Close()
+
+	model, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[credentialModel])
+	if err != nil {
+		if db.IsUniqueViolation(err) {
+			return domain.WebAuthnCredential{}, domain.ErrEntityAlreadyExists
+		}
+
+		return domain.WebAuthnCredential{}, fmt.Errorf("collect webauthn credential: %w", err)
+	}
+
+	created := model.toDomain()
+
+	err = auditlog.WriteLog(ctx, executor, domain.EntityWebAuthnCredential, created.ID.String(), domain.ActionCreate, 0)
+	if err != nil {
+		return domain.WebAuthnCredential{}, fmt.Errorf("write audit log: %w", err)
+	}
+
+	return created, nil
+}
+
+func (r *Repository) List(ctx context.Context, userID domain.UserID) ([]domain.WebAuthnCredential, error) {
+	executor := r.getExecutor(ctx)
+
+	query := `
+SELECT ` + credentialColumns + `
+FROM workflows_manager.webauthn_credentials
+WHERE user_id = $1
+ORDER BY id`
+
+	rows, err := executor.Query(ctx, query, int(userID))
+	if err != nil {
+		return nil, fmt.Errorf("query webauthn credentials: %w", err)
+	}
+	defer rows.Close()
+
+	listModels, err := pgx.CollectRows(rows, pgx.RowToStructByName[credentialModel])
+	if err != nil {
+		return nil, fmt.Errorf("collect webauthn credentials: %w", err)
+	}
+
+	creds := make([]domain.WebAuthnCredential, 0, len(listModels))
+	for i := range listModels {
+		creds = append(creds, listModels[i].toDomain())
+	}
+
+	return creds, nil
+}
+
+func (r *Repository) UpdateCredential(
+	ctx context.Context,
+	id domain.WebAuthnCredentialID,
+	credential []byte,
+	usedAt time.Time,
+) error {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+UPDATE workflows_manager.webauthn_credentials
+SET credential = $2, last_used_at = $3
+WHERE id = $1`
+
+	result, err := executor.Exec(ctx, query, id.Int(), credential, usedAt)
+	if err != nil {
+		return fmt.Errorf("update webauthn credential: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrEntityNotFound
+	}
+
+	return nil
+}
+
+func (r *Repository) Delete(ctx context.Context, userID domain.UserID, id domain.WebAuthnCredentialID) error {
+	executor := r.getExecutor(ctx)
+
+	result, err := executor.Exec(ctx,
+		`DELETE FROM workflows_manager.webauthn_credentials WHERE user_id = $1 AND id = $2`,
+		int(userID), id.Int(),
+	)
+	if err != nil {
+		return fmt.Errorf("delete webauthn credential: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrEntityNotFound
+	}
+
+	err = auditlog.WriteLog(ctx, executor, domain.EntityWebAuthnCredential, id.String(), domain.ActionDelete, 0)
+	if err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+
+	return nil
+}
+
+//nolint:ireturn // it's ok here
+func (r *Repository) getExecutor(ctx context.Context) db.Tx {
+	if tx := db.TxFromContext(ctx); tx != nil {
+		return tx
+	}
+
+	return r.db
+}
@@ -0,0 +1,82 @@
+package webhooks
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type webhookModel struct {
+	ID        int       `db:"id"`
+	ProjectID int       `db:"project_id"`
+	Name      string    `db:"name"`
+	URL       string    `db:"url"`
+	Events    []string  `db:"events"`
+	Enabled   bool      `db:"enabled"`
+	CreatedBy string    `db:"created_by"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func (m *webhookModel) toDomain() domain.Webhook {
+	events := make([]domain.LifecycleEventType, 0, len(m.Events))
+	for _, event := range m.Events {
+		events = append(events, domain.LifecycleEventType(event))
+	}
+
+	return domain.Webhook{
+		ID:        domain.WebhookID(m.ID),
+		ProjectID: domain.ProjectID(m.ProjectID),
+		Name:      m.Name,
+		URL:       m.URL,
+		Events:    events,
+		Enabled:   m.Enabled,
+		CreatedBy: m.CreatedBy,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
+
+type deliveryModel struct {
+	ID             int64          `db:"id"`
+	WebhookID      int            `db:"webhook_id"`
+	EventType      string         `db:"event_type"`
+	EventKey       string         `db:"event_key"`
+	Payload        []byte         `db:"payload"`
+	Status         string         `db:"status"`
+	Attempts       int            `db:"attempts"`
+	NextAttemptAt  *time.Time     `db:"next_attempt_at"`
+	LastStatusCode *int           `db:"last_status_code"`
+	LastError      sql.NullString `db:"last_error"`
+	CreatedAt      time.Time      `db:"created_at"`
+	DeliveredAt    *time.Time     `db:"delivered_at"`
+}
+
+func (m *deliveryModel) toDomain() domain.WebhookDelivery {
+	var lastError *string
+	if m.LastError.Valid {
+		lastError = &m.LastError.String
+	}
+
+	return domain.WebhookDelivery{
+		ID:             m.ID,
+		WebhookID:      domain.WebhookID(m.WebhookID),
+		EventType:      domain.LifecycleEventType(m.EventType),
+		EventKey:       m.EventKey,
+		Payload:        json.RawMessage(m.Payload),
+		Status:         domain.WebhookDeliveryStatus(m.Status),
+		Attempts:       m.Attempts,
+		NextAttemptAt:  m.NextAttemptAt,
+		LastStatusCode: m.LastStatusCode,
+		LastError:      lastError,
+		CreatedAt:      m.CreatedAt,
+		DeliveredAt:    m.DeliveredAt,
+	}
+}
+
+type dueDeliveryModel struct {
+	deliveryModel
+	URL string `db:"url"`
+}
@@ -0,0 +1,353 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/repository/auditlog"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.WebhooksRepository = (*Repository)(nil)
+
+const (
+	webhookColumns  = `id, project_id, name, url, events, enabled, created_by, created_at, updated_at`
+	deliveryColumns = `d.id, d.webhook_id, d.event_type, d.event_key, d.payload, d.status, d.attempts,
+d.next_attempt_at, d.last_status_code, d.last_error, d.created_at, d.delivered_at`
+)
+
+type Repository struct {
+	db db.Tx
+}
+
+func New(pool *pgxpool.Pool) *Repository {
+	return &Repository{
+		db: pool,
+	}
+}
+
+func (r *Repository) List(ctx context.Context, projectID domain.ProjectID) ([]domain.Webhook, error) {
+	query := `
+SELECT ` + webhookColumns + `
+FROM workflows_manager.notification_webhooks
+WHERE project_id = $1
+ORDER BY id`
+
+	return r.getMany(ctx, query, projectID.Int())
+}
+
+func (r *Repository) GetByID(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	id domain.WebhookID,
+) (domain.Webhook, error) {
+	executor := r.getExecutor(ctx)
+
+	query := `
+SELECT ` + webhookColumns + `
+FROM workflows_manager.notification_webhooks
+WHERE project_id = $1 AND id = $2
+LIMIT 1`
+
+	rows, err := executor.Query(ctx, query, projectID.Int(), id.Int())
+	if err != nil {
+		return domain.Webhook{}, fmt.Errorf("query webhook: %w", err)
+	}
+	defer rows.Close()
+
+	model, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[webhookModel])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Webhook{}, domain.ErrEntityNotFound
+		}
+
+		return domain.Webhook{}, fmt.Errorf("collect webhook: %w", err)
+	}
+
+	return model.toDomain(), nil
+}
+
+func (r *Repository) Create(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	dto domain.WebhookDTO,
+) (domain.WebhookID, error) {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+INSERT INTO workflows_manager.notification_webhooks (project_id, name, url, events, enabled, created_by)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id`
+
+	var id int
+	err := executor.QueryRow(ctx, query,
+		projectID.Int(),
+		dto.Name,
+		dto.URL,
+		eventsToStrings(dto.Events),
+		dto.Enabled,
+		appcontext.Username(ctx),
+	).Scan(&id)
+	if err != nil {
+		if db.IsUniqueViolation(err) {
+			return 0, domain.ErrEntityAlreadyExists
+		}
+
+		return 0, fmt.Errorf("insert webhook: %w", err)
+	}
+
+	webhookID := domain.WebhookID(id)
+	if err := auditlog.WriteLog(ctx, executor, domain.EntityWebhook, webhookID.String(), domain.ActionCreate, projectID); err != nil {
+		return 0, fmt.Errorf("write audit log: %w", err)
+	}
+
+	return webhookID, nil
+}
+
+func (r *Repository) Update(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	id domain.WebhookID,
+	dto domain.WebhookDTO,
+) error {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+UPDATE workflows_manager.notification_webhooks
+SET name = $3, url = $4, events = $5, enabled = $6, updated_at = NOW()
+WHERE project_id = $1 AND id = $2`
+
+	result, err := executor.Exec(ctx, query,
+		projectID.Int(),
+		id.Int(),
+		dto.Name,
+		dto.URL,
+		eventsToStrings(dto.Events),
+		dto.Enabled,
+	)
+	if err != nil {
+		if db.IsUniqueViolation(err) {
+			return domain.ErrEntityAlreadyExists
+		}
+
+		return fmt.Errorf("update webhook: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrEntityNotFound
+	}
+
+	if err := auditlog.WriteLog(ctx, executor, domain.EntityWebhook, id.String(), domain.ActionUpdate, projectID); err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) Delete(ctx context.Context, projectID domain.ProjectID, id domain.WebhookID) error {
+	executor := r.getExecutor(ctx)
+
+	result, err := executor.Exec(ctx,
+		`DELETE FROM workflows_manager.notification_webhooks WHERE project_id = $1 AND id = $2`,
+		projectID.Int(), id.Int(),
+	)
+	if err != nil {
+		return fmt.Errorf("delete webhook: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrEntityNotFound
+	}
+
+	if err := auditlog.WriteLog(ctx, executor, domain.EntityWebhook, id.String(), domain.ActionDelete, projectID); err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) ListSubscribed(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	eventType domain.LifecycleEventType,
+) ([]domain.Webhook, error) {
+	query := `
+SELECT ` + webhookColumns + `
+FROM workflows_manager.notification_webhooks
+WHERE project_id = $1 AND enabled AND $2 = ANY (events)
+ORDER BY id`
+
+	return r.getMany(ctx, query, projectID.Int(), string(eventType))
+}
+
+func (r *Repository) EnqueueDelivery(ctx context.Context, delivery domain.WebhookDelivery) (bool, error) {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+INSERT INTO workflows_manager.notification_deliveries
+    (webhook_id, event_type, event_key, payload, status, next_attempt_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (webhook_id, event_key) DO NOTHING`
+
+	result, err := executor.Exec(ctx, query,
+		delivery.WebhookID.Int(),
+		string(delivery.EventType),
+		delivery.EventKey,
+		[]byte(delivery.Payload),
+		string(domain.WebhookDeliveryPending),
+		delivery.NextAttemptAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("insert webhook delivery: %w", err)
+	}
+
+	return result.RowsAffected() > 0, nil
+}
+
+func (r *Repository) ListDueDeliveries(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+) ([]domain.WebhookDelivery, error) {
+	executor := r.getExecutor(ctx)
+
+	query := `
+SELECT ` + deliveryColumns + `, w.url
+FROM workflows_manager.notification_deliveries d
+JOIN workflows_manager.notification_webhooks w ON w.id = d.webhook_id
+WHERE d.status = $1 AND d.next_attempt_at <= $2
+ORDER BY d.next_attempt_at
+LIMIT $3
+FOR UPDATE OF d SKIP LOCKED`
+
+	rows, err := executor.Query(ctx, query, string(domain.WebhookDeliveryPending), now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query due webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	listModels, err := pgx.CollectRows(rows, pgx.RowToStructByName[dueDeliveryModel])
+	if err != nil {
+		return nil, fmt.Errorf("collect due webhook deliveries: %w", err)
+	}
+
+	deliveries := make([]domain.WebhookDelivery, 0, len(listModels))
+	for i := range listModels {
+		delivery := listModels[i].toDomain()
+		delivery.URL = listModels[i].URL
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, nil
+}
+
+func (r *Repository) SaveDeliveryAttempt(ctx context.Context, delivery domain.WebhookDelivery) error {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+UPDATE workflows_manager.notification_deliveries
+SET status = $2,
+    attempts = $3,
+    next_attempt_at = $4,
+    last_status_code = $5,
+    last_error = $6,
+    delivered_at = $7
+WHERE id = $1`
+
+	_, err := executor.Exec(ctx, query,
+		delivery.ID,
+		string(delivery.Status),
+		delivery.Attempts,
+		delivery.NextAttemptAt,
+		delivery.LastStatusCode,
+		delivery.LastError,
+		delivery.DeliveredAt,
+	)
+	if err != nil {
+		return fmt.Errorf("update webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) ListDeliveries(
+	ctx context.Context,
+	webhookID domain.WebhookID,
+	page, pageSize int,
+) ([]domain.WebhookDelivery, int, error) {
+	executor := r.getExecutor(ctx)
+
+	offset := (page - 1) * pageSize
+
+	query := `
+SELECT ` + deliveryColumns + `
+FROM workflows_manager.notification_deliveries d
+WHERE d.webhook_id = $1
+ORDER BY d.created_at DESC, d.id DESC
+LIMIT $2 OFFSET $3`
+
+	listModels, total, err := db.QueryPage(ctx, executor, db.PageQuery{
+		CountSQL:  `SELECT COUNT(*) FROM workflows_manager.notification_deliveries WHERE webhook_id = $1`,
+		CountArgs: []any{webhookID.Int()},
+		SQL:       query,
+		Args:      []any{webhookID.Int(), pageSize, offset},
+	}, pgx.RowToStructByName[deliveryModel])
+	if err != nil {
+		return nil, 0, fmt.Errorf("list webhook deliveries: %w", err)
+	}
+
+	deliveries := make([]domain.WebhookDelivery, 0, len(listModels))
+	for i := range listModels {
+		deliveries = append(deliveries, listModels[i].toDomain())
+	}
+
+	return deliveries, total, nil
+}
+
+func (r *Repository) getMany(ctx context.Context, query string, args ...any) ([]domain.Webhook, error) {
+	executor := r.getExecutor(ctx)
+
+	rows, err := executor.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	listModels, err := pgx.CollectRows(rows, pgx.RowToStructByName[webhookModel])
+	if err != nil {
+		return nil, fmt.Errorf("collect webhooks: %w", err)
+	}
+
+	webhooks := make([]domain.Webhook, 0, len(listModels))
+	for i := range listModels {
+		webhooks = append(webhooks, listModels[i].toDomain())
+	}
+
+	return webhooks, nil
+}
+
+//nolint:ireturn // it's ok here
+func (r *Repository) getExecutor(ctx context.Context) db.Tx {
+	if tx := db.TxFromContext(ctx); tx != nil {
+		return tx
+	}
+
+	return r.db
+}
+
+func eventsToStrings(events []domain.LifecycleEventType) []string {
+	result := make([]string, 0, len(events))
+	for _, event := range events {
+		result = append(result, string(event))
+	}
+
+	return result
+}
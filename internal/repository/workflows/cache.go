@@ -0,0 +1,93 @@
+package workflows
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/db"
+	simplecache "github.com/rom8726/floxy-manager/pkg/simple-cache"
+)
+
+const (
+	// definitionCacheTTL bounds how long a definition may be stale after a change on another replica.
+	// Changes made through this replica invalidate the cache right away.
+	definitionCacheTTL = 30 * time.Second
+
+	definitionCacheCleanupInterval = time.Minute
+)
+
+var definitionCacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "floxy_manager",
+	Subsystem: "cache",
+	Name:      "requests_total",
+	Help:      "Cache lookups by cache and result (hit or miss).",
+}, []string{"cache", "result"})
+
+type definitionLookup uint8
+
+const (
+	lookupByID definitionLookup = iota + 1
+	lookupByVersion
+	lookupAnyProject
+)
+
+type definitionKey struct {
+	lookup    definitionLookup
+	tenantID  domain.TenantID
+	projectID domain.ProjectID
+	id        string
+	name      string
+	version   int
+}
+
+// definitionCache keeps workflow definitions read by ID or by name and version.
+// Definitions rarely change but are read by every graph view.
+type definitionCache struct {
+	items *simplecache.Cache[definitionKey, domain.WorkflowDefinition]
+}
+
+func newDefinitionCache() *definitionCache {
+	items := simplecache.New[definitionKey, domain.WorkflowDefinition]()
+	items.StartCleanup(definitionCacheCleanupInterval)
+
+	return &definitionCache{items: items}
+}
+
+// get returns the cached definition or loads and caches it. Reads in a transaction bypass the cache,
+// the transaction may see changes that are not committed yet.
+func (c *definitionCache) get(
+	ctx context.Context,
+	key definitionKey,
+	load func() (domain.WorkflowDefinition, error),
+) (domain.WorkflowDefinition, error) {
+	if db.TxFromContext(ctx) != nil {
+		return load()
+	}
+
+	if definition, ok := c.items.Get(key); ok {
+		definitionCacheRequests.WithLabelValues("workflow_definitions", "hit").Inc()
+
+		return definition, nil
+	}
+
+	definitionCacheRequests.WithLabelValues("workflow_definitions", "miss").Inc()
+
+	definition, err := load()
+	if err != nil {
+		return domain.WorkflowDefinition{}, err
+	}
+
+	c.items.Set(key, definition, definitionCacheTTL)
+
+	return definition, nil
+}
+
+// invalidate drops every cached definition, a change of one definition or assignment
+// affects the lookups by ID, by version and across projects.
+func (c *definitionCache) invalidate() {
+	c.items.Clear()
+}
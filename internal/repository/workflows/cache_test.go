@@ -0,0 +1,52 @@
+package workflows
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+func TestDefinitionCache_Get(t *testing.T) {
+	cache := newDefinitionCache()
+	key := definitionKey{lookup: lookupByID, tenantID: 1, projectID: 2, id: "orders-v1"}
+
+	loads := 0
+	load := func() (domain.WorkflowDefinition, error) {
+		loads++
+
+		return domain.WorkflowDefinition{ID: "orders-v1", Version: loads}, nil
+	}
+
+	definition, err := cache.get(context.Background(), key, load)
+	require.NoError(t, err)
+	assert.Equal(t, 1, definition.Version)
+
+	definition, err = cache.get(context.Background(), key, load)
+	require.NoError(t, err)
+	assert.Equal(t, 1, definition.Version)
+	assert.Equal(t, 1, loads)
+
+	cache.invalidate()
+
+	definition, err = cache.get(context.Background(), key, load)
+	require.NoError(t, err)
+	assert.Equal(t, 2, definition.Version)
+
+	// Errors are not cached
+	errLoad := errors.New("load failed")
+	otherKey := definitionKey{lookup: lookupAnyProject, name: "orders", version: 1}
+
+	_, err = cache.get(context.Background(), otherKey, func() (domain.WorkflowDefinition, error) {
+		return domain.WorkflowDefinition{}, errLoad
+	})
+	require.ErrorIs(t, err, errLoad)
+
+	definition, err = cache.get(context.Background(), otherKey, load)
+	require.NoError(t, err)
+	assert.Equal(t, 3, definition.Version)
+}
@@ -0,0 +1,28 @@
+package workflows
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+// keyColumns are selected regardless of the field set: they identify rows and build cursors.
+var keyColumns = []string{"tenant_id", "project_id", "id", "created_at"}
+
+// selectColumns returns the select list of the columns matching fields. Rows are scanned with
+// pgx.RowToStructByNameLax, so skipped columns keep zero values.
+func selectColumns(columns []string, fields domain.FieldSet) string {
+	if fields.All() {
+		return "*"
+	}
+
+	selected := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if fields.Has(column) || slices.Contains(keyColumns, column) {
+			selected = append(selected, column)
+		}
+	}
+
+	return strings.Join(selected, ", ")
+}
@@ -0,0 +1,24 @@
+package workflows
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+func TestSelectColumns(t *testing.T) {
+	assert.Equal(t, "*", selectColumns(workflowInstanceColumns, domain.FieldSet{}))
+
+	assert.Equal(t,
+		"tenant_id, project_id, id, workflow_id, status, error, started_at, completed_at, created_at, updated_at, "+
+			"parent_instance_id",
+		selectColumns(workflowInstanceColumns, domain.FieldSet{Exclude: []string{"input", "output"}}),
+	)
+
+	assert.Equal(t,
+		"tenant_id, project_id, id, status, created_at",
+		selectColumns(workflowInstanceColumns, domain.FieldSet{Include: []string{"status", "id"}}),
+	)
+}
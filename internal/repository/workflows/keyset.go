@@ -0,0 +1,44 @@
+package workflows
+
+import (
+	"fmt"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+// keysetQuery builds a query of the columns of view rows matching conditions, newest first,
+// after the cursor. One extra row is requested to find out whether there is a next page.
+func keysetQuery(
+	view string,
+	columns string,
+	conditions string,
+	args []interface{},
+	cursor *domain.Cursor,
+	limit int,
+) (string, []interface{}) {
+	if cursor != nil {
+		args = append(args, cursor.CreatedAt, cursor.ID)
+		conditions += fmt.Sprintf(" AND (created_at, id) < ($%d, $%d)", len(args)-1, len(args))
+	}
+
+	args = append(args, limit+1)
+	query := fmt.Sprintf(`
+SELECT %s FROM %s
+WHERE %s
+ORDER BY created_at DESC, id DESC
+LIMIT $%d`, columns, view, conditions, len(args))
+
+	return query, args
+}
+
+// keysetPage trims the extra row requested by keysetQuery and returns the cursor of the next page.
+func keysetPage[T any](items []T, limit int, cursorOf func(*T) domain.Cursor) ([]T, *domain.Cursor) {
+	if len(items) <= limit {
+		return items, nil
+	}
+
+	items = items[:limit]
+	next := cursorOf(&items[limit-1])
+
+	return items, &next
+}
@@ -0,0 +1,56 @@
+package workflows
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+func TestKeysetQuery(t *testing.T) {
+	cursor := &domain.Cursor{CreatedAt: time.Date(2025, 1, 2, 3, 4, 5, 6000, time.UTC), ID: 42}
+
+	query, args := keysetQuery("workflows_manager.v_workflow_dlq", "*", "tenant_id = $1 AND project_id = $2",
+		[]interface{}{1, 2}, cursor, 20)
+
+	assert.Contains(t, query, "WHERE tenant_id = $1 AND project_id = $2 AND (created_at, id) < ($3, $4)")
+	assert.Contains(t, query, "ORDER BY created_at DESC, id DESC\nLIMIT $5")
+	assert.Equal(t, []interface{}{1, 2, cursor.CreatedAt, 42, 21}, args)
+
+	query, args = keysetQuery("workflows_manager.v_workflow_dlq", "id, created_at", "tenant_id = $1",
+		[]interface{}{1}, nil, 10)
+	assert.NotContains(t, query, "(created_at, id)")
+	assert.Contains(t, query, "SELECT id, created_at FROM")
+	assert.Equal(t, []interface{}{1, 11}, args)
+}
+
+func TestKeysetPage(t *testing.T) {
+	now := time.Now()
+	items := []domain.DLQItem{{ID: 3, CreatedAt: now}, {ID: 2, CreatedAt: now}, {ID: 1, CreatedAt: now}}
+	cursorOf := func(item *domain.DLQItem) domain.Cursor {
+		return domain.Cursor{CreatedAt: item.CreatedAt, ID: item.ID}
+	}
+
+	page, next := keysetPage(items, 2, cursorOf)
+	assert.Len(t, page, 2)
+	require.NotNil(t, next)
+	assert.Equal(t, 2, next.ID)
+
+	page, next = keysetPage(items, 3, cursorOf)
+	assert.Len(t, page, 3)
+	assert.Nil(t, next)
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	cursor := domain.Cursor{CreatedAt: time.Date(2025, 1, 2, 3, 4, 5, 123456000, time.UTC), ID: 7}
+
+	parsed, err := domain.ParseCursor(cursor.String())
+	require.NoError(t, err)
+	assert.Equal(t, cursor, parsed)
+
+	_, err = domain.ParseCursor("not a cursor")
+	assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+}
@@ -40,38 +40,53 @@ func (m *workflowDefinitionModel) toDomain() domain.WorkflowDefinition {
 	}
 }
 
+// workflowInstanceColumns are the columns of v_workflow_instances in workflowInstanceModel.
+var workflowInstanceColumns = []string{
+	"tenant_id", "project_id", "id", "workflow_id", "status", "input", "output", "error",
+	"started_at", "completed_at", "created_at", "updated_at", "parent_instance_id",
+}
+
 type workflowInstanceModel struct {
-	TenantID    int            `db:"tenant_id"`
-	ProjectID   int            `db:"project_id"`
-	ID          int            `db:"id"`
-	WorkflowID  string         `db:"workflow_id"`
-	Status      string         `db:"status"`
-	Input       sql.NullString `db:"input"`
-	Output      sql.NullString `db:"output"`
-	Error       sql.NullString `db:"error"`
-	StartedAt   sql.NullTime   `db:"started_at"`
-	CompletedAt sql.NullTime   `db:"completed_at"`
-	CreatedAt   time.Time      `db:"created_at"`
-	UpdatedAt   time.Time      `db:"updated_at"`
+	TenantID         int            `db:"tenant_id"`
+	ProjectID        int            `db:"project_id"`
+	ID               int            `db:"id"`
+	WorkflowID       string         `db:"workflow_id"`
+	Status           string         `db:"status"`
+	Input            sql.NullString `db:"input"`
+	Output           sql.NullString `db:"output"`
+	Error            sql.NullString `db:"error"`
+	StartedAt        sql.NullTime   `db:"started_at"`
+	CompletedAt      sql.NullTime   `db:"completed_at"`
+	CreatedAt        time.Time      `db:"created_at"`
+	UpdatedAt        time.Time      `db:"updated_at"`
+	ParentInstanceID *int           `db:"parent_instance_id"`
 }
 
 func (m *workflowInstanceModel) toDomain() domain.WorkflowInstance {
 	return domain.WorkflowInstance{
-		TenantID:    domain.TenantID(m.TenantID),
-		ProjectID:   domain.ProjectID(m.ProjectID),
-		ID:          m.ID,
-		WorkflowID:  m.WorkflowID,
-		Status:      m.Status,
-		Input:       parseJSONB(m.Input),
-		Output:      parseJSONB(m.Output),
-		Error:       m.Error,
-		StartedAt:   m.StartedAt,
-		CompletedAt: m.CompletedAt,
-		CreatedAt:   m.CreatedAt,
-		UpdatedAt:   m.UpdatedAt,
+		TenantID:         domain.TenantID(m.TenantID),
+		ProjectID:        domain.ProjectID(m.ProjectID),
+		ID:               m.ID,
+		WorkflowID:       m.WorkflowID,
+		Status:           m.Status,
+		Input:            parseJSONB(m.Input),
+		Output:           parseJSONB(m.Output),
+		Error:            m.Error,
+		StartedAt:        m.StartedAt,
+		CompletedAt:      m.CompletedAt,
+		CreatedAt:        m.CreatedAt,
+		UpdatedAt:        m.UpdatedAt,
+		ParentInstanceID: m.ParentInstanceID,
 	}
 }
 
+// workflowStepColumns are the columns of v_workflow_steps in workflowStepModel.
+var workflowStepColumns = []string{
+	"tenant_id", "project_id", "id", "instance_id", "step_name", "step_type", "status", "input", "output",
+	"error", "retry_count", "max_retries", "compensation_retry_count", "idempotency_key",
+	"started_at", "completed_at", "created_at",
+}
+
 type workflowStepModel struct {
 	TenantID               int            `db:"tenant_id"`
 	ProjectID              int            `db:"project_id"`
@@ -232,3 +247,73 @@ func (m *dlqItemModel) toDomain() domain.DLQItem {
 		CreatedAt:  m.CreatedAt,
 	}
 }
+
+type workflowFailureGroupModel struct {
+	StepName       string         `db:"step_name"`
+	ErrorSignature string         `db:"error_signature"`
+	SampleError    sql.NullString `db:"sample_error"`
+	Failures       int            `db:"failures"`
+	Instances      int            `db:"instances"`
+	LastOccurredAt time.Time      `db:"last_occurred_at"`
+}
+
+func (m *workflowFailureGroupModel) toDomain() domain.WorkflowFailureGroup {
+	return domain.WorkflowFailureGroup{
+		StepName:       m.StepName,
+		ErrorSignature: m.ErrorSignature,
+		SampleError:    m.SampleError.String,
+		Failures:       m.Failures,
+		Instances:      m.Instances,
+		LastOccurredAt: m.LastOccurredAt,
+	}
+}
+
+type workflowActiveVersionModel struct {
+	ProjectID    int       `db:"project_id"`
+	WorkflowName string    `db:"workflow_name"`
+	Version      int       `db:"version"`
+	WorkflowID   string    `db:"workflow_id"`
+	UpdatedBy    string    `db:"updated_by"`
+	UpdatedAt    time.Time `db:"updated_at"`
+}
+
+func (m *workflowActiveVersionModel) toDomain() domain.WorkflowActiveVersion {
+	return domain.WorkflowActiveVersion{
+		ProjectID:    domain.ProjectID(m.ProjectID),
+		WorkflowName: m.WorkflowName,
+		Version:      m.Version,
+		WorkflowID:   m.WorkflowID,
+		UpdatedBy:    m.UpdatedBy,
+		UpdatedAt:    m.UpdatedAt,
+	}
+}
+
+type workflowRolloutModel struct {
+	ID           int           `db:"id"`
+	ProjectID    int           `db:"project_id"`
+	WorkflowName string        `db:"workflow_name"`
+	FromVersion  sql.NullInt32 `db:"from_version"`
+	ToVersion    int           `db:"to_version"`
+	Action       string        `db:"action"`
+	CreatedBy    string        `db:"created_by"`
+	CreatedAt    time.Time     `db:"created_at"`
+}
+
+func (m *workflowRolloutModel) toDomain() domain.WorkflowRollout {
+	rollout := domain.WorkflowRollout{
+		ID:           m.ID,
+		ProjectID:    domain.ProjectID(m.ProjectID),
+		WorkflowName: m.WorkflowName,
+		ToVersion:    m.ToVersion,
+		Action:       m.Action,
+		CreatedBy:    m.CreatedBy,
+		CreatedAt:    m.CreatedAt,
+	}
+
+	if m.FromVersion.Valid {
+		from := int(m.FromVersion.Int32)
+		rollout.FromVersion = &from
+	}
+
+	return rollout
+}
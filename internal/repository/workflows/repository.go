@@ -5,22 +5,30 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
 	"github.com/rom8726/floxy-manager/internal/domain"
 	"github.com/rom8726/floxy-manager/internal/repository/auditlog"
+	"github.com/rom8726/floxy-manager/internal/repository/tenantschemas"
 	"github.com/rom8726/floxy-manager/pkg/db"
 )
 
 type Repository struct {
-	db db.Tx
+	db          db.Tx
+	schemas     *tenantschemas.Schemas
+	definitions *definitionCache
 }
 
-func New(pool *pgxpool.Pool) *Repository {
+func New(pool *pgxpool.Pool, schemas *tenantschemas.Schemas) *Repository {
 	return &Repository{
-		db: pool,
+		db:          pool,
+		schemas:     schemas,
+		definitions: newDefinitionCache(),
 	}
 }
 
@@ -31,7 +39,7 @@ func (r *Repository) ListWorkflowDefinitions(
 	projectID domain.ProjectID,
 	page, pageSize int,
 ) ([]domain.WorkflowDefinition, int, error) {
-	executor := r.getExecutor(ctx)
+	executor := r.tenantExecutor(ctx, tenantID)
 
 	offset := (page - 1) * pageSize
 
@@ -40,12 +48,6 @@ func (r *Repository) ListWorkflowDefinitions(
 SELECT COUNT(*) FROM workflows_manager.v_workflow_definitions 
 WHERE tenant_id = $1 AND project_id = $2`
 
-	var total int
-	err := executor.QueryRow(ctx, countQuery, tenantID.Int(), projectID.Int()).Scan(&total)
-	if err != nil {
-		return nil, 0, fmt.Errorf("count workflow definitions: %w", err)
-	}
-
 	// Fetch items
 	const query = `
 SELECT * FROM workflows_manager.v_workflow_definitions 
@@ -53,15 +55,111 @@ WHERE tenant_id = $1 AND project_id = $2
 ORDER BY created_at DESC
 LIMIT $3 OFFSET $4`
 
-	rows, err := executor.Query(ctx, query, tenantID.Int(), projectID.Int(), pageSize, offset)
+	listModels, total, err := db.QueryPage(ctx, executor, db.PageQuery{
+		CountSQL:  countQuery,
+		CountArgs: []any{tenantID.Int(), projectID.Int()},
+		SQL:       query,
+		Args:      []any{tenantID.Int(), projectID.Int(), pageSize, offset},
+	}, pgx.RowToStructByName[workflowDefinitionModel])
+	if err != nil {
+		return nil, 0, fmt.Errorf("list workflow definitions: %w", err)
+	}
+
+	definitions := make([]domain.WorkflowDefinition, 0, len(listModels))
+	for i := range listModels {
+		definitions = append(definitions, listModels[i].toDomain())
+	}
+
+	return definitions, total, nil
+}
+
+// FindWorkflowDefinition returns the definition with the name and version in any project.
+// TenantID and ProjectID of an unassigned definition are zero.
+func (r *Repository) FindWorkflowDefinition(
+	ctx context.Context,
+	name string,
+	version int,
+) (domain.WorkflowDefinition, error) {
+	key := definitionKey{lookup: lookupAnyProject, name: name, version: version}
+
+	return r.definitions.get(ctx, key, func() (domain.WorkflowDefinition, error) {
+		return r.findWorkflowDefinition(ctx, name, version)
+	})
+}
+
+func (r *Repository) findWorkflowDefinition(
+	ctx context.Context,
+	name string,
+	version int,
+) (domain.WorkflowDefinition, error) {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+SELECT coalesce(p.tenant_id, 0) AS tenant_id, coalesce(pw.project_id, 0) AS project_id,
+       wd.id, wd.name, wd.version, wd.definition, wd.created_at
+FROM workflows.workflow_definitions wd
+         LEFT JOIN workflows_manager.project_workflows pw ON pw.workflow_definition_id = wd.id
+         LEFT JOIN workflows_manager.projects p ON p.id = pw.project_id
+WHERE wd.name = $1 AND wd.version = $2
+LIMIT 1`
+
+	rows, err := executor.Query(ctx, query, name, version)
 	if err != nil {
-		return nil, 0, fmt.Errorf("query workflow definitions: %w", err)
+		return domain.WorkflowDefinition{}, fmt.Errorf("query workflow definition: %w", err)
 	}
 	defer rows.Close()
 
-	listModels, err := pgx.CollectRows(rows, pgx.RowToStructByName[workflowDefinitionModel])
+	model, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[workflowDefinitionModel])
 	if err != nil {
-		return nil, 0, fmt.Errorf("collect workflow definitions: %w", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.WorkflowDefinition{}, domain.ErrEntityNotFound
+		}
+		return domain.WorkflowDefinition{}, fmt.Errorf("collect workflow definition: %w", err)
+	}
+
+	return model.toDomain(), nil
+}
+
+// SearchWorkflowDefinitions returns workflow definitions having a step that matches the query
+func (r *Repository) SearchWorkflowDefinitions(
+	ctx context.Context,
+	tenantID domain.TenantID,
+	projectID domain.ProjectID,
+	query domain.WorkflowDefinitionSearchQuery,
+	page, pageSize int,
+) ([]domain.WorkflowDefinition, int, error) {
+	executor := r.tenantExecutor(ctx, tenantID)
+
+	offset := (page - 1) * pageSize
+
+	var text string
+	if query.Text != "" {
+		text = "%" + escapeLike(query.Text) + "%"
+	}
+
+	const condition = `
+WHERE tenant_id = $1 AND project_id = $2 AND EXISTS (
+	SELECT 1 FROM jsonb_each(CASE WHEN jsonb_typeof(definition->'steps') = 'object'
+		THEN definition->'steps' ELSE '{}'::jsonb END) AS s(name, step)
+	WHERE ($3 = '' OR s.step->>'type' = $3)
+	  AND ($4 = '' OR s.step->>'handler' = $4)
+	  AND ($5 = '' OR s.name ILIKE $5 OR s.step::text ILIKE $5)
+)`
+
+	args := []interface{}{tenantID.Int(), projectID.Int(), query.StepType, query.Handler, text}
+
+	listQuery := `SELECT * FROM workflows_manager.v_workflow_definitions` + condition + `
+ORDER BY name, version DESC
+LIMIT $6 OFFSET $7`
+
+	listModels, total, err := db.QueryPage(ctx, executor, db.PageQuery{
+		CountSQL:  `SELECT COUNT(*) FROM workflows_manager.v_workflow_definitions` + condition,
+		CountArgs: args,
+		SQL:       listQuery,
+		Args:      append(args, pageSize, offset),
+	}, pgx.RowToStructByName[workflowDefinitionModel])
+	if err != nil {
+		return nil, 0, fmt.Errorf("search workflow definitions: %w", err)
 	}
 
 	definitions := make([]domain.WorkflowDefinition, 0, len(listModels))
@@ -79,7 +177,20 @@ func (r *Repository) GetWorkflowDefinition(
 	projectID domain.ProjectID,
 	id string,
 ) (domain.WorkflowDefinition, error) {
-	executor := r.getExecutor(ctx)
+	key := definitionKey{lookup: lookupByID, tenantID: tenantID, projectID: projectID, id: id}
+
+	return r.definitions.get(ctx, key, func() (domain.WorkflowDefinition, error) {
+		return r.getWorkflowDefinition(ctx, tenantID, projectID, id)
+	})
+}
+
+func (r *Repository) getWorkflowDefinition(
+	ctx context.Context,
+	tenantID domain.TenantID,
+	projectID domain.ProjectID,
+	id string,
+) (domain.WorkflowDefinition, error) {
+	executor := r.tenantExecutor(ctx, tenantID)
 
 	const query = `
 SELECT * FROM workflows_manager.v_workflow_definitions 
@@ -103,17 +214,65 @@ LIMIT 1`
 	return model.toDomain(), nil
 }
 
+// GetWorkflowDefinitionByVersion returns a workflow definition by name and version
+func (r *Repository) GetWorkflowDefinitionByVersion(
+	ctx context.Context,
+	tenantID domain.TenantID,
+	projectID domain.ProjectID,
+	name string,
+	version int,
+) (domain.WorkflowDefinition, error) {
+	key := definitionKey{lookup: lookupByVersion, tenantID: tenantID, projectID: projectID, name: name, version: version}
+
+	return r.definitions.get(ctx, key, func() (domain.WorkflowDefinition, error) {
+		return r.getWorkflowDefinitionByVersion(ctx, tenantID, projectID, name, version)
+	})
+}
+
+func (r *Repository) getWorkflowDefinitionByVersion(
+	ctx context.Context,
+	tenantID domain.TenantID,
+	projectID domain.ProjectID,
+	name string,
+	version int,
+) (domain.WorkflowDefinition, error) {
+	executor := r.tenantExecutor(ctx, tenantID)
+
+	const query = `
+SELECT * FROM workflows_manager.v_workflow_definitions 
+WHERE tenant_id = $1 AND project_id = $2 AND name = $3 AND version = $4
+LIMIT 1`
+
+	rows, err := executor.Query(ctx, query, tenantID.Int(), projectID.Int(), name, version)
+	if err != nil {
+		return domain.WorkflowDefinition{}, fmt.Errorf("query workflow definition: %w", err)
+	}
+	defer rows.Close()
+
+	model, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[workflowDefinitionModel])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.WorkflowDefinition{}, domain.ErrEntityNotFound
+		}
+		return domain.WorkflowDefinition{}, fmt.Errorf("collect workflow definition: %w", err)
+	}
+
+	return model.toDomain(), nil
+}
+
 // ListWorkflowInstances returns workflow instances filtered by tenant_id and project_id
 func (r *Repository) ListWorkflowInstances(
 	ctx context.Context,
 	tenantID domain.TenantID,
 	projectID domain.ProjectID,
 	workflowID string,
+	fields domain.FieldSet,
 	page, pageSize int,
 ) ([]domain.WorkflowInstance, int, error) {
-	executor := r.getExecutor(ctx)
+	executor := r.tenantExecutor(ctx, tenantID)
 
 	offset := (page - 1) * pageSize
+	columns := selectColumns(workflowInstanceColumns, fields)
 
 	var countQuery string
 	var query string
@@ -127,7 +286,7 @@ WHERE tenant_id = $1 AND project_id = $2 AND workflow_id = $3`
 		countArgs = []interface{}{tenantID.Int(), projectID.Int(), workflowID}
 
 		query = `
-SELECT * FROM workflows_manager.v_workflow_instances 
+SELECT ` + columns + ` FROM workflows_manager.v_workflow_instances 
 WHERE tenant_id = $1 AND project_id = $2 AND workflow_id = $3
 ORDER BY created_at DESC
 LIMIT $4 OFFSET $5`
@@ -139,30 +298,129 @@ WHERE tenant_id = $1 AND project_id = $2`
 		countArgs = []interface{}{tenantID.Int(), projectID.Int()}
 
 		query = `
-SELECT * FROM workflows_manager.v_workflow_instances 
+SELECT ` + columns + ` FROM workflows_manager.v_workflow_instances 
 WHERE tenant_id = $1 AND project_id = $2
 ORDER BY created_at DESC
 LIMIT $3 OFFSET $4`
 		args = []interface{}{tenantID.Int(), projectID.Int(), pageSize, offset}
 	}
 
-	// Count total
-	var total int
-	err := executor.QueryRow(ctx, countQuery, countArgs...).Scan(&total)
+	listModels, total, err := db.QueryPage(ctx, executor, db.PageQuery{
+		CountSQL:  countQuery,
+		CountArgs: countArgs,
+		SQL:       query,
+		Args:      args,
+	}, pgx.RowToStructByNameLax[workflowInstanceModel])
 	if err != nil {
-		return nil, 0, fmt.Errorf("count workflow instances: %w", err)
+		return nil, 0, fmt.Errorf("list workflow instances: %w", err)
 	}
 
-	// Fetch items
+	instances := make([]domain.WorkflowInstance, 0, len(listModels))
+	for i := range listModels {
+		instances = append(instances, listModels[i].toDomain())
+	}
+
+	return instances, total, nil
+}
+
+// ListWorkflowInstancesAfter returns workflow instances after the cursor using keyset pagination
+func (r *Repository) ListWorkflowInstancesAfter(
+	ctx context.Context,
+	tenantID domain.TenantID,
+	projectID domain.ProjectID,
+	workflowID string,
+	fields domain.FieldSet,
+	cursor *domain.Cursor,
+	limit int,
+) ([]domain.WorkflowInstance, *domain.Cursor, error) {
+	executor := r.tenantExecutor(ctx, tenantID)
+
+	conditions := "tenant_id = $1 AND project_id = $2"
+	args := []interface{}{tenantID.Int(), projectID.Int()}
+	if workflowID != "" {
+		args = append(args, workflowID)
+		conditions += fmt.Sprintf(" AND workflow_id = $%d", len(args))
+	}
+
+	query, args := keysetQuery("workflows_manager.v_workflow_instances",
+		selectColumns(workflowInstanceColumns, fields), conditions, args, cursor, limit)
+
 	rows, err := executor.Query(ctx, query, args...)
 	if err != nil {
-		return nil, 0, fmt.Errorf("query workflow instances: %w", err)
+		return nil, nil, fmt.Errorf("query workflow instances: %w", err)
 	}
 	defer rows.Close()
 
-	listModels, err := pgx.CollectRows(rows, pgx.RowToStructByName[workflowInstanceModel])
+	listModels, err := pgx.CollectRows(rows, pgx.RowToStructByNameLax[workflowInstanceModel])
+	if err != nil {
+		return nil, nil, fmt.Errorf("collect workflow instances: %w", err)
+	}
+
+	instances := make([]domain.WorkflowInstance, 0, len(listModels))
+	for i := range listModels {
+		instances = append(instances, listModels[i].toDomain())
+	}
+
+	instances, next := keysetPage(instances, limit, func(item *domain.WorkflowInstance) domain.Cursor {
+		return domain.Cursor{CreatedAt: item.CreatedAt, ID: item.ID}
+	})
+
+	return instances, next, nil
+}
+
+// SearchWorkflowInstances returns workflow instances whose payload matches the query
+func (r *Repository) SearchWorkflowInstances(
+	ctx context.Context,
+	tenantID domain.TenantID,
+	projectID domain.ProjectID,
+	query domain.InstanceSearchQuery,
+	page, pageSize int,
+) ([]domain.WorkflowInstance, int, error) {
+	executor := r.tenantExecutor(ctx, tenantID)
+
+	offset := (page - 1) * pageSize
+
+	args := []interface{}{tenantID.Int(), projectID.Int()}
+
+	// The redacted locations are removed from the payloads before matching them
+	input, output := "input", "output"
+	for _, location := range query.Excluded {
+		args = append(args, location)
+		input += fmt.Sprintf(" #- $%d::text[]", len(args))
+		output += fmt.Sprintf(" #- $%d::text[]", len(args))
+	}
+
+	var condition string
+	if len(query.Contains) > 0 {
+		args = append(args, string(query.Contains))
+		condition = fmt.Sprintf("((%[1]s) @> $%[3]d::jsonb OR (%[2]s) @> $%[3]d::jsonb)", input, output, len(args))
+	} else {
+		args = append(args, "%"+escapeLike(query.Text)+"%")
+		condition = fmt.Sprintf("((%[1]s)::text ILIKE $%[3]d OR (%[2]s)::text ILIKE $%[3]d", input, output, len(args))
+		if len(query.Excluded) == 0 {
+			condition += fmt.Sprintf(" OR error ILIKE $%d", len(args))
+		}
+		condition += ")"
+	}
+
+	countQuery := `
+SELECT COUNT(*) FROM workflows_manager.v_workflow_instances 
+WHERE tenant_id = $1 AND project_id = $2 AND ` + condition
+
+	listQuery := `
+SELECT * FROM workflows_manager.v_workflow_instances 
+WHERE tenant_id = $1 AND project_id = $2 AND ` + condition + `
+ORDER BY created_at DESC
+LIMIT ` + fmt.Sprintf("$%d OFFSET $%d", len(args)+1, len(args)+2)
+
+	listModels, total, err := db.QueryPage(ctx, executor, db.PageQuery{
+		CountSQL:  countQuery,
+		CountArgs: args,
+		SQL:       listQuery,
+		Args:      append(args, pageSize, offset),
+	}, pgx.RowToStructByName[workflowInstanceModel])
 	if err != nil {
-		return nil, 0, fmt.Errorf("collect workflow instances: %w", err)
+		return nil, 0, fmt.Errorf("search workflow instances: %w", err)
 	}
 
 	instances := make([]domain.WorkflowInstance, 0, len(listModels))
@@ -180,7 +438,7 @@ func (r *Repository) GetWorkflowInstance(
 	projectID domain.ProjectID,
 	id int,
 ) (domain.WorkflowInstance, error) {
-	executor := r.getExecutor(ctx)
+	executor := r.tenantExecutor(ctx, tenantID)
 
 	const query = `
 SELECT * FROM workflows_manager.v_workflow_instances 
@@ -204,15 +462,32 @@ LIMIT 1`
 	return model.toDomain(), nil
 }
 
+// CreateInstanceLineage records that the instance is a re-run of the parent instance
+func (r *Repository) CreateInstanceLineage(ctx context.Context, instanceID, parentInstanceID int) error {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+INSERT INTO workflows_manager.instance_lineage (instance_id, parent_instance_id, created_by)
+VALUES ($1, $2, $3)`
+
+	_, err := executor.Exec(ctx, query, instanceID, parentInstanceID, appcontext.Username(ctx))
+	if err != nil {
+		return fmt.Errorf("insert instance lineage: %w", err)
+	}
+
+	return nil
+}
+
 // ListWorkflowSteps returns workflow steps for an instance
 func (r *Repository) ListWorkflowSteps(
 	ctx context.Context,
 	tenantID domain.TenantID,
 	projectID domain.ProjectID,
 	instanceID int,
+	fields domain.FieldSet,
 	page, pageSize int,
 ) ([]domain.WorkflowStep, int, error) {
-	executor := r.getExecutor(ctx)
+	executor := r.tenantExecutor(ctx, tenantID)
 
 	offset := (page - 1) * pageSize
 
@@ -221,28 +496,21 @@ func (r *Repository) ListWorkflowSteps(
 SELECT COUNT(*) FROM workflows_manager.v_workflow_steps 
 WHERE tenant_id = $1 AND project_id = $2 AND instance_id = $3`
 
-	var total int
-	err := executor.QueryRow(ctx, countQuery, tenantID.Int(), projectID.Int(), instanceID).Scan(&total)
-	if err != nil {
-		return nil, 0, fmt.Errorf("count workflow steps: %w", err)
-	}
-
 	// Fetch items
-	const query = `
-SELECT * FROM workflows_manager.v_workflow_steps 
+	query := `
+SELECT ` + selectColumns(workflowStepColumns, fields) + ` FROM workflows_manager.v_workflow_steps 
 WHERE tenant_id = $1 AND project_id = $2 AND instance_id = $3
 ORDER BY created_at ASC
 LIMIT $4 OFFSET $5`
 
-	rows, err := executor.Query(ctx, query, tenantID.Int(), projectID.Int(), instanceID, pageSize, offset)
+	listModels, total, err := db.QueryPage(ctx, executor, db.PageQuery{
+		CountSQL:  countQuery,
+		CountArgs: []any{tenantID.Int(), projectID.Int(), instanceID},
+		SQL:       query,
+		Args:      []any{tenantID.Int(), projectID.Int(), instanceID, pageSize, offset},
+	}, pgx.RowToStructByNameLax[workflowStepModel])
 	if err != nil {
-		return nil, 0, fmt.Errorf("query workflow steps: %w", err)
-	}
-	defer rows.Close()
-
-	listModels, err := pgx.CollectRows(rows, pgx.RowToStructByName[workflowStepModel])
-	if err != nil {
-		return nil, 0, fmt.Errorf("collect workflow steps: %w", err)
+		return nil, 0, fmt.Errorf("list workflow steps: %w", err)
 	}
 
 	steps := make([]domain.WorkflowStep, 0, len(listModels))
@@ -253,6 +521,37 @@ LIMIT $4 OFFSET $5`
 	return steps, total, nil
 }
 
+// GetWorkflowStep returns a step of an instance by ID
+func (r *Repository) GetWorkflowStep(
+	ctx context.Context,
+	tenantID domain.TenantID,
+	projectID domain.ProjectID,
+	instanceID, stepID int,
+) (domain.WorkflowStep, error) {
+	executor := r.tenantExecutor(ctx, tenantID)
+
+	const query = `
+SELECT * FROM workflows_manager.v_workflow_steps 
+WHERE tenant_id = $1 AND project_id = $2 AND instance_id = $3 AND id = $4
+LIMIT 1`
+
+	rows, err := executor.Query(ctx, query, tenantID.Int(), projectID.Int(), instanceID, stepID)
+	if err != nil {
+		return domain.WorkflowStep{}, fmt.Errorf("query workflow step: %w", err)
+	}
+	defer rows.Close()
+
+	model, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[workflowStepModel])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.WorkflowStep{}, domain.ErrEntityNotFound
+		}
+		return domain.WorkflowStep{}, fmt.Errorf("collect workflow step: %w", err)
+	}
+
+	return model.toDomain(), nil
+}
+
 // ListWorkflowEvents returns workflow events for an instance
 func (r *Repository) ListWorkflowEvents(
 	ctx context.Context,
@@ -261,7 +560,7 @@ func (r *Repository) ListWorkflowEvents(
 	instanceID int,
 	page, pageSize int,
 ) ([]domain.WorkflowEvent, int, error) {
-	executor := r.getExecutor(ctx)
+	executor := r.tenantExecutor(ctx, tenantID)
 
 	offset := (page - 1) * pageSize
 
@@ -270,12 +569,6 @@ func (r *Repository) ListWorkflowEvents(
 SELECT COUNT(*) FROM workflows_manager.v_workflow_events 
 WHERE tenant_id = $1 AND project_id = $2 AND instance_id = $3`
 
-	var total int
-	err := executor.QueryRow(ctx, countQuery, tenantID.Int(), projectID.Int(), instanceID).Scan(&total)
-	if err != nil {
-		return nil, 0, fmt.Errorf("count workflow events: %w", err)
-	}
-
 	// Fetch items
 	const query = `
 SELECT * FROM workflows_manager.v_workflow_events 
@@ -283,15 +576,48 @@ WHERE tenant_id = $1 AND project_id = $2 AND instance_id = $3
 ORDER BY created_at DESC
 LIMIT $4 OFFSET $5`
 
-	rows, err := executor.Query(ctx, query, tenantID.Int(), projectID.Int(), instanceID, pageSize, offset)
+	listModels, total, err := db.QueryPage(ctx, executor, db.PageQuery{
+		CountSQL:  countQuery,
+		CountArgs: []any{tenantID.Int(), projectID.Int(), instanceID},
+		SQL:       query,
+		Args:      []any{tenantID.Int(), projectID.Int(), instanceID, pageSize, offset},
+	}, pgx.RowToStructByName[workflowEventModel])
+	if err != nil {
+		return nil, 0, fmt.Errorf("list workflow events: %w", err)
+	}
+
+	events := make([]domain.WorkflowEvent, 0, len(listModels))
+	for i := range listModels {
+		events = append(events, listModels[i].toDomain())
+	}
+
+	return events, total, nil
+}
+
+// ListWorkflowEventsAfter returns workflow events for an instance after the cursor using keyset pagination
+func (r *Repository) ListWorkflowEventsAfter(
+	ctx context.Context,
+	tenantID domain.TenantID,
+	projectID domain.ProjectID,
+	instanceID int,
+	cursor *domain.Cursor,
+	limit int,
+) ([]domain.WorkflowEvent, *domain.Cursor, error) {
+	executor := r.tenantExecutor(ctx, tenantID)
+
+	query, args := keysetQuery("workflows_manager.v_workflow_events", "*",
+		"tenant_id = $1 AND project_id = $2 AND instance_id = $3",
+		[]interface{}{tenantID.Int(), projectID.Int(), instanceID}, cursor, limit)
+
+	rows, err := executor.Query(ctx, query, args...)
 	if err != nil {
-		return nil, 0, fmt.Errorf("query workflow events: %w", err)
+		return nil, nil, fmt.Errorf("query workflow events: %w", err)
 	}
 	defer rows.Close()
 
 	listModels, err := pgx.CollectRows(rows, pgx.RowToStructByName[workflowEventModel])
 	if err != nil {
-		return nil, 0, fmt.Errorf("collect workflow events: %w", err)
+		return nil, nil, fmt.Errorf("collect workflow events: %w", err)
 	}
 
 	events := make([]domain.WorkflowEvent, 0, len(listModels))
@@ -299,7 +625,11 @@ LIMIT $4 OFFSET $5`
 		events = append(events, listModels[i].toDomain())
 	}
 
-	return events, total, nil
+	events, next := keysetPage(events, limit, func(item *domain.WorkflowEvent) domain.Cursor {
+		return domain.Cursor{CreatedAt: item.CreatedAt, ID: item.ID}
+	})
+
+	return events, next, nil
 }
 
 // ListActiveWorkflows returns active workflows
@@ -309,7 +639,7 @@ func (r *Repository) ListActiveWorkflows(
 	projectID domain.ProjectID,
 	page, pageSize int,
 ) ([]domain.ActiveWorkflow, int, error) {
-	executor := r.getExecutor(ctx)
+	executor := r.tenantExecutor(ctx, tenantID)
 
 	offset := (page - 1) * pageSize
 
@@ -318,12 +648,6 @@ func (r *Repository) ListActiveWorkflows(
 SELECT COUNT(*) FROM workflows_manager.v_active_workflows 
 WHERE tenant_id = $1 AND project_id = $2`
 
-	var total int
-	err := executor.QueryRow(ctx, countQuery, tenantID.Int(), projectID.Int()).Scan(&total)
-	if err != nil {
-		return nil, 0, fmt.Errorf("count active workflows: %w", err)
-	}
-
 	// Fetch items
 	const query = `
 SELECT 
@@ -346,15 +670,14 @@ WHERE vaw.tenant_id = $1 AND vaw.project_id = $2
 ORDER BY vaw.created_at DESC
 LIMIT $3 OFFSET $4`
 
-	rows, err := executor.Query(ctx, query, tenantID.Int(), projectID.Int(), pageSize, offset)
+	listModels, total, err := db.QueryPage(ctx, executor, db.PageQuery{
+		CountSQL:  countQuery,
+		CountArgs: []any{tenantID.Int(), projectID.Int()},
+		SQL:       query,
+		Args:      []any{tenantID.Int(), projectID.Int(), pageSize, offset},
+	}, pgx.RowToStructByName[activeWorkflowModel])
 	if err != nil {
-		return nil, 0, fmt.Errorf("query active workflows: %w", err)
-	}
-	defer rows.Close()
-
-	listModels, err := pgx.CollectRows(rows, pgx.RowToStructByName[activeWorkflowModel])
-	if err != nil {
-		return nil, 0, fmt.Errorf("collect active workflows: %w", err)
+		return nil, 0, fmt.Errorf("list active workflows: %w", err)
 	}
 
 	workflows := make([]domain.ActiveWorkflow, 0, len(listModels))
@@ -372,7 +695,7 @@ func (r *Repository) ListWorkflowStats(
 	projectID domain.ProjectID,
 	page, pageSize int,
 ) ([]domain.WorkflowStat, int, error) {
-	executor := r.getExecutor(ctx)
+	executor := r.tenantExecutor(ctx, tenantID)
 
 	offset := (page - 1) * pageSize
 
@@ -381,12 +704,6 @@ func (r *Repository) ListWorkflowStats(
 SELECT COUNT(*) FROM workflows_manager.v_workflow_stats 
 WHERE tenant_id = $1 AND project_id = $2`
 
-	var total int
-	err := executor.QueryRow(ctx, countQuery, tenantID.Int(), projectID.Int()).Scan(&total)
-	if err != nil {
-		return nil, 0, fmt.Errorf("count workflow stats: %w", err)
-	}
-
 	// Fetch items
 	const query = `
 SELECT tenant_id, project_id, name, version, total_instances, completed, failed, running, avg_duration_seconds
@@ -395,23 +712,121 @@ WHERE tenant_id = $1 AND project_id = $2
 ORDER BY name, version
 LIMIT $3 OFFSET $4`
 
-	rows, err := executor.Query(ctx, query, tenantID.Int(), projectID.Int(), pageSize, offset)
+	listModels, total, err := db.QueryPage(ctx, executor, db.PageQuery{
+		CountSQL:  countQuery,
+		CountArgs: []any{tenantID.Int(), projectID.Int()},
+		SQL:       query,
+		Args:      []any{tenantID.Int(), projectID.Int(), pageSize, offset},
+	}, pgx.RowToStructByName[workflowStatModel])
 	if err != nil {
-		return nil, 0, fmt.Errorf("query workflow stats: %w", err)
+		return nil, 0, fmt.Errorf("list workflow stats: %w", err)
+	}
+
+	stats := make([]domain.WorkflowStat, 0, len(listModels))
+	for i := range listModels {
+		stats = append(stats, listModels[i].toDomain())
+	}
+
+	return stats, total, nil
+}
+
+// RefreshWorkflowStats recomputes the materialized workflow statistics without blocking their readers
+func (r *Repository) RefreshWorkflowStats(ctx context.Context) (time.Time, error) {
+	executor := r.getExecutor(ctx)
+
+	if _, err := executor.Exec(ctx, `REFRESH MATERIALIZED VIEW CONCURRENTLY workflows_manager.mv_workflow_stats`); err != nil {
+		return time.Time{}, fmt.Errorf("refresh workflow stats: %w", err)
+	}
+
+	const query = `
+INSERT INTO workflows_manager.materialized_view_refreshes (name, refreshed_at)
+VALUES ('mv_workflow_stats', now())
+ON CONFLICT (name) DO UPDATE SET refreshed_at = excluded.refreshed_at
+RETURNING refreshed_at`
+
+	var refreshedAt time.Time
+	if err := executor.QueryRow(ctx, query).Scan(&refreshedAt); err != nil {
+		return time.Time{}, fmt.Errorf("save workflow stats refresh time: %w", err)
+	}
+
+	return refreshedAt, nil
+}
+
+// WorkflowStatsRefreshedAt returns the time of the last workflow statistics refresh
+func (r *Repository) WorkflowStatsRefreshedAt(ctx context.Context) (time.Time, error) {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+SELECT refreshed_at FROM workflows_manager.materialized_view_refreshes
+WHERE name = 'mv_workflow_stats'`
+
+	var refreshedAt time.Time
+	if err := executor.QueryRow(ctx, query).Scan(&refreshedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return time.Time{}, domain.ErrEntityNotFound
+		}
+
+		return time.Time{}, fmt.Errorf("query workflow stats refresh time: %w", err)
+	}
+
+	return refreshedAt, nil
+}
+
+// ListWorkflowFailures groups failed steps of a workflow by step name and error signature
+func (r *Repository) ListWorkflowFailures(
+	ctx context.Context,
+	tenantID domain.TenantID,
+	projectID domain.ProjectID,
+	workflowID string,
+	since time.Time,
+	limit int,
+) ([]domain.WorkflowFailureGroup, error) {
+	executor := r.tenantExecutor(ctx, tenantID)
+
+	// The signature masks UUIDs and numbers, so errors differing only in IDs fall into one group
+	const query = `
+WITH failed AS (
+    SELECT s.step_name,
+           left(regexp_replace(
+               regexp_replace(coalesce(s.error, ''),
+                   '[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}', '<uuid>', 'gi'),
+               '[0-9]+', '<n>', 'g'), 500) AS error_signature,
+           s.error,
+           s.instance_id,
+           coalesce(s.completed_at, s.created_at) AS occurred_at
+    FROM workflows_manager.v_workflow_steps s
+    JOIN workflows.workflow_instances wi ON wi.id = s.instance_id
+    WHERE s.tenant_id = $1 AND s.project_id = $2 AND wi.workflow_id = $3
+      AND s.status = 'failed' AND s.created_at >= $4
+)
+SELECT step_name,
+       error_signature,
+       (array_agg(error ORDER BY occurred_at DESC))[1] AS sample_error,
+       count(*) AS failures,
+       count(DISTINCT instance_id) AS instances,
+       max(occurred_at) AS last_occurred_at
+FROM failed
+GROUP BY step_name, error_signature
+ORDER BY failures DESC, last_occurred_at DESC
+LIMIT $5`
+
+	rows, err := executor.Query(ctx, query, tenantID.Int(), projectID.Int(), workflowID, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query workflow failures: %w", err)
 	}
 	defer rows.Close()
 
-	listModels, err := pgx.CollectRows(rows, pgx.RowToStructByName[workflowStatModel])
+	listModels, err := pgx.CollectRows(rows, pgx.RowToStructByName[workflowFailureGroupModel])
 	if err != nil {
-		return nil, 0, fmt.Errorf("collect workflow stats: %w", err)
+		return nil, fmt.Errorf("collect workflow failures: %w", err)
 	}
 
-	stats := make([]domain.WorkflowStat, 0, len(listModels))
+	groups := make([]domain.WorkflowFailureGroup, 0, len(listModels))
 	for i := range listModels {
-		stats = append(stats, listModels[i].toDomain())
+		groups = append(groups, listModels[i].toDomain())
 	}
 
-	return stats, total, nil
+	return groups, nil
 }
 
 // ListDLQItems returns DLQ items with pagination
@@ -421,7 +836,7 @@ func (r *Repository) ListDLQItems(
 	projectID domain.ProjectID,
 	page, pageSize int,
 ) ([]domain.DLQItem, int, error) {
-	executor := r.getExecutor(ctx)
+	executor := r.tenantExecutor(ctx, tenantID)
 
 	offset := (page - 1) * pageSize
 
@@ -430,12 +845,6 @@ func (r *Repository) ListDLQItems(
 SELECT COUNT(*) FROM workflows_manager.v_workflow_dlq 
 WHERE tenant_id = $1 AND project_id = $2`
 
-	var total int
-	err := executor.QueryRow(ctx, countQuery, tenantID.Int(), projectID.Int()).Scan(&total)
-	if err != nil {
-		return nil, 0, fmt.Errorf("count DLQ items: %w", err)
-	}
-
 	// Fetch items
 	const query = `
 SELECT * FROM workflows_manager.v_workflow_dlq 
@@ -443,15 +852,47 @@ WHERE tenant_id = $1 AND project_id = $2
 ORDER BY created_at DESC
 LIMIT $3 OFFSET $4`
 
-	rows, err := executor.Query(ctx, query, tenantID.Int(), projectID.Int(), pageSize, offset)
+	listModels, total, err := db.QueryPage(ctx, executor, db.PageQuery{
+		CountSQL:  countQuery,
+		CountArgs: []any{tenantID.Int(), projectID.Int()},
+		SQL:       query,
+		Args:      []any{tenantID.Int(), projectID.Int(), pageSize, offset},
+	}, pgx.RowToStructByName[dlqItemModel])
 	if err != nil {
-		return nil, 0, fmt.Errorf("query DLQ items: %w", err)
+		return nil, 0, fmt.Errorf("list DLQ items: %w", err)
+	}
+
+	items := make([]domain.DLQItem, 0, len(listModels))
+	for i := range listModels {
+		items = append(items, listModels[i].toDomain())
+	}
+
+	return items, total, nil
+}
+
+// ListDLQItemsAfter returns DLQ items after the cursor using keyset pagination
+func (r *Repository) ListDLQItemsAfter(
+	ctx context.Context,
+	tenantID domain.TenantID,
+	projectID domain.ProjectID,
+	cursor *domain.Cursor,
+	limit int,
+) ([]domain.DLQItem, *domain.Cursor, error) {
+	executor := r.tenantExecutor(ctx, tenantID)
+
+	query, args := keysetQuery("workflows_manager.v_workflow_dlq", "*",
+		"tenant_id = $1 AND project_id = $2",
+		[]interface{}{tenantID.Int(), projectID.Int()}, cursor, limit)
+
+	rows, err := executor.Query(ctx, query, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("query DLQ items: %w", err)
 	}
 	defer rows.Close()
 
 	listModels, err := pgx.CollectRows(rows, pgx.RowToStructByName[dlqItemModel])
 	if err != nil {
-		return nil, 0, fmt.Errorf("collect DLQ items: %w", err)
+		return nil, nil, fmt.Errorf("collect DLQ items: %w", err)
 	}
 
 	items := make([]domain.DLQItem, 0, len(listModels))
@@ -459,7 +900,11 @@ LIMIT $3 OFFSET $4`
 		items = append(items, listModels[i].toDomain())
 	}
 
-	return items, total, nil
+	items, next := keysetPage(items, limit, func(item *domain.DLQItem) domain.Cursor {
+		return domain.Cursor{CreatedAt: item.CreatedAt, ID: item.ID}
+	})
+
+	return items, next, nil
 }
 
 // GetDLQItem returns a DLQ item by ID
@@ -469,7 +914,7 @@ func (r *Repository) GetDLQItem(
 	projectID domain.ProjectID,
 	id int,
 ) (domain.DLQItem, error) {
-	executor := r.getExecutor(ctx)
+	executor := r.tenantExecutor(ctx, tenantID)
 
 	const query = `
 SELECT * FROM workflows_manager.v_workflow_dlq 
@@ -512,12 +957,6 @@ WHERE NOT EXISTS (
     WHERE pw.workflow_definition_id = wd.id
 )`
 
-	var total int
-	err := executor.QueryRow(ctx, countQuery).Scan(&total)
-	if err != nil {
-		return nil, 0, fmt.Errorf("count unassigned workflow definitions: %w", err)
-	}
-
 	// Fetch items
 	const query = `
 SELECT 
@@ -537,15 +976,13 @@ WHERE NOT EXISTS (
 ORDER BY wd.created_at DESC
 LIMIT $1 OFFSET $2`
 
-	rows, err := executor.Query(ctx, query, pageSize, offset)
-	if err != nil {
-		return nil, 0, fmt.Errorf("query unassigned workflow definitions: %w", err)
-	}
-	defer rows.Close()
-
-	listModels, err := pgx.CollectRows(rows, pgx.RowToStructByName[workflowDefinitionModel])
+	listModels, total, err := db.QueryPage(ctx, executor, db.PageQuery{
+		CountSQL: countQuery,
+		SQL:      query,
+		Args:     []any{pageSize, offset},
+	}, pgx.RowToStructByName[workflowDefinitionModel])
 	if err != nil {
-		return nil, 0, fmt.Errorf("collect unassigned workflow definitions: %w", err)
+		return nil, 0, fmt.Errorf("list unassigned workflow definitions: %w", err)
 	}
 
 	definitions := make([]domain.WorkflowDefinition, 0, len(listModels))
@@ -558,12 +995,14 @@ LIMIT $1 OFFSET $2`
 
 // AssignWorkflowDefinitionsToProject assigns workflow definitions to a project
 // If workflowIDs is empty, assigns all unassigned workflows
+// Every assignment is written to the audit log, run it in a transaction
 func (r *Repository) AssignWorkflowDefinitionsToProject(
 	ctx context.Context,
 	projectID domain.ProjectID,
 	workflowIDs []string,
 ) (int, error) {
 	executor := r.getExecutor(ctx)
+	defer r.definitions.invalidate()
 
 	var query string
 	var args []interface{}
@@ -579,7 +1018,8 @@ WHERE NOT EXISTS (
     FROM workflows_manager.project_workflows pw 
     WHERE pw.workflow_definition_id = wd.id
 )
-ON CONFLICT (project_id, workflow_definition_id) DO NOTHING`
+ON CONFLICT (project_id, workflow_definition_id) DO NOTHING
+RETURNING workflow_definition_id`
 		args = []interface{}{projectID.Int()}
 	} else {
 		// Assign specific workflows - use VALUES clause for better performance
@@ -588,17 +1028,28 @@ INSERT INTO workflows_manager.project_workflows (project_id, workflow_definition
 SELECT $1, wd.id
 FROM workflows.workflow_definitions wd
 WHERE wd.id = ANY($2::text[])
-ON CONFLICT (project_id, workflow_definition_id) DO NOTHING`
+ON CONFLICT (project_id, workflow_definition_id) DO NOTHING
+RETURNING workflow_definition_id`
 		args = []interface{}{projectID.Int(), workflowIDs}
 	}
 
-	result, err := executor.Exec(ctx, query, args...)
+	rows, err := executor.Query(ctx, query, args...)
 	if err != nil {
 		return 0, fmt.Errorf("assign workflow definitions to project: %w", err)
 	}
 
-	rowsAffected := result.RowsAffected()
-	return int(rowsAffected), nil
+	assigned, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return 0, fmt.Errorf("assign workflow definitions to project: %w", err)
+	}
+
+	for _, id := range assigned {
+		if err := auditlog.WriteLog(ctx, executor, domain.EntityWorkflow, id, domain.ActionAssign, projectID); err != nil {
+			return 0, fmt.Errorf("write audit log: %w", err)
+		}
+	}
+
+	return len(assigned), nil
 }
 
 // CreateWorkflowDefinition creates a new workflow definition in the database
@@ -611,6 +1062,7 @@ func (r *Repository) CreateWorkflowDefinition(
 	definition json.RawMessage,
 ) (string, error) {
 	executor := r.getExecutor(ctx)
+	defer r.definitions.invalidate()
 
 	id := fmt.Sprintf("%s-v%d", name, version)
 
@@ -646,6 +1098,195 @@ ON CONFLICT (project_id, workflow_definition_id) DO NOTHING`
 	return workflowID, nil
 }
 
+// UpdateWorkflowDefinition stores the definition as the next version of the workflow
+// identified by id and assigns it to the same project. Existing versions are kept intact
+// because running instances reference them. Returns the ID of the new version, or
+// domain.ErrEntityAlreadyExists when a concurrent update took the next version first.
+func (r *Repository) UpdateWorkflowDefinition(
+	ctx context.Context,
+	tenantID domain.TenantID,
+	projectID domain.ProjectID,
+	id string,
+	definition json.RawMessage,
+) (string, error) {
+	executor := r.tenantExecutor(ctx, tenantID)
+	defer r.definitions.invalidate()
+
+	current, err := r.GetWorkflowDefinition(ctx, tenantID, projectID, id)
+	if err != nil {
+		return "", err
+	}
+
+	var latestVersion int
+	err = executor.QueryRow(ctx,
+		`SELECT COALESCE(MAX(version), 0) FROM workflows.workflow_definitions WHERE name = $1`,
+		current.Name,
+	).Scan(&latestVersion)
+	if err != nil {
+		return "", fmt.Errorf("get latest workflow version: %w", err)
+	}
+
+	version := latestVersion + 1
+	newID := fmt.Sprintf("%s-v%d", current.Name, version)
+
+	createQuery := `
+INSERT INTO workflows.workflow_definitions (id, name, version, definition)
+VALUES ($1, $2, $3, $4::jsonb)
+RETURNING id`
+
+	var workflowID string
+	err = executor.QueryRow(ctx, createQuery, newID, current.Name, version, definition).Scan(&workflowID)
+	if err != nil {
+		if db.IsUniqueViolation(err) {
+			return "", domain.ErrEntityAlreadyExists
+		}
+
+		return "", fmt.Errorf("create workflow definition version: %w", err)
+	}
+
+	assignQuery := `
+INSERT INTO workflows_manager.project_workflows (project_id, workflow_definition_id)
+VALUES ($1, $2)
+ON CONFLICT (project_id, workflow_definition_id) DO NOTHING`
+
+	_, err = executor.Exec(ctx, assignQuery, projectID.Int(), workflowID)
+	if err != nil {
+		return "", fmt.Errorf("assign workflow to project: %w", err)
+	}
+
+	if err := auditlog.WriteLog(ctx, executor, domain.EntityWorkflow, workflowID, domain.ActionUpdate, projectID); err != nil {
+		return "", fmt.Errorf("write audit log: %w", err)
+	}
+
+	return workflowID, nil
+}
+
+// DeleteWorkflowDefinition unassigns a workflow definition version from the project and deletes
+// the definition when no other project references it. Definitions that still have instances
+// or DLQ records are not deleted.
+func (r *Repository) DeleteWorkflowDefinition(
+	ctx context.Context,
+	tenantID domain.TenantID,
+	projectID domain.ProjectID,
+	id string,
+) error {
+	executor := r.tenantExecutor(ctx, tenantID)
+	defer r.definitions.invalidate()
+
+	if _, err := r.GetWorkflowDefinition(ctx, tenantID, projectID, id); err != nil {
+		return err
+	}
+
+	inUse, err := r.IsWorkflowDefinitionInUse(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if inUse {
+		return domain.ErrEntityInUse
+	}
+
+	_, err = executor.Exec(ctx,
+		`DELETE FROM workflows_manager.project_workflows WHERE project_id = $1 AND workflow_definition_id = $2`,
+		projectID.Int(), id,
+	)
+	if err != nil {
+		return fmt.Errorf("unassign workflow definition: %w", err)
+	}
+
+	_, err = executor.Exec(ctx, `
+DELETE FROM workflows.workflow_definitions wd
+WHERE wd.id = $1
+  AND NOT EXISTS (SELECT 1 FROM workflows_manager.project_workflows pw WHERE pw.workflow_definition_id = wd.id)`,
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("delete workflow definition: %w", err)
+	}
+
+	if err := auditlog.WriteLog(ctx, executor, domain.EntityWorkflow, id, domain.ActionDelete, projectID); err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+
+	return nil
+}
+
+// IsWorkflowDefinitionInUse reports whether any instance or DLQ record references the definition.
+func (r *Repository) IsWorkflowDefinitionInUse(ctx context.Context, id string) (bool, error) {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+SELECT EXISTS (SELECT 1 FROM workflows.workflow_instances WHERE workflow_id = $1)
+    OR EXISTS (SELECT 1 FROM workflows.workflow_dlq WHERE workflow_id = $1)`
+
+	var inUse bool
+	if err := executor.QueryRow(ctx, query, id).Scan(&inUse); err != nil {
+		return false, fmt.Errorf("check workflow definition usage: %w", err)
+	}
+
+	return inUse, nil
+}
+
+// TransferWorkflowDefinition moves the definition assignment from one project to another
+// and records the transfer in the audit log of both projects.
+func (r *Repository) TransferWorkflowDefinition(
+	ctx context.Context,
+	fromProjectID domain.ProjectID,
+	toProjectID domain.ProjectID,
+	id string,
+) error {
+	executor := r.getExecutor(ctx)
+	defer r.definitions.invalidate()
+
+	result, err := executor.Exec(ctx, `
+DELETE FROM workflows_manager.project_workflows
+WHERE project_id = $1 AND workflow_definition_id = $2`,
+		fromProjectID.Int(), id,
+	)
+	if err != nil {
+		return fmt.Errorf("unassign workflow definition: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrEntityNotFound
+	}
+
+	_, err = executor.Exec(ctx, `
+INSERT INTO workflows_manager.project_workflows (project_id, workflow_definition_id)
+VALUES ($1, $2)
+ON CONFLICT (project_id, workflow_definition_id) DO NOTHING`,
+		toProjectID.Int(), id,
+	)
+	if err != nil {
+		return fmt.Errorf("assign workflow definition: %w", err)
+	}
+
+	if err := auditlog.WriteLog(ctx, executor, domain.EntityWorkflow, id, domain.ActionTransfer, fromProjectID); err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+
+	if err := auditlog.WriteLog(ctx, executor, domain.EntityWorkflow, id, domain.ActionTransfer, toProjectID); err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+
+	return nil
+}
+
+// likeEscaper escapes the pattern characters of LIKE with the default escape character.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// tenantExecutor routes the queries of the workflow views to the schema of the tenant
+// in the tenant isolation mode.
+//
+//nolint:ireturn // it's ok here
+func (r *Repository) tenantExecutor(ctx context.Context, tenantID domain.TenantID) db.Tx {
+	return r.schemas.Executor(r.getExecutor(ctx), tenantID)
+}
+
 //nolint:ireturn // it's ok here
 func (r *Repository) getExecutor(ctx context.Context) db.Tx {
 	if tx := db.TxFromContext(ctx); tx != nil {
@@ -0,0 +1,277 @@
+package workflows
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/repository/auditlog"
+)
+
+const activeVersionColumns = `
+av.project_id, av.workflow_name, av.version, coalesce(wd.id, '') AS workflow_id, av.updated_by, av.updated_at`
+
+// ListActiveVersions returns the pinned workflows of the project
+func (r *Repository) ListActiveVersions(
+	ctx context.Context,
+	projectID domain.ProjectID,
+) ([]domain.WorkflowActiveVersion, error) {
+	executor := r.getExecutor(ctx)
+
+	query := `
+SELECT ` + activeVersionColumns + `
+FROM workflows_manager.workflow_active_versions av
+         LEFT JOIN workflows.workflow_definitions wd ON wd.name = av.workflow_name AND wd.version = av.version
+WHERE av.project_id = $1
+ORDER BY av.workflow_name`
+
+	rows, err := executor.Query(ctx, query, projectID.Int())
+	if err != nil {
+		return nil, fmt.Errorf("query active versions: %w", err)
+	}
+	defer rows.Close()
+
+	models, err := pgx.CollectRows(rows, pgx.RowToStructByName[workflowActiveVersionModel])
+	if err != nil {
+		return nil, fmt.Errorf("collect active versions: %w", err)
+	}
+
+	versions := make([]domain.WorkflowActiveVersion, 0, len(models))
+	for i := range models {
+		versions = append(versions, models[i].toDomain())
+	}
+
+	return versions, nil
+}
+
+// GetActiveVersion returns the active version of a workflow, domain.ErrEntityNotFound when it is not pinned
+func (r *Repository) GetActiveVersion(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	name string,
+) (domain.WorkflowActiveVersion, error) {
+	executor := r.getExecutor(ctx)
+
+	query := `
+SELECT ` + activeVersionColumns + `
+FROM workflows_manager.workflow_active_versions av
+         LEFT JOIN workflows.workflow_definitions wd ON wd.name = av.workflow_name AND wd.version = av.version
+WHERE av.project_id = $1 AND av.workflow_name = $2`
+
+	rows, err := executor.Query(ctx, query, projectID.Int(), name)
+	if err != nil {
+		return domain.WorkflowActiveVersion{}, fmt.Errorf("query active version: %w", err)
+	}
+	defer rows.Close()
+
+	model, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[workflowActiveVersionModel])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.WorkflowActiveVersion{}, domain.ErrEntityNotFound
+		}
+		return domain.WorkflowActiveVersion{}, fmt.Errorf("collect active version: %w", err)
+	}
+
+	return model.toDomain(), nil
+}
+
+// SetActiveVersion pins the workflow to the version and records the rollout.
+// Returns domain.ErrEntityNotFound when the version is not a definition of the project.
+func (r *Repository) SetActiveVersion(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	name string,
+	version int,
+	action string,
+) (domain.WorkflowActiveVersion, error) {
+	executor := r.getExecutor(ctx)
+
+	const definitionQuery = `
+SELECT wd.id
+FROM workflows.workflow_definitions wd
+         JOIN workflows_manager.project_workflows pw ON pw.workflow_definition_id = wd.id
+WHERE pw.project_id = $1 AND wd.name = $2 AND wd.version = $3`
+
+	var workflowID string
+	err := executor.QueryRow(ctx, definitionQuery, projectID.Int(), name, version).Scan(&workflowID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.WorkflowActiveVersion{}, domain.ErrEntityNotFound
+		}
+		return domain.WorkflowActiveVersion{}, fmt.Errorf("get workflow definition: %w", err)
+	}
+
+	const currentQuery = `
+SELECT version FROM workflows_manager.workflow_active_versions
+WHERE project_id = $1 AND workflow_name = $2
+FOR UPDATE`
+
+	var fromVersion *int
+	var current int
+	err = executor.QueryRow(ctx, currentQuery, projectID.Int(), name).Scan(&current)
+	switch {
+	case err == nil:
+		fromVersion = &current
+	case !errors.Is(err, pgx.ErrNoRows):
+		return domain.WorkflowActiveVersion{}, fmt.Errorf("get active version: %w", err)
+	}
+
+	const upsertQuery = `
+INSERT INTO workflows_manager.workflow_active_versions (project_id, workflow_name, version, updated_by)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (project_id, workflow_name) DO UPDATE
+SET version = EXCLUDED.version, updated_by = EXCLUDED.updated_by, updated_at = now()
+RETURNING project_id, workflow_name, version, $5::text AS workflow_id, updated_by, updated_at`
+
+	username := appcontext.Username(ctx)
+
+	rows, err := executor.Query(ctx, upsertQuery, projectID.Int(), name, version, username, workflowID)
+	if err != nil {
+		return domain.WorkflowActiveVersion{}, fmt.Errorf("set active version: %w", err)
+	}
+
+	model, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[workflowActiveVersionModel])
+	if err != nil {
+		return domain.WorkflowActiveVersion{}, fmt.Errorf("collect active version: %w", err)
+	}
+
+	const rolloutQuery = `
+INSERT INTO workflows_manager.workflow_version_rollouts
+    (project_id, workflow_name, from_version, to_version, action, created_by)
+VALUES ($1, $2, $3, $4, $5, $6)`
+
+	_, err = executor.Exec(ctx, rolloutQuery, projectID.Int(), name, fromVersion, version, action, username)
+	if err != nil {
+		return domain.WorkflowActiveVersion{}, fmt.Errorf("insert rollout: %w", err)
+	}
+
+	if err := auditlog.WriteLog(ctx, executor, domain.EntityWorkflow, workflowID, action, projectID); err != nil {
+		return domain.WorkflowActiveVersion{}, fmt.Errorf("write audit log: %w", err)
+	}
+
+	return model.toDomain(), nil
+}
+
+// DeleteActiveVersion unpins the workflow, new instances start on the version they reference again
+func (r *Repository) DeleteActiveVersion(ctx context.Context, projectID domain.ProjectID, name string) error {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+DELETE FROM workflows_manager.workflow_active_versions
+WHERE project_id = $1 AND workflow_name = $2`
+
+	tag, err := executor.Exec(ctx, query, projectID.Int(), name)
+	if err != nil {
+		return fmt.Errorf("delete active version: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrEntityNotFound
+	}
+
+	if err := auditlog.WriteLog(ctx, executor, domain.EntityWorkflow, name, domain.ActionDelete, projectID); err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+
+	return nil
+}
+
+// ListVersionRollouts returns the latest rollouts of a workflow, newest first
+func (r *Repository) ListVersionRollouts(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	name string,
+	limit int,
+) ([]domain.WorkflowRollout, error) {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+SELECT id, project_id, workflow_name, from_version, to_version, action, created_by, created_at
+FROM workflows_manager.workflow_version_rollouts
+WHERE project_id = $1 AND workflow_name = $2
+ORDER BY id DESC
+LIMIT $3`
+
+	rows, err := executor.Query(ctx, query, projectID.Int(), name, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query rollouts: %w", err)
+	}
+	defer rows.Close()
+
+	models, err := pgx.CollectRows(rows, pgx.RowToStructByName[workflowRolloutModel])
+	if err != nil {
+		return nil, fmt.Errorf("collect rollouts: %w", err)
+	}
+
+	rollouts := make([]domain.WorkflowRollout, 0, len(models))
+	for i := range models {
+		rollouts = append(rollouts, models[i].toDomain())
+	}
+
+	return rollouts, nil
+}
+
+// GetPromotedFromVersion returns the version that was active before the latest promotion to version.
+// Returns domain.ErrEntityNotFound when there is none.
+func (r *Repository) GetPromotedFromVersion(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	name string,
+	version int,
+) (int, error) {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+SELECT from_version
+FROM workflows_manager.workflow_version_rollouts
+WHERE project_id = $1 AND workflow_name = $2 AND to_version = $3 AND action = $4
+ORDER BY id DESC
+LIMIT 1`
+
+	var from *int
+	err := executor.QueryRow(ctx, query, projectID.Int(), name, version, domain.ActionPromote).Scan(&from)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domain.ErrEntityNotFound
+		}
+		return 0, fmt.Errorf("get promoted from version: %w", err)
+	}
+
+	if from == nil {
+		return 0, domain.ErrEntityNotFound
+	}
+
+	return *from, nil
+}
+
+// ResolveActiveWorkflowID returns the ID of the active version of the workflow the definition belongs to,
+// or the definition ID itself when the workflow is not pinned in the project.
+func (r *Repository) ResolveActiveWorkflowID(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	workflowID string,
+) (string, error) {
+	executor := r.getExecutor(ctx)
+
+	const query = `
+SELECT coalesce((
+    SELECT active.id
+    FROM workflows.workflow_definitions wd
+             JOIN workflows_manager.workflow_active_versions av
+                  ON av.project_id = $1 AND av.workflow_name = wd.name
+             JOIN workflows.workflow_definitions active
+                  ON active.name = av.workflow_name AND active.version = av.version
+    WHERE wd.id = $2
+), $2)`
+
+	var resolved string
+	if err := executor.QueryRow(ctx, query, projectID.Int(), workflowID).Scan(&resolved); err != nil {
+		return "", fmt.Errorf("resolve active workflow: %w", err)
+	}
+
+	return resolved, nil
+}
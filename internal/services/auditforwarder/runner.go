@@ -0,0 +1,124 @@
+// Package auditforwarder forwards the new audit log entries to the external sinks (SIEM) in the background,
+// whether workflow notifications are enabled or not.
+// Like the notifier, it runs on a single replica elected with a Postgres advisory lock.
+package auditforwarder
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rom8726/di"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ di.Servicer = (*Runner)(nil)
+
+// advisoryLockKey identifies the audit forwarding leader lock ("floxyaud").
+const advisoryLockKey int64 = 0x666c6f7879617564
+
+type Config struct {
+	Enabled  bool
+	Interval time.Duration
+}
+
+type Runner struct {
+	leaderLock *db.AdvisoryLock
+	auditSinks contract.AuditSinksUseCase
+	cfg        Config
+	isLeader   bool
+
+	ctxCancel context.CancelFunc
+	done      chan struct{}
+}
+
+func New(pool *pgxpool.Pool, auditSinks contract.AuditSinksUseCase, cfg *Config) *Runner {
+	return &Runner{
+		leaderLock: db.NewAdvisoryLock(pool, advisoryLockKey),
+		auditSinks: auditSinks,
+		cfg:        *cfg,
+	}
+}
+
+func (r *Runner) Start(context.Context) error {
+	if !r.cfg.Enabled {
+		slog.Info("Audit log forwarding is disabled")
+
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.ctxCancel = cancel
+	r.done = make(chan struct{})
+
+	go r.loop(ctx)
+
+	return nil
+}
+
+func (r *Runner) Stop(ctx context.Context) error {
+	if r.ctxCancel == nil {
+		return nil
+	}
+
+	r.ctxCancel()
+
+	select {
+	case <-r.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if err := r.leaderLock.Release(ctx); err != nil {
+		slog.Warn("Failed to release audit forwarding advisory lock", "error", err)
+	}
+
+	return nil
+}
+
+func (r *Runner) loop(ctx context.Context) {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		r.tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Runner) tick(ctx context.Context) {
+	isLeader, err := r.leaderLock.TryAcquire(ctx)
+	if err != nil {
+		slog.Error("Audit forwarding leader election failed", "error", err)
+
+		return
+	}
+
+	if isLeader != r.isLeader {
+		r.isLeader = isLeader
+		slog.Info("Audit forwarding leadership changed", "leader", isLeader)
+	}
+
+	if !isLeader {
+		return
+	}
+
+	forwarded, err := r.auditSinks.Forward(ctx)
+	if err != nil {
+		slog.Error("Failed to forward audit log", "error", err)
+	}
+
+	if forwarded > 0 {
+		slog.Debug("Audit log entries forwarded", "count", forwarded)
+	}
+}
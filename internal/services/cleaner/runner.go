@@ -0,0 +1,119 @@
+// Package cleaner applies the project retention settings in the background,
+// then runs the engine store cleanup that the floxy cleanup plugin exposes.
+// Like the scheduler, it runs on a single replica elected with a Postgres advisory lock.
+package cleaner
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rom8726/di"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ di.Servicer = (*Runner)(nil)
+
+// advisoryLockKey identifies the retention leader lock ("floxyret").
+const advisoryLockKey int64 = 0x666c6f7879726574
+
+type Config struct {
+	Enabled  bool
+	Interval time.Duration
+}
+
+type Runner struct {
+	leaderLock *db.AdvisoryLock
+	retention  contract.RetentionUseCase
+	cfg        Config
+	isLeader   bool
+
+	ctxCancel context.CancelFunc
+	done      chan struct{}
+}
+
+func New(pool *pgxpool.Pool, retention contract.RetentionUseCase, cfg *Config) *Runner {
+	return &Runner{
+		leaderLock: db.NewAdvisoryLock(pool, advisoryLockKey),
+		retention:  retention,
+		cfg:        *cfg,
+	}
+}
+
+func (r *Runner) Start(context.Context) error {
+	if !r.cfg.Enabled {
+		slog.Info("Retention runner is disabled")
+
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.ctxCancel = cancel
+	r.done = make(chan struct{})
+
+	go r.loop(ctx)
+
+	return nil
+}
+
+func (r *Runner) Stop(ctx context.Context) error {
+	if r.ctxCancel == nil {
+		return nil
+	}
+
+	r.ctxCancel()
+
+	select {
+	case <-r.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if err := r.leaderLock.Release(ctx); err != nil {
+		slog.Warn("Failed to release retention advisory lock", "error", err)
+	}
+
+	return nil
+}
+
+func (r *Runner) loop(ctx context.Context) {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		r.tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Runner) tick(ctx context.Context) {
+	isLeader, err := r.leaderLock.TryAcquire(ctx)
+	if err != nil {
+		slog.Error("Retention leader election failed", "error", err)
+
+		return
+	}
+
+	if isLeader != r.isLeader {
+		r.isLeader = isLeader
+		slog.Info("Retention leadership changed", "leader", isLeader)
+	}
+
+	if !isLeader {
+		return
+	}
+
+	if err := r.retention.RunAll(ctx, time.Now()); err != nil {
+		slog.Error("Failed to apply retention settings", "error", err)
+	}
+}
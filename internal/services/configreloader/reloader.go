@@ -0,0 +1,123 @@
+// Package configreloader reloads the configuration on SIGHUP or on request of the admin API
+// and writes what changed to the audit log.
+package configreloader
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rom8726/di"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/repository/auditlog"
+)
+
+var (
+	_ contract.ConfigReloader = (*Reloader)(nil)
+	_ di.Servicer             = (*Reloader)(nil)
+)
+
+// signalUsername attributes the audit log entries of the reloads on SIGHUP.
+const signalUsername = "sighup"
+
+// Apply loads the configuration again, applies the reloadable settings to the running
+// components and returns the changed settings.
+type Apply func(ctx context.Context) ([]domain.ConfigChange, error)
+
+type Reloader struct {
+	pool  *pgxpool.Pool
+	apply Apply
+	mu    sync.Mutex
+
+	signals chan os.Signal
+	done    chan struct{}
+}
+
+func New(pool *pgxpool.Pool, apply Apply) *Reloader {
+	return &Reloader{
+		pool:  pool,
+		apply: apply,
+	}
+}
+
+// Reload applies the configuration, concurrent reloads run one after another.
+func (r *Reloader) Reload(ctx context.Context) (domain.ConfigReload, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	changes, err := r.apply(ctx)
+	if err != nil {
+		return domain.ConfigReload{}, err
+	}
+
+	auditChanges := make(map[string]auditlog.Change)
+	for _, change := range changes {
+		if change.Reloaded {
+			slog.InfoContext(ctx, "Config setting reloaded", "key", change.Key)
+			auditChanges[change.Key] = auditlog.Change{Old: change.Old, New: change.New}
+		} else {
+			slog.WarnContext(ctx, "Config setting changed, restart to apply it", "key", change.Key)
+		}
+	}
+
+	if appcontext.Username(ctx) != "" {
+		err := auditlog.WriteChangeLog(ctx, r.pool, domain.EntityConfig, "", domain.ActionReload, 0, auditChanges)
+		if err != nil {
+			return domain.ConfigReload{}, fmt.Errorf("write audit log: %w", err)
+		}
+	}
+
+	return domain.ConfigReload{
+		Changes:    changes,
+		ReloadedAt: time.Now(),
+	}, nil
+}
+
+// Start reloads the configuration on SIGHUP.
+func (r *Reloader) Start(context.Context) error {
+	r.signals = make(chan os.Signal, 1)
+	r.done = make(chan struct{})
+	signal.Notify(r.signals, syscall.SIGHUP)
+
+	go r.loop()
+
+	return nil
+}
+
+func (r *Reloader) Stop(ctx context.Context) error {
+	if r.signals == nil {
+		return nil
+	}
+
+	signal.Stop(r.signals)
+	close(r.signals)
+
+	select {
+	case <-r.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return nil
+}
+
+func (r *Reloader) loop() {
+	defer close(r.done)
+
+	for range r.signals {
+		ctx := appcontext.WithUsername(context.Background(), signalUsername)
+
+		if _, err := r.Reload(ctx); err != nil {
+			slog.Error("Failed to reload config on SIGHUP", "error", err)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// Package definitionsearch finds the steps of workflow definitions matching a search query.
+package definitionsearch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+const maxTextLength = 256
+
+var ErrInvalidQuery = errors.New("invalid search query")
+
+type definition struct {
+	Steps map[string]json.RawMessage `json:"steps"`
+}
+
+type step struct {
+	Type    string `json:"type"`
+	Handler string `json:"handler"`
+}
+
+// ValidateQuery trims the query and checks at least one criterion is set.
+func ValidateQuery(query *domain.WorkflowDefinitionSearchQuery) error {
+	query.StepType = strings.TrimSpace(query.StepType)
+	query.Handler = strings.TrimSpace(query.Handler)
+	query.Text = strings.TrimSpace(query.Text)
+
+	if query.StepType == "" && query.Handler == "" && query.Text == "" {
+		return fmt.Errorf("%w: one of step_type, handler or q is required", ErrInvalidQuery)
+	}
+
+	if len(query.Text) > maxTextLength {
+		return fmt.Errorf("%w: q is longer than %d bytes", ErrInvalidQuery, maxTextLength)
+	}
+
+	return nil
+}
+
+// MatchSteps returns the sorted names of the definition steps matching all the criteria of the query.
+func MatchSteps(raw json.RawMessage, query domain.WorkflowDefinitionSearchQuery) ([]string, error) {
+	var def definition
+	if err := json.Unmarshal(raw, &def); err != nil {
+		return nil, fmt.Errorf("decode workflow definition: %w", err)
+	}
+
+	text := strings.ToLower(query.Text)
+
+	var names []string
+	for name, rawStep := range def.Steps {
+		var s step
+		if err := json.Unmarshal(rawStep, &s); err != nil {
+			continue
+		}
+
+		if query.StepType != "" && s.Type != query.StepType {
+			continue
+		}
+
+		if query.Handler != "" && s.Handler != query.Handler {
+			continue
+		}
+
+		if text != "" && !strings.Contains(strings.ToLower(name), text) && !containsText(rawStep, text) {
+			continue
+		}
+
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// containsText reports whether a key or a value of the step contains the lowercase text.
+func containsText(rawStep json.RawMessage, text string) bool {
+	var value any
+	if err := json.Unmarshal(rawStep, &value); err != nil {
+		return false
+	}
+
+	return walk(value, func(s string) bool {
+		return strings.Contains(strings.ToLower(s), text)
+	})
+}
+
+func walk(value any, match func(string) bool) bool {
+	switch value := value.(type) {
+	case map[string]any:
+		for key, item := range value {
+			if match(key) || walk(item, match) {
+				return true
+			}
+		}
+	case []any:
+		for _, item := range value {
+			if walk(item, match) {
+				return true
+			}
+		}
+	case string:
+		return match(value)
+	case nil:
+	default:
+		return match(fmt.Sprint(value))
+	}
+
+	return false
+}
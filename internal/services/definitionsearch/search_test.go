@@ -0,0 +1,43 @@
+package definitionsearch
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+func TestMatchSteps(t *testing.T) {
+	def := json.RawMessage(`{"start": "fetch", "steps": {
+		"fetch": {"type": "task", "handler": "http_call", "metadata": {"queue": "Orders-High"}},
+		"notify": {"type": "task", "handler": "http_call"},
+		"check": {"type": "condition", "condition": "{{ gt .amount 100 }}"}
+	}}`)
+
+	steps, err := MatchSteps(def, domain.WorkflowDefinitionSearchQuery{Handler: "http_call"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"fetch", "notify"}, steps)
+
+	steps, err = MatchSteps(def, domain.WorkflowDefinitionSearchQuery{Handler: "http_call", Text: "orders-high"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"fetch"}, steps)
+
+	steps, err = MatchSteps(def, domain.WorkflowDefinitionSearchQuery{StepType: "condition", Text: "chec"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"check"}, steps)
+
+	steps, err = MatchSteps(def, domain.WorkflowDefinitionSearchQuery{StepType: "fork"})
+	require.NoError(t, err)
+	assert.Empty(t, steps)
+}
+
+func TestValidateQuery(t *testing.T) {
+	query := domain.WorkflowDefinitionSearchQuery{Text: "  queue "}
+	require.NoError(t, ValidateQuery(&query))
+	assert.Equal(t, "queue", query.Text)
+
+	assert.ErrorIs(t, ValidateQuery(&domain.WorkflowDefinitionSearchQuery{Text: " "}), ErrInvalidQuery)
+}
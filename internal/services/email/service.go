@@ -2,17 +2,25 @@ package email
 
 import (
 	"context"
-	"crypto/tls"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/smtp"
+	"net/textproto"
 	"path"
 	"strings"
 	"time"
 
 	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
 )
 
+// ErrNotConfigured is returned when an email is delivered while no mail server is configured.
+var ErrNotConfigured = errors.New("SMTP host is not configured")
+
+// authFailedCode is the SMTP reply code of rejected credentials.
+const authFailedCode = 535
+
 // Config holds email service configuration.
 type Config struct {
 	SMTPHost      string
@@ -21,28 +29,49 @@ type Config struct {
 	CertFile      string
 	KeyFile       string
 	AllowInsecure bool
-	UseTLS        bool
-	BaseURL       string
-	From          string
+	// UseTLS selects implicit TLS, see resolveSecurity for the defaults without it.
+	UseTLS bool
+	// StartTLS is a domain.SMTPStartTLS* mode, empty for the default of the port.
+	StartTLS string
+	BaseURL  string
+	From     string
+	// AuthMethod is domain.SMTPAuthPlain or domain.SMTPAuthXOAuth2.
+	AuthMethod string
+	OAuth2     OAuth2Config
 }
 
 // Service implements contract.Emailer.
+// Emails are queued in the outbox and sent in the background, see contract.EmailOutboxUseCase.
+// The mail server and the base URL of links come from the runtime settings, the config holds the fallback.
 type Service struct {
-	config Config
+	config   Config
+	settings contract.RuntimeSettingsReader
+	outbox   contract.EmailOutboxRepository
+	tokens   *tokenSource
 }
 
 // New creates a new email service.
-func New(config *Config) *Service {
+func New(
+	config *Config,
+	settings contract.RuntimeSettingsReader,
+	outbox contract.EmailOutboxRepository,
+) *Service {
 	return &Service{
-		config: *config,
+		config:   *config,
+		settings: settings,
+		outbox:   outbox,
+		tokens:   newTokenSource(),
 	}
 }
 
-var _ contract.Emailer = (*Service)(nil)
+var (
+	_ contract.Emailer        = (*Service)(nil)
+	_ contract.EmailDeliverer = (*Service)(nil)
+)
 
 // SendResetPasswordEmail sends a password reset email with a token.
 func (s *Service) SendResetPasswordEmail(ctx context.Context, emailAddr, token string) error {
-	resetURL := path.Join(s.config.BaseURL, "/reset-password?token=", token)
+	resetURL := path.Join(s.effectiveConfig(ctx).BaseURL, "/reset-password?token=", token)
 
 	subject := "Reset Your Password"
 	body := fmt.Sprintf(`
@@ -63,6 +92,29 @@ Floxy Manager Team
 	return s.sendEmail(ctx, emailAddr, subject, body)
 }
 
+// SendVerifyEmail sends a link confirming the email address of a new user.
+func (s *Service) SendVerifyEmail(ctx context.Context, emailAddr, token string, ttl time.Duration) error {
+	verifyURL := strings.TrimRight(s.effectiveConfig(ctx).BaseURL, "/") + "/verify-email?token=" + token
+
+	subject := "Verify Your Email Address"
+	body := fmt.Sprintf(`
+Hello,
+
+An account was created for you in Floxy Manager. Click the link below to verify your email address:
+
+%s
+
+This link will expire in %d hours.
+
+If you did not expect this email, please ignore it.
+
+Best regards,
+Floxy Manager Team
+`, verifyURL, int(ttl.Hours()))
+
+	return s.sendEmail(ctx, emailAddr, subject, body)
+}
+
 // Send2FACodeEmail sends a 2FA code email for the specified action.
 func (s *Service) Send2FACodeEmail(ctx context.Context, emailAddr, code, action string) error {
 	var subject, body string
@@ -119,96 +171,153 @@ Floxy Manager Team
 	return s.sendEmail(ctx, emailAddr, subject, body)
 }
 
-// sendEmail sends an email using SMTP.
-func (s *Service) sendEmail(ctx context.Context, to, subject, body string) error {
-	if s.config.SMTPHost == "" {
-		slog.Warn("SMTP host is not configured, email sending is disabled")
-		return nil
-	}
+// SendNotificationEmail sends a notification rendered by the caller.
+func (s *Service) SendNotificationEmail(ctx context.Context, emailAddr, subject, body string) error {
+	return s.sendEmail(ctx, emailAddr, subject, body)
+}
 
-	// Create a context with timeout
-	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
-	defer cancel()
-
-	// Set up authentication
-	auth := smtp.PlainAuth("", s.config.Username, s.config.Password, strings.Split(s.config.SMTPHost, ":")[0])
-
-	// Format message
-	msg := []byte(fmt.Sprintf("From: %s\r\n", s.config.From) +
-		fmt.Sprintf("To: %s\r\n", to) +
-		fmt.Sprintf("Subject: %s\r\n", subject) +
-		"Content-Type: text/plain; charset=UTF-8\r\n" +
-		"\r\n" +
-		body)
-
-	// Determine if we need TLS
-	host := strings.Split(s.config.SMTPHost, ":")[0]
-	addr := s.config.SMTPHost
-	if !strings.Contains(addr, ":") {
-		addr = host + ":25"
-	}
+// SendMembershipExpiredEmail tells a user that their time-bound access to a project has expired.
+func (s *Service) SendMembershipExpiredEmail(ctx context.Context, emailAddr string, project domain.Project) error {
+	subject := fmt.Sprintf("[%s] Your project access has expired", project.Name)
+	body := fmt.Sprintf(`
+Hello,
 
-	// Send email
-	if s.config.UseTLS {
-		// TLS connection
-		tlsConfig := &tls.Config{
-			ServerName:         host,
-			InsecureSkipVerify: s.config.AllowInsecure,
-		}
+Your time-bound access to project "%s" has expired and your membership was removed.
 
-		if s.config.CertFile != "" && s.config.KeyFile != "" {
-			cert, err := tls.LoadX509KeyPair(s.config.CertFile, s.config.KeyFile)
-			if err != nil {
-				return fmt.Errorf("load certificate: %w", err)
-			}
-			tlsConfig.Certificates = []tls.Certificate{cert}
-		}
+If you still need access, please contact a project manager.
 
-		conn, err := tls.Dial("tcp", addr, tlsConfig)
-		if err != nil {
-			return fmt.Errorf("tls dial: %w", err)
-		}
-		defer conn.Close()
+Best regards,
+Floxy Manager Team
+`, project.Name)
 
-		client, err := smtp.NewClient(conn, host)
-		if err != nil {
-			return fmt.Errorf("create smtp client: %w", err)
-		}
-		defer client.Close()
+	return s.sendEmail(ctx, emailAddr, subject, body)
+}
 
-		if err := client.Auth(auth); err != nil {
-			return fmt.Errorf("authenticate: %w", err)
-		}
+// SendMemberAccessExpiredEmail tells a project manager that the access of a member has expired.
+func (s *Service) SendMemberAccessExpiredEmail(
+	ctx context.Context,
+	emailAddr string,
+	project domain.Project,
+	member domain.User,
+) error {
+	subject := fmt.Sprintf("[%s] Access of %s has expired", project.Name, member.Username)
+	body := fmt.Sprintf(`
+Hello,
 
-		if err := client.Mail(s.config.From); err != nil {
-			return fmt.Errorf("set sender: %w", err)
-		}
+The time-bound access of user "%s" (%s) to project "%s" has expired and the membership was removed.
 
-		if err := client.Rcpt(to); err != nil {
-			return fmt.Errorf("set recipient: %w", err)
-		}
+You receive this email because you manage memberships of the project.
+
+Best regards,
+Floxy Manager Team
+`, member.Username, member.Email, project.Name)
+
+	return s.sendEmail(ctx, emailAddr, subject, body)
+}
+
+// SendTestEmail sends an email checking the mail server configuration.
+// Unlike the other emails, it is sent right away and fails when no mail server is configured.
+func (s *Service) SendTestEmail(ctx context.Context, emailAddr string) error {
+	subject := "Floxy Manager test email"
+	body := fmt.Sprintf(`
+Hello,
+
+This is a test email sent from the SMTP settings of Floxy Manager at %s.
+
+If you received it, the mail server is configured correctly.
+
+Best regards,
+Floxy Manager Team
+`, time.Now().UTC().Format(time.RFC3339))
+
+	return s.Deliver(ctx, emailAddr, subject, body)
+}
+
+// Deliver sends an email right away, the outbox sender uses it for queued emails.
+func (s *Service) Deliver(ctx context.Context, emailAddr, subject, body string) error {
+	config := s.effectiveConfig(ctx)
+	if config.SMTPHost == "" {
+		return ErrNotConfigured
+	}
 
-		writer, err := client.Data()
+	host := hostName(config.SMTPHost)
+
+	var auth smtp.Auth
+	if config.AuthMethod == domain.SMTPAuthXOAuth2 {
+		token, err := s.tokens.Token(ctx, config.OAuth2)
 		if err != nil {
-			return fmt.Errorf("get data writer: %w", err)
+			return fmt.Errorf("get OAuth2 token: %w", err)
 		}
 
-		if _, err := writer.Write(msg); err != nil {
-			writer.Close()
-			return fmt.Errorf("write message: %w", err)
-		}
+		auth = &xoauth2Auth{username: config.Username, token: token, host: host}
+	} else {
+		auth = smtp.PlainAuth("", config.Username, config.Password, host)
+	}
+
+	err := deliver(ctx, config, auth, emailAddr, subject, body)
+
+	// A rejected token may have been revoked, the next attempt gets a new one
+	var smtpErr *textproto.Error
+	if errors.As(err, &smtpErr) && smtpErr.Code == authFailedCode && config.AuthMethod == domain.SMTPAuthXOAuth2 {
+		s.tokens.Invalidate(config.OAuth2)
+	}
+
+	return err
+}
 
-		if err := writer.Close(); err != nil {
-			return fmt.Errorf("close writer: %w", err)
+// effectiveConfig returns the config with the values of the runtime settings.
+// The config is used as is when the settings cannot be read.
+func (s *Service) effectiveConfig(ctx context.Context) Config {
+	config := s.config
+
+	smtpConfig, err := s.settings.GetSMTPConfig(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to read SMTP settings, using the environment", "error", err)
+	} else {
+		config.SMTPHost = smtpConfig.Addr
+		config.Username = smtpConfig.User
+		config.Password = smtpConfig.Password
+		config.From = smtpConfig.From
+		config.AllowInsecure = smtpConfig.AllowInsecure
+		config.UseTLS = smtpConfig.UseTLS
+		config.StartTLS = smtpConfig.StartTLS
+		config.AuthMethod = smtpConfig.AuthMethod
+		config.OAuth2 = OAuth2Config{
+			TokenURL:     smtpConfig.OAuth2TokenURL,
+			ClientID:     smtpConfig.OAuth2ClientID,
+			ClientSecret: smtpConfig.OAuth2ClientSecret,
+			RefreshToken: smtpConfig.OAuth2RefreshToken,
+			Scopes:       smtpConfig.OAuth2Scopes,
 		}
+	}
+
+	general, err := s.settings.GetGeneralSettings(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to read general settings, using the environment", "error", err)
+	} else if general.FrontendURL != "" {
+		config.BaseURL = general.FrontendURL
+	}
+
+	return config
+}
 
-		return client.Quit()
+// sendEmail queues an email in the outbox. Queued within a transaction, it is only sent once committed.
+func (s *Service) sendEmail(ctx context.Context, to, subject, body string) error {
+	if s.effectiveConfig(ctx).SMTPHost == "" {
+		slog.Warn("SMTP host is not configured, email sending is disabled")
+		return nil
 	}
 
-	// Plain SMTP
-	err := smtp.SendMail(addr, auth, s.config.From, []string{to}, msg)
+	now := time.Now()
+
+	_, err := s.outbox.Enqueue(ctx, domain.OutboxEmail{
+		Recipient:     to,
+		Subject:       subject,
+		Body:          body,
+		NextAttemptAt: &now,
+	})
 	if err != nil {
-		return fmt.Errorf("send mail: %w", err)
+		return fmt.Errorf("enqueue email: %w", err)
 	}
 
 	return nil
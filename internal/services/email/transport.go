@@ -0,0 +1,152 @@
+package email
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+const sendTimeout = 10 * time.Second
+
+// ErrStartTLSUnsupported is returned when STARTTLS is required but not offered by the mail server.
+var ErrStartTLSUnsupported = errors.New("mail server does not support STARTTLS")
+
+// security is how the connection to the mail server is protected.
+type security struct {
+	implicitTLS bool
+	startTLS    string
+}
+
+// resolveSecurity applies the port-based defaults: implicit TLS on 465, required STARTTLS on 587
+// and opportunistic STARTTLS elsewhere. UseTLS always selects implicit TLS.
+func resolveSecurity(config Config, port string) security {
+	if config.UseTLS {
+		return security{implicitTLS: true}
+	}
+
+	if config.StartTLS != "" {
+		return security{startTLS: config.StartTLS}
+	}
+
+	switch port {
+	case "465":
+		return security{implicitTLS: true}
+	case "587":
+		return security{startTLS: domain.SMTPStartTLSRequire}
+	default:
+		return security{startTLS: domain.SMTPStartTLSOpportunistic}
+	}
+}
+
+// deliver sends an email through the mail server of the config.
+func deliver(ctx context.Context, config Config, auth smtp.Auth, to, subject, body string) error {
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	// Format message
+	msg := []byte(fmt.Sprintf("From: %s\r\n", config.From) +
+		fmt.Sprintf("To: %s\r\n", to) +
+		fmt.Sprintf("Subject: %s\r\n", subject) +
+		"Content-Type: text/plain; charset=UTF-8\r\n" +
+		"\r\n" +
+		body)
+
+	host, port, err := net.SplitHostPort(config.SMTPHost)
+	if err != nil {
+		host, port = config.SMTPHost, "25"
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName:         host,
+		InsecureSkipVerify: config.AllowInsecure, //nolint:gosec // explicitly allowed by the configuration
+	}
+
+	if config.CertFile != "" && config.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+		if err != nil {
+			return fmt.Errorf("load certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	sec := resolveSecurity(config, port)
+
+	var conn net.Conn
+	dialer := &net.Dialer{}
+	if sec.implicitTLS {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", net.JoinHostPort(host, port))
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, port))
+	}
+	if err != nil {
+		return fmt.Errorf("dial: %w", err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return fmt.Errorf("create smtp client: %w", err)
+	}
+	defer client.Close()
+
+	if !sec.implicitTLS && sec.startTLS != domain.SMTPStartTLSDisabled {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				return fmt.Errorf("starttls: %w", err)
+			}
+		} else if sec.startTLS == domain.SMTPStartTLSRequire {
+			return ErrStartTLSUnsupported
+		}
+	}
+
+	// Like smtp.SendMail, credentials are only sent to servers offering authentication
+	if ok, _ := client.Extension("AUTH"); ok && auth != nil {
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("authenticate: %w", err)
+		}
+	}
+
+	if err := client.Mail(config.From); err != nil {
+		return fmt.Errorf("set sender: %w", err)
+	}
+
+	if err := client.Rcpt(to); err != nil {
+		return fmt.Errorf("set recipient: %w", err)
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("get data writer: %w", err)
+	}
+
+	if _, err := writer.Write(msg); err != nil {
+		writer.Close()
+		return fmt.Errorf("write message: %w", err)
+	}
+
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("close writer: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// hostName returns the host of the mail server address.
+func hostName(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+
+	return strings.TrimSuffix(addr, ":")
+}
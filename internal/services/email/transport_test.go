@@ -0,0 +1,20 @@
+package email
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+func TestResolveSecurity(t *testing.T) {
+	assert.Equal(t, security{implicitTLS: true}, resolveSecurity(Config{}, "465"))
+	assert.Equal(t, security{startTLS: domain.SMTPStartTLSRequire}, resolveSecurity(Config{}, "587"))
+	assert.Equal(t, security{startTLS: domain.SMTPStartTLSOpportunistic}, resolveSecurity(Config{}, "25"))
+
+	// Explicit settings win over the port
+	assert.Equal(t, security{implicitTLS: true}, resolveSecurity(Config{UseTLS: true}, "587"))
+	assert.Equal(t, security{startTLS: domain.SMTPStartTLSDisabled},
+		resolveSecurity(Config{StartTLS: domain.SMTPStartTLSDisabled}, "587"))
+}
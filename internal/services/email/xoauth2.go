@@ -0,0 +1,173 @@
+package email
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	tokenRequestTimeout = 10 * time.Second
+	// tokenExpiryMargin renews tokens before they expire during a send.
+	tokenExpiryMargin = time.Minute
+	maxTokenErrorSize = 1024
+)
+
+// OAuth2Config configures the XOAUTH2 authentication with the mail server.
+type OAuth2Config struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	// RefreshToken selects the refresh token grant, the client credentials grant is used without it.
+	RefreshToken string
+	Scopes       []string
+}
+
+func (c OAuth2Config) cacheKey() string {
+	return strings.Join([]string{c.TokenURL, c.ClientID, c.RefreshToken, strings.Join(c.Scopes, " ")}, "\x00")
+}
+
+type cachedToken struct {
+	accessToken string
+	expiresAt   time.Time
+}
+
+// tokenSource obtains OAuth2 access tokens and caches them until shortly before they expire.
+type tokenSource struct {
+	client *http.Client
+
+	mu     sync.Mutex
+	tokens map[string]cachedToken
+}
+
+func newTokenSource() *tokenSource {
+	return &tokenSource{
+		client: &http.Client{Timeout: tokenRequestTimeout},
+		tokens: make(map[string]cachedToken),
+	}
+}
+
+func (t *tokenSource) Token(ctx context.Context, config OAuth2Config) (string, error) {
+	key := config.cacheKey()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if token, ok := t.tokens[key]; ok && time.Now().Before(token.expiresAt) {
+		return token.accessToken, nil
+	}
+
+	token, err := t.fetch(ctx, config)
+	if err != nil {
+		return "", err
+	}
+
+	t.tokens[key] = token
+
+	return token.accessToken, nil
+}
+
+// Invalidate drops the cached token, e.g. when the mail server rejected it.
+func (t *tokenSource) Invalidate(config OAuth2Config) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.tokens, config.cacheKey())
+}
+
+func (t *tokenSource) fetch(ctx context.Context, config OAuth2Config) (cachedToken, error) {
+	form := url.Values{}
+	form.Set("client_id", config.ClientID)
+	if config.ClientSecret != "" {
+		form.Set("client_secret", config.ClientSecret)
+	}
+
+	if config.RefreshToken != "" {
+		form.Set("grant_type", "refresh_token")
+		form.Set("refresh_token", config.RefreshToken)
+	} else {
+		form.Set("grant_type", "client_credentials")
+	}
+
+	if len(config.Scopes) > 0 {
+		form.Set("scope", strings.Join(config.Scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return cachedToken{}, fmt.Errorf("create token request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return cachedToken{}, fmt.Errorf("request token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxTokenErrorSize))
+
+		return cachedToken{}, fmt.Errorf("token endpoint returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return cachedToken{}, fmt.Errorf("decode token response: %w", err)
+	}
+
+	if token.AccessToken == "" {
+		return cachedToken{}, errors.New("token endpoint returned no access token")
+	}
+
+	return cachedToken{
+		accessToken: token.AccessToken,
+		expiresAt:   time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - tokenExpiryMargin),
+	}, nil
+}
+
+// xoauth2Auth implements the XOAUTH2 SASL mechanism of Gmail and Microsoft 365.
+type xoauth2Auth struct {
+	username string
+	token    string
+	host     string
+}
+
+func (a *xoauth2Auth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	// Like smtp.PlainAuth, the token is only sent over TLS or to localhost
+	if !server.TLS && !isLocalhost(server.Name) {
+		return "", nil, errors.New("unencrypted connection")
+	}
+
+	if server.Name != a.host {
+		return "", nil, errors.New("wrong host name")
+	}
+
+	return "XOAUTH2", []byte("user=" + a.username + "\x01auth=Bearer " + a.token + "\x01\x01"), nil
+}
+
+func (a *xoauth2Auth) Next(_ []byte, more bool) ([]byte, error) {
+	if more {
+		// The server challenges with a JSON error, the empty response makes it report the failure
+		return []byte{}, nil
+	}
+
+	return nil, nil
+}
+
+func isLocalhost(name string) bool {
+	return name == "localhost" || name == "127.0.0.1" || name == "::1"
+}
@@ -0,0 +1,62 @@
+package email
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenSource_Token(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		requests = append(requests, r.PostForm.Get("grant_type")+" "+r.PostForm.Get("scope"))
+
+		_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "token-1", "expires_in": 3600})
+	}))
+	defer server.Close()
+
+	tokens := newTokenSource()
+	config := OAuth2Config{
+		TokenURL:     server.URL,
+		ClientID:     "client",
+		ClientSecret: "secret",
+		Scopes:       []string{"https://outlook.office365.com/.default"},
+	}
+
+	token, err := tokens.Token(context.Background(), config)
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", token)
+
+	// The token is cached until it expires or is rejected
+	_, err = tokens.Token(context.Background(), config)
+	require.NoError(t, err)
+	assert.Len(t, requests, 1)
+
+	tokens.Invalidate(config)
+	config.RefreshToken = "refresh"
+	_, err = tokens.Token(context.Background(), config)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"client_credentials https://outlook.office365.com/.default",
+		"refresh_token https://outlook.office365.com/.default",
+	}, requests)
+}
+
+func TestXOAuth2Auth_Start(t *testing.T) {
+	auth := &xoauth2Auth{username: "noreply@example.com", token: "token-1", host: "smtp.example.com"}
+
+	mechanism, response, err := auth.Start(&smtp.ServerInfo{Name: "smtp.example.com", TLS: true})
+	require.NoError(t, err)
+	assert.Equal(t, "XOAUTH2", mechanism)
+	assert.Equal(t, "user=noreply@example.com\x01auth=Bearer token-1\x01\x01", string(response))
+
+	_, _, err = auth.Start(&smtp.ServerInfo{Name: "smtp.example.com"})
+	require.Error(t, err)
+}
@@ -0,0 +1,124 @@
+// Package emailsender sends the queued emails, such as password resets, email verifications and
+// 2FA codes, in the background, apart from the notifier so they are not delayed by notifications.
+// Like the notifier, it runs on a single replica elected with a Postgres advisory lock.
+package emailsender
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rom8726/di"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ di.Servicer = (*Runner)(nil)
+
+// advisoryLockKey identifies the email outbox leader lock ("floxyeml").
+const advisoryLockKey int64 = 0x666c6f7879656d6c
+
+type Config struct {
+	Enabled  bool
+	Interval time.Duration
+}
+
+type Runner struct {
+	leaderLock *db.AdvisoryLock
+	emails     contract.EmailOutboxUseCase
+	cfg        Config
+	isLeader   bool
+
+	ctxCancel context.CancelFunc
+	done      chan struct{}
+}
+
+func New(pool *pgxpool.Pool, emails contract.EmailOutboxUseCase, cfg *Config) *Runner {
+	return &Runner{
+		leaderLock: db.NewAdvisoryLock(pool, advisoryLockKey),
+		emails:     emails,
+		cfg:        *cfg,
+	}
+}
+
+func (r *Runner) Start(context.Context) error {
+	if !r.cfg.Enabled {
+		slog.Info("Email outbox sender is disabled")
+
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.ctxCancel = cancel
+	r.done = make(chan struct{})
+
+	go r.loop(ctx)
+
+	return nil
+}
+
+func (r *Runner) Stop(ctx context.Context) error {
+	if r.ctxCancel == nil {
+		return nil
+	}
+
+	r.ctxCancel()
+
+	select {
+	case <-r.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if err := r.leaderLock.Release(ctx); err != nil {
+		slog.Warn("Failed to release email outbox advisory lock", "error", err)
+	}
+
+	return nil
+}
+
+func (r *Runner) loop(ctx context.Context) {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		r.tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Runner) tick(ctx context.Context) {
+	isLeader, err := r.leaderLock.TryAcquire(ctx)
+	if err != nil {
+		slog.Error("Email outbox leader election failed", "error", err)
+
+		return
+	}
+
+	if isLeader != r.isLeader {
+		r.isLeader = isLeader
+		slog.Info("Email outbox leadership changed", "leader", isLeader)
+	}
+
+	if !isLeader {
+		return
+	}
+
+	sent, err := r.emails.SendPending(ctx, time.Now())
+	if err != nil {
+		slog.Error("Failed to send queued emails", "error", err)
+	}
+
+	if sent > 0 {
+		slog.Debug("Queued emails sent", "count", sent)
+	}
+}
@@ -0,0 +1,191 @@
+package instancegraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+// Step statuses of floxy used to derive the compensation state
+const (
+	stepStatusCompensation = "compensation"
+	stepStatusRolledBack   = "rolled_back"
+)
+
+type graphDefinition struct {
+	Start string                    `json:"start"`
+	Steps map[string]stepDefinition `json:"steps"`
+}
+
+type stepDefinition struct {
+	Type       string   `json:"type"`
+	Handler    string   `json:"handler"`
+	MaxRetries int      `json:"max_retries"`
+	Next       []string `json:"next"`
+	Else       string   `json:"else"`
+	OnFailure  string   `json:"on_failure"`
+	Parallel   []string `json:"parallel"`
+}
+
+// Build annotates the definition graph with the executions of the instance steps.
+// Steps may be in any order; the latest execution of a step defines its node state.
+func Build(
+	definition json.RawMessage,
+	instance domain.WorkflowInstance,
+	steps []domain.WorkflowStep,
+) (domain.InstanceGraph, error) {
+	var def graphDefinition
+	if len(definition) > 0 {
+		if err := json.Unmarshal(definition, &def); err != nil {
+			return domain.InstanceGraph{}, fmt.Errorf("parse definition: %w", err)
+		}
+	}
+
+	graph := domain.InstanceGraph{
+		InstanceID:  instance.ID,
+		WorkflowID:  instance.WorkflowID,
+		Status:      instance.Status,
+		Start:       def.Start,
+		StartedAt:   nullTime(instance.StartedAt.Valid, instance.StartedAt.Time),
+		CompletedAt: nullTime(instance.CompletedAt.Valid, instance.CompletedAt.Time),
+		Nodes:       []domain.InstanceGraphNode{},
+		Edges:       []domain.InstanceGraphEdge{},
+	}
+
+	executions := make(map[string][]domain.WorkflowStep)
+	for _, step := range steps {
+		executions[step.StepName] = append(executions[step.StepName], step)
+	}
+
+	for _, name := range order(def) {
+		stepDef := def.Steps[name]
+		node := domain.InstanceGraphNode{
+			Name:         name,
+			Type:         stepDef.Type,
+			Handler:      stepDef.Handler,
+			MaxRetries:   stepDef.MaxRetries,
+			Status:       domain.InstanceGraphNotStarted,
+			Compensation: domain.CompensationNone,
+			InDefinition: true,
+		}
+		applyExecutions(&node, executions[name])
+		graph.Nodes = append(graph.Nodes, node)
+
+		graph.Edges = appendEdges(graph.Edges, name, "next", stepDef.Next...)
+		graph.Edges = appendEdges(graph.Edges, name, "parallel", stepDef.Parallel...)
+		graph.Edges = appendEdges(graph.Edges, name, "else", stepDef.Else)
+		graph.Edges = appendEdges(graph.Edges, name, "on_failure", stepDef.OnFailure)
+	}
+
+	// Steps run by the engine but absent in the definition, e.g. after the definition was edited
+	extra := make([]string, 0)
+	for name := range executions {
+		if _, ok := def.Steps[name]; !ok {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+
+	for _, name := range extra {
+		node := domain.InstanceGraphNode{
+			Name:         name,
+			Compensation: domain.CompensationNone,
+		}
+		applyExecutions(&node, executions[name])
+		graph.Nodes = append(graph.Nodes, node)
+	}
+
+	return graph, nil
+}
+
+// order lists the steps breadth-first from the start step, then the unreachable ones by name.
+func order(def graphDefinition) []string {
+	names := make([]string, 0, len(def.Steps))
+	visited := make(map[string]bool, len(def.Steps))
+
+	queue := []string{def.Start}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+
+		stepDef, ok := def.Steps[name]
+		if !ok || visited[name] {
+			continue
+		}
+		visited[name] = true
+		names = append(names, name)
+
+		queue = append(queue, stepDef.Next...)
+		queue = append(queue, stepDef.Parallel...)
+		queue = append(queue, stepDef.Else, stepDef.OnFailure)
+	}
+
+	rest := make([]string, 0)
+	for name := range def.Steps {
+		if !visited[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+
+	return append(names, rest...)
+}
+
+func applyExecutions(node *domain.InstanceGraphNode, executions []domain.WorkflowStep) {
+	if len(executions) == 0 {
+		return
+	}
+
+	latest := executions[0]
+	for _, step := range executions[1:] {
+		if step.CreatedAt.After(latest.CreatedAt) || step.CreatedAt.Equal(latest.CreatedAt) && step.ID > latest.ID {
+			latest = step
+		}
+	}
+
+	node.Executions = len(executions)
+	node.Status = latest.Status
+	node.RetryCount = latest.RetryCount
+	node.MaxRetries = latest.MaxRetries
+	node.CompensationRetryCount = latest.CompensationRetryCount
+	node.Error = latest.Error.String
+	node.StartedAt = nullTime(latest.StartedAt.Valid, latest.StartedAt.Time)
+	node.CompletedAt = nullTime(latest.CompletedAt.Valid, latest.CompletedAt.Time)
+
+	if node.Type == "" {
+		node.Type = latest.StepType
+	}
+
+	switch latest.Status {
+	case stepStatusCompensation:
+		node.Compensation = domain.CompensationRunning
+	case stepStatusRolledBack:
+		node.Compensation = domain.CompensationRolledBack
+	}
+
+	if latest.StartedAt.Valid && latest.CompletedAt.Valid {
+		duration := latest.CompletedAt.Time.Sub(latest.StartedAt.Time).Milliseconds()
+		node.DurationMs = &duration
+	}
+}
+
+func appendEdges(edges []domain.InstanceGraphEdge, from, kind string, to ...string) []domain.InstanceGraphEdge {
+	for _, target := range to {
+		if target != "" {
+			edges = append(edges, domain.InstanceGraphEdge{From: from, To: target, Kind: kind})
+		}
+	}
+
+	return edges
+}
+
+func nullTime(valid bool, t time.Time) *time.Time {
+	if !valid {
+		return nil
+	}
+
+	return &t
+}
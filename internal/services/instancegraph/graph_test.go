@@ -0,0 +1,64 @@
+package instancegraph
+
+import (
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+func TestBuild(t *testing.T) {
+	definition := json.RawMessage(`{
+		"start": "validate",
+		"steps": {
+			"validate": {"name": "validate", "type": "task", "handler": "validate", "next": ["charge"]},
+			"charge": {"name": "charge", "type": "task", "handler": "charge", "max_retries": 3,
+				"next": ["ship"], "on_failure": "refund"},
+			"ship": {"name": "ship", "type": "task", "handler": "ship"},
+			"refund": {"name": "refund", "type": "task", "handler": "refund"}
+		}
+	}`)
+	started := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) sql.NullTime { return sql.NullTime{Time: started.Add(d), Valid: true} }
+
+	steps := []domain.WorkflowStep{
+		{ID: 1, StepName: "validate", Status: "completed", StartedAt: at(0), CompletedAt: at(time.Second),
+			CreatedAt: started},
+		{ID: 2, StepName: "charge", Status: "failed", RetryCount: 3, StartedAt: at(time.Second),
+			Error: sql.NullString{String: "card declined", Valid: true}, CreatedAt: started.Add(time.Second)},
+		{ID: 3, StepName: "charge", Status: "rolled_back", RetryCount: 3, CompensationRetryCount: 1,
+			CreatedAt: started.Add(2 * time.Second)},
+		{ID: 4, StepName: "notify", StepType: "task", Status: "completed", CreatedAt: started},
+	}
+
+	graph, err := Build(definition, domain.WorkflowInstance{ID: 7, WorkflowID: "orders-v1", Status: "failed"}, steps)
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(graph.Nodes))
+	for _, node := range graph.Nodes {
+		names = append(names, node.Name)
+	}
+	assert.Equal(t, []string{"validate", "charge", "ship", "refund", "notify"}, names)
+
+	validate := graph.Nodes[0]
+	assert.Equal(t, "completed", validate.Status)
+	require.NotNil(t, validate.DurationMs)
+	assert.Equal(t, int64(1000), *validate.DurationMs)
+
+	charge := graph.Nodes[1]
+	assert.Equal(t, "rolled_back", charge.Status)
+	assert.Equal(t, domain.CompensationRolledBack, charge.Compensation)
+	assert.Equal(t, 2, charge.Executions)
+	assert.Equal(t, 1, charge.CompensationRetryCount)
+
+	assert.Equal(t, domain.InstanceGraphNotStarted, graph.Nodes[2].Status)
+	assert.False(t, graph.Nodes[4].InDefinition)
+
+	assert.Contains(t, graph.Edges, domain.InstanceGraphEdge{From: "charge", To: "refund", Kind: "on_failure"})
+	assert.Len(t, graph.Edges, 3)
+}
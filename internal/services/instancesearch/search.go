@@ -0,0 +1,314 @@
+// Package instancesearch parses instance search queries and locates their matches in instance payloads.
+package instancesearch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+const (
+	// MinTextLength is the shortest text query, shorter ones can't use trigram indexes.
+	MinTextLength  = 3
+	maxQueryLength = 1024
+
+	// snippetContext is the number of characters kept around a match in snippets.
+	snippetContext = 40
+	// maxMatches bounds the matches reported for one instance.
+	maxMatches = 20
+)
+
+var ErrInvalidQuery = errors.New("invalid search query")
+
+// ParseQuery parses the q parameter: a JSON object or array is a containment query,
+// anything else is a text query.
+func ParseQuery(q string) (domain.InstanceSearchQuery, error) {
+	q = strings.TrimSpace(q)
+	if len(q) > maxQueryLength {
+		return domain.InstanceSearchQuery{}, fmt.Errorf("%w: query is longer than %d bytes", ErrInvalidQuery, maxQueryLength)
+	}
+
+	if strings.HasPrefix(q, "{") || strings.HasPrefix(q, "[") {
+		if !json.Valid([]byte(q)) {
+			return domain.InstanceSearchQuery{}, fmt.Errorf("%w: malformed JSON document", ErrInvalidQuery)
+		}
+
+		return domain.InstanceSearchQuery{Contains: json.RawMessage(q)}, nil
+	}
+
+	if utf8.RuneCountInString(q) < MinTextLength {
+		return domain.InstanceSearchQuery{}, fmt.Errorf("%w: query must be at least %d characters", ErrInvalidQuery, MinTextLength)
+	}
+
+	return domain.InstanceSearchQuery{Text: q}, nil
+}
+
+// Highlight returns the matches of the query in the instance input, output and error.
+func Highlight(instance *domain.WorkflowInstance, query domain.InstanceSearchQuery) []domain.InstanceSearchMatch {
+	var matches []domain.InstanceSearchMatch
+
+	if len(query.Contains) > 0 {
+		doc, ok := decode(query.Contains)
+		if !ok {
+			return nil
+		}
+
+		for _, field := range []struct {
+			name  string
+			value json.RawMessage
+		}{{"input", instance.Input}, {"output", instance.Output}} {
+			value, ok := decode(field.value)
+			if !ok || !contains(value, doc) {
+				continue
+			}
+
+			walk(doc, "$", func(path string, leaf any) {
+				snippet := encode(leaf)
+				matches = append(matches, domain.InstanceSearchMatch{
+					Field:    field.name,
+					Path:     path,
+					Snippet:  snippet,
+					MatchEnd: utf8.RuneCountInString(snippet),
+				})
+			})
+		}
+
+		return limit(matches)
+	}
+
+	pattern := regexp.MustCompile("(?i)" + regexp.QuoteMeta(query.Text))
+
+	for _, field := range []struct {
+		name  string
+		value json.RawMessage
+	}{{"input", instance.Input}, {"output", instance.Output}} {
+		value, ok := decode(field.value)
+		if !ok {
+			continue
+		}
+
+		walkKeys(value, "$", func(path, text string) {
+			if match, ok := snippet(pattern, text, path, field.name); ok {
+				matches = append(matches, match)
+			}
+		})
+	}
+
+	if instance.Error.Valid {
+		if match, ok := snippet(pattern, instance.Error.String, "$", "error"); ok {
+			matches = append(matches, match)
+		}
+	}
+
+	return limit(matches)
+}
+
+func snippet(pattern *regexp.Regexp, text, path, field string) (domain.InstanceSearchMatch, bool) {
+	loc := pattern.FindStringIndex(text)
+	if loc == nil {
+		return domain.InstanceSearchMatch{}, false
+	}
+
+	before := []rune(text[:loc[0]])
+	matched := []rune(text[loc[0]:loc[1]])
+	after := []rune(text[loc[1]:])
+
+	prefix := ""
+	if len(before) > snippetContext {
+		before = before[len(before)-snippetContext:]
+		prefix = "…"
+	}
+
+	suffix := ""
+	if len(after) > snippetContext {
+		after = after[:snippetContext]
+		suffix = "…"
+	}
+
+	start := utf8.RuneCountInString(prefix) + len(before)
+
+	return domain.InstanceSearchMatch{
+		Field:      field,
+		Path:       path,
+		Snippet:    prefix + string(before) + string(matched) + string(after) + suffix,
+		MatchStart: start,
+		MatchEnd:   start + len(matched),
+	}, true
+}
+
+func limit(matches []domain.InstanceSearchMatch) []domain.InstanceSearchMatch {
+	if len(matches) > maxMatches {
+		return matches[:maxMatches]
+	}
+
+	return matches
+}
+
+func decode(raw json.RawMessage) (any, bool) {
+	if len(raw) == 0 {
+		return nil, false
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, false
+	}
+
+	return value, true
+}
+
+func encode(value any) string {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+
+	return string(b)
+}
+
+// contains reports whether value contains doc with the semantics of the jsonb @> operator.
+func contains(value, doc any) bool {
+	switch doc := doc.(type) {
+	case map[string]any:
+		object, ok := value.(map[string]any)
+		if !ok {
+			return false
+		}
+
+		for key, item := range doc {
+			v, ok := object[key]
+			if !ok || !contains(v, item) {
+				return false
+			}
+		}
+
+		return true
+	case []any:
+		array, ok := value.([]any)
+		if !ok {
+			return false
+		}
+
+		for _, item := range doc {
+			if !slicesContain(array, item) {
+				return false
+			}
+		}
+
+		return true
+	default:
+		// A top-level array contains its primitive elements
+		if array, ok := value.([]any); ok {
+			return slicesContain(array, doc)
+		}
+
+		return scalarEqual(value, doc)
+	}
+}
+
+func slicesContain(array []any, doc any) bool {
+	for _, v := range array {
+		if _, nested := v.([]any); nested {
+			if _, ok := doc.([]any); !ok {
+				continue
+			}
+		}
+
+		if contains(v, doc) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func scalarEqual(a, b any) bool {
+	an, aok := a.(json.Number)
+	bn, bok := b.(json.Number)
+	if aok && bok {
+		af, aerr := an.Float64()
+		bf, berr := bn.Float64()
+
+		return aerr == nil && berr == nil && af == bf
+	}
+
+	return a == b
+}
+
+// walk calls fn for every scalar of the document, empty objects and arrays are leaves too.
+func walk(value any, path string, fn func(path string, leaf any)) {
+	switch value := value.(type) {
+	case map[string]any:
+		if len(value) == 0 {
+			fn(path, value)
+			return
+		}
+
+		for _, key := range sortedKeys(value) {
+			walk(value[key], childPath(path, key), fn)
+		}
+	case []any:
+		if len(value) == 0 {
+			fn(path, value)
+			return
+		}
+
+		for i, item := range value {
+			walk(item, path+"["+strconv.Itoa(i)+"]", fn)
+		}
+	default:
+		fn(path, value)
+	}
+}
+
+// walkKeys calls fn with the text of every object key and scalar of the document.
+func walkKeys(value any, path string, fn func(path, text string)) {
+	switch value := value.(type) {
+	case map[string]any:
+		for _, key := range sortedKeys(value) {
+			child := childPath(path, key)
+			fn(child, key)
+			walkKeys(value[key], child, fn)
+		}
+	case []any:
+		for i, item := range value {
+			walkKeys(item, path+"["+strconv.Itoa(i)+"]", fn)
+		}
+	case string:
+		fn(path, value)
+	case nil:
+	default:
+		fn(path, encode(value))
+	}
+}
+
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func childPath(path, key string) string {
+	if identifier.MatchString(key) {
+		return path + "." + key
+	}
+
+	return path + "[" + strconv.Quote(key) + "]"
+}
+
+func sortedKeys(object map[string]any) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,71 @@
+package instancesearch
+
+import (
+	"database/sql"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+func TestParseQuery(t *testing.T) {
+	query, err := ParseQuery(` {"order": {"id": 42}} `)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"order": {"id": 42}}`, string(query.Contains))
+
+	query, err = ParseQuery("alice")
+	require.NoError(t, err)
+	assert.Equal(t, domain.InstanceSearchQuery{Text: "alice"}, query)
+
+	for _, q := range []string{"", "ab", `{"order":`} {
+		_, err := ParseQuery(q)
+		assert.ErrorIs(t, err, ErrInvalidQuery, q)
+	}
+}
+
+func TestHighlight_Contains(t *testing.T) {
+	instance := &domain.WorkflowInstance{
+		Input:  json.RawMessage(`{"order": {"id": 42.0, "tags": ["a", "b"]}, "user": "bob"}`),
+		Output: json.RawMessage(`{"order": {"id": 7}}`),
+	}
+
+	matches := Highlight(instance, domain.InstanceSearchQuery{
+		Contains: json.RawMessage(`{"order": {"id": 42, "tags": ["b"]}}`),
+	})
+
+	assert.Equal(t, []domain.InstanceSearchMatch{
+		{Field: "input", Path: "$.order.id", Snippet: "42", MatchEnd: 2},
+		{Field: "input", Path: "$.order.tags[0]", Snippet: `"b"`, MatchEnd: 3},
+	}, matches)
+}
+
+func TestHighlight_Text(t *testing.T) {
+	instance := &domain.WorkflowInstance{
+		Input:  json.RawMessage(`{"customer name": "Alice Smith", "items": [{"sku": "X-1"}]}`),
+		Output: json.RawMessage(`null`),
+		Error:  sql.NullString{String: "payment for alice declined", Valid: true},
+	}
+
+	matches := Highlight(instance, domain.InstanceSearchQuery{Text: "ALICE"})
+
+	assert.Equal(t, []domain.InstanceSearchMatch{
+		{Field: "input", Path: `$["customer name"]`, Snippet: "Alice Smith", MatchStart: 0, MatchEnd: 5},
+		{Field: "error", Path: "$", Snippet: "payment for alice declined", MatchStart: 12, MatchEnd: 17},
+	}, matches)
+}
+
+func TestSnippet_TrimsContext(t *testing.T) {
+	text := strings.Repeat("a", 50) + "needle" + strings.Repeat("b", 50)
+
+	match, ok := snippet(regexp.MustCompile("needle"), text, "$.x", "input")
+	require.True(t, ok)
+	assert.Equal(t, "…"+strings.Repeat("a", snippetContext)+"needle"+strings.Repeat("b", snippetContext)+"…",
+		match.Snippet)
+	assert.Equal(t, snippetContext+1, match.MatchStart)
+	assert.Equal(t, snippetContext+7, match.MatchEnd)
+}
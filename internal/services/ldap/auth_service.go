@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"strings"
 
 	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/passworder"
 )
 
 // AuthService provides LDAP authentication functionality.
@@ -53,6 +55,8 @@ func NewAuthService(service *Service) *AuthService {
 }
 
 // Authenticate authenticates a user against the LDAP server.
+// Only external users (or users not known yet) are authenticated by LDAP bind; local accounts
+// are left to the local provider.
 func (s *AuthService) Authenticate(ctx context.Context, username, password string) (*domain.User, error) {
 	if s.service == nil {
 		return nil, errors.New("LDAP service is not configured")
@@ -61,6 +65,15 @@ func (s *AuthService) Authenticate(ctx context.Context, username, password strin
 	// Remove domain from username if present
 	username = strings.Split(username, "@")[0]
 
+	localUser, err := s.service.userRepo.GetByUsername(ctx, username)
+	switch {
+	case err == nil && (!localUser.IsExternal || localUser.IsServiceAccount):
+		// Local account, let the local provider check the password
+		return nil, domain.ErrInvalidPassword
+	case err != nil && !errors.Is(err, domain.ErrEntityNotFound):
+		return nil, fmt.Errorf("failed to get user from database: %w", err)
+	}
+
 	// Authenticate will reload config and check if enabled internally
 	authenticated, err := s.service.Authenticate(ctx, username, password)
 	if err != nil {
@@ -73,6 +86,12 @@ func (s *AuthService) Authenticate(ctx context.Context, username, password strin
 			// LDAP is not configured, let other providers try
 			return nil, domain.ErrInvalidPassword
 		}
+		if errors.Is(err, ErrLDAPUnavailable) && localUser.ID != 0 && s.fallbackEnabled() {
+			slog.WarnContext(ctx, "LDAP server is unavailable, checking cached local password",
+				"username", username, "error", err)
+
+			return authenticateLocally(&localUser, password)
+		}
 		return nil, fmt.Errorf("LDAP authentication failed: %w", err)
 	}
 
@@ -80,20 +99,65 @@ func (s *AuthService) Authenticate(ctx context.Context, username, password strin
 		return nil, domain.ErrInvalidPassword
 	}
 
-	// Get or create the user in the local database
-	// user, err := s.service.syncUser(ctx, username)
-	// if err != nil {
-	//	return nil, fmt.Errorf("failed to get user from LDAP: %w", err)
-	//}
-
 	user, err := s.service.userRepo.GetByUsername(ctx, username)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user from database: %w", err)
 	}
 
+	if s.fallbackEnabled() {
+		if err := s.cachePassword(ctx, &user, password); err != nil {
+			slog.ErrorContext(ctx, "failed to cache LDAP user password", "username", username, "error", err)
+		}
+	}
+
 	return &user, nil
 }
 
+func (s *AuthService) fallbackEnabled() bool {
+	s.service.mu.RLock()
+	defer s.service.mu.RUnlock()
+
+	return s.service.localPasswordFallback()
+}
+
+// cachePassword stores the password hash of an LDAP user for the local password fallback.
+func (s *AuthService) cachePassword(ctx context.Context, user *domain.User, password string) error {
+	if user.PasswordHash != "" {
+		if valid, err := passworder.ValidatePassword(password, user.PasswordHash); err == nil && valid {
+			return nil
+		}
+	}
+
+	hash, err := passworder.PasswordHash(password)
+	if err != nil {
+		return fmt.Errorf("hash password: %w", err)
+	}
+
+	return s.service.userRepo.UpdatePassword(ctx, user.ID, hash)
+}
+
+// authenticateLocally checks the password cached by a previous LDAP login.
+func authenticateLocally(user *domain.User, password string) (*domain.User, error) {
+	if user.PasswordHash == "" {
+		return nil, domain.ErrInvalidPassword
+	}
+
+	valid, err := passworder.ValidatePassword(password, user.PasswordHash)
+	if err != nil {
+		return nil, fmt.Errorf("validate password: %w", err)
+	}
+
+	if !valid {
+		return nil, domain.ErrInvalidPassword
+	}
+
+	if !user.IsActive {
+		return nil, domain.ErrInactiveUser
+	}
+
+	return user, nil
+}
+
 // CanHandle returns true if the username matches the LDAP username pattern.
 func (s *AuthService) CanHandle(username string) bool {
 	// If LDAP service is nil, we can't handle any authentication
@@ -0,0 +1,32 @@
+package ldap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/passworder"
+)
+
+func TestAuthenticateLocally(t *testing.T) {
+	user := &domain.User{ID: 1, Username: "jdoe", IsActive: true, IsExternal: true}
+
+	// no cached password
+	_, err := authenticateLocally(user, "secret")
+	require.ErrorIs(t, err, domain.ErrInvalidPassword)
+
+	user.PasswordHash = passworder.MustPasswordHash("secret")
+
+	got, err := authenticateLocally(user, "secret")
+	require.NoError(t, err)
+	assert.Equal(t, user, got)
+
+	_, err = authenticateLocally(user, "wrong")
+	require.ErrorIs(t, err, domain.ErrInvalidPassword)
+
+	user.IsActive = false
+	_, err = authenticateLocally(user, "secret")
+	require.ErrorIs(t, err, domain.ErrInactiveUser)
+}
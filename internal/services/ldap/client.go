@@ -134,6 +134,10 @@ func (c *Client) Authenticate(_ context.Context, username, password string) (boo
 
 		// Try to bind as the user to verify the password
 		err = conn.Bind(userDN, password)
+
+		// The connection goes back to the pool, so restore the service account bind
+		c.restoreBind(conn)
+
 		if err != nil {
 			if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
 				return nil // Invalid credentials, not an error
@@ -231,6 +235,55 @@ func (c *Client) GetAllUsers(_ context.Context) ([]string, error) {
 	return usernames, err
 }
 
+// GetChangedUsers retrieves usernames of users whose changeAttr is greater than or equal to since
+// (all users if since is empty) and the greatest changeAttr value among them.
+func (c *Client) GetChangedUsers(_ context.Context, changeAttr, since string) ([]string, string, error) {
+	var (
+		usernames []string
+		maxValue  string
+	)
+
+	filter := c.config.UserFilter
+	if since != "" {
+		filter = fmt.Sprintf("(&%s(%s>=%s))", c.config.UserFilter, changeAttr, ldap.EscapeFilter(since))
+	}
+
+	err := c.withConnection(func(conn *ldap.Conn) error {
+		searchRequest := ldap.NewSearchRequest(
+			c.config.UserBaseDN,
+			ldap.ScopeWholeSubtree,
+			ldap.NeverDerefAliases, 0, 0, false,
+			filter,
+			[]string{c.config.UserNameAttr, changeAttr},
+			nil,
+		)
+
+		searchResult, err := conn.Search(searchRequest)
+		if err != nil {
+			return fmt.Errorf("LDAP search failed: %w", err)
+		}
+
+		usernames = make([]string, 0, len(searchResult.Entries))
+
+		for _, entry := range searchResult.Entries {
+			username := entry.GetEqualFoldAttributeValue(c.config.UserNameAttr)
+			if username == "" {
+				continue
+			}
+
+			usernames = append(usernames, username)
+
+			if value := entry.GetEqualFoldAttributeValue(changeAttr); cursorAfter(value, maxValue) {
+				maxValue = value
+			}
+		}
+
+		return nil
+	})
+
+	return usernames, maxValue, err
+}
+
 // Close closes all connections in the pool and cleans up resources.
 func (c *Client) Close() error {
 	c.mu.Lock()
@@ -272,6 +325,23 @@ func (c *Client) Close() error {
 	return nil
 }
 
+// restoreBind rebinds the connection with the service account credentials after a user bind.
+// The connection is closed if that fails, so it is not reused with the user identity.
+func (c *Client) restoreBind(conn *ldap.Conn) {
+	var err error
+
+	if c.config.BindDN != "" && c.config.BindPassword != "" {
+		err = conn.Bind(c.config.BindDN, c.config.BindPassword)
+	} else {
+		err = conn.UnauthenticatedBind("")
+	}
+
+	if err != nil {
+		slog.Warn("failed to restore LDAP service bind, closing connection", "error", err)
+		_ = conn.Close()
+	}
+}
+
 // createConnection creates a new LDAP connection.
 func (c *Client) createConnection() (*ldap.Conn, error) {
 	// Create new connection
@@ -429,7 +499,7 @@ func (c *Client) withConnection(fn func(conn *ldap.Conn) error) error {
 	// Get a connection from the pool
 	conn, err := c.getConnection()
 	if err != nil {
-		err = fmt.Errorf("failed to get LDAP connection: %w", err)
+		err = fmt.Errorf("%w: failed to get LDAP connection: %w", ErrLDAPUnavailable, err)
 
 		return err
 	}
@@ -449,7 +519,7 @@ func (c *Client) withConnection(fn func(conn *ldap.Conn) error) error {
 			// Get a fresh connection
 			freshConn, freshErr := c.getConnection()
 			if freshErr != nil {
-				return fmt.Errorf("failed to get fresh LDAP connection after error: %w", freshErr)
+				return fmt.Errorf("%w: failed to get fresh LDAP connection after error: %w", ErrLDAPUnavailable, freshErr)
 			}
 			defer c.releaseConnection(freshConn)
 
@@ -15,6 +15,10 @@ type ClientService interface {
 	// GetAllUsers retrieves all usernames from LDAP
 	GetAllUsers(ctx context.Context) ([]string, error)
 
+	// GetChangedUsers retrieves usernames of users with changeAttr >= since (all users if since is empty)
+	// and the greatest changeAttr value among them
+	GetChangedUsers(ctx context.Context, changeAttr, since string) ([]string, string, error)
+
 	// TestConnection tests the connection to the LDAP server
 	TestConnection(ctx context.Context) error
 
@@ -0,0 +1,260 @@
+package ldap
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/repository/membershipaudit"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+const defaultGroupAttr = "memberOf"
+
+type membershipAction string
+
+const (
+	membershipActionAdd    membershipAction = "add"
+	membershipActionUpdate membershipAction = "update"
+	membershipActionRemove membershipAction = "remove"
+)
+
+// membershipChange is a change of a project membership planned by group mappings.
+type membershipChange struct {
+	Action    membershipAction
+	ProjectID domain.ProjectID
+	RoleKey   string
+	Group     string
+	// Membership is the current membership for update and remove actions.
+	Membership *domain.ProjectMembership
+}
+
+// planMembershipChanges compares memberships granted by the group mappings with the current
+// memberships of the user. Only memberships created by LDAP sync are updated or removed;
+// manually added memberships are never touched. If several mappings match the same project,
+// the first one wins. Group DNs are compared case-insensitively.
+func planMembershipChanges(
+	mappings []domain.LDAPGroupMapping,
+	groups []string,
+	current []domain.ProjectMembership,
+) []membershipChange {
+	groupSet := make(map[string]struct{}, len(groups))
+	for _, group := range groups {
+		groupSet[strings.ToLower(group)] = struct{}{}
+	}
+
+	desired := make(map[domain.ProjectID]domain.LDAPGroupMapping)
+	order := make([]domain.ProjectID, 0, len(mappings))
+
+	for _, mapping := range mappings {
+		if _, ok := groupSet[strings.ToLower(mapping.Group)]; !ok {
+			continue
+		}
+
+		if _, ok := desired[mapping.ProjectID]; ok {
+			continue
+		}
+
+		desired[mapping.ProjectID] = mapping
+		order = append(order, mapping.ProjectID)
+	}
+
+	currentByProject := make(map[domain.ProjectID]domain.ProjectMembership, len(current))
+	for _, membership := range current {
+		currentByProject[membership.ProjectID] = membership
+	}
+
+	var changes []membershipChange
+
+	for _, projectID := range order {
+		mapping := desired[projectID]
+
+		membership, ok := currentByProject[projectID]
+		switch {
+		case !ok:
+			changes = append(changes, membershipChange{
+				Action:    membershipActionAdd,
+				ProjectID: projectID,
+				RoleKey:   mapping.RoleKey,
+				Group:     mapping.Group,
+			})
+		case membership.Source == domain.MembershipSourceLDAP && membership.RoleKey != mapping.RoleKey:
+			changes = append(changes, membershipChange{
+				Action:     membershipActionUpdate,
+				ProjectID:  projectID,
+				RoleKey:    mapping.RoleKey,
+				Group:      mapping.Group,
+				Membership: &membership,
+			})
+		}
+	}
+
+	for _, membership := range current {
+		if membership.Source != domain.MembershipSourceLDAP {
+			continue
+		}
+
+		if _, ok := desired[membership.ProjectID]; ok {
+			continue
+		}
+
+		changes = append(changes, membershipChange{
+			Action:     membershipActionRemove,
+			ProjectID:  membership.ProjectID,
+			RoleKey:    membership.RoleKey,
+			Membership: &membership,
+		})
+	}
+
+	return changes
+}
+
+// syncGroupMemberships applies the group mappings to the memberships of the user.
+// Every planned change is written to the sync log when syncID is set.
+func (s *Service) syncGroupMemberships(
+	ctx context.Context,
+	syncID string,
+	config *domain.LDAPConfig,
+	user *domain.User,
+	userAttrs map[string][]string,
+) error {
+	if len(config.GroupMappings) == 0 {
+		return nil
+	}
+
+	groupAttr := config.GroupAttr
+	if groupAttr == "" {
+		groupAttr = defaultGroupAttr
+	}
+
+	current, err := s.membershipsRepo.ListForUser(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("list user memberships: %w", err)
+	}
+
+	changes := planMembershipChanges(config.GroupMappings, userAttrs[groupAttr], current)
+
+	var (
+		firstErr error
+		applied  bool
+	)
+
+	for _, change := range changes {
+		if config.GroupMappingsDryRun {
+			s.writeMembershipLog(ctx, syncID, user, change, nil, true)
+
+			continue
+		}
+
+		err := s.applyMembershipChange(ctx, user, change)
+		s.writeMembershipLog(ctx, syncID, user, change, err, false)
+
+		if err == nil {
+			applied = true
+		} else if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	if applied {
+		s.permissions.InvalidateCache()
+	}
+
+	return firstErr
+}
+
+func (s *Service) applyMembershipChange(ctx context.Context, user *domain.User, change membershipChange) error {
+	var roleID domain.RoleID
+
+	if change.Action != membershipActionRemove {
+		role, err := s.rolesRepo.GetByKey(ctx, change.RoleKey)
+		if err != nil {
+			return fmt.Errorf("get role %q: %w", change.RoleKey, err)
+		}
+
+		roleID = role.ID
+	}
+
+	return s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		var (
+			membership     domain.ProjectMembership
+			membershipID   domain.MembershipID
+			oldVal, newVal any
+			err            error
+		)
+
+		switch change.Action {
+		case membershipActionAdd:
+			membership, err = s.membershipsRepo.CreateWithSource(ctx,
+				change.ProjectID, user.ID, roleID, domain.MembershipSourceLDAP)
+			membershipID, newVal = membership.ID, membership
+		case membershipActionUpdate:
+			membership, err = s.membershipsRepo.Update(ctx, change.ProjectID, change.Membership.ID, roleID)
+			membershipID, oldVal, newVal = change.Membership.ID, *change.Membership, membership
+		case membershipActionRemove:
+			err = s.membershipsRepo.Delete(ctx, change.ProjectID, change.Membership.ID)
+			membershipID, oldVal = change.Membership.ID, *change.Membership
+		}
+		if err != nil {
+			return fmt.Errorf("%s membership: %w", change.Action, err)
+		}
+
+		// LDAP sync is a system action, so there is no actor user
+		err = membershipaudit.Write(ctx, db.TxFromContext(ctx), membershipID, 0, string(change.Action), oldVal, newVal)
+		if err != nil {
+			return fmt.Errorf("write membership audit: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (s *Service) writeMembershipLog(
+	ctx context.Context,
+	syncID string,
+	user *domain.User,
+	change membershipChange,
+	applyErr error,
+	dryRun bool,
+) {
+	level := "info"
+	message := fmt.Sprintf("Group mapping: %s membership", change.Action)
+
+	if dryRun {
+		message = "Dry run: " + message
+	}
+
+	details := fmt.Sprintf("project_id: %d, role: %s", change.ProjectID, change.RoleKey)
+	if change.Group != "" {
+		details += ", group: " + change.Group
+	}
+
+	if applyErr != nil {
+		level = "error"
+		details += ", error: " + applyErr.Error()
+
+		slog.ErrorContext(ctx, message, "username", user.Username, "details", details, "sync_id", syncID)
+	} else {
+		slog.InfoContext(ctx, message, "username", user.Username, "details", details, "sync_id", syncID)
+	}
+
+	if syncID == "" {
+		return
+	}
+
+	log := domain.LDAPSyncLog{
+		Timestamp:     time.Now(),
+		Level:         level,
+		Message:       message,
+		Username:      &user.Username,
+		Details:       &details,
+		SyncSessionID: syncID,
+	}
+
+	if _, err := s.ldapSyncLogsRepo.Create(ctx, log); err != nil {
+		slog.ErrorContext(ctx, "Failed to write LDAP sync log", "error", err, "syncID", syncID)
+	}
+}
@@ -0,0 +1,52 @@
+package ldap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+func TestPlanMembershipChanges(t *testing.T) {
+	mappings := []domain.LDAPGroupMapping{
+		{Group: "CN=wf-admins,OU=Groups,DC=example,DC=com", ProjectID: 1, RoleKey: "project_owner"},
+		{Group: "CN=wf-devs,OU=Groups,DC=example,DC=com", ProjectID: 1, RoleKey: "project_developer"},
+		{Group: "CN=wf-devs,OU=Groups,DC=example,DC=com", ProjectID: 2, RoleKey: "project_developer"},
+		{Group: "CN=wf-devs,OU=Groups,DC=example,DC=com", ProjectID: 3, RoleKey: "project_developer"},
+	}
+	groups := []string{"cn=wf-admins,ou=groups,dc=example,dc=com", "CN=wf-devs,OU=Groups,DC=example,DC=com"}
+
+	current := []domain.ProjectMembership{
+		// LDAP managed, role changed by mapping
+		{ID: 10, ProjectID: 1, RoleKey: "project_viewer", Source: domain.MembershipSourceLDAP},
+		// manual membership is never touched
+		{ID: 11, ProjectID: 3, RoleKey: "project_viewer", Source: domain.MembershipSourceManual},
+		// LDAP managed, group no longer matches
+		{ID: 12, ProjectID: 4, RoleKey: "project_viewer", Source: domain.MembershipSourceLDAP},
+		// manual membership without mapping
+		{ID: 13, ProjectID: 5, RoleKey: "project_viewer", Source: domain.MembershipSourceManual},
+	}
+
+	changes := planMembershipChanges(mappings, groups, current)
+	require.Len(t, changes, 3)
+
+	assert.Equal(t, membershipActionUpdate, changes[0].Action)
+	assert.Equal(t, domain.ProjectID(1), changes[0].ProjectID)
+	assert.Equal(t, "project_owner", changes[0].RoleKey)
+	assert.Equal(t, domain.MembershipID(10), changes[0].Membership.ID)
+
+	assert.Equal(t, membershipActionAdd, changes[1].Action)
+	assert.Equal(t, domain.ProjectID(2), changes[1].ProjectID)
+	assert.Equal(t, "project_developer", changes[1].RoleKey)
+
+	assert.Equal(t, membershipActionRemove, changes[2].Action)
+	assert.Equal(t, domain.MembershipID(12), changes[2].Membership.ID)
+
+	assert.Empty(t, planMembershipChanges(mappings, groups, []domain.ProjectMembership{
+		{ID: 10, ProjectID: 1, RoleKey: "project_owner", Source: domain.MembershipSourceLDAP},
+		{ID: 11, ProjectID: 2, RoleKey: "project_developer", Source: domain.MembershipSourceLDAP},
+		{ID: 12, ProjectID: 3, RoleKey: "project_developer", Source: domain.MembershipSourceLDAP},
+	}))
+}
@@ -0,0 +1,168 @@
+package ldap
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+const (
+	syncCursorSetting       = "ldap_sync_cursor"
+	defaultChangeAttr       = "modifyTimestamp"
+	defaultFullSyncInterval = 24 * time.Hour
+)
+
+// userSyncPlan is the list of users to synchronize and the cursor to store after a successful sync.
+type userSyncPlan struct {
+	users []string
+	full  bool
+	// cursor is nil when incremental sync is disabled.
+	cursor *domain.LDAPSyncCursor
+}
+
+func (p *userSyncPlan) mode() string {
+	if p.full {
+		return "full"
+	}
+
+	return "incremental"
+}
+
+// planUserSync selects the users to synchronize. With incremental sync enabled only users
+// changed since the stored cursor are returned; a full sync is done when there is no cursor,
+// the change attribute was reconfigured or the last full sync is older than FullSyncInterval.
+func (s *Service) planUserSync(ctx context.Context) (userSyncPlan, error) {
+	config, err := s.settingsService.GetLDAPConfig(ctx)
+	if err != nil {
+		return userSyncPlan{}, fmt.Errorf("failed to get LDAP config: %w", err)
+	}
+
+	if !config.IncrementalSync {
+		users, err := s.client.GetAllUsers(ctx)
+		if err != nil {
+			return userSyncPlan{}, err
+		}
+
+		return userSyncPlan{users: users, full: true}, nil
+	}
+
+	changeAttr := config.ChangeAttr
+	if changeAttr == "" {
+		changeAttr = defaultChangeAttr
+	}
+
+	fullSyncInterval := time.Duration(config.FullSyncInterval) * time.Second
+	if fullSyncInterval <= 0 {
+		fullSyncInterval = defaultFullSyncInterval
+	}
+
+	cursor, err := s.loadSyncCursor(ctx)
+	if err != nil {
+		return userSyncPlan{}, err
+	}
+
+	now := time.Now()
+	full := needsFullSync(cursor, changeAttr, fullSyncInterval, now)
+
+	since := cursor.Value
+	if full {
+		since = ""
+	}
+
+	users, maxValue, err := s.client.GetChangedUsers(ctx, changeAttr, since)
+	if err != nil {
+		return userSyncPlan{}, err
+	}
+
+	next := advanceCursor(cursor, changeAttr, maxValue, full, now)
+
+	return userSyncPlan{users: users, full: full, cursor: &next}, nil
+}
+
+func needsFullSync(cursor domain.LDAPSyncCursor, changeAttr string, interval time.Duration, now time.Time) bool {
+	return cursor.Value == "" || cursor.ChangeAttr != changeAttr || now.Sub(cursor.LastFullSyncAt) >= interval
+}
+
+// advanceCursor returns the cursor to store after a successful sync.
+func advanceCursor(
+	cursor domain.LDAPSyncCursor,
+	changeAttr, maxValue string,
+	full bool,
+	now time.Time,
+) domain.LDAPSyncCursor {
+	next := cursor
+	next.ChangeAttr = changeAttr
+
+	if full {
+		next.Value = maxValue
+		next.LastFullSyncAt = now
+	} else if cursorAfter(maxValue, next.Value) {
+		next.Value = maxValue
+	}
+
+	return next
+}
+
+// cursorAfter reports whether change attribute value a is greater than b. Numeric values
+// (uSNChanged) are compared by magnitude, generalized time values (modifyTimestamp) lexically.
+func cursorAfter(a, b string) bool {
+	if a == "" {
+		return false
+	}
+
+	if b == "" {
+		return true
+	}
+
+	if isDigits(a) && isDigits(b) && len(a) != len(b) {
+		return len(a) > len(b)
+	}
+
+	return a > b
+}
+
+func isDigits(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return value != ""
+}
+
+func (s *Service) loadSyncCursor(ctx context.Context) (domain.LDAPSyncCursor, error) {
+	setting, err := s.settingsService.GetSetting(ctx, syncCursorSetting)
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			return domain.LDAPSyncCursor{}, nil
+		}
+
+		return domain.LDAPSyncCursor{}, fmt.Errorf("get sync cursor: %w", err)
+	}
+
+	var cursor domain.LDAPSyncCursor
+	if err := json.Unmarshal(setting.Value, &cursor); err != nil {
+		return domain.LDAPSyncCursor{}, fmt.Errorf("unmarshal sync cursor: %w", err)
+	}
+
+	return cursor, nil
+}
+
+// saveSyncCursor stores the cursor of the plan; it does nothing for non incremental syncs.
+func (s *Service) saveSyncCursor(ctx context.Context, plan *userSyncPlan) error {
+	if plan.cursor == nil {
+		return nil
+	}
+
+	err := s.settingsService.SetSetting(ctx, syncCursorSetting, plan.cursor, "LDAP incremental sync cursor")
+	if err != nil {
+		return fmt.Errorf("save sync cursor: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package ldap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+func TestCursorAfter(t *testing.T) {
+	assert.True(t, cursorAfter("100", ""))
+	assert.False(t, cursorAfter("", "100"))
+	assert.True(t, cursorAfter("1000", "999"))
+	assert.False(t, cursorAfter("999", "1000"))
+	assert.True(t, cursorAfter("20240102000000Z", "20240101235959Z"))
+	assert.False(t, cursorAfter("20240101000000Z", "20240101000000Z"))
+}
+
+func TestNeedsFullSync(t *testing.T) {
+	now := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	cursor := domain.LDAPSyncCursor{
+		ChangeAttr:     "uSNChanged",
+		Value:          "1000",
+		LastFullSyncAt: now.Add(-time.Hour),
+	}
+
+	assert.False(t, needsFullSync(cursor, "uSNChanged", 24*time.Hour, now))
+	assert.True(t, needsFullSync(cursor, "modifyTimestamp", 24*time.Hour, now))
+	assert.True(t, needsFullSync(cursor, "uSNChanged", time.Hour, now))
+	assert.True(t, needsFullSync(domain.LDAPSyncCursor{}, "uSNChanged", 24*time.Hour, now))
+}
+
+func TestAdvanceCursor(t *testing.T) {
+	now := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	lastFull := now.Add(-time.Hour)
+	cursor := domain.LDAPSyncCursor{ChangeAttr: "uSNChanged", Value: "1000", LastFullSyncAt: lastFull}
+
+	next := advanceCursor(cursor, "uSNChanged", "1200", false, now)
+	assert.Equal(t, domain.LDAPSyncCursor{ChangeAttr: "uSNChanged", Value: "1200", LastFullSyncAt: lastFull}, next)
+
+	// no changed users keeps the cursor
+	next = advanceCursor(cursor, "uSNChanged", "", false, now)
+	assert.Equal(t, cursor, next)
+
+	next = advanceCursor(cursor, "uSNChanged", "900", true, now)
+	assert.Equal(t, domain.LDAPSyncCursor{ChangeAttr: "uSNChanged", Value: "900", LastFullSyncAt: now}, next)
+}
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"reflect"
 	"sync"
 	"time"
 
@@ -16,6 +17,7 @@ import (
 
 	"github.com/rom8726/floxy-manager/internal/contract"
 	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/db"
 )
 
 var _ di.Servicer = (*Service)(nil)
@@ -24,6 +26,7 @@ var (
 	ErrLDAPDisabled       = errors.New("ldap integration is disabled")
 	ErrLDAPNotConfigured  = errors.New("ldap is not properly configured")
 	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrLDAPUnavailable    = errors.New("ldap server is unavailable")
 )
 
 type ClientFactory func(config *ClientConfig) (ClientService, error)
@@ -56,6 +59,11 @@ type Service struct {
 	ldapSyncLogsRepo  contract.LDAPSyncLogsRepository
 	ldapSyncStatsRepo contract.LDAPSyncStatsRepository
 	settingsService   contract.SettingsUseCase
+	membershipsRepo   contract.MembershipsRepository
+	rolesRepo         contract.RolesRepository
+	permissions       contract.PermissionsService
+	features          contract.LicenseFeatures
+	tx                db.TxManager
 	mu                sync.RWMutex
 	syncInterval      time.Duration
 
@@ -74,12 +82,22 @@ func New(
 	ldapSyncLogsRepo contract.LDAPSyncLogsRepository,
 	settingsService contract.SettingsUseCase,
 	ldapSyncStatsRepo contract.LDAPSyncStatsRepository,
+	membershipsRepo contract.MembershipsRepository,
+	rolesRepo contract.RolesRepository,
+	permissions contract.PermissionsService,
+	features contract.LicenseFeatures,
+	tx db.TxManager,
 ) (*Service, error) {
 	service := &Service{
 		userRepo:          userRepo,
 		ldapSyncLogsRepo:  ldapSyncLogsRepo,
 		settingsService:   settingsService,
 		ldapSyncStatsRepo: ldapSyncStatsRepo,
+		membershipsRepo:   membershipsRepo,
+		rolesRepo:         rolesRepo,
+		permissions:       permissions,
+		features:          features,
+		tx:                tx,
 		clientFactory:     func(config *ClientConfig) (ClientService, error) { return NewClient(config) },
 	}
 
@@ -128,7 +146,7 @@ func (s *Service) Authenticate(ctx context.Context, username, password string) (
 	// Ensure the user exists locally
 	_, err = s.userRepo.GetByUsername(ctx, username)
 	if err != nil {
-		if err := s.syncUser(ctx, username); err != nil {
+		if err := s.syncUser(ctx, "", username); err != nil {
 			return false, fmt.Errorf("failed to sync user: %w", err)
 		}
 	}
@@ -192,7 +210,8 @@ func (s *Service) Stop(context.Context) error {
 }
 
 // syncUser synchronizes a single user from LDAP to the local database.
-func (s *Service) syncUser(ctx context.Context, username string) error {
+// Membership changes made by group mappings are written to the sync log of syncID, if set.
+func (s *Service) syncUser(ctx context.Context, syncID, username string) error {
 	// Get user details from LDAP
 	userAttrs, err := s.client.GetUser(ctx, username)
 	if err != nil {
@@ -226,6 +245,7 @@ func (s *Service) syncUser(ctx context.Context, username string) error {
 			PasswordHash:  "", // No password needed for LDAP users
 			IsTmpPassword: false,
 			IsExternal:    true, // Mark as external user from LDAP
+			EmailVerified: true, // Email is managed by the directory
 		}
 
 		user, err = s.userRepo.Create(ctx, userDTO)
@@ -245,11 +265,19 @@ func (s *Service) syncUser(ctx context.Context, username string) error {
 		}
 	}
 
+	if err := s.syncGroupMemberships(ctx, syncID, config, &user, userAttrs); err != nil {
+		return fmt.Errorf("failed to sync group memberships: %w", err)
+	}
+
 	return nil
 }
 
 // SyncUsers synchronizes all users from LDAP to the local database.
 func (s *Service) SyncUsers(ctx context.Context) error {
+	return s.syncUsers(ctx, "")
+}
+
+func (s *Service) syncUsers(ctx context.Context, syncID string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -257,20 +285,31 @@ func (s *Service) SyncUsers(ctx context.Context) error {
 		return ErrLDAPNotConfigured
 	}
 
-	// Get all users from LDAP
-	users, err := s.client.GetAllUsers(ctx)
+	// Get users to sync from LDAP
+	plan, err := s.planUserSync(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get users from LDAP: %w", err)
 	}
 
+	slog.Info("Syncing users from LDAP", "mode", plan.mode(), "users", len(plan.users))
+
+	failed := 0
+
 	// Sync each user
-	for _, username := range users {
-		if err := s.syncUser(ctx, username); err != nil {
+	for _, username := range plan.users {
+		if err := s.syncUser(ctx, syncID, username); err != nil {
 			// Log error but continue with other users
 			slog.Error("failed to sync user", "username", username, "error", err)
+
+			failed++
 		}
 	}
 
+	// Failed users are retried by the next sync, so the cursor only advances without errors
+	if failed == 0 {
+		return s.saveSyncCursor(ctx, &plan)
+	}
+
 	return nil
 }
 
@@ -339,7 +378,7 @@ func (s *Service) backgroundSync(ctx context.Context) {
 
 			var firstErr error
 
-			if err := s.SyncUsers(ctx); err != nil {
+			if err := s.syncUsers(ctx, syncID); err != nil {
 				// Log error but continue
 				slog.Error("Error syncing users from LDAP", "error", err)
 
@@ -419,7 +458,7 @@ func (s *Service) reloadConfig(ctx context.Context, reason string) error {
 
 	s.syncInterval = time.Second * time.Duration(config.SyncInterval)
 
-	if !firstStart && *s.currentConfig == *config {
+	if !firstStart && reflect.DeepEqual(s.currentConfig, config) {
 		return nil
 	}
 
@@ -461,7 +500,13 @@ func (s *Service) reloadConfig(ctx context.Context, reason string) error {
 	return nil
 }
 
+// localPasswordFallback reports whether external users may log in with a cached local password
+// while the directory server is unavailable.
+func (s *Service) localPasswordFallback() bool {
+	return s.currentConfig != nil && s.currentConfig.LocalPasswordFallback
+}
+
 // isEnabled checks if LDAP is enabled both in configuration and by license.
 func (s *Service) isEnabled() bool {
-	return s.enabled
+	return s.enabled && s.features.IsFeatureAvailable(domain.FeatureLDAP)
 }
@@ -28,7 +28,7 @@ func (s *Service) StartManualSync(_ context.Context, syncID string, stopped chan
 	}
 
 	// Check if LDAP is enabled and configured
-	if !s.enabled || s.client == nil {
+	if !s.isEnabled() || s.client == nil {
 		return ErrLDAPNotConfigured
 	}
 
@@ -71,20 +71,20 @@ func (s *Service) StartManualSync(_ context.Context, syncID string, stopped chan
 
 		var totalUsers, syncedUsers, errs, warnings int
 
+		mode := "full"
+
 		defer func() {
 			duration := time.Since(startTime).Truncate(time.Second)
 
 			// Log the sync result
 			level := "info"
 			message := fmt.Sprintf("LDAP sync completed successfully. Duration: %s", duration)
-			details := fmt.Sprintf("Total users: %d, Synced users: %d, Errors: %d, Warnings: %d",
-				totalUsers, syncedUsers, errs, warnings)
+			details := fmt.Sprintf("Mode: %s, Total users: %d, Synced users: %d, Errors: %d, Warnings: %d",
+				mode, totalUsers, syncedUsers, errs, warnings)
 
 			if syncErr != nil {
 				level = "error"
 				message = fmt.Sprintf("LDAP sync failed. Duration: %s, Error: %v", duration, syncErr)
-				details = fmt.Sprintf("Total users: %d, Synced users: %d, Errors: %d, Warnings: %d",
-					totalUsers, syncedUsers, errs, warnings)
 			}
 
 			// Create log entry
@@ -160,19 +160,21 @@ func (s *Service) StartManualSync(_ context.Context, syncID string, stopped chan
 			}
 		}()
 
-		// Get all users from LDAP first to set the total count
-		users, err := s.client.GetAllUsers(syncCtx)
+		// Get users to sync from LDAP first to set the total count
+		plan, err := s.planUserSync(syncCtx)
 		if err != nil {
 			syncErr = fmt.Errorf("failed to get users from LDAP: %w", err)
 
 			return
 		}
 
+		users := plan.users
 		totalUsers = len(users)
+		mode = plan.mode()
 
 		s.syncMutex.Lock()
 		s.syncProgress.TotalItems = totalUsers
-		s.syncProgress.CurrentStep = "Syncing users"
+		s.syncProgress.CurrentStep = "Syncing users (" + mode + ")"
 		s.syncMutex.Unlock()
 
 		// Update initial stats
@@ -188,7 +190,7 @@ func (s *Service) StartManualSync(_ context.Context, syncID string, stopped chan
 			default:
 				time.Sleep(time.Millisecond * 100)
 
-				if err := s.syncUser(syncCtx, username); err != nil {
+				if err := s.syncUser(syncCtx, syncID, username); err != nil {
 					// Log error but continue with other users
 					slog.Error("failed to sync user", "username", username, "error", err)
 
@@ -212,6 +214,13 @@ func (s *Service) StartManualSync(_ context.Context, syncID string, stopped chan
 				}
 			}
 		}
+
+		// Failed users are retried by the next sync, so the cursor only advances without errors
+		if errs == 0 {
+			if err := s.saveSyncCursor(syncCtx, &plan); err != nil {
+				slog.Error("failed to save LDAP sync cursor", "error", err, "syncID", syncID)
+			}
+		}
 	}()
 
 	return nil
@@ -0,0 +1,306 @@
+// Package license verifies the signed license of the installation and tells which enterprise
+// features (SSO, LDAP, audit export) it enables.
+//
+// A license is "<payload>.<signature>": the base64url encoded JSON payload and the base64url encoded
+// Ed25519 signature of the encoded payload. Licensing is enforced only when the public key verifying
+// the signatures is configured.
+package license
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/repository/auditlog"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.LicenseService = (*Service)(nil)
+
+type Params struct {
+	// PublicKey verifies the signatures of licenses, licensing is not enforced without it.
+	PublicKey ed25519.PublicKey
+}
+
+type Service struct {
+	publicKey       ed25519.PublicKey
+	licensesRepo    contract.LicensesRepository
+	productInfoRepo contract.ProductInfoRepository
+	txManager       db.TxManager
+
+	current atomic.Pointer[domain.License]
+	now     func() time.Time
+}
+
+// payload is the signed part of a license.
+type payload struct {
+	ID        string             `json:"id"`
+	ClientID  string             `json:"client_id"`
+	Type      domain.LicenseType `json:"type"`
+	IssuedAt  time.Time          `json:"issued_at"`
+	ExpiresAt time.Time          `json:"expires_at"`
+}
+
+// New creates the service with the last installed license. A stored license that does not verify
+// is ignored, the features stay unavailable until a valid one is installed.
+func New(
+	params *Params,
+	licensesRepo contract.LicensesRepository,
+	productInfoRepo contract.ProductInfoRepository,
+	txManager db.TxManager,
+) (*Service, error) {
+	service := &Service{
+		publicKey:       params.PublicKey,
+		licensesRepo:    licensesRepo,
+		productInfoRepo: productInfoRepo,
+		txManager:       txManager,
+		now:             time.Now,
+	}
+
+	if !service.enforced() {
+		return service, nil
+	}
+
+	ctx := context.Background()
+
+	stored, err := licensesRepo.GetLastByExpiresAt(ctx)
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			return service, nil
+		}
+
+		return nil, fmt.Errorf("get license: %w", err)
+	}
+
+	license, err := service.verify(ctx, stored.LicenseText)
+	if err != nil {
+		slog.Warn("Stored license is not valid, enterprise features are disabled", "id", stored.ID, "error", err)
+
+		return service, nil
+	}
+
+	service.current.Store(&license)
+
+	return service, nil
+}
+
+// IsFeatureAvailable reports whether the feature is enabled by an unexpired license.
+// All features are available when licensing is not enforced.
+func (s *Service) IsFeatureAvailable(feature domain.LicenseFeature) bool {
+	if !s.enforced() {
+		return true
+	}
+
+	license := s.current.Load()
+	if license == nil || !s.now().Before(license.ExpiresAt) {
+		return false
+	}
+
+	return domain.IsFeatureAvailable(license.Type, feature)
+}
+
+// Status returns the installed license and the features available now.
+func (s *Service) Status(context.Context) (domain.LicenseStatus, error) {
+	status := domain.LicenseStatus{
+		Enforced: s.enforced(),
+		Features: []domain.LicenseFeature{},
+	}
+
+	for _, feature := range []domain.LicenseFeature{
+		domain.FeatureSSO, domain.FeatureLDAP, domain.FeatureCorpNotifChannels, domain.FeatureAuditExport,
+	} {
+		if s.IsFeatureAvailable(feature) {
+			status.Features = append(status.Features, feature)
+		}
+	}
+
+	license := s.current.Load()
+	if license == nil {
+		return status, nil
+	}
+
+	now := s.now()
+
+	status.ID = license.ID
+	status.Type = license.Type
+	status.IssuedAt = license.IssuedAt
+	status.ExpiresAt = license.ExpiresAt
+	status.LicenseText = license.LicenseText
+	status.IsExpired = !now.Before(license.ExpiresAt)
+	status.IsValid = !status.IsExpired
+
+	if !status.IsExpired {
+		status.DaysUntilExpiry = int(license.ExpiresAt.Sub(now).Hours() / 24)
+	}
+
+	return status, nil
+}
+
+// Install verifies and stores the license, it replaces the current one at once.
+func (s *Service) Install(ctx context.Context, licenseText string) (domain.LicenseStatus, error) {
+	license, err := s.unexpired(ctx, licenseText)
+	if err != nil {
+		return domain.LicenseStatus{}, err
+	}
+
+	return s.install(ctx, license)
+}
+
+// InstallIfNewer installs the license like Install unless the current one expires later, or at the same
+// time and was not issued earlier. It reports whether the license was installed.
+func (s *Service) InstallIfNewer(ctx context.Context, licenseText string) (domain.LicenseStatus, bool, error) {
+	license, err := s.unexpired(ctx, licenseText)
+	if err != nil {
+		return domain.LicenseStatus{}, false, err
+	}
+
+	if current := s.current.Load(); current != nil && !isNewer(&license, current) {
+		status, err := s.Status(ctx)
+
+		return status, false, err
+	}
+
+	status, err := s.install(ctx, license)
+	if err != nil {
+		return domain.LicenseStatus{}, false, err
+	}
+
+	return status, true, nil
+}
+
+// unexpired verifies the license and checks it has not expired.
+func (s *Service) unexpired(ctx context.Context, licenseText string) (domain.License, error) {
+	if !s.enforced() {
+		return domain.License{}, fmt.Errorf("%w: no license public key is configured", domain.ErrInvalidLicense)
+	}
+
+	license, err := s.verify(ctx, strings.TrimSpace(licenseText))
+	if err != nil {
+		return domain.License{}, err
+	}
+
+	if !s.now().Before(license.ExpiresAt) {
+		return domain.License{}, fmt.Errorf("%w: license expired at %s", domain.ErrInvalidLicense,
+			license.ExpiresAt.Format(time.RFC3339))
+	}
+
+	return license, nil
+}
+
+func (s *Service) install(ctx context.Context, license domain.License) (domain.LicenseStatus, error) {
+	err := s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+		var err error
+
+		license, err = s.licensesRepo.UpdateLicense(ctx, license)
+		if err != nil {
+			return fmt.Errorf("save license: %w", err)
+		}
+
+		// Licenses installed at startup from LICENSE_FILE have no acting user
+		if appcontext.Username(ctx) == "" {
+			return nil
+		}
+
+		err = auditlog.WriteChangeLog(ctx, db.TxFromContext(ctx), domain.EntityLicense, license.ID,
+			domain.ActionUpdate, 0, map[string]auditlog.Change{
+				"type":       {New: license.Type},
+				"expires_at": {New: license.ExpiresAt},
+			})
+		if err != nil {
+			return fmt.Errorf("write audit log: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return domain.LicenseStatus{}, err
+	}
+
+	s.current.Store(&license)
+
+	return s.Status(ctx)
+}
+
+// isNewer reports whether the license expires after the other one, or at the same time and was issued later.
+func isNewer(license, other *domain.License) bool {
+	if !license.ExpiresAt.Equal(other.ExpiresAt) {
+		return license.ExpiresAt.After(other.ExpiresAt)
+	}
+
+	return license.IssuedAt.After(other.IssuedAt)
+}
+
+func (s *Service) enforced() bool {
+	return len(s.publicKey) > 0
+}
+
+// verify checks the signature of the license and returns its content. The license must be issued
+// for the client ID of the installation when it has one.
+func (s *Service) verify(ctx context.Context, licenseText string) (domain.License, error) {
+	encodedPayload, encodedSignature, ok := strings.Cut(licenseText, ".")
+	if !ok {
+		return domain.License{}, fmt.Errorf("%w: malformed license", domain.ErrInvalidLicense)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !ed25519.Verify(s.publicKey, []byte(encodedPayload), signature) {
+		return domain.License{}, fmt.Errorf("%w: bad signature", domain.ErrInvalidLicense)
+	}
+
+	rawPayload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return domain.License{}, fmt.Errorf("%w: malformed payload", domain.ErrInvalidLicense)
+	}
+
+	var content payload
+	if err := json.Unmarshal(rawPayload, &content); err != nil {
+		return domain.License{}, fmt.Errorf("%w: malformed payload", domain.ErrInvalidLicense)
+	}
+
+	if err := uuid.Validate(content.ID); err != nil {
+		return domain.License{}, fmt.Errorf("%w: id must be a UUID", domain.ErrInvalidLicense)
+	}
+
+	if err := uuid.Validate(content.ClientID); err != nil {
+		return domain.License{}, fmt.Errorf("%w: client_id must be a UUID", domain.ErrInvalidLicense)
+	}
+
+	if len(domain.GetAvailableFeatures(content.Type)) == 0 {
+		return domain.License{}, fmt.Errorf("%w: unknown type %q", domain.ErrInvalidLicense, content.Type)
+	}
+
+	if content.ExpiresAt.Before(content.IssuedAt) {
+		return domain.License{}, fmt.Errorf("%w: expires before it is issued", domain.ErrInvalidLicense)
+	}
+
+	clientID, err := s.productInfoRepo.GetClientID(ctx)
+	switch {
+	case errors.Is(err, domain.ErrEntityNotFound):
+	case err != nil:
+		return domain.License{}, fmt.Errorf("get client ID: %w", err)
+	case !strings.EqualFold(clientID, content.ClientID):
+		return domain.License{}, fmt.Errorf("%w: issued for another installation", domain.ErrInvalidLicense)
+	}
+
+	return domain.License{
+		ID:          content.ID,
+		ClientID:    content.ClientID,
+		Type:        content.Type,
+		IssuedAt:    content.IssuedAt,
+		ExpiresAt:   content.ExpiresAt,
+		LicenseText: licenseText,
+		CreatedAt:   s.now(),
+	}, nil
+}
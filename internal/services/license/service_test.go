@@ -0,0 +1,198 @@
+package license
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+const testClientID = "5f0c7a9e-3d5b-4c51-9a8e-0b6f1f2d3c4e"
+
+type fakeTx struct{}
+
+func (fakeTx) ReadCommitted(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (fakeTx) RepeatableRead(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type fakeLicensesRepo struct {
+	saved *domain.License
+}
+
+func (r *fakeLicensesRepo) GetLastByExpiresAt(context.Context) (domain.License, error) {
+	if r.saved == nil {
+		return domain.License{}, domain.ErrEntityNotFound
+	}
+
+	return *r.saved, nil
+}
+
+func (r *fakeLicensesRepo) UpdateLicense(_ context.Context, license domain.License) (domain.License, error) {
+	r.saved = &license
+
+	return license, nil
+}
+
+type fakeProductInfoRepo struct{}
+
+func (fakeProductInfoRepo) GetClientID(context.Context) (string, error) {
+	return testClientID, nil
+}
+
+func sign(t *testing.T, key ed25519.PrivateKey, content payload) string {
+	t.Helper()
+
+	raw, err := json.Marshal(content)
+	require.NoError(t, err)
+
+	encoded := base64.RawURLEncoding.EncodeToString(raw)
+
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(ed25519.Sign(key, []byte(encoded)))
+}
+
+func TestService_Install(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	repo := &fakeLicensesRepo{}
+	service, err := New(&Params{PublicKey: publicKey}, repo, fakeProductInfoRepo{}, fakeTx{})
+	require.NoError(t, err)
+	assert.False(t, service.IsFeatureAvailable(domain.FeatureSSO))
+
+	now := time.Now()
+	content := payload{
+		ID:        "0b1c2d3e-4f50-4617-8293-a4b5c6d7e8f9",
+		ClientID:  testClientID,
+		Type:      domain.Commercial,
+		IssuedAt:  now.Add(-time.Hour),
+		ExpiresAt: now.Add(72 * time.Hour),
+	}
+
+	status, err := service.Install(context.Background(), sign(t, privateKey, content))
+	require.NoError(t, err)
+	assert.True(t, status.IsValid)
+	assert.Equal(t, 2, status.DaysUntilExpiry)
+	assert.Contains(t, status.Features, domain.FeatureAuditExport)
+	assert.True(t, service.IsFeatureAvailable(domain.FeatureLDAP))
+	require.NotNil(t, repo.saved)
+
+	// The stored license is loaded on start
+	restarted, err := New(&Params{PublicKey: publicKey}, repo, fakeProductInfoRepo{}, fakeTx{})
+	require.NoError(t, err)
+	assert.True(t, restarted.IsFeatureAvailable(domain.FeatureSSO))
+
+	restarted.now = func() time.Time { return now.Add(73 * time.Hour) }
+	assert.False(t, restarted.IsFeatureAvailable(domain.FeatureSSO))
+}
+
+func TestService_Install_Invalid(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	service, err := New(&Params{PublicKey: publicKey}, &fakeLicensesRepo{}, fakeProductInfoRepo{}, fakeTx{})
+	require.NoError(t, err)
+
+	now := time.Now()
+	valid := payload{
+		ID:        "0b1c2d3e-4f50-4617-8293-a4b5c6d7e8f9",
+		ClientID:  testClientID,
+		Type:      domain.Commercial,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(time.Hour),
+	}
+
+	otherClient := valid
+	otherClient.ClientID = "7d8e9f00-1a2b-4c3d-8e4f-5a6b7c8d9e0f"
+
+	expired := valid
+	expired.IssuedAt, expired.ExpiresAt = now.Add(-2*time.Hour), now.Add(-time.Hour)
+
+	for name, licenseText := range map[string]string{
+		"malformed":     "not-a-license",
+		"other key":     sign(t, otherKey, valid),
+		"other client":  sign(t, privateKey, otherClient),
+		"expired":       sign(t, privateKey, expired),
+		"tampered data": "e30" + sign(t, privateKey, valid)[3:],
+	} {
+		_, err := service.Install(context.Background(), licenseText)
+		assert.ErrorIs(t, err, domain.ErrInvalidLicense, name)
+	}
+
+	assert.False(t, service.IsFeatureAvailable(domain.FeatureSSO))
+}
+
+func TestService_NotEnforced(t *testing.T) {
+	service, err := New(&Params{}, &fakeLicensesRepo{}, fakeProductInfoRepo{}, fakeTx{})
+	require.NoError(t, err)
+
+	assert.True(t, service.IsFeatureAvailable(domain.FeatureSSO))
+
+	status, err := service.Status(context.Background())
+	require.NoError(t, err)
+	assert.False(t, status.Enforced)
+	assert.Len(t, status.Features, 4)
+}
+
+func TestService_InstallIfNewer(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	repo := &fakeLicensesRepo{}
+	service, err := New(&Params{PublicKey: publicKey}, repo, fakeProductInfoRepo{}, fakeTx{})
+	require.NoError(t, err)
+
+	now := time.Now()
+	older := payload{
+		ID:        "0b1c2d3e-4f50-4617-8293-a4b5c6d7e8f9",
+		ClientID:  testClientID,
+		Type:      domain.Trial,
+		IssuedAt:  now.Add(-time.Hour),
+		ExpiresAt: now.Add(24 * time.Hour),
+	}
+
+	newer := older
+	newer.ID = "1c2d3e4f-5061-4728-93a4-b5c6d7e8f901"
+	newer.Type = domain.Commercial
+	newer.ExpiresAt = now.Add(72 * time.Hour)
+
+	status, installed, err := service.InstallIfNewer(context.Background(), sign(t, privateKey, older))
+	require.NoError(t, err)
+	assert.True(t, installed)
+	assert.Equal(t, older.ID, status.ID)
+
+	_, installed, err = service.InstallIfNewer(context.Background(), sign(t, privateKey, newer))
+	require.NoError(t, err)
+	assert.True(t, installed)
+
+	// The older license and the installed one again are not installed
+	for _, content := range []payload{older, newer} {
+		status, installed, err = service.InstallIfNewer(context.Background(), sign(t, privateKey, content))
+		require.NoError(t, err)
+		assert.False(t, installed)
+		assert.Equal(t, newer.ID, status.ID)
+	}
+
+	assert.Equal(t, newer.ID, repo.saved.ID)
+
+	expired := newer
+	expired.IssuedAt, expired.ExpiresAt = now.Add(-2*time.Hour), now.Add(-time.Hour)
+
+	_, installed, err = service.InstallIfNewer(context.Background(), sign(t, privateKey, expired))
+	require.ErrorIs(t, err, domain.ErrInvalidLicense)
+	assert.False(t, installed)
+}
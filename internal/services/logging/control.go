@@ -0,0 +1,129 @@
+// Package logging holds the level and the format of the process logs, so they can be switched
+// while running, e.g. to see the debug logs of the permission checks without a restart.
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync/atomic"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+var _ contract.LogControl = (*Control)(nil)
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+var ErrInvalidSettings = errors.New("invalid log settings")
+
+var levels = map[string]slog.Level{
+	"debug": slog.LevelDebug,
+	"info":  slog.LevelInfo,
+	"warn":  slog.LevelWarn,
+	"error": slog.LevelError,
+}
+
+// Control switches the level and the format of the loggers using its Handler.
+type Control struct {
+	level slog.LevelVar
+	json  atomic.Bool
+	base  handler
+}
+
+func New(w io.Writer, level, format string) (*Control, error) {
+	c := &Control{}
+	c.base = handler{
+		control: c,
+		text:    slog.NewTextHandler(w, &slog.HandlerOptions{Level: &c.level}),
+		json:    slog.NewJSONHandler(w, &slog.HandlerOptions{Level: &c.level}),
+	}
+
+	if err := c.Update(domain.LogSettings{Level: level, Format: format}); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Handler returns the handler writing in the current format at the current level.
+func (c *Control) Handler() slog.Handler {
+	return &c.base
+}
+
+func (c *Control) Settings() domain.LogSettings {
+	settings := domain.LogSettings{Format: FormatText}
+	if c.json.Load() {
+		settings.Format = FormatJSON
+	}
+
+	for name, level := range levels {
+		if level == c.level.Level() {
+			settings.Level = name
+		}
+	}
+
+	return settings
+}
+
+// Update sets the level and the format, an empty one is left as is.
+func (c *Control) Update(settings domain.LogSettings) error {
+	level, ok := levels[settings.Level]
+	if settings.Level != "" && !ok {
+		return fmt.Errorf("%w: level must be debug, info, warn or error, got %q", ErrInvalidSettings, settings.Level)
+	}
+
+	switch settings.Format {
+	case "", FormatText, FormatJSON:
+	default:
+		return fmt.Errorf("%w: format must be text or json, got %q", ErrInvalidSettings, settings.Format)
+	}
+
+	if settings.Level != "" {
+		c.level.Set(level)
+	}
+
+	if settings.Format != "" {
+		c.json.Store(settings.Format == FormatJSON)
+	}
+
+	return nil
+}
+
+// handler keeps the derived handlers of both formats, so loggers created with With follow the switch.
+type handler struct {
+	control *Control
+	text    slog.Handler
+	json    slog.Handler
+}
+
+func (h *handler) current() slog.Handler {
+	if h.control.json.Load() {
+		return h.json
+	}
+
+	return h.text
+}
+
+func (h *handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.current().Enabled(ctx, level)
+}
+
+//nolint:gocritic // slog.Handler signature
+func (h *handler) Handle(ctx context.Context, record slog.Record) error {
+	return h.current().Handle(ctx, record)
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &handler{control: h.control, text: h.text.WithAttrs(attrs), json: h.json.WithAttrs(attrs)}
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	return &handler{control: h.control, text: h.text.WithGroup(name), json: h.json.WithGroup(name)}
+}
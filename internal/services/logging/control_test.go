@@ -0,0 +1,40 @@
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+func TestControl(t *testing.T) {
+	var buf bytes.Buffer
+
+	control, err := New(&buf, "info", FormatText)
+	require.NoError(t, err)
+
+	logger := slog.New(control.Handler()).With("component", "permissions")
+
+	logger.Debug("hidden")
+	assert.Empty(t, buf.String())
+
+	require.NoError(t, control.Update(domain.LogSettings{Level: "debug", Format: FormatJSON}))
+	assert.Equal(t, domain.LogSettings{Level: "debug", Format: FormatJSON}, control.Settings())
+
+	logger.Debug("visible")
+	assert.Contains(t, buf.String(), `"msg":"visible","component":"permissions"`)
+
+	// An empty field is left as is
+	require.NoError(t, control.Update(domain.LogSettings{Format: FormatText}))
+	assert.Equal(t, domain.LogSettings{Level: "debug", Format: FormatText}, control.Settings())
+
+	assert.ErrorIs(t, control.Update(domain.LogSettings{Level: "trace"}), ErrInvalidSettings)
+	assert.ErrorIs(t, control.Update(domain.LogSettings{Format: "xml"}), ErrInvalidSettings)
+
+	_, err = New(&buf, "info", "xml")
+	assert.Error(t, err)
+}
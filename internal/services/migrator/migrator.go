@@ -0,0 +1,217 @@
+// Package migrator applies the database schema migrations on startup and reports their status
+// to the admin API. Concurrent runs on several replicas are serialized by the migrate lock.
+package migrator
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net/url"
+	"strconv"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/jackc/pgx/v5/pgxpool"
+	_ "github.com/lib/pq"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/repository/auditlog"
+	"github.com/rom8726/floxy-manager/internal/repository/tenantschemas"
+)
+
+var _ contract.Migrator = (*Migrator)(nil)
+
+var ErrDirty = errors.New("database is dirty, fix the failed migration and force its version first")
+
+const dbSchema = "workflows_manager"
+
+type Config struct {
+	ConnString    string
+	MigrationsDir string
+}
+
+type Migrator struct {
+	// pool writes the audit log entries and schemas follows the applied migrations, both are nil
+	// for the runs before the app is created, the tenant schemas are provisioned on its start then.
+	pool    *pgxpool.Pool
+	schemas *tenantschemas.Schemas
+	cfg     Config
+}
+
+func New(pool *pgxpool.Pool, schemas *tenantschemas.Schemas, cfg *Config) *Migrator {
+	return &Migrator{
+		pool:    pool,
+		schemas: schemas,
+		cfg:     *cfg,
+	}
+}
+
+func (m *Migrator) Status(context.Context) (domain.MigrationStatus, error) {
+	var status domain.MigrationStatus
+
+	err := m.withMigrate(func(pgMigrate *migrate.Migrate) error {
+		var err error
+		status, err = m.status(pgMigrate)
+
+		return err
+	})
+
+	return status, err
+}
+
+func (m *Migrator) Up(ctx context.Context) (domain.MigrationStatus, error) {
+	var before, status domain.MigrationStatus
+
+	err := m.withMigrate(func(pgMigrate *migrate.Migrate) error {
+		var err error
+		before, err = m.status(pgMigrate)
+		if err != nil {
+			return err
+		}
+
+		if before.Dirty {
+			return ErrDirty
+		}
+
+		if err := pgMigrate.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+			return fmt.Errorf("up: %w", err)
+		}
+
+		status, err = m.status(pgMigrate)
+
+		return err
+	})
+	if err != nil {
+		return domain.MigrationStatus{}, err
+	}
+
+	if status.Version == before.Version {
+		return status, nil
+	}
+
+	slog.InfoContext(ctx, "Migrations applied", "from", before.Version, "to", status.Version)
+
+	if m.schemas != nil {
+		if err := m.schemas.ProvisionAll(ctx); err != nil {
+			return domain.MigrationStatus{}, fmt.Errorf("provision tenant schemas: %w", err)
+		}
+	}
+
+	if m.pool != nil {
+		err := auditlog.WriteLog(ctx, m.pool, domain.EntityMigration,
+			strconv.FormatUint(uint64(status.Version), 10), domain.ActionApply, 0)
+		if err != nil {
+			return domain.MigrationStatus{}, fmt.Errorf("write audit log: %w", err)
+		}
+	}
+
+	return status, nil
+}
+
+func (m *Migrator) status(pgMigrate *migrate.Migrate) (domain.MigrationStatus, error) {
+	version, dirty, err := pgMigrate.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return domain.MigrationStatus{}, fmt.Errorf("get version: %w", err)
+	}
+
+	pending, err := pendingMigrations(m.sourceURL(), version)
+	if err != nil {
+		return domain.MigrationStatus{}, err
+	}
+
+	return domain.MigrationStatus{
+		Version: version,
+		Dirty:   dirty,
+		Pending: pending,
+	}, nil
+}
+
+func (m *Migrator) withMigrate(fn func(pgMigrate *migrate.Migrate) error) error {
+	connStringURL, err := url.Parse(m.cfg.ConnString)
+	if err != nil {
+		return fmt.Errorf("parsing connection string: %w", err)
+	}
+
+	values := connStringURL.Query()
+	values.Set("search_path", dbSchema) // set db schema
+	connStringURL.RawQuery = values.Encode()
+
+	db, err := sql.Open("postgres", connStringURL.String())
+	if err != nil {
+		return fmt.Errorf("open postgres connection: %w", err)
+	}
+
+	defer func() { _ = db.Close() }()
+
+	_, err = db.Exec("CREATE SCHEMA IF NOT EXISTS " + dbSchema)
+	if err != nil {
+		return err
+	}
+
+	driver, err := postgres.WithInstance(db, &postgres.Config{})
+	if err != nil {
+		return fmt.Errorf("create postgres driver: %w", err)
+	}
+
+	pgMigrate, err := migrate.NewWithDatabaseInstance(m.sourceURL(), "postgres", driver)
+	if err != nil {
+		return fmt.Errorf("create migrations: %w", err)
+	}
+
+	defer func() { _, _ = pgMigrate.Close() }()
+
+	return fn(pgMigrate)
+}
+
+func (m *Migrator) sourceURL() string {
+	return "file://" + m.cfg.MigrationsDir
+}
+
+// pendingMigrations lists the migrations of the source newer than version.
+func pendingMigrations(sourceURL string, version uint) ([]domain.Migration, error) {
+	src, err := source.Open(sourceURL)
+	if err != nil {
+		return nil, fmt.Errorf("open migrations source: %w", err)
+	}
+
+	defer func() { _ = src.Close() }()
+
+	pending := make([]domain.Migration, 0)
+
+	next, err := src.First()
+	for err == nil {
+		if next > version {
+			migration, err := readMigration(src, next)
+			if err != nil {
+				return nil, err
+			}
+
+			pending = append(pending, migration)
+		}
+
+		next, err = src.Next(next)
+	}
+
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("read migrations source: %w", err)
+	}
+
+	return pending, nil
+}
+
+func readMigration(src source.Driver, version uint) (domain.Migration, error) {
+	body, name, err := src.ReadUp(version)
+	if err != nil {
+		return domain.Migration{}, fmt.Errorf("read migration %d: %w", version, err)
+	}
+
+	_ = body.Close()
+
+	return domain.Migration{Version: version, Name: name}, nil
+}
@@ -0,0 +1,30 @@
+package migrator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+func TestPendingMigrations(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"001_init.up.sql", "002_add_users.up.sql", "003_add_index.up.sql"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("SELECT 1;"), 0o600))
+	}
+
+	pending, err := pendingMigrations("file://"+dir, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Migration{
+		{Version: 2, Name: "add_users"},
+		{Version: 3, Name: "add_index"},
+	}, pending)
+
+	pending, err = pendingMigrations("file://"+dir, 3)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
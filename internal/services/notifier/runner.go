@@ -0,0 +1,185 @@
+// Package notifier reads workflow lifecycle events in the background and sends
+// project notifications for them.
+// Like the scheduler, it runs on a single replica elected with a Postgres advisory lock.
+package notifier
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rom8726/di"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ di.Servicer = (*Runner)(nil)
+
+// advisoryLockKey identifies the notifier leader lock ("floxyntf").
+const advisoryLockKey int64 = 0x666c6f78796e7466
+
+type Config struct {
+	Enabled  bool
+	Interval time.Duration
+}
+
+type Runner struct {
+	leaderLock *db.AdvisoryLock
+	events     contract.LifecycleEventsUseCase
+	webhooks   contract.WebhooksUseCase
+	channels   contract.NotificationChannelsUseCase
+	alerts     contract.AlertsUseCase
+	cfg        Config
+	interval   atomic.Int64
+	isLeader   bool
+
+	ctxCancel context.CancelFunc
+	done      chan struct{}
+}
+
+func New(
+	pool *pgxpool.Pool,
+	events contract.LifecycleEventsUseCase,
+	webhooks contract.WebhooksUseCase,
+	channels contract.NotificationChannelsUseCase,
+	alerts contract.AlertsUseCase,
+	cfg *Config,
+) *Runner {
+	runner := &Runner{
+		leaderLock: db.NewAdvisoryLock(pool, advisoryLockKey),
+		events:     events,
+		webhooks:   webhooks,
+		channels:   channels,
+		alerts:     alerts,
+		cfg:        *cfg,
+	}
+	runner.interval.Store(int64(cfg.Interval))
+
+	return runner
+}
+
+// SetInterval changes the interval of the running notifier from the next tick, e.g. on config reload.
+func (r *Runner) SetInterval(interval time.Duration) {
+	r.interval.Store(int64(interval))
+}
+
+func (r *Runner) Start(context.Context) error {
+	if !r.cfg.Enabled {
+		slog.Info("Workflow notifier is disabled")
+
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.ctxCancel = cancel
+	r.done = make(chan struct{})
+
+	go r.loop(ctx)
+
+	return nil
+}
+
+func (r *Runner) Stop(ctx context.Context) error {
+	if r.ctxCancel == nil {
+		return nil
+	}
+
+	r.ctxCancel()
+
+	select {
+	case <-r.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if err := r.leaderLock.Release(ctx); err != nil {
+		slog.Warn("Failed to release notifier advisory lock", "error", err)
+	}
+
+	return nil
+}
+
+func (r *Runner) loop(ctx context.Context) {
+	defer close(r.done)
+
+	interval := time.Duration(r.interval.Load())
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		r.tick(ctx)
+
+		if next := time.Duration(r.interval.Load()); next != interval {
+			interval = next
+			ticker.Reset(interval)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Runner) tick(ctx context.Context) {
+	isLeader, err := r.leaderLock.TryAcquire(ctx)
+	if err != nil {
+		slog.Error("Notifier leader election failed", "error", err)
+
+		return
+	}
+
+	if isLeader != r.isLeader {
+		r.isLeader = isLeader
+		slog.Info("Notifier leadership changed", "leader", isLeader)
+	}
+
+	if !isLeader {
+		return
+	}
+
+	now := time.Now()
+
+	if _, err := r.events.Dispatch(ctx, now, r.webhooks, r.channels, r.alerts); err != nil {
+		slog.Error("Failed to dispatch workflow lifecycle events", "error", err)
+	}
+
+	if err := r.channels.CheckLongRunning(ctx, now); err != nil {
+		slog.Error("Failed to check long running instances", "error", err)
+	}
+
+	if err := r.alerts.CheckLongRunning(ctx, now); err != nil {
+		slog.Error("Failed to check long running instances for alerts", "error", err)
+	}
+
+	delivered, err := r.webhooks.DeliverPending(ctx, now)
+	if err != nil {
+		slog.Error("Failed to deliver webhook notifications", "error", err)
+	}
+
+	if delivered > 0 {
+		slog.Debug("Webhook notifications delivered", "count", delivered)
+	}
+
+	sent, err := r.channels.SendPending(ctx, now)
+	if err != nil {
+		slog.Error("Failed to send channel notifications", "error", err)
+	}
+
+	if sent > 0 {
+		slog.Debug("Channel notifications sent", "count", sent)
+	}
+
+	emailed, err := r.alerts.SendPending(ctx, now)
+	if err != nil {
+		slog.Error("Failed to send email alerts", "error", err)
+	}
+
+	if emailed > 0 {
+		slog.Debug("Email alerts sent", "count", emailed)
+	}
+}
@@ -0,0 +1,121 @@
+package notifiers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+var _ contract.Notifier = (*ChatNotifier)(nil)
+
+// ChatNotifier renders the notification for a Slack or Microsoft Teams incoming webhook.
+type ChatNotifier struct {
+	webhook contract.Notifier
+}
+
+func NewChatNotifier(webhook contract.Notifier) *ChatNotifier {
+	return &ChatNotifier{webhook: webhook}
+}
+
+func (n *ChatNotifier) Notify(
+	ctx context.Context,
+	target domain.NotificationTarget,
+	notification domain.Notification,
+) error {
+	var (
+		body []byte
+		err  error
+	)
+
+	switch target.Kind {
+	case domain.NotificationTargetSlack:
+		body, err = slackPayload(target.Channel, notification)
+	case domain.NotificationTargetTeams:
+		body, err = teamsPayload(notification)
+	default:
+		return fmt.Errorf("unsupported chat kind %q", target.Kind)
+	}
+	if err != nil {
+		return fmt.Errorf("render message: %w", err)
+	}
+
+	notification.Payload = body
+
+	return n.webhook.Notify(ctx, domain.NotificationTarget{
+		Kind:    domain.NotificationTargetWebhook,
+		Address: target.Address,
+	}, notification)
+}
+
+// slackPayload renders the message for a Slack incoming webhook.
+func slackPayload(channel string, notification domain.Notification) ([]byte, error) {
+	type field struct {
+		Title string `json:"title"`
+		Value string `json:"value"`
+		Short bool   `json:"short"`
+	}
+
+	type attachment struct {
+		Color    string  `json:"color"`
+		Fallback string  `json:"fallback"`
+		Fields   []field `json:"fields"`
+	}
+
+	fields := make([]field, 0, len(notification.Facts))
+	for _, f := range notification.Facts {
+		fields = append(fields, field{Title: f.Name, Value: f.Value, Short: f.Name != "Error"})
+	}
+
+	payload := struct {
+		Channel     string       `json:"channel,omitempty"`
+		Text        string       `json:"text"`
+		Attachments []attachment `json:"attachments"`
+	}{
+		Channel: channel,
+		Text:    fmt.Sprintf("*%s*", notification.Subject),
+		Attachments: []attachment{{
+			Color:    "#" + notification.Color,
+			Fallback: notification.Subject,
+			Fields:   fields,
+		}},
+	}
+
+	return json.Marshal(payload)
+}
+
+// teamsPayload renders the message as a connector card for a Microsoft Teams incoming webhook.
+func teamsPayload(notification domain.Notification) ([]byte, error) {
+	type cardFact struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}
+
+	type section struct {
+		ActivityTitle string     `json:"activityTitle"`
+		Facts         []cardFact `json:"facts"`
+	}
+
+	facts := make([]cardFact, 0, len(notification.Facts))
+	for _, f := range notification.Facts {
+		facts = append(facts, cardFact{Name: f.Name, Value: f.Value})
+	}
+
+	payload := struct {
+		Type       string    `json:"@type"`
+		Context    string    `json:"@context"`
+		ThemeColor string    `json:"themeColor"`
+		Summary    string    `json:"summary"`
+		Sections   []section `json:"sections"`
+	}{
+		Type:       "MessageCard",
+		Context:    "https://schema.org/extensions",
+		ThemeColor: notification.Color,
+		Summary:    notification.Subject,
+		Sections:   []section{{ActivityTitle: notification.Subject, Facts: facts}},
+	}
+
+	return json.Marshal(payload)
+}
@@ -0,0 +1,79 @@
+package notifiers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type fakeNotifier struct {
+	target       domain.NotificationTarget
+	notification domain.Notification
+}
+
+func (f *fakeNotifier) Notify(
+	_ context.Context,
+	target domain.NotificationTarget,
+	notification domain.Notification,
+) error {
+	f.target = target
+	f.notification = notification
+
+	return nil
+}
+
+func TestPayloads(t *testing.T) {
+	notification := domain.Notification{
+		Subject: "Workflow instance failed",
+		Color:   "D70000",
+		Facts:   []domain.NotificationFact{{Name: "Workflow", Value: "orders-v2"}},
+	}
+
+	body, err := slackPayload("#ops", notification)
+	require.NoError(t, err)
+
+	var slack map[string]any
+	require.NoError(t, json.Unmarshal(body, &slack))
+	assert.Equal(t, "#ops", slack["channel"])
+	assert.Equal(t, "*Workflow instance failed*", slack["text"])
+
+	body, err = slackPayload("", notification)
+	require.NoError(t, err)
+	assert.NotContains(t, string(body), `"channel"`)
+
+	body, err = teamsPayload(notification)
+	require.NoError(t, err)
+
+	var teams map[string]any
+	require.NoError(t, json.Unmarshal(body, &teams))
+	assert.Equal(t, "MessageCard", teams["@type"])
+	assert.Equal(t, "Workflow instance failed", teams["summary"])
+}
+
+func TestRouter(t *testing.T) {
+	webhook := &fakeNotifier{}
+	router := &Router{notifiers: make(map[domain.NotificationTargetKind]contract.Notifier)}
+	router.Register(domain.NotificationTargetSlack, NewChatNotifier(webhook))
+
+	assert.True(t, router.Supports(domain.NotificationTargetSlack))
+	assert.False(t, router.Supports(domain.NotificationTargetTeams))
+
+	target := domain.NotificationTarget{
+		Kind:    domain.NotificationTargetSlack,
+		Address: "https://hooks.slack.com/services/x",
+		Channel: "#ops",
+	}
+	require.NoError(t, router.Notify(context.Background(), target, domain.Notification{Subject: "Hi"}))
+	assert.Equal(t, domain.NotificationTargetWebhook, webhook.target.Kind)
+	assert.Equal(t, target.Address, webhook.target.Address)
+	assert.Contains(t, string(webhook.notification.Payload), `"channel":"#ops"`)
+
+	err := router.Notify(context.Background(), domain.NotificationTarget{Kind: "sms"}, domain.Notification{})
+	assert.Error(t, err)
+}
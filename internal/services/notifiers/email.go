@@ -0,0 +1,27 @@
+package notifiers
+
+import (
+	"context"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+var _ contract.Notifier = (*EmailNotifier)(nil)
+
+// EmailNotifier sends the notification text to the target address.
+type EmailNotifier struct {
+	emailer contract.Emailer
+}
+
+func NewEmailNotifier(emailer contract.Emailer) *EmailNotifier {
+	return &EmailNotifier{emailer: emailer}
+}
+
+func (n *EmailNotifier) Notify(
+	ctx context.Context,
+	target domain.NotificationTarget,
+	notification domain.Notification,
+) error {
+	return n.emailer.SendNotificationEmail(ctx, target.Address, notification.Subject, notification.Text)
+}
@@ -0,0 +1,61 @@
+package notifiers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/safehttp"
+)
+
+var _ contract.NotificationRouter = (*Router)(nil)
+
+const requestTimeout = 10 * time.Second
+
+// Router implements contract.NotificationRouter. Use cases pick the targets, the router
+// hands each one to the notifier of its kind, so a new channel only needs a notifier.
+type Router struct {
+	notifiers map[domain.NotificationTargetKind]contract.Notifier
+}
+
+// New creates a router with the email, webhook and chat notifiers registered. The webhook and chat
+// notifiers connect only to public addresses, the URLs are set by project managers.
+func New(emailer contract.Emailer) *Router {
+	webhook := NewWebhookNotifier(safehttp.NewClient(requestTimeout))
+	chat := NewChatNotifier(webhook)
+
+	router := &Router{notifiers: make(map[domain.NotificationTargetKind]contract.Notifier)}
+	router.Register(domain.NotificationTargetEmail, NewEmailNotifier(emailer))
+	router.Register(domain.NotificationTargetWebhook, webhook)
+	router.Register(domain.NotificationTargetSlack, chat)
+	router.Register(domain.NotificationTargetTeams, chat)
+
+	return router
+}
+
+// Register sets the notifier of a target kind, replacing the current one.
+// It must be called before the router is used.
+func (r *Router) Register(kind domain.NotificationTargetKind, notifier contract.Notifier) {
+	r.notifiers[kind] = notifier
+}
+
+func (r *Router) Supports(kind domain.NotificationTargetKind) bool {
+	_, ok := r.notifiers[kind]
+
+	return ok
+}
+
+func (r *Router) Notify(
+	ctx context.Context,
+	target domain.NotificationTarget,
+	notification domain.Notification,
+) error {
+	notifier, ok := r.notifiers[target.Kind]
+	if !ok {
+		return fmt.Errorf("unsupported notification target kind %q", target.Kind)
+	}
+
+	return notifier.Notify(ctx, target, notification)
+}
@@ -0,0 +1,58 @@
+package notifiers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+var _ contract.Notifier = (*WebhookNotifier)(nil)
+
+const userAgent = "floxy-manager"
+
+// WebhookNotifier posts the notification payload to the target URL.
+// A non-2xx response is returned as *domain.NotificationEndpointError, without the response body:
+// the errors are shown to project viewers and the body could expose the target.
+type WebhookNotifier struct {
+	client *http.Client
+}
+
+func NewWebhookNotifier(client *http.Client) *WebhookNotifier {
+	return &WebhookNotifier{client: client}
+}
+
+func (n *WebhookNotifier) Notify(
+	ctx context.Context,
+	target domain.NotificationTarget,
+	notification domain.Notification,
+) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.Address, bytes.NewReader(notification.Payload))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	for name, value := range target.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return &domain.NotificationEndpointError{StatusCode: resp.StatusCode}
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package permissions
+
+import (
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+	simplecache "github.com/rom8726/floxy-manager/pkg/simple-cache"
+)
+
+const (
+	// decisionCacheTTL bounds how long a decision may be stale after a change on another replica.
+	// Changes made through this replica invalidate the cache right away.
+	decisionCacheTTL = 10 * time.Second
+
+	decisionCacheCleanupInterval = time.Minute
+)
+
+type projectRoleKey struct {
+	userID    domain.UserID
+	projectID domain.ProjectID
+}
+
+type rolePermKey struct {
+	roleID string
+	key    domain.PermKey
+}
+
+type globalPermKey struct {
+	userID domain.UserID
+	key    domain.PermKey
+}
+
+// decisionCache keeps the inputs of permission decisions for a short time,
+// so that a request checking many projects or permissions does not query each of them.
+type decisionCache struct {
+	projectRoles *simplecache.Cache[projectRoleKey, string]
+	rolePerms    *simplecache.Cache[rolePermKey, bool]
+	globalPerms  *simplecache.Cache[globalPermKey, bool]
+}
+
+func newDecisionCache() *decisionCache {
+	cache := &decisionCache{
+		projectRoles: simplecache.New[projectRoleKey, string](),
+		rolePerms:    simplecache.New[rolePermKey, bool](),
+		globalPerms:  simplecache.New[globalPermKey, bool](),
+	}
+
+	cache.projectRoles.StartCleanup(decisionCacheCleanupInterval)
+	cache.rolePerms.StartCleanup(decisionCacheCleanupInterval)
+	cache.globalPerms.StartCleanup(decisionCacheCleanupInterval)
+
+	return cache
+}
+
+func (c *decisionCache) clear() {
+	c.projectRoles.Clear()
+	c.rolePerms.Clear()
+	c.globalPerms.Clear()
+}
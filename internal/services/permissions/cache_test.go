@@ -0,0 +1,59 @@
+package permissions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type countingPermsRepo struct {
+	contract.PermissionsRepository
+
+	granted map[domain.PermKey]bool
+	calls   [][]domain.PermKey
+}
+
+func (r *countingPermsRepo) RoleHasPermissions(
+	_ context.Context,
+	_ string,
+	keys ...domain.PermKey,
+) (map[domain.PermKey]bool, error) {
+	r.calls = append(r.calls, keys)
+
+	result := make(map[domain.PermKey]bool, len(keys))
+	for _, key := range keys {
+		result[key] = r.granted[key]
+	}
+
+	return result, nil
+}
+
+func TestRoleHasPermissionsCache(t *testing.T) {
+	repo := &countingPermsRepo{granted: map[domain.PermKey]bool{domain.PermProjectView: true}}
+	s := &Service{perms: repo, cache: newDecisionCache()}
+	ctx := context.Background()
+
+	res, err := s.roleHasPermissions(ctx, "role", domain.PermProjectView, domain.PermProjectManage)
+	require.NoError(t, err)
+	assert.Equal(t, map[domain.PermKey]bool{domain.PermProjectView: true, domain.PermProjectManage: false}, res)
+	require.Len(t, repo.calls, 1)
+
+	// Only the key missing from the cache is queried
+	res, err = s.roleHasPermissions(ctx, "role", domain.PermProjectView, domain.PermAuditView)
+	require.NoError(t, err)
+	assert.True(t, res[domain.PermProjectView])
+	assert.False(t, res[domain.PermAuditView])
+	require.Len(t, repo.calls, 2)
+	assert.Equal(t, []domain.PermKey{domain.PermAuditView}, repo.calls[1])
+
+	s.InvalidateCache()
+
+	_, err = s.roleHasPermissions(ctx, "role", domain.PermProjectView)
+	require.NoError(t, err)
+	assert.Len(t, repo.calls, 3)
+}
@@ -3,6 +3,7 @@ package permissions
 import (
 	"context"
 	"log/slog"
+	"time"
 
 	etx "github.com/rom8726/floxy-manager/internal/context"
 	"github.com/rom8726/floxy-manager/internal/contract"
@@ -15,29 +16,132 @@ type Service struct {
 	roles    contract.RolesRepository
 	perms    contract.PermissionsRepository
 	member   contract.MembershipsRepository
+	tenant   contract.TenantMembershipsRepository
+	global   contract.GlobalRolesRepository
+	cache    *decisionCache
 }
 
+var _ contract.PermissionsService = (*Service)(nil)
+
 // New creates a new permissions service.
 func New(
 	projects contract.ProjectsRepository,
 	roles contract.RolesRepository,
 	perms contract.PermissionsRepository,
 	member contract.MembershipsRepository,
+	tenant contract.TenantMembershipsRepository,
+	global contract.GlobalRolesRepository,
 ) *Service {
-	return &Service{projects: projects, roles: roles, perms: perms, member: member}
+	return &Service{
+		projects: projects,
+		roles:    roles,
+		perms:    perms,
+		member:   member,
+		tenant:   tenant,
+		global:   global,
+		cache:    newDecisionCache(),
+	}
+}
+
+// InvalidateCache drops cached permission decisions. It is called after roles or memberships change.
+func (s *Service) InvalidateCache() {
+	s.cache.clear()
 }
 
 func (s *Service) isSuper(ctx context.Context) bool { return etx.IsSuper(ctx) }
 
+// projectRoleID returns the role of the user in the project. A project membership takes precedence,
+// otherwise the tenant membership of the project's tenant applies. It returns "" without either.
+func (s *Service) projectRoleID(ctx context.Context, userID domain.UserID, projectID domain.ProjectID) (string, error) {
+	key := projectRoleKey{userID: userID, projectID: projectID}
+	if roleID, ok := s.cache.projectRoles.Get(key); ok {
+		return roleID, nil
+	}
+
+	roleID, err := s.member.GetForUserProject(ctx, userID, projectID)
+	if err != nil {
+		return "", err
+	}
+
+	if roleID == "" {
+		roleID, err = s.tenant.GetForUserProject(ctx, userID, projectID)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	s.cache.projectRoles.Set(key, roleID, decisionCacheTTL)
+
+	return roleID, nil
+}
+
+// roleHasPermissions checks several permissions of a role. Only the keys missing from the cache are queried.
+func (s *Service) roleHasPermissions(
+	ctx context.Context,
+	roleID string,
+	keys ...domain.PermKey,
+) (map[domain.PermKey]bool, error) {
+	result := make(map[domain.PermKey]bool, len(keys))
+	missing := make([]domain.PermKey, 0, len(keys))
+
+	for _, key := range keys {
+		if has, ok := s.cache.rolePerms.Get(rolePermKey{roleID: roleID, key: key}); ok {
+			result[key] = has
+		} else {
+			missing = append(missing, key)
+		}
+	}
+
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	fetched, err := s.perms.RoleHasPermissions(ctx, roleID, missing...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range missing {
+		result[key] = fetched[key]
+		s.cache.rolePerms.Set(rolePermKey{roleID: roleID, key: key}, fetched[key], decisionCacheTTL)
+	}
+
+	return result, nil
+}
+
+// globalHasPermission checks whether global role assignments of the user grant the permission.
+func (s *Service) globalHasPermission(ctx context.Context, userID domain.UserID, permKey domain.PermKey) (bool, error) {
+	key := globalPermKey{userID: userID, key: permKey}
+	if has, ok := s.cache.globalPerms.Get(key); ok {
+		return has, nil
+	}
+
+	has, err := s.global.UserHasPermission(ctx, userID, permKey)
+	if err != nil {
+		return false, err
+	}
+
+	s.cache.globalPerms.Set(key, has, decisionCacheTTL)
+
+	return has, nil
+}
+
 // HasGlobalPermission checks global (non-project) permissions.
-// For now, only superuser has global permissions.
+// Superusers have all of them, other users get them from global role assignments.
 func (s *Service) HasGlobalPermission(
 	ctx context.Context,
 	permKey domain.PermKey,
 ) (bool, error) {
-	_ = permKey // reserved for future global-permissions storage
+	if s.isSuper(ctx) {
+		return true, nil
+	}
 
-	return s.isSuper(ctx), nil
+	userID := etx.UserID(ctx)
+	if userID == 0 {
+		return false, nil
+	}
+
+	return s.globalHasPermission(ctx, userID, permKey)
 }
 
 // HasProjectPermission checks if the user has a specific permission in the scope of the project.
@@ -62,26 +166,36 @@ func (s *Service) HasProjectPermission(
 		return false, err
 	}
 
-	roleID, err := s.member.GetForUserProject(ctx, userID, projectID)
+	roleID, err := s.projectRoleID(ctx, userID, projectID)
 	if err != nil {
 		slog.Debug("HasProjectPermission: failed to get membership", "error", err, "project_id", projectID, "user_id", userID, "permission", permKey)
 		return false, err
 	}
-	if roleID == "" {
+
+	hasPerm := false
+	if roleID != "" {
+		granted, err := s.roleHasPermissions(ctx, roleID, permKey)
+		if err != nil {
+			slog.Error("HasProjectPermission: failed to check role permission",
+				"error", err,
+				"project_id", projectID,
+				"user_id", userID,
+				"role_id", roleID,
+				"permission", permKey,
+			)
+			return false, err
+		}
+		hasPerm = granted[permKey]
+	} else {
 		slog.Debug("HasProjectPermission: no membership found", "project_id", projectID, "user_id", userID, "permission", permKey)
-		return false, nil
 	}
 
-	hasPerm, err := s.perms.RoleHasPermission(ctx, roleID, permKey)
-	if err != nil {
-		slog.Error("HasProjectPermission: failed to check role permission",
-			"error", err,
-			"project_id", projectID,
-			"user_id", userID,
-			"role_id", roleID,
-			"permission", permKey,
-		)
-		return false, err
+	// Global roles grant their permissions in every project
+	if !hasPerm {
+		hasPerm, err = s.globalHasPermission(ctx, userID, permKey)
+		if err != nil {
+			return false, err
+		}
 	}
 
 	slog.Debug("HasProjectPermission: result",
@@ -122,12 +236,22 @@ func (s *Service) CanViewProject(ctx context.Context, projectID domain.ProjectID
 		return domain.ErrUserNotFound
 	}
 
-	// Check if a user has any membership in the project (any role)
-	roleID, err := s.member.GetForUserProject(ctx, userID, projectID)
+	// Check if a user has any membership in the project or its tenant (any role)
+	roleID, err := s.projectRoleID(ctx, userID, projectID)
 	if err != nil {
 		return err
 	}
-	if roleID == "" {
+	if roleID != "" {
+		return nil
+	}
+
+	// Global roles with project.view can view every project
+	ok, err := s.globalHasPermission(ctx, userID, domain.PermProjectView)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
 		return domain.ErrPermissionDenied
 	}
 
@@ -162,6 +286,44 @@ func (s *Service) CanViewAudit(ctx context.Context, projectID domain.ProjectID)
 	return nil
 }
 
+// CanViewTenantAudit checks if a user can view the audit log of all projects of a tenant.
+// It requires audit.view globally or through the role of a tenant membership.
+func (s *Service) CanViewTenantAudit(ctx context.Context, tenantID domain.TenantID) error {
+	ok, err := s.HasGlobalPermission(ctx, domain.PermAuditView)
+	if err != nil {
+		return err
+	}
+
+	if ok {
+		return nil
+	}
+
+	userID := etx.UserID(ctx)
+	if userID == 0 {
+		return domain.ErrPermissionDenied
+	}
+
+	roleID, err := s.tenant.GetForUserTenant(ctx, userID, tenantID)
+	if err != nil {
+		return err
+	}
+
+	if roleID == "" {
+		return domain.ErrPermissionDenied
+	}
+
+	granted, err := s.roleHasPermissions(ctx, roleID, domain.PermAuditView)
+	if err != nil {
+		return err
+	}
+
+	if !granted[domain.PermAuditView] {
+		return domain.ErrPermissionDenied
+	}
+
+	return nil
+}
+
 // CanManageMembership checks if a user can manage project memberships.
 func (s *Service) CanManageMembership(ctx context.Context, projectID domain.ProjectID) error {
 	ok, err := s.HasProjectPermission(ctx, projectID, domain.PermMembershipManage)
@@ -190,6 +352,20 @@ func (s *Service) CanCreateWorkflow(ctx context.Context, projectID domain.Projec
 	return nil
 }
 
+// CanViewWorkflowData checks if a user can see the input and output payloads of workflow instances in a project.
+func (s *Service) CanViewWorkflowData(ctx context.Context, projectID domain.ProjectID) error {
+	ok, err := s.HasProjectPermission(ctx, projectID, domain.PermWorkflowDataView)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return domain.ErrPermissionDenied
+	}
+
+	return nil
+}
+
 // GetAccessibleProjects returns all projects that a user can access.
 func (s *Service) GetAccessibleProjects(
 	ctx context.Context,
@@ -199,17 +375,42 @@ func (s *Service) GetAccessibleProjects(
 		return projects, nil
 	}
 
-	out := make([]domain.Project, 0, len(projects))
+	userID := etx.UserID(ctx)
+	if userID == 0 {
+		return nil, domain.ErrUserNotFound
+	}
+
+	// Global roles with project.view can view every project
+	viewAll, err := s.globalHasPermission(ctx, userID, domain.PermProjectView)
+	if err != nil {
+		return nil, err
+	}
+
+	if viewAll {
+		return projects, nil
+	}
+
+	roleIDs, err := s.projectRoleIDs(ctx, userID, projects)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]domain.Project, 0, len(roleIDs))
 
 	for i := range projects {
 		project := projects[i]
 
-		ok, err := s.HasProjectPermission(ctx, project.ID, domain.PermProjectView)
+		roleID, ok := roleIDs[project.ID]
+		if !ok {
+			continue
+		}
+
+		granted, err := s.roleHasPermissions(ctx, roleID, domain.PermProjectView)
 		if err != nil {
 			return nil, err
 		}
 
-		if ok {
+		if granted[domain.PermProjectView] {
 			out = append(out, project)
 		}
 	}
@@ -217,6 +418,50 @@ func (s *Service) GetAccessibleProjects(
 	return out, nil
 }
 
+// projectRoleIDs resolves the roles of the user in the given projects with one query per membership kind.
+// A project membership takes precedence over a tenant membership. Projects without either are left out.
+func (s *Service) projectRoleIDs(
+	ctx context.Context,
+	userID domain.UserID,
+	projects []domain.Project,
+) (map[domain.ProjectID]string, error) {
+	memberships, err := s.member.ListForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tenantRoles, err := s.tenant.ListProjectRolesForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	memberRoles := make(map[domain.ProjectID]string, len(memberships))
+	for i := range memberships {
+		if !memberships[i].Expired(now) {
+			memberRoles[memberships[i].ProjectID] = string(memberships[i].RoleID)
+		}
+	}
+
+	result := make(map[domain.ProjectID]string)
+	for i := range projects {
+		projectID := projects[i].ID
+
+		roleID, ok := memberRoles[projectID]
+		if !ok {
+			roleID = tenantRoles[projectID]
+		}
+
+		s.cache.projectRoles.Set(projectRoleKey{userID: userID, projectID: projectID}, roleID, decisionCacheTTL)
+
+		if roleID != "" {
+			result[projectID] = roleID
+		}
+	}
+
+	return result, nil
+}
+
 // GetMyProjectPermissions returns permissions for projects where the user has a membership.
 func (s *Service) GetMyProjectPermissions(
 	ctx context.Context,
@@ -237,64 +482,51 @@ func (s *Service) GetMyProjectPermissions(
 		domain.PermProjectManage,
 		domain.PermProjectCreate,
 		domain.PermWorkflowCreate,
+		domain.PermWorkflowDataView,
 		domain.PermAuditView,
 		domain.PermMembershipManage,
 	}
 
-	result := make(map[domain.ProjectID][]domain.PermKey)
-
-	for i := range all {
-		project := all[i]
+	// Check memberships directly, do not use superuser bypass here
+	roleIDs, err := s.projectRoleIDs(ctx, userID, all)
+	if err != nil {
+		return nil, err
+	}
 
-		// Check membership directly, do not use superuser bypass here
-		roleID, mErr := s.member.GetForUserProject(ctx, userID, project.ID)
-		if mErr != nil {
-			// Continue to next project if membership not found (expected for projects user doesn't belong to)
-			continue
-		}
+	result := make(map[domain.ProjectID][]domain.PermKey)
 
-		if roleID == "" {
-			continue // no membership — skip this project
+	for projectID, roleID := range roleIDs {
+		// Collect granted permissions for the role
+		rolePerms, perr := s.roleHasPermissions(ctx, roleID, permKeys...)
+		if perr != nil {
+			slog.Error("Failed to check role permissions",
+				"error", perr,
+				"role_id", roleID,
+				"project_id", projectID,
+				"user_id", userID,
+			)
+			return nil, perr
 		}
 
-		// Collect granted permissions for the role
 		var granted []domain.PermKey
 
 		for _, key := range permKeys {
-			has, perr := s.perms.RoleHasPermission(ctx, roleID, key)
-			if perr != nil {
-				slog.Error("Failed to check role permission",
-					"error", perr,
-					"role_id", roleID,
-					"permission", key,
-					"project_id", project.ID,
-					"user_id", userID,
-				)
-				return nil, perr
-			}
-
-			if has {
-				slog.Debug("Permission granted",
-					"role_id", roleID,
-					"permission", key,
-					"project_id", project.ID,
-					"user_id", userID,
-				)
+			if rolePerms[key] {
 				granted = append(granted, key)
 			}
 		}
 
 		if len(granted) > 0 {
 			slog.Debug("Project permissions found",
-				"project_id", project.ID,
+				"project_id", projectID,
 				"user_id", userID,
 				"role_id", roleID,
 				"permissions_count", len(granted),
 			)
-			result[project.ID] = granted
+			result[projectID] = granted
 		} else {
 			slog.Debug("No permissions found for project",
-				"project_id", project.ID,
+				"project_id", projectID,
 				"user_id", userID,
 				"role_id", roleID,
 			)
@@ -315,27 +547,26 @@ func (s *Service) GetMyProjectRoles(ctx context.Context) (map[domain.ProjectID]d
 		return nil, err
 	}
 
-	result := make(map[domain.ProjectID]domain.Role)
-
-	for i := range all {
-		project := all[i]
-
-		// Check membership directly, do not use superuser bypass here
-		roleID, err := s.member.GetForUserProject(ctx, userID, project.ID)
-		if err != nil {
-			return nil, err
-		}
+	// Check memberships directly, do not use superuser bypass here
+	roleIDs, err := s.projectRoleIDs(ctx, userID, all)
+	if err != nil {
+		return nil, err
+	}
 
-		if roleID == "" {
-			continue // no membership — skip this project
-		}
+	result := make(map[domain.ProjectID]domain.Role, len(roleIDs))
+	roles := make(map[string]domain.Role)
 
-		role, err := s.roles.GetByID(ctx, domain.RoleID(roleID))
-		if err != nil {
-			return nil, err
+	for projectID, roleID := range roleIDs {
+		role, ok := roles[roleID]
+		if !ok {
+			role, err = s.roles.GetByID(ctx, domain.RoleID(roleID))
+			if err != nil {
+				return nil, err
+			}
+			roles[roleID] = role
 		}
 
-		result[project.ID] = role
+		result[projectID] = role
 	}
 
 	return result, nil
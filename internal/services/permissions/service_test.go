@@ -0,0 +1,67 @@
+package permissions
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type userMembershipsRepo struct {
+	contract.MembershipsRepository
+
+	memberships []domain.ProjectMembership
+}
+
+func (r *userMembershipsRepo) ListForUser(context.Context, domain.UserID) ([]domain.ProjectMembership, error) {
+	return r.memberships, nil
+}
+
+type userTenantRolesRepo struct {
+	contract.TenantMembershipsRepository
+
+	roles map[domain.ProjectID]string
+}
+
+func (r *userTenantRolesRepo) ListProjectRolesForUser(
+	context.Context,
+	domain.UserID,
+) (map[domain.ProjectID]string, error) {
+	return r.roles, nil
+}
+
+func TestProjectRoleIDs(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+
+	s := &Service{
+		member: &userMembershipsRepo{memberships: []domain.ProjectMembership{
+			{ProjectID: 1, RoleID: "owner"},
+			{ProjectID: 3, RoleID: "viewer", ValidUntil: &past},
+		}},
+		tenant: &userTenantRolesRepo{roles: map[domain.ProjectID]string{
+			1: "tenant_viewer",
+			2: "tenant_admin",
+			3: "tenant_viewer",
+		}},
+		cache: newDecisionCache(),
+	}
+
+	projects := []domain.Project{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}}
+
+	roles, err := s.projectRoleIDs(context.Background(), 7, projects)
+	require.NoError(t, err)
+	assert.Equal(t, map[domain.ProjectID]string{
+		1: "owner",         // project membership takes precedence
+		2: "tenant_admin",  // tenant membership
+		3: "tenant_viewer", // expired project membership is ignored
+	}, roles)
+
+	roleID, ok := s.cache.projectRoles.Get(projectRoleKey{userID: 7, projectID: 4})
+	assert.True(t, ok)
+	assert.Empty(t, roleID)
+}
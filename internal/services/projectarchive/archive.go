@@ -0,0 +1,162 @@
+// Package projectarchive reads and writes project archives: gzipped tarballs holding project.json
+// with the project settings and a workflows/*.json document per workflow definition version.
+package projectarchive
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+const (
+	projectFile  = "project.json"
+	workflowsDir = "workflows/"
+
+	// maxFileSize bounds a single decompressed archive file.
+	maxFileSize = 10 << 20
+	// maxWorkflows bounds the workflow definitions read from an archive.
+	maxWorkflows = 5000
+)
+
+var ErrInvalidArchive = errors.New("invalid project archive")
+
+var unsafeFileNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Write writes the archive as a gzipped tarball.
+func Write(w io.Writer, archive *domain.ProjectArchive) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	project, err := json.MarshalIndent(archive, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode project: %w", err)
+	}
+
+	if err := writeFile(tw, projectFile, project, archive.ExportedAt); err != nil {
+		return err
+	}
+
+	for i := range archive.Workflows {
+		workflow := &archive.Workflows[i]
+
+		data, err := json.MarshalIndent(workflow, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode workflow %s v%d: %w", workflow.Name, workflow.Version, err)
+		}
+
+		name := fmt.Sprintf("%s%04d-%s-v%d.json", workflowsDir, i+1,
+			unsafeFileNameChars.ReplaceAllString(workflow.Name, "_"), workflow.Version)
+		if err := writeFile(tw, name, data, archive.ExportedAt); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("write archive: %w", err)
+	}
+
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("write archive: %w", err)
+	}
+
+	return nil
+}
+
+// Read parses a gzipped tarball written by Write.
+func Read(data []byte) (*domain.ProjectArchive, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+	defer gz.Close()
+
+	var (
+		archive   *domain.ProjectArchive
+		workflows []domain.ProjectArchiveWorkflow
+	)
+
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+		}
+
+		name := path.Clean(strings.TrimPrefix(header.Name, "./"))
+		isWorkflow := strings.HasPrefix(name, workflowsDir) && path.Ext(name) == ".json"
+		if header.Typeflag != tar.TypeReg || (name != projectFile && !isWorkflow) {
+			continue
+		}
+
+		content, err := io.ReadAll(io.LimitReader(reader, maxFileSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidArchive, name, err)
+		}
+		if len(content) > maxFileSize {
+			return nil, fmt.Errorf("%w: %s is larger than %d bytes", ErrInvalidArchive, name, maxFileSize)
+		}
+
+		if name == projectFile {
+			archive = new(domain.ProjectArchive)
+			if err := json.Unmarshal(content, archive); err != nil {
+				return nil, fmt.Errorf("%w: %s: %v", ErrInvalidArchive, name, err)
+			}
+
+			continue
+		}
+
+		if len(workflows) >= maxWorkflows {
+			return nil, fmt.Errorf("%w: more than %d workflow definitions", ErrInvalidArchive, maxWorkflows)
+		}
+
+		var item domain.ProjectArchiveWorkflow
+		if err := json.Unmarshal(content, &item); err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidArchive, name, err)
+		}
+
+		workflows = append(workflows, item)
+	}
+
+	if archive == nil {
+		return nil, fmt.Errorf("%w: %s is missing", ErrInvalidArchive, projectFile)
+	}
+
+	if archive.FormatVersion != domain.ProjectArchiveFormatVersion {
+		return nil, fmt.Errorf("%w: unsupported format version %d", ErrInvalidArchive, archive.FormatVersion)
+	}
+
+	archive.Workflows = workflows
+
+	return archive, nil
+}
+
+func writeFile(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package projectarchive
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+func TestWriteRead(t *testing.T) {
+	archive := &domain.ProjectArchive{
+		FormatVersion: domain.ProjectArchiveFormatVersion,
+		ExportedAt:    time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC),
+		Project:       domain.ProjectArchiveProject{Name: "payments", Description: "staging"},
+		Workflows: []domain.ProjectArchiveWorkflow{
+			{Name: "charge card", Version: 2, Definition: json.RawMessage(`{"start":"a"}`)},
+		},
+		Schedules: []domain.ProjectArchiveSchedule{
+			{WorkflowID: "charge card-v2", Name: "nightly", CronExpr: "0 3 * * *", Timezone: "UTC",
+				Input: json.RawMessage(`{"amount":1}`)},
+		},
+		Memberships: []domain.ProjectArchiveMembership{{Username: "alice", RoleKey: "project_owner"}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, archive))
+
+	read, err := Read(buf.Bytes())
+	require.NoError(t, err)
+
+	assert.Equal(t, archive.Project, read.Project)
+	require.Len(t, read.Schedules, 1)
+	assert.Equal(t, "charge card-v2", read.Schedules[0].WorkflowID)
+	assert.JSONEq(t, `{"amount":1}`, string(read.Schedules[0].Input))
+	assert.Equal(t, archive.Memberships, read.Memberships)
+	require.Len(t, read.Workflows, 1)
+	assert.Equal(t, "charge card", read.Workflows[0].Name)
+	assert.JSONEq(t, `{"start":"a"}`, string(read.Workflows[0].Definition))
+}
+
+func TestRead_Invalid(t *testing.T) {
+	_, err := Read([]byte("not gzip"))
+	assert.ErrorIs(t, err, ErrInvalidArchive)
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, &domain.ProjectArchive{FormatVersion: 99}))
+
+	_, err = Read(buf.Bytes())
+	assert.ErrorIs(t, err, ErrInvalidArchive)
+}
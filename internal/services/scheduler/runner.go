@@ -0,0 +1,130 @@
+// Package scheduler runs cron-triggered workflow schedules in the background.
+// Only one replica runs schedules at a time: leadership is taken with a
+// Postgres session-level advisory lock held on a dedicated connection.
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rom8726/di"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ di.Servicer = (*Runner)(nil)
+
+// advisoryLockKey identifies the scheduler leader lock ("floxysch").
+const advisoryLockKey int64 = 0x666c6f7879736368
+
+type Config struct {
+	Enabled  bool
+	Interval time.Duration
+}
+
+// leaderLock is the lock electing the replica running the schedules.
+type leaderLock interface {
+	TryAcquire(ctx context.Context) (bool, error)
+	Release(ctx context.Context) error
+}
+
+type Runner struct {
+	leaderLock leaderLock
+	schedules  contract.SchedulesUseCase
+	cfg        Config
+	isLeader   bool
+
+	ctxCancel context.CancelFunc
+	done      chan struct{}
+}
+
+func New(pool *pgxpool.Pool, schedules contract.SchedulesUseCase, cfg *Config) *Runner {
+	return &Runner{
+		leaderLock: db.NewAdvisoryLock(pool, advisoryLockKey),
+		schedules:  schedules,
+		cfg:        *cfg,
+	}
+}
+
+func (r *Runner) Start(context.Context) error {
+	if !r.cfg.Enabled {
+		slog.Info("Workflow scheduler is disabled")
+
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.ctxCancel = cancel
+	r.done = make(chan struct{})
+
+	go r.loop(ctx)
+
+	return nil
+}
+
+func (r *Runner) Stop(ctx context.Context) error {
+	if r.ctxCancel == nil {
+		return nil
+	}
+
+	r.ctxCancel()
+
+	select {
+	case <-r.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if err := r.leaderLock.Release(ctx); err != nil {
+		slog.Warn("Failed to release scheduler advisory lock", "error", err)
+	}
+
+	return nil
+}
+
+func (r *Runner) loop(ctx context.Context) {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		r.tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Runner) tick(ctx context.Context) {
+	isLeader, err := r.leaderLock.TryAcquire(ctx)
+	if err != nil {
+		slog.Error("Scheduler leader election failed", "error", err)
+
+		return
+	}
+
+	if isLeader != r.isLeader {
+		r.isLeader = isLeader
+		slog.Info("Scheduler leadership changed", "leader", isLeader)
+	}
+
+	if !isLeader {
+		return
+	}
+
+	started, err := r.schedules.RunDue(ctx, time.Now())
+	if err != nil {
+		slog.Error("Failed to run due schedules", "error", err)
+	}
+
+	if started > 0 {
+		slog.Info("Scheduled workflows started", "count", started)
+	}
+}
@@ -0,0 +1,68 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+)
+
+type fakeLeaderLock struct {
+	leader bool
+	err    error
+}
+
+func (l *fakeLeaderLock) TryAcquire(context.Context) (bool, error) {
+	return l.leader, l.err
+}
+
+func (l *fakeLeaderLock) Release(context.Context) error {
+	return nil
+}
+
+type fakeSchedules struct {
+	contract.SchedulesUseCase
+	runs int
+}
+
+func (s *fakeSchedules) RunDue(context.Context, time.Time) (int, error) {
+	s.runs++
+
+	return 0, nil
+}
+
+func TestRunner_tick(t *testing.T) {
+	lock := &fakeLeaderLock{}
+	schedules := &fakeSchedules{}
+	runner := &Runner{leaderLock: lock, schedules: schedules}
+
+	runner.tick(context.Background())
+	assert.Zero(t, schedules.runs)
+
+	lock.leader = true
+	runner.tick(context.Background())
+	assert.Equal(t, 1, schedules.runs)
+	assert.True(t, runner.isLeader)
+
+	lock.leader, lock.err = false, errors.New("connection refused")
+	runner.tick(context.Background())
+	assert.Equal(t, 1, schedules.runs)
+}
+
+func TestRunner_StartStop(t *testing.T) {
+	schedules := &fakeSchedules{}
+	runner := &Runner{
+		leaderLock: &fakeLeaderLock{leader: true},
+		schedules:  schedules,
+		cfg:        Config{Enabled: true, Interval: time.Hour},
+	}
+
+	// The first tick runs at once, Stop waits for it
+	assert.NoError(t, runner.Start(context.Background()))
+	assert.NoError(t, runner.Stop(context.Background()))
+	assert.Equal(t, 1, schedules.runs)
+}
@@ -0,0 +1,92 @@
+// Package secretsrefresher fetches the secret references of the config again, so rotated
+// secrets such as the database password are picked up without a restart.
+// Every replica refreshes its own secrets, so no leader is elected.
+package secretsrefresher
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/rom8726/di"
+
+	"github.com/rom8726/floxy-manager/pkg/secrets"
+)
+
+var _ di.Servicer = (*Runner)(nil)
+
+type Config struct {
+	Interval time.Duration
+}
+
+type Runner struct {
+	resolver *secrets.Resolver
+	cfg      Config
+
+	ctxCancel context.CancelFunc
+	done      chan struct{}
+}
+
+func New(resolver *secrets.Resolver, cfg *Config) *Runner {
+	return &Runner{
+		resolver: resolver,
+		cfg:      *cfg,
+	}
+}
+
+func (r *Runner) Start(context.Context) error {
+	if r.resolver == nil || r.resolver.Len() == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.ctxCancel = cancel
+	r.done = make(chan struct{})
+
+	go r.loop(ctx)
+
+	return nil
+}
+
+func (r *Runner) Stop(ctx context.Context) error {
+	if r.ctxCancel == nil {
+		return nil
+	}
+
+	r.ctxCancel()
+
+	select {
+	case <-r.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return nil
+}
+
+func (r *Runner) loop(ctx context.Context) {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.tick(ctx)
+		}
+	}
+}
+
+func (r *Runner) tick(ctx context.Context) {
+	changed, err := r.resolver.Refresh(ctx)
+	if err != nil {
+		slog.Error("Failed to refresh secrets, keeping the previous values", "error", err)
+	}
+
+	if changed > 0 {
+		slog.Info("Secrets rotated", "changed", changed)
+	}
+}
@@ -5,13 +5,18 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
+	"strings"
+	"sync"
 
 	"github.com/rom8726/floxy-manager/internal/contract"
 	"github.com/rom8726/floxy-manager/internal/domain"
 )
 
 // SSOProviderManager manages multiple SSO providers.
+// Providers can be replaced at runtime when their settings change.
 type SSOProviderManager struct {
+	mu        sync.RWMutex
 	providers map[string]contract.SSOProvider
 	configs   map[string]domain.SSOProviderConfig
 }
@@ -26,19 +31,37 @@ func New() *SSOProviderManager {
 
 // AddProvider adds a new SSO provider.
 func (m *SSOProviderManager) AddProvider(name string, provider contract.SSOProvider, config domain.SSOProviderConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	m.providers[name] = provider
 	m.configs[name] = config
 }
 
+// RemoveProvider removes a provider, e.g. when it gets disabled.
+func (m *SSOProviderManager) RemoveProvider(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.providers, name)
+	delete(m.configs, name)
+}
+
 // GetProvider returns a provider by name.
 func (m *SSOProviderManager) GetProvider(name string) (contract.SSOProvider, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
 	provider, exists := m.providers[name]
 
 	return provider, exists
 }
 
-// GetEnabledProviders returns all enabled providers.
+// GetEnabledProviders returns all enabled providers ordered by name.
 func (m *SSOProviderManager) GetEnabledProviders() []contract.SSOProvider {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
 	var enabled []contract.SSOProvider
 
 	for _, provider := range m.providers {
@@ -47,11 +70,18 @@ func (m *SSOProviderManager) GetEnabledProviders() []contract.SSOProvider {
 		}
 	}
 
+	slices.SortFunc(enabled, func(a, b contract.SSOProvider) int {
+		return strings.Compare(a.GetName(), b.GetName())
+	})
+
 	return enabled
 }
 
 // GetProviderConfig returns the configuration for a provider.
 func (m *SSOProviderManager) GetProviderConfig(name string) (domain.SSOProviderConfig, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
 	providerConfig, exists := m.configs[name]
 
 	return providerConfig, exists
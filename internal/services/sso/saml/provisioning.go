@@ -0,0 +1,144 @@
+package samlprovider
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/crewjam/saml"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/repository/membershipaudit"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+// collectGroups returns all values of the configured group attribute.
+func (p *SAMLProvider) collectGroups(assertion *saml.Assertion) []string {
+	if p.config.GroupAttribute == "" {
+		return nil
+	}
+
+	var groups []string
+
+	for _, stmt := range assertion.AttributeStatements {
+		for _, attr := range stmt.Attributes {
+			if attr.Name != p.config.GroupAttribute && attr.FriendlyName != p.config.GroupAttribute {
+				continue
+			}
+
+			for _, value := range attr.Values {
+				if value.Value != "" {
+					groups = append(groups, value.Value)
+				}
+			}
+		}
+	}
+
+	return groups
+}
+
+// matchGroupMappings returns the mappings that apply to the given groups, at most one per project.
+// Mappings are checked in configuration order and group names are compared case-insensitively.
+func matchGroupMappings(mappings []domain.SAMLGroupMapping, groups []string) []domain.SAMLGroupMapping {
+	if len(mappings) == 0 || len(groups) == 0 {
+		return nil
+	}
+
+	groupSet := make(map[string]struct{}, len(groups))
+	for _, group := range groups {
+		groupSet[strings.ToLower(group)] = struct{}{}
+	}
+
+	var matched []domain.SAMLGroupMapping
+
+	seenProjects := make(map[domain.ProjectID]struct{})
+
+	for _, mapping := range mappings {
+		if _, ok := groupSet[strings.ToLower(mapping.Group)]; !ok {
+			continue
+		}
+
+		if _, ok := seenProjects[mapping.ProjectID]; ok {
+			continue
+		}
+
+		seenProjects[mapping.ProjectID] = struct{}{}
+		matched = append(matched, mapping)
+	}
+
+	return matched
+}
+
+// provisionMemberships grants project memberships according to the group mappings.
+// Existing memberships are left untouched, so roles changed manually are preserved.
+// Failures are logged and do not prevent the login.
+func (p *SAMLProvider) provisionMemberships(ctx context.Context, user *domain.User, groups []string) {
+	mappings := matchGroupMappings(p.config.GroupMappings, groups)
+	if len(mappings) == 0 {
+		return
+	}
+
+	defer p.permissions.InvalidateCache()
+
+	for _, mapping := range mappings {
+		if err := p.provisionMembership(ctx, user, mapping); err != nil {
+			slog.ErrorContext(ctx, "failed to provision SAML group membership",
+				"provider", p.name,
+				"user_id", user.ID,
+				"group", mapping.Group,
+				"project_id", mapping.ProjectID,
+				"role", mapping.RoleKey,
+				"error", err,
+			)
+		}
+	}
+}
+
+func (p *SAMLProvider) provisionMembership(
+	ctx context.Context,
+	user *domain.User,
+	mapping domain.SAMLGroupMapping,
+) error {
+	roleID, err := p.membershipsRepo.GetForUserProject(ctx, user.ID, mapping.ProjectID)
+	if err != nil {
+		return fmt.Errorf("get membership: %w", err)
+	}
+
+	if roleID != "" {
+		return nil
+	}
+
+	role, err := p.rolesRepo.GetByKey(ctx, mapping.RoleKey)
+	if err != nil {
+		return fmt.Errorf("get role: %w", err)
+	}
+
+	return p.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		membership, err := p.membershipsRepo.Create(ctx, mapping.ProjectID, user.ID, role.ID)
+		if err != nil {
+			return err
+		}
+
+		err = membershipaudit.Write(ctx, db.TxFromContext(ctx),
+			membership.ID,
+			int(user.ID),
+			"create",
+			nil,
+			membership,
+		)
+		if err != nil {
+			return fmt.Errorf("write membership audit: %w", err)
+		}
+
+		slog.InfoContext(ctx, "SAML group membership provisioned",
+			"provider", p.name,
+			"user_id", user.ID,
+			"group", mapping.Group,
+			"project_id", mapping.ProjectID,
+			"role", mapping.RoleKey,
+		)
+
+		return nil
+	})
+}
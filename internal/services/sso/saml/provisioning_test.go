@@ -0,0 +1,26 @@
+package samlprovider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+func TestMatchGroupMappings(t *testing.T) {
+	mappings := []domain.SAMLGroupMapping{
+		{Group: "wf-admins", ProjectID: 1, RoleKey: "project_owner"},
+		{Group: "wf-devs", ProjectID: 1, RoleKey: "project_developer"},
+		{Group: "wf-devs", ProjectID: 2, RoleKey: "project_developer"},
+		{Group: "ops", ProjectID: 3, RoleKey: "project_viewer"},
+	}
+
+	assert.Nil(t, matchGroupMappings(mappings, nil))
+	assert.Nil(t, matchGroupMappings(nil, []string{"wf-admins"}))
+
+	assert.Equal(t, []domain.SAMLGroupMapping{
+		{Group: "wf-admins", ProjectID: 1, RoleKey: "project_owner"},
+		{Group: "wf-devs", ProjectID: 2, RoleKey: "project_developer"},
+	}, matchGroupMappings(mappings, []string{"WF-Devs", "wf-admins", "unknown"}))
+}
@@ -22,7 +22,6 @@ import (
 	"path"
 	"path/filepath"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/crewjam/saml"
@@ -30,13 +29,31 @@ import (
 
 	"github.com/rom8726/floxy-manager/internal/contract"
 	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/db"
+	"github.com/rom8726/floxy-manager/pkg/secrets"
 )
 
 const (
-	metadataPath = "/api/v1/auth/saml/metadata"
-	acsPath      = "/api/v1/auth/saml/acs"
+	// requestTTL limits how long the user may stay at the Identity Provider login page.
+	requestTTL = 10 * time.Minute
+
+	legacyMetadataPath = "/api/v1/auth/saml/metadata"
+	legacyACSPath      = "/api/v1/auth/saml/acs"
+	providersPath      = "/api/v1/auth/saml/providers/"
 )
 
+// spPaths returns the SP metadata and ACS paths of the provider. The default AD provider
+// keeps the legacy paths so that already configured Identity Providers continue to work.
+func spPaths(name string) (metadataPath, acsPath string) {
+	if name == domain.SSOProviderNameADSaml {
+		return legacyMetadataPath, legacyACSPath
+	}
+
+	return providersPath + name + "/metadata", providersPath + name + "/acs"
+}
+
+var _ contract.SAMLReconfigurer = (*SAMLProvider)(nil)
+
 // SAMLProvider implements SSOProvider for SAML.
 type SAMLProvider struct {
 	name        string
@@ -48,8 +65,18 @@ type SAMLProvider struct {
 	certificate *x509.Certificate
 	privateKey  crypto.Signer
 
-	sp         *saml.ServiceProvider
-	requestIDs sync.Map
+	tx               db.TxManager
+	membershipsRepo  contract.MembershipsRepository
+	rolesRepo        contract.RolesRepository
+	samlRequestsRepo contract.SAMLRequestsRepository
+	permissions      contract.PermissionsService
+	manager          contract.SSOProviderManager
+	features         contract.LicenseFeatures
+
+	metadataPath string
+	acsPath      string
+
+	sp *saml.ServiceProvider
 }
 
 type SAMLParams struct {
@@ -64,14 +91,31 @@ func New(
 	params *SAMLParams,
 	manager contract.SSOProviderManager,
 	usersRepo contract.UsersRepository,
+	tx db.TxManager,
+	membershipsRepo contract.MembershipsRepository,
+	rolesRepo contract.RolesRepository,
+	samlRequestsRepo contract.SAMLRequestsRepository,
+	permissions contract.PermissionsService,
+	features contract.LicenseFeatures,
 ) (*SAMLProvider, error) {
+	metadataPath, acsPath := spPaths(params.Name)
+
 	provider := &SAMLProvider{
-		name:        params.Name,
-		displayName: params.DisplayName,
-		iconURL:     params.IconURL,
-		config:      params.Config,
-		usersRepo:   usersRepo,
-		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		name:             params.Name,
+		displayName:      params.DisplayName,
+		iconURL:          params.IconURL,
+		config:           params.Config,
+		usersRepo:        usersRepo,
+		httpClient:       &http.Client{Timeout: 30 * time.Second},
+		tx:               tx,
+		membershipsRepo:  membershipsRepo,
+		rolesRepo:        rolesRepo,
+		samlRequestsRepo: samlRequestsRepo,
+		permissions:      permissions,
+		manager:          manager,
+		features:         features,
+		metadataPath:     metadataPath,
+		acsPath:          acsPath,
 	}
 
 	if !params.Config.Enabled {
@@ -86,7 +130,11 @@ func New(
 		}
 	}
 
-	if params.Config.CreateCerts {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// Keys kept in a secret store are never generated.
+	if params.Config.CreateCerts && !secrets.IsReference(params.Config.CertificatePath) {
 		if _, err := os.Stat(params.Config.CertificatePath); err != nil {
 			err := provider.generateSAMLKeys(params.Config.CertificatePath, params.Config.PrivateKeyPath)
 			if err != nil {
@@ -97,7 +145,7 @@ func New(
 
 	// Load certificate if provided
 	if params.Config.CertificatePath != "" {
-		cert, err := provider.loadCertificate(params.Config.CertificatePath)
+		cert, err := provider.loadCertificate(ctx, params.Config.CertificatePath)
 		if err != nil {
 			return nil, fmt.Errorf("failed to load certificate: %w", err)
 		}
@@ -107,7 +155,7 @@ func New(
 
 	// Load private key if provided
 	if params.Config.PrivateKeyPath != "" {
-		key, err := provider.loadPrivateKey(params.Config.PrivateKeyPath)
+		key, err := provider.loadPrivateKey(ctx, params.Config.PrivateKeyPath)
 		if err != nil {
 			return nil, fmt.Errorf("failed to load private key: %w", err)
 		}
@@ -115,9 +163,6 @@ func New(
 		provider.privateKey = key
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
 	var err error
 
 	provider.sp, err = provider.makeSP(ctx)
@@ -140,6 +185,35 @@ func New(
 	return provider, nil
 }
 
+// Reconfigure replaces the provider registered in the manager with one built from the settings,
+// the certificates stay the same. The registered provider keeps serving if the new one cannot be built.
+func (p *SAMLProvider) Reconfigure(settings domain.SAMLSettings, publicRootURL string) error {
+	config := settings.Apply(*p.config)
+	config.PublicRootURL = publicRootURL
+	config.CallbackURL = strings.TrimRight(publicRootURL, "/") + "/api/v1/auth/sso/callback"
+
+	if !config.Enabled {
+		p.manager.RemoveProvider(p.name)
+
+		return nil
+	}
+
+	provider, err := New(
+		&SAMLParams{Name: p.name, DisplayName: p.displayName, IconURL: p.iconURL, Config: &config},
+		p.manager, p.usersRepo, p.tx, p.membershipsRepo, p.rolesRepo, p.samlRequestsRepo, p.permissions, p.features,
+	)
+	if err != nil {
+		return err
+	}
+
+	// New disables the provider when the Identity Provider metadata cannot be loaded
+	if !provider.configured() {
+		return fmt.Errorf("SAML provider %q could not load the Identity Provider metadata", p.name)
+	}
+
+	return nil
+}
+
 // GetType returns the type of SSO provider.
 func (p *SAMLProvider) GetType() string {
 	return string(domain.SSOProviderSAML)
@@ -162,11 +236,12 @@ func (p *SAMLProvider) GetIconURL() string {
 
 // IsEnabled returns true if the provider is enabled and the SSO feature is available in the current license.
 func (p *SAMLProvider) IsEnabled() bool {
-	if p.config == nil || !p.config.Enabled {
-		return false
-	}
+	return p.configured() && p.features.IsFeatureAvailable(domain.FeatureSSO)
+}
 
-	return true
+// configured reports whether the provider is enabled and its service provider is set up.
+func (p *SAMLProvider) configured() bool {
+	return p.config != nil && p.config.Enabled
 }
 
 func (p *SAMLProvider) GenerateSPMetadata() ([]byte, error) {
@@ -194,7 +269,7 @@ func (p *SAMLProvider) GenerateSPMetadata() ([]byte, error) {
 }
 
 // GenerateAuthURL generates the authorization URL with SAML AuthnRequest.
-func (p *SAMLProvider) GenerateAuthURL(state string) (string, error) {
+func (p *SAMLProvider) GenerateAuthURL(ctx context.Context, state string) (string, error) {
 	if !p.IsEnabled() {
 		return "", fmt.Errorf("SAML provider '%s' is not enabled", p.name)
 	}
@@ -223,7 +298,11 @@ func (p *SAMLProvider) GenerateAuthURL(state string) (string, error) {
 		"sso_binding_location", ssoBindingLocation,
 	)
 
-	p.requestIDs.Store(state, authReq.ID)
+	// Pending requests are kept in the database, so the IdP response may reach any replica
+	err = p.samlRequestsRepo.Save(ctx, p.name, state, authReq.ID, time.Now().Add(requestTTL))
+	if err != nil {
+		return "", fmt.Errorf("save authentication request: %w", err)
+	}
 
 	redirectURL, err := authReq.Redirect(state, p.sp)
 	if err != nil {
@@ -255,19 +334,15 @@ func (p *SAMLProvider) Authenticate(
 	)
 
 	// Try to find request ID by state
-	id, ok := p.requestIDs.LoadAndDelete(state)
-	if !ok {
-		// Log all stored states for debugging
-		slog.Warn("SAML state not found in requestIDs",
-			"state", state,
-			"available_states_count", countSyncMap(&p.requestIDs),
-		)
-		return nil, fmt.Errorf("invalid state: %s", state)
-	}
+	idStr, err := p.samlRequestsRepo.Take(ctx, p.name, state, time.Now())
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			slog.Warn("SAML state not found or expired", "provider", p.name, "state", state)
 
-	idStr, ok := id.(string)
-	if !ok {
-		return nil, fmt.Errorf("invalid id type: %T", id)
+			return nil, fmt.Errorf("invalid state: %s", state)
+		}
+
+		return nil, fmt.Errorf("take authentication request: %w", err)
 	}
 
 	slog.Debug("SAML request ID found",
@@ -354,12 +429,17 @@ func (p *SAMLProvider) Authenticate(
 		return nil, fmt.Errorf("find or create user: %w", err)
 	}
 
+	p.provisionMemberships(ctx, user, p.collectGroups(assertion))
+
 	return user, nil
 }
 
 // extractUserInfoFromAssertion extracts user information from SAML assertion.
 func (p *SAMLProvider) extractUserInfoFromAssertion(assertion *saml.Assertion) (username, email string) {
-	slog.Debug("Extracting user info from SAML assertion", assertion.AttributeStatements)
+	slog.Debug("Extracting user info from SAML assertion",
+		"provider", p.name,
+		"attribute_statements_count", len(assertion.AttributeStatements),
+	)
 
 	collected := p.collectByMapping(assertion)
 
@@ -450,11 +530,11 @@ func (p *SAMLProvider) findOrCreateUser(ctx context.Context, username, email str
 	return &user, nil
 }
 
-// loadCertificate loads a certificate from a file.
+// loadCertificate loads a certificate from a file or a secret reference.
 //
 //nolint:gosec // it's ok
-func (p *SAMLProvider) loadCertificate(path string) (*x509.Certificate, error) {
-	data, err := os.ReadFile(path)
+func (p *SAMLProvider) loadCertificate(ctx context.Context, path string) (*x509.Certificate, error) {
+	data, err := secrets.ReadFile(ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read certificate file: %w", err)
 	}
@@ -472,11 +552,11 @@ func (p *SAMLProvider) loadCertificate(path string) (*x509.Certificate, error) {
 	return cert, nil
 }
 
-// loadPrivateKey loads a private key from a file.
+// loadPrivateKey loads a private key from a file or a secret reference.
 //
 //nolint:gosec // it's ok
-func (p *SAMLProvider) loadPrivateKey(path string) (crypto.Signer, error) {
-	data, err := os.ReadFile(path)
+func (p *SAMLProvider) loadPrivateKey(ctx context.Context, path string) (crypto.Signer, error) {
+	data, err := secrets.ReadFile(ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read private key file: %w", err)
 	}
@@ -508,9 +588,9 @@ func (p *SAMLProvider) makeSP(ctx context.Context) (*saml.ServiceProvider, error
 	}
 
 	serviceProvider := &saml.ServiceProvider{
-		EntityID:              path.Join(p.config.PublicRootURL, metadataPath),
-		MetadataURL:           *rootURL.ResolveReference(&url.URL{Path: metadataPath}),
-		AcsURL:                *rootURL.ResolveReference(&url.URL{Path: acsPath}),
+		EntityID:              path.Join(p.config.PublicRootURL, p.metadataPath),
+		MetadataURL:           *rootURL.ResolveReference(&url.URL{Path: p.metadataPath}),
+		AcsURL:                *rootURL.ResolveReference(&url.URL{Path: p.acsPath}),
 		MetadataValidDuration: 24 * time.Hour,
 	}
 
@@ -683,16 +763,6 @@ func (p *SAMLProvider) makeSP(ctx context.Context) (*saml.ServiceProvider, error
 	return serviceProvider, nil
 }
 
-// countSyncMap counts entries in a sync.Map (for debugging)
-func countSyncMap(m *sync.Map) int {
-	count := 0
-	m.Range(func(key, value interface{}) bool {
-		count++
-		return true
-	})
-	return count
-}
-
 // extractInResponseTo extracts InResponseTo attribute from SAML response XML
 func extractInResponseTo(samlResponseXML []byte) string {
 	type Response struct {
@@ -0,0 +1,118 @@
+// Package statsrefresher refreshes the materialized workflow statistics in the background.
+// Like the retention runner, it runs on a single replica elected with a Postgres advisory lock.
+package statsrefresher
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rom8726/di"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ di.Servicer = (*Runner)(nil)
+
+// advisoryLockKey identifies the stats refresh leader lock ("floxysts").
+const advisoryLockKey int64 = 0x666c6f7879737473
+
+type Config struct {
+	Enabled  bool
+	Interval time.Duration
+}
+
+type Runner struct {
+	leaderLock    *db.AdvisoryLock
+	workflowStats contract.WorkflowStatsUseCase
+	cfg           Config
+	isLeader      bool
+
+	ctxCancel context.CancelFunc
+	done      chan struct{}
+}
+
+func New(pool *pgxpool.Pool, workflowStats contract.WorkflowStatsUseCase, cfg *Config) *Runner {
+	return &Runner{
+		leaderLock:    db.NewAdvisoryLock(pool, advisoryLockKey),
+		workflowStats: workflowStats,
+		cfg:           *cfg,
+	}
+}
+
+func (r *Runner) Start(context.Context) error {
+	if !r.cfg.Enabled {
+		slog.Info("Workflow stats refresh is disabled")
+
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.ctxCancel = cancel
+	r.done = make(chan struct{})
+
+	go r.loop(ctx)
+
+	return nil
+}
+
+func (r *Runner) Stop(ctx context.Context) error {
+	if r.ctxCancel == nil {
+		return nil
+	}
+
+	r.ctxCancel()
+
+	select {
+	case <-r.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if err := r.leaderLock.Release(ctx); err != nil {
+		slog.Warn("Failed to release stats refresh advisory lock", "error", err)
+	}
+
+	return nil
+}
+
+func (r *Runner) loop(ctx context.Context) {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		r.tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Runner) tick(ctx context.Context) {
+	isLeader, err := r.leaderLock.TryAcquire(ctx)
+	if err != nil {
+		slog.Error("Stats refresh leader election failed", "error", err)
+
+		return
+	}
+
+	if isLeader != r.isLeader {
+		r.isLeader = isLeader
+		slog.Info("Stats refresh leadership changed", "leader", isLeader)
+	}
+
+	if !isLeader {
+		return
+	}
+
+	if _, err := r.workflowStats.Refresh(ctx); err != nil {
+		slog.Error("Failed to refresh workflow stats", "error", err)
+	}
+}
@@ -0,0 +1,182 @@
+package tokenizer
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/secrets"
+)
+
+// Signing algorithms of tokens. HS256 uses the shared secret, the others a private key
+// whose public part downstream services can fetch to validate tokens.
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+var errUnsupportedKey = errors.New("unsupported private key type")
+
+// KeyLoader returns the signer of a key URI. Loaders of KMS backends are registered with
+// RegisterKeyLoader, the signing itself then happens in the KMS.
+type KeyLoader func(ctx context.Context, uri string) (crypto.Signer, error)
+
+var keyLoaders = struct {
+	sync.RWMutex
+	byScheme map[string]KeyLoader
+}{byScheme: map[string]KeyLoader{"file": loadPEMFile}}
+
+// RegisterKeyLoader registers the loader of key URIs with the scheme, e.g. "awskms".
+func RegisterKeyLoader(scheme string, loader KeyLoader) {
+	keyLoaders.Lock()
+	defer keyLoaders.Unlock()
+
+	keyLoaders.byScheme[scheme] = loader
+}
+
+// LoadSigner returns the signer of a key URI. A URI without a scheme is a PEM file path,
+// a secret reference such as vault://secret/data/floxy#jwt_key is a PEM kept in a secret store.
+func LoadSigner(ctx context.Context, uri string) (crypto.Signer, error) {
+	scheme, _, found := strings.Cut(uri, "://")
+	if !found {
+		scheme = "file"
+	}
+
+	keyLoaders.RLock()
+	loader, ok := keyLoaders.byScheme[scheme]
+	keyLoaders.RUnlock()
+
+	if ok {
+		return loader(ctx, uri)
+	}
+
+	if secrets.IsReference(uri) {
+		data, err := secrets.ReadFile(ctx, uri)
+		if err != nil {
+			return nil, fmt.Errorf("read private key: %w", err)
+		}
+
+		return parsePEM(data)
+	}
+
+	return nil, fmt.Errorf("no key loader for scheme %q", scheme)
+}
+
+// loadPEMFile reads a PKCS #1 RSA or PKCS #8 RSA/Ed25519 private key.
+func loadPEMFile(_ context.Context, uri string) (crypto.Signer, error) {
+	data, err := os.ReadFile(strings.TrimPrefix(uri, "file://"))
+	if err != nil {
+		return nil, fmt.Errorf("read private key: %w", err)
+	}
+
+	return parsePEM(data)
+}
+
+func parsePEM(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	if block.Type == "RSA PRIVATE KEY" {
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse private key: %w", err)
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errUnsupportedKey
+	}
+
+	return signer, nil
+}
+
+// signerMethod signs tokens with any crypto.Signer, so keys kept in a KMS work the same as
+// keys loaded from files. Verification is done by the standard method of the algorithm.
+type signerMethod struct {
+	verifier jwt.SigningMethod
+	hash     crypto.Hash
+}
+
+func newSignerMethod(algorithm string, signer crypto.Signer) (*signerMethod, error) {
+	switch algorithm {
+	case AlgorithmRS256:
+		if _, ok := signer.Public().(*rsa.PublicKey); !ok {
+			return nil, fmt.Errorf("%w: RS256 needs an RSA key", errUnsupportedKey)
+		}
+
+		return &signerMethod{verifier: jwt.SigningMethodRS256, hash: crypto.SHA256}, nil
+	case AlgorithmEdDSA:
+		if _, ok := signer.Public().(ed25519.PublicKey); !ok {
+			return nil, fmt.Errorf("%w: EdDSA needs an Ed25519 key", errUnsupportedKey)
+		}
+
+		return &signerMethod{verifier: jwt.SigningMethodEdDSA}, nil
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm %q", algorithm)
+	}
+}
+
+func (m *signerMethod) Alg() string {
+	return m.verifier.Alg()
+}
+
+func (m *signerMethod) Verify(signingString string, sig []byte, key any) error {
+	return m.verifier.Verify(signingString, sig, key)
+}
+
+func (m *signerMethod) Sign(signingString string, key any) ([]byte, error) {
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, jwt.ErrInvalidKeyType
+	}
+
+	// Ed25519 signs the message itself, RSA PKCS #1 v1.5 its digest
+	digest := []byte(signingString)
+	if m.hash != 0 {
+		hasher := m.hash.New()
+		hasher.Write(digest)
+		digest = hasher.Sum(nil)
+	}
+
+	return signer.Sign(rand.Reader, digest, m.hash)
+}
+
+// publicJWK describes the verification key of the asymmetric algorithms, false for HS256.
+func publicJWK(alg, keyID string, key any) (domain.JSONWebKey, bool) {
+	jwk := domain.JSONWebKey{Kid: keyID, Use: "sig", Alg: alg}
+
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(key)
+	default:
+		return domain.JSONWebKey{}, false
+	}
+
+	return jwk, true
+}
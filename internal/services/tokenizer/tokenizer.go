@@ -2,6 +2,7 @@
 package tokenizer
 
 import (
+	"crypto"
 	"errors"
 	"fmt"
 	"time"
@@ -19,42 +20,107 @@ var (
 )
 
 type Service struct {
-	secretKey        []byte
+	secretKey []byte
+	method    jwt.SigningMethod
+	// signKey and verifyKey are the secret key with HS256, the private and public keys otherwise.
+	signKey          any
+	verifyKey        any
+	keyID            string
 	accessTTL        time.Duration
 	refreshTTL       time.Duration
 	resetPasswordTTL time.Duration
+	verifyEmailTTL   time.Duration
+	impersonationTTL time.Duration
 }
 
 type ServiceParams struct {
-	SecretKey                               []byte
-	AccessTTL, RefreshTTL, ResetPasswordTTL time.Duration
+	// SecretKey signs HS256 tokens, it also encrypts 2FA secrets whatever the algorithm.
+	SecretKey []byte
+	// Algorithm is AlgorithmHS256 (default), AlgorithmRS256 or AlgorithmEdDSA.
+	Algorithm string
+	// Signer holds the private key of the asymmetric algorithms, see LoadSigner.
+	Signer crypto.Signer
+	// KeyID is set as the kid header of tokens and in the published key set.
+	KeyID                                                   string
+	AccessTTL, RefreshTTL, ResetPasswordTTL, VerifyEmailTTL time.Duration
+	// ImpersonationTTL is the lifetime of impersonation tokens, AccessTTL when zero.
+	ImpersonationTTL time.Duration
 }
 
 func New(
 	params *ServiceParams,
-) *Service {
-	return &Service{
+) (*Service, error) {
+	service := &Service{
 		secretKey:        params.SecretKey,
+		method:           jwt.SigningMethodHS256,
+		signKey:          params.SecretKey,
+		verifyKey:        params.SecretKey,
+		keyID:            params.KeyID,
 		accessTTL:        params.AccessTTL,
 		refreshTTL:       params.RefreshTTL,
 		resetPasswordTTL: params.ResetPasswordTTL,
+		verifyEmailTTL:   params.VerifyEmailTTL,
+		impersonationTTL: params.ImpersonationTTL,
 	}
+
+	if service.impersonationTTL <= 0 {
+		service.impersonationTTL = params.AccessTTL
+	}
+
+	if params.Algorithm == "" || params.Algorithm == AlgorithmHS256 {
+		return service, nil
+	}
+
+	if params.Signer == nil {
+		return nil, fmt.Errorf("%s signing needs a private key", params.Algorithm)
+	}
+
+	method, err := newSignerMethod(params.Algorithm, params.Signer)
+	if err != nil {
+		return nil, err
+	}
+
+	service.method = method
+	service.signKey = params.Signer
+	service.verifyKey = params.Signer.Public()
+
+	return service, nil
 }
 
 func (s *Service) SecretKey() string {
 	return string(s.secretKey)
 }
 
-func (s *Service) AccessToken(user *domain.User) (string, error) {
-	return s.generateToken(user, domain.TokenTypeAccess, s.accessTTL)
+// AccessToken creates an access token bound to the login session.
+func (s *Service) AccessToken(user *domain.User, sessionID domain.SessionID) (string, error) {
+	return s.generateToken(user, domain.TokenTypeAccess, s.accessTTL, sessionID, "", nil)
+}
+
+// RefreshToken creates a refresh token bound to the login session. The session keeps
+// tokenID to accept only the last issued token.
+func (s *Service) RefreshToken(user *domain.User, sessionID domain.SessionID, tokenID string) (string, error) {
+	return s.generateToken(user, domain.TokenTypeRefresh, s.refreshTTL, sessionID, tokenID, nil)
+}
+
+// ImpersonationToken creates an access token acting as the user on behalf of the actor. It is bound to
+// the session of the actor and carries the actor in the act claim, there is no refresh token.
+func (s *Service) ImpersonationToken(user, actor *domain.User, actorSessionID domain.SessionID) (string, error) {
+	return s.generateToken(user, domain.TokenTypeAccess, s.impersonationTTL, actorSessionID, "", &domain.TokenActor{
+		UserID:   uint(actor.ID),
+		Username: actor.Username,
+	})
+}
+
+func (s *Service) ImpersonationTokenTTL() time.Duration {
+	return s.impersonationTTL
 }
 
-func (s *Service) RefreshToken(user *domain.User) (string, error) {
-	return s.generateToken(user, domain.TokenTypeRefresh, s.refreshTTL)
+func (s *Service) RefreshTokenTTL() time.Duration {
+	return s.refreshTTL
 }
 
 func (s *Service) ResetPasswordToken(user *domain.User) (string, time.Duration, error) {
-	token, err := s.generateToken(user, domain.TokenTypeResetPassword, s.resetPasswordTTL)
+	token, err := s.generateToken(user, domain.TokenTypeResetPassword, s.resetPasswordTTL, "", "", nil)
 	if err != nil {
 		return "", 0, err
 	}
@@ -62,6 +128,25 @@ func (s *Service) ResetPasswordToken(user *domain.User) (string, time.Duration,
 	return token, s.resetPasswordTTL, nil
 }
 
+func (s *Service) VerifyEmailToken(user *domain.User) (string, time.Duration, error) {
+	token, err := s.generateToken(user, domain.TokenTypeVerifyEmail, s.verifyEmailTTL, "", "", nil)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return token, s.verifyEmailTTL, nil
+}
+
+// JWKS returns the public keys validating the tokens, none with HS256.
+func (s *Service) JWKS() domain.JSONWebKeySet {
+	set := domain.JSONWebKeySet{Keys: []domain.JSONWebKey{}}
+	if jwk, ok := publicJWK(s.method.Alg(), s.keyID, s.verifyKey); ok {
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	return set
+}
+
 func (s *Service) AccessTokenTTL() time.Duration {
 	return s.accessTTL
 }
@@ -80,12 +165,13 @@ func (s *Service) verifyToken(token string, tokenType domain.TokenType) (*domain
 		token,
 		&domain.TokenClaims{},
 		func(token *jwt.Token) (any, error) {
-			_, ok := token.Method.(*jwt.SigningMethodHMAC)
-			if !ok {
+			// Only the configured algorithm is accepted, a token signed with the public key
+			// as an HMAC secret must not pass
+			if token.Method.Alg() != s.method.Alg() {
 				return nil, errUnexpectedMethod
 			}
 
-			return s.secretKey, nil
+			return s.verifyKey, nil
 		},
 	)
 	if err != nil {
@@ -104,20 +190,42 @@ func (s *Service) verifyToken(token string, tokenType domain.TokenType) (*domain
 	return claims, nil
 }
 
-func (s *Service) generateToken(user *domain.User, tokenType domain.TokenType, ttl time.Duration) (string, error) {
+func (s *Service) generateToken(
+	user *domain.User,
+	tokenType domain.TokenType,
+	ttl time.Duration,
+	sessionID domain.SessionID,
+	tokenID string,
+	actor *domain.TokenActor,
+) (string, error) {
 	now := time.Now().UTC()
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &domain.TokenClaims{
+	if tokenID == "" {
+		tokenID = uuid.NewString()
+	}
+
+	claims := &domain.TokenClaims{
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
 			IssuedAt:  jwt.NewNumericDate(now),
-			ID:        uuid.NewString(),
+			ID:        tokenID,
 		},
 		TokenType:   tokenType,
 		UserID:      uint(user.ID),
 		Username:    user.Username,
 		IsSuperuser: user.IsSuperuser,
-	})
+		SessionID:   sessionID,
+		Actor:       actor,
+	}
+
+	if tokenType == domain.TokenTypeVerifyEmail {
+		claims.Email = user.Email
+	}
+
+	token := jwt.NewWithClaims(s.method, claims)
+	if s.keyID != "" {
+		token.Header["kid"] = s.keyID
+	}
 
-	return token.SignedString(s.secretKey)
+	return token.SignedString(s.signKey)
 }
@@ -0,0 +1,108 @@
+package tokenizer
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+func newTestService(t *testing.T, params ServiceParams) *Service {
+	t.Helper()
+
+	params.SecretKey = []byte("secret")
+	params.AccessTTL = time.Hour
+	params.RefreshTTL = time.Hour
+
+	service, err := New(&params)
+	require.NoError(t, err)
+
+	return service
+}
+
+func TestSigningAlgorithms(t *testing.T) {
+	rsaKey, err := LoadSigner(context.Background(), "test/sample_key")
+	require.NoError(t, err)
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	user := &domain.User{ID: 3, Username: "jdoe"}
+
+	for _, params := range []ServiceParams{
+		{},
+		{Algorithm: AlgorithmRS256, Signer: rsaKey, KeyID: "k1"},
+		{Algorithm: AlgorithmEdDSA, Signer: edKey},
+	} {
+		service := newTestService(t, params)
+
+		token, err := service.AccessToken(user, "s1")
+		require.NoError(t, err)
+
+		claims, err := service.VerifyToken(token, domain.TokenTypeAccess)
+		require.NoError(t, err)
+		assert.Equal(t, "jdoe", claims.Username)
+
+		jwks := service.JWKS()
+		if params.Algorithm == "" {
+			assert.Empty(t, jwks.Keys)
+			continue
+		}
+
+		require.Len(t, jwks.Keys, 1)
+		assert.Equal(t, params.Algorithm, jwks.Keys[0].Alg)
+		assert.Equal(t, params.KeyID, jwks.Keys[0].Kid)
+	}
+
+	_, err = New(&ServiceParams{Algorithm: AlgorithmEdDSA, Signer: rsaKey})
+	assert.Error(t, err)
+}
+
+func TestVerifyTokenRejectsOtherAlgorithm(t *testing.T) {
+	rsaKey, err := LoadSigner(context.Background(), "test/sample_key")
+	require.NoError(t, err)
+
+	service := newTestService(t, ServiceParams{Algorithm: AlgorithmRS256, Signer: rsaKey})
+
+	// An HS256 token signed with the shared secret is not accepted once RS256 is configured
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &domain.TokenClaims{
+		TokenType: domain.TokenTypeAccess,
+		UserID:    3,
+	}).SignedString([]byte("secret"))
+	require.NoError(t, err)
+
+	_, err = service.VerifyToken(token, domain.TokenTypeAccess)
+	assert.ErrorIs(t, err, domain.ErrInvalidToken)
+}
+
+func TestImpersonationToken(t *testing.T) {
+	service := newTestService(t, ServiceParams{ImpersonationTTL: 10 * time.Minute})
+
+	user := &domain.User{ID: 3, Username: "jdoe"}
+	actor := &domain.User{ID: 1, Username: "admin", IsSuperuser: true}
+
+	token, err := service.ImpersonationToken(user, actor, "s1")
+	require.NoError(t, err)
+
+	claims, err := service.VerifyToken(token, domain.TokenTypeAccess)
+	require.NoError(t, err)
+	assert.Equal(t, uint(3), claims.UserID)
+	assert.False(t, claims.IsSuperuser)
+	assert.Equal(t, domain.SessionID("s1"), claims.SessionID)
+	assert.Equal(t, &domain.TokenActor{UserID: 1, Username: "admin"}, claims.Actor)
+	assert.WithinDuration(t, time.Now().Add(10*time.Minute), claims.ExpiresAt.Time, time.Minute)
+
+	token, err = service.AccessToken(user, "s1")
+	require.NoError(t, err)
+
+	claims, err = service.VerifyToken(token, domain.TokenTypeAccess)
+	require.NoError(t, err)
+	assert.Nil(t, claims.Actor)
+}
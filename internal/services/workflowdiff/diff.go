@@ -0,0 +1,138 @@
+package workflowdiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+const stepsField = "steps"
+
+// Diff compares two workflow definitions (floxy graph JSON) and returns the step
+// additions, removals and field-level changes between them.
+func Diff(from, to domain.WorkflowDefinition) (domain.WorkflowDiff, error) {
+	fromGraph, err := parseGraph(from.Definition)
+	if err != nil {
+		return domain.WorkflowDiff{}, fmt.Errorf("parse definition %s: %w", from.ID, err)
+	}
+
+	toGraph, err := parseGraph(to.Definition)
+	if err != nil {
+		return domain.WorkflowDiff{}, fmt.Errorf("parse definition %s: %w", to.ID, err)
+	}
+
+	result := domain.WorkflowDiff{
+		Name:         to.Name,
+		FromVersion:  from.Version,
+		ToVersion:    to.Version,
+		Changes:      compareFields(withoutSteps(fromGraph), withoutSteps(toGraph)),
+		StepsAdded:   []string{},
+		StepsRemoved: []string{},
+		StepsChanged: []domain.WorkflowStepDiff{},
+	}
+
+	fromSteps := parseSteps(fromGraph)
+	toSteps := parseSteps(toGraph)
+
+	for _, name := range sortedKeys(toSteps) {
+		if _, ok := fromSteps[name]; !ok {
+			result.StepsAdded = append(result.StepsAdded, name)
+		}
+	}
+
+	for _, name := range sortedKeys(fromSteps) {
+		toStep, ok := toSteps[name]
+		if !ok {
+			result.StepsRemoved = append(result.StepsRemoved, name)
+
+			continue
+		}
+
+		if changes := compareFields(fromSteps[name], toStep); len(changes) > 0 {
+			result.StepsChanged = append(result.StepsChanged, domain.WorkflowStepDiff{
+				Step:    name,
+				Changes: changes,
+			})
+		}
+	}
+
+	return result, nil
+}
+
+func parseGraph(data json.RawMessage) (map[string]any, error) {
+	graph := map[string]any{}
+	if len(data) == 0 {
+		return graph, nil
+	}
+
+	if err := json.Unmarshal(data, &graph); err != nil {
+		return nil, err
+	}
+
+	return graph, nil
+}
+
+func withoutSteps(graph map[string]any) map[string]any {
+	result := make(map[string]any, len(graph))
+	for k, v := range graph {
+		if k != stepsField {
+			result[k] = v
+		}
+	}
+
+	return result
+}
+
+func parseSteps(graph map[string]any) map[string]map[string]any {
+	raw, _ := graph[stepsField].(map[string]any)
+
+	steps := make(map[string]map[string]any, len(raw))
+	for name, value := range raw {
+		step, ok := value.(map[string]any)
+		if !ok {
+			step = map[string]any{}
+		}
+		steps[name] = step
+	}
+
+	return steps
+}
+
+func compareFields(from, to map[string]any) []domain.WorkflowFieldChange {
+	keys := make(map[string]struct{}, len(from)+len(to))
+	for k := range from {
+		keys[k] = struct{}{}
+	}
+	for k := range to {
+		keys[k] = struct{}{}
+	}
+
+	changes := make([]domain.WorkflowFieldChange, 0)
+	for _, key := range sortedKeys(keys) {
+		fromValue, toValue := from[key], to[key]
+		if reflect.DeepEqual(fromValue, toValue) {
+			continue
+		}
+
+		changes = append(changes, domain.WorkflowFieldChange{
+			Field: key,
+			From:  fromValue,
+			To:    toValue,
+		})
+	}
+
+	return changes
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,66 @@
+package workflowdiff
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+func TestDiff(t *testing.T) {
+	from := domain.WorkflowDefinition{
+		ID:      "orders-v1",
+		Name:    "orders",
+		Version: 1,
+		Definition: json.RawMessage(`{
+			"start": "validate",
+			"dlq_enabled": false,
+			"steps": {
+				"validate": {"name": "validate", "handler": "validate", "max_retries": 1},
+				"charge": {"name": "charge", "handler": "charge", "max_retries": 3},
+				"legacy": {"name": "legacy", "handler": "legacy"}
+			}
+		}`),
+	}
+	to := domain.WorkflowDefinition{
+		ID:      "orders-v2",
+		Name:    "orders",
+		Version: 2,
+		Definition: json.RawMessage(`{
+			"start": "validate",
+			"dlq_enabled": true,
+			"steps": {
+				"validate": {"name": "validate", "handler": "validate", "max_retries": 1},
+				"charge": {"name": "charge", "handler": "charge_v2", "max_retries": 5},
+				"notify": {"name": "notify", "handler": "notify"}
+			}
+		}`),
+	}
+
+	diff, err := Diff(from, to)
+	require.NoError(t, err)
+
+	assert.Equal(t, "orders", diff.Name)
+	assert.Equal(t, 1, diff.FromVersion)
+	assert.Equal(t, 2, diff.ToVersion)
+	assert.Equal(t, []domain.WorkflowFieldChange{{Field: "dlq_enabled", From: false, To: true}}, diff.Changes)
+	assert.Equal(t, []string{"notify"}, diff.StepsAdded)
+	assert.Equal(t, []string{"legacy"}, diff.StepsRemoved)
+	require.Len(t, diff.StepsChanged, 1)
+	assert.Equal(t, "charge", diff.StepsChanged[0].Step)
+	assert.Equal(t, []domain.WorkflowFieldChange{
+		{Field: "handler", From: "charge", To: "charge_v2"},
+		{Field: "max_retries", From: float64(3), To: float64(5)},
+	}, diff.StepsChanged[0].Changes)
+}
+
+func TestDiff_InvalidDefinition(t *testing.T) {
+	_, err := Diff(
+		domain.WorkflowDefinition{ID: "a-v1", Definition: json.RawMessage(`{`)},
+		domain.WorkflowDefinition{ID: "a-v2", Definition: json.RawMessage(`{}`)},
+	)
+	require.Error(t, err)
+}
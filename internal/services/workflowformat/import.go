@@ -0,0 +1,290 @@
+package workflowformat
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+var ErrInvalidImport = errors.New("invalid import payload")
+
+// maxImportFileSize bounds a single decompressed archive file.
+const maxImportFileSize = 10 << 20
+
+// exportedFileName matches the <name>/v<version>.<ext> files of workflow exports.
+var exportedFileName = regexp.MustCompile(`^(?:.*/)?([^/]+)/v(\d+)\.(?:json|ya?ml)$`)
+
+type importDocument struct {
+	Name       string          `json:"name"`
+	Version    int             `json:"version"`
+	Definition json.RawMessage `json:"definition"`
+}
+
+// ParseImport extracts the workflow definitions of an import payload by its Content-Type:
+//   - application/json: an array of {name, version, definition} documents;
+//   - application/yaml: a stream of such documents;
+//   - application/zip and application/gzip (tar.gz): JSON or YAML files holding such a document,
+//     or a bare definition in a <name>/v<version> file as produced by the workflow export.
+func ParseImport(contentType string, data []byte, maxItems int) ([]domain.WorkflowImportItem, error) {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+
+	var (
+		items []domain.WorkflowImportItem
+		err   error
+	)
+
+	switch {
+	case mediaType == "application/json":
+		items, err = parseJSONImport(data)
+	case IsYAML(mediaType):
+		items, err = parseYAMLImport(data, maxItems)
+	case mediaType == "application/zip":
+		items, err = parseZipImport(data, maxItems)
+	case mediaType == "application/gzip" || mediaType == "application/x-gzip":
+		items, err = parseTarImport(data, maxItems)
+	default:
+		return nil, fmt.Errorf("%w: unsupported content type %q", ErrInvalidImport, mediaType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case len(items) == 0:
+		return nil, fmt.Errorf("%w: no workflow definitions", ErrInvalidImport)
+	case len(items) > maxItems:
+		return nil, fmt.Errorf("%w: more than %d workflow definitions", ErrInvalidImport, maxItems)
+	}
+
+	return items, nil
+}
+
+func parseJSONImport(data []byte) ([]domain.WorkflowImportItem, error) {
+	var docs []importDocument
+	if err := json.Unmarshal(data, &docs); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+	}
+
+	items := make([]domain.WorkflowImportItem, 0, len(docs))
+	for i := range docs {
+		items = append(items, docs[i].item("#"+strconv.Itoa(i)))
+	}
+
+	return items, nil
+}
+
+func parseYAMLImport(data []byte, maxItems int) ([]domain.WorkflowImportItem, error) {
+	var items []domain.WorkflowImportItem
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for i := 0; ; i++ {
+		var node yaml.Node
+		err := decoder.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			return items, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: document #%d: %v", ErrInvalidImport, i, err)
+		}
+
+		if len(items) >= maxItems {
+			return nil, fmt.Errorf("%w: more than %d workflow definitions", ErrInvalidImport, maxItems)
+		}
+
+		doc, err := decodeYAMLDocument(&node)
+		if err != nil {
+			return nil, fmt.Errorf("%w: document #%d: %v", ErrInvalidImport, i, err)
+		}
+
+		items = append(items, doc.item("#"+strconv.Itoa(i)))
+	}
+}
+
+func parseZipImport(data []byte, maxItems int) ([]domain.WorkflowImportItem, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+	}
+
+	var items []domain.WorkflowImportItem
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() || !isDefinitionFile(file.Name) {
+			continue
+		}
+
+		if len(items) >= maxItems {
+			return nil, fmt.Errorf("%w: more than %d workflow definitions", ErrInvalidImport, maxItems)
+		}
+
+		rc, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidImport, file.Name, err)
+		}
+
+		content, err := readLimited(rc)
+		_ = rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidImport, file.Name, err)
+		}
+
+		item, err := parseImportFile(file.Name, content)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func parseTarImport(data []byte, maxItems int) ([]domain.WorkflowImportItem, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+	}
+	defer gz.Close()
+
+	var items []domain.WorkflowImportItem
+
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return items, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+		}
+
+		if header.Typeflag != tar.TypeReg || !isDefinitionFile(header.Name) {
+			continue
+		}
+
+		if len(items) >= maxItems {
+			return nil, fmt.Errorf("%w: more than %d workflow definitions", ErrInvalidImport, maxItems)
+		}
+
+		content, err := readLimited(reader)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidImport, header.Name, err)
+		}
+
+		item, err := parseImportFile(header.Name, content)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+}
+
+// parseImportFile decodes an archive file: a document with a definition field,
+// or a bare definition named and versioned by the file path.
+func parseImportFile(name string, content []byte) (domain.WorkflowImportItem, error) {
+	if ext := path.Ext(name); ext == ".yaml" || ext == ".yml" {
+		var err error
+		content, err = YAMLToJSON(content)
+		if err != nil {
+			return domain.WorkflowImportItem{}, fmt.Errorf("%w: %s: %v", ErrInvalidImport, name, err)
+		}
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(content, &fields); err != nil {
+		return domain.WorkflowImportItem{}, fmt.Errorf("%w: %s: %v", ErrInvalidImport, name, err)
+	}
+
+	if _, ok := fields["definition"]; ok {
+		var doc importDocument
+		if err := json.Unmarshal(content, &doc); err != nil {
+			return domain.WorkflowImportItem{}, fmt.Errorf("%w: %s: %v", ErrInvalidImport, name, err)
+		}
+
+		return doc.item(name), nil
+	}
+
+	match := exportedFileName.FindStringSubmatch(name)
+	if match == nil {
+		return domain.WorkflowImportItem{}, fmt.Errorf(
+			"%w: %s: a bare definition must be stored as <name>/v<version>", ErrInvalidImport, name)
+	}
+
+	version, _ := strconv.Atoi(match[2])
+
+	return domain.WorkflowImportItem{
+		Source:     name,
+		Name:       match[1],
+		Version:    version,
+		Definition: json.RawMessage(content),
+	}, nil
+}
+
+func decodeYAMLDocument(node *yaml.Node) (importDocument, error) {
+	value, err := toJSONValue(node)
+	if err != nil {
+		return importDocument{}, err
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return importDocument{}, err
+	}
+
+	var doc importDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return importDocument{}, err
+	}
+
+	return doc, nil
+}
+
+func (d *importDocument) item(source string) domain.WorkflowImportItem {
+	return domain.WorkflowImportItem{
+		Source:     source,
+		Name:       d.Name,
+		Version:    d.Version,
+		Definition: d.Definition,
+	}
+}
+
+func isDefinitionFile(name string) bool {
+	base := path.Base(name)
+	if strings.HasPrefix(base, ".") || strings.HasPrefix(name, "__MACOSX/") {
+		return false
+	}
+
+	switch path.Ext(base) {
+	case ".json", ".yaml", ".yml":
+		return true
+	default:
+		return false
+	}
+}
+
+func readLimited(r io.Reader) ([]byte, error) {
+	content, err := io.ReadAll(io.LimitReader(r, maxImportFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(content) > maxImportFileSize {
+		return nil, fmt.Errorf("file is larger than %d bytes", maxImportFileSize)
+	}
+
+	return content, nil
+}
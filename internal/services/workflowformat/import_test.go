@@ -0,0 +1,67 @@
+package workflowformat
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseImport_YAMLStream(t *testing.T) {
+	items, err := ParseImport("application/yaml", []byte(`
+name: orders
+version: 1
+definition: {start: a, steps: {a: {type: task}}}
+---
+name: orders
+version: 2
+definition:
+  start: a
+  steps:
+    a: {type: task}
+`), 10)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, "#1", items[1].Source)
+	assert.Equal(t, 2, items[1].Version)
+	assert.JSONEq(t, `{"start": "a", "steps": {"a": {"type": "task"}}}`, string(items[1].Definition))
+
+	_, err = ParseImport("application/yaml", []byte("name: a\n---\nname: b\n---\nname: c\n"), 2)
+	assert.ErrorIs(t, err, ErrInvalidImport)
+}
+
+func TestParseImport_Zip(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"export/orders/v3.yaml": "start: a\nsteps:\n  a:\n    type: task\n",
+		"billing.json":          `{"name": "billing", "version": 1, "definition": {"start": "a"}}`,
+		"README.md":             "ignored",
+	} {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+
+	items, err := ParseImport("application/zip", buf.Bytes(), 10)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+
+	byName := map[string]int{}
+	for _, item := range items {
+		byName[item.Name] = item.Version
+	}
+	assert.Equal(t, map[string]int{"orders": 3, "billing": 1}, byName)
+}
+
+func TestParseImport_Unsupported(t *testing.T) {
+	_, err := ParseImport("text/plain", []byte("x"), 10)
+	assert.ErrorIs(t, err, ErrInvalidImport)
+
+	_, err = ParseImport("application/json", []byte("[]"), 10)
+	assert.ErrorIs(t, err, ErrInvalidImport)
+}
@@ -0,0 +1,221 @@
+// Package workflowformat converts workflow documents between JSON, the storage format, and YAML.
+package workflowformat
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var ErrInvalidYAML = errors.New("invalid YAML document")
+
+// IsYAML reports whether the media type (a Content-Type or an Accept entry) denotes YAML.
+func IsYAML(mediaType string) bool {
+	mediaType, _, _ = strings.Cut(mediaType, ";")
+
+	switch strings.ToLower(strings.TrimSpace(mediaType)) {
+	case "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+		return true
+	default:
+		return false
+	}
+}
+
+// AcceptsYAML reports whether YAML is preferred by the Accept header: it is listed before JSON.
+func AcceptsYAML(accept string) bool {
+	for _, part := range strings.Split(accept, ",") {
+		if IsYAML(part) {
+			return true
+		}
+
+		mediaType, _, _ := strings.Cut(part, ";")
+		if strings.TrimSpace(mediaType) == "application/json" {
+			return false
+		}
+	}
+
+	return false
+}
+
+// YAMLToJSON converts a single YAML document to compact JSON.
+// Anchors and aliases are resolved, mapping keys must be scalars.
+func YAMLToJSON(data []byte) ([]byte, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+
+	var node yaml.Node
+	if err := decoder.Decode(&node); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidYAML, err)
+	}
+
+	if err := decoder.Decode(new(yaml.Node)); !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: a single document is expected", ErrInvalidYAML)
+	}
+
+	value, err := toJSONValue(&node)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return nil, fmt.Errorf("encode JSON: %w", err)
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// JSONToYAML converts a JSON document to block style YAML keeping the order of object keys.
+func JSONToYAML(data []byte) ([]byte, error) {
+	// YAML is a superset of JSON: the document parses as a flow style node
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, fmt.Errorf("decode JSON: %w", err)
+	}
+
+	blockStyle(&node)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return nil, fmt.Errorf("encode YAML: %w", err)
+	}
+
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("encode YAML: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// MarshalYAML encodes v as JSON first, so that json tags and marshalers apply, then converts it to YAML.
+func MarshalYAML(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("encode JSON: %w", err)
+	}
+
+	return JSONToYAML(data)
+}
+
+func blockStyle(node *yaml.Node) {
+	if node.Kind == yaml.MappingNode || node.Kind == yaml.SequenceNode {
+		node.Style &^= yaml.FlowStyle
+	}
+
+	// Strings are double-quoted in JSON, plain style is used where it keeps the value a string
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!str" {
+		node.Style &^= yaml.DoubleQuotedStyle
+	}
+
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+func toJSONValue(node *yaml.Node) (any, error) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+
+		return toJSONValue(node.Content[0])
+	case yaml.AliasNode:
+		return toJSONValue(node.Alias)
+	case yaml.MappingNode:
+		object := make(map[string]any, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+
+			// Merge keys (<<: *anchor) copy the keys of the referenced mappings
+			if key.Tag == "!!merge" {
+				if err := merge(object, value); err != nil {
+					return nil, err
+				}
+
+				continue
+			}
+
+			if key.Kind != yaml.ScalarNode {
+				return nil, fmt.Errorf("%w: line %d: mapping keys must be scalars", ErrInvalidYAML, key.Line)
+			}
+
+			item, err := toJSONValue(value)
+			if err != nil {
+				return nil, err
+			}
+
+			object[key.Value] = item
+		}
+
+		return object, nil
+	case yaml.SequenceNode:
+		array := make([]any, 0, len(node.Content))
+		for _, child := range node.Content {
+			item, err := toJSONValue(child)
+			if err != nil {
+				return nil, err
+			}
+
+			array = append(array, item)
+		}
+
+		return array, nil
+	default:
+		// Timestamps are kept as written rather than normalized to RFC 3339
+		if node.Tag == "!!timestamp" {
+			return node.Value, nil
+		}
+
+		var value any
+		if err := node.Decode(&value); err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidYAML, node.Line, err)
+		}
+
+		if f, ok := value.(float64); ok && (math.IsInf(f, 0) || math.IsNaN(f)) {
+			return nil, fmt.Errorf("%w: line %d: %s is not a JSON number", ErrInvalidYAML, node.Line, node.Value)
+		}
+
+		return value, nil
+	}
+}
+
+func merge(object map[string]any, node *yaml.Node) error {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+
+	sources := []*yaml.Node{node}
+	if node.Kind == yaml.SequenceNode {
+		sources = node.Content
+	}
+
+	for _, source := range sources {
+		value, err := toJSONValue(source)
+		if err != nil {
+			return err
+		}
+
+		mapping, ok := value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%w: line %d: merge value must be a mapping", ErrInvalidYAML, source.Line)
+		}
+
+		for key, item := range mapping {
+			if _, exists := object[key]; !exists {
+				object[key] = item
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package workflowformat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestYAMLToJSON(t *testing.T) {
+	data := []byte(`
+name: orders
+version: 2
+defaults: &retry
+  max_retries: 3
+definition:
+  start: fetch
+  steps:
+    fetch:
+      <<: *retry
+      type: task
+      handler: http_call
+      next: [notify]
+    notify:
+      <<: *retry
+      max_retries: 1
+      type: task
+      handler: "123"
+      metadata:
+        since: 2025-01-02
+`)
+
+	out, err := YAMLToJSON(data)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"name": "orders",
+		"version": 2,
+		"defaults": {"max_retries": 3},
+		"definition": {
+			"start": "fetch",
+			"steps": {
+				"fetch": {"max_retries": 3, "type": "task", "handler": "http_call", "next": ["notify"]},
+				"notify": {"max_retries": 1, "type": "task", "handler": "123", "metadata": {"since": "2025-01-02"}}
+			}
+		}
+	}`, string(out))
+
+	for _, invalid := range []string{"a: [", "a: 1\n---\nb: 2", "? [a]\n: 1", "a: .inf"} {
+		_, err := YAMLToJSON([]byte(invalid))
+		assert.ErrorIs(t, err, ErrInvalidYAML, invalid)
+	}
+}
+
+func TestJSONToYAML(t *testing.T) {
+	out, err := JSONToYAML([]byte(`{"start":"fetch","steps":{"fetch":{"type":"task","handler":"true","next":["b","c"]}},"n":1.5}`))
+	require.NoError(t, err)
+	assert.Equal(t, `start: fetch
+steps:
+  fetch:
+    type: task
+    handler: "true"
+    next:
+      - b
+      - c
+n: 1.5
+`, string(out))
+
+	back, err := YAMLToJSON(out)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"start":"fetch","steps":{"fetch":{"type":"task","handler":"true","next":["b","c"]}},"n":1.5}`,
+		string(back))
+}
+
+func TestAcceptsYAML(t *testing.T) {
+	assert.True(t, AcceptsYAML("application/yaml"))
+	assert.True(t, AcceptsYAML("text/yaml;q=0.9, application/json"))
+	assert.False(t, AcceptsYAML("application/json, application/yaml"))
+	assert.False(t, AcceptsYAML("*/*"))
+}
@@ -0,0 +1,131 @@
+package workflowsim
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/shopspring/decimal"
+)
+
+// The functions below mirror the condition evaluation of the floxy engine, which is not exported:
+// a condition is a text/template executed over the step input that renders true or false.
+
+var conditionFuncs = template.FuncMap{
+	"eq":        func(a, b any) bool { return equalValues(a, b) },
+	"ne":        func(a, b any) bool { return !equalValues(a, b) },
+	"gt":        func(a, b any) bool { return compareNumbers(a, b) > 0 },
+	"lt":        func(a, b any) bool { return compareNumbers(a, b) < 0 },
+	"ge":        func(a, b any) bool { return compareNumbers(a, b) >= 0 },
+	"le":        func(a, b any) bool { return compareNumbers(a, b) <= 0 },
+	"contains":  func(s, substr string) bool { return strings.Contains(s, substr) },
+	"hasPrefix": func(s, prefix string) bool { return strings.HasPrefix(s, prefix) },
+	"hasSuffix": func(s, suffix string) bool { return strings.HasSuffix(s, suffix) },
+}
+
+func evaluateCondition(expr string, input json.RawMessage) (bool, error) {
+	tpl, err := template.New("condition").Funcs(conditionFuncs).Parse(expr)
+	if err != nil {
+		return false, fmt.Errorf("parse condition: %w", err)
+	}
+
+	// Like the engine, a non-object input leaves the data empty
+	var data map[string]any
+	_ = json.Unmarshal(input, &data)
+	if data == nil {
+		data = make(map[string]any)
+	}
+
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, data); err != nil {
+		return false, fmt.Errorf("execute condition: %w", err)
+	}
+
+	result := strings.TrimSpace(buf.String())
+	value, err := strconv.ParseBool(result)
+	if err != nil {
+		return false, fmt.Errorf("invalid condition output: %q", result)
+	}
+
+	return value, nil
+}
+
+func equalValues(a, b any) bool {
+	if a == nil || b == nil {
+		if a == nil && b == nil {
+			return true
+		}
+
+		other := a
+		if a == nil {
+			other = b
+		}
+
+		dec, err := toDecimal(other)
+
+		return err == nil && dec.IsZero()
+	}
+
+	aDec, aErr := toDecimal(a)
+	bDec, bErr := toDecimal(b)
+
+	if aErr == nil && bErr == nil {
+		return aDec.Equal(bDec)
+	}
+
+	if (aErr == nil) != (bErr == nil) {
+		return false
+	}
+
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+// compareNumbers treats non-numeric values as 0.
+func compareNumbers(a, b any) int {
+	aDec, err := toDecimal(a)
+	if err != nil {
+		aDec = decimal.Zero
+	}
+
+	bDec, err := toDecimal(b)
+	if err != nil {
+		bDec = decimal.Zero
+	}
+
+	return aDec.Cmp(bDec)
+}
+
+func toDecimal(v any) (decimal.Decimal, error) {
+	if v == nil {
+		return decimal.Zero, nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return decimal.NewFromInt(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return decimal.NewFromString(strconv.FormatUint(rv.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		return decimal.NewFromFloat(rv.Float()), nil
+	case reflect.String:
+		s := strings.TrimSpace(rv.String())
+		if s == "" {
+			return decimal.Zero, nil
+		}
+
+		return decimal.NewFromString(s)
+	case reflect.Bool:
+		if rv.Bool() {
+			return decimal.NewFromInt(1), nil
+		}
+
+		return decimal.Zero, nil
+	default:
+		return decimal.Zero, fmt.Errorf("cannot convert %T to decimal", v)
+	}
+}
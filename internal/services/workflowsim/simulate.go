@@ -0,0 +1,292 @@
+// Package workflowsim predicts the execution path of a workflow definition for a given input,
+// following the routing rules of the floxy engine without running handlers.
+package workflowsim
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	floxy "github.com/rom8726/floxy-pro"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+// MaxSteps bounds the steps walked by a simulation, definitions may loop through conditions.
+const MaxSteps = 1000
+
+var ErrInvalidSimulation = errors.New("invalid simulation")
+
+type pendingStep struct {
+	name  string
+	input json.RawMessage
+	// force runs a join without waiting for its steps
+	force bool
+}
+
+type simulator struct {
+	graph  floxy.GraphDefinition
+	params domain.WorkflowSimulationInput
+	result domain.WorkflowSimulation
+
+	queue []pendingStep
+	// outputs holds the outputs of the completed steps, for joins
+	outputs map[string]json.RawMessage
+	// joins are the joins reached whose awaited steps are not all completed yet
+	joins  []pendingStep
+	joined map[string]bool
+}
+
+// Simulate walks the definition from its start step with the input of params.
+func Simulate(definition json.RawMessage, params domain.WorkflowSimulationInput) (domain.WorkflowSimulation, error) {
+	var graph floxy.GraphDefinition
+	if err := json.Unmarshal(definition, &graph); err != nil {
+		return domain.WorkflowSimulation{}, fmt.Errorf("%w: malformed definition: %v", ErrInvalidSimulation, err)
+	}
+
+	if _, ok := graph.Steps[graph.Start]; !ok {
+		return domain.WorkflowSimulation{}, fmt.Errorf("%w: start step %q is not defined",
+			ErrInvalidSimulation, graph.Start)
+	}
+
+	input := params.Input
+	if len(input) == 0 {
+		input = json.RawMessage(`{}`)
+	}
+
+	if !json.Valid(input) {
+		return domain.WorkflowSimulation{}, fmt.Errorf("%w: input is not valid JSON", ErrInvalidSimulation)
+	}
+
+	for name, decision := range params.Decisions {
+		if decision != string(floxy.HumanDecisionConfirmed) && decision != string(floxy.HumanDecisionRejected) {
+			return domain.WorkflowSimulation{}, fmt.Errorf("%w: decision of %q must be confirmed or rejected",
+				ErrInvalidSimulation, name)
+		}
+	}
+
+	s := &simulator{
+		graph:  graph,
+		params: params,
+		result: domain.WorkflowSimulation{
+			Path:           []domain.WorkflowSimulationStep{},
+			DecisionPoints: []domain.WorkflowSimulationDecision{},
+			Warnings:       []string{},
+		},
+		queue:   []pendingStep{{name: graph.Start, input: input}},
+		outputs: make(map[string]json.RawMessage),
+		joined:  make(map[string]bool),
+	}
+
+	s.run()
+
+	return s.result, nil
+}
+
+func (s *simulator) run() {
+	for {
+		if len(s.queue) == 0 {
+			if len(s.joins) == 0 {
+				s.result.Outcome = domain.SimulationCompleted
+
+				return
+			}
+
+			// Nothing else can complete: the awaited steps are not on the predicted path
+			join := s.joins[0]
+			s.joins = s.joins[1:]
+			s.result.Warnings = append(s.result.Warnings, fmt.Sprintf(
+				"join %q waits for steps that are not on the predicted path: %v",
+				join.name, s.missing(join.name)))
+			join.force = true
+			s.queue = append(s.queue, join)
+		}
+
+		if len(s.result.Path) >= MaxSteps {
+			s.result.Outcome = domain.SimulationStepLimit
+
+			return
+		}
+
+		current := s.queue[0]
+		s.queue = s.queue[1:]
+
+		def := s.graph.Steps[current.name]
+		if def == nil {
+			s.result.Warnings = append(s.result.Warnings, fmt.Sprintf("step %q is not defined", current.name))
+			s.result.Outcome = domain.SimulationFailed
+
+			return
+		}
+
+		if def.Type == floxy.StepTypeJoin && !s.reachJoin(current) {
+			continue
+		}
+
+		if outcome := s.execute(current, def); outcome != "" {
+			s.result.Outcome = outcome
+
+			return
+		}
+	}
+}
+
+// reachJoin defers a join until the steps it waits for are completed. It reports whether the join runs now.
+func (s *simulator) reachJoin(join pendingStep) bool {
+	if s.joined[join.name] {
+		return false
+	}
+
+	if !join.force && len(s.missing(join.name)) > 0 && !s.anyJoined(join.name) {
+		if !slices.ContainsFunc(s.joins, func(p pendingStep) bool { return p.name == join.name }) {
+			s.joins = append(s.joins, join)
+		}
+
+		return false
+	}
+
+	s.joined[join.name] = true
+	s.joins = slices.DeleteFunc(s.joins, func(p pendingStep) bool { return p.name == join.name })
+
+	return true
+}
+
+func (s *simulator) missing(join string) []string {
+	var missing []string
+	for _, name := range s.graph.Steps[join].WaitFor {
+		if _, ok := s.outputs[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+
+	return missing
+}
+
+// anyJoined reports whether a join with the "any" strategy has one of its steps completed.
+func (s *simulator) anyJoined(join string) bool {
+	def := s.graph.Steps[join]
+	if def.JoinStrategy != floxy.JoinStrategyAny {
+		return false
+	}
+
+	return len(s.missing(join)) < len(def.WaitFor)
+}
+
+// execute records the step and queues the next ones. It returns a non-empty outcome when the walk stops.
+func (s *simulator) execute(current pendingStep, def *floxy.StepDefinition) string {
+	step := domain.WorkflowSimulationStep{
+		Name:    current.name,
+		Type:    string(def.Type),
+		Handler: def.Handler,
+		Input:   current.input,
+		Next:    []string{},
+	}
+
+	output := current.input
+	next := def.Next
+
+	switch def.Type {
+	case floxy.StepTypeTask:
+		if mocked, ok := s.params.StepOutputs[current.name]; ok {
+			output = mocked
+			step.OutputMocked = true
+		}
+	case floxy.StepTypeCondition:
+		result, err := evaluateCondition(def.Condition, current.input)
+		if err != nil {
+			step.Error = err.Error()
+			s.result.Path = append(s.result.Path, step)
+
+			return domain.SimulationFailed
+		}
+
+		step.ConditionResult = &result
+		if !result {
+			next = nil
+			if def.Else != "" {
+				next = []string{def.Else}
+			}
+		}
+	case floxy.StepTypeHuman:
+		decision, ok := s.params.Decisions[current.name]
+		if !ok {
+			decision = string(floxy.HumanDecisionConfirmed)
+		}
+
+		s.result.DecisionPoints = append(s.result.DecisionPoints, domain.WorkflowSimulationDecision{
+			Step:     current.name,
+			Decision: decision,
+			Assumed:  !ok,
+		})
+
+		if decision == string(floxy.HumanDecisionRejected) {
+			s.result.Path = append(s.result.Path, step)
+
+			return domain.SimulationAborted
+		}
+
+		output = json.RawMessage(`{"status":"confirmed"}`)
+	case floxy.StepTypeFork, floxy.StepTypeParallel:
+		for _, name := range def.Parallel {
+			s.queue = append(s.queue, pendingStep{name: name, input: current.input})
+			step.Next = append(step.Next, name)
+		}
+	case floxy.StepTypeJoin:
+		output = s.joinOutput(current.name)
+	case floxy.StepTypeSavePoint:
+		// Passes its input through
+	default:
+		step.Error = fmt.Sprintf("unsupported step type %q", def.Type)
+		s.result.Path = append(s.result.Path, step)
+
+		return domain.SimulationFailed
+	}
+
+	for _, name := range next {
+		s.queue = append(s.queue, pendingStep{name: name, input: output})
+		step.Next = append(step.Next, name)
+	}
+
+	step.Output = output
+	s.outputs[current.name] = output
+	s.result.Path = append(s.result.Path, step)
+
+	// A completed step may release a deferred join
+	for _, join := range s.joins {
+		if len(s.missing(join.name)) == 0 || s.anyJoined(join.name) {
+			s.queue = append(s.queue, join)
+		}
+	}
+
+	return ""
+}
+
+// joinOutput builds the output of a join like the engine does.
+func (s *simulator) joinOutput(join string) json.RawMessage {
+	def := s.graph.Steps[join]
+
+	strategy := def.JoinStrategy
+	if strategy == "" {
+		strategy = floxy.JoinStrategyAll
+	}
+
+	completed := []string{}
+	outputs := make(map[string]json.RawMessage)
+	for _, name := range def.WaitFor {
+		if output, ok := s.outputs[name]; ok {
+			completed = append(completed, name)
+			outputs[name] = output
+		}
+	}
+
+	output, _ := json.Marshal(map[string]any{
+		"completed": completed,
+		"failed":    []string{},
+		"strategy":  strategy,
+		"outputs":   outputs,
+		"status":    "success",
+	})
+
+	return output
+}
@@ -0,0 +1,110 @@
+package workflowsim
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+const testDefinition = `{
+  "start": "check",
+  "steps": {
+    "check": {"type": "condition", "condition": "{{ gt .amount 100 }}", "next": ["approve"], "else": "charge"},
+    "approve": {"type": "human", "next": ["charge"]},
+    "charge": {"type": "task", "handler": "charge", "next": ["notify"]},
+    "notify": {"type": "fork", "parallel": ["email", "sms"], "next": ["done"]},
+    "email": {"type": "task", "handler": "email", "next": ["done"]},
+    "sms": {"type": "task", "handler": "sms", "next": ["done"]},
+    "done": {"type": "join", "wait_for": ["email", "sms"]}
+  }
+}`
+
+func pathNames(sim domain.WorkflowSimulation) []string {
+	names := make([]string, 0, len(sim.Path))
+	for _, step := range sim.Path {
+		names = append(names, step.Name)
+	}
+
+	return names
+}
+
+func TestSimulate(t *testing.T) {
+	sim, err := Simulate(json.RawMessage(testDefinition), domain.WorkflowSimulationInput{
+		Input: json.RawMessage(`{"amount": 50}`),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, domain.SimulationCompleted, sim.Outcome)
+	assert.Equal(t, []string{"check", "charge", "notify", "email", "sms", "done"}, pathNames(sim))
+	require.NotNil(t, sim.Path[0].ConditionResult)
+	assert.False(t, *sim.Path[0].ConditionResult)
+	assert.Empty(t, sim.DecisionPoints)
+	assert.Empty(t, sim.Warnings)
+
+	sim, err = Simulate(json.RawMessage(testDefinition), domain.WorkflowSimulationInput{
+		Input:       json.RawMessage(`{"amount": 500}`),
+		StepOutputs: map[string]json.RawMessage{"charge": json.RawMessage(`{"charged": true}`)},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"check", "approve", "charge", "notify", "email", "sms", "done"}, pathNames(sim))
+	assert.Equal(t, []domain.WorkflowSimulationDecision{{Step: "approve", Decision: "confirmed", Assumed: true}},
+		sim.DecisionPoints)
+	assert.True(t, sim.Path[2].OutputMocked)
+	assert.JSONEq(t, `{"charged": true}`, string(sim.Path[3].Input))
+}
+
+func TestSimulate_Stops(t *testing.T) {
+	sim, err := Simulate(json.RawMessage(testDefinition), domain.WorkflowSimulationInput{
+		Input:     json.RawMessage(`{"amount": 500}`),
+		Decisions: map[string]string{"approve": "rejected"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, domain.SimulationAborted, sim.Outcome)
+	assert.Equal(t, []string{"check", "approve"}, pathNames(sim))
+
+	sim, err = Simulate(json.RawMessage(`{"start": "a", "steps": {"a": {"type": "condition", "condition": "{{ .x"}}}`),
+		domain.WorkflowSimulationInput{})
+	require.NoError(t, err)
+	assert.Equal(t, domain.SimulationFailed, sim.Outcome)
+	assert.NotEmpty(t, sim.Path[0].Error)
+
+	loop := `{"start": "a", "steps": {"a": {"type": "task", "next": ["b"]}, "b": {"type": "save_point", "next": ["a"]}}}`
+	sim, err = Simulate(json.RawMessage(loop), domain.WorkflowSimulationInput{})
+	require.NoError(t, err)
+	assert.Equal(t, domain.SimulationStepLimit, sim.Outcome)
+	assert.Len(t, sim.Path, MaxSteps)
+
+	_, err = Simulate(json.RawMessage(testDefinition), domain.WorkflowSimulationInput{
+		Decisions: map[string]string{"approve": "maybe"},
+	})
+	assert.ErrorIs(t, err, ErrInvalidSimulation)
+}
+
+func TestEvaluateCondition(t *testing.T) {
+	tests := []struct {
+		expr  string
+		input string
+		want  bool
+	}{
+		{`{{ eq .status "ok" }}`, `{"status": "ok"}`, true},
+		{`{{ eq .amount 10 }}`, `{"amount": 10.0}`, true},
+		{`{{ eq .amount "10" }}`, `{"amount": 10}`, true},
+		{`{{ lt .amount 5 }}`, `{"amount": 10}`, false},
+		{`{{ eq .missing 0 }}`, `{}`, true},
+		{`{{ hasPrefix .id "ord-" }}`, `{"id": "ord-1"}`, true},
+	}
+
+	for _, tt := range tests {
+		got, err := evaluateCondition(tt.expr, json.RawMessage(tt.input))
+		require.NoError(t, err, tt.expr)
+		assert.Equal(t, tt.want, got, tt.expr)
+	}
+
+	_, err := evaluateCondition(`{{ .amount }}`, json.RawMessage(`{"amount": 3}`))
+	assert.Error(t, err)
+}
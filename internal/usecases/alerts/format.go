@@ -0,0 +1,63 @@
+package alerts
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+// alertsNotification renders a single alert or a digest of alerts about project workflow instances.
+func alertsNotification(project domain.Project, alerts []domain.Alert) domain.Notification {
+	subject := fmt.Sprintf("[%s] %s", project.Name, alertTitle(alerts[0].Event))
+	if len(alerts) > 1 {
+		subject = fmt.Sprintf("[%s] %d workflow alerts", project.Name, len(alerts))
+	}
+
+	var details strings.Builder
+	for _, alert := range alerts {
+		event := alert.Event
+
+		fmt.Fprintf(&details, "- %s\n", alertTitle(event))
+		fmt.Fprintf(&details, "  Workflow: %s\n", event.WorkflowID)
+		fmt.Fprintf(&details, "  Instance: %d\n", event.InstanceID)
+
+		if event.Type == domain.EventLongRunningInstance {
+			fmt.Fprintf(&details, "  Running since: %s\n", event.OccurredAt.UTC().Format(time.RFC3339))
+		} else {
+			fmt.Fprintf(&details, "  Time: %s\n", event.OccurredAt.UTC().Format(time.RFC3339))
+		}
+
+		if event.Error != nil && *event.Error != "" {
+			fmt.Fprintf(&details, "  Error: %s\n", *event.Error)
+		}
+
+		details.WriteString("\n")
+	}
+
+	text := fmt.Sprintf(`
+Hello,
+
+The following workflow alerts were raised in project "%s":
+
+%s
+You receive this email because of your role in the project.
+
+Best regards,
+Floxy Manager Team
+`, project.Name, details.String())
+
+	return domain.Notification{Subject: subject, Text: text}
+}
+
+func alertTitle(event domain.LifecycleEvent) string {
+	switch event.Type {
+	case domain.EventInstanceFailed:
+		return "Workflow instance failed"
+	case domain.EventLongRunningInstance:
+		return "Workflow instance exceeded the duration threshold"
+	default:
+		return "Workflow event: " + string(event.Type)
+	}
+}
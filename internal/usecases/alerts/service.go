@@ -0,0 +1,362 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.AlertsUseCase = (*Service)(nil)
+
+var ErrInvalidAlertSettings = errors.New("invalid alert settings")
+
+const (
+	digestInterval = time.Hour
+	sendBatchSize  = 100
+)
+
+type Service struct {
+	tx              db.TxManager
+	alertsRepo      contract.AlertsRepository
+	eventsRepo      contract.LifecycleEventsRepository
+	projectsRepo    contract.ProjectsRepository
+	membershipsRepo contract.MembershipsRepository
+	rolesRepo       contract.RolesRepository
+	usersRepo       contract.UsersRepository
+	preferencesRepo contract.UserPreferencesRepository
+	notifier        contract.NotificationRouter
+}
+
+// recipient is a user receiving the alerts of a project.
+type recipient struct {
+	email       string
+	preferences domain.UserPreferences
+}
+
+func New(
+	tx db.TxManager,
+	alertsRepo contract.AlertsRepository,
+	eventsRepo contract.LifecycleEventsRepository,
+	projectsRepo contract.ProjectsRepository,
+	membershipsRepo contract.MembershipsRepository,
+	rolesRepo contract.RolesRepository,
+	usersRepo contract.UsersRepository,
+	preferencesRepo contract.UserPreferencesRepository,
+	notifier contract.NotificationRouter,
+) *Service {
+	return &Service{
+		tx:              tx,
+		alertsRepo:      alertsRepo,
+		eventsRepo:      eventsRepo,
+		projectsRepo:    projectsRepo,
+		membershipsRepo: membershipsRepo,
+		rolesRepo:       rolesRepo,
+		usersRepo:       usersRepo,
+		preferencesRepo: preferencesRepo,
+		notifier:        notifier,
+	}
+}
+
+// GetSettings returns the project alert settings, falling back to defaults if they were never saved.
+func (s *Service) GetSettings(ctx context.Context, projectID domain.ProjectID) (domain.AlertSettings, error) {
+	settings, err := s.alertsRepo.GetSettings(ctx, projectID)
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			return domain.AlertSettings{
+				ProjectID:        projectID,
+				RecipientRoleKey: domain.DefaultAlertRecipientRole,
+				NotifyOnFailure:  true,
+			}, nil
+		}
+
+		return domain.AlertSettings{}, fmt.Errorf("get alert settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+func (s *Service) UpdateSettings(ctx context.Context, settings domain.AlertSettings) (domain.AlertSettings, error) {
+	if settings.RecipientRoleKey == "" {
+		settings.RecipientRoleKey = domain.DefaultAlertRecipientRole
+	}
+
+	if settings.DurationThresholdSeconds != nil && *settings.DurationThresholdSeconds <= 0 {
+		return domain.AlertSettings{}, fmt.Errorf("%w: duration_threshold_seconds must be positive",
+			ErrInvalidAlertSettings)
+	}
+
+	if _, err := s.rolesRepo.GetByKey(ctx, settings.RecipientRoleKey); err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			return domain.AlertSettings{}, fmt.Errorf("%w: unknown role %q",
+				ErrInvalidAlertSettings, settings.RecipientRoleKey)
+		}
+
+		return domain.AlertSettings{}, fmt.Errorf("get role: %w", err)
+	}
+
+	var saved domain.AlertSettings
+	err := s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		if err := s.alertsRepo.SaveSettings(ctx, settings); err != nil {
+			return err
+		}
+
+		var err error
+		saved, err = s.alertsRepo.GetSettings(ctx, settings.ProjectID)
+
+		return err
+	})
+	if err != nil {
+		return domain.AlertSettings{}, fmt.Errorf("update alert settings: %w", err)
+	}
+
+	return saved, nil
+}
+
+// HandleEvents queues alerts for failed instances of projects with failure alerts enabled.
+func (s *Service) HandleEvents(ctx context.Context, events []domain.LifecycleEvent) error {
+	var settingsByProject map[domain.ProjectID]domain.AlertSettings
+
+	for i := range events {
+		event := events[i]
+		if event.Type != domain.EventInstanceFailed {
+			continue
+		}
+
+		if settingsByProject == nil {
+			var err error
+			settingsByProject, err = s.enabledSettings(ctx)
+			if err != nil {
+				return err
+			}
+		}
+
+		settings, ok := settingsByProject[event.ProjectID]
+		if !ok || !settings.NotifyOnFailure {
+			continue
+		}
+
+		if _, err := s.alertsRepo.EnqueueAlert(ctx, event.ProjectID, event); err != nil {
+			return fmt.Errorf("enqueue alert: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) CheckLongRunning(ctx context.Context, now time.Time) error {
+	settingsList, err := s.alertsRepo.ListEnabledSettings(ctx)
+	if err != nil {
+		return fmt.Errorf("list alert settings: %w", err)
+	}
+
+	for i := range settingsList {
+		settings := settingsList[i]
+		if settings.DurationThresholdSeconds == nil {
+			continue
+		}
+
+		startedBefore := now.Add(-time.Duration(*settings.DurationThresholdSeconds) * time.Second)
+
+		events, err := s.eventsRepo.ListLongRunning(ctx, settings.ProjectID, startedBefore)
+		if err != nil {
+			return fmt.Errorf("list long running instances: %w", err)
+		}
+
+		for j := range events {
+			if _, err := s.alertsRepo.EnqueueAlert(ctx, settings.ProjectID, events[j]); err != nil {
+				return fmt.Errorf("enqueue alert: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) SendPending(ctx context.Context, now time.Time) (int, error) {
+	settingsList, err := s.alertsRepo.ListEnabledSettings(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("list alert settings: %w", err)
+	}
+
+	sent := 0
+	for i := range settingsList {
+		settings := settingsList[i]
+		if !digestDue(settings, now) {
+			continue
+		}
+
+		count, err := s.sendProjectAlerts(ctx, settings, now)
+		if err != nil {
+			return sent, fmt.Errorf("send project %d alerts: %w", settings.ProjectID, err)
+		}
+
+		sent += count
+	}
+
+	return sent, nil
+}
+
+func (s *Service) sendProjectAlerts(ctx context.Context, settings domain.AlertSettings, now time.Time) (int, error) {
+	alerts, err := s.alertsRepo.ListPendingAlerts(ctx, settings.ProjectID, sendBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("list pending alerts: %w", err)
+	}
+
+	if len(alerts) == 0 {
+		return 0, nil
+	}
+
+	project, err := s.projectsRepo.GetByID(ctx, settings.ProjectID)
+	if err != nil {
+		return 0, fmt.Errorf("get project: %w", err)
+	}
+
+	recipients, err := s.recipients(ctx, settings)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(recipients) == 0 {
+		slog.Warn("No recipients for workflow alerts",
+			"project_id", settings.ProjectID,
+			"role", settings.RecipientRoleKey,
+		)
+	}
+
+	// In digest mode all pending alerts go out in one email; otherwise each alert is sent separately.
+	batches := [][]domain.Alert{alerts}
+	if !settings.DigestEnabled {
+		batches = make([][]domain.Alert, 0, len(alerts))
+		for i := range alerts {
+			batches = append(batches, alerts[i:i+1])
+		}
+	}
+
+	for _, batch := range batches {
+		for _, to := range recipients {
+			userBatch := optedIn(batch, to.preferences)
+			if len(userBatch) == 0 {
+				continue
+			}
+
+			target := domain.NotificationTarget{Kind: domain.NotificationTargetEmail, Address: to.email}
+			if err := s.notifier.Notify(ctx, target, alertsNotification(project, userBatch)); err != nil {
+				slog.Error("Failed to send workflow alerts email",
+					"error", err,
+					"project_id", settings.ProjectID,
+					"email", to.email,
+				)
+			}
+		}
+	}
+
+	ids := make([]int64, 0, len(alerts))
+	for i := range alerts {
+		ids = append(ids, alerts[i].ID)
+	}
+
+	if err := s.alertsRepo.MarkAlertsSent(ctx, ids, now); err != nil {
+		return 0, err
+	}
+
+	if settings.DigestEnabled {
+		if err := s.alertsRepo.SaveDigestTime(ctx, settings.ProjectID, now); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(alerts), nil
+}
+
+// recipients returns active project members with the configured role along with their preferences.
+func (s *Service) recipients(ctx context.Context, settings domain.AlertSettings) ([]recipient, error) {
+	memberships, err := s.membershipsRepo.ListForProject(ctx, settings.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("list project memberships: %w", err)
+	}
+
+	userIDs := make([]domain.UserID, 0, len(memberships))
+	for i := range memberships {
+		if memberships[i].RoleKey == settings.RecipientRoleKey {
+			userIDs = append(userIDs, memberships[i].UserID)
+		}
+	}
+
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	users, err := s.usersRepo.FetchByIDs(ctx, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("fetch users: %w", err)
+	}
+
+	preferencesList, err := s.preferencesRepo.ListByUserIDs(ctx, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("list user preferences: %w", err)
+	}
+
+	preferencesByUser := make(map[domain.UserID]domain.UserPreferences, len(preferencesList))
+	for i := range preferencesList {
+		preferencesByUser[preferencesList[i].UserID] = preferencesList[i]
+	}
+
+	recipients := make([]recipient, 0, len(users))
+	for i := range users {
+		if users[i].IsActive && users[i].Email != "" {
+			preferences, ok := preferencesByUser[users[i].ID]
+			if !ok {
+				preferences = domain.DefaultUserPreferences(users[i].ID)
+			}
+
+			recipients = append(recipients, recipient{email: users[i].Email, preferences: preferences})
+		}
+	}
+
+	return recipients, nil
+}
+
+// optedIn returns the alerts about events the user did not opt out of.
+func optedIn(alerts []domain.Alert, preferences domain.UserPreferences) []domain.Alert {
+	if len(preferences.NotificationOptOuts) == 0 {
+		return alerts
+	}
+
+	filtered := make([]domain.Alert, 0, len(alerts))
+	for i := range alerts {
+		if !preferences.OptedOut(alerts[i].Event.Type) {
+			filtered = append(filtered, alerts[i])
+		}
+	}
+
+	return filtered
+}
+
+func (s *Service) enabledSettings(ctx context.Context) (map[domain.ProjectID]domain.AlertSettings, error) {
+	settingsList, err := s.alertsRepo.ListEnabledSettings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list alert settings: %w", err)
+	}
+
+	settingsByProject := make(map[domain.ProjectID]domain.AlertSettings, len(settingsList))
+	for i := range settingsList {
+		settingsByProject[settingsList[i].ProjectID] = settingsList[i]
+	}
+
+	return settingsByProject, nil
+}
+
+// digestDue reports whether queued alerts of the project may be sent now.
+func digestDue(settings domain.AlertSettings, now time.Time) bool {
+	if !settings.DigestEnabled || settings.LastDigestAt == nil {
+		return true
+	}
+
+	return !now.Before(settings.LastDigestAt.Add(digestInterval))
+}
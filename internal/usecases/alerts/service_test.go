@@ -0,0 +1,48 @@
+package alerts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+func TestDigestDue(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	recent := now.Add(-30 * time.Minute)
+	old := now.Add(-time.Hour)
+
+	tests := []struct {
+		name     string
+		settings domain.AlertSettings
+		want     bool
+	}{
+		{name: "digest disabled", settings: domain.AlertSettings{LastDigestAt: &recent}, want: true},
+		{name: "first digest", settings: domain.AlertSettings{DigestEnabled: true}, want: true},
+		{name: "digest sent recently", settings: domain.AlertSettings{DigestEnabled: true, LastDigestAt: &recent}, want: false},
+		{name: "digest interval passed", settings: domain.AlertSettings{DigestEnabled: true, LastDigestAt: &old}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, digestDue(tt.settings, now))
+		})
+	}
+}
+
+func TestOptedIn(t *testing.T) {
+	failed := domain.Alert{ID: 1, Event: domain.LifecycleEvent{Type: domain.EventInstanceFailed}}
+	longRunning := domain.Alert{ID: 2, Event: domain.LifecycleEvent{Type: domain.EventLongRunningInstance}}
+	alerts := []domain.Alert{failed, longRunning}
+
+	preferences := domain.DefaultUserPreferences(1)
+	assert.Equal(t, alerts, optedIn(alerts, preferences))
+
+	preferences.NotificationOptOuts = []domain.LifecycleEventType{domain.EventLongRunningInstance}
+	assert.Equal(t, []domain.Alert{failed}, optedIn(alerts, preferences))
+
+	preferences.NotificationOptOuts = append(preferences.NotificationOptOuts, domain.EventInstanceFailed)
+	assert.Empty(t, optedIn(alerts, preferences))
+}
@@ -0,0 +1,165 @@
+package apitokens
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.APITokensUseCase = (*Service)(nil)
+
+var ErrInvalidAPIToken = errors.New("invalid api token")
+
+const (
+	tokenBytes        = 32
+	tokenPrefixLength = len(domain.APITokenPrefix) + 8
+	maxNameLength     = 128
+)
+
+type Service struct {
+	tx         db.TxManager
+	tokensRepo contract.APITokensRepository
+	usersRepo  contract.UsersRepository
+}
+
+func New(
+	tx db.TxManager,
+	tokensRepo contract.APITokensRepository,
+	usersRepo contract.UsersRepository,
+) *Service {
+	return &Service{
+		tx:         tx,
+		tokensRepo: tokensRepo,
+		usersRepo:  usersRepo,
+	}
+}
+
+func (s *Service) Create(
+	ctx context.Context,
+	userID domain.UserID,
+	dto domain.APITokenDTO,
+) (domain.APIToken, string, error) {
+	if err := validate(&dto, time.Now()); err != nil {
+		return domain.APIToken{}, "", err
+	}
+
+	user, err := s.usersRepo.GetByID(ctx, userID)
+	if err != nil {
+		return domain.APIToken{}, "", fmt.Errorf("get user: %w", err)
+	}
+
+	if !user.EmailVerified() {
+		return domain.APIToken{}, "", domain.ErrEmailNotVerified
+	}
+
+	plain, err := generateToken()
+	if err != nil {
+		return domain.APIToken{}, "", err
+	}
+
+	var token domain.APIToken
+	err = s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		var err error
+		token, err = s.tokensRepo.Create(ctx, userID, dto, hashToken(plain), plain[:tokenPrefixLength])
+
+		return err
+	})
+	if err != nil {
+		return domain.APIToken{}, "", fmt.Errorf("create api token: %w", err)
+	}
+
+	return token, plain, nil
+}
+
+func (s *Service) List(ctx context.Context, userID domain.UserID) ([]domain.APIToken, error) {
+	return s.tokensRepo.List(ctx, userID)
+}
+
+func (s *Service) Delete(ctx context.Context, userID domain.UserID, id domain.APITokenID) error {
+	return s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		return s.tokensRepo.Delete(ctx, userID, id)
+	})
+}
+
+func (s *Service) Authenticate(ctx context.Context, token string) (domain.User, domain.APIToken, error) {
+	if !strings.HasPrefix(token, domain.APITokenPrefix) {
+		return domain.User{}, domain.APIToken{}, domain.ErrInvalidToken
+	}
+
+	apiToken, err := s.tokensRepo.GetByHash(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			return domain.User{}, domain.APIToken{}, domain.ErrInvalidToken
+		}
+
+		return domain.User{}, domain.APIToken{}, fmt.Errorf("get api token: %w", err)
+	}
+
+	now := time.Now()
+	if apiToken.Expired(now) {
+		return domain.User{}, domain.APIToken{}, fmt.Errorf("%w: token expired", domain.ErrInvalidToken)
+	}
+
+	user, err := s.usersRepo.GetByID(ctx, apiToken.UserID)
+	if err != nil {
+		return domain.User{}, domain.APIToken{}, fmt.Errorf("get token owner: %w", err)
+	}
+
+	if !user.IsActive {
+		return domain.User{}, domain.APIToken{}, domain.ErrInactiveUser
+	}
+
+	if err := s.tokensRepo.TouchLastUsed(ctx, apiToken.ID, now); err != nil {
+		slog.WarnContext(ctx, "Failed to update api token last used time", "error", err, "token_id", apiToken.ID)
+	}
+
+	return user, apiToken, nil
+}
+
+func generateToken() (string, error) {
+	buf := make([]byte, tokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate api token: %w", err)
+	}
+
+	return domain.APITokenPrefix + hex.EncodeToString(buf), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func validate(dto *domain.APITokenDTO, now time.Time) error {
+	dto.Name = strings.TrimSpace(dto.Name)
+	if dto.Name == "" || len(dto.Name) > maxNameLength {
+		return fmt.Errorf("%w: name is required and must be at most %d characters", ErrInvalidAPIToken, maxNameLength)
+	}
+
+	if len(dto.Scopes) == 0 {
+		return fmt.Errorf("%w: at least one scope is required", ErrInvalidAPIToken)
+	}
+
+	for _, scope := range dto.Scopes {
+		if !scope.Valid() {
+			return fmt.Errorf("%w: unsupported scope %q", ErrInvalidAPIToken, scope)
+		}
+	}
+
+	if dto.ExpiresAt != nil && !dto.ExpiresAt.After(now) {
+		return fmt.Errorf("%w: expires_at must be in the future", ErrInvalidAPIToken)
+	}
+
+	return nil
+}
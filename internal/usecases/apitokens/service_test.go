@@ -0,0 +1,53 @@
+package apitokens
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+func TestGenerateToken(t *testing.T) {
+	token, err := generateToken()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(token, domain.APITokenPrefix))
+	assert.Len(t, hashToken(token), 64)
+
+	other, err := generateToken()
+	require.NoError(t, err)
+	assert.NotEqual(t, token, other)
+	assert.NotEqual(t, hashToken(token), hashToken(other))
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+
+	dto := domain.APITokenDTO{Name: " ci ", Scopes: []domain.APITokenScope{domain.APITokenScopeRead}}
+	require.NoError(t, validate(&dto, now))
+	assert.Equal(t, "ci", dto.Name)
+
+	for _, dto := range []domain.APITokenDTO{
+		{Scopes: []domain.APITokenScope{domain.APITokenScopeRead}},
+		{Name: "ci"},
+		{Name: "ci", Scopes: []domain.APITokenScope{"admin"}},
+		{Name: "ci", Scopes: []domain.APITokenScope{domain.APITokenScopeWrite}, ExpiresAt: &past},
+	} {
+		assert.ErrorIs(t, validate(&dto, now), ErrInvalidAPIToken)
+	}
+}
+
+func TestAPITokenAllows(t *testing.T) {
+	read := domain.APIToken{Scopes: []domain.APITokenScope{domain.APITokenScopeRead}}
+	assert.True(t, read.Allows(http.MethodGet))
+	assert.False(t, read.Allows(http.MethodPost))
+
+	write := domain.APIToken{Scopes: []domain.APITokenScope{domain.APITokenScopeWrite}}
+	assert.True(t, write.Allows(http.MethodGet))
+	assert.True(t, write.Allows(http.MethodDelete))
+}
@@ -0,0 +1,245 @@
+// Package auditsinks forwards the audit log to external systems (SIEM) in addition to Postgres.
+// Entries are picked up by the notifier from the audit_log table, so delivery is at least once:
+// an entry is sent again to every sink if any of them fails.
+package auditsinks
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/crypt"
+)
+
+var _ contract.AuditSinksUseCase = (*Service)(nil)
+
+var ErrInvalidAuditSink = errors.New("invalid audit sink")
+
+const (
+	settingName = "audit_sinks"
+	// batchSize is the number of entries sent to the sinks at once.
+	batchSize = 500
+	// maxBatches bounds the work of one Forward call after a sink outage.
+	maxBatches     = 20
+	requestTimeout = 10 * time.Second
+)
+
+// sink forwards audit log entries to an external system.
+type sink interface {
+	Send(ctx context.Context, entries []contract.AuditLogEntry) error
+}
+
+type Service struct {
+	settingsRepo contract.SettingRepository
+	auditLogRepo contract.AuditLogRepository
+	client       *http.Client
+	secret       []byte
+}
+
+func New(
+	settingsRepo contract.SettingRepository,
+	auditLogRepo contract.AuditLogRepository,
+	secret string,
+) *Service {
+	return &Service{
+		settingsRepo: settingsRepo,
+		auditLogRepo: auditLogRepo,
+		client:       &http.Client{Timeout: requestTimeout},
+		secret:       []byte(secret),
+	}
+}
+
+func (s *Service) GetConfig(ctx context.Context) (domain.AuditSinksConfig, error) {
+	setting, err := s.settingsRepo.GetByName(ctx, settingName)
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			return domain.AuditSinksConfig{Sinks: []domain.AuditSinkConfig{}}, nil
+		}
+
+		return domain.AuditSinksConfig{}, fmt.Errorf("get audit sinks config: %w", err)
+	}
+
+	var cfg domain.AuditSinksConfig
+	if err := json.Unmarshal(setting.Value, &cfg); err != nil {
+		return domain.AuditSinksConfig{}, fmt.Errorf("unmarshal audit sinks config: %w", err)
+	}
+
+	for i := range cfg.Sinks {
+		if cfg.Sinks[i].Token == "" {
+			continue
+		}
+
+		cfg.Sinks[i].Token, err = s.decrypt(cfg.Sinks[i].Token)
+		if err != nil {
+			return domain.AuditSinksConfig{}, err
+		}
+	}
+
+	return cfg, nil
+}
+
+func (s *Service) UpdateConfig(ctx context.Context, cfg domain.AuditSinksConfig) error {
+	names := make(map[string]struct{}, len(cfg.Sinks))
+	for i := range cfg.Sinks {
+		if err := validate(&cfg.Sinks[i]); err != nil {
+			return err
+		}
+
+		if _, ok := names[cfg.Sinks[i].Name]; ok {
+			return fmt.Errorf("%w: duplicate name %q", ErrInvalidAuditSink, cfg.Sinks[i].Name)
+		}
+		names[cfg.Sinks[i].Name] = struct{}{}
+	}
+
+	stored := domain.AuditSinksConfig{Sinks: make([]domain.AuditSinkConfig, len(cfg.Sinks))}
+	copy(stored.Sinks, cfg.Sinks)
+
+	for i := range stored.Sinks {
+		if stored.Sinks[i].Token == "" {
+			continue
+		}
+
+		var err error
+		stored.Sinks[i].Token, err = s.encrypt(stored.Sinks[i].Token)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := s.settingsRepo.SetByName(ctx, settingName, stored, "External systems the audit log is forwarded to")
+	if err != nil {
+		return fmt.Errorf("update audit sinks config: %w", err)
+	}
+
+	return nil
+}
+
+// Forward passes entries not forwarded yet to every enabled sink and marks them as forwarded.
+// Without enabled sinks the entries are only marked, so enabling a sink later does not replay the history.
+func (s *Service) Forward(ctx context.Context) (int, error) {
+	cfg, err := s.GetConfig(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	sinks := make(map[string]sink, len(cfg.Sinks))
+	for _, sinkCfg := range cfg.Sinks {
+		if sinkCfg.Enabled {
+			sinks[sinkCfg.Name] = s.newSink(sinkCfg)
+		}
+	}
+
+	var forwarded int
+	for range maxBatches {
+		entries, err := s.auditLogRepo.ListUnforwarded(ctx, batchSize)
+		if err != nil {
+			return forwarded, err
+		}
+
+		if len(entries) == 0 {
+			break
+		}
+
+		for name, sink := range sinks {
+			if err := sink.Send(ctx, entries); err != nil {
+				return forwarded, fmt.Errorf("send audit log to sink %q: %w", name, err)
+			}
+		}
+
+		ids := make([]int64, 0, len(entries))
+		for i := range entries {
+			ids = append(ids, entries[i].ID)
+		}
+
+		if err := s.auditLogRepo.MarkForwarded(ctx, ids); err != nil {
+			return forwarded, err
+		}
+
+		if len(sinks) > 0 {
+			forwarded += len(entries)
+		}
+
+		if len(entries) < batchSize {
+			break
+		}
+	}
+
+	return forwarded, nil
+}
+
+func (s *Service) newSink(cfg domain.AuditSinkConfig) sink {
+	switch cfg.Type {
+	case domain.AuditSinkSyslog:
+		return newSyslogSink(cfg)
+	case domain.AuditSinkKafka:
+		return newKafkaSink(cfg, s.client)
+	default:
+		return newWebhookSink(cfg, s.client)
+	}
+}
+
+func (s *Service) encrypt(value string) (string, error) {
+	encrypted, err := crypt.EncryptAESGCM([]byte(value), s.secret)
+	if err != nil {
+		return "", fmt.Errorf("encrypt audit sink token: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(encrypted), nil
+}
+
+func (s *Service) decrypt(value string) (string, error) {
+	encrypted, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return "", fmt.Errorf("decode audit sink token: %w", err)
+	}
+
+	decrypted, err := crypt.DecryptAESGCM(encrypted, s.secret)
+	if err != nil {
+		return "", fmt.Errorf("decrypt audit sink token: %w", err)
+	}
+
+	return string(decrypted), nil
+}
+
+// validate checks the sink config and fills defaults.
+func validate(cfg *domain.AuditSinkConfig) error {
+	if cfg.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidAuditSink)
+	}
+
+	switch cfg.Type {
+	case domain.AuditSinkSyslog:
+		if cfg.Network == "" {
+			cfg.Network = "udp"
+		}
+
+		if cfg.Network != "udp" && cfg.Network != "tcp" {
+			return fmt.Errorf("%w: %s: network must be udp or tcp", ErrInvalidAuditSink, cfg.Name)
+		}
+
+		if _, _, err := net.SplitHostPort(cfg.Address); err != nil {
+			return fmt.Errorf("%w: %s: address must be host:port", ErrInvalidAuditSink, cfg.Name)
+		}
+	case domain.AuditSinkKafka, domain.AuditSinkWebhook:
+		u, err := url.Parse(cfg.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: %s: url must be an absolute http(s) URL", ErrInvalidAuditSink, cfg.Name)
+		}
+
+		if cfg.Type == domain.AuditSinkKafka && cfg.Topic == "" {
+			return fmt.Errorf("%w: %s: topic is required", ErrInvalidAuditSink, cfg.Name)
+		}
+	default:
+		return fmt.Errorf("%w: %s: unknown type %q", ErrInvalidAuditSink, cfg.Name, cfg.Type)
+	}
+
+	return nil
+}
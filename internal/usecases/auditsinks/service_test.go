@@ -0,0 +1,144 @@
+package auditsinks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type fakeAuditLogRepo struct {
+	contract.AuditLogRepository
+	pending   []contract.AuditLogEntry
+	forwarded []int64
+}
+
+func (r *fakeAuditLogRepo) ListUnforwarded(_ context.Context, limit int) ([]contract.AuditLogEntry, error) {
+	return r.pending[:min(limit, len(r.pending))], nil
+}
+
+func (r *fakeAuditLogRepo) MarkForwarded(_ context.Context, ids []int64) error {
+	r.forwarded = append(r.forwarded, ids...)
+	r.pending = r.pending[len(ids):]
+
+	return nil
+}
+
+type fakeSettingsRepo struct {
+	contract.SettingRepository
+	value json.RawMessage
+}
+
+func (r *fakeSettingsRepo) GetByName(context.Context, string) (*domain.Setting, error) {
+	if r.value == nil {
+		return nil, domain.ErrEntityNotFound
+	}
+
+	return &domain.Setting{Value: r.value}, nil
+}
+
+func (r *fakeSettingsRepo) SetByName(_ context.Context, _ string, value any, _ string) error {
+	var err error
+	r.value, err = json.Marshal(value)
+
+	return err
+}
+
+func TestForward(t *testing.T) {
+	var received []contract.AuditLogEntry
+	var authorization string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+
+		var body struct {
+			Entries []contract.AuditLogEntry `json:"entries"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		received = append(received, body.Entries...)
+	}))
+	defer server.Close()
+
+	auditLogRepo := &fakeAuditLogRepo{pending: []contract.AuditLogEntry{
+		{ID: 1, Entity: domain.EntityProject, Action: domain.ActionUpdate, Username: "admin"},
+		{ID: 2, Entity: domain.EntityUser, Action: domain.ActionUpdate, Username: "admin"},
+	}}
+	settingsRepo := &fakeSettingsRepo{}
+	service := New(settingsRepo, auditLogRepo, "0123456789abcdef0123456789abcdef")
+
+	require.NoError(t, service.UpdateConfig(context.Background(), domain.AuditSinksConfig{
+		Sinks: []domain.AuditSinkConfig{
+			{Name: "splunk", Type: domain.AuditSinkWebhook, Enabled: true, URL: server.URL, Token: "hec-token"},
+			{Name: "elastic", Type: domain.AuditSinkWebhook, URL: "http://127.0.0.1:1"},
+		},
+	}))
+	assert.NotContains(t, string(settingsRepo.value), "hec-token")
+
+	count, err := service.Forward(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, count)
+	assert.Equal(t, []int64{1, 2}, auditLogRepo.forwarded)
+	assert.Len(t, received, 2)
+	assert.Equal(t, "Bearer hec-token", authorization)
+}
+
+func TestForward_NoSinks(t *testing.T) {
+	auditLogRepo := &fakeAuditLogRepo{pending: []contract.AuditLogEntry{{ID: 1}}}
+	service := New(&fakeSettingsRepo{}, auditLogRepo, "0123456789abcdef0123456789abcdef")
+
+	count, err := service.Forward(context.Background())
+	require.NoError(t, err)
+
+	assert.Zero(t, count)
+	assert.Equal(t, []int64{1}, auditLogRepo.forwarded)
+}
+
+func TestSyslogFormat(t *testing.T) {
+	sink := &syslogSink{network: "udp", address: "127.0.0.1:514", hostname: "manager-1"}
+
+	msg, err := sink.format(&contract.AuditLogEntry{
+		ID:        7,
+		Entity:    domain.EntityProject,
+		Action:    domain.ActionUpdate,
+		CreatedAt: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, string(msg), "<85>1 2025-03-01T12:00:00Z manager-1 floxy-manager - audit - {")
+	assert.Contains(t, string(msg), `"id":7`)
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  domain.AuditSinkConfig
+	}{
+		{"empty name", domain.AuditSinkConfig{Type: domain.AuditSinkWebhook, URL: "https://siem.example.com"}},
+		{"unknown type", domain.AuditSinkConfig{Name: "x", Type: "splunk"}},
+		{"syslog without port", domain.AuditSinkConfig{Name: "x", Type: domain.AuditSinkSyslog, Address: "siem"}},
+		{"syslog bad network", domain.AuditSinkConfig{
+			Name: "x", Type: domain.AuditSinkSyslog, Address: "siem:514", Network: "unix",
+		}},
+		{"kafka without topic", domain.AuditSinkConfig{Name: "x", Type: domain.AuditSinkKafka, URL: "http://rest-proxy"}},
+		{"webhook relative url", domain.AuditSinkConfig{Name: "x", Type: domain.AuditSinkWebhook, URL: "/hook"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, validate(&tt.cfg), ErrInvalidAuditSink)
+		})
+	}
+
+	syslog := domain.AuditSinkConfig{Name: "x", Type: domain.AuditSinkSyslog, Address: "siem:514"}
+	require.NoError(t, validate(&syslog))
+	assert.Equal(t, "udp", syslog.Network)
+}
@@ -0,0 +1,178 @@
+package auditsinks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+const (
+	appName          = "floxy-manager"
+	userAgent        = "floxy-manager"
+	maxErrorBodySize = 1024
+	// syslogPriority is facility security/authorization (10) and severity notice (5).
+	syslogPriority = 10*8 + 5
+)
+
+// syslogSink sends every entry as an RFC 5424 message with the entry JSON as the message body.
+// TCP messages are framed with octet counting (RFC 6587).
+type syslogSink struct {
+	network  string
+	address  string
+	hostname string
+}
+
+func newSyslogSink(cfg domain.AuditSinkConfig) *syslogSink {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+
+	return &syslogSink{
+		network:  cfg.Network,
+		address:  cfg.Address,
+		hostname: hostname,
+	}
+}
+
+func (s *syslogSink) Send(ctx context.Context, entries []contract.AuditLogEntry) error {
+	dialer := net.Dialer{Timeout: requestTimeout}
+
+	conn, err := dialer.DialContext(ctx, s.network, s.address)
+	if err != nil {
+		return fmt.Errorf("dial syslog: %w", err)
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(requestTimeout)); err != nil {
+		return fmt.Errorf("set syslog deadline: %w", err)
+	}
+
+	for i := range entries {
+		msg, err := s.format(&entries[i])
+		if err != nil {
+			return err
+		}
+
+		if s.network == "tcp" {
+			msg = append([]byte(strconv.Itoa(len(msg))+" "), msg...)
+		}
+
+		if _, err := conn.Write(msg); err != nil {
+			return fmt.Errorf("write syslog message: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *syslogSink) format(entry *contract.AuditLogEntry) ([]byte, error) {
+	body, err := json.Marshal(entry)
+	if err != nil {
+		return nil, fmt.Errorf("marshal audit log entry: %w", err)
+	}
+
+	header := fmt.Sprintf("<%d>1 %s %s %s - audit - ",
+		syslogPriority,
+		entry.CreatedAt.UTC().Format(time.RFC3339Nano),
+		s.hostname,
+		appName,
+	)
+
+	return append([]byte(header), body...), nil
+}
+
+// kafkaSink produces entries to a topic through a Kafka REST Proxy (v2 API), keyed by entity.
+type kafkaSink struct {
+	endpoint string
+	token    string
+	client   *http.Client
+}
+
+func newKafkaSink(cfg domain.AuditSinkConfig, client *http.Client) *kafkaSink {
+	return &kafkaSink{
+		endpoint: strings.TrimRight(cfg.URL, "/") + "/topics/" + url.PathEscape(cfg.Topic),
+		token:    cfg.Token,
+		client:   client,
+	}
+}
+
+func (s *kafkaSink) Send(ctx context.Context, entries []contract.AuditLogEntry) error {
+	type record struct {
+		Key   string                 `json:"key"`
+		Value contract.AuditLogEntry `json:"value"`
+	}
+
+	records := make([]record, 0, len(entries))
+	for i := range entries {
+		records = append(records, record{Key: entries[i].Entity, Value: entries[i]})
+	}
+
+	return post(ctx, s.client, s.endpoint, "application/vnd.kafka.json.v2+json", s.token,
+		map[string]any{"records": records})
+}
+
+// webhookSink posts entries as a JSON object {"entries": [...]}.
+type webhookSink struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func newWebhookSink(cfg domain.AuditSinkConfig, client *http.Client) *webhookSink {
+	return &webhookSink{
+		url:    cfg.URL,
+		token:  cfg.Token,
+		client: client,
+	}
+}
+
+func (s *webhookSink) Send(ctx context.Context, entries []contract.AuditLogEntry) error {
+	return post(ctx, s.client, s.url, "application/json", s.token, map[string]any{"entries": entries})
+}
+
+func post(ctx context.Context, client *http.Client, endpoint, contentType, token string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("User-Agent", userAgent)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, bytes.TrimSpace(respBody))
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	return nil
+}
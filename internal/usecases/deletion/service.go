@@ -0,0 +1,117 @@
+package deletion
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.DeletionUseCase = (*Service)(nil)
+
+type Service struct {
+	tx           db.TxManager
+	deletionRepo contract.DeletionRepository
+	projectsRepo contract.ProjectsRepository
+	tenantsRepo  contract.TenantsRepository
+}
+
+func New(
+	tx db.TxManager,
+	deletionRepo contract.DeletionRepository,
+	projectsRepo contract.ProjectsRepository,
+	tenantsRepo contract.TenantsRepository,
+) *Service {
+	return &Service{
+		tx:           tx,
+		deletionRepo: deletionRepo,
+		projectsRepo: projectsRepo,
+		tenantsRepo:  tenantsRepo,
+	}
+}
+
+func (s *Service) DeleteProject(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	force bool,
+) (domain.DeletionImpact, error) {
+	var impact domain.DeletionImpact
+	err := s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		var err error
+		impact, err = s.deletionRepo.ProjectImpact(ctx, projectID)
+		if err != nil {
+			return err
+		}
+
+		if !impact.Empty() && !force {
+			return domain.ErrEntityInUse
+		}
+
+		return s.deleteProject(ctx, projectID)
+	})
+	if err != nil {
+		return impact, fmt.Errorf("delete project: %w", err)
+	}
+
+	return impact, nil
+}
+
+func (s *Service) DeleteTenant(
+	ctx context.Context,
+	tenantID domain.TenantID,
+	force bool,
+) (domain.DeletionImpact, error) {
+	var impact domain.DeletionImpact
+	err := s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		var err error
+		impact, err = s.deletionRepo.TenantImpact(ctx, tenantID)
+		if err != nil {
+			return err
+		}
+
+		if !impact.Empty() && !force {
+			return domain.ErrEntityInUse
+		}
+
+		projectIDs, err := s.deletionRepo.ListTenantProjects(ctx, tenantID)
+		if err != nil {
+			return err
+		}
+
+		for _, projectID := range projectIDs {
+			if err := s.deleteProject(ctx, projectID); err != nil {
+				return fmt.Errorf("project %d: %w", projectID, err)
+			}
+		}
+
+		if _, err := s.deletionRepo.DeleteTenantMemberships(ctx, tenantID); err != nil {
+			return err
+		}
+
+		return s.tenantsRepo.Delete(ctx, tenantID)
+	})
+	if err != nil {
+		return impact, fmt.Errorf("delete tenant: %w", err)
+	}
+
+	return impact, nil
+}
+
+// deleteProject deletes the project after its dependents, instances first as they reference the definitions.
+func (s *Service) deleteProject(ctx context.Context, projectID domain.ProjectID) error {
+	if _, err := s.deletionRepo.DeleteProjectInstances(ctx, projectID); err != nil {
+		return err
+	}
+
+	if _, err := s.deletionRepo.DeleteProjectWorkflows(ctx, projectID); err != nil {
+		return err
+	}
+
+	if _, err := s.deletionRepo.DeleteProjectMemberships(ctx, projectID); err != nil {
+		return err
+	}
+
+	return s.projectsRepo.Delete(ctx, projectID)
+}
@@ -0,0 +1,130 @@
+package deletion
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type fakeTx struct{}
+
+func (fakeTx) ReadCommitted(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (fakeTx) RepeatableRead(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// fakeDeletionRepo records the cascade steps in order.
+type fakeDeletionRepo struct {
+	contract.DeletionRepository
+	impact domain.DeletionImpact
+	steps  []string
+}
+
+func (r *fakeDeletionRepo) ProjectImpact(context.Context, domain.ProjectID) (domain.DeletionImpact, error) {
+	return r.impact, nil
+}
+
+func (r *fakeDeletionRepo) TenantImpact(context.Context, domain.TenantID) (domain.DeletionImpact, error) {
+	return r.impact, nil
+}
+
+func (r *fakeDeletionRepo) ListTenantProjects(context.Context, domain.TenantID) ([]domain.ProjectID, error) {
+	return []domain.ProjectID{1, 2}, nil
+}
+
+func (r *fakeDeletionRepo) DeleteProjectInstances(_ context.Context, projectID domain.ProjectID) (int64, error) {
+	r.steps = append(r.steps, fmt.Sprintf("instances %d", projectID))
+
+	return 0, nil
+}
+
+func (r *fakeDeletionRepo) DeleteProjectWorkflows(_ context.Context, projectID domain.ProjectID) (int64, error) {
+	r.steps = append(r.steps, fmt.Sprintf("workflows %d", projectID))
+
+	return 0, nil
+}
+
+func (r *fakeDeletionRepo) DeleteProjectMemberships(_ context.Context, projectID domain.ProjectID) (int64, error) {
+	r.steps = append(r.steps, fmt.Sprintf("memberships %d", projectID))
+
+	return 0, nil
+}
+
+func (r *fakeDeletionRepo) DeleteTenantMemberships(context.Context, domain.TenantID) (int64, error) {
+	r.steps = append(r.steps, "tenant memberships")
+
+	return 0, nil
+}
+
+type fakeProjectsRepo struct {
+	contract.ProjectsRepository
+	repo *fakeDeletionRepo
+}
+
+func (r *fakeProjectsRepo) Delete(_ context.Context, id domain.ProjectID) error {
+	r.repo.steps = append(r.repo.steps, fmt.Sprintf("project %d", id))
+
+	return nil
+}
+
+type fakeTenantsRepo struct {
+	contract.TenantsRepository
+	repo *fakeDeletionRepo
+}
+
+func (r *fakeTenantsRepo) Delete(context.Context, domain.TenantID) error {
+	r.repo.steps = append(r.repo.steps, "tenant")
+
+	return nil
+}
+
+func newService(impact domain.DeletionImpact) (*Service, *fakeDeletionRepo) {
+	repo := &fakeDeletionRepo{impact: impact}
+
+	return New(fakeTx{}, repo, &fakeProjectsRepo{repo: repo}, &fakeTenantsRepo{repo: repo}), repo
+}
+
+func TestService_DeleteProject(t *testing.T) {
+	ctx := context.Background()
+
+	srv, repo := newService(domain.DeletionImpact{})
+	_, err := srv.DeleteProject(ctx, 1, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"instances 1", "workflows 1", "memberships 1", "project 1"}, repo.steps)
+
+	srv, repo = newService(domain.DeletionImpact{LiveInstances: 3, Memberships: 1})
+	impact, err := srv.DeleteProject(ctx, 1, false)
+	require.ErrorIs(t, err, domain.ErrEntityInUse)
+	assert.Equal(t, 3, impact.LiveInstances)
+	assert.Empty(t, repo.steps)
+
+	_, err = srv.DeleteProject(ctx, 1, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"instances 1", "workflows 1", "memberships 1", "project 1"}, repo.steps)
+}
+
+func TestService_DeleteTenant(t *testing.T) {
+	ctx := context.Background()
+
+	srv, repo := newService(domain.DeletionImpact{Projects: 2})
+	_, err := srv.DeleteTenant(ctx, 1, false)
+	require.ErrorIs(t, err, domain.ErrEntityInUse)
+	assert.Empty(t, repo.steps)
+
+	_, err = srv.DeleteTenant(ctx, 1, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"instances 1", "workflows 1", "memberships 1", "project 1",
+		"instances 2", "workflows 2", "memberships 2", "project 2",
+		"tenant memberships", "tenant",
+	}, repo.steps)
+}
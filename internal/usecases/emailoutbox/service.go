@@ -0,0 +1,142 @@
+package emailoutbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.EmailOutboxUseCase = (*Service)(nil)
+
+var ErrNotFailed = errors.New("only failed emails can be resent")
+
+const (
+	sendBatchSize  = 50
+	maxAttempts    = 8
+	retryBaseDelay = 30 * time.Second
+	retryMaxDelay  = time.Hour
+)
+
+type Service struct {
+	tx         db.TxManager
+	outboxRepo contract.EmailOutboxRepository
+	deliverer  contract.EmailDeliverer
+}
+
+func New(
+	tx db.TxManager,
+	outboxRepo contract.EmailOutboxRepository,
+	deliverer contract.EmailDeliverer,
+) *Service {
+	return &Service{
+		tx:         tx,
+		outboxRepo: outboxRepo,
+		deliverer:  deliverer,
+	}
+}
+
+func (s *Service) SendPending(ctx context.Context, now time.Time) (int, error) {
+	due, err := s.outboxRepo.ListDue(ctx, now, sendBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("list due emails: %w", err)
+	}
+
+	sent := 0
+	for i := range due {
+		email := due[i]
+		email.Attempts++
+
+		err := s.deliverer.Deliver(ctx, email.Recipient, email.Subject, email.Body)
+		if err == nil {
+			sentAt := time.Now()
+			email.Status = domain.EmailSent
+			email.SentAt = &sentAt
+			email.NextAttemptAt = nil
+			email.LastError = nil
+			sent++
+		} else {
+			errMsg := err.Error()
+			email.LastError = &errMsg
+
+			if email.Attempts >= maxAttempts {
+				email.Status = domain.EmailFailed
+				email.NextAttemptAt = nil
+
+				slog.Warn("Email failed permanently",
+					"error", err,
+					"email_id", email.ID,
+					"attempts", email.Attempts,
+				)
+			} else {
+				nextAttemptAt := now.Add(retryDelay(email.Attempts))
+				email.NextAttemptAt = &nextAttemptAt
+			}
+		}
+
+		if err := s.outboxRepo.SaveAttempt(ctx, email); err != nil {
+			return sent, fmt.Errorf("save email %d: %w", email.ID, err)
+		}
+	}
+
+	return sent, nil
+}
+
+func (s *Service) List(
+	ctx context.Context,
+	status domain.EmailStatus,
+	page, pageSize int,
+) ([]domain.OutboxEmail, int, error) {
+	emails, total, err := s.outboxRepo.List(ctx, status, page, pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list emails: %w", err)
+	}
+
+	return emails, total, nil
+}
+
+// Resend queues a failed email again, it is sent by the next run of the background sender.
+func (s *Service) Resend(ctx context.Context, id int64) (domain.OutboxEmail, error) {
+	var email domain.OutboxEmail
+	err := s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		current, err := s.outboxRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if current.Status != domain.EmailFailed {
+			return ErrNotFailed
+		}
+
+		if err := s.outboxRepo.Requeue(ctx, id, time.Now()); err != nil {
+			return err
+		}
+
+		email, err = s.outboxRepo.GetByID(ctx, id)
+
+		return err
+	})
+	if err != nil {
+		return domain.OutboxEmail{}, fmt.Errorf("resend email: %w", err)
+	}
+
+	return email, nil
+}
+
+// retryDelay returns the exponential backoff before the next attempt: 30s, 1m, 2m, ... up to 1h.
+func retryDelay(attempts int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= retryMaxDelay {
+			return retryMaxDelay
+		}
+	}
+
+	return delay
+}
@@ -0,0 +1,75 @@
+package emailoutbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type fakeOutboxRepo struct {
+	contract.EmailOutboxRepository
+	due   []domain.OutboxEmail
+	saved []domain.OutboxEmail
+}
+
+func (r *fakeOutboxRepo) ListDue(context.Context, time.Time, int) ([]domain.OutboxEmail, error) {
+	return r.due, nil
+}
+
+func (r *fakeOutboxRepo) SaveAttempt(_ context.Context, email domain.OutboxEmail) error {
+	r.saved = append(r.saved, email)
+
+	return nil
+}
+
+// fakeDeliverer fails for the recipients in failing.
+type fakeDeliverer struct {
+	failing string
+}
+
+func (d fakeDeliverer) Deliver(_ context.Context, email, _, _ string) error {
+	if email == d.failing {
+		return errors.New("connection refused")
+	}
+
+	return nil
+}
+
+func TestService_SendPending(t *testing.T) {
+	now := time.Now()
+	repo := &fakeOutboxRepo{due: []domain.OutboxEmail{
+		{ID: 1, Recipient: "ok@example.com", Status: domain.EmailPending},
+		{ID: 2, Recipient: "down@example.com", Status: domain.EmailPending, Attempts: 1},
+		{ID: 3, Recipient: "down@example.com", Status: domain.EmailPending, Attempts: maxAttempts - 1},
+	}}
+	srv := New(nil, repo, fakeDeliverer{failing: "down@example.com"})
+
+	sent, err := srv.SendPending(context.Background(), now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	require.Len(t, repo.saved, 3)
+
+	assert.Equal(t, domain.EmailSent, repo.saved[0].Status)
+	assert.NotNil(t, repo.saved[0].SentAt)
+
+	// A failed attempt is retried with a backoff until the last one
+	assert.Equal(t, domain.EmailPending, repo.saved[1].Status)
+	assert.Equal(t, now.Add(time.Minute), *repo.saved[1].NextAttemptAt)
+	assert.Equal(t, "connection refused", *repo.saved[1].LastError)
+
+	assert.Equal(t, domain.EmailFailed, repo.saved[2].Status)
+	assert.Nil(t, repo.saved[2].NextAttemptAt)
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, 30*time.Second, retryDelay(1))
+	assert.Equal(t, 2*time.Minute, retryDelay(3))
+	assert.Equal(t, time.Hour, retryDelay(maxAttempts+10))
+}
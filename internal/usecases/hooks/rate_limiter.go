@@ -0,0 +1,52 @@
+package hooks
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+// rateLimiter is a fixed-window in-memory limiter keyed by hook.
+type rateLimiter struct {
+	mu      sync.Mutex
+	window  time.Duration
+	entries map[domain.HookID]*rateLimitEntry
+	now     func() time.Time
+}
+
+type rateLimitEntry struct {
+	count       int
+	windowStart time.Time
+}
+
+func newRateLimiter(window time.Duration) *rateLimiter {
+	return &rateLimiter{
+		window:  window,
+		entries: make(map[domain.HookID]*rateLimitEntry),
+		now:     time.Now,
+	}
+}
+
+// Allow registers a call and reports whether it fits into the limit of the current window.
+func (l *rateLimiter) Allow(id domain.HookID, limit int) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+
+	entry, ok := l.entries[id]
+	if !ok || now.Sub(entry.windowStart) >= l.window {
+		l.entries[id] = &rateLimitEntry{count: 1, windowStart: now}
+
+		return true
+	}
+
+	if entry.count >= limit {
+		return false
+	}
+
+	entry.count++
+
+	return true
+}
@@ -0,0 +1,317 @@
+package hooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/crypt"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.HooksUseCase = (*Service)(nil)
+
+var (
+	ErrInvalidHook      = errors.New("invalid hook")
+	ErrHookDisabled     = errors.New("hook is disabled")
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrRateLimited      = errors.New("hook rate limit exceeded")
+	ErrInvalidPayload   = errors.New("payload must be valid JSON")
+)
+
+const (
+	signaturePrefix           = "sha256="
+	defaultRateLimitPerMinute = 60
+	tokenBytes                = 24
+	secretBytes               = 32
+)
+
+type Service struct {
+	tx            db.TxManager
+	hooksRepo     contract.HooksRepository
+	workflowsRepo contract.WorkflowsRepository
+	engine        contract.WorkflowEngine
+	variables     contract.VariablesInjector
+	limiter       *rateLimiter
+	secret        []byte
+}
+
+func New(
+	tx db.TxManager,
+	hooksRepo contract.HooksRepository,
+	workflowsRepo contract.WorkflowsRepository,
+	engine contract.WorkflowEngine,
+	variables contract.VariablesInjector,
+	secret string,
+) *Service {
+	return &Service{
+		tx:            tx,
+		hooksRepo:     hooksRepo,
+		workflowsRepo: workflowsRepo,
+		engine:        engine,
+		variables:     variables,
+		limiter:       newRateLimiter(time.Minute),
+		secret:        []byte(secret),
+	}
+}
+
+func (s *Service) List(ctx context.Context, projectID domain.ProjectID, page, pageSize int) ([]domain.Hook, int, error) {
+	return s.hooksRepo.List(ctx, projectID, page, pageSize)
+}
+
+func (s *Service) Get(ctx context.Context, projectID domain.ProjectID, id domain.HookID) (domain.Hook, error) {
+	return s.hooksRepo.GetByID(ctx, projectID, id)
+}
+
+// Create creates a hook with a random token and secret.
+// The plain secret is returned only once, in the created hook.
+func (s *Service) Create(
+	ctx context.Context,
+	tenantID domain.TenantID,
+	projectID domain.ProjectID,
+	dto domain.HookDTO,
+) (domain.Hook, error) {
+	if err := s.validate(ctx, tenantID, projectID, &dto); err != nil {
+		return domain.Hook{}, err
+	}
+
+	token, err := randomHex(tokenBytes)
+	if err != nil {
+		return domain.Hook{}, fmt.Errorf("generate token: %w", err)
+	}
+
+	secret, secretEncrypted, err := s.newSecret()
+	if err != nil {
+		return domain.Hook{}, err
+	}
+
+	var hook domain.Hook
+	err = s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		id, err := s.hooksRepo.Create(ctx, projectID, dto, token, secretEncrypted)
+		if err != nil {
+			return err
+		}
+
+		hook, err = s.hooksRepo.GetByID(ctx, projectID, id)
+
+		return err
+	})
+	if err != nil {
+		return domain.Hook{}, fmt.Errorf("create hook: %w", err)
+	}
+
+	hook.Secret = secret
+
+	return hook, nil
+}
+
+func (s *Service) Update(
+	ctx context.Context,
+	tenantID domain.TenantID,
+	projectID domain.ProjectID,
+	id domain.HookID,
+	dto domain.HookDTO,
+) (domain.Hook, error) {
+	if err := s.validate(ctx, tenantID, projectID, &dto); err != nil {
+		return domain.Hook{}, err
+	}
+
+	var hook domain.Hook
+	err := s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		if err := s.hooksRepo.Update(ctx, projectID, id, dto); err != nil {
+			return err
+		}
+
+		var err error
+		hook, err = s.hooksRepo.GetByID(ctx, projectID, id)
+
+		return err
+	})
+	if err != nil {
+		return domain.Hook{}, fmt.Errorf("update hook: %w", err)
+	}
+
+	return hook, nil
+}
+
+// RotateSecret replaces the hook secret and returns the hook with the new plain secret.
+func (s *Service) RotateSecret(ctx context.Context, projectID domain.ProjectID, id domain.HookID) (domain.Hook, error) {
+	secret, secretEncrypted, err := s.newSecret()
+	if err != nil {
+		return domain.Hook{}, err
+	}
+
+	var hook domain.Hook
+	err = s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		if err := s.hooksRepo.UpdateSecret(ctx, projectID, id, secretEncrypted); err != nil {
+			return err
+		}
+
+		hook, err = s.hooksRepo.GetByID(ctx, projectID, id)
+
+		return err
+	})
+	if err != nil {
+		return domain.Hook{}, fmt.Errorf("rotate hook secret: %w", err)
+	}
+
+	hook.Secret = secret
+
+	return hook, nil
+}
+
+func (s *Service) Delete(ctx context.Context, projectID domain.ProjectID, id domain.HookID) error {
+	return s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		return s.hooksRepo.Delete(ctx, projectID, id)
+	})
+}
+
+// Trigger verifies the request against the hook identified by token and starts
+// a workflow instance with the body as input.
+func (s *Service) Trigger(ctx context.Context, token string, body []byte, signature string) (int64, error) {
+	hook, err := s.hooksRepo.GetByToken(ctx, token)
+	if err != nil {
+		return 0, err
+	}
+
+	if !hook.Enabled {
+		return 0, ErrHookDisabled
+	}
+
+	if hook.RequireSignature {
+		secret, err := s.decryptSecret(hook.SecretEncrypted)
+		if err != nil {
+			return 0, err
+		}
+
+		if !verifySignature(secret, body, signature) {
+			return 0, ErrInvalidSignature
+		}
+	}
+
+	if !s.limiter.Allow(hook.ID, hook.RateLimitPerMinute) {
+		return 0, ErrRateLimited
+	}
+
+	input := json.RawMessage(body)
+	if len(body) == 0 {
+		input = json.RawMessage(`{}`)
+	} else if !json.Valid(body) {
+		return 0, ErrInvalidPayload
+	}
+
+	// Pinned workflows start on their active version
+	workflowID, err := s.workflowsRepo.ResolveActiveWorkflowID(ctx, hook.ProjectID, hook.WorkflowID)
+	if err != nil {
+		return 0, fmt.Errorf("resolve workflow: %w", err)
+	}
+
+	input, err = s.variables.InjectVariables(ctx, hook.ProjectID, input)
+	if err != nil {
+		return 0, fmt.Errorf("inject variables: %w", err)
+	}
+
+	instanceID, err := s.engine.Start(ctx, workflowID, input)
+	if err != nil {
+		return 0, fmt.Errorf("start workflow: %w", err)
+	}
+
+	if err := s.hooksRepo.MarkTriggered(ctx, hook.ID, time.Now()); err != nil {
+		slog.Warn("Failed to mark hook as triggered", "error", err, "hook_id", hook.ID)
+	}
+
+	return instanceID, nil
+}
+
+func (s *Service) validate(
+	ctx context.Context,
+	tenantID domain.TenantID,
+	projectID domain.ProjectID,
+	dto *domain.HookDTO,
+) error {
+	if dto.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidHook)
+	}
+
+	if dto.WorkflowID == "" {
+		return fmt.Errorf("%w: workflow_id is required", ErrInvalidHook)
+	}
+
+	if dto.RateLimitPerMinute < 0 {
+		return fmt.Errorf("%w: rate_limit_per_minute must be positive", ErrInvalidHook)
+	}
+
+	if dto.RateLimitPerMinute == 0 {
+		dto.RateLimitPerMinute = defaultRateLimitPerMinute
+	}
+
+	if _, err := s.workflowsRepo.GetWorkflowDefinition(ctx, tenantID, projectID, dto.WorkflowID); err != nil {
+		return fmt.Errorf("get workflow definition: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) newSecret() (plain, encrypted string, err error) {
+	plain, err = randomHex(secretBytes)
+	if err != nil {
+		return "", "", fmt.Errorf("generate secret: %w", err)
+	}
+
+	encryptedBytes, err := crypt.EncryptAESGCM([]byte(plain), s.secret)
+	if err != nil {
+		return "", "", fmt.Errorf("encrypt hook secret: %w", err)
+	}
+
+	return plain, base64.StdEncoding.EncodeToString(encryptedBytes), nil
+}
+
+func (s *Service) decryptSecret(encrypted string) ([]byte, error) {
+	encryptedBytes, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return nil, fmt.Errorf("decode hook secret: %w", err)
+	}
+
+	secret, err := crypt.DecryptAESGCM(encryptedBytes, s.secret)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt hook secret: %w", err)
+	}
+
+	return secret, nil
+}
+
+// Sign returns the signature header value ("sha256=<hex>") for body.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+func verifySignature(secret, body []byte, signature string) bool {
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return false
+	}
+
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
+
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,33 @@
+package hooks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifySignature(t *testing.T) {
+	secret := []byte("secret")
+	body := []byte(`{"order_id":42}`)
+
+	assert.True(t, verifySignature(secret, body, Sign(secret, body)))
+	assert.False(t, verifySignature(secret, []byte(`{"order_id":43}`), Sign(secret, body)))
+	assert.False(t, verifySignature([]byte("other"), body, Sign(secret, body)))
+	assert.False(t, verifySignature(secret, body, ""))
+	assert.False(t, verifySignature(secret, body, "md5=abc"))
+}
+
+func TestRateLimiter_Allow(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	limiter := newRateLimiter(time.Minute)
+	limiter.now = func() time.Time { return now }
+
+	assert.True(t, limiter.Allow(1, 2))
+	assert.True(t, limiter.Allow(1, 2))
+	assert.False(t, limiter.Allow(1, 2))
+	assert.True(t, limiter.Allow(2, 2), "limits are tracked per hook")
+
+	now = now.Add(time.Minute)
+	assert.True(t, limiter.Allow(1, 2), "new window resets the counter")
+}
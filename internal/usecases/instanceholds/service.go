@@ -0,0 +1,100 @@
+package instanceholds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	floxy "github.com/rom8726/floxy-pro"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.InstanceHoldsUseCase = (*Service)(nil)
+
+var ErrInstanceNotActive = errors.New("only pending or running instances can be paused")
+
+// The engine has no pause, an instance is paused by keeping its queued steps aside.
+type Service struct {
+	tx            db.TxManager
+	holdsRepo     contract.InstanceHoldsRepository
+	workflowsRepo contract.WorkflowsRepository
+}
+
+func New(
+	tx db.TxManager,
+	holdsRepo contract.InstanceHoldsRepository,
+	workflowsRepo contract.WorkflowsRepository,
+) *Service {
+	return &Service{
+		tx:            tx,
+		holdsRepo:     holdsRepo,
+		workflowsRepo: workflowsRepo,
+	}
+}
+
+func (s *Service) Pause(
+	ctx context.Context,
+	tenantID domain.TenantID,
+	projectID domain.ProjectID,
+	instanceID int,
+	reason string,
+) (domain.InstanceHold, error) {
+	instance, err := s.workflowsRepo.GetWorkflowInstance(ctx, tenantID, projectID, instanceID)
+	if err != nil {
+		return domain.InstanceHold{}, err
+	}
+
+	switch floxy.WorkflowStatus(instance.Status) {
+	case floxy.StatusPending, floxy.StatusRunning:
+	default:
+		return domain.InstanceHold{}, ErrInstanceNotActive
+	}
+
+	var hold domain.InstanceHold
+	err = s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		hold, err = s.holdsRepo.Create(ctx, projectID, instanceID, reason)
+
+		return err
+	})
+	if err != nil {
+		return domain.InstanceHold{}, fmt.Errorf("pause instance: %w", err)
+	}
+
+	return hold, nil
+}
+
+func (s *Service) Resume(
+	ctx context.Context,
+	tenantID domain.TenantID,
+	projectID domain.ProjectID,
+	instanceID int,
+) (int, error) {
+	if _, err := s.workflowsRepo.GetWorkflowInstance(ctx, tenantID, projectID, instanceID); err != nil {
+		return 0, err
+	}
+
+	var released int
+	err := s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		var err error
+		released, err = s.holdsRepo.Delete(ctx, projectID, instanceID)
+
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("resume instance: %w", err)
+	}
+
+	return released, nil
+}
+
+func (s *Service) List(ctx context.Context, projectID domain.ProjectID) ([]domain.InstanceHold, error) {
+	holds, err := s.holdsRepo.List(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("list instance holds: %w", err)
+	}
+
+	return holds, nil
+}
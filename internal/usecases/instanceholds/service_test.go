@@ -0,0 +1,93 @@
+package instanceholds
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type fakeTx struct{}
+
+func (fakeTx) ReadCommitted(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (fakeTx) RepeatableRead(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type fakeWorkflowsRepo struct {
+	contract.WorkflowsRepository
+	instance domain.WorkflowInstance
+}
+
+func (r *fakeWorkflowsRepo) GetWorkflowInstance(
+	_ context.Context,
+	_ domain.TenantID,
+	_ domain.ProjectID,
+	id int,
+) (domain.WorkflowInstance, error) {
+	if id != r.instance.ID {
+		return domain.WorkflowInstance{}, domain.ErrEntityNotFound
+	}
+
+	return r.instance, nil
+}
+
+type fakeHoldsRepo struct {
+	contract.InstanceHoldsRepository
+	held map[int]int
+}
+
+func (r *fakeHoldsRepo) Create(
+	_ context.Context,
+	projectID domain.ProjectID,
+	instanceID int,
+	reason string,
+) (domain.InstanceHold, error) {
+	if _, ok := r.held[instanceID]; ok {
+		return domain.InstanceHold{}, domain.ErrEntityAlreadyExists
+	}
+	r.held[instanceID] = 2
+
+	return domain.InstanceHold{InstanceID: instanceID, ProjectID: projectID, Reason: reason, HeldSteps: 2}, nil
+}
+
+func (r *fakeHoldsRepo) Delete(_ context.Context, _ domain.ProjectID, instanceID int) (int, error) {
+	steps, ok := r.held[instanceID]
+	if !ok {
+		return 0, domain.ErrEntityNotFound
+	}
+	delete(r.held, instanceID)
+
+	return steps, nil
+}
+
+func TestService_PauseAndResume(t *testing.T) {
+	ctx := context.Background()
+	workflows := &fakeWorkflowsRepo{instance: domain.WorkflowInstance{ID: 5, Status: "running"}}
+	srv := New(fakeTx{}, &fakeHoldsRepo{held: map[int]int{}}, workflows)
+
+	hold, err := srv.Pause(ctx, 1, 2, 5, "incident")
+	require.NoError(t, err)
+	assert.Equal(t, 2, hold.HeldSteps)
+
+	_, err = srv.Pause(ctx, 1, 2, 5, "again")
+	assert.ErrorIs(t, err, domain.ErrEntityAlreadyExists)
+
+	released, err := srv.Resume(ctx, 1, 2, 5)
+	require.NoError(t, err)
+	assert.Equal(t, 2, released)
+
+	_, err = srv.Resume(ctx, 1, 2, 5)
+	assert.ErrorIs(t, err, domain.ErrEntityNotFound)
+
+	workflows.instance.Status = "completed"
+	_, err = srv.Pause(ctx, 1, 2, 5, "")
+	assert.ErrorIs(t, err, ErrInstanceNotActive)
+}
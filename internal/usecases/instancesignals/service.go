@@ -0,0 +1,150 @@
+package instancesignals
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	floxy "github.com/rom8726/floxy-pro"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.InstanceSignalsUseCase = (*Service)(nil)
+
+var (
+	ErrInvalidSignal = errors.New("invalid signal")
+	ErrNoWaitingStep = errors.New("no step of the instance waits for the signal")
+)
+
+const maxSignalNameLength = 255
+
+// Service delivers external events to workflow instances. The engine has no event steps,
+// a signal completes the human step of the same name that waits for a decision,
+// its payload is recorded as the decision comment.
+type Service struct {
+	tx            db.TxManager
+	signalsRepo   contract.InstanceSignalsRepository
+	workflowsRepo contract.WorkflowsRepository
+	engine        contract.HumanDecisionMaker
+}
+
+func New(
+	tx db.TxManager,
+	signalsRepo contract.InstanceSignalsRepository,
+	workflowsRepo contract.WorkflowsRepository,
+	engine contract.HumanDecisionMaker,
+) *Service {
+	return &Service{
+		tx:            tx,
+		signalsRepo:   signalsRepo,
+		workflowsRepo: workflowsRepo,
+		engine:        engine,
+	}
+}
+
+func (s *Service) Send(
+	ctx context.Context,
+	tenantID domain.TenantID,
+	projectID domain.ProjectID,
+	instanceID int,
+	dto domain.InstanceSignalDTO,
+) (domain.InstanceSignal, bool, error) {
+	if err := validate(&dto); err != nil {
+		return domain.InstanceSignal{}, false, err
+	}
+
+	if _, err := s.workflowsRepo.GetWorkflowInstance(ctx, tenantID, projectID, instanceID); err != nil {
+		return domain.InstanceSignal{}, false, err
+	}
+
+	if dto.IdempotencyKey != nil {
+		signal, err := s.signalsRepo.GetByIdempotencyKey(ctx, instanceID, *dto.IdempotencyKey)
+		if err == nil {
+			return signal, true, nil
+		}
+		if !errors.Is(err, domain.ErrEntityNotFound) {
+			return domain.InstanceSignal{}, false, fmt.Errorf("get signal: %w", err)
+		}
+	}
+
+	step, err := s.signalsRepo.FindWaitingStep(ctx, instanceID, dto.Name)
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			return domain.InstanceSignal{}, false, ErrNoWaitingStep
+		}
+
+		return domain.InstanceSignal{}, false, fmt.Errorf("find waiting step: %w", err)
+	}
+
+	var signal domain.InstanceSignal
+	err = s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		var err error
+		// The record is written first: a concurrent delivery with the same key waits on it
+		signal, err = s.signalsRepo.Create(ctx, domain.InstanceSignal{
+			InstanceID:     instanceID,
+			ProjectID:      projectID,
+			StepID:         step.ID,
+			Name:           dto.Name,
+			Payload:        dto.Payload,
+			Decision:       dto.Decision,
+			IdempotencyKey: dto.IdempotencyKey,
+		})
+		if err != nil {
+			return err
+		}
+
+		var comment *string
+		if len(dto.Payload) > 0 {
+			payload := string(dto.Payload)
+			comment = &payload
+		}
+
+		return s.engine.MakeHumanDecision(ctx, int64(step.ID), appcontext.Username(ctx),
+			floxy.HumanDecision(dto.Decision), comment)
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityAlreadyExists) && dto.IdempotencyKey != nil {
+			signal, err = s.signalsRepo.GetByIdempotencyKey(ctx, instanceID, *dto.IdempotencyKey)
+			if err != nil {
+				return domain.InstanceSignal{}, false, fmt.Errorf("get signal: %w", err)
+			}
+
+			return signal, true, nil
+		}
+
+		return domain.InstanceSignal{}, false, fmt.Errorf("send signal: %w", err)
+	}
+
+	return signal, false, nil
+}
+
+func validate(dto *domain.InstanceSignalDTO) error {
+	if dto.Name == "" || len(dto.Name) > maxSignalNameLength {
+		return fmt.Errorf("%w: name is required and must be at most %d characters",
+			ErrInvalidSignal, maxSignalNameLength)
+	}
+
+	switch floxy.HumanDecision(dto.Decision) {
+	case "":
+		dto.Decision = string(floxy.HumanDecisionConfirmed)
+	case floxy.HumanDecisionConfirmed, floxy.HumanDecisionRejected:
+	default:
+		return fmt.Errorf("%w: decision must be %q or %q",
+			ErrInvalidSignal, floxy.HumanDecisionConfirmed, floxy.HumanDecisionRejected)
+	}
+
+	if len(dto.Payload) > 0 && !json.Valid(dto.Payload) {
+		return fmt.Errorf("%w: payload must be valid JSON", ErrInvalidSignal)
+	}
+
+	if dto.IdempotencyKey != nil && (*dto.IdempotencyKey == "" || len(*dto.IdempotencyKey) > maxSignalNameLength) {
+		return fmt.Errorf("%w: idempotency key must be 1 to %d characters", ErrInvalidSignal, maxSignalNameLength)
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package instancesignals
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	floxy "github.com/rom8726/floxy-pro"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type fakeTx struct{}
+
+func (fakeTx) ReadCommitted(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (fakeTx) RepeatableRead(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type fakeWorkflowsRepo struct {
+	contract.WorkflowsRepository
+}
+
+func (fakeWorkflowsRepo) GetWorkflowInstance(
+	_ context.Context,
+	_ domain.TenantID,
+	_ domain.ProjectID,
+	id int,
+) (domain.WorkflowInstance, error) {
+	return domain.WorkflowInstance{ID: id}, nil
+}
+
+// fakeSignalsRepo has a single step named "approval" waiting until it is signalled.
+type fakeSignalsRepo struct {
+	signals []domain.InstanceSignal
+}
+
+func (r *fakeSignalsRepo) FindWaitingStep(_ context.Context, instanceID int, name string) (domain.WorkflowStep, error) {
+	if name != "approval" || len(r.signals) > 0 {
+		return domain.WorkflowStep{}, domain.ErrEntityNotFound
+	}
+
+	return domain.WorkflowStep{ID: 11, InstanceID: instanceID, StepName: name}, nil
+}
+
+func (r *fakeSignalsRepo) Create(_ context.Context, signal domain.InstanceSignal) (domain.InstanceSignal, error) {
+	signal.ID = int64(len(r.signals) + 1)
+	r.signals = append(r.signals, signal)
+
+	return signal, nil
+}
+
+func (r *fakeSignalsRepo) GetByIdempotencyKey(_ context.Context, _ int, key string) (domain.InstanceSignal, error) {
+	for _, signal := range r.signals {
+		if signal.IdempotencyKey != nil && *signal.IdempotencyKey == key {
+			return signal, nil
+		}
+	}
+
+	return domain.InstanceSignal{}, domain.ErrEntityNotFound
+}
+
+type fakeEngine struct {
+	decisions []floxy.HumanDecision
+	comments  []*string
+}
+
+func (e *fakeEngine) MakeHumanDecision(
+	_ context.Context,
+	_ int64,
+	_ string,
+	decision floxy.HumanDecision,
+	comment *string,
+) error {
+	e.decisions = append(e.decisions, decision)
+	e.comments = append(e.comments, comment)
+
+	return nil
+}
+
+func TestService_Send(t *testing.T) {
+	ctx := context.Background()
+	engine := &fakeEngine{}
+	srv := New(fakeTx{}, &fakeSignalsRepo{}, fakeWorkflowsRepo{}, engine)
+	key := "callback-1"
+
+	_, _, err := srv.Send(ctx, 1, 2, 3, domain.InstanceSignalDTO{Name: "approval", Decision: "maybe"})
+	require.ErrorIs(t, err, ErrInvalidSignal)
+
+	signal, replayed, err := srv.Send(ctx, 1, 2, 3, domain.InstanceSignalDTO{
+		Name:           "approval",
+		Payload:        json.RawMessage(`{"paid":true}`),
+		IdempotencyKey: &key,
+	})
+	require.NoError(t, err)
+	assert.False(t, replayed)
+	assert.Equal(t, 11, signal.StepID)
+	assert.Equal(t, string(floxy.HumanDecisionConfirmed), signal.Decision)
+
+	again, replayed, err := srv.Send(ctx, 1, 2, 3, domain.InstanceSignalDTO{Name: "approval", IdempotencyKey: &key})
+	require.NoError(t, err)
+	assert.True(t, replayed)
+	assert.Equal(t, signal.ID, again.ID)
+
+	_, _, err = srv.Send(ctx, 1, 2, 3, domain.InstanceSignalDTO{Name: "approval"})
+	assert.ErrorIs(t, err, ErrNoWaitingStep)
+
+	require.Len(t, engine.decisions, 1)
+	require.NotNil(t, engine.comments[0])
+	assert.JSONEq(t, `{"paid":true}`, *engine.comments[0])
+}
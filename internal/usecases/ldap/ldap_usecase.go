@@ -13,6 +13,8 @@ import (
 	"github.com/rom8726/floxy-manager/internal/domain"
 )
 
+var ErrInvalidGroupMapping = errors.New("invalid LDAP group mapping")
+
 type UseCase struct {
 	ldapService       contract.LDAPService
 	ldapSyncLogsRepo  contract.LDAPSyncLogsRepository
@@ -178,6 +180,12 @@ func (uc *UseCase) GetStatistics(ctx context.Context) (domain.LDAPStatistics, er
 }
 
 func (uc *UseCase) UpdateConfig(ctx context.Context, cfg *domain.LDAPConfig) error {
+	for i, mapping := range cfg.GroupMappings {
+		if mapping.Group == "" || mapping.ProjectID <= 0 || mapping.RoleKey == "" {
+			return fmt.Errorf("%w: entry %d requires group, project_id and role_key", ErrInvalidGroupMapping, i)
+		}
+	}
+
 	if err := uc.settingsUseCase.UpdateLDAPConfig(ctx, cfg); err != nil {
 		return fmt.Errorf("failed to update LDAP config: %w", err)
 	}
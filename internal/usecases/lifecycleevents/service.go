@@ -0,0 +1,80 @@
+package lifecycleevents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.LifecycleEventsUseCase = (*Service)(nil)
+
+const (
+	cursorName = "lifecycle_events"
+	// lateCommitOverlap re-reads a short window before the cursor so that rows
+	// committed with an earlier timestamp after the previous read are not missed.
+	lateCommitOverlap = time.Minute
+	// maxWindow bounds the amount of history read in one call after a downtime.
+	maxWindow = time.Hour
+)
+
+type Service struct {
+	tx         db.TxManager
+	eventsRepo contract.LifecycleEventsRepository
+}
+
+func New(tx db.TxManager, eventsRepo contract.LifecycleEventsRepository) *Service {
+	return &Service{
+		tx:         tx,
+		eventsRepo: eventsRepo,
+	}
+}
+
+// Dispatch passes events that occurred since the previous call to the handlers and advances
+// the cursor in the same transaction. The first call only initializes the cursor: the history
+// before the notifier was started is not replayed.
+func (s *Service) Dispatch(
+	ctx context.Context,
+	now time.Time,
+	handlers ...contract.LifecycleEventHandler,
+) (int, error) {
+	var count int
+	err := s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		from, err := s.eventsRepo.GetCursor(ctx, cursorName)
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			return s.eventsRepo.SaveCursor(ctx, cursorName, now)
+		}
+		if err != nil {
+			return err
+		}
+
+		to := now
+		if to.Sub(from) > maxWindow {
+			to = from.Add(maxWindow)
+		}
+
+		events, err := s.eventsRepo.List(ctx, from.Add(-lateCommitOverlap), to)
+		if err != nil {
+			return err
+		}
+
+		for _, handler := range handlers {
+			if err := handler.HandleEvents(ctx, events); err != nil {
+				return err
+			}
+		}
+
+		count = len(events)
+
+		return s.eventsRepo.SaveCursor(ctx, cursorName, to)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("dispatch lifecycle events: %w", err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,47 @@
+package notificationchannels
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+// describe builds a human-readable message for the event.
+func describe(event domain.LifecycleEvent, now time.Time) domain.Notification {
+	content := domain.Notification{
+		Facts: []domain.NotificationFact{
+			{Name: "Project", Value: event.ProjectID.String()},
+			{Name: "Workflow", Value: event.WorkflowID},
+			{Name: "Instance", Value: strconv.FormatInt(event.InstanceID, 10)},
+		},
+	}
+
+	switch event.Type {
+	case domain.EventInstanceFailed:
+		content.Subject = "Workflow instance failed"
+		content.Color = "D70000"
+	case domain.EventDLQItemCreated:
+		content.Subject = "Workflow step moved to the dead letter queue"
+		content.Color = "E67E22"
+		if event.StepName != nil {
+			content.Facts = append(content.Facts, domain.NotificationFact{Name: "Step", Value: *event.StepName})
+		}
+	case domain.EventLongRunningInstance:
+		content.Subject = "Workflow instance is running too long"
+		content.Color = "F1C40F"
+		content.Facts = append(content.Facts, domain.NotificationFact{
+			Name:  "Running for",
+			Value: now.Sub(event.OccurredAt).Round(time.Minute).String(),
+		})
+	default:
+		content.Subject = "Workflow event: " + string(event.Type)
+		content.Color = "808080"
+	}
+
+	if event.Error != nil && *event.Error != "" {
+		content.Facts = append(content.Facts, domain.NotificationFact{Name: "Error", Value: *event.Error})
+	}
+
+	return content
+}
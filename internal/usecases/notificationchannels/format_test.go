@@ -0,0 +1,68 @@
+package notificationchannels
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+func TestDescribe(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	errMsg := "boom"
+
+	content := describe(domain.LifecycleEvent{
+		Type:       domain.EventInstanceFailed,
+		ProjectID:  3,
+		WorkflowID: "orders-v2",
+		InstanceID: 42,
+		Error:      &errMsg,
+	}, now)
+
+	assert.Equal(t, "Workflow instance failed", content.Subject)
+	assert.Equal(t, []domain.NotificationFact{
+		{Name: "Project", Value: "3"},
+		{Name: "Workflow", Value: "orders-v2"},
+		{Name: "Instance", Value: "42"},
+		{Name: "Error", Value: "boom"},
+	}, content.Facts)
+
+	content = describe(domain.LifecycleEvent{
+		Type:       domain.EventLongRunningInstance,
+		ProjectID:  3,
+		WorkflowID: "orders-v2",
+		InstanceID: 43,
+		OccurredAt: now.Add(-90 * time.Minute),
+	}, now)
+
+	assert.Equal(t, "Workflow instance is running too long", content.Subject)
+	assert.Contains(t, content.Facts, domain.NotificationFact{Name: "Running for", Value: "1h30m0s"})
+}
+
+func TestValidate(t *testing.T) {
+	dto := domain.NotificationChannelDTO{
+		Name:       "ops",
+		Kind:       domain.NotificationChannelTeams,
+		WebhookURL: "https://example.webhook.office.com/webhookb2/abc",
+		Channel:    "#ignored",
+		Events:     []domain.LifecycleEventType{domain.EventLongRunningInstance},
+	}
+	require.NoError(t, validate(&dto))
+	assert.Empty(t, dto.Channel)
+	assert.Equal(t, defaultLongRunningThreshold, dto.LongRunningThresholdSeconds)
+
+	invalid := dto
+	invalid.Kind = "discord"
+	assert.ErrorIs(t, validate(&invalid), ErrInvalidChannel)
+
+	invalid = dto
+	invalid.WebhookURL = "http://hooks.slack.com/services/x"
+	assert.ErrorIs(t, validate(&invalid), ErrInvalidChannel)
+
+	invalid = dto
+	invalid.Events = []domain.LifecycleEventType{domain.EventInstanceCompleted}
+	assert.ErrorIs(t, validate(&invalid), ErrInvalidChannel)
+}
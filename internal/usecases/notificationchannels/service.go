@@ -0,0 +1,405 @@
+package notificationchannels
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/crypt"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.NotificationChannelsUseCase = (*Service)(nil)
+
+var ErrInvalidChannel = errors.New("invalid notification channel")
+
+const (
+	defaultLongRunningThreshold = 3600
+	sendBatchSize               = 50
+	maxAttempts                 = 3
+	retryDelay                  = time.Minute
+)
+
+var supportedEvents = map[domain.LifecycleEventType]struct{}{
+	domain.EventInstanceFailed:      {},
+	domain.EventDLQItemCreated:      {},
+	domain.EventLongRunningInstance: {},
+}
+
+type Service struct {
+	tx              db.TxManager
+	channelsRepo    contract.NotificationChannelsRepository
+	eventsRepo      contract.LifecycleEventsRepository
+	projectSettings contract.ProjectSettingsReader
+	notifier        contract.NotificationRouter
+	secret          []byte
+}
+
+func New(
+	tx db.TxManager,
+	channelsRepo contract.NotificationChannelsRepository,
+	eventsRepo contract.LifecycleEventsRepository,
+	projectSettings contract.ProjectSettingsReader,
+	notifier contract.NotificationRouter,
+	secret string,
+) *Service {
+	return &Service{
+		tx:              tx,
+		channelsRepo:    channelsRepo,
+		eventsRepo:      eventsRepo,
+		projectSettings: projectSettings,
+		notifier:        notifier,
+		secret:          []byte(secret),
+	}
+}
+
+func (s *Service) List(ctx context.Context, projectID domain.ProjectID) ([]domain.NotificationChannel, error) {
+	return s.channelsRepo.List(ctx, projectID)
+}
+
+func (s *Service) ListWithWebhookURLs(
+	ctx context.Context,
+	projectID domain.ProjectID,
+) ([]domain.NotificationChannel, error) {
+	channels, err := s.channelsRepo.List(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range channels {
+		channels[i].WebhookURL, err = s.decrypt(channels[i].WebhookURLEncrypted)
+		if err != nil {
+			return nil, fmt.Errorf("channel %d: %w", channels[i].ID, err)
+		}
+	}
+
+	return channels, nil
+}
+
+func (s *Service) Get(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	id domain.NotificationChannelID,
+) (domain.NotificationChannel, error) {
+	return s.channelsRepo.GetByID(ctx, projectID, id)
+}
+
+func (s *Service) Create(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	dto domain.NotificationChannelDTO,
+) (domain.NotificationChannel, error) {
+	if dto.WebhookURL == "" {
+		return domain.NotificationChannel{}, fmt.Errorf("%w: webhook_url is required", ErrInvalidChannel)
+	}
+
+	// Channels created without events or threshold get the notification defaults of the project
+	settings, err := s.projectSettings.GetSettings(ctx, projectID)
+	if err != nil {
+		return domain.NotificationChannel{}, err
+	}
+
+	if len(dto.Events) == 0 {
+		dto.Events = settings.Notifications.Events
+	}
+
+	if dto.LongRunningThresholdSeconds == 0 {
+		dto.LongRunningThresholdSeconds = settings.Notifications.LongRunningThresholdSeconds
+	}
+
+	if err := validate(&dto); err != nil {
+		return domain.NotificationChannel{}, err
+	}
+
+	webhookURLEncrypted, err := s.encrypt(dto.WebhookURL)
+	if err != nil {
+		return domain.NotificationChannel{}, err
+	}
+
+	var channel domain.NotificationChannel
+	err = s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		id, err := s.channelsRepo.Create(ctx, projectID, dto, webhookURLEncrypted)
+		if err != nil {
+			return err
+		}
+
+		channel, err = s.channelsRepo.GetByID(ctx, projectID, id)
+
+		return err
+	})
+	if err != nil {
+		return domain.NotificationChannel{}, fmt.Errorf("create notification channel: %w", err)
+	}
+
+	return channel, nil
+}
+
+func (s *Service) Update(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	id domain.NotificationChannelID,
+	dto domain.NotificationChannelDTO,
+) (domain.NotificationChannel, error) {
+	if err := validate(&dto); err != nil {
+		return domain.NotificationChannel{}, err
+	}
+
+	var webhookURLEncrypted string
+	if dto.WebhookURL != "" {
+		var err error
+		webhookURLEncrypted, err = s.encrypt(dto.WebhookURL)
+		if err != nil {
+			return domain.NotificationChannel{}, err
+		}
+	}
+
+	var channel domain.NotificationChannel
+	err := s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		if err := s.channelsRepo.Update(ctx, projectID, id, dto, webhookURLEncrypted); err != nil {
+			return err
+		}
+
+		var err error
+		channel, err = s.channelsRepo.GetByID(ctx, projectID, id)
+
+		return err
+	})
+	if err != nil {
+		return domain.NotificationChannel{}, fmt.Errorf("update notification channel: %w", err)
+	}
+
+	return channel, nil
+}
+
+func (s *Service) Delete(ctx context.Context, projectID domain.ProjectID, id domain.NotificationChannelID) error {
+	return s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		return s.channelsRepo.Delete(ctx, projectID, id)
+	})
+}
+
+func (s *Service) ListMessages(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	id domain.NotificationChannelID,
+	page, pageSize int,
+) ([]domain.NotificationMessage, int, error) {
+	if _, err := s.channelsRepo.GetByID(ctx, projectID, id); err != nil {
+		return nil, 0, err
+	}
+
+	return s.channelsRepo.ListMessages(ctx, id, page, pageSize)
+}
+
+// HandleEvents enqueues a message for every channel subscribed to the event.
+func (s *Service) HandleEvents(ctx context.Context, events []domain.LifecycleEvent) error {
+	type subscriptionKey struct {
+		projectID domain.ProjectID
+		eventType domain.LifecycleEventType
+	}
+
+	subscriptions := make(map[subscriptionKey][]domain.NotificationChannel)
+
+	for i := range events {
+		event := events[i]
+		if _, ok := supportedEvents[event.Type]; !ok {
+			continue
+		}
+
+		key := subscriptionKey{projectID: event.ProjectID, eventType: event.Type}
+		channels, ok := subscriptions[key]
+		if !ok {
+			var err error
+			channels, err = s.channelsRepo.ListSubscribed(ctx, event.ProjectID, event.Type)
+			if err != nil {
+				return fmt.Errorf("list subscribed notification channels: %w", err)
+			}
+
+			subscriptions[key] = channels
+		}
+
+		if err := s.enqueue(ctx, channels, event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) CheckLongRunning(ctx context.Context, now time.Time) error {
+	channels, err := s.channelsRepo.ListSubscribed(ctx, 0, domain.EventLongRunningInstance)
+	if err != nil {
+		return fmt.Errorf("list subscribed notification channels: %w", err)
+	}
+
+	for i := range channels {
+		channel := channels[i]
+		startedBefore := now.Add(-time.Duration(channel.LongRunningThresholdSeconds) * time.Second)
+
+		events, err := s.eventsRepo.ListLongRunning(ctx, channel.ProjectID, startedBefore)
+		if err != nil {
+			return fmt.Errorf("list long running instances: %w", err)
+		}
+
+		for j := range events {
+			if err := s.enqueue(ctx, []domain.NotificationChannel{channel}, events[j]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) SendPending(ctx context.Context, now time.Time) (int, error) {
+	due, err := s.channelsRepo.ListDueMessages(ctx, now, sendBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("list due notification messages: %w", err)
+	}
+
+	sent := 0
+	for i := range due {
+		message := due[i]
+		message.Attempts++
+
+		if err := s.send(ctx, message, now); err == nil {
+			sentAt := time.Now()
+			message.Status = domain.NotificationMessageSent
+			message.SentAt = &sentAt
+			message.NextAttemptAt = nil
+			message.LastError = nil
+			sent++
+		} else {
+			errMsg := err.Error()
+			message.LastError = &errMsg
+
+			if message.Attempts >= maxAttempts {
+				message.Status = domain.NotificationMessageFailed
+				message.NextAttemptAt = nil
+
+				slog.Warn("Notification message failed permanently",
+					"error", err,
+					"channel_id", message.ChannelID,
+					"message_id", message.ID,
+				)
+			} else {
+				nextAttemptAt := now.Add(time.Duration(message.Attempts) * retryDelay)
+				message.NextAttemptAt = &nextAttemptAt
+			}
+		}
+
+		if err := s.channelsRepo.SaveMessageAttempt(ctx, message); err != nil {
+			return sent, fmt.Errorf("save notification message %d: %w", message.ID, err)
+		}
+	}
+
+	return sent, nil
+}
+
+func (s *Service) enqueue(
+	ctx context.Context,
+	channels []domain.NotificationChannel,
+	event domain.LifecycleEvent,
+) error {
+	now := time.Now()
+
+	for _, channel := range channels {
+		_, err := s.channelsRepo.EnqueueMessage(ctx, domain.NotificationMessage{
+			ChannelID:     channel.ID,
+			EventType:     event.Type,
+			EventKey:      event.Key(),
+			Event:         event,
+			NextAttemptAt: &now,
+		})
+		if err != nil {
+			return fmt.Errorf("enqueue notification channel %d message: %w", channel.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) send(ctx context.Context, message domain.NotificationMessage, now time.Time) error {
+	webhookURL, err := s.decrypt(message.Channel.WebhookURLEncrypted)
+	if err != nil {
+		return err
+	}
+
+	target := domain.NotificationTarget{
+		Kind:    domain.NotificationTargetKind(message.Channel.Kind),
+		Address: webhookURL,
+		Channel: message.Channel.Channel,
+	}
+
+	return s.notifier.Notify(ctx, target, describe(message.Event, now))
+}
+
+func (s *Service) encrypt(value string) (string, error) {
+	encrypted, err := crypt.EncryptAESGCM([]byte(value), s.secret)
+	if err != nil {
+		return "", fmt.Errorf("encrypt webhook url: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(encrypted), nil
+}
+
+func (s *Service) decrypt(value string) (string, error) {
+	encrypted, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return "", fmt.Errorf("decode webhook url: %w", err)
+	}
+
+	decrypted, err := crypt.DecryptAESGCM(encrypted, s.secret)
+	if err != nil {
+		return "", fmt.Errorf("decrypt webhook url: %w", err)
+	}
+
+	return string(decrypted), nil
+}
+
+// validate checks the DTO and fills defaults. An empty webhook URL is allowed (kept on update).
+func validate(dto *domain.NotificationChannelDTO) error {
+	if dto.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidChannel)
+	}
+
+	switch dto.Kind {
+	case domain.NotificationChannelSlack:
+	case domain.NotificationChannelTeams:
+		dto.Channel = ""
+	default:
+		return fmt.Errorf("%w: kind must be one of slack, teams", ErrInvalidChannel)
+	}
+
+	if dto.WebhookURL != "" {
+		u, err := url.Parse(dto.WebhookURL)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("%w: webhook_url must be an absolute https URL", ErrInvalidChannel)
+		}
+	}
+
+	if len(dto.Events) == 0 {
+		return fmt.Errorf("%w: at least one event is required", ErrInvalidChannel)
+	}
+
+	for _, event := range dto.Events {
+		if _, ok := supportedEvents[event]; !ok {
+			return fmt.Errorf("%w: unsupported event %q", ErrInvalidChannel, event)
+		}
+	}
+
+	if dto.LongRunningThresholdSeconds < 0 {
+		return fmt.Errorf("%w: long_running_threshold_seconds must be positive", ErrInvalidChannel)
+	}
+
+	if dto.LongRunningThresholdSeconds == 0 {
+		dto.LongRunningThresholdSeconds = defaultLongRunningThreshold
+	}
+
+	return nil
+}
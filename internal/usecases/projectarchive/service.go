@@ -0,0 +1,521 @@
+package projectarchive
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/usecases/alerts"
+	"github.com/rom8726/floxy-manager/internal/usecases/notificationchannels"
+	"github.com/rom8726/floxy-manager/internal/usecases/schedules"
+	"github.com/rom8726/floxy-manager/internal/usecases/webhooks"
+	"github.com/rom8726/floxy-manager/internal/usecases/workflows"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.ProjectArchiveUseCase = (*Service)(nil)
+
+// ErrImportRejected is returned when an archived entity fails validation, nothing is created then.
+var ErrImportRejected = errors.New("project import rejected")
+
+const listPageSize = 100
+
+type Service struct {
+	tx               db.TxManager
+	projectsUseCase  contract.ProjectsUseCase
+	workflowsRepo    contract.WorkflowsRepository
+	workflowsUseCase contract.WorkflowsUseCase
+	schedulesUseCase contract.SchedulesUseCase
+	channelsUseCase  contract.NotificationChannelsUseCase
+	webhooksUseCase  contract.WebhooksUseCase
+	alertsRepo       contract.AlertsRepository
+	alertsUseCase    contract.AlertsUseCase
+	membershipsRepo  contract.MembershipsRepository
+	membershipsSrv   contract.MembershipsUseCase
+	usersRepo        contract.UsersRepository
+	rolesRepo        contract.RolesRepository
+}
+
+func New(
+	tx db.TxManager,
+	projectsUseCase contract.ProjectsUseCase,
+	workflowsRepo contract.WorkflowsRepository,
+	workflowsUseCase contract.WorkflowsUseCase,
+	schedulesUseCase contract.SchedulesUseCase,
+	channelsUseCase contract.NotificationChannelsUseCase,
+	webhooksUseCase contract.WebhooksUseCase,
+	alertsRepo contract.AlertsRepository,
+	alertsUseCase contract.AlertsUseCase,
+	membershipsRepo contract.MembershipsRepository,
+	membershipsSrv contract.MembershipsUseCase,
+	usersRepo contract.UsersRepository,
+	rolesRepo contract.RolesRepository,
+) *Service {
+	return &Service{
+		tx:               tx,
+		projectsUseCase:  projectsUseCase,
+		workflowsRepo:    workflowsRepo,
+		workflowsUseCase: workflowsUseCase,
+		schedulesUseCase: schedulesUseCase,
+		channelsUseCase:  channelsUseCase,
+		webhooksUseCase:  webhooksUseCase,
+		alertsRepo:       alertsRepo,
+		alertsUseCase:    alertsUseCase,
+		membershipsRepo:  membershipsRepo,
+		membershipsSrv:   membershipsSrv,
+		usersRepo:        usersRepo,
+		rolesRepo:        rolesRepo,
+	}
+}
+
+func (s *Service) Export(
+	ctx context.Context,
+	tenantID domain.TenantID,
+	projectID domain.ProjectID,
+	includeSecrets bool,
+) (*domain.ProjectArchive, error) {
+	project, err := s.projectsUseCase.GetProject(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("get project: %w", err)
+	}
+
+	archive := &domain.ProjectArchive{
+		FormatVersion: domain.ProjectArchiveFormatVersion,
+		ExportedAt:    time.Now().UTC(),
+		Project:       domain.ProjectArchiveProject{Name: project.Name, Description: project.Description},
+	}
+
+	if archive.Workflows, err = s.exportWorkflows(ctx, tenantID, projectID); err != nil {
+		return nil, err
+	}
+
+	if archive.Schedules, err = s.exportSchedules(ctx, projectID); err != nil {
+		return nil, err
+	}
+
+	if archive.NotificationChannels, err = s.exportChannels(ctx, projectID, includeSecrets); err != nil {
+		return nil, err
+	}
+
+	webhooks, err := s.webhooksUseCase.List(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("list webhooks: %w", err)
+	}
+
+	archive.Webhooks = make([]domain.ProjectArchiveWebhook, 0, len(webhooks))
+	for i := range webhooks {
+		archive.Webhooks = append(archive.Webhooks, domain.ProjectArchiveWebhook{
+			Name:    webhooks[i].Name,
+			URL:     webhooks[i].URL,
+			Events:  webhooks[i].Events,
+			Enabled: webhooks[i].Enabled,
+		})
+	}
+
+	settings, err := s.alertsRepo.GetSettings(ctx, projectID)
+	switch {
+	case err == nil:
+		archive.AlertSettings = &domain.ProjectArchiveAlertSettings{
+			Enabled:                  settings.Enabled,
+			RecipientRoleKey:         settings.RecipientRoleKey,
+			NotifyOnFailure:          settings.NotifyOnFailure,
+			DurationThresholdSeconds: settings.DurationThresholdSeconds,
+			DigestEnabled:            settings.DigestEnabled,
+		}
+	case !errors.Is(err, domain.ErrEntityNotFound):
+		return nil, fmt.Errorf("get alert settings: %w", err)
+	}
+
+	if archive.Memberships, err = s.exportMemberships(ctx, projectID); err != nil {
+		return nil, err
+	}
+
+	return archive, nil
+}
+
+func (s *Service) exportWorkflows(
+	ctx context.Context,
+	tenantID domain.TenantID,
+	projectID domain.ProjectID,
+) ([]domain.ProjectArchiveWorkflow, error) {
+	var result []domain.ProjectArchiveWorkflow
+
+	for page := 1; ; page++ {
+		items, total, err := s.workflowsRepo.ListWorkflowDefinitions(ctx, tenantID, projectID, page, listPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("list workflow definitions: %w", err)
+		}
+
+		for i := range items {
+			result = append(result, domain.ProjectArchiveWorkflow{
+				Name:       items[i].Name,
+				Version:    items[i].Version,
+				Definition: items[i].Definition,
+			})
+		}
+
+		if len(items) == 0 || page*listPageSize >= total {
+			break
+		}
+	}
+
+	// Older versions first, so that the archive reads like the project history
+	slices.SortFunc(result, func(a, b domain.ProjectArchiveWorkflow) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.Version, b.Version))
+	})
+
+	return result, nil
+}
+
+func (s *Service) exportSchedules(
+	ctx context.Context,
+	projectID domain.ProjectID,
+) ([]domain.ProjectArchiveSchedule, error) {
+	result := make([]domain.ProjectArchiveSchedule, 0)
+
+	for page := 1; ; page++ {
+		items, total, err := s.schedulesUseCase.List(ctx, projectID, page, listPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("list schedules: %w", err)
+		}
+
+		for i := range items {
+			result = append(result, domain.ProjectArchiveSchedule{
+				WorkflowID: items[i].WorkflowID,
+				Name:       items[i].Name,
+				CronExpr:   items[i].CronExpr,
+				Timezone:   items[i].Timezone,
+				Input:      items[i].Input,
+				Enabled:    items[i].Enabled,
+			})
+		}
+
+		if len(items) == 0 || page*listPageSize >= total {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+func (s *Service) exportChannels(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	includeSecrets bool,
+) ([]domain.ProjectArchiveChannel, error) {
+	list := s.channelsUseCase.List
+	if includeSecrets {
+		list = s.channelsUseCase.ListWithWebhookURLs
+	}
+
+	channels, err := list(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("list notification channels: %w", err)
+	}
+
+	result := make([]domain.ProjectArchiveChannel, 0, len(channels))
+	for i := range channels {
+		result = append(result, domain.ProjectArchiveChannel{
+			Name:                        channels[i].Name,
+			Kind:                        channels[i].Kind,
+			WebhookURL:                  channels[i].WebhookURL,
+			Channel:                     channels[i].Channel,
+			Events:                      channels[i].Events,
+			LongRunningThresholdSeconds: channels[i].LongRunningThresholdSeconds,
+			Enabled:                     channels[i].Enabled,
+		})
+	}
+
+	return result, nil
+}
+
+// exportMemberships exports the manual memberships of people. LDAP memberships are recreated
+// by the group mappings of the target environment and service accounts are bound to the project.
+func (s *Service) exportMemberships(
+	ctx context.Context,
+	projectID domain.ProjectID,
+) ([]domain.ProjectArchiveMembership, error) {
+	memberships, err := s.membershipsRepo.ListForProject(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("list project memberships: %w", err)
+	}
+
+	now := time.Now()
+	userIDs := make([]domain.UserID, 0, len(memberships))
+	for i := range memberships {
+		userIDs = append(userIDs, memberships[i].UserID)
+	}
+
+	users, err := s.usersRepo.FetchByIDs(ctx, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("fetch members: %w", err)
+	}
+
+	usersByID := make(map[domain.UserID]*domain.User, len(users))
+	for i := range users {
+		usersByID[users[i].ID] = &users[i]
+	}
+
+	result := make([]domain.ProjectArchiveMembership, 0, len(memberships))
+	for i := range memberships {
+		membership := &memberships[i]
+		user, ok := usersByID[membership.UserID]
+		if !ok || user.IsServiceAccount || membership.Source == domain.MembershipSourceLDAP ||
+			membership.Expired(now) {
+			continue
+		}
+
+		result = append(result, domain.ProjectArchiveMembership{
+			Username:   user.Username,
+			Email:      user.Email,
+			RoleKey:    membership.RoleKey,
+			ValidUntil: membership.ValidUntil,
+		})
+	}
+
+	return result, nil
+}
+
+func (s *Service) Import(
+	ctx context.Context,
+	tenantID domain.TenantID,
+	name string,
+	archive *domain.ProjectArchive,
+) (domain.ProjectImportResult, error) {
+	if name == "" {
+		name = archive.Project.Name
+	}
+
+	if name == "" {
+		return domain.ProjectImportResult{}, fmt.Errorf("%w: project name is required", ErrImportRejected)
+	}
+
+	var result domain.ProjectImportResult
+	err := s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		result = domain.ProjectImportResult{Warnings: []string{}}
+
+		project, err := s.projectsUseCase.CreateProject(ctx, name, archive.Project.Description, tenantID)
+		if err != nil {
+			return err
+		}
+
+		result.ProjectID = project.ID
+
+		items := make([]domain.WorkflowImportItem, 0, len(archive.Workflows))
+		for i := range archive.Workflows {
+			workflow := &archive.Workflows[i]
+			items = append(items, domain.WorkflowImportItem{
+				Source:     fmt.Sprintf("%s v%d", workflow.Name, workflow.Version),
+				Name:       workflow.Name,
+				Version:    workflow.Version,
+				Definition: workflow.Definition,
+			})
+		}
+
+		result.Workflows, err = s.workflowsUseCase.ImportDefinitions(ctx, project.ID, items, false)
+		if errors.Is(err, workflows.ErrImportRejected) {
+			return fmt.Errorf("%w: workflow definitions rejected", ErrImportRejected)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := s.importSettings(ctx, tenantID, project.ID, archive, &result); err != nil {
+			return err
+		}
+
+		return s.importMemberships(ctx, project.ID, archive.Memberships, &result)
+	})
+	if err != nil {
+		if errors.Is(err, ErrImportRejected) {
+			// The project was rolled back, the workflow results tell what was rejected
+			result.ProjectID = 0
+
+			return result, err
+		}
+
+		return domain.ProjectImportResult{}, fmt.Errorf("import project: %w", err)
+	}
+
+	return result, nil
+}
+
+func (s *Service) importSettings(
+	ctx context.Context,
+	tenantID domain.TenantID,
+	projectID domain.ProjectID,
+	archive *domain.ProjectArchive,
+	result *domain.ProjectImportResult,
+) error {
+	for i := range archive.Schedules {
+		schedule := &archive.Schedules[i]
+		_, err := s.schedulesUseCase.Create(ctx, tenantID, projectID, domain.ScheduleDTO{
+			WorkflowID: schedule.WorkflowID,
+			Name:       schedule.Name,
+			CronExpr:   schedule.CronExpr,
+			Timezone:   schedule.Timezone,
+			Input:      schedule.Input,
+			Enabled:    schedule.Enabled,
+		})
+		if err != nil {
+			return rejected(err, "schedule %q", schedule.Name)
+		}
+
+		result.Schedules++
+	}
+
+	for i := range archive.NotificationChannels {
+		channel := &archive.NotificationChannels[i]
+		if channel.WebhookURL == "" {
+			result.Warnings = append(result.Warnings,
+				fmt.Sprintf("notification channel %q skipped: the archive has no webhook URL", channel.Name))
+
+			continue
+		}
+
+		_, err := s.channelsUseCase.Create(ctx, projectID, domain.NotificationChannelDTO{
+			Name:                        channel.Name,
+			Kind:                        channel.Kind,
+			WebhookURL:                  channel.WebhookURL,
+			Channel:                     channel.Channel,
+			Events:                      channel.Events,
+			LongRunningThresholdSeconds: channel.LongRunningThresholdSeconds,
+			Enabled:                     channel.Enabled,
+		})
+		if err != nil {
+			return rejected(err, "notification channel %q", channel.Name)
+		}
+
+		result.NotificationChannels++
+	}
+
+	for i := range archive.Webhooks {
+		webhook := &archive.Webhooks[i]
+		_, err := s.webhooksUseCase.Create(ctx, projectID, domain.WebhookDTO{
+			Name:    webhook.Name,
+			URL:     webhook.URL,
+			Events:  webhook.Events,
+			Enabled: webhook.Enabled,
+		})
+		if err != nil {
+			return rejected(err, "webhook %q", webhook.Name)
+		}
+
+		result.Webhooks++
+	}
+
+	if settings := archive.AlertSettings; settings != nil {
+		_, err := s.alertsUseCase.UpdateSettings(ctx, domain.AlertSettings{
+			ProjectID:                projectID,
+			Enabled:                  settings.Enabled,
+			RecipientRoleKey:         settings.RecipientRoleKey,
+			NotifyOnFailure:          settings.NotifyOnFailure,
+			DurationThresholdSeconds: settings.DurationThresholdSeconds,
+			DigestEnabled:            settings.DigestEnabled,
+		})
+		if err != nil {
+			return rejected(err, "alert settings")
+		}
+	}
+
+	return nil
+}
+
+// importMemberships adds the archived members found in this environment, the others are reported as warnings.
+func (s *Service) importMemberships(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	memberships []domain.ProjectArchiveMembership,
+	result *domain.ProjectImportResult,
+) error {
+	now := time.Now()
+	added := make(map[domain.UserID]struct{}, len(memberships))
+
+	for i := range memberships {
+		membership := &memberships[i]
+		if membership.ValidUntil != nil && !membership.ValidUntil.After(now) {
+			continue
+		}
+
+		user, err := s.findUser(ctx, membership)
+		if err != nil {
+			return err
+		}
+
+		if user == nil || user.IsServiceAccount {
+			result.Warnings = append(result.Warnings,
+				fmt.Sprintf("membership of %q skipped: no such user", membership.Username))
+
+			continue
+		}
+
+		if _, ok := added[user.ID]; ok {
+			continue
+		}
+
+		role, err := s.rolesRepo.GetByKey(ctx, membership.RoleKey)
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			result.Warnings = append(result.Warnings,
+				fmt.Sprintf("membership of %q skipped: no such role %q", membership.Username, membership.RoleKey))
+
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("get role %q: %w", membership.RoleKey, err)
+		}
+
+		_, err = s.membershipsSrv.CreateProjectMembership(ctx, projectID, user.ID, role.ID, membership.ValidUntil)
+		if err != nil {
+			return fmt.Errorf("create membership of %q: %w", membership.Username, err)
+		}
+
+		added[user.ID] = struct{}{}
+		result.Memberships++
+	}
+
+	return nil
+}
+
+// findUser matches the member by username, then by email. It returns nil when none matches.
+func (s *Service) findUser(ctx context.Context, membership *domain.ProjectArchiveMembership) (*domain.User, error) {
+	user, err := s.usersRepo.GetByUsername(ctx, membership.Username)
+	if err == nil {
+		return &user, nil
+	}
+	if !errors.Is(err, domain.ErrEntityNotFound) {
+		return nil, fmt.Errorf("get user %q: %w", membership.Username, err)
+	}
+
+	if membership.Email == "" {
+		return nil, nil
+	}
+
+	user, err = s.usersRepo.GetByEmail(ctx, membership.Email)
+	if errors.Is(err, domain.ErrEntityNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get user by email %q: %w", membership.Email, err)
+	}
+
+	return &user, nil
+}
+
+// rejected turns validation errors of the entity usecases into ErrImportRejected.
+func rejected(err error, format string, args ...any) error {
+	entity := fmt.Sprintf(format, args...)
+
+	if errors.Is(err, schedules.ErrInvalidSchedule) ||
+		errors.Is(err, notificationchannels.ErrInvalidChannel) ||
+		errors.Is(err, webhooks.ErrInvalidWebhook) ||
+		errors.Is(err, alerts.ErrInvalidAlertSettings) {
+		return fmt.Errorf("%w: %s: %v", ErrImportRejected, entity, err)
+	}
+
+	return fmt.Errorf("%s: %w", entity, err)
+}
@@ -0,0 +1,176 @@
+package projectarchive
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/usecases/workflows"
+)
+
+type fakeTx struct{}
+
+func (fakeTx) ReadCommitted(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (fakeTx) RepeatableRead(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type fakeProjects struct {
+	contract.ProjectsUseCase
+}
+
+func (fakeProjects) CreateProject(
+	_ context.Context,
+	name, description string,
+	_ domain.TenantID,
+) (domain.Project, error) {
+	return domain.Project{ID: 7, Name: name, Description: description}, nil
+}
+
+type fakeWorkflows struct {
+	contract.WorkflowsUseCase
+	reject bool
+}
+
+func (f fakeWorkflows) ImportDefinitions(
+	_ context.Context,
+	_ domain.ProjectID,
+	items []domain.WorkflowImportItem,
+	_ bool,
+) ([]domain.WorkflowImportResult, error) {
+	results := make([]domain.WorkflowImportResult, 0, len(items))
+	for i := range items {
+		results = append(results, domain.WorkflowImportResult{Name: items[i].Name, Version: items[i].Version})
+	}
+
+	if f.reject {
+		return results, workflows.ErrImportRejected
+	}
+
+	return results, nil
+}
+
+type fakeUsers struct {
+	contract.UsersRepository
+	users []domain.User
+}
+
+func (r fakeUsers) GetByUsername(_ context.Context, username string) (domain.User, error) {
+	for i := range r.users {
+		if r.users[i].Username == username {
+			return r.users[i], nil
+		}
+	}
+
+	return domain.User{}, domain.ErrEntityNotFound
+}
+
+func (r fakeUsers) GetByEmail(_ context.Context, email string) (domain.User, error) {
+	for i := range r.users {
+		if r.users[i].Email == email {
+			return r.users[i], nil
+		}
+	}
+
+	return domain.User{}, domain.ErrEntityNotFound
+}
+
+type fakeRoles struct {
+	contract.RolesRepository
+}
+
+func (fakeRoles) GetByKey(_ context.Context, key string) (domain.Role, error) {
+	if key != "project_member" {
+		return domain.Role{}, domain.ErrEntityNotFound
+	}
+
+	return domain.Role{ID: "role-1", Key: key}, nil
+}
+
+type fakeMemberships struct {
+	contract.MembershipsUseCase
+	created []domain.UserID
+}
+
+func (m *fakeMemberships) CreateProjectMembership(
+	_ context.Context,
+	projectID domain.ProjectID,
+	userID domain.UserID,
+	roleID domain.RoleID,
+	_ *time.Time,
+) (domain.ProjectMembership, error) {
+	m.created = append(m.created, userID)
+
+	return domain.ProjectMembership{ProjectID: projectID, UserID: userID, RoleID: roleID}, nil
+}
+
+func TestService_Import(t *testing.T) {
+	memberships := &fakeMemberships{}
+	srv := &Service{
+		tx:               fakeTx{},
+		projectsUseCase:  fakeProjects{},
+		workflowsUseCase: fakeWorkflows{},
+		usersRepo: fakeUsers{users: []domain.User{
+			{ID: 1, Username: "alice", Email: "alice@example.com"},
+			{ID: 2, Username: "bob.smith", Email: "bob@example.com"},
+			{ID: 3, Username: "ci", IsServiceAccount: true},
+		}},
+		rolesRepo:      fakeRoles{},
+		membershipsSrv: memberships,
+	}
+
+	expired := time.Now().Add(-time.Hour)
+	archive := &domain.ProjectArchive{
+		Project:              domain.ProjectArchiveProject{Name: "payments"},
+		Workflows:            []domain.ProjectArchiveWorkflow{{Name: "charge", Version: 1}},
+		NotificationChannels: []domain.ProjectArchiveChannel{{Name: "ops"}},
+		Memberships: []domain.ProjectArchiveMembership{
+			{Username: "alice", RoleKey: "project_member"},
+			{Username: "bob", Email: "bob@example.com", RoleKey: "project_member"},
+			{Username: "carol", RoleKey: "project_member"},
+			{Username: "ci", RoleKey: "project_member"},
+			{Username: "alice", RoleKey: "unknown"},
+			{Username: "bob.smith", RoleKey: "project_member", ValidUntil: &expired},
+		},
+	}
+
+	result, err := srv.Import(context.Background(), 1, "", archive)
+	require.NoError(t, err)
+
+	assert.Equal(t, domain.ProjectID(7), result.ProjectID)
+	assert.Len(t, result.Workflows, 1)
+	assert.Equal(t, []domain.UserID{1, 2}, memberships.created)
+	assert.Equal(t, 2, result.Memberships)
+	assert.Equal(t, []string{
+		`notification channel "ops" skipped: the archive has no webhook URL`,
+		`membership of "carol" skipped: no such user`,
+		`membership of "ci" skipped: no such user`,
+	}, result.Warnings)
+}
+
+func TestService_Import_WorkflowsRejected(t *testing.T) {
+	srv := &Service{
+		tx:               fakeTx{},
+		projectsUseCase:  fakeProjects{},
+		workflowsUseCase: fakeWorkflows{reject: true},
+	}
+
+	archive := &domain.ProjectArchive{
+		Project:   domain.ProjectArchiveProject{Name: "payments"},
+		Workflows: []domain.ProjectArchiveWorkflow{{Name: "charge", Version: 1}},
+	}
+
+	result, err := srv.Import(context.Background(), 1, "payments-copy", archive)
+	require.ErrorIs(t, err, ErrImportRejected)
+
+	assert.Zero(t, result.ProjectID)
+	assert.Len(t, result.Workflows, 1)
+}
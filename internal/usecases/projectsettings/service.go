@@ -0,0 +1,130 @@
+package projectsettings
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.ProjectSettingsUseCase = (*Service)(nil)
+
+var ErrInvalidSettings = errors.New("invalid project settings")
+
+// notificationEvents are the events notification channels can subscribe to.
+var notificationEvents = []domain.LifecycleEventType{
+	domain.EventInstanceFailed,
+	domain.EventDLQItemCreated,
+	domain.EventLongRunningInstance,
+}
+
+type Service struct {
+	tx           db.TxManager
+	settingsRepo contract.ProjectSettingsRepository
+}
+
+func New(
+	tx db.TxManager,
+	settingsRepo contract.ProjectSettingsRepository,
+) *Service {
+	return &Service{
+		tx:           tx,
+		settingsRepo: settingsRepo,
+	}
+}
+
+// GetSettings returns the project settings, the defaults if they were never saved.
+func (s *Service) GetSettings(ctx context.Context, projectID domain.ProjectID) (domain.ProjectSettings, error) {
+	settings, err := s.settingsRepo.Get(ctx, projectID)
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			settings = domain.ProjectSettings{ProjectID: projectID}
+		} else {
+			return domain.ProjectSettings{}, fmt.Errorf("get project settings: %w", err)
+		}
+	}
+
+	settings.ProjectSettingsValues = settings.WithDefaults()
+
+	return settings, nil
+}
+
+func (s *Service) ListSettings(ctx context.Context) ([]domain.ProjectSettings, error) {
+	settings, err := s.settingsRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list project settings: %w", err)
+	}
+
+	for i := range settings {
+		settings[i].ProjectSettingsValues = settings[i].WithDefaults()
+	}
+
+	return settings, nil
+}
+
+func (s *Service) UpdateSettings(
+	ctx context.Context,
+	settings domain.ProjectSettings,
+) (domain.ProjectSettings, error) {
+	values := settings.WithDefaults()
+	if err := validate(values); err != nil {
+		return domain.ProjectSettings{}, err
+	}
+
+	var saved domain.ProjectSettings
+	err := s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		if err := s.settingsRepo.Save(ctx, settings.ProjectID, values); err != nil {
+			return err
+		}
+
+		var err error
+		saved, err = s.GetSettings(ctx, settings.ProjectID)
+
+		return err
+	})
+	if err != nil {
+		return domain.ProjectSettings{}, fmt.Errorf("update project settings: %w", err)
+	}
+
+	return saved, nil
+}
+
+func validate(values domain.ProjectSettingsValues) error {
+	// Local is the zone of the server, not a zone users can rely on
+	if _, err := time.LoadLocation(values.Timezone); err != nil || values.Timezone == "Local" {
+		return fmt.Errorf("%w: unknown timezone %q", ErrInvalidSettings, values.Timezone)
+	}
+
+	if values.DefaultPageSize < 1 || values.DefaultPageSize > domain.MaxProjectPageSize {
+		return fmt.Errorf("%w: default_page_size must be between 1 and %d",
+			ErrInvalidSettings, domain.MaxProjectPageSize)
+	}
+
+	for _, event := range values.Notifications.Events {
+		if !slices.Contains(notificationEvents, event) {
+			return fmt.Errorf("%w: unsupported notification event %q", ErrInvalidSettings, event)
+		}
+	}
+
+	if values.Notifications.LongRunningThresholdSeconds < 0 {
+		return fmt.Errorf("%w: notifications.long_running_threshold_seconds must be positive", ErrInvalidSettings)
+	}
+
+	for field, days := range map[string]*int{
+		"completed_days": values.Retention.CompletedDays,
+		"failed_days":    values.Retention.FailedDays,
+		"events_days":    values.Retention.EventsDays,
+	} {
+		if days != nil && (*days <= 0 || *days > domain.MaxRetentionDays) {
+			return fmt.Errorf("%w: retention.%s must be between 1 and %d",
+				ErrInvalidSettings, field, domain.MaxRetentionDays)
+		}
+	}
+
+	return nil
+}
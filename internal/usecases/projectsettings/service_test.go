@@ -0,0 +1,82 @@
+package projectsettings
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type fakeTx struct{}
+
+func (fakeTx) ReadCommitted(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (fakeTx) RepeatableRead(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type fakeSettingsRepo struct {
+	contract.ProjectSettingsRepository
+	saved map[domain.ProjectID]domain.ProjectSettingsValues
+}
+
+func (r *fakeSettingsRepo) Get(_ context.Context, projectID domain.ProjectID) (domain.ProjectSettings, error) {
+	values, ok := r.saved[projectID]
+	if !ok {
+		return domain.ProjectSettings{}, domain.ErrEntityNotFound
+	}
+
+	return domain.ProjectSettings{ProjectID: projectID, ProjectSettingsValues: values}, nil
+}
+
+func (r *fakeSettingsRepo) Save(_ context.Context, projectID domain.ProjectID, values domain.ProjectSettingsValues) error {
+	r.saved[projectID] = values
+
+	return nil
+}
+
+func TestService_GetSettings_Defaults(t *testing.T) {
+	srv := New(fakeTx{}, &fakeSettingsRepo{saved: map[domain.ProjectID]domain.ProjectSettingsValues{}})
+
+	settings, err := srv.GetSettings(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, domain.DefaultProjectTimezone, settings.Timezone)
+	assert.Equal(t, domain.DefaultProjectPageSize, settings.DefaultPageSize)
+	assert.Empty(t, settings.Notifications.Events)
+}
+
+func TestService_UpdateSettings(t *testing.T) {
+	ctx := context.Background()
+	srv := New(fakeTx{}, &fakeSettingsRepo{saved: map[domain.ProjectID]domain.ProjectSettingsValues{}})
+
+	days := 30
+	settings, err := srv.UpdateSettings(ctx, domain.ProjectSettings{
+		ProjectID: 1,
+		ProjectSettingsValues: domain.ProjectSettingsValues{
+			Timezone:  "Europe/Berlin",
+			Retention: domain.RetentionDefaults{CompletedDays: &days},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Berlin", settings.Timezone)
+	assert.Equal(t, domain.DefaultProjectPageSize, settings.DefaultPageSize)
+	assert.Equal(t, &days, settings.Retention.CompletedDays)
+
+	invalid := []domain.ProjectSettingsValues{
+		{Timezone: "Mars/Olympus"},
+		{Timezone: "Local"},
+		{DefaultPageSize: domain.MaxProjectPageSize + 1},
+		{Notifications: domain.NotificationDefaults{Events: []domain.LifecycleEventType{"instance_started"}}},
+		{Retention: domain.RetentionDefaults{FailedDays: new(int)}},
+	}
+	for _, values := range invalid {
+		_, err := srv.UpdateSettings(ctx, domain.ProjectSettings{ProjectID: 1, ProjectSettingsValues: values})
+		require.ErrorIs(t, err, ErrInvalidSettings)
+	}
+}
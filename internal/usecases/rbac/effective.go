@@ -0,0 +1,165 @@
+package rbac
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+// GetEffectivePermissions computes the permissions of the user in the project and what grants each of them.
+// A project membership takes precedence over a tenant membership, global roles add to either.
+func (s *Service) GetEffectivePermissions(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	userID domain.UserID,
+) (domain.EffectivePermissions, error) {
+	if _, err := s.projectsRepo.GetByID(ctx, projectID); err != nil {
+		return domain.EffectivePermissions{}, fmt.Errorf("get project: %w", err)
+	}
+
+	user, err := s.usersRepo.GetByID(ctx, userID)
+	if err != nil {
+		return domain.EffectivePermissions{}, fmt.Errorf("get user: %w", err)
+	}
+
+	var grants []domain.PermissionGrant
+	if user.IsSuperuser {
+		grants, err = s.superuserGrants(ctx)
+	} else {
+		grants, err = s.membershipGrants(ctx, projectID, userID)
+		if err == nil {
+			var global []domain.PermissionGrant
+			global, err = s.globalRoleGrants(ctx, userID)
+			grants = append(grants, global...)
+		}
+	}
+	if err != nil {
+		return domain.EffectivePermissions{}, err
+	}
+
+	return domain.EffectivePermissions{
+		UserID:      userID,
+		ProjectID:   projectID,
+		IsSuperuser: user.IsSuperuser,
+		Permissions: grantedPermissions(grants),
+		Grants:      grants,
+	}, nil
+}
+
+func (s *Service) superuserGrants(ctx context.Context) ([]domain.PermissionGrant, error) {
+	perms, err := s.permsRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list permissions: %w", err)
+	}
+
+	grants := make([]domain.PermissionGrant, 0, len(perms))
+	for i := range perms {
+		grants = append(grants, domain.PermissionGrant{
+			Permission: perms[i].Key,
+			Source:     domain.PermissionGrantSuperuser,
+		})
+	}
+
+	return grants, nil
+}
+
+// membershipGrants returns the grants of the project membership of the user or,
+// without one, of the tenant membership in the tenant of the project.
+func (s *Service) membershipGrants(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	userID domain.UserID,
+) ([]domain.PermissionGrant, error) {
+	memberships, err := s.membershipsRepo.ListForUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list user memberships: %w", err)
+	}
+
+	now := time.Now()
+	for i := range memberships {
+		membership := memberships[i]
+		if membership.ProjectID != projectID || membership.Expired(now) {
+			continue
+		}
+
+		return s.roleGrants(ctx, domain.PermissionGrantProjectMembership,
+			membership.RoleID, membership.RoleKey, membership.RoleName, int(membership.ID))
+	}
+
+	tenantMembership, err := s.tenantMembershipsRepo.GetMembershipForUserProject(ctx, userID, projectID)
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("get tenant membership: %w", err)
+	}
+
+	return s.roleGrants(ctx, domain.PermissionGrantTenantMembership,
+		tenantMembership.RoleID, tenantMembership.RoleKey, tenantMembership.RoleName, int(tenantMembership.ID))
+}
+
+func (s *Service) globalRoleGrants(ctx context.Context, userID domain.UserID) ([]domain.PermissionGrant, error) {
+	assignments, err := s.globalRolesRepo.ListForUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list global role assignments: %w", err)
+	}
+
+	var grants []domain.PermissionGrant
+	for i := range assignments {
+		assignment := assignments[i]
+
+		roleGrants, err := s.roleGrants(ctx, domain.PermissionGrantGlobalRole,
+			assignment.RoleID, assignment.RoleKey, assignment.RoleName, int(assignment.ID))
+		if err != nil {
+			return nil, err
+		}
+
+		grants = append(grants, roleGrants...)
+	}
+
+	return grants, nil
+}
+
+func (s *Service) roleGrants(
+	ctx context.Context,
+	source domain.PermissionGrantSource,
+	roleID domain.RoleID,
+	roleKey, roleName string,
+	grantID int,
+) ([]domain.PermissionGrant, error) {
+	perms, err := s.permsRepo.ListForRole(ctx, roleID)
+	if err != nil {
+		return nil, fmt.Errorf("list role permissions: %w", err)
+	}
+
+	grants := make([]domain.PermissionGrant, 0, len(perms))
+	for i := range perms {
+		grants = append(grants, domain.PermissionGrant{
+			Permission: perms[i].Key,
+			Source:     source,
+			RoleID:     roleID,
+			RoleKey:    roleKey,
+			RoleName:   roleName,
+			GrantID:    grantID,
+		})
+	}
+
+	return grants, nil
+}
+
+// grantedPermissions returns the sorted set of permissions granted by any of the grants.
+func grantedPermissions(grants []domain.PermissionGrant) []domain.PermKey {
+	perms := make([]domain.PermKey, 0, len(grants))
+	for i := range grants {
+		perms = append(perms, grants[i].Permission)
+	}
+
+	slices.Sort(perms)
+
+	return slices.Compact(perms)
+}
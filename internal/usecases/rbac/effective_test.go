@@ -0,0 +1,21 @@
+package rbac
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+func TestGrantedPermissions(t *testing.T) {
+	grants := []domain.PermissionGrant{
+		{Permission: domain.PermProjectView, Source: domain.PermissionGrantProjectMembership},
+		{Permission: domain.PermAuditView, Source: domain.PermissionGrantProjectMembership},
+		{Permission: domain.PermProjectView, Source: domain.PermissionGrantGlobalRole},
+	}
+
+	assert.Equal(t, []domain.PermKey{domain.PermAuditView, domain.PermProjectView}, grantedPermissions(grants))
+	assert.Empty(t, grantedPermissions(nil))
+	assert.NotNil(t, grantedPermissions(nil))
+}
@@ -0,0 +1,184 @@
+package rbac
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/repository/membershipaudit"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+// ExpireProjectMemberships removes memberships whose valid_until has passed and notifies
+// the former members and the project managers. It returns the number of removed memberships.
+func (s *Service) ExpireProjectMemberships(ctx context.Context, now time.Time) (int, error) {
+	expired, err := s.membershipsRepo.ListExpired(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	defer func() {
+		if removed > 0 {
+			s.permissions.InvalidateCache()
+		}
+	}()
+
+	for i := range expired {
+		ok, err := s.expireMembership(ctx, expired[i], now)
+		if err != nil {
+			return removed, fmt.Errorf("expire membership %d: %w", expired[i].ID, err)
+		}
+
+		if !ok {
+			continue
+		}
+
+		removed++
+		s.notifyMembershipExpired(ctx, expired[i], now)
+	}
+
+	return removed, nil
+}
+
+// expireMembership deletes the membership if it is still expired and reports whether it was deleted.
+func (s *Service) expireMembership(ctx context.Context, membership domain.ProjectMembership, now time.Time) (bool, error) {
+	var removed bool
+	err := s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		current, err := s.membershipsRepo.Get(ctx, membership.ProjectID, membership.ID)
+		if err != nil {
+			if errors.Is(err, domain.ErrEntityNotFound) {
+				return nil
+			}
+
+			return err
+		}
+
+		// The membership may have been extended or changed since it was listed
+		if !current.Expired(now) {
+			return nil
+		}
+
+		if err := s.membershipsRepo.Delete(ctx, current.ProjectID, current.ID); err != nil {
+			if errors.Is(err, domain.ErrEntityNotFound) {
+				return nil
+			}
+
+			return err
+		}
+
+		exec := db.TxFromContext(ctx)
+		if err := membershipaudit.Write(ctx, exec, current.ID, 0, "expire", current, nil); err != nil {
+			return fmt.Errorf("write membership audit: %w", err)
+		}
+
+		removed = true
+
+		return nil
+	})
+
+	return removed, err
+}
+
+// notifyMembershipExpired emails the former member and the managers of the project.
+// Notification failures are logged and do not restore the membership.
+func (s *Service) notifyMembershipExpired(ctx context.Context, membership domain.ProjectMembership, now time.Time) {
+	project, err := s.projectsRepo.GetByID(ctx, membership.ProjectID)
+	if err != nil {
+		slog.Error("Failed to get project for expired membership",
+			"error", err,
+			"project_id", membership.ProjectID,
+			"membership_id", membership.ID,
+		)
+
+		return
+	}
+
+	member, err := s.usersRepo.GetByID(ctx, membership.UserID)
+	if err != nil {
+		slog.Error("Failed to get user for expired membership",
+			"error", err,
+			"user_id", membership.UserID,
+			"membership_id", membership.ID,
+		)
+
+		return
+	}
+
+	if member.IsActive && member.Email != "" && !member.IsServiceAccount {
+		if err := s.emailer.SendMembershipExpiredEmail(ctx, member.Email, project); err != nil {
+			slog.Error("Failed to send membership expired email", "error", err, "user_id", member.ID)
+		}
+	}
+
+	managers, err := s.projectManagers(ctx, membership.ProjectID, now)
+	if err != nil {
+		slog.Error("Failed to get project managers for expired membership",
+			"error", err,
+			"project_id", membership.ProjectID,
+		)
+
+		return
+	}
+
+	for i := range managers {
+		if managers[i].ID == member.ID {
+			continue
+		}
+
+		if err := s.emailer.SendMemberAccessExpiredEmail(ctx, managers[i].Email, project, member); err != nil {
+			slog.Error("Failed to send member access expired email", "error", err, "user_id", managers[i].ID)
+		}
+	}
+}
+
+// projectManagers returns active members of the project whose role can manage memberships.
+func (s *Service) projectManagers(ctx context.Context, projectID domain.ProjectID, now time.Time) ([]domain.User, error) {
+	memberships, err := s.membershipsRepo.ListForProject(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("list project memberships: %w", err)
+	}
+
+	canManage := make(map[domain.RoleID]bool)
+	userIDs := make([]domain.UserID, 0, len(memberships))
+	for i := range memberships {
+		if memberships[i].Expired(now) {
+			continue
+		}
+
+		roleID := memberships[i].RoleID
+		allowed, ok := canManage[roleID]
+		if !ok {
+			allowed, err = s.permsRepo.RoleHasPermission(ctx, string(roleID), domain.PermMembershipManage)
+			if err != nil {
+				return nil, fmt.Errorf("check role permission: %w", err)
+			}
+			canManage[roleID] = allowed
+		}
+
+		if allowed {
+			userIDs = append(userIDs, memberships[i].UserID)
+		}
+	}
+
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	users, err := s.usersRepo.FetchByIDs(ctx, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("fetch users: %w", err)
+	}
+
+	managers := make([]domain.User, 0, len(users))
+	for i := range users {
+		if users[i].IsActive && users[i].Email != "" {
+			managers = append(managers, users[i])
+		}
+	}
+
+	return managers, nil
+}
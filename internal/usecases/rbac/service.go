@@ -3,10 +3,13 @@ package rbac
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"time"
 
 	appctx "github.com/rom8726/floxy-manager/internal/context"
 	"github.com/rom8726/floxy-manager/internal/contract"
 	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/repository/auditlog"
 	"github.com/rom8726/floxy-manager/internal/repository/membershipaudit"
 	"github.com/rom8726/floxy-manager/pkg/db"
 )
@@ -14,11 +17,16 @@ import (
 var _ contract.MembershipsUseCase = (*Service)(nil)
 
 type Service struct {
-	projectsRepo    contract.ProjectsRepository
-	rolesRepo       contract.RolesRepository
-	permsRepo       contract.PermissionsRepository
-	membershipsRepo contract.MembershipsRepository
-	tx              db.TxManager
+	projectsRepo          contract.ProjectsRepository
+	rolesRepo             contract.RolesRepository
+	permsRepo             contract.PermissionsRepository
+	membershipsRepo       contract.MembershipsRepository
+	tenantMembershipsRepo contract.TenantMembershipsRepository
+	globalRolesRepo       contract.GlobalRolesRepository
+	usersRepo             contract.UsersRepository
+	emailer               contract.Emailer
+	permissions           contract.PermissionsService
+	tx                    db.TxManager
 }
 
 func New(
@@ -26,14 +34,24 @@ func New(
 	rolesRepo contract.RolesRepository,
 	permsRepo contract.PermissionsRepository,
 	membershipsRepo contract.MembershipsRepository,
+	tenantMembershipsRepo contract.TenantMembershipsRepository,
+	globalRolesRepo contract.GlobalRolesRepository,
+	usersRepo contract.UsersRepository,
+	emailer contract.Emailer,
+	permissions contract.PermissionsService,
 	tx db.TxManager,
 ) *Service {
 	return &Service{
-		projectsRepo:    projectsRepo,
-		rolesRepo:       rolesRepo,
-		permsRepo:       permsRepo,
-		membershipsRepo: membershipsRepo,
-		tx:              tx,
+		projectsRepo:          projectsRepo,
+		rolesRepo:             rolesRepo,
+		permsRepo:             permsRepo,
+		membershipsRepo:       membershipsRepo,
+		tenantMembershipsRepo: tenantMembershipsRepo,
+		globalRolesRepo:       globalRolesRepo,
+		usersRepo:             usersRepo,
+		emailer:               emailer,
+		permissions:           permissions,
+		tx:                    tx,
 	}
 }
 
@@ -48,6 +66,10 @@ func (s *Service) ListPermissions(ctx context.Context) ([]domain.Permission, err
 }
 
 func (s *Service) GetRolePermissions(ctx context.Context, roleID domain.RoleID) ([]domain.Permission, error) {
+	if _, err := s.rolesRepo.GetByID(ctx, roleID); err != nil {
+		return nil, fmt.Errorf("get role: %w", err)
+	}
+
 	return s.permsRepo.ListForRole(ctx, roleID)
 }
 
@@ -55,6 +77,20 @@ func (s *Service) ListRolePermissions(ctx context.Context) (map[domain.Role][]do
 	return s.permsRepo.ListForAllRoles(ctx)
 }
 
+// checkProjectRole rejects roles that are meant for tenant memberships or global assignments.
+func (s *Service) checkProjectRole(ctx context.Context, roleID domain.RoleID) error {
+	role, err := s.rolesRepo.GetByID(ctx, roleID)
+	if err != nil {
+		return fmt.Errorf("get role: %w", err)
+	}
+
+	if role.IsTenantRole() || role.IsGlobalRole() {
+		return domain.ErrRoleScopeMismatch
+	}
+
+	return nil
+}
+
 // Memberships
 
 func (s *Service) ListProjectMemberships(
@@ -77,6 +113,7 @@ func (s *Service) CreateProjectMembership(
 	projectID domain.ProjectID,
 	userID domain.UserID,
 	roleID domain.RoleID,
+	validUntil *time.Time,
 ) (domain.ProjectMembership, error) {
 	//project, err := s.projectsRepo.GetByID(ctx, projectID)
 	//if err != nil {
@@ -88,12 +125,34 @@ func (s *Service) CreateProjectMembership(
 	//	return domain.ProjectMembership{}, fmt.Errorf("get role: %w", err)
 	//}
 
+	user, err := s.usersRepo.GetByID(ctx, userID)
+	if err != nil {
+		return domain.ProjectMembership{}, fmt.Errorf("get user: %w", err)
+	}
+
+	if err := s.checkProjectRole(ctx, roleID); err != nil {
+		return domain.ProjectMembership{}, err
+	}
+
+	// Service accounts are bound to a single project
+	if user.IsServiceAccount && (user.ServiceProjectID == nil || *user.ServiceProjectID != projectID) {
+		return domain.ProjectMembership{}, fmt.Errorf("%w: service account belongs to another project",
+			domain.ErrPermissionDenied)
+	}
+
 	var created domain.ProjectMembership
 	if err := s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
 		membership, err := s.membershipsRepo.Create(ctx, projectID, userID, roleID)
 		if err != nil {
 			return err
 		}
+
+		if validUntil != nil {
+			if err := s.membershipsRepo.SetValidUntil(ctx, projectID, membership.ID, validUntil); err != nil {
+				return fmt.Errorf("set membership valid until: %w", err)
+			}
+			membership.ValidUntil = validUntil
+		}
 		created = membership
 
 		actorID := int(appctx.UserID(ctx))
@@ -131,6 +190,8 @@ func (s *Service) CreateProjectMembership(
 		return domain.ProjectMembership{}, err
 	}
 
+	s.permissions.InvalidateCache()
+
 	return created, nil
 }
 
@@ -161,6 +222,10 @@ func (s *Service) UpdateProjectMembership(
 	//	return domain.ProjectMembership{}, fmt.Errorf("get old role: %w", err)
 	//}
 
+	if err := s.checkProjectRole(ctx, roleID); err != nil {
+		return domain.ProjectMembership{}, err
+	}
+
 	var updated domain.ProjectMembership
 	if err := s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
 		old, err := s.membershipsRepo.Get(ctx, projectID, membershipID)
@@ -179,6 +244,17 @@ func (s *Service) UpdateProjectMembership(
 		if err := membershipaudit.Write(ctx, exec, m.ID, actorID, "update", old, m); err != nil {
 			return fmt.Errorf("write membership audit: %w", err)
 		}
+
+		changes, err := auditlog.Diff(membershipAuditFields(&old), membershipAuditFields(&m))
+		if err != nil {
+			return fmt.Errorf("diff membership: %w", err)
+		}
+
+		err = auditlog.WriteChangeLog(ctx, exec, domain.EntityMembership, strconv.Itoa(int(m.ID)),
+			domain.ActionUpdate, projectID, changes)
+		if err != nil {
+			return fmt.Errorf("write audit log: %w", err)
+		}
 		//
 		//content := domain.UserNotificationContent{
 		//	UserRoleChanged: &domain.UserRoleChangedContent{
@@ -203,6 +279,8 @@ func (s *Service) UpdateProjectMembership(
 		return domain.ProjectMembership{}, err
 	}
 
+	s.permissions.InvalidateCache()
+
 	return updated, nil
 }
 
@@ -221,7 +299,7 @@ func (s *Service) DeleteProjectMembership(
 	//	return fmt.Errorf("get membership: %w", err)
 	//}
 
-	return s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+	err := s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
 		old, err := s.membershipsRepo.Get(ctx, projectID, membershipID)
 		if err != nil {
 			return err
@@ -256,4 +334,21 @@ func (s *Service) DeleteProjectMembership(
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	s.permissions.InvalidateCache()
+
+	return nil
+}
+
+// membershipAuditFields lists the membership attributes recorded in audit log changes.
+func membershipAuditFields(m *domain.ProjectMembership) map[string]any {
+	return map[string]any{
+		"user_id":     m.UserID,
+		"role_id":     m.RoleID,
+		"role_key":    m.RoleKey,
+		"valid_until": m.ValidUntil,
+	}
 }
@@ -0,0 +1,86 @@
+package redaction
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/jsonpath"
+)
+
+// minMaskedLength bounds the masked values searched in error texts, shorter ones would mask common words.
+const minMaskedLength = 4
+
+type redactor struct {
+	paths []*jsonpath.Path
+}
+
+// run redacts the documents of a single item, collecting the masked values for its error text.
+func (r *redactor) run() *redaction {
+	return &redaction{paths: r.paths}
+}
+
+type redaction struct {
+	paths  []*jsonpath.Path
+	masked []string
+}
+
+func (r *redaction) document(raw json.RawMessage) json.RawMessage {
+	if len(raw) == 0 {
+		return raw
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return raw
+	}
+
+	for _, path := range r.paths {
+		doc = path.Replace(doc, func(value any) any {
+			r.collect(value)
+
+			return domain.RedactedValue
+		})
+	}
+
+	redacted, err := json.Marshal(doc)
+	if err != nil {
+		// Nothing leaks when the document cannot be encoded again
+		return json.RawMessage(`"` + domain.RedactedValue + `"`)
+	}
+
+	return redacted
+}
+
+func (r *redaction) text(text string) string {
+	for _, value := range r.masked {
+		text = strings.ReplaceAll(text, value, domain.RedactedValue)
+	}
+
+	return text
+}
+
+func (r *redaction) collect(value any) {
+	switch v := value.(type) {
+	case string:
+		if len(v) >= minMaskedLength && v != domain.RedactedValue {
+			r.masked = append(r.masked, v)
+		}
+	case json.Number:
+		if len(v) >= minMaskedLength {
+			r.masked = append(r.masked, v.String())
+		}
+	case map[string]any:
+		for _, item := range v {
+			r.collect(item)
+		}
+	case []any:
+		for _, item := range v {
+			r.collect(item)
+		}
+	}
+}
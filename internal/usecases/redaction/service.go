@@ -0,0 +1,287 @@
+package redaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/usecases/variables"
+	"github.com/rom8726/floxy-manager/pkg/db"
+	"github.com/rom8726/floxy-manager/pkg/jsonpath"
+)
+
+var _ contract.RedactionUseCase = (*Service)(nil)
+
+var ErrInvalidRules = errors.New("invalid redaction rules")
+
+const (
+	maxRules      = 100
+	maxRuleLength = 512
+)
+
+type Service struct {
+	tx            db.TxManager
+	redactionRepo contract.RedactionRepository
+	variablesRepo contract.VariablesRepository
+}
+
+func New(
+	tx db.TxManager,
+	redactionRepo contract.RedactionRepository,
+	variablesRepo contract.VariablesRepository,
+) *Service {
+	return &Service{
+		tx:            tx,
+		redactionRepo: redactionRepo,
+		variablesRepo: variablesRepo,
+	}
+}
+
+// GetRules returns the project redaction rules, none if they were never saved.
+func (s *Service) GetRules(ctx context.Context, projectID domain.ProjectID) (domain.RedactionRules, error) {
+	rules, err := s.redactionRepo.GetRules(ctx, projectID)
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			return domain.RedactionRules{ProjectID: projectID, Paths: []string{}}, nil
+		}
+
+		return domain.RedactionRules{}, fmt.Errorf("get redaction rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+func (s *Service) UpdateRules(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	paths []string,
+) (domain.RedactionRules, error) {
+	if len(paths) > maxRules {
+		return domain.RedactionRules{}, fmt.Errorf("%w: at most %d paths", ErrInvalidRules, maxRules)
+	}
+
+	seen := make(map[string]struct{}, len(paths))
+	unique := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if len(path) > maxRuleLength {
+			return domain.RedactionRules{}, fmt.Errorf("%w: path must be at most %d characters",
+				ErrInvalidRules, maxRuleLength)
+		}
+
+		if _, err := jsonpath.Compile(path); err != nil {
+			return domain.RedactionRules{}, fmt.Errorf("%w: %v", ErrInvalidRules, err)
+		}
+
+		if _, ok := seen[path]; !ok {
+			seen[path] = struct{}{}
+			unique = append(unique, path)
+		}
+	}
+
+	var saved domain.RedactionRules
+	err := s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		if err := s.redactionRepo.SaveRules(ctx, projectID, unique); err != nil {
+			return err
+		}
+
+		var err error
+		saved, err = s.redactionRepo.GetRules(ctx, projectID)
+
+		return err
+	})
+	if err != nil {
+		return domain.RedactionRules{}, fmt.Errorf("update redaction rules: %w", err)
+	}
+
+	return saved, nil
+}
+
+func (s *Service) RedactInstances(ctx context.Context, instances []domain.WorkflowInstance) error {
+	projectIDs := make([]domain.ProjectID, 0, len(instances))
+	for i := range instances {
+		projectIDs = append(projectIDs, instances[i].ProjectID)
+	}
+
+	redactors, err := s.redactors(ctx, projectIDs)
+	if err != nil {
+		return err
+	}
+
+	for i := range instances {
+		r, ok := redactors[instances[i].ProjectID]
+		if !ok {
+			continue
+		}
+
+		run := r.run()
+		instances[i].Input = run.document(instances[i].Input)
+		instances[i].Output = run.document(instances[i].Output)
+		instances[i].Error.String = run.text(instances[i].Error.String)
+	}
+
+	return nil
+}
+
+func (s *Service) RedactSteps(ctx context.Context, steps []domain.WorkflowStep) error {
+	projectIDs := make([]domain.ProjectID, 0, len(steps))
+	for i := range steps {
+		projectIDs = append(projectIDs, steps[i].ProjectID)
+	}
+
+	redactors, err := s.redactors(ctx, projectIDs)
+	if err != nil {
+		return err
+	}
+
+	for i := range steps {
+		r, ok := redactors[steps[i].ProjectID]
+		if !ok {
+			continue
+		}
+
+		run := r.run()
+		steps[i].Input = run.document(steps[i].Input)
+		steps[i].Output = run.document(steps[i].Output)
+		steps[i].Error.String = run.text(steps[i].Error.String)
+	}
+
+	return nil
+}
+
+func (s *Service) RedactEvents(ctx context.Context, events []domain.WorkflowEvent) error {
+	projectIDs := make([]domain.ProjectID, 0, len(events))
+	for i := range events {
+		projectIDs = append(projectIDs, events[i].ProjectID)
+	}
+
+	redactors, err := s.redactors(ctx, projectIDs)
+	if err != nil {
+		return err
+	}
+
+	for i := range events {
+		if r, ok := redactors[events[i].ProjectID]; ok {
+			events[i].Payload = r.run().document(events[i].Payload)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) RedactDLQItems(ctx context.Context, items []domain.DLQItem) error {
+	projectIDs := make([]domain.ProjectID, 0, len(items))
+	for i := range items {
+		projectIDs = append(projectIDs, items[i].ProjectID)
+	}
+
+	redactors, err := s.redactors(ctx, projectIDs)
+	if err != nil {
+		return err
+	}
+
+	for i := range items {
+		r, ok := redactors[items[i].ProjectID]
+		if !ok {
+			continue
+		}
+
+		run := r.run()
+		items[i].Input = run.document(items[i].Input)
+		items[i].Error.String = run.text(items[i].Error.String)
+	}
+
+	return nil
+}
+
+func (s *Service) SearchExclusions(ctx context.Context, projectID domain.ProjectID) ([][]string, error) {
+	redactors, err := s.redactors(ctx, []domain.ProjectID{projectID})
+	if err != nil {
+		return nil, err
+	}
+
+	r, ok := redactors[projectID]
+	if !ok {
+		return nil, nil
+	}
+
+	locations := make([][]string, 0, len(r.paths))
+	for _, path := range r.paths {
+		location, ok := path.Location()
+		if !ok {
+			return nil, fmt.Errorf("%w: %s selects several locations", domain.ErrPayloadNotSearchable, path)
+		}
+		locations = append(locations, location)
+	}
+
+	return locations, nil
+}
+
+// redactors returns the redactors of the projects having rules or secret variables. The secret variables
+// injected in the inputs are always redacted, whatever the rules and the permissions of the caller.
+func (s *Service) redactors(
+	ctx context.Context,
+	projectIDs []domain.ProjectID,
+) (map[domain.ProjectID]*redactor, error) {
+	slices.Sort(projectIDs)
+	projectIDs = slices.Compact(projectIDs)
+
+	if len(projectIDs) == 0 {
+		return nil, nil
+	}
+
+	rules, err := s.redactionRepo.ListRules(ctx, projectIDs)
+	if err != nil {
+		return nil, fmt.Errorf("list redaction rules: %w", err)
+	}
+
+	secrets, err := s.variablesRepo.ListSecretNames(ctx, projectIDs)
+	if err != nil {
+		return nil, fmt.Errorf("list secret variables: %w", err)
+	}
+
+	redactors := make(map[domain.ProjectID]*redactor, len(rules)+len(secrets))
+	redactorOf := func(projectID domain.ProjectID) *redactor {
+		r, ok := redactors[projectID]
+		if !ok {
+			r = &redactor{}
+			redactors[projectID] = r
+		}
+
+		return r
+	}
+
+	for i := range rules {
+		r := redactorOf(rules[i].ProjectID)
+		for _, expr := range rules[i].Paths {
+			path, err := jsonpath.Compile(expr)
+			if err != nil {
+				slog.Warn("Skipping invalid redaction rule", "error", err, "project_id", rules[i].ProjectID)
+
+				continue
+			}
+			r.paths = append(r.paths, path)
+		}
+	}
+
+	for projectID, names := range secrets {
+		r := redactorOf(projectID)
+		for _, name := range names {
+			path, err := jsonpath.Compile(secretPath(name))
+			if err != nil {
+				return nil, fmt.Errorf("compile path of secret variable %s: %w", name, err)
+			}
+			r.paths = append(r.paths, path)
+		}
+	}
+
+	return redactors, nil
+}
+
+// secretPath is the path of a secret variable in the inputs it is injected in.
+func secretPath(name string) string {
+	return "$." + variables.InputKey + "." + name
+}
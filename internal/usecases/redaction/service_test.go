@@ -0,0 +1,120 @@
+package redaction
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type fakeRedactionRepo struct {
+	contract.RedactionRepository
+	rules map[domain.ProjectID][]string
+}
+
+func (r *fakeRedactionRepo) ListRules(_ context.Context, projectIDs []domain.ProjectID) ([]domain.RedactionRules, error) {
+	var rules []domain.RedactionRules
+	for _, id := range projectIDs {
+		if paths, ok := r.rules[id]; ok {
+			rules = append(rules, domain.RedactionRules{ProjectID: id, Paths: paths})
+		}
+	}
+
+	return rules, nil
+}
+
+type fakeVariablesRepo struct {
+	contract.VariablesRepository
+	secrets map[domain.ProjectID][]string
+}
+
+func (r *fakeVariablesRepo) ListSecretNames(
+	_ context.Context,
+	projectIDs []domain.ProjectID,
+) (map[domain.ProjectID][]string, error) {
+	names := map[domain.ProjectID][]string{}
+	for _, id := range projectIDs {
+		if secrets, ok := r.secrets[id]; ok {
+			names[id] = secrets
+		}
+	}
+
+	return names, nil
+}
+
+func TestService_RedactInstances(t *testing.T) {
+	srv := New(nil, &fakeRedactionRepo{rules: map[domain.ProjectID][]string{
+		1: {"$.card.number", "$..token"},
+	}}, &fakeVariablesRepo{})
+
+	instances := []domain.WorkflowInstance{
+		{
+			ProjectID: 1,
+			Input:     json.RawMessage(`{"card":{"number":"4111111111111111"},"amount":10}`),
+			Output:    json.RawMessage(`{"auth":[{"token":"abcdef"}]}`),
+			Error:     sql.NullString{String: "card 4111111111111111 declined", Valid: true},
+		},
+		{
+			ProjectID: 2,
+			Input:     json.RawMessage(`{"card":{"number":"4111111111111111"}}`),
+		},
+	}
+
+	require.NoError(t, srv.RedactInstances(context.Background(), instances))
+
+	assert.JSONEq(t, `{"card":{"number":"[REDACTED]"},"amount":10}`, string(instances[0].Input))
+	assert.JSONEq(t, `{"auth":[{"token":"[REDACTED]"}]}`, string(instances[0].Output))
+	assert.Equal(t, "card [REDACTED] declined", instances[0].Error.String)
+	// Projects without rules are returned as is
+	assert.JSONEq(t, `{"card":{"number":"4111111111111111"}}`, string(instances[1].Input))
+}
+
+func TestService_RedactInstances_SecretVariables(t *testing.T) {
+	srv := New(nil, &fakeRedactionRepo{}, &fakeVariablesRepo{secrets: map[domain.ProjectID][]string{
+		1: {"API_TOKEN"},
+	}})
+
+	instances := []domain.WorkflowInstance{{
+		ProjectID: 1,
+		Input:     json.RawMessage(`{"order":7,"variables":{"API_TOKEN":"s3cr3t-token","REGION":"eu"}}`),
+		Error:     sql.NullString{String: "401 for token s3cr3t-token", Valid: true},
+	}}
+
+	require.NoError(t, srv.RedactInstances(context.Background(), instances))
+
+	assert.JSONEq(t, `{"order":7,"variables":{"API_TOKEN":"[REDACTED]","REGION":"eu"}}`, string(instances[0].Input))
+	assert.Equal(t, "401 for token [REDACTED]", instances[0].Error.String)
+}
+
+func TestService_SearchExclusions(t *testing.T) {
+	srv := New(nil, &fakeRedactionRepo{rules: map[domain.ProjectID][]string{
+		1: {"$.card.number"},
+		2: {"$..token"},
+	}}, &fakeVariablesRepo{secrets: map[domain.ProjectID][]string{
+		1: {"API_TOKEN"},
+	}})
+
+	locations, err := srv.SearchExclusions(context.Background(), 1)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, [][]string{{"card", "number"}, {"variables", "API_TOKEN"}}, locations)
+
+	_, err = srv.SearchExclusions(context.Background(), 2)
+	assert.ErrorIs(t, err, domain.ErrPayloadNotSearchable)
+
+	locations, err = srv.SearchExclusions(context.Background(), 3)
+	require.NoError(t, err)
+	assert.Empty(t, locations)
+}
+
+func TestService_UpdateRules_Invalid(t *testing.T) {
+	srv := New(nil, &fakeRedactionRepo{}, &fakeVariablesRepo{})
+
+	_, err := srv.UpdateRules(context.Background(), 1, []string{"card.number"})
+	assert.ErrorIs(t, err, ErrInvalidRules)
+}
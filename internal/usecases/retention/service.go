@@ -0,0 +1,272 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	floxy "github.com/rom8726/floxy-pro"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.RetentionUseCase = (*Service)(nil)
+
+var ErrInvalidRetentionSettings = errors.New("invalid retention settings")
+
+const (
+	// deleteBatchSize bounds the rows deleted by one statement, to keep locks short.
+	deleteBatchSize = 1000
+	day             = 24 * time.Hour
+)
+
+var (
+	completedStatuses = []string{string(floxy.StatusCompleted)}
+	failedStatuses    = []string{
+		string(floxy.StatusFailed),
+		string(floxy.StatusCancelled),
+		string(floxy.StatusAborted),
+	}
+)
+
+type Service struct {
+	tx              db.TxManager
+	retentionRepo   contract.RetentionRepository
+	storeCleaner    contract.WorkflowStoreCleaner
+	projectSettings contract.ProjectSettingsReader
+}
+
+func New(
+	tx db.TxManager,
+	retentionRepo contract.RetentionRepository,
+	storeCleaner contract.WorkflowStoreCleaner,
+	projectSettings contract.ProjectSettingsReader,
+) *Service {
+	return &Service{
+		tx:              tx,
+		retentionRepo:   retentionRepo,
+		storeCleaner:    storeCleaner,
+		projectSettings: projectSettings,
+	}
+}
+
+// GetSettings returns the project retention settings, the retention defaults of the project settings
+// if they were never saved.
+func (s *Service) GetSettings(ctx context.Context, projectID domain.ProjectID) (domain.RetentionSettings, error) {
+	settings, err := s.retentionRepo.GetSettings(ctx, projectID)
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			projectSettings, err := s.projectSettings.GetSettings(ctx, projectID)
+			if err != nil {
+				return domain.RetentionSettings{}, err
+			}
+
+			return projectSettings.Retention.Settings(projectID), nil
+		}
+
+		return domain.RetentionSettings{}, fmt.Errorf("get retention settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+func (s *Service) UpdateSettings(
+	ctx context.Context,
+	settings domain.RetentionSettings,
+) (domain.RetentionSettings, error) {
+	for field, days := range map[string]*int{
+		"completed_days": settings.CompletedDays,
+		"failed_days":    settings.FailedDays,
+		"events_days":    settings.EventsDays,
+	} {
+		if days != nil && (*days <= 0 || *days > domain.MaxRetentionDays) {
+			return domain.RetentionSettings{}, fmt.Errorf("%w: %s must be between 1 and %d",
+				ErrInvalidRetentionSettings, field, domain.MaxRetentionDays)
+		}
+	}
+
+	var saved domain.RetentionSettings
+	err := s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		if err := s.retentionRepo.SaveSettings(ctx, settings); err != nil {
+			return err
+		}
+
+		var err error
+		saved, err = s.retentionRepo.GetSettings(ctx, settings.ProjectID)
+
+		return err
+	})
+	if err != nil {
+		return domain.RetentionSettings{}, fmt.Errorf("update retention settings: %w", err)
+	}
+
+	return saved, nil
+}
+
+func (s *Service) RunProject(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	now time.Time,
+) (domain.RetentionRun, error) {
+	settings, err := s.GetSettings(ctx, projectID)
+	if err != nil {
+		return domain.RetentionRun{}, err
+	}
+
+	return s.apply(ctx, settings, now)
+}
+
+func (s *Service) RunAll(ctx context.Context, now time.Time) error {
+	configured, err := s.retentionRepo.ListConfiguredSettings(ctx)
+	if err != nil {
+		return fmt.Errorf("list retention settings: %w", err)
+	}
+
+	defaulted, err := s.listDefaultedSettings(ctx)
+	if err != nil {
+		return err
+	}
+	configured = append(configured, defaulted...)
+
+	for i := range configured {
+		run, err := s.apply(ctx, configured[i], now)
+		if err != nil {
+			slog.Error("Failed to apply retention settings", "error", err, "project_id", configured[i].ProjectID)
+
+			continue
+		}
+
+		if run.DeletedInstances > 0 || run.DeletedEvents > 0 {
+			slog.Info("Retention applied",
+				"project_id", configured[i].ProjectID,
+				"deleted_instances", run.DeletedInstances,
+				"deleted_events", run.DeletedEvents,
+			)
+		}
+	}
+
+	// Then the engine maintenance the cleanup plugin runs on demand
+	if err := s.storeCleaner.CleanupOldWorkflows(ctx); err != nil {
+		return fmt.Errorf("cleanup workflow store: %w", err)
+	}
+
+	return nil
+}
+
+// listDefaultedSettings returns the retention settings of the projects that never saved theirs
+// but have retention defaults in the project settings.
+func (s *Service) listDefaultedSettings(ctx context.Context) ([]domain.RetentionSettings, error) {
+	projectSettings, err := s.projectSettings.ListSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var defaulted []domain.RetentionSettings
+	for i := range projectSettings {
+		settings := projectSettings[i].Retention.Settings(projectSettings[i].ProjectID)
+		if !settings.Configured() {
+			continue
+		}
+
+		_, err := s.retentionRepo.GetSettings(ctx, settings.ProjectID)
+		switch {
+		case errors.Is(err, domain.ErrEntityNotFound):
+			defaulted = append(defaulted, settings)
+		case err != nil:
+			return nil, fmt.Errorf("get retention settings: %w", err)
+		}
+	}
+
+	return defaulted, nil
+}
+
+// apply deletes the expired history of the project and records the run.
+// A failed deletion is recorded on the settings rather than returned.
+func (s *Service) apply(
+	ctx context.Context,
+	settings domain.RetentionSettings,
+	now time.Time,
+) (domain.RetentionRun, error) {
+	run := domain.RetentionRun{RunAt: now}
+	if !settings.Configured() {
+		return run, nil
+	}
+
+	err := s.deleteExpired(ctx, settings, now, &run)
+	if err != nil {
+		errMsg := err.Error()
+		run.Error = &errMsg
+	}
+
+	if err := s.retentionRepo.SaveRun(ctx, settings.ProjectID, run); err != nil {
+		return run, fmt.Errorf("save retention run: %w", err)
+	}
+
+	return run, nil
+}
+
+func (s *Service) deleteExpired(
+	ctx context.Context,
+	settings domain.RetentionSettings,
+	now time.Time,
+	run *domain.RetentionRun,
+) error {
+	scopes := []struct {
+		days     *int
+		statuses []string
+	}{
+		{settings.CompletedDays, completedStatuses},
+		{settings.FailedDays, failedStatuses},
+	}
+
+	for _, scope := range scopes {
+		if scope.days == nil {
+			continue
+		}
+
+		before := now.Add(-time.Duration(*scope.days) * day)
+		deleted, err := deleteInBatches(ctx, func(ctx context.Context) (int64, error) {
+			return s.retentionRepo.DeleteInstances(ctx, settings.ProjectID, scope.statuses, before, deleteBatchSize)
+		})
+		run.DeletedInstances += deleted
+		if err != nil {
+			return err
+		}
+	}
+
+	if settings.EventsDays != nil {
+		before := now.Add(-time.Duration(*settings.EventsDays) * day)
+		deleted, err := deleteInBatches(ctx, func(ctx context.Context) (int64, error) {
+			return s.retentionRepo.DeleteEvents(ctx, settings.ProjectID, before, deleteBatchSize)
+		})
+		run.DeletedEvents += deleted
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func deleteInBatches(ctx context.Context, deleteBatch func(ctx context.Context) (int64, error)) (int64, error) {
+	var total int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		deleted, err := deleteBatch(ctx)
+		total += deleted
+		if err != nil {
+			return total, err
+		}
+
+		if deleted < deleteBatchSize {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package retention
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type fakeRetentionRepo struct {
+	contract.RetentionRepository
+	settings  []domain.RetentionSettings
+	instances map[string]int64
+	events    int64
+	deletes   []time.Time
+	runs      map[domain.ProjectID]domain.RetentionRun
+}
+
+func (r *fakeRetentionRepo) ListConfiguredSettings(context.Context) ([]domain.RetentionSettings, error) {
+	return r.settings, nil
+}
+
+func (r *fakeRetentionRepo) GetSettings(_ context.Context, projectID domain.ProjectID) (domain.RetentionSettings, error) {
+	for _, settings := range r.settings {
+		if settings.ProjectID == projectID {
+			return settings, nil
+		}
+	}
+
+	return domain.RetentionSettings{}, domain.ErrEntityNotFound
+}
+
+func (r *fakeRetentionRepo) DeleteInstances(
+	_ context.Context,
+	_ domain.ProjectID,
+	statuses []string,
+	before time.Time,
+	limit int,
+) (int64, error) {
+	r.deletes = append(r.deletes, before)
+	deleted := min(r.instances[statuses[0]], int64(limit))
+	r.instances[statuses[0]] -= deleted
+
+	return deleted, nil
+}
+
+func (r *fakeRetentionRepo) DeleteEvents(_ context.Context, _ domain.ProjectID, _ time.Time, limit int) (int64, error) {
+	deleted := min(r.events, int64(limit))
+	r.events -= deleted
+
+	return deleted, nil
+}
+
+func (r *fakeRetentionRepo) SaveRun(_ context.Context, projectID domain.ProjectID, run domain.RetentionRun) error {
+	r.runs[projectID] = run
+
+	return nil
+}
+
+type fakeStoreCleaner struct {
+	calls int
+}
+
+func (c *fakeStoreCleaner) CleanupOldWorkflows(context.Context) error {
+	c.calls++
+
+	return nil
+}
+
+type fakeProjectSettings struct {
+	settings []domain.ProjectSettings
+}
+
+func (p fakeProjectSettings) GetSettings(_ context.Context, projectID domain.ProjectID) (domain.ProjectSettings, error) {
+	for _, settings := range p.settings {
+		if settings.ProjectID == projectID {
+			return settings, nil
+		}
+	}
+
+	return domain.ProjectSettings{ProjectID: projectID}, nil
+}
+
+func (p fakeProjectSettings) ListSettings(context.Context) ([]domain.ProjectSettings, error) {
+	return p.settings, nil
+}
+
+func TestService_RunAll(t *testing.T) {
+	completedDays, eventsDays := 7, 30
+	repo := &fakeRetentionRepo{
+		settings: []domain.RetentionSettings{
+			{ProjectID: 1, CompletedDays: &completedDays, EventsDays: &eventsDays},
+		},
+		instances: map[string]int64{"completed": deleteBatchSize + 5, "failed": 10},
+		events:    3,
+		runs:      make(map[domain.ProjectID]domain.RetentionRun),
+	}
+	cleaner := &fakeStoreCleaner{}
+	// The retention defaults of project 1 are ignored, its retention settings are saved
+	failedDays := 1
+	projectSettings := fakeProjectSettings{settings: []domain.ProjectSettings{{
+		ProjectID:             1,
+		ProjectSettingsValues: domain.ProjectSettingsValues{Retention: domain.RetentionDefaults{FailedDays: &failedDays}},
+	}}}
+	srv := New(nil, repo, cleaner, projectSettings)
+
+	now := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, srv.RunAll(context.Background(), now))
+
+	// Failed instances are kept, their period is not set
+	assert.Equal(t, int64(10), repo.instances["failed"])
+	// Instances are deleted in batches
+	assert.Equal(t, []time.Time{now.AddDate(0, 0, -7), now.AddDate(0, 0, -7)}, repo.deletes)
+	assert.Equal(t, domain.RetentionRun{RunAt: now, DeletedInstances: deleteBatchSize + 5, DeletedEvents: 3},
+		repo.runs[1])
+	assert.Equal(t, 1, cleaner.calls)
+}
+
+func TestService_UpdateSettings_Invalid(t *testing.T) {
+	srv := New(nil, &fakeRetentionRepo{}, &fakeStoreCleaner{}, fakeProjectSettings{})
+	days := 0
+
+	_, err := srv.UpdateSettings(context.Background(), domain.RetentionSettings{ProjectID: 1, FailedDays: &days})
+	require.ErrorIs(t, err, ErrInvalidRetentionSettings)
+}
+
+func TestService_GetSettings_ProjectDefaults(t *testing.T) {
+	failedDays := 14
+	projectSettings := fakeProjectSettings{settings: []domain.ProjectSettings{{
+		ProjectID:             2,
+		ProjectSettingsValues: domain.ProjectSettingsValues{Retention: domain.RetentionDefaults{FailedDays: &failedDays}},
+	}}}
+	srv := New(nil, &fakeRetentionRepo{}, &fakeStoreCleaner{}, projectSettings)
+
+	settings, err := srv.GetSettings(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, domain.RetentionSettings{ProjectID: 2, FailedDays: &failedDays}, settings)
+}
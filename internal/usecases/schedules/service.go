@@ -0,0 +1,348 @@
+package schedules
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/cron"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.SchedulesUseCase = (*Service)(nil)
+
+var ErrInvalidSchedule = errors.New("invalid schedule")
+
+const (
+	defaultTimezone = "UTC"
+	maxPreviewRuns  = 50
+	dueBatchSize    = 100
+)
+
+type Service struct {
+	tx            db.TxManager
+	schedulesRepo contract.SchedulesRepository
+	workflowsRepo contract.WorkflowsRepository
+	engine        contract.WorkflowEngine
+	variables     contract.VariablesInjector
+}
+
+func New(
+	tx db.TxManager,
+	schedulesRepo contract.SchedulesRepository,
+	workflowsRepo contract.WorkflowsRepository,
+	engine contract.WorkflowEngine,
+	variables contract.VariablesInjector,
+) *Service {
+	return &Service{
+		tx:            tx,
+		schedulesRepo: schedulesRepo,
+		workflowsRepo: workflowsRepo,
+		engine:        engine,
+		variables:     variables,
+	}
+}
+
+func (s *Service) List(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	page, pageSize int,
+) ([]domain.Schedule, int, error) {
+	return s.schedulesRepo.List(ctx, projectID, page, pageSize)
+}
+
+func (s *Service) Get(ctx context.Context, projectID domain.ProjectID, id domain.ScheduleID) (domain.Schedule, error) {
+	return s.schedulesRepo.GetByID(ctx, projectID, id)
+}
+
+func (s *Service) Create(
+	ctx context.Context,
+	tenantID domain.TenantID,
+	projectID domain.ProjectID,
+	dto domain.ScheduleDTO,
+) (domain.Schedule, error) {
+	nextRunAt, err := s.prepare(ctx, tenantID, projectID, &dto)
+	if err != nil {
+		return domain.Schedule{}, err
+	}
+
+	var schedule domain.Schedule
+	err = s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		id, err := s.schedulesRepo.Create(ctx, projectID, dto, nextRunAt)
+		if err != nil {
+			return err
+		}
+
+		schedule, err = s.schedulesRepo.GetByID(ctx, projectID, id)
+
+		return err
+	})
+	if err != nil {
+		return domain.Schedule{}, fmt.Errorf("create schedule: %w", err)
+	}
+
+	return schedule, nil
+}
+
+func (s *Service) Update(
+	ctx context.Context,
+	tenantID domain.TenantID,
+	projectID domain.ProjectID,
+	id domain.ScheduleID,
+	dto domain.ScheduleDTO,
+) (domain.Schedule, error) {
+	nextRunAt, err := s.prepare(ctx, tenantID, projectID, &dto)
+	if err != nil {
+		return domain.Schedule{}, err
+	}
+
+	var schedule domain.Schedule
+	err = s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		if err := s.schedulesRepo.Update(ctx, projectID, id, dto, nextRunAt); err != nil {
+			return err
+		}
+
+		schedule, err = s.schedulesRepo.GetByID(ctx, projectID, id)
+
+		return err
+	})
+	if err != nil {
+		return domain.Schedule{}, fmt.Errorf("update schedule: %w", err)
+	}
+
+	return schedule, nil
+}
+
+func (s *Service) SetEnabled(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	id domain.ScheduleID,
+	enabled bool,
+) (domain.Schedule, error) {
+	var schedule domain.Schedule
+	err := s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		current, err := s.schedulesRepo.GetByID(ctx, projectID, id)
+		if err != nil {
+			return err
+		}
+
+		var nextRunAt *time.Time
+		if enabled {
+			nextRunAt, err = nextRun(current.CronExpr, current.Timezone, time.Now())
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := s.schedulesRepo.SetEnabled(ctx, projectID, id, enabled, nextRunAt); err != nil {
+			return err
+		}
+
+		schedule, err = s.schedulesRepo.GetByID(ctx, projectID, id)
+
+		return err
+	})
+	if err != nil {
+		return domain.Schedule{}, fmt.Errorf("set schedule enabled: %w", err)
+	}
+
+	return schedule, nil
+}
+
+func (s *Service) Delete(ctx context.Context, projectID domain.ProjectID, id domain.ScheduleID) error {
+	return s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		return s.schedulesRepo.Delete(ctx, projectID, id)
+	})
+}
+
+// PreviewNextRuns returns the next activation times of a cron expression without storing anything.
+func (s *Service) PreviewNextRuns(cronExpr, timezone string, count int) ([]time.Time, error) {
+	if count <= 0 || count > maxPreviewRuns {
+		return nil, fmt.Errorf("%w: count must be between 1 and %d", ErrInvalidSchedule, maxPreviewRuns)
+	}
+
+	schedule, err := parse(cronExpr, timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	runs, err := schedule.NextN(time.Now(), count)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSchedule, err)
+	}
+
+	return runs, nil
+}
+
+// RunDue starts workflow instances for all schedules whose next run time has come.
+// Missed activations are not replayed: the next run is computed from now. Activations are claimed,
+// moving next_run_at forward, in one transaction before the instances are started, so an activation
+// starts at most one instance even when its outcome cannot be saved.
+func (s *Service) RunDue(ctx context.Context, now time.Time) (int, error) {
+	var claimed []domain.Schedule
+	err := s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		due, err := s.schedulesRepo.ListDue(ctx, now, dueBatchSize)
+		if err != nil {
+			return fmt.Errorf("list due schedules: %w", err)
+		}
+
+		for i := range due {
+			schedule := due[i]
+			run := domain.ScheduleRun{RunAt: now}
+
+			run.NextRunAt, err = nextRun(schedule.CronExpr, schedule.Timezone, now)
+			if err != nil {
+				errMsg := err.Error()
+				run.Error = &errMsg
+			}
+
+			ok, err := s.schedulesRepo.ClaimRun(ctx, schedule.ID, runKey(schedule), run)
+			if err != nil {
+				return fmt.Errorf("claim schedule %d run: %w", schedule.ID, err)
+			}
+
+			if ok && run.Error == nil {
+				claimed = append(claimed, schedule)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	started := 0
+	for i := range claimed {
+		schedule := claimed[i]
+		run := domain.ScheduleRun{RunAt: now}
+
+		instanceID, err := s.start(ctx, schedule)
+		if err != nil {
+			errMsg := err.Error()
+			run.Error = &errMsg
+
+			slog.Error("Failed to start scheduled workflow",
+				"error", err,
+				"schedule_id", schedule.ID,
+				"workflow_id", schedule.WorkflowID,
+			)
+		} else {
+			run.InstanceID = &instanceID
+			started++
+		}
+
+		if err := s.schedulesRepo.SaveRun(ctx, schedule.ID, run); err != nil {
+			slog.Error("Failed to save schedule run",
+				"error", err,
+				"schedule_id", schedule.ID,
+				"instance_id", run.InstanceID,
+			)
+		}
+	}
+
+	return started, nil
+}
+
+// runKey identifies the activation of the schedule: its planned run time.
+func runKey(schedule domain.Schedule) string {
+	if schedule.NextRunAt == nil {
+		return ""
+	}
+
+	return schedule.NextRunAt.UTC().Format(time.RFC3339Nano)
+}
+
+// start starts an instance of the schedule workflow, on the active version when the workflow is pinned,
+// with the project variables in its input.
+func (s *Service) start(ctx context.Context, schedule domain.Schedule) (int64, error) {
+	workflowID, err := s.workflowsRepo.ResolveActiveWorkflowID(ctx, schedule.ProjectID, schedule.WorkflowID)
+	if err != nil {
+		return 0, err
+	}
+
+	input, err := s.variables.InjectVariables(ctx, schedule.ProjectID, schedule.Input)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.engine.Start(ctx, workflowID, input)
+}
+
+// prepare validates the DTO, fills defaults and returns the first run time.
+func (s *Service) prepare(
+	ctx context.Context,
+	tenantID domain.TenantID,
+	projectID domain.ProjectID,
+	dto *domain.ScheduleDTO,
+) (*time.Time, error) {
+	if dto.Name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidSchedule)
+	}
+
+	if dto.WorkflowID == "" {
+		return nil, fmt.Errorf("%w: workflow_id is required", ErrInvalidSchedule)
+	}
+
+	if dto.Timezone == "" {
+		dto.Timezone = defaultTimezone
+	}
+
+	if len(dto.Input) == 0 {
+		dto.Input = json.RawMessage(`{}`)
+	} else if !json.Valid(dto.Input) {
+		return nil, fmt.Errorf("%w: input must be valid JSON", ErrInvalidSchedule)
+	}
+
+	if _, err := s.workflowsRepo.GetWorkflowDefinition(ctx, tenantID, projectID, dto.WorkflowID); err != nil {
+		return nil, fmt.Errorf("get workflow definition: %w", err)
+	}
+
+	nextRunAt, err := nextRun(dto.CronExpr, dto.Timezone, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if !dto.Enabled {
+		return nil, nil
+	}
+
+	return nextRunAt, nil
+}
+
+func parse(cronExpr, timezone string) (*cron.Schedule, error) {
+	if timezone == "" {
+		timezone = defaultTimezone
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unknown timezone %q", ErrInvalidSchedule, timezone)
+	}
+
+	schedule, err := cron.Parse(cronExpr, loc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSchedule, err)
+	}
+
+	return schedule, nil
+}
+
+func nextRun(cronExpr, timezone string, after time.Time) (*time.Time, error) {
+	schedule, err := parse(cronExpr, timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	next, err := schedule.Next(after)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSchedule, err)
+	}
+
+	return &next, nil
+}
@@ -0,0 +1,177 @@
+package schedules
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type fakeTx struct{}
+
+func (fakeTx) ReadCommitted(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (fakeTx) RepeatableRead(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// fakeSchedulesRepo keeps the schedules in memory, ListDue returns the enabled ones that are due.
+type fakeSchedulesRepo struct {
+	contract.SchedulesRepository
+	schedules []domain.Schedule
+	runKeys   map[domain.ScheduleID]string
+	saveErr   error
+	saved     []domain.ScheduleRun
+}
+
+func (r *fakeSchedulesRepo) ListDue(_ context.Context, now time.Time, _ int) ([]domain.Schedule, error) {
+	var due []domain.Schedule
+	for _, schedule := range r.schedules {
+		if schedule.Enabled && schedule.NextRunAt != nil && !schedule.NextRunAt.After(now) {
+			due = append(due, schedule)
+		}
+	}
+
+	return due, nil
+}
+
+func (r *fakeSchedulesRepo) ClaimRun(
+	_ context.Context,
+	id domain.ScheduleID,
+	runKey string,
+	run domain.ScheduleRun,
+) (bool, error) {
+	if r.runKeys[id] == runKey {
+		return false, nil
+	}
+	r.runKeys[id] = runKey
+
+	for i := range r.schedules {
+		if r.schedules[i].ID == id {
+			r.schedules[i].NextRunAt = run.NextRunAt
+		}
+	}
+
+	return true, nil
+}
+
+func (r *fakeSchedulesRepo) SaveRun(_ context.Context, _ domain.ScheduleID, run domain.ScheduleRun) error {
+	if r.saveErr != nil {
+		return r.saveErr
+	}
+	r.saved = append(r.saved, run)
+
+	return nil
+}
+
+type fakeWorkflowsRepo struct {
+	contract.WorkflowsRepository
+}
+
+func (fakeWorkflowsRepo) ResolveActiveWorkflowID(_ context.Context, _ domain.ProjectID, workflowID string) (string, error) {
+	return workflowID, nil
+}
+
+type noVariables struct{}
+
+func (noVariables) InjectVariables(_ context.Context, _ domain.ProjectID, input json.RawMessage) (json.RawMessage, error) {
+	return input, nil
+}
+
+type fakeEngine struct {
+	started []string
+}
+
+func (e *fakeEngine) Start(_ context.Context, workflowID string, _ json.RawMessage) (int64, error) {
+	e.started = append(e.started, workflowID)
+
+	return int64(len(e.started)), nil
+}
+
+func newTestService(repo *fakeSchedulesRepo, engine *fakeEngine) *Service {
+	return New(fakeTx{}, repo, fakeWorkflowsRepo{}, engine, noVariables{})
+}
+
+func TestService_RunDue(t *testing.T) {
+	now := time.Date(2026, 1, 1, 10, 0, 30, 0, time.UTC)
+	planned := now.Add(-30 * time.Second)
+
+	repo := &fakeSchedulesRepo{
+		schedules: []domain.Schedule{
+			{ID: 1, WorkflowID: "orders-v1", CronExpr: "* * * * *", Timezone: "UTC", Enabled: true, NextRunAt: &planned},
+			{ID: 2, WorkflowID: "billing-v1", CronExpr: "* * * * *", Timezone: "UTC", Enabled: false, NextRunAt: &planned},
+		},
+		runKeys: map[domain.ScheduleID]string{},
+	}
+	engine := &fakeEngine{}
+	srv := newTestService(repo, engine)
+
+	started, err := srv.RunDue(context.Background(), now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, started)
+	assert.Equal(t, []string{"orders-v1"}, engine.started)
+	require.Len(t, repo.saved, 1)
+	assert.Equal(t, int64(1), *repo.saved[0].InstanceID)
+
+	require.NotNil(t, repo.schedules[0].NextRunAt)
+	assert.Equal(t, time.Date(2026, 1, 1, 10, 1, 0, 0, time.UTC), repo.schedules[0].NextRunAt.UTC())
+
+	// The activation is not due anymore
+	started, err = srv.RunDue(context.Background(), now)
+	require.NoError(t, err)
+	assert.Zero(t, started)
+	assert.Len(t, engine.started, 1)
+}
+
+func TestService_RunDue_SaveRunFails(t *testing.T) {
+	now := time.Date(2026, 1, 1, 10, 0, 30, 0, time.UTC)
+	planned := now.Add(-30 * time.Second)
+
+	repo := &fakeSchedulesRepo{
+		schedules: []domain.Schedule{
+			{ID: 1, WorkflowID: "orders-v1", CronExpr: "* * * * *", Timezone: "UTC", Enabled: true, NextRunAt: &planned},
+		},
+		runKeys: map[domain.ScheduleID]string{},
+		saveErr: errors.New("connection reset"),
+	}
+	engine := &fakeEngine{}
+	srv := newTestService(repo, engine)
+
+	started, err := srv.RunDue(context.Background(), now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, started)
+
+	started, err = srv.RunDue(context.Background(), now)
+	require.NoError(t, err)
+	assert.Zero(t, started)
+	assert.Len(t, engine.started, 1)
+}
+
+func TestService_RunDue_ClaimedActivation(t *testing.T) {
+	now := time.Date(2026, 1, 1, 10, 0, 30, 0, time.UTC)
+	planned := now.Add(-30 * time.Second)
+	schedule := domain.Schedule{
+		ID: 1, WorkflowID: "orders-v1", CronExpr: "* * * * *", Timezone: "UTC", Enabled: true, NextRunAt: &planned,
+	}
+
+	repo := &fakeSchedulesRepo{
+		schedules: []domain.Schedule{schedule},
+		runKeys:   map[domain.ScheduleID]string{1: runKey(schedule)},
+	}
+	engine := &fakeEngine{}
+	srv := newTestService(repo, engine)
+
+	started, err := srv.RunDue(context.Background(), now)
+	require.NoError(t, err)
+	assert.Zero(t, started)
+	assert.Empty(t, engine.started)
+}
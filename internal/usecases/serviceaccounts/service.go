@@ -0,0 +1,207 @@
+package serviceaccounts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.ServiceAccountsUseCase = (*Service)(nil)
+
+var ErrInvalidServiceAccount = errors.New("invalid service account")
+
+const (
+	// unusablePasswordHash never matches a password, so service accounts cannot log in.
+	unusablePasswordHash = "!"
+	emailDomain          = "service-accounts.invalid"
+)
+
+var nameRe = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+
+type Service struct {
+	tx              db.TxManager
+	usersRepo       contract.UsersRepository
+	membershipsRepo contract.MembershipsRepository
+	rolesRepo       contract.RolesRepository
+	apiTokens       contract.APITokensUseCase
+}
+
+func New(
+	tx db.TxManager,
+	usersRepo contract.UsersRepository,
+	membershipsRepo contract.MembershipsRepository,
+	rolesRepo contract.RolesRepository,
+	apiTokens contract.APITokensUseCase,
+) *Service {
+	return &Service{
+		tx:              tx,
+		usersRepo:       usersRepo,
+		membershipsRepo: membershipsRepo,
+		rolesRepo:       rolesRepo,
+		apiTokens:       apiTokens,
+	}
+}
+
+func (s *Service) List(ctx context.Context, projectID domain.ProjectID) ([]domain.ServiceAccount, error) {
+	users, err := s.usersRepo.ListServiceAccounts(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	memberships, err := s.membershipsRepo.ListForProject(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("list project memberships: %w", err)
+	}
+
+	membershipByUser := make(map[domain.UserID]domain.ProjectMembership, len(memberships))
+	for i := range memberships {
+		membershipByUser[memberships[i].UserID] = memberships[i]
+	}
+
+	accounts := make([]domain.ServiceAccount, 0, len(users))
+	for i := range users {
+		accounts = append(accounts, toServiceAccount(&users[i], projectID, membershipByUser[users[i].ID]))
+	}
+
+	return accounts, nil
+}
+
+func (s *Service) Create(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	dto domain.ServiceAccountDTO,
+) (domain.ServiceAccount, error) {
+	if !nameRe.MatchString(dto.Name) {
+		return domain.ServiceAccount{}, fmt.Errorf(
+			"%w: name must be 1-63 lowercase letters, digits or dashes", ErrInvalidServiceAccount)
+	}
+
+	role, err := s.rolesRepo.GetByKey(ctx, dto.RoleKey)
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			return domain.ServiceAccount{}, fmt.Errorf("%w: unknown role %q", ErrInvalidServiceAccount, dto.RoleKey)
+		}
+
+		return domain.ServiceAccount{}, fmt.Errorf("get role: %w", err)
+	}
+
+	username := usernamePrefix(projectID) + dto.Name
+
+	var account domain.ServiceAccount
+	err = s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		user, err := s.usersRepo.Create(ctx, domain.UserDTO{
+			Username:         username,
+			Email:            username + "@" + emailDomain,
+			PasswordHash:     unusablePasswordHash,
+			IsServiceAccount: true,
+			ServiceProjectID: &projectID,
+			EmailVerified:    true,
+		})
+		if err != nil {
+			if db.IsUniqueViolation(err) {
+				return domain.ErrEntityAlreadyExists
+			}
+
+			return err
+		}
+
+		membership, err := s.membershipsRepo.Create(ctx, projectID, user.ID, role.ID)
+		if err != nil {
+			return fmt.Errorf("create membership: %w", err)
+		}
+
+		account = toServiceAccount(&user, projectID, membership)
+
+		return nil
+	})
+	if err != nil {
+		return domain.ServiceAccount{}, fmt.Errorf("create service account: %w", err)
+	}
+
+	return account, nil
+}
+
+func (s *Service) Delete(ctx context.Context, projectID domain.ProjectID, id domain.UserID) error {
+	if _, err := s.get(ctx, projectID, id); err != nil {
+		return err
+	}
+
+	// Memberships, sessions and API tokens are removed by cascade.
+	return s.usersRepo.Delete(ctx, id)
+}
+
+func (s *Service) ListTokens(ctx context.Context, projectID domain.ProjectID, id domain.UserID) ([]domain.APIToken, error) {
+	if _, err := s.get(ctx, projectID, id); err != nil {
+		return nil, err
+	}
+
+	return s.apiTokens.List(ctx, id)
+}
+
+func (s *Service) CreateToken(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	id domain.UserID,
+	dto domain.APITokenDTO,
+) (domain.APIToken, string, error) {
+	if _, err := s.get(ctx, projectID, id); err != nil {
+		return domain.APIToken{}, "", err
+	}
+
+	return s.apiTokens.Create(ctx, id, dto)
+}
+
+func (s *Service) DeleteToken(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	id domain.UserID,
+	tokenID domain.APITokenID,
+) error {
+	if _, err := s.get(ctx, projectID, id); err != nil {
+		return err
+	}
+
+	return s.apiTokens.Delete(ctx, id, tokenID)
+}
+
+// get returns the service account user, or domain.ErrEntityNotFound if the user
+// is not a service account of the project.
+func (s *Service) get(ctx context.Context, projectID domain.ProjectID, id domain.UserID) (domain.User, error) {
+	user, err := s.usersRepo.GetByID(ctx, id)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	if !user.IsServiceAccount || user.ServiceProjectID == nil || *user.ServiceProjectID != projectID {
+		return domain.User{}, domain.ErrEntityNotFound
+	}
+
+	return user, nil
+}
+
+func usernamePrefix(projectID domain.ProjectID) string {
+	return "svc-" + projectID.String() + "-"
+}
+
+func toServiceAccount(
+	user *domain.User,
+	projectID domain.ProjectID,
+	membership domain.ProjectMembership,
+) domain.ServiceAccount {
+	return domain.ServiceAccount{
+		ID:        user.ID,
+		ProjectID: projectID,
+		Name:      strings.TrimPrefix(user.Username, usernamePrefix(projectID)),
+		Username:  user.Username,
+		RoleKey:   membership.RoleKey,
+		RoleName:  membership.RoleName,
+		IsActive:  user.IsActive,
+		CreatedAt: user.CreatedAt,
+	}
+}
@@ -0,0 +1,274 @@
+package settings
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/repository/auditlog"
+	"github.com/rom8726/floxy-manager/pkg/crypt"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+const (
+	smtpConfigSetting       = "smtp_config"
+	samlSettingsSetting     = "saml_settings"
+	generalSettingsSetting  = "general_settings"
+	licenseAgreementSetting = "license_agreement"
+)
+
+var ErrInvalidSettings = errors.New("invalid settings")
+
+// Defaults are the runtime-tunable settings taken from the environment, they apply until saved.
+type Defaults struct {
+	SMTP    domain.SMTPConfig
+	SAML    domain.SAMLSettings
+	General domain.GeneralSettings
+	// RotatedSMTPCredentials sets the current credentials of SMTP when they are rotated secrets, optional.
+	RotatedSMTPCredentials func(config *domain.SMTPConfig)
+}
+
+// GetSMTPConfig returns the mail server configuration with the secrets decrypted.
+func (s *Service) GetSMTPConfig(ctx context.Context) (domain.SMTPConfig, error) {
+	setting, err := s.settingsRepo.GetByName(ctx, smtpConfigSetting)
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			config := *s.smtpDefaults.Load()
+			if s.defaults.RotatedSMTPCredentials != nil {
+				s.defaults.RotatedSMTPCredentials(&config)
+			}
+
+			return config, nil
+		}
+
+		return domain.SMTPConfig{}, fmt.Errorf("get SMTP config: %w", err)
+	}
+
+	var config domain.SMTPConfig
+	if err := json.Unmarshal(setting.Value, &config); err != nil {
+		return domain.SMTPConfig{}, fmt.Errorf("unmarshal SMTP config: %w", err)
+	}
+
+	for name, secret := range smtpSecrets(&config) {
+		if *secret == "" {
+			continue
+		}
+
+		secretBytes, err := base64.StdEncoding.DecodeString(*secret)
+		if err != nil {
+			return domain.SMTPConfig{}, fmt.Errorf("decode SMTP %s: %w", name, err)
+		}
+
+		secretBytes, err = crypt.DecryptAESGCM(secretBytes, s.secret)
+		if err != nil {
+			return domain.SMTPConfig{}, fmt.Errorf("decrypt SMTP %s: %w", name, err)
+		}
+
+		*secret = string(secretBytes)
+	}
+
+	return config, nil
+}
+
+// UpdateSMTPConfig saves the mail server configuration. Empty secrets keep the current ones.
+func (s *Service) UpdateSMTPConfig(ctx context.Context, config domain.SMTPConfig) error {
+	if config.AuthMethod == "" {
+		config.AuthMethod = domain.SMTPAuthPlain
+	}
+
+	current, err := s.GetSMTPConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	currentSecrets := smtpSecrets(&current)
+	for name, secret := range smtpSecrets(&config) {
+		if *secret == "" {
+			*secret = *currentSecrets[name]
+		}
+	}
+
+	if err := validateSMTPConfig(config); err != nil {
+		return err
+	}
+
+	stored := config
+	for name, secret := range smtpSecrets(&stored) {
+		if *secret == "" {
+			continue
+		}
+
+		secretEncrypted, err := crypt.EncryptAESGCM([]byte(*secret), s.secret)
+		if err != nil {
+			return fmt.Errorf("encrypt SMTP %s: %w", name, err)
+		}
+
+		*secret = base64.StdEncoding.EncodeToString(secretEncrypted)
+	}
+
+	return s.saveAudited(ctx, smtpConfigSetting, "Mail server configuration", current, config, stored)
+}
+
+// smtpSecrets returns the fields of the config stored encrypted, keyed by name.
+func smtpSecrets(config *domain.SMTPConfig) map[string]*string {
+	return map[string]*string{
+		"password":             &config.Password,
+		"OAuth2 client secret": &config.OAuth2ClientSecret,
+		"OAuth2 refresh token": &config.OAuth2RefreshToken,
+	}
+}
+
+func validateSMTPConfig(config domain.SMTPConfig) error {
+	if config.Addr != "" && config.From == "" {
+		return fmt.Errorf("%w: from is required when addr is set", ErrInvalidSettings)
+	}
+
+	switch config.StartTLS {
+	case "", domain.SMTPStartTLSRequire, domain.SMTPStartTLSOpportunistic, domain.SMTPStartTLSDisabled:
+	default:
+		return fmt.Errorf("%w: starttls must be empty, %s, %s or %s", ErrInvalidSettings,
+			domain.SMTPStartTLSRequire, domain.SMTPStartTLSOpportunistic, domain.SMTPStartTLSDisabled)
+	}
+
+	if config.UseTLS && config.StartTLS != "" {
+		return fmt.Errorf("%w: starttls cannot be set with use_tls", ErrInvalidSettings)
+	}
+
+	switch config.AuthMethod {
+	case domain.SMTPAuthPlain:
+	case domain.SMTPAuthXOAuth2:
+		if config.User == "" {
+			return fmt.Errorf("%w: user is required for xoauth2", ErrInvalidSettings)
+		}
+
+		if !isAbsoluteURL(config.OAuth2TokenURL) {
+			return fmt.Errorf("%w: oauth2_token_url must be an absolute URL", ErrInvalidSettings)
+		}
+
+		if config.OAuth2ClientID == "" {
+			return fmt.Errorf("%w: oauth2_client_id is required for xoauth2", ErrInvalidSettings)
+		}
+
+		if config.OAuth2ClientSecret == "" && config.OAuth2RefreshToken == "" {
+			return fmt.Errorf("%w: oauth2_client_secret or oauth2_refresh_token is required for xoauth2",
+				ErrInvalidSettings)
+		}
+	default:
+		return fmt.Errorf("%w: auth_method must be %s or %s",
+			ErrInvalidSettings, domain.SMTPAuthPlain, domain.SMTPAuthXOAuth2)
+	}
+
+	return nil
+}
+
+func (s *Service) GetSAMLSettings(ctx context.Context) (domain.SAMLSettings, error) {
+	return getJSON(ctx, s, samlSettingsSetting, s.defaults.SAML)
+}
+
+func (s *Service) UpdateSAMLSettings(ctx context.Context, settings domain.SAMLSettings) error {
+	if settings.Enabled && !isAbsoluteURL(settings.IDPMetadataURL) {
+		return fmt.Errorf("%w: idp_metadata_url must be an absolute URL", ErrInvalidSettings)
+	}
+
+	for _, mapping := range settings.GroupMappings {
+		if mapping.Group == "" || mapping.ProjectID <= 0 || mapping.RoleKey == "" {
+			return fmt.Errorf("%w: invalid group mapping %+v", ErrInvalidSettings, mapping)
+		}
+	}
+
+	current, err := s.GetSAMLSettings(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.saveAudited(ctx, samlSettingsSetting, "Default SAML provider settings", current, settings, settings)
+}
+
+func (s *Service) GetGeneralSettings(ctx context.Context) (domain.GeneralSettings, error) {
+	return getJSON(ctx, s, generalSettingsSetting, s.defaults.General)
+}
+
+func (s *Service) UpdateGeneralSettings(ctx context.Context, settings domain.GeneralSettings) error {
+	settings.FrontendURL = strings.TrimRight(settings.FrontendURL, "/")
+	if !isAbsoluteURL(settings.FrontendURL) {
+		return fmt.Errorf("%w: frontend_url must be an absolute URL", ErrInvalidSettings)
+	}
+
+	current, err := s.GetGeneralSettings(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.saveAudited(ctx, generalSettingsSetting, "General installation settings", current, settings, settings)
+}
+
+// GetLicenseAgreement returns the license agreement, its version is empty when none was published.
+func (s *Service) GetLicenseAgreement(ctx context.Context) (domain.LicenseAgreement, error) {
+	return getJSON(ctx, s, licenseAgreementSetting, domain.LicenseAgreement{})
+}
+
+func (s *Service) UpdateLicenseAgreement(ctx context.Context, agreement domain.LicenseAgreement) error {
+	agreement.Version = strings.TrimSpace(agreement.Version)
+	if agreement.Version == "" || strings.TrimSpace(agreement.Text) == "" {
+		return fmt.Errorf("%w: version and text are required", ErrInvalidSettings)
+	}
+
+	current, err := s.GetLicenseAgreement(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.saveAudited(ctx, licenseAgreementSetting, "License agreement accepted by users", current, agreement, agreement)
+}
+
+// saveAudited stores the value of a setting and records the changes from current to updated in the audit log.
+func (s *Service) saveAudited(ctx context.Context, name, description string, current, updated, stored any) error {
+	changes, err := auditlog.Diff(current, updated)
+	if err != nil {
+		return fmt.Errorf("diff %s: %w", name, err)
+	}
+
+	return s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		if err := s.settingsRepo.SetByName(ctx, name, stored, description); err != nil {
+			return fmt.Errorf("update %s: %w", name, err)
+		}
+
+		err := auditlog.WriteChangeLog(ctx, db.TxFromContext(ctx), domain.EntitySetting, name,
+			domain.ActionUpdate, 0, changes)
+		if err != nil {
+			return fmt.Errorf("write audit log: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// getJSON returns the stored value of a setting, the default if it was never saved.
+func getJSON[T any](ctx context.Context, s *Service, name string, defaultValue T) (T, error) {
+	setting, err := s.settingsRepo.GetByName(ctx, name)
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			return defaultValue, nil
+		}
+
+		return defaultValue, fmt.Errorf("get %s: %w", name, err)
+	}
+
+	var value T
+	if err := json.Unmarshal(setting.Value, &value); err != nil {
+		return defaultValue, fmt.Errorf("unmarshal %s: %w", name, err)
+	}
+
+	return value, nil
+}
+
+func isAbsoluteURL(value string) bool {
+	u, err := url.Parse(value)
+
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,93 @@
+package settings
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/crypt"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+type fakeSettingsRepo struct {
+	contract.SettingRepository
+	values map[string]json.RawMessage
+}
+
+func (r *fakeSettingsRepo) GetByName(_ context.Context, name string) (*domain.Setting, error) {
+	value, ok := r.values[name]
+	if !ok {
+		return nil, domain.ErrEntityNotFound
+	}
+
+	return &domain.Setting{Name: name, Value: value}, nil
+}
+
+func TestService_GetSMTPConfig(t *testing.T) {
+	ctx := context.Background()
+	defaults := &Defaults{SMTP: domain.SMTPConfig{Addr: "env:25", From: "env@example.com"}}
+	repo := &fakeSettingsRepo{values: map[string]json.RawMessage{}}
+	srv := New(repo, nil, testSecret, defaults)
+
+	// The environment applies until the config is saved
+	config, err := srv.GetSMTPConfig(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, defaults.SMTP, config)
+
+	password, err := crypt.EncryptAESGCM([]byte("s3cr3t"), []byte(testSecret))
+	require.NoError(t, err)
+	repo.values[smtpConfigSetting], err = json.Marshal(domain.SMTPConfig{
+		Addr:     "smtp.example.com:587",
+		User:     "mailer",
+		Password: base64.StdEncoding.EncodeToString(password),
+		From:     "noreply@example.com",
+	})
+	require.NoError(t, err)
+
+	config, err = srv.GetSMTPConfig(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "smtp.example.com:587", config.Addr)
+	assert.Equal(t, "s3cr3t", config.Password)
+}
+
+func TestService_UpdateRuntimeSettings_Invalid(t *testing.T) {
+	ctx := context.Background()
+	srv := New(&fakeSettingsRepo{values: map[string]json.RawMessage{}}, nil, testSecret, &Defaults{})
+
+	err := srv.UpdateSMTPConfig(ctx, domain.SMTPConfig{Addr: "smtp.example.com:587"})
+	require.ErrorIs(t, err, ErrInvalidSettings)
+
+	err = srv.UpdateSMTPConfig(ctx, domain.SMTPConfig{
+		Addr:           "smtp.office365.com:587",
+		User:           "noreply@example.com",
+		From:           "noreply@example.com",
+		AuthMethod:     domain.SMTPAuthXOAuth2,
+		OAuth2TokenURL: "https://login.microsoftonline.com/tenant/oauth2/v2.0/token",
+		OAuth2ClientID: "client",
+	})
+	require.ErrorIs(t, err, ErrInvalidSettings)
+
+	err = srv.UpdateSMTPConfig(ctx, domain.SMTPConfig{AuthMethod: "cram-md5"})
+	require.ErrorIs(t, err, ErrInvalidSettings)
+
+	err = srv.UpdateSMTPConfig(ctx, domain.SMTPConfig{UseTLS: true, StartTLS: domain.SMTPStartTLSRequire})
+	require.ErrorIs(t, err, ErrInvalidSettings)
+
+	err = srv.UpdateSAMLSettings(ctx, domain.SAMLSettings{Enabled: true, IDPMetadataURL: "idp/metadata"})
+	require.ErrorIs(t, err, ErrInvalidSettings)
+
+	err = srv.UpdateSAMLSettings(ctx, domain.SAMLSettings{
+		GroupMappings: []domain.SAMLGroupMapping{{Group: "admins", RoleKey: "project_owner"}},
+	})
+	require.ErrorIs(t, err, ErrInvalidSettings)
+
+	err = srv.UpdateGeneralSettings(ctx, domain.GeneralSettings{FrontendURL: "ftp://example.com"})
+	require.ErrorIs(t, err, ErrInvalidSettings)
+}
@@ -6,29 +6,55 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
+	"sync/atomic"
 
 	"github.com/rom8726/floxy-manager/internal/contract"
 	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/repository/auditlog"
 	"github.com/rom8726/floxy-manager/pkg/crypt"
+	"github.com/rom8726/floxy-manager/pkg/db"
 )
 
+const (
+	ldapConfigSetting          = "ldap_config"
+	auditExportRowLimitSetting = "audit_export_row_limit"
+	auditReadAccessSetting     = "audit_read_access"
+)
+
+var _ contract.SettingsUseCase = (*Service)(nil)
+
 // Service provides settings management functionality.
 type Service struct {
 	settingsRepo contract.SettingRepository
+	tx           db.TxManager
 	secret       []byte
+	defaults     Defaults
+	// smtpDefaults is replaced on config reload, the other defaults need a restart.
+	smtpDefaults atomic.Pointer[domain.SMTPConfig]
 }
 
 // New creates a new settings use case.
-func New(settingsRepo contract.SettingRepository, secret string) *Service {
-	return &Service{
+func New(settingsRepo contract.SettingRepository, tx db.TxManager, secret string, defaults *Defaults) *Service {
+	service := &Service{
 		settingsRepo: settingsRepo,
+		tx:           tx,
 		secret:       []byte(secret),
+		defaults:     *defaults,
 	}
+	service.SetSMTPDefaults(defaults.SMTP)
+
+	return service
+}
+
+// SetSMTPDefaults replaces the mail server configuration used until one is saved.
+func (s *Service) SetSMTPDefaults(config domain.SMTPConfig) {
+	s.smtpDefaults.Store(&config)
 }
 
 // GetLDAPConfig retrieves LDAP configuration from settings.
 func (s *Service) GetLDAPConfig(ctx context.Context) (*domain.LDAPConfig, error) {
-	setting, err := s.settingsRepo.GetByName(ctx, "ldap_config")
+	setting, err := s.settingsRepo.GetByName(ctx, ldapConfigSetting)
 	if err != nil {
 		// Don't wrap ErrEntityNotFound to allow errors.Is to work
 		if errors.Is(err, domain.ErrEntityNotFound) {
@@ -62,7 +88,19 @@ func (s *Service) GetLDAPConfig(ctx context.Context) (*domain.LDAPConfig, error)
 }
 
 // UpdateLDAPConfig updates LDAP configuration in settings.
+// Changed fields are recorded in the audit log with the bind password redacted.
 func (s *Service) UpdateLDAPConfig(ctx context.Context, config *domain.LDAPConfig) error {
+	// An unreadable stored config (e.g. after a secret key change) must not block fixing it
+	current, err := s.GetLDAPConfig(ctx)
+	if err != nil && !errors.Is(err, domain.ErrEntityNotFound) {
+		slog.WarnContext(ctx, "Failed to read current LDAP config for audit", "error", err)
+	}
+
+	changes, err := auditlog.Diff(current, config)
+	if err != nil {
+		return fmt.Errorf("diff LDAP config: %w", err)
+	}
+
 	bindPasswordEncrypted, err := crypt.EncryptAESGCM([]byte(config.BindPassword), s.secret)
 	if err != nil {
 		return fmt.Errorf("encrypt LDAP bind password: %w", err)
@@ -70,14 +108,59 @@ func (s *Service) UpdateLDAPConfig(ctx context.Context, config *domain.LDAPConfi
 
 	config.BindPassword = base64.StdEncoding.EncodeToString(bindPasswordEncrypted)
 
-	err = s.settingsRepo.SetByName(
+	return s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		err := s.settingsRepo.SetByName(
+			ctx,
+			ldapConfigSetting,
+			config,
+			"LDAP server configuration for user and group synchronization",
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update LDAP config: %w", err)
+		}
+
+		err = auditlog.WriteChangeLog(ctx, db.TxFromContext(ctx), domain.EntityLDAPConfig, ldapConfigSetting,
+			domain.ActionUpdate, 0, changes)
+		if err != nil {
+			return fmt.Errorf("write audit log: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// GetAuditExportRowLimit returns the maximum number of rows of an audit log export.
+func (s *Service) GetAuditExportRowLimit(ctx context.Context) (int, error) {
+	setting, err := s.settingsRepo.GetByName(ctx, auditExportRowLimitSetting)
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			return domain.DefaultAuditExportRowLimit, nil
+		}
+		return 0, fmt.Errorf("get audit export row limit: %w", err)
+	}
+
+	var limit int
+	if err := json.Unmarshal(setting.Value, &limit); err != nil {
+		return 0, fmt.Errorf("unmarshal audit export row limit: %w", err)
+	}
+
+	return limit, nil
+}
+
+// SetAuditExportRowLimit updates the maximum number of rows of an audit log export.
+func (s *Service) SetAuditExportRowLimit(ctx context.Context, limit int) error {
+	if limit <= 0 || limit > domain.MaxAuditExportRowLimit {
+		return fmt.Errorf("audit export row limit must be between 1 and %d", domain.MaxAuditExportRowLimit)
+	}
+
+	err := s.settingsRepo.SetByName(
 		ctx,
-		"ldap_config",
-		config,
-		"LDAP server configuration for user and group synchronization",
+		auditExportRowLimitSetting,
+		limit,
+		"Maximum number of rows of an audit log export",
 	)
 	if err != nil {
-		return fmt.Errorf("failed to update LDAP config: %w", err)
+		return fmt.Errorf("failed to update audit export row limit: %w", err)
 	}
 
 	return nil
@@ -102,3 +185,37 @@ func (s *Service) DeleteSetting(ctx context.Context, name string) error {
 func (s *Service) ListSettings(ctx context.Context) ([]*domain.Setting, error) {
 	return s.settingsRepo.List(ctx)
 }
+
+// GetAuditReadAccess reports whether reads of sensitive resources are recorded in the audit log.
+// It is disabled until a superuser enables it.
+func (s *Service) GetAuditReadAccess(ctx context.Context) (bool, error) {
+	setting, err := s.settingsRepo.GetByName(ctx, auditReadAccessSetting)
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("get audit read access: %w", err)
+	}
+
+	var enabled bool
+	if err := json.Unmarshal(setting.Value, &enabled); err != nil {
+		return false, fmt.Errorf("unmarshal audit read access: %w", err)
+	}
+
+	return enabled, nil
+}
+
+// SetAuditReadAccess enables or disables recording reads of sensitive resources in the audit log.
+func (s *Service) SetAuditReadAccess(ctx context.Context, enabled bool) error {
+	err := s.settingsRepo.SetByName(
+		ctx,
+		auditReadAccessSetting,
+		enabled,
+		"Record reads of sensitive resources (users, LDAP config, audit log, DLQ payloads) in the audit log",
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update audit read access: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,286 @@
+package ssoproviders
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"slices"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/repository/auditlog"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.SSOProvidersUseCase = (*Service)(nil)
+
+const providersSetting = "sso_providers"
+
+var (
+	ErrInvalidProvider   = errors.New("invalid SSO provider")
+	ErrProviderExists    = errors.New("SSO provider already exists")
+	ErrProviderNotLoaded = errors.New("SSO provider could not be loaded")
+)
+
+// providerNameRe matches the names allowed for SAML providers of the environment.
+var providerNameRe = regexp.MustCompile(`^[a-z][a-z0-9_]{0,31}$`)
+
+// Config lists the names of the providers configured in the environment, they cannot be managed at runtime.
+type Config struct {
+	ReservedNames []string
+}
+
+type Service struct {
+	settingsRepo  contract.SettingRepository
+	tx            db.TxManager
+	settings      contract.RuntimeSettingsReader
+	loader        contract.SAMLProviderLoader
+	reservedNames []string
+}
+
+func New(
+	settingsRepo contract.SettingRepository,
+	tx db.TxManager,
+	settings contract.RuntimeSettingsReader,
+	loader contract.SAMLProviderLoader,
+	config *Config,
+) *Service {
+	return &Service{
+		settingsRepo:  settingsRepo,
+		tx:            tx,
+		settings:      settings,
+		loader:        loader,
+		reservedNames: config.ReservedNames,
+	}
+}
+
+func (s *Service) List(ctx context.Context) ([]domain.SSOProviderSettings, error) {
+	setting, err := s.settingsRepo.GetByName(ctx, providersSetting)
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			return []domain.SSOProviderSettings{}, nil
+		}
+
+		return nil, fmt.Errorf("get SSO providers: %w", err)
+	}
+
+	var providers []domain.SSOProviderSettings
+	if err := json.Unmarshal(setting.Value, &providers); err != nil {
+		return nil, fmt.Errorf("unmarshal SSO providers: %w", err)
+	}
+
+	return providers, nil
+}
+
+func (s *Service) Get(ctx context.Context, name string) (domain.SSOProviderSettings, error) {
+	providers, err := s.List(ctx)
+	if err != nil {
+		return domain.SSOProviderSettings{}, err
+	}
+
+	i := slices.IndexFunc(providers, func(p domain.SSOProviderSettings) bool { return p.Name == name })
+	if i < 0 {
+		return domain.SSOProviderSettings{}, domain.ErrEntityNotFound
+	}
+
+	return providers[i], nil
+}
+
+// Create saves and registers a provider. An enabled provider is only saved if it can be loaded.
+func (s *Service) Create(ctx context.Context, provider domain.SSOProviderSettings) error {
+	if err := s.validate(provider); err != nil {
+		return err
+	}
+
+	return s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		providers, err := s.List(ctx)
+		if err != nil {
+			return err
+		}
+
+		if slices.ContainsFunc(providers, func(p domain.SSOProviderSettings) bool { return p.Name == provider.Name }) {
+			return ErrProviderExists
+		}
+
+		providers = append(providers, provider)
+		if err := s.save(ctx, providers, provider.Name, domain.ActionCreate, nil, provider); err != nil {
+			return err
+		}
+
+		return s.load(ctx, provider)
+	})
+}
+
+// Update replaces the settings of a provider and reloads it.
+func (s *Service) Update(ctx context.Context, provider domain.SSOProviderSettings) error {
+	if err := s.validate(provider); err != nil {
+		return err
+	}
+
+	return s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		providers, err := s.List(ctx)
+		if err != nil {
+			return err
+		}
+
+		i := slices.IndexFunc(providers, func(p domain.SSOProviderSettings) bool { return p.Name == provider.Name })
+		if i < 0 {
+			return domain.ErrEntityNotFound
+		}
+
+		current := providers[i]
+		providers[i] = provider
+		if err := s.save(ctx, providers, provider.Name, domain.ActionUpdate, current, provider); err != nil {
+			return err
+		}
+
+		return s.load(ctx, provider)
+	})
+}
+
+func (s *Service) SetEnabled(ctx context.Context, name string, enabled bool) (domain.SSOProviderSettings, error) {
+	provider, err := s.Get(ctx, name)
+	if err != nil {
+		return domain.SSOProviderSettings{}, err
+	}
+
+	provider.Enabled = enabled
+	if err := s.Update(ctx, provider); err != nil {
+		return domain.SSOProviderSettings{}, err
+	}
+
+	return provider, nil
+}
+
+func (s *Service) Delete(ctx context.Context, name string) error {
+	return s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		providers, err := s.List(ctx)
+		if err != nil {
+			return err
+		}
+
+		i := slices.IndexFunc(providers, func(p domain.SSOProviderSettings) bool { return p.Name == name })
+		if i < 0 {
+			return domain.ErrEntityNotFound
+		}
+
+		current := providers[i]
+		providers = slices.Delete(providers, i, i+1)
+		if err := s.save(ctx, providers, name, domain.ActionDelete, current, nil); err != nil {
+			return err
+		}
+
+		s.loader.Unload(name)
+
+		return nil
+	})
+}
+
+func (s *Service) TestMetadata(ctx context.Context, settings domain.SAMLSettings) (string, error) {
+	if !isAbsoluteURL(settings.IDPMetadataURL) {
+		return "", fmt.Errorf("%w: idp_metadata_url must be an absolute URL", ErrInvalidProvider)
+	}
+
+	return s.loader.FetchMetadata(ctx, settings)
+}
+
+// LoadAll registers the saved providers. A provider that cannot be loaded is skipped.
+func (s *Service) LoadAll(ctx context.Context) error {
+	providers, err := s.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	general, err := s.settings.GetGeneralSettings(ctx)
+	if err != nil {
+		return fmt.Errorf("get general settings: %w", err)
+	}
+
+	for i := range providers {
+		if err := s.loader.Load(providers[i], general.FrontendURL); err != nil {
+			slog.ErrorContext(ctx, "Failed to load SSO provider", "provider", providers[i].Name, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// save stores the providers and records the change of one of them.
+func (s *Service) save(
+	ctx context.Context,
+	providers []domain.SSOProviderSettings,
+	name, action string,
+	current, updated any,
+) error {
+	err := s.settingsRepo.SetByName(ctx, providersSetting, providers, "SAML providers managed at runtime")
+	if err != nil {
+		return fmt.Errorf("save SSO providers: %w", err)
+	}
+
+	changes, err := auditlog.Diff(current, updated)
+	if err != nil {
+		return fmt.Errorf("diff SSO provider: %w", err)
+	}
+
+	err = auditlog.WriteChangeLog(ctx, db.TxFromContext(ctx), domain.EntitySSOProvider, name, action, 0, changes)
+	if err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+
+	return nil
+}
+
+// load registers the saved provider, it is the last step of the transaction
+// so a provider that cannot be loaded is not saved either.
+func (s *Service) load(ctx context.Context, provider domain.SSOProviderSettings) error {
+	general, err := s.settings.GetGeneralSettings(ctx)
+	if err != nil {
+		return fmt.Errorf("get general settings: %w", err)
+	}
+
+	if err := s.loader.Load(provider, general.FrontendURL); err != nil {
+		return fmt.Errorf("%w: %w", ErrProviderNotLoaded, err)
+	}
+
+	return nil
+}
+
+func (s *Service) validate(provider domain.SSOProviderSettings) error {
+	if !providerNameRe.MatchString(provider.Name) {
+		return fmt.Errorf("%w: name must match %s", ErrInvalidProvider, providerNameRe)
+	}
+
+	if slices.Contains(s.reservedNames, provider.Name) {
+		return fmt.Errorf("%w: provider %q is configured in the environment", ErrInvalidProvider, provider.Name)
+	}
+
+	for _, mapping := range provider.GroupMappings {
+		if mapping.Group == "" || mapping.ProjectID <= 0 || mapping.RoleKey == "" {
+			return fmt.Errorf("%w: invalid group mapping %+v", ErrInvalidProvider, mapping)
+		}
+	}
+
+	if !provider.Enabled {
+		return nil
+	}
+
+	if !isAbsoluteURL(provider.IDPMetadataURL) {
+		return fmt.Errorf("%w: idp_metadata_url must be an absolute URL", ErrInvalidProvider)
+	}
+
+	if provider.CertificatePath == "" || provider.PrivateKeyPath == "" {
+		return fmt.Errorf("%w: certificate_path and private_key_path are required", ErrInvalidProvider)
+	}
+
+	return nil
+}
+
+func isAbsoluteURL(value string) bool {
+	u, err := url.Parse(value)
+
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
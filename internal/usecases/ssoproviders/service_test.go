@@ -0,0 +1,116 @@
+package ssoproviders
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type fakeSettingsRepo struct {
+	contract.SettingRepository
+	values map[string]json.RawMessage
+}
+
+func (r *fakeSettingsRepo) GetByName(_ context.Context, name string) (*domain.Setting, error) {
+	value, ok := r.values[name]
+	if !ok {
+		return nil, domain.ErrEntityNotFound
+	}
+
+	return &domain.Setting{Name: name, Value: value}, nil
+}
+
+type fakeRuntimeSettings struct {
+	contract.RuntimeSettingsReader
+}
+
+func (fakeRuntimeSettings) GetGeneralSettings(context.Context) (domain.GeneralSettings, error) {
+	return domain.GeneralSettings{FrontendURL: "https://floxy.example.com"}, nil
+}
+
+// fakeLoader records the loaded providers and fails for the names in failing.
+type fakeLoader struct {
+	contract.SAMLProviderLoader
+	loaded  []string
+	failing string
+}
+
+func (l *fakeLoader) Load(provider domain.SSOProviderSettings, publicRootURL string) error {
+	if provider.Name == l.failing {
+		return errors.New("metadata unavailable")
+	}
+
+	l.loaded = append(l.loaded, provider.Name+" "+publicRootURL)
+
+	return nil
+}
+
+func TestService_Validate(t *testing.T) {
+	ctx := context.Background()
+	srv := New(&fakeSettingsRepo{values: map[string]json.RawMessage{}}, nil, fakeRuntimeSettings{}, &fakeLoader{},
+		&Config{ReservedNames: []string{"ad_saml"}})
+
+	enabled := domain.SSOProviderSettings{
+		Name:            "okta",
+		SAMLSettings:    domain.SAMLSettings{Enabled: true, IDPMetadataURL: "https://idp.example.com/metadata"},
+		CertificatePath: "/certs/okta.crt",
+		PrivateKeyPath:  "/certs/okta.key",
+	}
+
+	for name, provider := range map[string]domain.SSOProviderSettings{
+		"bad name":      {Name: "Okta SSO"},
+		"reserved name": {Name: "ad_saml"},
+		"relative url": func() domain.SSOProviderSettings {
+			p := enabled
+			p.IDPMetadataURL = "idp/metadata"
+
+			return p
+		}(),
+		"no key pair": func() domain.SSOProviderSettings {
+			p := enabled
+			p.PrivateKeyPath = ""
+
+			return p
+		}(),
+		"bad mapping": {
+			Name:         "okta",
+			SAMLSettings: domain.SAMLSettings{GroupMappings: []domain.SAMLGroupMapping{{Group: "admins"}}},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.ErrorIs(t, srv.Create(ctx, provider), ErrInvalidProvider)
+		})
+	}
+
+	require.NoError(t, srv.validate(enabled))
+	require.NoError(t, srv.validate(domain.SSOProviderSettings{Name: "draft"}))
+}
+
+func TestService_LoadAll(t *testing.T) {
+	ctx := context.Background()
+
+	value, err := json.Marshal([]domain.SSOProviderSettings{{Name: "okta"}, {Name: "broken"}, {Name: "azure"}})
+	require.NoError(t, err)
+
+	repo := &fakeSettingsRepo{values: map[string]json.RawMessage{providersSetting: value}}
+	loader := &fakeLoader{failing: "broken"}
+	srv := New(repo, nil, fakeRuntimeSettings{}, loader, &Config{})
+
+	// A provider that fails to load does not prevent the others
+	require.NoError(t, srv.LoadAll(ctx))
+	assert.Equal(t, []string{"okta https://floxy.example.com", "azure https://floxy.example.com"}, loader.loaded)
+
+	provider, err := srv.Get(ctx, "azure")
+	require.NoError(t, err)
+	assert.Equal(t, "azure", provider.Name)
+
+	_, err = srv.Get(ctx, "missing")
+	require.ErrorIs(t, err, domain.ErrEntityNotFound)
+}
@@ -0,0 +1,105 @@
+package steplogs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.StepLogsUseCase = (*Service)(nil)
+
+// MaxLogSize bounds the log of a single step.
+const MaxLogSize = 16 << 20
+
+type Service struct {
+	tx            db.TxManager
+	stepLogsRepo  contract.StepLogsRepository
+	workflowsRepo contract.WorkflowsRepository
+}
+
+func New(
+	tx db.TxManager,
+	stepLogsRepo contract.StepLogsRepository,
+	workflowsRepo contract.WorkflowsRepository,
+) *Service {
+	return &Service{
+		tx:            tx,
+		stepLogsRepo:  stepLogsRepo,
+		workflowsRepo: workflowsRepo,
+	}
+}
+
+func (s *Service) Append(
+	ctx context.Context,
+	tenantID domain.TenantID,
+	projectID domain.ProjectID,
+	instanceID, stepID int,
+	content []byte,
+) (int64, error) {
+	if _, err := s.workflowsRepo.GetWorkflowStep(ctx, tenantID, projectID, instanceID, stepID); err != nil {
+		return 0, err
+	}
+
+	var size int64
+	err := s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		if err := s.stepLogsRepo.Lock(ctx, stepID); err != nil {
+			return err
+		}
+
+		offset, err := s.stepLogsRepo.Size(ctx, stepID)
+		if err != nil {
+			return err
+		}
+
+		size = offset + int64(len(content))
+		if size > MaxLogSize {
+			return domain.ErrStepLogTooLarge
+		}
+
+		if len(content) == 0 {
+			return nil
+		}
+
+		return s.stepLogsRepo.Insert(ctx, stepID, offset, content)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("append step log: %w", err)
+	}
+
+	return size, nil
+}
+
+func (s *Service) Read(
+	ctx context.Context,
+	tenantID domain.TenantID,
+	projectID domain.ProjectID,
+	instanceID, stepID int,
+	offset, limit int64,
+	fn func([]byte) error,
+) (domain.StepLogInfo, error) {
+	step, err := s.workflowsRepo.GetWorkflowStep(ctx, tenantID, projectID, instanceID, stepID)
+	if err != nil {
+		return domain.StepLogInfo{}, err
+	}
+
+	info := domain.StepLogInfo{
+		StepID:     step.ID,
+		StepStatus: step.Status,
+	}
+
+	info.Size, err = s.stepLogsRepo.Size(ctx, stepID)
+	if err != nil {
+		return domain.StepLogInfo{}, err
+	}
+
+	if offset < info.Size && limit > 0 {
+		if err := s.stepLogsRepo.Read(ctx, stepID, offset, limit, fn); err != nil {
+			return domain.StepLogInfo{}, err
+		}
+	}
+
+	return info, nil
+}
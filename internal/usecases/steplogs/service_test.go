@@ -0,0 +1,102 @@
+package steplogs
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type fakeTx struct{}
+
+func (fakeTx) ReadCommitted(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (fakeTx) RepeatableRead(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type fakeWorkflowsRepo struct {
+	contract.WorkflowsRepository
+	step domain.WorkflowStep
+}
+
+func (r *fakeWorkflowsRepo) GetWorkflowStep(
+	_ context.Context,
+	_ domain.TenantID,
+	_ domain.ProjectID,
+	instanceID, stepID int,
+) (domain.WorkflowStep, error) {
+	if instanceID != r.step.InstanceID || stepID != r.step.ID {
+		return domain.WorkflowStep{}, domain.ErrEntityNotFound
+	}
+
+	return r.step, nil
+}
+
+// fakeStepLogsRepo keeps the log of a single step.
+type fakeStepLogsRepo struct {
+	log []byte
+}
+
+func (r *fakeStepLogsRepo) Lock(context.Context, int) error { return nil }
+
+func (r *fakeStepLogsRepo) Size(context.Context, int) (int64, error) { return int64(len(r.log)), nil }
+
+func (r *fakeStepLogsRepo) Insert(_ context.Context, _ int, offset int64, content []byte) error {
+	if offset != int64(len(r.log)) {
+		panic("unexpected offset")
+	}
+	r.log = append(r.log, content...)
+
+	return nil
+}
+
+func (r *fakeStepLogsRepo) Read(_ context.Context, _ int, offset, limit int64, fn func([]byte) error) error {
+	return fn(r.log[offset:min(offset+limit, int64(len(r.log)))])
+}
+
+func TestService_AppendAndRead(t *testing.T) {
+	ctx := context.Background()
+	logs := &fakeStepLogsRepo{}
+	srv := New(fakeTx{}, logs, &fakeWorkflowsRepo{step: domain.WorkflowStep{ID: 7, InstanceID: 3, Status: "running"}})
+
+	size, err := srv.Append(ctx, 1, 2, 3, 7, []byte("hello "))
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), size)
+
+	size, err = srv.Append(ctx, 1, 2, 3, 7, []byte("world"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), size)
+
+	_, err = srv.Append(ctx, 1, 2, 3, 8, []byte("x"))
+	assert.ErrorIs(t, err, domain.ErrEntityNotFound)
+
+	_, err = srv.Append(ctx, 1, 2, 3, 7, make([]byte, MaxLogSize))
+	assert.ErrorIs(t, err, domain.ErrStepLogTooLarge)
+
+	var buf bytes.Buffer
+	info, err := srv.Read(ctx, 1, 2, 3, 7, 6, 3, func(chunk []byte) error {
+		buf.Write(chunk)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "wor", buf.String())
+	assert.Equal(t, domain.StepLogInfo{StepID: 7, StepStatus: "running", Size: 11}, info)
+
+	// Reading past the end returns the size only
+	buf.Reset()
+	info, err = srv.Read(ctx, 1, 2, 3, 7, 11, 10, func(chunk []byte) error {
+		buf.Write(chunk)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Empty(t, buf.String())
+	assert.Equal(t, int64(11), info.Size)
+}
@@ -0,0 +1,121 @@
+package userpreferences
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.UserPreferencesUseCase = (*Service)(nil)
+
+var ErrInvalidPreferences = errors.New("invalid user preferences")
+
+// localeRe matches BCP 47 language tags such as "en", "de-AT" or "zh-Hant-TW".
+var localeRe = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$`)
+
+// alertEvents are the events users receive email alerts about.
+var alertEvents = []domain.LifecycleEventType{
+	domain.EventInstanceFailed,
+	domain.EventLongRunningInstance,
+}
+
+type Service struct {
+	tx              db.TxManager
+	preferencesRepo contract.UserPreferencesRepository
+	permissionsSrv  contract.PermissionsService
+}
+
+func New(
+	tx db.TxManager,
+	preferencesRepo contract.UserPreferencesRepository,
+	permissionsSrv contract.PermissionsService,
+) *Service {
+	return &Service{
+		tx:              tx,
+		preferencesRepo: preferencesRepo,
+		permissionsSrv:  permissionsSrv,
+	}
+}
+
+// Get returns the user preferences, the defaults if they were never saved.
+func (s *Service) Get(ctx context.Context, userID domain.UserID) (domain.UserPreferences, error) {
+	preferences, err := s.preferencesRepo.Get(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			return domain.DefaultUserPreferences(userID), nil
+		}
+
+		return domain.UserPreferences{}, fmt.Errorf("get user preferences: %w", err)
+	}
+
+	return preferences, nil
+}
+
+// Update saves the preferences of the current user, the ones left empty get their defaults.
+func (s *Service) Update(ctx context.Context, preferences domain.UserPreferences) (domain.UserPreferences, error) {
+	defaults := domain.DefaultUserPreferences(preferences.UserID)
+	if preferences.Locale == "" {
+		preferences.Locale = defaults.Locale
+	}
+
+	if preferences.ItemsPerPage == 0 {
+		preferences.ItemsPerPage = defaults.ItemsPerPage
+	}
+
+	if err := validate(preferences); err != nil {
+		return domain.UserPreferences{}, err
+	}
+
+	if preferences.DefaultProjectID != nil {
+		if err := s.permissionsSrv.CanViewProject(ctx, *preferences.DefaultProjectID); err != nil {
+			if errors.Is(err, domain.ErrPermissionDenied) || errors.Is(err, domain.ErrEntityNotFound) {
+				return domain.UserPreferences{}, fmt.Errorf("%w: default project %d is not available",
+					ErrInvalidPreferences, *preferences.DefaultProjectID)
+			}
+
+			return domain.UserPreferences{}, fmt.Errorf("check default project: %w", err)
+		}
+	}
+
+	var saved domain.UserPreferences
+	err := s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		if err := s.preferencesRepo.Save(ctx, preferences); err != nil {
+			return err
+		}
+
+		var err error
+		saved, err = s.preferencesRepo.Get(ctx, preferences.UserID)
+
+		return err
+	})
+	if err != nil {
+		return domain.UserPreferences{}, fmt.Errorf("update user preferences: %w", err)
+	}
+
+	return saved, nil
+}
+
+func validate(preferences domain.UserPreferences) error {
+	if !localeRe.MatchString(preferences.Locale) {
+		return fmt.Errorf("%w: invalid locale %q", ErrInvalidPreferences, preferences.Locale)
+	}
+
+	if preferences.ItemsPerPage < 1 || preferences.ItemsPerPage > domain.MaxUserItemsPerPage {
+		return fmt.Errorf("%w: items_per_page must be between 1 and %d",
+			ErrInvalidPreferences, domain.MaxUserItemsPerPage)
+	}
+
+	for _, optOut := range preferences.NotificationOptOuts {
+		if !slices.Contains(alertEvents, optOut) {
+			return fmt.Errorf("%w: unsupported notification opt-out %q", ErrInvalidPreferences, optOut)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package userpreferences
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type fakeTx struct{}
+
+func (fakeTx) ReadCommitted(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (fakeTx) RepeatableRead(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type fakePreferencesRepo struct {
+	contract.UserPreferencesRepository
+	saved map[domain.UserID]domain.UserPreferences
+}
+
+func (r *fakePreferencesRepo) Get(_ context.Context, userID domain.UserID) (domain.UserPreferences, error) {
+	preferences, ok := r.saved[userID]
+	if !ok {
+		return domain.UserPreferences{}, domain.ErrEntityNotFound
+	}
+
+	return preferences, nil
+}
+
+func (r *fakePreferencesRepo) Save(_ context.Context, preferences domain.UserPreferences) error {
+	r.saved[preferences.UserID] = preferences
+
+	return nil
+}
+
+// fakePermissions lets the user view project 1 only.
+type fakePermissions struct {
+	contract.PermissionsService
+}
+
+func (fakePermissions) CanViewProject(_ context.Context, projectID domain.ProjectID) error {
+	if projectID != 1 {
+		return domain.ErrPermissionDenied
+	}
+
+	return nil
+}
+
+func TestService_Update(t *testing.T) {
+	ctx := context.Background()
+	srv := New(fakeTx{}, &fakePreferencesRepo{saved: map[domain.UserID]domain.UserPreferences{}}, fakePermissions{})
+
+	preferences, err := srv.Get(ctx, 5)
+	require.NoError(t, err)
+	assert.Equal(t, domain.DefaultUserPreferences(5), preferences)
+
+	projectID := domain.ProjectID(1)
+	preferences, err = srv.Update(ctx, domain.UserPreferences{
+		UserID:              5,
+		DefaultProjectID:    &projectID,
+		Locale:              "de-AT",
+		NotificationOptOuts: []domain.LifecycleEventType{domain.EventLongRunningInstance},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "de-AT", preferences.Locale)
+	assert.Equal(t, domain.DefaultUserItemsPerPage, preferences.ItemsPerPage)
+	assert.True(t, preferences.OptedOut(domain.EventLongRunningInstance))
+
+	otherProjectID := domain.ProjectID(2)
+	invalid := []domain.UserPreferences{
+		{UserID: 5, Locale: "not a locale"},
+		{UserID: 5, ItemsPerPage: -1},
+		{UserID: 5, NotificationOptOuts: []domain.LifecycleEventType{domain.EventDLQItemCreated}},
+		{UserID: 5, DefaultProjectID: &otherProjectID},
+	}
+	for _, preferences := range invalid {
+		_, err := srv.Update(ctx, preferences)
+		require.ErrorIs(t, err, ErrInvalidPreferences)
+	}
+}
@@ -3,6 +3,7 @@ package users
 import (
 	"context"
 	"crypto/rand"
+	"crypto/subtle"
 	"encoding/base64"
 	"errors"
 	"fmt"
@@ -12,6 +13,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/go-webauthn/webauthn/webauthn"
 	"github.com/google/uuid"
 	"github.com/pquerna/otp/totp"
 	"github.com/skip2/go-qrcode"
@@ -33,11 +35,19 @@ type twoFACodeEntry struct {
 	ExpiresAt time.Time
 }
 
+// max2FASessionAttempts is the number of codes that may be tried with one 2FA session.
+const max2FASessionAttempts = 3
+
 // In-memory store for 2FA session IDs.
 type twoFASessionEntry struct {
 	UserID    domain.UserID
 	Username  string
+	Methods   []domain.TwoFAMethod
 	CreatedAt time.Time
+	// Attempts counts the codes tried with the session.
+	Attempts int
+	// WebAuthn is set once a security key assertion has been started for the session.
+	WebAuthn *webauthn.SessionData
 }
 
 var twoFASessionStore = struct {
@@ -79,7 +89,8 @@ func validate2FACode(userID domain.UserID, code, action string) bool {
 	defer twoFACodeStore.Unlock()
 
 	entry, ok := twoFACodeStore.codes[userID]
-	if !ok || entry.Action != action || entry.Code != code || time.Now().After(entry.ExpiresAt) {
+	if !ok || entry.Action != action || time.Now().After(entry.ExpiresAt) ||
+		subtle.ConstantTimeCompare([]byte(entry.Code), []byte(code)) != 1 {
 		return false
 	}
 
@@ -94,6 +105,10 @@ func (s *UsersService) Setup2FA(ctx context.Context, userID domain.UserID) (secr
 		return "", "", "", fmt.Errorf("get user: %w", err)
 	}
 
+	if err := requireVerifiedEmail(&user); err != nil {
+		return "", "", "", err
+	}
+
 	key, err := totp.Generate(totp.GenerateOpts{
 		Issuer:      issuerName,
 		AccountName: user.Email,
@@ -129,50 +144,51 @@ func (s *UsersService) Setup2FA(ctx context.Context, userID domain.UserID) (secr
 }
 
 // Confirm2FA enables 2FA for the user after validating the provided TOTP code.
-func (s *UsersService) Confirm2FA(ctx context.Context, userID domain.UserID, code string) error {
+// It returns a new set of one-time recovery codes; only their hashes are stored.
+func (s *UsersService) Confirm2FA(ctx context.Context, userID domain.UserID, code string) ([]string, error) {
 	if s.twoFARateLimiter.IsBlocked(userID) {
-		return domain.ErrTooMany2FAAttempts
+		return nil, domain.ErrTooMany2FAAttempts
 	}
 
 	user, err := s.usersRepo.GetByID(ctx, userID)
 	if err != nil {
-		return fmt.Errorf("get user: %w", err)
+		return nil, fmt.Errorf("get user: %w", err)
 	}
 
 	if user.TwoFASecret == "" {
-		return errors.New("2FA secret not set")
+		return nil, errors.New("2FA secret not set")
 	}
 
 	encKey := []byte(s.tokenizer.SecretKey())
 
 	encSecret, err := base64.StdEncoding.DecodeString(user.TwoFASecret)
 	if err != nil {
-		return fmt.Errorf("decode secret: %w", err)
+		return nil, fmt.Errorf("decode secret: %w", err)
 	}
 
 	plainSecret, err := crypt.DecryptAESGCM(encSecret, encKey)
 	if err != nil {
-		return fmt.Errorf("decrypt secret: %w", err)
+		return nil, fmt.Errorf("decrypt secret: %w", err)
 	}
 
 	valid := totp.Validate(code, string(plainSecret))
 	if !valid {
 		_, blocked := s.twoFARateLimiter.Inc(userID)
 		if blocked {
-			return domain.ErrTooMany2FAAttempts
+			return nil, domain.ErrTooMany2FAAttempts
 		}
 
-		return domain.ErrInvalid2FACode
+		return nil, domain.ErrInvalid2FACode
 	}
 
 	s.twoFARateLimiter.Reset(userID)
 
 	now := time.Now().UTC()
 	if err := s.usersRepo.Update2FA(ctx, userID, true, user.TwoFASecret, &now); err != nil {
-		return fmt.Errorf("update user: %w", err)
+		return nil, fmt.Errorf("update user: %w", err)
 	}
 
-	return nil
+	return s.issueRecoveryCodes(ctx, userID)
 }
 
 // Send2FACode Call this to initiate 2FA disable/reset: generates and sends code.
@@ -198,6 +214,10 @@ func (s *UsersService) Disable2FA(ctx context.Context, userID domain.UserID, ema
 		return fmt.Errorf("update user: %w", err)
 	}
 
+	if err := s.recoveryCodesRepo.DeleteAll(ctx, userID); err != nil {
+		return fmt.Errorf("delete recovery codes: %w", err)
+	}
+
 	return nil
 }
 
@@ -251,18 +271,18 @@ func (s *UsersService) Verify2FA(
 	ctx context.Context,
 	code, sessionID string,
 ) (accessToken, refreshToken string, expiresIn int, err error) {
-	session, ok := get2FASession(sessionID)
+	session, ok := take2FAAttempt(sessionID)
 	if !ok {
 		return "", "", 0, domain.ErrInvalidToken
 	}
 
 	userID := session.UserID
 	if s.twoFARateLimiter.IsBlocked(userID) {
+		delete2FASession(sessionID)
+
 		return "", "", 0, domain.ErrTooMany2FAAttempts
 	}
 
-	delete2FASession(sessionID)
-
 	user, err := s.usersRepo.GetByID(ctx, session.UserID)
 	if err != nil {
 		return "", "", 0, fmt.Errorf("get user: %w", err)
@@ -285,6 +305,14 @@ func (s *UsersService) Verify2FA(
 	}
 
 	valid := totp.Validate(code, string(plainSecret))
+	if !valid {
+		// a recovery code replaces the TOTP code when the authenticator is lost
+		valid, err = s.redeemRecoveryCode(ctx, userID, code)
+		if err != nil {
+			return "", "", 0, fmt.Errorf("redeem recovery code: %w", err)
+		}
+	}
+
 	if !valid {
 		_, blocked := s.twoFARateLimiter.Inc(userID)
 		if blocked {
@@ -294,16 +322,12 @@ func (s *UsersService) Verify2FA(
 		return "", "", 0, domain.ErrInvalid2FACode
 	}
 
+	delete2FASession(sessionID)
 	s.twoFARateLimiter.Reset(userID)
 
-	accessToken, err = s.tokenizer.AccessToken(&user)
-	if err != nil {
-		return "", "", 0, fmt.Errorf("generate access token: %w", err)
-	}
-
-	refreshToken, err = s.tokenizer.RefreshToken(&user)
+	accessToken, refreshToken, err = s.issueTokens(ctx, &user)
 	if err != nil {
-		return "", "", 0, fmt.Errorf("generate refresh token: %w", err)
+		return "", "", 0, err
 	}
 
 	expiresIn = int(s.tokenizer.AccessTokenTTL().Seconds())
@@ -367,18 +391,24 @@ func (s *UsersService) InitiateTOTPApproval(ctx context.Context, userID domain.U
 	}
 
 	// Create a 2FA session for approval (15 minutes TTL)
-	sessionID := generate2FASession(userID, user.Username, 15*time.Minute)
+	sessionID := generate2FASession(userID, user.Username, []domain.TwoFAMethod{domain.TwoFAMethodTOTP}, 15*time.Minute)
 
 	return sessionID, nil
 }
 
-func generate2FASession(userID domain.UserID, username string, ttl time.Duration) string {
+func generate2FASession(
+	userID domain.UserID,
+	username string,
+	methods []domain.TwoFAMethod,
+	ttl time.Duration,
+) string {
 	sessionID := uuid.NewString()
 
 	twoFASessionStore.Lock()
 	twoFASessionStore.sessions[sessionID] = twoFASessionEntry{
 		UserID:    userID,
 		Username:  username,
+		Methods:   methods,
 		CreatedAt: time.Now(),
 	}
 	twoFASessionStore.Unlock()
@@ -401,6 +431,42 @@ func get2FASession(sessionID string) (twoFASessionEntry, bool) {
 	return entry, ok
 }
 
+// take2FAAttempt counts a code tried with the session. The session is invalidated by the
+// last allowed attempt, so concurrent requests cannot try more codes than allowed.
+func take2FAAttempt(sessionID string) (twoFASessionEntry, bool) {
+	twoFASessionStore.Lock()
+	defer twoFASessionStore.Unlock()
+
+	entry, ok := twoFASessionStore.sessions[sessionID]
+	if !ok {
+		return twoFASessionEntry{}, false
+	}
+
+	entry.Attempts++
+	if entry.Attempts >= max2FASessionAttempts {
+		delete(twoFASessionStore.sessions, sessionID)
+	} else {
+		twoFASessionStore.sessions[sessionID] = entry
+	}
+
+	return entry, true
+}
+
+func set2FASessionWebAuthn(sessionID string, data *webauthn.SessionData) bool {
+	twoFASessionStore.Lock()
+	defer twoFASessionStore.Unlock()
+
+	entry, ok := twoFASessionStore.sessions[sessionID]
+	if !ok {
+		return false
+	}
+
+	entry.WebAuthn = data
+	twoFASessionStore.sessions[sessionID] = entry
+
+	return true
+}
+
 func delete2FASession(sessionID string) {
 	twoFASessionStore.Lock()
 	delete(twoFASessionStore.sessions, sessionID)
@@ -0,0 +1,34 @@
+package users
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+func TestTake2FAAttempt(t *testing.T) {
+	sessionID := generate2FASession(7, "jdoe", []domain.TwoFAMethod{domain.TwoFAMethodTOTP}, time.Minute)
+
+	for i := 1; i <= max2FASessionAttempts; i++ {
+		entry, ok := take2FAAttempt(sessionID)
+		require.True(t, ok)
+		assert.Equal(t, domain.UserID(7), entry.UserID)
+		assert.Equal(t, i, entry.Attempts)
+	}
+
+	_, ok := take2FAAttempt(sessionID)
+	assert.False(t, ok)
+}
+
+func TestValidate2FACode(t *testing.T) {
+	store2FACode(9, "123456", "disable", time.Minute)
+
+	assert.False(t, validate2FACode(9, "123457", "disable"))
+	assert.False(t, validate2FACode(9, "123456", "reset"))
+	assert.True(t, validate2FACode(9, "123456", "disable"))
+	assert.False(t, validate2FACode(9, "123456", "disable"))
+}
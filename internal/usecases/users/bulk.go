@@ -0,0 +1,105 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+// BulkUpdate applies the action to the users in a single transaction.
+// Users that cannot be changed (not found, superusers on delete or for user managers, the current user)
+// are reported per item and skipped; any other error rolls back the whole batch.
+func (s *UsersService) BulkUpdate(
+	ctx context.Context,
+	action domain.BulkUserAction,
+	ids []domain.UserID,
+) ([]domain.BulkUserResult, error) {
+	currentUser, err := s.currentUserManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ids = uniqueUserIDs(ids)
+
+	var results []domain.BulkUserResult
+
+	err = s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+		results = make([]domain.BulkUserResult, 0, len(ids))
+
+		for _, id := range ids {
+			err := s.applyBulkUserAction(ctx, &currentUser, action, id)
+			if err != nil && !isBulkItemError(err) {
+				return fmt.Errorf("user %d: %w", id, err)
+			}
+
+			results = append(results, domain.BulkUserResult{UserID: id, Err: err})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (s *UsersService) applyBulkUserAction(
+	ctx context.Context,
+	currentUser *domain.User,
+	action domain.BulkUserAction,
+	id domain.UserID,
+) error {
+	if id == currentUser.ID {
+		return domain.ErrPermissionDenied
+	}
+
+	user, err := s.usersRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !canManageUser(currentUser, &user) {
+		return domain.ErrPermissionDenied
+	}
+
+	switch action {
+	case domain.BulkUserActionActivate, domain.BulkUserActionDeactivate:
+		user.IsActive = action == domain.BulkUserActionActivate
+		user.UpdatedAt = time.Now()
+
+		return s.usersRepo.Update(ctx, &user)
+	case domain.BulkUserActionDelete:
+		if user.IsSuperuser {
+			return domain.ErrPermissionDenied
+		}
+
+		return s.usersRepo.Delete(ctx, id)
+	default:
+		return fmt.Errorf("unknown bulk action %q", action)
+	}
+}
+
+// isBulkItemError reports whether the error concerns only one user and must not abort the batch.
+func isBulkItemError(err error) bool {
+	return errors.Is(err, domain.ErrEntityNotFound) || errors.Is(err, domain.ErrPermissionDenied)
+}
+
+func uniqueUserIDs(ids []domain.UserID) []domain.UserID {
+	seen := make(map[domain.UserID]struct{}, len(ids))
+	unique := make([]domain.UserID, 0, len(ids))
+
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+
+		seen[id] = struct{}{}
+		unique = append(unique, id)
+	}
+
+	return unique
+}
@@ -0,0 +1,21 @@
+package users
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+func TestUniqueUserIDs(t *testing.T) {
+	ids := uniqueUserIDs([]domain.UserID{3, 1, 3, 2, 1})
+	assert.Equal(t, []domain.UserID{3, 1, 2}, ids)
+}
+
+func TestIsBulkItemError(t *testing.T) {
+	assert.True(t, isBulkItemError(domain.ErrEntityNotFound))
+	assert.True(t, isBulkItemError(fmt.Errorf("get user: %w", domain.ErrPermissionDenied)))
+	assert.False(t, isBulkItemError(fmt.Errorf("connection reset")))
+}
@@ -0,0 +1,110 @@
+package users
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+// verificationResendInterval limits how often a user can request another verification email.
+const verificationResendInterval = time.Minute
+
+// In-memory store of the last verification email sent to a user.
+var verificationEmailStore = struct {
+	sync.Mutex
+	sentAt map[domain.UserID]time.Time
+}{sentAt: make(map[domain.UserID]time.Time)}
+
+func allowVerificationEmail(userID domain.UserID, now time.Time) bool {
+	verificationEmailStore.Lock()
+	defer verificationEmailStore.Unlock()
+
+	if sentAt, ok := verificationEmailStore.sentAt[userID]; ok && now.Sub(sentAt) < verificationResendInterval {
+		return false
+	}
+
+	verificationEmailStore.sentAt[userID] = now
+
+	return true
+}
+
+// requireVerifiedEmail guards actions that are not allowed until the user verifies the email address.
+func requireVerifiedEmail(user *domain.User) error {
+	if !user.EmailVerified() {
+		return domain.ErrEmailNotVerified
+	}
+
+	return nil
+}
+
+func (s *UsersService) sendVerificationEmail(ctx context.Context, user *domain.User) error {
+	token, ttl, err := s.tokenizer.VerifyEmailToken(user)
+	if err != nil {
+		return fmt.Errorf("generate verify email token: %w", err)
+	}
+
+	if err := s.emailer.SendVerifyEmail(ctx, user.Email, token, ttl); err != nil {
+		return fmt.Errorf("send verify email: %w", err)
+	}
+
+	return nil
+}
+
+// trySendVerificationEmail sends the verification email to a user who has just been created
+// or changed the email address. A failure does not fail the calling operation, the user can
+// request another email.
+func (s *UsersService) trySendVerificationEmail(ctx context.Context, user *domain.User) {
+	if user.EmailVerified() || !allowVerificationEmail(user.ID, time.Now()) {
+		return
+	}
+
+	if err := s.sendVerificationEmail(ctx, user); err != nil {
+		slog.ErrorContext(ctx, "failed to send verification email", "user_id", user.ID, "error", err)
+	}
+}
+
+// VerifyEmail marks the email address of the user as verified using the token from the verification email.
+func (s *UsersService) VerifyEmail(ctx context.Context, token string) error {
+	claims, err := s.tokenizer.VerifyToken(token, domain.TokenTypeVerifyEmail)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.usersRepo.GetByID(ctx, domain.UserID(claims.UserID))
+	if err != nil {
+		return fmt.Errorf("get user: %w", err)
+	}
+
+	// The token was sent to an address the user has changed since.
+	if claims.Email != user.Email {
+		return domain.ErrInvalidToken
+	}
+
+	if err := s.usersRepo.MarkEmailVerified(ctx, user.ID); err != nil {
+		return fmt.Errorf("mark email verified: %w", err)
+	}
+
+	return nil
+}
+
+// ResendVerificationEmail sends another verification email to the user.
+func (s *UsersService) ResendVerificationEmail(ctx context.Context, userID domain.UserID) error {
+	user, err := s.usersRepo.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("get user: %w", err)
+	}
+
+	if user.EmailVerified() {
+		return domain.ErrEmailAlreadyVerified
+	}
+
+	if !allowVerificationEmail(userID, time.Now()) {
+		return domain.ErrTooManyVerificationEmails
+	}
+
+	return s.sendVerificationEmail(ctx, &user)
+}
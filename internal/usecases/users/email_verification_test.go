@@ -0,0 +1,29 @@
+package users
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+func TestAllowVerificationEmail(t *testing.T) {
+	const userID = domain.UserID(1001)
+
+	now := time.Now()
+
+	assert.True(t, allowVerificationEmail(userID, now))
+	assert.False(t, allowVerificationEmail(userID, now.Add(30*time.Second)))
+	assert.True(t, allowVerificationEmail(userID, now.Add(verificationResendInterval)))
+}
+
+func TestRequireVerifiedEmail(t *testing.T) {
+	user := &domain.User{}
+	assert.ErrorIs(t, requireVerifiedEmail(user), domain.ErrEmailNotVerified)
+
+	verifiedAt := time.Now()
+	user.EmailVerifiedAt = &verifiedAt
+	assert.NoError(t, requireVerifiedEmail(user))
+}
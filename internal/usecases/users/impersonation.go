@@ -0,0 +1,57 @@
+package users
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/internal/repository/auditlog"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+// Impersonate issues a short-lived access token acting as the user, so superusers can reproduce
+// permission issues. The token carries the superuser in the act claim and is bound to the session
+// of the superuser, the actions taken with it are audited with both usernames.
+// Only superusers logged in with a session can impersonate, and only users that are not superusers
+// or service accounts.
+func (s *UsersService) Impersonate(ctx context.Context, userID domain.UserID) (string, int, error) {
+	sessionID := appcontext.SessionID(ctx)
+	if sessionID == "" || appcontext.Impersonator(ctx) != "" {
+		return "", 0, domain.ErrPermissionDenied
+	}
+
+	actor, err := s.usersRepo.GetByID(ctx, appcontext.UserID(ctx))
+	if err != nil {
+		return "", 0, fmt.Errorf("get current user by id: %w", err)
+	}
+
+	if !actor.IsSuperuser || actor.ID == userID {
+		return "", 0, domain.ErrPermissionDenied
+	}
+
+	user, err := s.usersRepo.GetByID(ctx, userID)
+	if err != nil {
+		return "", 0, fmt.Errorf("get user by id: %w", err)
+	}
+
+	if user.IsSuperuser || user.IsServiceAccount || !user.IsActive {
+		return "", 0, domain.ErrPermissionDenied
+	}
+
+	token, err := s.tokenizer.ImpersonationToken(&user, &actor, sessionID)
+	if err != nil {
+		return "", 0, fmt.Errorf("create impersonation token: %w", err)
+	}
+
+	err = s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+		return auditlog.WriteLog(ctx, db.TxFromContext(ctx), domain.EntityUser, strconv.Itoa(userID.Int()),
+			domain.ActionImpersonate, 0)
+	})
+	if err != nil {
+		return "", 0, fmt.Errorf("write audit log: %w", err)
+	}
+
+	return token, int(s.tokenizer.ImpersonationTokenTTL().Seconds()), nil
+}
@@ -0,0 +1,87 @@
+package users
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+// Merge folds the source account into the target one, e.g. a local account duplicated by SAML or LDAP
+// provisioning under another username. The memberships, global roles, preferences and audit log
+// attribution of the source are moved to the target, then the source is deactivated and its sessions
+// are revoked. Everything is done in a single transaction. Only superusers can merge into their own
+// account or merge a source holding global roles, so user managers cannot grant themselves roles.
+func (s *UsersService) Merge(ctx context.Context, sourceID, targetID domain.UserID) (domain.UserMergeResult, error) {
+	currentUser, err := s.currentUserManager(ctx)
+	if err != nil {
+		return domain.UserMergeResult{}, err
+	}
+
+	if sourceID == targetID || sourceID == currentUser.ID {
+		return domain.UserMergeResult{}, domain.ErrInvalidUserMerge
+	}
+
+	if targetID == currentUser.ID && !currentUser.IsSuperuser {
+		return domain.UserMergeResult{}, domain.ErrPermissionDenied
+	}
+
+	var result domain.UserMergeResult
+
+	err = s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+		source, err := s.usersRepo.GetByID(ctx, sourceID)
+		if err != nil {
+			return fmt.Errorf("get source user: %w", err)
+		}
+
+		target, err := s.usersRepo.GetByID(ctx, targetID)
+		if err != nil {
+			return fmt.Errorf("get target user: %w", err)
+		}
+
+		if !canManageUser(&currentUser, &source) || !canManageUser(&currentUser, &target) {
+			return domain.ErrPermissionDenied
+		}
+
+		if source.IsServiceAccount || target.IsServiceAccount || !target.IsActive {
+			return domain.ErrInvalidUserMerge
+		}
+
+		if !currentUser.IsSuperuser {
+			globalRoles, err := s.globalRoles.ListForUser(ctx, sourceID)
+			if err != nil {
+				return fmt.Errorf("list global roles of source user: %w", err)
+			}
+
+			if len(globalRoles) > 0 {
+				return domain.ErrPermissionDenied
+			}
+		}
+
+		result, err = s.usersRepo.Merge(ctx, sourceID, targetID)
+		if err != nil {
+			return fmt.Errorf("merge users: %w", err)
+		}
+
+		now := time.Now()
+
+		source.IsActive = false
+		source.UpdatedAt = now
+
+		if err := s.usersRepo.Update(ctx, &source); err != nil {
+			return fmt.Errorf("deactivate source user: %w", err)
+		}
+
+		if err := s.sessionsRepo.RevokeAll(ctx, sourceID, now); err != nil {
+			return fmt.Errorf("revoke sessions of source user: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return domain.UserMergeResult{}, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,162 @@
+package users
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type fakeTx struct{}
+
+func (fakeTx) ReadCommitted(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (fakeTx) RepeatableRead(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// fakeMergeUsersRepo keeps the users in memory and counts the merges.
+type fakeMergeUsersRepo struct {
+	contract.UsersRepository
+
+	users  map[domain.UserID]domain.User
+	merged int
+}
+
+func (f *fakeMergeUsersRepo) GetByID(_ context.Context, id domain.UserID) (domain.User, error) {
+	user, ok := f.users[id]
+	if !ok {
+		return domain.User{}, domain.ErrEntityNotFound
+	}
+
+	return user, nil
+}
+
+func (f *fakeMergeUsersRepo) Merge(_ context.Context, sourceID, targetID domain.UserID) (domain.UserMergeResult, error) {
+	f.merged++
+
+	return domain.UserMergeResult{SourceID: sourceID, TargetID: targetID}, nil
+}
+
+func (f *fakeMergeUsersRepo) Update(_ context.Context, user *domain.User) error {
+	f.users[user.ID] = *user
+
+	return nil
+}
+
+type fakeMergeSessionsRepo struct {
+	contract.SessionsRepository
+
+	revoked []domain.UserID
+}
+
+func (f *fakeMergeSessionsRepo) RevokeAll(_ context.Context, userID domain.UserID, _ time.Time) error {
+	f.revoked = append(f.revoked, userID)
+
+	return nil
+}
+
+// userManagerPermissions grants user.manage to every caller.
+type userManagerPermissions struct {
+	contract.PermissionsService
+}
+
+func (userManagerPermissions) HasGlobalPermission(context.Context, domain.PermKey) (bool, error) {
+	return true, nil
+}
+
+type fakeGlobalRolesRepo struct {
+	contract.GlobalRolesRepository
+
+	byUser map[domain.UserID][]domain.GlobalRoleAssignment
+}
+
+func (f *fakeGlobalRolesRepo) ListForUser(_ context.Context, userID domain.UserID) ([]domain.GlobalRoleAssignment, error) {
+	return f.byUser[userID], nil
+}
+
+func newMergeService(globalRoles map[domain.UserID][]domain.GlobalRoleAssignment) (
+	*UsersService,
+	*fakeMergeUsersRepo,
+	*fakeMergeSessionsRepo,
+) {
+	usersRepo := &fakeMergeUsersRepo{users: map[domain.UserID]domain.User{
+		1: {ID: 1, Username: "admin", IsActive: true, IsSuperuser: true},
+		2: {ID: 2, Username: "manager", IsActive: true},
+		3: {ID: 3, Username: "jdoe", IsActive: true},
+		4: {ID: 4, Username: "john.doe", IsActive: true, IsExternal: true},
+	}}
+	sessionsRepo := &fakeMergeSessionsRepo{}
+
+	return &UsersService{
+		usersRepo:    usersRepo,
+		sessionsRepo: sessionsRepo,
+		txManager:    fakeTx{},
+		permissions:  userManagerPermissions{},
+		globalRoles:  &fakeGlobalRolesRepo{byUser: globalRoles},
+	}, usersRepo, sessionsRepo
+}
+
+func asCurrentUser(id domain.UserID) context.Context {
+	return appcontext.WithUserID(context.Background(), id)
+}
+
+func TestUsersService_Merge(t *testing.T) {
+	svc, usersRepo, sessionsRepo := newMergeService(nil)
+
+	result, err := svc.Merge(asCurrentUser(2), 3, 4)
+	require.NoError(t, err)
+	assert.Equal(t, domain.UserMergeResult{SourceID: 3, TargetID: 4}, result)
+	assert.False(t, usersRepo.users[3].IsActive)
+	assert.Equal(t, []domain.UserID{3}, sessionsRepo.revoked)
+}
+
+func TestUsersService_Merge_IntoCurrentUser(t *testing.T) {
+	svc, usersRepo, _ := newMergeService(nil)
+
+	_, err := svc.Merge(asCurrentUser(2), 3, 2)
+	require.ErrorIs(t, err, domain.ErrPermissionDenied)
+	assert.Zero(t, usersRepo.merged)
+
+	_, err = svc.Merge(asCurrentUser(1), 3, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, usersRepo.merged)
+}
+
+func TestUsersService_Merge_SourceWithGlobalRoles(t *testing.T) {
+	svc, usersRepo, _ := newMergeService(map[domain.UserID][]domain.GlobalRoleAssignment{
+		3: {{UserID: 3, RoleKey: "auditor"}},
+	})
+
+	_, err := svc.Merge(asCurrentUser(2), 3, 4)
+	require.ErrorIs(t, err, domain.ErrPermissionDenied)
+	assert.Zero(t, usersRepo.merged)
+	assert.True(t, usersRepo.users[3].IsActive)
+
+	_, err = svc.Merge(asCurrentUser(1), 3, 4)
+	require.NoError(t, err)
+	assert.Equal(t, 1, usersRepo.merged)
+}
+
+func TestUsersService_Merge_Invalid(t *testing.T) {
+	svc, usersRepo, _ := newMergeService(nil)
+
+	_, err := svc.Merge(asCurrentUser(2), 3, 3)
+	require.ErrorIs(t, err, domain.ErrInvalidUserMerge)
+
+	_, err = svc.Merge(asCurrentUser(2), 2, 4)
+	require.ErrorIs(t, err, domain.ErrInvalidUserMerge)
+
+	_, err = svc.Merge(asCurrentUser(2), 1, 4)
+	require.ErrorIs(t, err, domain.ErrPermissionDenied)
+
+	assert.Zero(t, usersRepo.merged)
+}
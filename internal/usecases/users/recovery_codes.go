@@ -0,0 +1,118 @@
+package users
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+const (
+	recoveryCodesCount = 10
+	// recoveryCodeBytes gives 10 hex characters shown as xxxxx-xxxxx.
+	recoveryCodeBytes = 5
+)
+
+// generateRecoveryCodes returns new plain recovery codes and their hashes.
+func generateRecoveryCodes() (codes, hashes []string, err error) {
+	codes = make([]string, 0, recoveryCodesCount)
+	hashes = make([]string, 0, recoveryCodesCount)
+
+	buf := make([]byte, recoveryCodeBytes)
+	for range recoveryCodesCount {
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, fmt.Errorf("generate recovery code: %w", err)
+		}
+
+		code := hex.EncodeToString(buf)
+		codes = append(codes, code[:5]+"-"+code[5:])
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+
+	return codes, hashes, nil
+}
+
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeRecoveryCode returns the code without separators and whether it looks like a recovery code.
+// TOTP codes are 6 digits, so they are never taken for recovery codes.
+func normalizeRecoveryCode(code string) (string, bool) {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	if len(code) != recoveryCodeBytes*2 {
+		return "", false
+	}
+
+	if _, err := hex.DecodeString(code); err != nil {
+		return "", false
+	}
+
+	return code, true
+}
+
+// issueRecoveryCodes replaces the recovery codes of the user with a new set.
+func (s *UsersService) issueRecoveryCodes(ctx context.Context, userID domain.UserID) ([]string, error) {
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.recoveryCodesRepo.Replace(ctx, userID, hashes); err != nil {
+		return nil, fmt.Errorf("save recovery codes: %w", err)
+	}
+
+	return codes, nil
+}
+
+// redeemRecoveryCode uses up a recovery code; it reports false if the code is unknown or already used.
+func (s *UsersService) redeemRecoveryCode(ctx context.Context, userID domain.UserID, code string) (bool, error) {
+	normalized, ok := normalizeRecoveryCode(code)
+	if !ok {
+		return false, nil
+	}
+
+	err := s.recoveryCodesRepo.Use(ctx, userID, hashRecoveryCode(normalized))
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	remaining, err := s.recoveryCodesRepo.CountUnused(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	slog.InfoContext(ctx, "2FA recovery code used", "user_id", userID, "remaining", remaining)
+
+	return true, nil
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes after validating a current TOTP code.
+func (s *UsersService) RegenerateRecoveryCodes(
+	ctx context.Context,
+	userID domain.UserID,
+	code string,
+) ([]string, error) {
+	if err := s.VerifyTOTP(ctx, userID, code); err != nil {
+		return nil, err
+	}
+
+	return s.issueRecoveryCodes(ctx, userID)
+}
+
+// CountRecoveryCodes returns the number of unused recovery codes of the user.
+func (s *UsersService) CountRecoveryCodes(ctx context.Context, userID domain.UserID) (int, error) {
+	return s.recoveryCodesRepo.CountUnused(ctx, userID)
+}
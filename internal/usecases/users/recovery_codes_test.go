@@ -0,0 +1,35 @@
+package users
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	codes, hashes, err := generateRecoveryCodes()
+	require.NoError(t, err)
+	require.Len(t, codes, recoveryCodesCount)
+	require.Len(t, hashes, recoveryCodesCount)
+
+	for i, code := range codes {
+		assert.Regexp(t, `^[0-9a-f]{5}-[0-9a-f]{5}$`, code)
+
+		normalized, ok := normalizeRecoveryCode(code)
+		require.True(t, ok)
+		assert.Equal(t, hashes[i], hashRecoveryCode(normalized))
+	}
+}
+
+func TestNormalizeRecoveryCode(t *testing.T) {
+	code, ok := normalizeRecoveryCode(" 1A2B3-C4D5E ")
+	assert.True(t, ok)
+	assert.Equal(t, "1a2b3c4d5e", code)
+
+	_, ok = normalizeRecoveryCode("123456")
+	assert.False(t, ok)
+
+	_, ok = normalizeRecoveryCode("zzzzz-zzzzz")
+	assert.False(t, ok)
+}
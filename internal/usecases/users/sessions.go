@@ -0,0 +1,173 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	appcontext "github.com/rom8726/floxy-manager/internal/context"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+// ListSessions returns active login sessions of the user.
+func (s *UsersService) ListSessions(ctx context.Context, userID domain.UserID) ([]domain.Session, error) {
+	return s.sessionsRepo.ListActive(ctx, userID, time.Now())
+}
+
+// RevokeSession revokes a login session so its refresh token can no longer be used.
+func (s *UsersService) RevokeSession(ctx context.Context, userID domain.UserID, id domain.SessionID) error {
+	return s.sessionsRepo.Revoke(ctx, userID, id, time.Now())
+}
+
+// RevokeAllSessions revokes every login session of the user.
+func (s *UsersService) RevokeAllSessions(ctx context.Context, userID domain.UserID) error {
+	return s.sessionsRepo.RevokeAll(ctx, userID, time.Now())
+}
+
+// issueTokens starts a new login session and returns tokens bound to it.
+//
+//nolint:nonamedreturns // we need named here
+func (s *UsersService) issueTokens(
+	ctx context.Context,
+	user *domain.User,
+) (accessToken, refreshToken string, err error) {
+	if user.IsServiceAccount {
+		return "", "", domain.ErrServiceAccountLogin
+	}
+
+	ip, userAgent := appcontext.ClientInfo(ctx)
+	refreshTokenID := uuid.NewString()
+
+	sessionID, err := s.sessionsRepo.Create(ctx, domain.SessionDTO{
+		UserID:         user.ID,
+		UserAgent:      userAgent,
+		IPAddress:      ip,
+		ExpiresAt:      time.Now().Add(s.tokenizer.RefreshTokenTTL()),
+		RefreshTokenID: refreshTokenID,
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("create session: %w", err)
+	}
+
+	accessToken, err = s.tokenizer.AccessToken(user, sessionID)
+	if err != nil {
+		return "", "", fmt.Errorf("generate access token: %w", err)
+	}
+
+	refreshToken, err = s.tokenizer.RefreshToken(user, sessionID, refreshTokenID)
+	if err != nil {
+		return "", "", fmt.Errorf("generate refresh token: %w", err)
+	}
+
+	return accessToken, refreshToken, nil
+}
+
+// CheckSession returns domain.ErrInvalidToken if the session was revoked or has expired.
+func (s *UsersService) CheckSession(ctx context.Context, userID domain.UserID, id domain.SessionID) error {
+	_, err := s.activeSession(ctx, userID, id, time.Now())
+
+	return err
+}
+
+// Logout revokes the session of the refresh token, or all sessions of its user if all is set.
+// Already revoked sessions are not an error.
+func (s *UsersService) Logout(ctx context.Context, refreshToken string, all bool) error {
+	claims, err := s.tokenizer.VerifyToken(refreshToken, domain.TokenTypeRefresh)
+	if err != nil {
+		return fmt.Errorf("verify refresh token: %w", err)
+	}
+
+	return s.revokeSessions(ctx, domain.UserID(claims.UserID), claims.SessionID, all)
+}
+
+// LogoutSession revokes the given session of the user, or all of the user's sessions if all is set.
+func (s *UsersService) LogoutSession(ctx context.Context, userID domain.UserID, id domain.SessionID, all bool) error {
+	return s.revokeSessions(ctx, userID, id, all)
+}
+
+func (s *UsersService) revokeSessions(ctx context.Context, userID domain.UserID, id domain.SessionID, all bool) error {
+	if all {
+		return s.sessionsRepo.RevokeAll(ctx, userID, time.Now())
+	}
+
+	if id == "" {
+		return fmt.Errorf("%w: token has no session", domain.ErrInvalidToken)
+	}
+
+	err := s.sessionsRepo.Revoke(ctx, userID, id, time.Now())
+	if err != nil && !errors.Is(err, domain.ErrEntityNotFound) {
+		return err
+	}
+
+	return nil
+}
+
+// useSession checks that the session of a refresh token is still active, extends it and
+// makes newTokenID its only valid refresh token. A refresh token that was already rotated
+// means it leaked, so the session is revoked for both the attacker and the user.
+func (s *UsersService) useSession(ctx context.Context, claims *domain.TokenClaims, newTokenID string) error {
+	now := time.Now()
+	userID := domain.UserID(claims.UserID)
+
+	session, err := s.activeSession(ctx, userID, claims.SessionID, now)
+	if err != nil {
+		return err
+	}
+
+	// Sessions started before rotation accept their token once
+	currentTokenID := claims.ID
+	if session.RefreshTokenID == "" {
+		currentTokenID = ""
+	}
+
+	err = s.sessionsRepo.Rotate(ctx, session.ID, currentTokenID, newTokenID, now, now.Add(s.tokenizer.RefreshTokenTTL()))
+	if err == nil {
+		return nil
+	}
+
+	if !errors.Is(err, domain.ErrEntityNotFound) {
+		return fmt.Errorf("rotate session token: %w", err)
+	}
+
+	slog.WarnContext(ctx, "Refresh token reuse detected, revoking the session",
+		"user_id", userID,
+		"session_id", session.ID,
+		"token_id", claims.ID,
+	)
+
+	if err := s.sessionsRepo.RevokeReused(ctx, userID, session.ID, now); err != nil {
+		return fmt.Errorf("revoke session: %w", err)
+	}
+
+	return fmt.Errorf("%w: refresh token was already used", domain.ErrInvalidToken)
+}
+
+func (s *UsersService) activeSession(
+	ctx context.Context,
+	userID domain.UserID,
+	id domain.SessionID,
+	now time.Time,
+) (domain.Session, error) {
+	if id == "" {
+		return domain.Session{}, fmt.Errorf("%w: token has no session", domain.ErrInvalidToken)
+	}
+
+	session, err := s.sessionsRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrEntityNotFound) {
+			return domain.Session{}, fmt.Errorf("%w: session not found", domain.ErrInvalidToken)
+		}
+
+		return domain.Session{}, fmt.Errorf("get session: %w", err)
+	}
+
+	if session.UserID != userID || !session.Active(now) {
+		return domain.Session{}, fmt.Errorf("%w: session is revoked or expired", domain.ErrInvalidToken)
+	}
+
+	return session, nil
+}
@@ -0,0 +1,86 @@
+package users
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type fakeSessionsRepo struct {
+	contract.SessionsRepository
+
+	session domain.Session
+	reused  bool
+}
+
+func (f *fakeSessionsRepo) GetByID(context.Context, domain.SessionID) (domain.Session, error) {
+	return f.session, nil
+}
+
+func (f *fakeSessionsRepo) Rotate(
+	_ context.Context,
+	_ domain.SessionID,
+	currentTokenID, newTokenID string,
+	_, _ time.Time,
+) error {
+	if currentTokenID != f.session.RefreshTokenID {
+		return domain.ErrEntityNotFound
+	}
+
+	f.session.RefreshTokenID = newTokenID
+
+	return nil
+}
+
+func (f *fakeSessionsRepo) RevokeReused(_ context.Context, _ domain.UserID, _ domain.SessionID, at time.Time) error {
+	f.reused = true
+	f.session.RevokedAt = &at
+
+	return nil
+}
+
+type fakeTokenizer struct {
+	contract.Tokenizer
+}
+
+func (fakeTokenizer) RefreshTokenTTL() time.Duration {
+	return time.Hour
+}
+
+func TestUseSessionRotation(t *testing.T) {
+	repo := &fakeSessionsRepo{session: domain.Session{
+		ID:        "s1",
+		UserID:    5,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}}
+	svc := &UsersService{sessionsRepo: repo, tokenizer: fakeTokenizer{}}
+
+	claims := func(tokenID string) *domain.TokenClaims {
+		return &domain.TokenClaims{
+			RegisteredClaims: jwt.RegisteredClaims{ID: tokenID},
+			UserID:           5,
+			SessionID:        "s1",
+		}
+	}
+
+	// A session started before rotation accepts its token once
+	require.NoError(t, svc.useSession(context.Background(), claims("legacy"), "t1"))
+	assert.Equal(t, "t1", repo.session.RefreshTokenID)
+
+	require.NoError(t, svc.useSession(context.Background(), claims("t1"), "t2"))
+	assert.Equal(t, "t2", repo.session.RefreshTokenID)
+
+	err := svc.useSession(context.Background(), claims("t1"), "t3")
+	assert.ErrorIs(t, err, domain.ErrInvalidToken)
+	assert.True(t, repo.reused)
+
+	err = svc.useSession(context.Background(), claims("t2"), "t4")
+	assert.ErrorIs(t, err, domain.ErrInvalidToken)
+}
@@ -30,7 +30,7 @@ func (s *UsersService) GetSSOMetadata(_ context.Context, providerName string) ([
 }
 
 // SSOInitiate initiates the SSO login flow by generating a redirect URL to the specified provider.
-func (s *UsersService) SSOInitiate(_ context.Context, providerName string) (redirectURL string, err error) {
+func (s *UsersService) SSOInitiate(ctx context.Context, providerName string) (redirectURL string, err error) {
 	if s.ssoManager == nil {
 		return "", errors.New("SSO is not enabled")
 	}
@@ -50,7 +50,7 @@ func (s *UsersService) SSOInitiate(_ context.Context, providerName string) (redi
 		return "", fmt.Errorf("failed to generate state: %w", err)
 	}
 
-	redirectURL, err = provider.GenerateAuthURL(state)
+	redirectURL, err = provider.GenerateAuthURL(ctx, state)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate auth URL: %w", err)
 	}
@@ -90,15 +90,15 @@ func (s *UsersService) SSOCallback(
 		return "", "", 0, domain.ErrInactiveUser
 	}
 
-	// Generate tokens
-	accessToken, err = s.tokenizer.AccessToken(user)
-	if err != nil {
-		return "", "", 0, fmt.Errorf("generate access token: %w", err)
+	// A user who has never logged in was just registered by the provider
+	if user.LastLogin == nil {
+		s.trySendVerificationEmail(ctx, user)
 	}
 
-	refreshToken, err = s.tokenizer.RefreshToken(user)
+	// Generate tokens
+	accessToken, refreshToken, err = s.issueTokens(ctx, user)
 	if err != nil {
-		return "", "", 0, fmt.Errorf("generate refresh token: %w", err)
+		return "", "", 0, err
 	}
 
 	// Update last login
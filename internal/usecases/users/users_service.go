@@ -5,30 +5,51 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+
 	appcontext "github.com/rom8726/floxy-manager/internal/context"
 	"github.com/rom8726/floxy-manager/internal/contract"
 	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/db"
 	"github.com/rom8726/floxy-manager/pkg/passworder"
 )
 
 type UsersService struct {
-	usersRepo        contract.UsersRepository
-	tokenizer        contract.Tokenizer
-	emailer          contract.Emailer
-	twoFARateLimiter contract.TwoFARateLimiter
-	ssoManager       contract.SSOProviderManager
-	authProvider     AuthProvider
+	usersRepo         contract.UsersRepository
+	sessionsRepo      contract.SessionsRepository
+	tokenizer         contract.Tokenizer
+	emailer           contract.Emailer
+	twoFARateLimiter  contract.TwoFARateLimiter
+	ssoManager        contract.SSOProviderManager
+	authProvider      AuthProvider
+	webAuthn          *webauthn.WebAuthn
+	webAuthnRepo      contract.WebAuthnCredentialsRepository
+	recoveryCodesRepo contract.RecoveryCodesRepository
+	txManager         db.TxManager
+	permissions       contract.PermissionsService
+	memberships       contract.MembershipsUseCase
+	globalRoles       contract.GlobalRolesRepository
 }
 
 func New(
 	usersRepo contract.UsersRepository,
+	sessionsRepo contract.SessionsRepository,
 	tokenizer contract.Tokenizer,
 	emailer contract.Emailer,
 	twoFARateLimiter contract.TwoFARateLimiter,
 	ssoManager contract.SSOProviderManager,
 	authProviders []AuthProvider,
+	webAuthn *webauthn.WebAuthn,
+	webAuthnRepo contract.WebAuthnCredentialsRepository,
+	recoveryCodesRepo contract.RecoveryCodesRepository,
+	txManager db.TxManager,
+	permissions contract.PermissionsService,
+	memberships contract.MembershipsUseCase,
+	globalRoles contract.GlobalRolesRepository,
 ) *UsersService {
 	// Create a chain of authentication providers
 	authProvider := NewAuthProviderChain(
@@ -41,12 +62,20 @@ func New(
 	authProvider.providers = append(authProvider.providers, localAuthProvider)
 
 	return &UsersService{
-		usersRepo:        usersRepo,
-		tokenizer:        tokenizer,
-		emailer:          emailer,
-		twoFARateLimiter: twoFARateLimiter,
-		authProvider:     authProvider,
-		ssoManager:       ssoManager,
+		usersRepo:         usersRepo,
+		sessionsRepo:      sessionsRepo,
+		tokenizer:         tokenizer,
+		emailer:           emailer,
+		twoFARateLimiter:  twoFARateLimiter,
+		authProvider:      authProvider,
+		ssoManager:        ssoManager,
+		webAuthn:          webAuthn,
+		webAuthnRepo:      webAuthnRepo,
+		recoveryCodesRepo: recoveryCodesRepo,
+		txManager:         txManager,
+		permissions:       permissions,
+		memberships:       memberships,
+		globalRoles:       globalRoles,
 	}
 }
 
@@ -68,21 +97,21 @@ func (s *UsersService) Login(
 		return "", "", "", false, domain.ErrInactiveUser
 	}
 
-	if user.TwoFAEnabled {
-		sessionID = generate2FASession(user.ID, user.Username, time.Minute)
+	methods, err := s.twoFAMethods(ctx, user)
+	if err != nil {
+		return "", "", "", false, err
+	}
+
+	if len(methods) > 0 {
+		sessionID = generate2FASession(user.ID, user.Username, methods, time.Minute)
 
 		return "", "", sessionID, false, domain.ErrTwoFARequired
 	}
 
 	// Generate tokens
-	accessToken, err = s.tokenizer.AccessToken(user)
-	if err != nil {
-		return "", "", "", false, fmt.Errorf("generate access token: %w", err)
-	}
-
-	refreshToken, err = s.tokenizer.RefreshToken(user)
+	accessToken, refreshToken, err = s.issueTokens(ctx, user)
 	if err != nil {
-		return "", "", "", false, fmt.Errorf("generate refresh token: %w", err)
+		return "", "", "", false, err
 	}
 
 	if err := s.usersRepo.UpdateLastLogin(ctx, user.ID); err != nil {
@@ -113,12 +142,20 @@ func (s *UsersService) LoginReissue(
 		return "", "", domain.ErrInactiveUser
 	}
 
-	accessToken, err = s.tokenizer.AccessToken(&user)
+	// The audit entry of a detected reuse is attributed to the owner of the token
+	ctx = appcontext.WithUsername(ctx, user.Username)
+
+	refreshTokenID := uuid.NewString()
+	if err := s.useSession(ctx, claims, refreshTokenID); err != nil {
+		return "", "", err
+	}
+
+	accessToken, err = s.tokenizer.AccessToken(&user, claims.SessionID)
 	if err != nil {
 		return "", "", fmt.Errorf("generate access token: %w", err)
 	}
 
-	refreshToken, err = s.tokenizer.RefreshToken(&user)
+	refreshToken, err = s.tokenizer.RefreshToken(&user, claims.SessionID, refreshTokenID)
 	if err != nil {
 		return "", "", fmt.Errorf("generate refresh token: %w", err)
 	}
@@ -140,26 +177,40 @@ func (s *UsersService) List(ctx context.Context) ([]domain.User, error) {
 	return s.usersRepo.List(ctx)
 }
 
-// Create creates a new user. Only superusers can create new users.
+// Create creates a new user. Only superusers and user managers can create new users,
+// superusers can be created by superusers only.
 func (s *UsersService) Create(
 	ctx context.Context,
 	currentUser domain.User,
 	username, email, password string,
 	isSuperuser bool,
 ) (domain.User, error) {
-	// Check if the current user is a superuser
-	if !currentUser.IsSuperuser {
-		return domain.User{}, domain.ErrPermissionDenied
+	return s.CreateWithMemberships(ctx, currentUser, username, email, password, isSuperuser, nil)
+}
+
+// CreateWithMemberships creates the user and grants the project memberships in one transaction,
+// so the user is not created when a membership cannot be granted. The current user must be able
+// to manage the memberships of the projects.
+func (s *UsersService) CreateWithMemberships(
+	ctx context.Context,
+	currentUser domain.User,
+	username, email, password string,
+	isSuperuser bool,
+	grants []domain.MembershipGrant,
+) (domain.User, error) {
+	if err := s.checkUserManager(ctx, &currentUser); err != nil {
+		return domain.User{}, err
 	}
 
-	_, err := s.usersRepo.GetByUsername(ctx, username)
-	if err == nil {
-		return domain.User{}, domain.ErrUsernameAlreadyInUse
+	// Only superusers can create superusers
+	if isSuperuser && !currentUser.IsSuperuser {
+		return domain.User{}, domain.ErrPermissionDenied
 	}
 
-	_, err = s.usersRepo.GetByEmail(ctx, email)
-	if err == nil {
-		return domain.User{}, domain.ErrEmailAlreadyInUse
+	for _, grant := range grants {
+		if err := s.permissions.CanManageMembership(ctx, grant.ProjectID); err != nil {
+			return domain.User{}, err
+		}
 	}
 
 	passwordHash, err := passworder.PasswordHash(password)
@@ -176,11 +227,36 @@ func (s *UsersService) Create(
 		IsExternal:    false,
 	}
 
-	user, err := s.usersRepo.Create(ctx, userDTO)
+	var user domain.User
+	err = s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+		if _, err := s.usersRepo.GetByUsername(ctx, username); err == nil {
+			return domain.ErrUsernameAlreadyInUse
+		}
+
+		if _, err := s.usersRepo.GetByEmail(ctx, email); err == nil {
+			return domain.ErrEmailAlreadyInUse
+		}
+
+		user, err = s.usersRepo.Create(ctx, userDTO)
+		if err != nil {
+			return fmt.Errorf("create user: %w", err)
+		}
+
+		for _, grant := range grants {
+			_, err := s.memberships.CreateProjectMembership(ctx, grant.ProjectID, user.ID, grant.RoleID, grant.ValidUntil)
+			if err != nil {
+				return fmt.Errorf("create membership in project %d: %w", grant.ProjectID, err)
+			}
+		}
+
+		return nil
+	})
 	if err != nil {
-		return domain.User{}, fmt.Errorf("create user: %w", err)
+		return domain.User{}, err
 	}
 
+	s.trySendVerificationEmail(ctx, &user)
+
 	return user, nil
 }
 
@@ -227,19 +303,12 @@ func (s *UsersService) SetSuperuserStatus(
 }
 
 // SetActiveStatus sets or unsets the active status of a user.
-// Only superusers can change the active status of users.
+// Only superusers and user managers can change the active status of users;
+// user managers cannot change superusers.
 func (s *UsersService) SetActiveStatus(ctx context.Context, id domain.UserID, isActive bool) (domain.User, error) {
-	// Get the current user from context
-	currentUserID := appcontext.UserID(ctx)
-
-	currentUser, err := s.usersRepo.GetByID(ctx, currentUserID)
+	currentUser, err := s.currentUserManager(ctx)
 	if err != nil {
-		return domain.User{}, fmt.Errorf("get current user by id: %w", err)
-	}
-
-	// Check if the current user is a superuser
-	if !currentUser.IsSuperuser {
-		return domain.User{}, domain.ErrPermissionDenied
+		return domain.User{}, err
 	}
 
 	// Get the user to modify
@@ -248,6 +317,10 @@ func (s *UsersService) SetActiveStatus(ctx context.Context, id domain.UserID, is
 		return domain.User{}, fmt.Errorf("get user by id: %w", err)
 	}
 
+	if !canManageUser(&currentUser, &user) {
+		return domain.User{}, domain.ErrPermissionDenied
+	}
+
 	user.IsActive = isActive
 	user.UpdatedAt = time.Now()
 
@@ -259,19 +332,10 @@ func (s *UsersService) SetActiveStatus(ctx context.Context, id domain.UserID, is
 }
 
 // Delete deletes a user.
-// Only superusers can delete users, and superusers cannot be deleted.
+// Only superusers and user managers can delete users, and superusers cannot be deleted.
 func (s *UsersService) Delete(ctx context.Context, id domain.UserID) error {
-	// Get the current user from context
-	currentUserID := appcontext.UserID(ctx)
-
-	currentUser, err := s.usersRepo.GetByID(ctx, currentUserID)
-	if err != nil {
-		return fmt.Errorf("get current user by id: %w", err)
-	}
-
-	// Check if the current user is a superuser
-	if !currentUser.IsSuperuser {
-		return domain.ErrPermissionDenied
+	if _, err := s.currentUserManager(ctx); err != nil {
+		return err
 	}
 
 	user, err := s.usersRepo.GetByID(ctx, id)
@@ -290,13 +354,50 @@ func (s *UsersService) Delete(ctx context.Context, id domain.UserID) error {
 	return nil
 }
 
+// currentUserManager returns the current user if they can manage users.
+func (s *UsersService) currentUserManager(ctx context.Context) (domain.User, error) {
+	currentUser, err := s.usersRepo.GetByID(ctx, appcontext.UserID(ctx))
+	if err != nil {
+		return domain.User{}, fmt.Errorf("get current user by id: %w", err)
+	}
+
+	if err := s.checkUserManager(ctx, &currentUser); err != nil {
+		return domain.User{}, err
+	}
+
+	return currentUser, nil
+}
+
+// checkUserManager allows superusers and users granted user.manage by a global role.
+func (s *UsersService) checkUserManager(ctx context.Context, user *domain.User) error {
+	if user.IsSuperuser {
+		return nil
+	}
+
+	ok, err := s.permissions.HasGlobalPermission(ctx, domain.PermUserManage)
+	if err != nil {
+		return fmt.Errorf("check user.manage permission: %w", err)
+	}
+
+	if !ok {
+		return domain.ErrPermissionDenied
+	}
+
+	return nil
+}
+
+// canManageUser reports whether the manager may change the user; only superusers can change superusers.
+func canManageUser(manager, user *domain.User) bool {
+	return manager.IsSuperuser || !user.IsSuperuser
+}
+
 func (s *UsersService) UpdatePassword(ctx context.Context, id domain.UserID, oldPassword, newPassword string) error {
 	user, err := s.usersRepo.GetByID(ctx, id)
 	if err != nil {
 		return fmt.Errorf("get user by id: %w", err)
 	}
 
-	if user.IsExternal {
+	if user.IsExternal || user.IsServiceAccount {
 		return domain.ErrPermissionDenied
 	}
 
@@ -317,6 +418,118 @@ func (s *UsersService) UpdatePassword(ctx context.Context, id domain.UserID, old
 	return s.usersRepo.UpdatePassword(ctx, id, passwordHash)
 }
 
+// UpdateProfile changes the email and display name of the user.
+// Usernames can be changed by superusers only, see UpdateUser.
+func (s *UsersService) UpdateProfile(
+	ctx context.Context,
+	id domain.UserID,
+	update domain.UserUpdate,
+) (domain.User, error) {
+	if update.Username != nil {
+		return domain.User{}, domain.ErrPermissionDenied
+	}
+
+	user, err := s.usersRepo.GetByID(ctx, id)
+	if err != nil {
+		return domain.User{}, fmt.Errorf("get user by id: %w", err)
+	}
+
+	return s.applyUserUpdate(ctx, &user, update)
+}
+
+// UpdateUser changes the username, email and display name of a user.
+// Only superusers and user managers can update other users. The admin user (username="admin") cannot be renamed.
+func (s *UsersService) UpdateUser(
+	ctx context.Context,
+	id domain.UserID,
+	update domain.UserUpdate,
+) (domain.User, error) {
+	currentUser, err := s.currentUserManager(ctx)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	user, err := s.usersRepo.GetByID(ctx, id)
+	if err != nil {
+		return domain.User{}, fmt.Errorf("get user by id: %w", err)
+	}
+
+	if !canManageUser(&currentUser, &user) {
+		return domain.User{}, domain.ErrPermissionDenied
+	}
+
+	if user.Username == "admin" && update.Username != nil && strings.TrimSpace(*update.Username) != user.Username {
+		return domain.User{}, domain.ErrPermissionDenied
+	}
+
+	return s.applyUserUpdate(ctx, &user, update)
+}
+
+// applyUserUpdate saves the changed fields of the user. A changed email has to be verified again.
+// Usernames and emails of external users and service accounts are managed outside and cannot be changed.
+func (s *UsersService) applyUserUpdate(
+	ctx context.Context,
+	user *domain.User,
+	update domain.UserUpdate,
+) (domain.User, error) {
+	username, email, displayName := user.Username, user.Email, user.DisplayName
+	if update.Username != nil {
+		username = strings.TrimSpace(*update.Username)
+	}
+
+	if update.Email != nil {
+		email = strings.TrimSpace(*update.Email)
+	}
+
+	if update.DisplayName != nil {
+		displayName = strings.TrimSpace(*update.DisplayName)
+	}
+
+	usernameChanged := username != user.Username
+	emailChanged := email != user.Email
+
+	if (usernameChanged || emailChanged) && (user.IsExternal || user.IsServiceAccount) {
+		return domain.User{}, domain.ErrPermissionDenied
+	}
+
+	if usernameChanged {
+		_, err := s.usersRepo.GetByUsername(ctx, username)
+		if err == nil {
+			return domain.User{}, domain.ErrUsernameAlreadyInUse
+		}
+
+		if !errors.Is(err, domain.ErrEntityNotFound) {
+			return domain.User{}, fmt.Errorf("get user by username: %w", err)
+		}
+	}
+
+	if emailChanged {
+		_, err := s.usersRepo.GetByEmail(ctx, email)
+		if err == nil {
+			return domain.User{}, domain.ErrEmailAlreadyInUse
+		}
+
+		if !errors.Is(err, domain.ErrEntityNotFound) {
+			return domain.User{}, fmt.Errorf("get user by email: %w", err)
+		}
+	}
+
+	if err := s.usersRepo.UpdateProfile(ctx, user.ID, username, email, displayName); err != nil {
+		return domain.User{}, fmt.Errorf("update profile: %w", err)
+	}
+
+	updated, err := s.usersRepo.GetByID(ctx, user.ID)
+	if err != nil {
+		return domain.User{}, fmt.Errorf("get user by id: %w", err)
+	}
+
+	if emailChanged {
+		s.trySendVerificationEmail(ctx, &updated)
+	}
+
+	return updated, nil
+}
+
 func (s *UsersService) ChangeTemporaryPassword(ctx context.Context, id domain.UserID, newPassword string) error {
 	user, err := s.usersRepo.GetByID(ctx, id)
 	if err != nil {
@@ -431,6 +644,20 @@ func (s *UsersService) UpdateLicenseAcceptance(ctx context.Context, userID domai
 	return nil
 }
 
+// AcceptLicense records that the user accepted the version of the license agreement.
+// Accepting on behalf of the user with an impersonation token is not allowed.
+func (s *UsersService) AcceptLicense(ctx context.Context, userID domain.UserID, version string) error {
+	if appcontext.Impersonator(ctx) != "" {
+		return domain.ErrPermissionDenied
+	}
+
+	if err := s.usersRepo.AcceptLicense(ctx, userID, version, time.Now()); err != nil {
+		return fmt.Errorf("accept license: %w", err)
+	}
+
+	return nil
+}
+
 // VerifyPassword verifies that the provided password is correct for the given user.
 func (s *UsersService) VerifyPassword(ctx context.Context, userID domain.UserID, password string) error {
 	// Get the user
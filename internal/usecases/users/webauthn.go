@@ -0,0 +1,336 @@
+package users
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+const (
+	webAuthnRegistrationTTL = 5 * time.Minute
+	defaultSecurityKeyName  = "Security key"
+	maxSecurityKeyNameLen   = 128
+)
+
+// In-memory store for pending security key registrations.
+type webAuthnRegistrationEntry struct {
+	Session   webauthn.SessionData
+	ExpiresAt time.Time
+}
+
+var webAuthnRegistrationStore = struct {
+	sync.Mutex
+	sessions map[domain.UserID]webAuthnRegistrationEntry
+}{sessions: make(map[domain.UserID]webAuthnRegistrationEntry)}
+
+// webAuthnUser adapts a user and its security keys to webauthn.User.
+type webAuthnUser struct {
+	user        *domain.User
+	credentials []webauthn.Credential
+	stored      []domain.WebAuthnCredential
+}
+
+func (u *webAuthnUser) WebAuthnID() []byte {
+	return binary.BigEndian.AppendUint64(nil, uint64(u.user.ID))
+}
+
+func (u *webAuthnUser) WebAuthnName() string {
+	return u.user.Username
+}
+
+func (u *webAuthnUser) WebAuthnDisplayName() string {
+	return u.user.Name()
+}
+
+func (u *webAuthnUser) WebAuthnCredentials() []webauthn.Credential {
+	return u.credentials
+}
+
+// storedCredential returns the stored security key the assertion was made with.
+func (u *webAuthnUser) storedCredential(credentialID []byte) (domain.WebAuthnCredential, bool) {
+	for _, cred := range u.stored {
+		if bytes.Equal(cred.CredentialID, credentialID) {
+			return cred, true
+		}
+	}
+
+	return domain.WebAuthnCredential{}, false
+}
+
+func (s *UsersService) loadWebAuthnUser(ctx context.Context, user *domain.User) (*webAuthnUser, error) {
+	stored, err := s.webAuthnRepo.List(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list security keys: %w", err)
+	}
+
+	credentials := make([]webauthn.Credential, 0, len(stored))
+	for _, cred := range stored {
+		var credential webauthn.Credential
+		if err := json.Unmarshal(cred.Credential, &credential); err != nil {
+			return nil, fmt.Errorf("unmarshal security key %d: %w", cred.ID, err)
+		}
+
+		credentials = append(credentials, credential)
+	}
+
+	return &webAuthnUser{user: user, credentials: credentials, stored: stored}, nil
+}
+
+// BeginWebAuthnRegistration starts registration of a security key for the user.
+func (s *UsersService) BeginWebAuthnRegistration(
+	ctx context.Context,
+	userID domain.UserID,
+) (*protocol.CredentialCreation, error) {
+	user, err := s.usersRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+
+	if err := requireVerifiedEmail(&user); err != nil {
+		return nil, err
+	}
+
+	waUser, err := s.loadWebAuthnUser(ctx, &user)
+	if err != nil {
+		return nil, err
+	}
+
+	exclusions := make([]protocol.CredentialDescriptor, 0, len(waUser.credentials))
+	for _, credential := range waUser.credentials {
+		exclusions = append(exclusions, credential.Descriptor())
+	}
+
+	creation, session, err := s.webAuthn.BeginRegistration(waUser, webauthn.WithExclusions(exclusions))
+	if err != nil {
+		return nil, fmt.Errorf("begin webauthn registration: %w", err)
+	}
+
+	webAuthnRegistrationStore.Lock()
+	webAuthnRegistrationStore.sessions[userID] = webAuthnRegistrationEntry{
+		Session:   *session,
+		ExpiresAt: time.Now().Add(webAuthnRegistrationTTL),
+	}
+	webAuthnRegistrationStore.Unlock()
+
+	return creation, nil
+}
+
+// FinishWebAuthnRegistration verifies the attestation sent by the browser and stores the security key.
+func (s *UsersService) FinishWebAuthnRegistration(
+	ctx context.Context,
+	userID domain.UserID,
+	name string,
+	req *http.Request,
+) (domain.WebAuthnCredential, error) {
+	webAuthnRegistrationStore.Lock()
+	entry, ok := webAuthnRegistrationStore.sessions[userID]
+	delete(webAuthnRegistrationStore.sessions, userID)
+	webAuthnRegistrationStore.Unlock()
+
+	if !ok || time.Now().After(entry.ExpiresAt) {
+		return domain.WebAuthnCredential{}, domain.ErrInvalidToken
+	}
+
+	user, err := s.usersRepo.GetByID(ctx, userID)
+	if err != nil {
+		return domain.WebAuthnCredential{}, fmt.Errorf("get user: %w", err)
+	}
+
+	waUser, err := s.loadWebAuthnUser(ctx, &user)
+	if err != nil {
+		return domain.WebAuthnCredential{}, err
+	}
+
+	credential, err := s.webAuthn.FinishRegistration(waUser, entry.Session, req)
+	if err != nil {
+		return domain.WebAuthnCredential{}, fmt.Errorf("%w: %w", domain.ErrInvalidWebAuthnResponse, err)
+	}
+
+	data, err := json.Marshal(credential)
+	if err != nil {
+		return domain.WebAuthnCredential{}, fmt.Errorf("marshal security key: %w", err)
+	}
+
+	return s.webAuthnRepo.Create(ctx, domain.WebAuthnCredential{
+		UserID:       userID,
+		Name:         securityKeyName(name),
+		CredentialID: credential.ID,
+		Credential:   data,
+	})
+}
+
+func (s *UsersService) ListWebAuthnCredentials(
+	ctx context.Context,
+	userID domain.UserID,
+) ([]domain.WebAuthnCredential, error) {
+	return s.webAuthnRepo.List(ctx, userID)
+}
+
+func (s *UsersService) DeleteWebAuthnCredential(
+	ctx context.Context,
+	userID domain.UserID,
+	id domain.WebAuthnCredentialID,
+) error {
+	return s.webAuthnRepo.Delete(ctx, userID, id)
+}
+
+// Get2FAMethods returns the second factors the user of a pending 2FA login session can use.
+func (s *UsersService) Get2FAMethods(_ context.Context, sessionID string) ([]domain.TwoFAMethod, error) {
+	session, ok := get2FASession(sessionID)
+	if !ok {
+		return nil, domain.ErrInvalidToken
+	}
+
+	return session.Methods, nil
+}
+
+// BeginWebAuthnLogin starts the security key assertion for a pending 2FA login session.
+func (s *UsersService) BeginWebAuthnLogin(
+	ctx context.Context,
+	sessionID string,
+) (*protocol.CredentialAssertion, error) {
+	session, ok := get2FASession(sessionID)
+	if !ok {
+		return nil, domain.ErrInvalidToken
+	}
+
+	if s.twoFARateLimiter.IsBlocked(session.UserID) {
+		return nil, domain.ErrTooMany2FAAttempts
+	}
+
+	user, err := s.usersRepo.GetByID(ctx, session.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+
+	waUser, err := s.loadWebAuthnUser(ctx, &user)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(waUser.credentials) == 0 {
+		return nil, domain.ErrNoWebAuthnCredentials
+	}
+
+	assertion, data, err := s.webAuthn.BeginLogin(waUser)
+	if err != nil {
+		return nil, fmt.Errorf("begin webauthn login: %w", err)
+	}
+
+	if !set2FASessionWebAuthn(sessionID, data) {
+		return nil, domain.ErrInvalidToken
+	}
+
+	return assertion, nil
+}
+
+// FinishWebAuthnLogin verifies the security key assertion and completes the 2FA login.
+func (s *UsersService) FinishWebAuthnLogin(
+	ctx context.Context,
+	sessionID string,
+	req *http.Request,
+) (accessToken, refreshToken string, expiresIn int, err error) {
+	session, ok := get2FASession(sessionID)
+	if !ok || session.WebAuthn == nil {
+		return "", "", 0, domain.ErrInvalidToken
+	}
+
+	userID := session.UserID
+	if s.twoFARateLimiter.IsBlocked(userID) {
+		return "", "", 0, domain.ErrTooMany2FAAttempts
+	}
+
+	delete2FASession(sessionID)
+
+	user, err := s.usersRepo.GetByID(ctx, userID)
+	if err != nil {
+		return "", "", 0, fmt.Errorf("get user: %w", err)
+	}
+
+	waUser, err := s.loadWebAuthnUser(ctx, &user)
+	if err != nil {
+		return "", "", 0, err
+	}
+
+	credential, err := s.webAuthn.FinishLogin(waUser, *session.WebAuthn, req)
+	if err == nil && credential.Authenticator.CloneWarning {
+		err = errors.New("security key sign counter went backwards, the key may be cloned")
+	}
+
+	if err != nil {
+		_, blocked := s.twoFARateLimiter.Inc(userID)
+		if blocked {
+			return "", "", 0, domain.ErrTooMany2FAAttempts
+		}
+
+		return "", "", 0, fmt.Errorf("%w: %w", domain.ErrInvalidWebAuthnResponse, err)
+	}
+
+	s.twoFARateLimiter.Reset(userID)
+
+	if stored, ok := waUser.storedCredential(credential.ID); ok {
+		data, err := json.Marshal(credential)
+		if err != nil {
+			return "", "", 0, fmt.Errorf("marshal security key: %w", err)
+		}
+
+		if err := s.webAuthnRepo.UpdateCredential(ctx, stored.ID, data, time.Now()); err != nil {
+			return "", "", 0, fmt.Errorf("update security key: %w", err)
+		}
+	}
+
+	accessToken, refreshToken, err = s.issueTokens(ctx, &user)
+	if err != nil {
+		return "", "", 0, err
+	}
+
+	expiresIn = int(s.tokenizer.AccessTokenTTL().Seconds())
+
+	return accessToken, refreshToken, expiresIn, nil
+}
+
+// twoFAMethods returns the second factors configured for the user; the login requires
+// 2FA when at least one is configured and any of them completes it.
+func (s *UsersService) twoFAMethods(ctx context.Context, user *domain.User) ([]domain.TwoFAMethod, error) {
+	var methods []domain.TwoFAMethod
+
+	if user.TwoFAEnabled {
+		methods = append(methods, domain.TwoFAMethodTOTP)
+	}
+
+	creds, err := s.webAuthnRepo.List(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list security keys: %w", err)
+	}
+
+	if len(creds) > 0 {
+		methods = append(methods, domain.TwoFAMethodWebAuthn)
+	}
+
+	return methods, nil
+}
+
+func securityKeyName(name string) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return defaultSecurityKeyName
+	}
+
+	if len([]rune(name)) > maxSecurityKeyNameLen {
+		name = string([]rune(name)[:maxSecurityKeyNameLen])
+	}
+
+	return name
+}
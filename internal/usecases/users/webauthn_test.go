@@ -0,0 +1,35 @@
+package users
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+func TestSecurityKeyName(t *testing.T) {
+	assert.Equal(t, defaultSecurityKeyName, securityKeyName("  "))
+	assert.Equal(t, "YubiKey 5", securityKeyName(" YubiKey 5 "))
+	assert.Len(t, []rune(securityKeyName(strings.Repeat("ключ", 50))), maxSecurityKeyNameLen)
+}
+
+func TestWebAuthnUserStoredCredential(t *testing.T) {
+	user := &webAuthnUser{
+		user: &domain.User{ID: 42, Username: "jdoe"},
+		stored: []domain.WebAuthnCredential{
+			{ID: 1, CredentialID: []byte{1, 2, 3}},
+			{ID: 2, CredentialID: []byte{4, 5, 6}},
+		},
+	}
+
+	cred, ok := user.storedCredential([]byte{4, 5, 6})
+	assert.True(t, ok)
+	assert.Equal(t, domain.WebAuthnCredentialID(2), cred.ID)
+
+	_, ok = user.storedCredential([]byte{7})
+	assert.False(t, ok)
+
+	assert.Equal(t, []byte{0, 0, 0, 0, 0, 0, 0, 42}, user.WebAuthnID())
+}
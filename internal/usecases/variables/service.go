@@ -0,0 +1,208 @@
+package variables
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/crypt"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.VariablesUseCase = (*Service)(nil)
+
+var ErrInvalidVariable = errors.New("invalid variable")
+
+// InputKey is the key of the workflow input the variables are injected under.
+const InputKey = "variables"
+
+const maxValueSize = 64 << 10
+
+var namePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]{0,127}$`)
+
+type Service struct {
+	tx            db.TxManager
+	variablesRepo contract.VariablesRepository
+	secret        []byte
+}
+
+func New(
+	tx db.TxManager,
+	variablesRepo contract.VariablesRepository,
+	secret string,
+) *Service {
+	return &Service{
+		tx:            tx,
+		variablesRepo: variablesRepo,
+		secret:        []byte(secret),
+	}
+}
+
+func (s *Service) List(ctx context.Context, projectID domain.ProjectID) ([]domain.ProjectVariable, error) {
+	variables, err := s.variablesRepo.List(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range variables {
+		if err := s.reveal(&variables[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return variables, nil
+}
+
+func (s *Service) Get(ctx context.Context, projectID domain.ProjectID, name string) (domain.ProjectVariable, error) {
+	variable, err := s.variablesRepo.Get(ctx, projectID, name)
+	if err != nil {
+		return domain.ProjectVariable{}, err
+	}
+
+	if err := s.reveal(&variable); err != nil {
+		return domain.ProjectVariable{}, err
+	}
+
+	return variable, nil
+}
+
+func (s *Service) Set(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	dto domain.ProjectVariableDTO,
+) (domain.ProjectVariable, bool, error) {
+	if !namePattern.MatchString(dto.Name) {
+		return domain.ProjectVariable{}, false, fmt.Errorf(
+			"%w: name must start with a letter or underscore and contain only letters, digits and underscores",
+			ErrInvalidVariable)
+	}
+
+	if len(dto.Value) > maxValueSize {
+		return domain.ProjectVariable{}, false, fmt.Errorf("%w: value must be at most %d bytes",
+			ErrInvalidVariable, maxValueSize)
+	}
+
+	valueEncrypted, err := s.encrypt(dto.Value)
+	if err != nil {
+		return domain.ProjectVariable{}, false, err
+	}
+
+	var (
+		variable domain.ProjectVariable
+		created  bool
+	)
+	err = s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = s.variablesRepo.Upsert(ctx, projectID, dto.Name, valueEncrypted, dto.Secret)
+		if err != nil {
+			return err
+		}
+
+		variable, err = s.variablesRepo.Get(ctx, projectID, dto.Name)
+
+		return err
+	})
+	if err != nil {
+		return domain.ProjectVariable{}, false, fmt.Errorf("set variable: %w", err)
+	}
+
+	if !variable.Secret {
+		variable.Value = dto.Value
+	}
+
+	return variable, created, nil
+}
+
+func (s *Service) Delete(ctx context.Context, projectID domain.ProjectID, name string) error {
+	return s.variablesRepo.Delete(ctx, projectID, name)
+}
+
+// InjectVariables sets the project variables under the "variables" key of an object input,
+// replacing the values of the same name given in the input. Other inputs are returned unchanged.
+// The values of secret variables are redacted from every payload read, see the redaction use case.
+func (s *Service) InjectVariables(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	input json.RawMessage,
+) (json.RawMessage, error) {
+	variables, err := s.variablesRepo.List(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("list variables: %w", err)
+	}
+
+	if len(variables) == 0 {
+		return input, nil
+	}
+
+	object := map[string]json.RawMessage{}
+	if len(input) > 0 && (json.Unmarshal(input, &object) != nil || object == nil) {
+		return input, nil
+	}
+
+	values := map[string]any{}
+	if current, ok := object[InputKey]; ok {
+		// Variables passed explicitly are kept unless the project defines them
+		_ = json.Unmarshal(current, &values)
+		if values == nil {
+			values = map[string]any{}
+		}
+	}
+
+	for i := range variables {
+		value, err := s.decrypt(variables[i].ValueEncrypted)
+		if err != nil {
+			return nil, fmt.Errorf("variable %s: %w", variables[i].Name, err)
+		}
+		values[variables[i].Name] = value
+	}
+
+	object[InputKey], err = json.Marshal(values)
+	if err != nil {
+		return nil, fmt.Errorf("marshal variables: %w", err)
+	}
+
+	return json.Marshal(object)
+}
+
+// reveal decrypts the value of a variable that is not secret.
+func (s *Service) reveal(variable *domain.ProjectVariable) error {
+	if variable.Secret {
+		return nil
+	}
+
+	value, err := s.decrypt(variable.ValueEncrypted)
+	if err != nil {
+		return fmt.Errorf("variable %s: %w", variable.Name, err)
+	}
+	variable.Value = value
+
+	return nil
+}
+
+func (s *Service) encrypt(value string) (string, error) {
+	encrypted, err := crypt.EncryptAESGCM([]byte(value), s.secret)
+	if err != nil {
+		return "", fmt.Errorf("encrypt variable: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(encrypted), nil
+}
+
+func (s *Service) decrypt(value string) (string, error) {
+	encrypted, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return "", fmt.Errorf("decode variable: %w", err)
+	}
+
+	decrypted, err := crypt.DecryptAESGCM(encrypted, s.secret)
+	if err != nil {
+		return "", fmt.Errorf("decrypt variable: %w", err)
+	}
+
+	return string(decrypted), nil
+}
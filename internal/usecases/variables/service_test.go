@@ -0,0 +1,59 @@
+package variables
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type fakeVariablesRepo struct {
+	contract.VariablesRepository
+	variables []domain.ProjectVariable
+}
+
+func (r *fakeVariablesRepo) List(context.Context, domain.ProjectID) ([]domain.ProjectVariable, error) {
+	return r.variables, nil
+}
+
+func TestService_InjectVariables(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeVariablesRepo{}
+	srv := New(nil, repo, "0123456789abcdef0123456789abcdef")
+
+	// Without variables the input is kept as is
+	got, err := srv.InjectVariables(ctx, 1, json.RawMessage(`{"a":1}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"a":1}`, string(got))
+
+	token, err := srv.encrypt("s3cr3t")
+	require.NoError(t, err)
+	region, err := srv.encrypt("eu")
+	require.NoError(t, err)
+	repo.variables = []domain.ProjectVariable{
+		{Name: "TOKEN", ValueEncrypted: token, Secret: true},
+		{Name: "REGION", ValueEncrypted: region},
+	}
+
+	got, err = srv.InjectVariables(ctx, 1, json.RawMessage(`{"a":1,"variables":{"REGION":"us","EXTRA":"x"}}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"a":1,"variables":{"TOKEN":"s3cr3t","REGION":"eu","EXTRA":"x"}}`, string(got))
+
+	got, err = srv.InjectVariables(ctx, 1, nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"variables":{"TOKEN":"s3cr3t","REGION":"eu"}}`, string(got))
+
+	got, err = srv.InjectVariables(ctx, 1, json.RawMessage(`[1,2]`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `[1,2]`, string(got))
+
+	variables, err := srv.List(ctx, 1)
+	require.NoError(t, err)
+	assert.Empty(t, variables[0].Value)
+	assert.Equal(t, "eu", variables[1].Value)
+}
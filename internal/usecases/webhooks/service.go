@@ -0,0 +1,295 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/db"
+	"github.com/rom8726/floxy-manager/pkg/safehttp"
+)
+
+var _ contract.WebhooksUseCase = (*Service)(nil)
+
+var ErrInvalidWebhook = errors.New("invalid webhook")
+
+const (
+	deliveryBatchSize = 50
+	maxAttempts       = 6
+	retryBaseDelay    = 30 * time.Second
+	retryMaxDelay     = time.Hour
+)
+
+type Service struct {
+	tx           db.TxManager
+	webhooksRepo contract.WebhooksRepository
+	notifier     contract.NotificationRouter
+}
+
+func New(tx db.TxManager, webhooksRepo contract.WebhooksRepository, notifier contract.NotificationRouter) *Service {
+	return &Service{
+		tx:           tx,
+		webhooksRepo: webhooksRepo,
+		notifier:     notifier,
+	}
+}
+
+func (s *Service) List(ctx context.Context, projectID domain.ProjectID) ([]domain.Webhook, error) {
+	return s.webhooksRepo.List(ctx, projectID)
+}
+
+func (s *Service) Get(ctx context.Context, projectID domain.ProjectID, id domain.WebhookID) (domain.Webhook, error) {
+	return s.webhooksRepo.GetByID(ctx, projectID, id)
+}
+
+func (s *Service) Create(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	dto domain.WebhookDTO,
+) (domain.Webhook, error) {
+	if err := validate(dto); err != nil {
+		return domain.Webhook{}, err
+	}
+
+	var webhook domain.Webhook
+	err := s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		id, err := s.webhooksRepo.Create(ctx, projectID, dto)
+		if err != nil {
+			return err
+		}
+
+		webhook, err = s.webhooksRepo.GetByID(ctx, projectID, id)
+
+		return err
+	})
+	if err != nil {
+		return domain.Webhook{}, fmt.Errorf("create webhook: %w", err)
+	}
+
+	return webhook, nil
+}
+
+func (s *Service) Update(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	id domain.WebhookID,
+	dto domain.WebhookDTO,
+) (domain.Webhook, error) {
+	if err := validate(dto); err != nil {
+		return domain.Webhook{}, err
+	}
+
+	var webhook domain.Webhook
+	err := s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		if err := s.webhooksRepo.Update(ctx, projectID, id, dto); err != nil {
+			return err
+		}
+
+		var err error
+		webhook, err = s.webhooksRepo.GetByID(ctx, projectID, id)
+
+		return err
+	})
+	if err != nil {
+		return domain.Webhook{}, fmt.Errorf("update webhook: %w", err)
+	}
+
+	return webhook, nil
+}
+
+func (s *Service) Delete(ctx context.Context, projectID domain.ProjectID, id domain.WebhookID) error {
+	return s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		return s.webhooksRepo.Delete(ctx, projectID, id)
+	})
+}
+
+func (s *Service) ListDeliveries(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	id domain.WebhookID,
+	page, pageSize int,
+) ([]domain.WebhookDelivery, int, error) {
+	if _, err := s.webhooksRepo.GetByID(ctx, projectID, id); err != nil {
+		return nil, 0, err
+	}
+
+	return s.webhooksRepo.ListDeliveries(ctx, id, page, pageSize)
+}
+
+// HandleEvents enqueues a delivery for every webhook subscribed to the event.
+func (s *Service) HandleEvents(ctx context.Context, events []domain.LifecycleEvent) error {
+	type subscriptionKey struct {
+		projectID domain.ProjectID
+		eventType domain.LifecycleEventType
+	}
+
+	subscriptions := make(map[subscriptionKey][]domain.Webhook)
+	now := time.Now()
+
+	for i := range events {
+		event := events[i]
+
+		key := subscriptionKey{projectID: event.ProjectID, eventType: event.Type}
+		webhooks, ok := subscriptions[key]
+		if !ok {
+			var err error
+			webhooks, err = s.webhooksRepo.ListSubscribed(ctx, event.ProjectID, event.Type)
+			if err != nil {
+				return fmt.Errorf("list subscribed webhooks: %w", err)
+			}
+
+			subscriptions[key] = webhooks
+		}
+
+		if len(webhooks) == 0 {
+			continue
+		}
+
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("marshal event: %w", err)
+		}
+
+		for _, webhook := range webhooks {
+			_, err := s.webhooksRepo.EnqueueDelivery(ctx, domain.WebhookDelivery{
+				WebhookID:     webhook.ID,
+				EventType:     event.Type,
+				EventKey:      event.Key(),
+				Payload:       payload,
+				NextAttemptAt: &now,
+			})
+			if err != nil {
+				return fmt.Errorf("enqueue webhook %d delivery: %w", webhook.ID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) DeliverPending(ctx context.Context, now time.Time) (int, error) {
+	due, err := s.webhooksRepo.ListDueDeliveries(ctx, now, deliveryBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("list due webhook deliveries: %w", err)
+	}
+
+	delivered := 0
+	for i := range due {
+		delivery := due[i]
+		delivery.Attempts++
+
+		statusCode, err := s.send(ctx, delivery)
+		delivery.LastStatusCode = nil
+		if statusCode != 0 {
+			delivery.LastStatusCode = &statusCode
+		}
+
+		if err == nil {
+			deliveredAt := time.Now()
+			delivery.Status = domain.WebhookDeliveryDelivered
+			delivery.DeliveredAt = &deliveredAt
+			delivery.NextAttemptAt = nil
+			delivery.LastError = nil
+			delivered++
+		} else {
+			errMsg := err.Error()
+			delivery.LastError = &errMsg
+
+			if delivery.Attempts >= maxAttempts {
+				delivery.Status = domain.WebhookDeliveryFailed
+				delivery.NextAttemptAt = nil
+
+				slog.Warn("Webhook delivery failed permanently",
+					"error", err,
+					"webhook_id", delivery.WebhookID,
+					"delivery_id", delivery.ID,
+					"attempts", delivery.Attempts,
+				)
+			} else {
+				nextAttemptAt := now.Add(retryDelay(delivery.Attempts))
+				delivery.NextAttemptAt = &nextAttemptAt
+			}
+		}
+
+		if err := s.webhooksRepo.SaveDeliveryAttempt(ctx, delivery); err != nil {
+			return delivered, fmt.Errorf("save webhook delivery %d: %w", delivery.ID, err)
+		}
+	}
+
+	return delivered, nil
+}
+
+// send posts the delivery payload, on failure it returns the response status code (0 if no response).
+func (s *Service) send(ctx context.Context, delivery domain.WebhookDelivery) (int, error) {
+	target := domain.NotificationTarget{
+		Kind:    domain.NotificationTargetWebhook,
+		Address: delivery.URL,
+		Headers: map[string]string{
+			"X-Floxy-Event":    string(delivery.EventType),
+			"X-Floxy-Delivery": strconv.FormatInt(delivery.ID, 10),
+		},
+	}
+
+	err := s.notifier.Notify(ctx, target, domain.Notification{Payload: delivery.Payload})
+	if err != nil {
+		var endpointErr *domain.NotificationEndpointError
+		if errors.As(err, &endpointErr) {
+			return endpointErr.StatusCode, err
+		}
+
+		return 0, err
+	}
+
+	return 0, nil
+}
+
+// retryDelay returns the exponential backoff before the next attempt: 30s, 1m, 2m, ... up to 1h.
+func retryDelay(attempts int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= retryMaxDelay {
+			return retryMaxDelay
+		}
+	}
+
+	return delay
+}
+
+func validate(dto domain.WebhookDTO) error {
+	if dto.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidWebhook)
+	}
+
+	u, err := url.Parse(dto.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http(s) URL", ErrInvalidWebhook)
+	}
+
+	// Names resolving to internal addresses are rejected when the deliveries connect
+	host := u.Hostname()
+	if ip, err := netip.ParseAddr(host); (err == nil && !safehttp.IsPublic(ip)) || strings.EqualFold(host, "localhost") {
+		return fmt.Errorf("%w: url must point to a public address", ErrInvalidWebhook)
+	}
+
+	if len(dto.Events) == 0 {
+		return fmt.Errorf("%w: at least one event is required", ErrInvalidWebhook)
+	}
+
+	for _, event := range dto.Events {
+		if !event.Valid() {
+			return fmt.Errorf("%w: unknown event %q", ErrInvalidWebhook, event)
+		}
+	}
+
+	return nil
+}
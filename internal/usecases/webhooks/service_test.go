@@ -0,0 +1,53 @@
+package webhooks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, 30*time.Second, retryDelay(1))
+	assert.Equal(t, time.Minute, retryDelay(2))
+	assert.Equal(t, 4*time.Minute, retryDelay(4))
+	assert.Equal(t, time.Hour, retryDelay(20))
+}
+
+func TestValidate(t *testing.T) {
+	valid := domain.WebhookDTO{
+		Name:   "ops",
+		URL:    "https://example.com/hooks/floxy",
+		Events: []domain.LifecycleEventType{domain.EventInstanceFailed, domain.EventDLQItemCreated},
+	}
+	require.NoError(t, validate(valid))
+
+	tests := []struct {
+		name   string
+		modify func(dto *domain.WebhookDTO)
+	}{
+		{"empty name", func(dto *domain.WebhookDTO) { dto.Name = "" }},
+		{"relative url", func(dto *domain.WebhookDTO) { dto.URL = "/hooks" }},
+		{"unsupported scheme", func(dto *domain.WebhookDTO) { dto.URL = "ftp://example.com" }},
+		{"loopback", func(dto *domain.WebhookDTO) { dto.URL = "http://127.0.0.1:8080/hooks" }},
+		{"localhost", func(dto *domain.WebhookDTO) { dto.URL = "http://localhost/hooks" }},
+		{"private", func(dto *domain.WebhookDTO) { dto.URL = "https://10.0.0.5/hooks" }},
+		{"metadata", func(dto *domain.WebhookDTO) { dto.URL = "http://169.254.169.254/latest/meta-data" }},
+		{"ipv6 loopback", func(dto *domain.WebhookDTO) { dto.URL = "http://[::1]/hooks" }},
+		{"no events", func(dto *domain.WebhookDTO) { dto.Events = nil }},
+		{"unknown event", func(dto *domain.WebhookDTO) {
+			dto.Events = []domain.LifecycleEventType{"instance_started"}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dto := valid
+			tt.modify(&dto)
+			assert.ErrorIs(t, validate(dto), ErrInvalidWebhook)
+		})
+	}
+}
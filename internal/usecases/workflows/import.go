@@ -0,0 +1,181 @@
+package workflows
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	floxy "github.com/rom8726/floxy-pro"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+// ErrImportRejected is returned when an imported item is invalid or conflicts, nothing is created then.
+var ErrImportRejected = errors.New("workflow import rejected")
+
+const (
+	MaxImportItems        = 500
+	maxWorkflowNameLength = 255
+)
+
+func (s *Service) ImportDefinitions(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	items []domain.WorkflowImportItem,
+	dryRun bool,
+) ([]domain.WorkflowImportResult, error) {
+	results := make([]domain.WorkflowImportResult, len(items))
+	seen := make(map[string]string, len(items))
+	rejected := false
+
+	for i := range items {
+		item := &items[i]
+		results[i] = domain.WorkflowImportResult{Source: item.Source, Name: item.Name, Version: item.Version}
+
+		err := validateImportItem(item)
+		if err == nil {
+			key := fmt.Sprintf("%s-v%d", item.Name, item.Version)
+			if source, ok := seen[key]; ok {
+				err = fmt.Errorf("duplicates %s", source)
+			}
+			seen[key] = item.Source
+		}
+
+		if err != nil {
+			results[i].Status = domain.WorkflowImportInvalid
+			results[i].Error = err.Error()
+			rejected = true
+		}
+	}
+
+	if rejected {
+		return results, ErrImportRejected
+	}
+
+	err := s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		for i := range items {
+			item, result := &items[i], &results[i]
+
+			existing, err := s.workflowsRepo.FindWorkflowDefinition(ctx, item.Name, item.Version)
+			switch {
+			case errors.Is(err, domain.ErrEntityNotFound):
+				result.Status = domain.WorkflowImportCreated
+			case err != nil:
+				return err
+			case existing.ProjectID != projectID:
+				result.Status = domain.WorkflowImportConflict
+				result.Error = "version exists in another project"
+			case !sameJSON(existing.Definition, item.Definition):
+				result.Status = domain.WorkflowImportConflict
+				result.Error = "version exists with another definition"
+			default:
+				result.Status = domain.WorkflowImportUnchanged
+				result.ID = existing.ID
+			}
+
+			if result.Status == domain.WorkflowImportConflict {
+				rejected = true
+			}
+		}
+
+		if rejected || dryRun {
+			return nil
+		}
+
+		for i := range items {
+			item, result := &items[i], &results[i]
+			if result.Status != domain.WorkflowImportCreated {
+				continue
+			}
+
+			id, err := s.workflowsRepo.CreateWorkflowDefinition(ctx, projectID, item.Name, item.Version,
+				item.Definition)
+			if err != nil {
+				return fmt.Errorf("create %s: %w", item.Source, err)
+			}
+
+			result.ID = id
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("import workflow definitions: %w", err)
+	}
+
+	if rejected {
+		return results, ErrImportRejected
+	}
+
+	return results, nil
+}
+
+func validateImportItem(item *domain.WorkflowImportItem) error {
+	switch {
+	case strings.TrimSpace(item.Name) != item.Name || item.Name == "":
+		return errors.New("name is required and must not have surrounding spaces")
+	case len(item.Name) > maxWorkflowNameLength:
+		return fmt.Errorf("name is longer than %d characters", maxWorkflowNameLength)
+	case item.Version <= 0:
+		return errors.New("version must be greater than 0")
+	case len(item.Definition) == 0:
+		return errors.New("definition is required")
+	}
+
+	return validateDefinition(item.Definition)
+}
+
+// validateDefinition checks the graph is well-formed: the start step exists
+// and all the transitions refer to existing steps.
+func validateDefinition(raw json.RawMessage) error {
+	var graph floxy.GraphDefinition
+	if err := json.Unmarshal(raw, &graph); err != nil {
+		return fmt.Errorf("malformed definition: %w", err)
+	}
+
+	if len(graph.Steps) == 0 {
+		return errors.New("definition has no steps")
+	}
+
+	if _, ok := graph.Steps[graph.Start]; !ok {
+		return fmt.Errorf("start step %q is not defined", graph.Start)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(graph.Steps)) {
+		step := graph.Steps[name]
+		if step == nil {
+			return fmt.Errorf("step %q is empty", name)
+		}
+
+		refs := append(append(append([]string{}, step.Next...), step.Parallel...), step.WaitFor...)
+		if step.Else != "" {
+			refs = append(refs, step.Else)
+		}
+		if step.OnFailure != "" {
+			refs = append(refs, step.OnFailure)
+		}
+
+		for _, ref := range refs {
+			if _, ok := graph.Steps[ref]; !ok {
+				return fmt.Errorf("step %q refers to undefined step %q", name, ref)
+			}
+		}
+	}
+
+	return nil
+}
+
+// sameJSON reports whether the documents are equal regardless of formatting and key order.
+func sameJSON(a, b json.RawMessage) bool {
+	var va, vb any
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(va, vb)
+}
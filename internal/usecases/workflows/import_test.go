@@ -0,0 +1,110 @@
+package workflows
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type fakeTx struct{}
+
+func (fakeTx) ReadCommitted(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (fakeTx) RepeatableRead(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type fakeWorkflowsRepo struct {
+	contract.WorkflowsRepository
+	definitions map[string]domain.WorkflowDefinition
+	created     []string
+}
+
+func (r *fakeWorkflowsRepo) FindWorkflowDefinition(
+	_ context.Context,
+	name string,
+	version int,
+) (domain.WorkflowDefinition, error) {
+	def, ok := r.definitions[fmt.Sprintf("%s-v%d", name, version)]
+	if !ok {
+		return domain.WorkflowDefinition{}, domain.ErrEntityNotFound
+	}
+
+	return def, nil
+}
+
+func (r *fakeWorkflowsRepo) CreateWorkflowDefinition(
+	_ context.Context,
+	_ domain.ProjectID,
+	name string,
+	version int,
+	_ json.RawMessage,
+) (string, error) {
+	id := fmt.Sprintf("%s-v%d", name, version)
+	r.created = append(r.created, id)
+
+	return id, nil
+}
+
+const testDefinition = `{"start": "a", "steps": {"a": {"type": "task", "handler": "h", "next": ["b"]}, "b": {"type": "task"}}}`
+
+func TestService_ImportDefinitions(t *testing.T) {
+	repo := &fakeWorkflowsRepo{definitions: map[string]domain.WorkflowDefinition{
+		"orders-v1":  {ID: "orders-v1", ProjectID: 2, Definition: json.RawMessage(testDefinition)},
+		"billing-v1": {ID: "billing-v1", ProjectID: 3, Definition: json.RawMessage(testDefinition)},
+	}}
+	srv := &Service{tx: fakeTx{}, workflowsRepo: repo}
+
+	item := func(name string, version int) domain.WorkflowImportItem {
+		return domain.WorkflowImportItem{Source: name, Name: name, Version: version,
+			Definition: json.RawMessage(testDefinition)}
+	}
+
+	results, err := srv.ImportDefinitions(context.Background(), 2, []domain.WorkflowImportItem{
+		item("orders", 1), item("orders", 2),
+	}, false)
+	require.NoError(t, err)
+	assert.Equal(t, domain.WorkflowImportUnchanged, results[0].Status)
+	assert.Equal(t, domain.WorkflowImportCreated, results[1].Status)
+	assert.Equal(t, "orders-v2", results[1].ID)
+	assert.Equal(t, []string{"orders-v2"}, repo.created)
+
+	// A conflict rejects the whole import
+	repo.created = nil
+	results, err = srv.ImportDefinitions(context.Background(), 2, []domain.WorkflowImportItem{
+		item("payments", 1), item("billing", 1),
+	}, false)
+	require.ErrorIs(t, err, ErrImportRejected)
+	assert.Equal(t, domain.WorkflowImportCreated, results[0].Status)
+	assert.Equal(t, domain.WorkflowImportConflict, results[1].Status)
+	assert.Empty(t, repo.created)
+
+	results, err = srv.ImportDefinitions(context.Background(), 2, []domain.WorkflowImportItem{
+		item("payments", 1), item("payments", 1),
+	}, false)
+	require.ErrorIs(t, err, ErrImportRejected)
+	assert.Equal(t, domain.WorkflowImportInvalid, results[1].Status)
+	assert.Empty(t, repo.created)
+}
+
+func TestValidateDefinition(t *testing.T) {
+	require.NoError(t, validateDefinition(json.RawMessage(testDefinition)))
+
+	for _, def := range []string{
+		`[]`,
+		`{"start": "a", "steps": {}}`,
+		`{"start": "x", "steps": {"a": {"type": "task"}}}`,
+		`{"start": "a", "steps": {"a": {"type": "task", "on_failure": "undo"}}}`,
+	} {
+		assert.Error(t, validateDefinition(json.RawMessage(def)), def)
+	}
+}
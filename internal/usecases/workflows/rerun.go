@@ -0,0 +1,65 @@
+package workflows
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/jsonpatch"
+)
+
+// RerunInstance starts the new instance on the definition of the original one, even when
+// the workflow is pinned to another version: a re-run repeats the original instance.
+func (s *Service) RerunInstance(
+	ctx context.Context,
+	tenantID domain.TenantID,
+	projectID domain.ProjectID,
+	instanceID int,
+	patch json.RawMessage,
+) (domain.WorkflowInstance, error) {
+	original, err := s.workflowsRepo.GetWorkflowInstance(ctx, tenantID, projectID, instanceID)
+	if err != nil {
+		return domain.WorkflowInstance{}, err
+	}
+
+	input := original.Input
+	if len(input) == 0 {
+		input = json.RawMessage(`{}`)
+	}
+
+	if len(patch) > 0 {
+		input, err = jsonpatch.Apply(input, patch)
+		if err != nil {
+			return domain.WorkflowInstance{}, err
+		}
+	}
+
+	// Variables are injected again, so that the new instance gets their current values
+	input, err = s.variables.InjectVariables(ctx, projectID, input)
+	if err != nil {
+		return domain.WorkflowInstance{}, fmt.Errorf("inject variables: %w", err)
+	}
+
+	// The engine starts the instance in its own transaction, so the lineage is recorded afterwards
+	newID, err := s.engine.Start(ctx, original.WorkflowID, input)
+	if err != nil {
+		return domain.WorkflowInstance{}, fmt.Errorf("start workflow: %w", err)
+	}
+
+	if err := s.workflowsRepo.CreateInstanceLineage(ctx, int(newID), instanceID); err != nil {
+		slog.ErrorContext(ctx, "Failed to record instance lineage",
+			"error", err,
+			"instance_id", newID,
+			"parent_instance_id", instanceID,
+		)
+	}
+
+	instance, err := s.workflowsRepo.GetWorkflowInstance(ctx, tenantID, projectID, int(newID))
+	if err != nil {
+		return domain.WorkflowInstance{}, fmt.Errorf("get started instance: %w", err)
+	}
+
+	return instance, nil
+}
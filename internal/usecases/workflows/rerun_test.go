@@ -0,0 +1,78 @@
+package workflows
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/jsonpatch"
+)
+
+type fakeInstancesRepo struct {
+	contract.WorkflowsRepository
+	instances map[int]domain.WorkflowInstance
+}
+
+func (r *fakeInstancesRepo) GetWorkflowInstance(
+	_ context.Context,
+	_ domain.TenantID,
+	_ domain.ProjectID,
+	id int,
+) (domain.WorkflowInstance, error) {
+	instance, ok := r.instances[id]
+	if !ok {
+		return domain.WorkflowInstance{}, domain.ErrEntityNotFound
+	}
+
+	return instance, nil
+}
+
+func (r *fakeInstancesRepo) CreateInstanceLineage(_ context.Context, instanceID, parentInstanceID int) error {
+	instance := r.instances[instanceID]
+	instance.ParentInstanceID = &parentInstanceID
+	r.instances[instanceID] = instance
+
+	return nil
+}
+
+type fakeEngine struct {
+	repo *fakeInstancesRepo
+}
+
+func (e *fakeEngine) Start(_ context.Context, workflowID string, input json.RawMessage) (int64, error) {
+	id := len(e.repo.instances) + 1
+	e.repo.instances[id] = domain.WorkflowInstance{ID: id, WorkflowID: workflowID, Input: input}
+
+	return int64(id), nil
+}
+
+type noVariables struct{}
+
+func (noVariables) InjectVariables(_ context.Context, _ domain.ProjectID, input json.RawMessage) (json.RawMessage, error) {
+	return input, nil
+}
+
+func TestService_RerunInstance(t *testing.T) {
+	repo := &fakeInstancesRepo{instances: map[int]domain.WorkflowInstance{
+		1: {ID: 1, WorkflowID: "orders-v1", Input: json.RawMessage(`{"order_id": 7, "amount": 10}`)},
+	}}
+	srv := &Service{workflowsRepo: repo, engine: &fakeEngine{repo: repo}, variables: noVariables{}}
+
+	instance, err := srv.RerunInstance(context.Background(), 1, 1, 1,
+		json.RawMessage(`[{"op": "replace", "path": "/amount", "value": 12}]`))
+	require.NoError(t, err)
+	assert.Equal(t, "orders-v1", instance.WorkflowID)
+	assert.JSONEq(t, `{"order_id": 7, "amount": 12}`, string(instance.Input))
+	require.NotNil(t, instance.ParentInstanceID)
+	assert.Equal(t, 1, *instance.ParentInstanceID)
+
+	_, err = srv.RerunInstance(context.Background(), 1, 1, 1,
+		json.RawMessage(`[{"op": "remove", "path": "/missing"}]`))
+	require.ErrorIs(t, err, jsonpatch.ErrInvalidPatch)
+	assert.Len(t, repo.instances, 2)
+}
@@ -0,0 +1,177 @@
+package workflows
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+	"github.com/rom8726/floxy-manager/pkg/db"
+)
+
+var _ contract.WorkflowsUseCase = (*Service)(nil)
+
+// ErrInstancesNotIncluded is returned when a definition with instances is transferred
+// without its instances. Instances are scoped to projects through their definition,
+// so they cannot stay behind in the source project.
+var ErrInstancesNotIncluded = errors.New("workflow definition has instances, include_instances is required")
+
+var ErrSameProject = errors.New("source and target projects are the same")
+
+var ErrOtherTenant = errors.New("target project belongs to another tenant")
+
+// updateAttempts bounds the retries of a definition update losing the next version to a concurrent one.
+const updateAttempts = 3
+
+type Service struct {
+	tx             db.TxManager
+	workflowsRepo  contract.WorkflowsRepository
+	projectsRepo   contract.ProjectsRepository
+	permissionsSrv contract.PermissionsService
+	engine         contract.WorkflowEngine
+	variables      contract.VariablesInjector
+}
+
+func New(
+	tx db.TxManager,
+	workflowsRepo contract.WorkflowsRepository,
+	projectsRepo contract.ProjectsRepository,
+	permissionsSrv contract.PermissionsService,
+	engine contract.WorkflowEngine,
+	variables contract.VariablesInjector,
+) *Service {
+	return &Service{
+		tx:             tx,
+		workflowsRepo:  workflowsRepo,
+		projectsRepo:   projectsRepo,
+		permissionsSrv: permissionsSrv,
+		engine:         engine,
+		variables:      variables,
+	}
+}
+
+// AssignDefinitionsToProject assigns the definitions to the project, the caller must be able to manage it.
+func (s *Service) AssignDefinitionsToProject(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	ids []string,
+) (int, error) {
+	if err := s.permissionsSrv.CanManageProject(ctx, projectID); err != nil {
+		return 0, err
+	}
+
+	var assigned int
+	err := s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		var err error
+		assigned, err = s.workflowsRepo.AssignWorkflowDefinitionsToProject(ctx, projectID, ids)
+
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return assigned, nil
+}
+
+// UpdateDefinition stores the definition as the next version of the workflow in one transaction.
+// An update racing another one for the same version is retried with the following version.
+func (s *Service) UpdateDefinition(
+	ctx context.Context,
+	tenantID domain.TenantID,
+	projectID domain.ProjectID,
+	id string,
+	definition json.RawMessage,
+) (string, error) {
+	var workflowID string
+
+	var err error
+	for range updateAttempts {
+		err = s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+			var err error
+			workflowID, err = s.workflowsRepo.UpdateWorkflowDefinition(ctx, tenantID, projectID, id, definition)
+
+			return err
+		})
+		if !errors.Is(err, domain.ErrEntityAlreadyExists) {
+			break
+		}
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return workflowID, nil
+}
+
+// DeleteDefinition unassigns the definition from the project in one transaction, the definition
+// itself is deleted when no other project references it.
+func (s *Service) DeleteDefinition(
+	ctx context.Context,
+	tenantID domain.TenantID,
+	projectID domain.ProjectID,
+	id string,
+) error {
+	return s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		return s.workflowsRepo.DeleteWorkflowDefinition(ctx, tenantID, projectID, id)
+	})
+}
+
+// TransferDefinition moves a workflow definition (with its instance history) between projects
+// of the tenant. The caller must be able to manage both projects.
+func (s *Service) TransferDefinition(
+	ctx context.Context,
+	tenantID domain.TenantID,
+	fromProjectID domain.ProjectID,
+	toProjectID domain.ProjectID,
+	id string,
+	includeInstances bool,
+) error {
+	if fromProjectID == toProjectID {
+		return ErrSameProject
+	}
+
+	if err := s.permissionsSrv.CanManageProject(ctx, fromProjectID); err != nil {
+		return err
+	}
+
+	if err := s.permissionsSrv.CanManageProject(ctx, toProjectID); err != nil {
+		return err
+	}
+
+	if _, err := s.projectsRepo.GetByID(ctx, toProjectID); err != nil {
+		return fmt.Errorf("get target project: %w", err)
+	}
+
+	tenantProjects, err := s.projectsRepo.ListByTenant(ctx, tenantID)
+	if err != nil {
+		return fmt.Errorf("list tenant projects: %w", err)
+	}
+
+	if !slices.ContainsFunc(tenantProjects, func(project domain.Project) bool { return project.ID == toProjectID }) {
+		return ErrOtherTenant
+	}
+
+	return s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		if _, err := s.workflowsRepo.GetWorkflowDefinition(ctx, tenantID, fromProjectID, id); err != nil {
+			return err
+		}
+
+		if !includeInstances {
+			inUse, err := s.workflowsRepo.IsWorkflowDefinitionInUse(ctx, id)
+			if err != nil {
+				return err
+			}
+
+			if inUse {
+				return ErrInstancesNotIncluded
+			}
+		}
+
+		return s.workflowsRepo.TransferWorkflowDefinition(ctx, fromProjectID, toProjectID, id)
+	})
+}
@@ -0,0 +1,98 @@
+package workflows
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type fakeUpdateRepo struct {
+	contract.WorkflowsRepository
+	conflicts int
+	calls     int
+}
+
+func (r *fakeUpdateRepo) UpdateWorkflowDefinition(
+	_ context.Context,
+	_ domain.TenantID,
+	_ domain.ProjectID,
+	_ string,
+	_ json.RawMessage,
+) (string, error) {
+	r.calls++
+	if r.calls <= r.conflicts {
+		return "", domain.ErrEntityAlreadyExists
+	}
+
+	return fmt.Sprintf("orders-v%d", r.calls+1), nil
+}
+
+func TestService_UpdateDefinition(t *testing.T) {
+	ctx := context.Background()
+
+	repo := &fakeUpdateRepo{conflicts: 1}
+	srv := &Service{tx: fakeTx{}, workflowsRepo: repo}
+
+	id, err := srv.UpdateDefinition(ctx, 1, 1, "orders-v1", json.RawMessage(testDefinition))
+	require.NoError(t, err)
+	assert.Equal(t, "orders-v3", id)
+	assert.Equal(t, 2, repo.calls)
+
+	repo = &fakeUpdateRepo{conflicts: updateAttempts}
+	srv = &Service{tx: fakeTx{}, workflowsRepo: repo}
+
+	_, err = srv.UpdateDefinition(ctx, 1, 1, "orders-v1", json.RawMessage(testDefinition))
+	require.ErrorIs(t, err, domain.ErrEntityAlreadyExists)
+	assert.Equal(t, updateAttempts, repo.calls)
+}
+
+type allowAllPermissions struct {
+	contract.PermissionsService
+}
+
+func (allowAllPermissions) CanManageProject(context.Context, domain.ProjectID) error {
+	return nil
+}
+
+type fakeProjectsRepo struct {
+	contract.ProjectsRepository
+	tenants map[domain.ProjectID]domain.TenantID
+}
+
+func (r *fakeProjectsRepo) GetByID(_ context.Context, id domain.ProjectID) (domain.Project, error) {
+	if _, ok := r.tenants[id]; !ok {
+		return domain.Project{}, domain.ErrEntityNotFound
+	}
+
+	return domain.Project{ID: id}, nil
+}
+
+func (r *fakeProjectsRepo) ListByTenant(_ context.Context, tenantID domain.TenantID) ([]domain.Project, error) {
+	var projects []domain.Project
+	for id, projectTenantID := range r.tenants {
+		if projectTenantID == tenantID {
+			projects = append(projects, domain.Project{ID: id})
+		}
+	}
+
+	return projects, nil
+}
+
+func TestService_TransferDefinition_OtherTenant(t *testing.T) {
+	srv := &Service{
+		tx:             fakeTx{},
+		workflowsRepo:  &fakeWorkflowsRepo{},
+		projectsRepo:   &fakeProjectsRepo{tenants: map[domain.ProjectID]domain.TenantID{1: 1, 2: 2}},
+		permissionsSrv: allowAllPermissions{},
+	}
+
+	err := srv.TransferDefinition(context.Background(), 1, 1, 2, "orders-v1", true)
+	require.ErrorIs(t, err, ErrOtherTenant)
+}
@@ -0,0 +1,87 @@
+package workflows
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+var ErrVersionAlreadyActive = errors.New("version is already active")
+
+// ErrNoRollbackTarget is returned when the workflow has no version to roll back to:
+// it is not pinned or its active version was not promoted from another one.
+var ErrNoRollbackTarget = errors.New("no previous version to roll back to")
+
+// PromoteVersion makes the version of the workflow the active one in the project.
+func (s *Service) PromoteVersion(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	name string,
+	version int,
+) (domain.WorkflowActiveVersion, error) {
+	var active domain.WorkflowActiveVersion
+	err := s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		current, err := s.workflowsRepo.GetActiveVersion(ctx, projectID, name)
+		switch {
+		case err == nil:
+			if current.Version == version {
+				return ErrVersionAlreadyActive
+			}
+		case !errors.Is(err, domain.ErrEntityNotFound):
+			return fmt.Errorf("get active version: %w", err)
+		}
+
+		active, err = s.workflowsRepo.SetActiveVersion(ctx, projectID, name, version, domain.ActionPromote)
+
+		return err
+	})
+	if err != nil {
+		return domain.WorkflowActiveVersion{}, err
+	}
+
+	return active, nil
+}
+
+func (s *Service) RollbackVersion(
+	ctx context.Context,
+	projectID domain.ProjectID,
+	name string,
+	version int,
+) (domain.WorkflowActiveVersion, error) {
+	var active domain.WorkflowActiveVersion
+	err := s.tx.ReadCommitted(ctx, func(ctx context.Context) error {
+		current, err := s.workflowsRepo.GetActiveVersion(ctx, projectID, name)
+		if err != nil {
+			if errors.Is(err, domain.ErrEntityNotFound) {
+				return ErrNoRollbackTarget
+			}
+			return fmt.Errorf("get active version: %w", err)
+		}
+
+		if version == 0 {
+			// Walking back the promotions: successive rollbacks keep going to older versions
+			version, err = s.workflowsRepo.GetPromotedFromVersion(ctx, projectID, name, current.Version)
+			if err != nil {
+				if errors.Is(err, domain.ErrEntityNotFound) {
+					return ErrNoRollbackTarget
+				}
+				return fmt.Errorf("get previous version: %w", err)
+			}
+		}
+
+		if current.Version == version {
+			return ErrVersionAlreadyActive
+		}
+
+		active, err = s.workflowsRepo.SetActiveVersion(ctx, projectID, name, version, domain.ActionRollback)
+
+		return err
+	})
+	if err != nil {
+		return domain.WorkflowActiveVersion{}, err
+	}
+
+	return active, nil
+}
@@ -0,0 +1,102 @@
+package workflows
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+	"github.com/rom8726/floxy-manager/internal/domain"
+)
+
+type fakeVersionsRepo struct {
+	contract.WorkflowsRepository
+	active   map[string]int
+	rollouts []domain.WorkflowRollout
+}
+
+func (r *fakeVersionsRepo) GetActiveVersion(
+	_ context.Context,
+	_ domain.ProjectID,
+	name string,
+) (domain.WorkflowActiveVersion, error) {
+	version, ok := r.active[name]
+	if !ok {
+		return domain.WorkflowActiveVersion{}, domain.ErrEntityNotFound
+	}
+
+	return domain.WorkflowActiveVersion{WorkflowName: name, Version: version}, nil
+}
+
+func (r *fakeVersionsRepo) SetActiveVersion(
+	_ context.Context,
+	_ domain.ProjectID,
+	name string,
+	version int,
+	action string,
+) (domain.WorkflowActiveVersion, error) {
+	rollout := domain.WorkflowRollout{WorkflowName: name, ToVersion: version, Action: action}
+	if from, ok := r.active[name]; ok {
+		rollout.FromVersion = &from
+	}
+	r.rollouts = append(r.rollouts, rollout)
+	r.active[name] = version
+
+	return domain.WorkflowActiveVersion{WorkflowName: name, Version: version}, nil
+}
+
+func (r *fakeVersionsRepo) GetPromotedFromVersion(
+	_ context.Context,
+	_ domain.ProjectID,
+	name string,
+	version int,
+) (int, error) {
+	for i := len(r.rollouts) - 1; i >= 0; i-- {
+		rollout := r.rollouts[i]
+		if rollout.WorkflowName == name && rollout.ToVersion == version && rollout.Action == domain.ActionPromote {
+			if rollout.FromVersion == nil {
+				break
+			}
+
+			return *rollout.FromVersion, nil
+		}
+	}
+
+	return 0, domain.ErrEntityNotFound
+}
+
+func TestService_RollbackVersion(t *testing.T) {
+	repo := &fakeVersionsRepo{active: map[string]int{}}
+	srv := &Service{tx: fakeTx{}, workflowsRepo: repo}
+	ctx := context.Background()
+
+	_, err := srv.RollbackVersion(ctx, 1, "orders", 0)
+	require.ErrorIs(t, err, ErrNoRollbackTarget)
+
+	for _, version := range []int{1, 2, 3} {
+		_, err := srv.PromoteVersion(ctx, 1, "orders", version)
+		require.NoError(t, err)
+	}
+
+	_, err = srv.PromoteVersion(ctx, 1, "orders", 3)
+	require.ErrorIs(t, err, ErrVersionAlreadyActive)
+
+	// Successive rollbacks walk back the promotions
+	active, err := srv.RollbackVersion(ctx, 1, "orders", 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, active.Version)
+
+	active, err = srv.RollbackVersion(ctx, 1, "orders", 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, active.Version)
+
+	_, err = srv.RollbackVersion(ctx, 1, "orders", 0)
+	require.ErrorIs(t, err, ErrNoRollbackTarget)
+
+	active, err = srv.RollbackVersion(ctx, 1, "orders", 3)
+	require.NoError(t, err)
+	assert.Equal(t, 3, active.Version)
+	assert.Equal(t, domain.ActionRollback, repo.rollouts[len(repo.rollouts)-1].Action)
+}
@@ -0,0 +1,52 @@
+package workflowstats
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+)
+
+var _ contract.WorkflowStatsUseCase = (*Service)(nil)
+
+// minRefreshInterval coalesces refreshes requested at once, e.g. by several users or replicas.
+const minRefreshInterval = 5 * time.Second
+
+type Service struct {
+	workflowsRepo contract.WorkflowsRepository
+
+	mu  sync.Mutex
+	now func() time.Time
+}
+
+func New(workflowsRepo contract.WorkflowsRepository) *Service {
+	return &Service{
+		workflowsRepo: workflowsRepo,
+		now:           time.Now,
+	}
+}
+
+// Refresh recomputes the workflow statistics. A refresh requested while another one runs
+// waits for it and returns its result instead of starting a new one.
+func (s *Service) Refresh(ctx context.Context) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	refreshedAt, err := s.workflowsRepo.WorkflowStatsRefreshedAt(ctx)
+	if err == nil && s.now().Sub(refreshedAt) < minRefreshInterval {
+		return refreshedAt, nil
+	}
+
+	refreshedAt, err = s.workflowsRepo.RefreshWorkflowStats(ctx)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("refresh workflow stats: %w", err)
+	}
+
+	return refreshedAt, nil
+}
+
+func (s *Service) RefreshedAt(ctx context.Context) (time.Time, error) {
+	return s.workflowsRepo.WorkflowStatsRefreshedAt(ctx)
+}
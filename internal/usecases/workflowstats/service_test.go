@@ -0,0 +1,48 @@
+package workflowstats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rom8726/floxy-manager/internal/contract"
+)
+
+type fakeWorkflowsRepo struct {
+	contract.WorkflowsRepository
+
+	refreshedAt time.Time
+	refreshes   int
+}
+
+func (f *fakeWorkflowsRepo) RefreshWorkflowStats(context.Context) (time.Time, error) {
+	f.refreshes++
+	f.refreshedAt = f.refreshedAt.Add(time.Minute)
+
+	return f.refreshedAt, nil
+}
+
+func (f *fakeWorkflowsRepo) WorkflowStatsRefreshedAt(context.Context) (time.Time, error) {
+	return f.refreshedAt, nil
+}
+
+func TestService_Refresh(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	repo := &fakeWorkflowsRepo{refreshedAt: now.Add(-time.Minute)}
+	srv := New(repo)
+	srv.now = func() time.Time { return now }
+
+	refreshedAt, err := srv.Refresh(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, now, refreshedAt)
+	assert.Equal(t, 1, repo.refreshes)
+
+	// Refreshed moments ago, the stats are returned as they are
+	refreshedAt, err = srv.Refresh(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, now, refreshedAt)
+	assert.Equal(t, 1, repo.refreshes)
+}
@@ -0,0 +1,27 @@
+-- workflow schedules (cron triggers)
+create table if not exists workflows_manager.workflow_schedules
+(
+    id                     integer generated by default as identity
+        constraint pk_workflow_schedules primary key,
+    project_id             integer                                not null
+        references workflows_manager.projects (id) on delete cascade,
+    workflow_definition_id text                                   not null
+        references workflows.workflow_definitions (id) on delete cascade,
+    name                   varchar(255)                           not null,
+    cron_expr              varchar(255)                           not null,
+    timezone               varchar(64)              default 'UTC' not null,
+    input                  jsonb                    default '{}'  not null,
+    enabled                boolean                  default true  not null,
+    next_run_at            timestamp with time zone,
+    last_run_at            timestamp with time zone,
+    last_instance_id       bigint,
+    last_error             text,
+    created_by             workflows_manager.username             not null,
+    created_at             timestamp with time zone default now() not null,
+    updated_at             timestamp with time zone default now() not null,
+    constraint uq_workflow_schedules_project_name unique (project_id, name)
+);
+
+create index if not exists idx_workflow_schedules_due
+    on workflows_manager.workflow_schedules (next_run_at)
+    where enabled;
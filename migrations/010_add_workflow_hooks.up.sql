@@ -0,0 +1,23 @@
+-- inbound webhook triggers
+create table if not exists workflows_manager.workflow_hooks
+(
+    id                     integer generated by default as identity
+        constraint pk_workflow_hooks primary key,
+    project_id             integer                                not null
+        references workflows_manager.projects (id) on delete cascade,
+    workflow_definition_id text                                   not null
+        references workflows.workflow_definitions (id) on delete cascade,
+    name                   varchar(255)                           not null,
+    token                  varchar(64)                            not null,
+    secret_encrypted       text                                   not null,
+    require_signature      boolean                  default true  not null,
+    rate_limit_per_minute  integer                  default 60    not null,
+    enabled                boolean                  default true  not null,
+    last_triggered_at      timestamp with time zone,
+    created_by             workflows_manager.username             not null,
+    created_at             timestamp with time zone default now() not null,
+    updated_at             timestamp with time zone default now() not null,
+    constraint uq_workflow_hooks_token unique (token),
+    constraint uq_workflow_hooks_project_name unique (project_id, name),
+    constraint ck_workflow_hooks_rate_limit check (rate_limit_per_minute > 0)
+);
@@ -0,0 +1,52 @@
+-- outbound webhook notifications for workflow lifecycle events
+create table if not exists workflows_manager.notification_webhooks
+(
+    id         integer generated by default as identity
+        constraint pk_notification_webhooks primary key,
+    project_id integer                                not null
+        references workflows_manager.projects (id) on delete cascade,
+    name       varchar(255)                           not null,
+    url        text                                   not null,
+    events     text[]                                 not null,
+    enabled    boolean                  default true  not null,
+    created_by workflows_manager.username             not null,
+    created_at timestamp with time zone default now() not null,
+    updated_at timestamp with time zone default now() not null,
+    constraint uq_notification_webhooks_project_name unique (project_id, name)
+);
+
+create table if not exists workflows_manager.notification_deliveries
+(
+    id               bigint generated by default as identity
+        constraint pk_notification_deliveries primary key,
+    webhook_id       integer                                    not null
+        references workflows_manager.notification_webhooks (id) on delete cascade,
+    event_type       varchar(64)                                not null,
+    event_key        varchar(255)                               not null,
+    payload          jsonb                                      not null,
+    status           varchar(16)              default 'pending' not null
+        constraint chk_notification_deliveries_status check (status in ('pending', 'delivered', 'failed')),
+    attempts         integer                  default 0         not null,
+    next_attempt_at  timestamp with time zone,
+    last_status_code integer,
+    last_error       text,
+    created_at       timestamp with time zone default now()     not null,
+    delivered_at     timestamp with time zone,
+    constraint uq_notification_deliveries_event unique (webhook_id, event_key)
+);
+
+create index if not exists idx_notification_deliveries_due
+    on workflows_manager.notification_deliveries (next_attempt_at)
+    where status = 'pending';
+
+create index if not exists idx_notification_deliveries_webhook
+    on workflows_manager.notification_deliveries (webhook_id, created_at desc);
+
+-- positions of background event readers over the floxy tables
+create table if not exists workflows_manager.event_cursors
+(
+    name       varchar(64)              not null
+        constraint pk_event_cursors primary key,
+    position   timestamp with time zone not null,
+    updated_at timestamp with time zone default now() not null
+);
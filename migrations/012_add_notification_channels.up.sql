@@ -0,0 +1,47 @@
+-- Slack / Microsoft Teams notification channels
+create table if not exists workflows_manager.notification_channels
+(
+    id                             integer generated by default as identity
+        constraint pk_notification_channels primary key,
+    project_id                     integer                                not null
+        references workflows_manager.projects (id) on delete cascade,
+    name                           varchar(255)                           not null,
+    kind                           varchar(16)                            not null
+        constraint chk_notification_channels_kind check (kind in ('slack', 'teams')),
+    webhook_url_encrypted          text                                   not null,
+    channel                        varchar(255),
+    events                         text[]                                 not null,
+    long_running_threshold_seconds integer                  default 3600  not null
+        constraint chk_notification_channels_threshold check (long_running_threshold_seconds > 0),
+    enabled                        boolean                  default true  not null,
+    created_by                     workflows_manager.username             not null,
+    created_at                     timestamp with time zone default now() not null,
+    updated_at                     timestamp with time zone default now() not null,
+    constraint uq_notification_channels_project_name unique (project_id, name)
+);
+
+create table if not exists workflows_manager.notification_channel_messages
+(
+    id              bigint generated by default as identity
+        constraint pk_notification_channel_messages primary key,
+    channel_id      integer                                    not null
+        references workflows_manager.notification_channels (id) on delete cascade,
+    event_type      varchar(64)                                not null,
+    event_key       varchar(255)                               not null,
+    event           jsonb                                      not null,
+    status          varchar(16)              default 'pending' not null
+        constraint chk_notification_channel_messages_status check (status in ('pending', 'sent', 'failed')),
+    attempts        integer                  default 0         not null,
+    next_attempt_at timestamp with time zone,
+    last_error      text,
+    created_at      timestamp with time zone default now()     not null,
+    sent_at         timestamp with time zone,
+    constraint uq_notification_channel_messages_event unique (channel_id, event_key)
+);
+
+create index if not exists idx_notification_channel_messages_due
+    on workflows_manager.notification_channel_messages (next_attempt_at)
+    where status = 'pending';
+
+create index if not exists idx_notification_channel_messages_channel
+    on workflows_manager.notification_channel_messages (channel_id, created_at desc);
@@ -0,0 +1,32 @@
+-- email alerts for failed and long running workflow instances
+create table if not exists workflows_manager.project_alert_settings
+(
+    project_id                 integer                                          not null
+        constraint pk_project_alert_settings primary key
+        references workflows_manager.projects (id) on delete cascade,
+    enabled                    boolean                  default false           not null,
+    recipient_role_key         varchar(64)              default 'project_owner' not null,
+    notify_on_failure          boolean                  default true            not null,
+    duration_threshold_seconds integer
+        constraint chk_project_alert_settings_threshold check (duration_threshold_seconds > 0),
+    digest_enabled             boolean                  default false           not null,
+    last_digest_at             timestamp with time zone,
+    updated_at                 timestamp with time zone default now()           not null
+);
+
+create table if not exists workflows_manager.project_alerts
+(
+    id         bigint generated by default as identity
+        constraint pk_project_alerts primary key,
+    project_id integer                                not null
+        references workflows_manager.projects (id) on delete cascade,
+    event_key  varchar(255)                           not null,
+    event      jsonb                                  not null,
+    created_at timestamp with time zone default now() not null,
+    sent_at    timestamp with time zone,
+    constraint uq_project_alerts_event unique (project_id, event_key)
+);
+
+create index if not exists idx_project_alerts_pending
+    on workflows_manager.project_alerts (project_id, created_at)
+    where sent_at is null;
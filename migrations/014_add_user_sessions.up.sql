@@ -0,0 +1,17 @@
+-- login sessions backing refresh tokens
+create table if not exists workflows_manager.user_sessions
+(
+    id           uuid                     default uuid_generate_v4() not null
+        constraint pk_user_sessions primary key,
+    user_id      integer                                             not null
+        references workflows_manager.users (id) on delete cascade,
+    user_agent   varchar(512)             default ''                 not null,
+    ip_address   varchar(64)              default ''                 not null,
+    created_at   timestamp with time zone default now()              not null,
+    last_used_at timestamp with time zone default now()              not null,
+    expires_at   timestamp with time zone                            not null,
+    revoked_at   timestamp with time zone
+);
+
+create index if not exists idx_user_sessions_user_id
+    on workflows_manager.user_sessions (user_id, created_at desc);
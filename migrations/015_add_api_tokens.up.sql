@@ -0,0 +1,17 @@
+-- personal access tokens for non-interactive API access
+create table if not exists workflows_manager.api_tokens
+(
+    id           integer generated by default as identity
+        constraint pk_api_tokens primary key,
+    user_id      integer                                not null
+        references workflows_manager.users (id) on delete cascade,
+    name         varchar(128)                           not null,
+    token_hash   varchar(64)                            not null
+        constraint uq_api_tokens_hash unique,
+    token_prefix varchar(16)                            not null,
+    scopes       text[]                                 not null,
+    expires_at   timestamp with time zone,
+    last_used_at timestamp with time zone,
+    created_at   timestamp with time zone default now() not null,
+    constraint uq_api_tokens_user_name unique (user_id, name)
+);
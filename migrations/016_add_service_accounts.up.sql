@@ -0,0 +1,13 @@
+-- service accounts: non-human users bound to a single project, authenticated by API tokens only
+alter table workflows_manager.users
+    add column if not exists is_service_account boolean default false not null,
+    add column if not exists service_project_id integer
+        references workflows_manager.projects (id) on delete cascade;
+
+alter table workflows_manager.users
+    add constraint chk_users_service_account_project
+        check (not is_service_account or service_project_id is not null);
+
+create index if not exists idx_users_service_project_id
+    on workflows_manager.users (service_project_id)
+    where is_service_account;
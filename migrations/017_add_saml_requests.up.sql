@@ -0,0 +1,13 @@
+-- pending SAML AuthnRequests keyed by RelayState, shared by all replicas
+create table if not exists workflows_manager.saml_requests
+(
+    state      varchar(128)                           not null
+        constraint pk_saml_requests primary key,
+    provider   varchar(64)                            not null,
+    request_id varchar(128)                           not null,
+    created_at timestamp with time zone default now() not null,
+    expires_at timestamp with time zone               not null
+);
+
+create index if not exists idx_saml_requests_expires_at
+    on workflows_manager.saml_requests (expires_at);
@@ -0,0 +1,3 @@
+-- source of a project membership: added manually or managed by LDAP group mappings
+alter table workflows_manager.memberships
+    add column if not exists source varchar(16) default 'manual' not null;
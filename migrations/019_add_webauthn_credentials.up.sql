@@ -0,0 +1,17 @@
+-- security keys (WebAuthn/FIDO2) registered as a second factor
+create table if not exists workflows_manager.webauthn_credentials
+(
+    id            integer generated by default as identity
+        constraint pk_webauthn_credentials primary key,
+    user_id       integer                                not null
+        references workflows_manager.users (id) on delete cascade,
+    name          varchar(128)                           not null,
+    credential_id bytea                                  not null
+        constraint uq_webauthn_credentials_credential_id unique,
+    credential    jsonb                                  not null,
+    last_used_at  timestamp with time zone,
+    created_at    timestamp with time zone default now() not null
+);
+
+create index if not exists idx_webauthn_credentials_user_id
+    on workflows_manager.webauthn_credentials (user_id);
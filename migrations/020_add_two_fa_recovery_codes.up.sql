@@ -0,0 +1,12 @@
+-- one-time recovery codes for users who lost their TOTP authenticator
+create table if not exists workflows_manager.two_fa_recovery_codes
+(
+    id         integer generated by default as identity
+        constraint pk_two_fa_recovery_codes primary key,
+    user_id    integer                                not null
+        references workflows_manager.users (id) on delete cascade,
+    code_hash  varchar(64)                            not null,
+    used_at    timestamp with time zone,
+    created_at timestamp with time zone default now() not null,
+    constraint uq_two_fa_recovery_codes_user_hash unique (user_id, code_hash)
+);
@@ -0,0 +1,7 @@
+-- email verification; existing users are treated as verified
+alter table workflows_manager.users
+    add column if not exists email_verified_at timestamp with time zone;
+
+update workflows_manager.users
+set email_verified_at = created_at
+where email_verified_at is null;
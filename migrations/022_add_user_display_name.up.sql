@@ -0,0 +1,3 @@
+-- optional human-readable name shown instead of the username
+alter table workflows_manager.users
+    add column if not exists display_name varchar(255) not null default '';
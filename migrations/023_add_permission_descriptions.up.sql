@@ -0,0 +1,14 @@
+-- human-readable descriptions of what each permission grants
+alter table workflows_manager.permissions
+    add column if not exists description text not null default '';
+
+update workflows_manager.permissions
+set description = case key
+    when 'project.view' then 'View the project, its workflows, instances and schedules'
+    when 'project.manage' then 'Change project settings and manage workflows, schedules, hooks and alerts'
+    when 'project.create' then 'Create new projects'
+    when 'workflow.create' then 'Create and update workflow definitions in the project'
+    when 'audit.view' then 'View the project audit log'
+    when 'membership.manage' then 'Add, change and remove project members'
+    else description
+end;
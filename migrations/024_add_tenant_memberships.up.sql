@@ -0,0 +1,39 @@
+-- tenant-level memberships grant a role in every project of the tenant
+create table if not exists workflows_manager.tenant_memberships
+(
+    id         integer generated by default as identity
+        constraint pk_tenant_memberships primary key,
+    tenant_id  integer                                not null,
+    user_id    integer                                not null,
+    role_id    uuid                                   not null,
+    created_at timestamp with time zone default now() not null,
+    constraint uq_tenant_membership_tenant_user unique (tenant_id, user_id),
+    constraint fk_tenant_memberships_tenant
+        foreign key (tenant_id) references workflows_manager.tenants (id) on delete cascade,
+    constraint fk_tenant_memberships_user
+        foreign key (user_id) references workflows_manager.users (id) on delete cascade,
+    constraint fk_tenant_memberships_role
+        foreign key (role_id) references workflows_manager.roles (id) on delete restrict
+);
+
+create index if not exists idx_tenant_memberships_user_id on workflows_manager.tenant_memberships (user_id);
+
+insert into workflows_manager.roles (id, key, name, description)
+values ('5f0b6c2e-8d1a-4c3b-9e7f-2a4d6b8c0e1f', 'tenant_admin', 'Tenant Admin', 'Full control of every project in the tenant'),
+       ('7a9c1e3f-5b2d-4f6a-8c0e-1d3f5b7a9c2e', 'tenant_viewer', 'Tenant Viewer', 'Read-only access to every project in the tenant')
+on conflict (key) do nothing;
+
+insert into workflows_manager.role_permissions (role_id, permission_id)
+select r.id, p.id
+from workflows_manager.roles r
+join workflows_manager.permissions p
+    on p.key in ('project.view', 'project.manage', 'workflow.create', 'audit.view', 'membership.manage')
+where r.key = 'tenant_admin'
+on conflict (role_id, permission_id) do nothing;
+
+insert into workflows_manager.role_permissions (role_id, permission_id)
+select r.id, p.id
+from workflows_manager.roles r
+join workflows_manager.permissions p on p.key = 'project.view'
+where r.key = 'tenant_viewer'
+on conflict (role_id, permission_id) do nothing;
@@ -0,0 +1,39 @@
+-- global roles delegate administration without superuser rights;
+-- their permissions apply globally and in every project
+insert into workflows_manager.permissions (id, key, name, description)
+values ('3c5e7a9b-1d2f-4a6c-8e0b-9f1a3c5e7d2b', 'user.manage', 'Manage users',
+        'Create, edit, deactivate and delete users that are not superusers')
+on conflict (key) do nothing;
+
+insert into workflows_manager.roles (id, key, name, description)
+values ('9e1f3a5c-7b2d-4c8e-a0f2-4b6d8e0a2c4f', 'global_user_manager', 'User Manager', 'Manage users without superuser rights'),
+       ('2b4d6f8a-0c1e-4a3b-9d5f-7e9a1c3b5d6e', 'global_auditor', 'Auditor', 'Read-only access to every project and its audit log')
+on conflict (key) do nothing;
+
+insert into workflows_manager.role_permissions (role_id, permission_id)
+select r.id, p.id
+from workflows_manager.roles r
+join workflows_manager.permissions p on p.key = 'user.manage'
+where r.key = 'global_user_manager'
+on conflict (role_id, permission_id) do nothing;
+
+insert into workflows_manager.role_permissions (role_id, permission_id)
+select r.id, p.id
+from workflows_manager.roles r
+join workflows_manager.permissions p on p.key in ('project.view', 'audit.view')
+where r.key = 'global_auditor'
+on conflict (role_id, permission_id) do nothing;
+
+create table if not exists workflows_manager.global_role_assignments
+(
+    id         integer generated by default as identity
+        constraint pk_global_role_assignments primary key,
+    user_id    integer                                not null,
+    role_id    uuid                                   not null,
+    created_at timestamp with time zone default now() not null,
+    constraint uq_global_role_assignment_user_role unique (user_id, role_id),
+    constraint fk_global_role_assignments_user
+        foreign key (user_id) references workflows_manager.users (id) on delete cascade,
+    constraint fk_global_role_assignments_role
+        foreign key (role_id) references workflows_manager.roles (id) on delete restrict
+);
@@ -0,0 +1,7 @@
+-- time-bound project memberships stop granting access after valid_until and are then removed by the notifier
+alter table workflows_manager.memberships
+    add column if not exists valid_until timestamp with time zone;
+
+create index if not exists idx_memberships_valid_until
+    on workflows_manager.memberships (valid_until)
+    where valid_until is not null;
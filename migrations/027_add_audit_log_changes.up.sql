@@ -0,0 +1,3 @@
+-- before/after values of changed fields for update entries, secrets redacted
+alter table workflows_manager.audit_log
+    add column if not exists changes jsonb;
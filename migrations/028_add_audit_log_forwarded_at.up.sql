@@ -0,0 +1,12 @@
+-- entries with forwarded_at null are still to be passed to external audit sinks (SIEM)
+alter table workflows_manager.audit_log
+    add column if not exists forwarded_at timestamp with time zone;
+
+-- the history written before sinks existed is not forwarded
+update workflows_manager.audit_log
+set forwarded_at = created_at
+where forwarded_at is null;
+
+create index if not exists idx_audit_log_not_forwarded
+    on workflows_manager.audit_log (id)
+    where forwarded_at is null;
@@ -0,0 +1,3 @@
+-- HTTP status of the request that performed the action, for entries written by the audit middleware
+alter table workflows_manager.audit_log
+    add column if not exists status_code integer;
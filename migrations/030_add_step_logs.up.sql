@@ -0,0 +1,12 @@
+-- output attached to workflow steps by workers; a step log is the concatenation of its chunks.
+-- No foreign key to workflows.workflow_steps: the engine may repartition that table.
+create table if not exists workflows_manager.step_logs
+(
+    id           bigint generated by default as identity
+        constraint pk_step_logs primary key,
+    step_id      bigint                                 not null,
+    start_offset bigint                                 not null,
+    content      bytea                                  not null,
+    created_at   timestamp with time zone default now() not null,
+    constraint uq_step_logs_step_offset unique (step_id, start_offset)
+);
@@ -0,0 +1,14 @@
+-- indexes for searching workflow instances by payload: jsonb containment and substring (trigram) search
+create extension if not exists pg_trgm;
+
+create index if not exists idx_workflow_instances_input_jsonb
+    on workflows.workflow_instances using gin (input jsonb_path_ops);
+create index if not exists idx_workflow_instances_output_jsonb
+    on workflows.workflow_instances using gin (output jsonb_path_ops);
+
+create index if not exists idx_workflow_instances_input_trgm
+    on workflows.workflow_instances using gin ((input::text) gin_trgm_ops);
+create index if not exists idx_workflow_instances_output_trgm
+    on workflows.workflow_instances using gin ((output::text) gin_trgm_ops);
+create index if not exists idx_workflow_instances_error_trgm
+    on workflows.workflow_instances using gin (error gin_trgm_ops);
@@ -0,0 +1,29 @@
+-- active workflow versions: new instances of a pinned workflow start on its active version
+create table if not exists workflows_manager.workflow_active_versions
+(
+    project_id    integer                                not null
+        references workflows_manager.projects (id) on delete cascade,
+    workflow_name text                                   not null,
+    version       integer                                not null,
+    updated_by    workflows_manager.username             not null,
+    updated_at    timestamp with time zone default now() not null,
+    constraint pk_workflow_active_versions primary key (project_id, workflow_name)
+);
+
+-- history of the promotions and rollbacks of active versions
+create table if not exists workflows_manager.workflow_version_rollouts
+(
+    id            integer generated by default as identity
+        constraint pk_workflow_version_rollouts primary key,
+    project_id    integer                                not null
+        references workflows_manager.projects (id) on delete cascade,
+    workflow_name text                                   not null,
+    from_version  integer,
+    to_version    integer                                not null,
+    action        varchar(16)                            not null,
+    created_by    workflows_manager.username             not null,
+    created_at    timestamp with time zone default now() not null
+);
+
+create index if not exists idx_workflow_version_rollouts_workflow
+    on workflows_manager.workflow_version_rollouts (project_id, workflow_name, id desc);
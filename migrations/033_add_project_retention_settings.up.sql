@@ -0,0 +1,18 @@
+-- per-project retention of workflow instances and events, applied by the retention runner
+create table if not exists workflows_manager.project_retention_settings
+(
+    project_id             integer                                not null
+        constraint pk_project_retention_settings primary key
+        references workflows_manager.projects (id) on delete cascade,
+    completed_days         integer
+        constraint chk_project_retention_settings_completed check (completed_days > 0),
+    failed_days            integer
+        constraint chk_project_retention_settings_failed check (failed_days > 0),
+    events_days            integer
+        constraint chk_project_retention_settings_events check (events_days > 0),
+    last_run_at            timestamp with time zone,
+    last_deleted_instances bigint                   default 0     not null,
+    last_deleted_events    bigint                   default 0     not null,
+    last_error             text,
+    updated_at             timestamp with time zone default now() not null
+);
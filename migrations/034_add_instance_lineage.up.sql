@@ -0,0 +1,31 @@
+-- lineage of re-run workflow instances.
+-- No foreign keys to workflows.workflow_instances: the engine may repartition that table.
+create table if not exists workflows_manager.instance_lineage
+(
+    instance_id        bigint                                 not null
+        constraint pk_instance_lineage primary key,
+    parent_instance_id bigint                                 not null,
+    created_by         workflows_manager.username             not null,
+    created_at         timestamp with time zone default now() not null
+);
+
+create index if not exists idx_instance_lineage_parent
+    on workflows_manager.instance_lineage (parent_instance_id);
+
+drop view if exists workflows_manager.v_workflow_instances;
+
+create view workflows_manager.v_workflow_instances as
+select
+    p.tenant_id,
+    pw.project_id,
+    wi.*,
+    il.parent_instance_id
+from workflows.workflow_instances wi
+         join workflows.workflow_definitions wd
+              on wd.id = wi.workflow_id
+         join workflows_manager.project_workflows pw
+              on pw.workflow_definition_id = wd.id
+         join workflows_manager.projects p
+              on p.id = pw.project_id
+         left join workflows_manager.instance_lineage il
+              on il.instance_id = wi.id;
@@ -0,0 +1,55 @@
+-- paused workflow instances. The engine has no pause: while an instance is held, its steps are
+-- diverted from workflows.workflow_queue into held_queue_items so that workers do not dispatch them,
+-- and they are queued again on resume. Steps already running are left to finish.
+create table if not exists workflows_manager.instance_holds
+(
+    instance_id bigint                                 not null
+        constraint pk_instance_holds primary key,
+    project_id  integer                                not null
+        references workflows_manager.projects (id) on delete cascade,
+    reason      text                                   not null default '',
+    created_by  workflows_manager.username             not null,
+    created_at  timestamp with time zone default now() not null
+);
+
+create index if not exists idx_instance_holds_project
+    on workflows_manager.instance_holds (project_id);
+
+create table if not exists workflows_manager.held_queue_items
+(
+    id           bigint                                 not null
+        constraint pk_held_queue_items primary key,
+    instance_id  bigint                                 not null,
+    step_id      bigint,
+    scheduled_at timestamp with time zone               not null,
+    priority     integer                                not null,
+    held_at      timestamp with time zone default now() not null
+);
+
+create index if not exists idx_held_queue_items_instance
+    on workflows_manager.held_queue_items (instance_id);
+
+-- steps queued by the engine for a held instance are kept aside.
+-- Recreating workflows.workflow_queue (e.g. by engine migrations) drops the trigger.
+create or replace function workflows_manager.hold_queue_item() returns trigger
+    language plpgsql
+as
+$$
+begin
+    if exists (select 1 from workflows_manager.instance_holds where instance_id = new.instance_id) then
+        insert into workflows_manager.held_queue_items (id, instance_id, step_id, scheduled_at, priority)
+        values (new.id, new.instance_id, new.step_id, new.scheduled_at, new.priority);
+
+        return null;
+    end if;
+
+    return new;
+end;
+$$;
+
+drop trigger if exists trg_workflow_queue_hold on workflows.workflow_queue;
+create trigger trg_workflow_queue_hold
+    before insert
+    on workflows.workflow_queue
+    for each row
+execute function workflows_manager.hold_queue_item();
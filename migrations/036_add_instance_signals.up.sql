@@ -0,0 +1,24 @@
+-- external events delivered to waiting steps of workflow instances.
+-- The idempotency key is unique per instance, a repeated delivery returns the recorded signal.
+create table if not exists workflows_manager.instance_signals
+(
+    id              bigserial
+        constraint pk_instance_signals primary key,
+    instance_id     bigint                                 not null,
+    project_id      integer                                not null
+        references workflows_manager.projects (id) on delete cascade,
+    step_id         bigint                                 not null,
+    name            varchar(255)                           not null,
+    payload         jsonb,
+    decision        varchar(20)                            not null,
+    idempotency_key varchar(255),
+    created_by      workflows_manager.username             not null,
+    created_at      timestamp with time zone default now() not null
+);
+
+create unique index if not exists uq_instance_signals_idempotency_key
+    on workflows_manager.instance_signals (instance_id, idempotency_key)
+    where idempotency_key is not null;
+
+create index if not exists idx_instance_signals_instance
+    on workflows_manager.instance_signals (instance_id);
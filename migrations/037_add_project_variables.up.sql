@@ -0,0 +1,15 @@
+-- project variables injected into workflow inputs at start time.
+-- Values are encrypted with the manager secret key (AES-GCM).
+create table if not exists workflows_manager.project_variables
+(
+    project_id      integer                                not null
+        references workflows_manager.projects (id) on delete cascade,
+    name            varchar(128)                           not null,
+    value_encrypted text                                   not null,
+    secret          boolean                  default true  not null,
+    created_by      workflows_manager.username             not null,
+    created_at      timestamp with time zone default now() not null,
+    updated_by      workflows_manager.username             not null,
+    updated_at      timestamp with time zone default now() not null,
+    constraint pk_project_variables primary key (project_id, name)
+);
@@ -0,0 +1,10 @@
+-- JSONPath patterns of the instance, step, event and DLQ payload values masked in API responses
+create table if not exists workflows_manager.project_redaction_rules
+(
+    project_id integer                                not null
+        constraint pk_project_redaction_rules primary key
+        references workflows_manager.projects (id) on delete cascade,
+    paths      text[]                                 not null,
+    updated_by workflows_manager.username             not null,
+    updated_at timestamp with time zone default now() not null
+);
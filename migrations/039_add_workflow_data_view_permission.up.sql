@@ -0,0 +1,13 @@
+-- workflow.data.view grants access to the raw input and output payloads of instances
+insert into workflows_manager.permissions (id, key, name, description)
+values ('6d8f0b2c-4e6a-4c1d-9b3f-5a7c9e1d3f5b', 'workflow.data.view', 'View workflow data',
+        'View the input, output and event payloads of workflow instances and DLQ items')
+on conflict (key) do nothing;
+
+-- Roles that could see the payloads keep seeing them, the permission can then be revoked per role
+insert into workflows_manager.role_permissions (role_id, permission_id)
+select rp.role_id, p.id
+from workflows_manager.role_permissions rp
+join workflows_manager.permissions v on v.id = rp.permission_id and v.key = 'project.view'
+join workflows_manager.permissions p on p.key = 'workflow.data.view'
+on conflict (role_id, permission_id) do nothing;
@@ -0,0 +1,7 @@
+-- audit entries outlive their project: deleting a project clears their project reference
+alter table workflows_manager.audit_log
+    drop constraint if exists audit_log_project_id_fkey;
+
+alter table workflows_manager.audit_log
+    add constraint audit_log_project_id_fkey
+        foreign key (project_id) references workflows_manager.projects (id) on delete set null;
@@ -0,0 +1,10 @@
+-- settings of a project as one JSON document, unset values fall back to their defaults
+create table if not exists workflows_manager.project_settings
+(
+    project_id integer                                not null
+        constraint pk_project_settings primary key
+        references workflows_manager.projects (id) on delete cascade,
+    settings   jsonb                    default '{}'  not null,
+    updated_by workflows_manager.username             not null,
+    updated_at timestamp with time zone default now() not null
+);
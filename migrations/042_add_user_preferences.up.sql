@@ -0,0 +1,13 @@
+-- UI preferences of users, previously kept by the SPA in the local storage of the browser
+create table if not exists workflows_manager.user_preferences
+(
+    user_id               integer                                  not null
+        constraint pk_user_preferences primary key
+        references workflows_manager.users (id) on delete cascade,
+    default_project_id    integer
+        references workflows_manager.projects (id) on delete set null,
+    locale                varchar(35)              default 'en'    not null,
+    items_per_page        integer                  default 20      not null,
+    notification_opt_outs varchar(64)[]            default '{}'    not null,
+    updated_at            timestamp with time zone default now()   not null
+);
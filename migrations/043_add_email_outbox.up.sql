@@ -0,0 +1,23 @@
+-- emails queued for the background sender, failed ones are kept for resend
+create table if not exists workflows_manager.email_outbox
+(
+    id              bigint generated by default as identity
+        constraint pk_email_outbox primary key,
+    recipient       varchar(255)                               not null,
+    subject         varchar(512)                               not null,
+    body            text                                       not null,
+    status          varchar(16)              default 'pending' not null
+        constraint chk_email_outbox_status check (status in ('pending', 'sent', 'failed')),
+    attempts        integer                  default 0         not null,
+    next_attempt_at timestamp with time zone,
+    last_error      text,
+    created_at      timestamp with time zone default now()     not null,
+    sent_at         timestamp with time zone
+);
+
+create index if not exists idx_email_outbox_due
+    on workflows_manager.email_outbox (next_attempt_at)
+    where status = 'pending';
+
+create index if not exists idx_email_outbox_status
+    on workflows_manager.email_outbox (status, created_at desc);
@@ -0,0 +1,3 @@
+-- the ID (jti) of the only refresh token of a session that may still be used; NULL for sessions created before rotation
+alter table workflows_manager.user_sessions
+    add column if not exists refresh_token_id varchar(64);
@@ -0,0 +1,31 @@
+-- workflow statistics are aggregated by a background refresh instead of on every request;
+-- the unique index lets the refresh run concurrently with reads
+create materialized view if not exists workflows_manager.mv_workflow_stats as
+select ws.*
+from workflows.workflow_stats ws;
+
+create unique index if not exists mv_workflow_stats_name_version_idx
+    on workflows_manager.mv_workflow_stats (name, version);
+
+create table if not exists workflows_manager.materialized_view_refreshes
+(
+    name         varchar(100) primary key,
+    refreshed_at timestamptz not null
+);
+
+insert into workflows_manager.materialized_view_refreshes (name, refreshed_at)
+values ('mv_workflow_stats', now())
+on conflict (name) do nothing;
+
+create or replace view workflows_manager.v_workflow_stats as
+select
+    p.tenant_id,
+    pw.project_id,
+    ms.*
+from workflows_manager.mv_workflow_stats ms
+         join workflows.workflow_definitions wd
+              on wd.name = ms.name and wd.version = ms.version
+         join workflows_manager.project_workflows pw
+              on pw.workflow_definition_id = wd.id
+         join workflows_manager.projects p
+              on p.id = pw.project_id;
@@ -0,0 +1,19 @@
+-- responses of mutating requests sent with an Idempotency-Key header, replayed on retries until they expire;
+-- status_code is NULL while the first request is in progress
+create table if not exists workflows_manager.idempotency_keys
+(
+    user_id         bigint                                 not null,
+    method          varchar(16)                            not null,
+    path            varchar(1024)                          not null,
+    idempotency_key varchar(255)                           not null,
+    request_hash    varchar(64)                            not null,
+    status_code     integer,
+    content_type    varchar(255)                           not null default '',
+    response_body   bytea,
+    created_at      timestamp with time zone default now() not null,
+    expires_at      timestamp with time zone               not null,
+    constraint pk_idempotency_keys primary key (user_id, method, path, idempotency_key)
+);
+
+create index if not exists idx_idempotency_keys_expires_at
+    on workflows_manager.idempotency_keys (expires_at);
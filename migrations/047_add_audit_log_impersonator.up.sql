@@ -0,0 +1,3 @@
+-- the superuser acting as the user for actions performed with an impersonation token
+alter table workflows_manager.audit_log
+    add column if not exists impersonator workflows_manager.username;
@@ -0,0 +1,4 @@
+-- the version of the license agreement accepted by the user and when it was accepted
+alter table workflows_manager.users
+    add column if not exists license_version varchar(64) not null default '',
+    add column if not exists license_accepted_at timestamptz;
@@ -0,0 +1,3 @@
+-- the activation claimed by the last run of the schedule, so an activation starts at most one instance
+alter table workflows_manager.workflow_schedules
+    add column if not exists last_run_key varchar(64);
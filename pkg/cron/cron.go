@@ -0,0 +1,261 @@
+// Package cron parses standard five-field cron expressions and computes their activation times.
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSearchYears limits the search for the next activation time (e.g. for "0 0 30 2 *").
+const maxSearchYears = 5
+
+var ErrNoActivation = errors.New("cron expression has no activation time")
+
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var monthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var dayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+type field struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField = field{min: 0, max: 59}
+	hourField   = field{min: 0, max: 23}
+	domField    = field{min: 1, max: 31}
+	monthField  = field{min: 1, max: 12, names: monthNames}
+	dowField    = field{min: 0, max: 7, names: dayNames}
+)
+
+// Schedule is a parsed cron expression: minute, hour, day of month, month, day of week.
+type Schedule struct {
+	minutes  uint64
+	hours    uint64
+	dom      uint64
+	months   uint64
+	dow      uint64
+	domStar  bool
+	dowStar  bool
+	location *time.Location
+}
+
+// Parse parses a cron expression in the given location (UTC if nil).
+func Parse(expr string, loc *time.Location) (*Schedule, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	expr = strings.TrimSpace(expr)
+	if macro, ok := macros[strings.ToLower(expr)]; ok {
+		expr = macro
+	}
+
+	parts := strings.Fields(expr)
+	if len(parts) != 5 {
+		return nil, fmt.Errorf("expected 5 fields, got %d", len(parts))
+	}
+
+	schedule := &Schedule{location: loc}
+
+	var err error
+	if schedule.minutes, err = parseField(parts[0], minuteField); err != nil {
+		return nil, fmt.Errorf("minute: %w", err)
+	}
+	if schedule.hours, err = parseField(parts[1], hourField); err != nil {
+		return nil, fmt.Errorf("hour: %w", err)
+	}
+	if schedule.dom, err = parseField(parts[2], domField); err != nil {
+		return nil, fmt.Errorf("day of month: %w", err)
+	}
+	if schedule.months, err = parseField(parts[3], monthField); err != nil {
+		return nil, fmt.Errorf("month: %w", err)
+	}
+	if schedule.dow, err = parseField(parts[4], dowField); err != nil {
+		return nil, fmt.Errorf("day of week: %w", err)
+	}
+
+	// 7 is an alias for Sunday
+	if schedule.dow&(1<<7) != 0 {
+		schedule.dow |= 1
+	}
+
+	schedule.domStar = parts[2] == "*" || parts[2] == "?"
+	schedule.dowStar = parts[4] == "*" || parts[4] == "?"
+
+	return schedule, nil
+}
+
+// Next returns the first activation time strictly after t.
+func (s *Schedule) Next(t time.Time) (time.Time, error) {
+	t = t.In(s.location).Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(maxSearchYears, 0, 0)
+
+	for t.Before(limit) {
+		if !has(s.months, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, s.location)
+
+			continue
+		}
+
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, s.location)
+
+			continue
+		}
+
+		if !has(s.hours, t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, s.location)
+
+			continue
+		}
+
+		if !has(s.minutes, t.Minute()) {
+			t = t.Add(time.Minute)
+
+			continue
+		}
+
+		return t, nil
+	}
+
+	return time.Time{}, ErrNoActivation
+}
+
+// NextN returns up to n consecutive activation times after t.
+func (s *Schedule) NextN(t time.Time, n int) ([]time.Time, error) {
+	result := make([]time.Time, 0, n)
+	for range n {
+		next, err := s.Next(t)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, next)
+		t = next
+	}
+
+	return result, nil
+}
+
+// dayMatches applies the classic cron rule: when both day of month and day of week
+// are restricted, a day matches if either of them matches.
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domMatch := has(s.dom, t.Day())
+	dowMatch := has(s.dow, int(t.Weekday()))
+
+	switch {
+	case s.domStar && s.dowStar:
+		return true
+	case s.domStar:
+		return dowMatch
+	case s.dowStar:
+		return domMatch
+	default:
+		return domMatch || dowMatch
+	}
+}
+
+func parseField(value string, f field) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(value, ",") {
+		partBits, err := parseRange(part, f)
+		if err != nil {
+			return 0, err
+		}
+		bits |= partBits
+	}
+
+	return bits, nil
+}
+
+func parseRange(value string, f field) (uint64, error) {
+	step := 1
+	rangePart := value
+
+	if idx := strings.Index(value, "/"); idx >= 0 {
+		var err error
+		step, err = strconv.Atoi(value[idx+1:])
+		if err != nil || step <= 0 {
+			return 0, fmt.Errorf("invalid step in %q", value)
+		}
+		rangePart = value[:idx]
+	}
+
+	var start, end int
+	switch {
+	case rangePart == "*" || rangePart == "?":
+		start, end = f.min, f.max
+	case strings.Contains(rangePart, "-"):
+		bounds := strings.SplitN(rangePart, "-", 2)
+
+		var err error
+		if start, err = parseValue(bounds[0], f); err != nil {
+			return 0, err
+		}
+		if end, err = parseValue(bounds[1], f); err != nil {
+			return 0, err
+		}
+	default:
+		var err error
+		if start, err = parseValue(rangePart, f); err != nil {
+			return 0, err
+		}
+
+		end = start
+		if strings.Contains(value, "/") {
+			end = f.max
+		}
+	}
+
+	if start > end {
+		return 0, fmt.Errorf("invalid range %q", value)
+	}
+
+	var bits uint64
+	for i := start; i <= end; i += step {
+		bits |= 1 << uint(i)
+	}
+
+	return bits, nil
+}
+
+func parseValue(value string, f field) (int, error) {
+	if v, ok := f.names[strings.ToLower(value)]; ok {
+		return v, nil
+	}
+
+	v, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", value)
+	}
+
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("value %d out of range [%d, %d]", v, f.min, f.max)
+	}
+
+	return v, nil
+}
+
+func has(bits uint64, v int) bool {
+	return bits&(1<<uint(v)) != 0
+}
@@ -0,0 +1,81 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+	}
+
+	for _, expr := range tests {
+		t.Run(expr, func(t *testing.T) {
+			_, err := Parse(expr, nil)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	base := time.Date(2024, time.January, 31, 10, 17, 30, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		expr     string
+		expected time.Time
+	}{
+		{"every minute", "* * * * *", time.Date(2024, 1, 31, 10, 18, 0, 0, time.UTC)},
+		{"every 15 minutes", "*/15 * * * *", time.Date(2024, 1, 31, 10, 30, 0, 0, time.UTC)},
+		{"daily macro", "@daily", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"hour list", "0 9,18 * * *", time.Date(2024, 1, 31, 18, 0, 0, 0, time.UTC)},
+		{"weekday names", "30 8 * * mon-fri", time.Date(2024, 2, 1, 8, 30, 0, 0, time.UTC)},
+		{"sunday as 7", "0 0 * * 7", time.Date(2024, 2, 4, 0, 0, 0, 0, time.UTC)},
+		{"leap day", "0 12 29 feb *", time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC)},
+		{"dom or dow", "0 0 1 * sat", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := Parse(tt.expr, nil)
+			require.NoError(t, err)
+
+			next, err := schedule.Next(base)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, next)
+		})
+	}
+}
+
+func TestSchedule_NextImpossible(t *testing.T) {
+	schedule, err := Parse("0 0 30 2 *", nil)
+	require.NoError(t, err)
+
+	_, err = schedule.Next(time.Now())
+	assert.ErrorIs(t, err, ErrNoActivation)
+}
+
+func TestSchedule_NextN(t *testing.T) {
+	schedule, err := Parse("0 */6 * * *", nil)
+	require.NoError(t, err)
+
+	times, err := schedule.NextN(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 3)
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{
+		time.Date(2024, 1, 1, 6, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 1, 18, 0, 0, 0, time.UTC),
+	}, times)
+}
@@ -0,0 +1,86 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AdvisoryLock is a Postgres session-level advisory lock held on a dedicated
+// pooled connection. It is used to elect a single leader among replicas.
+type AdvisoryLock struct {
+	pool *pgxpool.Pool
+	key  int64
+
+	mu   sync.Mutex
+	conn *pgxpool.Conn
+}
+
+func NewAdvisoryLock(pool *pgxpool.Pool, key int64) *AdvisoryLock {
+	return &AdvisoryLock{
+		pool: pool,
+		key:  key,
+	}
+}
+
+// TryAcquire keeps the lock connection alive or tries to take the lock.
+// It reports whether the lock is held by this process.
+func (l *AdvisoryLock) TryAcquire(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn != nil {
+		if err := l.conn.Ping(ctx); err == nil {
+			return true, nil
+		}
+
+		// The session is gone, and the lock with it.
+		l.conn.Release()
+		l.conn = nil
+	}
+
+	conn, err := l.pool.Acquire(ctx)
+	if err != nil {
+		return false, fmt.Errorf("acquire connection: %w", err)
+	}
+
+	var locked bool
+	if err := conn.QueryRow(ctx, `SELECT pg_try_advisory_lock($1)`, l.key).Scan(&locked); err != nil {
+		conn.Release()
+
+		return false, fmt.Errorf("try advisory lock: %w", err)
+	}
+
+	if !locked {
+		conn.Release()
+
+		return false, nil
+	}
+
+	l.conn = conn
+
+	return true, nil
+}
+
+// Release unlocks and returns the connection to the pool if the lock is held.
+func (l *AdvisoryLock) Release(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return nil
+	}
+
+	defer func() {
+		l.conn.Release()
+		l.conn = nil
+	}()
+
+	if _, err := l.conn.Exec(ctx, `SELECT pg_advisory_unlock($1)`, l.key); err != nil {
+		return fmt.Errorf("advisory unlock: %w", err)
+	}
+
+	return nil
+}
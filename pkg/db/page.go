@@ -0,0 +1,48 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// PageQuery is the count query and the page query of a paginated list.
+type PageQuery struct {
+	CountSQL  string
+	CountArgs []any
+	SQL       string
+	Args      []any
+}
+
+// QueryPage runs the count and the page queries in a single round-trip and collects the rows with scan.
+// Unlike a COUNT(*) OVER() column the total is known when the page is past the last row.
+func QueryPage[T any](ctx context.Context, executor Tx, query PageQuery, scan pgx.RowToFunc[T]) ([]T, int, error) {
+	batch := &pgx.Batch{}
+	batch.Queue(query.CountSQL, query.CountArgs...)
+	batch.Queue(query.SQL, query.Args...)
+
+	results := executor.SendBatch(ctx, batch)
+	defer results.Close()
+
+	var total int
+	if err := results.QueryRow().Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count: %w", err)
+	}
+
+	rows, err := results.Query()
+	if err != nil {
+		return nil, 0, fmt.Errorf("query: %w", err)
+	}
+
+	items, err := pgx.CollectRows(rows, scan)
+	if err != nil {
+		return nil, 0, fmt.Errorf("collect: %w", err)
+	}
+
+	if err := results.Close(); err != nil {
+		return nil, 0, fmt.Errorf("close batch: %w", err)
+	}
+
+	return items, total, nil
+}
@@ -0,0 +1,173 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var queryRelationRe = regexp.MustCompile(`(?i)\b(?:from|into|update|join)\s+([a-z_][a-z0-9_]*\.[a-z_][a-z0-9_]*)`)
+
+type queryStatsKey struct{}
+
+type queryStats struct {
+	start time.Time
+	sql   string
+	args  []any
+}
+
+// QueryStatsTracer is a pgx query and batch tracer exporting query durations per query label
+// and logging the queries slower than SlowThreshold. A zero SlowThreshold disables the log.
+// A batch is observed as a whole and labeled after its first query.
+type QueryStatsTracer struct {
+	SlowThreshold time.Duration
+
+	duration *prometheus.HistogramVec
+}
+
+var (
+	_ pgx.QueryTracer = (*QueryStatsTracer)(nil)
+	_ pgx.BatchTracer = (*QueryStatsTracer)(nil)
+)
+
+func NewQueryStatsTracer(slowThreshold time.Duration, registerer prometheus.Registerer) (*QueryStatsTracer, error) {
+	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "floxy_manager",
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "Duration of database queries including reading the rows.",
+		Buckets:   []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	}, []string{"query", "status"})
+
+	if err := registerer.Register(duration); err != nil {
+		return nil, fmt.Errorf("register query duration histogram: %w", err)
+	}
+
+	return &QueryStatsTracer{
+		SlowThreshold: slowThreshold,
+		duration:      duration,
+	}, nil
+}
+
+func (t *QueryStatsTracer) TraceQueryStart(
+	ctx context.Context,
+	_ *pgx.Conn,
+	data pgx.TraceQueryStartData,
+) context.Context {
+	return context.WithValue(ctx, queryStatsKey{}, &queryStats{
+		start: time.Now(),
+		sql:   data.SQL,
+		args:  data.Args,
+	})
+}
+
+func (t *QueryStatsTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	t.observe(ctx, QueryLabel, data.Err)
+}
+
+func (t *QueryStatsTracer) TraceBatchStart(
+	ctx context.Context,
+	_ *pgx.Conn,
+	data pgx.TraceBatchStartData,
+) context.Context {
+	stats := &queryStats{start: time.Now()}
+
+	if data.Batch != nil {
+		sqls := make([]string, 0, len(data.Batch.QueuedQueries))
+		for _, query := range data.Batch.QueuedQueries {
+			sqls = append(sqls, query.SQL)
+			stats.args = append(stats.args, query.Arguments...)
+		}
+
+		stats.sql = strings.Join(sqls, "; ")
+	}
+
+	return context.WithValue(ctx, queryStatsKey{}, stats)
+}
+
+func (t *QueryStatsTracer) TraceBatchQuery(context.Context, *pgx.Conn, pgx.TraceBatchQueryData) {}
+
+func (t *QueryStatsTracer) TraceBatchEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchEndData) {
+	t.observe(ctx, func(sql string) string { return "batch " + QueryLabel(sql) }, data.Err)
+}
+
+func (t *QueryStatsTracer) observe(ctx context.Context, labelOf func(sql string) string, err error) {
+	stats, ok := ctx.Value(queryStatsKey{}).(*queryStats)
+	if !ok {
+		return
+	}
+
+	elapsed := time.Since(stats.start)
+	label := labelOf(stats.sql)
+
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+
+	t.duration.WithLabelValues(label, status).Observe(elapsed.Seconds())
+
+	if t.SlowThreshold > 0 && elapsed >= t.SlowThreshold {
+		slog.WarnContext(ctx, "Slow query",
+			"query", label,
+			"duration", elapsed,
+			"sql", strings.Join(strings.Fields(stats.sql), " "),
+			"args", redactArgs(stats.args),
+		)
+	}
+}
+
+// QueryLabel names a query by its statement and first schema-qualified relation,
+// e.g. "select workflows_manager.v_workflow_instances", keeping the metric cardinality low.
+func QueryLabel(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return "unknown"
+	}
+
+	verb := strings.ToLower(fields[0])
+
+	match := queryRelationRe.FindStringSubmatch(sql)
+	if match == nil {
+		return verb
+	}
+
+	return verb + " " + strings.ToLower(match[1])
+}
+
+// redactArgs keeps the types of the bound args and the values of numbers and booleans only,
+// strings and payloads may hold secrets or personal data.
+func redactArgs(args []any) []string {
+	redacted := make([]string, 0, len(args))
+
+	for _, arg := range args {
+		if arg == nil {
+			redacted = append(redacted, "NULL")
+
+			continue
+		}
+
+		value := reflect.ValueOf(arg)
+
+		switch value.Kind() { //nolint:exhaustive // everything else is redacted
+		case reflect.Bool,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			redacted = append(redacted, fmt.Sprint(arg))
+		case reflect.String, reflect.Slice:
+			redacted = append(redacted, fmt.Sprintf("<%T len=%d>", arg, value.Len()))
+		default:
+			redacted = append(redacted, fmt.Sprintf("<%T>", arg))
+		}
+	}
+
+	return redacted
+}
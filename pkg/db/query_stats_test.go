@@ -0,0 +1,41 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type projectID int
+
+func TestQueryLabel(t *testing.T) {
+	assert.Equal(t, "select workflows_manager.v_workflow_instances",
+		QueryLabel("\nSELECT id, status\nFROM workflows_manager.v_workflow_instances i\nWHERE project_id = $1"))
+	assert.Equal(t, "with workflows.workflow_instances",
+		QueryLabel("WITH doomed AS (DELETE FROM workflows.workflow_instances RETURNING id) SELECT count(*) FROM doomed"))
+	assert.Equal(t, "insert workflows_manager.projects", QueryLabel("INSERT INTO workflows_manager.projects (name) VALUES ($1)"))
+	assert.Equal(t, "select", QueryLabel("SELECT 1"))
+	assert.Equal(t, "unknown", QueryLabel(" "))
+}
+
+func TestRedactArgs(t *testing.T) {
+	assert.Equal(t,
+		[]string{"NULL", "42", "3", "true", "<string len=6>", "<[]uint8 len=2>", "<map[string]string>"},
+		redactArgs([]any{nil, 42, projectID(3), true, "secret", []byte("{}"), map[string]string{}}),
+	)
+}
+
+func TestQueryStatsTracer(t *testing.T) {
+	tracer, err := NewQueryStatsTracer(0, prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "SELECT 1 FROM workflows.workflow_instances"})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{})
+
+	assert.Equal(t, 1, testutil.CollectAndCount(tracer.duration))
+}
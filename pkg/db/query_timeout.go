@@ -0,0 +1,116 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const queryCanceledCode = "57014"
+
+type (
+	queryCancelKey        struct{}
+	queryTimeoutMarkerKey struct{}
+)
+
+// IsQueryTimeout reports whether err is a query stopped by a deadline or by the Postgres statement_timeout.
+func IsQueryTimeout(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == queryCanceledCode
+	}
+
+	return pgconn.Timeout(err) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// WithQueryTimeoutMarker returns a context in which QueryTimeoutTracer records timed out queries.
+func WithQueryTimeoutMarker(ctx context.Context) context.Context {
+	return context.WithValue(ctx, queryTimeoutMarkerKey{}, new(atomic.Bool))
+}
+
+// QueryTimedOut reports whether a query run with the context (or its children) timed out.
+func QueryTimedOut(ctx context.Context) bool {
+	marker, ok := ctx.Value(queryTimeoutMarkerKey{}).(*atomic.Bool)
+
+	return ok && marker.Load()
+}
+
+// QueryTimeoutTracer is a pgx query and batch tracer bounding every query (or batch) with a deadline,
+// the deadline covers reading the rows until they are closed.
+// A zero Timeout keeps the caller deadline only.
+type QueryTimeoutTracer struct {
+	Timeout time.Duration
+}
+
+var (
+	_ pgx.QueryTracer = (*QueryTimeoutTracer)(nil)
+	_ pgx.BatchTracer = (*QueryTimeoutTracer)(nil)
+)
+
+func NewQueryTimeoutTracer(timeout time.Duration) *QueryTimeoutTracer {
+	return &QueryTimeoutTracer{Timeout: timeout}
+}
+
+func (t *QueryTimeoutTracer) TraceQueryStart(
+	ctx context.Context,
+	_ *pgx.Conn,
+	_ pgx.TraceQueryStartData,
+) context.Context {
+	return t.start(ctx)
+}
+
+func (t *QueryTimeoutTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	t.end(ctx, data.Err)
+}
+
+func (t *QueryTimeoutTracer) TraceBatchStart(
+	ctx context.Context,
+	_ *pgx.Conn,
+	_ pgx.TraceBatchStartData,
+) context.Context {
+	return t.start(ctx)
+}
+
+func (t *QueryTimeoutTracer) TraceBatchQuery(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchQueryData) {
+	markQueryTimeout(ctx, data.Err)
+}
+
+func (t *QueryTimeoutTracer) TraceBatchEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchEndData) {
+	t.end(ctx, data.Err)
+}
+
+func (t *QueryTimeoutTracer) start(ctx context.Context) context.Context {
+	if t.Timeout <= 0 {
+		return ctx
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, t.Timeout)
+
+	return context.WithValue(ctx, queryCancelKey{}, cancel)
+}
+
+func (t *QueryTimeoutTracer) end(ctx context.Context, err error) {
+	markQueryTimeout(ctx, err)
+
+	if cancel, ok := ctx.Value(queryCancelKey{}).(context.CancelFunc); ok {
+		cancel()
+	}
+}
+
+func markQueryTimeout(ctx context.Context, err error) {
+	if !IsQueryTimeout(err) {
+		return
+	}
+
+	if marker, ok := ctx.Value(queryTimeoutMarkerKey{}).(*atomic.Bool); ok {
+		marker.Store(true)
+	}
+}
@@ -0,0 +1,78 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// SessionVars sets Postgres session variables of the pooled connections from the context acquiring them,
+// so row-level security policies can read them with current_setting('app.user_id', true).
+// The values are set again only when they differ from the ones the connection already has.
+type SessionVars struct {
+	names  []string
+	values func(ctx context.Context) []string
+
+	mu      sync.Mutex
+	applied map[*pgx.Conn][]string
+}
+
+// NewSessionVars returns the session variables with the names, values returns their values
+// in the same order; an unknown value is an empty string.
+func NewSessionVars(names []string, values func(ctx context.Context) []string) *SessionVars {
+	return &SessionVars{
+		names:   names,
+		values:  values,
+		applied: make(map[*pgx.Conn][]string),
+	}
+}
+
+// PrepareConn is the pgxpool.Config.PrepareConn hook setting the variables.
+func (s *SessionVars) PrepareConn(ctx context.Context, conn *pgx.Conn) (bool, error) {
+	values := s.values(ctx)
+
+	s.mu.Lock()
+	applied := s.applied[conn]
+	s.mu.Unlock()
+
+	if slices.Equal(applied, values) {
+		return true, nil
+	}
+
+	sql, args := s.query(values)
+	if _, err := conn.Exec(ctx, sql, args...); err != nil {
+		// The connection may have a part of the variables set, it is not reused
+		s.BeforeClose(conn)
+
+		return false, fmt.Errorf("set session variables: %w", err)
+	}
+
+	s.mu.Lock()
+	s.applied[conn] = values
+	s.mu.Unlock()
+
+	return true, nil
+}
+
+// BeforeClose is the pgxpool.Config.BeforeClose hook forgetting the variables of the connection.
+func (s *SessionVars) BeforeClose(conn *pgx.Conn) {
+	s.mu.Lock()
+	delete(s.applied, conn)
+	s.mu.Unlock()
+}
+
+func (s *SessionVars) query(values []string) (string, []any) {
+	calls := make([]string, 0, len(s.names))
+	args := make([]any, 0, 2*len(s.names))
+
+	for i, name := range s.names {
+		calls = append(calls, fmt.Sprintf("set_config($%d, $%d, false)", 2*i+1, 2*i+2))
+		args = append(args, name, values[i])
+	}
+
+	return "SELECT " + strings.Join(calls, ", "), args
+}
@@ -0,0 +1,16 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionVars_Query(t *testing.T) {
+	vars := NewSessionVars([]string{"app.user_id", "app.tenant_id"}, func(context.Context) []string { return nil })
+
+	sql, args := vars.query([]string{"1", ""})
+	assert.Equal(t, "SELECT set_config($1, $2, false), set_config($3, $4, false)", sql)
+	assert.Equal(t, []any{"app.user_id", "1", "app.tenant_id", ""}, args)
+}
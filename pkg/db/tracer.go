@@ -0,0 +1,57 @@
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// QueryTracers runs several pgx query tracers, pgx accepts a single one.
+// Queries end in the reverse order of their start. Batches are traced by the tracers
+// implementing pgx.BatchTracer.
+type QueryTracers []pgx.QueryTracer
+
+var (
+	_ pgx.QueryTracer = QueryTracers(nil)
+	_ pgx.BatchTracer = QueryTracers(nil)
+)
+
+func (t QueryTracers) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	for _, tracer := range t {
+		ctx = tracer.TraceQueryStart(ctx, conn, data)
+	}
+
+	return ctx
+}
+
+func (t QueryTracers) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+	for i := len(t) - 1; i >= 0; i-- {
+		t[i].TraceQueryEnd(ctx, conn, data)
+	}
+}
+
+func (t QueryTracers) TraceBatchStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceBatchStartData) context.Context {
+	for _, tracer := range t {
+		if batchTracer, ok := tracer.(pgx.BatchTracer); ok {
+			ctx = batchTracer.TraceBatchStart(ctx, conn, data)
+		}
+	}
+
+	return ctx
+}
+
+func (t QueryTracers) TraceBatchQuery(ctx context.Context, conn *pgx.Conn, data pgx.TraceBatchQueryData) {
+	for _, tracer := range t {
+		if batchTracer, ok := tracer.(pgx.BatchTracer); ok {
+			batchTracer.TraceBatchQuery(ctx, conn, data)
+		}
+	}
+}
+
+func (t QueryTracers) TraceBatchEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceBatchEndData) {
+	for i := len(t) - 1; i >= 0; i-- {
+		if batchTracer, ok := t[i].(pgx.BatchTracer); ok {
+			batchTracer.TraceBatchEnd(ctx, conn, data)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package httpserver
 
 import (
 	"context"
+	"crypto/tls"
 	"net"
 	"net/http"
 	"time"
@@ -10,22 +11,30 @@ import (
 type ServerTLS struct {
 	Listener net.Listener
 
-	ReadTimeout  time.Duration
-	WriteTimeout time.Duration
-	IdleTimeout  time.Duration
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	MaxHeaderBytes    int
 
 	Handler http.Handler
 
 	CertFile string
 	KeyFile  string
+
+	// TLSConfig is used instead of the files when it provides certificates, e.g. with ACME. Optional.
+	TLSConfig *tls.Config
 }
 
 func (s *ServerTLS) ListenAndServe(ctx context.Context) error {
 	srv := http.Server{
-		ReadTimeout:  s.ReadTimeout,
-		WriteTimeout: s.WriteTimeout,
-		IdleTimeout:  s.IdleTimeout,
-		Handler:      s.Handler,
+		ReadHeaderTimeout: s.ReadHeaderTimeout,
+		ReadTimeout:       s.ReadTimeout,
+		WriteTimeout:      s.WriteTimeout,
+		IdleTimeout:       s.IdleTimeout,
+		MaxHeaderBytes:    s.MaxHeaderBytes,
+		Handler:           s.Handler,
+		TLSConfig:         s.TLSConfig,
 	}
 
 	errc := make(chan error, 1)
@@ -0,0 +1,142 @@
+package middlewares
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// compressMinSize is the size of the first write below which the response is sent uncompressed.
+const compressMinSize = 1024
+
+// CompressMdw compresses responses with gzip or deflate according to the Accept-Encoding request header.
+func CompressMdw(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		encoding := negotiateEncoding(req.Header.Get("Accept-Encoding"))
+		if encoding == "" {
+			next.ServeHTTP(writer, req)
+
+			return
+		}
+
+		writer.Header().Add("Vary", "Accept-Encoding")
+
+		cw := &compressWriter{ResponseWriter: writer, encoding: encoding}
+		defer cw.Close()
+
+		next.ServeHTTP(cw, req)
+	})
+}
+
+// negotiateEncoding returns "gzip", "deflate" or "" when the client accepts neither.
+func negotiateEncoding(acceptEncoding string) string {
+	var deflate bool
+
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+				continue
+			}
+		}
+
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "gzip", "*":
+			return "gzip"
+		case "deflate":
+			deflate = true
+		}
+	}
+
+	if deflate {
+		return "deflate"
+	}
+
+	return ""
+}
+
+// compressWriter decides on the first write whether to compress: responses without a body,
+// already encoded or with a small first write are passed through.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+
+	status  int
+	decided bool
+	encoder io.WriteCloser
+}
+
+func (w *compressWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	if !w.decided {
+		w.decide(len(b))
+	}
+
+	if w.encoder != nil {
+		return w.encoder.Write(b)
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush flushes buffered compressed data, so streaming handlers keep working.
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		w.decide(0)
+	}
+
+	if flusher, ok := w.encoder.(interface{ Flush() error }); ok {
+		_ = flusher.Flush()
+	}
+
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *compressWriter) Close() {
+	if !w.decided {
+		// Nothing was written, send the status only
+		w.decide(0)
+	}
+
+	if w.encoder != nil {
+		_ = w.encoder.Close()
+	}
+}
+
+func (w *compressWriter) decide(firstWrite int) {
+	w.decided = true
+
+	header := w.Header()
+	compress := firstWrite >= compressMinSize &&
+		header.Get("Content-Encoding") == "" &&
+		w.status != http.StatusNoContent && w.status != http.StatusNotModified
+
+	if compress {
+		header.Set("Content-Encoding", w.encoding)
+		header.Del("Content-Length")
+
+		if w.encoding == "gzip" {
+			w.encoder = gzip.NewWriter(w.ResponseWriter)
+		} else {
+			w.encoder = zlib.NewWriter(w.ResponseWriter)
+		}
+	}
+
+	if w.status != 0 {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+}
@@ -0,0 +1,65 @@
+package middlewares
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressMdw(t *testing.T) {
+	t.Parallel()
+
+	body := strings.Repeat(`{"name":"workflow"}`, 100)
+	handler := CompressMdw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, r.URL.Query().Get("prefix")+body)
+	}))
+
+	t.Run("gzip", func(t *testing.T) {
+		t.Parallel()
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/workflows", nil)
+		req.Header.Set("Accept-Encoding", "br;q=1.0, gzip;q=0.8")
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		require.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+		assert.Equal(t, "Accept-Encoding", rec.Header().Get("Vary"))
+
+		reader, err := gzip.NewReader(rec.Body)
+		require.NoError(t, err)
+		decoded, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, body, string(decoded))
+	})
+
+	t.Run("not accepted", func(t *testing.T) {
+		t.Parallel()
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/workflows", nil)
+		req.Header.Set("Accept-Encoding", "gzip;q=0, identity")
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		assert.Empty(t, rec.Header().Get("Content-Encoding"))
+		assert.Equal(t, body, rec.Body.String())
+	})
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "gzip", negotiateEncoding("gzip, deflate"))
+	assert.Equal(t, "deflate", negotiateEncoding("deflate"))
+	assert.Equal(t, "deflate", negotiateEncoding("gzip;q=0, deflate"))
+	assert.Empty(t, negotiateEncoding("br"))
+	assert.Empty(t, negotiateEncoding(""))
+}
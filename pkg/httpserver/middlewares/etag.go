@@ -0,0 +1,102 @@
+package middlewares
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// ETagMdw buffers successful GET and HEAD responses, sets a weak ETag computed from the body and
+// answers 304 Not Modified when it matches If-None-Match. Responses are per user, so they are
+// marked as private and revalidated on every use.
+func ETagMdw(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			next.ServeHTTP(writer, req)
+
+			return
+		}
+
+		buf := &bufferedWriter{header: make(http.Header)}
+		next.ServeHTTP(buf, req)
+
+		header := writer.Header()
+		for key, values := range buf.header {
+			header[key] = values
+		}
+
+		status := buf.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		if status != http.StatusOK {
+			writer.WriteHeader(status)
+			_, _ = writer.Write(buf.body.Bytes())
+
+			return
+		}
+
+		sum := sha256.Sum256(buf.body.Bytes())
+		etag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+
+		header.Set("ETag", etag)
+		if header.Get("Cache-Control") == "" {
+			header.Set("Cache-Control", "private, no-cache")
+		}
+
+		if etagMatches(req.Header.Get("If-None-Match"), etag) {
+			header.Del("Content-Length")
+			header.Del("Content-Type")
+			writer.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+
+		writer.WriteHeader(status)
+		_, _ = writer.Write(buf.body.Bytes())
+	})
+}
+
+// etagMatches implements the weak comparison of If-None-Match.
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// bufferedWriter collects the whole response of the wrapped handler.
+type bufferedWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *bufferedWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	return w.body.Write(b)
+}
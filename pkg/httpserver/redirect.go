@@ -0,0 +1,25 @@
+package httpserver
+
+import (
+	"net"
+	"net/http"
+)
+
+// RedirectHTTPS redirects requests to the same URL over HTTPS on httpsPort,
+// the port is left out of the URL when it is the default 443.
+func RedirectHTTPS(httpsPort string) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+		host := req.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+
+		if httpsPort != "" && httpsPort != "443" {
+			host = net.JoinHostPort(host, httpsPort)
+		}
+
+		target := "https://" + host + req.URL.RequestURI()
+
+		http.Redirect(writer, req, target, http.StatusPermanentRedirect)
+	})
+}
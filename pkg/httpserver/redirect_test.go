@@ -0,0 +1,20 @@
+package httpserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedirectHTTPS(t *testing.T) {
+	rec := httptest.NewRecorder()
+	RedirectHTTPS("443").ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://floxy.local:80/api/v1/users?page=2", nil))
+	assert.Equal(t, http.StatusPermanentRedirect, rec.Code)
+	assert.Equal(t, "https://floxy.local/api/v1/users?page=2", rec.Header().Get("Location"))
+
+	rec = httptest.NewRecorder()
+	RedirectHTTPS("8443").ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "http://floxy.local/login", nil))
+	assert.Equal(t, "https://floxy.local:8443/login", rec.Header().Get("Location"))
+}
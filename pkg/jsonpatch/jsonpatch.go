@@ -0,0 +1,307 @@
+// Package jsonpatch applies JSON Patch documents (RFC 6902) to JSON values.
+package jsonpatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidPatch = errors.New("invalid json patch")
+
+// Operation is an operation of a JSON Patch document.
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Apply applies the patch, a JSON array of operations, to the document.
+// The operations are applied in order; when one fails the document is left unchanged.
+func Apply(document, patch json.RawMessage) (json.RawMessage, error) {
+	var ops []Operation
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	doc, err := decode(document)
+	if err != nil {
+		return nil, fmt.Errorf("decode document: %w", err)
+	}
+
+	for i, op := range ops {
+		doc, err = apply(doc, op)
+		if err != nil {
+			return nil, fmt.Errorf("%w: operation %d (%s %s): %v", ErrInvalidPatch, i, op.Op, op.Path, err)
+		}
+	}
+
+	return json.Marshal(doc)
+}
+
+func apply(doc any, op Operation) (any, error) {
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return nil, errors.New("value is required")
+		}
+
+		value, err := decode(op.Value)
+		if err != nil {
+			return nil, fmt.Errorf("decode value: %w", err)
+		}
+
+		switch op.Op {
+		case "add":
+			return add(doc, op.Path, value)
+		case "replace":
+			if _, err := get(doc, op.Path); err != nil {
+				return nil, err
+			}
+			if doc, err = remove(doc, op.Path); err != nil {
+				return nil, err
+			}
+
+			return add(doc, op.Path, value)
+		default:
+			current, err := get(doc, op.Path)
+			if err != nil {
+				return nil, err
+			}
+			if !reflect.DeepEqual(current, value) {
+				return nil, errors.New("test failed")
+			}
+
+			return doc, nil
+		}
+	case "remove":
+		return remove(doc, op.Path)
+	case "move", "copy":
+		value, err := get(doc, op.From)
+		if err != nil {
+			return nil, err
+		}
+
+		if op.Op == "move" {
+			if strings.HasPrefix(op.Path, op.From+"/") {
+				return nil, errors.New("cannot move a value into itself")
+			}
+			if doc, err = remove(doc, op.From); err != nil {
+				return nil, err
+			}
+		} else {
+			// The copy must not share containers with the source
+			if value, err = clone(value); err != nil {
+				return nil, err
+			}
+		}
+
+		return add(doc, op.Path, value)
+	default:
+		return nil, fmt.Errorf("unknown operation %q", op.Op)
+	}
+}
+
+func get(doc any, path string) (any, error) {
+	tokens, err := parsePointer(path)
+	if err != nil {
+		return nil, err
+	}
+
+	current := doc
+	for _, token := range tokens {
+		switch node := current.(type) {
+		case map[string]any:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("path %q does not exist", path)
+			}
+			current = value
+		case []any:
+			idx, err := arrayIndex(token, len(node)-1)
+			if err != nil {
+				return nil, fmt.Errorf("path %q: %w", path, err)
+			}
+			current = node[idx]
+		default:
+			return nil, fmt.Errorf("path %q does not exist", path)
+		}
+	}
+
+	return current, nil
+}
+
+func add(doc any, path string, value any) (any, error) {
+	tokens, err := parsePointer(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tokens) == 0 {
+		return value, nil
+	}
+
+	return update(doc, tokens, path, func(parent any, last string) (any, error) {
+		switch node := parent.(type) {
+		case map[string]any:
+			node[last] = value
+
+			return node, nil
+		case []any:
+			if last == "-" {
+				return append(node, value), nil
+			}
+
+			idx, err := arrayIndex(last, len(node))
+			if err != nil {
+				return nil, err
+			}
+
+			node = append(node, nil)
+			copy(node[idx+1:], node[idx:])
+			node[idx] = value
+
+			return node, nil
+		default:
+			return nil, errors.New("parent is not an object or an array")
+		}
+	})
+}
+
+func remove(doc any, path string) (any, error) {
+	tokens, err := parsePointer(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tokens) == 0 {
+		return nil, errors.New("cannot remove the whole document")
+	}
+
+	return update(doc, tokens, path, func(parent any, last string) (any, error) {
+		switch node := parent.(type) {
+		case map[string]any:
+			if _, ok := node[last]; !ok {
+				return nil, errors.New("member does not exist")
+			}
+			delete(node, last)
+
+			return node, nil
+		case []any:
+			idx, err := arrayIndex(last, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+
+			return append(node[:idx], node[idx+1:]...), nil
+		default:
+			return nil, errors.New("parent is not an object or an array")
+		}
+	})
+}
+
+// update walks to the parent of the last token and replaces it with the result of fn,
+// arrays may be reallocated by fn.
+func update(doc any, tokens []string, path string, fn func(parent any, last string) (any, error)) (any, error) {
+	if len(tokens) == 1 {
+		updated, err := fn(doc, tokens[0])
+		if err != nil {
+			return nil, fmt.Errorf("path %q: %w", path, err)
+		}
+
+		return updated, nil
+	}
+
+	switch node := doc.(type) {
+	case map[string]any:
+		child, ok := node[tokens[0]]
+		if !ok {
+			return nil, fmt.Errorf("path %q does not exist", path)
+		}
+
+		updated, err := update(child, tokens[1:], path, fn)
+		if err != nil {
+			return nil, err
+		}
+		node[tokens[0]] = updated
+
+		return node, nil
+	case []any:
+		idx, err := arrayIndex(tokens[0], len(node)-1)
+		if err != nil {
+			return nil, fmt.Errorf("path %q: %w", path, err)
+		}
+
+		updated, err := update(node[idx], tokens[1:], path, fn)
+		if err != nil {
+			return nil, err
+		}
+		node[idx] = updated
+
+		return node, nil
+	default:
+		return nil, fmt.Errorf("path %q does not exist", path)
+	}
+}
+
+// parsePointer splits a JSON Pointer (RFC 6901) into its unescaped reference tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("path %q must start with /", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+
+	return tokens, nil
+}
+
+func arrayIndex(token string, maxIdx int) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+
+	idx, err := strconv.Atoi(token)
+	if err != nil || idx < 0 {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+
+	if idx > maxIdx {
+		return 0, fmt.Errorf("array index %d out of bounds", idx)
+	}
+
+	return idx, nil
+}
+
+// decode keeps numbers as json.Number so that they are not rounded through float64.
+func decode(data json.RawMessage) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+func clone(value any) (any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return decode(data)
+}
@@ -0,0 +1,70 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+	}{
+		{
+			name:  "add and replace members",
+			doc:   `{"order": {"id": 12345678901234567890, "amount": 10}}`,
+			patch: `[{"op": "replace", "path": "/order/amount", "value": 20}, {"op": "add", "path": "/retry", "value": true}]`,
+			want:  `{"order": {"id": 12345678901234567890, "amount": 20}, "retry": true}`,
+		},
+		{
+			name:  "array insert and append",
+			doc:   `{"items": ["a", "c"]}`,
+			patch: `[{"op": "add", "path": "/items/1", "value": "b"}, {"op": "add", "path": "/items/-", "value": "d"}]`,
+			want:  `{"items": ["a", "b", "c", "d"]}`,
+		},
+		{
+			name:  "remove, move and copy",
+			doc:   `{"a": 1, "b": {"c": 2}, "d": [1, 2]}`,
+			patch: `[{"op": "remove", "path": "/a"}, {"op": "move", "from": "/b/c", "path": "/c"}, {"op": "copy", "from": "/d", "path": "/e"}, {"op": "remove", "path": "/d/0"}]`,
+			want:  `{"b": {}, "c": 2, "d": [2], "e": [1, 2]}`,
+		},
+		{
+			name:  "escaped pointer and test",
+			doc:   `{"a/b": {"m~n": 1}}`,
+			patch: `[{"op": "test", "path": "/a~1b/m~0n", "value": 1}, {"op": "replace", "path": "/a~1b/m~0n", "value": 2}]`,
+			want:  `{"a/b": {"m~n": 2}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply(json.RawMessage(tt.doc), json.RawMessage(tt.patch))
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestApply_Invalid(t *testing.T) {
+	tests := []string{
+		`{"op": "add"}`,
+		`[{"op": "replace", "path": "/missing", "value": 1}]`,
+		`[{"op": "remove", "path": "/items/5"}]`,
+		`[{"op": "test", "path": "/a", "value": 2}]`,
+		`[{"op": "move", "from": "/items", "path": "/items/0"}]`,
+		`[{"op": "add", "path": "a", "value": 1}]`,
+		`[{"op": "unknown", "path": "/a"}]`,
+	}
+
+	for _, patch := range tests {
+		t.Run(patch, func(t *testing.T) {
+			_, err := Apply(json.RawMessage(`{"a": 1, "items": [1]}`), json.RawMessage(patch))
+			require.ErrorIs(t, err, ErrInvalidPatch)
+		})
+	}
+}
@@ -0,0 +1,235 @@
+// Package jsonpath selects values of decoded JSON documents with a subset of JSONPath:
+// the root $, children .name and ['name'], array indexes [0] (negative from the end),
+// wildcards .* and [*] and the recursive descent .. before any of them.
+package jsonpath
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidPath = errors.New("invalid json path")
+
+type selectorKind int
+
+const (
+	selectKey selectorKind = iota
+	selectIndex
+	selectWildcard
+)
+
+type selector struct {
+	kind      selectorKind
+	key       string
+	index     int
+	recursive bool
+}
+
+// Path is a compiled JSONPath expression.
+type Path struct {
+	expr      string
+	selectors []selector
+}
+
+// Compile parses the expression.
+func Compile(expr string) (*Path, error) {
+	if !strings.HasPrefix(expr, "$") {
+		return nil, fmt.Errorf("%w: %q must start with $", ErrInvalidPath, expr)
+	}
+
+	p := &Path{expr: expr}
+	for rest := expr[1:]; rest != ""; {
+		sel, tail, err := parseSelector(rest)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %v", ErrInvalidPath, expr, err)
+		}
+		p.selectors = append(p.selectors, sel)
+		rest = tail
+	}
+
+	return p, nil
+}
+
+func (p *Path) String() string {
+	return p.expr
+}
+
+// Location returns the keys and array indexes of the single location the path selects.
+// It reports false for the root and for paths with wildcards or recursive descents.
+func (p *Path) Location() ([]string, bool) {
+	if len(p.selectors) == 0 {
+		return nil, false
+	}
+
+	location := make([]string, 0, len(p.selectors))
+	for _, sel := range p.selectors {
+		switch {
+		case sel.recursive:
+			return nil, false
+		case sel.kind == selectKey:
+			location = append(location, sel.key)
+		case sel.kind == selectIndex:
+			location = append(location, strconv.Itoa(sel.index))
+		default:
+			return nil, false
+		}
+	}
+
+	return location, true
+}
+
+// Replace replaces the values selected in the document, as decoded by encoding/json, with the result of fn.
+// Objects and arrays are updated in place, the possibly replaced document is returned.
+func (p *Path) Replace(doc any, fn func(value any) any) any {
+	return replace(doc, p.selectors, fn)
+}
+
+func replace(node any, selectors []selector, fn func(value any) any) any {
+	if len(selectors) == 0 {
+		return fn(node)
+	}
+
+	sel, rest := selectors[0], selectors[1:]
+	node = replaceChildren(node, sel, rest, fn)
+
+	if sel.recursive {
+		switch n := node.(type) {
+		case map[string]any:
+			for key, value := range n {
+				n[key] = replace(value, selectors, fn)
+			}
+		case []any:
+			for i, value := range n {
+				n[i] = replace(value, selectors, fn)
+			}
+		}
+	}
+
+	return node
+}
+
+func replaceChildren(node any, sel selector, rest []selector, fn func(value any) any) any {
+	switch n := node.(type) {
+	case map[string]any:
+		switch sel.kind {
+		case selectKey:
+			if value, ok := n[sel.key]; ok {
+				n[sel.key] = replace(value, rest, fn)
+			}
+		case selectWildcard:
+			for key, value := range n {
+				n[key] = replace(value, rest, fn)
+			}
+		case selectIndex:
+		}
+	case []any:
+		switch sel.kind {
+		case selectIndex:
+			idx := sel.index
+			if idx < 0 {
+				idx += len(n)
+			}
+			if idx >= 0 && idx < len(n) {
+				n[idx] = replace(n[idx], rest, fn)
+			}
+		case selectWildcard:
+			for i, value := range n {
+				n[i] = replace(value, rest, fn)
+			}
+		case selectKey:
+		}
+	}
+
+	return node
+}
+
+func parseSelector(s string) (selector, string, error) {
+	var sel selector
+
+	switch {
+	case strings.HasPrefix(s, ".."):
+		sel.recursive = true
+		s = s[2:]
+		if strings.HasPrefix(s, "[") {
+			return parseBracket(s, sel)
+		}
+	case strings.HasPrefix(s, "."):
+		s = s[1:]
+	case strings.HasPrefix(s, "["):
+		return parseBracket(s, sel)
+	default:
+		return sel, "", fmt.Errorf("unexpected %q", s)
+	}
+
+	if strings.HasPrefix(s, "*") {
+		sel.kind = selectWildcard
+
+		return sel, s[1:], nil
+	}
+
+	end := strings.IndexAny(s, ".[")
+	if end < 0 {
+		end = len(s)
+	}
+	if end == 0 {
+		return sel, "", errors.New("empty member name")
+	}
+
+	sel.kind = selectKey
+	sel.key = s[:end]
+
+	return sel, s[end:], nil
+}
+
+func parseBracket(s string, sel selector) (selector, string, error) {
+	s = s[1:]
+
+	if s != "" && (s[0] == '\'' || s[0] == '"') {
+		quote := s[0]
+		var key strings.Builder
+		for i := 1; i < len(s); i++ {
+			switch s[i] {
+			case '\\':
+				if i+1 < len(s) {
+					i++
+					key.WriteByte(s[i])
+				}
+			case quote:
+				if i+1 >= len(s) || s[i+1] != ']' {
+					return sel, "", errors.New("expected ] after the quoted name")
+				}
+				sel.kind = selectKey
+				sel.key = key.String()
+
+				return sel, s[i+2:], nil
+			default:
+				key.WriteByte(s[i])
+			}
+		}
+
+		return sel, "", errors.New("unterminated quoted name")
+	}
+
+	end := strings.IndexByte(s, ']')
+	if end < 0 {
+		return sel, "", errors.New("unterminated [")
+	}
+
+	token := strings.TrimSpace(s[:end])
+	if token == "*" {
+		sel.kind = selectWildcard
+
+		return sel, s[end+1:], nil
+	}
+
+	idx, err := strconv.Atoi(token)
+	if err != nil {
+		return sel, "", fmt.Errorf("invalid index %q", token)
+	}
+	sel.kind = selectIndex
+	sel.index = idx
+
+	return sel, s[end+1:], nil
+}
@@ -0,0 +1,74 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPath_Replace(t *testing.T) {
+	tests := []struct {
+		path string
+		doc  string
+		want string
+	}{
+		{"$.token", `{"token":"t","a":1}`, `{"token":"X","a":1}`},
+		{"$.user['e-mail']", `{"user":{"e-mail":"a@b.c"}}`, `{"user":{"e-mail":"X"}}`},
+		{"$.cards[*].number", `{"cards":[{"number":1},{"number":2,"x":3}]}`,
+			`{"cards":[{"number":"X"},{"number":"X","x":3}]}`},
+		{"$.items[-1]", `{"items":[1,2,3]}`, `{"items":[1,2,"X"]}`},
+		{"$..password", `{"password":"p","nested":[{"password":"q"},{"other":1}]}`,
+			`{"password":"X","nested":[{"password":"X"},{"other":1}]}`},
+		{"$.missing.path", `{"a":1}`, `{"a":1}`},
+		{"$", `{"a":1}`, `"X"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			p, err := Compile(tt.path)
+			require.NoError(t, err)
+
+			var doc any
+			require.NoError(t, json.Unmarshal([]byte(tt.doc), &doc))
+
+			got, err := json.Marshal(p.Replace(doc, func(any) any { return "X" }))
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestCompile_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "token", "$.", "$[1", "$['a'", "$[x]", "$.a..", "$a"} {
+		_, err := Compile(expr)
+		assert.ErrorIs(t, err, ErrInvalidPath, expr)
+	}
+}
+
+func TestPath_Location(t *testing.T) {
+	tests := []struct {
+		path string
+		want []string
+		ok   bool
+	}{
+		{"$.variables.API_TOKEN", []string{"variables", "API_TOKEN"}, true},
+		{"$.user['e-mail']", []string{"user", "e-mail"}, true},
+		{"$.items[-1].id", []string{"items", "-1", "id"}, true},
+		{"$.cards[*].number", nil, false},
+		{"$..password", nil, false},
+		{"$", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			p, err := Compile(tt.path)
+			require.NoError(t, err)
+
+			got, ok := p.Location()
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
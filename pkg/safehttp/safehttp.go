@@ -0,0 +1,67 @@
+// Package safehttp makes HTTP requests to user-supplied URLs, such as webhooks, without letting them
+// reach the loopback, private, link-local and other non-public addresses of the deployment.
+//
+// The destination is checked on every dial after the name is resolved, so redirects and DNS names
+// pointing to internal addresses are rejected too.
+package safehttp
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenAddress is returned when a request would connect to a non-public address.
+var ErrForbiddenAddress = errors.New("destination address is not public")
+
+// sharedAddressSpace is the carrier-grade NAT range of RFC 6598, not covered by netip.Addr.IsPrivate.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// NewClient returns a client that connects only to public addresses. Proxies from the environment
+// are not used, since the proxy would connect to the destination unchecked.
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: 30 * time.Second,
+		Control:   Control,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert // it's *http.Transport
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+// Control is a net.Dialer control function rejecting connections to non-public addresses.
+func Control(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("parse dial address: %w", err)
+	}
+
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return fmt.Errorf("parse dial address: %w", err)
+	}
+
+	if !IsPublic(ip) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, ip)
+	}
+
+	return nil
+}
+
+// IsPublic reports whether the address is a public unicast address.
+func IsPublic(ip netip.Addr) bool {
+	ip = ip.Unmap()
+
+	return ip.IsValid() &&
+		ip.IsGlobalUnicast() &&
+		!ip.IsPrivate() &&
+		!sharedAddressSpace.Contains(ip)
+}
@@ -0,0 +1,101 @@
+// Package safejson decodes untrusted JSON documents with a bound on the nesting depth
+// and, optionally, rejecting fields unknown to the target type.
+package safejson
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DefaultMaxDepth is the nesting depth allowed when Options leave it unset.
+const DefaultMaxDepth = 64
+
+var ErrTooDeep = errors.New("json document is nested too deeply")
+
+type Options struct {
+	// MaxDepth bounds the nesting of objects and arrays, DefaultMaxDepth when zero.
+	MaxDepth              int
+	DisallowUnknownFields bool
+}
+
+type contextKey struct{}
+
+// NewContext returns a context carrying the decoding options.
+func NewContext(ctx context.Context, opts Options) context.Context {
+	return context.WithValue(ctx, contextKey{}, opts)
+}
+
+// FromContext returns the decoding options of the context, the zero Options if there are none.
+func FromContext(ctx context.Context) Options {
+	opts, _ := ctx.Value(contextKey{}).(Options)
+
+	return opts
+}
+
+// Decode reads the first JSON value of r into v. The whole input is read into memory,
+// the caller bounds its size. An empty input yields io.EOF like json.Decoder.
+func Decode(r io.Reader, v any, opts Options) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	maxDepth := opts.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = DefaultMaxDepth
+	}
+
+	if err := checkDepth(data, maxDepth); err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if opts.DisallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+
+	return decoder.Decode(v)
+}
+
+// checkDepth scans the raw document for objects and arrays nested deeper than maxDepth.
+// Malformed documents are left to the decoder.
+func checkDepth(data []byte, maxDepth int) error {
+	var (
+		depth    int
+		inString bool
+		escaped  bool
+	)
+
+	for _, c := range data {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+			if depth > maxDepth {
+				return fmt.Errorf("%w: more than %d levels", ErrTooDeep, maxDepth)
+			}
+		case '}', ']':
+			depth--
+		}
+	}
+
+	return nil
+}
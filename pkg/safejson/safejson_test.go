@@ -0,0 +1,43 @@
+package safejson
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode(t *testing.T) {
+	var v struct {
+		Name string `json:"name"`
+	}
+
+	require.NoError(t, Decode(strings.NewReader(`{"name":"a","extra":[[1]]}`), &v, Options{}))
+	assert.Equal(t, "a", v.Name)
+
+	err := Decode(strings.NewReader(`{"name":"a","extra":1}`), &v, Options{DisallowUnknownFields: true})
+	assert.Error(t, err)
+
+	err = Decode(strings.NewReader(`{"name":"a","extra":[[[1]]]}`), &v, Options{MaxDepth: 3})
+	assert.ErrorIs(t, err, ErrTooDeep)
+
+	// Brackets inside strings do not count
+	require.NoError(t, Decode(strings.NewReader(`{"name":"[[[\"{{"}`), &v, Options{MaxDepth: 1}))
+	assert.Equal(t, `[[["{{`, v.Name)
+
+	deep := strings.Repeat("[", DefaultMaxDepth+1) + strings.Repeat("]", DefaultMaxDepth+1)
+	var a any
+	assert.ErrorIs(t, Decode(strings.NewReader(deep), &a, Options{}), ErrTooDeep)
+
+	assert.ErrorIs(t, Decode(strings.NewReader(""), &v, Options{}), io.EOF)
+}
+
+func TestContext(t *testing.T) {
+	assert.Equal(t, Options{}, FromContext(context.Background()))
+
+	opts := Options{MaxDepth: 10, DisallowUnknownFields: true}
+	assert.Equal(t, opts, FromContext(NewContext(context.Background(), opts)))
+}
@@ -0,0 +1,70 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+)
+
+// secretsManagerClients caches a client per region, so the credentials resolved by the default
+// chain are reused between the refreshes.
+var secretsManagerClients = struct {
+	sync.Mutex
+	byRegion map[string]*secretsmanager.Client
+}{byRegion: map[string]*secretsmanager.Client{}}
+
+// fetchAWSSecretsManager reads the string of an AWS Secrets Manager secret by name or ARN:
+// awssm://prod/floxy#db_password reads the db_password key of the prod/floxy JSON secret.
+// The region is the region query parameter or the one of the AWS configuration (AWS_REGION).
+// The credentials come from the default AWS credential chain: env variables, shared config and SSO,
+// web identity (IRSA), ECS task roles and EC2 instance profiles.
+// AWS_ENDPOINT_URL_SECRETS_MANAGER overrides the endpoint, e.g. for a local emulator.
+func fetchAWSSecretsManager(ctx context.Context, ref Ref) (string, error) {
+	client, err := secretsManagerClient(ctx, ref.Query.Get("region"))
+	if err != nil {
+		return "", err
+	}
+
+	secret, err := client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: &ref.Path})
+	if err != nil {
+		return "", fmt.Errorf("get secret value: %w", err)
+	}
+
+	if secret.SecretString == nil {
+		return "", errors.New("binary secrets are not supported")
+	}
+
+	return selectKey(*secret.SecretString, ref.Key)
+}
+
+func secretsManagerClient(ctx context.Context, region string) (*secretsmanager.Client, error) {
+	secretsManagerClients.Lock()
+	defer secretsManagerClients.Unlock()
+
+	if client, ok := secretsManagerClients.byRegion[region]; ok {
+		return client, nil
+	}
+
+	var opts []func(*awsconfig.LoadOptions) error
+	if region != "" {
+		opts = append(opts, awsconfig.WithRegion(region))
+	}
+
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("load AWS config: %w", err)
+	}
+
+	if cfg.Region == "" {
+		return nil, errors.New("AWS region is not set")
+	}
+
+	client := secretsmanager.NewFromConfig(cfg)
+	secretsManagerClients.byRegion[region] = client
+
+	return client, nil
+}
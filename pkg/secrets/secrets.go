@@ -0,0 +1,252 @@
+// Package secrets resolves secret references such as vault://secret/data/floxy#db_password
+// so that secrets are kept in a secret store instead of env or config files.
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Ref is a parsed secret reference scheme://path?query#key. Key selects a field of a secret
+// holding a JSON object or a key-value map.
+type Ref struct {
+	Scheme string
+	Path   string
+	Key    string
+	Query  url.Values
+}
+
+func (r Ref) String() string {
+	ref := r.Scheme + "://" + r.Path
+	if len(r.Query) > 0 {
+		ref += "?" + r.Query.Encode()
+	}
+
+	if r.Key != "" {
+		ref += "#" + r.Key
+	}
+
+	return ref
+}
+
+// Fetcher returns the secret a reference points to. Fetchers of other stores are registered with Register.
+type Fetcher func(ctx context.Context, ref Ref) (string, error)
+
+var fetchers = struct {
+	sync.RWMutex
+	byScheme map[string]Fetcher
+}{byScheme: map[string]Fetcher{
+	"vault": fetchVault,
+	"awssm": fetchAWSSecretsManager,
+}}
+
+// Register registers the fetcher of references with the scheme, e.g. "gcpsm".
+func Register(scheme string, fetcher Fetcher) {
+	fetchers.Lock()
+	defer fetchers.Unlock()
+
+	fetchers.byScheme[scheme] = fetcher
+}
+
+func lookupFetcher(scheme string) (Fetcher, bool) {
+	fetchers.RLock()
+	defer fetchers.RUnlock()
+
+	fetcher, ok := fetchers.byScheme[scheme]
+
+	return fetcher, ok
+}
+
+// ParseRef parses a reference. Values whose scheme has no registered fetcher are not references,
+// so plain values and URLs such as postgres://... are left alone.
+func ParseRef(value string) (Ref, bool) {
+	scheme, rest, found := strings.Cut(value, "://")
+	if !found {
+		return Ref{}, false
+	}
+
+	if _, ok := lookupFetcher(scheme); !ok {
+		return Ref{}, false
+	}
+
+	ref := Ref{Scheme: scheme}
+	rest, ref.Key, _ = strings.Cut(rest, "#")
+	ref.Path, _, _ = strings.Cut(rest, "?")
+
+	if _, query, found := strings.Cut(rest, "?"); found {
+		ref.Query, _ = url.ParseQuery(query)
+	}
+
+	return ref, true
+}
+
+// IsReference reports whether the value is a secret reference.
+func IsReference(value string) bool {
+	_, ok := ParseRef(value)
+
+	return ok
+}
+
+// Fetch returns the secret of a reference.
+func Fetch(ctx context.Context, ref Ref) (string, error) {
+	fetcher, ok := lookupFetcher(ref.Scheme)
+	if !ok {
+		return "", fmt.Errorf("no secrets fetcher for scheme %q", ref.Scheme)
+	}
+
+	secret, err := fetcher(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("fetch secret %s: %w", ref, err)
+	}
+
+	return secret, nil
+}
+
+// ReadFile reads a file, or fetches the secret when the path is a reference, e.g. for PEM keys.
+func ReadFile(ctx context.Context, pathOrRef string) ([]byte, error) {
+	ref, ok := ParseRef(pathOrRef)
+	if !ok {
+		return os.ReadFile(pathOrRef)
+	}
+
+	secret, err := Fetch(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(secret), nil
+}
+
+// selectKey returns the field key of a secret stored as a JSON object, or the secret itself without a key.
+func selectKey(secret, key string) (string, error) {
+	if key == "" {
+		return secret, nil
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(secret), &fields); err != nil {
+		return "", fmt.Errorf("secret is not a JSON object, cannot select key %q", key)
+	}
+
+	return fieldValue(fields, key)
+}
+
+func fieldValue(fields map[string]any, key string) (string, error) {
+	value, ok := fields[key]
+	if !ok {
+		return "", fmt.Errorf("secret has no key %q", key)
+	}
+
+	switch value := value.(type) {
+	case string:
+		return value, nil
+	case nil:
+		return "", fmt.Errorf("secret key %q is null", key)
+	default:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+
+		return string(data), nil
+	}
+}
+
+// Value is the current value of a secret, references are updated in place by Resolver.Refresh.
+type Value struct {
+	ref     *Ref
+	current atomic.Pointer[string]
+}
+
+// Static returns a Value that never changes.
+func Static(value string) *Value {
+	v := &Value{}
+	v.current.Store(&value)
+
+	return v
+}
+
+func (v *Value) Get() string {
+	return *v.current.Load()
+}
+
+// IsReference reports whether the value comes from a secret store and may rotate.
+func (v *Value) IsReference() bool {
+	return v.ref != nil
+}
+
+// Resolver fetches the values of references and keeps them to refresh them later.
+type Resolver struct {
+	mu     sync.Mutex
+	values []*Value
+}
+
+func NewResolver() *Resolver {
+	return &Resolver{}
+}
+
+// Resolve returns the value of a reference, or a static value when the value is not a reference.
+func (r *Resolver) Resolve(ctx context.Context, value string) (*Value, error) {
+	ref, ok := ParseRef(value)
+	if !ok {
+		return Static(value), nil
+	}
+
+	secret, err := Fetch(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &Value{ref: &ref}
+	v.current.Store(&secret)
+
+	r.mu.Lock()
+	r.values = append(r.values, v)
+	r.mu.Unlock()
+
+	return v, nil
+}
+
+// Len returns the number of resolved references.
+func (r *Resolver) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.values)
+}
+
+// Refresh fetches the resolved references again and returns how many of them changed.
+// A reference that cannot be fetched keeps its previous value.
+func (r *Resolver) Refresh(ctx context.Context) (int, error) {
+	r.mu.Lock()
+	values := append([]*Value(nil), r.values...)
+	r.mu.Unlock()
+
+	var (
+		changed int
+		errs    []error
+	)
+
+	for _, v := range values {
+		secret, err := Fetch(ctx, *v.ref)
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		if secret != v.Get() {
+			v.current.Store(&secret)
+			changed++
+		}
+	}
+
+	return changed, errors.Join(errs...)
+}
@@ -0,0 +1,131 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRef(t *testing.T) {
+	ref, ok := ParseRef("vault://secret/data/floxy#db_password")
+	require.True(t, ok)
+	assert.Equal(t, Ref{Scheme: "vault", Path: "secret/data/floxy", Key: "db_password"}, ref)
+
+	ref, ok = ParseRef("awssm://arn:aws:secretsmanager:eu-west-1:123:secret:floxy?region=eu-west-1#password")
+	require.True(t, ok)
+	assert.Equal(t, "arn:aws:secretsmanager:eu-west-1:123:secret:floxy", ref.Path)
+	assert.Equal(t, "eu-west-1", ref.Query.Get("region"))
+	assert.Equal(t, "password", ref.Key)
+
+	for _, value := range []string{"s3cr3t", "postgres://user@host/db", ""} {
+		_, ok := ParseRef(value)
+		assert.False(t, ok, value)
+	}
+}
+
+func TestFetchVault(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusForbidden)
+
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v1/secret/data/floxy":
+			_, _ = w.Write([]byte(`{"data": {"data": {"db_password": "v2"}, "metadata": {"version": 3}}}`))
+		case "/v1/kv/floxy":
+			_, _ = w.Write([]byte(`{"data": {"db_password": "v1"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("VAULT_ADDR", server.URL)
+	t.Setenv("VAULT_TOKEN", "root")
+
+	secret, err := Fetch(context.Background(), Ref{Scheme: "vault", Path: "secret/data/floxy", Key: "db_password"})
+	require.NoError(t, err)
+	assert.Equal(t, "v2", secret)
+
+	secret, err = Fetch(context.Background(), Ref{Scheme: "vault", Path: "kv/floxy", Key: "db_password"})
+	require.NoError(t, err)
+	assert.Equal(t, "v1", secret)
+
+	_, err = Fetch(context.Background(), Ref{Scheme: "vault", Path: "kv/floxy", Key: "missing"})
+	require.Error(t, err)
+}
+
+func TestFetch_AWSSecretsManager(t *testing.T) {
+	var target, authorization string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target, authorization = r.Header.Get("X-Amz-Target"), r.Header.Get("Authorization")
+
+		var req struct {
+			SecretID string `json:"SecretId"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.SecretID != "prod/floxy" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		_, _ = w.Write([]byte(`{"Name": "prod/floxy", "SecretString": "{\"db_password\": \"s3cr3t\"}"}`))
+	}))
+	defer srv.Close()
+
+	t.Setenv("AWS_ENDPOINT_URL_SECRETS_MANAGER", srv.URL)
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY")
+	t.Setenv("AWS_CONFIG_FILE", t.TempDir()+"/config")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", t.TempDir()+"/credentials")
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+
+	ref, ok := ParseRef("awssm://prod/floxy?region=ap-south-2#db_password")
+	require.True(t, ok)
+
+	secret, err := Fetch(context.Background(), ref)
+	require.NoError(t, err)
+	assert.Equal(t, "s3cr3t", secret)
+	assert.Equal(t, "secretsmanager.GetSecretValue", target)
+	assert.Contains(t, authorization, "Credential=AKIDEXAMPLE/")
+	assert.Contains(t, authorization, "/ap-south-2/secretsmanager/aws4_request")
+}
+
+func TestResolver_Refresh(t *testing.T) {
+	current := "first"
+	Register("test", func(_ context.Context, ref Ref) (string, error) {
+		return selectKey(`{"password": "`+current+`"}`, ref.Key)
+	})
+
+	resolver := NewResolver()
+
+	static, err := resolver.Resolve(context.Background(), "literal")
+	require.NoError(t, err)
+	assert.False(t, static.IsReference())
+
+	value, err := resolver.Resolve(context.Background(), "test://db#password")
+	require.NoError(t, err)
+	assert.True(t, value.IsReference())
+	assert.Equal(t, "first", value.Get())
+	assert.Equal(t, 1, resolver.Len())
+
+	current = "second"
+	changed, err := resolver.Refresh(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, changed)
+	assert.Equal(t, "second", value.Get())
+	assert.Equal(t, "literal", static.Get())
+}
+
+func TestRef_String(t *testing.T) {
+	ref := Ref{Scheme: "awssm", Path: "prod/floxy", Key: "password", Query: url.Values{"region": {"eu-west-1"}}}
+	assert.Equal(t, "awssm://prod/floxy?region=eu-west-1#password", ref.String())
+}
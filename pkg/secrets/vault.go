@@ -0,0 +1,78 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const fetchTimeout = 10 * time.Second
+
+var httpClient = &http.Client{Timeout: fetchTimeout}
+
+// fetchVault reads a secret of the HashiCorp Vault KV engine, version 1 or 2:
+// vault://secret/data/floxy#db_password reads the db_password key of the secret/floxy KV v2 secret.
+// The server and the token come from the VAULT_ADDR, VAULT_TOKEN and VAULT_NAMESPACE variables
+// used by the Vault CLI.
+func fetchVault(ctx context.Context, ref Ref) (string, error) {
+	addr := strings.TrimSuffix(os.Getenv("VAULT_ADDR"), "/")
+	if addr == "" {
+		return "", errors.New("VAULT_ADDR is not set")
+	}
+
+	if ref.Key == "" {
+		return "", errors.New("vault references need a #key")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, addr+"/v1/"+strings.TrimPrefix(ref.Path, "/"), nil)
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("X-Vault-Token", os.Getenv("VAULT_TOKEN"))
+	if namespace := os.Getenv("VAULT_NAMESPACE"); namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault answered %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return vaultSecretKey(body, ref.Key)
+}
+
+// vaultSecretKey selects the key of a KV read response, KV v2 nests the fields in data.data.
+func vaultSecretKey(body []byte, key string) (string, error) {
+	var resp struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("decode response: %w", err)
+	}
+
+	fields := resp.Data
+	if nested, ok := fields["data"].(map[string]any); ok {
+		if _, isV2 := fields["metadata"]; isV2 {
+			fields = nested
+		}
+	}
+
+	return fieldValue(fields, key)
+}
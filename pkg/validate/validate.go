@@ -0,0 +1,242 @@
+// Package validate checks structs against the rules of their `validate` field tags:
+//
+//	Name  string `json:"name" validate:"required,max=255"`
+//	Email string `json:"email" validate:"required,email"`
+//	Role  string `json:"role" validate:"oneof=admin member"`
+//
+// The rules are required, min=N and max=N (length of strings, slices and maps, value of numbers),
+// email, url and oneof=a b c. Rules other than required are skipped for zero values and nil pointers,
+// so optional fields are checked only when set. Nested structs, pointers to them and slices of them are checked
+// too, the fields are reported by their JSON names.
+package validate
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const tagName = "validate"
+
+// FieldError is a failed rule of a field.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Errors are the failed rules of a struct, one per field.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, fieldErr := range e {
+		messages = append(messages, fieldErr.Field+" "+fieldErr.Message)
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// Struct checks v, a struct or a pointer to one. It returns Errors when rules fail.
+// Malformed tags panic, they are programming errors.
+func Struct(v any) error {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil
+		}
+
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var errs Errors
+	checkStruct(value, "", &errs)
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+func checkStruct(value reflect.Value, prefix string, errs *Errors) {
+	typ := value.Type()
+
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, ok := fieldName(field)
+		if !ok {
+			continue
+		}
+
+		if field.Anonymous {
+			name = strings.TrimSuffix(prefix, ".")
+		} else {
+			name = prefix + name
+		}
+
+		fieldValue := value.Field(i)
+
+		if tag := field.Tag.Get(tagName); tag != "" {
+			if fieldErr, failed := checkField(fieldValue, tag); failed {
+				fieldErr.Field = name
+				*errs = append(*errs, fieldErr)
+
+				continue
+			}
+		}
+
+		checkNested(fieldValue, name, errs)
+	}
+}
+
+// checkNested checks the structs held by the field.
+func checkNested(value reflect.Value, name string, errs *Errors) {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return
+		}
+
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		checkStruct(value, nestedPrefix(name), errs)
+	case reflect.Slice, reflect.Array:
+		for i := range value.Len() {
+			checkNested(value.Index(i), fmt.Sprintf("%s[%d]", name, i), errs)
+		}
+	default:
+	}
+}
+
+func nestedPrefix(name string) string {
+	if name == "" {
+		return ""
+	}
+
+	return name + "."
+}
+
+// fieldName returns the JSON name of the field, ok is false for fields skipped by JSON.
+func fieldName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+
+	return name, true
+}
+
+// checkField applies the rules of the tag, stopping at the first failed one.
+func checkField(value reflect.Value, tag string) (FieldError, bool) {
+	// A set pointer is checked even when it points to the zero value
+	zero := value.IsZero()
+	for value.Kind() == reflect.Pointer && !value.IsNil() {
+		value = value.Elem()
+	}
+
+	for _, rule := range strings.Split(tag, ",") {
+		rule, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+
+		if rule == "required" {
+			if zero {
+				return FieldError{Rule: rule, Message: "is required"}, true
+			}
+
+			continue
+		}
+
+		if zero {
+			continue
+		}
+
+		if message, ok := checkRule(value, rule, param); !ok {
+			return FieldError{Rule: rule, Message: message}, true
+		}
+	}
+
+	return FieldError{}, false
+}
+
+func checkRule(value reflect.Value, rule, param string) (string, bool) {
+	switch rule {
+	case "min":
+		return checkBound(value, param, true)
+	case "max":
+		return checkBound(value, param, false)
+	case "email":
+		if _, err := mail.ParseAddress(value.String()); err != nil {
+			return "must be a valid email address", false
+		}
+	case "url":
+		if parsed, err := url.ParseRequestURI(value.String()); err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			return "must be a valid URL", false
+		}
+	case "oneof":
+		options := strings.Fields(param)
+		if !slices.Contains(options, fmt.Sprint(value.Interface())) {
+			return "must be one of: " + strings.Join(options, ", "), false
+		}
+	default:
+		panic(fmt.Sprintf("validate: unknown rule %q", rule))
+	}
+
+	return "", true
+}
+
+func checkBound(value reflect.Value, param string, isMin bool) (string, bool) {
+	bound, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		panic(fmt.Sprintf("validate: invalid bound %q", param))
+	}
+
+	var (
+		actual float64
+		unit   string
+	)
+
+	switch value.Kind() {
+	case reflect.String:
+		actual, unit = float64(utf8.RuneCountInString(value.String())), " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		actual, unit = float64(value.Len()), " items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		actual = float64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		actual = float64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		actual = value.Float()
+	default:
+		panic(fmt.Sprintf("validate: bound on %s", value.Kind()))
+	}
+
+	if isMin && actual < bound {
+		return "must be at least " + param + unit, false
+	}
+
+	if !isMin && actual > bound {
+		return "must be at most " + param + unit, false
+	}
+
+	return "", true
+}
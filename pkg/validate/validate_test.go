@@ -0,0 +1,55 @@
+package validate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testMembership struct {
+	ProjectID int    `json:"project_id" validate:"required,min=1"`
+	RoleID    string `json:"role_id" validate:"required"`
+}
+
+type testRequest struct {
+	Name        string           `json:"name" validate:"required,max=5"`
+	Email       *string          `json:"email" validate:"email"`
+	Password    string           `json:"password" validate:"min=6"`
+	Kind        string           `json:"kind" validate:"oneof=a b"`
+	Memberships []testMembership `json:"memberships"`
+	internal    string
+}
+
+func TestStruct(t *testing.T) {
+	require.NoError(t, Struct(&testRequest{Name: "name"}))
+	require.NoError(t, Struct((*testRequest)(nil)))
+
+	email := "not-an-email"
+	err := Struct(&testRequest{
+		Email:       &email,
+		Password:    "123",
+		Kind:        "c",
+		Memberships: []testMembership{{ProjectID: 1, RoleID: "viewer"}, {ProjectID: -1}},
+	})
+
+	var errs Errors
+	require.ErrorAs(t, err, &errs)
+	assert.Equal(t, Errors{
+		{Field: "name", Rule: "required", Message: "is required"},
+		{Field: "email", Rule: "email", Message: "must be a valid email address"},
+		{Field: "password", Rule: "min", Message: "must be at least 6 characters"},
+		{Field: "kind", Rule: "oneof", Message: "must be one of: a, b"},
+		{Field: "memberships[1].project_id", Rule: "min", Message: "must be at least 1"},
+		{Field: "memberships[1].role_id", Rule: "required", Message: "is required"},
+	}, errs)
+}
+
+func TestStruct_SetPointer(t *testing.T) {
+	empty := ""
+	err := Struct(&testRequest{Name: "name", Email: &empty})
+
+	var errs Errors
+	require.ErrorAs(t, err, &errs)
+	assert.Equal(t, "email must be a valid email address", errs.Error())
+}
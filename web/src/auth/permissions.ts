@@ -9,6 +9,7 @@ export const PERMISSIONS = {
     },
     workflow: {
         create: 'workflow.create',
+        dataView: 'workflow.data.view',
     },
     audit: {
         view: 'audit.view',
@@ -22,6 +23,7 @@ export type PermissionKey =
     | typeof PERMISSIONS.project.view
     | typeof PERMISSIONS.project.manage
     | typeof PERMISSIONS.workflow.create
+    | typeof PERMISSIONS.workflow.dataView
     | typeof PERMISSIONS.audit.view
     | typeof PERMISSIONS.membership.manage
 
@@ -98,6 +100,7 @@ export function useRBAC(projectId?: string | number) {
             canViewProject: () => check(PERMISSIONS.project.view),
             canManageProject,
             canCreateWorkflow: () => check(PERMISSIONS.workflow.create),
+            canViewWorkflowData: () => check(PERMISSIONS.workflow.dataView),
             canViewAudit: () => check(PERMISSIONS.audit.view),
             canManageMembership: () => check(PERMISSIONS.membership.manage),
             canCreateProject,
@@ -140,6 +140,15 @@ export interface MyProjectsResponse {
   projects: ProjectInfo[];
 }
 
+export interface UserPreferences {
+  user_id?: number;
+  default_project_id: number | null;
+  locale: string;
+  items_per_page: number;
+  notification_opt_outs: string[];
+  updated_at?: string | null;
+}
+
 export interface Verify2FARequest {
   code: string;
   session_id: string;
@@ -266,6 +275,14 @@ const apiClient = {
     return api.get('/api/v1/users/me/projects');
   },
 
+  getMyPreferences: async (): Promise<AxiosResponse<UserPreferences>> => {
+    return api.get('/api/v1/users/me/preferences');
+  },
+
+  updateMyPreferences: async (data: UserPreferences): Promise<AxiosResponse<UserPreferences>> => {
+    return api.put('/api/v1/users/me/preferences', data);
+  },
+
   verify2FA: async (data: Verify2FARequest): Promise<AxiosResponse<Verify2FAResponse>> => {
     return api.post('/api/v1/auth/2fa/verify', data);
   },